@@ -0,0 +1,187 @@
+// Package audit records every tool call as a JSON line: the tool, its
+// arguments, who made the call, how long it took, how large the result was,
+// whether it was cut short, and the error, if any. Shared deployments keep
+// the log to answer who queried what and when. Arguments that carry
+// payloads, such as manifests and base64 data, may hold secret values, so
+// they are logged by size only.
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/response"
+)
+
+const (
+	// Redacted replaces the value of arguments that may hold secrets.
+	Redacted = "[REDACTED]"
+
+	// maxValueLength bounds the string arguments and error messages written
+	// to the log, so a large argument cannot bloat every entry.
+	maxValueLength = 512
+)
+
+// redactedArguments are the arguments whose values are never logged: payloads
+// that may hold secret data, and anything named like a credential.
+var redactedArguments = map[string]bool{
+	"data":     true,
+	"manifest": true,
+}
+
+// sensitiveWords mark argument names that hold credentials.
+var sensitiveWords = []string{"password", "token", "credential", "private_key"}
+
+// Entry is a single audit record.
+type Entry struct {
+	Time        time.Time      `json:"time"`
+	Tool        string         `json:"tool"`
+	Arguments   map[string]any `json:"arguments,omitempty"`
+	Caller      string         `json:"caller,omitempty"`
+	Session     string         `json:"session,omitempty"`
+	DurationMS  int64          `json:"duration_ms"`
+	ResultBytes int            `json:"result_bytes"`
+	Partial     bool           `json:"partial,omitempty"`
+	Error       string         `json:"error,omitempty"`
+}
+
+// Logger writes audit entries. It is safe for concurrent use.
+type Logger struct {
+	// identify returns the caller and session of a call, when known.
+	identify func(context.Context) (caller, session string)
+
+	// now returns the current time. It defaults to time.Now and is replaced
+	// in tests.
+	now func() time.Time
+
+	mu  sync.Mutex
+	out io.Writer
+}
+
+// New creates a Logger writing to out. identify names the caller and session
+// of a call from its context; it may be nil when neither is known.
+func New(out io.Writer, identify func(context.Context) (caller, session string)) *Logger {
+	return &Logger{identify: identify, now: time.Now, out: out}
+}
+
+// Wrap returns a handler that records every call to the named tool once it
+// finishes.
+func (l *Logger) Wrap(toolName string, next func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error)) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		started := l.now()
+		result, err := next(ctx, request)
+
+		entry := Entry{
+			Time:        started.UTC(),
+			Tool:        toolName,
+			Arguments:   Redact(request.GetArguments()),
+			DurationMS:  l.now().Sub(started).Milliseconds(),
+			ResultBytes: resultSize(result),
+			Partial:     response.IsPartial(result),
+		}
+		if l.identify != nil {
+			entry.Caller, entry.Session = l.identify(ctx)
+		}
+		switch {
+		case err != nil:
+			entry.Error = truncate(err.Error())
+		case result != nil && result.IsError:
+			entry.Error = truncate(resultText(result))
+		}
+
+		l.write(entry)
+		return result, err
+	}
+}
+
+// write encodes entry as a single line. Write errors are dropped: a full disk
+// should not fail the tool calls being audited.
+func (l *Logger) write(entry Entry) {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, _ = l.out.Write(append(line, '\n'))
+}
+
+// Redact returns a copy of args with the values of sensitive arguments
+// replaced and long strings truncated.
+func Redact(args map[string]any) map[string]any {
+	if len(args) == 0 {
+		return nil
+	}
+
+	redacted := make(map[string]any, len(args))
+	for name, value := range args {
+		if isSensitive(name) {
+			if s, ok := value.(string); ok {
+				redacted[name] = fmt.Sprintf("%s (%d bytes)", Redacted, len(s))
+			} else {
+				redacted[name] = Redacted
+			}
+			continue
+		}
+		if s, ok := value.(string); ok {
+			value = truncate(s)
+		}
+		redacted[name] = value
+	}
+	return redacted
+}
+
+// isSensitive reports whether the argument called name may hold a secret.
+func isSensitive(name string) bool {
+	name = strings.ToLower(name)
+	if redactedArguments[name] {
+		return true
+	}
+	for _, word := range sensitiveWords {
+		if strings.Contains(name, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// truncate shortens s to maxValueLength bytes.
+func truncate(s string) string {
+	if len(s) <= maxValueLength {
+		return s
+	}
+	return fmt.Sprintf("%s... (%d bytes truncated)", s[:maxValueLength], len(s)-maxValueLength)
+}
+
+// resultSize returns the size of the result's text content, in bytes.
+func resultSize(result *mcp.CallToolResult) int {
+	if result == nil {
+		return 0
+	}
+
+	size := 0
+	for _, content := range result.Content {
+		if text, ok := content.(mcp.TextContent); ok {
+			size += len(text.Text)
+		}
+	}
+	return size
+}
+
+// resultText returns the text of an error result.
+func resultText(result *mcp.CallToolResult) string {
+	for _, content := range result.Content {
+		if text, ok := content.(mcp.TextContent); ok {
+			return text.Text
+		}
+	}
+	return "tool returned an error result"
+}
@@ -0,0 +1,135 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/response"
+)
+
+func request(args map[string]any) mcp.CallToolRequest {
+	var r mcp.CallToolRequest
+	r.Params.Arguments = args
+	return r
+}
+
+func decodeEntries(t *testing.T, buf *bytes.Buffer) []Entry {
+	t.Helper()
+
+	var entries []Entry
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var entry Entry
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("expected a JSON line, got %q: %v", line, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestWrap(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	logger := New(&buf, func(context.Context) (string, string) { return "alice@example.com", "session-1" })
+
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	logger.now = func() time.Time {
+		current := now
+		now = now.Add(250 * time.Millisecond)
+		return current
+	}
+
+	ok := logger.Wrap("list_resources", func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText(`{"items":[]}`), nil
+	})
+	partial := logger.Wrap("diagnose_pod", func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return response.Partial(mcp.NewToolResultText(`{}`)), nil
+	})
+	failing := logger.Wrap("get_resource", func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultError("pods \"web\" not found"), nil
+	})
+	broken := logger.Wrap("get_logs", func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return nil, errors.New("connection refused")
+	})
+
+	if _, err := ok(context.Background(), request(map[string]any{"resource_type": "pods", "namespace": "default"})); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := failing(context.Background(), request(map[string]any{"name": "web"})); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := broken(context.Background(), request(nil)); err == nil {
+		t.Fatal("expected the handler error to be returned")
+	}
+	if _, err := partial(context.Background(), request(nil)); err != nil {
+		t.Fatal(err)
+	}
+
+	entries := decodeEntries(t, &buf)
+	if len(entries) != 4 {
+		t.Fatalf("expected 4 entries, got %d", len(entries))
+	}
+
+	first := entries[0]
+	if first.Tool != "list_resources" || first.Caller != "alice@example.com" || first.Session != "session-1" {
+		t.Errorf("unexpected entry %+v", first)
+	}
+	if first.DurationMS != 250 || first.ResultBytes != len(`{"items":[]}`) || first.Error != "" {
+		t.Errorf("unexpected duration, size, or error in %+v", first)
+	}
+	if first.Arguments["resource_type"] != "pods" {
+		t.Errorf("expected the arguments to be logged, got %v", first.Arguments)
+	}
+
+	if entries[1].Error != `pods "web" not found` {
+		t.Errorf("expected the error result to be logged, got %q", entries[1].Error)
+	}
+	if entries[2].Error != "connection refused" {
+		t.Errorf("expected the handler error to be logged, got %q", entries[2].Error)
+	}
+	if first.Partial || !entries[3].Partial {
+		t.Errorf("expected only the partial result to be logged as partial, got %+v and %+v", first, entries[3])
+	}
+}
+
+func TestRedact(t *testing.T) {
+	t.Parallel()
+
+	args := map[string]any{
+		"manifest":       "apiVersion: v1\nkind: Secret\ndata:\n  password: aHVudGVyMg==",
+		"data":           "aHVudGVyMg==",
+		"bearer_token":   map[string]any{"value": "abc"},
+		"namespace":      "default",
+		"label_selector": strings.Repeat("a", 600),
+	}
+
+	got := Redact(args)
+
+	if got["manifest"] != fmt.Sprintf("%s (%d bytes)", Redacted, len(args["manifest"].(string))) {
+		t.Errorf("expected the manifest to be redacted, got %v", got["manifest"])
+	}
+	if got["data"] != Redacted+" (12 bytes)" {
+		t.Errorf("expected the data to be redacted, got %v", got["data"])
+	}
+	if got["bearer_token"] != Redacted {
+		t.Errorf("expected the token to be redacted, got %v", got["bearer_token"])
+	}
+	if got["namespace"] != "default" {
+		t.Errorf("expected the namespace to be kept, got %v", got["namespace"])
+	}
+	if s, _ := got["label_selector"].(string); !strings.HasSuffix(s, "(88 bytes truncated)") {
+		t.Errorf("expected the long selector to be truncated, got %q", s)
+	}
+	if args["data"] != "aHVudGVyMg==" {
+		t.Error("expected the original arguments to be unchanged")
+	}
+}
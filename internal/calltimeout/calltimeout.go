@@ -0,0 +1,166 @@
+// Package calltimeout bounds how long a tool call may run. Tool handlers pass
+// their context to every Kubernetes request, so a deadline on it stops calls
+// stuck on a hung API server, a dropped connection, or a slow credential
+// plugin, and the MCP session gets an error instead of waiting forever. Tools
+// that query a cluster also accept a timeout_seconds argument that replaces
+// the server's timeout for that call.
+//
+// The same applies when the client gives up on a call, through a
+// notifications/cancelled message or by closing its HTTP request: the call
+// returns right away, and the handler's requests are cancelled with it.
+package calltimeout
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/response"
+)
+
+const (
+	// Argument is the tool argument that replaces the timeout for a call.
+	Argument = "timeout_seconds"
+
+	// contextArgument marks the tools that talk to a cluster: only those
+	// get the timeout argument.
+	contextArgument = "context"
+
+	// partialGrace is how long a stopped call waits for its handler to
+	// return what it gathered before the stop. Handlers that check their
+	// context return as soon as their requests are cancelled.
+	partialGrace = time.Second
+)
+
+// Limiter applies a timeout to tool calls. It is safe for concurrent use.
+type Limiter struct {
+	timeout time.Duration
+}
+
+// New creates a Limiter that stops tool calls after timeout. A zero timeout
+// only applies the timeouts requested through Argument.
+func New(timeout time.Duration) *Limiter {
+	return &Limiter{timeout: timeout}
+}
+
+// AddParameter returns tool with the timeout argument added to its input
+// schema, and whether it was changed. Tools without a context argument do
+// not query a cluster and are returned unchanged.
+//
+//nolint:gocritic // mcp.Tool is passed by value the way the MCP server registers it
+func AddParameter(tool mcp.Tool) (mcp.Tool, bool) {
+	if _, ok := tool.InputSchema.Properties[contextArgument]; !ok {
+		return tool, false
+	}
+
+	// The properties map may be shared with the original tool definition,
+	// so the argument is added to a copy.
+	properties := make(map[string]any, len(tool.InputSchema.Properties)+1)
+	for name, property := range tool.InputSchema.Properties {
+		properties[name] = property
+	}
+	properties[Argument] = map[string]any{
+		"type":        "integer",
+		"minimum":     1,
+		"description": "Maximum number of seconds this call may take before it is stopped (defaults to the server's configured timeout). Raise it for slow queries against large clusters",
+	}
+	tool.InputSchema.Properties = properties
+
+	return tool, true
+}
+
+// Wrap returns a handler that runs next with a deadline on its context: the
+// timeout requested through Argument, or the Limiter's default. When the
+// deadline passes, next gets a short grace period to return what it has
+// gathered so far, after which the call returns an error result, even if
+// next has not noticed the deadline yet. A call cancelled by the client
+// returns its context's error the same way.
+//
+// A result next returns after its context ended may be missing what it had
+// not gathered yet, so it is marked with response.Partial.
+func (l *Limiter) Wrap(next func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error)) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		timeout, err := l.timeoutFor(request.GetArguments())
+		if err != nil {
+			return response.Errorf("failed to parse arguments: %s", err)
+		}
+
+		if timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+
+		type outcome struct {
+			result *mcp.CallToolResult
+			err    error
+		}
+
+		// The handler runs in its own goroutine so a call stuck somewhere
+		// that ignores the context, such as a credential plugin, cannot hold
+		// the session. Its outcome is dropped once the grace period after
+		// the context ends runs out.
+		done := make(chan outcome, 1)
+		go func() {
+			result, err := next(ctx, request)
+			done <- outcome{result, err}
+		}()
+
+		var out outcome
+		select {
+		case out = <-done:
+		case <-ctx.Done():
+			grace := time.NewTimer(partialGrace)
+			defer grace.Stop()
+
+			select {
+			case out = <-done:
+			case <-grace.C:
+				return stopped(ctx, timeout)
+			}
+		}
+
+		switch {
+		case ctx.Err() == nil:
+			return out.result, out.err
+		case out.err != nil || out.result == nil || out.result.IsError:
+			// Errors caused by the stop are replaced by the reason.
+			return stopped(ctx, timeout)
+		default:
+			return response.Partial(out.result), nil
+		}
+	}
+}
+
+// stopped is the outcome of a call whose context ended: the timeout error
+// result when the deadline passed, or the context's error when the client
+// cancelled the call.
+func stopped(ctx context.Context, timeout time.Duration) (*mcp.CallToolResult, error) {
+	if timeout > 0 && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return timedOut(timeout)
+	}
+	return nil, ctx.Err() //nolint:wrapcheck // cancellation by the client is returned as is
+}
+
+// timeoutFor returns the timeout for a call with the given arguments.
+func (l *Limiter) timeoutFor(args map[string]any) (time.Duration, error) {
+	raw, ok := args[Argument]
+	if !ok || raw == nil {
+		return l.timeout, nil
+	}
+
+	seconds, ok := raw.(float64)
+	if !ok || seconds < 1 || seconds != float64(int64(seconds)) {
+		return 0, fmt.Errorf("%s must be a whole number of seconds, at least 1", Argument)
+	}
+
+	return time.Duration(seconds) * time.Second, nil
+}
+
+// timedOut is the result of a call stopped by its timeout.
+func timedOut(timeout time.Duration) (*mcp.CallToolResult, error) {
+	return response.Errorf("the tool call did not finish within %s and was stopped. The API server may be slow or unreachable; retry with a larger %s for slow queries", timeout, Argument)
+}
@@ -0,0 +1,171 @@
+package calltimeout
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/response"
+)
+
+func request(args map[string]any) mcp.CallToolRequest {
+	var r mcp.CallToolRequest
+	r.Params.Arguments = args
+	return r
+}
+
+func resultText(t *testing.T, result *mcp.CallToolResult) string {
+	t.Helper()
+
+	if result == nil || len(result.Content) == 0 {
+		t.Fatalf("expected a result with content, got %+v", result)
+	}
+	text, ok := result.Content[0].(mcp.TextContent)
+	if !ok {
+		t.Fatalf("expected text content, got %T", result.Content[0])
+	}
+	return text.Text
+}
+
+func TestAddParameter(t *testing.T) {
+	t.Parallel()
+
+	properties := map[string]any{"context": map[string]any{"type": "string"}}
+	tool := mcp.Tool{Name: "list_resources", InputSchema: mcp.ToolInputSchema{Type: "object", Properties: properties}}
+
+	got, ok := AddParameter(tool)
+	if !ok {
+		t.Fatal("expected a tool with a context argument to get the timeout argument")
+	}
+	if _, ok := got.InputSchema.Properties[Argument]; !ok {
+		t.Errorf("expected argument %q in %v", Argument, got.InputSchema.Properties)
+	}
+	if len(properties) != 1 {
+		t.Errorf("expected the original properties to be unchanged, got %v", properties)
+	}
+
+	local := mcp.Tool{Name: "server_capabilities", InputSchema: mcp.ToolInputSchema{Type: "object", Properties: map[string]any{}}}
+	if _, ok := AddParameter(local); ok {
+		t.Error("expected a tool without a context argument to be left unchanged")
+	}
+}
+
+func TestWrap(t *testing.T) {
+	t.Parallel()
+
+	// blocking waits for its context, like a request to a hung API server,
+	// and reports how long it was given.
+	blocking := func(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		<-ctx.Done()
+		return mcp.NewToolResultError(ctx.Err().Error()), nil
+	}
+
+	// ignoring never looks at its context, like a stuck credential plugin.
+	ignoring := func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		time.Sleep(5 * time.Second)
+		return mcp.NewToolResultText("{}"), nil
+	}
+
+	fast := func(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		deadline, ok := ctx.Deadline()
+		if !ok {
+			return mcp.NewToolResultText("no deadline"), nil
+		}
+		return mcp.NewToolResultText(time.Until(deadline).Round(time.Minute).String()), nil
+	}
+
+	tests := []struct {
+		name     string
+		limiter  *Limiter
+		handler  func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error)
+		args     map[string]any
+		wantErr  bool
+		wantText string
+	}{
+		{name: "default timeout", limiter: New(time.Hour), handler: fast, wantText: "1h0m0s"},
+		{name: "per-request timeout", limiter: New(time.Hour), handler: fast, args: map[string]any{Argument: float64(120)}, wantText: "2m0s"},
+		{name: "per-request timeout without a default", limiter: New(0), handler: fast, args: map[string]any{Argument: float64(600)}, wantText: "10m0s"},
+		{name: "no timeout", limiter: New(0), handler: fast, wantText: "no deadline"},
+		{name: "handler stopped by the deadline", limiter: New(50 * time.Millisecond), handler: blocking, wantErr: true, wantText: "did not finish within 50ms"},
+		{name: "handler ignoring the deadline", limiter: New(50 * time.Millisecond), handler: ignoring, wantErr: true, wantText: "did not finish within 50ms"},
+		{name: "invalid timeout", limiter: New(time.Hour), handler: fast, args: map[string]any{Argument: float64(0.5)}, wantErr: true, wantText: "whole number of seconds"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			start := time.Now()
+			result, err := tt.limiter.Wrap(tt.handler)(context.Background(), request(tt.args))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if elapsed := time.Since(start); elapsed > 2*time.Second {
+				t.Errorf("expected the call to return promptly, took %s", elapsed)
+			}
+			if result.IsError != tt.wantErr {
+				t.Errorf("expected error %t, got %+v", tt.wantErr, result)
+			}
+			if text := resultText(t, result); !strings.Contains(text, tt.wantText) {
+				t.Errorf("expected %q in %q", tt.wantText, text)
+			}
+		})
+	}
+}
+
+func TestWrapCancelled(t *testing.T) {
+	t.Parallel()
+
+	// ignoring never looks at its context, like a stuck credential plugin.
+	ignoring := func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		time.Sleep(5 * time.Second)
+		return mcp.NewToolResultText("{}"), nil
+	}
+
+	for _, limiter := range []*Limiter{New(0), New(time.Hour)} {
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(50*time.Millisecond, cancel)
+
+		start := time.Now()
+		result, err := limiter.Wrap(ignoring)(ctx, request(nil))
+		if !errors.Is(err, context.Canceled) || result != nil {
+			t.Errorf("timeout %s: expected the cancellation error, got %+v and %v", limiter.timeout, result, err)
+		}
+		if elapsed := time.Since(start); elapsed > 2*time.Second {
+			t.Errorf("timeout %s: expected the call to return promptly, took %s", limiter.timeout, elapsed)
+		}
+	}
+}
+
+func TestWrapPartial(t *testing.T) {
+	t.Parallel()
+
+	// gathering returns what it has once its context ends, like a handler
+	// that checks ctx.Err() between requests.
+	gathering := func(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		<-ctx.Done()
+		return mcp.NewToolResultText(`{"items":["web-1"]}`), nil
+	}
+
+	for _, limiter := range []*Limiter{New(50 * time.Millisecond), New(0)} {
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(50*time.Millisecond, cancel)
+
+		result, err := limiter.Wrap(gathering)(ctx, request(nil))
+		cancel()
+		if err != nil {
+			t.Fatalf("timeout %s: unexpected error: %v", limiter.timeout, err)
+		}
+		if !response.IsPartial(result) || result.IsError {
+			t.Errorf("timeout %s: expected a partial result, got %+v", limiter.timeout, result)
+		}
+		if text := resultText(t, result); text != `{"items":["web-1"]}` {
+			t.Errorf("timeout %s: expected the gathered items, got %q", limiter.timeout, text)
+		}
+	}
+}
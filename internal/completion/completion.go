@@ -0,0 +1,237 @@
+// Package completion answers completion/complete requests, so clients can
+// autocomplete the context, namespace, and resource type arguments of
+// prompts and resource templates from live cluster data instead of guessing
+// names and failing on typos.
+package completion
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/kubernetes"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/objectresources"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/resourcefilter"
+)
+
+const (
+	// cacheTTL is how long the names read from a cluster are reused. Clients
+	// request completions on every keystroke, so only the first one of a
+	// burst reaches the cluster.
+	cacheTTL = 30 * time.Second
+
+	// maxValues is the most values a completion may carry, as set by the MCP
+	// specification.
+	maxValues = 100
+)
+
+// source is a kind of name that can be completed.
+type source int
+
+const (
+	contexts source = iota + 1
+	namespaces
+	resourceTypes
+)
+
+// sourceOf returns what the argument named name holds, or 0 when it is not
+// completed. Prompts name their namespaces after what lives there, such as
+// client_namespace, and resource templates call the resource type "type".
+func sourceOf(name string) source {
+	switch {
+	case name == "context":
+		return contexts
+	case name == "namespace" || strings.HasSuffix(name, "_namespace"):
+		return namespaces
+	case name == "resource_type" || name == "type":
+		return resourceTypes
+	default:
+		return 0
+	}
+}
+
+type cacheKey struct {
+	source  source
+	context string
+}
+
+type cacheEntry struct {
+	values  []string
+	fetched time.Time
+}
+
+// Provider completes prompt and resource template arguments. It implements
+// both server.PromptCompletionProvider and server.ResourceCompletionProvider.
+type Provider struct {
+	client         kubernetes.ClusterReader
+	resourceFilter *resourcefilter.Filter
+	now            func() time.Time
+
+	mu    sync.Mutex
+	cache map[cacheKey]cacheEntry
+}
+
+// New creates a Provider that reads names through client. Resource types
+// disabled by filter, which may be nil, are not offered.
+func New(client kubernetes.ClusterReader, filter *resourcefilter.Filter) *Provider {
+	return &Provider{
+		client:         client,
+		resourceFilter: filter,
+		now:            time.Now,
+		cache:          make(map[cacheKey]cacheEntry),
+	}
+}
+
+// CompletePromptArgument completes an argument of a prompt. The context
+// argument, when already set, selects the cluster namespaces are read from.
+func (p *Provider) CompletePromptArgument(ctx context.Context, _ string, argument mcp.CompleteArgument, completeContext mcp.CompleteContext) (*mcp.Completion, error) {
+	return p.complete(ctx, argument, completeContext.Arguments["context"], false)
+}
+
+// CompleteResourceArgument completes a variable of a cluster object resource
+// template. Besides the names read from the cluster, the context and
+// namespace variables are offered objectresources.Unset, which stands for
+// the current context and for no namespace.
+func (p *Provider) CompleteResourceArgument(ctx context.Context, uri string, argument mcp.CompleteArgument, completeContext mcp.CompleteContext) (*mcp.Completion, error) {
+	if !strings.HasPrefix(uri, objectresources.Scheme+"://") {
+		return &mcp.Completion{Values: []string{}}, nil
+	}
+
+	contextName := completeContext.Arguments["context"]
+	if contextName == objectresources.Unset {
+		contextName = ""
+	}
+	return p.complete(ctx, argument, contextName, true)
+}
+
+// complete returns the names of the argument's kind that start with its
+// value, ignoring case, read from the cluster of contextName.
+func (p *Provider) complete(ctx context.Context, argument mcp.CompleteArgument, contextName string, withUnset bool) (*mcp.Completion, error) {
+	source := sourceOf(argument.Name)
+	if source == 0 {
+		return &mcp.Completion{Values: []string{}}, nil
+	}
+
+	// The context list does not depend on the selected context.
+	if source == contexts {
+		contextName = ""
+	}
+
+	candidates, err := p.candidates(ctx, source, contextName)
+	if err != nil {
+		return nil, err
+	}
+	if withUnset && source != resourceTypes {
+		candidates = append([]string{objectresources.Unset}, candidates...)
+	}
+
+	return match(candidates, argument.Value), nil
+}
+
+// match returns the candidates starting with prefix, ignoring case, capped
+// at maxValues.
+func match(candidates []string, prefix string) *mcp.Completion {
+	prefix = strings.ToLower(prefix)
+
+	values := make([]string, 0)
+	for _, candidate := range candidates {
+		if strings.HasPrefix(strings.ToLower(candidate), prefix) {
+			values = append(values, candidate)
+		}
+	}
+
+	completion := &mcp.Completion{Values: values, Total: len(values)}
+	if len(values) > maxValues {
+		completion.Values = values[:maxValues]
+		completion.HasMore = true
+	}
+	return completion
+}
+
+// candidates returns the sorted names of source, from the cache when they
+// were read less than cacheTTL ago.
+func (p *Provider) candidates(ctx context.Context, source source, contextName string) ([]string, error) {
+	key := cacheKey{source: source, context: contextName}
+
+	p.mu.Lock()
+	entry, ok := p.cache[key]
+	p.mu.Unlock()
+	if ok && p.now().Sub(entry.fetched) < cacheTTL {
+		return entry.values, nil
+	}
+
+	values, err := p.fetch(ctx, source, contextName)
+	if err != nil {
+		return nil, err
+	}
+	slices.Sort(values)
+	values = slices.Compact(values)
+
+	p.mu.Lock()
+	p.cache[key] = cacheEntry{values: values, fetched: p.now()}
+	p.mu.Unlock()
+
+	return values, nil
+}
+
+// fetch reads the names of source from the cluster of contextName.
+func (p *Provider) fetch(ctx context.Context, source source, contextName string) ([]string, error) {
+	if source == contexts {
+		list, err := p.client.ListContexts()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list contexts: %w", err)
+		}
+
+		names := make([]string, 0, len(list))
+		for _, kubeContext := range list {
+			names = append(names, kubeContext.Name)
+		}
+		return names, nil
+	}
+
+	client, err := p.client.ForContext(ctx, contextName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client with context %s: %w", contextName, err)
+	}
+
+	if source == namespaces {
+		list, err := client.ListNamespaces(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list namespaces: %w", err)
+		}
+
+		names := make([]string, 0, len(list.Items))
+		for i := range list.Items {
+			names = append(names, list.Items[i].Name)
+		}
+		return names, nil
+	}
+
+	lists, err := client.DiscoverResources(ctx)
+	if err != nil && len(lists) == 0 {
+		return nil, fmt.Errorf("failed to discover resource types: %w", err)
+	}
+
+	// Groups that failed discovery are left out; their types still resolve
+	// when typed in full.
+	var names []string
+	for _, list := range lists {
+		for _, resource := range list.APIResources {
+			// Subresources such as pods/log are not listable types.
+			if strings.Contains(resource.Name, "/") {
+				continue
+			}
+			if p.resourceFilter != nil && p.resourceFilter.MatchesAPIResource(list.GroupVersion, resource.Name) {
+				continue
+			}
+			names = append(names, resource.Name)
+		}
+	}
+	return names, nil
+}
@@ -0,0 +1,157 @@
+package completion
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/fakecluster"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/kubernetes"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/resourcefilter"
+)
+
+// countingReader serves a fixed list of contexts and counts the namespace
+// lists that reach the cluster.
+type countingReader struct {
+	kubernetes.ClusterReader
+	namespaceLists int
+}
+
+func (r *countingReader) ForContext(_ context.Context, _ string) (kubernetes.ClusterReader, error) {
+	return r, nil
+}
+
+func (r *countingReader) ListContexts() ([]kubernetes.KubeContext, error) {
+	return []kubernetes.KubeContext{{Name: "staging"}, {Name: "prod"}, {Name: "prod-eu"}}, nil
+}
+
+func (r *countingReader) ListNamespaces(ctx context.Context, opts metav1.ListOptions) (*corev1.NamespaceList, error) {
+	r.namespaceLists++
+	return r.ClusterReader.ListNamespaces(ctx, opts) //nolint:wrapcheck // passed through as is
+}
+
+func newProvider(t *testing.T, disabled string) (*Provider, *countingReader) {
+	t.Helper()
+
+	objects := []runtime.Object{}
+	for _, name := range []string{"default", "kube-system", "shop", "shipping"} {
+		objects = append(objects, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}})
+	}
+	reader := &countingReader{ClusterReader: fakecluster.New(fakecluster.Config{Objects: objects})}
+
+	filter, err := resourcefilter.NewFilter(disabled, reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return New(reader, filter), reader
+}
+
+func TestCompletePromptArgument(t *testing.T) {
+	t.Parallel()
+
+	provider, _ := newProvider(t, "secrets")
+
+	tests := []struct {
+		argument string
+		value    string
+		want     []string
+	}{
+		{"namespace", "sh", []string{"shipping", "shop"}},
+		{"client_namespace", "KUBE", []string{"kube-system"}},
+		{"context", "prod", []string{"prod", "prod-eu"}},
+		{"resource_type", "se", []string{"services"}},
+		{"pod", "web", []string{}},
+	}
+
+	for _, tt := range tests {
+		completion, err := provider.CompletePromptArgument(context.Background(), "debug_failing_pod",
+			mcp.CompleteArgument{Name: tt.argument, Value: tt.value}, mcp.CompleteContext{})
+		if err != nil {
+			t.Errorf("failed to complete %s=%q: %v", tt.argument, tt.value, err)
+			continue
+		}
+		if !reflect.DeepEqual(completion.Values, tt.want) {
+			t.Errorf("expected %s=%q to complete to %v, got %v", tt.argument, tt.value, tt.want, completion.Values)
+		}
+	}
+}
+
+func TestCompleteResourceArgument(t *testing.T) {
+	t.Parallel()
+
+	provider, _ := newProvider(t, "")
+
+	completion, err := provider.CompleteResourceArgument(context.Background(), "k8s://{context}/{namespace}/{type}",
+		mcp.CompleteArgument{Name: "namespace", Value: ""}, mcp.CompleteContext{Arguments: map[string]string{"context": "_"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"_", "default", "kube-system", "shipping", "shop"}
+	if !reflect.DeepEqual(completion.Values, want) || completion.Total != len(want) {
+		t.Errorf("expected the namespaces and the unset marker %v, got %+v", want, completion)
+	}
+
+	completion, err = provider.CompleteResourceArgument(context.Background(), "k8s://{context}/{namespace}/{type}",
+		mcp.CompleteArgument{Name: "type", Value: "deploy"}, mcp.CompleteContext{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(completion.Values, []string{"deployments"}) {
+		t.Errorf("expected the type to complete to deployments, got %v", completion.Values)
+	}
+
+	completion, err = provider.CompleteResourceArgument(context.Background(), "file:///{path}",
+		mcp.CompleteArgument{Name: "namespace"}, mcp.CompleteContext{})
+	if err != nil || len(completion.Values) != 0 {
+		t.Errorf("expected no completions for other resources, got %+v, %v", completion, err)
+	}
+}
+
+func TestCompleteCachesNames(t *testing.T) {
+	t.Parallel()
+
+	provider, reader := newProvider(t, "")
+	now := time.Now()
+	provider.now = func() time.Time { return now }
+
+	complete := func() {
+		t.Helper()
+		if _, err := provider.CompletePromptArgument(context.Background(), "capacity_review",
+			mcp.CompleteArgument{Name: "namespace", Value: "s"}, mcp.CompleteContext{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	complete()
+	complete()
+	if reader.namespaceLists != 1 {
+		t.Errorf("expected repeated completions to reuse the namespaces, got %d lists", reader.namespaceLists)
+	}
+
+	now = now.Add(cacheTTL)
+	complete()
+	if reader.namespaceLists != 2 {
+		t.Errorf("expected expired namespaces to be listed again, got %d lists", reader.namespaceLists)
+	}
+}
+
+func TestMatchCapsValues(t *testing.T) {
+	t.Parallel()
+
+	candidates := make([]string, 150)
+	for i := range candidates {
+		candidates[i] = "ns"
+	}
+
+	completion := match(candidates, "N")
+	if len(completion.Values) != maxValues || completion.Total != 150 || !completion.HasMore {
+		t.Errorf("expected %d of 150 values and more to come, got %d values, total %d, more %v",
+			maxValues, len(completion.Values), completion.Total, completion.HasMore)
+	}
+}
@@ -0,0 +1,193 @@
+// Package cors lets browser-based MCP clients reach the HTTP transports.
+// Browsers block cross-origin requests, and the preflight requests sent before
+// them, unless the server answers with CORS headers naming the page's origin.
+// The headers are only sent to origins the operator allows, so other web
+// pages a user visits cannot talk to the server from the user's browser.
+package cors
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultHeaders are the request headers MCP clients send: the JSON-RPC body
+// type, the bearer token, and the streamable HTTP session and protocol
+// headers. Allowed headers given in Config are added to these.
+var defaultHeaders = []string{
+	"Accept",
+	"Authorization",
+	"Content-Type",
+	"Last-Event-ID",
+	"Mcp-Protocol-Version",
+	"Mcp-Session-Id",
+}
+
+// exposedHeaders are the response headers browser clients need to read.
+var exposedHeaders = []string{
+	"Mcp-Session-Id",
+	"WWW-Authenticate",
+}
+
+// allowedMethods are the methods the MCP transports answer.
+var allowedMethods = []string{
+	http.MethodGet,
+	http.MethodPost,
+	http.MethodDelete,
+	http.MethodOptions,
+}
+
+// preflightMaxAge is how long browsers may cache a preflight answer.
+const preflightMaxAge = 10 * time.Minute
+
+// Config configures a Policy.
+type Config struct {
+	// AllowedOrigins are the origins, such as https://app.example.com, that
+	// may call the server. An entry may use a wildcard for one or more
+	// subdomain levels, as in https://*.example.com, and "*" allows every
+	// origin.
+	AllowedOrigins []string
+
+	// AllowedHeaders are request headers allowed in addition to the ones MCP
+	// clients send.
+	AllowedHeaders []string
+}
+
+// Policy answers preflight requests and adds CORS headers to responses for
+// allowed origins.
+type Policy struct {
+	anyOrigin bool
+	origins   map[string]bool
+	suffixes  []wildcard
+	headers   string
+}
+
+// wildcard is an origin with a subdomain wildcard, split around it.
+type wildcard struct {
+	scheme string
+	suffix string
+}
+
+// New validates cfg and creates a Policy from it.
+func New(cfg Config) (*Policy, error) {
+	if len(cfg.AllowedOrigins) == 0 {
+		return nil, errors.New("at least one allowed origin is required")
+	}
+
+	p := &Policy{origins: make(map[string]bool)}
+	for _, origin := range cfg.AllowedOrigins {
+		origin = strings.ToLower(strings.TrimSuffix(strings.TrimSpace(origin), "/"))
+		if origin == "*" {
+			p.anyOrigin = true
+			continue
+		}
+
+		if scheme, host, ok := strings.Cut(origin, "://*."); ok {
+			if err := validateOrigin(scheme + "://" + host); err != nil {
+				return nil, fmt.Errorf("invalid allowed origin %q: %w", origin, err)
+			}
+			p.suffixes = append(p.suffixes, wildcard{scheme: scheme + "://", suffix: "." + host})
+			continue
+		}
+
+		if err := validateOrigin(origin); err != nil {
+			return nil, fmt.Errorf("invalid allowed origin %q: %w", origin, err)
+		}
+		p.origins[origin] = true
+	}
+
+	headers := append([]string{}, defaultHeaders...)
+	for _, header := range cfg.AllowedHeaders {
+		if header = strings.TrimSpace(header); header != "" {
+			headers = append(headers, http.CanonicalHeaderKey(header))
+		}
+	}
+	p.headers = strings.Join(headers, ", ")
+
+	return p, nil
+}
+
+// validateOrigin checks that origin is a scheme and host, with an optional
+// port, and nothing else.
+func validateOrigin(origin string) error {
+	u, err := url.Parse(origin)
+	if err != nil {
+		return err //nolint:wrapcheck // url parse errors are self-descriptive
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return errors.New("the scheme must be http or https")
+	}
+	if u.Host == "" || u.Path != "" || u.RawQuery != "" || u.Fragment != "" || u.User != nil {
+		return errors.New("an origin is a scheme and host, such as https://app.example.com, with no path")
+	}
+	return nil
+}
+
+// allows reports whether origin may call the server.
+func (p *Policy) allows(origin string) bool {
+	if p.anyOrigin {
+		return true
+	}
+
+	origin = strings.ToLower(origin)
+	if p.origins[origin] {
+		return true
+	}
+	for _, w := range p.suffixes {
+		if host, ok := strings.CutPrefix(origin, w.scheme); ok && strings.HasSuffix(host, w.suffix) && len(host) > len(w.suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Middleware returns a handler that answers preflight requests itself and
+// adds CORS headers to the responses next writes for allowed origins. It
+// must wrap any authentication, since browsers send preflight requests
+// without credentials.
+func (p *Policy) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Add("Vary", "Origin")
+		allowed := p.allows(origin)
+		preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+
+		if preflight {
+			w.Header().Add("Vary", "Access-Control-Request-Method")
+			w.Header().Add("Vary", "Access-Control-Request-Headers")
+			if !allowed {
+				http.Error(w, "origin not allowed", http.StatusForbidden)
+				return
+			}
+			w.Header().Set("Access-Control-Allow-Origin", p.allowOrigin(origin))
+			w.Header().Set("Access-Control-Allow-Methods", strings.Join(allowedMethods, ", "))
+			w.Header().Set("Access-Control-Allow-Headers", p.headers)
+			w.Header().Set("Access-Control-Max-Age", strconv.Itoa(int(preflightMaxAge.Seconds())))
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		if allowed {
+			w.Header().Set("Access-Control-Allow-Origin", p.allowOrigin(origin))
+			w.Header().Set("Access-Control-Expose-Headers", strings.Join(exposedHeaders, ", "))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// allowOrigin is the Access-Control-Allow-Origin value for origin.
+func (p *Policy) allowOrigin(origin string) string {
+	if p.anyOrigin {
+		return "*"
+	}
+	return origin
+}
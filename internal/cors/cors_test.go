@@ -0,0 +1,154 @@
+package cors
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		origins []string
+		wantErr bool
+	}{
+		{name: "exact origin", origins: []string{"https://app.example.com"}},
+		{name: "origin with port and trailing slash", origins: []string{"http://localhost:5173/"}},
+		{name: "wildcard subdomain", origins: []string{"https://*.example.com"}},
+		{name: "any origin", origins: []string{"*"}},
+		{name: "no origins", wantErr: true},
+		{name: "path", origins: []string{"https://app.example.com/mcp"}, wantErr: true},
+		{name: "no scheme", origins: []string{"app.example.com"}, wantErr: true},
+		{name: "other scheme", origins: []string{"ftp://app.example.com"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := New(Config{AllowedOrigins: tt.origins})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestAllows(t *testing.T) {
+	t.Parallel()
+
+	policy, err := New(Config{AllowedOrigins: []string{"https://app.example.com", "https://*.internal.example.com", "http://localhost:5173"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		origin string
+		want   bool
+	}{
+		{origin: "https://app.example.com", want: true},
+		{origin: "HTTPS://APP.EXAMPLE.COM", want: true},
+		{origin: "http://app.example.com", want: false},
+		{origin: "https://app.example.com.evil.com", want: false},
+		{origin: "https://tools.internal.example.com", want: true},
+		{origin: "https://a.b.internal.example.com", want: true},
+		{origin: "https://internal.example.com", want: false},
+		{origin: "https://evilinternal.example.com", want: false},
+		{origin: "http://tools.internal.example.com", want: false},
+		{origin: "http://localhost:5173", want: true},
+		{origin: "http://localhost:3000", want: false},
+	}
+
+	for _, tt := range tests {
+		if got := policy.allows(tt.origin); got != tt.want {
+			t.Errorf("allows(%q) = %v, want %v", tt.origin, got, tt.want)
+		}
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	t.Parallel()
+
+	policy, err := New(Config{AllowedOrigins: []string{"https://app.example.com"}, AllowedHeaders: []string{"x-tenant"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var reached bool
+	handler := policy.Middleware(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		reached = true
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	serve := func(method, origin string, preflight bool) *httptest.ResponseRecorder {
+		reached = false
+		req := httptest.NewRequest(method, "/mcp", nil)
+		if origin != "" {
+			req.Header.Set("Origin", origin)
+		}
+		if preflight {
+			req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// A preflight from an allowed origin is answered without reaching the
+	// handler, which may require a token the browser does not send.
+	rec := serve(http.MethodOptions, "https://app.example.com", true)
+	if rec.Code != http.StatusNoContent || reached {
+		t.Fatalf("expected the preflight to be answered with 204, got %d (handler reached: %v)", rec.Code, reached)
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "https://app.example.com" {
+		t.Errorf("expected the origin to be allowed, got %q", got)
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Headers"); !strings.Contains(got, "Authorization") || !strings.Contains(got, "X-Tenant") {
+		t.Errorf("expected the default and configured headers, got %q", got)
+	}
+
+	rec = serve(http.MethodOptions, "https://evil.example.com", true)
+	if rec.Code != http.StatusForbidden || reached {
+		t.Fatalf("expected the preflight from another origin to be refused, got %d (handler reached: %v)", rec.Code, reached)
+	}
+
+	rec = serve(http.MethodPost, "https://app.example.com", false)
+	if !reached || rec.Header().Get("Access-Control-Allow-Origin") != "https://app.example.com" {
+		t.Errorf("expected the request to reach the handler with CORS headers, got %v", rec.Header())
+	}
+	if got := rec.Header().Get("Access-Control-Expose-Headers"); !strings.Contains(got, "Mcp-Session-Id") {
+		t.Errorf("expected the session header to be exposed, got %q", got)
+	}
+
+	rec = serve(http.MethodPost, "https://evil.example.com", false)
+	if !reached || rec.Header().Get("Access-Control-Allow-Origin") != "" {
+		t.Errorf("expected no CORS headers for another origin, got %v", rec.Header())
+	}
+
+	rec = serve(http.MethodPost, "", false)
+	if !reached || rec.Header().Get("Vary") != "" {
+		t.Errorf("expected requests without an origin to pass through untouched, got %v", rec.Header())
+	}
+}
+
+func TestMiddlewareAnyOrigin(t *testing.T) {
+	t.Parallel()
+
+	policy, err := New(Config{AllowedOrigins: []string{"*"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	handler := policy.Middleware(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {}))
+	req := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+	req.Header.Set("Origin", "https://anything.example.org")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "*" {
+		t.Errorf("expected every origin to be allowed, got %q", got)
+	}
+}
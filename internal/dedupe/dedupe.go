@@ -0,0 +1,145 @@
+// Package dedupe collapses identical tool calls made within a short window.
+// Agents frequently retry or repeat a call verbatim while looping; instead of
+// querying the cluster again, the first call's result is served to the
+// repeats, marked as cached in the result's _meta.
+package dedupe
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/response"
+)
+
+const (
+	// CachedMetaKey is the _meta field set to true on results served from the cache.
+	CachedMetaKey = "cached"
+
+	// CachedAgeMetaKey is the _meta field holding the age of a cached result, in seconds.
+	CachedAgeMetaKey = "cached_age_seconds"
+)
+
+// Cache remembers tool results for a fixed window, keyed by tool name and
+// arguments. Concurrent identical calls share a single execution. Error
+// results are never served past the call that produced them, so a retry
+// after a failure always reaches the cluster, and neither are results of
+// calls cancelled by their caller. It is safe for concurrent use.
+type Cache struct {
+	window time.Duration
+
+	// now returns the current time. It defaults to time.Now and is replaced
+	// in tests to expire entries without waiting.
+	now func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]*entry
+}
+
+// entry is a single call, either in flight or completed.
+type entry struct {
+	done     chan struct{}
+	result   *mcp.CallToolResult
+	err      error
+	finished time.Time
+}
+
+// New creates a Cache that serves repeated identical calls for window.
+func New(window time.Duration) *Cache {
+	return &Cache{
+		window:  window,
+		now:     time.Now,
+		entries: make(map[string]*entry),
+	}
+}
+
+// Window returns the configured deduplication window.
+func (c *Cache) Window() time.Duration {
+	return c.window
+}
+
+// Wrap returns a handler that deduplicates identical calls to the named tool.
+func (c *Cache) Wrap(toolName string, next func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error)) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		arguments, err := json.Marshal(request.GetArguments())
+		if err != nil {
+			return next(ctx, request)
+		}
+
+		// json.Marshal sorts map keys, so argument order does not matter
+		key := toolName + "\x00" + string(arguments)
+
+		c.mu.Lock()
+		c.sweepLocked()
+
+		if e, ok := c.entries[key]; ok {
+			c.mu.Unlock()
+
+			select {
+			case <-e.done:
+			case <-ctx.Done():
+				return nil, ctx.Err() //nolint:wrapcheck // cancellation is reported as is
+			}
+
+			if errors.Is(e.err, context.Canceled) || response.IsPartial(e.result) {
+				// The shared call was stopped by its own caller, which says
+				// nothing about this one.
+				return next(ctx, request)
+			}
+
+			if e.err != nil || e.result == nil || e.result.IsError {
+				// A retry after a failure must reach the cluster again, but
+				// callers that raced the failed call share its outcome.
+				return e.result, e.err
+			}
+
+			return c.markCached(e), nil
+		}
+
+		e := &entry{done: make(chan struct{})}
+		c.entries[key] = e
+		c.mu.Unlock()
+
+		e.result, e.err = next(ctx, request)
+
+		c.mu.Lock()
+		e.finished = c.now()
+		if e.err != nil || e.result == nil || e.result.IsError || response.IsPartial(e.result) {
+			delete(c.entries, key)
+		}
+		c.mu.Unlock()
+		close(e.done)
+
+		return e.result, e.err
+	}
+}
+
+// sweepLocked drops completed entries older than the window. The caller must
+// hold c.mu.
+func (c *Cache) sweepLocked() {
+	now := c.now()
+	for key, e := range c.entries {
+		select {
+		case <-e.done:
+			if now.Sub(e.finished) >= c.window {
+				delete(c.entries, key)
+			}
+		default:
+		}
+	}
+}
+
+// markCached returns a shallow copy of a cached result with the cached marker
+// added to its _meta, leaving the stored result untouched.
+func (c *Cache) markCached(e *entry) *mcp.CallToolResult {
+	c.mu.Lock()
+	age := c.now().Sub(e.finished)
+	c.mu.Unlock()
+
+	result := response.WithMeta(e.result, CachedMetaKey, true)
+	return response.WithMeta(result, CachedAgeMetaKey, age.Round(time.Millisecond).Seconds())
+}
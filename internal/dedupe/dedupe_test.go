@@ -0,0 +1,201 @@
+package dedupe
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/response"
+)
+
+func request(args map[string]any) mcp.CallToolRequest {
+	var r mcp.CallToolRequest
+	r.Params.Arguments = args
+	return r
+}
+
+func isCached(result *mcp.CallToolResult) bool {
+	if result.Meta == nil {
+		return false
+	}
+	cached, _ := result.Meta.AdditionalFields[CachedMetaKey].(bool)
+	return cached
+}
+
+func TestCacheServesRepeatedCalls(t *testing.T) {
+	t.Parallel()
+
+	cache := New(5 * time.Second)
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	cache.now = func() time.Time { return now }
+
+	var calls atomic.Int32
+	handler := cache.Wrap("list_resources", func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		calls.Add(1)
+		return mcp.NewToolResultText(`{"count":1}`), nil
+	})
+
+	first, err := handler(context.Background(), request(map[string]any{"resource_type": "pods", "namespace": "shop"}))
+	if err != nil || isCached(first) {
+		t.Fatalf("expected a fresh result, got %v, %v", first, err)
+	}
+
+	now = now.Add(2 * time.Second)
+
+	// Same arguments in a different order hit the cache
+	second, err := handler(context.Background(), request(map[string]any{"namespace": "shop", "resource_type": "pods"}))
+	if err != nil || !isCached(second) {
+		t.Fatalf("expected a cached result, got %v, %v", second, err)
+	}
+	if age := second.Meta.AdditionalFields[CachedAgeMetaKey]; age != 2.0 {
+		t.Errorf("expected cached age 2, got %v", age)
+	}
+	if isCached(first) {
+		t.Error("marking a cached result must not modify the stored result")
+	}
+
+	// Different arguments run the handler
+	if _, err := handler(context.Background(), request(map[string]any{"resource_type": "nodes"})); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Past the window the handler runs again
+	now = now.Add(5 * time.Second)
+	third, err := handler(context.Background(), request(map[string]any{"resource_type": "pods", "namespace": "shop"}))
+	if err != nil || isCached(third) {
+		t.Fatalf("expected a fresh result after the window, got %v, %v", third, err)
+	}
+
+	if got := calls.Load(); got != 3 {
+		t.Errorf("expected 3 handler calls, got %d", got)
+	}
+}
+
+func TestCacheSkipsErrors(t *testing.T) {
+	t.Parallel()
+
+	cache := New(time.Minute)
+
+	var calls atomic.Int32
+	failing := cache.Wrap("get_logs", func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		calls.Add(1)
+		return nil, errors.New("connection refused")
+	})
+	erroring := cache.Wrap("get_resource", func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		calls.Add(1)
+		return mcp.NewToolResultError("not found"), nil
+	})
+
+	for range 2 {
+		if _, err := failing(context.Background(), request(nil)); err == nil {
+			t.Fatal("expected an error")
+		}
+		if result, _ := erroring(context.Background(), request(nil)); isCached(result) {
+			t.Fatal("error results must not be cached")
+		}
+	}
+
+	if got := calls.Load(); got != 4 {
+		t.Errorf("expected every failing call to run, got %d calls", got)
+	}
+}
+
+func TestCacheSkipsCancelledCalls(t *testing.T) {
+	t.Parallel()
+
+	cache := New(time.Minute)
+
+	var calls atomic.Int32
+	partial := cache.Wrap("diagnose_pod", func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		calls.Add(1)
+		return response.Partial(mcp.NewToolResultText("{}")), nil
+	})
+
+	for range 2 {
+		if result, _ := partial(context.Background(), request(nil)); isCached(result) {
+			t.Fatal("partial results must not be cached")
+		}
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("expected every partial call to run, got %d calls", got)
+	}
+
+	// A call joining one that its caller cancels runs on its own.
+	calls.Store(0)
+	release := make(chan struct{})
+	blocking := cache.Wrap("get_logs", func(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		calls.Add(1)
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-release:
+			return mcp.NewToolResultText("{}"), nil
+		}
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan error, 1)
+	go func() {
+		_, err := blocking(ctx, request(nil))
+		first <- err
+	}()
+	for calls.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	second := make(chan error, 1)
+	go func() {
+		_, err := blocking(context.Background(), request(nil))
+		second <- err
+	}()
+
+	cancel()
+	if err := <-first; !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected the cancelled call to fail, got %v", err)
+	}
+	close(release)
+	if err := <-second; err != nil {
+		t.Errorf("expected the joining call to run on its own, got %v", err)
+	}
+}
+
+func TestCacheSharesInFlightCalls(t *testing.T) {
+	t.Parallel()
+
+	cache := New(time.Minute)
+
+	release := make(chan struct{})
+	var calls atomic.Int32
+	handler := cache.Wrap("get_node_metrics", func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		calls.Add(1)
+		<-release
+		return mcp.NewToolResultText("{}"), nil
+	})
+
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := handler(context.Background(), request(nil)); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+
+	// Wait for the first call to start before letting it finish
+	for calls.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	wg.Wait()
+
+	if got := calls.Load(); got != 1 {
+		t.Errorf("expected concurrent identical calls to share one execution, got %d", got)
+	}
+}
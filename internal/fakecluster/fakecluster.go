@@ -0,0 +1,385 @@
+// Package fakecluster builds kubernetes.Client instances backed by in-memory
+// fake clientsets, so tool handlers can be exercised end to end in tests
+// without an API server. It is only meant to be imported from tests.
+package fakecluster
+
+import (
+	"fmt"
+	"strings"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/version"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	metadatafake "k8s.io/client-go/metadata/fake"
+	"k8s.io/client-go/openapi"
+	k8stesting "k8s.io/client-go/testing"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+	metricsfake "k8s.io/metrics/pkg/client/clientset/versioned/fake"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/kubernetes"
+)
+
+// Config describes the contents of a fake cluster.
+type Config struct {
+	// Namespace is the client's default namespace.
+	Namespace string
+
+	// Objects are typed built-in objects (pods, nodes, deployments, ...).
+	// They are served by the typed clientset, the dynamic client, and the
+	// metadata client.
+	Objects []runtime.Object
+
+	// Custom are custom resources served only by the dynamic and metadata
+	// clients. Their resource types must be listed in APIResources to be
+	// resolvable.
+	Custom []*unstructured.Unstructured
+
+	// APIResources is what discovery reports. Defaults to DefaultAPIResources.
+	// Every group version listed is also reported as preferred, so when several
+	// versions of a type are listed, name resolution picks the first one.
+	APIResources []*metav1.APIResourceList
+
+	// ServerVersion is the version the API server reports (e.g., "v1.29.4").
+	// Defaults to the client-go build version.
+	ServerVersion string
+
+	// NodeMetrics and PodMetrics are served by the fake metrics-server client.
+	NodeMetrics []metricsv1beta1.NodeMetrics
+	PodMetrics  []metricsv1beta1.PodMetrics
+
+	// OpenAPISchemas are the OpenAPI v3 documents served per group version,
+	// keyed by group version (e.g., "v1", "apps/v1").
+	OpenAPISchemas map[string][]byte
+
+	// AllowedAccess lists the actions SelfSubjectAccessReviews allow; every
+	// other review is denied. Empty fields match any value.
+	AllowedAccess []authorizationv1.ResourceAttributes
+}
+
+// New builds a kubernetes.Client backed by fake clientsets seeded from cfg.
+// It panics if the seed objects are invalid, since that is a test bug.
+func New(cfg Config) *kubernetes.Client {
+	if cfg.APIResources == nil {
+		cfg.APIResources = DefaultAPIResources()
+	}
+
+	clientset := kubefake.NewClientset(cfg.Objects...)
+
+	// Access reviews are answered from the configured permissions, since the
+	// object tracker cannot store them.
+	clientset.PrependReactor("create", "selfsubjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		create, ok := action.(k8stesting.CreateAction)
+		if !ok {
+			return false, nil, nil
+		}
+		review, ok := create.GetObject().(*authorizationv1.SelfSubjectAccessReview)
+		if !ok {
+			return false, nil, nil
+		}
+
+		result := review.DeepCopy()
+		if attributes := review.Spec.ResourceAttributes; attributes != nil {
+			for _, allowed := range cfg.AllowedAccess {
+				if accessMatches(allowed, *attributes) {
+					result.Status.Allowed = true
+					result.Status.Reason = "allowed by fakecluster"
+					break
+				}
+			}
+		}
+		return true, result, nil
+	})
+
+	discovery, ok := clientset.Discovery().(*fakediscovery.FakeDiscovery)
+	if !ok {
+		panic("fakecluster: unexpected discovery client type")
+	}
+	discovery.Resources = cfg.APIResources
+	if cfg.ServerVersion != "" {
+		discovery.FakedServerVersion = &version.Info{GitVersion: cfg.ServerVersion}
+	}
+
+	metrics := metricsfake.NewSimpleClientset()
+	nodeMetricsGVR := metricsv1beta1.SchemeGroupVersion.WithResource("nodes")
+	podMetricsGVR := metricsv1beta1.SchemeGroupVersion.WithResource("pods")
+
+	// The generated metrics fakes query the "nodes" and "pods" resources, which
+	// the tracker cannot guess from the NodeMetrics and PodMetrics kinds, so
+	// seed the tracker with explicit resources.
+	for i := range cfg.NodeMetrics {
+		if err := metrics.Tracker().Create(nodeMetricsGVR, &cfg.NodeMetrics[i], ""); err != nil {
+			panic(fmt.Sprintf("fakecluster: failed to seed node metrics: %v", err))
+		}
+	}
+	for i := range cfg.PodMetrics {
+		if err := metrics.Tracker().Create(podMetricsGVR, &cfg.PodMetrics[i], cfg.PodMetrics[i].Namespace); err != nil {
+			panic(fmt.Sprintf("fakecluster: failed to seed pod metrics: %v", err))
+		}
+	}
+
+	return kubernetes.NewClientFromInterfaces(
+		clientset,
+		newDynamicClient(cfg),
+		newMetadataClient(cfg),
+		preferredDiscovery{FakeDiscovery: discovery, schemas: cfg.OpenAPISchemas},
+		metrics,
+		cfg.Namespace,
+	)
+}
+
+// accessMatches reports whether an allowed action covers the requested
+// attributes. Empty fields of allowed match any value.
+func accessMatches(allowed, requested authorizationv1.ResourceAttributes) bool {
+	matches := func(allowed, requested string) bool {
+		return allowed == "" || allowed == requested
+	}
+
+	return matches(allowed.Namespace, requested.Namespace) &&
+		matches(allowed.Verb, requested.Verb) &&
+		matches(allowed.Group, requested.Group) &&
+		matches(allowed.Resource, requested.Resource) &&
+		matches(allowed.Subresource, requested.Subresource) &&
+		matches(allowed.Name, requested.Name)
+}
+
+// preferredDiscovery makes the fake discovery client report its configured
+// resources as the server's preferred resources, which the upstream fake
+// leaves empty. Resource type resolution depends on it. It also serves the
+// configured OpenAPI v3 documents, where the upstream fake panics.
+type preferredDiscovery struct {
+	*fakediscovery.FakeDiscovery
+	schemas map[string][]byte
+}
+
+// ServerPreferredResources returns the configured API resources.
+func (d preferredDiscovery) ServerPreferredResources() ([]*metav1.APIResourceList, error) {
+	return d.Resources, nil
+}
+
+// OpenAPIV3 returns a client serving the configured OpenAPI v3 documents.
+func (d preferredDiscovery) OpenAPIV3() openapi.Client {
+	return openAPIClient(d.schemas)
+}
+
+// openAPIClient serves OpenAPI v3 documents keyed by group version.
+type openAPIClient map[string][]byte
+
+// Paths returns the documents under the discovery paths the API server uses.
+func (c openAPIClient) Paths() (map[string]openapi.GroupVersion, error) {
+	paths := make(map[string]openapi.GroupVersion, len(c))
+	for groupVersion, doc := range c {
+		path := "apis/" + groupVersion
+		if !strings.Contains(groupVersion, "/") {
+			path = "api/" + groupVersion
+		}
+		paths[path] = openAPIDocument{path: path, doc: doc}
+	}
+	return paths, nil
+}
+
+// openAPIDocument is a single group version's OpenAPI v3 document.
+type openAPIDocument struct {
+	path string
+	doc  []byte
+}
+
+// Schema returns the document regardless of the requested content type.
+func (d openAPIDocument) Schema(string) ([]byte, error) {
+	return d.doc, nil
+}
+
+// ServerRelativeURL returns the document's discovery path.
+func (d openAPIDocument) ServerRelativeURL() string {
+	return "/openapi/v3/" + d.path
+}
+
+// newDynamicClient builds a fake dynamic client serving the typed objects
+// (converted to unstructured) and the custom resources. Every resource listed
+// in discovery is registered with its list kind so listing an empty type works.
+func newDynamicClient(cfg Config) *dynamicfake.FakeDynamicClient {
+	scheme := runtime.NewScheme()
+	listKinds := make(map[schema.GroupVersionResource]string)
+
+	register := func(gvk schema.GroupVersionKind) {
+		if !scheme.Recognizes(gvk) {
+			scheme.AddKnownTypeWithName(gvk, &unstructured.Unstructured{})
+		}
+		listGVK := gvk.GroupVersion().WithKind(gvk.Kind + "List")
+		if !scheme.Recognizes(listGVK) {
+			scheme.AddKnownTypeWithName(listGVK, &unstructured.UnstructuredList{})
+		}
+	}
+
+	for _, list := range cfg.APIResources {
+		gv, err := schema.ParseGroupVersion(list.GroupVersion)
+		if err != nil {
+			panic(fmt.Sprintf("fakecluster: invalid group version %q: %v", list.GroupVersion, err))
+		}
+
+		for _, resource := range list.APIResources {
+			if strings.Contains(resource.Name, "/") {
+				continue
+			}
+			register(gv.WithKind(resource.Kind))
+			listKinds[gv.WithResource(resource.Name)] = resource.Kind + "List"
+		}
+	}
+
+	objects := make([]runtime.Object, 0, len(cfg.Objects)+len(cfg.Custom))
+
+	for _, obj := range cfg.Objects {
+		gvks, _, err := clientgoscheme.Scheme.ObjectKinds(obj)
+		if err != nil || len(gvks) == 0 {
+			panic(fmt.Sprintf("fakecluster: unknown object type %T: %v", obj, err))
+		}
+
+		content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		if err != nil {
+			panic(fmt.Sprintf("fakecluster: failed to convert %T: %v", obj, err))
+		}
+
+		u := &unstructured.Unstructured{Object: content}
+		u.SetGroupVersionKind(gvks[0])
+		register(gvks[0])
+		objects = append(objects, u)
+	}
+
+	for _, obj := range cfg.Custom {
+		register(obj.GroupVersionKind())
+		objects = append(objects, obj.DeepCopy())
+	}
+
+	return dynamicfake.NewSimpleDynamicClientWithCustomListKinds(scheme, listKinds, objects...)
+}
+
+// newMetadataClient builds a fake metadata client serving the object metadata
+// of the typed objects and the custom resources. Objects are stored under the
+// resource discovery lists for their kind, since the tracker cannot always
+// guess plural names from kinds.
+func newMetadataClient(cfg Config) *metadatafake.FakeMetadataClient {
+	scheme := metadatafake.NewTestScheme()
+	if err := metav1.AddMetaToScheme(scheme); err != nil {
+		panic(fmt.Sprintf("fakecluster: failed to build metadata scheme: %v", err))
+	}
+	client := metadatafake.NewSimpleMetadataClient(scheme)
+
+	resources := make(map[schema.GroupVersionKind]schema.GroupVersionResource)
+	for _, list := range cfg.APIResources {
+		gv, err := schema.ParseGroupVersion(list.GroupVersion)
+		if err != nil {
+			panic(fmt.Sprintf("fakecluster: invalid group version %q: %v", list.GroupVersion, err))
+		}
+
+		for _, resource := range list.APIResources {
+			if !strings.Contains(resource.Name, "/") {
+				resources[gv.WithKind(resource.Kind)] = gv.WithResource(resource.Name)
+			}
+		}
+	}
+
+	seed := func(gvk schema.GroupVersionKind, objectMeta metav1.ObjectMeta) {
+		gvr, ok := resources[gvk]
+		if !ok {
+			gvr, _ = meta.UnsafeGuessKindToResource(gvk)
+		}
+
+		obj := &metav1.PartialObjectMetadata{ObjectMeta: objectMeta}
+		obj.SetGroupVersionKind(gvk)
+		if err := client.Tracker().Create(gvr, obj, objectMeta.Namespace); err != nil {
+			panic(fmt.Sprintf("fakecluster: failed to seed metadata for %s %s: %v", gvk.Kind, objectMeta.Name, err))
+		}
+	}
+
+	for _, obj := range cfg.Objects {
+		gvks, _, err := clientgoscheme.Scheme.ObjectKinds(obj)
+		if err != nil || len(gvks) == 0 {
+			panic(fmt.Sprintf("fakecluster: unknown object type %T: %v", obj, err))
+		}
+
+		accessor, err := meta.Accessor(obj)
+		if err != nil {
+			panic(fmt.Sprintf("fakecluster: object %T has no metadata: %v", obj, err))
+		}
+		seed(gvks[0], objectMetaOf(accessor))
+	}
+
+	for _, obj := range cfg.Custom {
+		seed(obj.GroupVersionKind(), objectMetaOf(obj))
+	}
+
+	return client
+}
+
+// objectMetaOf copies the metadata fields tools read from an object.
+func objectMetaOf(obj metav1.Object) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:              obj.GetName(),
+		Namespace:         obj.GetNamespace(),
+		UID:               obj.GetUID(),
+		Labels:            obj.GetLabels(),
+		Annotations:       obj.GetAnnotations(),
+		OwnerReferences:   obj.GetOwnerReferences(),
+		Finalizers:        obj.GetFinalizers(),
+		CreationTimestamp: obj.GetCreationTimestamp(),
+		DeletionTimestamp: obj.GetDeletionTimestamp(),
+		ResourceVersion:   obj.GetResourceVersion(),
+		Generation:        obj.GetGeneration(),
+	}
+}
+
+// DefaultAPIResources returns discovery data for the built-in resource types
+// most tools touch: core pods, nodes, namespaces, services, config maps,
+// secrets, resource quotas, and events, plus the apps/v1 and batch/v1 workload
+// types.
+func DefaultAPIResources() []*metav1.APIResourceList {
+	return []*metav1.APIResourceList{
+		{
+			GroupVersion: "v1",
+			APIResources: []metav1.APIResource{
+				resource("pods", "pod", "Pod", true, "po"),
+				resource("nodes", "node", "Node", false, "no"),
+				resource("namespaces", "namespace", "Namespace", false, "ns"),
+				resource("services", "service", "Service", true, "svc"),
+				resource("configmaps", "configmap", "ConfigMap", true, "cm"),
+				resource("secrets", "secret", "Secret", true),
+				resource("resourcequotas", "resourcequota", "ResourceQuota", true, "quota"),
+				resource("events", "event", "Event", true, "ev"),
+			},
+		},
+		{
+			GroupVersion: "apps/v1",
+			APIResources: []metav1.APIResource{
+				resource("deployments", "deployment", "Deployment", true, "deploy"),
+				resource("statefulsets", "statefulset", "StatefulSet", true, "sts"),
+				resource("daemonsets", "daemonset", "DaemonSet", true, "ds"),
+				resource("replicasets", "replicaset", "ReplicaSet", true, "rs"),
+			},
+		},
+		{
+			GroupVersion: "batch/v1",
+			APIResources: []metav1.APIResource{
+				resource("jobs", "job", "Job", true),
+				resource("cronjobs", "cronjob", "CronJob", true, "cj"),
+			},
+		},
+	}
+}
+
+func resource(name, singular, kind string, namespaced bool, shortNames ...string) metav1.APIResource {
+	return metav1.APIResource{
+		Name:         name,
+		SingularName: singular,
+		Kind:         kind,
+		Namespaced:   namespaced,
+		ShortNames:   shortNames,
+		Verbs:        metav1.Verbs{"get", "list", "watch"},
+	}
+}
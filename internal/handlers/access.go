@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"github.com/mark3labs/mcp-go/mcp"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/kubernetes"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/resourcefilter"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/toolschema"
+)
+
+// The resource types the access tools read, checked against the resource
+// filter.
+var (
+	accessPodsGVR                = schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+	accessSecretsGVR             = schema.GroupVersionResource{Version: "v1", Resource: "secrets"}
+	accessServiceAccountsGVR     = schema.GroupVersionResource{Version: "v1", Resource: "serviceaccounts"}
+	accessRoleBindingsGVR        = schema.GroupVersionResource{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "rolebindings"}
+	accessClusterRoleBindingsGVR = schema.GroupVersionResource{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "clusterrolebindings"}
+	accessRolesGVR               = schema.GroupVersionResource{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "roles"}
+	accessClusterRolesGVR        = schema.GroupVersionResource{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "clusterroles"}
+)
+
+// AccessHandler provides MCP tools that answer identity and authorization
+// questions: which ServiceAccounts workloads run as, the credentials they
+// can use, and what RBAC grants them. Secret values are never returned.
+type AccessHandler struct {
+	client         kubernetes.ClusterReader
+	resourceFilter *resourcefilter.Filter
+	alwaysStart    bool
+}
+
+// NewAccessHandler creates a new AccessHandler with the provided Kubernetes client
+// and an optional resource filter for blocking access to specific resource types.
+// alwaysStart mirrors the --always-start flag: when true, connectivity and auth errors
+// are intercepted and returned as structured tool errors so the LLM can surface them
+// to the user rather than treating them as retryable failures.
+func NewAccessHandler(client kubernetes.ClusterReader, filter *resourcefilter.Filter, alwaysStart bool) *AccessHandler {
+	return &AccessHandler{
+		client:         client,
+		resourceFilter: filter,
+		alwaysStart:    alwaysStart,
+	}
+}
+
+// disabledResult returns the tool error for a resource type disabled by the
+// resource filter, or nil when gvr may be read.
+func (h *AccessHandler) disabledResult(resourceType string, gvr schema.GroupVersionResource) (*mcp.CallToolResult, error) {
+	return filterDisabledResult(h.resourceFilter, h.alwaysStart, resourceType, gvr)
+}
+
+// isDisabled reports whether gvr is disabled by the resource filter.
+func (h *AccessHandler) isDisabled(gvr schema.GroupVersionResource) bool {
+	return h.resourceFilter != nil && h.resourceFilter.IsDisabled(gvr)
+}
+
+// GetTools returns all identity and access MCP tools provided by this handler.
+func (h *AccessHandler) GetTools() []MCPTool {
+	return []MCPTool{
+		NewMCPTool(
+			mcp.NewTool("get_service_account_credentials",
+				mcp.WithDescription("Map the identity of a namespace or a single workload: which ServiceAccount each workload runs as, whether its API token is automounted, the bound (projected) tokens pods receive with their audiences and expirations, image pull secrets with the registries they cover, legacy long-lived token Secrets, workload identity annotations, and the RoleBindings and ClusterRoleBindings that grant it permissions, directly or through its groups. Secret values are never returned, only their names, types, and key names. Flags missing ServiceAccounts and pull secrets, long-lived tokens, and cluster-admin bindings."),
+				toolschema.Input[GetServiceAccountCredentialsParams](),
+				toolschema.Output[GetServiceAccountCredentialsResult](),
+			),
+			h.GetServiceAccountCredentials,
+		),
+		NewMCPTool(
+			mcp.NewTool("get_effective_permissions",
+				mcp.WithDescription("Resolve everything a user, group, or ServiceAccount is allowed to do: finds every RoleBinding and ClusterRoleBinding that applies to it, directly or through its groups (including the implicit ServiceAccount and system:authenticated groups), resolves the referenced Roles and ClusterRoles including aggregated ClusterRoles, and returns a deduplicated matrix of verbs per resource and namespace with the bindings that grant each. Flags bindings to missing roles, full wildcard access, Secret read access, and escalate, bind, or impersonate verbs."),
+				toolschema.Input[GetEffectivePermissionsParams](),
+				toolschema.Output[GetEffectivePermissionsResult](),
+			),
+			h.GetEffectivePermissions,
+		),
+	}
+}
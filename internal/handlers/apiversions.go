@@ -0,0 +1,256 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/connectivity"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/resourcefilter"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/response"
+)
+
+// ListAPIVersionsParams defines the parameters for the list_api_versions MCP tool.
+type ListAPIVersionsParams struct {
+	// Group restricts the inventory to a single API group. "core" selects
+	// the legacy core group.
+	Group string `json:"group,omitempty" description:"API group to report on, such as apps or cert-manager.io. Use 'core' for the core group (leave empty for all groups)"`
+
+	// Context specifies which Kubernetes context to use for this operation.
+	// If empty, uses the current context from kubeconfig.
+	Context string `json:"context,omitempty" description:"Kubernetes context to use (defaults to current context from kubeconfig)"`
+}
+
+// APIGroupInventory is an API group with the versions the cluster serves.
+type APIGroupInventory struct {
+	// Name is the group name, or "core" for the legacy core group.
+	Name string `json:"name"`
+
+	// PreferredVersion is the version clients such as kubectl use by default.
+	PreferredVersion string `json:"preferred_version"`
+
+	// Versions are the served versions, in the server's priority order.
+	Versions []string `json:"versions"`
+
+	// CustomResources are the CRDs that define resources in the group.
+	CustomResources []CRDVersionInventory `json:"custom_resources,omitempty"`
+}
+
+// CRDVersionInventory describes the versions of a CustomResourceDefinition.
+type CRDVersionInventory struct {
+	Name           string `json:"name"`
+	Kind           string `json:"kind"`
+	StorageVersion string `json:"storage_version"`
+
+	// StoredVersions are the versions objects may still be persisted as in
+	// etcd, from status.storedVersions.
+	StoredVersions []string            `json:"stored_versions,omitempty"`
+	Versions       []CRDVersionDetails `json:"versions"`
+	Findings       []string            `json:"findings,omitempty"`
+}
+
+// CRDVersionDetails is a single version declared by a CRD.
+type CRDVersionDetails struct {
+	Name               string `json:"name"`
+	Served             bool   `json:"served"`
+	Storage            bool   `json:"storage"`
+	Deprecated         bool   `json:"deprecated,omitempty"`
+	DeprecationWarning string `json:"deprecation_warning,omitempty"`
+}
+
+// ListAPIVersionsResult is the result of the list_api_versions MCP tool.
+type ListAPIVersionsResult struct {
+	Groups []APIGroupInventory `json:"groups"`
+	Count  int                 `json:"count"`
+
+	// CRDs counts the CustomResourceDefinitions reported in Groups.
+	CRDs     int      `json:"crds"`
+	Findings []string `json:"findings"`
+	Note     string   `json:"note"`
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// ListAPIVersions implements the list_api_versions MCP tool.
+// It lists every API group with its served versions and preferred version.
+// For groups defined by CRDs, it also reports each CRD's storage version and
+// the versions objects may still be stored as, which is what decides whether
+// an old version can be dropped. Discovery does not publish storage versions
+// for built-in groups, so only CRDs carry them.
+func (h *ResourceHandler) ListAPIVersions(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var params ListAPIVersionsParams
+	if err := bindParams(request, &params); err != nil {
+		return response.Errorf("failed to parse arguments: %s", err)
+	}
+
+	// Use the appropriate client based on context
+	client, err := h.client.ForContext(ctx, params.Context)
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to create client with context %s: %v", params.Context, err)
+	}
+
+	groupList, err := client.ServerGroups(ctx)
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to discover API groups: %v", err)
+	}
+
+	filter := params.Group
+	if strings.EqualFold(filter, "core") {
+		filter = ""
+	}
+
+	groups := make([]APIGroupInventory, 0, len(groupList.Groups))
+	byName := make(map[string]int, len(groupList.Groups))
+	for _, group := range groupList.Groups {
+		if params.Group != "" && group.Name != filter {
+			continue
+		}
+
+		name := group.Name
+		if name == "" {
+			name = "core"
+		}
+		inventory := APIGroupInventory{
+			Name:             name,
+			PreferredVersion: group.PreferredVersion.Version,
+			Versions:         make([]string, 0, len(group.Versions)),
+		}
+		for _, served := range group.Versions {
+			inventory.Versions = append(inventory.Versions, served.Version)
+		}
+
+		byName[group.Name] = len(groups)
+		groups = append(groups, inventory)
+	}
+
+	if params.Group != "" && len(groups) == 0 {
+		return response.Errorf("the cluster does not serve the API group %q; call with an empty group to list every group", params.Group)
+	}
+
+	var warnings []string
+	findings := make([]string, 0)
+	crdCount := 0
+
+	if h.resourceFilter != nil && h.resourceFilter.IsDisabled(crdGVR) {
+		warnings = append(warnings, fmt.Sprintf("%s are disabled by configuration, so CRD storage versions were not read", resourcefilter.FormatGVR(crdGVR)))
+	} else if list, err := client.ListResources(ctx, crdGVR, "", metav1.ListOptions{}); err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		warnings = append(warnings, fmt.Sprintf("failed to list CustomResourceDefinitions, so CRD storage versions were not read: %v", err))
+	} else {
+		for i := range list.Items {
+			crd := crdVersionInventory(&list.Items[i])
+			group, _, _ := unstructured.NestedString(list.Items[i].Object, "spec", "group")
+
+			index, ok := byName[group]
+			if !ok {
+				// Either filtered out, or a CRD that serves no version and so
+				// has no group in discovery.
+				if params.Group != "" {
+					continue
+				}
+				index = len(groups)
+				byName[group] = index
+				groups = append(groups, APIGroupInventory{Name: group, Versions: make([]string, 0)})
+			}
+
+			groups[index].CustomResources = append(groups[index].CustomResources, crd)
+			for _, finding := range crd.Findings {
+				findings = append(findings, crd.Name+": "+finding)
+			}
+			crdCount++
+		}
+	}
+
+	for i := range groups {
+		sort.Slice(groups[i].CustomResources, func(a, b int) bool {
+			return groups[i].CustomResources[a].Name < groups[i].CustomResources[b].Name
+		})
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if (groups[i].Name == "core") != (groups[j].Name == "core") {
+			return groups[i].Name == "core"
+		}
+		return groups[i].Name < groups[j].Name
+	})
+
+	return response.JSON(ListAPIVersionsResult{
+		Groups:   groups,
+		Count:    len(groups),
+		CRDs:     crdCount,
+		Findings: findings,
+		Note:     "Discovery does not publish the storage version of built-in API groups; storage versions are only reported for CustomResourceDefinitions.",
+		Warnings: warnings,
+	})
+}
+
+// crdVersionInventory reads the declared, served, storage, and stored
+// versions of a CRD, and flags the ones that get in the way of removing an
+// old version.
+func crdVersionInventory(obj *unstructured.Unstructured) CRDVersionInventory {
+	inventory := CRDVersionInventory{Name: obj.GetName(), Versions: make([]CRDVersionDetails, 0)}
+	inventory.Kind, _, _ = unstructured.NestedString(obj.Object, "spec", "names", "kind")
+	inventory.StoredVersions, _, _ = unstructured.NestedStringSlice(obj.Object, "status", "storedVersions")
+
+	versions, _, _ := unstructured.NestedSlice(obj.Object, "spec", "versions")
+	served := 0
+	for _, raw := range versions {
+		version, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		details := CRDVersionDetails{}
+		details.Name, _, _ = unstructured.NestedString(version, "name")
+		details.Served, _, _ = unstructured.NestedBool(version, "served")
+		details.Storage, _, _ = unstructured.NestedBool(version, "storage")
+		details.Deprecated, _, _ = unstructured.NestedBool(version, "deprecated")
+		details.DeprecationWarning, _, _ = unstructured.NestedString(version, "deprecationWarning")
+		if details.Name == "" {
+			continue
+		}
+
+		if details.Storage {
+			inventory.StorageVersion = details.Name
+		}
+		if details.Served {
+			served++
+		}
+		inventory.Versions = append(inventory.Versions, details)
+	}
+
+	for _, details := range inventory.Versions {
+		if details.Storage && !details.Served {
+			inventory.Findings = append(inventory.Findings, fmt.Sprintf("storage version %s is not served, so objects are written in a version clients cannot read back", details.Name))
+		}
+		if details.Served && details.Deprecated {
+			inventory.Findings = append(inventory.Findings, fmt.Sprintf("still serves deprecated version %s; move clients and manifests to %s", details.Name, inventory.StorageVersion))
+		}
+	}
+	if served == 0 {
+		inventory.Findings = append(inventory.Findings, "serves no version, so its custom resources are unreachable through the API")
+	}
+
+	var stale []string
+	for _, stored := range inventory.StoredVersions {
+		if stored != inventory.StorageVersion {
+			stale = append(stale, stored)
+		}
+	}
+	if len(stale) > 0 {
+		inventory.Findings = append(inventory.Findings, fmt.Sprintf("objects may still be stored as %s; migrate them to %s and remove the old versions from status.storedVersions before dropping them", strings.Join(stale, ", "), inventory.StorageVersion))
+	}
+
+	return inventory
+}
@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/fakecluster"
+)
+
+func TestCRDVersionInventory(t *testing.T) {
+	t.Parallel()
+
+	deprecated := crdVersion("v1beta1", true, false)
+	deprecated["deprecated"] = true
+	deprecated["deprecationWarning"] = "example.com/v1beta1 Widget is deprecated; use example.com/v1"
+
+	tests := []struct {
+		name   string
+		crd    *unstructured.Unstructured
+		stored []string
+		want   []string
+	}{
+		{
+			name:   "single version",
+			crd:    crdObject("example.com", "Widget", "widgets", "Namespaced", crdVersion("v1", true, true)),
+			stored: []string{"v1"},
+		},
+		{
+			name:   "old stored version and deprecated version",
+			crd:    crdObject("example.com", "Widget", "widgets", "Namespaced", deprecated, crdVersion("v1", true, true)),
+			stored: []string{"v1beta1", "v1"},
+			want: []string{
+				"still serves deprecated version v1beta1; move clients and manifests to v1",
+				"objects may still be stored as v1beta1; migrate them to v1 and remove the old versions from status.storedVersions before dropping them",
+			},
+		},
+		{
+			name: "storage version not served",
+			crd:  crdObject("example.com", "Widget", "widgets", "Namespaced", crdVersion("v1beta1", true, false), crdVersion("v1", false, true)),
+			want: []string{"storage version v1 is not served, so objects are written in a version clients cannot read back"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if tt.stored != nil {
+				stored := make([]interface{}, 0, len(tt.stored))
+				for _, version := range tt.stored {
+					stored = append(stored, version)
+				}
+				tt.crd.Object["status"] = map[string]interface{}{"storedVersions": stored}
+			}
+
+			got := crdVersionInventory(tt.crd)
+			if got.StorageVersion != "v1" || !reflect.DeepEqual(got.StoredVersions, tt.stored) {
+				t.Errorf("unexpected storage versions %+v", got)
+			}
+			if !reflect.DeepEqual(got.Findings, tt.want) {
+				t.Errorf("expected findings %q, got %q", tt.want, got.Findings)
+			}
+		})
+	}
+}
+
+func TestListAPIVersions_FakeCluster(t *testing.T) {
+	t.Parallel()
+
+	widgets := crdObject("example.com", "Widget", "widgets", "Namespaced", crdVersion("v1alpha1", true, false), crdVersion("v1", true, true))
+	widgets.Object["status"] = map[string]interface{}{"storedVersions": []interface{}{"v1alpha1", "v1"}}
+
+	apiResources := append(fakecluster.DefaultAPIResources(),
+		&metav1.APIResourceList{
+			GroupVersion: "apiextensions.k8s.io/v1",
+			APIResources: []metav1.APIResource{{Name: "customresourcedefinitions", Kind: "CustomResourceDefinition", Verbs: metav1.Verbs{"get", "list"}}},
+		},
+		&metav1.APIResourceList{
+			GroupVersion: "example.com/v1",
+			APIResources: []metav1.APIResource{{Name: "widgets", Kind: "Widget", Namespaced: true, Verbs: metav1.Verbs{"get", "list"}}},
+		},
+		&metav1.APIResourceList{
+			GroupVersion: "example.com/v1alpha1",
+			APIResources: []metav1.APIResource{{Name: "widgets", Kind: "Widget", Namespaced: true, Verbs: metav1.Verbs{"get", "list"}}},
+		},
+	)
+
+	handler := NewResourceHandler(fakecluster.New(fakecluster.Config{
+		Custom:       []*unstructured.Unstructured{widgets},
+		APIResources: apiResources,
+	}), nil, false)
+
+	result, isErr := callTool(t, handler.ListAPIVersions, map[string]any{})
+	if isErr {
+		t.Fatalf("unexpected error: %v", result["error"])
+	}
+
+	var groups []APIGroupInventory
+	decodeInto(t, result["groups"], &groups)
+
+	names := make([]string, 0, len(groups))
+	for _, group := range groups {
+		names = append(names, group.Name)
+	}
+	if want := []string{"core", "apiextensions.k8s.io", "apps", "batch", "example.com"}; !reflect.DeepEqual(names, want) {
+		t.Fatalf("expected groups %v, got %v", want, names)
+	}
+
+	example := groups[len(groups)-1]
+	if example.PreferredVersion != "v1" || !reflect.DeepEqual(example.Versions, []string{"v1", "v1alpha1"}) {
+		t.Errorf("unexpected versions %+v", example)
+	}
+	if len(example.CustomResources) != 1 || example.CustomResources[0].StorageVersion != "v1" || len(example.CustomResources[0].Findings) != 1 {
+		t.Errorf("unexpected custom resources %+v", example.CustomResources)
+	}
+
+	var findings []string
+	decodeInto(t, result["findings"], &findings)
+	if len(findings) != 1 || result["crds"] != float64(1) {
+		t.Errorf("unexpected findings %q in %+v", findings, result)
+	}
+
+	result, isErr = callTool(t, handler.ListAPIVersions, map[string]any{"group": "core"})
+	if isErr {
+		t.Fatalf("unexpected error: %v", result["error"])
+	}
+	groups = nil
+	decodeInto(t, result["groups"], &groups)
+	if len(groups) != 1 || groups[0].Name != "core" || groups[0].PreferredVersion != "v1" {
+		t.Errorf("unexpected core group %+v", groups)
+	}
+
+	if _, isErr := callTool(t, handler.ListAPIVersions, map[string]any{"group": "missing.example.com"}); !isErr {
+		t.Error("expected an error for a group the cluster does not serve")
+	}
+}
@@ -0,0 +1,299 @@
+package handlers
+
+import (
+	"context"
+	"sort"
+	"strconv"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/connectivity"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/kubernetes"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/response"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/toolschema"
+)
+
+// vpaAPIVersion is the API version of the VerticalPodAutoscaler CRD installed
+// by the Kubernetes autoscaler project.
+const vpaAPIVersion = "autoscaling.k8s.io/v1"
+
+// AutoscalingHandler provides MCP tools for inspecting autoscaling objects such
+// as VerticalPodAutoscalers, whose CRDs may or may not be installed.
+type AutoscalingHandler struct {
+	client      kubernetes.ClusterReader
+	alwaysStart bool
+}
+
+// NewAutoscalingHandler creates a new AutoscalingHandler with the provided Kubernetes client.
+// alwaysStart mirrors the --always-start flag: when true, connectivity and auth errors
+// are intercepted and returned as structured tool errors so the LLM can surface them
+// to the user rather than treating them as retryable failures.
+func NewAutoscalingHandler(client kubernetes.ClusterReader, alwaysStart bool) *AutoscalingHandler {
+	return &AutoscalingHandler{
+		client:      client,
+		alwaysStart: alwaysStart,
+	}
+}
+
+// GetVPARecommendationsParams defines the parameters for the get_vpa_recommendations MCP tool.
+type GetVPARecommendationsParams struct {
+	// Namespace restricts the VPAs to a namespace.
+	// If empty, VPAs across all namespaces are returned.
+	Namespace string `json:"namespace,omitempty" description:"Namespace to list VPAs from (leave empty for all namespaces)"`
+
+	// Name restricts the results to a single VPA.
+	Name string `json:"name,omitempty" description:"Specific VPA name to get (requires namespace)"`
+
+	// Context specifies which Kubernetes context to use for this operation.
+	// If empty, uses the current context from kubeconfig.
+	Context string `json:"context,omitempty" description:"Kubernetes context to use (defaults to current context from kubeconfig)"`
+}
+
+// VPAContainerRecommendation pairs a container's VPA recommendation with the
+// requests currently configured on the target workload.
+type VPAContainerRecommendation struct {
+	Container      string            `json:"container"`
+	Target         map[string]string `json:"target,omitempty"`
+	LowerBound     map[string]string `json:"lower_bound,omitempty"`
+	UpperBound     map[string]string `json:"upper_bound,omitempty"`
+	UncappedTarget map[string]string `json:"uncapped_target,omitempty"`
+
+	// CurrentRequests are the requests from the target workload's pod template.
+	CurrentRequests map[string]string `json:"current_requests,omitempty"`
+}
+
+// VPARecommendation summarizes a single VerticalPodAutoscaler object.
+type VPARecommendation struct {
+	Name       string                       `json:"name"`
+	Namespace  string                       `json:"namespace"`
+	Target     string                       `json:"target"`
+	UpdateMode string                       `json:"update_mode,omitempty"`
+	Containers []VPAContainerRecommendation `json:"containers"`
+
+	// TargetError is set when the target workload could not be read, in which
+	// case current requests are not available.
+	TargetError string `json:"target_error,omitempty"`
+}
+
+// GetVPARecommendationsResult is the result of the get_vpa_recommendations
+// MCP tool. When the VerticalPodAutoscaler CRD is not installed, only
+// Installed and Message are set.
+type GetVPARecommendationsResult struct {
+	Installed bool                `json:"installed"`
+	Message   string              `json:"message,omitempty"`
+	Namespace string              `json:"namespace,omitempty"`
+	Count     int                 `json:"count"`
+	Items     []VPARecommendation `json:"items,omitzero"`
+}
+
+// GetVPARecommendations implements the get_vpa_recommendations MCP tool.
+// It lists VerticalPodAutoscaler objects and reports their target, lower, and
+// upper bound recommendations next to the requests currently configured on the
+// workload they target. If the VPA CRD is not installed, it returns a result
+// saying so instead of an error.
+func (h *AutoscalingHandler) GetVPARecommendations(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var params GetVPARecommendationsParams
+	if err := bindParams(request, &params); err != nil {
+		return response.Errorf("failed to parse arguments: %s", err)
+	}
+
+	// Use the appropriate client based on context
+	client, err := h.client.ForContext(ctx, params.Context)
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to create client with context %s: %v", params.Context, err)
+	}
+
+	gvr, err := client.ResolveResourceType("verticalpodautoscalers", vpaAPIVersion)
+	if err != nil {
+		if connectivity.IsError(err) {
+			if h.alwaysStart {
+				return response.Error(connectivity.ErrorMessage(err))
+			}
+			return response.Errorf("failed to discover vertical pod autoscaler API: %v", err)
+		}
+
+		// Discovery worked but the resource type is unknown: VPA isn't installed.
+		return response.JSON(GetVPARecommendationsResult{
+			Installed: false,
+			Message:   "The VerticalPodAutoscaler CRD (" + vpaAPIVersion + ") is not installed in this cluster, so there are no VPA recommendations to report.",
+		})
+	}
+
+	var items []unstructured.Unstructured
+	if params.Name != "" {
+		vpa, err := client.GetResource(ctx, gvr, params.Namespace, params.Name)
+		if err != nil {
+			if h.alwaysStart && connectivity.IsTransportError(err) {
+				return response.Error(connectivity.ErrorMessage(err))
+			}
+			return response.Errorf("failed to get vertical pod autoscaler: %v", err)
+		}
+		items = []unstructured.Unstructured{*vpa}
+	} else {
+		list, err := client.ListResources(ctx, gvr, params.Namespace, metav1.ListOptions{})
+		if err != nil {
+			if h.alwaysStart && connectivity.IsTransportError(err) {
+				return response.Error(connectivity.ErrorMessage(err))
+			}
+			return response.Errorf("failed to list vertical pod autoscalers: %v", err)
+		}
+		items = list.Items
+	}
+
+	results := make([]VPARecommendation, 0, len(items))
+	for i := range items {
+		if ctx.Err() != nil {
+			break
+		}
+		vpa := parseVPA(&items[i])
+
+		requests, err := h.workloadRequests(ctx, client, &items[i])
+		if err != nil {
+			if h.alwaysStart && connectivity.IsTransportError(err) {
+				return response.Error(connectivity.ErrorMessage(err))
+			}
+			vpa.TargetError = err.Error()
+		}
+
+		for j := range vpa.Containers {
+			vpa.Containers[j].CurrentRequests = requests[vpa.Containers[j].Container]
+		}
+
+		results = append(results, vpa)
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Namespace != results[j].Namespace {
+			return results[i].Namespace < results[j].Namespace
+		}
+		return results[i].Name < results[j].Name
+	})
+
+	return response.JSON(GetVPARecommendationsResult{
+		Installed: true,
+		Namespace: params.Namespace,
+		Count:     len(results),
+		Items:     results,
+	})
+}
+
+// workloadRequests fetches the workload referenced by a VPA's spec.targetRef
+// and returns the resource requests of each container in its pod template.
+func (h *AutoscalingHandler) workloadRequests(ctx context.Context, client kubernetes.ClusterReader, vpa *unstructured.Unstructured) (map[string]map[string]string, error) {
+	kind, _, _ := unstructured.NestedString(vpa.Object, "spec", "targetRef", "kind")
+	name, _, _ := unstructured.NestedString(vpa.Object, "spec", "targetRef", "name")
+	apiVersion, _, _ := unstructured.NestedString(vpa.Object, "spec", "targetRef", "apiVersion")
+
+	if kind == "" || name == "" {
+		return nil, nil
+	}
+
+	gvr, err := client.ResolveResourceType(kind, apiVersion)
+	if err != nil {
+		return nil, err //nolint:wrapcheck // resolution errors already describe the resource type
+	}
+
+	workload, err := client.GetResource(ctx, gvr, vpa.GetNamespace(), name)
+	if err != nil {
+		return nil, err //nolint:wrapcheck // kubernetes API errors are self-descriptive
+	}
+
+	return podTemplateRequests(workload.Object), nil
+}
+
+// parseVPA extracts the target reference, update mode, and per-container
+// recommendations from an unstructured VerticalPodAutoscaler.
+func parseVPA(vpa *unstructured.Unstructured) VPARecommendation {
+	kind, _, _ := unstructured.NestedString(vpa.Object, "spec", "targetRef", "kind")
+	name, _, _ := unstructured.NestedString(vpa.Object, "spec", "targetRef", "name")
+	mode, _, _ := unstructured.NestedString(vpa.Object, "spec", "updatePolicy", "updateMode")
+
+	result := VPARecommendation{
+		Name:       vpa.GetName(),
+		Namespace:  vpa.GetNamespace(),
+		Target:     kind + "/" + name,
+		UpdateMode: mode,
+		Containers: []VPAContainerRecommendation{},
+	}
+
+	recommendations, _, _ := unstructured.NestedSlice(vpa.Object, "status", "recommendation", "containerRecommendations")
+	for _, raw := range recommendations {
+		rec, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		container, _, _ := unstructured.NestedString(rec, "containerName")
+		result.Containers = append(result.Containers, VPAContainerRecommendation{
+			Container:      container,
+			Target:         nestedStringMap(rec, "target"),
+			LowerBound:     nestedStringMap(rec, "lowerBound"),
+			UpperBound:     nestedStringMap(rec, "upperBound"),
+			UncappedTarget: nestedStringMap(rec, "uncappedTarget"),
+		})
+	}
+
+	return result
+}
+
+// podTemplateRequests returns the resource requests of each container in an
+// unstructured workload's spec.template.spec.containers, keyed by container name.
+func podTemplateRequests(workload map[string]interface{}) map[string]map[string]string {
+	requests := make(map[string]map[string]string)
+
+	containers, _, _ := unstructured.NestedSlice(workload, "spec", "template", "spec", "containers")
+	for _, raw := range containers {
+		container, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		name, _, _ := unstructured.NestedString(container, "name")
+		requests[name] = nestedStringMap(container, "resources", "requests")
+	}
+
+	return requests
+}
+
+// nestedStringMap reads a map of scalar values (such as a resource list) from
+// obj at the given path and renders each value as a string. It returns nil
+// when the path does not exist.
+func nestedStringMap(obj map[string]interface{}, fields ...string) map[string]string {
+	raw, found, err := unstructured.NestedMap(obj, fields...)
+	if err != nil || !found {
+		return nil
+	}
+
+	out := make(map[string]string, len(raw))
+	for key, value := range raw {
+		switch v := value.(type) {
+		case string:
+			out[key] = v
+		case int64:
+			out[key] = strconv.FormatInt(v, 10)
+		case float64:
+			out[key] = strconv.FormatFloat(v, 'f', -1, 64)
+		}
+	}
+
+	return out
+}
+
+// GetTools returns all autoscaling-related MCP tools provided by this handler.
+func (h *AutoscalingHandler) GetTools() []MCPTool {
+	return []MCPTool{
+		NewMCPTool(
+			mcp.NewTool("get_vpa_recommendations",
+				mcp.WithDescription("List VerticalPodAutoscaler objects and their target/lower/upper bound recommendations per container, alongside the requests currently configured on the target workload. Reports installed=false if the VPA CRD is not present in the cluster."),
+				toolschema.Input[GetVPARecommendationsParams](),
+				toolschema.Output[GetVPARecommendationsResult](),
+			),
+			h.GetVPARecommendations,
+		),
+	}
+}
@@ -0,0 +1,219 @@
+package handlers
+
+import (
+	"reflect"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/fakecluster"
+)
+
+func TestParseVPA(t *testing.T) {
+	t.Parallel()
+
+	vpa := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "autoscaling.k8s.io/v1",
+		"kind":       "VerticalPodAutoscaler",
+		"metadata": map[string]interface{}{
+			"name":      "web-vpa",
+			"namespace": "shop",
+		},
+		"spec": map[string]interface{}{
+			"targetRef": map[string]interface{}{
+				"apiVersion": "apps/v1",
+				"kind":       "Deployment",
+				"name":       "web",
+			},
+			"updatePolicy": map[string]interface{}{
+				"updateMode": "Off",
+			},
+		},
+		"status": map[string]interface{}{
+			"recommendation": map[string]interface{}{
+				"containerRecommendations": []interface{}{
+					map[string]interface{}{
+						"containerName": "app",
+						"target":        map[string]interface{}{"cpu": "250m", "memory": "262144k"},
+						"lowerBound":    map[string]interface{}{"cpu": "100m", "memory": "131072k"},
+						"upperBound":    map[string]interface{}{"cpu": "1", "memory": "1Gi"},
+					},
+				},
+			},
+		},
+	}}
+
+	got := parseVPA(vpa)
+
+	want := VPARecommendation{
+		Name:       "web-vpa",
+		Namespace:  "shop",
+		Target:     "Deployment/web",
+		UpdateMode: "Off",
+		Containers: []VPAContainerRecommendation{
+			{
+				Container:  "app",
+				Target:     map[string]string{"cpu": "250m", "memory": "262144k"},
+				LowerBound: map[string]string{"cpu": "100m", "memory": "131072k"},
+				UpperBound: map[string]string{"cpu": "1", "memory": "1Gi"},
+			},
+		},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseVPA mismatch\nwant: %#v\ngot:  %#v", want, got)
+	}
+}
+
+func TestParseVPA_NoRecommendation(t *testing.T) {
+	t.Parallel()
+
+	vpa := &unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "fresh", "namespace": "default"},
+		"spec": map[string]interface{}{
+			"targetRef": map[string]interface{}{"kind": "StatefulSet", "name": "db"},
+		},
+	}}
+
+	got := parseVPA(vpa)
+	if got.Target != "StatefulSet/db" {
+		t.Errorf("expected target StatefulSet/db, got %q", got.Target)
+	}
+	if got.Containers == nil || len(got.Containers) != 0 {
+		t.Errorf("expected empty non-nil containers, got %#v", got.Containers)
+	}
+}
+
+func TestPodTemplateRequests(t *testing.T) {
+	t.Parallel()
+
+	workload := map[string]interface{}{
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"containers": []interface{}{
+						map[string]interface{}{
+							"name": "app",
+							"resources": map[string]interface{}{
+								"requests": map[string]interface{}{"cpu": "500m", "memory": int64(1024)},
+							},
+						},
+						map[string]interface{}{"name": "sidecar"},
+					},
+				},
+			},
+		},
+	}
+
+	got := podTemplateRequests(workload)
+	want := map[string]map[string]string{
+		"app":     {"cpu": "500m", "memory": "1024"},
+		"sidecar": nil,
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("podTemplateRequests mismatch\nwant: %#v\ngot:  %#v", want, got)
+	}
+}
+
+func TestGetVPARecommendations_NotInstalled(t *testing.T) {
+	t.Parallel()
+
+	handler := NewAutoscalingHandler(fakecluster.New(fakecluster.Config{}), false)
+
+	result, isErr := callTool(t, handler.GetVPARecommendations, map[string]any{})
+	if isErr {
+		t.Fatalf("unexpected error: %v", result["error"])
+	}
+	if result["installed"] != false {
+		t.Errorf("expected installed=false, got %v", result["installed"])
+	}
+}
+
+func TestGetVPARecommendations_FakeCluster(t *testing.T) {
+	t.Parallel()
+
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop"},
+		Spec: appsv1.DeploymentSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{
+						Name: "app",
+						Resources: corev1.ResourceRequirements{
+							Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m")},
+						},
+					}},
+				},
+			},
+		},
+	}
+
+	vpa := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "autoscaling.k8s.io/v1",
+		"kind":       "VerticalPodAutoscaler",
+		"metadata":   map[string]interface{}{"name": "web-vpa", "namespace": "shop"},
+		"spec": map[string]interface{}{
+			"targetRef": map[string]interface{}{"apiVersion": "apps/v1", "kind": "Deployment", "name": "web"},
+		},
+		"status": map[string]interface{}{
+			"recommendation": map[string]interface{}{
+				"containerRecommendations": []interface{}{
+					map[string]interface{}{"containerName": "app", "target": map[string]interface{}{"cpu": "250m"}},
+				},
+			},
+		},
+	}}
+
+	apiResources := append(fakecluster.DefaultAPIResources(), &metav1.APIResourceList{
+		GroupVersion: "autoscaling.k8s.io/v1",
+		APIResources: []metav1.APIResource{{
+			Name:         "verticalpodautoscalers",
+			SingularName: "verticalpodautoscaler",
+			Kind:         "VerticalPodAutoscaler",
+			Namespaced:   true,
+			ShortNames:   []string{"vpa"},
+			Verbs:        metav1.Verbs{"get", "list"},
+		}},
+	})
+
+	handler := NewAutoscalingHandler(fakecluster.New(fakecluster.Config{
+		Objects:      []runtime.Object{deployment},
+		Custom:       []*unstructured.Unstructured{vpa},
+		APIResources: apiResources,
+	}), false)
+
+	result, isErr := callTool(t, handler.GetVPARecommendations, map[string]any{"namespace": "shop"})
+	if isErr {
+		t.Fatalf("unexpected error: %v", result["error"])
+	}
+
+	items, _ := result["items"].([]any)
+	if len(items) != 1 {
+		t.Fatalf("expected 1 VPA, got %v", result["items"])
+	}
+
+	item, _ := items[0].(map[string]any)
+	if item["target"] != "Deployment/web" {
+		t.Errorf("expected target Deployment/web, got %v", item["target"])
+	}
+	if item["target_error"] != nil {
+		t.Errorf("unexpected target error: %v", item["target_error"])
+	}
+
+	containers, _ := item["containers"].([]any)
+	if len(containers) != 1 {
+		t.Fatalf("expected 1 container recommendation, got %v", item["containers"])
+	}
+
+	container, _ := containers[0].(map[string]any)
+	want := map[string]any{"cpu": "500m"}
+	if !reflect.DeepEqual(container["current_requests"], want) {
+		t.Errorf("expected current requests %v, got %v", want, container["current_requests"])
+	}
+}
@@ -0,0 +1,237 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/connectivity"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/kubernetes"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/response"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/toolschema"
+)
+
+// ServerSettings describes how this server instance was configured. It is
+// filled from flags and environment variables at startup and reported by the
+// server_capabilities tool.
+type ServerSettings struct {
+	// Version is the version of this server binary.
+	Version string
+
+	// Transport is the MCP transport the server is listening on.
+	Transport string
+
+	// Policies are the restrictions in effect for every tool call.
+	Policies ServerPolicies
+
+	// Features reports which optional features are enabled, by name.
+	Features map[string]bool
+
+	// Settings holds the values of tunable options, such as intervals.
+	Settings map[string]string
+}
+
+// ServerPolicies are the access restrictions of a server instance.
+type ServerPolicies struct {
+	// ReadOnly is always true: the server exposes no tool that writes to the
+	// cluster.
+	ReadOnly bool `json:"read_only"`
+
+	// DefaultNamespace is the namespace used when a tool call omits one. It
+	// is a default, not a restriction.
+	DefaultNamespace string `json:"default_namespace,omitempty"`
+
+	// DisabledTools are the tool names disabled by configuration.
+	DisabledTools []string `json:"disabled_tools"`
+
+	// DisabledResources are the resource types that tools refuse to read, as
+	// configured.
+	DisabledResources []string `json:"disabled_resources"`
+
+	// AllowedContexts are the kubeconfig contexts the context parameter may
+	// select, besides the current one. Empty means every context.
+	AllowedContexts []string `json:"allowed_contexts,omitempty"`
+
+	// ImpersonateUser and ImpersonateGroups are the identity every request
+	// impersonates, so the cluster authorizes reads with its permissions.
+	ImpersonateUser   string   `json:"impersonate_user,omitempty"`
+	ImpersonateGroups []string `json:"impersonate_groups,omitempty"`
+}
+
+// ConnectedCluster describes the kubeconfig context the server reads from
+// and the Kubernetes version of its API server.
+type ConnectedCluster struct {
+	Context       string `json:"context,omitempty"`
+	Cluster       string `json:"cluster,omitempty"`
+	User          string `json:"user,omitempty"`
+	Namespace     string `json:"namespace,omitempty"`
+	ServerVersion string `json:"server_version,omitempty"`
+}
+
+// ServerInfo identifies the server binary and its transport.
+type ServerInfo struct {
+	Name      string `json:"name"`
+	Version   string `json:"version"`
+	Transport string `json:"transport"`
+}
+
+// ToolsInfo lists the tools registered on the server and those disabled by
+// configuration.
+type ToolsInfo struct {
+	Enabled  []string `json:"enabled"`
+	Count    int      `json:"count"`
+	Disabled []string `json:"disabled"`
+}
+
+// ServerCapabilitiesResult is the result of the server_capabilities MCP tool.
+type ServerCapabilitiesResult struct {
+	Server   ServerInfo        `json:"server"`
+	Cluster  ConnectedCluster  `json:"cluster"`
+	Tools    ToolsInfo         `json:"tools"`
+	Policies ServerPolicies    `json:"policies"`
+	Features map[string]bool   `json:"features"`
+	Settings map[string]string `json:"settings,omitempty"`
+	Warnings []string          `json:"warnings,omitempty"`
+}
+
+// ServerCapabilitiesParams defines the parameters for the server_capabilities MCP tool.
+type ServerCapabilitiesParams struct {
+	// Context specifies which Kubernetes context to report on.
+	// If empty, uses the current context from kubeconfig.
+	Context string `json:"context,omitempty" description:"Kubernetes context to report on (defaults to current context from kubeconfig)"`
+}
+
+// CapabilitiesHandler provides the MCP tool that describes what this server
+// deployment allows, so clients and users can introspect it.
+type CapabilitiesHandler struct {
+	client      kubernetes.ClusterReader
+	alwaysStart bool
+	settings    ServerSettings
+
+	// tools returns the names of the tools registered on the server.
+	tools func() []string
+}
+
+// NewCapabilitiesHandler creates a new CapabilitiesHandler. settings is
+// reported as is, and tools is called on every request to list the tools
+// the server registered.
+// alwaysStart mirrors the --always-start flag: when true, connectivity and auth errors
+// are intercepted and returned as structured tool errors so the LLM can surface them
+// to the user rather than treating them as retryable failures.
+func NewCapabilitiesHandler(client kubernetes.ClusterReader, alwaysStart bool, settings ServerSettings, tools func() []string) *CapabilitiesHandler {
+	return &CapabilitiesHandler{
+		client:      client,
+		alwaysStart: alwaysStart,
+		settings:    settings,
+		tools:       tools,
+	}
+}
+
+// ServerCapabilities implements the server_capabilities MCP tool.
+// It reports the server version and transport, the enabled and disabled
+// tools, the policies in effect, the enabled features, and the context and
+// Kubernetes version of the connected cluster. Cluster details are best
+// effort: failures to read them are reported as warnings.
+func (h *CapabilitiesHandler) ServerCapabilities(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var params ServerCapabilitiesParams
+	if err := bindParams(request, &params); err != nil {
+		return response.Errorf("failed to parse arguments: %s", err)
+	}
+
+	// Use the appropriate client based on context
+	client, err := h.client.ForContext(ctx, params.Context)
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to create client with context %s: %v", params.Context, err)
+	}
+
+	var warnings []string
+
+	cluster, err := kubeconfigCluster(client, params.Context)
+	if err != nil {
+		warnings = append(warnings, fmt.Sprintf("failed to read kubeconfig contexts: %v", err))
+	}
+
+	if info, err := client.ServerVersion(); err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			warnings = append(warnings, connectivity.ErrorMessage(err))
+		} else {
+			warnings = append(warnings, fmt.Sprintf("failed to get the Kubernetes server version: %v", err))
+		}
+	} else {
+		cluster.ServerVersion = info.GitVersion
+	}
+
+	enabled := make([]string, 0)
+	if h.tools != nil {
+		enabled = append(enabled, h.tools()...)
+	}
+	sort.Strings(enabled)
+
+	return response.JSON(ServerCapabilitiesResult{
+		Server: ServerInfo{
+			Name:      "mcp-kubernetes-ro",
+			Version:   h.settings.Version,
+			Transport: h.settings.Transport,
+		},
+		Cluster: cluster,
+		Tools: ToolsInfo{
+			Enabled:  enabled,
+			Count:    len(enabled),
+			Disabled: h.settings.Policies.DisabledTools,
+		},
+		Policies: h.settings.Policies,
+		Features: h.settings.Features,
+		Settings: h.settings.Settings,
+		Warnings: warnings,
+	})
+}
+
+// kubeconfigCluster describes the kubeconfig context named contextName, or
+// the current context when it is empty. The server version is left empty.
+func kubeconfigCluster(client kubernetes.ClusterReader, contextName string) (ConnectedCluster, error) {
+	cluster := ConnectedCluster{Context: contextName}
+
+	contexts, err := client.ListContexts()
+	if err != nil {
+		return cluster, err //nolint:wrapcheck // callers add context
+	}
+
+	for _, kubeContext := range contexts {
+		if (contextName == "" && kubeContext.Current) || kubeContext.Name == contextName {
+			cluster.Context = kubeContext.Name
+			cluster.Cluster = kubeContext.Cluster
+			cluster.User = kubeContext.User
+			cluster.Namespace = kubeContext.Namespace
+			break
+		}
+	}
+
+	return cluster, nil
+}
+
+// GetTools returns the capabilities MCP tools provided by this handler.
+func (h *CapabilitiesHandler) GetTools() []MCPTool {
+	return []MCPTool{
+		NewMCPTool(
+			mcp.NewTool("server_capabilities",
+				mcp.WithDescription("Describe what this server deployment allows: server version and transport, enabled and disabled tools, active policies (read-only access, default namespace, disabled resources, allowed contexts), optional features that are on or off, and the connected kubeconfig context and Kubernetes version. Call it first when unsure whether a tool or resource type is available."),
+				toolschema.Input[ServerCapabilitiesParams](),
+				toolschema.Output[ServerCapabilitiesResult](),
+			),
+			h.ServerCapabilities,
+		),
+		NewMCPTool(
+			mcp.NewTool("verify_readonly",
+				mcp.WithDescription("Verify that the credentials of a context cannot modify the cluster. Runs a SelfSubjectAccessReview for every write verb (create, update, patch, delete, deletecollection) on core workload, configuration, storage, networking, RBAC, and extension resources, plus pod exec, attach, port-forward, and eviction, RBAC escalate and bind, impersonation, and a wildcard check for cluster-admin. Reports a read_only, not_read_only, or inconclusive verdict with every allowed verb and the authorizer's reason, and names the context, cluster, user, and time checked, so the report can be kept as evidence when approving a deployment. Access reviews are not stored and do not change the cluster."),
+				toolschema.Input[VerifyReadOnlyParams](),
+				toolschema.Output[VerifyReadOnlyResult](),
+			),
+			h.VerifyReadOnly,
+		),
+	}
+}
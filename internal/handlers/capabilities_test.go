@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/fakecluster"
+)
+
+func TestServerCapabilities_FakeCluster(t *testing.T) {
+	t.Parallel()
+
+	settings := ServerSettings{
+		Version:   "v1.2.3",
+		Transport: "stdio",
+		Policies: ServerPolicies{
+			ReadOnly:          true,
+			DefaultNamespace:  "shop",
+			DisabledTools:     []string{"get_logs"},
+			DisabledResources: []string{"secrets"},
+		},
+		Features: map[string]bool{"port_forwarding": false},
+	}
+
+	handler := NewCapabilitiesHandler(fakecluster.New(fakecluster.Config{ServerVersion: "v1.30.2"}), false, settings, func() []string {
+		return []string{"list_resources", "get_resource"}
+	})
+
+	result, isErr := callTool(t, handler.ServerCapabilities, map[string]any{})
+	if isErr {
+		t.Fatalf("unexpected error: %v", result["error"])
+	}
+
+	var cluster ConnectedCluster
+	decodeInto(t, result["cluster"], &cluster)
+	if cluster.ServerVersion != "v1.30.2" {
+		t.Errorf("expected server version v1.30.2, got %q", cluster.ServerVersion)
+	}
+
+	var tools struct {
+		Enabled  []string `json:"enabled"`
+		Disabled []string `json:"disabled"`
+	}
+	decodeInto(t, result["tools"], &tools)
+	if want := []string{"get_resource", "list_resources"}; !reflect.DeepEqual(tools.Enabled, want) {
+		t.Errorf("expected enabled tools %v, got %v", want, tools.Enabled)
+	}
+	if want := []string{"get_logs"}; !reflect.DeepEqual(tools.Disabled, want) {
+		t.Errorf("expected disabled tools %v, got %v", want, tools.Disabled)
+	}
+
+	var policies ServerPolicies
+	decodeInto(t, result["policies"], &policies)
+	if !reflect.DeepEqual(policies, settings.Policies) {
+		t.Errorf("expected policies %+v, got %+v", settings.Policies, policies)
+	}
+}
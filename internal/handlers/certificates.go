@@ -0,0 +1,345 @@
+package handlers
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/connectivity"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/response"
+)
+
+const (
+	// defaultCertificateWindowDays is how close to expiry check_certificates
+	// flags a certificate by default.
+	defaultCertificateWindowDays = 30
+
+	certificateStatusValid       = "valid"
+	certificateStatusExpiring    = "expiring"
+	certificateStatusExpired     = "expired"
+	certificateStatusNotYetValid = "not_yet_valid"
+	certificateStatusInvalid     = "invalid"
+)
+
+// certificateSecretsGVR is the resource the resource filter is checked
+// against before TLS Secrets are read.
+var certificateSecretsGVR = schema.GroupVersionResource{Version: "v1", Resource: "secrets"}
+
+// certificateStatusOrder sorts the most urgent statuses first.
+var certificateStatusOrder = map[string]int{
+	certificateStatusInvalid:     0,
+	certificateStatusExpired:     1,
+	certificateStatusNotYetValid: 2,
+	certificateStatusExpiring:    3,
+	certificateStatusValid:       4,
+}
+
+// CheckCertificatesParams defines the parameters for the check_certificates MCP tool.
+type CheckCertificatesParams struct {
+	// Namespace limits the Secrets checked.
+	Namespace string `json:"namespace,omitempty" description:"Namespace to check TLS Secrets in (leave empty for all namespaces)"`
+
+	// ExpiringWithinDays is how close to expiry a certificate is flagged.
+	ExpiringWithinDays int `json:"expiring_within_days,omitempty" minimum:"1" default:"30" description:"Flag certificates that expire within this many days (defaults to 30)"`
+
+	// OnlyProblems limits the report to Secrets with findings.
+	OnlyProblems bool `json:"only_problems,omitempty" description:"When true, returns only Secrets with findings, such as expired, expiring, or unusable certificates"`
+
+	// Context specifies which Kubernetes context to use for this operation.
+	Context string `json:"context,omitempty" description:"Kubernetes context to use (defaults to current context from kubeconfig)"`
+}
+
+// CertificateInfo describes an X.509 certificate without its key material.
+type CertificateInfo struct {
+	Subject         string   `json:"subject"`
+	Issuer          string   `json:"issuer"`
+	SerialNumber    string   `json:"serial_number"`
+	DNSNames        []string `json:"dns_names,omitempty"`
+	IPAddresses     []string `json:"ip_addresses,omitempty"`
+	EmailAddresses  []string `json:"email_addresses,omitempty"`
+	URIs            []string `json:"uris,omitempty"`
+	NotBefore       string   `json:"not_before"`
+	NotAfter        string   `json:"not_after"`
+	DaysUntilExpiry int      `json:"days_until_expiry"`
+	IsCA            bool     `json:"is_ca"`
+	SelfSigned      bool     `json:"self_signed"`
+}
+
+// TLSSecretReport describes the certificate stored in a kubernetes.io/tls
+// Secret.
+type TLSSecretReport struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+
+	// Status is the most urgent state of the certificate and its chain:
+	// valid, expiring, expired, not_yet_valid, or invalid.
+	Status      string           `json:"status"`
+	Certificate *CertificateInfo `json:"certificate,omitempty"`
+
+	// Chain holds the certificates after the first one in tls.crt, usually
+	// the intermediates.
+	Chain    []CertificateInfo `json:"chain,omitempty"`
+	Findings []string          `json:"findings,omitempty"`
+}
+
+// CheckCertificatesResult is the result of the check_certificates MCP tool.
+type CheckCertificatesResult struct {
+	Namespace      string            `json:"namespace,omitempty"`
+	Certificates   []TLSSecretReport `json:"certificates"`
+	SecretsChecked int               `json:"secrets_checked"`
+
+	// ByStatus counts the TLS Secrets by the status of their most urgent
+	// certificate.
+	ByStatus           map[string]int `json:"by_status"`
+	ExpiringWithinDays int            `json:"expiring_within_days"`
+}
+
+// CheckCertificates implements the check_certificates MCP tool.
+// It parses the certificates in kubernetes.io/tls Secrets and reports their
+// subject, names, issuer, and days until expiry, flagging certificates that
+// are expired, expire soon, or cannot be used. Only tls.crt is reported;
+// tls.key is read to check that it matches the certificate and is never
+// returned.
+func (h *ResourceHandler) CheckCertificates(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var params CheckCertificatesParams
+	if err := bindParams(request, &params); err != nil {
+		return response.Errorf("failed to parse arguments: %s", err)
+	}
+
+	// Use the appropriate client based on context
+	client, err := h.client.ForContext(ctx, params.Context)
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to create client with context %s: %v", params.Context, err)
+	}
+
+	if result, err := h.disabledResult("secrets", certificateSecretsGVR); result != nil || err != nil {
+		return result, err
+	}
+
+	window := params.ExpiringWithinDays
+	if window <= 0 {
+		window = defaultCertificateWindowDays
+	}
+
+	secrets, err := client.ListSecrets(ctx, params.Namespace, metav1.ListOptions{FieldSelector: "type=" + string(corev1.SecretTypeTLS)})
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to list secrets: %v", err)
+	}
+
+	now := time.Now()
+
+	reports := make([]TLSSecretReport, 0, len(secrets.Items))
+	byStatus := make(map[string]int)
+	checked := 0
+
+	for i := range secrets.Items {
+		secret := &secrets.Items[i]
+		if secret.Type != corev1.SecretTypeTLS {
+			continue
+		}
+
+		checked++
+		report := tlsSecretReport(secret, window, now)
+		byStatus[report.Status]++
+
+		if params.OnlyProblems && len(report.Findings) == 0 {
+			continue
+		}
+		reports = append(reports, report)
+	}
+
+	// Most urgent first, then the soonest expiry, then namespace and name
+	sort.SliceStable(reports, func(i, j int) bool {
+		a, b := reports[i], reports[j]
+		if certificateStatusOrder[a.Status] != certificateStatusOrder[b.Status] {
+			return certificateStatusOrder[a.Status] < certificateStatusOrder[b.Status]
+		}
+		if a.Certificate != nil && b.Certificate != nil && a.Certificate.NotAfter != b.Certificate.NotAfter {
+			return a.Certificate.NotAfter < b.Certificate.NotAfter
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+
+	return response.JSON(CheckCertificatesResult{
+		Namespace:          params.Namespace,
+		Certificates:       reports,
+		SecretsChecked:     checked,
+		ByStatus:           byStatus,
+		ExpiringWithinDays: window,
+	})
+}
+
+// tlsSecretReport parses the certificates of a TLS Secret and explains what
+// needs attention. The most urgent state of any certificate in tls.crt, or
+// a tls.key that does not match, decides the status.
+func tlsSecretReport(secret *corev1.Secret, window int, now time.Time) TLSSecretReport {
+	report := TLSSecretReport{
+		Namespace: secret.Namespace,
+		Name:      secret.Name,
+		Status:    certificateStatusValid,
+	}
+
+	invalid := func(finding string) TLSSecretReport {
+		report.Status = certificateStatusInvalid
+		report.Findings = append(report.Findings, finding)
+		return report
+	}
+
+	certPEM := secret.Data[corev1.TLSCertKey]
+	if len(certPEM) == 0 {
+		return invalid(fmt.Sprintf("has no %s", corev1.TLSCertKey))
+	}
+
+	certificates, err := parseCertificates(certPEM)
+	if err != nil {
+		return invalid(fmt.Sprintf("%s cannot be parsed: %v", corev1.TLSCertKey, err))
+	}
+
+	for i, certificate := range certificates {
+		info := certificateInfo(certificate, now)
+		if i == 0 {
+			report.Certificate = &info
+		} else {
+			report.Chain = append(report.Chain, info)
+		}
+
+		label := "the certificate"
+		if i > 0 {
+			label = fmt.Sprintf("chain certificate %q", info.Subject)
+		}
+
+		status, finding := certificateValidity(certificate, info.DaysUntilExpiry, window, now)
+		if status == certificateStatusValid {
+			continue
+		}
+		report.Findings = append(report.Findings, label+" "+finding)
+		if certificateStatusOrder[status] < certificateStatusOrder[report.Status] {
+			report.Status = status
+		}
+	}
+
+	leaf := certificates[0]
+	if len(leaf.DNSNames) == 0 && len(leaf.IPAddresses) == 0 && len(leaf.URIs) == 0 && len(leaf.EmailAddresses) == 0 {
+		report.Findings = append(report.Findings, "the certificate has no subject alternative names; clients ignore the common name when verifying a hostname")
+	}
+
+	if keyPEM := secret.Data[corev1.TLSPrivateKeyKey]; len(keyPEM) == 0 {
+		report.Findings = append(report.Findings, fmt.Sprintf("has no %s, so it cannot be used to serve TLS", corev1.TLSPrivateKeyKey))
+	} else if _, err := tls.X509KeyPair(certPEM, keyPEM); err != nil {
+		// The error only describes the mismatch or the key type, never the
+		// key itself.
+		report.Status = certificateStatusInvalid
+		report.Findings = append(report.Findings, fmt.Sprintf("%s cannot be used with the certificate: %v", corev1.TLSPrivateKeyKey, err))
+	}
+
+	return report
+}
+
+// parseCertificates parses every CERTIFICATE block in a PEM bundle, in order.
+func parseCertificates(data []byte) ([]*x509.Certificate, error) {
+	var certificates []*x509.Certificate
+
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+
+		certificate, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("certificate %d: %w", len(certificates)+1, err)
+		}
+		certificates = append(certificates, certificate)
+	}
+
+	if len(certificates) == 0 {
+		return nil, fmt.Errorf("no PEM encoded certificate found")
+	}
+
+	return certificates, nil
+}
+
+// certificateInfo describes a certificate. Days until expiry round down, so
+// a certificate expiring in 12 hours has 0 days left and one that expired
+// an hour ago has -1.
+func certificateInfo(certificate *x509.Certificate, now time.Time) CertificateInfo {
+	info := CertificateInfo{
+		Subject:         certificate.Subject.String(),
+		Issuer:          certificate.Issuer.String(),
+		SerialNumber:    certificate.SerialNumber.Text(16),
+		DNSNames:        certificate.DNSNames,
+		EmailAddresses:  certificate.EmailAddresses,
+		NotBefore:       formatTime(certificate.NotBefore),
+		NotAfter:        formatTime(certificate.NotAfter),
+		DaysUntilExpiry: int(math.Floor(certificate.NotAfter.Sub(now).Hours() / 24)),
+		IsCA:            certificate.IsCA,
+		SelfSigned:      isSelfSigned(certificate),
+	}
+
+	for _, ip := range certificate.IPAddresses {
+		info.IPAddresses = append(info.IPAddresses, ip.String())
+	}
+
+	for _, uri := range certificate.URIs {
+		info.URIs = append(info.URIs, uri.String())
+	}
+
+	return info
+}
+
+// isSelfSigned reports whether a certificate is self-signed: its issuer
+// is its subject and its own key verifies its signature.
+func isSelfSigned(certificate *x509.Certificate) bool {
+	if certificate.Issuer.String() != certificate.Subject.String() {
+		return false
+	}
+	return certificate.CheckSignatureFrom(certificate) == nil
+}
+
+// certificateValidity returns the status of a certificate at now and, unless
+// it is valid, a finding that completes a sentence about the certificate.
+func certificateValidity(certificate *x509.Certificate, days, window int, now time.Time) (string, string) {
+	switch {
+	case now.After(certificate.NotAfter):
+		return certificateStatusExpired, fmt.Sprintf("expired on %s (%s ago)", formatTime(certificate.NotAfter), now.Sub(certificate.NotAfter).Round(time.Hour))
+	case now.Before(certificate.NotBefore):
+		return certificateStatusNotYetValid, fmt.Sprintf("is not valid until %s", formatTime(certificate.NotBefore))
+	case days < window:
+		return certificateStatusExpiring, fmt.Sprintf("expires on %s, in %s", formatTime(certificate.NotAfter), pluralDays(days))
+	}
+	return certificateStatusValid, ""
+}
+
+// pluralDays renders a number of days, with "less than a day" for zero.
+func pluralDays(days int) string {
+	switch days {
+	case 0:
+		return "less than a day"
+	case 1:
+		return "1 day"
+	}
+	return fmt.Sprintf("%d days", days)
+}
@@ -0,0 +1,256 @@
+package handlers
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/fakecluster"
+)
+
+// testCertificate is a generated certificate with its PEM encoded key.
+type testCertificate struct {
+	cert    *x509.Certificate
+	key     *ecdsa.PrivateKey
+	certPEM []byte
+	keyPEM  []byte
+}
+
+// newTestCertificate creates a certificate valid from notBefore to notAfter,
+// signed by parent, or self-signed when parent is nil.
+func newTestCertificate(t *testing.T, commonName string, dnsNames []string, notBefore, notAfter time.Time, parent *testCertificate) testCertificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: commonName},
+		DNSNames:              dnsNames,
+		NotBefore:             notBefore,
+		NotAfter:              notAfter,
+		IsCA:                  parent == nil,
+		BasicConstraintsValid: true,
+	}
+
+	signer, signerKey := template, key
+	if parent != nil {
+		signer, signerKey = parent.cert, parent.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("failed to parse certificate: %v", err)
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("failed to marshal key: %v", err)
+	}
+
+	return testCertificate{
+		cert:    cert,
+		key:     key,
+		certPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		keyPEM:  pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+	}
+}
+
+func TestTLSSecretReport(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	ca := newTestCertificate(t, "Example CA", nil, now.Add(-24*time.Hour), now.Add(365*24*time.Hour), nil)
+	shortCA := newTestCertificate(t, "Short CA", nil, now.Add(-24*time.Hour), now.Add(5*24*time.Hour+time.Hour), nil)
+	valid := newTestCertificate(t, "shop.example.com", []string{"shop.example.com"}, now.Add(-time.Hour), now.Add(90*24*time.Hour), &ca)
+	expiring := newTestCertificate(t, "shop.example.com", []string{"shop.example.com"}, now.Add(-time.Hour), now.Add(10*24*time.Hour+time.Hour), &ca)
+	expired := newTestCertificate(t, "old.example.com", []string{"old.example.com"}, now.Add(-48*time.Hour), now.Add(-24*time.Hour), &ca)
+	future := newTestCertificate(t, "new.example.com", []string{"new.example.com"}, now.Add(24*time.Hour), now.Add(90*24*time.Hour), &ca)
+	noSANs := newTestCertificate(t, "legacy", nil, now.Add(-time.Hour), now.Add(90*24*time.Hour), &ca)
+	chained := newTestCertificate(t, "shop.example.com", []string{"shop.example.com"}, now.Add(-time.Hour), now.Add(90*24*time.Hour), &shortCA)
+
+	join := func(blocks ...[]byte) []byte {
+		var result []byte
+		for _, block := range blocks {
+			result = append(result, block...)
+		}
+		return result
+	}
+
+	tests := []struct {
+		name       string
+		data       map[string][]byte
+		wantStatus string
+		wantDays   int
+		want       []string
+	}{
+		{
+			name:       "valid",
+			data:       map[string][]byte{"tls.crt": join(valid.certPEM, ca.certPEM), "tls.key": valid.keyPEM},
+			wantStatus: certificateStatusValid,
+			wantDays:   89,
+		},
+		{
+			name:       "expiring",
+			data:       map[string][]byte{"tls.crt": expiring.certPEM, "tls.key": expiring.keyPEM},
+			wantStatus: certificateStatusExpiring,
+			wantDays:   10,
+			want:       []string{"the certificate expires on " + formatTime(expiring.cert.NotAfter) + ", in 10 days"},
+		},
+		{
+			name:       "expired",
+			data:       map[string][]byte{"tls.crt": expired.certPEM, "tls.key": expired.keyPEM},
+			wantStatus: certificateStatusExpired,
+			wantDays:   -2,
+			want:       []string{"the certificate expired on " + formatTime(expired.cert.NotAfter) + " (24h0m0s ago)"},
+		},
+		{
+			name:       "not yet valid",
+			data:       map[string][]byte{"tls.crt": future.certPEM, "tls.key": future.keyPEM},
+			wantStatus: certificateStatusNotYetValid,
+			wantDays:   89,
+			want:       []string{"the certificate is not valid until " + formatTime(future.cert.NotBefore)},
+		},
+		{
+			name:       "chain expires first",
+			data:       map[string][]byte{"tls.crt": join(chained.certPEM, shortCA.certPEM), "tls.key": chained.keyPEM},
+			wantStatus: certificateStatusExpiring,
+			wantDays:   89,
+			want:       []string{`chain certificate "CN=Short CA" expires on ` + formatTime(shortCA.cert.NotAfter) + ", in 5 days"},
+		},
+		{
+			name:       "no subject alternative names",
+			data:       map[string][]byte{"tls.crt": noSANs.certPEM, "tls.key": noSANs.keyPEM},
+			wantStatus: certificateStatusValid,
+			wantDays:   89,
+			want:       []string{"the certificate has no subject alternative names; clients ignore the common name when verifying a hostname"},
+		},
+		{
+			name:       "mismatched key",
+			data:       map[string][]byte{"tls.crt": valid.certPEM, "tls.key": expired.keyPEM},
+			wantStatus: certificateStatusInvalid,
+			wantDays:   89,
+			want:       []string{"tls.key cannot be used with the certificate: tls: private key does not match public key"},
+		},
+		{
+			name:       "missing key",
+			data:       map[string][]byte{"tls.crt": valid.certPEM},
+			wantStatus: certificateStatusValid,
+			wantDays:   89,
+			want:       []string{"has no tls.key, so it cannot be used to serve TLS"},
+		},
+		{
+			name:       "not a certificate",
+			data:       map[string][]byte{"tls.crt": []byte("not a certificate"), "tls.key": valid.keyPEM},
+			wantStatus: certificateStatusInvalid,
+			want:       []string{"tls.crt cannot be parsed: no PEM encoded certificate found"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			secret := &corev1.Secret{Type: corev1.SecretTypeTLS, Data: tt.data}
+			got := tlsSecretReport(secret, defaultCertificateWindowDays, now)
+
+			if got.Status != tt.wantStatus {
+				t.Errorf("expected status %q, got %q", tt.wantStatus, got.Status)
+			}
+			if got.Certificate != nil && got.Certificate.DaysUntilExpiry != tt.wantDays {
+				t.Errorf("expected %d days until expiry, got %d", tt.wantDays, got.Certificate.DaysUntilExpiry)
+			}
+			if strings.Join(got.Findings, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("expected findings %q, got %q", tt.want, got.Findings)
+			}
+		})
+	}
+}
+
+func TestCheckCertificates_FakeCluster(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	ca := newTestCertificate(t, "Example CA", nil, now.Add(-24*time.Hour), now.Add(365*24*time.Hour), nil)
+	valid := newTestCertificate(t, "shop.example.com", []string{"shop.example.com", "www.shop.example.com"}, now.Add(-time.Hour), now.Add(90*24*time.Hour), &ca)
+	expiring := newTestCertificate(t, "api.example.com", []string{"api.example.com"}, now.Add(-time.Hour), now.Add(20*24*time.Hour), &ca)
+
+	handler := NewResourceHandler(fakecluster.New(fakecluster.Config{
+		Objects: []runtime.Object{
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "shop-tls", Namespace: "shop"},
+				Type:       corev1.SecretTypeTLS,
+				Data:       map[string][]byte{"tls.crt": valid.certPEM, "tls.key": valid.keyPEM},
+			},
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "api-tls", Namespace: "shop"},
+				Type:       corev1.SecretTypeTLS,
+				Data:       map[string][]byte{"tls.crt": expiring.certPEM, "tls.key": expiring.keyPEM},
+			},
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "db-password", Namespace: "shop"},
+				Type:       corev1.SecretTypeOpaque,
+				Data:       map[string][]byte{"password": []byte("hunter2")},
+			},
+		},
+	}), nil, false)
+
+	result, isErr := callTool(t, handler.CheckCertificates, map[string]any{"namespace": "shop"})
+	if isErr {
+		t.Fatalf("unexpected error: %v", result["error"])
+	}
+
+	if result["secrets_checked"] != float64(2) {
+		t.Errorf("expected only the TLS Secrets to be checked, got %v", result["secrets_checked"])
+	}
+
+	var reports []TLSSecretReport
+	decodeInto(t, result["certificates"], &reports)
+	if len(reports) != 2 || reports[0].Name != "api-tls" || reports[0].Status != certificateStatusExpiring {
+		t.Fatalf("expected the expiring certificate first, got %+v", reports)
+	}
+	if reports[1].Certificate == nil || strings.Join(reports[1].Certificate.DNSNames, ",") != "shop.example.com,www.shop.example.com" || reports[1].Certificate.Issuer != "CN=Example CA" {
+		t.Errorf("unexpected valid certificate %+v", reports[1].Certificate)
+	}
+
+	result, isErr = callTool(t, handler.CheckCertificates, map[string]any{"namespace": "shop", "expiring_within_days": 7})
+	if isErr {
+		t.Fatalf("unexpected error: %v", result["error"])
+	}
+
+	decodeInto(t, result["certificates"], &reports)
+	for _, report := range reports {
+		if report.Status != certificateStatusValid {
+			t.Errorf("expected %s to be valid with a 7 day window, got %s", report.Name, report.Status)
+		}
+	}
+
+	encoded, err := json.Marshal(result)
+	if err != nil {
+		t.Fatalf("failed to encode result: %v", err)
+	}
+	if strings.Contains(string(encoded), "PRIVATE KEY") || strings.Contains(string(encoded), "hunter2") {
+		t.Errorf("secret data must not be returned, got %s", encoded)
+	}
+}
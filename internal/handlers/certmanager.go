@@ -0,0 +1,534 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/connectivity"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/kubernetes"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/resourcefilter"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/response"
+)
+
+const (
+	// certManagerAPIVersion is the API version of cert-manager's Certificate
+	// and CertificateRequest CRDs.
+	certManagerAPIVersion = "cert-manager.io/v1"
+
+	// certManagerACMEAPIVersion is the API version of cert-manager's ACME
+	// Order and Challenge CRDs.
+	certManagerACMEAPIVersion = "acme.cert-manager.io/v1"
+
+	// certManagerStuckAfter is how long a CertificateRequest or Challenge may
+	// stay pending before it is flagged.
+	certManagerStuckAfter = 10 * time.Minute
+)
+
+// GetCertManagerStatusParams defines the parameters for the get_cert_manager_status MCP tool.
+type GetCertManagerStatusParams struct {
+	// Namespace limits the resources reported.
+	Namespace string `json:"namespace,omitempty" description:"Namespace to report cert-manager resources for (leave empty for all namespaces)"`
+
+	// OnlyProblems limits the report to resources with findings.
+	OnlyProblems bool `json:"only_problems,omitempty" description:"When true, returns only Certificates, CertificateRequests, and Challenges with findings"`
+
+	// Context specifies which Kubernetes context to use for this operation.
+	Context string `json:"context,omitempty" description:"Kubernetes context to use (defaults to current context from kubeconfig)"`
+}
+
+// CertManagerCertificate summarizes a cert-manager Certificate.
+type CertManagerCertificate struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+
+	// Ready is the status of the Ready condition: True, False, or Unknown.
+	Ready      string   `json:"ready"`
+	Reason     string   `json:"reason,omitempty"`
+	Message    string   `json:"message,omitempty"`
+	SecretName string   `json:"secret_name,omitempty"`
+	Issuer     string   `json:"issuer,omitempty"`
+	DNSNames   []string `json:"dns_names,omitempty"`
+	NotAfter   string   `json:"not_after,omitempty"`
+
+	// RenewalTime is when cert-manager plans to renew the certificate.
+	RenewalTime string `json:"renewal_time,omitempty"`
+	Revision    int64  `json:"revision,omitempty"`
+
+	// Issuing is true while cert-manager is issuing a new certificate.
+	Issuing                bool     `json:"issuing"`
+	FailedIssuanceAttempts int64    `json:"failed_issuance_attempts,omitempty"`
+	LastFailureTime        string   `json:"last_failure_time,omitempty"`
+	Findings               []string `json:"findings,omitempty"`
+}
+
+// CertManagerRequest summarizes a cert-manager CertificateRequest.
+type CertManagerRequest struct {
+	Namespace   string `json:"namespace"`
+	Name        string `json:"name"`
+	Certificate string `json:"certificate,omitempty"`
+	Issuer      string `json:"issuer,omitempty"`
+
+	// Approved is the status of the Approved condition, or "Denied" when
+	// the request was denied.
+	Approved string   `json:"approved,omitempty"`
+	Ready    string   `json:"ready"`
+	Reason   string   `json:"reason,omitempty"`
+	Message  string   `json:"message,omitempty"`
+	Age      string   `json:"age"`
+	Findings []string `json:"findings,omitempty"`
+}
+
+// CertManagerChallenge summarizes an ACME Challenge.
+type CertManagerChallenge struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+
+	// CertificateRequest is found through the Challenge's Order.
+	CertificateRequest string   `json:"certificate_request,omitempty"`
+	Type               string   `json:"type,omitempty"`
+	DNSName            string   `json:"dns_name,omitempty"`
+	State              string   `json:"state"`
+	Reason             string   `json:"reason,omitempty"`
+	Presented          bool     `json:"presented"`
+	Processing         bool     `json:"processing"`
+	Age                string   `json:"age"`
+	Findings           []string `json:"findings,omitempty"`
+}
+
+// GetCertManagerStatusResult is the result of the get_cert_manager_status MCP
+// tool. When cert-manager is not installed, only Installed and Message are
+// set.
+type GetCertManagerStatusResult struct {
+	Installed bool   `json:"installed"`
+	Message   string `json:"message,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+
+	Certificates        []CertManagerCertificate `json:"certificates,omitzero"`
+	CertificateRequests []CertManagerRequest     `json:"certificate_requests,omitzero"`
+	Challenges          []CertManagerChallenge   `json:"challenges,omitzero"`
+
+	// CertificatesByReady counts the Certificates by their Ready condition.
+	CertificatesByReady             map[string]int `json:"certificates_by_ready,omitzero"`
+	CertificatesWithFindings        int            `json:"certificates_with_findings"`
+	CertificateRequestsWithFindings int            `json:"certificate_requests_with_findings"`
+	ChallengesWithFindings          int            `json:"challenges_with_findings"`
+
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// GetCertManagerStatus implements the get_cert_manager_status MCP tool.
+// It lists cert-manager Certificates, CertificateRequests, and ACME
+// Challenges, and explains why certificates are not ready or not renewed:
+// failed or denied requests, challenges that cannot be validated, and
+// renewals that are overdue. If cert-manager is not installed, it returns a
+// result saying so instead of an error.
+func (h *ResourceHandler) GetCertManagerStatus(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var params GetCertManagerStatusParams
+	if err := bindParams(request, &params); err != nil {
+		return response.Errorf("failed to parse arguments: %s", err)
+	}
+
+	// Use the appropriate client based on context
+	client, err := h.client.ForContext(ctx, params.Context)
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to create client with context %s: %v", params.Context, err)
+	}
+
+	certificatesGVR, err := client.ResolveResourceType("certificates", certManagerAPIVersion)
+	if err != nil {
+		if connectivity.IsError(err) {
+			if h.alwaysStart {
+				return response.Error(connectivity.ErrorMessage(err))
+			}
+			return response.Errorf("failed to discover cert-manager API: %v", err)
+		}
+
+		// Discovery worked but the resource type is unknown: cert-manager isn't installed.
+		return response.JSON(GetCertManagerStatusResult{
+			Installed: false,
+			Message:   "The cert-manager Certificate CRD (" + certManagerAPIVersion + ") is not installed in this cluster, so there are no cert-manager certificates to report. TLS Secrets can still be inspected with check_certificates.",
+		})
+	}
+
+	if result, err := h.disabledResult("certificates", certificatesGVR); result != nil || err != nil {
+		return result, err
+	}
+
+	certificateList, err := client.ListResources(ctx, certificatesGVR, params.Namespace, metav1.ListOptions{})
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to list certificates: %v", err)
+	}
+
+	lookup := &certManagerLookup{handler: h, client: client, namespace: params.Namespace}
+
+	requestItems, err := lookup.list(ctx, "certificaterequests", certManagerAPIVersion)
+	if err != nil {
+		return response.Error(connectivity.ErrorMessage(err))
+	}
+
+	orderItems, err := lookup.list(ctx, "orders", certManagerACMEAPIVersion)
+	if err != nil {
+		return response.Error(connectivity.ErrorMessage(err))
+	}
+
+	challengeItems, err := lookup.list(ctx, "challenges", certManagerACMEAPIVersion)
+	if err != nil {
+		return response.Error(connectivity.ErrorMessage(err))
+	}
+
+	now := time.Now()
+
+	// Orders link Challenges to the CertificateRequest they validate.
+	orderRequests := make(map[string]string, len(orderItems))
+	for i := range orderItems {
+		if owner := ownerOfKind(&orderItems[i], "CertificateRequest"); owner != "" {
+			orderRequests[orderItems[i].GetNamespace()+"/"+orderItems[i].GetName()] = owner
+		}
+	}
+
+	challenges := make([]CertManagerChallenge, 0, len(challengeItems))
+	challengesWithFindings := 0
+	for i := range challengeItems {
+		challenge := certManagerChallenge(&challengeItems[i], now)
+		if order := ownerOfKind(&challengeItems[i], "Order"); order != "" {
+			challenge.CertificateRequest = orderRequests[challenge.Namespace+"/"+order]
+		}
+		if len(challenge.Findings) > 0 {
+			challengesWithFindings++
+		} else if params.OnlyProblems {
+			continue
+		}
+		challenges = append(challenges, challenge)
+	}
+
+	requests := make([]CertManagerRequest, 0, len(requestItems))
+	requestsWithFindings := 0
+	for i := range requestItems {
+		entry := certManagerRequest(&requestItems[i], now)
+		if len(entry.Findings) > 0 {
+			requestsWithFindings++
+		} else if params.OnlyProblems {
+			continue
+		}
+		requests = append(requests, entry)
+	}
+
+	certificates := make([]CertManagerCertificate, 0, len(certificateList.Items))
+	readyCounts := make(map[string]int)
+	certificatesWithFindings := 0
+	for i := range certificateList.Items {
+		entry := certManagerCertificate(&certificateList.Items[i], now)
+		readyCounts[entry.Ready]++
+		if len(entry.Findings) > 0 {
+			certificatesWithFindings++
+		} else if params.OnlyProblems {
+			continue
+		}
+		certificates = append(certificates, entry)
+	}
+
+	// Entries with findings first, then by namespace and name
+	sort.SliceStable(certificates, func(i, j int) bool {
+		a, b := certificates[i], certificates[j]
+		return findingsFirst(len(a.Findings), len(b.Findings), a.Namespace+"/"+a.Name, b.Namespace+"/"+b.Name)
+	})
+	sort.SliceStable(requests, func(i, j int) bool {
+		a, b := requests[i], requests[j]
+		return findingsFirst(len(a.Findings), len(b.Findings), a.Namespace+"/"+a.Name, b.Namespace+"/"+b.Name)
+	})
+	sort.SliceStable(challenges, func(i, j int) bool {
+		a, b := challenges[i], challenges[j]
+		return findingsFirst(len(a.Findings), len(b.Findings), a.Namespace+"/"+a.Name, b.Namespace+"/"+b.Name)
+	})
+
+	return response.JSON(GetCertManagerStatusResult{
+		Installed:                       true,
+		Namespace:                       params.Namespace,
+		Certificates:                    certificates,
+		CertificateRequests:             requests,
+		Challenges:                      challenges,
+		CertificatesByReady:             readyCounts,
+		CertificatesWithFindings:        certificatesWithFindings,
+		CertificateRequestsWithFindings: requestsWithFindings,
+		ChallengesWithFindings:          challengesWithFindings,
+		Warnings:                        lookup.warnings,
+	})
+}
+
+// certManagerLookup lists the optional cert-manager resource types, turning
+// missing or disabled types into warnings.
+type certManagerLookup struct {
+	handler   *ResourceHandler
+	client    kubernetes.ClusterReader
+	namespace string
+	warnings  []string
+}
+
+// list returns the objects of a resource type, or none with a warning when
+// the type is not served, is disabled, or cannot be listed. The only error
+// returned is a connectivity error that --always-start reports as is.
+func (l *certManagerLookup) list(ctx context.Context, resource, apiVersion string) ([]unstructured.Unstructured, error) {
+	gvr, err := l.client.ResolveResourceType(resource, apiVersion)
+	if err != nil {
+		if connectivity.IsError(err) {
+			if l.handler.alwaysStart {
+				return nil, err //nolint:wrapcheck // reported through connectivity.ErrorMessage
+			}
+			l.warnings = append(l.warnings, fmt.Sprintf("failed to discover %s: %v", resource, err))
+			return nil, nil
+		}
+		l.warnings = append(l.warnings, fmt.Sprintf("%s (%s) are not served by this cluster and were not checked", resource, apiVersion))
+		return nil, nil
+	}
+
+	if filter := l.handler.resourceFilter; filter != nil && filter.IsDisabled(gvr) {
+		l.warnings = append(l.warnings, fmt.Sprintf("%s are disabled by configuration and were not checked", resourcefilter.FormatGVR(gvr)))
+		return nil, nil
+	}
+
+	list, err := l.client.ListResources(ctx, gvr, l.namespace, metav1.ListOptions{})
+	if err != nil {
+		if l.handler.alwaysStart && connectivity.IsTransportError(err) {
+			return nil, err //nolint:wrapcheck // reported through connectivity.ErrorMessage
+		}
+		l.warnings = append(l.warnings, fmt.Sprintf("failed to list %s: %v", resource, err))
+		return nil, nil
+	}
+
+	return list.Items, nil
+}
+
+// certManagerCertificate summarizes a Certificate and explains why it may
+// need attention.
+func certManagerCertificate(obj *unstructured.Unstructured, now time.Time) CertManagerCertificate {
+	entry := CertManagerCertificate{
+		Namespace: obj.GetNamespace(),
+		Name:      obj.GetName(),
+		Issuer:    certManagerIssuerRef(obj),
+	}
+
+	entry.SecretName, _, _ = unstructured.NestedString(obj.Object, "spec", "secretName")
+	entry.DNSNames, _, _ = unstructured.NestedStringSlice(obj.Object, "spec", "dnsNames")
+	entry.NotAfter, _, _ = unstructured.NestedString(obj.Object, "status", "notAfter")
+	entry.RenewalTime, _, _ = unstructured.NestedString(obj.Object, "status", "renewalTime")
+	entry.Revision, _, _ = unstructured.NestedInt64(obj.Object, "status", "revision")
+	entry.FailedIssuanceAttempts, _, _ = unstructured.NestedInt64(obj.Object, "status", "failedIssuanceAttempts")
+	entry.LastFailureTime, _, _ = unstructured.NestedString(obj.Object, "status", "lastFailureTime")
+
+	ready := unstructuredCondition(obj, "Ready")
+	entry.Ready, entry.Reason, entry.Message = ready.status, ready.reason, ready.message
+	if entry.Ready == "" {
+		entry.Ready = "Unknown"
+	}
+
+	issuing := unstructuredCondition(obj, "Issuing")
+	entry.Issuing = issuing.status == "True"
+
+	switch {
+	case !ready.found:
+		entry.Findings = append(entry.Findings, "has no Ready condition; cert-manager has not processed it, which usually means its controller is not running")
+	case ready.status != "True":
+		entry.Findings = append(entry.Findings, conditionFinding("is not ready", ready))
+	}
+
+	if notAfter, err := time.Parse(time.RFC3339, entry.NotAfter); err == nil && now.After(notAfter) {
+		entry.Findings = append(entry.Findings, fmt.Sprintf("the certificate in Secret %q expired on %s", entry.SecretName, entry.NotAfter))
+	}
+
+	if renewal, err := time.Parse(time.RFC3339, entry.RenewalTime); err == nil && now.Sub(renewal) > time.Hour {
+		entry.Findings = append(entry.Findings, fmt.Sprintf("renewal was due at %s and has not completed", entry.RenewalTime))
+	}
+
+	if entry.FailedIssuanceAttempts > 0 {
+		finding := fmt.Sprintf("%d issuance attempts failed", entry.FailedIssuanceAttempts)
+		if entry.LastFailureTime != "" {
+			finding += ", the last at " + entry.LastFailureTime
+		}
+		if issuing.status == "False" && issuing.message != "" {
+			finding += ": " + issuing.message
+		}
+		entry.Findings = append(entry.Findings, finding+"; cert-manager retries with an exponential backoff of up to 32 hours")
+	}
+
+	return entry
+}
+
+// certManagerRequest summarizes a CertificateRequest and explains why it
+// may need attention.
+func certManagerRequest(obj *unstructured.Unstructured, now time.Time) CertManagerRequest {
+	entry := CertManagerRequest{
+		Namespace:   obj.GetNamespace(),
+		Name:        obj.GetName(),
+		Certificate: ownerOfKind(obj, "Certificate"),
+		Issuer:      certManagerIssuerRef(obj),
+		Age:         now.Sub(obj.GetCreationTimestamp().Time).Round(time.Second).String(),
+	}
+
+	if entry.Certificate == "" {
+		entry.Certificate = obj.GetAnnotations()["cert-manager.io/certificate-name"]
+	}
+
+	ready := unstructuredCondition(obj, "Ready")
+	entry.Ready, entry.Reason, entry.Message = ready.status, ready.reason, ready.message
+	if entry.Ready == "" {
+		entry.Ready = "Unknown"
+	}
+
+	approved := unstructuredCondition(obj, "Approved")
+	denied := unstructuredCondition(obj, "Denied")
+	invalid := unstructuredCondition(obj, "InvalidRequest")
+	entry.Approved = approved.status
+	if denied.status == "True" {
+		entry.Approved = "Denied"
+	}
+
+	stuck := now.Sub(obj.GetCreationTimestamp().Time) > certManagerStuckAfter
+
+	switch {
+	case denied.status == "True":
+		entry.Findings = append(entry.Findings, conditionFinding("was denied", denied))
+	case invalid.status == "True":
+		entry.Findings = append(entry.Findings, conditionFinding("is invalid", invalid))
+	case ready.status == "True":
+	case ready.reason == "Failed":
+		entry.Findings = append(entry.Findings, conditionFinding("failed", ready))
+	case !approved.found && stuck:
+		entry.Findings = append(entry.Findings, fmt.Sprintf("has not been approved after %s; check that an approver, such as cert-manager's built-in one or approver-policy, is running", entry.Age))
+	case stuck:
+		entry.Findings = append(entry.Findings, conditionFinding(fmt.Sprintf("is still not ready after %s", entry.Age), ready))
+	}
+
+	return entry
+}
+
+// certManagerChallenge summarizes an ACME Challenge and explains why it may
+// need attention. The caller links it to its CertificateRequest.
+func certManagerChallenge(obj *unstructured.Unstructured, now time.Time) CertManagerChallenge {
+	entry := CertManagerChallenge{
+		Namespace: obj.GetNamespace(),
+		Name:      obj.GetName(),
+		Age:       now.Sub(obj.GetCreationTimestamp().Time).Round(time.Second).String(),
+	}
+
+	entry.Type, _, _ = unstructured.NestedString(obj.Object, "spec", "type")
+	entry.DNSName, _, _ = unstructured.NestedString(obj.Object, "spec", "dnsName")
+	entry.State, _, _ = unstructured.NestedString(obj.Object, "status", "state")
+	entry.Reason, _, _ = unstructured.NestedString(obj.Object, "status", "reason")
+	entry.Presented, _, _ = unstructured.NestedBool(obj.Object, "status", "presented")
+	entry.Processing, _, _ = unstructured.NestedBool(obj.Object, "status", "processing")
+
+	if entry.State == "" {
+		entry.State = "pending"
+	}
+
+	describe := func(what string) string {
+		if entry.Reason != "" {
+			what += ": " + entry.Reason
+		}
+		return what
+	}
+
+	switch entry.State {
+	case "valid":
+	case "invalid", "errored", "expired":
+		entry.Findings = append(entry.Findings, describe(fmt.Sprintf("%s challenge for %s is %s", entry.Type, entry.DNSName, entry.State)))
+	default:
+		if now.Sub(obj.GetCreationTimestamp().Time) <= certManagerStuckAfter {
+			break
+		}
+		finding := fmt.Sprintf("%s challenge for %s is still %s after %s", entry.Type, entry.DNSName, entry.State, entry.Age)
+		if !entry.Presented {
+			finding += " and has not been presented"
+		}
+		entry.Findings = append(entry.Findings, describe(finding))
+	}
+
+	return entry
+}
+
+// certManagerIssuerRef renders spec.issuerRef as Kind/name, where the kind
+// defaults to Issuer.
+func certManagerIssuerRef(obj *unstructured.Unstructured) string {
+	name, _, _ := unstructured.NestedString(obj.Object, "spec", "issuerRef", "name")
+	if name == "" {
+		return ""
+	}
+
+	kind, _, _ := unstructured.NestedString(obj.Object, "spec", "issuerRef", "kind")
+	if kind == "" {
+		kind = "Issuer"
+	}
+	return kind + "/" + name
+}
+
+// ownerOfKind returns the name of the first owner of obj with the given kind.
+func ownerOfKind(obj *unstructured.Unstructured, kind string) string {
+	for _, owner := range obj.GetOwnerReferences() {
+		if owner.Kind == kind {
+			return owner.Name
+		}
+	}
+	return ""
+}
+
+// unstructuredConditionInfo is a status condition read from an unstructured
+// object.
+type unstructuredConditionInfo struct {
+	found          bool
+	status         string
+	reason         string
+	message        string
+	lastTransition string
+}
+
+// unstructuredCondition returns the status.conditions entry of the given
+// type.
+func unstructuredCondition(obj *unstructured.Unstructured, conditionType string) unstructuredConditionInfo {
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, item := range conditions {
+		condition, ok := item.(map[string]interface{})
+		if !ok || condition["type"] != conditionType {
+			continue
+		}
+
+		info := unstructuredConditionInfo{found: true}
+		info.status, _ = condition["status"].(string)
+		info.reason, _ = condition["reason"].(string)
+		info.message, _ = condition["message"].(string)
+		info.lastTransition, _ = condition["lastTransitionTime"].(string)
+		return info
+	}
+	return unstructuredConditionInfo{}
+}
+
+// conditionFinding appends a condition's reason and message to a finding.
+func conditionFinding(finding string, condition unstructuredConditionInfo) string {
+	if condition.reason != "" {
+		finding += " (" + condition.reason + ")"
+	}
+	if condition.message != "" {
+		finding += ": " + condition.message
+	}
+	return finding
+}
+
+// findingsFirst orders entries with findings before those without, then by
+// key.
+func findingsFirst(aFindings, bFindings int, aKey, bKey string) bool {
+	if (aFindings > 0) != (bFindings > 0) {
+		return aFindings > 0
+	}
+	return aKey < bKey
+}
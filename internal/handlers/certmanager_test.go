@@ -0,0 +1,252 @@
+package handlers
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/fakecluster"
+)
+
+// certManagerObject builds an unstructured cert-manager object created at
+// created.
+func certManagerObject(apiVersion, kind, name string, created time.Time, fields map[string]interface{}) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": apiVersion,
+		"kind":       kind,
+		"metadata": map[string]interface{}{
+			"name":              name,
+			"namespace":         "shop",
+			"creationTimestamp": created.UTC().Format(time.RFC3339),
+		},
+	}}
+	for key, value := range fields {
+		obj.Object[key] = value
+	}
+	return obj
+}
+
+func certManagerCondition(conditionType, status, reason, message string) map[string]interface{} {
+	return map[string]interface{}{"type": conditionType, "status": status, "reason": reason, "message": message}
+}
+
+func TestCertManagerFindings(t *testing.T) {
+	t.Parallel()
+
+	// Creation timestamps are stored with second precision.
+	now := time.Now().Truncate(time.Second)
+	old := now.Add(-time.Hour)
+
+	tests := []struct {
+		name string
+		got  func() []string
+		want []string
+	}{
+		{
+			name: "ready certificate",
+			got: func() []string {
+				return certManagerCertificate(certManagerObject(certManagerAPIVersion, "Certificate", "web", old, map[string]interface{}{
+					"status": map[string]interface{}{
+						"conditions":  []interface{}{certManagerCondition("Ready", "True", "Ready", "Certificate is up to date and has not expired")},
+						"renewalTime": now.Add(24 * time.Hour).UTC().Format(time.RFC3339),
+					},
+				}), now).Findings
+			},
+		},
+		{
+			name: "certificate without conditions",
+			got: func() []string {
+				return certManagerCertificate(certManagerObject(certManagerAPIVersion, "Certificate", "web", old, nil), now).Findings
+			},
+			want: []string{"has no Ready condition; cert-manager has not processed it, which usually means its controller is not running"},
+		},
+		{
+			name: "failing renewal",
+			got: func() []string {
+				return certManagerCertificate(certManagerObject(certManagerAPIVersion, "Certificate", "web", old, map[string]interface{}{
+					"spec": map[string]interface{}{"secretName": "web-tls"},
+					"status": map[string]interface{}{
+						"conditions": []interface{}{
+							certManagerCondition("Ready", "False", "Expired", "Certificate expired"),
+							certManagerCondition("Issuing", "False", "Failed", "The certificate request has failed to complete and will be retried"),
+						},
+						"notAfter":               "2024-01-01T00:00:00Z",
+						"renewalTime":            "2023-12-01T00:00:00Z",
+						"failedIssuanceAttempts": int64(3),
+						"lastFailureTime":        "2024-01-02T00:00:00Z",
+					},
+				}), now).Findings
+			},
+			want: []string{
+				"is not ready (Expired): Certificate expired",
+				`the certificate in Secret "web-tls" expired on 2024-01-01T00:00:00Z`,
+				"renewal was due at 2023-12-01T00:00:00Z and has not completed",
+				"3 issuance attempts failed, the last at 2024-01-02T00:00:00Z: The certificate request has failed to complete and will be retried; cert-manager retries with an exponential backoff of up to 32 hours",
+			},
+		},
+		{
+			name: "denied request",
+			got: func() []string {
+				return certManagerRequest(certManagerObject(certManagerAPIVersion, "CertificateRequest", "web-1", now, map[string]interface{}{
+					"status": map[string]interface{}{"conditions": []interface{}{certManagerCondition("Denied", "True", "policy.cert-manager.io", "No policy approved this request")}},
+				}), now).Findings
+			},
+			want: []string{"was denied (policy.cert-manager.io): No policy approved this request"},
+		},
+		{
+			name: "request not approved",
+			got: func() []string {
+				return certManagerRequest(certManagerObject(certManagerAPIVersion, "CertificateRequest", "web-1", old, nil), now).Findings
+			},
+			want: []string{"has not been approved after 1h0m0s; check that an approver, such as cert-manager's built-in one or approver-policy, is running"},
+		},
+		{
+			name: "recent pending request",
+			got: func() []string {
+				return certManagerRequest(certManagerObject(certManagerAPIVersion, "CertificateRequest", "web-1", now, map[string]interface{}{
+					"status": map[string]interface{}{"conditions": []interface{}{
+						certManagerCondition("Approved", "True", "cert-manager.io", "Certificate request has been approved by cert-manager.io"),
+						certManagerCondition("Ready", "False", "Pending", "Waiting on certificate issuance from order shop/web-1-123"),
+					}},
+				}), now).Findings
+			},
+		},
+		{
+			name: "invalid challenge",
+			got: func() []string {
+				return certManagerChallenge(certManagerObject(certManagerACMEAPIVersion, "Challenge", "web-1-123-456", now, map[string]interface{}{
+					"spec":   map[string]interface{}{"type": "HTTP-01", "dnsName": "shop.example.com"},
+					"status": map[string]interface{}{"state": "invalid", "reason": "Error accepting authorization: 404 from http://shop.example.com/.well-known/acme-challenge/x"},
+				}), now).Findings
+			},
+			want: []string{"HTTP-01 challenge for shop.example.com is invalid: Error accepting authorization: 404 from http://shop.example.com/.well-known/acme-challenge/x"},
+		},
+		{
+			name: "stuck challenge",
+			got: func() []string {
+				return certManagerChallenge(certManagerObject(certManagerACMEAPIVersion, "Challenge", "web-1-123-456", old, map[string]interface{}{
+					"spec":   map[string]interface{}{"type": "DNS-01", "dnsName": "shop.example.com"},
+					"status": map[string]interface{}{"state": "pending", "processing": true, "reason": "Waiting for DNS-01 challenge propagation"},
+				}), now).Findings
+			},
+			want: []string{"DNS-01 challenge for shop.example.com is still pending after 1h0m0s and has not been presented: Waiting for DNS-01 challenge propagation"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tt.got(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected findings %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestGetCertManagerStatus_NotInstalled(t *testing.T) {
+	t.Parallel()
+
+	handler := NewResourceHandler(fakecluster.New(fakecluster.Config{}), nil, false)
+
+	result, isErr := callTool(t, handler.GetCertManagerStatus, map[string]any{})
+	if isErr {
+		t.Fatalf("unexpected error: %v", result["error"])
+	}
+	if result["installed"] != false {
+		t.Errorf("expected installed=false, got %v", result["installed"])
+	}
+}
+
+func TestGetCertManagerStatus_FakeCluster(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	old := now.Add(-time.Hour)
+	owner := func(kind, name string) []interface{} {
+		return []interface{}{map[string]interface{}{"apiVersion": "v1", "kind": kind, "name": name, "uid": name}}
+	}
+
+	certificate := certManagerObject(certManagerAPIVersion, "Certificate", "web", old, map[string]interface{}{
+		"spec": map[string]interface{}{
+			"secretName": "web-tls",
+			"dnsNames":   []interface{}{"shop.example.com"},
+			"issuerRef":  map[string]interface{}{"name": "letsencrypt", "kind": "ClusterIssuer"},
+		},
+		"status": map[string]interface{}{"conditions": []interface{}{
+			certManagerCondition("Ready", "False", "DoesNotExist", "Issuing certificate as Secret does not exist"),
+			certManagerCondition("Issuing", "True", "DoesNotExist", "Issuing certificate as Secret does not exist"),
+		}},
+	})
+	certificateRequest := certManagerObject(certManagerAPIVersion, "CertificateRequest", "web-1", old, map[string]interface{}{
+		"status": map[string]interface{}{"conditions": []interface{}{
+			certManagerCondition("Approved", "True", "cert-manager.io", "Certificate request has been approved by cert-manager.io"),
+			certManagerCondition("Ready", "False", "Pending", "Waiting on certificate issuance from order shop/web-1-123"),
+		}},
+	})
+	certificateRequest.Object["metadata"].(map[string]interface{})["ownerReferences"] = owner("Certificate", "web")
+
+	order := certManagerObject(certManagerACMEAPIVersion, "Order", "web-1-123", old, nil)
+	order.Object["metadata"].(map[string]interface{})["ownerReferences"] = owner("CertificateRequest", "web-1")
+
+	challenge := certManagerObject(certManagerACMEAPIVersion, "Challenge", "web-1-123-456", old, map[string]interface{}{
+		"spec":   map[string]interface{}{"type": "HTTP-01", "dnsName": "shop.example.com"},
+		"status": map[string]interface{}{"state": "pending", "presented": true, "processing": true},
+	})
+	challenge.Object["metadata"].(map[string]interface{})["ownerReferences"] = owner("Order", "web-1-123")
+
+	resource := func(name, kind string) metav1.APIResource {
+		return metav1.APIResource{Name: name, Kind: kind, Namespaced: true, Verbs: metav1.Verbs{"get", "list"}}
+	}
+	apiResources := append(fakecluster.DefaultAPIResources(),
+		&metav1.APIResourceList{
+			GroupVersion: certManagerAPIVersion,
+			APIResources: []metav1.APIResource{resource("certificates", "Certificate"), resource("certificaterequests", "CertificateRequest")},
+		},
+		&metav1.APIResourceList{
+			GroupVersion: certManagerACMEAPIVersion,
+			APIResources: []metav1.APIResource{resource("orders", "Order"), resource("challenges", "Challenge")},
+		},
+	)
+
+	handler := NewResourceHandler(fakecluster.New(fakecluster.Config{
+		Custom:       []*unstructured.Unstructured{certificate, certificateRequest, order, challenge},
+		APIResources: apiResources,
+	}), nil, false)
+
+	result, isErr := callTool(t, handler.GetCertManagerStatus, map[string]any{"namespace": "shop"})
+	if isErr {
+		t.Fatalf("unexpected error: %v", result["error"])
+	}
+
+	if result["installed"] != true {
+		t.Fatalf("expected installed=true, got %v", result)
+	}
+
+	var certificates []CertManagerCertificate
+	decodeInto(t, result["certificates"], &certificates)
+	if len(certificates) != 1 || certificates[0].Issuer != "ClusterIssuer/letsencrypt" || !certificates[0].Issuing || certificates[0].Ready != "False" {
+		t.Errorf("unexpected certificates %+v", certificates)
+	}
+
+	var requests []CertManagerRequest
+	decodeInto(t, result["certificate_requests"], &requests)
+	if len(requests) != 1 || requests[0].Certificate != "web" || len(requests[0].Findings) != 1 || !strings.HasPrefix(requests[0].Findings[0], "is still not ready after 1h") {
+		t.Errorf("unexpected certificate requests %+v", requests)
+	}
+
+	var challenges []CertManagerChallenge
+	decodeInto(t, result["challenges"], &challenges)
+	if len(challenges) != 1 || challenges[0].CertificateRequest != "web-1" || len(challenges[0].Findings) != 1 {
+		t.Errorf("unexpected challenges %+v", challenges)
+	}
+
+	if result["warnings"] != nil {
+		t.Errorf("unexpected warnings %v", result["warnings"])
+	}
+}
@@ -0,0 +1,597 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/connectivity"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/kubernetes"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/response"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/toolschema"
+)
+
+const (
+	// defaultSummaryEventWindow is how far back cluster_summary looks for Warning events.
+	defaultSummaryEventWindow = 60
+
+	// defaultSummaryMaxItems is how many entries cluster_summary lists per section.
+	defaultSummaryMaxItems = 20
+)
+
+// ClusterHandler provides MCP tools that report on the cluster as a whole,
+// aggregating several resource types into a single answer.
+type ClusterHandler struct {
+	client      kubernetes.ClusterReader
+	alwaysStart bool
+
+	// now returns the current time. It defaults to time.Now and is replaced
+	// in tests so event windows and ages are deterministic.
+	now func() time.Time
+}
+
+// NewClusterHandler creates a new ClusterHandler with the provided Kubernetes client.
+// alwaysStart mirrors the --always-start flag: when true, connectivity and auth errors
+// are intercepted and returned as structured tool errors so the LLM can surface them
+// to the user rather than treating them as retryable failures.
+func NewClusterHandler(client kubernetes.ClusterReader, alwaysStart bool) *ClusterHandler {
+	return &ClusterHandler{
+		client:      client,
+		alwaysStart: alwaysStart,
+		now:         time.Now,
+	}
+}
+
+// ClusterSummaryParams defines the parameters for the cluster_summary MCP tool.
+type ClusterSummaryParams struct {
+	// Namespace restricts the pod, event, PVC, and deployment sections to a
+	// namespace. Nodes are always reported cluster-wide.
+	Namespace string `json:"namespace,omitempty" description:"Namespace to summarize (leave empty for all namespaces). Nodes are always summarized cluster-wide"`
+
+	// EventWindowMinutes is how far back to look for Warning events.
+	EventWindowMinutes int `json:"event_window_minutes,omitempty" minimum:"1" default:"60" description:"How many minutes back to look for Warning events (defaults to 60)"`
+
+	// MaxItems caps the entries listed in each section; totals are always reported.
+	MaxItems int `json:"max_items,omitempty" minimum:"1" default:"20" description:"Maximum number of entries listed per section (defaults to 20). Totals always cover every entry"`
+
+	// Context specifies which Kubernetes context to use for this operation.
+	Context string `json:"context,omitempty" description:"Kubernetes context to use (defaults to current context from kubeconfig)"`
+}
+
+// NodeHealthSummary reports node readiness.
+type NodeHealthSummary struct {
+	Total         int            `json:"total"`
+	Ready         int            `json:"ready"`
+	NotReady      []NotReadyNode `json:"not_ready"`
+	Unschedulable []string       `json:"unschedulable"`
+}
+
+// NotReadyNode is a node whose Ready condition is not True.
+type NotReadyNode struct {
+	Name string `json:"name"`
+
+	// Status is the Ready condition status ("False" or "Unknown"), or
+	// "Missing" when the node reports no Ready condition.
+	Status  string `json:"status"`
+	Reason  string `json:"reason,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// PodHealthSummary reports pods that are not running and ready.
+type PodHealthSummary struct {
+	Total        int          `json:"total"`
+	Running      int          `json:"running"`
+	Succeeded    int          `json:"succeeded"`
+	ProblemCount int          `json:"problem_count"`
+	Problems     []ProblemPod `json:"problems"`
+}
+
+// ProblemPod is a pod that is neither completed nor running with all
+// containers ready.
+type ProblemPod struct {
+	Namespace string   `json:"namespace"`
+	Name      string   `json:"name"`
+	Phase     string   `json:"phase"`
+	Ready     string   `json:"ready"`
+	Restarts  int32    `json:"restarts"`
+	Reasons   []string `json:"reasons,omitempty"`
+	Node      string   `json:"node,omitempty"`
+}
+
+// WarningEventSummary groups recent Warning events by object and reason.
+type WarningEventSummary struct {
+	Namespace string `json:"namespace,omitempty"`
+	Object    string `json:"object"`
+	Reason    string `json:"reason"`
+	Message   string `json:"message"`
+	Count     int32  `json:"count"`
+	LastSeen  string `json:"last_seen"`
+}
+
+// PendingClaim is a PersistentVolumeClaim that is not bound yet.
+type PendingClaim struct {
+	Namespace    string `json:"namespace"`
+	Name         string `json:"name"`
+	StorageClass string `json:"storage_class,omitempty"`
+	Age          string `json:"age"`
+}
+
+// FailingDeployment is a Deployment that is unavailable, stuck rolling out,
+// or running fewer ready replicas than desired.
+type FailingDeployment struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Desired   int32  `json:"desired"`
+	Ready     int32  `json:"ready"`
+	Available int32  `json:"available"`
+	Reason    string `json:"reason"`
+	Message   string `json:"message,omitempty"`
+}
+
+// ClusterSummaryResult is the result of the cluster_summary MCP tool. The
+// lists are cut to max_items, while the counts cover every item.
+type ClusterSummaryResult struct {
+	Namespace string            `json:"namespace"`
+	Healthy   bool              `json:"healthy"`
+	Nodes     NodeHealthSummary `json:"nodes"`
+	Pods      PodHealthSummary  `json:"pods"`
+
+	EventWindowMinutes int                   `json:"event_window_minutes"`
+	WarningEventCount  int                   `json:"warning_event_count"`
+	WarningEvents      []WarningEventSummary `json:"warning_events"`
+
+	PendingPVCCount int            `json:"pending_pvc_count"`
+	PendingPVCs     []PendingClaim `json:"pending_pvcs"`
+
+	FailingDeploymentCount int                 `json:"failing_deployment_count"`
+	FailingDeployments     []FailingDeployment `json:"failing_deployments"`
+
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// ClusterSummary implements the cluster_summary MCP tool.
+// It answers "how is my cluster doing?" in a single call by combining node
+// readiness, pods that are not running and ready, recent Warning events,
+// pending PersistentVolumeClaims, and failing Deployments. Sections other than
+// nodes and pods are best effort: when listing them fails (for example, due to
+// RBAC), the failure is reported under warnings and the rest of the summary is
+// still returned.
+func (h *ClusterHandler) ClusterSummary(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var params ClusterSummaryParams
+	if err := bindParams(request, &params); err != nil {
+		return response.Errorf("failed to parse arguments: %s", err)
+	}
+
+	eventWindow := params.EventWindowMinutes
+	if eventWindow == 0 {
+		eventWindow = defaultSummaryEventWindow
+	}
+
+	maxItems := params.MaxItems
+	if maxItems == 0 {
+		maxItems = defaultSummaryMaxItems
+	}
+
+	// Use the appropriate client based on context
+	client, err := h.client.ForContext(ctx, params.Context)
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to create client with context %s: %v", params.Context, err)
+	}
+
+	nodes, err := client.ListNodes(ctx, metav1.ListOptions{})
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to list nodes: %v", err)
+	}
+
+	pods, err := client.ListPods(ctx, params.Namespace, metav1.ListOptions{})
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to list pods: %v", err)
+	}
+
+	now := h.now()
+	var warnings []string
+
+	nodeSummary := summarizeNodeHealth(nodes.Items)
+	podSummary := summarizePodHealth(pods.Items, maxItems)
+
+	events := make([]WarningEventSummary, 0)
+	eventCount := 0
+	if list, err := client.ListEvents(ctx, params.Namespace, metav1.ListOptions{FieldSelector: "type=" + corev1.EventTypeWarning}); err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		warnings = append(warnings, fmt.Sprintf("failed to list events: %v", err))
+	} else {
+		all := summarizeWarningEvents(list.Items, now.Add(-time.Duration(eventWindow)*time.Minute))
+		eventCount = len(all)
+		events = truncate(all, maxItems)
+	}
+
+	claims := make([]PendingClaim, 0)
+	claimCount := 0
+	if list, err := client.ListPersistentVolumeClaims(ctx, params.Namespace, metav1.ListOptions{}); err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		warnings = append(warnings, fmt.Sprintf("failed to list persistent volume claims: %v", err))
+	} else {
+		all := pendingClaims(list.Items, now)
+		claimCount = len(all)
+		claims = truncate(all, maxItems)
+	}
+
+	deployments := make([]FailingDeployment, 0)
+	deploymentCount := 0
+	if list, err := client.ListDeployments(ctx, params.Namespace, metav1.ListOptions{}); err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		warnings = append(warnings, fmt.Sprintf("failed to list deployments: %v", err))
+	} else {
+		all := failingDeployments(list.Items)
+		deploymentCount = len(all)
+		deployments = truncate(all, maxItems)
+	}
+
+	healthy := len(nodeSummary.NotReady) == 0 &&
+		podSummary.ProblemCount == 0 &&
+		claimCount == 0 &&
+		deploymentCount == 0
+
+	return response.JSON(ClusterSummaryResult{
+		Namespace:              params.Namespace,
+		Healthy:                healthy,
+		Nodes:                  nodeSummary,
+		Pods:                   podSummary,
+		EventWindowMinutes:     eventWindow,
+		WarningEventCount:      eventCount,
+		WarningEvents:          events,
+		PendingPVCCount:        claimCount,
+		PendingPVCs:            claims,
+		FailingDeploymentCount: deploymentCount,
+		FailingDeployments:     deployments,
+		Warnings:               warnings,
+	})
+}
+
+// summarizeNodeHealth counts ready nodes and lists the ones that are not
+// ready or are cordoned.
+func summarizeNodeHealth(nodes []corev1.Node) NodeHealthSummary {
+	summary := NodeHealthSummary{
+		Total:         len(nodes),
+		NotReady:      make([]NotReadyNode, 0),
+		Unschedulable: make([]string, 0),
+	}
+
+	for i := range nodes {
+		node := &nodes[i]
+
+		if node.Spec.Unschedulable {
+			summary.Unschedulable = append(summary.Unschedulable, node.Name)
+		}
+
+		if isNodeReady(node) {
+			summary.Ready++
+			continue
+		}
+
+		notReady := NotReadyNode{Name: node.Name, Status: "Missing"}
+		for _, condition := range node.Status.Conditions {
+			if condition.Type == corev1.NodeReady {
+				notReady.Status = string(condition.Status)
+				notReady.Reason = condition.Reason
+				notReady.Message = condition.Message
+				break
+			}
+		}
+		summary.NotReady = append(summary.NotReady, notReady)
+	}
+
+	sort.Slice(summary.NotReady, func(i, j int) bool {
+		return summary.NotReady[i].Name < summary.NotReady[j].Name
+	})
+	sort.Strings(summary.Unschedulable)
+
+	return summary
+}
+
+// summarizePodHealth counts pods by phase and lists up to maxItems pods that
+// are neither completed nor running with every container ready, ordered by
+// restart count so crash-looping pods come first.
+func summarizePodHealth(pods []corev1.Pod, maxItems int) PodHealthSummary {
+	summary := PodHealthSummary{Total: len(pods)}
+	problems := make([]ProblemPod, 0)
+
+	for i := range pods {
+		pod := &pods[i]
+
+		switch pod.Status.Phase {
+		case corev1.PodSucceeded:
+			summary.Succeeded++
+			continue
+		case corev1.PodRunning:
+			summary.Running++
+		}
+
+		ready, total, restarts := 0, len(pod.Spec.Containers), int32(0)
+		for _, status := range pod.Status.ContainerStatuses {
+			if status.Ready {
+				ready++
+			}
+			restarts += status.RestartCount
+		}
+
+		if pod.Status.Phase == corev1.PodRunning && ready == total {
+			continue
+		}
+
+		reasons := waitingReasons(pod)
+		if pod.Status.Reason != "" {
+			reasons = append(reasons, pod.Status.Reason)
+		}
+
+		problems = append(problems, ProblemPod{
+			Namespace: pod.Namespace,
+			Name:      pod.Name,
+			Phase:     string(pod.Status.Phase),
+			Ready:     fmt.Sprintf("%d/%d", ready, total),
+			Restarts:  restarts,
+			Reasons:   reasons,
+			Node:      pod.Spec.NodeName,
+		})
+	}
+
+	sort.Slice(problems, func(i, j int) bool {
+		if problems[i].Restarts != problems[j].Restarts {
+			return problems[i].Restarts > problems[j].Restarts
+		}
+		if problems[i].Namespace != problems[j].Namespace {
+			return problems[i].Namespace < problems[j].Namespace
+		}
+		return problems[i].Name < problems[j].Name
+	})
+
+	summary.ProblemCount = len(problems)
+	summary.Problems = truncate(problems, maxItems)
+
+	return summary
+}
+
+// summarizeWarningEvents groups Warning events last seen after since by
+// involved object and reason, most recent first.
+func summarizeWarningEvents(events []corev1.Event, since time.Time) []WarningEventSummary {
+	type key struct{ namespace, object, reason string }
+
+	grouped := make(map[key]*WarningEventSummary)
+	lastSeen := make(map[key]time.Time)
+
+	for i := range events {
+		event := &events[i]
+		if event.Type != corev1.EventTypeWarning {
+			continue
+		}
+
+		seen := eventLastSeen(event)
+		if seen.Before(since) {
+			continue
+		}
+
+		count := event.Count
+		if event.Series != nil && event.Series.Count > count {
+			count = event.Series.Count
+		}
+		if count == 0 {
+			count = 1
+		}
+
+		k := key{
+			namespace: event.InvolvedObject.Namespace,
+			object:    event.InvolvedObject.Kind + "/" + event.InvolvedObject.Name,
+			reason:    event.Reason,
+		}
+
+		summary, ok := grouped[k]
+		if !ok {
+			summary = &WarningEventSummary{Namespace: k.namespace, Object: k.object, Reason: k.reason}
+			grouped[k] = summary
+		}
+
+		summary.Count += count
+		if seen.After(lastSeen[k]) {
+			lastSeen[k] = seen
+			summary.Message = event.Message
+			summary.LastSeen = formatTime(seen)
+		}
+	}
+
+	keys := make([]key, 0, len(grouped))
+	for k := range grouped {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if !lastSeen[keys[i]].Equal(lastSeen[keys[j]]) {
+			return lastSeen[keys[i]].After(lastSeen[keys[j]])
+		}
+		if keys[i].object != keys[j].object {
+			return keys[i].object < keys[j].object
+		}
+		return keys[i].reason < keys[j].reason
+	})
+
+	result := make([]WarningEventSummary, 0, len(keys))
+	for _, k := range keys {
+		result = append(result, *grouped[k])
+	}
+
+	return result
+}
+
+// eventLastSeen returns the most recent time an event was observed, falling
+// back through the fields populated by the different event APIs.
+func eventLastSeen(event *corev1.Event) time.Time {
+	if event.Series != nil && !event.Series.LastObservedTime.IsZero() {
+		return event.Series.LastObservedTime.Time
+	}
+	if !event.LastTimestamp.IsZero() {
+		return event.LastTimestamp.Time
+	}
+	if !event.EventTime.IsZero() {
+		return event.EventTime.Time
+	}
+	return event.CreationTimestamp.Time
+}
+
+// pendingClaims returns the PersistentVolumeClaims in the Pending phase,
+// oldest first.
+func pendingClaims(claims []corev1.PersistentVolumeClaim, now time.Time) []PendingClaim {
+	pending := make([]PendingClaim, 0)
+
+	sort.SliceStable(claims, func(i, j int) bool {
+		return claims[i].CreationTimestamp.Before(&claims[j].CreationTimestamp)
+	})
+
+	for i := range claims {
+		claim := &claims[i]
+		if claim.Status.Phase != corev1.ClaimPending {
+			continue
+		}
+
+		entry := PendingClaim{
+			Namespace: claim.Namespace,
+			Name:      claim.Name,
+			Age:       now.Sub(claim.CreationTimestamp.Time).Round(time.Second).String(),
+		}
+		if claim.Spec.StorageClassName != nil {
+			entry.StorageClass = *claim.Spec.StorageClassName
+		}
+
+		pending = append(pending, entry)
+	}
+
+	return pending
+}
+
+// failingDeployments returns the Deployments that report an unavailable or
+// stalled rollout, or that run fewer ready replicas than desired.
+func failingDeployments(deployments []appsv1.Deployment) []FailingDeployment {
+	failing := make([]FailingDeployment, 0)
+
+	for i := range deployments {
+		deployment := &deployments[i]
+
+		desired := int32(1)
+		if deployment.Spec.Replicas != nil {
+			desired = *deployment.Spec.Replicas
+		}
+
+		entry := FailingDeployment{
+			Namespace: deployment.Namespace,
+			Name:      deployment.Name,
+			Desired:   desired,
+			Ready:     deployment.Status.ReadyReplicas,
+			Available: deployment.Status.AvailableReplicas,
+		}
+
+		for _, condition := range deployment.Status.Conditions {
+			var failed bool
+			switch condition.Type {
+			case appsv1.DeploymentAvailable, appsv1.DeploymentProgressing:
+				failed = condition.Status == corev1.ConditionFalse
+			case appsv1.DeploymentReplicaFailure:
+				failed = condition.Status == corev1.ConditionTrue
+			}
+
+			if failed && entry.Reason == "" {
+				entry.Reason = condition.Reason
+				entry.Message = condition.Message
+			}
+		}
+
+		if entry.Reason == "" && entry.Ready < desired {
+			entry.Reason = "ReplicasNotReady"
+			entry.Message = fmt.Sprintf("%d of %d replicas are ready", entry.Ready, desired)
+		}
+
+		if entry.Reason != "" {
+			failing = append(failing, entry)
+		}
+	}
+
+	sort.Slice(failing, func(i, j int) bool {
+		if failing[i].Namespace != failing[j].Namespace {
+			return failing[i].Namespace < failing[j].Namespace
+		}
+		return failing[i].Name < failing[j].Name
+	})
+
+	return failing
+}
+
+// truncate returns at most n items of s.
+func truncate[T any](s []T, n int) []T {
+	if len(s) > n {
+		return s[:n]
+	}
+	return s
+}
+
+// GetTools returns all cluster-level MCP tools provided by this handler.
+func (h *ClusterHandler) GetTools() []MCPTool {
+	return []MCPTool{
+		NewMCPTool(
+			mcp.NewTool("cluster_summary",
+				mcp.WithDescription("Summarize cluster health in one call: node readiness, pods that are not running and ready, recent Warning events grouped by object and reason, pending PersistentVolumeClaims, and failing Deployments. Use it first for \"how is my cluster doing?\" questions"),
+				toolschema.Input[ClusterSummaryParams](),
+				toolschema.Output[ClusterSummaryResult](),
+			),
+			h.ClusterSummary,
+		),
+		NewMCPTool(
+			mcp.NewTool("get_cluster_info",
+				mcp.WithDescription("Identify the connected cluster: kubeconfig context and cluster, API server URL, Kubernetes version and build details, the platform it most likely runs on (EKS, GKE, AKS, OpenShift, k3s, RKE2, kind, minikube, or Docker Desktop, with the evidence for it and the nodes' cloud provider), and the API groups and versions it serves. Use it to learn what kind of cluster you are talking to before giving platform-specific advice"),
+				toolschema.Input[GetClusterInfoParams](),
+				toolschema.Output[GetClusterInfoResult](),
+			),
+			h.GetClusterInfo,
+		),
+		NewMCPTool(
+			mcp.NewTool("check_control_plane_health",
+				mcp.WithDescription("Check the health of the control plane: probes the API server's /livez and /readyz endpoints, with the verbose per-check breakdown where permitted, and reads the leader election Leases of kube-scheduler and kube-controller-manager to see whether a leader is still renewing them. Reports kube-apiserver, etcd, kube-scheduler, and kube-controller-manager as healthy, unhealthy, or unknown, and lists the failing checks. Replaces the deprecated componentstatuses API"),
+				toolschema.Input[CheckControlPlaneHealthParams](),
+				toolschema.Output[CheckControlPlaneHealthResult](),
+			),
+			h.CheckControlPlaneHealth,
+		),
+		NewMCPTool(
+			mcp.NewTool("list_webhooks",
+				mcp.WithDescription("Audit admission webhooks from ValidatingWebhookConfigurations and MutatingWebhookConfigurations: failure policy, namespace and object selectors, timeout, side effects, and rules, and whether the backing Service exists and has ready endpoints. Flags webhooks that would reject requests because their backend is missing or down, fail closed for every namespace including kube-system, match every resource, or use long timeouts. Use it when API requests fail with \"failed calling webhook\" errors"),
+				toolschema.Input[ListWebhooksParams](),
+				toolschema.Output[ListWebhooksResult](),
+			),
+			h.ListWebhooks,
+		),
+		NewMCPTool(
+			mcp.NewTool("priority_class_report",
+				mcp.WithDescription("List PriorityClasses with their value, preemption policy, whether they are the global default, and how many pods use them, then show which workloads run at which priority, from the highest. Also reports the pods preempted recently, from the Preempted events the scheduler records and the Preempting events the kubelet records when it evicts pods to admit critical ones. Use it to investigate capacity problems, unexpected evictions, or pods that keep getting replaced."),
+				toolschema.Input[PriorityClassReportParams](),
+				toolschema.Output[PriorityClassReportResult](),
+			),
+			h.PriorityClassReport,
+		),
+	}
+}
@@ -0,0 +1,243 @@
+package handlers
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/fakecluster"
+)
+
+func TestFailingDeployments(t *testing.T) {
+	t.Parallel()
+
+	replicas := func(n int32) *int32 { return &n }
+
+	tests := []struct {
+		name       string
+		deployment appsv1.Deployment
+		wantReason string
+	}{
+		{
+			name: "healthy",
+			deployment: appsv1.Deployment{
+				Spec:   appsv1.DeploymentSpec{Replicas: replicas(2)},
+				Status: appsv1.DeploymentStatus{ReadyReplicas: 2, AvailableReplicas: 2},
+			},
+		},
+		{
+			name:       "replicas default to one",
+			deployment: appsv1.Deployment{},
+			wantReason: "ReplicasNotReady",
+		},
+		{
+			name: "scaled to zero",
+			deployment: appsv1.Deployment{
+				Spec: appsv1.DeploymentSpec{Replicas: replicas(0)},
+			},
+		},
+		{
+			name: "progress deadline exceeded",
+			deployment: appsv1.Deployment{
+				Spec: appsv1.DeploymentSpec{Replicas: replicas(1)},
+				Status: appsv1.DeploymentStatus{
+					ReadyReplicas: 1,
+					Conditions: []appsv1.DeploymentCondition{
+						{Type: appsv1.DeploymentAvailable, Status: corev1.ConditionTrue, Reason: "MinimumReplicasAvailable"},
+						{Type: appsv1.DeploymentProgressing, Status: corev1.ConditionFalse, Reason: "ProgressDeadlineExceeded"},
+					},
+				},
+			},
+			wantReason: "ProgressDeadlineExceeded",
+		},
+		{
+			name: "replica failure",
+			deployment: appsv1.Deployment{
+				Spec: appsv1.DeploymentSpec{Replicas: replicas(1)},
+				Status: appsv1.DeploymentStatus{
+					Conditions: []appsv1.DeploymentCondition{
+						{Type: appsv1.DeploymentReplicaFailure, Status: corev1.ConditionTrue, Reason: "FailedCreate"},
+					},
+				},
+			},
+			wantReason: "FailedCreate",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			failing := failingDeployments([]appsv1.Deployment{tt.deployment})
+
+			var got string
+			if len(failing) > 0 {
+				got = failing[0].Reason
+			}
+			if got != tt.wantReason {
+				t.Errorf("expected reason %q, got %q", tt.wantReason, got)
+			}
+		})
+	}
+}
+
+func TestClusterSummary(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	ago := func(d time.Duration) metav1.Time { return metav1.NewTime(now.Add(-d)) }
+
+	readyNode := func(name string, status corev1.ConditionStatus) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status: corev1.NodeStatus{Conditions: []corev1.NodeCondition{
+				{Type: corev1.NodeReady, Status: status, Reason: "KubeletNotReady"},
+			}},
+		}
+	}
+
+	handler := NewClusterHandler(fakecluster.New(fakecluster.Config{
+		Objects: []runtime.Object{
+			readyNode("node-a", corev1.ConditionTrue),
+			readyNode("node-b", corev1.ConditionUnknown),
+			&corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop"},
+				Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "app"}}},
+				Status: corev1.PodStatus{
+					Phase:             corev1.PodRunning,
+					ContainerStatuses: []corev1.ContainerStatus{{Name: "app", Ready: true}},
+				},
+			},
+			&corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "worker", Namespace: "shop"},
+				Spec:       corev1.PodSpec{NodeName: "node-a", Containers: []corev1.Container{{Name: "app"}}},
+				Status: corev1.PodStatus{
+					Phase: corev1.PodRunning,
+					ContainerStatuses: []corev1.ContainerStatus{{
+						Name:         "app",
+						RestartCount: 7,
+						State:        corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+					}},
+				},
+			},
+			&corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "migrate", Namespace: "shop"},
+				Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "app"}}},
+				Status:     corev1.PodStatus{Phase: corev1.PodSucceeded},
+			},
+			&corev1.Event{
+				ObjectMeta:     metav1.ObjectMeta{Name: "worker.1", Namespace: "shop"},
+				InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "worker", Namespace: "shop"},
+				Type:           corev1.EventTypeWarning,
+				Reason:         "BackOff",
+				Message:        "Back-off restarting failed container",
+				Count:          12,
+				LastTimestamp:  ago(5 * time.Minute),
+			},
+			&corev1.Event{
+				ObjectMeta:     metav1.ObjectMeta{Name: "worker.2", Namespace: "shop"},
+				InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "worker", Namespace: "shop"},
+				Type:           corev1.EventTypeWarning,
+				Reason:         "BackOff",
+				Message:        "Back-off restarting failed container app",
+				Count:          3,
+				LastTimestamp:  ago(time.Minute),
+			},
+			&corev1.Event{
+				ObjectMeta:     metav1.ObjectMeta{Name: "old", Namespace: "shop"},
+				InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "web", Namespace: "shop"},
+				Type:           corev1.EventTypeWarning,
+				Reason:         "Unhealthy",
+				LastTimestamp:  ago(3 * time.Hour),
+			},
+			&corev1.Event{
+				ObjectMeta:     metav1.ObjectMeta{Name: "normal", Namespace: "shop"},
+				InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "web", Namespace: "shop"},
+				Type:           corev1.EventTypeNormal,
+				Reason:         "Pulled",
+				LastTimestamp:  ago(time.Minute),
+			},
+			&corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{Name: "data", Namespace: "shop", CreationTimestamp: ago(10 * time.Minute)},
+				Status:     corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimPending},
+			},
+			&corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{Name: "cache", Namespace: "shop"},
+				Status:     corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimBound},
+			},
+			&appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Name: "worker", Namespace: "shop"},
+				Status:     appsv1.DeploymentStatus{Replicas: 1},
+			},
+		},
+	}), false)
+	handler.now = func() time.Time { return now }
+
+	result, isErr := callTool(t, handler.ClusterSummary, map[string]any{})
+	if isErr {
+		t.Fatalf("unexpected error: %v", result["error"])
+	}
+
+	if result["healthy"] != false {
+		t.Errorf("expected the cluster to be reported unhealthy")
+	}
+
+	var nodes NodeHealthSummary
+	decodeInto(t, result["nodes"], &nodes)
+	if nodes.Total != 2 || nodes.Ready != 1 || len(nodes.NotReady) != 1 || nodes.NotReady[0].Status != "Unknown" {
+		t.Errorf("unexpected node summary: %#v", nodes)
+	}
+
+	var pods PodHealthSummary
+	decodeInto(t, result["pods"], &pods)
+	wantPods := PodHealthSummary{
+		Total:        3,
+		Running:      2,
+		Succeeded:    1,
+		ProblemCount: 1,
+		Problems: []ProblemPod{{
+			Namespace: "shop",
+			Name:      "worker",
+			Phase:     "Running",
+			Ready:     "0/1",
+			Restarts:  7,
+			Reasons:   []string{"CrashLoopBackOff"},
+			Node:      "node-a",
+		}},
+	}
+	if !reflect.DeepEqual(pods, wantPods) {
+		t.Errorf("pod summary mismatch\nwant: %#v\ngot:  %#v", wantPods, pods)
+	}
+
+	var events []WarningEventSummary
+	decodeInto(t, result["warning_events"], &events)
+	wantEvents := []WarningEventSummary{{
+		Namespace: "shop",
+		Object:    "Pod/worker",
+		Reason:    "BackOff",
+		Message:   "Back-off restarting failed container app",
+		Count:     15,
+		LastSeen:  "2024-05-01T11:59:00Z",
+	}}
+	if !reflect.DeepEqual(events, wantEvents) {
+		t.Errorf("warning events mismatch\nwant: %#v\ngot:  %#v", wantEvents, events)
+	}
+
+	var claims []PendingClaim
+	decodeInto(t, result["pending_pvcs"], &claims)
+	if len(claims) != 1 || claims[0].Name != "data" || claims[0].Age != "10m0s" {
+		t.Errorf("unexpected pending claims: %#v", claims)
+	}
+
+	var deployments []FailingDeployment
+	decodeInto(t, result["failing_deployments"], &deployments)
+	if len(deployments) != 1 || deployments[0].Name != "worker" || deployments[0].Reason != "ReplicasNotReady" {
+		t.Errorf("unexpected failing deployments: %#v", deployments)
+	}
+}
@@ -0,0 +1,372 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/connectivity"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/response"
+)
+
+// clusterInfoNodeSample is how many nodes get_cluster_info reads to detect
+// the platform. Node labels and provider IDs are the same across a cluster's
+// nodes, so a few are enough.
+const clusterInfoNodeSample = 5
+
+// GetClusterInfoParams defines the parameters for the get_cluster_info MCP tool.
+type GetClusterInfoParams struct {
+	// Context specifies which Kubernetes context to use for this operation.
+	Context string `json:"context,omitempty" description:"Kubernetes context to use (defaults to current context from kubeconfig)"`
+}
+
+// ClusterPlatform is the Kubernetes distribution or managed service a
+// cluster most likely runs on, with the signals that point at it.
+type ClusterPlatform struct {
+	// Name is a platform such as EKS, GKE, AKS, or kind, or "unknown".
+	Name     string   `json:"name"`
+	Evidence []string `json:"evidence,omitempty"`
+
+	// CloudProvider is the provider named in the nodes' provider IDs, such
+	// as aws, gce, or azure.
+	CloudProvider string `json:"cloud_provider,omitempty"`
+
+	// Alternatives lists other platforms some signals point at.
+	Alternatives []string `json:"alternatives,omitempty"`
+}
+
+// APIGroupVersions is an API group and the versions the cluster serves.
+type APIGroupVersions struct {
+	// Name is the group name, or "core" for the legacy core group.
+	Name     string   `json:"name"`
+	Versions []string `json:"versions"`
+}
+
+// clusterSignals are the facts platform detection looks at.
+type clusterSignals struct {
+	contextName string
+	clusterName string
+	serverURL   string
+	gitVersion  string
+	nodes       []corev1.Node
+	groups      map[string]bool
+}
+
+// platformChecks detect a platform from the cluster's signals. Each returns
+// the evidence found, if any. They are listed in order of preference for
+// breaking ties.
+var platformChecks = []struct {
+	name  string
+	check func(clusterSignals) []string
+}{
+	{"EKS", func(s clusterSignals) []string {
+		return collectEvidence(
+			versionEvidence(s, "-eks-"),
+			hostEvidence(s, ".eks.amazonaws.com"),
+			nodeLabelEvidence(s, "eks.amazonaws.com/nodegroup", "eks.amazonaws.com/compute-type"),
+			namePrefixEvidence(s, "arn:aws:eks:"),
+		)
+	}},
+	{"GKE", func(s clusterSignals) []string {
+		return collectEvidence(
+			versionEvidence(s, "-gke."),
+			nodeLabelEvidence(s, "cloud.google.com/gke-nodepool"),
+			namePrefixEvidence(s, "gke_"),
+		)
+	}},
+	{"AKS", func(s clusterSignals) []string {
+		return collectEvidence(
+			hostEvidence(s, ".azmk8s.io"),
+			nodeLabelEvidence(s, "kubernetes.azure.com/cluster"),
+		)
+	}},
+	{"OpenShift", func(s clusterSignals) []string {
+		return collectEvidence(
+			groupEvidence(s, "config.openshift.io"),
+			groupEvidence(s, "route.openshift.io"),
+		)
+	}},
+	{"k3s", func(s clusterSignals) []string {
+		return collectEvidence(
+			versionEvidence(s, "+k3s"),
+			nodeLabelValueEvidence(s, "node.kubernetes.io/instance-type", "k3s"),
+		)
+	}},
+	{"RKE2", func(s clusterSignals) []string {
+		return collectEvidence(versionEvidence(s, "+rke2"))
+	}},
+	{"kind", func(s clusterSignals) []string {
+		return collectEvidence(
+			providerEvidence(s, "kind"),
+			namePrefixEvidence(s, "kind-"),
+		)
+	}},
+	{"minikube", func(s clusterSignals) []string {
+		return collectEvidence(
+			nodeLabelEvidence(s, "minikube.k8s.io/name"),
+			namePrefixEvidence(s, "minikube"),
+		)
+	}},
+	{"Docker Desktop", func(s clusterSignals) []string {
+		return collectEvidence(
+			nodeNameEvidence(s, "docker-desktop"),
+			namePrefixEvidence(s, "docker-desktop"),
+		)
+	}},
+}
+
+// ServerVersionInfo is the version the API server reports.
+type ServerVersionInfo struct {
+	GitVersion string `json:"git_version"`
+	Major      string `json:"major"`
+	Minor      string `json:"minor"`
+	Platform   string `json:"platform"`
+	GoVersion  string `json:"go_version"`
+	BuildDate  string `json:"build_date"`
+}
+
+// GetClusterInfoResult is the result of the get_cluster_info MCP tool.
+type GetClusterInfoResult struct {
+	Cluster       ConnectedCluster   `json:"cluster"`
+	ServerURL     string             `json:"server_url,omitempty"`
+	Version       ServerVersionInfo  `json:"version"`
+	Platform      ClusterPlatform    `json:"platform"`
+	APIGroups     []APIGroupVersions `json:"api_groups"`
+	APIGroupCount int                `json:"api_group_count"`
+	Warnings      []string           `json:"warnings,omitempty"`
+}
+
+// GetClusterInfo implements the get_cluster_info MCP tool.
+// It reports what the connected cluster is: the kubeconfig context and API
+// server URL, the Kubernetes version, the API groups and versions it serves,
+// and the distribution or managed service it most likely runs on. The
+// platform is detected from the version string, server URL, kubeconfig
+// names, node labels and provider IDs, and served API groups. Everything but
+// the server version is best effort.
+func (h *ClusterHandler) GetClusterInfo(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var params GetClusterInfoParams
+	if err := bindParams(request, &params); err != nil {
+		return response.Errorf("failed to parse arguments: %s", err)
+	}
+
+	// Use the appropriate client based on context
+	client, err := h.client.ForContext(ctx, params.Context)
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to create client with context %s: %v", params.Context, err)
+	}
+
+	info, err := client.ServerVersion()
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to get server version: %v", err)
+	}
+
+	var warnings []string
+
+	cluster, err := kubeconfigCluster(client, params.Context)
+	if err != nil {
+		warnings = append(warnings, fmt.Sprintf("failed to read kubeconfig contexts: %v", err))
+	}
+	cluster.ServerVersion = info.GitVersion
+
+	signals := clusterSignals{
+		contextName: cluster.Context,
+		clusterName: cluster.Cluster,
+		serverURL:   client.ServerURL(),
+		gitVersion:  info.GitVersion,
+		groups:      make(map[string]bool),
+	}
+
+	if nodes, err := client.ListNodes(ctx, metav1.ListOptions{Limit: clusterInfoNodeSample}); err != nil {
+		warnings = append(warnings, fmt.Sprintf("failed to list nodes, so node labels were not used to detect the platform: %v", err))
+	} else {
+		signals.nodes = nodes.Items
+	}
+
+	groups := make([]APIGroupVersions, 0)
+	lists, err := client.DiscoverAllResources(ctx)
+	if err != nil {
+		warnings = append(warnings, fmt.Sprintf("API discovery was incomplete: %v", err))
+	}
+	versions := make(map[string][]string)
+	for _, list := range lists {
+		gv, err := schema.ParseGroupVersion(list.GroupVersion)
+		if err != nil {
+			continue
+		}
+		name := gv.Group
+		if name == "" {
+			name = "core"
+		}
+		if !containsString(versions[name], gv.Version) {
+			versions[name] = append(versions[name], gv.Version)
+		}
+		signals.groups[gv.Group] = true
+	}
+	for name, served := range versions {
+		sort.Strings(served)
+		groups = append(groups, APIGroupVersions{Name: name, Versions: served})
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if (groups[i].Name == "core") != (groups[j].Name == "core") {
+			return groups[i].Name == "core"
+		}
+		return groups[i].Name < groups[j].Name
+	})
+
+	return response.JSON(GetClusterInfoResult{
+		Cluster:   cluster,
+		ServerURL: signals.serverURL,
+		Version: ServerVersionInfo{
+			GitVersion: info.GitVersion,
+			Major:      info.Major,
+			Minor:      info.Minor,
+			Platform:   info.Platform,
+			GoVersion:  info.GoVersion,
+			BuildDate:  info.BuildDate,
+		},
+		Platform:      detectPlatform(signals),
+		APIGroups:     groups,
+		APIGroupCount: len(groups),
+		Warnings:      warnings,
+	})
+}
+
+// detectPlatform picks the platform with the most evidence, breaking ties
+// in the order of platformChecks.
+func detectPlatform(s clusterSignals) ClusterPlatform {
+	platform := ClusterPlatform{Name: "unknown", CloudProvider: nodeCloudProvider(s.nodes)}
+
+	for _, candidate := range platformChecks {
+		evidence := candidate.check(s)
+		if len(evidence) == 0 {
+			continue
+		}
+
+		if len(evidence) > len(platform.Evidence) {
+			if platform.Name != "unknown" {
+				platform.Alternatives = append(platform.Alternatives, platform.Name)
+			}
+			platform.Name = candidate.name
+			platform.Evidence = evidence
+		} else {
+			platform.Alternatives = append(platform.Alternatives, candidate.name)
+		}
+	}
+
+	return platform
+}
+
+// nodeCloudProvider returns the scheme of the first node provider ID, such
+// as aws for aws:///us-east-1a/i-0123.
+func nodeCloudProvider(nodes []corev1.Node) string {
+	for i := range nodes {
+		if scheme, _, ok := strings.Cut(nodes[i].Spec.ProviderID, "://"); ok && scheme != "" {
+			return scheme
+		}
+	}
+	return ""
+}
+
+// collectEvidence drops the checks that found nothing.
+func collectEvidence(found ...string) []string {
+	var evidence []string
+	for _, item := range found {
+		if item != "" {
+			evidence = append(evidence, item)
+		}
+	}
+	return evidence
+}
+
+// versionEvidence reports a server version containing marker.
+func versionEvidence(s clusterSignals, marker string) string {
+	if strings.Contains(s.gitVersion, marker) {
+		return fmt.Sprintf("server version %s contains %q", s.gitVersion, marker)
+	}
+	return ""
+}
+
+// hostEvidence reports an API server host ending in suffix.
+func hostEvidence(s clusterSignals, suffix string) string {
+	parsed, err := url.Parse(s.serverURL)
+	if err != nil || parsed.Hostname() == "" {
+		return ""
+	}
+	if host := strings.ToLower(parsed.Hostname()); strings.HasSuffix(host, suffix) {
+		return fmt.Sprintf("API server host %s ends in %s", host, suffix)
+	}
+	return ""
+}
+
+// namePrefixEvidence reports a context or cluster name starting with prefix.
+func namePrefixEvidence(s clusterSignals, prefix string) string {
+	for _, name := range []string{s.contextName, s.clusterName} {
+		if strings.HasPrefix(name, prefix) {
+			return fmt.Sprintf("kubeconfig name %s starts with %s", name, prefix)
+		}
+	}
+	return ""
+}
+
+// nodeLabelEvidence reports a node carrying any of the label keys.
+func nodeLabelEvidence(s clusterSignals, keys ...string) string {
+	for i := range s.nodes {
+		for _, key := range keys {
+			if _, ok := s.nodes[i].Labels[key]; ok {
+				return fmt.Sprintf("node %s has label %s", s.nodes[i].Name, key)
+			}
+		}
+	}
+	return ""
+}
+
+// nodeLabelValueEvidence reports a node whose label key is set to value.
+func nodeLabelValueEvidence(s clusterSignals, key, value string) string {
+	for i := range s.nodes {
+		if s.nodes[i].Labels[key] == value {
+			return fmt.Sprintf("node %s has label %s=%s", s.nodes[i].Name, key, value)
+		}
+	}
+	return ""
+}
+
+// nodeNameEvidence reports a node with the given name.
+func nodeNameEvidence(s clusterSignals, name string) string {
+	for i := range s.nodes {
+		if s.nodes[i].Name == name {
+			return "node " + name + " exists"
+		}
+	}
+	return ""
+}
+
+// providerEvidence reports a node provider ID with the given scheme.
+func providerEvidence(s clusterSignals, scheme string) string {
+	for i := range s.nodes {
+		if strings.HasPrefix(s.nodes[i].Spec.ProviderID, scheme+"://") {
+			return fmt.Sprintf("node %s has provider ID %s", s.nodes[i].Name, s.nodes[i].Spec.ProviderID)
+		}
+	}
+	return ""
+}
+
+// groupEvidence reports a served API group.
+func groupEvidence(s clusterSignals, group string) string {
+	if s.groups[group] {
+		return "serves the " + group + " API group"
+	}
+	return ""
+}
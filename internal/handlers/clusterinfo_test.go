@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/fakecluster"
+)
+
+func TestDetectPlatform(t *testing.T) {
+	t.Parallel()
+
+	node := func(name, providerID string, labels map[string]string) corev1.Node {
+		return corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}, Spec: corev1.NodeSpec{ProviderID: providerID}}
+	}
+
+	tests := []struct {
+		name    string
+		signals clusterSignals
+		want    ClusterPlatform
+	}{
+		{
+			name: "eks",
+			signals: clusterSignals{
+				clusterName: "arn:aws:eks:us-east-1:123:cluster/prod",
+				serverURL:   "https://ABC123.gr7.us-east-1.eks.amazonaws.com",
+				gitVersion:  "v1.29.4-eks-036c24b",
+				nodes:       []corev1.Node{node("ip-10-0-0-1", "aws:///us-east-1a/i-0123", map[string]string{"eks.amazonaws.com/nodegroup": "default"})},
+			},
+			want: ClusterPlatform{
+				Name: "EKS",
+				Evidence: []string{
+					`server version v1.29.4-eks-036c24b contains "-eks-"`,
+					"API server host abc123.gr7.us-east-1.eks.amazonaws.com ends in .eks.amazonaws.com",
+					"node ip-10-0-0-1 has label eks.amazonaws.com/nodegroup",
+					"kubeconfig name arn:aws:eks:us-east-1:123:cluster/prod starts with arn:aws:eks:",
+				},
+				CloudProvider: "aws",
+			},
+		},
+		{
+			name: "kind",
+			signals: clusterSignals{
+				contextName: "kind-dev",
+				serverURL:   "https://127.0.0.1:6443",
+				gitVersion:  "v1.30.0",
+				nodes:       []corev1.Node{node("dev-control-plane", "kind://docker/dev/dev-control-plane", nil)},
+			},
+			want: ClusterPlatform{
+				Name:          "kind",
+				Evidence:      []string{"node dev-control-plane has provider ID kind://docker/dev/dev-control-plane", "kubeconfig name kind-dev starts with kind-"},
+				CloudProvider: "kind",
+			},
+		},
+		{
+			name: "openshift on aws",
+			signals: clusterSignals{
+				gitVersion: "v1.28.9+416ecaf",
+				groups:     map[string]bool{"config.openshift.io": true, "route.openshift.io": true},
+				nodes:      []corev1.Node{node("worker-0", "aws:///us-east-1a/i-0456", nil)},
+			},
+			want: ClusterPlatform{
+				Name:          "OpenShift",
+				Evidence:      []string{"serves the config.openshift.io API group", "serves the route.openshift.io API group"},
+				CloudProvider: "aws",
+			},
+		},
+		{
+			name:    "unknown",
+			signals: clusterSignals{gitVersion: "v1.30.0"},
+			want:    ClusterPlatform{Name: "unknown"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := detectPlatform(tt.signals); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestGetClusterInfo_FakeCluster(t *testing.T) {
+	t.Parallel()
+
+	handler := NewClusterHandler(fakecluster.New(fakecluster.Config{
+		ServerVersion: "v1.29.4-gke.1043002",
+		Objects: []runtime.Object{
+			&corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{Name: "gke-pool-1", Labels: map[string]string{"cloud.google.com/gke-nodepool": "pool"}},
+				Spec:       corev1.NodeSpec{ProviderID: "gce://project/us-central1-a/gke-pool-1"},
+			},
+		},
+	}), false)
+
+	result, isErr := callTool(t, handler.GetClusterInfo, map[string]any{})
+	if isErr {
+		t.Fatalf("unexpected error: %v", result["error"])
+	}
+
+	var platform ClusterPlatform
+	decodeInto(t, result["platform"], &platform)
+	if platform.Name != "GKE" || platform.CloudProvider != "gce" || len(platform.Evidence) != 2 {
+		t.Errorf("unexpected platform %+v", platform)
+	}
+
+	var groups []APIGroupVersions
+	decodeInto(t, result["api_groups"], &groups)
+	want := []APIGroupVersions{
+		{Name: "core", Versions: []string{"v1"}},
+		{Name: "apps", Versions: []string{"v1"}},
+		{Name: "batch", Versions: []string{"v1"}},
+	}
+	if !reflect.DeepEqual(groups, want) {
+		t.Errorf("expected groups %+v, got %+v", want, groups)
+	}
+
+	if version, ok := result["version"].(map[string]any); !ok || version["git_version"] != "v1.29.4-gke.1043002" {
+		t.Errorf("unexpected version %+v", result["version"])
+	}
+	if _, ok := result["server_url"]; ok {
+		t.Errorf("expected no server URL for a client without a kubeconfig, got %v", result["server_url"])
+	}
+}
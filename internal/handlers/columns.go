@@ -0,0 +1,171 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// column is a field list_resources projects out of every listed object.
+type column struct {
+	// name is the path as given, used as the column's key in every item.
+	name string
+
+	// steps are the map keys and list indexes to follow, in order.
+	steps []columnStep
+}
+
+// columnStep is one step of a column path: a map key, a list index, or,
+// with all set, every element of a list.
+type columnStep struct {
+	key   string
+	index int
+	all   bool
+}
+
+// parseColumns parses the comma-separated columns argument of
+// list_resources. Paths are dot-separated keys, such as status.phase, where
+// a leading dot is optional and dots inside a key, as in label names, are
+// escaped with a backslash. A key may be followed by [n] to pick a list
+// element, or [*] for all of them; keys applied to a list are applied to
+// each of its elements, so spec.containers.image lists every image.
+func parseColumns(value string) ([]column, error) {
+	var columns []column
+	seen := make(map[string]bool)
+
+	for _, raw := range strings.Split(value, ",") {
+		name := strings.TrimPrefix(strings.TrimSpace(raw), ".")
+		if name == "" {
+			continue
+		}
+
+		steps, err := parseColumnPath(name)
+		if err != nil {
+			return nil, fmt.Errorf("invalid column %q: %w", name, err)
+		}
+		if !seen[name] {
+			seen[name] = true
+			columns = append(columns, column{name: name, steps: steps})
+		}
+	}
+
+	if len(columns) == 0 {
+		return nil, errors.New("no columns given: pass paths such as \"metadata.name,status.phase\"")
+	}
+	return columns, nil
+}
+
+// parseColumnPath splits a column path into its steps.
+func parseColumnPath(path string) ([]columnStep, error) {
+	var (
+		steps []columnStep
+		key   strings.Builder
+	)
+
+	for i := 0; i < len(path); i++ {
+		switch c := path[i]; c {
+		case '\\':
+			if i+1 == len(path) {
+				return nil, errors.New("ends with a backslash")
+			}
+			i++
+			key.WriteByte(path[i])
+		case '.':
+			if key.Len() == 0 && (len(steps) == 0 || path[i-1] != ']') {
+				return nil, errors.New("has an empty key")
+			}
+			if key.Len() > 0 {
+				steps = append(steps, columnStep{key: key.String()})
+				key.Reset()
+			}
+		case '[':
+			end := strings.IndexByte(path[i:], ']')
+			if end < 0 {
+				return nil, errors.New("has an unterminated [")
+			}
+			if key.Len() > 0 {
+				steps = append(steps, columnStep{key: key.String()})
+				key.Reset()
+			}
+
+			index := path[i+1 : i+end]
+			i += end
+			if index == "*" {
+				steps = append(steps, columnStep{all: true})
+				continue
+			}
+			n, err := strconv.Atoi(index)
+			if err != nil || n < 0 {
+				return nil, fmt.Errorf("has an invalid list index [%s]: use a non-negative number or *", index)
+			}
+			steps = append(steps, columnStep{index: n})
+		default:
+			key.WriteByte(c)
+		}
+	}
+
+	if key.Len() > 0 {
+		steps = append(steps, columnStep{key: key.String()})
+	} else if path[len(path)-1] == '.' {
+		return nil, errors.New("has an empty key")
+	}
+	return steps, nil
+}
+
+// projectColumns returns the values of columns in object, keyed by column
+// name. Fields missing from the object are null.
+func projectColumns(object map[string]any, columns []column) map[string]any {
+	projected := make(map[string]any, len(columns))
+	for _, column := range columns {
+		value, _ := lookupColumn(object, column.steps)
+		projected[column.name] = value
+	}
+	return projected
+}
+
+// lookupColumn follows steps from value. Keys applied to a list are applied
+// to each element, and the elements missing the rest of the path are left
+// out of the result.
+func lookupColumn(value any, steps []columnStep) (any, bool) {
+	if len(steps) == 0 {
+		return value, true
+	}
+	step := steps[0]
+
+	if list, ok := value.([]any); ok && (step.all || step.key != "") {
+		rest := steps
+		if step.all {
+			rest = steps[1:]
+		}
+
+		values := make([]any, 0, len(list))
+		for _, element := range list {
+			if found, ok := lookupColumn(element, rest); ok {
+				values = append(values, found)
+			}
+		}
+		return values, true
+	}
+
+	switch {
+	case step.key != "":
+		object, ok := value.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		field, ok := object[step.key]
+		if !ok {
+			return nil, false
+		}
+		return lookupColumn(field, steps[1:])
+	case step.all:
+		return nil, false
+	default:
+		list, ok := value.([]any)
+		if !ok || step.index >= len(list) {
+			return nil, false
+		}
+		return lookupColumn(list[step.index], steps[1:])
+	}
+}
@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/fakecluster"
+)
+
+func TestParseColumns(t *testing.T) {
+	t.Parallel()
+
+	columns, err := parseColumns(" .metadata.name, spec.containers[0].image,metadata.labels.app\\.kubernetes\\.io/name,spec.containers[*].name,metadata.name")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []column{
+		{name: "metadata.name", steps: []columnStep{{key: "metadata"}, {key: "name"}}},
+		{name: "spec.containers[0].image", steps: []columnStep{{key: "spec"}, {key: "containers"}, {index: 0}, {key: "image"}}},
+		{name: "metadata.labels.app\\.kubernetes\\.io/name", steps: []columnStep{{key: "metadata"}, {key: "labels"}, {key: "app.kubernetes.io/name"}}},
+		{name: "spec.containers[*].name", steps: []columnStep{{key: "spec"}, {key: "containers"}, {all: true}, {key: "name"}}},
+	}
+	if !reflect.DeepEqual(columns, want) {
+		t.Errorf("expected columns %+v, got %+v", want, columns)
+	}
+
+	for _, value := range []string{"", " , ", "metadata..name", "status.", "spec.containers[0", "spec.containers[-1]", "spec.containers[x]", "metadata\\"} {
+		if _, err := parseColumns(value); err == nil {
+			t.Errorf("expected %q to be rejected", value)
+		}
+	}
+}
+
+func TestProjectColumns(t *testing.T) {
+	t.Parallel()
+
+	object := map[string]any{
+		"metadata": map[string]any{"name": "web-1", "labels": map[string]any{"app.kubernetes.io/name": "web"}},
+		"spec": map[string]any{
+			"nodeName": "worker-1",
+			"containers": []any{
+				map[string]any{"name": "app", "image": "shop:1.2"},
+				map[string]any{"name": "proxy"},
+			},
+		},
+	}
+
+	columns, err := parseColumns("metadata.name,spec.nodeName,status.phase,spec.containers.image,spec.containers[1].name,spec.containers[5].name,metadata.labels.app\\.kubernetes\\.io/name")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]any{
+		"metadata.name":                              "web-1",
+		"spec.nodeName":                              "worker-1",
+		"status.phase":                               nil,
+		"spec.containers.image":                      []any{"shop:1.2"},
+		"spec.containers[1].name":                    "proxy",
+		"spec.containers[5].name":                    nil,
+		"metadata.labels.app\\.kubernetes\\.io/name": "web",
+	}
+	if got := projectColumns(object, columns); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestListResources_Columns(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	client := fakecluster.New(fakecluster.Config{
+		Objects: []runtime.Object{
+			&corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "shop", CreationTimestamp: metav1.NewTime(now.Add(-time.Hour))},
+				Spec:       corev1.PodSpec{NodeName: "worker-1"},
+				Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+			},
+			&corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "web-2", Namespace: "shop", CreationTimestamp: metav1.NewTime(now)},
+				Status:     corev1.PodStatus{Phase: corev1.PodPending},
+			},
+			&corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "invoicer", Namespace: "billing", CreationTimestamp: metav1.NewTime(now.Add(-time.Minute))},
+				Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+			},
+		},
+	})
+	handler := NewResourceHandler(client, nil, false)
+
+	result, isErr := callTool(t, handler.ListResources, map[string]any{
+		"resource_type": "pods",
+		"namespace":     "shop",
+		"columns":       "metadata.name,status.phase,spec.nodeName",
+	})
+	if isErr {
+		t.Fatalf("unexpected error: %v", result["error"])
+	}
+
+	want := []any{
+		map[string]any{"metadata.name": "web-2", "status.phase": "Pending", "spec.nodeName": nil},
+		map[string]any{"metadata.name": "web-1", "status.phase": "Running", "spec.nodeName": "worker-1"},
+	}
+	if !reflect.DeepEqual(result["items"], want) {
+		t.Errorf("expected the columns of each pod, newest first, %v, got %v", want, result["items"])
+	}
+
+	result, isErr = callTool(t, handler.ListResources, map[string]any{
+		"resource_type": "pods",
+		"namespace":     "shop,billing",
+		"columns":       "metadata.namespace,metadata.name",
+	})
+	if isErr {
+		t.Fatalf("unexpected error: %v", result["error"])
+	}
+
+	want = []any{
+		map[string]any{"metadata.namespace": "shop", "metadata.name": "web-2"},
+		map[string]any{"metadata.namespace": "billing", "metadata.name": "invoicer"},
+		map[string]any{"metadata.namespace": "shop", "metadata.name": "web-1"},
+	}
+	if !reflect.DeepEqual(result["items"], want) {
+		t.Errorf("expected the columns of the pods of both namespaces, newest first, %v, got %v", want, result["items"])
+	}
+
+	if result, isErr := callTool(t, handler.ListResources, map[string]any{"resource_type": "pods", "columns": "status..phase"}); !isErr {
+		t.Errorf("expected an invalid column to be rejected, got %v", result)
+	}
+}
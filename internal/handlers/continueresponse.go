@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/response"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/responselimit"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/toolschema"
+)
+
+// ContinueResponseHandler provides the MCP tool that reads the rest of a
+// result truncated by a responselimit.Limiter. It is only registered when a
+// maximum response size is set with --max-response-bytes.
+type ContinueResponseHandler struct {
+	limiter *responselimit.Limiter
+}
+
+// NewContinueResponseHandler creates a new ContinueResponseHandler backed by
+// the provided limiter.
+func NewContinueResponseHandler(limiter *responselimit.Limiter) *ContinueResponseHandler {
+	return &ContinueResponseHandler{
+		limiter: limiter,
+	}
+}
+
+// ContinueResponseParams defines the parameters for the continue_response MCP tool.
+type ContinueResponseParams struct {
+	// ContinuationToken points to the next page of a truncated result.
+	ContinuationToken string `json:"continuation_token" required:"true" description:"The continuation_token from the end of a truncated tool result"`
+}
+
+// ContinueResponse implements the continue_response MCP tool.
+// It returns the next page of a truncated result, ending with a new
+// continuation token while more remains.
+func (h *ContinueResponseHandler) ContinueResponse(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var params ContinueResponseParams
+	if err := bindParams(request, &params); err != nil {
+		return response.Errorf("failed to parse arguments: %s", err)
+	}
+
+	result, err := h.limiter.Continue(ctx, params.ContinuationToken)
+	if err != nil {
+		return response.Error(err.Error())
+	}
+
+	return result, nil
+}
+
+// GetTools returns the continue_response MCP tool.
+func (h *ContinueResponseHandler) GetTools() []MCPTool {
+	return []MCPTool{
+		NewMCPTool(
+			mcp.NewTool(responselimit.ContinueTool,
+				mcp.WithDescription("Read the next part of a tool result that was truncated because it was too large. Pass the continuation_token shown at the end of the truncated result. Prefer narrowing the original request when only part of the result is needed"),
+				toolschema.Input[ContinueResponseParams](),
+			),
+			h.ContinueResponse,
+		),
+	}
+}
@@ -0,0 +1,340 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/connectivity"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/kubernetes"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/response"
+)
+
+const (
+	componentHealthy   = "healthy"
+	componentUnhealthy = "unhealthy"
+	componentUnknown   = "unknown"
+)
+
+// leaderElectionComponents are the control plane components that hold a
+// leader election Lease in kube-system named after themselves.
+var leaderElectionComponents = []string{"kube-controller-manager", "kube-scheduler"}
+
+// CheckControlPlaneHealthParams defines the parameters for the
+// check_control_plane_health MCP tool.
+type CheckControlPlaneHealthParams struct {
+	// IncludePassing lists every health check instead of only failing ones.
+	IncludePassing bool `json:"include_passing,omitempty" description:"List every /livez and /readyz check, not only the failing ones"`
+
+	// Context specifies which Kubernetes context to use for this operation.
+	// If empty, uses the current context from kubeconfig.
+	Context string `json:"context,omitempty" description:"Kubernetes context to use (defaults to current context from kubeconfig)"`
+}
+
+// HealthEndpointReport is the result of probing /livez or /readyz.
+type HealthEndpointReport struct {
+	Endpoint string `json:"endpoint"`
+
+	// Status is "ok", "failed", or "unreachable" when the endpoint could not
+	// be read.
+	Status     string `json:"status"`
+	HTTPStatus int    `json:"http_status,omitempty"`
+
+	// Verbose reports whether the per-check breakdown was available.
+	Verbose bool          `json:"verbose"`
+	Total   int           `json:"total_checks"`
+	Failed  int           `json:"failed_checks"`
+	Checks  []HealthCheck `json:"checks,omitempty"`
+	Error   string        `json:"error,omitempty"`
+}
+
+// HealthCheck is a single named check of a health endpoint, such as etcd or
+// poststarthook/rbac/bootstrap-roles.
+type HealthCheck struct {
+	Name string `json:"name"`
+
+	// Status is "ok", "failed", or "excluded".
+	Status string `json:"status"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// ControlPlaneComponent is the health of a control plane component.
+type ControlPlaneComponent struct {
+	Name string `json:"name"`
+
+	// Status is "healthy", "unhealthy", or "unknown".
+	Status  string `json:"status"`
+	Source  string `json:"source"`
+	Message string `json:"message,omitempty"`
+}
+
+// CheckControlPlaneHealthResult is the result of the
+// check_control_plane_health MCP tool.
+type CheckControlPlaneHealthResult struct {
+	Healthy    bool                    `json:"healthy"`
+	Components []ControlPlaneComponent `json:"components"`
+	Endpoints  []HealthEndpointReport  `json:"endpoints"`
+	Findings   []string                `json:"findings"`
+	Note       string                  `json:"note"`
+}
+
+// CheckControlPlaneHealth implements the check_control_plane_health MCP tool.
+// It probes the API server's /livez and /readyz endpoints, with the verbose
+// per-check breakdown where permitted, and reads the leader election Leases
+// of kube-scheduler and kube-controller-manager, whose leader renews them
+// every few seconds while it runs. Together they replace componentstatuses,
+// which is deprecated and reports wrong results on many clusters. Every
+// source is best effort, so a component that cannot be checked is reported
+// as unknown rather than failing the tool.
+func (h *ClusterHandler) CheckControlPlaneHealth(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var params CheckControlPlaneHealthParams
+	if err := bindParams(request, &params); err != nil {
+		return response.Errorf("failed to parse arguments: %s", err)
+	}
+
+	// Use the appropriate client based on context
+	client, err := h.client.ForContext(ctx, params.Context)
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to create client with context %s: %v", params.Context, err)
+	}
+
+	now := h.now()
+	findings := make([]string, 0)
+	components := make([]ControlPlaneComponent, 0, 2+len(leaderElectionComponents))
+
+	endpoints := make([]HealthEndpointReport, 0, 2)
+	var etcd []HealthCheck
+	for _, endpoint := range []string{"/livez", "/readyz"} {
+		report, err := probeHealthEndpoint(ctx, client, endpoint)
+		if err != nil {
+			if h.alwaysStart && connectivity.IsTransportError(err) {
+				return response.Error(connectivity.ErrorMessage(err))
+			}
+			report.Error = err.Error()
+		}
+
+		for _, check := range report.Checks {
+			if check.Status == "failed" {
+				findings = append(findings, fmt.Sprintf("%s check %s failed: %s", endpoint, check.Name, check.Reason))
+			}
+			if endpoint == "/readyz" && strings.HasPrefix(check.Name, "etcd") {
+				etcd = append(etcd, check)
+			}
+		}
+		if report.Status == "failed" && !report.Verbose {
+			findings = append(findings, fmt.Sprintf("%s reports the API server is not healthy, but the per-check breakdown is not available to explain why", endpoint))
+		}
+
+		if !params.IncludePassing {
+			failing := make([]HealthCheck, 0, report.Failed)
+			for _, check := range report.Checks {
+				if check.Status == "failed" {
+					failing = append(failing, check)
+				}
+			}
+			report.Checks = failing
+		}
+		endpoints = append(endpoints, report)
+	}
+
+	components = append(components, apiServerComponent(endpoints), etcdComponent(etcd, endpoints[1]))
+
+	for _, name := range leaderElectionComponents {
+		component := ControlPlaneComponent{Name: name, Source: "Lease kube-system/" + name}
+
+		lease, err := client.GetLease(ctx, "kube-system", name)
+		switch {
+		case apierrors.IsNotFound(err):
+			component.Status = componentUnknown
+			component.Message = "no leader election Lease was found; leader election may be disabled, or the platform manages this component out of sight"
+		case err != nil:
+			if h.alwaysStart && connectivity.IsTransportError(err) {
+				return response.Error(connectivity.ErrorMessage(err))
+			}
+			component.Status = componentUnknown
+			component.Message = fmt.Sprintf("failed to read the leader election Lease: %v", err)
+		default:
+			component.Status, component.Message = leaseHealth(lease, now)
+		}
+
+		if component.Status == componentUnhealthy {
+			findings = append(findings, fmt.Sprintf("%s: %s", name, component.Message))
+		}
+		components = append(components, component)
+	}
+
+	healthy := true
+	for _, component := range components {
+		if component.Status == componentUnhealthy {
+			healthy = false
+		}
+	}
+
+	result := CheckControlPlaneHealthResult{
+		Healthy:    healthy,
+		Components: components,
+		Endpoints:  endpoints,
+		Findings:   findings,
+		Note:       "componentstatuses (kubectl get componentstatuses) is deprecated and unreliable on many clusters, so health comes from the API server's /livez and /readyz endpoints and the leader election Leases instead. Components reported as unknown could not be checked, which is common on managed control planes.",
+	}
+
+	return response.JSON(result)
+}
+
+// probeHealthEndpoint probes a health endpoint with the verbose breakdown,
+// falling back to the plain status when verbose output is not permitted.
+// The report is returned even on error, marked unreachable.
+func probeHealthEndpoint(ctx context.Context, client kubernetes.ClusterReader, endpoint string) (HealthEndpointReport, error) {
+	report := HealthEndpointReport{Endpoint: endpoint, Status: "unreachable", Verbose: true}
+
+	probe, err := client.ProbeHealth(ctx, endpoint, true)
+	if apierrors.IsForbidden(err) {
+		report.Verbose = false
+		probe, err = client.ProbeHealth(ctx, endpoint, false)
+	}
+	if err != nil {
+		report.Verbose = false
+		return report, err
+	}
+
+	report.HTTPStatus = probe.StatusCode
+	report.Status = "failed"
+	if probe.StatusCode == http.StatusOK {
+		report.Status = "ok"
+	}
+
+	report.Checks = parseHealthChecks(probe.Body)
+	if len(report.Checks) == 0 {
+		report.Verbose = false
+	}
+	report.Total = len(report.Checks)
+	for _, check := range report.Checks {
+		if check.Status == "failed" {
+			report.Failed++
+		}
+	}
+
+	return report, nil
+}
+
+// parseHealthChecks reads the checks of a verbose health report, made of
+// lines such as "[+]ping ok", "[+]informer-sync excluded: ok", and
+// "[-]etcd failed: reason withheld". Other lines are ignored.
+func parseHealthChecks(body string) []HealthCheck {
+	var checks []HealthCheck
+	for _, line := range strings.Split(body, "\n") {
+		line = strings.TrimSpace(line)
+
+		var passed bool
+		switch {
+		case strings.HasPrefix(line, "[+]"):
+			passed = true
+		case strings.HasPrefix(line, "[-]"):
+		default:
+			continue
+		}
+
+		name, rest, _ := strings.Cut(line[len("[+]"):], " ")
+		check := HealthCheck{Name: name, Status: "ok"}
+		switch {
+		case !passed:
+			check.Status = "failed"
+			check.Reason = strings.TrimSpace(strings.TrimPrefix(rest, "failed:"))
+		case strings.HasPrefix(rest, "excluded"):
+			check.Status = "excluded"
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+// apiServerComponent reports the API server as healthy when both endpoints
+// pass, and unhealthy when either fails.
+func apiServerComponent(endpoints []HealthEndpointReport) ControlPlaneComponent {
+	component := ControlPlaneComponent{Name: "kube-apiserver", Source: "/livez and /readyz", Status: componentHealthy}
+
+	var failed, unreachable []string
+	for _, report := range endpoints {
+		switch report.Status {
+		case "failed":
+			failed = append(failed, report.Endpoint)
+		case "unreachable":
+			unreachable = append(unreachable, report.Endpoint)
+		}
+	}
+
+	switch {
+	case len(failed) > 0:
+		component.Status = componentUnhealthy
+		component.Message = strings.Join(failed, " and ") + " reported failing checks"
+	case len(unreachable) == len(endpoints):
+		component.Status = componentUnknown
+		component.Message = "the health endpoints could not be read"
+	case len(unreachable) > 0:
+		component.Message = strings.Join(unreachable, " and ") + " could not be read"
+	}
+
+	return component
+}
+
+// etcdComponent reports etcd from the etcd checks of /readyz, as seen by the
+// API server.
+func etcdComponent(checks []HealthCheck, readyz HealthEndpointReport) ControlPlaneComponent {
+	component := ControlPlaneComponent{Name: "etcd", Source: "/readyz etcd checks"}
+
+	if len(checks) == 0 {
+		component.Status = componentUnknown
+		component.Message = "/readyz did not report etcd checks"
+		if !readyz.Verbose {
+			component.Message = "the /readyz per-check breakdown is not available"
+		}
+		return component
+	}
+
+	component.Status = componentHealthy
+	for _, check := range checks {
+		if check.Status == "failed" {
+			component.Status = componentUnhealthy
+			component.Message = fmt.Sprintf("check %s failed: %s", check.Name, check.Reason)
+			break
+		}
+	}
+	return component
+}
+
+// leaseHealth reports a leader election Lease as healthy while its holder
+// keeps renewing it within the lease duration.
+func leaseHealth(lease *coordinationv1.Lease, now time.Time) (string, string) {
+	holder := ""
+	if lease.Spec.HolderIdentity != nil {
+		holder = *lease.Spec.HolderIdentity
+	}
+	if holder == "" {
+		return componentUnhealthy, "the leader election Lease has no holder, so no instance is acting as leader"
+	}
+
+	if lease.Spec.RenewTime == nil {
+		return componentUnknown, fmt.Sprintf("held by %s, but the Lease has no renew time", holder)
+	}
+
+	duration := 15 * time.Second
+	if lease.Spec.LeaseDurationSeconds != nil {
+		duration = time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second
+	}
+
+	age := now.Sub(lease.Spec.RenewTime.Time)
+	if age > duration {
+		return componentUnhealthy, fmt.Sprintf("held by %s, but last renewed %s ago, longer than its %s lease duration; no instance appears to be running as leader", holder, age.Round(time.Second), duration)
+	}
+
+	return componentHealthy, fmt.Sprintf("held by %s, renewed %s ago", holder, age.Round(time.Second))
+}
@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/fakecluster"
+)
+
+func TestParseHealthChecks(t *testing.T) {
+	t.Parallel()
+
+	body := "[+]ping ok\n" +
+		"[+]log ok\n" +
+		"[-]etcd failed: reason withheld\n" +
+		"[+]informer-sync excluded: ok\n" +
+		"[+]poststarthook/rbac/bootstrap-roles ok\n" +
+		"readyz check failed\n"
+
+	want := []HealthCheck{
+		{Name: "ping", Status: "ok"},
+		{Name: "log", Status: "ok"},
+		{Name: "etcd", Status: "failed", Reason: "reason withheld"},
+		{Name: "informer-sync", Status: "excluded"},
+		{Name: "poststarthook/rbac/bootstrap-roles", Status: "ok"},
+	}
+	if got := parseHealthChecks(body); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+
+	if got := parseHealthChecks("ok"); got != nil {
+		t.Errorf("expected no checks for a plain report, got %+v", got)
+	}
+}
+
+func TestLeaseHealth(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	lease := func(holder string, renewedAgo time.Duration) *coordinationv1.Lease {
+		duration := int32(15)
+		renew := metav1.NewMicroTime(now.Add(-renewedAgo))
+		return &coordinationv1.Lease{Spec: coordinationv1.LeaseSpec{HolderIdentity: &holder, LeaseDurationSeconds: &duration, RenewTime: &renew}}
+	}
+
+	tests := []struct {
+		name        string
+		lease       *coordinationv1.Lease
+		wantStatus  string
+		wantMessage string
+	}{
+		{
+			name:        "renewed",
+			lease:       lease("cp-1_abc", 2*time.Second),
+			wantStatus:  componentHealthy,
+			wantMessage: "held by cp-1_abc, renewed 2s ago",
+		},
+		{
+			name:        "stale",
+			lease:       lease("cp-1_abc", 10*time.Minute),
+			wantStatus:  componentUnhealthy,
+			wantMessage: "held by cp-1_abc, but last renewed 10m0s ago, longer than its 15s lease duration; no instance appears to be running as leader",
+		},
+		{
+			name:        "released",
+			lease:       lease("", time.Second),
+			wantStatus:  componentUnhealthy,
+			wantMessage: "the leader election Lease has no holder, so no instance is acting as leader",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			status, message := leaseHealth(tt.lease, now)
+			if status != tt.wantStatus || message != tt.wantMessage {
+				t.Errorf("expected %s %q, got %s %q", tt.wantStatus, tt.wantMessage, status, message)
+			}
+		})
+	}
+}
+
+func TestCheckControlPlaneHealth_FakeCluster(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	lease := func(name string, renewedAgo time.Duration) *coordinationv1.Lease {
+		holder := "cp-1_" + name
+		duration := int32(15)
+		renew := metav1.NewMicroTime(now.Add(-renewedAgo))
+		return &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "kube-system"},
+			Spec:       coordinationv1.LeaseSpec{HolderIdentity: &holder, LeaseDurationSeconds: &duration, RenewTime: &renew},
+		}
+	}
+
+	handler := NewClusterHandler(fakecluster.New(fakecluster.Config{
+		Objects: []runtime.Object{lease("kube-scheduler", 3*time.Second), lease("kube-controller-manager", time.Hour)},
+	}), false)
+	handler.now = func() time.Time { return now }
+
+	result, isErr := callTool(t, handler.CheckControlPlaneHealth, map[string]any{})
+	if isErr {
+		t.Fatalf("unexpected error: %v", result["error"])
+	}
+
+	var components []ControlPlaneComponent
+	decodeInto(t, result["components"], &components)
+
+	got := make(map[string]string, len(components))
+	for _, component := range components {
+		got[component.Name] = component.Status
+	}
+	// The fake cluster has no REST config, so the health endpoints cannot be
+	// probed and the API server and etcd are unknown.
+	want := map[string]string{
+		"kube-apiserver":          componentUnknown,
+		"etcd":                    componentUnknown,
+		"kube-scheduler":          componentHealthy,
+		"kube-controller-manager": componentUnhealthy,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected components %v, got %v", want, got)
+	}
+
+	var findings []string
+	decodeInto(t, result["findings"], &findings)
+	if len(findings) != 1 || result["healthy"] != false {
+		t.Errorf("expected one finding and an unhealthy control plane, got %q in %+v", findings, result)
+	}
+
+	var endpoints []HealthEndpointReport
+	decodeInto(t, result["endpoints"], &endpoints)
+	if len(endpoints) != 2 || endpoints[0].Status != "unreachable" || endpoints[0].Error == "" {
+		t.Errorf("unexpected endpoints %+v", endpoints)
+	}
+}
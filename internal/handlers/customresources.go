@@ -0,0 +1,330 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/connectivity"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/kubernetes"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/response"
+)
+
+const (
+	// customResourcePageSize is how many objects are requested per page when
+	// counting custom resources, so large collections are never fetched in a
+	// single response.
+	customResourcePageSize = 500
+
+	// customResourceDefaultThreshold is the default instance count at or
+	// above which a CRD is flagged as possibly runaway.
+	customResourceDefaultThreshold = 1000
+)
+
+// crdGVR is the resource CustomResourceDefinitions are served under.
+var crdGVR = schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}
+
+// CountCustomResourcesParams defines the parameters for the count_custom_resources MCP tool.
+type CountCustomResourcesParams struct {
+	// CRD limits the report to a single CustomResourceDefinition.
+	CRD string `json:"crd,omitempty" description:"CustomResourceDefinition to count, by CRD name (e.g., 'certificates.cert-manager.io'), plural, or kind (leave empty for every CRD)"`
+
+	// Namespace limits the counts of namespaced custom resources.
+	Namespace string `json:"namespace,omitempty" description:"Namespace to count namespaced custom resources in (leave empty for all namespaces). Cluster-scoped custom resources are always counted cluster-wide"`
+
+	// Threshold is the instance count at or above which a CRD is flagged.
+	Threshold int `json:"threshold,omitempty" default:"1000" minimum:"1" description:"Instance count at or above which a CRD is flagged as possibly accumulating objects"`
+
+	// Context specifies which Kubernetes context to use for this operation.
+	Context string `json:"context,omitempty" description:"Kubernetes context to use (defaults to current context from kubeconfig)"`
+}
+
+// CustomResourceCount is the number of instances of a CRD's custom resource.
+type CustomResourceCount struct {
+	// Name is the CRD's name, such as certificates.cert-manager.io.
+	Name  string `json:"name"`
+	Kind  string `json:"kind"`
+	Group string `json:"group"`
+
+	// Version is the served version instances were listed through,
+	// preferring the storage version.
+	Version string `json:"version,omitempty"`
+	Scope   string `json:"scope"`
+	Total   int    `json:"total"`
+
+	// Namespaces holds the per-namespace counts of a namespaced CRD, largest
+	// first.
+	Namespaces []NamespaceCount `json:"namespaces,omitempty"`
+
+	// Error explains why the instances could not be counted.
+	Error    string   `json:"error,omitempty"`
+	Findings []string `json:"findings,omitempty"`
+}
+
+// NamespaceCount is the number of instances of a custom resource in a
+// namespace.
+type NamespaceCount struct {
+	Namespace string `json:"namespace"`
+	Count     int    `json:"count"`
+}
+
+// customResourceDefinition is the part of a CRD the count needs.
+type customResourceDefinition struct {
+	name       string
+	kind       string
+	plural     string
+	group      string
+	version    string
+	namespaced bool
+}
+
+// CountCustomResourcesResult is the result of the count_custom_resources MCP
+// tool.
+type CountCustomResourcesResult struct {
+	CRDs  []CustomResourceCount `json:"crds"`
+	Count int                   `json:"count"`
+
+	// TotalInstances counts the custom resources of every CRD, and
+	// UnusedCRDs the CRDs without any.
+	TotalInstances int `json:"total_instances"`
+	UnusedCRDs     int `json:"unused_crds"`
+	Threshold      int `json:"threshold"`
+
+	Namespace string   `json:"namespace,omitempty"`
+	Warnings  []string `json:"warnings,omitempty"`
+}
+
+// CountCustomResources implements the count_custom_resources MCP tool.
+// For every installed CRD, or a single one, it counts the custom resources
+// per namespace through metadata-only, paginated lists, so even very large
+// collections are cheap to count. CRDs without instances point at unused
+// operators, and CRDs at or above the threshold at controllers that create
+// objects without cleaning them up.
+func (h *ResourceHandler) CountCustomResources(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var params CountCustomResourcesParams
+	if err := bindParams(request, &params); err != nil {
+		return response.Errorf("failed to parse arguments: %s", err)
+	}
+
+	threshold := params.Threshold
+	if threshold <= 0 {
+		threshold = customResourceDefaultThreshold
+	}
+
+	// Use the appropriate client based on context
+	client, err := h.client.ForContext(ctx, params.Context)
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to create client with context %s: %v", params.Context, err)
+	}
+
+	if result, err := h.disabledResult("customresourcedefinitions", crdGVR); result != nil || err != nil {
+		return result, err
+	}
+
+	list, err := client.ListResources(ctx, crdGVR, "", metav1.ListOptions{})
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to list CustomResourceDefinitions: %v", err)
+	}
+
+	var definitions []customResourceDefinition
+	for i := range list.Items {
+		crd := parseCustomResourceDefinition(&list.Items[i])
+		if params.CRD == "" || crd.matches(params.CRD) {
+			definitions = append(definitions, crd)
+		}
+	}
+
+	if params.CRD != "" && len(definitions) == 0 {
+		return response.Errorf("no CustomResourceDefinition named %q is installed; call with an empty crd to list every CRD", params.CRD)
+	}
+
+	counts := make([]CustomResourceCount, 0, len(definitions))
+	var warnings []string
+	instances, unused := 0, 0
+
+	for _, crd := range definitions {
+		if ctx.Err() != nil {
+			break
+		}
+		count := CustomResourceCount{
+			Name:    crd.name,
+			Kind:    crd.kind,
+			Group:   crd.group,
+			Version: crd.version,
+			Scope:   "Cluster",
+		}
+		if crd.namespaced {
+			count.Scope = "Namespaced"
+		}
+
+		gvr := schema.GroupVersionResource{Group: crd.group, Version: crd.version, Resource: crd.plural}
+
+		switch {
+		case crd.version == "":
+			count.Error = "the CRD serves no version, so its instances cannot be listed"
+			count.Findings = append(count.Findings, "serves no version, so its custom resources are unreachable through the API")
+		case h.resourceFilter != nil && h.resourceFilter.IsDisabled(gvr):
+			count.Error = "access to this resource is disabled by configuration"
+		default:
+			namespace := ""
+			if crd.namespaced {
+				namespace = params.Namespace
+			}
+
+			perNamespace, total, err := countResourceMetadata(ctx, client, gvr, namespace)
+			if err != nil {
+				if h.alwaysStart && connectivity.IsTransportError(err) {
+					return response.Error(connectivity.ErrorMessage(err))
+				}
+				count.Error = err.Error()
+				warnings = append(warnings, fmt.Sprintf("failed to count %s: %v", crd.name, err))
+				break
+			}
+
+			count.Total = total
+			if crd.namespaced {
+				count.Namespaces = perNamespace
+			}
+			count.Findings = customResourceFindings(crd, total, threshold, params.Namespace)
+		}
+
+		if count.Error == "" {
+			instances += count.Total
+			if count.Total == 0 {
+				unused++
+			}
+		}
+		counts = append(counts, count)
+	}
+
+	// Largest collections first, since runaway objects are what this tool
+	// is most often asked to find.
+	sort.SliceStable(counts, func(i, j int) bool {
+		if counts[i].Total != counts[j].Total {
+			return counts[i].Total > counts[j].Total
+		}
+		return counts[i].Name < counts[j].Name
+	})
+
+	return response.JSON(CountCustomResourcesResult{
+		CRDs:           counts,
+		Count:          len(counts),
+		TotalInstances: instances,
+		UnusedCRDs:     unused,
+		Threshold:      threshold,
+		Namespace:      params.Namespace,
+		Warnings:       warnings,
+	})
+}
+
+// parseCustomResourceDefinition reads the names, scope, and version to list
+// through from a CRD. The storage version is preferred when it is served;
+// otherwise the first served version is used.
+func parseCustomResourceDefinition(obj *unstructured.Unstructured) customResourceDefinition {
+	crd := customResourceDefinition{name: obj.GetName()}
+	crd.group, _, _ = unstructured.NestedString(obj.Object, "spec", "group")
+	crd.kind, _, _ = unstructured.NestedString(obj.Object, "spec", "names", "kind")
+	crd.plural, _, _ = unstructured.NestedString(obj.Object, "spec", "names", "plural")
+
+	scope, _, _ := unstructured.NestedString(obj.Object, "spec", "scope")
+	crd.namespaced = scope != "Cluster"
+
+	versions, _, _ := unstructured.NestedSlice(obj.Object, "spec", "versions")
+	for _, raw := range versions {
+		version, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		name, _, _ := unstructured.NestedString(version, "name")
+		served, _, _ := unstructured.NestedBool(version, "served")
+		storage, _, _ := unstructured.NestedBool(version, "storage")
+		if !served || name == "" {
+			continue
+		}
+		if crd.version == "" || storage {
+			crd.version = name
+		}
+		if storage {
+			break
+		}
+	}
+
+	return crd
+}
+
+// matches reports whether name refers to the CRD by its full name, plural,
+// or kind.
+func (crd customResourceDefinition) matches(name string) bool {
+	return strings.EqualFold(name, crd.name) || strings.EqualFold(name, crd.plural) || strings.EqualFold(name, crd.kind)
+}
+
+// countResourceMetadata counts the objects of a resource per namespace,
+// paging through metadata-only lists. Namespaces are sorted by count,
+// largest first.
+func countResourceMetadata(ctx context.Context, client kubernetes.ClusterReader, gvr schema.GroupVersionResource, namespace string) ([]NamespaceCount, int, error) {
+	byNamespace := make(map[string]int)
+	total := 0
+
+	opts := metav1.ListOptions{Limit: customResourcePageSize}
+	for {
+		page, err := client.ListResourceMetadata(ctx, gvr, namespace, opts)
+		if err != nil {
+			return nil, 0, err //nolint:wrapcheck // kubernetes API errors are self-descriptive
+		}
+
+		for i := range page.Items {
+			byNamespace[page.Items[i].Namespace]++
+			total++
+		}
+
+		if page.Continue == "" {
+			break
+		}
+		opts.Continue = page.Continue
+	}
+
+	counts := make([]NamespaceCount, 0, len(byNamespace))
+	for ns, count := range byNamespace {
+		if ns != "" {
+			counts = append(counts, NamespaceCount{Namespace: ns, Count: count})
+		}
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		return counts[i].Namespace < counts[j].Namespace
+	})
+
+	return counts, total, nil
+}
+
+// customResourceFindings flags CRDs without instances and CRDs whose
+// instance count reached the threshold.
+func customResourceFindings(crd customResourceDefinition, total, threshold int, namespace string) []string {
+	var findings []string
+
+	switch {
+	case total == 0 && crd.namespaced && namespace != "":
+		findings = append(findings, fmt.Sprintf("has no %s in namespace %s", crd.plural, namespace))
+	case total == 0:
+		findings = append(findings, fmt.Sprintf("has no %s, so the operator or chart that installed it may be unused", crd.plural))
+	case total >= threshold:
+		findings = append(findings, fmt.Sprintf("has %d %s, at or above the threshold of %d; check for a controller creating them without cleaning them up", total, crd.plural, threshold))
+	}
+
+	return findings
+}
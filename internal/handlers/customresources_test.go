@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/fakecluster"
+)
+
+func crdObject(group, kind, plural, scope string, versions ...map[string]interface{}) *unstructured.Unstructured {
+	rawVersions := make([]interface{}, 0, len(versions))
+	for _, version := range versions {
+		rawVersions = append(rawVersions, version)
+	}
+
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apiextensions.k8s.io/v1",
+		"kind":       "CustomResourceDefinition",
+		"metadata":   map[string]interface{}{"name": plural + "." + group},
+		"spec": map[string]interface{}{
+			"group":    group,
+			"scope":    scope,
+			"names":    map[string]interface{}{"kind": kind, "plural": plural},
+			"versions": rawVersions,
+		},
+	}}
+}
+
+func crdVersion(name string, served, storage bool) map[string]interface{} {
+	return map[string]interface{}{"name": name, "served": served, "storage": storage}
+}
+
+func TestParseCustomResourceDefinition(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		crd         *unstructured.Unstructured
+		wantVersion string
+		namespaced  bool
+	}{
+		{
+			name:        "storage version preferred",
+			crd:         crdObject("example.com", "Widget", "widgets", "Namespaced", crdVersion("v1alpha1", true, false), crdVersion("v1", true, true)),
+			wantVersion: "v1",
+			namespaced:  true,
+		},
+		{
+			name:        "storage version not served",
+			crd:         crdObject("example.com", "Widget", "widgets", "Cluster", crdVersion("v1beta1", true, false), crdVersion("v1", false, true)),
+			wantVersion: "v1beta1",
+		},
+		{
+			name: "nothing served",
+			crd:  crdObject("example.com", "Widget", "widgets", "Cluster", crdVersion("v1", false, true)),
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			crd := parseCustomResourceDefinition(tt.crd)
+			if crd.version != tt.wantVersion || crd.namespaced != tt.namespaced {
+				t.Errorf("expected version %q and namespaced %v, got %+v", tt.wantVersion, tt.namespaced, crd)
+			}
+			if !crd.matches("Widgets") || !crd.matches("widget") || !crd.matches("widgets.example.com") || crd.matches("gadgets") {
+				t.Errorf("unexpected name matching for %+v", crd)
+			}
+		})
+	}
+}
+
+func TestCountCustomResources_FakeCluster(t *testing.T) {
+	t.Parallel()
+
+	widget := func(namespace, name string) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "example.com/v1",
+			"kind":       "Widget",
+			"metadata":   map[string]interface{}{"name": name, "namespace": namespace},
+		}}
+	}
+
+	apiResources := append(fakecluster.DefaultAPIResources(),
+		&metav1.APIResourceList{
+			GroupVersion: "apiextensions.k8s.io/v1",
+			APIResources: []metav1.APIResource{{Name: "customresourcedefinitions", Kind: "CustomResourceDefinition", Verbs: metav1.Verbs{"get", "list"}}},
+		},
+		&metav1.APIResourceList{
+			GroupVersion: "example.com/v1",
+			APIResources: []metav1.APIResource{
+				{Name: "widgets", Kind: "Widget", Namespaced: true, Verbs: metav1.Verbs{"get", "list"}},
+				{Name: "gadgets", Kind: "Gadget", Verbs: metav1.Verbs{"get", "list"}},
+			},
+		},
+	)
+
+	handler := NewResourceHandler(fakecluster.New(fakecluster.Config{
+		Custom: []*unstructured.Unstructured{
+			crdObject("example.com", "Widget", "widgets", "Namespaced", crdVersion("v1alpha1", true, false), crdVersion("v1", true, true)),
+			crdObject("example.com", "Gadget", "gadgets", "Cluster", crdVersion("v1", true, true)),
+			widget("shop", "a"), widget("shop", "b"), widget("batch", "c"),
+		},
+		APIResources: apiResources,
+	}), nil, false)
+
+	result, isErr := callTool(t, handler.CountCustomResources, map[string]any{"threshold": 3})
+	if isErr {
+		t.Fatalf("unexpected error: %v", result["error"])
+	}
+
+	var counts []CustomResourceCount
+	decodeInto(t, result["crds"], &counts)
+
+	want := []CustomResourceCount{
+		{
+			Name: "widgets.example.com", Kind: "Widget", Group: "example.com", Version: "v1", Scope: "Namespaced", Total: 3,
+			Namespaces: []NamespaceCount{{Namespace: "shop", Count: 2}, {Namespace: "batch", Count: 1}},
+			Findings:   []string{"has 3 widgets, at or above the threshold of 3; check for a controller creating them without cleaning them up"},
+		},
+		{
+			Name: "gadgets.example.com", Kind: "Gadget", Group: "example.com", Version: "v1", Scope: "Cluster",
+			Findings: []string{"has no gadgets, so the operator or chart that installed it may be unused"},
+		},
+	}
+	if !reflect.DeepEqual(counts, want) {
+		t.Errorf("expected counts\n%+v\ngot\n%+v", want, counts)
+	}
+	if result["total_instances"] != float64(3) || result["unused_crds"] != float64(1) {
+		t.Errorf("unexpected totals in %+v", result)
+	}
+
+	result, isErr = callTool(t, handler.CountCustomResources, map[string]any{"crd": "Widget", "namespace": "batch"})
+	if isErr {
+		t.Fatalf("unexpected error: %v", result["error"])
+	}
+
+	counts = nil
+	decodeInto(t, result["crds"], &counts)
+	if len(counts) != 1 || counts[0].Total != 1 || len(counts[0].Findings) != 0 {
+		t.Errorf("expected only the widgets in batch, got %+v", counts)
+	}
+
+	if _, isErr := callTool(t, handler.CountCustomResources, map[string]any{"crd": "sprockets"}); !isErr {
+		t.Error("expected an error for a CRD that is not installed")
+	}
+}
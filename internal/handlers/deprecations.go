@@ -0,0 +1,444 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/version"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/connectivity"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/resourcefilter"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/response"
+)
+
+const (
+	// deprecationStatusDeprecated marks an API version that still works in the target release.
+	deprecationStatusDeprecated = "deprecated"
+
+	// deprecationStatusRemovedInTarget marks an API version removed by the target release.
+	deprecationStatusRemovedInTarget = "removed_in_target"
+
+	// deprecationStatusRemoved marks an API version the cluster no longer serves.
+	deprecationStatusRemoved = "removed"
+)
+
+// deprecatedAPI describes an API version of a kind that Kubernetes deprecated
+// and, in most cases, removed in a later release.
+type deprecatedAPI struct {
+	APIVersion   string
+	Kind         string
+	Resource     string
+	DeprecatedIn string
+	RemovedIn    string
+	Replacement  string
+}
+
+// deprecatedAPIs is the table of deprecated API versions, following the
+// Kubernetes deprecated API migration guide. Replacement is the API version
+// to migrate to, or empty when the API was removed without a replacement.
+var deprecatedAPIs = []deprecatedAPI{
+	// Removed in 1.16
+	{"extensions/v1beta1", "Deployment", "deployments", "1.9", "1.16", "apps/v1"},
+	{"extensions/v1beta1", "DaemonSet", "daemonsets", "1.9", "1.16", "apps/v1"},
+	{"extensions/v1beta1", "ReplicaSet", "replicasets", "1.9", "1.16", "apps/v1"},
+	{"extensions/v1beta1", "NetworkPolicy", "networkpolicies", "1.9", "1.16", "networking.k8s.io/v1"},
+	{"extensions/v1beta1", "PodSecurityPolicy", "podsecuritypolicies", "1.10", "1.16", "policy/v1beta1"},
+	{"apps/v1beta1", "Deployment", "deployments", "1.9", "1.16", "apps/v1"},
+	{"apps/v1beta1", "StatefulSet", "statefulsets", "1.9", "1.16", "apps/v1"},
+	{"apps/v1beta2", "Deployment", "deployments", "1.9", "1.16", "apps/v1"},
+	{"apps/v1beta2", "StatefulSet", "statefulsets", "1.9", "1.16", "apps/v1"},
+	{"apps/v1beta2", "DaemonSet", "daemonsets", "1.9", "1.16", "apps/v1"},
+	{"apps/v1beta2", "ReplicaSet", "replicasets", "1.9", "1.16", "apps/v1"},
+
+	// Removed in 1.22
+	{"extensions/v1beta1", "Ingress", "ingresses", "1.14", "1.22", "networking.k8s.io/v1"},
+	{"networking.k8s.io/v1beta1", "Ingress", "ingresses", "1.19", "1.22", "networking.k8s.io/v1"},
+	{"networking.k8s.io/v1beta1", "IngressClass", "ingressclasses", "1.19", "1.22", "networking.k8s.io/v1"},
+	{"admissionregistration.k8s.io/v1beta1", "MutatingWebhookConfiguration", "mutatingwebhookconfigurations", "1.16", "1.22", "admissionregistration.k8s.io/v1"},
+	{"admissionregistration.k8s.io/v1beta1", "ValidatingWebhookConfiguration", "validatingwebhookconfigurations", "1.16", "1.22", "admissionregistration.k8s.io/v1"},
+	{"apiextensions.k8s.io/v1beta1", "CustomResourceDefinition", "customresourcedefinitions", "1.16", "1.22", "apiextensions.k8s.io/v1"},
+	{"apiregistration.k8s.io/v1beta1", "APIService", "apiservices", "1.19", "1.22", "apiregistration.k8s.io/v1"},
+	{"certificates.k8s.io/v1beta1", "CertificateSigningRequest", "certificatesigningrequests", "1.19", "1.22", "certificates.k8s.io/v1"},
+	{"coordination.k8s.io/v1beta1", "Lease", "leases", "1.19", "1.22", "coordination.k8s.io/v1"},
+	{"rbac.authorization.k8s.io/v1beta1", "ClusterRole", "clusterroles", "1.17", "1.22", "rbac.authorization.k8s.io/v1"},
+	{"rbac.authorization.k8s.io/v1beta1", "ClusterRoleBinding", "clusterrolebindings", "1.17", "1.22", "rbac.authorization.k8s.io/v1"},
+	{"rbac.authorization.k8s.io/v1beta1", "Role", "roles", "1.17", "1.22", "rbac.authorization.k8s.io/v1"},
+	{"rbac.authorization.k8s.io/v1beta1", "RoleBinding", "rolebindings", "1.17", "1.22", "rbac.authorization.k8s.io/v1"},
+	{"scheduling.k8s.io/v1beta1", "PriorityClass", "priorityclasses", "1.14", "1.22", "scheduling.k8s.io/v1"},
+	{"storage.k8s.io/v1beta1", "CSIDriver", "csidrivers", "1.19", "1.22", "storage.k8s.io/v1"},
+	{"storage.k8s.io/v1beta1", "CSINode", "csinodes", "1.17", "1.22", "storage.k8s.io/v1"},
+	{"storage.k8s.io/v1beta1", "StorageClass", "storageclasses", "1.19", "1.22", "storage.k8s.io/v1"},
+	{"storage.k8s.io/v1beta1", "VolumeAttachment", "volumeattachments", "1.19", "1.22", "storage.k8s.io/v1"},
+
+	// Removed in 1.25
+	{"batch/v1beta1", "CronJob", "cronjobs", "1.21", "1.25", "batch/v1"},
+	{"discovery.k8s.io/v1beta1", "EndpointSlice", "endpointslices", "1.21", "1.25", "discovery.k8s.io/v1"},
+	{"events.k8s.io/v1beta1", "Event", "events", "1.19", "1.25", "events.k8s.io/v1"},
+	{"autoscaling/v2beta1", "HorizontalPodAutoscaler", "horizontalpodautoscalers", "1.22", "1.25", "autoscaling/v2"},
+	{"policy/v1beta1", "PodDisruptionBudget", "poddisruptionbudgets", "1.21", "1.25", "policy/v1"},
+	{"policy/v1beta1", "PodSecurityPolicy", "podsecuritypolicies", "1.21", "1.25", ""},
+	{"node.k8s.io/v1beta1", "RuntimeClass", "runtimeclasses", "1.20", "1.25", "node.k8s.io/v1"},
+
+	// Removed in 1.26
+	{"flowcontrol.apiserver.k8s.io/v1beta1", "FlowSchema", "flowschemas", "1.23", "1.26", "flowcontrol.apiserver.k8s.io/v1"},
+	{"flowcontrol.apiserver.k8s.io/v1beta1", "PriorityLevelConfiguration", "prioritylevelconfigurations", "1.23", "1.26", "flowcontrol.apiserver.k8s.io/v1"},
+	{"autoscaling/v2beta2", "HorizontalPodAutoscaler", "horizontalpodautoscalers", "1.23", "1.26", "autoscaling/v2"},
+
+	// Removed in 1.27
+	{"storage.k8s.io/v1beta1", "CSIStorageCapacity", "csistoragecapacities", "1.24", "1.27", "storage.k8s.io/v1"},
+
+	// Removed in 1.29
+	{"flowcontrol.apiserver.k8s.io/v1beta2", "FlowSchema", "flowschemas", "1.26", "1.29", "flowcontrol.apiserver.k8s.io/v1"},
+	{"flowcontrol.apiserver.k8s.io/v1beta2", "PriorityLevelConfiguration", "prioritylevelconfigurations", "1.26", "1.29", "flowcontrol.apiserver.k8s.io/v1"},
+
+	// Removed in 1.32
+	{"flowcontrol.apiserver.k8s.io/v1beta3", "FlowSchema", "flowschemas", "1.29", "1.32", "flowcontrol.apiserver.k8s.io/v1"},
+	{"flowcontrol.apiserver.k8s.io/v1beta3", "PriorityLevelConfiguration", "prioritylevelconfigurations", "1.29", "1.32", "flowcontrol.apiserver.k8s.io/v1"},
+}
+
+// CheckDeprecatedAPIsParams defines the parameters for the check_deprecated_apis MCP tool.
+type CheckDeprecatedAPIsParams struct {
+	// TargetVersion is the Kubernetes release being upgraded to.
+	TargetVersion string `json:"target_version,omitempty" description:"Kubernetes version being upgraded to (e.g., \"1.32\"). Defaults to the minor release after the server's version"`
+
+	// Namespace limits the scan of namespaced resources.
+	Namespace string `json:"namespace,omitempty" description:"Namespace to scan namespaced resources in (leave empty for all namespaces). Cluster-scoped resources are always scanned"`
+
+	// Context specifies the Kubernetes context to use.
+	Context string `json:"context,omitempty" description:"Kubernetes context to use (defaults to current context from kubeconfig)"`
+}
+
+// DeprecatedAPIStatus reports a deprecated API version and what happens to it
+// by the target release.
+type DeprecatedAPIStatus struct {
+	// APIVersion is the deprecated API version (e.g., "batch/v1beta1").
+	APIVersion string `json:"api_version"`
+
+	// Kind is the resource kind served by the deprecated API version.
+	Kind string `json:"kind"`
+
+	// DeprecatedIn is the release that deprecated the API version.
+	DeprecatedIn string `json:"deprecated_in"`
+
+	// RemovedIn is the release that removed, or will remove, the API version.
+	RemovedIn string `json:"removed_in"`
+
+	// Replacement is the API version to migrate to, if any.
+	Replacement string `json:"replacement,omitempty"`
+
+	// Status is "deprecated", "removed_in_target", or "removed".
+	Status string `json:"status"`
+}
+
+// DeprecatedAPIUsage reports an object last written through a deprecated API version.
+type DeprecatedAPIUsage struct {
+	DeprecatedAPIStatus
+
+	// Namespace is the object's namespace, empty for cluster-scoped objects.
+	Namespace string `json:"namespace,omitempty"`
+
+	// Name is the object's name.
+	Name string `json:"name"`
+
+	// Sources lists where the deprecated API version was found: the
+	// last-applied-configuration annotation or a field manager's entry.
+	Sources []string `json:"sources"`
+}
+
+// CheckDeprecatedAPIsResult is the result of the check_deprecated_apis MCP
+// tool.
+type CheckDeprecatedAPIsResult struct {
+	ServerVersion string `json:"server_version"`
+	TargetVersion string `json:"target_version"`
+	Namespace     string `json:"namespace,omitempty"`
+
+	// ServedDeprecatedAPIs lists the deprecated APIs the cluster still
+	// serves, whether or not objects use them.
+	ServedDeprecatedAPIs []DeprecatedAPIStatus `json:"served_deprecated_apis"`
+
+	// Count counts Resources, and BreakingCount those that use an API
+	// removed by the target version.
+	Count         int                  `json:"count"`
+	BreakingCount int                  `json:"breaking_count"`
+	Resources     []DeprecatedAPIUsage `json:"resources"`
+
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// CheckDeprecatedAPIs implements the check_deprecated_apis MCP tool.
+// It reports which deprecated API versions the cluster still serves and which
+// objects were last applied or written through a deprecated API version, as
+// recorded in their last-applied-configuration annotation and managedFields.
+// Those are the manifests and controllers that will break on upgrade.
+func (h *ResourceHandler) CheckDeprecatedAPIs(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var params CheckDeprecatedAPIsParams
+	if err := bindParams(request, &params); err != nil {
+		return response.Errorf("failed to parse arguments: %s", err)
+	}
+
+	// Use the appropriate client based on context
+	client, err := h.client.ForContext(ctx, params.Context)
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to create client with context %s: %v", params.Context, err)
+	}
+
+	info, err := client.ServerVersion()
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to get server version: %v", err)
+	}
+
+	serverVersion, err := version.ParseGeneric(info.GitVersion)
+	if err != nil {
+		return response.Errorf("failed to parse server version %q: %v", info.GitVersion, err)
+	}
+	serverVersion = version.MajorMinor(serverVersion.Major(), serverVersion.Minor())
+
+	targetVersion := version.MajorMinor(serverVersion.Major(), serverVersion.Minor()+1)
+	if params.TargetVersion != "" {
+		parsed, err := version.ParseGeneric(params.TargetVersion)
+		if err != nil {
+			return response.Errorf("invalid target_version %q: %v", params.TargetVersion, err)
+		}
+		targetVersion = version.MajorMinor(parsed.Major(), parsed.Minor())
+
+		if serverVersion.GreaterThan(targetVersion) {
+			return response.Errorf("target_version %s is older than the server version %s", targetVersion, serverVersion)
+		}
+	}
+
+	lists, err := client.DiscoverAllResources(ctx)
+	if err != nil && len(lists) == 0 {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to discover API resources: %v", err)
+	}
+
+	served := make(map[schema.GroupVersionResource]metav1.APIResource)
+	for _, list := range lists {
+		gv, err := schema.ParseGroupVersion(list.GroupVersion)
+		if err != nil {
+			continue
+		}
+		for i := range list.APIResources {
+			served[gv.WithResource(list.APIResources[i].Name)] = list.APIResources[i]
+		}
+	}
+
+	var (
+		servedDeprecated []DeprecatedAPIStatus
+		warnings         []string
+	)
+
+	// Objects of a kind are readable through any served version, so each kind
+	// is listed once through its replacement (or the deprecated version itself
+	// when nothing newer is served) and checked for every deprecated version.
+	byListGVR := make(map[schema.GroupVersionResource][]DeprecatedAPIStatus)
+	var listOrder []schema.GroupVersionResource
+
+	for _, api := range deprecatedAPIs {
+		status, ok := deprecationStatus(api, serverVersion, targetVersion)
+		if !ok {
+			continue
+		}
+
+		deprecatedGVR := mustGroupVersion(api.APIVersion).WithResource(api.Resource)
+		if _, ok := served[deprecatedGVR]; ok {
+			servedDeprecated = append(servedDeprecated, status)
+		}
+
+		listGVR, found := schema.GroupVersionResource{}, false
+		for _, candidate := range []string{api.Replacement, api.APIVersion} {
+			if candidate == "" {
+				continue
+			}
+			gvr := mustGroupVersion(candidate).WithResource(api.Resource)
+			if _, ok := served[gvr]; ok {
+				listGVR, found = gvr, true
+				break
+			}
+		}
+		if !found {
+			continue
+		}
+
+		if _, seen := byListGVR[listGVR]; !seen {
+			listOrder = append(listOrder, listGVR)
+		}
+		byListGVR[listGVR] = append(byListGVR[listGVR], status)
+	}
+
+	usages := make([]DeprecatedAPIUsage, 0)
+
+	for _, gvr := range listOrder {
+		if ctx.Err() != nil {
+			break
+		}
+		if h.resourceFilter != nil && h.resourceFilter.IsDisabled(gvr) {
+			continue
+		}
+
+		namespace := ""
+		if served[gvr].Namespaced {
+			namespace = params.Namespace
+		}
+
+		list, err := client.ListResources(ctx, gvr, namespace, metav1.ListOptions{})
+		if err != nil {
+			if h.alwaysStart && connectivity.IsTransportError(err) {
+				return response.Error(connectivity.ErrorMessage(err))
+			}
+			warnings = append(warnings, fmt.Sprintf("failed to list %s: %v", resourcefilter.FormatGVR(gvr), err))
+			continue
+		}
+
+		for i := range list.Items {
+			usages = append(usages, deprecatedUsages(&list.Items[i], byListGVR[gvr])...)
+		}
+	}
+
+	sort.Slice(servedDeprecated, func(i, j int) bool {
+		return deprecatedStatusLess(servedDeprecated[i], servedDeprecated[j])
+	})
+
+	breaking := 0
+	for i := range usages {
+		if usages[i].Status != deprecationStatusDeprecated {
+			breaking++
+		}
+	}
+
+	sort.Slice(usages, func(i, j int) bool {
+		a, b := usages[i], usages[j]
+		if a.DeprecatedAPIStatus != b.DeprecatedAPIStatus {
+			return deprecatedStatusLess(a.DeprecatedAPIStatus, b.DeprecatedAPIStatus)
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+
+	return response.JSON(CheckDeprecatedAPIsResult{
+		ServerVersion:        info.GitVersion,
+		TargetVersion:        targetVersion.String(),
+		Namespace:            params.Namespace,
+		ServedDeprecatedAPIs: servedDeprecated,
+		Count:                len(usages),
+		BreakingCount:        breaking,
+		Resources:            usages,
+		Warnings:             warnings,
+	})
+}
+
+// deprecationStatus reports the status of a deprecated API for an upgrade from
+// the server version to the target version. It returns false when the API is
+// not yet deprecated in the target version.
+func deprecationStatus(api deprecatedAPI, serverVersion, targetVersion *version.Version) (DeprecatedAPIStatus, bool) {
+	if !targetVersion.AtLeast(version.MustParseGeneric(api.DeprecatedIn)) {
+		return DeprecatedAPIStatus{}, false
+	}
+
+	status := DeprecatedAPIStatus{
+		APIVersion:   api.APIVersion,
+		Kind:         api.Kind,
+		DeprecatedIn: api.DeprecatedIn,
+		RemovedIn:    api.RemovedIn,
+		Replacement:  api.Replacement,
+		Status:       deprecationStatusDeprecated,
+	}
+
+	removedIn := version.MustParseGeneric(api.RemovedIn)
+	switch {
+	case serverVersion.AtLeast(removedIn):
+		status.Status = deprecationStatusRemoved
+	case targetVersion.AtLeast(removedIn):
+		status.Status = deprecationStatusRemovedInTarget
+	}
+
+	return status, true
+}
+
+// deprecatedStatusLess orders deprecated APIs that break the upgrade first,
+// then by kind and API version.
+func deprecatedStatusLess(a, b DeprecatedAPIStatus) bool {
+	rank := func(status string) int {
+		switch status {
+		case deprecationStatusRemovedInTarget:
+			return 0
+		case deprecationStatusRemoved:
+			return 1
+		default:
+			return 2
+		}
+	}
+
+	if rank(a.Status) != rank(b.Status) {
+		return rank(a.Status) < rank(b.Status)
+	}
+	if a.Kind != b.Kind {
+		return a.Kind < b.Kind
+	}
+	return a.APIVersion < b.APIVersion
+}
+
+// deprecatedUsages returns the deprecated API versions an object was last
+// written through, looking at the last-applied-configuration annotation and
+// the API version recorded for each field manager.
+func deprecatedUsages(obj *unstructured.Unstructured, candidates []DeprecatedAPIStatus) []DeprecatedAPIUsage {
+	sources := make(map[string][]string)
+
+	if lastApplied := obj.GetAnnotations()["kubectl.kubernetes.io/last-applied-configuration"]; lastApplied != "" {
+		var applied struct {
+			APIVersion string `json:"apiVersion"`
+		}
+		if err := json.Unmarshal([]byte(lastApplied), &applied); err == nil && applied.APIVersion != "" {
+			sources[applied.APIVersion] = append(sources[applied.APIVersion], "last-applied-configuration")
+		}
+	}
+
+	for _, entry := range obj.GetManagedFields() {
+		if entry.APIVersion == "" {
+			continue
+		}
+		sources[entry.APIVersion] = append(sources[entry.APIVersion], "managedFields:"+entry.Manager)
+	}
+
+	var usages []DeprecatedAPIUsage
+	for _, candidate := range candidates {
+		found := sources[candidate.APIVersion]
+		if len(found) == 0 || candidate.Kind != obj.GetKind() {
+			continue
+		}
+
+		usages = append(usages, DeprecatedAPIUsage{
+			DeprecatedAPIStatus: candidate,
+			Namespace:           obj.GetNamespace(),
+			Name:                obj.GetName(),
+			Sources:             found,
+		})
+	}
+
+	return usages
+}
+
+// mustGroupVersion parses an API version from the built-in deprecation table.
+func mustGroupVersion(apiVersion string) schema.GroupVersion {
+	gv, err := schema.ParseGroupVersion(apiVersion)
+	if err != nil {
+		panic(fmt.Sprintf("invalid API version %q in deprecation table: %v", apiVersion, err))
+	}
+	return gv
+}
@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	batchv1 "k8s.io/api/batch/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/version"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/fakecluster"
+)
+
+func TestDeprecationStatus(t *testing.T) {
+	t.Parallel()
+
+	cronJob := deprecatedAPI{"batch/v1beta1", "CronJob", "cronjobs", "1.21", "1.25", "batch/v1"}
+
+	tests := []struct {
+		name       string
+		server     string
+		target     string
+		wantStatus string
+		wantOK     bool
+	}{
+		{name: "not deprecated by target", server: "1.19", target: "1.20", wantOK: false},
+		{name: "deprecated", server: "1.22", target: "1.24", wantStatus: deprecationStatusDeprecated, wantOK: true},
+		{name: "removed in target", server: "1.24", target: "1.25", wantStatus: deprecationStatusRemovedInTarget, wantOK: true},
+		{name: "already removed", server: "1.26", target: "1.27", wantStatus: deprecationStatusRemoved, wantOK: true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, ok := deprecationStatus(cronJob, version.MustParseGeneric(tt.server), version.MustParseGeneric(tt.target))
+			if ok != tt.wantOK {
+				t.Fatalf("expected ok=%v, got %v", tt.wantOK, ok)
+			}
+			if got.Status != tt.wantStatus {
+				t.Errorf("expected status %q, got %q", tt.wantStatus, got.Status)
+			}
+		})
+	}
+}
+
+func TestCheckDeprecatedAPIs(t *testing.T) {
+	t.Parallel()
+
+	apiResources := append(fakecluster.DefaultAPIResources(),
+		&metav1.APIResourceList{
+			GroupVersion: "batch/v1",
+			APIResources: []metav1.APIResource{{Name: "cronjobs", Kind: "CronJob", Namespaced: true}},
+		},
+		&metav1.APIResourceList{
+			GroupVersion: "batch/v1beta1",
+			APIResources: []metav1.APIResource{{Name: "cronjobs", Kind: "CronJob", Namespaced: true}},
+		},
+		&metav1.APIResourceList{
+			GroupVersion: "policy/v1",
+			APIResources: []metav1.APIResource{{Name: "poddisruptionbudgets", Kind: "PodDisruptionBudget", Namespaced: true}},
+		},
+	)
+
+	handler := NewResourceHandler(fakecluster.New(fakecluster.Config{
+		ServerVersion: "v1.24.7",
+		APIResources:  apiResources,
+		Objects: []runtime.Object{
+			&batchv1.CronJob{ObjectMeta: metav1.ObjectMeta{
+				Name:      "nightly",
+				Namespace: "jobs",
+				ManagedFields: []metav1.ManagedFieldsEntry{
+					{Manager: "helm", APIVersion: "batch/v1beta1"},
+					{Manager: "kube-controller-manager", APIVersion: "batch/v1"},
+				},
+			}},
+			&batchv1.CronJob{ObjectMeta: metav1.ObjectMeta{
+				Name:          "current",
+				Namespace:     "jobs",
+				ManagedFields: []metav1.ManagedFieldsEntry{{Manager: "helm", APIVersion: "batch/v1"}},
+			}},
+			&policyv1.PodDisruptionBudget{ObjectMeta: metav1.ObjectMeta{
+				Name:      "web",
+				Namespace: "shop",
+				Annotations: map[string]string{
+					"kubectl.kubernetes.io/last-applied-configuration": `{"apiVersion":"policy/v1beta1","kind":"PodDisruptionBudget"}`,
+				},
+			}},
+		},
+	}), nil, false)
+
+	result, isErr := callTool(t, handler.CheckDeprecatedAPIs, map[string]any{})
+	if isErr {
+		t.Fatalf("unexpected error: %v", result["error"])
+	}
+
+	if result["target_version"] != "1.25" {
+		t.Errorf("expected default target 1.25, got %v", result["target_version"])
+	}
+	if result["breaking_count"] != float64(2) {
+		t.Errorf("expected 2 breaking usages, got %v", result["breaking_count"])
+	}
+
+	var served []DeprecatedAPIStatus
+	decodeInto(t, result["served_deprecated_apis"], &served)
+	if len(served) != 1 || served[0].APIVersion != "batch/v1beta1" || served[0].Status != deprecationStatusRemovedInTarget {
+		t.Errorf("expected only batch/v1beta1 to be reported as served, got %#v", served)
+	}
+
+	var usages []DeprecatedAPIUsage
+	decodeInto(t, result["resources"], &usages)
+
+	got := make(map[string][]string)
+	for _, usage := range usages {
+		got[usage.APIVersion+" "+usage.Namespace+"/"+usage.Name] = usage.Sources
+	}
+
+	want := map[string][]string{
+		"batch/v1beta1 jobs/nightly": {"managedFields:helm"},
+		"policy/v1beta1 shop/web":    {"last-applied-configuration"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("usages mismatch\nwant: %v\ngot:  %v", want, got)
+	}
+
+	result, isErr = callTool(t, handler.CheckDeprecatedAPIs, map[string]any{"target_version": "1.20"})
+	if msg, _ := result["error"].(string); !isErr || !strings.Contains(msg, "older than the server version") {
+		t.Errorf("expected an error for a target older than the server, got %v", result)
+	}
+}
@@ -0,0 +1,514 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/connectivity"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/kubernetes"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/response"
+)
+
+const (
+	// defaultDiagnoseLogLines is how many log lines diagnose_pod fetches per failing container.
+	defaultDiagnoseLogLines = 50
+
+	// maxDiagnoseEvents is how many of the pod's most recent events diagnose_pod reports.
+	maxDiagnoseEvents = 20
+
+	// Finding severities, from most to least severe.
+	severityError   = "error"
+	severityWarning = "warning"
+)
+
+// DiagnosePodParams defines the parameters for the diagnose_pod MCP tool.
+type DiagnosePodParams struct {
+	// Namespace specifies the pod's namespace.
+	Namespace string `json:"namespace" required:"true" description:"Pod namespace"`
+
+	// Name specifies which pod to diagnose.
+	Name string `json:"name" required:"true" description:"Pod name"`
+
+	// LogLines is how many log lines to fetch from each failing container.
+	LogLines int `json:"log_lines,omitempty" minimum:"0" maximum:"500" default:"50" description:"Number of log lines to fetch from each failing container (defaults to 50, 0 uses the default)"`
+
+	// Context specifies which Kubernetes context to use for this operation.
+	Context string `json:"context,omitempty" description:"Kubernetes context to use (defaults to current context from kubeconfig)"`
+}
+
+// DiagnosisFinding is a single problem detected on a pod.
+type DiagnosisFinding struct {
+	// Severity is "error" or "warning".
+	Severity string `json:"severity"`
+
+	// Container is the affected container, if the finding is container specific.
+	Container string `json:"container,omitempty"`
+
+	// Reason is the short machine-readable reason, such as CrashLoopBackOff.
+	Reason string `json:"reason"`
+
+	// Message describes what was observed.
+	Message string `json:"message"`
+
+	// Suggestion is the likely cause or the next step to take.
+	Suggestion string `json:"suggestion,omitempty"`
+}
+
+// DiagnosedContainer is the status of a single init or regular container.
+type DiagnosedContainer struct {
+	Name         string                 `json:"name"`
+	Init         bool                   `json:"init,omitempty"`
+	Image        string                 `json:"image"`
+	Ready        bool                   `json:"ready"`
+	RestartCount int32                  `json:"restart_count"`
+	State        ContainerStateSummary  `json:"state"`
+	LastState    *ContainerStateSummary `json:"last_state,omitempty"`
+}
+
+// DiagnosedEvent is an event recorded for the pod.
+type DiagnosedEvent struct {
+	Type     string `json:"type"`
+	Reason   string `json:"reason"`
+	Message  string `json:"message"`
+	Count    int32  `json:"count"`
+	LastSeen string `json:"last_seen,omitempty"`
+}
+
+// DiagnosedLogs is the tail of a failing container's logs.
+type DiagnosedLogs struct {
+	Container string `json:"container"`
+
+	// Previous indicates the logs come from the previous, terminated
+	// instance of the container, as with "kubectl logs --previous".
+	Previous bool   `json:"previous"`
+	Logs     string `json:"logs,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// DiagnosePodResult is the result of the diagnose_pod MCP tool.
+type DiagnosePodResult struct {
+	Namespace    string               `json:"namespace"`
+	Name         string               `json:"name"`
+	Phase        string               `json:"phase"`
+	StatusReason string               `json:"status_reason,omitempty"`
+	Node         string               `json:"node"`
+	Healthy      bool                 `json:"healthy"`
+	Summary      string               `json:"summary"`
+	Findings     []DiagnosisFinding   `json:"findings"`
+	Containers   []DiagnosedContainer `json:"containers"`
+	Events       []DiagnosedEvent     `json:"events"`
+	Logs         []DiagnosedLogs      `json:"logs"`
+	Warnings     []string             `json:"warnings,omitempty"`
+}
+
+// DiagnosePod implements the diagnose_pod MCP tool.
+// It collects a pod's status, its recent events, and the tail of the logs of
+// every failing container, and turns them into findings that explain the most
+// common failure modes: crash loops and their exit codes, image pull errors,
+// configuration errors, scheduling failures, evictions, and failing probes.
+// Events and logs are best effort; failures to read them are reported as
+// warnings without failing the diagnosis.
+func (h *PodHandler) DiagnosePod(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var params DiagnosePodParams
+	if err := bindParams(request, &params); err != nil {
+		return response.Errorf("failed to parse arguments: %s", err)
+	}
+
+	logLines := params.LogLines
+	if logLines == 0 {
+		logLines = defaultDiagnoseLogLines
+	}
+
+	// Use the appropriate client based on context
+	client, err := h.client.ForContext(ctx, params.Context)
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to create client with context %s: %v", params.Context, err)
+	}
+
+	pod, err := client.GetPod(ctx, params.Namespace, params.Name)
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("%v", err)
+	}
+
+	var warnings []string
+
+	events := make([]corev1.Event, 0)
+	selector := fields.Set{"involvedObject.kind": "Pod", "involvedObject.name": pod.Name}.AsSelector().String()
+	if list, err := client.ListEvents(ctx, pod.Namespace, metav1.ListOptions{FieldSelector: selector}); err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		warnings = append(warnings, fmt.Sprintf("failed to list events: %v", err))
+	} else {
+		events = podEvents(list.Items, pod)
+	}
+
+	containers := diagnosedContainers(pod)
+	findings := diagnosePod(pod, events)
+
+	logs := make([]DiagnosedLogs, 0)
+	for _, target := range logTargets(pod) {
+		if ctx.Err() != nil {
+			break
+		}
+		lines := int64(logLines)
+		entry := DiagnosedLogs{Container: target.container, Previous: target.previous}
+
+		output, err := client.GetPodLogsWithOptions(ctx, pod.Namespace, pod.Name, &kubernetes.LogOptions{
+			Container: target.container,
+			MaxLines:  &lines,
+			Previous:  target.previous,
+		})
+		if err != nil {
+			if h.alwaysStart && connectivity.IsTransportError(err) {
+				return response.Error(connectivity.ErrorMessage(err))
+			}
+			entry.Error = err.Error()
+		} else {
+			entry.Logs = output
+		}
+
+		logs = append(logs, entry)
+	}
+
+	reported := make([]DiagnosedEvent, 0, len(events))
+	for i := range events {
+		if len(reported) == maxDiagnoseEvents {
+			break
+		}
+
+		event := &events[i]
+		count := event.Count
+		if count == 0 {
+			count = 1
+		}
+
+		reported = append(reported, DiagnosedEvent{
+			Type:     event.Type,
+			Reason:   event.Reason,
+			Message:  event.Message,
+			Count:    count,
+			LastSeen: formatTime(eventLastSeen(event)),
+		})
+	}
+
+	summary := "no problems detected"
+	if len(findings) > 0 {
+		summary = findings[0].Message
+		if findings[0].Container != "" {
+			summary = findings[0].Container + ": " + summary
+		}
+	}
+
+	return response.JSON(DiagnosePodResult{
+		Namespace:    pod.Namespace,
+		Name:         pod.Name,
+		Phase:        string(pod.Status.Phase),
+		StatusReason: pod.Status.Reason,
+		Node:         pod.Spec.NodeName,
+		Healthy:      len(findings) == 0,
+		Summary:      summary,
+		Findings:     findings,
+		Containers:   containers,
+		Events:       reported,
+		Logs:         logs,
+		Warnings:     warnings,
+	})
+}
+
+// podEvents returns the events about pod, newest first. Events are matched on
+// the pod's UID when both sides have one, so events from an earlier pod with
+// the same name (common for StatefulSets) are left out.
+func podEvents(events []corev1.Event, pod *corev1.Pod) []corev1.Event {
+	matched := make([]corev1.Event, 0, len(events))
+	for i := range events {
+		involved := events[i].InvolvedObject
+		if involved.Kind != "Pod" || involved.Name != pod.Name {
+			continue
+		}
+		if involved.UID != "" && pod.UID != "" && involved.UID != pod.UID {
+			continue
+		}
+		matched = append(matched, events[i])
+	}
+
+	sort.SliceStable(matched, func(i, j int) bool {
+		return eventLastSeen(&matched[i]).After(eventLastSeen(&matched[j]))
+	})
+
+	return matched
+}
+
+// diagnosedContainers reports the status of the pod's init and regular
+// containers, in spec order.
+func diagnosedContainers(pod *corev1.Pod) []DiagnosedContainer {
+	containers := make([]DiagnosedContainer, 0, len(pod.Spec.InitContainers)+len(pod.Spec.Containers))
+
+	add := func(specs []corev1.Container, statuses []corev1.ContainerStatus, init bool) {
+		byName := make(map[string]corev1.ContainerStatus, len(statuses))
+		for i := range statuses {
+			byName[statuses[i].Name] = statuses[i]
+		}
+
+		for i := range specs {
+			entry := DiagnosedContainer{
+				Name:  specs[i].Name,
+				Init:  init,
+				Image: specs[i].Image,
+				State: ContainerStateSummary{State: "unknown"},
+			}
+
+			if status, ok := byName[specs[i].Name]; ok {
+				entry.Ready = status.Ready
+				entry.RestartCount = status.RestartCount
+				entry.State = summarizeContainerState(status.State)
+				if status.LastTerminationState.Terminated != nil {
+					last := summarizeContainerState(status.LastTerminationState)
+					entry.LastState = &last
+				}
+			}
+
+			containers = append(containers, entry)
+		}
+	}
+
+	add(pod.Spec.InitContainers, pod.Status.InitContainerStatuses, true)
+	add(pod.Spec.Containers, pod.Status.ContainerStatuses, false)
+
+	return containers
+}
+
+// logTarget is a container whose logs are worth fetching.
+type logTarget struct {
+	container string
+	previous  bool
+}
+
+// logTargets returns the containers whose logs help explain a failure: those
+// that restarted, are waiting after a crash, or terminated with an error.
+// Containers waiting to restart after a crash have no current logs, so the
+// previous instance is read instead.
+func logTargets(pod *corev1.Pod) []logTarget {
+	var targets []logTarget
+
+	statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+	for i := range statuses {
+		status := &statuses[i]
+
+		crashed := status.LastTerminationState.Terminated != nil
+		waiting := status.State.Waiting != nil
+		failed := status.State.Terminated != nil && status.State.Terminated.ExitCode != 0
+
+		switch {
+		case crashed && waiting:
+			targets = append(targets, logTarget{container: status.Name, previous: true})
+		case failed, crashed, status.RestartCount > 0:
+			targets = append(targets, logTarget{container: status.Name})
+		}
+	}
+
+	return targets
+}
+
+// diagnosePod turns a pod's status and events into findings, most severe
+// first.
+func diagnosePod(pod *corev1.Pod, events []corev1.Event) []DiagnosisFinding {
+	var findings []DiagnosisFinding
+
+	if pod.Status.Reason == "Evicted" {
+		findings = append(findings, DiagnosisFinding{
+			Severity:   severityError,
+			Reason:     "Evicted",
+			Message:    "the pod was evicted: " + pod.Status.Message,
+			Suggestion: "The node ran short of a resource (usually memory or ephemeral storage). Set resource requests that reflect real usage, and check the node with get_node_conditions.",
+		})
+	}
+
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodScheduled && condition.Status == corev1.ConditionFalse {
+			findings = append(findings, DiagnosisFinding{
+				Severity:   severityError,
+				Reason:     condition.Reason,
+				Message:    "the pod cannot be scheduled: " + condition.Message,
+				Suggestion: "No node satisfies the pod's resource requests, node selectors, affinity, or taint tolerations. Compare the pod's requirements with the capacity and labels of the nodes.",
+			})
+		}
+	}
+
+	statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+	for i := range statuses {
+		findings = append(findings, diagnoseContainer(pod, &statuses[i])...)
+	}
+
+	findings = append(findings, diagnoseEvents(events)...)
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		return findings[i].Severity == severityError && findings[j].Severity != severityError
+	})
+
+	if findings == nil {
+		findings = make([]DiagnosisFinding, 0)
+	}
+
+	return findings
+}
+
+// diagnoseContainer explains the state of a single container.
+func diagnoseContainer(pod *corev1.Pod, status *corev1.ContainerStatus) []DiagnosisFinding {
+	var findings []DiagnosisFinding
+
+	last := status.LastTerminationState.Terminated
+
+	if waiting := status.State.Waiting; waiting != nil {
+		switch waiting.Reason {
+		case "CrashLoopBackOff":
+			finding := DiagnosisFinding{
+				Severity: severityError,
+				Reason:   waiting.Reason,
+				Message:  fmt.Sprintf("container keeps crashing and has restarted %d times", status.RestartCount),
+			}
+			if last != nil {
+				finding.Message += fmt.Sprintf("; it last exited with code %d (%s)", last.ExitCode, last.Reason)
+				finding.Suggestion = exitCodeMeaning(last.ExitCode, last.Reason)
+			}
+			findings = append(findings, finding)
+		case "ImagePullBackOff", "ErrImagePull", "InvalidImageName", "ErrImageNeverPull":
+			findings = append(findings, DiagnosisFinding{
+				Severity:   severityError,
+				Reason:     waiting.Reason,
+				Message:    "the container image cannot be pulled: " + waiting.Message,
+				Suggestion: "Check the image name and tag for typos, that the image exists in the registry, and that the pod has imagePullSecrets for private registries.",
+			})
+		case "CreateContainerConfigError":
+			findings = append(findings, DiagnosisFinding{
+				Severity:   severityError,
+				Reason:     waiting.Reason,
+				Message:    "the container configuration is invalid: " + waiting.Message,
+				Suggestion: "A referenced ConfigMap, Secret, or key usually does not exist. Create it or fix the reference in env, envFrom, or volumes.",
+			})
+		case "CreateContainerError", "RunContainerError":
+			findings = append(findings, DiagnosisFinding{
+				Severity:   severityError,
+				Reason:     waiting.Reason,
+				Message:    "the container runtime could not start the container: " + waiting.Message,
+				Suggestion: "Check the command, working directory, and volume mounts; the runtime's message usually names the missing file or invalid setting.",
+			})
+		case "ContainerCreating", "PodInitializing":
+			// Normal while starting; events explain slow starts such as failed mounts.
+		default:
+			if waiting.Reason != "" {
+				findings = append(findings, DiagnosisFinding{
+					Severity: severityWarning,
+					Reason:   waiting.Reason,
+					Message:  strings.TrimSpace("container is waiting: " + waiting.Message),
+				})
+			}
+		}
+	}
+
+	if terminated := status.State.Terminated; terminated != nil && terminated.ExitCode != 0 {
+		findings = append(findings, DiagnosisFinding{
+			Severity:   severityError,
+			Reason:     terminated.Reason,
+			Message:    fmt.Sprintf("container terminated with exit code %d", terminated.ExitCode),
+			Suggestion: exitCodeMeaning(terminated.ExitCode, terminated.Reason),
+		})
+	}
+
+	if status.State.Running != nil {
+		if last != nil && status.RestartCount > 0 {
+			findings = append(findings, DiagnosisFinding{
+				Severity:   severityWarning,
+				Reason:     "Restarted",
+				Message:    fmt.Sprintf("container is running but has restarted %d times; it last exited with code %d (%s)", status.RestartCount, last.ExitCode, last.Reason),
+				Suggestion: exitCodeMeaning(last.ExitCode, last.Reason),
+			})
+		}
+
+		if !status.Ready && pod.Status.Phase == corev1.PodRunning {
+			findings = append(findings, DiagnosisFinding{
+				Severity:   severityWarning,
+				Reason:     "NotReady",
+				Message:    "container is running but not ready",
+				Suggestion: "The readiness probe is failing, so the pod receives no Service traffic. Check the probe's path, port, and timing against the application's startup time.",
+			})
+		}
+	}
+
+	for i := range findings {
+		findings[i].Container = status.Name
+	}
+
+	return findings
+}
+
+// diagnoseEvents reports Warning events that point at a cause not visible in
+// the pod status, such as volumes that cannot be mounted.
+func diagnoseEvents(events []corev1.Event) []DiagnosisFinding {
+	suggestions := map[string]string{
+		"FailedMount":            "A volume cannot be mounted. Check that the referenced PersistentVolumeClaim, ConfigMap, or Secret exists and that the volume is not attached to another node.",
+		"FailedAttachVolume":     "The volume cannot be attached to the node. It may still be attached elsewhere or be in a different availability zone than the node.",
+		"Unhealthy":              "A liveness, readiness, or startup probe is failing. Failing liveness probes restart the container.",
+		"FailedCreatePodSandBox": "The pod sandbox could not be created, which usually points at the node's CNI network plugin.",
+	}
+
+	var findings []DiagnosisFinding
+	seen := make(map[string]bool)
+
+	for i := range events {
+		event := &events[i]
+		suggestion, ok := suggestions[event.Reason]
+		if event.Type != corev1.EventTypeWarning || !ok || seen[event.Reason] {
+			continue
+		}
+		seen[event.Reason] = true
+
+		findings = append(findings, DiagnosisFinding{
+			Severity:   severityWarning,
+			Reason:     event.Reason,
+			Message:    event.Message,
+			Suggestion: suggestion,
+		})
+	}
+
+	return findings
+}
+
+// exitCodeMeaning explains the usual cause of a container exit code.
+func exitCodeMeaning(code int32, reason string) string {
+	switch {
+	case reason == "OOMKilled":
+		return "The container exceeded its memory limit and was killed by the kernel. Raise the memory limit or reduce the application's memory usage."
+	case code == 0:
+		return "The process exited successfully. Long-running containers must not exit; check that the command keeps running in the foreground."
+	case code == 1:
+		return "The application exited with a generic error. The logs from the previous instance usually explain why."
+	case code == 126:
+		return "The command could not be executed, usually because the file is not executable."
+	case code == 127:
+		return "The command was not found in the image. Check the command and args against the image's contents."
+	case code == 137:
+		return "The process was killed with SIGKILL, typically by a failing liveness probe, an eviction, or running out of memory."
+	case code == 139:
+		return "The process crashed with a segmentation fault."
+	case code == 143:
+		return "The process was stopped with SIGTERM, typically during a restart triggered by a failing liveness probe or a rollout."
+	case code > 128:
+		return fmt.Sprintf("The process was killed by signal %d.", code-128)
+	default:
+		return "The application exited with an error. The logs from the previous instance usually explain why."
+	}
+}
@@ -0,0 +1,177 @@
+package handlers
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/fakecluster"
+)
+
+func TestDiagnosePodFindings(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		pod         corev1.Pod
+		events      []corev1.Event
+		wantReasons []string
+	}{
+		{
+			name: "healthy",
+			pod: corev1.Pod{Status: corev1.PodStatus{
+				Phase: corev1.PodRunning,
+				ContainerStatuses: []corev1.ContainerStatus{{
+					Name:  "app",
+					Ready: true,
+					State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+				}},
+			}},
+			wantReasons: []string{},
+		},
+		{
+			name: "crash loop after OOM kill",
+			pod: corev1.Pod{Status: corev1.PodStatus{
+				Phase: corev1.PodRunning,
+				ContainerStatuses: []corev1.ContainerStatus{{
+					Name:                 "app",
+					RestartCount:         5,
+					State:                corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+					LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 137, Reason: "OOMKilled"}},
+				}},
+			}},
+			wantReasons: []string{"CrashLoopBackOff"},
+		},
+		{
+			name: "unschedulable with failed mount warning",
+			pod: corev1.Pod{Status: corev1.PodStatus{
+				Phase: corev1.PodPending,
+				Conditions: []corev1.PodCondition{
+					{Type: corev1.PodScheduled, Status: corev1.ConditionFalse, Reason: "Unschedulable", Message: "0/3 nodes are available"},
+				},
+			}},
+			events: []corev1.Event{
+				{Type: corev1.EventTypeNormal, Reason: "Scheduled"},
+				{Type: corev1.EventTypeWarning, Reason: "FailedMount", Message: "configmap \"settings\" not found"},
+				{Type: corev1.EventTypeWarning, Reason: "FailedMount", Message: "configmap \"settings\" not found"},
+			},
+			wantReasons: []string{"Unschedulable", "FailedMount"},
+		},
+		{
+			name: "image pull error is listed before a readiness warning",
+			pod: corev1.Pod{Status: corev1.PodStatus{
+				Phase: corev1.PodRunning,
+				ContainerStatuses: []corev1.ContainerStatus{
+					{Name: "app", State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}},
+					{Name: "sidecar", State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ImagePullBackOff"}}},
+				},
+			}},
+			wantReasons: []string{"ImagePullBackOff", "NotReady"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			findings := diagnosePod(&tt.pod, tt.events)
+
+			reasons := make([]string, 0, len(findings))
+			for _, finding := range findings {
+				reasons = append(reasons, finding.Reason)
+			}
+			if !reflect.DeepEqual(reasons, tt.wantReasons) {
+				t.Errorf("expected findings %v, got %v", tt.wantReasons, reasons)
+			}
+		})
+	}
+}
+
+func TestLogTargets(t *testing.T) {
+	t.Parallel()
+
+	crashed := corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 1}}
+
+	pod := &corev1.Pod{Status: corev1.PodStatus{
+		InitContainerStatuses: []corev1.ContainerStatus{
+			{Name: "migrate", State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 0}}},
+		},
+		ContainerStatuses: []corev1.ContainerStatus{
+			{Name: "app", State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}}, LastTerminationState: crashed, RestartCount: 3},
+			{Name: "proxy", State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}, LastTerminationState: crashed, RestartCount: 1},
+			{Name: "healthy", State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}},
+		},
+	}}
+
+	want := []logTarget{{container: "app", previous: true}, {container: "proxy"}}
+	if got := logTargets(pod); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected log targets %v, got %v", want, got)
+	}
+}
+
+func TestDiagnosePod_FakeCluster(t *testing.T) {
+	t.Parallel()
+
+	handler := NewPodHandler(fakecluster.New(fakecluster.Config{
+		Objects: []runtime.Object{
+			&corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "worker", Namespace: "shop", UID: "current"},
+				Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Image: "shop/worker:1.2"}}},
+				Status: corev1.PodStatus{
+					Phase: corev1.PodRunning,
+					ContainerStatuses: []corev1.ContainerStatus{{
+						Name:                 "app",
+						RestartCount:         4,
+						State:                corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+						LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 127, Reason: "Error"}},
+					}},
+				},
+			},
+			&corev1.Event{
+				ObjectMeta:     metav1.ObjectMeta{Name: "worker.1", Namespace: "shop"},
+				InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "worker", Namespace: "shop", UID: "current"},
+				Type:           corev1.EventTypeWarning,
+				Reason:         "BackOff",
+				Message:        "Back-off restarting failed container app",
+			},
+			&corev1.Event{
+				ObjectMeta:     metav1.ObjectMeta{Name: "worker.old", Namespace: "shop"},
+				InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "worker", Namespace: "shop", UID: "previous"},
+				Type:           corev1.EventTypeWarning,
+				Reason:         "FailedMount",
+			},
+		},
+	}), false)
+
+	result, isErr := callTool(t, handler.DiagnosePod, map[string]any{"namespace": "shop", "name": "worker"})
+	if isErr {
+		t.Fatalf("unexpected error: %v", result["error"])
+	}
+
+	if result["healthy"] != false {
+		t.Error("expected the pod to be reported unhealthy")
+	}
+	if want := "app: container keeps crashing and has restarted 4 times; it last exited with code 127 (Error)"; result["summary"] != want {
+		t.Errorf("expected summary %q, got %q", want, result["summary"])
+	}
+
+	var events []DiagnosedEvent
+	decodeInto(t, result["events"], &events)
+	if len(events) != 1 || events[0].Reason != "BackOff" {
+		t.Errorf("expected only the current pod's events, got %#v", events)
+	}
+
+	var logs []DiagnosedLogs
+	decodeInto(t, result["logs"], &logs)
+	if len(logs) != 1 || logs[0].Container != "app" || !logs[0].Previous || logs[0].Error != "" {
+		t.Errorf("expected previous logs for app, got %#v", logs)
+	}
+
+	if result, isErr := callTool(t, handler.DiagnosePod, map[string]any{"namespace": "shop", "name": "missing"}); !isErr {
+		t.Errorf("expected an error for a missing pod, got %v", result)
+	}
+}
@@ -0,0 +1,439 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"regexp"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/connectivity"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/kubernetes"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/resourcefilter"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/response"
+)
+
+// lastAppliedAnnotation holds the configuration kubectl apply last applied,
+// which it uses to find the fields a new manifest removes.
+const lastAppliedAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+
+// defaultMaxDifferences is how many field differences a diff returns unless
+// the caller asks for more.
+const defaultMaxDifferences = 100
+
+// serverManagedFields are set by the API server or by controllers rather than
+// by whoever applies the object, so they differ between clusters even when
+// the applied configuration is the same. Diffs ignore them.
+var serverManagedFields = [][]string{
+	{"metadata", "managedFields"},
+	{"metadata", "resourceVersion"},
+	{"metadata", "uid"},
+	{"metadata", "creationTimestamp"},
+	{"metadata", "generation"},
+	{"metadata", "selfLink"},
+	{"metadata", "deletionTimestamp"},
+	{"metadata", "deletionGracePeriodSeconds"},
+	{"metadata", "annotations", lastAppliedAnnotation},
+	{"metadata", "annotations", "deployment.kubernetes.io/revision"},
+}
+
+// serverAllocatedFields are server-managed fields specific to a kind, such as
+// the cluster IP allocated to a Service.
+var serverAllocatedFields = map[string][][]string{
+	"Service": {
+		{"spec", "clusterIP"},
+		{"spec", "clusterIPs"},
+	},
+}
+
+// DiffResourceAcrossContextsParams defines the parameters for the
+// diff_resource_across_contexts MCP tool.
+type DiffResourceAcrossContextsParams struct {
+	// ResourceType specifies the type of the resource to compare.
+	ResourceType string `json:"resource_type" required:"true" description:"The type of resource to compare (e.g., deployments, configmaps)"`
+
+	// APIVersion disambiguates resource types served by several API groups.
+	APIVersion string `json:"api_version,omitempty" description:"API version for the resource (e.g., 'v1', 'apps/v1'). Optional, used to disambiguate resource types"`
+
+	// Namespace specifies the namespace of the resource in both contexts.
+	Namespace string `json:"namespace,omitempty" description:"Namespace of the resource in both contexts (defaults to each context's namespace; leave empty for cluster-scoped resources)"`
+
+	// Name specifies the resource to compare.
+	Name string `json:"name" required:"true" description:"Resource name"`
+
+	// ContextA is the first context to read the resource from.
+	ContextA string `json:"context_a,omitempty" description:"First Kubernetes context to compare (defaults to current context from kubeconfig)"`
+
+	// ContextB is the second context to read the resource from.
+	ContextB string `json:"context_b" required:"true" description:"Second Kubernetes context to compare"`
+
+	// IncludeStatus compares the status stanza too, which usually differs.
+	IncludeStatus bool `json:"include_status,omitempty" default:"false" description:"When true, also compares the status stanza. By default only the desired state is compared"`
+
+	// MaxDifferences bounds how many differences are returned.
+	MaxDifferences int `json:"max_differences,omitempty" minimum:"1" default:"100" description:"Maximum number of field differences to return (defaults to 100)"`
+}
+
+// FieldDifference is a field whose value differs between two objects.
+type FieldDifference struct {
+	// Path locates the field, such as
+	// spec.template.spec.containers[name=app].image.
+	Path string `json:"path"`
+
+	// Change is "added" when only the second object sets the field,
+	// "removed" when only the first one does, or "changed".
+	Change string `json:"change"`
+
+	A interface{} `json:"a,omitempty"`
+	B interface{} `json:"b,omitempty"`
+}
+
+// DiffResourceAcrossContextsResult is the result of the
+// diff_resource_across_contexts MCP tool. Kind and the fields after it are
+// only set when the resource exists in both contexts.
+type DiffResourceAcrossContextsResult struct {
+	ResourceType string `json:"resource_type"`
+	Name         string `json:"name"`
+	ContextA     string `json:"context_a"`
+	ContextB     string `json:"context_b"`
+	ExistsInA    bool   `json:"exists_in_a"`
+	ExistsInB    bool   `json:"exists_in_b"`
+	Identical    bool   `json:"identical"`
+
+	Kind       string   `json:"kind,omitempty"`
+	NamespaceA string   `json:"namespace_a,omitempty"`
+	NamespaceB string   `json:"namespace_b,omitempty"`
+	Warnings   []string `json:"warnings,omitempty"`
+
+	// DifferenceCount counts every difference, even when Differences was
+	// cut to max_differences and Truncated is set.
+	DifferenceCount int               `json:"difference_count,omitempty"`
+	Truncated       bool              `json:"truncated,omitempty"`
+	Differences     []FieldDifference `json:"differences,omitempty"`
+	IgnoredFields   []string          `json:"ignored_fields,omitempty"`
+}
+
+// DiffResourceAcrossContexts implements the diff_resource_across_contexts MCP tool.
+// It reads the same resource from two kubeconfig contexts and returns the
+// fields that differ, ignoring fields the API server manages such as
+// resourceVersion, uid, managedFields, and by default the status stanza.
+// Lists of named entries, such as containers or env, are matched by name so
+// that reordering them does not show up as a difference.
+func (h *ResourceHandler) DiffResourceAcrossContexts(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var params DiffResourceAcrossContextsParams
+	if err := bindParams(request, &params); err != nil {
+		return response.Errorf("failed to parse arguments: %s", err)
+	}
+
+	if params.ContextA == params.ContextB {
+		return response.Errorf("context_a and context_b must be different contexts")
+	}
+
+	maxDifferences := params.MaxDifferences
+	if maxDifferences <= 0 {
+		maxDifferences = defaultMaxDifferences
+	}
+
+	objA, result, err := h.getForDiff(ctx, params, params.ContextA)
+	if result != nil || err != nil {
+		return result, err
+	}
+
+	objB, result, err := h.getForDiff(ctx, params, params.ContextB)
+	if result != nil || err != nil {
+		return result, err
+	}
+
+	if objA == nil && objB == nil {
+		return response.Errorf("%s %q was not found in either context", params.ResourceType, params.Name)
+	}
+
+	out := DiffResourceAcrossContextsResult{
+		ResourceType: params.ResourceType,
+		Name:         params.Name,
+		ContextA:     contextLabel(params.ContextA),
+		ContextB:     contextLabel(params.ContextB),
+		ExistsInA:    objA != nil,
+		ExistsInB:    objB != nil,
+	}
+
+	if objA == nil || objB == nil {
+		return response.JSON(out)
+	}
+
+	out.Kind = objA.GetKind()
+	out.NamespaceA = objA.GetNamespace()
+	out.NamespaceB = objB.GetNamespace()
+
+	if objA.GetAPIVersion() != objB.GetAPIVersion() {
+		out.Warnings = []string{fmt.Sprintf("the contexts serve the resource as %s and %s; fields may differ only because of the API version",
+			objA.GetAPIVersion(), objB.GetAPIVersion())}
+	}
+
+	ignored := diffIgnoredPaths(objA.GetKind(), params.IncludeStatus)
+	differences := diffValues("", normalizeForDiff(objA, ignored), normalizeForDiff(objB, ignored))
+
+	out.Identical = len(differences) == 0
+	out.DifferenceCount = len(differences)
+	if len(differences) > maxDifferences {
+		differences = differences[:maxDifferences]
+		out.Truncated = true
+	}
+	out.Differences = differences
+
+	ignoredFields := make([]string, 0, len(ignored))
+	for _, path := range ignored {
+		ignoredFields = append(ignoredFields, formatFieldPath(path))
+	}
+	out.IgnoredFields = ignoredFields
+
+	return response.JSON(out)
+}
+
+// getForDiff reads the resource of a diff from one context. It returns a nil
+// object when the resource does not exist there, and a non-nil result when
+// the tool should stop with an error.
+func (h *ResourceHandler) getForDiff(ctx context.Context, params DiffResourceAcrossContextsParams, contextName string) (*unstructured.Unstructured, *mcp.CallToolResult, error) {
+	client, err := h.client.ForContext(ctx, contextName)
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			result, err := response.Error(connectivity.ErrorMessage(err))
+			return nil, result, err
+		}
+		result, err := response.Errorf("failed to create client with context %s: %v", contextName, err)
+		return nil, result, err
+	}
+
+	gvr, err := client.ResolveResourceType(params.ResourceType, params.APIVersion)
+	if err != nil {
+		if h.alwaysStart && connectivity.IsError(err) {
+			result, err := response.Error(connectivity.ErrorMessage(err))
+			return nil, result, err
+		}
+		result, err := response.Errorf("failed to resolve resource type in context %s: %v", contextLabel(contextName), err)
+		return nil, result, err
+	}
+
+	if result, err := h.disabledResult(params.ResourceType, gvr); result != nil || err != nil {
+		return nil, result, err
+	}
+
+	obj, err := getResourceFrom(ctx, client, gvr, params.Namespace, params.Name)
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			result, err := response.Error(connectivity.ErrorMessage(err))
+			return nil, result, err
+		}
+		result, err := response.Errorf("failed to get resource from context %s: %v", contextLabel(contextName), err)
+		return nil, result, err
+	}
+
+	return obj, nil, nil
+}
+
+// getResourceFrom reads a resource, returning nil without an error when it
+// does not exist.
+func getResourceFrom(ctx context.Context, client kubernetes.ClusterReader, gvr schema.GroupVersionResource, namespace, name string) (*unstructured.Unstructured, error) {
+	obj, err := client.GetResource(ctx, gvr, namespace, name)
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	return obj, err //nolint:wrapcheck // callers add context
+}
+
+// disabledResult returns the tool error for a resource type disabled by the
+// resource filter, or nil when gvr may be read.
+func (h *ResourceHandler) disabledResult(resourceType string, gvr schema.GroupVersionResource) (*mcp.CallToolResult, error) {
+	return filterDisabledResult(h.resourceFilter, h.alwaysStart, resourceType, gvr)
+}
+
+// filterDisabledResult returns the tool error for a resource type disabled
+// by filter, or nil when gvr may be read or filter is nil.
+func filterDisabledResult(filter *resourcefilter.Filter, alwaysStart bool, resourceType string, gvr schema.GroupVersionResource) (*mcp.CallToolResult, error) {
+	if filter == nil || !filter.IsDisabled(gvr) {
+		return nil, nil
+	}
+
+	if initErr := filter.InitError(); initErr != nil {
+		if alwaysStart && connectivity.IsError(initErr) {
+			return response.Error(connectivity.ErrorMessage(initErr))
+		}
+		return response.Errorf("resource filter could not be initialized: %v", initErr)
+	}
+	return response.Errorf("access to resource %q (%s) is disabled by configuration and cannot be queried",
+		resourceType, resourcefilter.FormatGVR(gvr))
+}
+
+// contextLabel names a context in messages, where an empty name means the
+// current context.
+func contextLabel(name string) string {
+	if name == "" {
+		return "(current)"
+	}
+	return name
+}
+
+// diffIgnoredPaths lists the fields a diff of kind ignores.
+func diffIgnoredPaths(kind string, includeStatus bool) [][]string {
+	paths := append(append([][]string{}, serverManagedFields...), serverAllocatedFields[kind]...)
+	if !includeStatus {
+		paths = append(paths, []string{"status"})
+	}
+	return paths
+}
+
+// formatFieldPath writes a field path in the notation of FieldDifference.
+func formatFieldPath(path []string) string {
+	joined := ""
+	for _, key := range path {
+		joined = fieldPath(joined, key)
+	}
+	return joined
+}
+
+// normalizeForDiff returns a copy of obj without the ignored fields and
+// without the UIDs of owner references, which differ between clusters.
+func normalizeForDiff(obj *unstructured.Unstructured, ignored [][]string) map[string]interface{} {
+	normalized := obj.DeepCopy()
+
+	for _, path := range ignored {
+		unstructured.RemoveNestedField(normalized.Object, path...)
+	}
+
+	// Removing the last-applied-configuration can leave an empty map behind.
+	if len(normalized.GetAnnotations()) == 0 {
+		unstructured.RemoveNestedField(normalized.Object, "metadata", "annotations")
+	}
+
+	if refs, found, _ := unstructured.NestedSlice(normalized.Object, "metadata", "ownerReferences"); found {
+		for _, ref := range refs {
+			if m, ok := ref.(map[string]interface{}); ok {
+				delete(m, "uid")
+			}
+		}
+		_ = unstructured.SetNestedSlice(normalized.Object, refs, "metadata", "ownerReferences")
+	}
+
+	return normalized.Object
+}
+
+// identifierKey matches map keys that can be written after a dot in a path.
+var identifierKey = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+
+// fieldPath appends a map key to a path, quoting keys such as annotation
+// names that contain dots or slashes.
+func fieldPath(path, key string) string {
+	if !identifierKey.MatchString(key) {
+		return fmt.Sprintf("%s[%q]", path, key)
+	}
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// diffValues compares two decoded JSON values and returns their differences,
+// ordered by path.
+func diffValues(path string, a, b interface{}) []FieldDifference {
+	var differences []FieldDifference
+
+	switch av := a.(type) {
+	case map[string]interface{}:
+		bv, ok := b.(map[string]interface{})
+		if !ok {
+			break
+		}
+
+		for _, key := range unionKeys(av, bv) {
+			childPath := fieldPath(path, key)
+			aValue, inA := av[key]
+			bValue, inB := bv[key]
+			switch {
+			case !inA:
+				differences = append(differences, FieldDifference{Path: childPath, Change: "added", B: bValue})
+			case !inB:
+				differences = append(differences, FieldDifference{Path: childPath, Change: "removed", A: aValue})
+			default:
+				differences = append(differences, diffValues(childPath, aValue, bValue)...)
+			}
+		}
+		return differences
+
+	case []interface{}:
+		bv, ok := b.([]interface{})
+		if !ok {
+			break
+		}
+
+		if aNamed, bNamed := namedEntries(av), namedEntries(bv); aNamed != nil && bNamed != nil {
+			return diffNamedLists(path, av, bv, aNamed, bNamed)
+		}
+
+		for i := 0; i < len(av) || i < len(bv); i++ {
+			childPath := fmt.Sprintf("%s[%d]", path, i)
+			switch {
+			case i >= len(av):
+				differences = append(differences, FieldDifference{Path: childPath, Change: "added", B: bv[i]})
+			case i >= len(bv):
+				differences = append(differences, FieldDifference{Path: childPath, Change: "removed", A: av[i]})
+			default:
+				differences = append(differences, diffValues(childPath, av[i], bv[i])...)
+			}
+		}
+		return differences
+	}
+
+	if !reflect.DeepEqual(a, b) {
+		differences = append(differences, FieldDifference{Path: path, Change: "changed", A: a, B: b})
+	}
+	return differences
+}
+
+// namedEntries returns the index of each entry of a list of objects keyed by
+// a unique "name", like containers, volumes, or env. It returns nil for any
+// other list.
+func namedEntries(list []interface{}) map[string]int {
+	if len(list) == 0 {
+		return nil
+	}
+
+	names := make(map[string]int, len(list))
+	for i, item := range list {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		name, ok := m["name"].(string)
+		if !ok || name == "" {
+			return nil
+		}
+		if _, dup := names[name]; dup {
+			return nil
+		}
+		names[name] = i
+	}
+	return names
+}
+
+// diffNamedLists compares two lists of named entries by name.
+func diffNamedLists(path string, a, b []interface{}, aNames, bNames map[string]int) []FieldDifference {
+	var differences []FieldDifference
+	for _, name := range unionNames(aNames, bNames) {
+		childPath := fmt.Sprintf("%s[name=%s]", path, name)
+		ai, inA := aNames[name]
+		bi, inB := bNames[name]
+		switch {
+		case !inA:
+			differences = append(differences, FieldDifference{Path: childPath, Change: "added", B: b[bi]})
+		case !inB:
+			differences = append(differences, FieldDifference{Path: childPath, Change: "removed", A: a[ai]})
+		default:
+			differences = append(differences, diffValues(childPath, a[ai], b[bi])...)
+		}
+	}
+	return differences
+}
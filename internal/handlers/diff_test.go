@@ -0,0 +1,181 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/fakecluster"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/kubernetes"
+)
+
+// contextClients serves a separate fake cluster for each kubeconfig context.
+type contextClients struct {
+	kubernetes.ClusterReader
+	contexts map[string]kubernetes.ClusterReader
+}
+
+func (c contextClients) ForContext(_ context.Context, name string) (kubernetes.ClusterReader, error) {
+	if name == "" {
+		return c.ClusterReader, nil
+	}
+	if client, ok := c.contexts[name]; ok {
+		return client, nil
+	}
+	return nil, fmt.Errorf("context %q not found", name)
+}
+
+func TestDiffValues(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		a, b map[string]interface{}
+		want []FieldDifference
+	}{
+		{
+			name: "identical",
+			a:    map[string]interface{}{"replicas": int64(2)},
+			b:    map[string]interface{}{"replicas": int64(2)},
+		},
+		{
+			name: "scalar change, added and removed keys",
+			a:    map[string]interface{}{"replicas": int64(2), "paused": true},
+			b:    map[string]interface{}{"replicas": int64(5), "minReadySeconds": int64(10)},
+			want: []FieldDifference{
+				{Path: "minReadySeconds", Change: "added", B: int64(10)},
+				{Path: "paused", Change: "removed", A: true},
+				{Path: "replicas", Change: "changed", A: int64(2), B: int64(5)},
+			},
+		},
+		{
+			name: "named entries are matched by name",
+			a: map[string]interface{}{"containers": []interface{}{
+				map[string]interface{}{"name": "app", "image": "web:1.0"},
+				map[string]interface{}{"name": "sidecar", "image": "proxy:1.0"},
+			}},
+			b: map[string]interface{}{"containers": []interface{}{
+				map[string]interface{}{"name": "sidecar", "image": "proxy:1.0"},
+				map[string]interface{}{"name": "app", "image": "web:1.1"},
+			}},
+			want: []FieldDifference{
+				{Path: "containers[name=app].image", Change: "changed", A: "web:1.0", B: "web:1.1"},
+			},
+		},
+		{
+			name: "unnamed lists are compared by index",
+			a:    map[string]interface{}{"args": []interface{}{"--port=80"}},
+			b:    map[string]interface{}{"args": []interface{}{"--port=8080", "--verbose"}},
+			want: []FieldDifference{
+				{Path: "args[0]", Change: "changed", A: "--port=80", B: "--port=8080"},
+				{Path: "args[1]", Change: "added", B: "--verbose"},
+			},
+		},
+		{
+			name: "keys with dots are quoted",
+			a:    map[string]interface{}{"labels": map[string]interface{}{"app.kubernetes.io/version": "1.0"}},
+			b:    map[string]interface{}{"labels": map[string]interface{}{"app.kubernetes.io/version": "1.1"}},
+			want: []FieldDifference{
+				{Path: `labels["app.kubernetes.io/version"]`, Change: "changed", A: "1.0", B: "1.1"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := diffValues("", tt.a, tt.b); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestDiffResourceAcrossContexts_FakeCluster(t *testing.T) {
+	t.Parallel()
+
+	deployment := func(uid, image string, replicas int32) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "web", Namespace: "shop", UID: types.UID("uid-" + uid), ResourceVersion: uid,
+				Annotations: map[string]string{"deployment.kubernetes.io/revision": uid},
+			},
+			Spec: appsv1.DeploymentSpec{
+				Replicas: &replicas,
+				Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "app", Image: image}},
+				}},
+			},
+			Status: appsv1.DeploymentStatus{ReadyReplicas: replicas},
+		}
+	}
+
+	staging := fakecluster.New(fakecluster.Config{Objects: []runtime.Object{
+		deployment("1", "web:1.1", 1),
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "only-staging", Namespace: "shop"}},
+	}})
+	production := fakecluster.New(fakecluster.Config{Objects: []runtime.Object{
+		deployment("2", "web:1.0", 3),
+	}})
+
+	handler := NewResourceHandler(contextClients{
+		ClusterReader: staging,
+		contexts:      map[string]kubernetes.ClusterReader{"production": production},
+	}, nil, false)
+
+	result, isErr := callTool(t, handler.DiffResourceAcrossContexts, map[string]any{
+		"resource_type": "deployments",
+		"namespace":     "shop",
+		"name":          "web",
+		"context_b":     "production",
+	})
+	if isErr {
+		t.Fatalf("unexpected error: %v", result["error"])
+	}
+
+	var differences []FieldDifference
+	decodeInto(t, result["differences"], &differences)
+
+	var paths []string
+	for _, difference := range differences {
+		paths = append(paths, difference.Path)
+	}
+	want := []string{"spec.replicas", "spec.template.spec.containers[name=app].image"}
+	if !reflect.DeepEqual(paths, want) {
+		t.Errorf("expected differences at %v, got %+v", want, differences)
+	}
+	if result["identical"] != false {
+		t.Errorf("expected identical=false, got %v", result["identical"])
+	}
+
+	result, isErr = callTool(t, handler.DiffResourceAcrossContexts, map[string]any{
+		"resource_type": "configmaps",
+		"namespace":     "shop",
+		"name":          "only-staging",
+		"context_b":     "production",
+	})
+	if isErr {
+		t.Fatalf("unexpected error: %v", result["error"])
+	}
+	if result["exists_in_a"] != true || result["exists_in_b"] != false {
+		t.Errorf("expected the configmap only in context a, got %v", result)
+	}
+
+	if _, isErr := callTool(t, handler.DiffResourceAcrossContexts, map[string]any{
+		"resource_type": "deployments",
+		"namespace":     "shop",
+		"name":          "web",
+		"context_b":     "missing",
+	}); !isErr {
+		t.Error("expected an error for an unknown context")
+	}
+}
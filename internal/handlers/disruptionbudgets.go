@@ -0,0 +1,309 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/connectivity"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/response"
+)
+
+// ListPodDisruptionBudgetsParams defines the parameters for the
+// list_pod_disruption_budgets MCP tool.
+type ListPodDisruptionBudgetsParams struct {
+	// Namespace restricts the report to a namespace.
+	Namespace string `json:"namespace,omitempty" description:"Namespace to list PodDisruptionBudgets from (leave empty for all namespaces)"`
+
+	// OnlyProblems drops the budgets without findings.
+	OnlyProblems bool `json:"only_problems,omitempty" description:"When true, returns only PodDisruptionBudgets with findings, such as those that currently block node drains"`
+
+	// Context specifies which Kubernetes context to use for this operation.
+	Context string `json:"context,omitempty" description:"Kubernetes context to use (defaults to current context from kubeconfig)"`
+}
+
+// PodDisruptionBudgetInfo is a PodDisruptionBudget with its status and the
+// workloads whose pods it protects.
+type PodDisruptionBudgetInfo struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+
+	// MinAvailable and MaxUnavailable are rendered as set, either as a count
+	// or a percentage; only one of them is set.
+	MinAvailable   string `json:"min_available,omitempty"`
+	MaxUnavailable string `json:"max_unavailable,omitempty"`
+
+	// Selector is empty both for a missing selector, which matches no pods,
+	// and an empty one, which matches every pod; findings tell them apart.
+	Selector string `json:"selector,omitempty"`
+
+	// UnhealthyPodEvictionPolicy defaults to IfHealthyBudget, which only
+	// allows evicting pods that are not ready while the budget is met.
+	UnhealthyPodEvictionPolicy string `json:"unhealthy_pod_eviction_policy"`
+
+	// The counts come from the status kept by the disruption controller.
+	ExpectedPods       int32 `json:"expected_pods"`
+	CurrentHealthy     int32 `json:"current_healthy"`
+	DesiredHealthy     int32 `json:"desired_healthy"`
+	DisruptionsAllowed int32 `json:"disruptions_allowed"`
+
+	// BlocksDrains is true when no pod it covers can be evicted right now.
+	// Draining any of BlockedNodes waits until that changes.
+	BlocksDrains bool     `json:"blocks_drains"`
+	BlockedNodes []string `json:"blocked_nodes,omitempty"`
+
+	// MatchedPods and Workloads are only reported when pods could be listed.
+	MatchedPods *int     `json:"matched_pods,omitempty"`
+	Workloads   []string `json:"workloads,omitempty"`
+	Findings    []string `json:"findings,omitempty"`
+}
+
+// ListPodDisruptionBudgetsResult is the result of the
+// list_pod_disruption_budgets MCP tool.
+type ListPodDisruptionBudgetsResult struct {
+	Namespace            string                    `json:"namespace"`
+	PodDisruptionBudgets []PodDisruptionBudgetInfo `json:"pod_disruption_budgets"`
+
+	// Count is the number of budgets found, including those left out when
+	// only problems were requested.
+	Count               int      `json:"count"`
+	BlockingDrains      int      `json:"blocking_drains"`
+	BudgetsWithFindings int      `json:"budgets_with_findings"`
+	Warnings            []string `json:"warnings,omitempty"`
+}
+
+// ListPodDisruptionBudgets implements the list_pod_disruption_budgets MCP
+// tool. It reports each PodDisruptionBudget with the healthy counts and
+// allowed disruptions from its status, matches its selector against the pods
+// of its namespace to name the workloads it protects, and flags budgets that
+// currently block node drains, match no pods, or overlap with another budget.
+// Matching pods is best effort and reports listing failures under warnings.
+func (h *WorkloadHandler) ListPodDisruptionBudgets(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var params ListPodDisruptionBudgetsParams
+	if err := bindParams(request, &params); err != nil {
+		return response.Errorf("failed to parse arguments: %s", err)
+	}
+
+	// Use the appropriate client based on context
+	client, err := h.client.ForContext(ctx, params.Context)
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to create client with context %s: %v", params.Context, err)
+	}
+
+	list, err := client.ListPodDisruptionBudgets(ctx, params.Namespace, metav1.ListOptions{})
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to list pod disruption budgets: %v", err)
+	}
+
+	var warnings []string
+
+	var pods []corev1.Pod
+	podsListed := false
+	if len(list.Items) > 0 {
+		if podList, err := client.ListPods(ctx, params.Namespace, metav1.ListOptions{}); err != nil {
+			if h.alwaysStart && connectivity.IsTransportError(err) {
+				return response.Error(connectivity.ErrorMessage(err))
+			}
+			warnings = append(warnings, fmt.Sprintf("failed to list pods, budgets were not matched to workloads: %v", err))
+		} else {
+			pods = podList.Items
+			podsListed = true
+		}
+	}
+
+	budgets := podDisruptionBudgets(list.Items, pods, podsListed)
+
+	blocking := 0
+	withFindings := 0
+	for i := range budgets {
+		if budgets[i].BlocksDrains {
+			blocking++
+		}
+		if len(budgets[i].Findings) > 0 {
+			withFindings++
+		}
+	}
+
+	count := len(budgets)
+	if params.OnlyProblems {
+		filtered := make([]PodDisruptionBudgetInfo, 0, withFindings)
+		for i := range budgets {
+			if len(budgets[i].Findings) > 0 {
+				filtered = append(filtered, budgets[i])
+			}
+		}
+		budgets = filtered
+	}
+
+	return response.JSON(ListPodDisruptionBudgetsResult{
+		Namespace:            params.Namespace,
+		PodDisruptionBudgets: budgets,
+		Count:                count,
+		BlockingDrains:       blocking,
+		BudgetsWithFindings:  withFindings,
+		Warnings:             warnings,
+	})
+}
+
+// podDisruptionBudgets builds the report for every budget, matching them
+// against pods when podsListed is true. Budgets that block drains come
+// first, then those with other findings.
+func podDisruptionBudgets(items []policyv1.PodDisruptionBudget, pods []corev1.Pod, podsListed bool) []PodDisruptionBudgetInfo {
+	// matched holds the pods each budget selects, by index into items, and
+	// coveredBy the budgets selecting each pod, to find overlaps.
+	matched := make([][]*corev1.Pod, len(items))
+	coveredBy := make(map[string][]string)
+	if podsListed {
+		for i := range items {
+			budget := &items[i]
+			selector, err := metav1.LabelSelectorAsSelector(budget.Spec.Selector)
+			if err != nil {
+				continue
+			}
+			for j := range pods {
+				pod := &pods[j]
+				// Finished pods are ignored by the disruption controller
+				// and can always be evicted.
+				if pod.Namespace != budget.Namespace || pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+					continue
+				}
+				if !selector.Matches(labels.Set(pod.Labels)) {
+					continue
+				}
+				matched[i] = append(matched[i], pod)
+				key := pod.Namespace + "/" + pod.Name
+				coveredBy[key] = append(coveredBy[key], budget.Name)
+			}
+		}
+	}
+
+	budgets := make([]PodDisruptionBudgetInfo, 0, len(items))
+	for i := range items {
+		var overlaps []string
+		for _, pod := range matched[i] {
+			for _, other := range coveredBy[pod.Namespace+"/"+pod.Name] {
+				if other != items[i].Name && !containsString(overlaps, other) {
+					overlaps = append(overlaps, other)
+				}
+			}
+		}
+		sort.Strings(overlaps)
+
+		var podsMatched []*corev1.Pod
+		if podsListed {
+			podsMatched = matched[i]
+			if podsMatched == nil {
+				podsMatched = []*corev1.Pod{}
+			}
+		}
+		budgets = append(budgets, podDisruptionBudgetInfo(&items[i], podsMatched, overlaps))
+	}
+
+	sort.SliceStable(budgets, func(i, j int) bool {
+		a, b := &budgets[i], &budgets[j]
+		if a.BlocksDrains != b.BlocksDrains {
+			return a.BlocksDrains
+		}
+		return findingsFirst(len(a.Findings), len(b.Findings), a.Namespace+"/"+a.Name, b.Namespace+"/"+b.Name)
+	})
+
+	return budgets
+}
+
+// podDisruptionBudgetInfo reports a single budget. pods are the running pods
+// it selects, or nil when pods could not be listed; overlaps names the other
+// budgets that select any of them.
+func podDisruptionBudgetInfo(budget *policyv1.PodDisruptionBudget, pods []*corev1.Pod, overlaps []string) PodDisruptionBudgetInfo {
+	status := budget.Status
+	entry := PodDisruptionBudgetInfo{
+		Namespace:                  budget.Namespace,
+		Name:                       budget.Name,
+		Selector:                   webhookSelector(budget.Spec.Selector),
+		UnhealthyPodEvictionPolicy: string(policyv1.IfHealthyBudget),
+		ExpectedPods:               status.ExpectedPods,
+		CurrentHealthy:             status.CurrentHealthy,
+		DesiredHealthy:             status.DesiredHealthy,
+		DisruptionsAllowed:         status.DisruptionsAllowed,
+	}
+
+	if budget.Spec.MinAvailable != nil {
+		entry.MinAvailable = budget.Spec.MinAvailable.String()
+	}
+	if budget.Spec.MaxUnavailable != nil {
+		entry.MaxUnavailable = budget.Spec.MaxUnavailable.String()
+	}
+	if policy := budget.Spec.UnhealthyPodEvictionPolicy; policy != nil {
+		entry.UnhealthyPodEvictionPolicy = string(*policy)
+	}
+
+	if pods != nil {
+		matchedPods := len(pods)
+		entry.MatchedPods = &matchedPods
+
+		var nodes []string
+		for _, pod := range pods {
+			if workload := podWorkload(pod); workload != "" && !containsString(entry.Workloads, workload) {
+				entry.Workloads = append(entry.Workloads, workload)
+			}
+			if pod.Spec.NodeName != "" && !containsString(nodes, pod.Spec.NodeName) {
+				nodes = append(nodes, pod.Spec.NodeName)
+			}
+		}
+		sort.Strings(entry.Workloads)
+		sort.Strings(nodes)
+
+		entry.BlockedNodes = nodes
+	}
+
+	if budget.Status.ObservedGeneration < budget.Generation {
+		entry.Findings = append(entry.Findings, "the disruption controller has not observed the latest spec yet, so the status below may be stale")
+	}
+
+	entry.BlocksDrains = status.DisruptionsAllowed == 0 && status.ExpectedPods > 0
+	if entry.BlocksDrains {
+		nodes := "the nodes running its pods"
+		if len(entry.BlockedNodes) > 0 {
+			nodes = strings.Join(truncate(entry.BlockedNodes, 10), ", ")
+		}
+
+		if status.CurrentHealthy < status.DesiredHealthy {
+			finding := fmt.Sprintf("blocks node drains: only %d of the %d required pods are healthy, so evictions are refused and draining %s waits until more pods become ready", status.CurrentHealthy, status.DesiredHealthy, nodes)
+			if entry.UnhealthyPodEvictionPolicy != string(policyv1.AlwaysAllow) {
+				finding += "; with unhealthyPodEvictionPolicy AlwaysAllow the pods that are not ready could still be evicted"
+			}
+			entry.Findings = append(entry.Findings, finding)
+		} else {
+			entry.Findings = append(entry.Findings, fmt.Sprintf("blocks node drains even with every pod healthy: it requires %d of %d pods to stay healthy, so draining %s never finishes; lower minAvailable, raise maxUnavailable, or add replicas", status.DesiredHealthy, status.ExpectedPods, nodes))
+		}
+	} else {
+		entry.BlockedNodes = nil
+	}
+
+	switch {
+	case budget.Spec.Selector == nil:
+		entry.Findings = append(entry.Findings, "has no selector, so it matches no pods and protects nothing")
+	case entry.Selector == "":
+		entry.Findings = append(entry.Findings, "has an empty selector, which matches every pod in the namespace")
+	case pods != nil && len(pods) == 0:
+		entry.Findings = append(entry.Findings, "matches no running pods, so it protects nothing; check the selector against the labels of the workload's pod template")
+	}
+
+	if len(overlaps) > 0 {
+		entry.Findings = append(entry.Findings, fmt.Sprintf("selects pods that are also selected by %s; the eviction API refuses to evict pods covered by more than one PodDisruptionBudget, so drains fail for them", strings.Join(overlaps, ", ")))
+	}
+
+	return entry
+}
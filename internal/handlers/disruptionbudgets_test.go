@@ -0,0 +1,190 @@
+package handlers
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/fakecluster"
+)
+
+// disruptionBudget builds a PodDisruptionBudget in the shop namespace that
+// selects pods labeled app=name.
+func disruptionBudget(name string, minAvailable intstr.IntOrString, status policyv1.PodDisruptionBudgetStatus) *policyv1.PodDisruptionBudget {
+	return &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "shop"},
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			MinAvailable: &minAvailable,
+			Selector:     &metav1.LabelSelector{MatchLabels: map[string]string{"app": name}},
+		},
+		Status: status,
+	}
+}
+
+// disruptionBudgetPod builds a running pod of a Deployment's ReplicaSet,
+// scheduled on node.
+func disruptionBudgetPod(name, app, node string) *corev1.Pod {
+	controller := true
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "shop",
+			Labels:    map[string]string{"app": app, "pod-template-hash": "5d4f8"},
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: "apps/v1", Kind: "ReplicaSet", Name: app + "-5d4f8", Controller: &controller,
+			}},
+		},
+		Spec:   corev1.PodSpec{NodeName: node},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+}
+
+func TestPodDisruptionBudgetInfo(t *testing.T) {
+	t.Parallel()
+
+	alwaysAllow := policyv1.AlwaysAllow
+
+	tests := []struct {
+		name       string
+		budget     *policyv1.PodDisruptionBudget
+		pods       []*corev1.Pod
+		overlaps   []string
+		wantBlocks bool
+		want       []string
+	}{
+		{
+			name:   "allows disruptions",
+			budget: disruptionBudget("web", intstr.FromInt32(2), policyv1.PodDisruptionBudgetStatus{ExpectedPods: 3, CurrentHealthy: 3, DesiredHealthy: 2, DisruptionsAllowed: 1}),
+			pods:   []*corev1.Pod{disruptionBudgetPod("web-1", "web", "node-a")},
+		},
+		{
+			name:       "unhealthy pods",
+			budget:     disruptionBudget("web", intstr.FromInt32(2), policyv1.PodDisruptionBudgetStatus{ExpectedPods: 3, CurrentHealthy: 1, DesiredHealthy: 2}),
+			pods:       []*corev1.Pod{disruptionBudgetPod("web-1", "web", "node-b"), disruptionBudgetPod("web-2", "web", "node-a")},
+			wantBlocks: true,
+			want:       []string{"blocks node drains: only 1 of the 2 required pods are healthy, so evictions are refused and draining node-a, node-b waits until more pods become ready; with unhealthyPodEvictionPolicy AlwaysAllow the pods that are not ready could still be evicted"},
+		},
+		{
+			name: "unhealthy pods with always allow",
+			budget: func() *policyv1.PodDisruptionBudget {
+				budget := disruptionBudget("web", intstr.FromInt32(2), policyv1.PodDisruptionBudgetStatus{ExpectedPods: 3, CurrentHealthy: 1, DesiredHealthy: 2})
+				budget.Spec.UnhealthyPodEvictionPolicy = &alwaysAllow
+				return budget
+			}(),
+			wantBlocks: true,
+			want:       []string{"blocks node drains: only 1 of the 2 required pods are healthy, so evictions are refused and draining the nodes running its pods waits until more pods become ready"},
+		},
+		{
+			name:       "budget equals replicas",
+			budget:     disruptionBudget("db", intstr.FromString("100%"), policyv1.PodDisruptionBudgetStatus{ExpectedPods: 1, CurrentHealthy: 1, DesiredHealthy: 1}),
+			pods:       []*corev1.Pod{disruptionBudgetPod("db-1", "db", "node-a")},
+			wantBlocks: true,
+			want:       []string{"blocks node drains even with every pod healthy: it requires 1 of 1 pods to stay healthy, so draining node-a never finishes; lower minAvailable, raise maxUnavailable, or add replicas"},
+		},
+		{
+			name:   "matches no pods",
+			budget: disruptionBudget("cache", intstr.FromInt32(1), policyv1.PodDisruptionBudgetStatus{}),
+			pods:   []*corev1.Pod{},
+			want:   []string{"matches no running pods, so it protects nothing; check the selector against the labels of the workload's pod template"},
+		},
+		{
+			name: "empty selector",
+			budget: func() *policyv1.PodDisruptionBudget {
+				budget := disruptionBudget("everything", intstr.FromInt32(1), policyv1.PodDisruptionBudgetStatus{ExpectedPods: 3, CurrentHealthy: 3, DesiredHealthy: 1, DisruptionsAllowed: 2})
+				budget.Spec.Selector = &metav1.LabelSelector{}
+				return budget
+			}(),
+			pods:     []*corev1.Pod{disruptionBudgetPod("web-1", "web", "node-a")},
+			overlaps: []string{"web"},
+			want: []string{
+				"has an empty selector, which matches every pod in the namespace",
+				"selects pods that are also selected by web; the eviction API refuses to evict pods covered by more than one PodDisruptionBudget, so drains fail for them",
+			},
+		},
+		{
+			name: "stale status",
+			budget: func() *policyv1.PodDisruptionBudget {
+				budget := disruptionBudget("web", intstr.FromInt32(1), policyv1.PodDisruptionBudgetStatus{ObservedGeneration: 1, ExpectedPods: 2, CurrentHealthy: 2, DesiredHealthy: 1, DisruptionsAllowed: 1})
+				budget.Generation = 2
+				return budget
+			}(),
+			want: []string{"the disruption controller has not observed the latest spec yet, so the status below may be stale"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := podDisruptionBudgetInfo(tt.budget, tt.pods, tt.overlaps)
+			if got.BlocksDrains != tt.wantBlocks {
+				t.Errorf("expected blocks_drains=%t, got %t", tt.wantBlocks, got.BlocksDrains)
+			}
+			if !reflect.DeepEqual(got.Findings, tt.want) {
+				t.Errorf("expected findings %q, got %q", tt.want, got.Findings)
+			}
+		})
+	}
+}
+
+func TestListPodDisruptionBudgets_FakeCluster(t *testing.T) {
+	t.Parallel()
+
+	finished := disruptionBudgetPod("db-migrate", "db", "node-b")
+	finished.Status.Phase = corev1.PodSucceeded
+
+	handler := NewWorkloadHandler(fakecluster.New(fakecluster.Config{
+		Objects: []runtime.Object{
+			disruptionBudget("web", intstr.FromInt32(1), policyv1.PodDisruptionBudgetStatus{ExpectedPods: 2, CurrentHealthy: 2, DesiredHealthy: 1, DisruptionsAllowed: 1}),
+			disruptionBudget("db", intstr.FromInt32(1), policyv1.PodDisruptionBudgetStatus{ExpectedPods: 1, CurrentHealthy: 1, DesiredHealthy: 1}),
+			disruptionBudgetPod("web-1", "web", "node-a"),
+			disruptionBudgetPod("web-2", "web", "node-b"),
+			disruptionBudgetPod("db-1", "db", "node-a"),
+			finished,
+		},
+	}), false)
+
+	result, isErr := callTool(t, handler.ListPodDisruptionBudgets, map[string]any{"namespace": "shop"})
+	if isErr {
+		t.Fatalf("unexpected error: %v", result["error"])
+	}
+
+	var budgets []PodDisruptionBudgetInfo
+	decodeInto(t, result["pod_disruption_budgets"], &budgets)
+	if len(budgets) != 2 {
+		t.Fatalf("expected 2 budgets, got %+v", budgets)
+	}
+
+	blocking := budgets[0]
+	if blocking.Name != "db" || !blocking.BlocksDrains || !reflect.DeepEqual(blocking.BlockedNodes, []string{"node-a"}) {
+		t.Errorf("expected the db budget first, blocking node-a, got %+v", blocking)
+	}
+	if blocking.MatchedPods == nil || *blocking.MatchedPods != 1 || !reflect.DeepEqual(blocking.Workloads, []string{"Deployment/db"}) {
+		t.Errorf("expected the finished pod to be ignored, got %+v", blocking)
+	}
+
+	healthy := budgets[1]
+	if healthy.MinAvailable != "1" || healthy.Selector != "app=web" || healthy.UnhealthyPodEvictionPolicy != "IfHealthyBudget" || len(healthy.Findings) != 0 {
+		t.Errorf("unexpected web budget %+v", healthy)
+	}
+
+	if result["blocking_drains"] != float64(1) || result["count"] != float64(2) {
+		t.Errorf("unexpected totals %+v", result)
+	}
+
+	result, isErr = callTool(t, handler.ListPodDisruptionBudgets, map[string]any{"namespace": "shop", "only_problems": true})
+	if isErr {
+		t.Fatalf("unexpected error: %v", result["error"])
+	}
+
+	decodeInto(t, result["pod_disruption_budgets"], &budgets)
+	if len(budgets) != 1 || budgets[0].Name != "db" {
+		t.Errorf("expected only the blocking budget, got %+v", budgets)
+	}
+}
@@ -0,0 +1,454 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/connectivity"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/resourcefilter"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/response"
+)
+
+const (
+	// dnsNamespace is where cluster DNS runs on virtually every distribution.
+	dnsNamespace = "kube-system"
+
+	// dnsSelector selects the cluster DNS Service and Deployment. CoreDNS
+	// keeps the label of the kube-dns addon it replaced.
+	dnsSelector = "k8s-app=kube-dns"
+
+	// nodeLocalDNSSelector selects the NodeLocal DNSCache DaemonSet.
+	nodeLocalDNSSelector = "k8s-app=node-local-dns"
+
+	// defaultClusterDomain is used when the Corefile does not name one.
+	defaultClusterDomain = "cluster.local"
+)
+
+// dnsConfigMapNames are the ConfigMaps in dnsNamespace that configure
+// cluster DNS: the CoreDNS Corefile, custom server blocks on AKS, and the
+// stub domains of kube-dns.
+var dnsConfigMapNames = []string{"coredns", "coredns-custom", "kube-dns"}
+
+var dnsConfigMapsGVR = schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+
+// GetDNSConfigParams defines the parameters for the get_dns_config MCP tool.
+type GetDNSConfigParams struct {
+	// Namespace is the namespace of Pod.
+	Namespace string `json:"namespace,omitempty" description:"Namespace of the pod to include (defaults to the namespace of the current context)"`
+
+	// Pod is a pod whose DNS settings are included.
+	Pod string `json:"pod,omitempty" description:"Name of a pod whose DNS policy, dnsConfig, and expected resolv.conf to include"`
+
+	// Context specifies which Kubernetes context to use for this operation.
+	Context string `json:"context,omitempty" description:"Kubernetes context to use (defaults to current context from kubeconfig)"`
+}
+
+// DNSServerBlock is a server block of a Corefile.
+type DNSServerBlock struct {
+	// Zones are the zones the block serves, with their port, such as ".:53".
+	Zones   []string `json:"zones"`
+	Plugins []string `json:"plugins"`
+
+	// Forward are the upstream resolvers of the forward plugin.
+	Forward []string `json:"forward,omitempty"`
+}
+
+// DNSWorkload is a Deployment or DaemonSet running cluster DNS.
+type DNSWorkload struct {
+	Kind    string   `json:"kind"`
+	Name    string   `json:"name"`
+	Images  []string `json:"images"`
+	Desired int32    `json:"desired"`
+	Ready   int32    `json:"ready"`
+}
+
+// PodDNSSettings are the DNS settings of a pod and the resolv.conf they
+// produce.
+type PodDNSSettings struct {
+	Namespace   string        `json:"namespace"`
+	Name        string        `json:"name"`
+	DNSPolicy   string        `json:"dns_policy"`
+	HostNetwork bool          `json:"host_network,omitempty"`
+	DNSConfig   *PodDNSConfig `json:"dns_config,omitempty"`
+	HostAliases []string      `json:"host_aliases,omitempty"`
+
+	// ResolvConf is the resolv.conf the kubelet writes for the pod, as far
+	// as it can be derived from the API.
+	ResolvConf []string `json:"resolv_conf"`
+	Findings   []string `json:"findings"`
+}
+
+// DNSService is the Service clients send DNS queries to.
+type DNSService struct {
+	Namespace string               `json:"namespace"`
+	Name      string               `json:"name"`
+	ClusterIP string               `json:"cluster_ip"`
+	Ports     []ServicePortMapping `json:"ports"`
+}
+
+// GetDNSConfigResult is the result of the get_dns_config MCP tool.
+type GetDNSConfigResult struct {
+	DNSService *DNSService   `json:"dns_service,omitempty"`
+	Workloads  []DNSWorkload `json:"workloads"`
+
+	// ConfigMaps holds the data of the DNS ConfigMaps, by name.
+	ConfigMaps    map[string]map[string]string `json:"config_maps"`
+	Servers       []DNSServerBlock             `json:"servers"`
+	ClusterDomain string                       `json:"cluster_domain"`
+
+	// Pod holds the DNS settings of the requested pod.
+	Pod *PodDNSSettings `json:"pod,omitempty"`
+
+	Findings []string `json:"findings"`
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// GetDNSConfig implements the get_dns_config MCP tool.
+// It gathers the cluster DNS setup in one place: the DNS Service and its
+// cluster IP, the CoreDNS or kube-dns Deployments and their readiness,
+// NodeLocal DNSCache, and the DNS ConfigMaps with the server blocks of the
+// Corefile. With a pod, it also reports the pod's DNS policy and dnsConfig
+// and the resolv.conf they produce. Every part is read on a best-effort basis,
+// and ConfigMaps follow the resource filter.
+func (h *ResourceHandler) GetDNSConfig(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var params GetDNSConfigParams
+	if err := bindParams(request, &params); err != nil {
+		return response.Errorf("failed to parse arguments: %s", err)
+	}
+
+	// Use the appropriate client based on context
+	client, err := h.client.ForContext(ctx, params.Context)
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to create client with context %s: %v", params.Context, err)
+	}
+
+	var warnings, findings []string
+
+	// failed records a best-effort read that failed, and reports whether
+	// the error must be returned as is.
+	failed := func(what string, err error) bool {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return true
+		}
+		warnings = append(warnings, fmt.Sprintf("failed to read %s: %v", what, err))
+		return false
+	}
+
+	var result GetDNSConfigResult
+
+	var dnsIP string
+	if services, err := client.ListServices(ctx, dnsNamespace, metav1.ListOptions{LabelSelector: dnsSelector}); err != nil {
+		if failed("the DNS service", err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+	} else if len(services.Items) == 0 {
+		findings = append(findings, fmt.Sprintf("no Service labeled %s in namespace %s; cluster DNS may use a different label or not be installed", dnsSelector, dnsNamespace))
+	} else {
+		service := services.Items[0]
+		dnsIP = service.Spec.ClusterIP
+		result.DNSService = &DNSService{
+			Namespace: service.Namespace,
+			Name:      service.Name,
+			ClusterIP: service.Spec.ClusterIP,
+			Ports:     servicePortMappings(service.Spec.Ports),
+		}
+	}
+
+	workloads := make([]DNSWorkload, 0)
+	if deployments, err := client.ListDeployments(ctx, dnsNamespace, metav1.ListOptions{LabelSelector: dnsSelector}); err != nil {
+		if failed("the DNS deployments", err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+	} else {
+		for i := range deployments.Items {
+			deployment := &deployments.Items[i]
+			desired := int32(1)
+			if deployment.Spec.Replicas != nil {
+				desired = *deployment.Spec.Replicas
+			}
+			workloads = append(workloads, DNSWorkload{
+				Kind:    "Deployment",
+				Name:    deployment.Name,
+				Images:  containerImages(deployment.Spec.Template.Spec.Containers),
+				Desired: desired,
+				Ready:   deployment.Status.ReadyReplicas,
+			})
+		}
+	}
+
+	if daemonSets, err := client.ListDaemonSets(ctx, dnsNamespace, metav1.ListOptions{LabelSelector: nodeLocalDNSSelector}); err != nil {
+		if failed("the NodeLocal DNSCache daemonset", err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+	} else {
+		for i := range daemonSets.Items {
+			daemonSet := &daemonSets.Items[i]
+			workloads = append(workloads, DNSWorkload{
+				Kind:    "DaemonSet",
+				Name:    daemonSet.Name,
+				Images:  containerImages(daemonSet.Spec.Template.Spec.Containers),
+				Desired: daemonSet.Status.DesiredNumberScheduled,
+				Ready:   daemonSet.Status.NumberReady,
+			})
+		}
+		if len(daemonSets.Items) > 0 {
+			findings = append(findings, "NodeLocal DNSCache is installed, so pods may query a node-local address instead of the DNS Service IP")
+		}
+	}
+
+	for _, workload := range workloads {
+		switch {
+		case workload.Ready == 0 && workload.Desired > 0:
+			findings = append(findings, fmt.Sprintf("%s %s has no ready replicas, so DNS lookups served by it fail", workload.Kind, workload.Name))
+		case workload.Ready < workload.Desired:
+			findings = append(findings, fmt.Sprintf("%s %s has %d of %d replicas ready", workload.Kind, workload.Name, workload.Ready, workload.Desired))
+		}
+	}
+	result.Workloads = workloads
+
+	clusterDomain := ""
+	configMaps := make(map[string]map[string]string)
+	servers := make([]DNSServerBlock, 0)
+	if h.resourceFilter != nil && h.resourceFilter.IsDisabled(dnsConfigMapsGVR) {
+		warnings = append(warnings, fmt.Sprintf("%s are disabled by configuration, so the DNS configuration was not read", resourcefilter.FormatGVR(dnsConfigMapsGVR)))
+	} else {
+		for _, name := range dnsConfigMapNames {
+			obj, err := getResourceFrom(ctx, client, dnsConfigMapsGVR, dnsNamespace, name)
+			if err != nil {
+				if failed("ConfigMap "+name, err) {
+					return response.Error(connectivity.ErrorMessage(err))
+				}
+				continue
+			}
+			if obj == nil {
+				continue
+			}
+
+			data := nestedStringMap(obj.Object, "data")
+			configMaps[name] = data
+
+			keys := make([]string, 0, len(data))
+			for key := range data {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+
+			// Corefile is the main configuration; *.server files add server
+			// blocks in coredns-custom.
+			for _, key := range keys {
+				if key != "Corefile" && !strings.HasSuffix(key, ".server") {
+					continue
+				}
+				servers = append(servers, parseCorefile(data[key])...)
+				if domain := corefileClusterDomain(data[key]); domain != "" && clusterDomain == "" {
+					clusterDomain = domain
+				}
+			}
+		}
+		if len(configMaps) == 0 {
+			findings = append(findings, fmt.Sprintf("none of the ConfigMaps %s exist in namespace %s", strings.Join(dnsConfigMapNames, ", "), dnsNamespace))
+		}
+	}
+
+	for _, server := range servers {
+		for _, upstream := range server.Forward {
+			if upstream == "/etc/resolv.conf" {
+				findings = append(findings, fmt.Sprintf("server block %s forwards to /etc/resolv.conf, so external names resolve through the resolvers of the nodes running CoreDNS", strings.Join(server.Zones, " ")))
+			}
+		}
+	}
+
+	if clusterDomain == "" {
+		clusterDomain = defaultClusterDomain
+	}
+
+	result.ConfigMaps = configMaps
+	result.Servers = servers
+	result.ClusterDomain = clusterDomain
+
+	if params.Pod != "" {
+		pod, err := client.GetPod(ctx, params.Namespace, params.Pod)
+		if err != nil {
+			if h.alwaysStart && connectivity.IsTransportError(err) {
+				return response.Error(connectivity.ErrorMessage(err))
+			}
+			return response.Errorf("failed to get pod: %v", err)
+		}
+		settings := podDNSSettings(pod, dnsIP, clusterDomain)
+		result.Pod = &settings
+	}
+
+	if findings == nil {
+		findings = []string{}
+	}
+	result.Findings = findings
+	result.Warnings = warnings
+
+	return response.JSON(result)
+}
+
+// containerImages lists the images of containers, in order.
+func containerImages(containers []corev1.Container) []string {
+	images := make([]string, 0, len(containers))
+	for _, container := range containers {
+		images = append(images, container.Image)
+	}
+	return images
+}
+
+// parseCorefile returns the server blocks of a Corefile with the plugins
+// each one enables. Snippets, imports, and nested plugin blocks are not
+// expanded.
+func parseCorefile(corefile string) []DNSServerBlock {
+	var blocks []DNSServerBlock
+	var current *DNSServerBlock
+	depth := 0
+
+	scanner := bufio.NewScanner(strings.NewReader(corefile))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		opens := strings.Count(line, "{")
+		closes := strings.Count(line, "}")
+
+		switch {
+		case depth == 0 && opens > 0:
+			zones := fields[:len(fields)-1]
+			if last := fields[len(fields)-1]; last != "{" {
+				zones = append(zones, strings.TrimSuffix(last, "{"))
+			}
+			blocks = append(blocks, DNSServerBlock{Zones: zones, Plugins: []string{}})
+			current = &blocks[len(blocks)-1]
+		case depth == 1 && current != nil && fields[0] != "}":
+			plugin := strings.TrimSuffix(fields[0], "{")
+			current.Plugins = append(current.Plugins, plugin)
+			if plugin == "forward" && len(fields) > 2 {
+				for _, upstream := range fields[2:] {
+					if upstream == "{" {
+						break
+					}
+					current.Forward = append(current.Forward, upstream)
+				}
+			}
+		}
+
+		depth += opens - closes
+		if depth <= 0 {
+			depth = 0
+			current = nil
+		}
+	}
+
+	return blocks
+}
+
+// corefileClusterDomain returns the first zone of the kubernetes plugin in a
+// Corefile, which is the cluster domain.
+func corefileClusterDomain(corefile string) string {
+	scanner := bufio.NewScanner(strings.NewReader(corefile))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) > 1 && fields[0] == "kubernetes" && fields[1] != "{" {
+			return strings.TrimSuffix(fields[1], ".")
+		}
+	}
+	return ""
+}
+
+// podDNSSettings describes the DNS settings of a pod and the resolv.conf the
+// kubelet writes for it. Settings inherited from the node cannot be read
+// from the API and are marked as such.
+func podDNSSettings(pod *corev1.Pod, dnsIP, clusterDomain string) PodDNSSettings {
+	override, _ := podDNSOverride(pod)
+
+	settings := PodDNSSettings{
+		Namespace:   pod.Namespace,
+		Name:        pod.Name,
+		DNSPolicy:   override.DNSPolicy,
+		HostNetwork: override.HostNetwork,
+		DNSConfig:   override.DNSConfig,
+		HostAliases: override.HostAliases,
+		ResolvConf:  []string{},
+		Findings:    override.Findings,
+	}
+
+	var nameservers, searches []string
+	options := make(map[string]string)
+	var optionOrder []string
+	setOption := func(name, value string) {
+		if _, ok := options[name]; !ok {
+			optionOrder = append(optionOrder, name)
+		}
+		options[name] = value
+	}
+
+	policy := corev1.DNSPolicy(override.DNSPolicy)
+	clusterFirst := policy == corev1.DNSClusterFirstWithHostNet || (policy == corev1.DNSClusterFirst && !pod.Spec.HostNetwork)
+
+	switch {
+	case clusterFirst:
+		if dnsIP != "" {
+			nameservers = append(nameservers, dnsIP)
+		} else {
+			nameservers = append(nameservers, "<cluster DNS IP>")
+		}
+		searches = append(searches, pod.Namespace+".svc."+clusterDomain, "svc."+clusterDomain, clusterDomain)
+		setOption("ndots", "5")
+	case policy != corev1.DNSNone:
+		settings.ResolvConf = append(settings.ResolvConf, "# nameservers, search domains, and options inherited from the node")
+	}
+
+	if config := pod.Spec.DNSConfig; config != nil {
+		nameservers = append(nameservers, config.Nameservers...)
+		searches = append(searches, config.Searches...)
+		for _, option := range config.Options {
+			value := ""
+			if option.Value != nil {
+				value = *option.Value
+			}
+			setOption(option.Name, value)
+		}
+	}
+
+	for _, nameserver := range nameservers {
+		settings.ResolvConf = append(settings.ResolvConf, "nameserver "+nameserver)
+	}
+	if len(searches) > 0 {
+		line := "search " + strings.Join(searches, " ")
+		if clusterFirst {
+			line += " <node search domains>"
+		}
+		settings.ResolvConf = append(settings.ResolvConf, line)
+	}
+	if len(optionOrder) > 0 {
+		values := make([]string, 0, len(optionOrder))
+		for _, name := range optionOrder {
+			if options[name] != "" {
+				values = append(values, name+":"+options[name])
+			} else {
+				values = append(values, name)
+			}
+		}
+		settings.ResolvConf = append(settings.ResolvConf, "options "+strings.Join(values, " "))
+	}
+
+	return settings
+}
@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"reflect"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/fakecluster"
+)
+
+const testCorefile = `.:53 {
+    errors
+    health {
+       lameduck 5s
+    }
+    kubernetes cluster.local in-addr.arpa ip6.arpa {
+       pods insecure
+       fallthrough in-addr.arpa ip6.arpa
+    }
+    forward . /etc/resolv.conf {
+       max_concurrent 1000
+    }
+    cache 30
+}
+corp.example.com:53 {
+    # internal zone
+    forward . 10.0.0.2 10.0.0.3
+}
+`
+
+func TestParseCorefile(t *testing.T) {
+	t.Parallel()
+
+	want := []DNSServerBlock{
+		{Zones: []string{".:53"}, Plugins: []string{"errors", "health", "kubernetes", "forward", "cache"}, Forward: []string{"/etc/resolv.conf"}},
+		{Zones: []string{"corp.example.com:53"}, Plugins: []string{"forward"}, Forward: []string{"10.0.0.2", "10.0.0.3"}},
+	}
+	if got := parseCorefile(testCorefile); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+
+	if got := corefileClusterDomain(testCorefile); got != "cluster.local" {
+		t.Errorf("expected cluster.local, got %q", got)
+	}
+}
+
+func TestPodDNSSettings(t *testing.T) {
+	t.Parallel()
+
+	ndots := "2"
+	tests := []struct {
+		name string
+		spec corev1.PodSpec
+		want []string
+	}{
+		{
+			name: "cluster first",
+			want: []string{
+				"nameserver 10.96.0.10",
+				"search shop.svc.cluster.local svc.cluster.local cluster.local <node search domains>",
+				"options ndots:5",
+			},
+		},
+		{
+			name: "cluster first with dns config",
+			spec: corev1.PodSpec{DNSConfig: &corev1.PodDNSConfig{
+				Searches: []string{"corp.example.com"},
+				Options:  []corev1.PodDNSConfigOption{{Name: "ndots", Value: &ndots}, {Name: "edns0"}},
+			}},
+			want: []string{
+				"nameserver 10.96.0.10",
+				"search shop.svc.cluster.local svc.cluster.local cluster.local corp.example.com <node search domains>",
+				"options ndots:2 edns0",
+			},
+		},
+		{
+			name: "host network with cluster first",
+			spec: corev1.PodSpec{HostNetwork: true},
+			want: []string{"# nameservers, search domains, and options inherited from the node"},
+		},
+		{
+			name: "none",
+			spec: corev1.PodSpec{DNSPolicy: corev1.DNSNone, DNSConfig: &corev1.PodDNSConfig{Nameservers: []string{"1.1.1.1"}}},
+			want: []string{"nameserver 1.1.1.1"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop"}, Spec: tt.spec}
+			if got := podDNSSettings(pod, "10.96.0.10", "cluster.local").ResolvConf; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestGetDNSConfig_FakeCluster(t *testing.T) {
+	t.Parallel()
+
+	replicas := int32(2)
+	dnsLabels := map[string]string{"k8s-app": "kube-dns"}
+	handler := NewResourceHandler(fakecluster.New(fakecluster.Config{
+		Objects: []runtime.Object{
+			&corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{Name: "kube-dns", Namespace: "kube-system", Labels: dnsLabels},
+				Spec: corev1.ServiceSpec{
+					ClusterIP: "10.96.0.10",
+					Ports:     []corev1.ServicePort{{Name: "dns", Port: 53, Protocol: corev1.ProtocolUDP}},
+				},
+			},
+			&appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Name: "coredns", Namespace: "kube-system", Labels: dnsLabels},
+				Spec: appsv1.DeploymentSpec{
+					Replicas: &replicas,
+					Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "coredns", Image: "coredns/coredns:1.11.1"}}}},
+				},
+				Status: appsv1.DeploymentStatus{ReadyReplicas: 1},
+			},
+			&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "coredns", Namespace: "kube-system"},
+				Data:       map[string]string{"Corefile": testCorefile},
+			},
+			&corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop"},
+				Spec:       corev1.PodSpec{DNSPolicy: corev1.DNSClusterFirst},
+			},
+		},
+	}), nil, false)
+
+	result, isErr := callTool(t, handler.GetDNSConfig, map[string]any{"namespace": "shop", "pod": "web"})
+	if isErr {
+		t.Fatalf("unexpected error: %v", result["error"])
+	}
+
+	var service map[string]any
+	decodeInto(t, result["dns_service"], &service)
+	if service["cluster_ip"] != "10.96.0.10" {
+		t.Errorf("expected the DNS service IP, got %v", service)
+	}
+
+	var workloads []DNSWorkload
+	decodeInto(t, result["workloads"], &workloads)
+	want := []DNSWorkload{{Kind: "Deployment", Name: "coredns", Images: []string{"coredns/coredns:1.11.1"}, Desired: 2, Ready: 1}}
+	if !reflect.DeepEqual(workloads, want) {
+		t.Errorf("expected workloads %+v, got %+v", want, workloads)
+	}
+
+	var servers []DNSServerBlock
+	decodeInto(t, result["servers"], &servers)
+	if len(servers) != 2 {
+		t.Errorf("expected two server blocks, got %+v", servers)
+	}
+
+	var findings []string
+	decodeInto(t, result["findings"], &findings)
+	if len(findings) != 2 {
+		t.Errorf("expected findings for the unready replica and the resolv.conf upstream, got %q", findings)
+	}
+
+	var pod PodDNSSettings
+	decodeInto(t, result["pod"], &pod)
+	if pod.Name != "web" || len(pod.ResolvConf) != 3 || pod.ResolvConf[0] != "nameserver 10.96.0.10" {
+		t.Errorf("unexpected pod settings %+v", pod)
+	}
+}
@@ -0,0 +1,259 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/connectivity"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/response"
+)
+
+// maxDNSOverridePods bounds how many pod names each dns_overrides_report
+// entry lists; PodCount always covers all of them.
+const maxDNSOverridePods = 10
+
+// DNSOverridesReportParams defines the parameters for the dns_overrides_report MCP tool.
+type DNSOverridesReportParams struct {
+	// Namespace restricts the report to a namespace.
+	Namespace string `json:"namespace,omitempty" description:"Namespace to report on (leave empty for all namespaces)"`
+
+	// LabelSelector restricts the report to matching pods.
+	LabelSelector string `json:"label_selector,omitempty" description:"Label selector to filter pods (e.g., 'app=web')"`
+
+	// Context specifies which Kubernetes context to use for this operation.
+	Context string `json:"context,omitempty" description:"Kubernetes context to use (defaults to current context from kubeconfig)"`
+}
+
+// PodDNSConfig is the dnsConfig of a pod, with options written as
+// name:value.
+type PodDNSConfig struct {
+	Nameservers []string `json:"nameservers,omitempty"`
+	Searches    []string `json:"searches,omitempty"`
+	Options     []string `json:"options,omitempty"`
+}
+
+// DNSOverride is a group of pods of the same workload that share the same
+// DNS overrides.
+type DNSOverride struct {
+	Namespace string `json:"namespace"`
+
+	// Workload is the controller of the pods, such as Deployment/web. It is
+	// empty for standalone pods.
+	Workload string   `json:"workload,omitempty"`
+	Pods     []string `json:"pods"`
+	PodCount int      `json:"pod_count"`
+
+	DNSPolicy   string        `json:"dns_policy"`
+	HostNetwork bool          `json:"host_network,omitempty"`
+	DNSConfig   *PodDNSConfig `json:"dns_config,omitempty"`
+
+	// HostAliases are the /etc/hosts entries, written as "ip host...".
+	HostAliases []string `json:"host_aliases,omitempty"`
+	Findings    []string `json:"findings"`
+}
+
+// DNSOverridesReportResult is the result of the dns_overrides_report MCP
+// tool.
+type DNSOverridesReportResult struct {
+	Overrides    []DNSOverride `json:"overrides"`
+	PodsChecked  int           `json:"pods_checked"`
+	PodsAffected int           `json:"pods_affected"`
+
+	// PodsByType counts the affected pods by the kind of override: the DNS
+	// policy, a custom DNS config, or host aliases.
+	PodsByType map[string]int `json:"pods_by_type"`
+}
+
+// DNSOverridesReport implements the dns_overrides_report MCP tool.
+// It finds pods whose name resolution differs from the cluster default: a
+// dnsPolicy other than ClusterFirst, host network pods that fall back to the
+// node's DNS, a custom dnsConfig, or hostAliases entries that bypass DNS.
+// Pods of the same workload with the same overrides are reported once.
+func (h *NetworkHandler) DNSOverridesReport(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var params DNSOverridesReportParams
+	if err := bindParams(request, &params); err != nil {
+		return response.Errorf("failed to parse arguments: %s", err)
+	}
+
+	// Use the appropriate client based on context
+	client, err := h.client.ForContext(ctx, params.Context)
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to create client with context %s: %v", params.Context, err)
+	}
+
+	pods, err := client.ListPods(ctx, params.Namespace, metav1.ListOptions{LabelSelector: params.LabelSelector})
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to list pods: %v", err)
+	}
+
+	overrides := dnsOverrides(pods.Items)
+
+	counts := map[string]int{
+		"dns_policy":   0,
+		"dns_config":   0,
+		"host_aliases": 0,
+	}
+	affected := 0
+	for _, override := range overrides {
+		affected += override.PodCount
+		if override.DNSPolicy != string(corev1.DNSClusterFirst) || override.HostNetwork {
+			counts["dns_policy"] += override.PodCount
+		}
+		if override.DNSConfig != nil {
+			counts["dns_config"] += override.PodCount
+		}
+		if len(override.HostAliases) > 0 {
+			counts["host_aliases"] += override.PodCount
+		}
+	}
+
+	return response.JSON(DNSOverridesReportResult{
+		Overrides:    overrides,
+		PodsChecked:  len(pods.Items),
+		PodsAffected: affected,
+		PodsByType:   counts,
+	})
+}
+
+// dnsOverrides groups the pods with DNS overrides by workload and overrides,
+// ordered by namespace and workload. Terminated pods are skipped.
+func dnsOverrides(pods []corev1.Pod) []DNSOverride {
+	groups := make(map[string]*DNSOverride)
+	var order []string
+
+	for i := range pods {
+		pod := &pods[i]
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+
+		override, ok := podDNSOverride(pod)
+		if !ok {
+			continue
+		}
+
+		// Group by workload and by the overrides themselves, so a rollout
+		// that changes them shows both versions.
+		fingerprint, _ := json.Marshal(override)
+		owner := override.Workload
+		if owner == "" {
+			owner = "Pod/" + pod.Name
+		}
+		key := pod.Namespace + "/" + owner + "/" + string(fingerprint)
+
+		group, ok := groups[key]
+		if !ok {
+			group = &override
+			groups[key] = group
+			order = append(order, key)
+		}
+		group.PodCount++
+		if len(group.Pods) < maxDNSOverridePods {
+			group.Pods = append(group.Pods, pod.Name)
+		}
+	}
+
+	overrides := make([]DNSOverride, 0, len(order))
+	for _, key := range order {
+		overrides = append(overrides, *groups[key])
+	}
+
+	sort.SliceStable(overrides, func(i, j int) bool {
+		if overrides[i].Namespace != overrides[j].Namespace {
+			return overrides[i].Namespace < overrides[j].Namespace
+		}
+		if overrides[i].Workload != overrides[j].Workload {
+			return overrides[i].Workload < overrides[j].Workload
+		}
+		return overrides[i].Pods[0] < overrides[j].Pods[0]
+	})
+
+	return overrides
+}
+
+// podDNSOverride describes the DNS overrides of a pod, without its name. It
+// returns false when the pod resolves names the default way.
+func podDNSOverride(pod *corev1.Pod) (DNSOverride, bool) {
+	policy := pod.Spec.DNSPolicy
+	if policy == "" {
+		policy = corev1.DNSClusterFirst
+	}
+
+	override := DNSOverride{
+		Namespace:   pod.Namespace,
+		Workload:    podWorkload(pod),
+		Pods:        []string{},
+		DNSPolicy:   string(policy),
+		HostNetwork: pod.Spec.HostNetwork,
+		Findings:    []string{},
+	}
+
+	changed := false
+
+	switch policy {
+	case corev1.DNSClusterFirst:
+		if pod.Spec.HostNetwork {
+			changed = true
+			override.Findings = append(override.Findings, "the pod uses the host network with dnsPolicy ClusterFirst, which falls back to the node's resolv.conf, so Service names do not resolve; use ClusterFirstWithHostNet")
+		}
+	case corev1.DNSDefault:
+		changed = true
+		override.Findings = append(override.Findings, "dnsPolicy Default uses the node's resolv.conf instead of cluster DNS, so Service names do not resolve")
+	case corev1.DNSNone:
+		changed = true
+		override.Findings = append(override.Findings, "dnsPolicy None ignores cluster DNS and uses only dnsConfig, so Service names resolve only if its nameservers and searches allow it")
+	case corev1.DNSClusterFirstWithHostNet:
+		changed = true
+		if !pod.Spec.HostNetwork {
+			override.Findings = append(override.Findings, "dnsPolicy ClusterFirstWithHostNet behaves like ClusterFirst because the pod does not use the host network")
+		}
+	}
+
+	if config := pod.Spec.DNSConfig; config != nil && (len(config.Nameservers) > 0 || len(config.Searches) > 0 || len(config.Options) > 0) {
+		changed = true
+		override.DNSConfig = &PodDNSConfig{
+			Nameservers: config.Nameservers,
+			Searches:    config.Searches,
+		}
+		for _, option := range config.Options {
+			value := option.Name
+			if option.Value != nil {
+				value += ":" + *option.Value
+			}
+			override.DNSConfig.Options = append(override.DNSConfig.Options, value)
+		}
+
+		if len(config.Nameservers) > 0 && policy != corev1.DNSNone {
+			override.Findings = append(override.Findings, fmt.Sprintf("dnsConfig adds nameservers %s after the policy's own, which are only queried when earlier ones fail", strings.Join(config.Nameservers, ", ")))
+		}
+		if len(config.Searches) > 0 {
+			override.Findings = append(override.Findings, fmt.Sprintf("dnsConfig adds search domains %s, which are tried for every short name", strings.Join(config.Searches, ", ")))
+		}
+		if len(override.DNSConfig.Options) > 0 {
+			override.Findings = append(override.Findings, fmt.Sprintf("dnsConfig sets resolver options %s", strings.Join(override.DNSConfig.Options, ", ")))
+		}
+	}
+
+	for _, alias := range pod.Spec.HostAliases {
+		changed = true
+		override.HostAliases = append(override.HostAliases, alias.IP+" "+strings.Join(alias.Hostnames, " "))
+	}
+	if len(override.HostAliases) > 0 {
+		override.Findings = append(override.Findings, "hostAliases pin names in /etc/hosts, so DNS changes for those names never reach the pod")
+	}
+
+	return override, changed
+}
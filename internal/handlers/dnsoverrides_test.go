@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/fakecluster"
+)
+
+func TestPodDNSOverride(t *testing.T) {
+	t.Parallel()
+
+	ndots := "2"
+	tests := []struct {
+		name         string
+		spec         corev1.PodSpec
+		wantChanged  bool
+		wantPolicy   string
+		wantFindings int
+	}{
+		{
+			name:       "default policy",
+			wantPolicy: "ClusterFirst",
+		},
+		{
+			name:         "host network with ClusterFirst",
+			spec:         corev1.PodSpec{HostNetwork: true, DNSPolicy: corev1.DNSClusterFirst},
+			wantChanged:  true,
+			wantPolicy:   "ClusterFirst",
+			wantFindings: 1,
+		},
+		{
+			name:        "host network with ClusterFirstWithHostNet",
+			spec:        corev1.PodSpec{HostNetwork: true, DNSPolicy: corev1.DNSClusterFirstWithHostNet},
+			wantChanged: true,
+			wantPolicy:  "ClusterFirstWithHostNet",
+		},
+		{
+			name:         "node resolver",
+			spec:         corev1.PodSpec{DNSPolicy: corev1.DNSDefault},
+			wantChanged:  true,
+			wantPolicy:   "Default",
+			wantFindings: 1,
+		},
+		{
+			name: "custom resolver options",
+			spec: corev1.PodSpec{DNSConfig: &corev1.PodDNSConfig{
+				Searches: []string{"corp.example.com"},
+				Options:  []corev1.PodDNSConfigOption{{Name: "ndots", Value: &ndots}, {Name: "edns0"}},
+			}},
+			wantChanged:  true,
+			wantPolicy:   "ClusterFirst",
+			wantFindings: 2,
+		},
+		{
+			name:         "host aliases",
+			spec:         corev1.PodSpec{HostAliases: []corev1.HostAlias{{IP: "10.0.0.5", Hostnames: []string{"db.internal"}}}},
+			wantChanged:  true,
+			wantPolicy:   "ClusterFirst",
+			wantFindings: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, changed := podDNSOverride(&corev1.Pod{Spec: tt.spec})
+			if changed != tt.wantChanged {
+				t.Fatalf("expected changed=%v, got %v", tt.wantChanged, changed)
+			}
+			if got.DNSPolicy != tt.wantPolicy {
+				t.Errorf("expected policy %q, got %q", tt.wantPolicy, got.DNSPolicy)
+			}
+			if len(got.Findings) != tt.wantFindings {
+				t.Errorf("expected %d findings, got %v", tt.wantFindings, got.Findings)
+			}
+		})
+	}
+}
+
+func TestDNSOverridesReport_FakeCluster(t *testing.T) {
+	t.Parallel()
+
+	ndots := "1"
+	controller := true
+	webPod := func(name string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            name,
+				Namespace:       "shop",
+				OwnerReferences: []metav1.OwnerReference{{Kind: "ReplicaSet", Name: "web-abc12", Controller: &controller}},
+				Labels:          map[string]string{"pod-template-hash": "abc12"},
+			},
+			Spec: corev1.PodSpec{
+				DNSConfig:   &corev1.PodDNSConfig{Options: []corev1.PodDNSConfigOption{{Name: "ndots", Value: &ndots}}},
+				HostAliases: []corev1.HostAlias{{IP: "10.0.0.5", Hostnames: []string{"db.internal", "db"}}},
+			},
+			Status: corev1.PodStatus{Phase: corev1.PodRunning},
+		}
+	}
+
+	handler := NewNetworkHandler(fakecluster.New(fakecluster.Config{
+		Objects: []runtime.Object{
+			webPod("web-abc12-1"),
+			webPod("web-abc12-2"),
+			&corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "plain", Namespace: "shop"},
+				Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+			},
+			&corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "done", Namespace: "shop"},
+				Spec:       corev1.PodSpec{DNSPolicy: corev1.DNSDefault},
+				Status:     corev1.PodStatus{Phase: corev1.PodSucceeded},
+			},
+			&corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "kube-system"},
+				Spec:       corev1.PodSpec{HostNetwork: true, DNSPolicy: corev1.DNSClusterFirst},
+				Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+			},
+		},
+	}), false)
+
+	result, isErr := callTool(t, handler.DNSOverridesReport, map[string]any{})
+	if isErr {
+		t.Fatalf("unexpected error: %v", result["error"])
+	}
+
+	var overrides []DNSOverride
+	decodeInto(t, result["overrides"], &overrides)
+	if len(overrides) != 2 {
+		t.Fatalf("expected two overrides, got %+v", overrides)
+	}
+
+	if overrides[0].Namespace != "kube-system" || !overrides[0].HostNetwork || overrides[0].PodCount != 1 {
+		t.Errorf("expected the host network pod first, got %+v", overrides[0])
+	}
+
+	web := overrides[1]
+	if web.Workload != "Deployment/web" || web.PodCount != 2 || !reflect.DeepEqual(web.Pods, []string{"web-abc12-1", "web-abc12-2"}) {
+		t.Errorf("expected both web pods grouped under the deployment, got %+v", web)
+	}
+	if !reflect.DeepEqual(web.HostAliases, []string{"10.0.0.5 db.internal db"}) {
+		t.Errorf("unexpected host aliases %v", web.HostAliases)
+	}
+	if web.DNSConfig == nil || !reflect.DeepEqual(web.DNSConfig.Options, []string{"ndots:1"}) {
+		t.Errorf("unexpected dns config %+v", web.DNSConfig)
+	}
+
+	if result["pods_affected"] != float64(3) {
+		t.Errorf("expected 3 affected pods, got %v", result["pods_affected"])
+	}
+}
@@ -0,0 +1,379 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/connectivity"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/response"
+)
+
+// maxServiceEndpointPods bounds how many selected pods get_service_endpoints
+// lists.
+const maxServiceEndpointPods = 50
+
+// GetServiceEndpointsParams defines the parameters for the get_service_endpoints MCP tool.
+type GetServiceEndpointsParams struct {
+	// Namespace specifies the namespace of the Service.
+	Namespace string `json:"namespace,omitempty" description:"Namespace of the Service (defaults to the namespace of the current context)"`
+
+	// Name specifies the Service.
+	Name string `json:"name" required:"true" description:"Name of the Service"`
+
+	// Context specifies which Kubernetes context to use for this operation.
+	Context string `json:"context,omitempty" description:"Kubernetes context to use (defaults to current context from kubeconfig)"`
+}
+
+// ServicePortMapping is a port of a Service and where it sends traffic.
+type ServicePortMapping struct {
+	Name       string `json:"name,omitempty"`
+	Port       int32  `json:"port"`
+	TargetPort string `json:"target_port"`
+	Protocol   string `json:"protocol"`
+	NodePort   int32  `json:"node_port,omitempty"`
+}
+
+// ServiceEndpoint is an endpoint of an EndpointSlice.
+type ServiceEndpoint struct {
+	Addresses []string `json:"addresses"`
+
+	// Pod is set when the endpoint is backed by a pod.
+	Pod         string `json:"pod,omitempty"`
+	Node        string `json:"node,omitempty"`
+	Zone        string `json:"zone,omitempty"`
+	Ready       bool   `json:"ready"`
+	Serving     bool   `json:"serving"`
+	Terminating bool   `json:"terminating,omitempty"`
+}
+
+// ServiceEndpointSlice is an EndpointSlice of a Service. Ports are written
+// as name:port/protocol.
+type ServiceEndpointSlice struct {
+	Name        string            `json:"name"`
+	AddressType string            `json:"address_type"`
+	Ports       []string          `json:"ports"`
+	Endpoints   []ServiceEndpoint `json:"endpoints"`
+}
+
+// SelectedPod is a pod matched by the selector of a Service.
+type SelectedPod struct {
+	Name  string `json:"name"`
+	IP    string `json:"ip,omitempty"`
+	Node  string `json:"node,omitempty"`
+	Phase string `json:"phase"`
+	Ready bool   `json:"ready"`
+
+	// InEndpoints is true when an EndpointSlice of the Service lists the pod.
+	InEndpoints bool `json:"in_endpoints"`
+}
+
+// GetServiceEndpointsResult is the result of the get_service_endpoints MCP
+// tool.
+type GetServiceEndpointsResult struct {
+	Namespace      string                 `json:"namespace"`
+	Name           string                 `json:"name"`
+	Type           string                 `json:"type"`
+	ClusterIP      string                 `json:"cluster_ip"`
+	Selector       map[string]string      `json:"selector"`
+	Ports          []ServicePortMapping   `json:"ports"`
+	EndpointSlices []ServiceEndpointSlice `json:"endpoint_slices"`
+	Pods           []SelectedPod          `json:"pods"`
+
+	// PodsTruncated is set when only some of the selected pods are listed,
+	// and PodCount then holds how many there are.
+	PodsTruncated bool `json:"pods_truncated,omitempty"`
+	PodCount      int  `json:"pod_count,omitempty"`
+
+	Findings []string `json:"findings"`
+}
+
+// GetServiceEndpoints implements the get_service_endpoints MCP tool.
+// It resolves a Service to the pods behind it: the Service's selector and
+// ports, its EndpointSlices with each endpoint's address, pod, and
+// conditions, and the pods its selector matches with their readiness. It
+// adds findings for the usual reasons a Service does not route, such as a
+// selector matching no pods, no ready endpoints, or a named target port
+// that the pods do not declare.
+func (h *NetworkHandler) GetServiceEndpoints(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var params GetServiceEndpointsParams
+	if err := bindParams(request, &params); err != nil {
+		return response.Errorf("failed to parse arguments: %s", err)
+	}
+
+	// Use the appropriate client based on context
+	client, err := h.client.ForContext(ctx, params.Context)
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to create client with context %s: %v", params.Context, err)
+	}
+
+	service, err := client.GetService(ctx, params.Namespace, params.Name)
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to get service: %v", err)
+	}
+
+	slices, err := client.ListEndpointSlices(ctx, service.Namespace, metav1.ListOptions{
+		LabelSelector: discoveryv1.LabelServiceName + "=" + service.Name,
+	})
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to list endpoint slices: %v", err)
+	}
+
+	var pods []corev1.Pod
+	if len(service.Spec.Selector) > 0 {
+		list, err := client.ListPods(ctx, service.Namespace, metav1.ListOptions{
+			LabelSelector: labels.SelectorFromSet(service.Spec.Selector).String(),
+		})
+		if err != nil {
+			if h.alwaysStart && connectivity.IsTransportError(err) {
+				return response.Error(connectivity.ErrorMessage(err))
+			}
+			return response.Errorf("failed to list pods: %v", err)
+		}
+		pods = list.Items
+	}
+
+	endpointSlices := serviceEndpointSlices(slices.Items)
+	selected, truncated := selectedPods(pods, endpointSlices)
+
+	result := GetServiceEndpointsResult{
+		Namespace:      service.Namespace,
+		Name:           service.Name,
+		Type:           string(service.Spec.Type),
+		ClusterIP:      service.Spec.ClusterIP,
+		Selector:       service.Spec.Selector,
+		Ports:          servicePortMappings(service.Spec.Ports),
+		EndpointSlices: endpointSlices,
+		Pods:           selected,
+		Findings:       serviceEndpointFindings(service, endpointSlices, pods),
+	}
+
+	if truncated {
+		result.PodsTruncated = true
+		result.PodCount = len(pods)
+	}
+
+	return response.JSON(result)
+}
+
+// servicePortMappings lists the ports of a Service. A target port that is
+// not set defaults to the port itself, like the API server does.
+func servicePortMappings(ports []corev1.ServicePort) []ServicePortMapping {
+	mappings := make([]ServicePortMapping, 0, len(ports))
+	for _, port := range ports {
+		target := port.TargetPort.String()
+		if port.TargetPort.Type == intstr.Int && port.TargetPort.IntVal == 0 {
+			target = fmt.Sprintf("%d", port.Port)
+		}
+		mappings = append(mappings, ServicePortMapping{
+			Name:       port.Name,
+			Port:       port.Port,
+			TargetPort: target,
+			Protocol:   portProtocol(port.Protocol),
+			NodePort:   port.NodePort,
+		})
+	}
+	return mappings
+}
+
+// serviceEndpointSlices lists the endpoints of EndpointSlices, sorted by
+// slice name. Like kube-proxy, an unknown ready or serving condition counts
+// as true.
+func serviceEndpointSlices(slices []discoveryv1.EndpointSlice) []ServiceEndpointSlice {
+	result := make([]ServiceEndpointSlice, 0, len(slices))
+	for i := range slices {
+		slice := &slices[i]
+
+		entry := ServiceEndpointSlice{
+			Name:        slice.Name,
+			AddressType: string(slice.AddressType),
+			Ports:       make([]string, 0, len(slice.Ports)),
+			Endpoints:   make([]ServiceEndpoint, 0, len(slice.Endpoints)),
+		}
+
+		for _, port := range slice.Ports {
+			value := "<unset>"
+			if port.Port != nil {
+				value = fmt.Sprintf("%d", *port.Port)
+			}
+			if port.Name != nil && *port.Name != "" {
+				value = *port.Name + ":" + value
+			}
+			var protocol corev1.Protocol
+			if port.Protocol != nil {
+				protocol = *port.Protocol
+			}
+			entry.Ports = append(entry.Ports, value+"/"+portProtocol(protocol))
+		}
+
+		for _, endpoint := range slice.Endpoints {
+			item := ServiceEndpoint{
+				Addresses:   endpoint.Addresses,
+				Ready:       endpoint.Conditions.Ready == nil || *endpoint.Conditions.Ready,
+				Serving:     endpoint.Conditions.Serving == nil || *endpoint.Conditions.Serving,
+				Terminating: endpoint.Conditions.Terminating != nil && *endpoint.Conditions.Terminating,
+			}
+			if endpoint.TargetRef != nil && endpoint.TargetRef.Kind == "Pod" {
+				item.Pod = endpoint.TargetRef.Name
+			}
+			if endpoint.NodeName != nil {
+				item.Node = *endpoint.NodeName
+			}
+			if endpoint.Zone != nil {
+				item.Zone = *endpoint.Zone
+			}
+			entry.Endpoints = append(entry.Endpoints, item)
+		}
+
+		result = append(result, entry)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+
+	return result
+}
+
+// selectedPods lists the pods selected by a Service, sorted by name, and
+// whether the list was cut at maxServiceEndpointPods.
+func selectedPods(pods []corev1.Pod, slices []ServiceEndpointSlice) ([]SelectedPod, bool) {
+	inEndpoints := make(map[string]bool)
+	for _, slice := range slices {
+		for _, endpoint := range slice.Endpoints {
+			if endpoint.Pod != "" {
+				inEndpoints[endpoint.Pod] = true
+			}
+		}
+	}
+
+	selected := make([]SelectedPod, 0, len(pods))
+	for i := range pods {
+		pod := &pods[i]
+		selected = append(selected, SelectedPod{
+			Name:        pod.Name,
+			IP:          pod.Status.PodIP,
+			Node:        pod.Spec.NodeName,
+			Phase:       string(pod.Status.Phase),
+			Ready:       isPodReady(pod),
+			InEndpoints: inEndpoints[pod.Name],
+		})
+	}
+
+	sort.Slice(selected, func(i, j int) bool {
+		return selected[i].Name < selected[j].Name
+	})
+
+	if len(selected) > maxServiceEndpointPods {
+		return selected[:maxServiceEndpointPods], true
+	}
+	return selected, false
+}
+
+// serviceEndpointFindings explains why a Service may not route traffic.
+func serviceEndpointFindings(service *corev1.Service, slices []ServiceEndpointSlice, pods []corev1.Pod) []string {
+	findings := make([]string, 0)
+
+	if service.Spec.Type == corev1.ServiceTypeExternalName {
+		return append(findings, fmt.Sprintf("ExternalName Service resolves to %s through DNS and has no endpoints", service.Spec.ExternalName))
+	}
+
+	total, ready := 0, 0
+	for _, slice := range slices {
+		for _, endpoint := range slice.Endpoints {
+			total++
+			if endpoint.Ready {
+				ready++
+			}
+		}
+	}
+
+	if len(service.Spec.Selector) == 0 {
+		if len(slices) == 0 {
+			findings = append(findings, "the Service has no selector and no EndpointSlices, so it routes nowhere until endpoints are added manually")
+		} else {
+			findings = append(findings, "the Service has no selector, so its EndpointSlices are managed manually or by another controller")
+		}
+	} else {
+		running := 0
+		for i := range pods {
+			if pods[i].Status.Phase == corev1.PodRunning {
+				running++
+			}
+		}
+		switch {
+		case len(pods) == 0:
+			findings = append(findings, fmt.Sprintf("the selector %s matches no pods in namespace %s", labels.SelectorFromSet(service.Spec.Selector).String(), service.Namespace))
+		case running == 0:
+			findings = append(findings, fmt.Sprintf("the selector matches %d pods but none are running", len(pods)))
+		}
+	}
+
+	switch {
+	case total > 0 && ready == 0:
+		findings = append(findings, fmt.Sprintf("none of the %d endpoints are ready, so traffic is not routed", total))
+	case ready < total:
+		findings = append(findings, fmt.Sprintf("%d of %d endpoints are ready", ready, total))
+	}
+
+	if service.Spec.PublishNotReadyAddresses {
+		findings = append(findings, "publishNotReadyAddresses is set, so pods receive traffic before they are ready")
+	}
+
+	for _, port := range service.Spec.Ports {
+		if port.TargetPort.Type != intstr.String {
+			continue
+		}
+		var missing []string
+		for i := range pods {
+			if !podDeclaresPort(&pods[i], port.TargetPort.StrVal) {
+				missing = append(missing, pods[i].Name)
+			}
+		}
+		if len(missing) > 0 {
+			findings = append(findings, fmt.Sprintf("target port %q of port %d is not declared by pods: %s", port.TargetPort.StrVal, port.Port, strings.Join(missing, ", ")))
+		}
+	}
+
+	return findings
+}
+
+// podDeclaresPort reports whether a container of the pod declares a named
+// port.
+func podDeclaresPort(pod *corev1.Pod, name string) bool {
+	for _, container := range pod.Spec.Containers {
+		for _, port := range container.Ports {
+			if port.Name == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isPodReady reports whether the pod's Ready condition is true.
+func isPodReady(pod *corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
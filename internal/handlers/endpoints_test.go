@@ -0,0 +1,187 @@
+package handlers
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/fakecluster"
+)
+
+func TestServiceEndpointFindings(t *testing.T) {
+	t.Parallel()
+
+	selectorService := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop"},
+		Spec: corev1.ServiceSpec{
+			Selector: map[string]string{"app": "web"},
+			Ports:    []corev1.ServicePort{{Port: 80, TargetPort: intstr.FromString("http")}},
+		},
+	}
+	runningPod := func(portName string) corev1.Pod {
+		return corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "web-1"},
+			Spec: corev1.PodSpec{Containers: []corev1.Container{{
+				Name:  "app",
+				Ports: []corev1.ContainerPort{{Name: portName, ContainerPort: 8080}},
+			}}},
+			Status: corev1.PodStatus{Phase: corev1.PodRunning},
+		}
+	}
+	slice := func(ready bool) []ServiceEndpointSlice {
+		return []ServiceEndpointSlice{{Endpoints: []ServiceEndpoint{{Pod: "web-1", Ready: ready}}}}
+	}
+
+	tests := []struct {
+		name    string
+		service *corev1.Service
+		slices  []ServiceEndpointSlice
+		pods    []corev1.Pod
+		want    []string
+	}{
+		{
+			name:    "healthy service",
+			service: selectorService,
+			slices:  slice(true),
+			pods:    []corev1.Pod{runningPod("http")},
+			want:    []string{},
+		},
+		{
+			name:    "selector matches no pods",
+			service: selectorService,
+			want:    []string{"the selector app=web matches no pods in namespace shop"},
+		},
+		{
+			name:    "no ready endpoints and missing named port",
+			service: selectorService,
+			slices:  slice(false),
+			pods:    []corev1.Pod{runningPod("metrics")},
+			want: []string{
+				"none of the 1 endpoints are ready, so traffic is not routed",
+				`target port "http" of port 80 is not declared by pods: web-1`,
+			},
+		},
+		{
+			name:    "external name",
+			service: &corev1.Service{Spec: corev1.ServiceSpec{Type: corev1.ServiceTypeExternalName, ExternalName: "db.example.com"}},
+			want:    []string{"ExternalName Service resolves to db.example.com through DNS and has no endpoints"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := serviceEndpointFindings(tt.service, tt.slices, tt.pods); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestGetServiceEndpoints_FakeCluster(t *testing.T) {
+	t.Parallel()
+
+	ready, notReady := true, false
+	port := int32(8080)
+	portName := "http"
+	node := "node-1"
+
+	pod := func(name, ip string, isReady bool) *corev1.Pod {
+		status := corev1.ConditionFalse
+		if isReady {
+			status = corev1.ConditionTrue
+		}
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "shop", Labels: map[string]string{"app": "web"}},
+			Spec:       corev1.PodSpec{NodeName: node},
+			Status: corev1.PodStatus{
+				Phase:      corev1.PodRunning,
+				PodIP:      ip,
+				Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: status}},
+			},
+		}
+	}
+
+	handler := NewNetworkHandler(fakecluster.New(fakecluster.Config{
+		Objects: []runtime.Object{
+			&corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop"},
+				Spec: corev1.ServiceSpec{
+					Type:      corev1.ServiceTypeClusterIP,
+					ClusterIP: "10.96.0.10",
+					Selector:  map[string]string{"app": "web"},
+					Ports:     []corev1.ServicePort{{Name: "http", Port: 80, TargetPort: intstr.FromInt32(8080)}},
+				},
+			},
+			pod("web-1", "10.244.0.5", true),
+			pod("web-2", "10.244.0.6", false),
+			&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "shop", Labels: map[string]string{"app": "other"}}},
+			&discoveryv1.EndpointSlice{
+				ObjectMeta:  metav1.ObjectMeta{Name: "web-abcde", Namespace: "shop", Labels: map[string]string{discoveryv1.LabelServiceName: "web"}},
+				AddressType: discoveryv1.AddressTypeIPv4,
+				Ports:       []discoveryv1.EndpointPort{{Name: &portName, Port: &port}},
+				Endpoints: []discoveryv1.Endpoint{
+					{
+						Addresses:  []string{"10.244.0.5"},
+						Conditions: discoveryv1.EndpointConditions{Ready: &ready},
+						TargetRef:  &corev1.ObjectReference{Kind: "Pod", Name: "web-1"},
+						NodeName:   &node,
+					},
+					{
+						Addresses:  []string{"10.244.0.6"},
+						Conditions: discoveryv1.EndpointConditions{Ready: &notReady, Serving: &notReady},
+						TargetRef:  &corev1.ObjectReference{Kind: "Pod", Name: "web-2"},
+						NodeName:   &node,
+					},
+				},
+			},
+			&discoveryv1.EndpointSlice{
+				ObjectMeta:  metav1.ObjectMeta{Name: "other-abcde", Namespace: "shop", Labels: map[string]string{discoveryv1.LabelServiceName: "other"}},
+				AddressType: discoveryv1.AddressTypeIPv4,
+			},
+		},
+	}), false)
+
+	result, isErr := callTool(t, handler.GetServiceEndpoints, map[string]any{"namespace": "shop", "name": "web"})
+	if isErr {
+		t.Fatalf("unexpected error: %v", result["error"])
+	}
+
+	var ports []ServicePortMapping
+	decodeInto(t, result["ports"], &ports)
+	if want := []ServicePortMapping{{Name: "http", Port: 80, TargetPort: "8080", Protocol: "TCP"}}; !reflect.DeepEqual(ports, want) {
+		t.Errorf("expected ports %+v, got %+v", want, ports)
+	}
+
+	var slices []ServiceEndpointSlice
+	decodeInto(t, result["endpoint_slices"], &slices)
+	if len(slices) != 1 || slices[0].Name != "web-abcde" || !reflect.DeepEqual(slices[0].Ports, []string{"http:8080/TCP"}) {
+		t.Fatalf("expected only the web slice, got %+v", slices)
+	}
+	if len(slices[0].Endpoints) != 2 || !slices[0].Endpoints[0].Ready || slices[0].Endpoints[1].Ready {
+		t.Errorf("unexpected endpoints %+v", slices[0].Endpoints)
+	}
+
+	var pods []SelectedPod
+	decodeInto(t, result["pods"], &pods)
+	want := []SelectedPod{
+		{Name: "web-1", IP: "10.244.0.5", Node: "node-1", Phase: "Running", Ready: true, InEndpoints: true},
+		{Name: "web-2", IP: "10.244.0.6", Node: "node-1", Phase: "Running", InEndpoints: true},
+	}
+	if !reflect.DeepEqual(pods, want) {
+		t.Errorf("expected pods %+v, got %+v", want, pods)
+	}
+
+	var findings []string
+	decodeInto(t, result["findings"], &findings)
+	if !reflect.DeepEqual(findings, []string{"1 of 2 endpoints are ready"}) {
+		t.Errorf("unexpected findings %q", findings)
+	}
+}
@@ -0,0 +1,402 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/connectivity"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/response"
+)
+
+// maxExplainDepth bounds how deep recursive explanations descend, since some
+// schemas (such as CRD validation schemas) reference themselves.
+const maxExplainDepth = 10
+
+// ExplainResourceParams defines the parameters for the explain_resource MCP tool.
+type ExplainResourceParams struct {
+	// Resource is the resource type, optionally followed by a dot-separated field
+	// path, the same way kubectl explain accepts it (e.g., "deployment.spec.strategy").
+	Resource string `json:"resource" required:"true" description:"Resource type optionally followed by a dot-separated field path, like kubectl explain (e.g., \"deployment\", \"deployment.spec.strategy\", \"pod.spec.containers.resources\")"`
+
+	// APIVersion optionally pins the API version for the resource type.
+	APIVersion string `json:"api_version,omitempty" description:"API version for the resource (e.g., \"v1\", \"apps/v1\"), if not provided, the tool will use the server's preferred version"`
+
+	// Recursive includes the nested fields of every field, without descriptions.
+	Recursive bool `json:"recursive,omitempty" description:"When true, lists nested fields recursively (names and types only, like kubectl explain --recursive)"`
+
+	// Context specifies the Kubernetes context to use.
+	Context string `json:"context,omitempty" description:"Kubernetes context to use (defaults to current context from kubeconfig)"`
+}
+
+// ExplainField documents a single field of a resource schema.
+type ExplainField struct {
+	// Name is the field name as it appears in manifests.
+	Name string `json:"name"`
+
+	// Type is the field type (e.g., "string", "[]Container", "map[string]string").
+	Type string `json:"type"`
+
+	// Description is the field documentation. Omitted in recursive explanations.
+	Description string `json:"description,omitempty"`
+
+	// Required reports whether the parent object requires the field.
+	Required bool `json:"required,omitempty"`
+
+	// Enum lists the allowed values, when the schema restricts them.
+	Enum []interface{} `json:"enum,omitempty"`
+
+	// Fields are the nested fields, only populated in recursive explanations.
+	Fields []ExplainField `json:"fields,omitempty"`
+}
+
+// ExplainResourceResult is the result of the explain_resource MCP tool.
+type ExplainResourceResult struct {
+	Kind       string `json:"kind"`
+	APIVersion string `json:"api_version"`
+
+	// FieldPath is the explained field, when one was requested, and Type
+	// its type, or the kind otherwise.
+	FieldPath   string         `json:"field_path,omitempty"`
+	Type        string         `json:"type"`
+	Description string         `json:"description"`
+	Fields      []ExplainField `json:"fields"`
+}
+
+// ExplainResource implements the explain_resource MCP tool.
+// It fetches the OpenAPI v3 schema the server publishes for the resource's group
+// version and returns the documentation for the requested field path, covering
+// both built-in types and custom resources with structural schemas.
+func (h *ResourceHandler) ExplainResource(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var params ExplainResourceParams
+	if err := bindParams(request, &params); err != nil {
+		return response.Errorf("failed to parse arguments: %s", err)
+	}
+
+	resourceType, fieldPath, _ := strings.Cut(params.Resource, ".")
+
+	var path []string
+	if fieldPath != "" {
+		path = strings.Split(fieldPath, ".")
+	}
+
+	// Use the appropriate client based on context
+	client, err := h.client.ForContext(ctx, params.Context)
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to create client with context %s: %v", params.Context, err)
+	}
+
+	gvr, err := client.ResolveResourceType(resourceType, params.APIVersion)
+	if err != nil {
+		if h.alwaysStart && connectivity.IsError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to resolve resource type: %v", err)
+	}
+
+	lists, err := client.DiscoverResources(ctx)
+	if err != nil && len(lists) == 0 {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to discover API resources: %v", err)
+	}
+
+	kind := resourceKind(lists, gvr)
+	if kind == "" {
+		return response.Errorf("failed to find the kind for resource type %q", gvr.Resource)
+	}
+
+	doc, err := loadOpenAPIDocument(client, gvr.GroupVersion())
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to get OpenAPI schema: %v", err)
+	}
+
+	gvk := gvr.GroupVersion().WithKind(kind)
+	root := doc.findKind(gvk)
+	if root == nil {
+		return response.Errorf("the OpenAPI schema for %s does not describe kind %s", gvr.GroupVersion(), kind)
+	}
+
+	field, err := doc.walk(root, path)
+	if err != nil {
+		return response.Errorf("%s: %v", kind, err)
+	}
+
+	result := ExplainResourceResult{
+		Kind:        kind,
+		APIVersion:  gvr.GroupVersion().String(),
+		Type:        kind,
+		Description: doc.description(field),
+		Fields:      doc.fields(field, params.Recursive, 0, map[string]bool{}),
+	}
+
+	if len(path) > 0 {
+		result.FieldPath = strings.Join(path, ".")
+		result.Type = doc.typeName(field)
+	}
+
+	return response.JSON(result)
+}
+
+// resourceKind returns the kind served for a resolved resource type, or an
+// empty string if discovery does not list it.
+func resourceKind(lists []*metav1.APIResourceList, gvr schema.GroupVersionResource) string {
+	for _, list := range lists {
+		if list.GroupVersion != gvr.GroupVersion().String() {
+			continue
+		}
+
+		for i := range list.APIResources {
+			if list.APIResources[i].Name == gvr.Resource {
+				return list.APIResources[i].Kind
+			}
+		}
+	}
+
+	return ""
+}
+
+// openAPIDocument is the subset of an OpenAPI v3 document needed to explain
+// resource fields.
+type openAPIDocument struct {
+	Components struct {
+		Schemas map[string]*openAPISchema `json:"schemas"`
+	} `json:"components"`
+}
+
+// openAPISchema is the subset of an OpenAPI v3 schema object needed to explain
+// resource fields.
+type openAPISchema struct {
+	Ref                   string                    `json:"$ref,omitempty"`
+	AllOf                 []*openAPISchema          `json:"allOf,omitempty"`
+	Type                  string                    `json:"type,omitempty"`
+	Description           string                    `json:"description,omitempty"`
+	Properties            map[string]*openAPISchema `json:"properties,omitempty"`
+	Items                 *openAPISchema            `json:"items,omitempty"`
+	AdditionalProperties  json.RawMessage           `json:"additionalProperties,omitempty"`
+	Required              []string                  `json:"required,omitempty"`
+	Enum                  []interface{}             `json:"enum,omitempty"`
+	IntOrString           bool                      `json:"x-kubernetes-int-or-string,omitempty"`
+	PreserveUnknownFields bool                      `json:"x-kubernetes-preserve-unknown-fields,omitempty"`
+	GroupVersionKinds     []struct {
+		Group   string `json:"group"`
+		Version string `json:"version"`
+		Kind    string `json:"kind"`
+	} `json:"x-kubernetes-group-version-kind,omitempty"`
+}
+
+// mapValues returns the schema of a map's values, or nil when the schema does
+// not describe a map with typed values.
+func (s *openAPISchema) mapValues() *openAPISchema {
+	if len(s.AdditionalProperties) == 0 {
+		return nil
+	}
+
+	var values openAPISchema
+	if err := json.Unmarshal(s.AdditionalProperties, &values); err != nil {
+		// additionalProperties may also be a boolean
+		return nil
+	}
+
+	return &values
+}
+
+// findKind returns the top-level schema tagged with the given kind.
+func (d *openAPIDocument) findKind(gvk schema.GroupVersionKind) *openAPISchema {
+	for _, s := range d.Components.Schemas {
+		for _, candidate := range s.GroupVersionKinds {
+			if candidate.Group == gvk.Group && candidate.Version == gvk.Version && candidate.Kind == gvk.Kind {
+				return s
+			}
+		}
+	}
+
+	return nil
+}
+
+// resolve follows $ref pointers and single-element allOf wrappers, which is how
+// Kubernetes attaches descriptions and defaults to referenced types. It returns
+// the resolved schema and the name of the last referenced schema, if any.
+func (d *openAPIDocument) resolve(s *openAPISchema) (*openAPISchema, string) {
+	var ref string
+
+	for s != nil {
+		switch {
+		case s.Ref != "":
+			ref = strings.TrimPrefix(s.Ref, "#/components/schemas/")
+			s = d.Components.Schemas[ref]
+		case len(s.AllOf) == 1 && s.Type == "" && len(s.Properties) == 0:
+			s = s.AllOf[0]
+		default:
+			return s, ref
+		}
+	}
+
+	return nil, ref
+}
+
+// container resolves a schema to the object holding its fields, unwrapping
+// arrays so that list fields can be explained like their elements.
+func (d *openAPIDocument) container(s *openAPISchema) (*openAPISchema, string) {
+	resolved, ref := d.resolve(s)
+	for resolved != nil && resolved.Type == "array" && resolved.Items != nil {
+		resolved, ref = d.resolve(resolved.Items)
+	}
+
+	return resolved, ref
+}
+
+// walk descends from the root schema along the field path.
+func (d *openAPIDocument) walk(root *openAPISchema, path []string) (*openAPISchema, error) {
+	current := root
+
+	for i, name := range path {
+		parent, _ := d.container(current)
+
+		var field *openAPISchema
+		if parent != nil {
+			field = parent.Properties[name]
+		}
+
+		if field == nil {
+			location := "the resource"
+			if i > 0 {
+				location = strings.Join(path[:i], ".")
+			}
+
+			var available []string
+			if parent != nil {
+				for property := range parent.Properties {
+					available = append(available, property)
+				}
+				sort.Strings(available)
+			}
+
+			if len(available) == 0 {
+				return nil, fmt.Errorf("field %q does not exist in %s, which has no documented fields", name, location)
+			}
+
+			return nil, fmt.Errorf("field %q does not exist in %s; available fields: %s", name, location, strings.Join(available, ", "))
+		}
+
+		current = field
+	}
+
+	return current, nil
+}
+
+// typeName renders a schema's type the way kubectl explain does: primitives
+// by name, referenced objects by their short type name, and lists and maps
+// with Go-like notation.
+func (d *openAPIDocument) typeName(s *openAPISchema) string {
+	resolved, ref := d.resolve(s)
+	if resolved == nil {
+		return "Object"
+	}
+
+	if resolved.IntOrString {
+		return "IntOrString"
+	}
+
+	switch resolved.Type {
+	case "array":
+		if resolved.Items == nil {
+			return "[]Object"
+		}
+		return "[]" + d.typeName(resolved.Items)
+	case "object", "":
+		if values := resolved.mapValues(); values != nil && len(resolved.Properties) == 0 {
+			return "map[string]" + d.typeName(values)
+		}
+		if ref != "" {
+			return ref[strings.LastIndex(ref, ".")+1:]
+		}
+		return "Object"
+	default:
+		return resolved.Type
+	}
+}
+
+// description returns a field's documentation, preferring the description set
+// on the field itself over the one of the type it references.
+func (d *openAPIDocument) description(s *openAPISchema) string {
+	if s.Description != "" {
+		return s.Description
+	}
+
+	if resolved, _ := d.resolve(s); resolved != nil {
+		return resolved.Description
+	}
+
+	return ""
+}
+
+// fields lists the fields of a schema sorted by name. When recursive is set,
+// nested fields are included without descriptions; seen tracks the referenced
+// types on the current branch to stop at self-referencing schemas.
+func (d *openAPIDocument) fields(s *openAPISchema, recursive bool, depth int, seen map[string]bool) []ExplainField {
+	parent, ref := d.container(s)
+	if parent == nil || len(parent.Properties) == 0 {
+		return nil
+	}
+
+	if ref != "" {
+		if seen[ref] {
+			return nil
+		}
+		seen[ref] = true
+		defer delete(seen, ref)
+	}
+
+	required := make(map[string]bool, len(parent.Required))
+	for _, name := range parent.Required {
+		required[name] = true
+	}
+
+	names := make([]string, 0, len(parent.Properties))
+	for name := range parent.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fields := make([]ExplainField, 0, len(names))
+	for _, name := range names {
+		property := parent.Properties[name]
+		resolved, _ := d.resolve(property)
+
+		field := ExplainField{
+			Name:     name,
+			Type:     d.typeName(property),
+			Required: required[name],
+		}
+
+		if resolved != nil {
+			field.Enum = resolved.Enum
+		}
+
+		if recursive {
+			if depth < maxExplainDepth {
+				field.Fields = d.fields(property, recursive, depth+1, seen)
+			}
+		} else {
+			field.Description = d.description(property)
+		}
+
+		fields = append(fields, field)
+	}
+
+	return fields
+}
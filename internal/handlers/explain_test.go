@@ -0,0 +1,183 @@
+package handlers
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/fakecluster"
+)
+
+// appsV1OpenAPI is a trimmed apps/v1 OpenAPI v3 document shaped like the one
+// the API server publishes, with allOf-wrapped references and a list field.
+const appsV1OpenAPI = `{
+  "components": {
+    "schemas": {
+      "io.k8s.api.apps.v1.Deployment": {
+        "description": "Deployment enables declarative updates for Pods and ReplicaSets.",
+        "type": "object",
+        "properties": {
+          "apiVersion": {"type": "string", "description": "APIVersion defines the versioned schema."},
+          "spec": {"allOf": [{"$ref": "#/components/schemas/io.k8s.api.apps.v1.DeploymentSpec"}], "default": {}, "description": "Specification of the desired behavior of the Deployment."}
+        },
+        "x-kubernetes-group-version-kind": [{"group": "apps", "kind": "Deployment", "version": "v1"}]
+      },
+      "io.k8s.api.apps.v1.DeploymentSpec": {
+        "description": "DeploymentSpec is the specification of the desired behavior of the Deployment.",
+        "type": "object",
+        "required": ["selector"],
+        "properties": {
+          "replicas": {"type": "integer", "format": "int32", "description": "Number of desired pods."},
+          "selector": {"allOf": [{"$ref": "#/components/schemas/io.k8s.apimachinery.pkg.apis.meta.v1.LabelSelector"}], "description": "Label selector for pods."},
+          "strategy": {"allOf": [{"$ref": "#/components/schemas/io.k8s.api.apps.v1.DeploymentStrategy"}], "default": {}, "description": "The deployment strategy to use to replace existing pods with new ones."},
+          "containers": {"type": "array", "items": {"allOf": [{"$ref": "#/components/schemas/io.k8s.api.core.v1.Container"}], "default": {}}, "description": "Containers of the pod."}
+        }
+      },
+      "io.k8s.api.apps.v1.DeploymentStrategy": {
+        "description": "DeploymentStrategy describes how to replace existing pods with new ones.",
+        "type": "object",
+        "properties": {
+          "type": {"type": "string", "enum": ["Recreate", "RollingUpdate"], "description": "Type of deployment."},
+          "rollingUpdate": {"allOf": [{"$ref": "#/components/schemas/io.k8s.api.apps.v1.RollingUpdateDeployment"}], "description": "Rolling update config params."}
+        }
+      },
+      "io.k8s.api.apps.v1.RollingUpdateDeployment": {
+        "description": "Spec to control the desired behavior of rolling update.",
+        "type": "object",
+        "properties": {
+          "maxSurge": {"allOf": [{"$ref": "#/components/schemas/io.k8s.apimachinery.pkg.util.intstr.IntOrString"}], "description": "The maximum number of pods that can be scheduled above the desired number of pods."}
+        }
+      },
+      "io.k8s.api.core.v1.Container": {
+        "description": "A single application container.",
+        "type": "object",
+        "required": ["name"],
+        "properties": {
+          "name": {"type": "string", "default": "", "description": "Name of the container."},
+          "env": {"type": "object", "additionalProperties": {"type": "string", "default": ""}, "description": "Environment variables."}
+        }
+      },
+      "io.k8s.apimachinery.pkg.apis.meta.v1.LabelSelector": {
+        "description": "A label selector.",
+        "type": "object",
+        "properties": {
+          "matchLabels": {"type": "object", "additionalProperties": {"type": "string", "default": ""}, "description": "matchLabels is a map of {key,value} pairs."}
+        }
+      },
+      "io.k8s.apimachinery.pkg.util.intstr.IntOrString": {
+        "description": "IntOrString is a type that can hold an int32 or a string.",
+        "type": "string",
+        "format": "int-or-string",
+        "x-kubernetes-int-or-string": true
+      }
+    }
+  }
+}`
+
+func TestExplainResource(t *testing.T) {
+	t.Parallel()
+
+	handler := NewResourceHandler(fakecluster.New(fakecluster.Config{
+		OpenAPISchemas: map[string][]byte{"apps/v1": []byte(appsV1OpenAPI)},
+	}), nil, false)
+
+	tests := []struct {
+		name       string
+		args       map[string]any
+		wantType   string
+		wantDesc   string
+		wantFields []ExplainField
+		wantErr    string
+	}{
+		{
+			name:     "resource root",
+			args:     map[string]any{"resource": "deploy"},
+			wantType: "Deployment",
+			wantDesc: "Deployment enables declarative updates for Pods and ReplicaSets.",
+			wantFields: []ExplainField{
+				{Name: "apiVersion", Type: "string", Description: "APIVersion defines the versioned schema."},
+				{Name: "spec", Type: "DeploymentSpec", Description: "Specification of the desired behavior of the Deployment."},
+			},
+		},
+		{
+			name:     "nested field",
+			args:     map[string]any{"resource": "deployment.spec.strategy"},
+			wantType: "DeploymentStrategy",
+			wantDesc: "The deployment strategy to use to replace existing pods with new ones.",
+			wantFields: []ExplainField{
+				{Name: "rollingUpdate", Type: "RollingUpdateDeployment", Description: "Rolling update config params."},
+				{Name: "type", Type: "string", Description: "Type of deployment.", Enum: []interface{}{"Recreate", "RollingUpdate"}},
+			},
+		},
+		{
+			name:     "through a list",
+			args:     map[string]any{"resource": "deployments.spec.containers"},
+			wantType: "[]Container",
+			wantDesc: "Containers of the pod.",
+			wantFields: []ExplainField{
+				{Name: "env", Type: "map[string]string", Description: "Environment variables."},
+				{Name: "name", Type: "string", Description: "Name of the container.", Required: true},
+			},
+		},
+		{
+			name:     "leaf",
+			args:     map[string]any{"resource": "deployment.spec.strategy.rollingUpdate.maxSurge"},
+			wantType: "IntOrString",
+			wantDesc: "The maximum number of pods that can be scheduled above the desired number of pods.",
+		},
+		{
+			name:     "recursive",
+			args:     map[string]any{"resource": "deployment.spec.strategy", "recursive": true},
+			wantType: "DeploymentStrategy",
+			wantDesc: "The deployment strategy to use to replace existing pods with new ones.",
+			wantFields: []ExplainField{
+				{Name: "rollingUpdate", Type: "RollingUpdateDeployment", Fields: []ExplainField{
+					{Name: "maxSurge", Type: "IntOrString"},
+				}},
+				{Name: "type", Type: "string", Enum: []interface{}{"Recreate", "RollingUpdate"}},
+			},
+		},
+		{
+			name:    "unknown field",
+			args:    map[string]any{"resource": "deployment.spec.strategie"},
+			wantErr: `field "strategie" does not exist in spec; available fields: containers, replicas, selector, strategy`,
+		},
+		{
+			name:    "no schema published",
+			args:    map[string]any{"resource": "pods"},
+			wantErr: `does not publish an OpenAPI v3 schema for "v1"`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			result, isErr := callTool(t, handler.ExplainResource, tt.args)
+			if tt.wantErr != "" {
+				msg, _ := result["error"].(string)
+				if !isErr || !strings.Contains(msg, tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, result)
+				}
+				return
+			}
+			if isErr {
+				t.Fatalf("unexpected error: %v", result["error"])
+			}
+
+			if result["type"] != tt.wantType {
+				t.Errorf("expected type %q, got %v", tt.wantType, result["type"])
+			}
+			if result["description"] != tt.wantDesc {
+				t.Errorf("expected description %q, got %v", tt.wantDesc, result["description"])
+			}
+
+			var got []ExplainField
+			decodeInto(t, result["fields"], &got)
+			if !reflect.DeepEqual(got, tt.wantFields) {
+				t.Errorf("fields mismatch\nwant: %#v\ngot:  %#v", tt.wantFields, got)
+			}
+		})
+	}
+}
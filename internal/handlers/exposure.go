@@ -0,0 +1,338 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/connectivity"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/response"
+)
+
+// internalLoadBalancerAnnotations are the annotations cloud providers use to
+// provision a load balancer on a private network, with the value that
+// enables it. An empty value matches any value.
+var internalLoadBalancerAnnotations = map[string]string{
+	"service.beta.kubernetes.io/aws-load-balancer-internal":          "",
+	"service.beta.kubernetes.io/aws-load-balancer-scheme":            "internal",
+	"service.beta.kubernetes.io/azure-load-balancer-internal":        "true",
+	"networking.gke.io/load-balancer-type":                           "Internal",
+	"cloud.google.com/load-balancer-type":                            "Internal",
+	"service.beta.kubernetes.io/oci-load-balancer-internal":          "true",
+	"service.beta.kubernetes.io/openstack-internal-load-balancer":    "true",
+	"service.kubernetes.io/ibm-load-balancer-cloud-provider-ip-type": "private",
+}
+
+// ingressSourceRangeAnnotations are the ingress controller annotations that
+// restrict which client addresses may reach an Ingress.
+var ingressSourceRangeAnnotations = []string{
+	"nginx.ingress.kubernetes.io/whitelist-source-range",
+	"nginx.ingress.kubernetes.io/allowlist-source-range",
+	"alb.ingress.kubernetes.io/inbound-cidrs",
+	"haproxy.org/allow-list",
+	"traefik.ingress.kubernetes.io/whitelist-source-range",
+}
+
+// ExternalExposureReportParams defines the parameters for the external_exposure_report MCP tool.
+type ExternalExposureReportParams struct {
+	// Namespace restricts the report to a namespace.
+	Namespace string `json:"namespace,omitempty" description:"Namespace to report on (leave empty for all namespaces)"`
+
+	// Context specifies which Kubernetes context to use for this operation.
+	Context string `json:"context,omitempty" description:"Kubernetes context to use (defaults to current context from kubeconfig)"`
+}
+
+// ExposedService is a Service reachable from outside the cluster.
+type ExposedService struct {
+	Namespace string   `json:"namespace"`
+	Name      string   `json:"name"`
+	Type      string   `json:"type"`
+	Ports     []string `json:"ports"`
+
+	// Addresses are the load balancer IPs and hostnames and the external
+	// IPs of the Service.
+	Addresses             []string `json:"addresses,omitempty"`
+	SourceRanges          []string `json:"source_ranges,omitempty"`
+	ExternalTrafficPolicy string   `json:"external_traffic_policy,omitempty"`
+	LoadBalancerClass     string   `json:"load_balancer_class,omitempty"`
+
+	// Internal is true when annotations ask the cloud provider for a load
+	// balancer on a private network.
+	Internal bool     `json:"internal,omitempty"`
+	Findings []string `json:"findings,omitempty"`
+}
+
+// IngressTLS is a TLS entry of an Ingress.
+type IngressTLS struct {
+	Hosts      []string `json:"hosts,omitempty"`
+	SecretName string   `json:"secret_name,omitempty"`
+}
+
+// ExposedIngress is an Ingress and how it is exposed.
+type ExposedIngress struct {
+	Namespace    string       `json:"namespace"`
+	Name         string       `json:"name"`
+	Class        string       `json:"class,omitempty"`
+	Hosts        []string     `json:"hosts"`
+	Addresses    []string     `json:"addresses,omitempty"`
+	TLS          []IngressTLS `json:"tls,omitempty"`
+	SourceRanges []string     `json:"source_ranges,omitempty"`
+	Findings     []string     `json:"findings,omitempty"`
+}
+
+// ExternalExposureSummary counts the exposed objects and their findings.
+type ExternalExposureSummary struct {
+	Services  int `json:"services"`
+	Ingresses int `json:"ingresses"`
+	Findings  int `json:"findings"`
+}
+
+// ExternalExposureReportResult is the result of the external_exposure_report
+// MCP tool.
+type ExternalExposureReportResult struct {
+	Services  []ExposedService        `json:"services"`
+	Ingresses []ExposedIngress        `json:"ingresses"`
+	Summary   ExternalExposureSummary `json:"summary"`
+	Warnings  []string                `json:"warnings,omitempty"`
+}
+
+// ExternalExposureReport implements the external_exposure_report MCP tool.
+// It lists everything reachable from outside the cluster: LoadBalancer and
+// NodePort Services and Services with external IPs, with their addresses and
+// source ranges, and Ingresses with their hosts, addresses, and TLS
+// configuration. Findings flag exposure worth a second look, such as a public
+// load balancer open to every address or an Ingress host served without TLS.
+func (h *NetworkHandler) ExternalExposureReport(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var params ExternalExposureReportParams
+	if err := bindParams(request, &params); err != nil {
+		return response.Errorf("failed to parse arguments: %s", err)
+	}
+
+	// Use the appropriate client based on context
+	client, err := h.client.ForContext(ctx, params.Context)
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to create client with context %s: %v", params.Context, err)
+	}
+
+	services, err := client.ListServices(ctx, params.Namespace, metav1.ListOptions{})
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to list services: %v", err)
+	}
+
+	var warnings []string
+
+	exposedIngresses := make([]ExposedIngress, 0)
+	ingresses, err := client.ListIngresses(ctx, params.Namespace, metav1.ListOptions{})
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		warnings = append(warnings, fmt.Sprintf("failed to list ingresses: %v", err))
+	} else {
+		for i := range ingresses.Items {
+			exposedIngresses = append(exposedIngresses, exposedIngress(&ingresses.Items[i]))
+		}
+	}
+
+	exposedServices := make([]ExposedService, 0)
+	for i := range services.Items {
+		if exposed, ok := exposedService(&services.Items[i]); ok {
+			exposedServices = append(exposedServices, exposed)
+		}
+	}
+
+	sort.Slice(exposedServices, func(i, j int) bool {
+		if exposedServices[i].Namespace != exposedServices[j].Namespace {
+			return exposedServices[i].Namespace < exposedServices[j].Namespace
+		}
+		return exposedServices[i].Name < exposedServices[j].Name
+	})
+	sort.Slice(exposedIngresses, func(i, j int) bool {
+		if exposedIngresses[i].Namespace != exposedIngresses[j].Namespace {
+			return exposedIngresses[i].Namespace < exposedIngresses[j].Namespace
+		}
+		return exposedIngresses[i].Name < exposedIngresses[j].Name
+	})
+
+	findings := 0
+	for _, service := range exposedServices {
+		findings += len(service.Findings)
+	}
+	for _, ingress := range exposedIngresses {
+		findings += len(ingress.Findings)
+	}
+
+	return response.JSON(ExternalExposureReportResult{
+		Services:  exposedServices,
+		Ingresses: exposedIngresses,
+		Summary: ExternalExposureSummary{
+			Services:  len(exposedServices),
+			Ingresses: len(exposedIngresses),
+			Findings:  findings,
+		},
+		Warnings: warnings,
+	})
+}
+
+// exposedService describes how a Service is reachable from outside the
+// cluster. It returns false for Services that are only reachable inside it.
+func exposedService(svc *corev1.Service) (ExposedService, bool) {
+	isLoadBalancer := svc.Spec.Type == corev1.ServiceTypeLoadBalancer
+	if !isLoadBalancer && svc.Spec.Type != corev1.ServiceTypeNodePort && len(svc.Spec.ExternalIPs) == 0 {
+		return ExposedService{}, false
+	}
+
+	exposed := ExposedService{
+		Namespace:             svc.Namespace,
+		Name:                  svc.Name,
+		Type:                  string(svc.Spec.Type),
+		Ports:                 make([]string, 0, len(svc.Spec.Ports)),
+		SourceRanges:          svc.Spec.LoadBalancerSourceRanges,
+		ExternalTrafficPolicy: string(svc.Spec.ExternalTrafficPolicy),
+	}
+
+	if svc.Spec.LoadBalancerClass != nil {
+		exposed.LoadBalancerClass = *svc.Spec.LoadBalancerClass
+	}
+
+	for _, port := range svc.Spec.Ports {
+		description := fmt.Sprintf("%d/%s", port.Port, portProtocol(port.Protocol))
+		if port.NodePort != 0 {
+			description += fmt.Sprintf(" (node port %d)", port.NodePort)
+		}
+		exposed.Ports = append(exposed.Ports, description)
+	}
+
+	for _, lb := range svc.Status.LoadBalancer.Ingress {
+		if lb.IP != "" {
+			exposed.Addresses = append(exposed.Addresses, lb.IP)
+		}
+		if lb.Hostname != "" {
+			exposed.Addresses = append(exposed.Addresses, lb.Hostname)
+		}
+	}
+	exposed.Addresses = append(exposed.Addresses, svc.Spec.ExternalIPs...)
+
+	for annotation, want := range internalLoadBalancerAnnotations {
+		if value, ok := svc.Annotations[annotation]; ok && (want == "" || strings.EqualFold(value, want)) {
+			exposed.Internal = true
+		}
+	}
+
+	if isLoadBalancer {
+		switch {
+		case len(svc.Status.LoadBalancer.Ingress) == 0:
+			exposed.Findings = append(exposed.Findings, "the load balancer has no address yet; it may still be provisioning or no load balancer controller handles it")
+		case len(svc.Spec.LoadBalancerSourceRanges) == 0 && !exposed.Internal:
+			exposed.Findings = append(exposed.Findings, "the load balancer accepts traffic from any address; set loadBalancerSourceRanges to restrict it")
+		}
+	}
+
+	if svc.Spec.Type == corev1.ServiceTypeNodePort {
+		exposed.Findings = append(exposed.Findings, "the ports are open on every node, so anything that can reach a node can reach the Service")
+	}
+
+	if len(svc.Spec.ExternalIPs) > 0 {
+		exposed.Findings = append(exposed.Findings, "the Service uses spec.externalIPs, which can intercept traffic to those addresses cluster-wide (CVE-2020-8554)")
+	}
+
+	return exposed, true
+}
+
+// exposedIngress describes an Ingress, its TLS configuration, and the hosts
+// it serves over plain HTTP.
+func exposedIngress(ingress *networkingv1.Ingress) ExposedIngress {
+	exposed := ExposedIngress{
+		Namespace: ingress.Namespace,
+		Name:      ingress.Name,
+		Class:     ingressClass(ingress),
+		Hosts:     make([]string, 0, len(ingress.Spec.Rules)),
+	}
+
+	tlsHosts := make(map[string]bool)
+	for _, tls := range ingress.Spec.TLS {
+		exposed.TLS = append(exposed.TLS, IngressTLS{Hosts: tls.Hosts, SecretName: tls.SecretName})
+		for _, host := range tls.Hosts {
+			tlsHosts[host] = true
+		}
+		if tls.SecretName == "" {
+			exposed.Findings = append(exposed.Findings, fmt.Sprintf("TLS for %s has no secretName, so the ingress controller serves its default certificate", strings.Join(tls.Hosts, ", ")))
+		}
+	}
+
+	var plainHTTP []string
+	for _, rule := range ingress.Spec.Rules {
+		host := rule.Host
+		if host == "" {
+			host = "*"
+		}
+		if containsString(exposed.Hosts, host) {
+			continue
+		}
+		exposed.Hosts = append(exposed.Hosts, host)
+
+		if !tlsHosts[rule.Host] && !coveredByWildcard(rule.Host, tlsHosts) {
+			plainHTTP = append(plainHTTP, host)
+		}
+	}
+	if ingress.Spec.DefaultBackend != nil && len(ingress.Spec.Rules) == 0 {
+		exposed.Hosts = append(exposed.Hosts, "*")
+		if len(ingress.Spec.TLS) == 0 {
+			plainHTTP = append(plainHTTP, "*")
+		}
+	}
+	if len(plainHTTP) > 0 {
+		exposed.Findings = append(exposed.Findings, fmt.Sprintf("served without TLS: %s", strings.Join(plainHTTP, ", ")))
+	}
+
+	for _, lb := range ingress.Status.LoadBalancer.Ingress {
+		if lb.IP != "" {
+			exposed.Addresses = append(exposed.Addresses, lb.IP)
+		}
+		if lb.Hostname != "" {
+			exposed.Addresses = append(exposed.Addresses, lb.Hostname)
+		}
+	}
+
+	for _, annotation := range ingressSourceRangeAnnotations {
+		if value := ingress.Annotations[annotation]; value != "" {
+			for _, cidr := range strings.Split(value, ",") {
+				if cidr = strings.TrimSpace(cidr); cidr != "" {
+					exposed.SourceRanges = append(exposed.SourceRanges, cidr)
+				}
+			}
+		}
+	}
+
+	return exposed
+}
+
+// ingressClass returns the class of an Ingress from ingressClassName or,
+// for older Ingresses, the kubernetes.io/ingress.class annotation.
+func ingressClass(ingress *networkingv1.Ingress) string {
+	if ingress.Spec.IngressClassName != nil {
+		return *ingress.Spec.IngressClassName
+	}
+	return ingress.Annotations["kubernetes.io/ingress.class"]
+}
+
+// coveredByWildcard reports whether a wildcard TLS host such as
+// *.example.com covers host.
+func coveredByWildcard(host string, tlsHosts map[string]bool) bool {
+	if _, parent, ok := strings.Cut(host, "."); ok {
+		return tlsHosts["*."+parent]
+	}
+	return false
+}
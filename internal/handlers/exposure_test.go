@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/fakecluster"
+)
+
+func TestExposedService(t *testing.T) {
+	t.Parallel()
+
+	loadBalancer := func(annotations map[string]string, sourceRanges []string, addresses ...string) *corev1.Service {
+		svc := &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop", Annotations: annotations},
+			Spec: corev1.ServiceSpec{
+				Type:                     corev1.ServiceTypeLoadBalancer,
+				Ports:                    []corev1.ServicePort{{Port: 443, NodePort: 31443}},
+				LoadBalancerSourceRanges: sourceRanges,
+			},
+		}
+		for _, address := range addresses {
+			svc.Status.LoadBalancer.Ingress = append(svc.Status.LoadBalancer.Ingress, corev1.LoadBalancerIngress{IP: address})
+		}
+		return svc
+	}
+
+	tests := []struct {
+		name         string
+		svc          *corev1.Service
+		wantExposed  bool
+		wantInternal bool
+		wantFindings int
+	}{
+		{
+			name: "cluster IP service",
+			svc:  &corev1.Service{Spec: corev1.ServiceSpec{Type: corev1.ServiceTypeClusterIP}},
+		},
+		{
+			name:         "public load balancer open to everyone",
+			svc:          loadBalancer(nil, nil, "203.0.113.10"),
+			wantExposed:  true,
+			wantFindings: 1,
+		},
+		{
+			name:        "load balancer restricted by source ranges",
+			svc:         loadBalancer(nil, []string{"10.0.0.0/8"}, "203.0.113.10"),
+			wantExposed: true,
+		},
+		{
+			name:         "internal load balancer",
+			svc:          loadBalancer(map[string]string{"service.beta.kubernetes.io/aws-load-balancer-scheme": "internal"}, nil, "10.0.0.10"),
+			wantExposed:  true,
+			wantInternal: true,
+		},
+		{
+			name:         "pending load balancer",
+			svc:          loadBalancer(nil, nil),
+			wantExposed:  true,
+			wantFindings: 1,
+		},
+		{
+			name:         "cluster IP service with external IPs",
+			svc:          &corev1.Service{Spec: corev1.ServiceSpec{Type: corev1.ServiceTypeClusterIP, ExternalIPs: []string{"198.51.100.7"}}},
+			wantExposed:  true,
+			wantFindings: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, exposed := exposedService(tt.svc)
+			if exposed != tt.wantExposed {
+				t.Fatalf("expected exposed=%v, got %v", tt.wantExposed, exposed)
+			}
+			if got.Internal != tt.wantInternal {
+				t.Errorf("expected internal=%v, got %v", tt.wantInternal, got.Internal)
+			}
+			if len(got.Findings) != tt.wantFindings {
+				t.Errorf("expected %d findings, got %v", tt.wantFindings, got.Findings)
+			}
+		})
+	}
+}
+
+func TestExternalExposureReport_FakeCluster(t *testing.T) {
+	t.Parallel()
+
+	className := "nginx"
+	handler := NewNetworkHandler(fakecluster.New(fakecluster.Config{
+		Objects: []runtime.Object{
+			&corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop"},
+				Spec: corev1.ServiceSpec{
+					Type:  corev1.ServiceTypeNodePort,
+					Ports: []corev1.ServicePort{{Port: 80, NodePort: 30080}},
+				},
+			},
+			&corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{Name: "internal", Namespace: "shop"},
+				Spec:       corev1.ServiceSpec{Type: corev1.ServiceTypeClusterIP},
+			},
+			&networkingv1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "shop",
+					Namespace:   "shop",
+					Annotations: map[string]string{"nginx.ingress.kubernetes.io/whitelist-source-range": "10.0.0.0/8, 192.168.0.0/16"},
+				},
+				Spec: networkingv1.IngressSpec{
+					IngressClassName: &className,
+					TLS:              []networkingv1.IngressTLS{{Hosts: []string{"*.example.com"}, SecretName: "wildcard-tls"}},
+					Rules: []networkingv1.IngressRule{
+						{Host: "shop.example.com"},
+						{Host: "legacy.example.org"},
+					},
+				},
+			},
+		},
+	}), false)
+
+	result, isErr := callTool(t, handler.ExternalExposureReport, map[string]any{})
+	if isErr {
+		t.Fatalf("unexpected error: %v", result["error"])
+	}
+
+	var services []ExposedService
+	decodeInto(t, result["services"], &services)
+	if len(services) != 1 || services[0].Name != "web" || !reflect.DeepEqual(services[0].Ports, []string{"80/TCP (node port 30080)"}) {
+		t.Errorf("expected only the NodePort service, got %+v", services)
+	}
+
+	var ingresses []ExposedIngress
+	decodeInto(t, result["ingresses"], &ingresses)
+	if len(ingresses) != 1 {
+		t.Fatalf("expected one ingress, got %+v", ingresses)
+	}
+
+	want := ExposedIngress{
+		Namespace:    "shop",
+		Name:         "shop",
+		Class:        "nginx",
+		Hosts:        []string{"shop.example.com", "legacy.example.org"},
+		TLS:          []IngressTLS{{Hosts: []string{"*.example.com"}, SecretName: "wildcard-tls"}},
+		SourceRanges: []string{"10.0.0.0/8", "192.168.0.0/16"},
+		Findings:     []string{"served without TLS: legacy.example.org"},
+	}
+	if !reflect.DeepEqual(ingresses[0], want) {
+		t.Errorf("expected ingress %+v, got %+v", want, ingresses[0])
+	}
+}
@@ -0,0 +1,254 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/connectivity"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/kubernetes"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/response"
+)
+
+// findResourceDefaultLimit is how many matches find_resource returns per
+// resource type when no limit is given.
+const findResourceDefaultLimit = 50
+
+// findResourceCommonTypes are the types find_resource searches when no
+// resource type is given: the ones people usually know by name.
+var findResourceCommonTypes = []string{
+	"pods",
+	"deployments",
+	"statefulsets",
+	"daemonsets",
+	"jobs",
+	"cronjobs",
+	"services",
+	"ingresses",
+	"configmaps",
+	"secrets",
+	"persistentvolumeclaims",
+	"serviceaccounts",
+}
+
+// FindResourceParams defines the parameters for the find_resource MCP tool.
+type FindResourceParams struct {
+	// Name is the name fragment, or the regular expression with Regex set,
+	// to match object names against.
+	Name string `json:"name" required:"true" description:"Part of the name to look for, matched ignoring case (e.g., \"payments\" finds \"payments-api-7d9f8\"), or a regular expression when regex is true"`
+
+	// Regex treats Name as a regular expression.
+	Regex bool `json:"regex,omitempty" description:"When true, name is a Go regular expression matched against the whole name or part of it (e.g., \"^payments-(api|worker)\"). Case-sensitive unless it starts with (?i)"`
+
+	// ResourceType limits the search to a single type.
+	ResourceType string `json:"resource_type,omitempty" description:"The type of resource to search (e.g., \"pods\", \"certificates\"). Leave empty to search the common types: pods, deployments, statefulsets, daemonsets, jobs, cronjobs, services, ingresses, configmaps, secrets, persistentvolumeclaims, and serviceaccounts"`
+
+	// APIVersion optionally constrains the resolution of ResourceType.
+	APIVersion string `json:"api_version,omitempty" description:"API version for resource_type (e.g., \"v1\", \"apps/v1\"), if not provided, the tool will try to resolve the resource type from the API resources list"`
+
+	// Namespace limits the search of namespaced types.
+	Namespace string `json:"namespace,omitempty" description:"Namespace to search in (leave empty for all namespaces). Cluster-scoped types are always searched cluster-wide"`
+
+	// Limit caps the matches returned per resource type.
+	Limit int `json:"limit,omitempty" default:"50" minimum:"1" description:"Maximum number of matches to return per resource type; the result is marked as truncated when a type has more"`
+
+	// Context specifies which Kubernetes context to use for this operation.
+	Context string `json:"context,omitempty" description:"Kubernetes context to use (defaults to current context from kubeconfig)"`
+}
+
+// FindResourceMatch is an object whose name matched.
+type FindResourceMatch struct {
+	Kind       string `json:"kind"`
+	APIVersion string `json:"api_version"`
+	Resource   string `json:"resource"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name"`
+}
+
+// FindResourceResult is the result of the find_resource MCP tool.
+type FindResourceResult struct {
+	Name      string `json:"name"`
+	Regex     bool   `json:"regex,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+
+	// SearchedTypes lists the resource types searched.
+	SearchedTypes []string `json:"searched_types"`
+
+	// Matches are sorted with exact name matches first, then by kind,
+	// namespace, and name.
+	Matches []FindResourceMatch `json:"matches"`
+	Count   int                 `json:"count"`
+
+	// Truncated is set when a type had more matches than the limit.
+	Truncated bool     `json:"truncated,omitempty"`
+	Warnings  []string `json:"warnings,omitempty"`
+}
+
+// findTargetResult holds the matches of one searched type.
+type findTargetResult struct {
+	matches   []FindResourceMatch
+	truncated bool
+}
+
+// FindResource implements the find_resource MCP tool.
+// It finds objects by a fragment of their name, or a regular expression,
+// when the namespace they live in is not known. Names cannot be matched by
+// the API server, so each type is paged through with metadata-only lists,
+// a few types at a time, and the names are matched here.
+func (h *ResourceHandler) FindResource(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var params FindResourceParams
+	if err := bindParams(request, &params); err != nil {
+		return response.Errorf("failed to parse arguments: %s", err)
+	}
+
+	if strings.TrimSpace(params.Name) == "" {
+		return response.Error("name is required: pass part of the name to look for")
+	}
+
+	var matchName func(name string) bool
+	if params.Regex {
+		pattern, err := regexp.Compile(params.Name)
+		if err != nil {
+			return response.Errorf("invalid regular expression: %v", err)
+		}
+		matchName = pattern.MatchString
+	} else {
+		fragment := strings.ToLower(strings.TrimSpace(params.Name))
+		matchName = func(name string) bool { return strings.Contains(strings.ToLower(name), fragment) }
+	}
+
+	limit := params.Limit
+	if limit <= 0 {
+		limit = findResourceDefaultLimit
+	}
+
+	// Use the appropriate client based on context
+	client, err := h.client.ForContext(ctx, params.Context)
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to create client with context %s: %v", params.Context, err)
+	}
+
+	lists, err := client.DiscoverResources(ctx)
+	if err != nil && len(lists) == 0 {
+		if h.alwaysStart && connectivity.IsError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to discover resource types: %v", err)
+	}
+
+	var targets []searchTarget
+	if params.ResourceType != "" {
+		gvr, err := client.ResolveResourceType(params.ResourceType, params.APIVersion)
+		if err != nil {
+			if h.alwaysStart && connectivity.IsError(err) {
+				return response.Error(connectivity.ErrorMessage(err))
+			}
+			return response.Errorf("failed to resolve resource type: %v", err)
+		}
+		if result, err := h.disabledResult(params.ResourceType, gvr); result != nil || err != nil {
+			return result, err
+		}
+		targets = append(targets, searchTargetOf(lists, gvr))
+	} else {
+		// Common types the cluster does not serve, or that are disabled by
+		// configuration, are skipped rather than reported.
+		for _, resourceType := range findResourceCommonTypes {
+			gvr, err := client.ResolveResourceType(resourceType, "")
+			if err != nil || (h.resourceFilter != nil && h.resourceFilter.IsDisabled(gvr)) {
+				continue
+			}
+			targets = append(targets, searchTargetOf(lists, gvr))
+		}
+	}
+
+	found, errs := fetchPerTarget(ctx, targets, func(ctx context.Context, target searchTarget) (findTargetResult, error) {
+		return findInTarget(ctx, client, target, params.Namespace, matchName, limit)
+	})
+
+	result := FindResourceResult{
+		Name:          params.Name,
+		Regex:         params.Regex,
+		Namespace:     params.Namespace,
+		SearchedTypes: make([]string, 0, len(targets)),
+		Matches:       []FindResourceMatch{},
+	}
+
+	for i, target := range targets {
+		result.SearchedTypes = append(result.SearchedTypes, target.gvr.GroupResource().String())
+
+		if errs[i] != nil {
+			if h.alwaysStart && connectivity.IsTransportError(errs[i]) {
+				return response.Error(connectivity.ErrorMessage(errs[i]))
+			}
+			result.Warnings = append(result.Warnings, fmt.Sprintf("failed to search %s: %v", target.gvr.GroupResource(), errs[i]))
+			continue
+		}
+
+		result.Matches = append(result.Matches, found[i].matches...)
+		result.Truncated = result.Truncated || found[i].truncated
+	}
+
+	// An exact name is most likely the object being looked for.
+	exact := func(match FindResourceMatch) bool { return strings.EqualFold(match.Name, params.Name) }
+	sort.SliceStable(result.Matches, func(i, j int) bool {
+		a, b := result.Matches[i], result.Matches[j]
+		if exact(a) != exact(b) {
+			return exact(a)
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+	result.Count = len(result.Matches)
+
+	return response.JSON(result)
+}
+
+// findInTarget pages through the metadata of target's objects and returns
+// up to limit of those whose name matches. It stops reading once the limit
+// is passed.
+func findInTarget(ctx context.Context, client kubernetes.ClusterReader, target searchTarget, namespace string, matchName func(string) bool, limit int) (findTargetResult, error) {
+	var result findTargetResult
+
+	opts := metav1.ListOptions{Limit: customResourcePageSize}
+	for {
+		page, err := client.ListResourceMetadata(ctx, target.gvr, target.listNamespace(namespace), opts)
+		if err != nil {
+			return findTargetResult{}, err //nolint:wrapcheck // kubernetes API errors are self-descriptive
+		}
+
+		for i := range page.Items {
+			if !matchName(page.Items[i].Name) {
+				continue
+			}
+			if len(result.matches) == limit {
+				result.truncated = true
+				return result, nil
+			}
+			result.matches = append(result.matches, FindResourceMatch{
+				Kind:       target.kind,
+				APIVersion: target.gvr.GroupVersion().String(),
+				Resource:   target.gvr.Resource,
+				Namespace:  page.Items[i].Namespace,
+				Name:       page.Items[i].Name,
+			})
+		}
+
+		if page.Continue == "" {
+			return result, nil
+		}
+		opts.Continue = page.Continue
+	}
+}
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"reflect"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/fakecluster"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/resourcefilter"
+)
+
+func TestFindResource(t *testing.T) {
+	t.Parallel()
+
+	client := fakecluster.New(fakecluster.Config{
+		Namespace: "default",
+		Objects: []runtime.Object{
+			&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "payments-api", Namespace: "shop"}},
+			&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "Payments", Namespace: "billing"}},
+			&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "payments-api-7d9f8", Namespace: "shop"}},
+			&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "payments-worker-1", Namespace: "shop"}},
+			&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cart-1", Namespace: "shop"}},
+			&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "payments-keys", Namespace: "billing"}},
+			&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "payments-node"}},
+		},
+	})
+
+	filter, err := resourcefilter.NewFilter("secrets", client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler := NewResourceHandler(client, filter, false)
+
+	result, isErr := callTool(t, handler.FindResource, map[string]any{"name": "payments"})
+	if isErr {
+		t.Fatalf("unexpected error: %v", result["error"])
+	}
+
+	// Every namespace of the common types is searched, even with a default
+	// namespace, and the exact match comes first.
+	want := []any{
+		map[string]any{"kind": "Service", "api_version": "v1", "resource": "services", "namespace": "billing", "name": "Payments"},
+		map[string]any{"kind": "Deployment", "api_version": "apps/v1", "resource": "deployments", "namespace": "shop", "name": "payments-api"},
+		map[string]any{"kind": "Pod", "api_version": "v1", "resource": "pods", "namespace": "shop", "name": "payments-api-7d9f8"},
+		map[string]any{"kind": "Pod", "api_version": "v1", "resource": "pods", "namespace": "shop", "name": "payments-worker-1"},
+	}
+	if !reflect.DeepEqual(result["matches"], want) {
+		t.Errorf("expected matches %v, got %v", want, result["matches"])
+	}
+
+	result, isErr = callTool(t, handler.FindResource, map[string]any{
+		"name":          "^payments-(api|worker)-",
+		"regex":         true,
+		"resource_type": "pods",
+		"limit":         1,
+	})
+	if isErr {
+		t.Fatalf("unexpected error: %v", result["error"])
+	}
+	if result["count"] != float64(1) || result["truncated"] != true {
+		t.Errorf("expected one pod and a truncated result, got %v", result)
+	}
+
+	result, isErr = callTool(t, handler.FindResource, map[string]any{"name": "node", "resource_type": "nodes"})
+	if isErr || result["count"] != float64(1) {
+		t.Errorf("expected the cluster-scoped node to be found, got %v", result)
+	}
+
+	for _, args := range []map[string]any{
+		{"name": " "},
+		{"name": "payments-(", "regex": true},
+		{"name": "payments", "resource_type": "secrets"},
+		{"name": "payments", "resource_type": "widgets"},
+	} {
+		if result, isErr := callTool(t, handler.FindResource, args); !isErr {
+			t.Errorf("expected %v to be rejected, got %v", args, result)
+		}
+	}
+}
@@ -0,0 +1,454 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/connectivity"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/response"
+)
+
+const (
+	// defaultRevisionHistoryLimit is the revisionHistoryLimit Kubernetes uses
+	// for Deployments, StatefulSets, and DaemonSets that do not set one.
+	defaultRevisionHistoryLimit = 10
+
+	// suggestedRevisionHistoryLimit is the revisionHistoryLimit recommended
+	// in the kubectl suggestions; a few revisions are enough to roll back.
+	suggestedRevisionHistoryLimit = 3
+
+	// suggestedJobTTLSeconds is the ttlSecondsAfterFinished recommended in the
+	// kubectl suggestions: one day, long enough to read the logs of a Job.
+	suggestedJobTTLSeconds = 86400
+
+	// defaultGCPolicyMaxItems is how many entries gc_policy_report lists per section.
+	defaultGCPolicyMaxItems = 20
+)
+
+// GCPolicyReportParams defines the parameters for the gc_policy_report MCP tool.
+type GCPolicyReportParams struct {
+	// Namespace restricts the report to a namespace.
+	Namespace string `json:"namespace,omitempty" description:"Namespace to audit (leave empty for all namespaces)"`
+
+	// MaxItems caps the entries listed in each section; totals are always reported.
+	MaxItems int `json:"max_items,omitempty" minimum:"1" default:"20" description:"Maximum number of entries listed per section (defaults to 20). Totals always cover every entry"`
+
+	// Context specifies which Kubernetes context to use for this operation.
+	Context string `json:"context,omitempty" description:"Kubernetes context to use (defaults to current context from kubeconfig)"`
+}
+
+// JobWithoutTTL is a Job that is never cleaned up automatically because it
+// has no ttlSecondsAfterFinished and no CronJob owns it.
+type JobWithoutTTL struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+
+	// Status is Complete, Failed, or Active.
+	Status string `json:"status"`
+	Age    string `json:"age"`
+
+	// FinishedAgo is how long ago the Job completed or failed.
+	FinishedAgo string `json:"finished_ago,omitempty"`
+	Suggestion  string `json:"suggestion"`
+
+	// finishedAt orders finished Jobs from the oldest.
+	finishedAt time.Time
+}
+
+// RevisionHistory is a workload that keeps the default or a larger number of
+// old revisions for rollbacks.
+type RevisionHistory struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+
+	// Limit is the effective revisionHistoryLimit, and Default is true when
+	// the workload does not set it.
+	Limit   int32 `json:"revision_history_limit"`
+	Default bool  `json:"default"`
+
+	// RetainedReplicaSets counts the old, scaled-down ReplicaSets a
+	// Deployment keeps. It is only reported for Deployments.
+	RetainedReplicaSets *int   `json:"retained_replica_sets,omitempty"`
+	Suggestion          string `json:"suggestion"`
+}
+
+// TerminatedPods counts the pods of a namespace that finished running and
+// are kept until someone deletes them.
+type TerminatedPods struct {
+	Namespace string `json:"namespace"`
+	Succeeded int    `json:"succeeded"`
+	Failed    int    `json:"failed"`
+
+	// OwnedByJobs counts the terminated pods that belong to a Job; they are
+	// deleted together with their Job.
+	OwnedByJobs int `json:"owned_by_jobs"`
+
+	// OldestAge is the age of the oldest terminated pod.
+	OldestAge   string   `json:"oldest_age"`
+	Suggestions []string `json:"suggestions"`
+
+	oldest time.Time
+}
+
+// GCPolicyReportResult is the result of the gc_policy_report MCP tool.
+type GCPolicyReportResult struct {
+	Namespace            string            `json:"namespace"`
+	JobsWithoutTTLCount  int               `json:"jobs_without_ttl_count"`
+	JobsWithoutTTL       []JobWithoutTTL   `json:"jobs_without_ttl"`
+	RevisionHistoryCount int               `json:"revision_history_count"`
+	RevisionHistory      []RevisionHistory `json:"revision_history"`
+	TerminatedPodCount   int               `json:"terminated_pod_count"`
+	TerminatedPods       []TerminatedPods  `json:"terminated_pods"`
+	Notes                []string          `json:"notes"`
+	Warnings             []string          `json:"warnings,omitempty"`
+}
+
+// GCPolicyReport implements the gc_policy_report MCP tool.
+// It audits how finished and superseded objects get cleaned up: Jobs without
+// ttlSecondsAfterFinished, workloads that keep the default or a larger
+// revisionHistoryLimit, and Succeeded or Failed pods that accumulate per
+// namespace. Every finding comes with a kubectl command as a suggestion; the
+// tool itself never changes the cluster. Sections other than Jobs are best
+// effort and report listing failures under warnings.
+func (h *WorkloadHandler) GCPolicyReport(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var params GCPolicyReportParams
+	if err := bindParams(request, &params); err != nil {
+		return response.Errorf("failed to parse arguments: %s", err)
+	}
+
+	maxItems := params.MaxItems
+	if maxItems == 0 {
+		maxItems = defaultGCPolicyMaxItems
+	}
+
+	// Use the appropriate client based on context
+	client, err := h.client.ForContext(ctx, params.Context)
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to create client with context %s: %v", params.Context, err)
+	}
+
+	jobList, err := client.ListJobs(ctx, params.Namespace, metav1.ListOptions{})
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to list jobs: %v", err)
+	}
+
+	now := h.now()
+	var warnings []string
+
+	jobs := jobsWithoutTTL(jobList.Items, now)
+
+	var replicaSets []appsv1.ReplicaSet
+	if list, err := client.ListReplicaSets(ctx, params.Namespace, metav1.ListOptions{}); err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		warnings = append(warnings, fmt.Sprintf("failed to list replica sets, retained ReplicaSets were not counted: %v", err))
+	} else {
+		replicaSets = list.Items
+	}
+
+	revisions := make([]RevisionHistory, 0)
+	if list, err := client.ListDeployments(ctx, params.Namespace, metav1.ListOptions{}); err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		warnings = append(warnings, fmt.Sprintf("failed to list deployments: %v", err))
+	} else {
+		revisions = append(revisions, deploymentRevisionHistory(list.Items, replicaSets)...)
+	}
+
+	if list, err := client.ListStatefulSets(ctx, params.Namespace, metav1.ListOptions{}); err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		warnings = append(warnings, fmt.Sprintf("failed to list statefulsets: %v", err))
+	} else {
+		for i := range list.Items {
+			statefulSet := &list.Items[i]
+			if entry, ok := revisionHistory("StatefulSet", statefulSet.Namespace, statefulSet.Name, statefulSet.Spec.RevisionHistoryLimit); ok {
+				revisions = append(revisions, entry)
+			}
+		}
+	}
+
+	if list, err := client.ListDaemonSets(ctx, params.Namespace, metav1.ListOptions{}); err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		warnings = append(warnings, fmt.Sprintf("failed to list daemonsets: %v", err))
+	} else {
+		for i := range list.Items {
+			daemonSet := &list.Items[i]
+			if entry, ok := revisionHistory("DaemonSet", daemonSet.Namespace, daemonSet.Name, daemonSet.Spec.RevisionHistoryLimit); ok {
+				revisions = append(revisions, entry)
+			}
+		}
+	}
+
+	sortRevisionHistory(revisions)
+
+	terminated := make([]TerminatedPods, 0)
+	terminatedCount := 0
+	if list, err := client.ListPods(ctx, params.Namespace, metav1.ListOptions{}); err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		warnings = append(warnings, fmt.Sprintf("failed to list pods: %v", err))
+	} else {
+		terminated = terminatedPodsByNamespace(list.Items, now)
+		for _, entry := range terminated {
+			terminatedCount += entry.Succeeded + entry.Failed
+		}
+	}
+
+	return response.JSON(GCPolicyReportResult{
+		Namespace:            params.Namespace,
+		JobsWithoutTTLCount:  len(jobs),
+		JobsWithoutTTL:       truncate(jobs, maxItems),
+		RevisionHistoryCount: len(revisions),
+		RevisionHistory:      truncate(revisions, maxItems),
+		TerminatedPodCount:   terminatedCount,
+		TerminatedPods:       truncate(terminated, maxItems),
+		Notes: []string{
+			"Jobs created by CronJobs are not listed: the CronJob's successfulJobsHistoryLimit and failedJobsHistoryLimit already bound them.",
+			"Set ttlSecondsAfterFinished and revisionHistoryLimit in the manifests or charts that create these objects too, or the next apply reverts the patch.",
+			"The kube-controller-manager only deletes terminated pods once the whole cluster has more than --terminated-pod-gc-threshold of them (12500 by default).",
+			"Suggestions are kubectl commands to review and run manually; this server never changes the cluster.",
+		},
+		Warnings: warnings,
+	})
+}
+
+// jobsWithoutTTL returns the Jobs that no CronJob owns and that have no
+// ttlSecondsAfterFinished, finished Jobs first from the oldest, then the
+// ones still running.
+func jobsWithoutTTL(jobs []batchv1.Job, now time.Time) []JobWithoutTTL {
+	out := make([]JobWithoutTTL, 0)
+
+	for i := range jobs {
+		job := &jobs[i]
+
+		if job.Spec.TTLSecondsAfterFinished != nil {
+			continue
+		}
+
+		if owner := metav1.GetControllerOf(job); owner != nil && owner.Kind == "CronJob" {
+			continue
+		}
+
+		entry := JobWithoutTTL{
+			Namespace: job.Namespace,
+			Name:      job.Name,
+			Status:    "Active",
+			Age:       now.Sub(job.CreationTimestamp.Time).Round(time.Second).String(),
+			Suggestion: fmt.Sprintf("kubectl patch job %s -n %s --type=merge -p '{\"spec\":{\"ttlSecondsAfterFinished\":%d}}'",
+				job.Name, job.Namespace, suggestedJobTTLSeconds),
+		}
+
+		for _, condition := range job.Status.Conditions {
+			if condition.Status != corev1.ConditionTrue {
+				continue
+			}
+
+			switch condition.Type {
+			case batchv1.JobComplete:
+				entry.Status = "Complete"
+				entry.finishedAt = condition.LastTransitionTime.Time
+			case batchv1.JobFailed:
+				entry.Status = "Failed"
+				entry.finishedAt = condition.LastTransitionTime.Time
+			}
+		}
+
+		if entry.Status == "Complete" && job.Status.CompletionTime != nil {
+			entry.finishedAt = job.Status.CompletionTime.Time
+		}
+
+		if !entry.finishedAt.IsZero() {
+			entry.FinishedAgo = now.Sub(entry.finishedAt).Round(time.Second).String()
+		}
+
+		out = append(out, entry)
+	}
+
+	sort.SliceStable(out, func(i, j int) bool {
+		iFinished, jFinished := out[i].Status != "Active", out[j].Status != "Active"
+		if iFinished != jFinished {
+			return iFinished
+		}
+		if !out[i].finishedAt.Equal(out[j].finishedAt) {
+			return out[i].finishedAt.Before(out[j].finishedAt)
+		}
+		if out[i].Namespace != out[j].Namespace {
+			return out[i].Namespace < out[j].Namespace
+		}
+		return out[i].Name < out[j].Name
+	})
+
+	return out
+}
+
+// deploymentRevisionHistory reports the Deployments that keep the default or
+// a larger revisionHistoryLimit, with the old ReplicaSets each one retains.
+func deploymentRevisionHistory(deployments []appsv1.Deployment, replicaSets []appsv1.ReplicaSet) []RevisionHistory {
+	// Old ReplicaSets are scaled down to zero and kept for rollbacks.
+	retained := make(map[string]int)
+	for i := range replicaSets {
+		replicaSet := &replicaSets[i]
+
+		owner := metav1.GetControllerOf(replicaSet)
+		if owner == nil || owner.Kind != "Deployment" {
+			continue
+		}
+
+		if replicaSet.Spec.Replicas != nil && *replicaSet.Spec.Replicas == 0 {
+			retained[replicaSet.Namespace+"/"+owner.Name]++
+		}
+	}
+
+	out := make([]RevisionHistory, 0)
+	for i := range deployments {
+		deployment := &deployments[i]
+
+		entry, ok := revisionHistory("Deployment", deployment.Namespace, deployment.Name, deployment.Spec.RevisionHistoryLimit)
+		if !ok {
+			continue
+		}
+
+		if replicaSets != nil {
+			count := retained[deployment.Namespace+"/"+deployment.Name]
+			entry.RetainedReplicaSets = &count
+		}
+
+		out = append(out, entry)
+	}
+
+	return out
+}
+
+// revisionHistory reports a workload whose revisionHistoryLimit is unset or
+// at least the default, and whether it should be reported at all.
+func revisionHistory(kind, namespace, name string, limit *int32) (RevisionHistory, bool) {
+	entry := RevisionHistory{
+		Kind:      kind,
+		Namespace: namespace,
+		Name:      name,
+		Limit:     defaultRevisionHistoryLimit,
+		Default:   limit == nil,
+	}
+
+	if limit != nil {
+		entry.Limit = *limit
+	}
+
+	if entry.Limit < defaultRevisionHistoryLimit {
+		return RevisionHistory{}, false
+	}
+
+	entry.Suggestion = fmt.Sprintf("kubectl patch %s %s -n %s --type=merge -p '{\"spec\":{\"revisionHistoryLimit\":%d}}'",
+		strings.ToLower(kind), name, namespace, suggestedRevisionHistoryLimit)
+
+	return entry, true
+}
+
+// sortRevisionHistory orders workloads by the ReplicaSets they retain, then
+// by the largest limit, then by kind, namespace, and name.
+func sortRevisionHistory(entries []RevisionHistory) {
+	retained := func(entry RevisionHistory) int {
+		if entry.RetainedReplicaSets == nil {
+			return 0
+		}
+		return *entry.RetainedReplicaSets
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		if ri, rj := retained(entries[i]), retained(entries[j]); ri != rj {
+			return ri > rj
+		}
+		if entries[i].Limit != entries[j].Limit {
+			return entries[i].Limit > entries[j].Limit
+		}
+		if entries[i].Kind != entries[j].Kind {
+			return entries[i].Kind < entries[j].Kind
+		}
+		if entries[i].Namespace != entries[j].Namespace {
+			return entries[i].Namespace < entries[j].Namespace
+		}
+		return entries[i].Name < entries[j].Name
+	})
+}
+
+// terminatedPodsByNamespace counts the Succeeded and Failed pods of every
+// namespace that has any, ordered by the most terminated pods first.
+func terminatedPodsByNamespace(pods []corev1.Pod, now time.Time) []TerminatedPods {
+	byNamespace := make(map[string]*TerminatedPods)
+
+	for i := range pods {
+		pod := &pods[i]
+
+		if pod.Status.Phase != corev1.PodSucceeded && pod.Status.Phase != corev1.PodFailed {
+			continue
+		}
+
+		entry, ok := byNamespace[pod.Namespace]
+		if !ok {
+			entry = &TerminatedPods{Namespace: pod.Namespace}
+			byNamespace[pod.Namespace] = entry
+		}
+
+		if pod.Status.Phase == corev1.PodSucceeded {
+			entry.Succeeded++
+		} else {
+			entry.Failed++
+		}
+
+		if owner := metav1.GetControllerOf(pod); owner != nil && owner.Kind == "Job" {
+			entry.OwnedByJobs++
+		}
+
+		if entry.oldest.IsZero() || pod.CreationTimestamp.Time.Before(entry.oldest) {
+			entry.oldest = pod.CreationTimestamp.Time
+		}
+	}
+
+	out := make([]TerminatedPods, 0, len(byNamespace))
+	for _, entry := range byNamespace {
+		entry.OldestAge = now.Sub(entry.oldest).Round(time.Second).String()
+
+		entry.Suggestions = make([]string, 0, 2)
+		if entry.Succeeded > 0 {
+			entry.Suggestions = append(entry.Suggestions, fmt.Sprintf("kubectl delete pods -n %s --field-selector=status.phase==Succeeded", entry.Namespace))
+		}
+		if entry.Failed > 0 {
+			entry.Suggestions = append(entry.Suggestions, fmt.Sprintf("kubectl delete pods -n %s --field-selector=status.phase==Failed", entry.Namespace))
+		}
+
+		out = append(out, *entry)
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		ti, tj := out[i].Succeeded+out[i].Failed, out[j].Succeeded+out[j].Failed
+		if ti != tj {
+			return ti > tj
+		}
+		return out[i].Namespace < out[j].Namespace
+	})
+
+	return out
+}
@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/fakecluster"
+)
+
+func TestJobsWithoutTTL(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	ttl := int32(600)
+	isController := true
+
+	job := func(name string, finished time.Time, conditionType batchv1.JobConditionType) batchv1.Job {
+		job := batchv1.Job{ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         "batch",
+			CreationTimestamp: metav1.NewTime(now.Add(-48 * time.Hour)),
+		}}
+		if conditionType != "" {
+			job.Status.Conditions = []batchv1.JobCondition{{
+				Type:               conditionType,
+				Status:             corev1.ConditionTrue,
+				LastTransitionTime: metav1.NewTime(finished),
+			}}
+		}
+		return job
+	}
+
+	withTTL := job("with-ttl", now.Add(-time.Hour), batchv1.JobComplete)
+	withTTL.Spec.TTLSecondsAfterFinished = &ttl
+
+	fromCronJob := job("nightly-29000000", now.Add(-time.Hour), batchv1.JobComplete)
+	fromCronJob.OwnerReferences = []metav1.OwnerReference{{Kind: "CronJob", Name: "nightly", Controller: &isController}}
+
+	got := jobsWithoutTTL([]batchv1.Job{
+		job("running", time.Time{}, ""),
+		job("recent", now.Add(-time.Hour), batchv1.JobComplete),
+		job("old-failure", now.Add(-24*time.Hour), batchv1.JobFailed),
+		withTTL,
+		fromCronJob,
+	}, now)
+
+	var names, statuses, finished []string
+	for _, entry := range got {
+		names = append(names, entry.Name)
+		statuses = append(statuses, entry.Status)
+		finished = append(finished, entry.FinishedAgo)
+	}
+
+	if want := []string{"old-failure", "recent", "running"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("expected jobs %v, got %v", want, names)
+	}
+	if want := []string{"Failed", "Complete", "Active"}; !reflect.DeepEqual(statuses, want) {
+		t.Errorf("expected statuses %v, got %v", want, statuses)
+	}
+	if want := []string{"24h0m0s", "1h0m0s", ""}; !reflect.DeepEqual(finished, want) {
+		t.Errorf("expected finished ages %v, got %v", want, finished)
+	}
+	if want := `kubectl patch job recent -n batch --type=merge -p '{"spec":{"ttlSecondsAfterFinished":86400}}'`; got[1].Suggestion != want {
+		t.Errorf("expected suggestion %q, got %q", want, got[1].Suggestion)
+	}
+}
+
+func TestGCPolicyReport_FakeCluster(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	isController := true
+	zero := int32(0)
+	three := int32(3)
+	twenty := int32(20)
+
+	oldReplicaSet := func(name string) *appsv1.ReplicaSet {
+		return &appsv1.ReplicaSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            name,
+				Namespace:       "shop",
+				OwnerReferences: []metav1.OwnerReference{{Kind: "Deployment", Name: "web", Controller: &isController}},
+			},
+			Spec: appsv1.ReplicaSetSpec{Replicas: &zero},
+		}
+	}
+
+	terminatedPod := func(name string, phase corev1.PodPhase, age time.Duration) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "batch", CreationTimestamp: metav1.NewTime(now.Add(-age))},
+			Status:     corev1.PodStatus{Phase: phase},
+		}
+	}
+
+	handler := NewWorkloadHandler(fakecluster.New(fakecluster.Config{
+		Objects: []runtime.Object{
+			&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop"}},
+			&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "shop"}, Spec: appsv1.DeploymentSpec{RevisionHistoryLimit: &three}},
+			&appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "shop"}, Spec: appsv1.StatefulSetSpec{RevisionHistoryLimit: &twenty}},
+			oldReplicaSet("web-1"),
+			oldReplicaSet("web-2"),
+			terminatedPod("done-1", corev1.PodSucceeded, time.Hour),
+			terminatedPod("done-2", corev1.PodSucceeded, 3*time.Hour),
+			terminatedPod("broken", corev1.PodFailed, 2*time.Hour),
+			&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "running", Namespace: "batch"}, Status: corev1.PodStatus{Phase: corev1.PodRunning}},
+			&batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "migrate", Namespace: "shop", CreationTimestamp: metav1.NewTime(now.Add(-time.Hour))}},
+		},
+	}), false)
+	handler.now = func() time.Time { return now }
+
+	result, isErr := callTool(t, handler.GCPolicyReport, map[string]any{})
+	if isErr {
+		t.Fatalf("unexpected error: %v", result["error"])
+	}
+
+	var jobs []JobWithoutTTL
+	decodeInto(t, result["jobs_without_ttl"], &jobs)
+	if len(jobs) != 1 || jobs[0].Name != "migrate" || jobs[0].Status != "Active" {
+		t.Errorf("expected the active migrate job, got %+v", jobs)
+	}
+
+	var revisions []RevisionHistory
+	decodeInto(t, result["revision_history"], &revisions)
+
+	var workloads []string
+	for _, entry := range revisions {
+		workloads = append(workloads, entry.Kind+"/"+entry.Name)
+	}
+	if want := []string{"Deployment/web", "StatefulSet/db"}; !reflect.DeepEqual(workloads, want) {
+		t.Fatalf("expected revision history entries %v, got %v", want, workloads)
+	}
+	if revisions[0].RetainedReplicaSets == nil || *revisions[0].RetainedReplicaSets != 2 || !revisions[0].Default {
+		t.Errorf("expected web to retain 2 ReplicaSets with the default limit, got %+v", revisions[0])
+	}
+
+	var terminated []TerminatedPods
+	decodeInto(t, result["terminated_pods"], &terminated)
+	want := []TerminatedPods{{
+		Namespace: "batch",
+		Succeeded: 2,
+		Failed:    1,
+		OldestAge: "3h0m0s",
+		Suggestions: []string{
+			"kubectl delete pods -n batch --field-selector=status.phase==Succeeded",
+			"kubectl delete pods -n batch --field-selector=status.phase==Failed",
+		},
+	}}
+	if !reflect.DeepEqual(terminated, want) {
+		t.Errorf("expected terminated pods %+v, got %+v", want, terminated)
+	}
+}
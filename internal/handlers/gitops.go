@@ -0,0 +1,454 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/connectivity"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/resourcefilter"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/response"
+)
+
+// maxOutOfSyncResources bounds how many drifted resources are listed per
+// Argo CD Application; the count is always reported in the finding.
+const maxOutOfSyncResources = 10
+
+// gitopsKinds are the GitOps object types get_gitops_status reports, with
+// the API versions to look for, newest first.
+var gitopsKinds = []struct {
+	tool        string
+	kind        string
+	resource    string
+	apiVersions []string
+	summarize   func(*unstructured.Unstructured) GitOpsObject
+}{
+	{"argocd", "Application", "applications", []string{"argoproj.io/v1alpha1"}, argoApplication},
+	{"flux", "Kustomization", "kustomizations", []string{"kustomize.toolkit.fluxcd.io/v1", "kustomize.toolkit.fluxcd.io/v1beta2"}, fluxKustomization},
+	{"flux", "HelmRelease", "helmreleases", []string{"helm.toolkit.fluxcd.io/v2", "helm.toolkit.fluxcd.io/v2beta2", "helm.toolkit.fluxcd.io/v2beta1"}, fluxHelmRelease},
+}
+
+// GetGitOpsStatusParams defines the parameters for the get_gitops_status MCP tool.
+type GetGitOpsStatusParams struct {
+	// Namespace limits the GitOps objects reported.
+	Namespace string `json:"namespace,omitempty" description:"Namespace of the GitOps objects to report (leave empty for all namespaces). Argo CD Applications usually live in the argocd namespace"`
+
+	// Tool limits the report to Argo CD or Flux.
+	Tool string `json:"tool,omitempty" enum:"argocd,flux" description:"Only report objects of this GitOps tool (leave empty for both)"`
+
+	// OnlyProblems limits the report to objects with findings.
+	OnlyProblems bool `json:"only_problems,omitempty" description:"When true, returns only the objects with findings, such as out-of-sync, degraded, or failing ones"`
+
+	// Context specifies which Kubernetes context to use for this operation.
+	Context string `json:"context,omitempty" description:"Kubernetes context to use (defaults to current context from kubeconfig)"`
+}
+
+// GitOpsObject summarizes an Argo CD Application, Flux Kustomization, or
+// Flux HelmRelease.
+type GitOpsObject struct {
+	// Tool is "argocd" or "flux".
+	Tool      string `json:"tool"`
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+
+	// Source is where the desired state comes from: a Git repository and
+	// path, a Flux source, or a Helm chart.
+	Source      string `json:"source,omitempty"`
+	Destination string `json:"destination,omitempty"`
+
+	// Revision is the last revision synced or applied. AttemptedRevision is
+	// set when Flux last attempted a different one.
+	Revision          string `json:"revision,omitempty"`
+	AttemptedRevision string `json:"attempted_revision,omitempty"`
+
+	// Sync and Health are Argo CD's sync and health statuses.
+	Sync   string `json:"sync,omitempty"`
+	Health string `json:"health,omitempty"`
+
+	// Ready is the status of Flux's Ready condition: True, False, or Unknown.
+	Ready     string `json:"ready,omitempty"`
+	Reason    string `json:"reason,omitempty"`
+	Message   string `json:"message,omitempty"`
+	Suspended bool   `json:"suspended,omitempty"`
+
+	// LastReconciled is when Argo CD last compared the Application with its
+	// source, or when Flux's Ready condition last changed.
+	LastReconciled string `json:"last_reconciled,omitempty"`
+
+	// OutOfSync lists the resources of an Argo CD Application that differ
+	// from the source, up to maxOutOfSyncResources.
+	OutOfSync []string `json:"out_of_sync_resources,omitempty"`
+	Findings  []string `json:"findings,omitempty"`
+}
+
+// GetGitOpsStatusResult is the result of the get_gitops_status MCP tool.
+// When neither Argo CD nor Flux is installed, only Installed, which is then
+// false, and Message are set.
+type GetGitOpsStatusResult struct {
+	// Installed reports, by tool, whether Argo CD and Flux are installed.
+	Installed any    `json:"installed"`
+	Message   string `json:"message,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+
+	Objects      []GitOpsObject `json:"objects,omitzero"`
+	Count        int            `json:"count"`
+	WithFindings int            `json:"with_findings"`
+
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// GetGitOpsStatus implements the get_gitops_status MCP tool.
+// It detects Argo CD and Flux through their CRDs and reports the sync and
+// health status, revision, last reconcile, and error messages of Argo CD
+// Applications, Flux Kustomizations, and Flux HelmReleases, flagging the ones
+// that drifted from Git or fail to reconcile. If neither tool is installed,
+// it returns a result saying so instead of an error.
+func (h *ResourceHandler) GetGitOpsStatus(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var params GetGitOpsStatusParams
+	if err := bindParams(request, &params); err != nil {
+		return response.Errorf("failed to parse arguments: %s", err)
+	}
+
+	// Use the appropriate client based on context
+	client, err := h.client.ForContext(ctx, params.Context)
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to create client with context %s: %v", params.Context, err)
+	}
+
+	var warnings []string
+	installed := map[string]bool{"argocd": false, "flux": false}
+	objects := make([]GitOpsObject, 0)
+	withFindings := 0
+
+	for _, gitopsKind := range gitopsKinds {
+		if ctx.Err() != nil {
+			break
+		}
+		if params.Tool != "" && params.Tool != gitopsKind.tool {
+			continue
+		}
+
+		var resolveErr error
+		found := false
+		for _, apiVersion := range gitopsKind.apiVersions {
+			gvr, err := client.ResolveResourceType(gitopsKind.resource, apiVersion)
+			if err != nil {
+				if connectivity.IsError(err) {
+					resolveErr = err
+					break
+				}
+				continue
+			}
+			found = true
+			installed[gitopsKind.tool] = true
+
+			if h.resourceFilter != nil && h.resourceFilter.IsDisabled(gvr) {
+				warnings = append(warnings, fmt.Sprintf("%s are disabled by configuration and were not checked", resourcefilter.FormatGVR(gvr)))
+				break
+			}
+
+			list, err := client.ListResources(ctx, gvr, params.Namespace, metav1.ListOptions{})
+			if err != nil {
+				if h.alwaysStart && connectivity.IsTransportError(err) {
+					return response.Error(connectivity.ErrorMessage(err))
+				}
+				warnings = append(warnings, fmt.Sprintf("failed to list %s: %v", resourcefilter.FormatGVR(gvr), err))
+				break
+			}
+
+			for i := range list.Items {
+				object := gitopsKind.summarize(&list.Items[i])
+				if len(object.Findings) > 0 {
+					withFindings++
+				} else if params.OnlyProblems {
+					continue
+				}
+				objects = append(objects, object)
+			}
+			break
+		}
+
+		if resolveErr != nil {
+			if h.alwaysStart {
+				return response.Error(connectivity.ErrorMessage(resolveErr))
+			}
+			warnings = append(warnings, fmt.Sprintf("failed to discover %s: %v", gitopsKind.resource, resolveErr))
+		} else if !found && params.Tool == gitopsKind.tool {
+			warnings = append(warnings, fmt.Sprintf("%s (%s) are not served by this cluster", gitopsKind.resource, strings.Join(gitopsKind.apiVersions, ", ")))
+		}
+	}
+
+	if !installed["argocd"] && !installed["flux"] && len(warnings) == 0 {
+		return response.JSON(GetGitOpsStatusResult{
+			Installed: false,
+			Message:   "Neither Argo CD (argoproj.io Applications) nor Flux (toolkit.fluxcd.io Kustomizations and HelmReleases) is installed in this cluster, so there is no GitOps status to report.",
+		})
+	}
+
+	// Objects with findings first, then by tool, namespace, and name
+	sort.SliceStable(objects, func(i, j int) bool {
+		a, b := objects[i], objects[j]
+		return findingsFirst(len(a.Findings), len(b.Findings), a.Tool+"/"+a.Namespace+"/"+a.Name, b.Tool+"/"+b.Namespace+"/"+b.Name)
+	})
+
+	return response.JSON(GetGitOpsStatusResult{
+		Installed:    installed,
+		Namespace:    params.Namespace,
+		Objects:      objects,
+		Count:        len(objects),
+		WithFindings: withFindings,
+		Warnings:     warnings,
+	})
+}
+
+// argoApplication summarizes an Argo CD Application and explains why it may
+// need attention.
+func argoApplication(obj *unstructured.Unstructured) GitOpsObject {
+	entry := GitOpsObject{Tool: "argocd", Kind: "Application", Namespace: obj.GetNamespace(), Name: obj.GetName()}
+
+	var sources []string
+	if source, ok, _ := unstructured.NestedMap(obj.Object, "spec", "source"); ok {
+		sources = append(sources, argoSource(source))
+	}
+	multiple, _, _ := unstructured.NestedSlice(obj.Object, "spec", "sources")
+	for _, raw := range multiple {
+		if source, ok := raw.(map[string]interface{}); ok {
+			sources = append(sources, argoSource(source))
+		}
+	}
+	entry.Source = strings.Join(sources, ", ")
+
+	server, _, _ := unstructured.NestedString(obj.Object, "spec", "destination", "server")
+	if server == "" {
+		server, _, _ = unstructured.NestedString(obj.Object, "spec", "destination", "name")
+	}
+	namespace, _, _ := unstructured.NestedString(obj.Object, "spec", "destination", "namespace")
+	entry.Destination = strings.Trim(server+"/"+namespace, "/")
+
+	entry.Sync, _, _ = unstructured.NestedString(obj.Object, "status", "sync", "status")
+	entry.Revision, _, _ = unstructured.NestedString(obj.Object, "status", "sync", "revision")
+	entry.Health, _, _ = unstructured.NestedString(obj.Object, "status", "health", "status")
+	entry.Message, _, _ = unstructured.NestedString(obj.Object, "status", "health", "message")
+	entry.LastReconciled, _, _ = unstructured.NestedString(obj.Object, "status", "reconciledAt")
+	if entry.Sync == "" {
+		entry.Sync = "Unknown"
+	}
+	if entry.Health == "" {
+		entry.Health = "Unknown"
+	}
+
+	outOfSync := 0
+	resources, _, _ := unstructured.NestedSlice(obj.Object, "status", "resources")
+	for _, raw := range resources {
+		resource, ok := raw.(map[string]interface{})
+		if !ok || resource["status"] != "OutOfSync" {
+			continue
+		}
+		outOfSync++
+		if len(entry.OutOfSync) < maxOutOfSyncResources {
+			kind, _ := resource["kind"].(string)
+			name, _ := resource["name"].(string)
+			if namespace, _ := resource["namespace"].(string); namespace != "" {
+				name = namespace + "/" + name
+			}
+			entry.OutOfSync = append(entry.OutOfSync, kind+" "+name)
+		}
+	}
+
+	_, automated, _ := unstructured.NestedMap(obj.Object, "spec", "syncPolicy", "automated")
+	switch entry.Sync {
+	case "OutOfSync":
+		finding := "is OutOfSync with its source"
+		if outOfSync > 0 {
+			finding = fmt.Sprintf("is OutOfSync: %d resource(s) differ from the source", outOfSync)
+		}
+		if !automated {
+			finding += "; automated sync is disabled, so the drift stays until someone syncs it"
+		}
+		entry.Findings = append(entry.Findings, finding)
+	case "Unknown":
+		entry.Findings = append(entry.Findings, "sync status is Unknown; Argo CD could not compare it with its source")
+	}
+
+	switch entry.Health {
+	case "Degraded", "Missing", "Unknown":
+		finding := "health is " + entry.Health
+		if entry.Message != "" {
+			finding += ": " + entry.Message
+		}
+		entry.Findings = append(entry.Findings, finding)
+	}
+
+	phase, _, _ := unstructured.NestedString(obj.Object, "status", "operationState", "phase")
+	if phase == "Failed" || phase == "Error" {
+		message, _, _ := unstructured.NestedString(obj.Object, "status", "operationState", "message")
+		finishedAt, _, _ := unstructured.NestedString(obj.Object, "status", "operationState", "finishedAt")
+		finding := "the last sync failed"
+		if phase == "Error" {
+			finding = "the last sync errored"
+		}
+		if finishedAt != "" {
+			finding += " at " + finishedAt
+		}
+		if message != "" {
+			finding += ": " + message
+		}
+		entry.Findings = append(entry.Findings, finding)
+	}
+
+	// Argo CD reports problems such as ComparisonError or SyncError as
+	// application conditions.
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, raw := range conditions {
+		condition, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		conditionType, _ := condition["type"].(string)
+		message, _ := condition["message"].(string)
+		if strings.HasSuffix(conditionType, "Error") || strings.HasSuffix(conditionType, "Warning") {
+			entry.Findings = append(entry.Findings, conditionType+": "+message)
+		}
+	}
+
+	return entry
+}
+
+// argoSource renders an Application source as repository, path or chart,
+// and target revision.
+func argoSource(source map[string]interface{}) string {
+	rendered, _, _ := unstructured.NestedString(source, "repoURL")
+	if path, _, _ := unstructured.NestedString(source, "path"); path != "" {
+		rendered += " " + path
+	}
+	if chart, _, _ := unstructured.NestedString(source, "chart"); chart != "" {
+		rendered += " chart " + chart
+	}
+	if revision, _, _ := unstructured.NestedString(source, "targetRevision"); revision != "" {
+		rendered += "@" + revision
+	}
+	return rendered
+}
+
+// fluxKustomization summarizes a Flux Kustomization and explains why it may
+// need attention.
+func fluxKustomization(obj *unstructured.Unstructured) GitOpsObject {
+	entry := fluxObject(obj, "Kustomization")
+
+	entry.Source = fluxSourceRef(obj, "spec", "sourceRef")
+	if path, _, _ := unstructured.NestedString(obj.Object, "spec", "path"); path != "" {
+		entry.Source += " " + path
+	}
+	entry.Destination, _, _ = unstructured.NestedString(obj.Object, "spec", "targetNamespace")
+	entry.Revision, _, _ = unstructured.NestedString(obj.Object, "status", "lastAppliedRevision")
+	entry.AttemptedRevision, _, _ = unstructured.NestedString(obj.Object, "status", "lastAttemptedRevision")
+
+	return fluxFindings(entry)
+}
+
+// fluxHelmRelease summarizes a Flux HelmRelease and explains why it may need
+// attention.
+func fluxHelmRelease(obj *unstructured.Unstructured) GitOpsObject {
+	entry := fluxObject(obj, "HelmRelease")
+
+	if chart, _, _ := unstructured.NestedString(obj.Object, "spec", "chart", "spec", "chart"); chart != "" {
+		entry.Source = "chart " + chart
+		if version, _, _ := unstructured.NestedString(obj.Object, "spec", "chart", "spec", "version"); version != "" {
+			entry.Source += "@" + version
+		}
+		entry.Source += " from " + fluxSourceRef(obj, "spec", "chart", "spec", "sourceRef")
+	} else {
+		entry.Source = fluxSourceRef(obj, "spec", "chartRef")
+	}
+	entry.Destination, _, _ = unstructured.NestedString(obj.Object, "spec", "targetNamespace")
+
+	// helm.toolkit.fluxcd.io/v2 records releases in status.history, newest
+	// first; earlier versions set lastAppliedRevision.
+	entry.Revision, _, _ = unstructured.NestedString(obj.Object, "status", "lastAppliedRevision")
+	if history, _, _ := unstructured.NestedSlice(obj.Object, "status", "history"); len(history) > 0 {
+		if latest, ok := history[0].(map[string]interface{}); ok {
+			name, _, _ := unstructured.NestedString(latest, "chartName")
+			version, _, _ := unstructured.NestedString(latest, "chartVersion")
+			if version != "" {
+				entry.Revision = strings.TrimPrefix(name+"@"+version, "@")
+			}
+		}
+	}
+	entry.AttemptedRevision, _, _ = unstructured.NestedString(obj.Object, "status", "lastAttemptedRevision")
+	if strings.HasSuffix(entry.Revision, "@"+entry.AttemptedRevision) {
+		entry.AttemptedRevision = ""
+	}
+
+	entry = fluxFindings(entry)
+
+	for _, counter := range []struct{ field, what string }{
+		{"installFailures", "install"},
+		{"upgradeFailures", "upgrade"},
+	} {
+		if failures, _, _ := unstructured.NestedInt64(obj.Object, "status", counter.field); failures > 0 {
+			entry.Findings = append(entry.Findings, fmt.Sprintf("%d %s attempt(s) failed since the last success", failures, counter.what))
+		}
+	}
+
+	return entry
+}
+
+// fluxObject reads the fields every Flux object shares: suspension and the
+// Ready condition.
+func fluxObject(obj *unstructured.Unstructured, kind string) GitOpsObject {
+	entry := GitOpsObject{Tool: "flux", Kind: kind, Namespace: obj.GetNamespace(), Name: obj.GetName()}
+	entry.Suspended, _, _ = unstructured.NestedBool(obj.Object, "spec", "suspend")
+
+	ready := unstructuredCondition(obj, "Ready")
+	entry.Ready, entry.Reason, entry.Message = ready.status, ready.reason, ready.message
+	entry.LastReconciled = ready.lastTransition
+	if !ready.found {
+		entry.Ready = "Unknown"
+		entry.Findings = append(entry.Findings, "has no Ready condition; Flux has not processed it, which usually means its controller is not running")
+	} else if ready.status == "False" {
+		entry.Findings = append(entry.Findings, conditionFinding("is not ready", ready))
+	}
+
+	return entry
+}
+
+// fluxFindings adds the findings that depend on the revisions and suspension
+// of a Flux object.
+func fluxFindings(entry GitOpsObject) GitOpsObject {
+	if entry.Suspended {
+		entry.Findings = append(entry.Findings, "reconciliation is suspended, so changes to the source are not applied")
+	}
+	if entry.AttemptedRevision != "" && entry.AttemptedRevision != entry.Revision {
+		finding := fmt.Sprintf("revision %s was attempted but not applied", entry.AttemptedRevision)
+		if entry.Revision != "" {
+			finding += "; the cluster still runs " + entry.Revision
+		}
+		entry.Findings = append(entry.Findings, finding)
+	}
+	return entry
+}
+
+// fluxSourceRef renders a sourceRef or chartRef as Kind/name, with the
+// namespace when it differs from the object's.
+func fluxSourceRef(obj *unstructured.Unstructured, fields ...string) string {
+	ref, ok, _ := unstructured.NestedMap(obj.Object, fields...)
+	if !ok {
+		return ""
+	}
+
+	kind, _, _ := unstructured.NestedString(ref, "kind")
+	name, _, _ := unstructured.NestedString(ref, "name")
+	if namespace, _, _ := unstructured.NestedString(ref, "namespace"); namespace != "" && namespace != obj.GetNamespace() {
+		name = namespace + "/" + name
+	}
+	return kind + "/" + name
+}
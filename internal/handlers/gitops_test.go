@@ -0,0 +1,202 @@
+package handlers
+
+import (
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/fakecluster"
+)
+
+func gitopsObject(apiVersion, kind, namespace, name string, spec, status map[string]interface{}) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": apiVersion,
+		"kind":       kind,
+		"metadata":   map[string]interface{}{"name": name, "namespace": namespace},
+		"spec":       spec,
+		"status":     status,
+	}}
+}
+
+func readyCondition(status, reason, message string) map[string]interface{} {
+	return map[string]interface{}{"conditions": []interface{}{map[string]interface{}{
+		"type": "Ready", "status": status, "reason": reason, "message": message, "lastTransitionTime": "2024-05-01T10:00:00Z",
+	}}}
+}
+
+func TestArgoApplication(t *testing.T) {
+	t.Parallel()
+
+	spec := map[string]interface{}{
+		"source":      map[string]interface{}{"repoURL": "https://github.com/acme/deploy", "path": "apps/web", "targetRevision": "main"},
+		"destination": map[string]interface{}{"server": "https://kubernetes.default.svc", "namespace": "web"},
+	}
+
+	tests := []struct {
+		name   string
+		status map[string]interface{}
+		want   []string
+	}{
+		{
+			name: "synced and healthy",
+			status: map[string]interface{}{
+				"sync":   map[string]interface{}{"status": "Synced", "revision": "abc123"},
+				"health": map[string]interface{}{"status": "Healthy"},
+			},
+		},
+		{
+			name: "drifted, degraded, and failing",
+			status: map[string]interface{}{
+				"sync":   map[string]interface{}{"status": "OutOfSync", "revision": "abc123"},
+				"health": map[string]interface{}{"status": "Degraded", "message": "Deployment web exceeded its progress deadline"},
+				"resources": []interface{}{
+					map[string]interface{}{"kind": "Deployment", "namespace": "web", "name": "web", "status": "OutOfSync"},
+					map[string]interface{}{"kind": "Service", "namespace": "web", "name": "web", "status": "Synced"},
+				},
+				"operationState": map[string]interface{}{"phase": "Failed", "message": "one or more objects failed to apply", "finishedAt": "2024-05-01T10:00:00Z"},
+				"conditions":     []interface{}{map[string]interface{}{"type": "ComparisonError", "message": "failed to load target state"}},
+			},
+			want: []string{
+				"is OutOfSync: 1 resource(s) differ from the source; automated sync is disabled, so the drift stays until someone syncs it",
+				"health is Degraded: Deployment web exceeded its progress deadline",
+				"the last sync failed at 2024-05-01T10:00:00Z: one or more objects failed to apply",
+				"ComparisonError: failed to load target state",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := argoApplication(gitopsObject("argoproj.io/v1alpha1", "Application", "argocd", "web", spec, tt.status))
+			if got.Source != "https://github.com/acme/deploy apps/web@main" || got.Destination != "https://kubernetes.default.svc/web" {
+				t.Errorf("unexpected source and destination %+v", got)
+			}
+			if !reflect.DeepEqual(got.Findings, tt.want) {
+				t.Errorf("expected findings %q, got %q", tt.want, got.Findings)
+			}
+		})
+	}
+}
+
+func TestFluxObjects(t *testing.T) {
+	t.Parallel()
+
+	kustomizationSpec := map[string]interface{}{
+		"sourceRef": map[string]interface{}{"kind": "GitRepository", "name": "flux-system", "namespace": "flux-system"},
+		"path":      "./apps",
+	}
+	helmSpec := map[string]interface{}{
+		"chart": map[string]interface{}{"spec": map[string]interface{}{
+			"chart": "podinfo", "version": "6.x", "sourceRef": map[string]interface{}{"kind": "HelmRepository", "name": "podinfo"},
+		}},
+	}
+
+	failedStatus := readyCondition("False", "UpgradeFailed", "context deadline exceeded")
+	failedStatus["history"] = []interface{}{map[string]interface{}{"chartName": "podinfo", "chartVersion": "6.5.0"}}
+	failedStatus["lastAttemptedRevision"] = "6.5.1"
+	failedStatus["upgradeFailures"] = int64(2)
+
+	suspendedSpec := map[string]interface{}{"sourceRef": kustomizationSpec["sourceRef"], "path": "./apps", "suspend": true}
+
+	tests := []struct {
+		name       string
+		object     GitOpsObject
+		wantSource string
+		want       []string
+	}{
+		{
+			name:       "ready kustomization",
+			object:     fluxKustomization(gitopsObject("kustomize.toolkit.fluxcd.io/v1", "Kustomization", "flux-system", "apps", kustomizationSpec, readyCondition("True", "ReconciliationSucceeded", "Applied revision: main@sha1:abc"))),
+			wantSource: "GitRepository/flux-system ./apps",
+		},
+		{
+			name:       "suspended kustomization",
+			object:     fluxKustomization(gitopsObject("kustomize.toolkit.fluxcd.io/v1", "Kustomization", "apps", "web", suspendedSpec, readyCondition("True", "ReconciliationSucceeded", ""))),
+			wantSource: "GitRepository/flux-system/flux-system ./apps",
+			want:       []string{"reconciliation is suspended, so changes to the source are not applied"},
+		},
+		{
+			name:       "failed upgrade",
+			object:     fluxHelmRelease(gitopsObject("helm.toolkit.fluxcd.io/v2", "HelmRelease", "web", "podinfo", helmSpec, failedStatus)),
+			wantSource: "chart podinfo@6.x from HelmRepository/podinfo",
+			want: []string{
+				"is not ready (UpgradeFailed): context deadline exceeded",
+				"revision 6.5.1 was attempted but not applied; the cluster still runs podinfo@6.5.0",
+				"2 upgrade attempt(s) failed since the last success",
+			},
+		},
+		{
+			name:       "never reconciled",
+			object:     fluxHelmRelease(gitopsObject("helm.toolkit.fluxcd.io/v2", "HelmRelease", "web", "podinfo", helmSpec, map[string]interface{}{})),
+			wantSource: "chart podinfo@6.x from HelmRepository/podinfo",
+			want:       []string{"has no Ready condition; Flux has not processed it, which usually means its controller is not running"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if tt.object.Source != tt.wantSource {
+				t.Errorf("expected source %q, got %q", tt.wantSource, tt.object.Source)
+			}
+			if !reflect.DeepEqual(tt.object.Findings, tt.want) {
+				t.Errorf("expected findings %q, got %q", tt.want, tt.object.Findings)
+			}
+		})
+	}
+}
+
+func TestGetGitOpsStatus_FakeCluster(t *testing.T) {
+	t.Parallel()
+
+	notInstalled := NewResourceHandler(fakecluster.New(fakecluster.Config{}), nil, false)
+	result, isErr := callTool(t, notInstalled.GetGitOpsStatus, map[string]any{})
+	if isErr || result["installed"] != false {
+		t.Fatalf("expected a not-installed result, got %+v", result)
+	}
+
+	apiResources := append(fakecluster.DefaultAPIResources(),
+		&metav1.APIResourceList{
+			GroupVersion: "kustomize.toolkit.fluxcd.io/v1",
+			APIResources: []metav1.APIResource{{Name: "kustomizations", Kind: "Kustomization", Namespaced: true, Verbs: metav1.Verbs{"get", "list"}}},
+		},
+	)
+
+	handler := NewResourceHandler(fakecluster.New(fakecluster.Config{
+		Custom: []*unstructured.Unstructured{
+			gitopsObject("kustomize.toolkit.fluxcd.io/v1", "Kustomization", "flux-system", "apps", map[string]interface{}{}, readyCondition("True", "ReconciliationSucceeded", "")),
+			gitopsObject("kustomize.toolkit.fluxcd.io/v1", "Kustomization", "flux-system", "infra", map[string]interface{}{}, readyCondition("False", "BuildFailed", "kustomization.yaml not found")),
+		},
+		APIResources: apiResources,
+	}), nil, false)
+
+	result, isErr = callTool(t, handler.GetGitOpsStatus, map[string]any{"only_problems": true})
+	if isErr {
+		t.Fatalf("unexpected error: %v", result["error"])
+	}
+
+	if want := map[string]any{"argocd": false, "flux": true}; !reflect.DeepEqual(result["installed"], want) {
+		t.Errorf("expected installed %v, got %v", want, result["installed"])
+	}
+
+	var objects []GitOpsObject
+	decodeInto(t, result["objects"], &objects)
+	if len(objects) != 1 || objects[0].Name != "infra" || objects[0].LastReconciled != "2024-05-01T10:00:00Z" || result["with_findings"] != float64(1) {
+		t.Errorf("unexpected objects %+v in %+v", objects, result)
+	}
+
+	result, isErr = callTool(t, handler.GetGitOpsStatus, map[string]any{"tool": "argocd"})
+	if isErr {
+		t.Fatalf("unexpected error: %v", result["error"])
+	}
+	if warnings, ok := result["warnings"].([]any); !ok || len(warnings) != 1 {
+		t.Errorf("expected a warning that Argo CD is not served, got %+v", result)
+	}
+}
@@ -0,0 +1,265 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/connectivity"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/response"
+)
+
+// Backend statuses reported by get_ingress_routes when validate_backends is set.
+const (
+	backendStatusOK              = "ok"
+	backendStatusMissingService  = "service not found"
+	backendStatusMissingPort     = "port not found on service"
+	backendStatusResourceBackend = "resource backend not validated"
+)
+
+// GetIngressRoutesParams defines the parameters for the get_ingress_routes MCP tool.
+type GetIngressRoutesParams struct {
+	// Namespace restricts the routes to a namespace.
+	Namespace string `json:"namespace,omitempty" description:"Namespace of the Ingresses (leave empty for all namespaces)"`
+
+	// Name restricts the routes to a single Ingress.
+	Name string `json:"name,omitempty" description:"Name of a single Ingress to map (leave empty for all Ingresses)"`
+
+	// Host restricts the routes to a host.
+	Host string `json:"host,omitempty" description:"Only return routes for this host (e.g., 'shop.example.com')"`
+
+	// ValidateBackends checks that each backend Service and port exists.
+	ValidateBackends bool `json:"validate_backends,omitempty" description:"Check that each backend Service exists and exposes the referenced port"`
+
+	// Context specifies which Kubernetes context to use for this operation.
+	Context string `json:"context,omitempty" description:"Kubernetes context to use (defaults to current context from kubeconfig)"`
+}
+
+// IngressRoute is a single host and path of an Ingress and the backend it
+// routes to.
+type IngressRoute struct {
+	Namespace string `json:"namespace"`
+	Ingress   string `json:"ingress"`
+	Class     string `json:"class,omitempty"`
+
+	// Host is "*" for rules without a host.
+	Host     string `json:"host"`
+	Path     string `json:"path,omitempty"`
+	PathType string `json:"path_type,omitempty"`
+
+	// DefaultBackend is true for the Ingress default backend, which serves
+	// requests no rule matches.
+	DefaultBackend bool `json:"default_backend,omitempty"`
+
+	// Service and Port are set for Service backends; Resource is set as
+	// Kind/name for resource backends.
+	Service  string `json:"service,omitempty"`
+	Port     string `json:"port,omitempty"`
+	Resource string `json:"resource,omitempty"`
+
+	// TLS is true when a TLS entry covers the host; TLSSecret is the Secret
+	// holding its certificate.
+	TLS       bool   `json:"tls"`
+	TLSSecret string `json:"tls_secret,omitempty"`
+
+	// BackendStatus is set when backends are validated.
+	BackendStatus string `json:"backend_status,omitempty"`
+}
+
+// GetIngressRoutesResult is the result of the get_ingress_routes MCP tool.
+type GetIngressRoutesResult struct {
+	Routes []IngressRoute `json:"routes"`
+	Count  int            `json:"count"`
+}
+
+// GetIngressRoutes implements the get_ingress_routes MCP tool.
+// It flattens Ingresses into one entry per host and path with the Service
+// and port they route to, the ingress class, and the TLS Secret serving the
+// host. With validate_backends, it also checks that each backend Service
+// exists and exposes the referenced port.
+func (h *NetworkHandler) GetIngressRoutes(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var params GetIngressRoutesParams
+	if err := bindParams(request, &params); err != nil {
+		return response.Errorf("failed to parse arguments: %s", err)
+	}
+
+	// Use the appropriate client based on context
+	client, err := h.client.ForContext(ctx, params.Context)
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to create client with context %s: %v", params.Context, err)
+	}
+
+	ingresses, err := client.ListIngresses(ctx, params.Namespace, metav1.ListOptions{})
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to list ingresses: %v", err)
+	}
+
+	routes := make([]IngressRoute, 0)
+	found := false
+	for i := range ingresses.Items {
+		if params.Name != "" && ingresses.Items[i].Name != params.Name {
+			continue
+		}
+		found = true
+		for _, route := range ingressRoutes(&ingresses.Items[i]) {
+			if params.Host == "" || strings.EqualFold(route.Host, params.Host) {
+				routes = append(routes, route)
+			}
+		}
+	}
+
+	if params.Name != "" && !found {
+		return response.Errorf("ingress %q not found", params.Name)
+	}
+
+	if params.ValidateBackends && len(routes) > 0 {
+		services, err := client.ListServices(ctx, params.Namespace, metav1.ListOptions{})
+		if err != nil {
+			if h.alwaysStart && connectivity.IsTransportError(err) {
+				return response.Error(connectivity.ErrorMessage(err))
+			}
+			return response.Errorf("failed to list services: %v", err)
+		}
+		validateIngressBackends(routes, services.Items)
+	}
+
+	sort.SliceStable(routes, func(i, j int) bool {
+		if routes[i].Namespace != routes[j].Namespace {
+			return routes[i].Namespace < routes[j].Namespace
+		}
+		if routes[i].Host != routes[j].Host {
+			return routes[i].Host < routes[j].Host
+		}
+		return routes[i].Path < routes[j].Path
+	})
+
+	return response.JSON(GetIngressRoutesResult{
+		Routes: routes,
+		Count:  len(routes),
+	})
+}
+
+// ingressRoutes flattens an Ingress into its routes, in the order of its
+// rules and paths, followed by the default backend.
+func ingressRoutes(ingress *networkingv1.Ingress) []IngressRoute {
+	class := ingressClass(ingress)
+
+	route := func(host string, backend *networkingv1.IngressBackend) IngressRoute {
+		entry := IngressRoute{
+			Namespace: ingress.Namespace,
+			Ingress:   ingress.Name,
+			Class:     class,
+			Host:      host,
+		}
+		if host == "" {
+			entry.Host = "*"
+		}
+		entry.TLSSecret, entry.TLS = ingressTLSSecret(ingress, host)
+
+		switch {
+		case backend.Service != nil:
+			entry.Service = backend.Service.Name
+			entry.Port = backend.Service.Port.Name
+			if entry.Port == "" {
+				entry.Port = fmt.Sprintf("%d", backend.Service.Port.Number)
+			}
+		case backend.Resource != nil:
+			entry.Resource = backend.Resource.Kind + "/" + backend.Resource.Name
+		}
+		return entry
+	}
+
+	var routes []IngressRoute
+	for _, rule := range ingress.Spec.Rules {
+		if rule.HTTP == nil {
+			continue
+		}
+		for i := range rule.HTTP.Paths {
+			path := &rule.HTTP.Paths[i]
+			entry := route(rule.Host, &path.Backend)
+			entry.Path = path.Path
+			if path.PathType != nil {
+				entry.PathType = string(*path.PathType)
+			}
+			routes = append(routes, entry)
+		}
+	}
+
+	if ingress.Spec.DefaultBackend != nil {
+		entry := route("", ingress.Spec.DefaultBackend)
+		entry.DefaultBackend = true
+		routes = append(routes, entry)
+	}
+
+	return routes
+}
+
+// ingressTLSSecret returns the Secret of the TLS entry covering host, either
+// by name or through a wildcard, and whether any entry covers it. An empty
+// host, as used by the default backend, is covered by a TLS entry without
+// hosts.
+func ingressTLSSecret(ingress *networkingv1.Ingress, host string) (string, bool) {
+	for _, tls := range ingress.Spec.TLS {
+		if host == "" && len(tls.Hosts) == 0 {
+			return tls.SecretName, true
+		}
+		hosts := make(map[string]bool, len(tls.Hosts))
+		for _, h := range tls.Hosts {
+			hosts[h] = true
+		}
+		if host != "" && (hosts[host] || coveredByWildcard(host, hosts)) {
+			return tls.SecretName, true
+		}
+	}
+	return "", false
+}
+
+// validateIngressBackends sets the backend status of each route from the
+// Services in the cluster.
+func validateIngressBackends(routes []IngressRoute, services []corev1.Service) {
+	byName := make(map[string]*corev1.Service, len(services))
+	for i := range services {
+		byName[services[i].Namespace+"/"+services[i].Name] = &services[i]
+	}
+
+	for i := range routes {
+		route := &routes[i]
+		if route.Service == "" {
+			route.BackendStatus = backendStatusResourceBackend
+			continue
+		}
+
+		service, ok := byName[route.Namespace+"/"+route.Service]
+		switch {
+		case !ok:
+			route.BackendStatus = backendStatusMissingService
+		case service.Spec.Type == corev1.ServiceTypeExternalName || servicePortExists(service, route.Port):
+			route.BackendStatus = backendStatusOK
+		default:
+			route.BackendStatus = backendStatusMissingPort
+		}
+	}
+}
+
+// servicePortExists reports whether a Service has a port with the given
+// name or number.
+func servicePortExists(service *corev1.Service, port string) bool {
+	for _, servicePort := range service.Spec.Ports {
+		if servicePort.Name == port || fmt.Sprintf("%d", servicePort.Port) == port {
+			return true
+		}
+	}
+	return false
+}
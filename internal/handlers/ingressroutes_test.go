@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/fakecluster"
+)
+
+func TestIngressTLSSecret(t *testing.T) {
+	t.Parallel()
+
+	ingress := &networkingv1.Ingress{Spec: networkingv1.IngressSpec{TLS: []networkingv1.IngressTLS{
+		{Hosts: []string{"shop.example.com"}, SecretName: "shop-tls"},
+		{Hosts: []string{"*.example.org"}, SecretName: "wildcard-tls"},
+	}}}
+
+	tests := []struct {
+		host       string
+		wantSecret string
+		wantTLS    bool
+	}{
+		{host: "shop.example.com", wantSecret: "shop-tls", wantTLS: true},
+		{host: "api.example.org", wantSecret: "wildcard-tls", wantTLS: true},
+		{host: "deep.api.example.org"},
+		{host: "other.example.com"},
+		{host: ""},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.host, func(t *testing.T) {
+			t.Parallel()
+
+			secret, tls := ingressTLSSecret(ingress, tt.host)
+			if secret != tt.wantSecret || tls != tt.wantTLS {
+				t.Errorf("expected (%q, %v), got (%q, %v)", tt.wantSecret, tt.wantTLS, secret, tls)
+			}
+		})
+	}
+}
+
+func TestGetIngressRoutes_FakeCluster(t *testing.T) {
+	t.Parallel()
+
+	className := "nginx"
+	prefix := networkingv1.PathTypePrefix
+	backend := func(service string, port int32, portName string) networkingv1.IngressBackend {
+		return networkingv1.IngressBackend{Service: &networkingv1.IngressServiceBackend{
+			Name: service,
+			Port: networkingv1.ServiceBackendPort{Number: port, Name: portName},
+		}}
+	}
+
+	handler := NewNetworkHandler(fakecluster.New(fakecluster.Config{
+		Objects: []runtime.Object{
+			&corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop"},
+				Spec:       corev1.ServiceSpec{Ports: []corev1.ServicePort{{Name: "http", Port: 80}}},
+			},
+			&corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "shop"},
+				Spec:       corev1.ServiceSpec{Ports: []corev1.ServicePort{{Port: 8080}}},
+			},
+			&networkingv1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{Name: "shop", Namespace: "shop"},
+				Spec: networkingv1.IngressSpec{
+					IngressClassName: &className,
+					TLS:              []networkingv1.IngressTLS{{Hosts: []string{"shop.example.com"}, SecretName: "shop-tls"}},
+					Rules: []networkingv1.IngressRule{
+						{
+							Host: "shop.example.com",
+							IngressRuleValue: networkingv1.IngressRuleValue{HTTP: &networkingv1.HTTPIngressRuleValue{Paths: []networkingv1.HTTPIngressPath{
+								{Path: "/", PathType: &prefix, Backend: backend("web", 0, "http")},
+								{Path: "/api", PathType: &prefix, Backend: backend("api", 9090, "")},
+							}}},
+						},
+						{
+							Host: "legacy.example.com",
+							IngressRuleValue: networkingv1.IngressRuleValue{HTTP: &networkingv1.HTTPIngressRuleValue{Paths: []networkingv1.HTTPIngressPath{
+								{Path: "/", PathType: &prefix, Backend: backend("legacy", 80, "")},
+							}}},
+						},
+					},
+				},
+			},
+		},
+	}), false)
+
+	result, isErr := callTool(t, handler.GetIngressRoutes, map[string]any{"validate_backends": true})
+	if isErr {
+		t.Fatalf("unexpected error: %v", result["error"])
+	}
+
+	var routes []IngressRoute
+	decodeInto(t, result["routes"], &routes)
+
+	want := []IngressRoute{
+		{Namespace: "shop", Ingress: "shop", Class: "nginx", Host: "legacy.example.com", Path: "/", PathType: "Prefix", Service: "legacy", Port: "80", BackendStatus: backendStatusMissingService},
+		{Namespace: "shop", Ingress: "shop", Class: "nginx", Host: "shop.example.com", Path: "/", PathType: "Prefix", Service: "web", Port: "http", TLS: true, TLSSecret: "shop-tls", BackendStatus: backendStatusOK},
+		{Namespace: "shop", Ingress: "shop", Class: "nginx", Host: "shop.example.com", Path: "/api", PathType: "Prefix", Service: "api", Port: "9090", TLS: true, TLSSecret: "shop-tls", BackendStatus: backendStatusMissingPort},
+	}
+	if !reflect.DeepEqual(routes, want) {
+		t.Errorf("expected routes %+v, got %+v", want, routes)
+	}
+
+	result, isErr = callTool(t, handler.GetIngressRoutes, map[string]any{"name": "missing"})
+	if !isErr {
+		t.Errorf("expected an error for a missing ingress, got %v", result)
+	}
+}
@@ -0,0 +1,290 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/connectivity"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/kubernetes"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/response"
+)
+
+const (
+	// defaultInitFailureLogLines is how many log lines init_failures fetches
+	// from each blocking init container.
+	defaultInitFailureLogLines = 20
+
+	// maxInitFailureLogs is how many pods init_failures fetches logs for,
+	// starting with the pods stuck the longest.
+	maxInitFailureLogs = 10
+)
+
+// InitFailuresParams defines the parameters for the init_failures MCP tool.
+type InitFailuresParams struct {
+	// Namespace restricts the report to a namespace.
+	Namespace string `json:"namespace,omitempty" description:"Namespace to check (leave empty for all namespaces)"`
+
+	// LabelSelector restricts the report to matching pods.
+	LabelSelector string `json:"label_selector,omitempty" description:"Label selector to filter pods (e.g., 'app=web')"`
+
+	// LogLines is how many log lines to fetch from each blocking init container.
+	LogLines int `json:"log_lines,omitempty" minimum:"0" maximum:"200" default:"20" description:"Number of log lines to fetch from each blocking init container (defaults to 20, 0 uses the default)"`
+
+	// Context specifies which Kubernetes context to use for this operation.
+	Context string `json:"context,omitempty" description:"Kubernetes context to use (defaults to current context from kubeconfig)"`
+}
+
+// InitFailure is a pod stuck initializing and the init container blocking it.
+type InitFailure struct {
+	Namespace string `json:"namespace"`
+	Pod       string `json:"pod"`
+	Node      string `json:"node,omitempty"`
+	Workload  string `json:"workload,omitempty"`
+
+	// Status is the pod status as kubectl shows it, such as
+	// Init:CrashLoopBackOff or Init:1/3.
+	Status string `json:"status"`
+
+	// BlockingContainer is the first init container that has not completed.
+	BlockingContainer string                 `json:"blocking_container"`
+	Image             string                 `json:"image"`
+	Completed         int                    `json:"completed"`
+	Total             int                    `json:"total"`
+	RestartCount      int32                  `json:"restart_count"`
+	State             ContainerStateSummary  `json:"state"`
+	LastState         *ContainerStateSummary `json:"last_state,omitempty"`
+
+	// ExitCode is the most recent exit code of the blocking container, with
+	// its usual meaning.
+	ExitCode        *int32 `json:"exit_code,omitempty"`
+	ExitCodeMeaning string `json:"exit_code_meaning,omitempty"`
+
+	// StuckSince is when the pod started initializing, and StuckFor how long
+	// ago that was.
+	StuckSince string `json:"stuck_since,omitempty"`
+	StuckFor   string `json:"stuck_for,omitempty"`
+
+	Logs *DiagnosedLogs `json:"logs,omitempty"`
+
+	stuckSince time.Time
+	logTarget  *logTarget
+}
+
+// InitFailuresResult is the result of the init_failures MCP tool.
+type InitFailuresResult struct {
+	Pods  []InitFailure `json:"pods"`
+	Count int           `json:"count"`
+}
+
+// InitFailures implements the init_failures MCP tool.
+// It finds pods that have not finished running their init containers and
+// reports, for each, the init container blocking it with its state, exit
+// code, and restart count, how long the pod has been initializing, and the
+// tail of the container's logs. Logs are fetched for the pods stuck the
+// longest, up to maxInitFailureLogs, and failures to read them are reported
+// on the entry.
+func (h *PodHandler) InitFailures(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var params InitFailuresParams
+	if err := bindParams(request, &params); err != nil {
+		return response.Errorf("failed to parse arguments: %s", err)
+	}
+
+	logLines := params.LogLines
+	if logLines == 0 {
+		logLines = defaultInitFailureLogLines
+	}
+
+	// Use the appropriate client based on context
+	client, err := h.client.ForContext(ctx, params.Context)
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to create client with context %s: %v", params.Context, err)
+	}
+
+	pods, err := client.ListPods(ctx, params.Namespace, metav1.ListOptions{LabelSelector: params.LabelSelector})
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to list pods: %v", err)
+	}
+
+	failures := initFailures(pods.Items, time.Now())
+
+	for i := range failures {
+		failure := &failures[i]
+		if i >= maxInitFailureLogs || ctx.Err() != nil {
+			break
+		}
+		if failure.logTarget == nil {
+			continue
+		}
+
+		lines := int64(logLines)
+		logs := DiagnosedLogs{Container: failure.logTarget.container, Previous: failure.logTarget.previous}
+
+		output, err := client.GetPodLogsWithOptions(ctx, failure.Namespace, failure.Pod, &kubernetes.LogOptions{
+			Container: failure.logTarget.container,
+			MaxLines:  &lines,
+			Previous:  failure.logTarget.previous,
+		})
+		if err != nil {
+			if h.alwaysStart && connectivity.IsTransportError(err) {
+				return response.Error(connectivity.ErrorMessage(err))
+			}
+			logs.Error = err.Error()
+		} else {
+			logs.Logs = output
+		}
+
+		failure.Logs = &logs
+	}
+
+	return response.JSON(InitFailuresResult{
+		Pods:  failures,
+		Count: len(failures),
+	})
+}
+
+// initFailures returns the pods blocked on an init container, stuck the
+// longest first.
+func initFailures(pods []corev1.Pod, now time.Time) []InitFailure {
+	failures := make([]InitFailure, 0)
+
+	for i := range pods {
+		if failure, ok := initFailure(&pods[i], now); ok {
+			failures = append(failures, failure)
+		}
+	}
+
+	sort.SliceStable(failures, func(i, j int) bool {
+		if !failures[i].stuckSince.Equal(failures[j].stuckSince) {
+			return failures[i].stuckSince.Before(failures[j].stuckSince)
+		}
+		if failures[i].Namespace != failures[j].Namespace {
+			return failures[i].Namespace < failures[j].Namespace
+		}
+		return failures[i].Pod < failures[j].Pod
+	})
+
+	return failures
+}
+
+// initFailure describes the init container blocking a pod. It returns false
+// when the pod is not initializing: it has no init containers, they have all
+// completed, or the pod is not scheduled or already finished. Sidecar init
+// containers, which keep running, count as completed once started.
+func initFailure(pod *corev1.Pod, now time.Time) (InitFailure, bool) {
+	if len(pod.Spec.InitContainers) == 0 || pod.Spec.NodeName == "" || pod.Status.Phase != corev1.PodPending {
+		return InitFailure{}, false
+	}
+
+	statuses := make(map[string]*corev1.ContainerStatus, len(pod.Status.InitContainerStatuses))
+	for i := range pod.Status.InitContainerStatuses {
+		statuses[pod.Status.InitContainerStatuses[i].Name] = &pod.Status.InitContainerStatuses[i]
+	}
+
+	completed := 0
+	var blocking *corev1.Container
+	var status *corev1.ContainerStatus
+	for i := range pod.Spec.InitContainers {
+		container := &pod.Spec.InitContainers[i]
+		current := statuses[container.Name]
+		if initContainerDone(container, current) {
+			completed++
+			continue
+		}
+		if blocking == nil {
+			blocking, status = container, current
+		}
+	}
+
+	if blocking == nil {
+		return InitFailure{}, false
+	}
+
+	failure := InitFailure{
+		Namespace:         pod.Namespace,
+		Pod:               pod.Name,
+		Node:              pod.Spec.NodeName,
+		Workload:          podWorkload(pod),
+		BlockingContainer: blocking.Name,
+		Image:             blocking.Image,
+		Completed:         completed,
+		Total:             len(pod.Spec.InitContainers),
+		State:             ContainerStateSummary{State: "unknown"},
+		stuckSince:        pod.CreationTimestamp.Time,
+	}
+	failure.Status = fmt.Sprintf("Init:%d/%d", completed, failure.Total)
+
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodScheduled && condition.Status == corev1.ConditionTrue && !condition.LastTransitionTime.IsZero() {
+			failure.stuckSince = condition.LastTransitionTime.Time
+		}
+	}
+	if !failure.stuckSince.IsZero() {
+		failure.StuckSince = formatTime(failure.stuckSince)
+		failure.StuckFor = now.Sub(failure.stuckSince).Round(time.Second).String()
+	}
+
+	if status == nil {
+		return failure, true
+	}
+
+	failure.RestartCount = status.RestartCount
+	failure.State = summarizeContainerState(status.State)
+
+	last := status.LastTerminationState.Terminated
+	if last != nil {
+		lastState := summarizeContainerState(status.LastTerminationState)
+		failure.LastState = &lastState
+	}
+
+	switch {
+	case status.State.Waiting != nil && status.State.Waiting.Reason != "" && status.State.Waiting.Reason != "PodInitializing":
+		failure.Status = "Init:" + status.State.Waiting.Reason
+	case status.State.Terminated != nil && status.State.Terminated.Reason != "":
+		failure.Status = "Init:" + status.State.Terminated.Reason
+	case status.State.Terminated != nil:
+		failure.Status = fmt.Sprintf("Init:ExitCode:%d", status.State.Terminated.ExitCode)
+	}
+
+	switch {
+	case status.State.Terminated != nil:
+		code := status.State.Terminated.ExitCode
+		failure.ExitCode = &code
+		failure.ExitCodeMeaning = exitCodeMeaning(code, status.State.Terminated.Reason)
+		failure.logTarget = &logTarget{container: blocking.Name}
+	case last != nil:
+		code := last.ExitCode
+		failure.ExitCode = &code
+		failure.ExitCodeMeaning = exitCodeMeaning(code, last.Reason)
+		failure.logTarget = &logTarget{container: blocking.Name, previous: status.State.Waiting != nil}
+	case status.State.Running != nil:
+		// A running init container that never finishes is usually waiting
+		// on a dependency, which its logs tend to show.
+		failure.logTarget = &logTarget{container: blocking.Name}
+	}
+
+	return failure, true
+}
+
+// initContainerDone reports whether an init container no longer blocks the
+// pod: it exited successfully or, for a sidecar, it has started.
+func initContainerDone(container *corev1.Container, status *corev1.ContainerStatus) bool {
+	if status == nil {
+		return false
+	}
+	if container.RestartPolicy != nil && *container.RestartPolicy == corev1.ContainerRestartPolicyAlways {
+		return status.Started != nil && *status.Started
+	}
+	return status.State.Terminated != nil && status.State.Terminated.ExitCode == 0
+}
@@ -0,0 +1,171 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/fakecluster"
+)
+
+func initPod(name string, statuses ...corev1.ContainerStatus) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "shop"},
+		Spec: corev1.PodSpec{
+			NodeName: "node-1",
+			InitContainers: []corev1.Container{
+				{Name: "migrate", Image: "shop/migrate:1.0"},
+				{Name: "wait-for-db", Image: "busybox"},
+			},
+			Containers: []corev1.Container{{Name: "app", Image: "shop/app:1.0"}},
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodPending, InitContainerStatuses: statuses},
+	}
+}
+
+func TestInitFailure(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	done := corev1.ContainerStatus{Name: "migrate", State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 0, Reason: "Completed"}}}
+	sidecar := corev1.ContainerRestartPolicyAlways
+	started := true
+
+	tests := []struct {
+		name          string
+		pod           *corev1.Pod
+		wantOK        bool
+		wantStatus    string
+		wantContainer string
+		wantCompleted int
+		wantPrevious  bool
+		wantLogs      bool
+	}{
+		{
+			name: "crash looping first init container",
+			pod: initPod("crash", corev1.ContainerStatus{
+				Name:                 "migrate",
+				RestartCount:         3,
+				State:                corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+				LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 1, Reason: "Error"}},
+			}),
+			wantOK:        true,
+			wantStatus:    "Init:CrashLoopBackOff",
+			wantContainer: "migrate",
+			wantPrevious:  true,
+			wantLogs:      true,
+		},
+		{
+			name: "second init container still running",
+			pod: initPod("waiting", done, corev1.ContainerStatus{
+				Name:  "wait-for-db",
+				State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+			}),
+			wantOK:        true,
+			wantStatus:    "Init:1/2",
+			wantContainer: "wait-for-db",
+			wantCompleted: 1,
+			wantLogs:      true,
+		},
+		{
+			name:          "init container without status",
+			pod:           initPod("new"),
+			wantOK:        true,
+			wantStatus:    "Init:0/2",
+			wantContainer: "migrate",
+		},
+		{
+			name: "all init containers completed",
+			pod: initPod("done", done, corev1.ContainerStatus{
+				Name:  "wait-for-db",
+				State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 0}},
+			}),
+		},
+		{
+			name: "started sidecar does not block",
+			pod: func() *corev1.Pod {
+				pod := initPod("sidecar", done, corev1.ContainerStatus{
+					Name:    "wait-for-db",
+					Started: &started,
+					State:   corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+				})
+				pod.Spec.InitContainers[1].RestartPolicy = &sidecar
+				return pod
+			}(),
+		},
+		{
+			name: "unscheduled pod",
+			pod: func() *corev1.Pod {
+				pod := initPod("unscheduled")
+				pod.Spec.NodeName = ""
+				return pod
+			}(),
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, ok := initFailure(tt.pod, now)
+			if ok != tt.wantOK {
+				t.Fatalf("expected ok=%v, got %v", tt.wantOK, ok)
+			}
+			if !ok {
+				return
+			}
+			if got.Status != tt.wantStatus || got.BlockingContainer != tt.wantContainer || got.Completed != tt.wantCompleted {
+				t.Errorf("expected %s blocked by %s after %d, got %+v", tt.wantStatus, tt.wantContainer, tt.wantCompleted, got)
+			}
+			if (got.logTarget != nil) != tt.wantLogs {
+				t.Fatalf("expected logs=%v, got %+v", tt.wantLogs, got.logTarget)
+			}
+			if got.logTarget != nil && got.logTarget.previous != tt.wantPrevious {
+				t.Errorf("expected previous=%v, got %v", tt.wantPrevious, got.logTarget.previous)
+			}
+		})
+	}
+}
+
+func TestInitFailures_FakeCluster(t *testing.T) {
+	t.Parallel()
+
+	stuck := initPod("web-1", corev1.ContainerStatus{
+		Name:  "migrate",
+		State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 127, Reason: "Error"}},
+	})
+	stuck.CreationTimestamp = metav1.NewTime(time.Now().Add(-time.Hour))
+
+	running := initPod("web-2")
+	running.Status.Phase = corev1.PodRunning
+
+	handler := NewPodHandler(fakecluster.New(fakecluster.Config{
+		Objects: []runtime.Object{stuck, running},
+	}), false)
+
+	result, isErr := callTool(t, handler.InitFailures, map[string]any{})
+	if isErr {
+		t.Fatalf("unexpected error: %v", result["error"])
+	}
+
+	var failures []InitFailure
+	decodeInto(t, result["pods"], &failures)
+	if len(failures) != 1 {
+		t.Fatalf("expected one stuck pod, got %+v", failures)
+	}
+
+	failure := failures[0]
+	if failure.Pod != "web-1" || failure.Status != "Init:Error" || failure.ExitCode == nil || *failure.ExitCode != 127 {
+		t.Errorf("unexpected failure %+v", failure)
+	}
+	if failure.StuckFor == "" {
+		t.Error("expected how long the pod has been stuck")
+	}
+	if failure.Logs == nil || failure.Logs.Container != "migrate" || failure.Logs.Previous || failure.Logs.Error != "" {
+		t.Errorf("expected current logs for migrate, got %+v", failure.Logs)
+	}
+}
@@ -0,0 +1,322 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/connectivity"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/kubernetes"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/response"
+)
+
+const (
+	// searchConcurrency is how many resource types search_by_label and
+	// find_resource list at once.
+	searchConcurrency = 8
+
+	// labelSearchDefaultLimit is how many matches search_by_label returns
+	// per resource type when no limit is given.
+	labelSearchDefaultLimit = 100
+)
+
+// SearchByLabelParams defines the parameters for the search_by_label MCP tool.
+type SearchByLabelParams struct {
+	// LabelSelector is the selector every searched type is listed with.
+	LabelSelector string `json:"label_selector" required:"true" description:"Label selector to search for (e.g., \"app=payments\" or \"app.kubernetes.io/part-of=shop,tier!=cache\")"`
+
+	// ResourceTypes limits the search to the given types.
+	ResourceTypes string `json:"resource_types,omitempty" description:"Comma-separated resource types to search (e.g., \"deployments,services,configmaps\"), by plural, singular, kind, or short name. Leave empty to search every namespaced type the cluster serves, except events"`
+
+	// Namespace limits the search of namespaced types.
+	Namespace string `json:"namespace,omitempty" description:"Namespace to search in (leave empty for all namespaces). Cluster-scoped types given in resource_types are always searched cluster-wide"`
+
+	// Limit caps the matches returned per resource type.
+	Limit int `json:"limit,omitempty" default:"100" minimum:"1" description:"Maximum number of matches to return per resource type; types with more are marked as truncated"`
+
+	// Context specifies which Kubernetes context to use for this operation.
+	Context string `json:"context,omitempty" description:"Kubernetes context to use (defaults to current context from kubeconfig)"`
+}
+
+// LabelSearchMatch is an object matching the label selector.
+type LabelSearchMatch struct {
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+}
+
+// LabelSearchGroup holds the matches of a resource type.
+type LabelSearchGroup struct {
+	Kind       string `json:"kind"`
+	APIVersion string `json:"api_version"`
+	Resource   string `json:"resource"`
+	Count      int    `json:"count"`
+
+	// Truncated is set when the type has more matches than the limit.
+	Truncated bool               `json:"truncated,omitempty"`
+	Items     []LabelSearchMatch `json:"items"`
+}
+
+// SearchByLabelResult is the result of the search_by_label MCP tool.
+type SearchByLabelResult struct {
+	LabelSelector string `json:"label_selector"`
+	Namespace     string `json:"namespace,omitempty"`
+
+	// SearchedTypes counts the resource types listed, and Total the matches
+	// across all of them.
+	SearchedTypes int `json:"searched_types"`
+	Total         int `json:"total"`
+
+	// Kinds holds only the types with matches, sorted by kind.
+	Kinds    []LabelSearchGroup `json:"kinds"`
+	Warnings []string           `json:"warnings,omitempty"`
+}
+
+// searchTarget is a resource type search_by_label and find_resource list.
+type searchTarget struct {
+	gvr        schema.GroupVersionResource
+	kind       string
+	namespaced bool
+}
+
+// SearchByLabel implements the search_by_label MCP tool.
+// It lists the objects matching a label selector across many resource types
+// at once, through metadata-only lists a few types at a time, and groups
+// them by kind: everything labelled app=payments, whatever it is. Types that
+// cannot be listed, such as those the user is not allowed to read, are
+// reported as warnings instead of failing the search.
+func (h *ResourceHandler) SearchByLabel(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var params SearchByLabelParams
+	if err := bindParams(request, &params); err != nil {
+		return response.Errorf("failed to parse arguments: %s", err)
+	}
+
+	params.LabelSelector = strings.TrimSpace(params.LabelSelector)
+	if params.LabelSelector == "" {
+		return response.Error("label_selector is required: searching every type without one would list every object in the cluster")
+	}
+	if _, err := labels.Parse(params.LabelSelector); err != nil {
+		return response.Errorf("invalid label selector: %v", err)
+	}
+
+	limit := params.Limit
+	if limit <= 0 {
+		limit = labelSearchDefaultLimit
+	}
+
+	// Use the appropriate client based on context
+	client, err := h.client.ForContext(ctx, params.Context)
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to create client with context %s: %v", params.Context, err)
+	}
+
+	lists, err := client.DiscoverResources(ctx)
+	if err != nil && len(lists) == 0 {
+		if h.alwaysStart && connectivity.IsError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to discover resource types: %v", err)
+	}
+
+	var targets []searchTarget
+	if params.ResourceTypes == "" {
+		targets = h.labelSearchDefaultTargets(lists)
+	} else {
+		for _, resourceType := range strings.Split(params.ResourceTypes, ",") {
+			resourceType = strings.TrimSpace(resourceType)
+			if resourceType == "" {
+				continue
+			}
+
+			gvr, err := client.ResolveResourceType(resourceType, "")
+			if err != nil {
+				if h.alwaysStart && connectivity.IsError(err) {
+					return response.Error(connectivity.ErrorMessage(err))
+				}
+				return response.Errorf("failed to resolve resource type %q: %v", resourceType, err)
+			}
+			if result, err := h.disabledResult(resourceType, gvr); result != nil || err != nil {
+				return result, err
+			}
+			// A type given twice, such as "deploy,deployments", is searched once.
+			if !slices.ContainsFunc(targets, func(target searchTarget) bool { return target.gvr == gvr }) {
+				targets = append(targets, searchTargetOf(lists, gvr))
+			}
+		}
+		if len(targets) == 0 {
+			return response.Error("no resource types given: pass types such as \"deployments,services\", or leave resource_types empty to search every namespaced type")
+		}
+	}
+
+	groups, errs := fetchPerTarget(ctx, targets, func(ctx context.Context, target searchTarget) (LabelSearchGroup, error) {
+		return searchLabelTarget(ctx, client, target, params.Namespace, params.LabelSelector, limit)
+	})
+
+	result := SearchByLabelResult{
+		LabelSelector: params.LabelSelector,
+		Namespace:     params.Namespace,
+		SearchedTypes: len(targets),
+		Kinds:         []LabelSearchGroup{},
+	}
+
+	for i, target := range targets {
+		if errs[i] != nil {
+			if h.alwaysStart && connectivity.IsTransportError(errs[i]) {
+				return response.Error(connectivity.ErrorMessage(errs[i]))
+			}
+			result.Warnings = append(result.Warnings, fmt.Sprintf("failed to search %s: %v", target.gvr.GroupResource(), errs[i]))
+			continue
+		}
+		if groups[i].Count == 0 {
+			continue
+		}
+		result.Total += groups[i].Count
+		result.Kinds = append(result.Kinds, groups[i])
+	}
+
+	sort.SliceStable(result.Kinds, func(i, j int) bool {
+		if result.Kinds[i].Kind != result.Kinds[j].Kind {
+			return result.Kinds[i].Kind < result.Kinds[j].Kind
+		}
+		return result.Kinds[i].APIVersion < result.Kinds[j].APIVersion
+	})
+
+	return response.JSON(result)
+}
+
+// labelSearchDefaultTargets returns the namespaced, listable types of the
+// preferred API versions, leaving out subresources, events, which are
+// short-lived records rather than objects anyone labels, metrics, and the
+// types disabled by configuration.
+func (h *ResourceHandler) labelSearchDefaultTargets(lists []*metav1.APIResourceList) []searchTarget {
+	var targets []searchTarget
+
+	for _, list := range lists {
+		gv, err := schema.ParseGroupVersion(list.GroupVersion)
+		if err != nil || gv.Group == "metrics.k8s.io" {
+			continue
+		}
+
+		for _, resource := range list.APIResources {
+			if !resource.Namespaced || strings.Contains(resource.Name, "/") || resource.Name == "events" {
+				continue
+			}
+			if len(resource.Verbs) > 0 && !slices.Contains(resource.Verbs, "list") {
+				continue
+			}
+			if h.resourceFilter != nil && h.resourceFilter.MatchesAPIResource(list.GroupVersion, resource.Name) {
+				continue
+			}
+
+			targets = append(targets, searchTarget{
+				gvr:        gv.WithResource(resource.Name),
+				kind:       resource.Kind,
+				namespaced: true,
+			})
+		}
+	}
+
+	return targets
+}
+
+// searchTargetOf returns the kind and scope discovery reports for gvr.
+// Types missing from the preferred versions are assumed to be namespaced,
+// and named after their resource.
+func searchTargetOf(lists []*metav1.APIResourceList, gvr schema.GroupVersionResource) searchTarget {
+	for _, list := range lists {
+		if list.GroupVersion != gvr.GroupVersion().String() {
+			continue
+		}
+		for _, resource := range list.APIResources {
+			if resource.Name == gvr.Resource {
+				return searchTarget{gvr: gvr, kind: resource.Kind, namespaced: resource.Namespaced}
+			}
+		}
+	}
+	return searchTarget{gvr: gvr, kind: gvr.Resource, namespaced: true}
+}
+
+// listNamespace returns the namespace target is listed in: namespace for a
+// namespaced type, or every namespace when it is empty, and cluster-wide
+// for a cluster-scoped one.
+func (target searchTarget) listNamespace(namespace string) string {
+	if target.namespaced && namespace != "" {
+		return namespace
+	}
+	return kubernetes.AllNamespaces
+}
+
+// fetchPerTarget calls fetch for every target, a few at a time. The results
+// and errors are indexed like targets, and the targets left when the
+// context is cancelled hold its error.
+func fetchPerTarget[T any](ctx context.Context, targets []searchTarget, fetch func(ctx context.Context, target searchTarget) (T, error)) ([]T, []error) {
+	results := make([]T, len(targets))
+	errs := make([]error, len(targets))
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, searchConcurrency)
+
+	for i, target := range targets {
+		if !acquireSlot(ctx, sem) {
+			errs[i] = ctx.Err()
+			continue
+		}
+		wg.Add(1)
+
+		go func(i int, target searchTarget) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			results[i], errs[i] = fetch(ctx, target)
+		}(i, target)
+	}
+
+	wg.Wait()
+	return results, errs
+}
+
+// searchLabelTarget lists up to limit objects of target matching selector.
+func searchLabelTarget(ctx context.Context, client kubernetes.ClusterReader, target searchTarget, namespace, selector string, limit int) (LabelSearchGroup, error) {
+	list, err := client.ListResourceMetadata(ctx, target.gvr, target.listNamespace(namespace), metav1.ListOptions{
+		LabelSelector: selector,
+		Limit:         int64(limit),
+	})
+	if err != nil {
+		return LabelSearchGroup{}, err //nolint:wrapcheck // kubernetes API errors are self-descriptive
+	}
+
+	group := LabelSearchGroup{
+		Kind:       target.kind,
+		APIVersion: target.gvr.GroupVersion().String(),
+		Resource:   target.gvr.Resource,
+		Truncated:  list.Continue != "",
+		Items:      make([]LabelSearchMatch, 0, len(list.Items)),
+	}
+	for i := range list.Items {
+		if len(group.Items) == limit {
+			group.Truncated = true
+			break
+		}
+		group.Items = append(group.Items, LabelSearchMatch{Namespace: list.Items[i].Namespace, Name: list.Items[i].Name})
+	}
+	group.Count = len(group.Items)
+
+	sort.Slice(group.Items, func(i, j int) bool {
+		if group.Items[i].Namespace != group.Items[j].Namespace {
+			return group.Items[i].Namespace < group.Items[j].Namespace
+		}
+		return group.Items[i].Name < group.Items[j].Name
+	})
+
+	return group, nil
+}
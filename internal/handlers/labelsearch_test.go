@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"reflect"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/fakecluster"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/resourcefilter"
+)
+
+func TestSearchByLabel(t *testing.T) {
+	t.Parallel()
+
+	payments := map[string]string{"app": "payments"}
+	client := fakecluster.New(fakecluster.Config{
+		Namespace: "default",
+		Objects: []runtime.Object{
+			&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "payments", Namespace: "shop", Labels: payments}},
+			&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "payments", Namespace: "shop", Labels: payments}},
+			&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "payments-config", Namespace: "shop", Labels: payments}},
+			&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "payments-keys", Namespace: "billing", Labels: payments}},
+			&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "payments-2", Namespace: "shop", Labels: payments}},
+			&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "payments-1", Namespace: "shop", Labels: payments}},
+			&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cart-1", Namespace: "shop", Labels: map[string]string{"app": "cart"}}},
+			&corev1.Event{ObjectMeta: metav1.ObjectMeta{Name: "payments.1", Namespace: "shop", Labels: payments}},
+			&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "worker-1", Labels: payments}},
+		},
+	})
+
+	filter, err := resourcefilter.NewFilter("secrets", client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler := NewResourceHandler(client, filter, false)
+
+	result, isErr := callTool(t, handler.SearchByLabel, map[string]any{"label_selector": "app=payments"})
+	if isErr {
+		t.Fatalf("unexpected error: %v", result["error"])
+	}
+
+	// Every namespace is searched, even with a default namespace, and
+	// events, nodes, and disabled secrets are left out.
+	kinds := result["kinds"].([]any)
+	var got []string
+	for _, raw := range kinds {
+		group := raw.(map[string]any)
+		got = append(got, group["kind"].(string))
+	}
+	if want := []string{"ConfigMap", "Deployment", "Pod", "Service"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected matches of kinds %v, got %v", want, got)
+	}
+	if result["total"] != float64(5) {
+		t.Errorf("expected 5 matches, got %v", result["total"])
+	}
+
+	pods := kinds[2].(map[string]any)
+	wantPods := []any{
+		map[string]any{"namespace": "shop", "name": "payments-1"},
+		map[string]any{"namespace": "shop", "name": "payments-2"},
+	}
+	if !reflect.DeepEqual(pods["items"], wantPods) || pods["api_version"] != "v1" {
+		t.Errorf("expected the matching pods sorted by name, got %v", pods)
+	}
+
+	result, isErr = callTool(t, handler.SearchByLabel, map[string]any{
+		"label_selector": "app=payments",
+		"resource_types": "po,nodes,pods",
+		"limit":          1,
+	})
+	if isErr {
+		t.Fatalf("unexpected error: %v", result["error"])
+	}
+	if result["searched_types"] != float64(2) {
+		t.Errorf("expected repeated types to be searched once, got %v types", result["searched_types"])
+	}
+	kinds = result["kinds"].([]any)
+	if len(kinds) != 2 || kinds[0].(map[string]any)["kind"] != "Node" {
+		t.Fatalf("expected the cluster-scoped nodes to be searched, got %v", kinds)
+	}
+	if pods := kinds[1].(map[string]any); pods["count"] != float64(1) || pods["truncated"] != true {
+		t.Errorf("expected the pods to be capped at the limit, got %v", pods)
+	}
+
+	for _, args := range []map[string]any{
+		{"label_selector": " "},
+		{"label_selector": "app in (payments"},
+		{"label_selector": "app=payments", "resource_types": "secrets"},
+		{"label_selector": "app=payments", "resource_types": "widgets"},
+	} {
+		if result, isErr := callTool(t, handler.SearchByLabel, args); !isErr {
+			t.Errorf("expected %v to be rejected, got %v", args, result)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package handlers
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/connectivity"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/kubernetes"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/response"
+)
+
+// NamespaceObjectCount is the number of listed objects in a namespace, as
+// returned by list_resources with count_only.
+type NamespaceObjectCount struct {
+	Namespace string `json:"namespace"`
+	Count     int    `json:"count"`
+
+	// PodStatuses counts the pods by their "kubectl get pods" status, such
+	// as Running or CrashLoopBackOff, when pod_status is set.
+	PodStatuses map[string]int `json:"pod_statuses,omitempty"`
+}
+
+// countResources implements list_resources with count_only. Objects are
+// counted per namespace while paging through metadata-only lists, so no
+// item is returned and only names and namespaces cross the wire. With
+// pod_status, the pods themselves are paged through instead, since their
+// status is not part of their metadata, and counted by status.
+//
+//nolint:gocritic // params is passed by value like the other tool parameters
+func (h *ResourceHandler) countResources(ctx context.Context, client kubernetes.ClusterReader, gvr schema.GroupVersionResource, namespaces []string, params ListResourcesParams) (*mcp.CallToolResult, error) {
+	listed := namespaces
+	switch {
+	case params.AllNamespaces:
+		listed = []string{kubernetes.AllNamespaces}
+	case len(listed) == 0:
+		listed = []string{""}
+	}
+
+	listOptions := metav1.ListOptions{
+		LabelSelector: params.LabelSelector,
+		FieldSelector: params.FieldSelector,
+	}
+
+	count := func(ctx context.Context, namespace string) (map[string]*NamespaceObjectCount, error) {
+		return countListedObjects(ctx, client, gvr, namespace, listOptions, params.PodStatus)
+	}
+
+	var (
+		tallies []map[string]*NamespaceObjectCount
+		err     error
+	)
+	if len(listed) == 1 {
+		var tally map[string]*NamespaceObjectCount
+		if tally, err = count(ctx, listed[0]); err == nil {
+			tallies = append(tallies, tally)
+		}
+	} else {
+		tallies, err = fetchPerNamespace(ctx, listed, count)
+	}
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to count resources: %v", err)
+	}
+
+	// Namespaces asked for by name are reported even when empty.
+	byNamespace := make(map[string]*NamespaceObjectCount)
+	for _, namespace := range namespaces {
+		byNamespace[namespace] = &NamespaceObjectCount{Namespace: namespace}
+	}
+
+	result := ListResourcesResult{
+		ResourceType:  params.ResourceType,
+		Namespace:     params.Namespace,
+		AllNamespaces: params.AllNamespaces,
+		Items:         []any{},
+	}
+	if len(namespaces) > 1 {
+		result.Namespace = ""
+		result.Namespaces = namespaces
+	}
+	if params.PodStatus {
+		result.PodStatuses = make(map[string]int)
+	}
+
+	for _, tally := range tallies {
+		for namespace, counted := range tally {
+			result.Count += counted.Count
+			for status, n := range counted.PodStatuses {
+				result.PodStatuses[status] += n
+			}
+
+			// Cluster-scoped objects have no namespace to count them in.
+			if namespace == "" {
+				continue
+			}
+			byNamespace[namespace] = counted
+		}
+	}
+
+	result.NamespaceCounts = make([]NamespaceObjectCount, 0, len(byNamespace))
+	for _, counted := range byNamespace {
+		result.NamespaceCounts = append(result.NamespaceCounts, *counted)
+	}
+	sort.Slice(result.NamespaceCounts, func(i, j int) bool {
+		a, b := result.NamespaceCounts[i], result.NamespaceCounts[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.Namespace < b.Namespace
+	})
+
+	return response.JSON(result)
+}
+
+// countListedObjects counts the objects of gvr in namespace matching opts,
+// keyed by the namespace they live in, paging through the list. With
+// podStatuses, the pods are also counted by status.
+//
+//nolint:gocritic // opts is copied so its paging fields can be set
+func countListedObjects(ctx context.Context, client kubernetes.ClusterReader, gvr schema.GroupVersionResource, namespace string, opts metav1.ListOptions, podStatuses bool) (map[string]*NamespaceObjectCount, error) {
+	counts := make(map[string]*NamespaceObjectCount)
+	counted := func(namespace string) *NamespaceObjectCount {
+		if counts[namespace] == nil {
+			counts[namespace] = &NamespaceObjectCount{Namespace: namespace}
+			if podStatuses {
+				counts[namespace].PodStatuses = make(map[string]int)
+			}
+		}
+		return counts[namespace]
+	}
+
+	now := time.Now()
+	opts.Limit = customResourcePageSize
+	for {
+		var next string
+
+		if podStatuses {
+			page, err := client.ListResources(ctx, gvr, namespace, opts)
+			if err != nil {
+				return nil, err //nolint:wrapcheck // kubernetes API errors are self-descriptive
+			}
+			for i := range page.Items {
+				c := counted(page.Items[i].GetNamespace())
+				c.Count++
+
+				var pod corev1.Pod
+				if err := runtime.DefaultUnstructuredConverter.FromUnstructured(page.Items[i].Object, &pod); err != nil {
+					c.PodStatuses["Unknown"]++
+					continue
+				}
+				c.PodStatuses[rollUpPodStatus(&pod, now).Status]++
+			}
+			next = page.GetContinue()
+		} else {
+			page, err := client.ListResourceMetadata(ctx, gvr, namespace, opts)
+			if err != nil {
+				return nil, err //nolint:wrapcheck // kubernetes API errors are self-descriptive
+			}
+			for i := range page.Items {
+				counted(page.Items[i].Namespace).Count++
+			}
+			next = page.Continue
+		}
+
+		if next == "" {
+			return counts, nil
+		}
+		opts.Continue = next
+	}
+}
@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/fakecluster"
+)
+
+func TestListResources_CountOnly(t *testing.T) {
+	t.Parallel()
+
+	crashing := corev1.PodStatus{
+		Phase: corev1.PodRunning,
+		ContainerStatuses: []corev1.ContainerStatus{{
+			Name:  "app",
+			State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+		}},
+	}
+	running := corev1.PodStatus{Phase: corev1.PodRunning}
+	web := map[string]string{"app": "web"}
+
+	client := fakecluster.New(fakecluster.Config{
+		Namespace: "shop",
+		Objects: []runtime.Object{
+			&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "shop", Labels: web}, Status: running},
+			&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web-2", Namespace: "shop", Labels: web}, Status: crashing},
+			&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "worker", Namespace: "shop"}, Status: crashing},
+			&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "invoicer", Namespace: "billing"}, Status: running},
+			&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "worker-1"}},
+			&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "worker-2"}},
+		},
+	})
+	handler := NewResourceHandler(client, nil, false)
+
+	tests := []struct {
+		name       string
+		args       map[string]any
+		wantCount  float64
+		wantCounts any
+		wantStatus any
+	}{
+		{
+			name:      "default namespace",
+			args:      map[string]any{"resource_type": "pods", "count_only": true},
+			wantCount: 3,
+			wantCounts: []any{
+				map[string]any{"namespace": "shop", "count": float64(3)},
+			},
+		},
+		{
+			name:      "every namespace by status",
+			args:      map[string]any{"resource_type": "pods", "all_namespaces": true, "count_only": true, "pod_status": true},
+			wantCount: 4,
+			wantCounts: []any{
+				map[string]any{"namespace": "shop", "count": float64(3), "pod_statuses": map[string]any{"Running": float64(1), "CrashLoopBackOff": float64(2)}},
+				map[string]any{"namespace": "billing", "count": float64(1), "pod_statuses": map[string]any{"Running": float64(1)}},
+			},
+			wantStatus: map[string]any{"Running": float64(2), "CrashLoopBackOff": float64(2)},
+		},
+		{
+			name:      "several namespaces with a selector",
+			args:      map[string]any{"resource_type": "pods", "namespace": "shop,billing", "label_selector": "app=web", "count_only": true},
+			wantCount: 2,
+			wantCounts: []any{
+				map[string]any{"namespace": "shop", "count": float64(2)},
+				map[string]any{"namespace": "billing", "count": float64(0)},
+			},
+		},
+		{
+			name:      "cluster-scoped",
+			args:      map[string]any{"resource_type": "nodes", "all_namespaces": true, "count_only": true},
+			wantCount: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		result, isErr := callTool(t, handler.ListResources, tt.args)
+		if isErr {
+			t.Fatalf("%s: unexpected error: %v", tt.name, result["error"])
+		}
+		if result["count"] != tt.wantCount {
+			t.Errorf("%s: expected a count of %v, got %v", tt.name, tt.wantCount, result["count"])
+		}
+		if !reflect.DeepEqual(result["namespace_counts"], tt.wantCounts) {
+			t.Errorf("%s: expected namespace counts %v, got %v", tt.name, tt.wantCounts, result["namespace_counts"])
+		}
+		if !reflect.DeepEqual(result["pod_statuses"], tt.wantStatus) {
+			t.Errorf("%s: expected pod statuses %v, got %v", tt.name, tt.wantStatus, result["pod_statuses"])
+		}
+		if items, _ := result["items"].([]any); len(items) != 0 {
+			t.Errorf("%s: expected no items, got %v", tt.name, items)
+		}
+	}
+
+	for _, args := range []map[string]any{
+		{"resource_type": "pods", "count_only": true, "columns": "metadata.name"},
+		{"resource_type": "pods", "count_only": true, "include_spec": true},
+		{"resource_type": "pods", "count_only": true, "limit": 10},
+	} {
+		if result, isErr := callTool(t, handler.ListResources, args); !isErr {
+			t.Errorf("expected %v to be rejected, got %v", args, result)
+		}
+	}
+}
@@ -2,7 +2,6 @@ package handlers
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"strings"
 
@@ -11,13 +10,14 @@ import (
 	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/kubernetes"
 	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/logfilter"
 	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/response"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/toolschema"
 )
 
 // LogHandler provides MCP tools for retrieving and filtering Kubernetes pod logs.
 // It supports advanced log filtering with grep-like capabilities, time-based filtering,
 // container selection in multi-container pods, and access to previous container logs.
 type LogHandler struct {
-	client      *kubernetes.Client
+	client      kubernetes.ClusterReader
 	alwaysStart bool
 }
 
@@ -25,61 +25,81 @@ type LogHandler struct {
 // alwaysStart mirrors the --always-start flag: when true, connectivity and auth errors
 // are intercepted and returned as structured tool errors so the LLM can surface them
 // to the user rather than treating them as retryable failures.
-func NewLogHandler(client *kubernetes.Client, alwaysStart bool) *LogHandler {
+func NewLogHandler(client kubernetes.ClusterReader, alwaysStart bool) *LogHandler {
 	return &LogHandler{
 		client:      client,
 		alwaysStart: alwaysStart,
 	}
 }
 
-// GetLogs implements the get_logs MCP tool.
-// It retrieves pod logs with comprehensive filtering options including grep-like
-// pattern matching, time-based filtering, line limits, and container selection.
-// The logs can be filtered both by inclusion and exclusion patterns, supporting
-// both literal strings and regular expressions.
-func (h *LogHandler) GetLogs(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	var params struct {
-		// Namespace specifies the pod's namespace.
-		Namespace string `json:"namespace"`
+// GetLogsParams defines the parameters for the get_logs MCP tool.
+type GetLogsParams struct {
+	// Namespace specifies the pod's namespace.
+	Namespace string `json:"namespace" required:"true" description:"Pod namespace"`
 
-		// Name specifies which pod's logs to retrieve.
-		Name string `json:"name"`
+	// Name specifies which pod's logs to retrieve.
+	Name string `json:"name" required:"true" description:"Pod name"`
 
-		// Container specifies which container's logs to retrieve (optional for single-container pods).
-		Container string `json:"container"`
+	// Container specifies which container's logs to retrieve (optional for single-container pods).
+	// Ephemeral debug containers are accepted as well.
+	Container string `json:"container" description:"Container name (required for multi-container pods). Ephemeral debug containers listed by get_pod_containers are also accepted"`
 
-		// Context specifies which Kubernetes context to use for this operation.
-		Context string `json:"context"`
+	// Context specifies which Kubernetes context to use for this operation.
+	Context string `json:"context" description:"Kubernetes context to use (defaults to current context from kubeconfig)"`
 
-		// MaxLines limits the number of log lines to retrieve.
-		MaxLines int `json:"max_lines"`
+	// MaxLines limits the number of log lines to retrieve.
+	MaxLines int `json:"max_lines" minimum:"0" description:"Maximum number of lines to retrieve"`
 
-		// GrepInclude contains comma-separated patterns that lines must match to be included.
-		GrepInclude string `json:"grep_include"`
+	// GrepInclude contains comma-separated patterns that lines must match to be included.
+	GrepInclude string `json:"grep_include" description:"Include only lines matching these patterns (comma-separated). Works like grep - includes lines containing any of these patterns"`
 
-		// GrepExclude contains comma-separated patterns that exclude lines from output.
-		GrepExclude string `json:"grep_exclude"`
+	// GrepExclude contains comma-separated patterns that exclude lines from output.
+	GrepExclude string `json:"grep_exclude" description:"Exclude lines matching these patterns (comma-separated). Works like grep -v - excludes lines containing any of these patterns"`
 
-		// UseRegex determines whether to treat patterns as regular expressions.
-		UseRegex bool `json:"use_regex"`
+	// UseRegex determines whether to treat patterns as regular expressions.
+	UseRegex bool `json:"use_regex" description:"Whether to treat grep patterns as regular expressions instead of literal strings"`
 
-		// Since retrieves logs newer than this time (supports durations like "5m" or absolute times).
-		Since string `json:"since"`
+	// Since retrieves logs newer than this time (supports durations like "5m" or absolute times).
+	Since string `json:"since" description:"Return logs newer than this time. Supports durations like \"5m\", \"1h\", \"2h30m\", \"1d\" or absolute times like \"2023-01-01T10:00:00Z\""`
 
-		// Previous retrieves logs from the previous terminated container instance.
-		Previous bool `json:"previous"`
-	}
+	// Previous retrieves logs from the previous terminated container instance.
+	Previous bool `json:"previous" description:"Return logs from the previous terminated container instance (like kubectl logs --previous)"`
+}
 
-	if err := request.BindArguments(&params); err != nil {
-		return nil, fmt.Errorf("failed to parse arguments: %w", err)
-	}
+// LogsMetadata describes how the logs returned by get_logs were filtered.
+type LogsMetadata struct {
+	TotalLines    int      `json:"total_lines"`
+	MatchingLines int      `json:"matching_lines"`
+	Filtered      bool     `json:"filtered"`
+	Since         string   `json:"since"`
+	Previous      bool     `json:"previous"`
+	UseRegex      bool     `json:"use_regex"`
+	GrepInclude   []string `json:"grep_include"`
+	GrepExclude   []string `json:"grep_exclude"`
+}
 
-	if params.Name == "" {
-		return nil, errors.New("pod name is required")
+// GetLogsResult is the result of the get_logs MCP tool.
+type GetLogsResult struct {
+	Namespace string       `json:"namespace"`
+	Pod       string       `json:"pod"`
+	Container string       `json:"container"`
+	Logs      string       `json:"logs"`
+	Metadata  LogsMetadata `json:"metadata"`
+}
+
+// GetLogs implements the get_logs MCP tool.
+// It retrieves pod logs with comprehensive filtering options including grep-like
+// pattern matching, time-based filtering, line limits, and container selection.
+// The logs can be filtered both by inclusion and exclusion patterns, supporting
+// both literal strings and regular expressions.
+func (h *LogHandler) GetLogs(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var params GetLogsParams
+	if err := bindParams(request, &params); err != nil {
+		return nil, fmt.Errorf("failed to parse arguments: %w", err)
 	}
 
 	// Use the appropriate client based on context
-	client, err := h.client.ForContext(params.Context)
+	client, err := h.client.ForContext(ctx, params.Context)
 	if err != nil {
 		if h.alwaysStart && connectivity.IsTransportError(err) {
 			return response.Error(connectivity.ErrorMessage(err))
@@ -157,51 +177,55 @@ func (h *LogHandler) GetLogs(ctx context.Context, request mcp.CallToolRequest) (
 		return nil, fmt.Errorf("failed to count matching lines: %w", err)
 	}
 
-	responseData := map[string]interface{}{
-		"namespace": params.Namespace,
-		"pod":       params.Name,
-		"container": params.Container,
-		"logs":      filteredLogs,
-		"metadata": map[string]interface{}{
-			"total_lines":    len(strings.Split(logs, "\n")),
-			"matching_lines": matchingLines,
-			"filtered":       len(grepInclude) > 0 || len(grepExclude) > 0,
-			"since":          params.Since,
-			"previous":       params.Previous,
-			"use_regex":      params.UseRegex,
-			"grep_include":   grepInclude,
-			"grep_exclude":   grepExclude,
+	return response.JSON(GetLogsResult{
+		Namespace: params.Namespace,
+		Pod:       params.Name,
+		Container: params.Container,
+		Logs:      filteredLogs,
+		Metadata: LogsMetadata{
+			TotalLines:    len(strings.Split(logs, "\n")),
+			MatchingLines: matchingLines,
+			Filtered:      len(grepInclude) > 0 || len(grepExclude) > 0,
+			Since:         params.Since,
+			Previous:      params.Previous,
+			UseRegex:      params.UseRegex,
+			GrepInclude:   grepInclude,
+			GrepExclude:   grepExclude,
 		},
-	}
+	})
+}
+
+// GetPodContainersParams defines the parameters for the get_pod_containers MCP tool.
+type GetPodContainersParams struct {
+	// Namespace specifies the pod's namespace.
+	Namespace string `json:"namespace" required:"true" description:"Pod namespace"`
+
+	// Name specifies which pod to inspect for containers.
+	Name string `json:"name" required:"true" description:"Pod name"`
+
+	// Context specifies which Kubernetes context to use for this operation.
+	Context string `json:"context" description:"Kubernetes context to use (defaults to current context from kubeconfig)"`
+}
 
-	return response.JSON(responseData)
+// GetPodContainersResult is the result of the get_pod_containers MCP tool.
+type GetPodContainersResult struct {
+	Containers          []string                 `json:"containers"`
+	EphemeralContainers []EphemeralContainerInfo `json:"ephemeral_containers"`
 }
 
 // GetPodContainers implements the get_pod_containers MCP tool.
 // It retrieves the list of container names within a specific pod, which is useful
 // for identifying available containers before retrieving logs from multi-container pods.
+// Ephemeral containers attached with "kubectl debug" are reported separately with
+// their image, target container, and current state.
 func (h *LogHandler) GetPodContainers(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	var params struct {
-		// Namespace specifies the pod's namespace.
-		Namespace string `json:"namespace"`
-
-		// Name specifies which pod to inspect for containers.
-		Name string `json:"name"`
-
-		// Context specifies which Kubernetes context to use for this operation.
-		Context string `json:"context"`
-	}
-
-	if err := request.BindArguments(&params); err != nil {
+	var params GetPodContainersParams
+	if err := bindParams(request, &params); err != nil {
 		return nil, fmt.Errorf("failed to parse arguments: %w", err)
 	}
 
-	if params.Name == "" {
-		return nil, errors.New("pod name is required")
-	}
-
 	// Use the appropriate client based on context
-	client, err := h.client.ForContext(params.Context)
+	client, err := h.client.ForContext(ctx, params.Context)
 	if err != nil {
 		if h.alwaysStart && connectivity.IsTransportError(err) {
 			return response.Error(connectivity.ErrorMessage(err))
@@ -209,7 +233,7 @@ func (h *LogHandler) GetPodContainers(ctx context.Context, request mcp.CallToolR
 		return nil, fmt.Errorf("failed to create client with context %s: %w", params.Context, err)
 	}
 
-	containers, err := client.GetPodContainers(ctx, params.Namespace, params.Name)
+	pod, err := client.GetPod(ctx, params.Namespace, params.Name)
 	if err != nil {
 		if h.alwaysStart && connectivity.IsTransportError(err) {
 			return response.Error(connectivity.ErrorMessage(err))
@@ -217,8 +241,14 @@ func (h *LogHandler) GetPodContainers(ctx context.Context, request mcp.CallToolR
 		return nil, fmt.Errorf("failed to get pod containers: %w", err)
 	}
 
-	return response.JSON(map[string]interface{}{
-		"containers": containers,
+	containers := make([]string, 0, len(pod.Spec.Containers))
+	for i := range pod.Spec.Containers {
+		containers = append(containers, pod.Spec.Containers[i].Name)
+	}
+
+	return response.JSON(GetPodContainersResult{
+		Containers:          containers,
+		EphemeralContainers: ephemeralContainerInfos(pod),
 	})
 }
 
@@ -230,55 +260,16 @@ func (h *LogHandler) GetTools() []MCPTool {
 		NewMCPTool(
 			mcp.NewTool("get_logs",
 				mcp.WithDescription("Get pod logs with advanced filtering options including grep patterns, time filtering, and previous logs"),
-				mcp.WithString("namespace",
-					mcp.Required(),
-					mcp.Description("Pod namespace"),
-				),
-				mcp.WithString("name",
-					mcp.Required(),
-					mcp.Description("Pod name"),
-				),
-				mcp.WithString("container",
-					mcp.Description("Container name (required for multi-container pods)"),
-				),
-				mcp.WithString("context",
-					mcp.Description("Kubernetes context to use (defaults to current context from kubeconfig)"),
-				),
-				mcp.WithNumber("max_lines",
-					mcp.Description("Maximum number of lines to retrieve"),
-				),
-				mcp.WithString("grep_include",
-					mcp.Description("Include only lines matching these patterns (comma-separated). Works like grep - includes lines containing any of these patterns"),
-				),
-				mcp.WithString("grep_exclude",
-					mcp.Description("Exclude lines matching these patterns (comma-separated). Works like grep -v - excludes lines containing any of these patterns"),
-				),
-				mcp.WithBoolean("use_regex",
-					mcp.Description("Whether to treat grep patterns as regular expressions instead of literal strings"),
-				),
-				mcp.WithString("since",
-					mcp.Description("Return logs newer than this time. Supports durations like \"5m\", \"1h\", \"2h30m\", \"1d\" or absolute times like \"2023-01-01T10:00:00Z\""),
-				),
-				mcp.WithBoolean("previous",
-					mcp.Description("Return logs from the previous terminated container instance (like kubectl logs --previous)"),
-				),
+				toolschema.Input[GetLogsParams](),
+				toolschema.Output[GetLogsResult](),
 			),
 			h.GetLogs,
 		),
 		NewMCPTool(
 			mcp.NewTool("get_pod_containers",
-				mcp.WithDescription("List containers in a pod for log access"),
-				mcp.WithString("namespace",
-					mcp.Required(),
-					mcp.Description("Pod namespace"),
-				),
-				mcp.WithString("name",
-					mcp.Required(),
-					mcp.Description("Pod name"),
-				),
-				mcp.WithString("context",
-					mcp.Description("Kubernetes context to use (defaults to current context from kubeconfig)"),
-				),
+				mcp.WithDescription("List containers in a pod for log access, including ephemeral debug containers attached via kubectl debug (with their image, target container, and state)"),
+				toolschema.Input[GetPodContainersParams](),
+				toolschema.Output[GetPodContainersResult](),
 			),
 			h.GetPodContainers,
 		),
@@ -0,0 +1,391 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/connectivity"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/resourcefilter"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/response"
+)
+
+// Actions reported by diff_manifest, named like the output of kubectl apply.
+const (
+	manifestActionCreate    = "create"
+	manifestActionConfigure = "configure"
+	manifestActionUnchanged = "unchanged"
+	manifestActionError     = "error"
+)
+
+// DiffManifestParams defines the parameters for the diff_manifest MCP tool.
+type DiffManifestParams struct {
+	// Manifest is the YAML or JSON to compare. YAML may hold several
+	// documents separated by "---".
+	Manifest string `json:"manifest" required:"true" description:"Manifest to compare with the live objects, as YAML or JSON. YAML may contain several documents separated by ---"`
+
+	// Namespace is used for documents that do not set metadata.namespace.
+	Namespace string `json:"namespace,omitempty" description:"Namespace for documents that do not set metadata.namespace (defaults to the namespace of the current context)"`
+
+	// MaxDifferences bounds how many differences are returned per document.
+	MaxDifferences int `json:"max_differences,omitempty" minimum:"1" default:"100" description:"Maximum number of field differences to return per document (defaults to 100)"`
+
+	// Context specifies which Kubernetes context to use for this operation.
+	Context string `json:"context,omitempty" description:"Kubernetes context to use (defaults to current context from kubeconfig)"`
+}
+
+// ManifestDiffResult is the diff of one manifest document against its live
+// object.
+type ManifestDiffResult struct {
+	Index      int    `json:"index"`
+	APIVersion string `json:"api_version,omitempty"`
+	Kind       string `json:"kind,omitempty"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name,omitempty"`
+
+	// Action is what kubectl apply would do: create, configure, or
+	// unchanged. It is error when the document could not be compared.
+	Action string `json:"action"`
+	Error  string `json:"error,omitempty"`
+
+	// Differences use a for the live value and b for the manifest value.
+	DifferenceCount int               `json:"difference_count,omitempty"`
+	Differences     []FieldDifference `json:"differences,omitempty"`
+	Truncated       bool              `json:"truncated,omitempty"`
+	Notes           []string          `json:"notes,omitempty"`
+}
+
+// DiffManifestResult is the result of the diff_manifest MCP tool.
+type DiffManifestResult struct {
+	Documents []ManifestDiffResult `json:"documents"`
+	Count     int                  `json:"count"`
+
+	// Changed counts the documents that would be created or configured.
+	Changed int `json:"changed"`
+}
+
+// DiffManifest implements the diff_manifest MCP tool.
+// It previews what kubectl apply would change without applying anything:
+// for every document of a manifest it reads the live object and compares the
+// fields the manifest sets. Like client-side apply, fields the manifest
+// leaves out are kept, unless the last-applied-configuration annotation shows
+// they were applied before, in which case they would be removed. Server-side
+// defaulting and admission webhooks are not simulated, so fields they change
+// may show up as differences.
+func (h *ResourceHandler) DiffManifest(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var params DiffManifestParams
+	if err := bindParams(request, &params); err != nil {
+		return response.Errorf("failed to parse arguments: %s", err)
+	}
+
+	documents, err := decodeManifest(params.Manifest)
+	if err != nil {
+		return response.Errorf("failed to parse manifest: %v", err)
+	}
+
+	if len(documents) == 0 {
+		return response.Error("the manifest contains no documents")
+	}
+
+	if len(documents) > maxManifestDocuments {
+		return response.Errorf("the manifest contains %d documents, more than the maximum of %d", len(documents), maxManifestDocuments)
+	}
+
+	maxDifferences := params.MaxDifferences
+	if maxDifferences <= 0 {
+		maxDifferences = defaultMaxDifferences
+	}
+
+	// Use the appropriate client based on context
+	client, err := h.client.ForContext(ctx, params.Context)
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to create client with context %s: %v", params.Context, err)
+	}
+
+	results := make([]ManifestDiffResult, 0, len(documents))
+	changed := 0
+
+	for i, object := range documents {
+		manifest := &unstructured.Unstructured{Object: object}
+		result := ManifestDiffResult{
+			Index:      i,
+			APIVersion: manifest.GetAPIVersion(),
+			Kind:       manifest.GetKind(),
+			Namespace:  manifest.GetNamespace(),
+			Name:       manifest.GetName(),
+		}
+		if result.Namespace == "" {
+			result.Namespace = params.Namespace
+		}
+
+		fail := func(format string, args ...interface{}) {
+			result.Action = manifestActionError
+			result.Error = fmt.Sprintf(format, args...)
+		}
+
+		gvk, issues := manifestGroupVersionKind(result.APIVersion, result.Kind)
+		switch {
+		case len(issues) > 0:
+			fail("%s", issues[0].Message)
+		case result.Name == "":
+			fail("metadata.name is required")
+		}
+		if result.Action == manifestActionError {
+			results = append(results, result)
+			continue
+		}
+
+		gvr, err := client.ResolveResourceType(gvk.Kind, gvk.GroupVersion().String())
+		if err != nil {
+			if h.alwaysStart && connectivity.IsError(err) {
+				return response.Error(connectivity.ErrorMessage(err))
+			}
+			fail("failed to resolve %s %s: %v", result.APIVersion, result.Kind, err)
+			results = append(results, result)
+			continue
+		}
+
+		if h.resourceFilter != nil && h.resourceFilter.IsDisabled(gvr) {
+			fail("access to %s is disabled by configuration and cannot be queried", resourcefilter.FormatGVR(gvr))
+			results = append(results, result)
+			continue
+		}
+
+		live, err := getResourceFrom(ctx, client, gvr, result.Namespace, result.Name)
+		if err != nil {
+			if h.alwaysStart && connectivity.IsTransportError(err) {
+				return response.Error(connectivity.ErrorMessage(err))
+			}
+			fail("failed to get the live object: %v", err)
+			results = append(results, result)
+			continue
+		}
+
+		if live == nil {
+			result.Action = manifestActionCreate
+			changed++
+			results = append(results, result)
+			continue
+		}
+		result.Namespace = live.GetNamespace()
+
+		// kubectl apply fills in the namespace of documents that leave it out.
+		if manifest.GetNamespace() == "" && result.Namespace != "" {
+			manifest = manifest.DeepCopy()
+			manifest.SetNamespace(result.Namespace)
+		}
+
+		var lastApplied map[string]interface{}
+		if raw, ok := live.GetAnnotations()[lastAppliedAnnotation]; ok {
+			if err := json.Unmarshal([]byte(raw), &lastApplied); err != nil {
+				result.Notes = append(result.Notes, fmt.Sprintf("the %s annotation could not be parsed, so fields removed from the manifest are not detected: %v", lastAppliedAnnotation, err))
+			}
+		} else {
+			result.Notes = append(result.Notes, "the live object was not created with kubectl apply, so fields left out of the manifest are not reported as removed")
+		}
+
+		ignored := diffIgnoredPaths(live.GetKind(), false)
+		differences := applyDiff("",
+			normalizeForDiff(live, ignored),
+			normalizeForDiff(manifest, ignored),
+			stripIgnored(lastApplied, ignored))
+
+		result.Action = manifestActionUnchanged
+		if len(differences) > 0 {
+			result.Action = manifestActionConfigure
+			changed++
+		}
+		result.DifferenceCount = len(differences)
+		if len(differences) > maxDifferences {
+			differences = differences[:maxDifferences]
+			result.Truncated = true
+		}
+		result.Differences = differences
+
+		results = append(results, result)
+	}
+
+	return response.JSON(DiffManifestResult{
+		Documents: results,
+		Count:     len(results),
+		Changed:   changed,
+	})
+}
+
+// stripIgnored returns a copy of a last-applied configuration without the
+// ignored fields, or nil when there is none.
+func stripIgnored(lastApplied map[string]interface{}, ignored [][]string) map[string]interface{} {
+	if lastApplied == nil {
+		return nil
+	}
+	return normalizeForDiff(&unstructured.Unstructured{Object: lastApplied}, ignored)
+}
+
+// applyDiff compares the fields a manifest sets with the live object, the way
+// client-side apply merges them. Fields only the live object has are kept by
+// apply, so they are only reported as removed when lastApplied, the previously
+// applied configuration at the same path, shows the manifest used to set them.
+func applyDiff(path string, live, manifest, lastApplied interface{}) []FieldDifference {
+	var differences []FieldDifference
+
+	switch mv := manifest.(type) {
+	case map[string]interface{}:
+		lv, ok := live.(map[string]interface{})
+		if !ok {
+			break
+		}
+		av, _ := lastApplied.(map[string]interface{})
+
+		for _, key := range unionKeys(lv, mv) {
+			childPath := fieldPath(path, key)
+			liveValue, inLive := lv[key]
+			manifestValue, inManifest := mv[key]
+			_, wasApplied := av[key]
+
+			switch {
+			case !inLive:
+				if manifestValue != nil {
+					differences = append(differences, FieldDifference{Path: childPath, Change: "added", B: manifestValue})
+				}
+			case !inManifest:
+				if wasApplied {
+					differences = append(differences, FieldDifference{Path: childPath, Change: "removed", A: liveValue})
+				}
+			default:
+				differences = append(differences, applyDiff(childPath, liveValue, manifestValue, av[key])...)
+			}
+		}
+		return differences
+
+	case []interface{}:
+		lv, ok := live.([]interface{})
+		if !ok {
+			break
+		}
+		av, _ := lastApplied.([]interface{})
+
+		// Lists of named entries are merged by name, like containers.
+		if liveNames, manifestNames := namedEntries(lv), namedEntries(mv); liveNames != nil && manifestNames != nil {
+			appliedNames := namedEntries(av)
+			for _, name := range unionNames(liveNames, manifestNames) {
+				childPath := fmt.Sprintf("%s[name=%s]", path, name)
+				li, inLive := liveNames[name]
+				mi, inManifest := manifestNames[name]
+				ai, wasApplied := appliedNames[name]
+
+				switch {
+				case !inLive:
+					differences = append(differences, FieldDifference{Path: childPath, Change: "added", B: mv[mi]})
+				case !inManifest:
+					if wasApplied {
+						differences = append(differences, FieldDifference{Path: childPath, Change: "removed", A: lv[li]})
+					}
+				default:
+					var applied interface{}
+					if wasApplied {
+						applied = av[ai]
+					}
+					differences = append(differences, applyDiff(childPath, lv[li], mv[mi], applied)...)
+				}
+			}
+			return differences
+		}
+
+		// Other lists are replaced as a whole. When they have the same
+		// length, compare entries one by one so that defaulted fields
+		// inside them, such as a port's protocol, are not differences.
+		if len(lv) == len(mv) {
+			for i := range mv {
+				var applied interface{}
+				if i < len(av) {
+					applied = av[i]
+				}
+				differences = append(differences, applyDiff(fmt.Sprintf("%s[%d]", path, i), lv[i], mv[i], applied)...)
+			}
+			return differences
+		}
+	}
+
+	if !manifestValueEqual(path, live, manifest) {
+		differences = append(differences, FieldDifference{Path: path, Change: "changed", A: live, B: manifest})
+	}
+	return differences
+}
+
+// manifestValueEqual compares a live value with a manifest value. Manifests
+// decode numbers as floats while live objects use integers, and resource
+// quantities may be written in different units, such as 0.5 and 500m.
+func manifestValueEqual(path string, live, manifest interface{}) bool {
+	if reflect.DeepEqual(live, manifest) {
+		return true
+	}
+
+	if ln, ok := jsonNumber(live); ok {
+		if mn, ok := jsonNumber(manifest); ok {
+			return ln == mn
+		}
+	}
+
+	if strings.Contains(path, "requests.") || strings.Contains(path, "limits.") || strings.Contains(path, "capacity.") || strings.Contains(path, "hard.") {
+		lq, lerr := resource.ParseQuantity(fmt.Sprint(live))
+		mq, merr := resource.ParseQuantity(fmt.Sprint(manifest))
+		if lerr == nil && merr == nil {
+			return lq.Cmp(mq) == 0
+		}
+	}
+
+	return false
+}
+
+// jsonNumber converts a decoded JSON number to a float64.
+func jsonNumber(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case int64:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
+
+// unionKeys returns the keys of two maps, sorted.
+func unionKeys(a, b map[string]interface{}) []string {
+	keys := make([]string, 0, len(a)+len(b))
+	for key := range a {
+		keys = append(keys, key)
+	}
+	for key := range b {
+		if _, ok := a[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// unionNames returns the names of two named-entry indexes, sorted.
+func unionNames(a, b map[string]int) []string {
+	names := make([]string, 0, len(a)+len(b))
+	for name := range a {
+		names = append(names, name)
+	}
+	for name := range b {
+		if _, ok := a[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,179 @@
+package handlers
+
+import (
+	"reflect"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/fakecluster"
+)
+
+func TestApplyDiff(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name                        string
+		live, manifest, lastApplied map[string]interface{}
+		want                        []FieldDifference
+	}{
+		{
+			name:     "fields only the live object sets are kept",
+			live:     map[string]interface{}{"replicas": int64(2), "progressDeadlineSeconds": int64(600)},
+			manifest: map[string]interface{}{"replicas": float64(2)},
+		},
+		{
+			name:        "fields dropped from the last applied configuration are removed",
+			live:        map[string]interface{}{"replicas": int64(2), "paused": true},
+			manifest:    map[string]interface{}{"replicas": float64(3)},
+			lastApplied: map[string]interface{}{"replicas": float64(2), "paused": true},
+			want: []FieldDifference{
+				{Path: "paused", Change: "removed", A: true},
+				{Path: "replicas", Change: "changed", A: int64(2), B: float64(3)},
+			},
+		},
+		{
+			name: "defaulted fields inside lists are ignored",
+			live: map[string]interface{}{"ports": []interface{}{
+				map[string]interface{}{"containerPort": int64(80), "protocol": "TCP"},
+			}},
+			manifest: map[string]interface{}{"ports": []interface{}{
+				map[string]interface{}{"containerPort": float64(80)},
+			}},
+		},
+		{
+			name: "equal quantities in different units",
+			live: map[string]interface{}{"resources": map[string]interface{}{
+				"requests": map[string]interface{}{"cpu": "500m", "memory": "1Gi"},
+			}},
+			manifest: map[string]interface{}{"resources": map[string]interface{}{
+				"requests": map[string]interface{}{"cpu": float64(0.5), "memory": "1024Mi"},
+			}},
+		},
+		{
+			name: "named entries are merged by name",
+			live: map[string]interface{}{"containers": []interface{}{
+				map[string]interface{}{"name": "app", "image": "web:1.0"},
+				map[string]interface{}{"name": "injected", "image": "proxy:1.0"},
+			}},
+			manifest: map[string]interface{}{"containers": []interface{}{
+				map[string]interface{}{"name": "app", "image": "web:1.1"},
+			}},
+			want: []FieldDifference{
+				{Path: "containers[name=app].image", Change: "changed", A: "web:1.0", B: "web:1.1"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var lastApplied interface{}
+			if tt.lastApplied != nil {
+				lastApplied = tt.lastApplied
+			}
+			if got := applyDiff("", tt.live, tt.manifest, lastApplied); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestDiffManifest_FakeCluster(t *testing.T) {
+	t.Parallel()
+
+	replicas := int32(2)
+	handler := NewResourceHandler(fakecluster.New(fakecluster.Config{
+		Namespace: "shop",
+		Objects: []runtime.Object{
+			&appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop", ResourceVersion: "42"},
+				Spec: appsv1.DeploymentSpec{
+					Replicas: &replicas,
+					Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+						Containers: []corev1.Container{{
+							Name:      "app",
+							Image:     "web:1.0",
+							Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m")}},
+						}},
+					}},
+				},
+				Status: appsv1.DeploymentStatus{ReadyReplicas: 2},
+			},
+			&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "web-config", Namespace: "shop"},
+				Data:       map[string]string{"mode": "production"},
+			},
+		},
+	}), nil, false)
+
+	manifest := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  replicas: 2
+  template:
+    spec:
+      containers:
+      - name: app
+        image: web:1.1
+        resources:
+          requests:
+            cpu: 0.5
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: web-config
+  namespace: shop
+data:
+  mode: production
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: new-config
+data:
+  key: value
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  labels: {}
+`
+
+	result, isErr := callTool(t, handler.DiffManifest, map[string]any{"manifest": manifest})
+	if isErr {
+		t.Fatalf("unexpected error: %v", result["error"])
+	}
+
+	var documents []ManifestDiffResult
+	decodeInto(t, result["documents"], &documents)
+
+	var actions []string
+	for _, document := range documents {
+		actions = append(actions, document.Action)
+	}
+	want := []string{manifestActionConfigure, manifestActionUnchanged, manifestActionCreate, manifestActionError}
+	if !reflect.DeepEqual(actions, want) {
+		t.Fatalf("expected actions %v, got %v (%+v)", want, actions, documents)
+	}
+
+	wantDifferences := []FieldDifference{
+		{Path: "spec.template.spec.containers[name=app].image", Change: "changed", A: "web:1.0", B: "web:1.1"},
+	}
+	if !reflect.DeepEqual(documents[0].Differences, wantDifferences) {
+		t.Errorf("expected differences %+v, got %+v", wantDifferences, documents[0].Differences)
+	}
+
+	if result["changed"] != float64(2) {
+		t.Errorf("expected 2 changed documents, got %v", result["changed"])
+	}
+}
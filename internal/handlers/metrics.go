@@ -7,11 +7,14 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/connectivity"
 	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/kubernetes"
 	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/response"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/toolschema"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
 )
 
@@ -20,18 +23,23 @@ import (
 // The handler supports both cluster-wide and targeted metrics retrieval with
 // client-side pagination for consistent ordering and performance.
 type MetricsHandler struct {
-	client      *kubernetes.Client
+	client      kubernetes.ClusterReader
 	alwaysStart bool
+
+	// wait pauses between polls when get_node_metrics samples several times.
+	// It defaults to sleepContext and is replaced in tests to avoid waiting.
+	wait func(ctx context.Context, d time.Duration) error
 }
 
 // NewMetricsHandler creates a new MetricsHandler with the provided Kubernetes client.
 // alwaysStart mirrors the --always-start flag: when true, connectivity and auth errors
 // are intercepted and returned as structured tool errors so the LLM can surface them
 // to the user rather than treating them as retryable failures.
-func NewMetricsHandler(client *kubernetes.Client, alwaysStart bool) *MetricsHandler {
+func NewMetricsHandler(client kubernetes.ClusterReader, alwaysStart bool) *MetricsHandler {
 	return &MetricsHandler{
 		client:      client,
 		alwaysStart: alwaysStart,
+		wait:        sleepContext,
 	}
 }
 
@@ -61,51 +69,163 @@ func formatMetricsServerError(err error) string {
 type GetNodeMetricsParams struct {
 	// NodeName specifies a specific node to get metrics for.
 	// If empty, retrieves metrics for all nodes in the cluster.
-	NodeName string `json:"node_name,omitempty"`
+	NodeName string `json:"node_name,omitempty" description:"Specific node name to get metrics for (optional - if not provided, returns metrics for all nodes)"`
 
 	// Context specifies which Kubernetes context to use for this operation.
 	// If empty, uses the current context from kubeconfig.
-	Context string `json:"context,omitempty"`
+	Context string `json:"context,omitempty" description:"Kubernetes context to use (defaults to current context from kubeconfig)"`
 
 	// Limit restricts the maximum number of node metrics returned.
 	// If 0, returns all matching metrics.
-	Limit int `json:"limit,omitempty"`
+	Limit int `json:"limit,omitempty" minimum:"0" description:"Maximum number of node metrics to return (optional - defaults to all)"`
 
 	// Continue is a pagination token from a previous response.
 	// Used to retrieve the next page of results.
-	Continue string `json:"continue,omitempty"`
+	Continue string `json:"continue,omitempty" description:"Continue token for pagination (optional - from previous response)"`
 
 	// TitleOnly when true, returns only node names.
 	// When false (default), returns complete node metrics information.
-	TitleOnly *bool `json:"title_only,omitempty"`
+	TitleOnly *bool `json:"title_only,omitempty" default:"false" description:"When true, returns only node names. When false (default), returns complete node metrics"`
+
+	// Samples polls the metrics-server this many times within the call and
+	// returns a series per node. 0 or 1 returns a single reading.
+	Samples int `json:"samples,omitempty" minimum:"1" maximum:"10" description:"Poll the metrics-server this many times within the call and return a per-node series with min/max/avg and first-to-last deltas, for crude trend visibility (optional - defaults to a single reading). Cannot be combined with title_only or pagination"`
+
+	// IntervalSeconds is the delay between polls when Samples is greater than 1.
+	IntervalSeconds int `json:"interval_seconds,omitempty" minimum:"1" maximum:"60" default:"15" description:"Seconds between polls when samples is greater than 1 (defaults to 15, the metrics-server's default scrape resolution). The whole sampling window may not exceed 30 seconds, and fewer samples are taken when the call's timeout leaves less time, 5 seconds before it ends"`
 }
 
 // GetPodMetricsParams defines the parameters for the get_pod_metrics MCP tool.
 // It supports namespace-scoped, cluster-wide, and targeted pod metrics with pagination.
 type GetPodMetricsParams struct {
-	// Namespace specifies the target namespace for pod metrics.
-	// If empty, retrieves metrics for pods across all namespaces.
-	Namespace string `json:"namespace,omitempty"`
+	// Namespace specifies the target namespace for pod metrics, or several
+	// namespaces separated by commas. If empty, retrieves metrics for pods
+	// across all namespaces.
+	Namespace string `json:"namespace,omitempty" description:"Namespace to get pod metrics from, or several comma-separated namespaces (optional - if not provided, returns metrics for all pods)"`
+
+	// AllNamespaces explicitly retrieves metrics for pods across all
+	// namespaces.
+	AllNamespaces bool `json:"all_namespaces,omitempty" description:"When true, returns metrics for pods across every namespace. Cannot be combined with namespace"`
 
 	// PodName specifies a specific pod to get metrics for.
 	// If provided, Namespace must also be specified.
-	PodName string `json:"pod_name,omitempty"`
+	PodName string `json:"pod_name,omitempty" description:"Specific pod name to get metrics for (optional - if not provided, returns metrics for all pods in namespace or cluster)"`
 
 	// Context specifies which Kubernetes context to use for this operation.
 	// If empty, uses the current context from kubeconfig.
-	Context string `json:"context,omitempty"`
+	Context string `json:"context,omitempty" description:"Kubernetes context to use (defaults to current context from kubeconfig)"`
 
 	// Limit restricts the maximum number of pod metrics returned.
 	// If 0, returns all matching metrics.
-	Limit int `json:"limit,omitempty"`
+	Limit int `json:"limit,omitempty" minimum:"0" description:"Maximum number of pod metrics to return (optional - defaults to all)"`
 
 	// Continue is a pagination token from a previous response.
 	// Used to retrieve the next page of results.
-	Continue string `json:"continue,omitempty"`
+	Continue string `json:"continue,omitempty" description:"Continue token for pagination (optional - from previous response)"`
 
 	// TitleOnly when true, returns only pod names.
 	// When false (default), returns complete pod metrics information.
-	TitleOnly *bool `json:"title_only,omitempty"`
+	TitleOnly *bool `json:"title_only,omitempty" default:"false" description:"When true, returns only pod names with namespaces. When false (default), returns complete pod metrics"`
+
+	// ByContainer when true, flattens the results into one row per container
+	// with pod, namespace, and container keys instead of pod-level objects.
+	ByContainer bool `json:"by_container,omitempty" description:"When true, flattens results into one row per container (namespace, pod, container, cpu, memory), sorted by namespace, pod, and container. Useful when sidecars dominate usage. Cannot be combined with title_only"`
+
+	// Workload aggregates usage across the pods of a single workload, written
+	// as Kind/name. Requires Namespace.
+	Workload string `json:"workload,omitempty" description:"Aggregate usage across the replicas of a workload as Kind/name (e.g., 'Deployment/web', 'StatefulSet/db'). Returns min/max/avg/total CPU and memory across its pods instead of a pod list. Requires namespace; cannot be combined with pod_name, title_only, by_container, or pagination"`
+}
+
+// WorkloadUsageStats summarizes a resource's usage across a workload's pods.
+type WorkloadUsageStats struct {
+	Min   int64 `json:"min"`
+	Max   int64 `json:"max"`
+	Avg   int64 `json:"avg"`
+	Total int64 `json:"total"`
+}
+
+// WorkloadPodUsage is the usage of a single pod of a workload, summed across
+// its containers.
+type WorkloadPodUsage struct {
+	Name          string `json:"name"`
+	CPUMillicores int64  `json:"cpu_millicores"`
+	MemoryBytes   int64  `json:"memory_bytes"`
+}
+
+// ContainerMetricsRow is a flattened per-container usage entry returned by
+// get_pod_metrics when by_container=true.
+type ContainerMetricsRow struct {
+	// Namespace is the namespace of the pod running the container.
+	Namespace string `json:"namespace"`
+
+	// Pod is the name of the pod running the container.
+	Pod string `json:"pod"`
+
+	// Container is the container name.
+	Container string `json:"container"`
+
+	// CPU is the container's CPU usage (e.g., "8020419n").
+	CPU string `json:"cpu"`
+
+	// Memory is the container's memory usage (e.g., "48164Ki").
+	Memory string `json:"memory"`
+
+	// Timestamp is when the metrics-server collected the sample.
+	Timestamp string `json:"timestamp"`
+}
+
+// metricsAPIVersion is the API version of the metrics-server resources.
+const metricsAPIVersion = "metrics.k8s.io/v1beta1"
+
+// MetricsList is a list of node or pod metrics returned by get_node_metrics
+// and get_pod_metrics, or of their samples. It is also their output schema,
+// where every field is optional: a single node or pod is returned as its
+// metrics object, or as a MetricsTitle with title_only, and a workload as
+// WorkloadMetrics.
+type MetricsList struct {
+	Kind       string `json:"kind,omitempty"`
+	APIVersion string `json:"apiVersion,omitempty"`
+	Namespace  string `json:"namespace,omitempty"`
+
+	// Pod is the pod whose containers are listed with by_container.
+	Pod string `json:"pod,omitempty"`
+
+	// Samples and IntervalSeconds describe the samples taken of each node.
+	Samples         int `json:"samples,omitempty"`
+	IntervalSeconds int `json:"interval_seconds,omitempty"`
+
+	Count    int    `json:"count,omitempty"`
+	Items    []any  `json:"items,omitzero"`
+	Continue string `json:"continue,omitempty"`
+}
+
+// MetricsTitle names a single node or pod listed with title_only.
+type MetricsTitle struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// WorkloadMetrics is the usage of the pods of a workload, aggregated by
+// get_pod_metrics.
+type WorkloadMetrics struct {
+	Namespace       string             `json:"namespace"`
+	Workload        string             `json:"workload"`
+	PodCount        int                `json:"pod_count"`
+	PodsWithMetrics int                `json:"pods_with_metrics"`
+	CPUMillicores   WorkloadUsageStats `json:"cpu_millicores"`
+	MemoryBytes     WorkloadUsageStats `json:"memory_bytes"`
+	Pods            []WorkloadPodUsage `json:"pods"`
+
+	PodsWithoutMetrics []string `json:"pods_without_metrics,omitempty"`
+}
+
+// anyItems converts items for MetricsList.Items.
+func anyItems[T any](items []T) []any {
+	converted := make([]any, len(items))
+	for i := range items {
+		converted[i] = items[i]
+	}
+	return converted
 }
 
 // GetNodeMetrics implements the get_node_metrics MCP tool.
@@ -114,12 +234,12 @@ type GetPodMetricsParams struct {
 // for consistent ordering. Results are sorted by timestamp (newest first).
 func (h *MetricsHandler) GetNodeMetrics(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	var params GetNodeMetricsParams
-	if err := request.BindArguments(&params); err != nil {
+	if err := bindParams(request, &params); err != nil {
 		return response.Errorf("failed to parse arguments: %s", err)
 	}
 
 	// Use the appropriate client based on context
-	client, err := h.client.ForContext(params.Context)
+	client, err := h.client.ForContext(ctx, params.Context)
 	if err != nil {
 		if h.alwaysStart && connectivity.IsTransportError(err) {
 			return response.Error(connectivity.ErrorMessage(err))
@@ -133,6 +253,13 @@ func (h *MetricsHandler) GetNodeMetrics(ctx context.Context, request mcp.CallToo
 		titleOnly = *params.TitleOnly
 	}
 
+	if params.Samples > 1 {
+		if titleOnly || params.Limit > 0 || params.Continue != "" {
+			return response.Error("samples cannot be combined with title_only or pagination")
+		}
+		return h.sampleNodeMetrics(ctx, client, params)
+	}
+
 	if params.NodeName != "" {
 		// Get specific node metrics
 		nodeMetrics, err := client.GetNodeMetricsByName(ctx, params.NodeName)
@@ -147,10 +274,7 @@ func (h *MetricsHandler) GetNodeMetrics(ctx context.Context, request mcp.CallToo
 		}
 
 		if titleOnly {
-			result := map[string]interface{}{
-				"name": nodeMetrics.Name,
-			}
-			return response.JSON(result)
+			return response.JSON(MetricsTitle{Name: nodeMetrics.Name})
 		}
 		return response.JSON(nodeMetrics)
 	}
@@ -179,7 +303,8 @@ func (h *MetricsHandler) GetNodeMetrics(ctx context.Context, request mcp.CallToo
 
 		// Handle pagination for names only
 		if params.Limit > 0 {
-			paginationState, err := parseContinueToken(params.Continue)
+			listing := PaginationState{Type: "node", Sort: sortByName, TitleOnly: true}
+			offset, err := resumeOffset(params.Continue, listing)
 			if err != nil {
 				return response.Errorf("invalid continue token: %v", err)
 			}
@@ -190,28 +315,27 @@ func (h *MetricsHandler) GetNodeMetrics(ctx context.Context, request mcp.CallToo
 				allItems[i] = name
 			}
 
-			paginatedItems, hasMore := paginateItems(allItems, params.Limit, paginationState.Offset)
+			paginatedItems, hasMore := paginateItems(allItems, params.Limit, offset)
 
-			result := map[string]interface{}{
-				"kind":       "NodeMetricsList",
-				"apiVersion": "metrics.k8s.io/v1beta1",
-				"count":      len(paginatedItems),
-				"items":      paginatedItems,
+			result := MetricsList{
+				Kind:       "NodeMetricsList",
+				APIVersion: metricsAPIVersion,
+				Count:      len(paginatedItems),
+				Items:      paginatedItems,
 			}
 
 			if hasMore {
-				nextOffset := paginationState.Offset + params.Limit
-				result["continue"] = generateContinueToken(nextOffset, "node", "")
+				result.Continue = generateContinueToken(offset+params.Limit, listing)
 			}
 
 			return response.JSON(result)
 		}
 
-		result := map[string]interface{}{
-			"kind":       "NodeMetricsList",
-			"apiVersion": "metrics.k8s.io/v1beta1",
-			"count":      len(nodeNames),
-			"items":      nodeNames,
+		result := MetricsList{
+			Kind:       "NodeMetricsList",
+			APIVersion: metricsAPIVersion,
+			Count:      len(nodeNames),
+			Items:      anyItems(nodeNames),
 		}
 
 		return response.JSON(result)
@@ -233,36 +357,36 @@ func (h *MetricsHandler) GetNodeMetrics(ctx context.Context, request mcp.CallToo
 	// Handle client-side pagination
 	if params.Limit > 0 {
 		// Parse continue token to get offset
-		paginationState, err := parseContinueToken(params.Continue)
+		listing := PaginationState{Type: "node", Sort: sortByTimestamp}
+		offset, err := resumeOffset(params.Continue, listing)
 		if err != nil {
 			return response.Errorf("invalid continue token: %v", err)
 		}
 
 		// Apply client-side pagination
-		paginatedItems, hasMore := paginateItems(allItems, params.Limit, paginationState.Offset)
+		paginatedItems, hasMore := paginateItems(allItems, params.Limit, offset)
 
-		result := map[string]interface{}{
-			"kind":       "NodeMetricsList",
-			"apiVersion": "metrics.k8s.io/v1beta1",
-			"count":      len(paginatedItems),
-			"items":      paginatedItems,
+		result := MetricsList{
+			Kind:       "NodeMetricsList",
+			APIVersion: metricsAPIVersion,
+			Count:      len(paginatedItems),
+			Items:      paginatedItems,
 		}
 
 		// Add continue token if there are more results
 		if hasMore {
-			nextOffset := paginationState.Offset + params.Limit
-			result["continue"] = generateContinueToken(nextOffset, "node", "")
+			result.Continue = generateContinueToken(offset+params.Limit, listing)
 		}
 
 		return response.JSON(result)
 	}
 
 	// Return all items if no pagination requested
-	result := map[string]interface{}{
-		"kind":       "NodeMetricsList",
-		"apiVersion": "metrics.k8s.io/v1beta1",
-		"count":      len(allItems),
-		"items":      allItems,
+	result := MetricsList{
+		Kind:       "NodeMetricsList",
+		APIVersion: metricsAPIVersion,
+		Count:      len(allItems),
+		Items:      allItems,
 	}
 
 	return response.JSON(result)
@@ -274,12 +398,12 @@ func (h *MetricsHandler) GetNodeMetrics(ctx context.Context, request mcp.CallToo
 // pagination for consistent ordering. Results are sorted by timestamp (newest first).
 func (h *MetricsHandler) GetPodMetrics(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	var params GetPodMetricsParams
-	if err := request.BindArguments(&params); err != nil {
+	if err := bindParams(request, &params); err != nil {
 		return response.Errorf("failed to parse arguments: %s", err)
 	}
 
 	// Use the appropriate client based on context
-	client, err := h.client.ForContext(params.Context)
+	client, err := h.client.ForContext(ctx, params.Context)
 	if err != nil {
 		if h.alwaysStart && connectivity.IsTransportError(err) {
 			return response.Error(connectivity.ErrorMessage(err))
@@ -293,6 +417,33 @@ func (h *MetricsHandler) GetPodMetrics(ctx context.Context, request mcp.CallTool
 		titleOnly = *params.TitleOnly
 	}
 
+	if titleOnly && params.ByContainer {
+		return response.Error("title_only and by_container cannot be used together")
+	}
+
+	namespaces := splitNamespaces(params.Namespace)
+	switch {
+	case params.AllNamespaces && len(namespaces) > 0:
+		return response.Error(errNamespaceScope.Error())
+	case len(namespaces) > 1 && (params.PodName != "" || params.Workload != ""):
+		return response.Error("pod_name and workload require a single namespace")
+	case len(namespaces) == 1:
+		params.Namespace = namespaces[0]
+	}
+
+	if params.Workload != "" {
+		switch {
+		case params.Namespace == "":
+			return response.Error("namespace is required when specifying workload")
+		case params.PodName != "":
+			return response.Error("workload and pod_name cannot be used together")
+		case titleOnly || params.ByContainer || params.Limit > 0 || params.Continue != "":
+			return response.Error("workload cannot be combined with title_only, by_container, or pagination")
+		}
+
+		return h.workloadPodMetrics(ctx, client, params)
+	}
+
 	if params.PodName != "" {
 		// Get specific pod metrics
 		if params.Namespace == "" {
@@ -311,11 +462,17 @@ func (h *MetricsHandler) GetPodMetrics(ctx context.Context, request mcp.CallTool
 		}
 
 		if titleOnly {
-			result := map[string]interface{}{
-				"name":      podMetrics.Name,
-				"namespace": podMetrics.Namespace,
-			}
-			return response.JSON(result)
+			return response.JSON(MetricsTitle{Name: podMetrics.Name, Namespace: podMetrics.Namespace})
+		}
+
+		if params.ByContainer {
+			rows := flattenContainerMetrics([]metricsv1beta1.PodMetrics{*podMetrics})
+			return response.JSON(MetricsList{
+				Namespace: params.Namespace,
+				Pod:       params.PodName,
+				Count:     len(rows),
+				Items:     anyItems(rows),
+			})
 		}
 		return response.JSON(podMetrics)
 	}
@@ -323,10 +480,14 @@ func (h *MetricsHandler) GetPodMetrics(ctx context.Context, request mcp.CallTool
 	// Always fetch all pod metrics from the server
 	var podMetricsList *metricsv1beta1.PodMetricsList
 
-	if params.Namespace != "" {
+	switch {
+	case len(namespaces) > 1:
+		// Get pod metrics for each namespace and merge them
+		podMetricsList, err = podMetricsAcrossNamespaces(ctx, client, namespaces)
+	case params.Namespace != "":
 		// Get pod metrics for specific namespace
 		podMetricsList, err = client.GetPodMetricsByNamespace(ctx, params.Namespace)
-	} else {
+	default:
 		// Get pod metrics for all namespaces
 		podMetricsList, err = client.GetPodMetrics(ctx)
 	}
@@ -341,6 +502,42 @@ func (h *MetricsHandler) GetPodMetrics(ctx context.Context, request mcp.CallTool
 		return response.Errorf("failed to get pod metrics: %v", err)
 	}
 
+	if params.ByContainer {
+		rows := flattenContainerMetrics(podMetricsList.Items)
+
+		allItems := make([]interface{}, len(rows))
+		for i := range rows {
+			allItems[i] = rows[i]
+		}
+
+		result := MetricsList{Namespace: params.Namespace}
+
+		if params.Limit > 0 {
+			// Tokens for another listing are rejected; a namespace change restarts
+			// from the first page
+			listing := PaginationState{Type: "pod_container", Namespace: params.Namespace, Sort: sortByContainer}
+			offset, err := resumeOffset(params.Continue, listing)
+			if err != nil {
+				return response.Errorf("invalid continue token: %v", err)
+			}
+
+			paginatedItems, hasMore := paginateItems(allItems, params.Limit, offset)
+			result.Count = len(paginatedItems)
+			result.Items = paginatedItems
+
+			if hasMore {
+				result.Continue = generateContinueToken(offset+params.Limit, listing)
+			}
+
+			return response.JSON(result)
+		}
+
+		result.Count = len(allItems)
+		result.Items = allItems
+
+		return response.JSON(result)
+	}
+
 	if titleOnly {
 		// Return only pod names with namespaces
 		type PodName struct {
@@ -365,51 +562,43 @@ func (h *MetricsHandler) GetPodMetrics(ctx context.Context, request mcp.CallTool
 
 		// Handle pagination for names only
 		if params.Limit > 0 {
-			paginationState, err := parseContinueToken(params.Continue)
+			// Tokens for another listing are rejected; a namespace change restarts
+			// from the first page
+			listing := PaginationState{Type: "pod", Namespace: params.Namespace, Sort: sortByNamespaceName, TitleOnly: true}
+			offset, err := resumeOffset(params.Continue, listing)
 			if err != nil {
 				return response.Errorf("invalid continue token: %v", err)
 			}
 
-			// Validate that the continue token is for the same request type
-			if paginationState.Type != "" && paginationState.Type != "pod" {
-				return response.Error("continue token is not valid for pod metrics")
-			}
-
-			// Reset pagination if namespace context has changed
-			if paginationState.Namespace != params.Namespace {
-				paginationState.Offset = 0
-			}
-
 			// Convert to interface slice for pagination
 			allItems := make([]interface{}, len(podNames))
 			for i, podName := range podNames {
 				allItems[i] = podName
 			}
 
-			paginatedItems, hasMore := paginateItems(allItems, params.Limit, paginationState.Offset)
+			paginatedItems, hasMore := paginateItems(allItems, params.Limit, offset)
 
-			result := map[string]interface{}{
-				"kind":       "PodMetricsList",
-				"apiVersion": "metrics.k8s.io/v1beta1",
-				"namespace":  params.Namespace,
-				"count":      len(paginatedItems),
-				"items":      paginatedItems,
+			result := MetricsList{
+				Kind:       "PodMetricsList",
+				APIVersion: metricsAPIVersion,
+				Namespace:  params.Namespace,
+				Count:      len(paginatedItems),
+				Items:      paginatedItems,
 			}
 
 			if hasMore {
-				nextOffset := paginationState.Offset + params.Limit
-				result["continue"] = generateContinueToken(nextOffset, "pod", params.Namespace)
+				result.Continue = generateContinueToken(offset+params.Limit, listing)
 			}
 
 			return response.JSON(result)
 		}
 
-		result := map[string]interface{}{
-			"kind":       "PodMetricsList",
-			"apiVersion": "metrics.k8s.io/v1beta1",
-			"namespace":  params.Namespace,
-			"count":      len(podNames),
-			"items":      podNames,
+		result := MetricsList{
+			Kind:       "PodMetricsList",
+			APIVersion: metricsAPIVersion,
+			Namespace:  params.Namespace,
+			Count:      len(podNames),
+			Items:      anyItems(podNames),
 		}
 
 		return response.JSON(result)
@@ -430,71 +619,130 @@ func (h *MetricsHandler) GetPodMetrics(ctx context.Context, request mcp.CallTool
 
 	// Handle client-side pagination
 	if params.Limit > 0 {
-		// Parse continue token to get offset
-		paginationState, err := parseContinueToken(params.Continue)
+		// Tokens for another listing are rejected; a namespace change restarts
+		// from the first page
+		listing := PaginationState{Type: "pod", Namespace: params.Namespace, Sort: sortByTimestamp}
+		offset, err := resumeOffset(params.Continue, listing)
 		if err != nil {
 			return response.Errorf("invalid continue token: %v", err)
 		}
 
-		// Validate that the continue token is for the same request type
-		if paginationState.Type != "" && paginationState.Type != "pod" {
-			return response.Error("continue token is not valid for pod metrics")
-		}
-
-		// Reset pagination if namespace context has changed
-		if paginationState.Namespace != params.Namespace {
-			paginationState.Offset = 0
-		}
-
 		// Apply client-side pagination
-		paginatedItems, hasMore := paginateItems(allItems, params.Limit, paginationState.Offset)
-
-		result := map[string]interface{}{
-			"kind":       "PodMetricsList",
-			"apiVersion": "metrics.k8s.io/v1beta1",
-			"namespace":  params.Namespace,
-			"count":      len(paginatedItems),
-			"items":      paginatedItems,
+		paginatedItems, hasMore := paginateItems(allItems, params.Limit, offset)
+
+		result := MetricsList{
+			Kind:       "PodMetricsList",
+			APIVersion: metricsAPIVersion,
+			Namespace:  params.Namespace,
+			Count:      len(paginatedItems),
+			Items:      paginatedItems,
 		}
 
 		// Add continue token if there are more results
 		if hasMore {
-			nextOffset := paginationState.Offset + params.Limit
-			result["continue"] = generateContinueToken(nextOffset, "pod", params.Namespace)
+			result.Continue = generateContinueToken(offset+params.Limit, listing)
 		}
 
 		return response.JSON(result)
 	}
 
 	// Return all items if no pagination requested
-	result := map[string]interface{}{
-		"kind":       "PodMetricsList",
-		"apiVersion": "metrics.k8s.io/v1beta1",
-		"namespace":  params.Namespace,
-		"count":      len(allItems),
-		"items":      allItems,
+	result := MetricsList{
+		Kind:       "PodMetricsList",
+		APIVersion: metricsAPIVersion,
+		Namespace:  params.Namespace,
+		Count:      len(allItems),
+		Items:      allItems,
 	}
 
 	return response.JSON(result)
 }
 
-// PaginationState represents the state for client-side pagination
+// podMetricsAcrossNamespaces retrieves the pod metrics of several namespaces
+// concurrently and merges them into one list.
+func podMetricsAcrossNamespaces(ctx context.Context, client kubernetes.ClusterReader, namespaces []string) (*metricsv1beta1.PodMetricsList, error) {
+	lists, err := fetchPerNamespace(ctx, namespaces, client.GetPodMetricsByNamespace)
+	if err != nil {
+		return nil, err
+	}
+
+	merged := &metricsv1beta1.PodMetricsList{}
+	for _, list := range lists {
+		merged.Items = append(merged.Items, list.Items...)
+	}
+	return merged, nil
+}
+
+// flattenContainerMetrics converts pod-level metrics into one row per container,
+// sorted by namespace, pod, and container name for stable pagination.
+func flattenContainerMetrics(pods []metricsv1beta1.PodMetrics) []ContainerMetricsRow {
+	var rows []ContainerMetricsRow
+
+	for i := range pods {
+		pod := &pods[i]
+		for j := range pod.Containers {
+			container := &pod.Containers[j]
+			rows = append(rows, ContainerMetricsRow{
+				Namespace: pod.Namespace,
+				Pod:       pod.Name,
+				Container: container.Name,
+				CPU:       container.Usage.Cpu().String(),
+				Memory:    container.Usage.Memory().String(),
+				Timestamp: formatTime(pod.Timestamp.Time),
+			})
+		}
+	}
+
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].Namespace != rows[j].Namespace {
+			return rows[i].Namespace < rows[j].Namespace
+		}
+		if rows[i].Pod != rows[j].Pod {
+			return rows[i].Pod < rows[j].Pod
+		}
+		return rows[i].Container < rows[j].Container
+	})
+
+	return rows
+}
+
+// Sort orders recorded in continue tokens. Each listing is paginated over a
+// fixed order, so a token is only meaningful for the order it was issued for.
+const (
+	sortByTimestamp          = "timestamp"
+	sortByTimestampAscending = "timestamp ascending"
+	sortByName               = "name"
+	sortByNamespaceName      = "namespace,name"
+	sortByContainer          = "namespace,pod,container"
+)
+
+// PaginationState represents the state for client-side pagination. Besides the
+// offset, it records the listing the token was issued for, so a token replayed
+// against a different listing, or one sorted or filtered differently, is
+// rejected instead of returning an unrelated slice.
 type PaginationState struct {
-	Offset    int    `json:"offset"`
-	Type      string `json:"type"` // "node" or "pod"
-	Namespace string `json:"namespace,omitempty"`
+	Offset        int    `json:"offset"`
+	Type          string `json:"type"` // "node", "pod", "pod_container", or "resource"
+	Namespace     string `json:"namespace,omitempty"`
+	Resource      string `json:"resource,omitempty"`
+	LabelSelector string `json:"label_selector,omitempty"`
+	FieldSelector string `json:"field_selector,omitempty"`
+	Sort          string `json:"sort,omitempty"`
+	TitleOnly     bool   `json:"title_only,omitempty"`
+
+	// After is the last item of the previous page, for listings that resume
+	// right after it instead of at Offset, so objects created or deleted
+	// between pages do not shift the items across pages.
+	After *SortKey `json:"after,omitempty"`
 }
 
 // generateContinueToken creates a continue token for client-side pagination
-func generateContinueToken(offset int, itemType, namespace string) string {
-	state := PaginationState{
-		Offset:    offset,
-		Type:      itemType,
-		Namespace: namespace,
-	}
+// that resumes listing at offset.
+func generateContinueToken(offset int, listing PaginationState) string {
+	listing.Offset = offset
 
 	//nolint:errchkjson // we control the struct and it's strongly typed
-	data, _ := json.Marshal(state)
+	data, _ := json.Marshal(listing)
 	return base64.URLEncoding.EncodeToString(data)
 }
 
@@ -517,6 +765,88 @@ func parseContinueToken(token string) (*PaginationState, error) {
 	return &state, nil
 }
 
+// resumeOffset returns the offset a continue token resumes listing at. Tokens
+// issued for another item type, namespace, resource type, selector, sort
+// order, or title_only mode are rejected, since their offset points into a
+// different listing.
+func resumeOffset(token string, listing PaginationState) (int, error) {
+	state, err := resumeState(token, listing)
+	if err != nil {
+		return 0, err
+	}
+	return state.Offset, nil
+}
+
+// resumeState returns the pagination state a continue token resumes listing
+// from, rejecting tokens issued for another listing like resumeOffset. An
+// empty token resumes from the first page.
+func resumeState(token string, listing PaginationState) (*PaginationState, error) {
+	state, err := parseContinueToken(token)
+	if err != nil {
+		return nil, err
+	}
+
+	if token == "" {
+		return state, nil
+	}
+
+	mismatch := func(issued, requested string) error {
+		return fmt.Errorf("continue token does not match this request: it was issued for %s, not %s; repeat the request without continue to start from the first page", issued, requested)
+	}
+
+	switch {
+	case state.Type != listing.Type:
+		return nil, mismatch(describeItemType(state.Type), describeItemType(listing.Type))
+	case state.Namespace != listing.Namespace:
+		return nil, mismatch(describeNamespace(state.Namespace), describeNamespace(listing.Namespace))
+	case state.Resource != listing.Resource:
+		return nil, mismatch(fmt.Sprintf("resource type %q", state.Resource), fmt.Sprintf("%q", listing.Resource))
+	case state.LabelSelector != listing.LabelSelector:
+		return nil, mismatch(fmt.Sprintf("label selector %q", state.LabelSelector), fmt.Sprintf("%q", listing.LabelSelector))
+	case state.FieldSelector != listing.FieldSelector:
+		return nil, mismatch(fmt.Sprintf("field selector %q", state.FieldSelector), fmt.Sprintf("%q", listing.FieldSelector))
+	case state.Sort != listing.Sort || state.TitleOnly != listing.TitleOnly:
+		return nil, mismatch(
+			fmt.Sprintf("a listing with title_only=%t sorted by %s", state.TitleOnly, describeSort(state.Sort)),
+			fmt.Sprintf("title_only=%t sorted by %s", listing.TitleOnly, describeSort(listing.Sort)),
+		)
+	}
+
+	return state, nil
+}
+
+// describeNamespace names the namespace a continue token was issued for in
+// error messages.
+func describeNamespace(namespace string) string {
+	if namespace == "" {
+		return "all namespaces"
+	}
+	return fmt.Sprintf("namespace %q", namespace)
+}
+
+// describeItemType names a continue token's item type for error messages.
+func describeItemType(itemType string) string {
+	switch itemType {
+	case "pod_container":
+		return "per-container pod metrics"
+	case "resource":
+		return "a sorted resource listing"
+	case "":
+		return "unknown items"
+	default:
+		return itemType + " metrics"
+	}
+}
+
+// describeSort names a continue token's sort order for error messages.
+// Tokens from before sort orders were recorded have none.
+func describeSort(sort string) string {
+	if sort == "" {
+		return "an unknown order"
+	}
+	return sort
+}
+
 // paginateItems applies client-side pagination to a slice of items
 func paginateItems(items []interface{}, limit, offset int) ([]interface{}, bool) {
 	if offset >= len(items) {
@@ -540,48 +870,130 @@ func (h *MetricsHandler) GetTools() []MCPTool {
 	return []MCPTool{
 		NewMCPTool(
 			mcp.NewTool("get_node_metrics",
-				mcp.WithDescription("Get node metrics (CPU and memory usage). Returns complete metrics by default (title_only=false), or only node names when title_only=true"),
-				mcp.WithString("node_name",
-					mcp.Description("Specific node name to get metrics for (optional - if not provided, returns metrics for all nodes)"),
-				),
-				mcp.WithString("context",
-					mcp.Description("Kubernetes context to use (defaults to current context from kubeconfig)"),
-				),
-				mcp.WithNumber("limit",
-					mcp.Description("Maximum number of node metrics to return (optional - defaults to all)"),
-				),
-				mcp.WithString("continue",
-					mcp.Description("Continue token for pagination (optional - from previous response)"),
-				),
-				mcp.WithBoolean("title_only",
-					mcp.Description("When true, returns only node names. When false (default), returns complete node metrics"),
-				),
+				mcp.WithDescription("Get node metrics (CPU and memory usage). Returns complete metrics by default (title_only=false), only node names when title_only=true, or a short per-node usage series with deltas when samples is greater than 1"),
+				toolschema.Input[GetNodeMetricsParams](),
+				toolschema.Output[MetricsList](),
 			),
 			h.GetNodeMetrics,
 		),
 		NewMCPTool(
 			mcp.NewTool("get_pod_metrics",
-				mcp.WithDescription("Get pod metrics (CPU and memory usage). Returns complete metrics by default (title_only=false), or only pod names with namespaces when title_only=true"),
-				mcp.WithString("namespace",
-					mcp.Description("Namespace to get pod metrics from (optional - if not provided, returns metrics for all pods)"),
-				),
-				mcp.WithString("pod_name",
-					mcp.Description("Specific pod name to get metrics for (optional - if not provided, returns metrics for all pods in namespace or cluster)"),
-				),
-				mcp.WithString("context",
-					mcp.Description("Kubernetes context to use (defaults to current context from kubeconfig)"),
-				),
-				mcp.WithNumber("limit",
-					mcp.Description("Maximum number of pod metrics to return (optional - defaults to all)"),
-				),
-				mcp.WithString("continue",
-					mcp.Description("Continue token for pagination (optional - from previous response)"),
-				),
-				mcp.WithBoolean("title_only",
-					mcp.Description("When true, returns only pod names with namespaces. When false (default), returns complete pod metrics"),
-				),
+				mcp.WithDescription("Get pod metrics (CPU and memory usage). Returns complete metrics by default (title_only=false), only pod names with namespaces when title_only=true, one row per container when by_container=true, or min/max/avg/total usage across a Deployment's or StatefulSet's replicas when workload is set"),
+				toolschema.Input[GetPodMetricsParams](),
+				toolschema.Output[MetricsList](),
 			),
 			h.GetPodMetrics,
 		),
 	}
 }
+
+// workloadPodMetrics aggregates pod metrics across the pods of the workload
+// named in params, matched the same way topology_report attributes pods to
+// workloads: through the pod's controller, with ReplicaSet pods attributed to
+// their Deployment.
+func (h *MetricsHandler) workloadPodMetrics(ctx context.Context, client kubernetes.ClusterReader, params GetPodMetricsParams) (*mcp.CallToolResult, error) {
+	pods, err := client.ListPods(ctx, params.Namespace, metav1.ListOptions{})
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to list pods: %v", err)
+	}
+
+	workload := ""
+	members := make(map[string]bool)
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if owner := podWorkload(pod); strings.EqualFold(owner, params.Workload) {
+			workload = owner
+			members[pod.Name] = true
+		}
+	}
+
+	if len(members) == 0 {
+		return response.Errorf("no pods found for workload %q in namespace %q; use Kind/name as reported by topology_report (e.g., 'Deployment/web')", params.Workload, params.Namespace)
+	}
+
+	podMetricsList, err := client.GetPodMetricsByNamespace(ctx, params.Namespace)
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		if isMetricsServerError(err) {
+			return response.Errorf("%s", formatMetricsServerError(err))
+		}
+		return response.Errorf("failed to get pod metrics: %v", err)
+	}
+
+	usages := make([]WorkloadPodUsage, 0, len(members))
+	for i := range podMetricsList.Items {
+		podMetrics := &podMetricsList.Items[i]
+		if !members[podMetrics.Name] {
+			continue
+		}
+
+		usage := WorkloadPodUsage{Name: podMetrics.Name}
+		for j := range podMetrics.Containers {
+			usage.CPUMillicores += podMetrics.Containers[j].Usage.Cpu().MilliValue()
+			usage.MemoryBytes += podMetrics.Containers[j].Usage.Memory().Value()
+		}
+		usages = append(usages, usage)
+	}
+
+	sort.Slice(usages, func(i, j int) bool {
+		return usages[i].Name < usages[j].Name
+	})
+
+	missing := make([]string, 0)
+	reported := make(map[string]bool, len(usages))
+	for _, usage := range usages {
+		reported[usage.Name] = true
+	}
+	for name := range members {
+		if !reported[name] {
+			missing = append(missing, name)
+		}
+	}
+	sort.Strings(missing)
+
+	cpu := make([]int64, len(usages))
+	memory := make([]int64, len(usages))
+	for i, usage := range usages {
+		cpu[i] = usage.CPUMillicores
+		memory[i] = usage.MemoryBytes
+	}
+
+	// Pods that just started or are not running have no metrics yet.
+	if len(missing) == 0 {
+		missing = nil
+	}
+
+	return response.JSON(WorkloadMetrics{
+		Namespace:          params.Namespace,
+		Workload:           workload,
+		PodCount:           len(members),
+		PodsWithMetrics:    len(usages),
+		CPUMillicores:      workloadUsageStats(cpu),
+		MemoryBytes:        workloadUsageStats(memory),
+		Pods:               usages,
+		PodsWithoutMetrics: missing,
+	})
+}
+
+// workloadUsageStats computes min/max/avg/total over values, returning zeros
+// when values is empty.
+func workloadUsageStats(values []int64) WorkloadUsageStats {
+	if len(values) == 0 {
+		return WorkloadUsageStats{}
+	}
+
+	stats := WorkloadUsageStats{Min: values[0], Max: values[0]}
+	for _, v := range values {
+		stats.Total += v
+		stats.Min = min(stats.Min, v)
+		stats.Max = max(stats.Max, v)
+	}
+	stats.Avg = stats.Total / int64(len(values))
+
+	return stats
+}
@@ -0,0 +1,344 @@
+package handlers
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/fakecluster"
+)
+
+func TestFlattenContainerMetrics(t *testing.T) {
+	t.Parallel()
+
+	timestamp := metav1.NewTime(time.Date(2026, 3, 11, 12, 0, 0, 0, time.UTC))
+	usage := func(cpu, memory string) corev1.ResourceList {
+		return corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse(cpu),
+			corev1.ResourceMemory: resource.MustParse(memory),
+		}
+	}
+
+	pods := []metricsv1beta1.PodMetrics{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "prod"},
+			Timestamp:  timestamp,
+			Containers: []metricsv1beta1.ContainerMetrics{
+				{Name: "istio-proxy", Usage: usage("250m", "128Mi")},
+				{Name: "app", Usage: usage("10m", "64Mi")},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "dev"},
+			Timestamp:  timestamp,
+			Containers: []metricsv1beta1.ContainerMetrics{
+				{Name: "app", Usage: usage("5m", "32Mi")},
+			},
+		},
+	}
+
+	got := flattenContainerMetrics(pods)
+	want := []ContainerMetricsRow{
+		{Namespace: "dev", Pod: "api", Container: "app", CPU: "5m", Memory: "32Mi", Timestamp: "2026-03-11T12:00:00Z"},
+		{Namespace: "prod", Pod: "web", Container: "app", CPU: "10m", Memory: "64Mi", Timestamp: "2026-03-11T12:00:00Z"},
+		{Namespace: "prod", Pod: "web", Container: "istio-proxy", CPU: "250m", Memory: "128Mi", Timestamp: "2026-03-11T12:00:00Z"},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("flattenContainerMetrics() mismatch\nwant: %#v\ngot:  %#v", want, got)
+	}
+}
+
+func TestGetNodeMetrics_FakeCluster(t *testing.T) {
+	t.Parallel()
+
+	nodeMetrics := func(name string) metricsv1beta1.NodeMetrics {
+		return metricsv1beta1.NodeMetrics{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Usage: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("250m"),
+				corev1.ResourceMemory: resource.MustParse("1Gi"),
+			},
+		}
+	}
+
+	handler := NewMetricsHandler(fakecluster.New(fakecluster.Config{
+		NodeMetrics: []metricsv1beta1.NodeMetrics{nodeMetrics("node-b"), nodeMetrics("node-a")},
+	}), false)
+
+	result, isErr := callTool(t, handler.GetNodeMetrics, map[string]any{"title_only": true})
+	if isErr {
+		t.Fatalf("unexpected error: %v", result["error"])
+	}
+
+	want := []any{"node-a", "node-b"}
+	if !reflect.DeepEqual(result["items"], want) {
+		t.Errorf("expected items %v, got %v", want, result["items"])
+	}
+
+	result, isErr = callTool(t, handler.GetNodeMetrics, map[string]any{"node_name": "node-a"})
+	if isErr {
+		t.Fatalf("unexpected error: %v", result["error"])
+	}
+
+	usage, _ := result["usage"].(map[string]any)
+	if usage["cpu"] != "250m" {
+		t.Errorf("expected cpu usage 250m, got %v", usage["cpu"])
+	}
+}
+
+func TestGetPodMetrics_Workload(t *testing.T) {
+	t.Parallel()
+
+	controller := true
+	pod := func(name, ownerKind, ownerName, hash string) *corev1.Pod {
+		return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "shop",
+			Labels:    map[string]string{"pod-template-hash": hash},
+			OwnerReferences: []metav1.OwnerReference{
+				{Kind: ownerKind, Name: ownerName, Controller: &controller},
+			},
+		}}
+	}
+
+	podMetrics := func(name string, containers ...string) metricsv1beta1.PodMetrics {
+		metrics := metricsv1beta1.PodMetrics{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "shop"}}
+		for i := 0; i < len(containers); i += 2 {
+			metrics.Containers = append(metrics.Containers, metricsv1beta1.ContainerMetrics{
+				Name: "c" + strconv.Itoa(i),
+				Usage: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse(containers[i]),
+					corev1.ResourceMemory: resource.MustParse(containers[i+1]),
+				},
+			})
+		}
+		return metrics
+	}
+
+	handler := NewMetricsHandler(fakecluster.New(fakecluster.Config{
+		Objects: []runtime.Object{
+			pod("web-7d9f8-a", "ReplicaSet", "web-7d9f8", "7d9f8"),
+			pod("web-7d9f8-b", "ReplicaSet", "web-7d9f8", "7d9f8"),
+			pod("web-7d9f8-c", "ReplicaSet", "web-7d9f8", "7d9f8"),
+			pod("db-0", "StatefulSet", "db", ""),
+		},
+		PodMetrics: []metricsv1beta1.PodMetrics{
+			podMetrics("web-7d9f8-a", "100m", "100Mi", "50m", "28Mi"),
+			podMetrics("web-7d9f8-b", "300m", "256Mi"),
+			podMetrics("db-0", "1", "1Gi"),
+		},
+	}), false)
+
+	result, isErr := callTool(t, handler.GetPodMetrics, map[string]any{"namespace": "shop", "workload": "deployment/web"})
+	if isErr {
+		t.Fatalf("unexpected error: %v", result["error"])
+	}
+
+	if result["workload"] != "Deployment/web" || result["pod_count"] != float64(3) || result["pods_with_metrics"] != float64(2) {
+		t.Errorf("unexpected workload totals: %v", result)
+	}
+
+	var cpu, memory WorkloadUsageStats
+	decodeInto(t, result["cpu_millicores"], &cpu)
+	decodeInto(t, result["memory_bytes"], &memory)
+
+	if want := (WorkloadUsageStats{Min: 150, Max: 300, Avg: 225, Total: 450}); cpu != want {
+		t.Errorf("expected cpu stats %+v, got %+v", want, cpu)
+	}
+	if want := (WorkloadUsageStats{Min: 128 << 20, Max: 256 << 20, Avg: 192 << 20, Total: 384 << 20}); memory != want {
+		t.Errorf("expected memory stats %+v, got %+v", want, memory)
+	}
+	if !reflect.DeepEqual(result["pods_without_metrics"], []any{"web-7d9f8-c"}) {
+		t.Errorf("expected web-7d9f8-c to be reported without metrics, got %v", result["pods_without_metrics"])
+	}
+
+	for _, args := range []map[string]any{
+		{"workload": "Deployment/web"},
+		{"namespace": "shop", "workload": "Deployment/api"},
+		{"namespace": "shop", "workload": "Deployment/web", "by_container": true},
+	} {
+		if result, isErr := callTool(t, handler.GetPodMetrics, args); !isErr {
+			t.Errorf("expected an error for %v, got %v", args, result)
+		}
+	}
+}
+
+func TestResumeOffset(t *testing.T) {
+	t.Parallel()
+
+	listing := PaginationState{Type: "pod", Namespace: "shop", Sort: sortByNamespaceName, TitleOnly: true}
+
+	tests := []struct {
+		name       string
+		token      string
+		wantOffset int
+		wantErr    bool
+	}{
+		{
+			name:  "first page",
+			token: "",
+		},
+		{
+			name:       "same listing",
+			token:      generateContinueToken(10, listing),
+			wantOffset: 10,
+		},
+		{
+			name:    "namespace changed",
+			token:   generateContinueToken(10, PaginationState{Type: "pod", Namespace: "billing", Sort: sortByNamespaceName, TitleOnly: true}),
+			wantErr: true,
+		},
+		{
+			name:    "every namespace instead",
+			token:   generateContinueToken(10, PaginationState{Type: "pod", Sort: sortByNamespaceName, TitleOnly: true}),
+			wantErr: true,
+		},
+		{
+			name:    "resource type changed",
+			token:   generateContinueToken(10, PaginationState{Type: "pod", Namespace: "shop", Resource: "pods", Sort: sortByNamespaceName, TitleOnly: true}),
+			wantErr: true,
+		},
+		{
+			name:    "label selector changed",
+			token:   generateContinueToken(10, PaginationState{Type: "pod", Namespace: "shop", LabelSelector: "app=web", Sort: sortByNamespaceName, TitleOnly: true}),
+			wantErr: true,
+		},
+		{
+			name:    "field selector changed",
+			token:   generateContinueToken(10, PaginationState{Type: "pod", Namespace: "shop", FieldSelector: "status.phase=Running", Sort: sortByNamespaceName, TitleOnly: true}),
+			wantErr: true,
+		},
+		{
+			name:    "title_only changed",
+			token:   generateContinueToken(10, PaginationState{Type: "pod", Namespace: "shop", Sort: sortByTimestamp}),
+			wantErr: true,
+		},
+		{
+			name:    "sort changed",
+			token:   generateContinueToken(10, PaginationState{Type: "pod", Namespace: "shop", Sort: sortByTimestamp, TitleOnly: true}),
+			wantErr: true,
+		},
+		{
+			name:    "item type changed",
+			token:   generateContinueToken(10, PaginationState{Type: "node", Sort: sortByNamespaceName, TitleOnly: true}),
+			wantErr: true,
+		},
+		{
+			name:    "token without a recorded sort",
+			token:   "eyJvZmZzZXQiOjEwLCJ0eXBlIjoicG9kIiwibmFtZXNwYWNlIjoic2hvcCJ9",
+			wantErr: true,
+		},
+		{
+			name:    "malformed",
+			token:   "not-a-token",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			offset, err := resumeOffset(tt.token, listing)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %t, got %v", tt.wantErr, err)
+			}
+			if offset != tt.wantOffset {
+				t.Errorf("expected offset %d, got %d", tt.wantOffset, offset)
+			}
+		})
+	}
+}
+
+func TestGetPodMetrics_ContinueAcrossModes(t *testing.T) {
+	t.Parallel()
+
+	podMetrics := func(name string, age time.Duration) metricsv1beta1.PodMetrics {
+		return metricsv1beta1.PodMetrics{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "shop"}, Timestamp: metav1.NewTime(time.Now().Add(-age))}
+	}
+
+	handler := NewMetricsHandler(fakecluster.New(fakecluster.Config{
+		PodMetrics: []metricsv1beta1.PodMetrics{
+			podMetrics("a", 3*time.Second),
+			podMetrics("b", 2*time.Second),
+			podMetrics("c", time.Second),
+		},
+	}), false)
+
+	result, isErr := callTool(t, handler.GetPodMetrics, map[string]any{"namespace": "shop", "title_only": true, "limit": 2})
+	if isErr {
+		t.Fatalf("unexpected error: %v", result["error"])
+	}
+
+	token, _ := result["continue"].(string)
+	if token == "" {
+		t.Fatal("expected a continue token")
+	}
+
+	result, isErr = callTool(t, handler.GetPodMetrics, map[string]any{"namespace": "shop", "title_only": true, "limit": 2, "continue": token})
+	if isErr {
+		t.Fatalf("unexpected error: %v", result["error"])
+	}
+	if want := []any{map[string]any{"name": "c", "namespace": "shop"}}; !reflect.DeepEqual(result["items"], want) {
+		t.Errorf("expected items %v, got %v", want, result["items"])
+	}
+
+	if result, isErr := callTool(t, handler.GetPodMetrics, map[string]any{"namespace": "shop", "limit": 2, "continue": token}); !isErr {
+		t.Errorf("expected a title_only token to be rejected for full metrics, got %v", result)
+	}
+}
+
+func TestGetPodMetrics_AcrossNamespaces(t *testing.T) {
+	t.Parallel()
+
+	podMetrics := func(namespace, name string) metricsv1beta1.PodMetrics {
+		return metricsv1beta1.PodMetrics{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+	}
+
+	handler := NewMetricsHandler(fakecluster.New(fakecluster.Config{
+		PodMetrics: []metricsv1beta1.PodMetrics{
+			podMetrics("shop", "web"),
+			podMetrics("billing", "invoicer"),
+			podMetrics("kube-system", "coredns"),
+		},
+	}), false)
+
+	result, isErr := callTool(t, handler.GetPodMetrics, map[string]any{"namespace": "shop,billing", "title_only": true})
+	if isErr {
+		t.Fatalf("unexpected error: %v", result["error"])
+	}
+	want := []any{
+		map[string]any{"name": "invoicer", "namespace": "billing"},
+		map[string]any{"name": "web", "namespace": "shop"},
+	}
+	if !reflect.DeepEqual(result["items"], want) {
+		t.Errorf("expected items %v, got %v", want, result["items"])
+	}
+
+	result, isErr = callTool(t, handler.GetPodMetrics, map[string]any{"all_namespaces": true, "title_only": true})
+	if isErr {
+		t.Fatalf("unexpected error: %v", result["error"])
+	}
+	if items, _ := result["items"].([]any); len(items) != 3 {
+		t.Errorf("expected the pods of every namespace, got %v", result["items"])
+	}
+
+	for _, args := range []map[string]any{
+		{"namespace": "shop", "all_namespaces": true},
+		{"namespace": "shop,billing", "pod_name": "web"},
+		{"namespace": "shop,billing", "workload": "Deployment/web"},
+	} {
+		if result, isErr := callTool(t, handler.GetPodMetrics, args); !isErr {
+			t.Errorf("expected an error for %v, got %v", args, result)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/metricshistory"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/response"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/toolschema"
+)
+
+// MetricsHistoryHandler provides MCP tools for reading the metrics history
+// collected by a background metricshistory.Sampler. It is only registered when
+// sampling is enabled with --metrics-history-interval.
+type MetricsHistoryHandler struct {
+	sampler *metricshistory.Sampler
+}
+
+// NewMetricsHistoryHandler creates a new MetricsHistoryHandler backed by the
+// provided sampler.
+func NewMetricsHistoryHandler(sampler *metricshistory.Sampler) *MetricsHistoryHandler {
+	return &MetricsHistoryHandler{
+		sampler: sampler,
+	}
+}
+
+// GetMetricsHistoryParams defines the parameters for the get_metrics_history MCP tool.
+type GetMetricsHistoryParams struct {
+	// Kind restricts the results to "node" or "pod" series. If empty, both are returned.
+	Kind string `json:"kind,omitempty" enum:"node,pod" description:"Restrict results to \"node\" or \"pod\" series (optional - defaults to both)"`
+
+	// Namespace restricts pod series to a namespace.
+	Namespace string `json:"namespace,omitempty" description:"Restrict pod series to this namespace (optional)"`
+
+	// Name restricts the results to a single node or pod by name.
+	Name string `json:"name,omitempty" description:"Restrict results to a single node or pod by name (optional)"`
+
+	// IncludeSamples when true, includes the raw samples for each series in
+	// addition to the min/max/avg summary.
+	IncludeSamples bool `json:"include_samples,omitempty" description:"When true, includes the raw samples for each series in addition to the summary"`
+}
+
+// MetricsHistorySeries is the per-node or per-pod entry returned by get_metrics_history.
+type MetricsHistorySeries struct {
+	metricshistory.Key
+
+	// Summary reports min/max/avg/latest CPU and memory over the retained window.
+	Summary metricshistory.Summary `json:"summary"`
+
+	// Samples contains the raw retained samples, oldest first, when requested.
+	Samples []metricshistory.Sample `json:"samples,omitempty"`
+}
+
+// GetMetricsHistoryResult is the result of the get_metrics_history MCP tool.
+type GetMetricsHistoryResult struct {
+	Sampler metricshistory.Status  `json:"sampler"`
+	Count   int                    `json:"count"`
+	Items   []MetricsHistorySeries `json:"items"`
+}
+
+// GetMetricsHistory implements the get_metrics_history MCP tool.
+// It returns recent CPU and memory trends for nodes and pods from the in-memory
+// history, which helps surface spikes that a single point-in-time reading hides.
+func (h *MetricsHistoryHandler) GetMetricsHistory(_ context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var params GetMetricsHistoryParams
+	if err := bindParams(request, &params); err != nil {
+		return response.Errorf("failed to parse arguments: %s", err)
+	}
+
+	history := h.sampler.History(metricshistory.Filter{
+		Kind:      metricshistory.Kind(params.Kind),
+		Namespace: params.Namespace,
+		Name:      params.Name,
+	})
+
+	items := make([]MetricsHistorySeries, 0, len(history))
+	for _, series := range history {
+		summary, ok := metricshistory.Summarize(series.Samples)
+		if !ok {
+			continue
+		}
+
+		item := MetricsHistorySeries{
+			Key:     series.Key,
+			Summary: summary,
+		}
+
+		if params.IncludeSamples {
+			item.Samples = series.Samples
+		}
+
+		items = append(items, item)
+	}
+
+	return response.JSON(GetMetricsHistoryResult{
+		Sampler: h.sampler.Status(),
+		Count:   len(items),
+		Items:   items,
+	})
+}
+
+// GetTools returns all metrics history MCP tools provided by this handler.
+func (h *MetricsHistoryHandler) GetTools() []MCPTool {
+	return []MCPTool{
+		NewMCPTool(
+			mcp.NewTool("get_metrics_history",
+				mcp.WithDescription("Get recent CPU and memory trends (min/max/avg/latest) for nodes and pods from the server's in-memory metrics history, which is sampled from the metrics-server in the background. Useful for spotting spikes that point-in-time metrics hide. Only covers the current kubeconfig context."),
+				toolschema.Input[GetMetricsHistoryParams](),
+				toolschema.Output[GetMetricsHistoryResult](),
+			),
+			h.GetMetricsHistory,
+		),
+	}
+}
@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/connectivity"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/kubernetes"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/metricshistory"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/response"
+)
+
+const (
+	// maxMetricsSamples is the most polls a single get_node_metrics call may make.
+	maxMetricsSamples = 10
+
+	// maxMetricsSampleWindow bounds how long a single get_node_metrics call
+	// may spend polling, so a sampled call cannot hold a request open for long.
+	maxMetricsSampleWindow = 30 * time.Second
+
+	// metricsSampleMargin is the time left before the call's deadline once
+	// the last sample is due, for the last poll and the response.
+	metricsSampleMargin = 5 * time.Second
+
+	// defaultMetricsSampleInterval is the delay between polls, in seconds. It
+	// matches the metrics-server's default scrape resolution.
+	defaultMetricsSampleInterval = 15
+)
+
+// NodeMetricsSeries is the usage of a single node sampled several times
+// within one get_node_metrics call.
+type NodeMetricsSeries struct {
+	Name    string                  `json:"name"`
+	Samples []metricshistory.Sample `json:"samples"`
+
+	// Summary holds min/max/avg/latest and the first-to-last delta across
+	// the samples.
+	Summary metricshistory.Summary `json:"summary"`
+}
+
+// sleepContext waits for d, returning early with ctx's error if ctx is done first.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err() //nolint:wrapcheck // cancellation is reported as is
+	case <-timer.C:
+		return nil
+	}
+}
+
+// sampleNodeMetrics polls node metrics params.Samples times, waiting
+// params.IntervalSeconds between polls, and returns one series per node. The
+// metrics-server only refreshes usage once per scrape, so polls that return
+// an already seen timestamp are not recorded again.
+//
+// The samples are also clamped to the ones due metricsSampleMargin before
+// the call's deadline, if it has one. A call stopped by its timeout or by the
+// client between polls anyway returns the samples taken so far, which
+// calltimeout marks as partial.
+func (h *MetricsHandler) sampleNodeMetrics(ctx context.Context, client kubernetes.ClusterReader, params GetNodeMetricsParams) (*mcp.CallToolResult, error) {
+	interval := params.IntervalSeconds
+	if interval == 0 {
+		interval = defaultMetricsSampleInterval
+	}
+
+	window := time.Duration(params.Samples-1) * time.Duration(interval) * time.Second
+	if window > maxMetricsSampleWindow {
+		return response.Errorf("sampling %d times every %ds spans %s, which exceeds the maximum window of %s; reduce samples or interval_seconds", params.Samples, interval, window, maxMetricsSampleWindow)
+	}
+
+	samples := params.Samples
+	if deadline, ok := ctx.Deadline(); ok {
+		available := time.Until(deadline) - metricsSampleMargin
+		if fit := int(available/(time.Duration(interval)*time.Second)) + 1; fit < samples {
+			samples = max(fit, 1)
+		}
+	}
+
+	series := make(map[string][]metricshistory.Sample)
+
+	taken := 0
+	for ; taken < samples; taken++ {
+		if taken > 0 {
+			if err := h.wait(ctx, time.Duration(interval)*time.Second); err != nil {
+				break
+			}
+		}
+
+		items, err := pollNodeMetrics(ctx, client, params.NodeName)
+		if err != nil {
+			if taken > 0 && ctx.Err() != nil {
+				break
+			}
+			if params.NodeName != "" {
+				return h.nodeMetricsError(err, fmt.Sprintf("failed to get node metrics for %s", params.NodeName))
+			}
+			return h.nodeMetricsError(err, "failed to get node metrics")
+		}
+
+		appendNodeSamples(series, items)
+	}
+
+	names := make([]string, 0, len(series))
+	for name := range series {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	nodes := make([]NodeMetricsSeries, 0, len(names))
+	for _, name := range names {
+		summary, _ := metricshistory.Summarize(series[name])
+		nodes = append(nodes, NodeMetricsSeries{
+			Name:    name,
+			Samples: series[name],
+			Summary: summary,
+		})
+	}
+
+	return response.JSON(MetricsList{
+		Kind:            "NodeMetricsSeries",
+		Samples:         taken,
+		IntervalSeconds: interval,
+		Count:           len(nodes),
+		Items:           anyItems(nodes),
+	})
+}
+
+// pollNodeMetrics reads the current metrics of the named node, or of every
+// node when name is empty.
+func pollNodeMetrics(ctx context.Context, client kubernetes.ClusterReader, name string) ([]metricsv1beta1.NodeMetrics, error) {
+	if name != "" {
+		nodeMetrics, err := client.GetNodeMetricsByName(ctx, name)
+		if err != nil {
+			return nil, err //nolint:wrapcheck // callers add the context through nodeMetricsError
+		}
+		return []metricsv1beta1.NodeMetrics{*nodeMetrics}, nil
+	}
+
+	nodeMetricsList, err := client.GetNodeMetrics(ctx)
+	if err != nil {
+		return nil, err //nolint:wrapcheck // callers add the context through nodeMetricsError
+	}
+	return nodeMetricsList.Items, nil
+}
+
+// nodeMetricsError converts a node metrics failure into a tool error,
+// recognizing connectivity problems and a missing metrics-server.
+func (h *MetricsHandler) nodeMetricsError(err error, message string) (*mcp.CallToolResult, error) {
+	if h.alwaysStart && connectivity.IsTransportError(err) {
+		return response.Error(connectivity.ErrorMessage(err))
+	}
+	if isMetricsServerError(err) {
+		return response.Errorf("%s", formatMetricsServerError(err))
+	}
+	return response.Errorf("%s: %v", message, err)
+}
+
+// appendNodeSamples records one poll of node metrics into series, skipping
+// nodes whose sample timestamp has not changed since the previous poll.
+func appendNodeSamples(series map[string][]metricshistory.Sample, items []metricsv1beta1.NodeMetrics) {
+	for i := range items {
+		node := &items[i]
+
+		sample := metricshistory.Sample{
+			Timestamp:     node.Timestamp.Time,
+			CPUMillicores: node.Usage.Cpu().MilliValue(),
+			MemoryBytes:   node.Usage.Memory().Value(),
+		}
+
+		existing := series[node.Name]
+		if n := len(existing); n > 0 && !sample.Timestamp.After(existing[n-1].Timestamp) {
+			continue
+		}
+
+		series[node.Name] = append(existing, sample)
+	}
+}
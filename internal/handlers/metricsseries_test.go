@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/fakecluster"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/metricshistory"
+)
+
+func TestAppendNodeSamples(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2026, 3, 11, 12, 0, 0, 0, time.UTC)
+	poll := func(offset time.Duration, cpu string) []metricsv1beta1.NodeMetrics {
+		return []metricsv1beta1.NodeMetrics{{
+			ObjectMeta: metav1.ObjectMeta{Name: "node-a"},
+			Timestamp:  metav1.NewTime(start.Add(offset)),
+			Usage: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse(cpu),
+				corev1.ResourceMemory: resource.MustParse("1Gi"),
+			},
+		}}
+	}
+
+	series := make(map[string][]metricshistory.Sample)
+	appendNodeSamples(series, poll(0, "100m"))
+	appendNodeSamples(series, poll(0, "100m")) // metrics-server has not scraped again
+	appendNodeSamples(series, poll(15*time.Second, "400m"))
+
+	want := []metricshistory.Sample{
+		{Timestamp: start, CPUMillicores: 100, MemoryBytes: 1 << 30},
+		{Timestamp: start.Add(15 * time.Second), CPUMillicores: 400, MemoryBytes: 1 << 30},
+	}
+	if !reflect.DeepEqual(series["node-a"], want) {
+		t.Errorf("series mismatch\nwant: %#v\ngot:  %#v", want, series["node-a"])
+	}
+}
+
+func TestGetNodeMetrics_Samples(t *testing.T) {
+	t.Parallel()
+
+	handler := NewMetricsHandler(fakecluster.New(fakecluster.Config{
+		NodeMetrics: []metricsv1beta1.NodeMetrics{{
+			ObjectMeta: metav1.ObjectMeta{Name: "node-a"},
+			Timestamp:  metav1.NewTime(time.Date(2026, 3, 11, 12, 0, 0, 0, time.UTC)),
+			Usage: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("250m"),
+				corev1.ResourceMemory: resource.MustParse("1Gi"),
+			},
+		}},
+	}), false)
+
+	var waits []time.Duration
+	handler.wait = func(_ context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return nil
+	}
+
+	result, isErr := callTool(t, handler.GetNodeMetrics, map[string]any{"samples": 3, "interval_seconds": 15})
+	if isErr {
+		t.Fatalf("unexpected error: %v", result["error"])
+	}
+
+	if want := []time.Duration{15 * time.Second, 15 * time.Second}; !reflect.DeepEqual(waits, want) {
+		t.Errorf("expected waits %v, got %v", want, waits)
+	}
+
+	var items []NodeMetricsSeries
+	decodeInto(t, result["items"], &items)
+	if len(items) != 1 || items[0].Name != "node-a" || len(items[0].Samples) != 1 || items[0].Summary.CPUMillicores.Latest != 250 {
+		t.Errorf("expected a single deduplicated sample for node-a, got %#v", items)
+	}
+
+	// Samples are clamped to the ones due before the call's deadline.
+	waits = nil
+	result, isErr = callTool(t, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		ctx, cancel := context.WithTimeout(ctx, 25*time.Second)
+		defer cancel()
+		return handler.GetNodeMetrics(ctx, request)
+	}, map[string]any{"samples": 3, "interval_seconds": 15})
+	if isErr || result["samples"] != float64(2) || len(waits) != 1 {
+		t.Errorf("expected 2 samples within the deadline, got %v after %d waits", result, len(waits))
+	}
+
+	// A call stopped between polls keeps the samples already taken.
+	waits = nil
+	handler.wait = func(context.Context, time.Duration) error {
+		if len(waits) == 1 {
+			return context.Canceled
+		}
+		waits = append(waits, 0)
+		return nil
+	}
+	result, isErr = callTool(t, handler.GetNodeMetrics, map[string]any{"samples": 3})
+	if isErr || result["samples"] != float64(2) || result["count"] != float64(1) {
+		t.Errorf("expected the two samples taken before the stop, got %v", result)
+	}
+
+	for _, args := range []map[string]any{
+		{"samples": 10, "interval_seconds": 60},
+		{"samples": 2, "title_only": true},
+	} {
+		if result, isErr := callTool(t, handler.GetNodeMetrics, args); !isErr {
+			t.Errorf("expected an error for %v, got %v", args, result)
+		}
+	}
+}
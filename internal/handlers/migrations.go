@@ -0,0 +1,372 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/connectivity"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/response"
+)
+
+// MigrationTargetsParams defines the parameters for the migration_targets MCP tool.
+type MigrationTargetsParams struct {
+	// APIVersion is the deprecated API version to migrate from.
+	APIVersion string `json:"api_version" required:"true" description:"Deprecated API version to migrate from (e.g., 'extensions/v1beta1'), as reported by check_deprecated_apis"`
+
+	// Kind is the kind served by the deprecated API version.
+	Kind string `json:"kind" required:"true" description:"Kind or plural resource name to migrate (e.g., 'Ingress' or 'ingresses')"`
+
+	// Namespace limits the search for affected namespaced objects.
+	Namespace string `json:"namespace,omitempty" description:"Namespace to search for affected objects (leave empty for all namespaces). Ignored for cluster-scoped kinds"`
+
+	// Context specifies the Kubernetes context to use.
+	Context string `json:"context,omitempty" description:"Kubernetes context to use (defaults to current context from kubeconfig)"`
+}
+
+// MigrationObject is an object that was last applied or written through the
+// deprecated API version and whose manifest or controller needs migrating.
+type MigrationObject struct {
+	// Namespace is the object's namespace, empty for cluster-scoped objects.
+	Namespace string `json:"namespace,omitempty"`
+
+	// Name is the object's name.
+	Name string `json:"name"`
+
+	// Sources lists where the deprecated API version was found: the
+	// last-applied-configuration annotation or a field manager's entry.
+	Sources []string `json:"sources"`
+}
+
+// MigrationTargetsResult is the result of the migration_targets MCP tool.
+type MigrationTargetsResult struct {
+	APIVersion   string `json:"api_version"`
+	Kind         string `json:"kind"`
+	DeprecatedIn string `json:"deprecated_in"`
+	RemovedIn    string `json:"removed_in"`
+
+	// Replacement is the API version to migrate to, when there is one.
+	Replacement       string   `json:"replacement,omitempty"`
+	DeprecatedServed  bool     `json:"deprecated_served"`
+	ReplacementServed bool     `json:"replacement_served"`
+	FieldChanges      []string `json:"field_changes"`
+
+	Namespace      string            `json:"namespace,omitempty"`
+	ObjectsChecked int               `json:"objects_checked"`
+	Count          int               `json:"count"`
+	Objects        []MigrationObject `json:"objects"`
+
+	Notes    []string `json:"notes,omitempty"`
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// MigrationTargets implements the migration_targets MCP tool.
+// For a deprecated API version and kind from the deprecation table, it
+// reports the API version to migrate to, the field-level changes between
+// the two versions that manifests must account for, and the objects last
+// applied or written through the deprecated version. It complements
+// check_deprecated_apis, which finds what to migrate, with how to migrate it.
+func (h *ResourceHandler) MigrationTargets(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var params MigrationTargetsParams
+	if err := bindParams(request, &params); err != nil {
+		return response.Errorf("failed to parse arguments: %s", err)
+	}
+
+	api, ok := findDeprecatedAPI(params.APIVersion, params.Kind)
+	if !ok {
+		var known []string
+		for _, candidate := range deprecatedAPIs {
+			if candidate.APIVersion == params.APIVersion {
+				known = append(known, candidate.Kind)
+			}
+		}
+		if len(known) == 0 {
+			return response.Errorf("%s %s is not a deprecated API in the built-in deprecation table", params.APIVersion, params.Kind)
+		}
+		return response.Errorf("%s %s is not a deprecated API in the built-in deprecation table; deprecated kinds in %s are: %s",
+			params.APIVersion, params.Kind, params.APIVersion, strings.Join(known, ", "))
+	}
+
+	// Use the appropriate client based on context
+	client, err := h.client.ForContext(ctx, params.Context)
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to create client with context %s: %v", params.Context, err)
+	}
+
+	lists, err := client.DiscoverAllResources(ctx)
+	if err != nil && len(lists) == 0 {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to discover API resources: %v", err)
+	}
+
+	served := make(map[schema.GroupVersionResource]metav1.APIResource)
+	for _, list := range lists {
+		gv, err := schema.ParseGroupVersion(list.GroupVersion)
+		if err != nil {
+			continue
+		}
+		for i := range list.APIResources {
+			served[gv.WithResource(list.APIResources[i].Name)] = list.APIResources[i]
+		}
+	}
+
+	deprecatedGVR := mustGroupVersion(api.APIVersion).WithResource(api.Resource)
+	_, deprecatedServed := served[deprecatedGVR]
+
+	var notes, warnings []string
+
+	// Follow the replacement while it is itself deprecated, so the target is
+	// the version that is safe to migrate to.
+	target := api.Replacement
+	for target != "" {
+		next, ok := findDeprecatedAPI(target, api.Kind)
+		if !ok {
+			break
+		}
+		notes = append(notes, fmt.Sprintf("%s %s is itself deprecated in %s and removed in %s", target, api.Kind, next.DeprecatedIn, next.RemovedIn))
+		target = next.Replacement
+	}
+
+	replacementServed := false
+	if target == "" {
+		notes = append(notes, fmt.Sprintf("%s was removed without a replacement API version", api.Kind))
+	} else {
+		_, replacementServed = served[mustGroupVersion(target).WithResource(api.Resource)]
+		if !replacementServed {
+			notes = append(notes, fmt.Sprintf("the cluster does not serve %s %s yet; upgrade the cluster before migrating manifests to it", target, api.Kind))
+		}
+	}
+
+	// Objects of a kind are readable through any served version, so they are
+	// listed through the replacement when possible.
+	listGVR, found := schema.GroupVersionResource{}, false
+	for _, candidate := range []string{api.Replacement, api.APIVersion} {
+		if candidate == "" {
+			continue
+		}
+		gvr := mustGroupVersion(candidate).WithResource(api.Resource)
+		if _, ok := served[gvr]; ok {
+			listGVR, found = gvr, true
+			break
+		}
+	}
+
+	objects := make([]MigrationObject, 0)
+	checked := 0
+
+	if found {
+		if result, err := h.disabledResult(api.Resource, listGVR); result != nil || err != nil {
+			return result, err
+		}
+
+		namespace := ""
+		if served[listGVR].Namespaced {
+			namespace = params.Namespace
+		}
+
+		list, err := client.ListResources(ctx, listGVR, namespace, metav1.ListOptions{})
+		if err != nil {
+			if h.alwaysStart && connectivity.IsTransportError(err) {
+				return response.Error(connectivity.ErrorMessage(err))
+			}
+			return response.Errorf("failed to list %s: %v", api.Resource, err)
+		}
+
+		candidate := DeprecatedAPIStatus{APIVersion: api.APIVersion, Kind: api.Kind}
+		for i := range list.Items {
+			checked++
+			for _, usage := range deprecatedUsages(&list.Items[i], []DeprecatedAPIStatus{candidate}) {
+				objects = append(objects, MigrationObject{
+					Namespace: usage.Namespace,
+					Name:      usage.Name,
+					Sources:   usage.Sources,
+				})
+			}
+		}
+	} else {
+		warnings = append(warnings, fmt.Sprintf("the cluster serves %s through neither %s nor its replacement, so affected objects could not be listed", api.Resource, api.APIVersion))
+	}
+
+	sort.Slice(objects, func(i, j int) bool {
+		if objects[i].Namespace != objects[j].Namespace {
+			return objects[i].Namespace < objects[j].Namespace
+		}
+		return objects[i].Name < objects[j].Name
+	})
+
+	return response.JSON(MigrationTargetsResult{
+		APIVersion:        api.APIVersion,
+		Kind:              api.Kind,
+		DeprecatedIn:      api.DeprecatedIn,
+		RemovedIn:         api.RemovedIn,
+		Replacement:       target,
+		DeprecatedServed:  deprecatedServed,
+		ReplacementServed: replacementServed,
+		FieldChanges:      migrationFieldChanges(api),
+		Namespace:         params.Namespace,
+		ObjectsChecked:    checked,
+		Count:             len(objects),
+		Objects:           objects,
+		Notes:             notes,
+		Warnings:          warnings,
+	})
+}
+
+// findDeprecatedAPI returns the deprecation table entry for an API version
+// and a kind, matched case-insensitively by kind or plural resource name.
+func findDeprecatedAPI(apiVersion, kind string) (deprecatedAPI, bool) {
+	for _, api := range deprecatedAPIs {
+		if api.APIVersion != apiVersion {
+			continue
+		}
+		if strings.EqualFold(api.Kind, kind) || strings.EqualFold(api.Resource, kind) {
+			return api, true
+		}
+	}
+	return deprecatedAPI{}, false
+}
+
+// migrationFieldChanges returns the changes between a deprecated API version
+// and its replacement that a manifest must account for, following the
+// Kubernetes deprecated API migration guide. Kinds whose schema did not
+// change only need their apiVersion updated.
+func migrationFieldChanges(api deprecatedAPI) []string {
+	switch {
+	case api.Kind == "Deployment":
+		changes := []string{
+			"spec.selector is required and must match spec.template.metadata.labels; it can no longer be defaulted from the template labels",
+			"spec.selector is immutable after creation",
+			"spec.rollbackTo was removed; roll back with kubectl rollout undo instead",
+			"spec.progressDeadlineSeconds defaults to 600",
+			"spec.revisionHistoryLimit defaults to 10",
+		}
+		if api.APIVersion == "extensions/v1beta1" {
+			changes = append(changes, "spec.strategy.rollingUpdate.maxSurge and maxUnavailable default to 25% instead of 1")
+		}
+		return changes
+
+	case api.Kind == "DaemonSet":
+		changes := []string{
+			"spec.selector is required and must match spec.template.metadata.labels; it can no longer be defaulted from the template labels",
+			"spec.selector is immutable after creation",
+		}
+		if api.APIVersion == "extensions/v1beta1" {
+			changes = append(changes,
+				"spec.templateGeneration was removed",
+				"spec.updateStrategy.type defaults to RollingUpdate instead of OnDelete",
+			)
+		}
+		return changes
+
+	case api.Kind == "StatefulSet":
+		changes := []string{
+			"spec.selector is required and must match spec.template.metadata.labels; it can no longer be defaulted from the template labels",
+			"spec.selector is immutable after creation",
+		}
+		if api.APIVersion == "apps/v1beta1" {
+			changes = append(changes, "spec.updateStrategy.type defaults to RollingUpdate instead of OnDelete")
+		}
+		return changes
+
+	case api.Kind == "ReplicaSet":
+		return []string{
+			"spec.selector is required and must match spec.template.metadata.labels; it can no longer be defaulted from the template labels",
+			"spec.selector is immutable after creation",
+		}
+
+	case api.Kind == "Ingress":
+		return []string{
+			"spec.backend is renamed to spec.defaultBackend",
+			"backend serviceName is renamed to service.name",
+			"numeric backend servicePort is renamed to service.port.number, and string servicePort to service.port.name",
+			"pathType is required for each path: Exact, Prefix, or ImplementationSpecific",
+			"the kubernetes.io/ingress.class annotation is replaced by spec.ingressClassName",
+		}
+
+	case api.Kind == "MutatingWebhookConfiguration" || api.Kind == "ValidatingWebhookConfiguration":
+		return []string{
+			"webhooks[*].failurePolicy defaults to Fail instead of Ignore",
+			"webhooks[*].matchPolicy defaults to Equivalent instead of Exact",
+			"webhooks[*].timeoutSeconds defaults to 10s instead of 30s",
+			"webhooks[*].sideEffects is required and must be None or NoneOnDryRun",
+			"webhooks[*].admissionReviewVersions is required",
+			"webhooks[*].name must be unique within the configuration",
+		}
+
+	case api.Kind == "CustomResourceDefinition":
+		return []string{
+			"spec.scope is required and no longer defaults to Namespaced",
+			"spec.preserveUnknownFields: true is not allowed; use x-kubernetes-preserve-unknown-fields in the schema instead",
+			"spec.version is removed; list every version in spec.versions",
+			"spec.validation is moved to spec.versions[*].schema, which is required and must be a structural schema",
+			"spec.subresources is moved to spec.versions[*].subresources",
+			"spec.additionalPrinterColumns is moved to spec.versions[*].additionalPrinterColumns, and JSONPath is renamed to jsonPath",
+			"spec.conversion.webhookClientConfig is moved to spec.conversion.webhook.clientConfig",
+			"spec.conversion.conversionReviewVersions is moved to spec.conversion.webhook.conversionReviewVersions and is required",
+		}
+
+	case api.Kind == "CertificateSigningRequest":
+		return []string{
+			"spec.signerName is required and kubernetes.io/legacy-unknown is not allowed",
+			"spec.usages is required",
+			"status.conditions cannot contain duplicate types, and status.certificate must be PEM-encoded",
+		}
+
+	case api.Kind == "EndpointSlice":
+		return []string{
+			"endpoints[*].topology is removed",
+			"the kubernetes.io/hostname topology key is replaced by endpoints[*].nodeName",
+			"the topology.kubernetes.io/zone topology key is replaced by endpoints[*].zone",
+		}
+
+	case api.Kind == "Event" && api.APIVersion == "events.k8s.io/v1beta1":
+		return []string{
+			"type is limited to Normal and Warning",
+			"involvedObject is renamed to regarding",
+			"action, reason, reportingController, and reportingInstance are required when creating events",
+			"firstTimestamp is replaced by eventTime",
+			"lastTimestamp is replaced by series.lastObservedTime",
+			"count is replaced by series.count",
+			"source.component is replaced by reportingController, and source.host by reportingInstance",
+		}
+
+	case api.Kind == "HorizontalPodAutoscaler" && api.APIVersion == "autoscaling/v2beta1":
+		return []string{
+			"targetAverageUtilization is replaced by target.averageUtilization with target.type: Utilization",
+			"targetAverageValue is replaced by target.averageValue with target.type: AverageValue",
+			"targetValue is replaced by target.value with target.type: Value",
+			"metricName and metricSelector are moved to metric.name and metric.selector",
+		}
+
+	case api.Kind == "PodDisruptionBudget":
+		return []string{
+			"an empty spec.selector ({}) selects every pod in the namespace instead of none",
+		}
+
+	case api.Kind == "PodSecurityPolicy":
+		return []string{
+			"there is no replacement kind; enforce pod security with Pod Security Admission namespace labels (pod-security.kubernetes.io/enforce) or a third-party admission controller",
+		}
+
+	case api.Kind == "PriorityLevelConfiguration":
+		changes := []string{
+			"spec.limited.nominalConcurrencyShares defaults to 30 only when unset; an explicit 0 is kept",
+		}
+		if api.APIVersion != "flowcontrol.apiserver.k8s.io/v1beta3" {
+			changes = append(changes, "spec.limited.assuredConcurrencyShares is renamed to spec.limited.nominalConcurrencyShares")
+		}
+		return changes
+	}
+
+	return []string{"no schema changes; only apiVersion needs updating"}
+}
@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/fakecluster"
+)
+
+func TestFindDeprecatedAPI(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		apiVersion string
+		kind       string
+		wantKind   string
+		wantOK     bool
+	}{
+		{name: "kind", apiVersion: "batch/v1beta1", kind: "CronJob", wantKind: "CronJob", wantOK: true},
+		{name: "lowercase kind", apiVersion: "extensions/v1beta1", kind: "ingress", wantKind: "Ingress", wantOK: true},
+		{name: "resource name", apiVersion: "policy/v1beta1", kind: "poddisruptionbudgets", wantKind: "PodDisruptionBudget", wantOK: true},
+		{name: "served version", apiVersion: "batch/v1", kind: "CronJob"},
+		{name: "kind not deprecated in version", apiVersion: "extensions/v1beta1", kind: "CronJob"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, ok := findDeprecatedAPI(tt.apiVersion, tt.kind)
+			if ok != tt.wantOK || got.Kind != tt.wantKind {
+				t.Errorf("expected kind=%q ok=%v, got kind=%q ok=%v", tt.wantKind, tt.wantOK, got.Kind, ok)
+			}
+		})
+	}
+}
+
+func TestMigrationTargets(t *testing.T) {
+	t.Parallel()
+
+	apiResources := append(fakecluster.DefaultAPIResources(),
+		&metav1.APIResourceList{
+			GroupVersion: "networking.k8s.io/v1",
+			APIResources: []metav1.APIResource{{Name: "ingresses", Kind: "Ingress", Namespaced: true}},
+		},
+	)
+
+	handler := NewResourceHandler(fakecluster.New(fakecluster.Config{
+		APIResources: apiResources,
+		Objects: []runtime.Object{
+			&networkingv1.Ingress{ObjectMeta: metav1.ObjectMeta{
+				Name:      "web",
+				Namespace: "shop",
+				Annotations: map[string]string{
+					"kubectl.kubernetes.io/last-applied-configuration": `{"apiVersion":"extensions/v1beta1","kind":"Ingress"}`,
+				},
+			}},
+			&networkingv1.Ingress{ObjectMeta: metav1.ObjectMeta{
+				Name:          "admin",
+				Namespace:     "ops",
+				ManagedFields: []metav1.ManagedFieldsEntry{{Manager: "helm", APIVersion: "networking.k8s.io/v1beta1"}},
+			}},
+			&networkingv1.Ingress{ObjectMeta: metav1.ObjectMeta{
+				Name:          "api",
+				Namespace:     "shop",
+				ManagedFields: []metav1.ManagedFieldsEntry{{Manager: "argocd", APIVersion: "networking.k8s.io/v1"}},
+			}},
+		},
+	}), nil, false)
+
+	result, isErr := callTool(t, handler.MigrationTargets, map[string]any{"api_version": "extensions/v1beta1", "kind": "Ingress"})
+	if isErr {
+		t.Fatalf("unexpected error: %v", result["error"])
+	}
+
+	if result["replacement"] != "networking.k8s.io/v1" || result["replacement_served"] != true || result["deprecated_served"] != false {
+		t.Errorf("unexpected replacement details: %v", result)
+	}
+	if result["objects_checked"] != float64(3) {
+		t.Errorf("expected 3 objects checked, got %v", result["objects_checked"])
+	}
+
+	var objects []MigrationObject
+	decodeInto(t, result["objects"], &objects)
+	want := []MigrationObject{{Namespace: "shop", Name: "web", Sources: []string{"last-applied-configuration"}}}
+	if !reflect.DeepEqual(objects, want) {
+		t.Errorf("expected objects %+v, got %+v", want, objects)
+	}
+
+	var changes []string
+	decodeInto(t, result["field_changes"], &changes)
+	if len(changes) == 0 || !strings.Contains(changes[0], "spec.defaultBackend") {
+		t.Errorf("expected Ingress field changes, got %q", changes)
+	}
+
+	result, isErr = callTool(t, handler.MigrationTargets, map[string]any{"api_version": "extensions/v1beta1", "kind": "PodSecurityPolicy"})
+	if isErr {
+		t.Fatalf("unexpected error: %v", result["error"])
+	}
+	if _, ok := result["replacement"]; ok {
+		t.Errorf("expected no replacement for PodSecurityPolicy, got %v", result["replacement"])
+	}
+	var notes []string
+	decodeInto(t, result["notes"], &notes)
+	if len(notes) != 2 || !strings.Contains(notes[0], "policy/v1beta1 PodSecurityPolicy is itself deprecated") {
+		t.Errorf("expected notes about the deprecated replacement, got %q", notes)
+	}
+
+	result, isErr = callTool(t, handler.MigrationTargets, map[string]any{"api_version": "extensions/v1beta1", "kind": "CronJob"})
+	if msg, _ := result["error"].(string); !isErr || !strings.Contains(msg, "deprecated kinds in extensions/v1beta1 are: Deployment") {
+		t.Errorf("expected an error listing the deprecated kinds, got %v", result)
+	}
+}
@@ -0,0 +1,290 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/connectivity"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/response"
+)
+
+// GetNamespaceLimitsParams defines the parameters for the get_namespace_limits MCP tool.
+type GetNamespaceLimitsParams struct {
+	// Namespace specifies the namespace to summarize.
+	Namespace string `json:"namespace" required:"true" description:"Namespace to summarize quotas and limit ranges for"`
+
+	// Context specifies which Kubernetes context to use for this operation.
+	// If empty, uses the current context from kubeconfig.
+	Context string `json:"context,omitempty" description:"Kubernetes context to use (defaults to current context from kubeconfig)"`
+}
+
+// LimitRangeInfo summarizes a single LimitRange object.
+type LimitRangeInfo struct {
+	Name   string           `json:"name"`
+	Limits []LimitRangeItem `json:"limits"`
+}
+
+// LimitRangeItem is one entry of a LimitRange, for Containers, Pods, or
+// PersistentVolumeClaims, with quantities rendered as strings.
+type LimitRangeItem struct {
+	Type                 string            `json:"type"`
+	Min                  map[string]string `json:"min,omitempty"`
+	Max                  map[string]string `json:"max,omitempty"`
+	Default              map[string]string `json:"default,omitempty"`
+	DefaultRequest       map[string]string `json:"default_request,omitempty"`
+	MaxLimitRequestRatio map[string]string `json:"max_limit_request_ratio,omitempty"`
+}
+
+// ContainerDefault is the effective constraint on one resource for the
+// containers of new pods, merged across the namespace's LimitRanges.
+type ContainerDefault struct {
+	// Resource is the resource name, such as cpu or memory.
+	Resource string `json:"resource"`
+
+	// DefaultRequest and DefaultLimit are applied to containers that do
+	// not set their own request or limit.
+	DefaultRequest string `json:"default_request,omitempty"`
+	DefaultLimit   string `json:"default_limit,omitempty"`
+
+	Min                  string `json:"min,omitempty"`
+	Max                  string `json:"max,omitempty"`
+	MaxLimitRequestRatio string `json:"max_limit_request_ratio,omitempty"`
+
+	// LimitRanges names the LimitRanges that constrain the resource.
+	LimitRanges []string `json:"limit_ranges"`
+}
+
+// GetNamespaceLimitsResult is the result of the get_namespace_limits MCP
+// tool.
+type GetNamespaceLimitsResult struct {
+	Namespace         string             `json:"namespace"`
+	Quotas            []QuotaUsage       `json:"quotas"`
+	LimitRanges       []LimitRangeInfo   `json:"limit_ranges"`
+	ContainerDefaults []ContainerDefault `json:"container_defaults"`
+	Findings          []string           `json:"findings,omitempty"`
+}
+
+// GetNamespaceLimits implements the get_namespace_limits MCP tool.
+// It combines the ResourceQuotas and LimitRanges of a namespace into one
+// summary, including the default requests and limits the LimitRanger
+// admission plugin applies to containers of new pods that do not set their
+// own, and flags quotas that new pods would trip over.
+func (h *QuotaHandler) GetNamespaceLimits(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var params GetNamespaceLimitsParams
+	if err := bindParams(request, &params); err != nil {
+		return response.Errorf("failed to parse arguments: %s", err)
+	}
+
+	// Use the appropriate client based on context
+	client, err := h.client.ForContext(ctx, params.Context)
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to create client with context %s: %v", params.Context, err)
+	}
+
+	quotas, err := client.ListResourceQuotas(ctx, params.Namespace)
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to list resource quotas: %v", err)
+	}
+
+	limitRanges, err := client.ListLimitRanges(ctx, params.Namespace)
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to list limit ranges: %v", err)
+	}
+
+	sort.Slice(quotas.Items, func(i, j int) bool { return quotas.Items[i].Name < quotas.Items[j].Name })
+	sort.Slice(limitRanges.Items, func(i, j int) bool { return limitRanges.Items[i].Name < limitRanges.Items[j].Name })
+
+	usages := make([]QuotaUsage, 0, len(quotas.Items))
+	for i := range quotas.Items {
+		usages = append(usages, quotaUsage(&quotas.Items[i], defaultQuotaThreshold))
+	}
+
+	ranges := make([]LimitRangeInfo, 0, len(limitRanges.Items))
+	for i := range limitRanges.Items {
+		ranges = append(ranges, limitRangeInfo(&limitRanges.Items[i]))
+	}
+
+	defaults := containerDefaults(limitRanges.Items)
+
+	return response.JSON(GetNamespaceLimitsResult{
+		Namespace:         params.Namespace,
+		Quotas:            usages,
+		LimitRanges:       ranges,
+		ContainerDefaults: defaults,
+		Findings:          namespaceLimitFindings(quotas.Items, usages, defaults),
+	})
+}
+
+// limitRangeInfo renders the entries of a LimitRange.
+func limitRangeInfo(limitRange *corev1.LimitRange) LimitRangeInfo {
+	info := LimitRangeInfo{Name: limitRange.Name, Limits: make([]LimitRangeItem, 0, len(limitRange.Spec.Limits))}
+
+	for _, limit := range limitRange.Spec.Limits {
+		info.Limits = append(info.Limits, LimitRangeItem{
+			Type:                 string(limit.Type),
+			Min:                  nonEmptyResources(limit.Min),
+			Max:                  nonEmptyResources(limit.Max),
+			Default:              nonEmptyResources(limit.Default),
+			DefaultRequest:       nonEmptyResources(limit.DefaultRequest),
+			MaxLimitRequestRatio: nonEmptyResources(limit.MaxLimitRequestRatio),
+		})
+	}
+
+	return info
+}
+
+// nonEmptyResources formats a resource list, or returns nil when it is
+// empty so the field is omitted.
+func nonEmptyResources(list corev1.ResourceList) map[string]string {
+	if len(list) == 0 {
+		return nil
+	}
+	return formatResources(list)
+}
+
+// containerDefaults merges the Container entries of LimitRanges, sorted by
+// name, into the effective constraint on each resource. When several
+// LimitRanges set the same value, the first one is reported; every one of
+// them is listed under LimitRanges.
+func containerDefaults(limitRanges []corev1.LimitRange) []ContainerDefault {
+	byResource := make(map[corev1.ResourceName]*ContainerDefault)
+
+	set := func(target *string, quantities corev1.ResourceList, name corev1.ResourceName) {
+		if quantity, ok := quantities[name]; ok && *target == "" {
+			*target = quantity.String()
+		}
+	}
+
+	for i := range limitRanges {
+		for _, limit := range limitRanges[i].Spec.Limits {
+			if limit.Type != corev1.LimitTypeContainer {
+				continue
+			}
+
+			names := make(corev1.ResourceList)
+			for _, list := range []corev1.ResourceList{limit.Min, limit.Max, limit.Default, limit.DefaultRequest, limit.MaxLimitRequestRatio} {
+				for name, quantity := range list {
+					names[name] = quantity
+				}
+			}
+
+			for _, name := range sortedResourceNames(names) {
+				entry := byResource[name]
+				if entry == nil {
+					entry = &ContainerDefault{Resource: string(name)}
+					byResource[name] = entry
+				}
+
+				set(&entry.DefaultRequest, limit.DefaultRequest, name)
+				set(&entry.DefaultLimit, limit.Default, name)
+				set(&entry.Min, limit.Min, name)
+				set(&entry.Max, limit.Max, name)
+				set(&entry.MaxLimitRequestRatio, limit.MaxLimitRequestRatio, name)
+
+				if !containsString(entry.LimitRanges, limitRanges[i].Name) {
+					entry.LimitRanges = append(entry.LimitRanges, limitRanges[i].Name)
+				}
+			}
+		}
+	}
+
+	defaults := make([]ContainerDefault, 0, len(byResource))
+	for _, entry := range byResource {
+		defaults = append(defaults, *entry)
+	}
+	sort.Slice(defaults, func(i, j int) bool { return defaults[i].Resource < defaults[j].Resource })
+
+	return defaults
+}
+
+// quotaRequirement maps the compute resources a ResourceQuota can track to
+// the container resource and whether the quota counts its request or limit.
+// While a quota tracks one of them, pods whose containers omit it are
+// rejected.
+var quotaRequirement = map[corev1.ResourceName]struct {
+	resource corev1.ResourceName
+	limit    bool
+}{
+	corev1.ResourceCPU:                      {corev1.ResourceCPU, false},
+	corev1.ResourceMemory:                   {corev1.ResourceMemory, false},
+	corev1.ResourceEphemeralStorage:         {corev1.ResourceEphemeralStorage, false},
+	corev1.ResourceRequestsCPU:              {corev1.ResourceCPU, false},
+	corev1.ResourceRequestsMemory:           {corev1.ResourceMemory, false},
+	corev1.ResourceRequestsEphemeralStorage: {corev1.ResourceEphemeralStorage, false},
+	corev1.ResourceLimitsCPU:                {corev1.ResourceCPU, true},
+	corev1.ResourceLimitsMemory:             {corev1.ResourceMemory, true},
+	corev1.ResourceLimitsEphemeralStorage:   {corev1.ResourceEphemeralStorage, true},
+}
+
+// namespaceLimitFindings flags exhausted quotas, quotas that require a
+// request or limit no LimitRange defaults, and resources whose defaults
+// come from several LimitRanges.
+func namespaceLimitFindings(quotas []corev1.ResourceQuota, usages []QuotaUsage, defaults []ContainerDefault) []string {
+	var findings []string
+
+	for _, usage := range usages {
+		var exhausted []string
+		for _, res := range usage.Resources {
+			if res.Exhausted {
+				exhausted = append(exhausted, fmt.Sprintf("%s (%s of %s)", res.Resource, res.Used, res.Hard))
+			}
+		}
+		if len(exhausted) > 0 {
+			findings = append(findings, fmt.Sprintf("ResourceQuota %s is exhausted for %s; new objects that need more are rejected", usage.Name, strings.Join(exhausted, ", ")))
+		}
+	}
+
+	defaultsByResource := make(map[string]ContainerDefault, len(defaults))
+	for _, entry := range defaults {
+		defaultsByResource[entry.Resource] = entry
+	}
+
+	for i := range quotas {
+		quota := &quotas[i]
+
+		// Scoped quotas such as BestEffort do not track compute resources
+		// of every pod, so only unscoped quotas are checked.
+		if len(quota.Spec.Scopes) > 0 || quota.Spec.ScopeSelector != nil {
+			continue
+		}
+
+		for _, name := range sortedResourceNames(quota.Spec.Hard) {
+			requirement, ok := quotaRequirement[name]
+			if !ok {
+				continue
+			}
+
+			entry := defaultsByResource[string(requirement.resource)]
+			if requirement.limit && entry.DefaultLimit == "" {
+				findings = append(findings, fmt.Sprintf("ResourceQuota %s tracks %s, but no LimitRange sets a default %s limit, so pods with a container that does not set one are rejected", quota.Name, name, requirement.resource))
+			}
+			// A container that sets only a limit gets a request equal to
+			// it, so a default limit also covers the request.
+			if !requirement.limit && entry.DefaultRequest == "" && entry.DefaultLimit == "" {
+				findings = append(findings, fmt.Sprintf("ResourceQuota %s tracks %s, but no LimitRange sets a default %s request, so pods with a container that does not set one are rejected", quota.Name, name, requirement.resource))
+			}
+		}
+	}
+
+	for _, entry := range defaults {
+		if len(entry.LimitRanges) > 1 {
+			findings = append(findings, fmt.Sprintf("%d LimitRanges (%s) constrain %s for containers; the strictest min and max apply, but which default is applied is not guaranteed", len(entry.LimitRanges), strings.Join(entry.LimitRanges, ", "), entry.Resource))
+		}
+	}
+
+	return findings
+}
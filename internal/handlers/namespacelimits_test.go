@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/fakecluster"
+)
+
+func TestContainerDefaults(t *testing.T) {
+	t.Parallel()
+
+	limitRanges := []corev1.LimitRange{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "defaults"},
+			Spec: corev1.LimitRangeSpec{Limits: []corev1.LimitRangeItem{
+				{
+					Type:           corev1.LimitTypeContainer,
+					Default:        corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("512Mi")},
+					DefaultRequest: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("256Mi"), corev1.ResourceCPU: resource.MustParse("100m")},
+				},
+				{
+					Type: corev1.LimitTypePod,
+					Max:  corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")},
+				},
+			}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "limits"},
+			Spec: corev1.LimitRangeSpec{Limits: []corev1.LimitRangeItem{{
+				Type:           corev1.LimitTypeContainer,
+				Max:            corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")},
+				DefaultRequest: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("250m")},
+			}}},
+		},
+	}
+
+	want := []ContainerDefault{
+		{Resource: "cpu", DefaultRequest: "100m", Max: "2", LimitRanges: []string{"defaults", "limits"}},
+		{Resource: "memory", DefaultRequest: "256Mi", DefaultLimit: "512Mi", LimitRanges: []string{"defaults"}},
+	}
+
+	if got := containerDefaults(limitRanges); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+}
+
+func TestNamespaceLimitFindings(t *testing.T) {
+	t.Parallel()
+
+	quota := func(name string, hard corev1.ResourceList, scopes ...corev1.ResourceQuotaScope) corev1.ResourceQuota {
+		return corev1.ResourceQuota{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       corev1.ResourceQuotaSpec{Hard: hard, Scopes: scopes},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		quotas   []corev1.ResourceQuota
+		usages   []QuotaUsage
+		defaults []ContainerDefault
+		want     []string
+	}{
+		{
+			name:     "defaults cover the quota",
+			quotas:   []corev1.ResourceQuota{quota("compute", corev1.ResourceList{corev1.ResourceRequestsCPU: resource.MustParse("4"), corev1.ResourceLimitsMemory: resource.MustParse("8Gi")})},
+			defaults: []ContainerDefault{{Resource: "cpu", DefaultLimit: "1"}, {Resource: "memory", DefaultLimit: "512Mi"}},
+		},
+		{
+			name:   "quota without defaults",
+			quotas: []corev1.ResourceQuota{quota("compute", corev1.ResourceList{corev1.ResourceLimitsMemory: resource.MustParse("8Gi"), corev1.ResourcePods: resource.MustParse("10")})},
+			want:   []string{"ResourceQuota compute tracks limits.memory, but no LimitRange sets a default memory limit, so pods with a container that does not set one are rejected"},
+		},
+		{
+			name:   "scoped quota",
+			quotas: []corev1.ResourceQuota{quota("best-effort", corev1.ResourceList{corev1.ResourceRequestsCPU: resource.MustParse("1")}, corev1.ResourceQuotaScopeNotBestEffort)},
+		},
+		{
+			name:   "exhausted quota",
+			usages: []QuotaUsage{{Name: "objects", Resources: []QuotaResourceUsage{{Resource: "pods", Hard: "10", Used: "10", Exhausted: true}}}},
+			want:   []string{"ResourceQuota objects is exhausted for pods (10 of 10); new objects that need more are rejected"},
+		},
+		{
+			name:     "several limit ranges",
+			defaults: []ContainerDefault{{Resource: "cpu", DefaultRequest: "100m", LimitRanges: []string{"a", "b"}}},
+			want:     []string{"2 LimitRanges (a, b) constrain cpu for containers; the strictest min and max apply, but which default is applied is not guaranteed"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := namespaceLimitFindings(tt.quotas, tt.usages, tt.defaults); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected findings %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestGetNamespaceLimits_FakeCluster(t *testing.T) {
+	t.Parallel()
+
+	handler := NewQuotaHandler(fakecluster.New(fakecluster.Config{
+		Objects: []runtime.Object{
+			&corev1.ResourceQuota{
+				ObjectMeta: metav1.ObjectMeta{Name: "compute", Namespace: "shop"},
+				Spec: corev1.ResourceQuotaSpec{Hard: corev1.ResourceList{
+					corev1.ResourceRequestsCPU:  resource.MustParse("4"),
+					corev1.ResourceLimitsMemory: resource.MustParse("8Gi"),
+				}},
+				Status: corev1.ResourceQuotaStatus{
+					Hard: corev1.ResourceList{corev1.ResourceRequestsCPU: resource.MustParse("4"), corev1.ResourceLimitsMemory: resource.MustParse("8Gi")},
+					Used: corev1.ResourceList{corev1.ResourceRequestsCPU: resource.MustParse("1"), corev1.ResourceLimitsMemory: resource.MustParse("2Gi")},
+				},
+			},
+			&corev1.LimitRange{
+				ObjectMeta: metav1.ObjectMeta{Name: "defaults", Namespace: "shop"},
+				Spec: corev1.LimitRangeSpec{Limits: []corev1.LimitRangeItem{{
+					Type:           corev1.LimitTypeContainer,
+					DefaultRequest: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")},
+				}}},
+			},
+			&corev1.LimitRange{
+				ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "batch"},
+			},
+		},
+	}), false)
+
+	result, isErr := callTool(t, handler.GetNamespaceLimits, map[string]any{"namespace": "shop"})
+	if isErr {
+		t.Fatalf("unexpected error: %v", result["error"])
+	}
+
+	var ranges []LimitRangeInfo
+	decodeInto(t, result["limit_ranges"], &ranges)
+	if len(ranges) != 1 || ranges[0].Name != "defaults" || ranges[0].Limits[0].DefaultRequest["cpu"] != "100m" {
+		t.Errorf("unexpected limit ranges %+v", ranges)
+	}
+
+	var quotas []QuotaUsage
+	decodeInto(t, result["quotas"], &quotas)
+	if len(quotas) != 1 || len(quotas[0].Resources) != 2 {
+		t.Errorf("unexpected quotas %+v", quotas)
+	}
+
+	var defaults []ContainerDefault
+	decodeInto(t, result["container_defaults"], &defaults)
+	if len(defaults) != 1 || defaults[0].Resource != "cpu" || defaults[0].DefaultRequest != "100m" {
+		t.Errorf("unexpected container defaults %+v", defaults)
+	}
+
+	var findings []string
+	decodeInto(t, result["findings"], &findings)
+	want := []string{"ResourceQuota compute tracks limits.memory, but no LimitRange sets a default memory limit, so pods with a container that does not set one are rejected"}
+	if !reflect.DeepEqual(findings, want) {
+		t.Errorf("expected findings %q, got %q", want, findings)
+	}
+
+	if _, isErr := callTool(t, handler.GetNamespaceLimits, map[string]any{}); !isErr {
+		t.Error("expected an error without a namespace")
+	}
+}
@@ -0,0 +1,526 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/connectivity"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/kubernetes"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/resourcefilter"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/response"
+)
+
+// defaultOverviewMaxItems is how many objects get_namespace_overview lists
+// per kind when no limit is given.
+const defaultOverviewMaxItems = 20
+
+// overviewSection is a kind get_namespace_overview reports, and how its
+// objects are read and summarized.
+type overviewSection struct {
+	kind string
+	gvr  schema.GroupVersionResource
+	read func(ctx context.Context, client kubernetes.ClusterReader, namespace string, now time.Time) ([]NamespaceOverviewItem, error)
+}
+
+// namespaceOverviewSections are the kinds get_namespace_overview reports,
+// in the order they are returned: workloads first, then what exposes and
+// configures them.
+var namespaceOverviewSections = []overviewSection{
+	{"Deployment", schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}, overviewDeployments},
+	{"StatefulSet", schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "statefulsets"}, overviewStatefulSets},
+	{"DaemonSet", schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "daemonsets"}, overviewDaemonSets},
+	{"Job", schema.GroupVersionResource{Group: "batch", Version: "v1", Resource: "jobs"}, overviewJobs},
+	{"CronJob", schema.GroupVersionResource{Group: "batch", Version: "v1", Resource: "cronjobs"}, overviewCronJobs},
+	{"Pod", relatedPodsGVR, overviewPods},
+	{"Service", relatedServicesGVR, overviewServices},
+	{"Ingress", relatedIngressesGVR, overviewIngresses},
+	{"PersistentVolumeClaim", relatedPVCsGVR, overviewClaims},
+	{"ConfigMap", relatedConfigMapsGVR, overviewMetadataOnly(relatedConfigMapsGVR)},
+	{"Secret", relatedSecretsGVR, overviewMetadataOnly(relatedSecretsGVR)},
+}
+
+// GetNamespaceOverviewParams defines the parameters for the get_namespace_overview MCP tool.
+type GetNamespaceOverviewParams struct {
+	// Namespace is the namespace to take the inventory of.
+	Namespace string `json:"namespace" required:"true" description:"Namespace to take the inventory of"`
+
+	// MaxItems caps the objects listed per kind; counts cover every object.
+	MaxItems int `json:"max_items,omitempty" minimum:"1" default:"20" description:"Maximum number of objects listed per kind, those needing attention first (defaults to 20). Counts always cover every object"`
+
+	// Context specifies which Kubernetes context to use for this operation.
+	Context string `json:"context,omitempty" description:"Kubernetes context to use (defaults to current context from kubeconfig)"`
+}
+
+// NamespaceOverviewItem is the brief status of an object in a namespace.
+type NamespaceOverviewItem struct {
+	Name string `json:"name"`
+
+	// Status is the object's state in a word or two, such as "Running",
+	// "Bound", or the reason a workload is unavailable.
+	Status string `json:"status,omitempty"`
+
+	// Ready counts the ready replicas or containers, as "2/3".
+	Ready string `json:"ready,omitempty"`
+
+	// Details adds what identifies the object at a glance, such as a
+	// Service's ports or a CronJob's schedule.
+	Details string `json:"details,omitempty"`
+	Age     string `json:"age"`
+
+	// Problem is set for objects that need attention.
+	Problem bool `json:"problem,omitempty"`
+}
+
+// NamespaceOverviewKind holds the objects of a kind in a namespace.
+type NamespaceOverviewKind struct {
+	Kind     string `json:"kind"`
+	Count    int    `json:"count"`
+	Problems int    `json:"problems,omitempty"`
+
+	// Items lists the objects, those with problems first, cut to max_items.
+	Items     []NamespaceOverviewItem `json:"items"`
+	Truncated bool                    `json:"truncated,omitempty"`
+}
+
+// GetNamespaceOverviewResult is the result of the get_namespace_overview MCP tool.
+type GetNamespaceOverviewResult struct {
+	Namespace string `json:"namespace"`
+
+	// Phase is the namespace's phase, such as "Active" or "Terminating".
+	Phase string `json:"phase,omitempty"`
+
+	// Total counts the objects of every kind, and Problems the ones that
+	// need attention.
+	Total    int                     `json:"total"`
+	Problems int                     `json:"problems"`
+	Kinds    []NamespaceOverviewKind `json:"kinds"`
+	Warnings []string                `json:"warnings,omitempty"`
+}
+
+// GetNamespaceOverview implements the get_namespace_overview MCP tool.
+// It answers "what lives in this namespace?" in one call, like "kubectl get
+// all" but including configuration and storage: the workloads, pods,
+// Services, Ingresses, PVCs, ConfigMaps, and Secrets of the namespace, each
+// counted and listed with a brief status. Secrets are read as metadata
+// only, so their data never leaves the cluster. Kinds that are disabled by
+// configuration or cannot be listed are reported as warnings.
+func (h *ResourceHandler) GetNamespaceOverview(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var params GetNamespaceOverviewParams
+	if err := bindParams(request, &params); err != nil {
+		return response.Errorf("failed to parse arguments: %s", err)
+	}
+
+	if params.Namespace == "" {
+		return response.Error("namespace is required")
+	}
+
+	maxItems := params.MaxItems
+	if maxItems <= 0 {
+		maxItems = defaultOverviewMaxItems
+	}
+
+	// Use the appropriate client based on context
+	client, err := h.client.ForContext(ctx, params.Context)
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to create client with context %s: %v", params.Context, err)
+	}
+
+	result := GetNamespaceOverviewResult{
+		Namespace: params.Namespace,
+		Kinds:     make([]NamespaceOverviewKind, 0, len(namespaceOverviewSections)),
+	}
+
+	namespace, err := client.GetNamespace(ctx, params.Namespace)
+	switch {
+	case err == nil:
+		result.Phase = string(namespace.Status.Phase)
+	case apierrors.IsNotFound(err):
+		return response.Errorf("namespace %q not found", params.Namespace)
+	case h.alwaysStart && connectivity.IsTransportError(err):
+		return response.Error(connectivity.ErrorMessage(err))
+	default:
+		result.Warnings = append(result.Warnings, fmt.Sprintf("failed to get namespace: %v", err))
+	}
+
+	now := time.Now()
+	for _, section := range namespaceOverviewSections {
+		if ctx.Err() != nil {
+			break
+		}
+		if h.resourceFilter != nil && h.resourceFilter.IsDisabled(section.gvr) {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("%s are disabled by configuration and were not listed", resourcefilter.FormatGVR(section.gvr)))
+			continue
+		}
+
+		items, err := section.read(ctx, client, params.Namespace, now)
+		if err != nil {
+			if h.alwaysStart && connectivity.IsTransportError(err) {
+				return response.Error(connectivity.ErrorMessage(err))
+			}
+			result.Warnings = append(result.Warnings, fmt.Sprintf("failed to list %s: %v", section.gvr.Resource, err))
+			continue
+		}
+
+		kind := NamespaceOverviewKind{Kind: section.kind, Count: len(items)}
+		for _, item := range items {
+			if item.Problem {
+				kind.Problems++
+			}
+		}
+
+		sort.SliceStable(items, func(i, j int) bool {
+			if items[i].Problem != items[j].Problem {
+				return items[i].Problem
+			}
+			return items[i].Name < items[j].Name
+		})
+		kind.Items = truncate(items, maxItems)
+		kind.Truncated = len(items) > maxItems
+
+		result.Total += kind.Count
+		result.Problems += kind.Problems
+		result.Kinds = append(result.Kinds, kind)
+	}
+
+	return response.JSON(result)
+}
+
+// overviewAge renders how long ago an object was created.
+func overviewAge(created metav1.Time, now time.Time) string {
+	return now.Sub(created.Time).Round(time.Second).String()
+}
+
+// overviewDeployments summarizes Deployments by their ready replicas and
+// the reason they are failing, as cluster_summary reports them.
+func overviewDeployments(ctx context.Context, client kubernetes.ClusterReader, namespace string, now time.Time) ([]NamespaceOverviewItem, error) {
+	list, err := client.ListDeployments(ctx, namespace, metav1.ListOptions{})
+	if err != nil {
+		return nil, err //nolint:wrapcheck // kubernetes API errors are self-descriptive
+	}
+
+	failing := make(map[string]FailingDeployment)
+	for _, deployment := range failingDeployments(list.Items) {
+		failing[deployment.Name] = deployment
+	}
+
+	items := make([]NamespaceOverviewItem, 0, len(list.Items))
+	for i := range list.Items {
+		deployment := &list.Items[i]
+		item := NamespaceOverviewItem{
+			Name:   deployment.Name,
+			Status: "Available",
+			Ready:  fmt.Sprintf("%d/%d", deployment.Status.ReadyReplicas, replicasOf(deployment.Spec.Replicas)),
+			Age:    overviewAge(deployment.CreationTimestamp, now),
+		}
+		if entry, ok := failing[deployment.Name]; ok {
+			item.Status = entry.Reason
+			item.Problem = true
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// overviewStatefulSets summarizes StatefulSets by their ready replicas.
+func overviewStatefulSets(ctx context.Context, client kubernetes.ClusterReader, namespace string, now time.Time) ([]NamespaceOverviewItem, error) {
+	list, err := client.ListStatefulSets(ctx, namespace, metav1.ListOptions{})
+	if err != nil {
+		return nil, err //nolint:wrapcheck // kubernetes API errors are self-descriptive
+	}
+
+	items := make([]NamespaceOverviewItem, 0, len(list.Items))
+	for i := range list.Items {
+		statefulSet := &list.Items[i]
+		desired := replicasOf(statefulSet.Spec.Replicas)
+		items = append(items, readinessItem(statefulSet.Name, statefulSet.Status.ReadyReplicas, desired, overviewAge(statefulSet.CreationTimestamp, now)))
+	}
+	return items, nil
+}
+
+// overviewDaemonSets summarizes DaemonSets by their ready pods out of the
+// nodes they should run on.
+func overviewDaemonSets(ctx context.Context, client kubernetes.ClusterReader, namespace string, now time.Time) ([]NamespaceOverviewItem, error) {
+	list, err := client.ListDaemonSets(ctx, namespace, metav1.ListOptions{})
+	if err != nil {
+		return nil, err //nolint:wrapcheck // kubernetes API errors are self-descriptive
+	}
+
+	items := make([]NamespaceOverviewItem, 0, len(list.Items))
+	for i := range list.Items {
+		daemonSet := &list.Items[i]
+		items = append(items, readinessItem(daemonSet.Name, daemonSet.Status.NumberReady, daemonSet.Status.DesiredNumberScheduled, overviewAge(daemonSet.CreationTimestamp, now)))
+	}
+	return items, nil
+}
+
+// readinessItem summarizes a workload that is healthy when all of its
+// desired replicas are ready.
+func readinessItem(name string, ready, desired int32, age string) NamespaceOverviewItem {
+	item := NamespaceOverviewItem{
+		Name:   name,
+		Status: "Ready",
+		Ready:  fmt.Sprintf("%d/%d", ready, desired),
+		Age:    age,
+	}
+	if ready < desired {
+		item.Status = "ReplicasNotReady"
+		item.Problem = true
+	}
+	return item
+}
+
+// replicasOf returns the desired replicas of a workload, which default to
+// one when unset.
+func replicasOf(replicas *int32) int32 {
+	if replicas == nil {
+		return 1
+	}
+	return *replicas
+}
+
+// overviewJobs summarizes Jobs by their succeeded completions and whether
+// they finished or failed.
+func overviewJobs(ctx context.Context, client kubernetes.ClusterReader, namespace string, now time.Time) ([]NamespaceOverviewItem, error) {
+	list, err := client.ListJobs(ctx, namespace, metav1.ListOptions{})
+	if err != nil {
+		return nil, err //nolint:wrapcheck // kubernetes API errors are self-descriptive
+	}
+
+	items := make([]NamespaceOverviewItem, 0, len(list.Items))
+	for i := range list.Items {
+		job := &list.Items[i]
+		item := NamespaceOverviewItem{
+			Name:   job.Name,
+			Status: "Running",
+			Ready:  fmt.Sprintf("%d/%d", job.Status.Succeeded, replicasOf(job.Spec.Completions)),
+			Age:    overviewAge(job.CreationTimestamp, now),
+		}
+		if job.Spec.Suspend != nil && *job.Spec.Suspend {
+			item.Status = "Suspended"
+		}
+		for _, condition := range job.Status.Conditions {
+			if condition.Status != corev1.ConditionTrue {
+				continue
+			}
+			switch condition.Type {
+			case batchv1.JobComplete:
+				item.Status = "Complete"
+			case batchv1.JobFailed:
+				item.Status = "Failed"
+				item.Details = condition.Reason
+				item.Problem = true
+			}
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// overviewCronJobs summarizes CronJobs by their schedule, running jobs, and
+// last run. CronJobs have no typed lister, so they are read through the
+// dynamic client.
+func overviewCronJobs(ctx context.Context, client kubernetes.ClusterReader, namespace string, now time.Time) ([]NamespaceOverviewItem, error) {
+	list, err := client.ListResources(ctx, schema.GroupVersionResource{Group: "batch", Version: "v1", Resource: "cronjobs"}, namespace, metav1.ListOptions{})
+	if err != nil {
+		return nil, err //nolint:wrapcheck // kubernetes API errors are self-descriptive
+	}
+
+	items := make([]NamespaceOverviewItem, 0, len(list.Items))
+	for i := range list.Items {
+		var cronJob batchv1.CronJob
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(list.Items[i].Object, &cronJob); err != nil {
+			items = append(items, NamespaceOverviewItem{Name: list.Items[i].GetName(), Age: overviewAge(list.Items[i].GetCreationTimestamp(), now)})
+			continue
+		}
+
+		item := NamespaceOverviewItem{
+			Name:    cronJob.Name,
+			Status:  "Scheduled",
+			Details: "schedule " + cronJob.Spec.Schedule,
+			Age:     overviewAge(cronJob.CreationTimestamp, now),
+		}
+		switch {
+		case cronJob.Spec.Suspend != nil && *cronJob.Spec.Suspend:
+			item.Status = "Suspended"
+		case len(cronJob.Status.Active) > 0:
+			item.Status = fmt.Sprintf("%d active", len(cronJob.Status.Active))
+		}
+		if last := cronJob.Status.LastScheduleTime; last != nil {
+			item.Details += ", last run " + overviewAge(*last, now) + " ago"
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// overviewPods summarizes pods the way "kubectl get pods" does. Pods that
+// neither completed nor run with every container ready are problems.
+func overviewPods(ctx context.Context, client kubernetes.ClusterReader, namespace string, now time.Time) ([]NamespaceOverviewItem, error) {
+	list, err := client.ListPods(ctx, namespace, metav1.ListOptions{})
+	if err != nil {
+		return nil, err //nolint:wrapcheck // kubernetes API errors are self-descriptive
+	}
+
+	items := make([]NamespaceOverviewItem, 0, len(list.Items))
+	for i := range list.Items {
+		pod := &list.Items[i]
+		rollup := rollUpPodStatus(pod, now)
+
+		item := NamespaceOverviewItem{
+			Name:   pod.Name,
+			Status: rollup.Status,
+			Ready:  rollup.Ready,
+			Age:    rollup.Age,
+		}
+		if rollup.Restarts > 0 {
+			item.Details = fmt.Sprintf("%d restarts", rollup.Restarts)
+		}
+
+		ready := 0
+		for _, status := range pod.Status.ContainerStatuses {
+			if status.Ready {
+				ready++
+			}
+		}
+		item.Problem = pod.Status.Phase != corev1.PodSucceeded && (rollup.Status != "Running" || ready < len(pod.Spec.Containers))
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// overviewServices summarizes Services by their type, cluster IP, and
+// ports. A LoadBalancer without an address yet is a problem.
+func overviewServices(ctx context.Context, client kubernetes.ClusterReader, namespace string, now time.Time) ([]NamespaceOverviewItem, error) {
+	list, err := client.ListServices(ctx, namespace, metav1.ListOptions{})
+	if err != nil {
+		return nil, err //nolint:wrapcheck // kubernetes API errors are self-descriptive
+	}
+
+	items := make([]NamespaceOverviewItem, 0, len(list.Items))
+	for i := range list.Items {
+		service := &list.Items[i]
+
+		ports := make([]string, 0, len(service.Spec.Ports))
+		for _, port := range service.Spec.Ports {
+			ports = append(ports, fmt.Sprintf("%d/%s", port.Port, port.Protocol))
+		}
+
+		details := []string{}
+		if service.Spec.ClusterIP != "" {
+			details = append(details, service.Spec.ClusterIP)
+		}
+		if len(ports) > 0 {
+			details = append(details, strings.Join(ports, ","))
+		}
+
+		item := NamespaceOverviewItem{
+			Name:    service.Name,
+			Status:  string(service.Spec.Type),
+			Details: strings.Join(details, " "),
+			Age:     overviewAge(service.CreationTimestamp, now),
+		}
+		if service.Spec.Type == corev1.ServiceTypeLoadBalancer && len(service.Status.LoadBalancer.Ingress) == 0 {
+			item.Status = "LoadBalancer (pending)"
+			item.Problem = true
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// overviewIngresses summarizes Ingresses by their class and hosts.
+func overviewIngresses(ctx context.Context, client kubernetes.ClusterReader, namespace string, now time.Time) ([]NamespaceOverviewItem, error) {
+	list, err := client.ListIngresses(ctx, namespace, metav1.ListOptions{})
+	if err != nil {
+		return nil, err //nolint:wrapcheck // kubernetes API errors are self-descriptive
+	}
+
+	items := make([]NamespaceOverviewItem, 0, len(list.Items))
+	for i := range list.Items {
+		ingress := &list.Items[i]
+
+		var hosts []string
+		for _, rule := range ingress.Spec.Rules {
+			if rule.Host != "" && !slices.Contains(hosts, rule.Host) {
+				hosts = append(hosts, rule.Host)
+			}
+		}
+
+		item := NamespaceOverviewItem{
+			Name:    ingress.Name,
+			Details: strings.Join(hosts, ","),
+			Age:     overviewAge(ingress.CreationTimestamp, now),
+		}
+		if ingress.Spec.IngressClassName != nil {
+			item.Status = *ingress.Spec.IngressClassName
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// overviewClaims summarizes PersistentVolumeClaims by their phase,
+// capacity, and storage class. Claims that are not bound are problems.
+func overviewClaims(ctx context.Context, client kubernetes.ClusterReader, namespace string, now time.Time) ([]NamespaceOverviewItem, error) {
+	list, err := client.ListPersistentVolumeClaims(ctx, namespace, metav1.ListOptions{})
+	if err != nil {
+		return nil, err //nolint:wrapcheck // kubernetes API errors are self-descriptive
+	}
+
+	items := make([]NamespaceOverviewItem, 0, len(list.Items))
+	for i := range list.Items {
+		claim := &list.Items[i]
+
+		var details []string
+		if capacity, ok := claim.Status.Capacity[corev1.ResourceStorage]; ok {
+			details = append(details, capacity.String())
+		}
+		if claim.Spec.StorageClassName != nil {
+			details = append(details, *claim.Spec.StorageClassName)
+		}
+
+		items = append(items, NamespaceOverviewItem{
+			Name:    claim.Name,
+			Status:  string(claim.Status.Phase),
+			Details: strings.Join(details, " "),
+			Age:     overviewAge(claim.CreationTimestamp, now),
+			Problem: claim.Status.Phase != corev1.ClaimBound,
+		})
+	}
+	return items, nil
+}
+
+// overviewMetadataOnly lists the objects of gvr through metadata-only lists,
+// for kinds whose contents are not summarized, such as Secrets.
+func overviewMetadataOnly(gvr schema.GroupVersionResource) func(ctx context.Context, client kubernetes.ClusterReader, namespace string, now time.Time) ([]NamespaceOverviewItem, error) {
+	return func(ctx context.Context, client kubernetes.ClusterReader, namespace string, now time.Time) ([]NamespaceOverviewItem, error) {
+		list, err := client.ListResourceMetadata(ctx, gvr, namespace, metav1.ListOptions{})
+		if err != nil {
+			return nil, err //nolint:wrapcheck // kubernetes API errors are self-descriptive
+		}
+
+		items := make([]NamespaceOverviewItem, 0, len(list.Items))
+		for i := range list.Items {
+			items = append(items, NamespaceOverviewItem{
+				Name: list.Items[i].Name,
+				Age:  overviewAge(list.Items[i].CreationTimestamp, now),
+			})
+		}
+		return items, nil
+	}
+}
@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"reflect"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/fakecluster"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/resourcefilter"
+)
+
+func TestGetNamespaceOverview(t *testing.T) {
+	t.Parallel()
+
+	two, three := int32(2), int32(3)
+	meta := func(name string) metav1.ObjectMeta {
+		return metav1.ObjectMeta{Name: name, Namespace: "shop"}
+	}
+	client := fakecluster.New(fakecluster.Config{
+		Objects: []runtime.Object{
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "shop"}, Status: corev1.NamespaceStatus{Phase: corev1.NamespaceActive}},
+			&appsv1.Deployment{
+				ObjectMeta: meta("web"),
+				Spec:       appsv1.DeploymentSpec{Replicas: &two},
+				Status:     appsv1.DeploymentStatus{ReadyReplicas: 2, AvailableReplicas: 2},
+			},
+			&appsv1.Deployment{
+				ObjectMeta: meta("api"),
+				Spec:       appsv1.DeploymentSpec{Replicas: &three},
+				Status:     appsv1.DeploymentStatus{ReadyReplicas: 1},
+			},
+			&batchv1.Job{
+				ObjectMeta: meta("migrate"),
+				Status: batchv1.JobStatus{Conditions: []batchv1.JobCondition{
+					{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Reason: "BackoffLimitExceeded"},
+				}},
+			},
+			&corev1.Pod{
+				ObjectMeta: meta("web-1"),
+				Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "app"}}},
+				Status:     corev1.PodStatus{Phase: corev1.PodRunning, ContainerStatuses: []corev1.ContainerStatus{{Name: "app", Ready: true}}},
+			},
+			&corev1.Pod{
+				ObjectMeta: meta("api-1"),
+				Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "app"}}},
+				Status: corev1.PodStatus{Phase: corev1.PodRunning, ContainerStatuses: []corev1.ContainerStatus{{
+					Name:         "app",
+					RestartCount: 4,
+					State:        corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+				}}},
+			},
+			&corev1.Service{
+				ObjectMeta: meta("web"),
+				Spec: corev1.ServiceSpec{
+					Type:      corev1.ServiceTypeClusterIP,
+					ClusterIP: "10.0.0.10",
+					Ports:     []corev1.ServicePort{{Port: 80, Protocol: corev1.ProtocolTCP}},
+				},
+			},
+			&corev1.PersistentVolumeClaim{ObjectMeta: meta("data"), Status: corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimPending}},
+			&corev1.ConfigMap{ObjectMeta: meta("settings")},
+			&corev1.Secret{ObjectMeta: meta("keys")},
+			&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "invoicer", Namespace: "billing"}},
+		},
+	})
+
+	filter, err := resourcefilter.NewFilter("secrets", client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler := NewResourceHandler(client, filter, false)
+
+	result, isErr := callTool(t, handler.GetNamespaceOverview, map[string]any{"namespace": "shop", "max_items": 1})
+	if isErr {
+		t.Fatalf("unexpected error: %v", result["error"])
+	}
+
+	if result["phase"] != "Active" || result["total"] != float64(8) || result["problems"] != float64(4) {
+		t.Errorf("expected 8 objects with 4 problems in an active namespace, got %v", result)
+	}
+
+	kinds := make(map[string]map[string]any)
+	for _, raw := range result["kinds"].([]any) {
+		kind := raw.(map[string]any)
+		kinds[kind["kind"].(string)] = kind
+	}
+	if _, ok := kinds["Secret"]; ok {
+		t.Error("expected disabled secrets to be left out")
+	}
+	if warnings, _ := result["warnings"].([]any); len(warnings) != 1 {
+		t.Errorf("expected a warning about the disabled secrets, got %v", result["warnings"])
+	}
+
+	// Problems come first, and lists are cut to max_items.
+	deployments := kinds["Deployment"]
+	wantDeployments := []any{map[string]any{"name": "api", "status": "ReplicasNotReady", "ready": "1/3", "age": deployments["items"].([]any)[0].(map[string]any)["age"], "problem": true}}
+	if !reflect.DeepEqual(deployments["items"], wantDeployments) || deployments["count"] != float64(2) || deployments["truncated"] != true {
+		t.Errorf("expected the failing deployment first out of 2, got %v", deployments)
+	}
+
+	tests := []struct {
+		kind   string
+		status string
+		field  string
+		value  string
+	}{
+		{"Pod", "CrashLoopBackOff", "details", "4 restarts"},
+		{"Job", "Failed", "details", "BackoffLimitExceeded"},
+		{"Service", "ClusterIP", "details", "10.0.0.10 80/TCP"},
+		{"PersistentVolumeClaim", "Pending", "name", "data"},
+		{"ConfigMap", "", "name", "settings"},
+	}
+	for _, tt := range tests {
+		kind, ok := kinds[tt.kind]
+		if !ok {
+			t.Errorf("expected %s to be reported", tt.kind)
+			continue
+		}
+		item := kind["items"].([]any)[0].(map[string]any)
+		if status, _ := item["status"].(string); status != tt.status || item[tt.field] != tt.value {
+			t.Errorf("expected %s with status %q and %s %q, got %v", tt.kind, tt.status, tt.field, tt.value, item)
+		}
+	}
+
+	if result, isErr := callTool(t, handler.GetNamespaceOverview, map[string]any{"namespace": "missing"}); !isErr {
+		t.Errorf("expected a missing namespace to be rejected, got %v", result)
+	}
+}
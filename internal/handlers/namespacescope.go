@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// namespaceFetchConcurrency bounds how many namespaces are queried at once
+// when a tool is given several of them.
+const namespaceFetchConcurrency = 8
+
+// errNamespaceScope is returned when a namespace and all_namespaces are both
+// given.
+var errNamespaceScope = errors.New("namespace and all_namespaces cannot be used together: leave namespace empty to list across every namespace")
+
+// splitNamespaces parses a namespace argument that may name several
+// namespaces separated by commas. Blank entries and repeats are dropped, and
+// the order is kept.
+func splitNamespaces(namespace string) []string {
+	var namespaces []string
+	seen := make(map[string]bool)
+
+	for _, name := range strings.Split(namespace, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		namespaces = append(namespaces, name)
+	}
+
+	return namespaces
+}
+
+// fetchPerNamespace calls fetch for every namespace, a few at a time, and
+// returns the results in the order of namespaces. The first error stops the
+// remaining calls and is returned naming its namespace, and a cancelled
+// call returns the context's error.
+func fetchPerNamespace[T any](ctx context.Context, namespaces []string, fetch func(ctx context.Context, namespace string) (T, error)) ([]T, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([]T, len(namespaces))
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+	sem := make(chan struct{}, namespaceFetchConcurrency)
+
+	for i, namespace := range namespaces {
+		if !acquireSlot(ctx, sem) {
+			break
+		}
+		wg.Add(1)
+
+		go func(i int, namespace string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			result, err := fetch(ctx, namespace)
+			if err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = fmt.Errorf("namespace %q: %w", namespace, err)
+					cancel()
+				}
+				mu.Unlock()
+				return
+			}
+			results[i] = result
+		}(i, namespace)
+	}
+
+	wg.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err //nolint:wrapcheck // cancellation is reported as is
+	}
+	return results, nil
+}
+
+// acquireSlot takes a slot of sem for a fan-out, waiting for one to free up.
+// It returns false once ctx is done, so a cancelled call stops starting
+// requests for the objects left.
+func acquireSlot(ctx context.Context, sem chan struct{}) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	select {
+	case sem <- struct{}{}:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync/atomic"
+	"testing"
+)
+
+func TestSplitNamespaces(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		namespace string
+		want      []string
+	}{
+		{namespace: "", want: nil},
+		{namespace: "shop", want: []string{"shop"}},
+		{namespace: " shop , billing,,shop ", want: []string{"shop", "billing"}},
+	}
+
+	for _, tt := range tests {
+		if got := splitNamespaces(tt.namespace); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("splitNamespaces(%q) = %v, want %v", tt.namespace, got, tt.want)
+		}
+	}
+}
+
+func TestFetchPerNamespace(t *testing.T) {
+	t.Parallel()
+
+	namespaces := []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j"}
+	results, err := fetchPerNamespace(context.Background(), namespaces, func(_ context.Context, namespace string) (string, error) {
+		return namespace + "!", nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, namespace := range namespaces {
+		if results[i] != namespace+"!" {
+			t.Errorf("expected results in the order of namespaces, got %v", results)
+			break
+		}
+	}
+
+	forbidden := errors.New("forbidden")
+	_, err = fetchPerNamespace(context.Background(), namespaces, func(ctx context.Context, namespace string) (string, error) {
+		if namespace == "c" {
+			return "", forbidden
+		}
+		return namespace, nil
+	})
+	if !errors.Is(err, forbidden) || err.Error() != `namespace "c": forbidden` {
+		t.Errorf("expected the error to name its namespace, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var calls atomic.Int32
+	_, err = fetchPerNamespace(ctx, namespaces, func(_ context.Context, namespace string) (string, error) {
+		calls.Add(1)
+		return namespace, nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected a cancelled call to return the context error, got %v", err)
+	}
+	if n := calls.Load(); n != 0 {
+		t.Errorf("expected a cancelled call to fetch nothing, got %d calls", n)
+	}
+}
@@ -0,0 +1,432 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/connectivity"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/response"
+)
+
+// AnalyzeNetworkPolicyParams defines the parameters for the analyze_network_policy MCP tool.
+type AnalyzeNetworkPolicyParams struct {
+	// SourceNamespace is the namespace of the client.
+	SourceNamespace string `json:"source_namespace" required:"true" description:"Namespace of the source (client) pod"`
+
+	// SourcePod names the client pod.
+	SourcePod string `json:"source_pod,omitempty" description:"Name of the source pod (set this or source_labels)"`
+
+	// SourceLabels describes the client by its labels instead of a pod.
+	SourceLabels string `json:"source_labels,omitempty" description:"Labels of the source pod as key=value pairs separated by commas, used instead of source_pod (e.g., 'app=frontend')"`
+
+	// DestinationNamespace is the namespace of the server.
+	DestinationNamespace string `json:"destination_namespace" required:"true" description:"Namespace of the destination (server) pod"`
+
+	// DestinationPod names the server pod.
+	DestinationPod string `json:"destination_pod,omitempty" description:"Name of the destination pod (set this or destination_labels)"`
+
+	// DestinationLabels describes the server by its labels instead of a pod.
+	DestinationLabels string `json:"destination_labels,omitempty" description:"Labels of the destination pod as key=value pairs separated by commas, used instead of destination_pod (e.g., 'app=api')"`
+
+	// Port is the destination port of the traffic.
+	Port int `json:"port" required:"true" minimum:"1" maximum:"65535" description:"Destination port of the traffic"`
+
+	// Protocol is the protocol of the traffic.
+	Protocol string `json:"protocol,omitempty" enum:"TCP,UDP,SCTP" default:"TCP" description:"Protocol of the traffic: TCP, UDP, or SCTP (defaults to TCP)"`
+
+	// Context specifies which Kubernetes context to use for this operation.
+	Context string `json:"context,omitempty" description:"Kubernetes context to use (defaults to current context from kubeconfig)"`
+}
+
+// NetworkPolicyEndpoint is one side of the traffic analyze_network_policy
+// evaluates.
+type NetworkPolicyEndpoint struct {
+	Namespace string            `json:"namespace"`
+	Pod       string            `json:"pod,omitempty"`
+	Labels    map[string]string `json:"labels"`
+	IP        string            `json:"ip,omitempty"`
+
+	namespaceLabels map[string]string
+	ports           []corev1.ContainerPort
+}
+
+// NetworkPolicyVerdict is the outcome of the NetworkPolicies for one
+// direction of the traffic: egress from the source or ingress to the
+// destination.
+type NetworkPolicyVerdict struct {
+	// Isolated is true when at least one policy selects the pod for this
+	// direction. Pods that are not isolated allow all traffic.
+	Isolated bool `json:"isolated"`
+	Allowed  bool `json:"allowed"`
+
+	// Policies are the policies that select the pod for this direction.
+	Policies []string `json:"policies"`
+
+	// AllowedBy are the rules that allow the traffic, as "policy rule N".
+	AllowedBy []string `json:"allowed_by,omitempty"`
+	Reason    string   `json:"reason"`
+}
+
+// AnalyzeNetworkPolicyResult is the result of the analyze_network_policy MCP
+// tool.
+type AnalyzeNetworkPolicyResult struct {
+	Source      *NetworkPolicyEndpoint `json:"source"`
+	Destination *NetworkPolicyEndpoint `json:"destination"`
+	Port        int32                  `json:"port"`
+	Protocol    string                 `json:"protocol"`
+	Allowed     bool                   `json:"allowed"`
+	Summary     string                 `json:"summary"`
+	Egress      NetworkPolicyVerdict   `json:"egress"`
+	Ingress     NetworkPolicyVerdict   `json:"ingress"`
+	Notes       []string               `json:"notes"`
+	Warnings    []string               `json:"warnings,omitempty"`
+}
+
+// AnalyzeNetworkPolicy implements the analyze_network_policy MCP tool.
+// It evaluates whether traffic from a source pod to a destination pod on a
+// port is allowed by the NetworkPolicies of both namespaces, following the
+// NetworkPolicy semantics: traffic must be allowed both as egress from the
+// source and as ingress to the destination, and a pod only restricts a
+// direction when a policy selects it for that direction. Either side can be
+// an existing pod or a set of labels; named ports and ipBlock peers can only
+// be evaluated against an existing pod.
+func (h *NetworkHandler) AnalyzeNetworkPolicy(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var params AnalyzeNetworkPolicyParams
+	if err := bindParams(request, &params); err != nil {
+		return response.Errorf("failed to parse arguments: %s", err)
+	}
+
+	if (params.SourcePod == "") == (params.SourceLabels == "") {
+		return response.Errorf("set exactly one of source_pod or source_labels")
+	}
+	if (params.DestinationPod == "") == (params.DestinationLabels == "") {
+		return response.Errorf("set exactly one of destination_pod or destination_labels")
+	}
+
+	protocol := portProtocol(corev1.Protocol(params.Protocol))
+
+	// Use the appropriate client based on context
+	client, err := h.client.ForContext(ctx, params.Context)
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to create client with context %s: %v", params.Context, err)
+	}
+
+	var warnings []string
+
+	endpoint := func(namespace, podName, podLabels string) (*NetworkPolicyEndpoint, error) {
+		result := &NetworkPolicyEndpoint{Namespace: namespace}
+
+		if podName != "" {
+			pod, err := client.GetPod(ctx, namespace, podName)
+			if err != nil {
+				return nil, err
+			}
+			result.Pod = pod.Name
+			result.Labels = pod.Labels
+			result.IP = pod.Status.PodIP
+			for _, container := range pod.Spec.Containers {
+				result.ports = append(result.ports, container.Ports...)
+			}
+			if pod.Spec.HostNetwork {
+				warnings = append(warnings, fmt.Sprintf("pod %s/%s uses the host network, which NetworkPolicies usually do not apply to", namespace, pod.Name))
+			}
+		} else {
+			set, err := labels.ConvertSelectorToLabelsMap(podLabels)
+			if err != nil {
+				return nil, fmt.Errorf("invalid labels %q: %w", podLabels, err)
+			}
+			result.Labels = set
+		}
+		if result.Labels == nil {
+			result.Labels = map[string]string{}
+		}
+
+		// The API server sets this label on every namespace, so it is a
+		// safe fallback when the namespace cannot be read.
+		result.namespaceLabels = map[string]string{corev1.LabelMetadataName: namespace}
+		ns, err := client.GetNamespace(ctx, namespace)
+		if err != nil {
+			if connectivity.IsTransportError(err) {
+				return nil, err
+			}
+			warnings = append(warnings, fmt.Sprintf("failed to get namespace %s, only its name is used to match namespace selectors: %v", namespace, err))
+		} else {
+			for key, value := range ns.Labels {
+				result.namespaceLabels[key] = value
+			}
+		}
+
+		return result, nil
+	}
+
+	source, err := endpoint(params.SourceNamespace, params.SourcePod, params.SourceLabels)
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to resolve source: %v", err)
+	}
+
+	destination, err := endpoint(params.DestinationNamespace, params.DestinationPod, params.DestinationLabels)
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to resolve destination: %v", err)
+	}
+
+	sourcePolicies, err := client.ListNetworkPolicies(ctx, source.Namespace, metav1.ListOptions{})
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to list network policies: %v", err)
+	}
+
+	destinationPolicies := sourcePolicies
+	if destination.Namespace != source.Namespace {
+		destinationPolicies, err = client.ListNetworkPolicies(ctx, destination.Namespace, metav1.ListOptions{})
+		if err != nil {
+			if h.alwaysStart && connectivity.IsTransportError(err) {
+				return response.Error(connectivity.ErrorMessage(err))
+			}
+			return response.Errorf("failed to list network policies: %v", err)
+		}
+	}
+
+	port := int32(params.Port)
+	evaluation := &policyEvaluation{port: port, protocol: protocol}
+	egress := evaluation.verdict(sourcePolicies.Items, networkingv1.PolicyTypeEgress, source, destination)
+	ingress := evaluation.verdict(destinationPolicies.Items, networkingv1.PolicyTypeIngress, destination, source)
+
+	allowed := egress.Allowed && ingress.Allowed
+
+	var summary string
+	switch {
+	case allowed:
+		summary = fmt.Sprintf("traffic on %d/%s is allowed", port, protocol)
+	case !egress.Allowed && !ingress.Allowed:
+		summary = fmt.Sprintf("traffic on %d/%s is denied by both the source's egress and the destination's ingress policies", port, protocol)
+	case !egress.Allowed:
+		summary = fmt.Sprintf("traffic on %d/%s is denied by the source's egress policies", port, protocol)
+	default:
+		summary = fmt.Sprintf("traffic on %d/%s is denied by the destination's ingress policies", port, protocol)
+	}
+
+	evaluation.note("NetworkPolicies are only enforced when the cluster's network plugin supports them")
+
+	return response.JSON(AnalyzeNetworkPolicyResult{
+		Source:      source,
+		Destination: destination,
+		Port:        port,
+		Protocol:    protocol,
+		Allowed:     allowed,
+		Summary:     summary,
+		Egress:      egress,
+		Ingress:     ingress,
+		Notes:       evaluation.notes,
+		Warnings:    warnings,
+	})
+}
+
+// policyEvaluation evaluates NetworkPolicies for traffic on a port and
+// collects notes about what could not be evaluated.
+type policyEvaluation struct {
+	port     int32
+	protocol string
+	notes    []string
+}
+
+// note records a note once.
+func (e *policyEvaluation) note(format string, args ...interface{}) {
+	message := fmt.Sprintf(format, args...)
+	if !containsString(e.notes, message) {
+		e.notes = append(e.notes, message)
+	}
+}
+
+// verdict evaluates the policies in the namespace of subject for one
+// direction. For egress, subject is the source and peer the destination; for
+// ingress, subject is the destination and peer the source.
+func (e *policyEvaluation) verdict(policies []networkingv1.NetworkPolicy, direction networkingv1.PolicyType, subject, peer *NetworkPolicyEndpoint) NetworkPolicyVerdict {
+	verdict := NetworkPolicyVerdict{Policies: make([]string, 0)}
+
+	destination := peer
+	if direction == networkingv1.PolicyTypeIngress {
+		destination = subject
+	}
+
+	for i := range policies {
+		policy := &policies[i]
+		if policy.Namespace != subject.Namespace || !policyHasType(policy, direction) {
+			continue
+		}
+		if !e.selects(policy.Name, &policy.Spec.PodSelector, subject.Labels) {
+			continue
+		}
+
+		verdict.Policies = append(verdict.Policies, policy.Name)
+
+		if direction == networkingv1.PolicyTypeIngress {
+			for j, rule := range policy.Spec.Ingress {
+				if e.peersMatch(policy, rule.From, peer) && e.portsMatch(policy.Name, rule.Ports, destination) {
+					verdict.AllowedBy = append(verdict.AllowedBy, fmt.Sprintf("%s rule %d", policy.Name, j+1))
+				}
+			}
+		} else {
+			for j, rule := range policy.Spec.Egress {
+				if e.peersMatch(policy, rule.To, peer) && e.portsMatch(policy.Name, rule.Ports, destination) {
+					verdict.AllowedBy = append(verdict.AllowedBy, fmt.Sprintf("%s rule %d", policy.Name, j+1))
+				}
+			}
+		}
+	}
+
+	label := strings.ToLower(string(direction))
+	verdict.Isolated = len(verdict.Policies) > 0
+	verdict.Allowed = !verdict.Isolated || len(verdict.AllowedBy) > 0
+
+	switch {
+	case !verdict.Isolated:
+		verdict.Reason = fmt.Sprintf("no policy selects the pod for %s, so all %s traffic is allowed", label, label)
+	case verdict.Allowed:
+		verdict.Reason = fmt.Sprintf("allowed by %s", strings.Join(verdict.AllowedBy, ", "))
+	default:
+		verdict.Reason = fmt.Sprintf("the pod is selected for %s by %s, and none of their rules allow this traffic", label, strings.Join(verdict.Policies, ", "))
+	}
+
+	return verdict
+}
+
+// policyHasType reports whether a policy applies to a direction. Without
+// policyTypes, every policy applies to ingress and those with egress rules
+// also to egress.
+func policyHasType(policy *networkingv1.NetworkPolicy, direction networkingv1.PolicyType) bool {
+	if len(policy.Spec.PolicyTypes) == 0 {
+		return direction == networkingv1.PolicyTypeIngress || len(policy.Spec.Egress) > 0
+	}
+	for _, policyType := range policy.Spec.PolicyTypes {
+		if policyType == direction {
+			return true
+		}
+	}
+	return false
+}
+
+// selects reports whether a label selector of a policy matches a set of
+// labels. Invalid selectors match nothing.
+func (e *policyEvaluation) selects(policy string, selector *metav1.LabelSelector, set map[string]string) bool {
+	parsed, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		e.note("policy %s has an invalid selector, which was treated as matching nothing: %v", policy, err)
+		return false
+	}
+	return parsed.Matches(labels.Set(set))
+}
+
+// peersMatch reports whether the peers of a rule include the endpoint. A
+// rule without peers matches every endpoint.
+func (e *policyEvaluation) peersMatch(policy *networkingv1.NetworkPolicy, peers []networkingv1.NetworkPolicyPeer, endpoint *NetworkPolicyEndpoint) bool {
+	if len(peers) == 0 {
+		return true
+	}
+
+	for _, peer := range peers {
+		if peer.IPBlock != nil {
+			if e.ipBlockMatches(policy.Name, peer.IPBlock, endpoint) {
+				return true
+			}
+			continue
+		}
+
+		if peer.NamespaceSelector != nil {
+			if !e.selects(policy.Name, peer.NamespaceSelector, endpoint.namespaceLabels) {
+				continue
+			}
+		} else if endpoint.Namespace != policy.Namespace {
+			continue
+		}
+
+		if peer.PodSelector == nil || e.selects(policy.Name, peer.PodSelector, endpoint.Labels) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ipBlockMatches reports whether an ipBlock peer includes the IP of the
+// endpoint.
+func (e *policyEvaluation) ipBlockMatches(policy string, block *networkingv1.IPBlock, endpoint *NetworkPolicyEndpoint) bool {
+	ip := net.ParseIP(endpoint.IP)
+	if ip == nil {
+		e.note("ipBlock %s in policy %s was not evaluated because the pod IP is unknown; pass a pod name instead of labels", block.CIDR, policy)
+		return false
+	}
+
+	e.note("ipBlock %s in policy %s was matched against the pod IP; whether ipBlock applies to pod traffic depends on the network plugin", block.CIDR, policy)
+
+	if _, cidr, err := net.ParseCIDR(block.CIDR); err != nil || !cidr.Contains(ip) {
+		return false
+	}
+	for _, except := range block.Except {
+		if _, cidr, err := net.ParseCIDR(except); err == nil && cidr.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// portsMatch reports whether the ports of a rule include the evaluated port
+// on the destination. A rule without ports matches every port. Named ports
+// are resolved against the container ports of the destination pod.
+func (e *policyEvaluation) portsMatch(policy string, ports []networkingv1.NetworkPolicyPort, destination *NetworkPolicyEndpoint) bool {
+	if len(ports) == 0 {
+		return true
+	}
+
+	for _, port := range ports {
+		var protocol corev1.Protocol
+		if port.Protocol != nil {
+			protocol = *port.Protocol
+		}
+		if portProtocol(protocol) != e.protocol {
+			continue
+		}
+
+		switch {
+		case port.Port == nil:
+			return true
+		case port.Port.Type == intstr.Int:
+			end := port.Port.IntVal
+			if port.EndPort != nil {
+				end = *port.EndPort
+			}
+			if e.port >= port.Port.IntVal && e.port <= end {
+				return true
+			}
+		case destination.Pod == "":
+			e.note("named port %q in policy %s was not evaluated because the destination is described by labels; pass a pod name instead", port.Port.StrVal, policy)
+		default:
+			for _, containerPort := range destination.ports {
+				if containerPort.Name == port.Port.StrVal && containerPort.ContainerPort == e.port && portProtocol(containerPort.Protocol) == e.protocol {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,249 @@
+package handlers
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/fakecluster"
+)
+
+func TestPolicyEvaluationVerdict(t *testing.T) {
+	t.Parallel()
+
+	tcpPort := func(port intstr.IntOrString) []networkingv1.NetworkPolicyPort {
+		return []networkingv1.NetworkPolicyPort{{Port: &port}}
+	}
+	endPort := int32(8090)
+
+	api := &NetworkPolicyEndpoint{
+		Namespace:       "shop",
+		Pod:             "api-1",
+		Labels:          map[string]string{"app": "api"},
+		IP:              "10.244.1.5",
+		namespaceLabels: map[string]string{corev1.LabelMetadataName: "shop"},
+		ports:           []corev1.ContainerPort{{Name: "http", ContainerPort: 8080}},
+	}
+	frontend := &NetworkPolicyEndpoint{
+		Namespace:       "web",
+		Labels:          map[string]string{"app": "frontend"},
+		namespaceLabels: map[string]string{corev1.LabelMetadataName: "web", "team": "web"},
+	}
+
+	policy := func(name string, rules ...networkingv1.NetworkPolicyIngressRule) networkingv1.NetworkPolicy {
+		return networkingv1.NetworkPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "shop"},
+			Spec: networkingv1.NetworkPolicySpec{
+				PodSelector: metav1.LabelSelector{MatchLabels: map[string]string{"app": "api"}},
+				Ingress:     rules,
+			},
+		}
+	}
+
+	tests := []struct {
+		name          string
+		policies      []networkingv1.NetworkPolicy
+		wantIsolated  bool
+		wantAllowed   bool
+		wantAllowedBy []string
+	}{
+		{
+			name:        "no policies",
+			wantAllowed: true,
+		},
+		{
+			name:         "default deny",
+			policies:     []networkingv1.NetworkPolicy{policy("deny-all")},
+			wantIsolated: true,
+		},
+		{
+			name: "namespace selector and named port",
+			policies: []networkingv1.NetworkPolicy{
+				policy("deny-all"),
+				policy("from-web", networkingv1.NetworkPolicyIngressRule{
+					From:  []networkingv1.NetworkPolicyPeer{{NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "web"}}}},
+					Ports: tcpPort(intstr.FromString("http")),
+				}),
+			},
+			wantIsolated:  true,
+			wantAllowed:   true,
+			wantAllowedBy: []string{"from-web rule 1"},
+		},
+		{
+			name: "pod selector without namespace selector only matches the policy namespace",
+			policies: []networkingv1.NetworkPolicy{
+				policy("same-namespace", networkingv1.NetworkPolicyIngressRule{
+					From: []networkingv1.NetworkPolicyPeer{{PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "frontend"}}}},
+				}),
+			},
+			wantIsolated: true,
+		},
+		{
+			name: "port range",
+			policies: []networkingv1.NetworkPolicy{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "range", Namespace: "shop"},
+					Spec: networkingv1.NetworkPolicySpec{
+						Ingress: []networkingv1.NetworkPolicyIngressRule{
+							{Ports: []networkingv1.NetworkPolicyPort{{Port: &intstr.IntOrString{IntVal: 8000}, EndPort: &endPort}}},
+						},
+					},
+				},
+			},
+			wantIsolated:  true,
+			wantAllowed:   true,
+			wantAllowedBy: []string{"range rule 1"},
+		},
+		{
+			name: "wrong port",
+			policies: []networkingv1.NetworkPolicy{
+				policy("other-port", networkingv1.NetworkPolicyIngressRule{Ports: tcpPort(intstr.FromInt32(9090))}),
+			},
+			wantIsolated: true,
+		},
+		{
+			name: "egress only policy does not isolate ingress",
+			policies: []networkingv1.NetworkPolicy{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "egress", Namespace: "shop"},
+					Spec:       networkingv1.NetworkPolicySpec{PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeEgress}},
+				},
+			},
+			wantAllowed: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			evaluation := &policyEvaluation{port: 8080, protocol: "TCP"}
+			got := evaluation.verdict(tt.policies, networkingv1.PolicyTypeIngress, api, frontend)
+			if got.Isolated != tt.wantIsolated || got.Allowed != tt.wantAllowed {
+				t.Errorf("expected isolated=%v allowed=%v, got %+v", tt.wantIsolated, tt.wantAllowed, got)
+			}
+			if !reflect.DeepEqual(got.AllowedBy, tt.wantAllowedBy) {
+				t.Errorf("expected allowed by %v, got %v", tt.wantAllowedBy, got.AllowedBy)
+			}
+		})
+	}
+}
+
+func TestIPBlockMatches(t *testing.T) {
+	t.Parallel()
+
+	block := &networkingv1.IPBlock{CIDR: "10.0.0.0/8", Except: []string{"10.1.0.0/16"}}
+
+	tests := []struct {
+		ip   string
+		want bool
+	}{
+		{ip: "10.2.3.4", want: true},
+		{ip: "10.1.3.4"},
+		{ip: "192.168.1.1"},
+		{ip: ""},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.ip, func(t *testing.T) {
+			t.Parallel()
+
+			evaluation := &policyEvaluation{}
+			if got := evaluation.ipBlockMatches("policy", block, &NetworkPolicyEndpoint{IP: tt.ip}); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestAnalyzeNetworkPolicy_FakeCluster(t *testing.T) {
+	t.Parallel()
+
+	port := intstr.FromInt32(5432)
+	handler := NewNetworkHandler(fakecluster.New(fakecluster.Config{
+		Objects: []runtime.Object{
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "shop", Labels: map[string]string{corev1.LabelMetadataName: "shop"}}},
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "data", Labels: map[string]string{corev1.LabelMetadataName: "data"}}},
+			&corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "api-1", Namespace: "shop", Labels: map[string]string{"app": "api"}},
+				Status:     corev1.PodStatus{PodIP: "10.244.1.5"},
+			},
+			&corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "db-0", Namespace: "data", Labels: map[string]string{"app": "db"}},
+				Status:     corev1.PodStatus{PodIP: "10.244.2.9"},
+			},
+			&networkingv1.NetworkPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "api-egress", Namespace: "shop"},
+				Spec: networkingv1.NetworkPolicySpec{
+					PodSelector: metav1.LabelSelector{MatchLabels: map[string]string{"app": "api"}},
+					PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeEgress},
+					Egress: []networkingv1.NetworkPolicyEgressRule{{
+						To:    []networkingv1.NetworkPolicyPeer{{NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{corev1.LabelMetadataName: "data"}}}},
+						Ports: []networkingv1.NetworkPolicyPort{{Port: &port}},
+					}},
+				},
+			},
+			&networkingv1.NetworkPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "db-ingress", Namespace: "data"},
+				Spec: networkingv1.NetworkPolicySpec{
+					PodSelector: metav1.LabelSelector{MatchLabels: map[string]string{"app": "db"}},
+					Ingress: []networkingv1.NetworkPolicyIngressRule{{
+						From: []networkingv1.NetworkPolicyPeer{{
+							NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{corev1.LabelMetadataName: "shop"}},
+							PodSelector:       &metav1.LabelSelector{MatchLabels: map[string]string{"app": "worker"}},
+						}},
+					}},
+				},
+			},
+		},
+	}), false)
+
+	args := map[string]any{
+		"source_namespace":      "shop",
+		"source_pod":            "api-1",
+		"destination_namespace": "data",
+		"destination_pod":       "db-0",
+		"port":                  5432,
+	}
+
+	result, isErr := callTool(t, handler.AnalyzeNetworkPolicy, args)
+	if isErr {
+		t.Fatalf("unexpected error: %v", result["error"])
+	}
+
+	if result["allowed"] != false {
+		t.Errorf("expected the traffic to be denied, got %v", result["summary"])
+	}
+
+	var egress, ingress NetworkPolicyVerdict
+	decodeInto(t, result["egress"], &egress)
+	decodeInto(t, result["ingress"], &ingress)
+	if !egress.Allowed || !reflect.DeepEqual(egress.AllowedBy, []string{"api-egress rule 1"}) {
+		t.Errorf("expected egress allowed by api-egress, got %+v", egress)
+	}
+	if ingress.Allowed || !reflect.DeepEqual(ingress.Policies, []string{"db-ingress"}) {
+		t.Errorf("expected ingress denied by db-ingress, got %+v", ingress)
+	}
+
+	args["source_pod"] = ""
+	args["source_labels"] = "app=worker"
+	result, isErr = callTool(t, handler.AnalyzeNetworkPolicy, args)
+	if isErr {
+		t.Fatalf("unexpected error: %v", result["error"])
+	}
+	if result["allowed"] != true {
+		t.Errorf("expected worker pods to be allowed, got %v", result["summary"])
+	}
+
+	args["source_pod"] = "api-1"
+	if result, isErr := callTool(t, handler.AnalyzeNetworkPolicy, args); !isErr {
+		t.Errorf("expected an error when both source_pod and source_labels are set, got %v", result)
+	}
+}
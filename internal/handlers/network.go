@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/kubernetes"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/toolschema"
+)
+
+// NetworkHandler provides MCP tools that inspect how workloads are exposed
+// on the network: Services, node and host ports, and Ingresses.
+type NetworkHandler struct {
+	client      kubernetes.ClusterReader
+	alwaysStart bool
+}
+
+// NewNetworkHandler creates a new NetworkHandler with the provided Kubernetes client.
+// alwaysStart mirrors the --always-start flag: when true, connectivity and auth errors
+// are intercepted and returned as structured tool errors so the LLM can surface them
+// to the user rather than treating them as retryable failures.
+func NewNetworkHandler(client kubernetes.ClusterReader, alwaysStart bool) *NetworkHandler {
+	return &NetworkHandler{
+		client:      client,
+		alwaysStart: alwaysStart,
+	}
+}
+
+// GetTools returns all network MCP tools provided by this handler.
+func (h *NetworkHandler) GetTools() []MCPTool {
+	return []MCPTool{
+		NewMCPTool(
+			mcp.NewTool("get_service_endpoints",
+				mcp.WithDescription("Resolve a Service to the pods behind it in one call: its type, cluster IP, selector, and port to target port mappings, its EndpointSlices with each endpoint's addresses, pod, node, and ready/serving/terminating conditions, and the pods its selector matches with their IPs, readiness, and whether they are in the endpoints. Findings explain why the Service may not route, such as a selector that matches no pods, no ready endpoints, or a named target port the pods do not declare."),
+				toolschema.Input[GetServiceEndpointsParams](),
+				toolschema.Output[GetServiceEndpointsResult](),
+			),
+			h.GetServiceEndpoints,
+		),
+		NewMCPTool(
+			mcp.NewTool("get_ingress_routes",
+				mcp.WithDescription("Flatten Ingresses into one route per host and path, each mapped to its backend Service and port (or resource backend), with the path type, ingress class, whether the host is served over TLS, and the TLS Secret that holds its certificate. Default backends are included. Optionally validates that every backend Service exists and exposes the referenced port."),
+				toolschema.Input[GetIngressRoutesParams](),
+				toolschema.Output[GetIngressRoutesResult](),
+			),
+			h.GetIngressRoutes,
+		),
+		NewMCPTool(
+			mcp.NewTool("analyze_network_policy",
+				mcp.WithDescription("Evaluate whether traffic from a source pod to a destination pod on a port and protocol is allowed by NetworkPolicies. Each side is an existing pod or a set of labels in a namespace. Checks both directions, egress from the source and ingress to the destination, and reports for each whether the pod is isolated, which policies select it, and which policy rules allow the traffic. Handles pod and namespace selectors, ipBlock peers, port ranges, and named ports."),
+				toolschema.Input[AnalyzeNetworkPolicyParams](),
+				toolschema.Output[AnalyzeNetworkPolicyResult](),
+			),
+			h.AnalyzeNetworkPolicy,
+		),
+		NewMCPTool(
+			mcp.NewTool("port_allocation_report",
+				mcp.WithDescription("Map the node ports in use across the cluster: every NodePort and LoadBalancer Service port with its allocated nodePort, health check node ports, and every hostPort or hostNetwork port used by pods, per node. Detects collisions, such as two pods on a node binding the same host port or a hostPort that shadows an allocated nodePort, and summarizes how much of the NodePort range is left."),
+				toolschema.Input[PortAllocationReportParams](),
+				toolschema.Output[PortAllocationReportResult](),
+			),
+			h.PortAllocationReport,
+		),
+		NewMCPTool(
+			mcp.NewTool("external_exposure_report",
+				mcp.WithDescription("List everything exposed outside the cluster: LoadBalancer and NodePort Services and Services with externalIPs, with their external IPs or hostnames, ports, source ranges, and whether the load balancer is internal, plus Ingresses with their class, hosts, addresses, TLS configuration, and source-range annotations. Flags load balancers open to any address or still pending, and Ingress hosts served without TLS."),
+				toolschema.Input[ExternalExposureReportParams](),
+				toolschema.Output[ExternalExposureReportResult](),
+			),
+			h.ExternalExposureReport,
+		),
+		NewMCPTool(
+			mcp.NewTool("dns_overrides_report",
+				mcp.WithDescription("Find pods whose DNS resolution differs from the cluster default: a dnsPolicy other than ClusterFirst, host network pods that fall back to the node's resolver, custom dnsConfig nameservers, search domains, or options such as ndots, and hostAliases entries that pin names in /etc/hosts. Pods are grouped by workload, with findings explaining how each override changes name resolution. Useful when DNS works everywhere except in one pod."),
+				toolschema.Input[DNSOverridesReportParams](),
+				toolschema.Output[DNSOverridesReportResult](),
+			),
+			h.DNSOverridesReport,
+		),
+	}
+}
@@ -0,0 +1,210 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/connectivity"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/response"
+)
+
+// nodeConditionOrder is the order standard node conditions are listed in.
+// Other conditions, such as those added by node-problem-detector, follow in
+// alphabetical order.
+var nodeConditionOrder = map[corev1.NodeConditionType]int{
+	corev1.NodeReady:              0,
+	corev1.NodeMemoryPressure:     1,
+	corev1.NodeDiskPressure:       2,
+	corev1.NodePIDPressure:        3,
+	corev1.NodeNetworkUnavailable: 4,
+}
+
+// GetNodeConditionsParams defines the parameters for the get_node_conditions MCP tool.
+type GetNodeConditionsParams struct {
+	// NodeName restricts the report to a single node.
+	NodeName string `json:"node_name,omitempty" description:"Name of a single node to report on (leave empty for all nodes)"`
+
+	// LabelSelector restricts the report to nodes matching the selector.
+	LabelSelector string `json:"label_selector,omitempty" description:"Label selector to filter nodes (e.g., 'node-role.kubernetes.io/worker=')"`
+
+	// UnhealthyOnly omits nodes without any problem from the node list.
+	UnhealthyOnly bool `json:"unhealthy_only,omitempty" description:"Only list nodes that are not Ready, report a pressure or other problem condition, or are cordoned"`
+
+	// Context specifies which Kubernetes context to use for this operation.
+	// If empty, uses the current context from kubeconfig.
+	Context string `json:"context,omitempty" description:"Kubernetes context to use (defaults to current context from kubeconfig)"`
+}
+
+// NodeConditions describes the health of a single node.
+type NodeConditions struct {
+	Name           string `json:"name"`
+	Healthy        bool   `json:"healthy"`
+	Unschedulable  bool   `json:"unschedulable"`
+	KubeletVersion string `json:"kubelet_version"`
+
+	// LastHeartbeat is when the kubelet last updated the Ready condition.
+	LastHeartbeat string `json:"last_heartbeat,omitempty"`
+
+	// Problems summarizes why the node is unhealthy, such as
+	// "MemoryPressure=True (KubeletHasInsufficientMemory)".
+	Problems   []string        `json:"problems,omitempty"`
+	Conditions []NodeCondition `json:"conditions"`
+	Taints     []string        `json:"taints,omitempty"`
+}
+
+// NodeCondition is a single node condition as reported by the kubelet or
+// another node agent.
+type NodeCondition struct {
+	Type               string `json:"type"`
+	Status             string `json:"status"`
+	Reason             string `json:"reason,omitempty"`
+	Message            string `json:"message,omitempty"`
+	LastHeartbeat      string `json:"last_heartbeat,omitempty"`
+	LastTransitionTime string `json:"last_transition_time,omitempty"`
+}
+
+// GetNodeConditionsResult is the result of the get_node_conditions MCP tool.
+type GetNodeConditionsResult struct {
+	TotalNodes     int              `json:"total_nodes"`
+	UnhealthyNodes int              `json:"unhealthy_nodes"`
+	Nodes          []NodeConditions `json:"nodes"`
+}
+
+// GetNodeConditions implements the get_node_conditions MCP tool.
+// It summarizes the Ready, MemoryPressure, DiskPressure, PIDPressure, and
+// NetworkUnavailable conditions of every node together with taints, kubelet
+// version, and last heartbeat. A node is unhealthy when Ready is not True,
+// when any other condition is True (the convention for problem conditions,
+// including those added by node-problem-detector), or when it is cordoned.
+func (h *NodeHandler) GetNodeConditions(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var params GetNodeConditionsParams
+	if err := bindParams(request, &params); err != nil {
+		return response.Errorf("failed to parse arguments: %s", err)
+	}
+
+	// Use the appropriate client based on context
+	client, err := h.client.ForContext(ctx, params.Context)
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to create client with context %s: %v", params.Context, err)
+	}
+
+	nodes, err := client.ListNodes(ctx, metav1.ListOptions{LabelSelector: params.LabelSelector})
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to list nodes: %v", err)
+	}
+
+	reports := make([]NodeConditions, 0, len(nodes.Items))
+	total, unhealthy := 0, 0
+
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+		if params.NodeName != "" && node.Name != params.NodeName {
+			continue
+		}
+
+		report := nodeConditions(node)
+
+		total++
+		if !report.Healthy {
+			unhealthy++
+		} else if params.UnhealthyOnly {
+			continue
+		}
+
+		reports = append(reports, report)
+	}
+
+	if params.NodeName != "" && total == 0 {
+		return response.Errorf("node %q not found", params.NodeName)
+	}
+
+	sort.Slice(reports, func(i, j int) bool {
+		if reports[i].Healthy != reports[j].Healthy {
+			return !reports[i].Healthy
+		}
+		return reports[i].Name < reports[j].Name
+	})
+
+	return response.JSON(GetNodeConditionsResult{
+		TotalNodes:     total,
+		UnhealthyNodes: unhealthy,
+		Nodes:          reports,
+	})
+}
+
+// nodeConditions builds the health report for a single node.
+func nodeConditions(node *corev1.Node) NodeConditions {
+	report := NodeConditions{
+		Name:           node.Name,
+		Unschedulable:  node.Spec.Unschedulable,
+		KubeletVersion: node.Status.NodeInfo.KubeletVersion,
+		Conditions:     make([]NodeCondition, 0, len(node.Status.Conditions)),
+	}
+
+	conditions := append([]corev1.NodeCondition{}, node.Status.Conditions...)
+	sort.SliceStable(conditions, func(i, j int) bool {
+		oi, iKnown := nodeConditionOrder[conditions[i].Type]
+		oj, jKnown := nodeConditionOrder[conditions[j].Type]
+		switch {
+		case iKnown && jKnown:
+			return oi < oj
+		case iKnown != jKnown:
+			return iKnown
+		default:
+			return conditions[i].Type < conditions[j].Type
+		}
+	})
+
+	hasReady := false
+	for _, condition := range conditions {
+		report.Conditions = append(report.Conditions, NodeCondition{
+			Type:               string(condition.Type),
+			Status:             string(condition.Status),
+			Reason:             condition.Reason,
+			Message:            condition.Message,
+			LastHeartbeat:      formatTime(condition.LastHeartbeatTime.Time),
+			LastTransitionTime: formatTime(condition.LastTransitionTime.Time),
+		})
+
+		problem := condition.Status == corev1.ConditionTrue
+		if condition.Type == corev1.NodeReady {
+			hasReady = true
+			report.LastHeartbeat = formatTime(condition.LastHeartbeatTime.Time)
+			problem = condition.Status != corev1.ConditionTrue
+		}
+
+		if problem {
+			entry := fmt.Sprintf("%s=%s", condition.Type, condition.Status)
+			if condition.Reason != "" {
+				entry += " (" + condition.Reason + ")"
+			}
+			report.Problems = append(report.Problems, entry)
+		}
+	}
+
+	if !hasReady {
+		report.Problems = append([]string{"Ready condition missing"}, report.Problems...)
+	}
+	if node.Spec.Unschedulable {
+		report.Problems = append(report.Problems, "cordoned (unschedulable)")
+	}
+
+	for _, taint := range node.Spec.Taints {
+		report.Taints = append(report.Taints, formatTaint(taint))
+	}
+
+	report.Healthy = len(report.Problems) == 0
+
+	return report
+}
@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/fakecluster"
+)
+
+func TestNodeConditions(t *testing.T) {
+	t.Parallel()
+
+	heartbeat := metav1.NewTime(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC))
+
+	tests := []struct {
+		name         string
+		node         corev1.Node
+		wantProblems []string
+		wantOrder    []string
+	}{
+		{
+			name: "healthy",
+			node: corev1.Node{Status: corev1.NodeStatus{Conditions: []corev1.NodeCondition{
+				{Type: corev1.NodeMemoryPressure, Status: corev1.ConditionFalse},
+				{Type: corev1.NodeReady, Status: corev1.ConditionTrue, LastHeartbeatTime: heartbeat},
+			}}},
+			wantOrder: []string{"Ready", "MemoryPressure"},
+		},
+		{
+			name: "pressure and custom conditions",
+			node: corev1.Node{Status: corev1.NodeStatus{Conditions: []corev1.NodeCondition{
+				{Type: "KernelDeadlock", Status: corev1.ConditionTrue, Reason: "DockerHung"},
+				{Type: corev1.NodeDiskPressure, Status: corev1.ConditionTrue, Reason: "KubeletHasDiskPressure"},
+				{Type: corev1.NodeReady, Status: corev1.ConditionTrue},
+			}}},
+			wantProblems: []string{"DiskPressure=True (KubeletHasDiskPressure)", "KernelDeadlock=True (DockerHung)"},
+			wantOrder:    []string{"Ready", "DiskPressure", "KernelDeadlock"},
+		},
+		{
+			name: "not ready and cordoned",
+			node: corev1.Node{
+				Spec: corev1.NodeSpec{Unschedulable: true},
+				Status: corev1.NodeStatus{Conditions: []corev1.NodeCondition{
+					{Type: corev1.NodeReady, Status: corev1.ConditionUnknown, Reason: "NodeStatusUnknown"},
+				}},
+			},
+			wantProblems: []string{"Ready=Unknown (NodeStatusUnknown)", "cordoned (unschedulable)"},
+			wantOrder:    []string{"Ready"},
+		},
+		{
+			name:         "missing ready condition",
+			node:         corev1.Node{},
+			wantProblems: []string{"Ready condition missing"},
+			wantOrder:    []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := nodeConditions(&tt.node)
+
+			if !reflect.DeepEqual(got.Problems, tt.wantProblems) {
+				t.Errorf("expected problems %v, got %v", tt.wantProblems, got.Problems)
+			}
+			if got.Healthy != (len(tt.wantProblems) == 0) {
+				t.Errorf("unexpected healthy=%v", got.Healthy)
+			}
+
+			order := make([]string, 0, len(got.Conditions))
+			for _, condition := range got.Conditions {
+				order = append(order, condition.Type)
+			}
+			if !reflect.DeepEqual(order, tt.wantOrder) {
+				t.Errorf("expected condition order %v, got %v", tt.wantOrder, order)
+			}
+		})
+	}
+}
+
+func TestGetNodeConditions_FakeCluster(t *testing.T) {
+	t.Parallel()
+
+	node := func(name string, ready corev1.ConditionStatus) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status: corev1.NodeStatus{Conditions: []corev1.NodeCondition{
+				{Type: corev1.NodeReady, Status: ready},
+			}},
+		}
+	}
+
+	handler := NewNodeHandler(fakecluster.New(fakecluster.Config{
+		Objects: []runtime.Object{
+			node("node-a", corev1.ConditionTrue),
+			node("node-b", corev1.ConditionFalse),
+			node("node-c", corev1.ConditionTrue),
+		},
+	}), false)
+
+	result, isErr := callTool(t, handler.GetNodeConditions, map[string]any{})
+	if isErr {
+		t.Fatalf("unexpected error: %v", result["error"])
+	}
+
+	var nodes []NodeConditions
+	decodeInto(t, result["nodes"], &nodes)
+
+	names := make([]string, 0, len(nodes))
+	for _, n := range nodes {
+		names = append(names, n.Name)
+	}
+	if want := []string{"node-b", "node-a", "node-c"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("expected unhealthy nodes first %v, got %v", want, names)
+	}
+
+	result, isErr = callTool(t, handler.GetNodeConditions, map[string]any{"unhealthy_only": true})
+	if isErr {
+		t.Fatalf("unexpected error: %v", result["error"])
+	}
+	decodeInto(t, result["nodes"], &nodes)
+	if len(nodes) != 1 || nodes[0].Name != "node-b" || result["total_nodes"] != float64(3) || result["unhealthy_nodes"] != float64(1) {
+		t.Errorf("unexpected unhealthy_only result: %v", result)
+	}
+
+	if result, isErr := callTool(t, handler.GetNodeConditions, map[string]any{"node_name": "node-z"}); !isErr {
+		t.Errorf("expected an error for a missing node, got %v", result)
+	}
+}
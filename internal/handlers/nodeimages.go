@@ -0,0 +1,340 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/connectivity"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/kubernetes"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/response"
+)
+
+const (
+	// imageGCHighThresholdPercent is the kubelet's default
+	// imageGCHighThresholdPercent: above it, the kubelet deletes unused images.
+	imageGCHighThresholdPercent = 85
+
+	// imageGCLowThresholdPercent is the kubelet's default
+	// imageGCLowThresholdPercent, which image garbage collection frees down to.
+	imageGCLowThresholdPercent = 80
+
+	// nodeStatusMaxImages is the kubelet's default --node-status-max-images:
+	// node status lists at most this many images.
+	nodeStatusMaxImages = 50
+
+	// nodeStatsConcurrency bounds how many kubelet stats summaries are read
+	// at the same time.
+	nodeStatsConcurrency = 8
+)
+
+// Image garbage collection risk levels reported by node_images_report.
+const (
+	imageRiskHigh    = "high"
+	imageRiskMedium  = "medium"
+	imageRiskLow     = "low"
+	imageRiskUnknown = "unknown"
+)
+
+// imageRiskOrder sorts nodes with the highest risk first.
+var imageRiskOrder = map[string]int{
+	imageRiskHigh:    0,
+	imageRiskMedium:  1,
+	imageRiskUnknown: 2,
+	imageRiskLow:     3,
+}
+
+// NodeImagesReportParams defines the parameters for the node_images_report MCP tool.
+type NodeImagesReportParams struct {
+	// NodeName restricts the report to a single node.
+	NodeName string `json:"node_name,omitempty" description:"Name of a single node to report on (leave empty for all nodes)"`
+
+	// LabelSelector restricts the report to nodes matching the selector.
+	LabelSelector string `json:"label_selector,omitempty" description:"Label selector to filter nodes (e.g., 'node-role.kubernetes.io/worker=')"`
+
+	// Context specifies which Kubernetes context to use for this operation.
+	// If empty, uses the current context from kubeconfig.
+	Context string `json:"context,omitempty" description:"Kubernetes context to use (defaults to current context from kubeconfig)"`
+}
+
+// ImageFilesystem is the usage of the filesystem a node stores images on.
+type ImageFilesystem struct {
+	Capacity    string  `json:"capacity"`
+	Used        string  `json:"used"`
+	Available   string  `json:"available"`
+	UsedPercent float64 `json:"used_percent"`
+}
+
+// NodeImages describes the container images stored on a node and how close
+// its image filesystem is to image garbage collection.
+type NodeImages struct {
+	Name string `json:"name"`
+
+	// ImageCount is the number of images in node status. The kubelet lists
+	// at most 50 by default, so ImageCountTruncated marks a lower bound.
+	ImageCount          int    `json:"image_count"`
+	ImageCountTruncated bool   `json:"image_count_truncated,omitempty"`
+	ImagesSize          string `json:"images_size"`
+	ImagesSizeBytes     int64  `json:"images_size_bytes"`
+
+	// ImageFilesystem is nil when the kubelet stats could not be read.
+	ImageFilesystem *ImageFilesystem `json:"image_filesystem,omitempty"`
+	DiskPressure    bool             `json:"disk_pressure"`
+
+	// Risk is high, medium, low, or unknown when the image filesystem usage
+	// is unknown and the node reports no disk pressure.
+	Risk    string   `json:"risk"`
+	Reasons []string `json:"reasons,omitempty"`
+}
+
+// ImageGCThresholds holds the kubelet image garbage collection thresholds
+// the nodes were assessed against.
+type ImageGCThresholds struct {
+	ImageGCHighPercent int `json:"image_gc_high_percent"`
+	ImageGCLowPercent  int `json:"image_gc_low_percent"`
+}
+
+// NodeImagesReportResult is the result of the node_images_report MCP tool.
+type NodeImagesReportResult struct {
+	TotalNodes  int               `json:"total_nodes"`
+	AtRiskNodes int               `json:"at_risk_nodes"`
+	Nodes       []NodeImages      `json:"nodes"`
+	Thresholds  ImageGCThresholds `json:"thresholds"`
+	Warnings    []string          `json:"warnings,omitempty"`
+}
+
+// NodeImagesReport implements the node_images_report MCP tool.
+// It combines the images each node reports in its status with the image
+// filesystem usage from the kubelet's stats summary, read through the node
+// proxy, to find nodes at risk of image garbage collection storms. Nodes are
+// rated against the kubelet's default image GC thresholds. Reading kubelet
+// stats needs get access to nodes/proxy; when it fails the report is still
+// returned from node status alone, with a warning.
+func (h *NodeHandler) NodeImagesReport(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var params NodeImagesReportParams
+	if err := bindParams(request, &params); err != nil {
+		return response.Errorf("failed to parse arguments: %s", err)
+	}
+
+	// Use the appropriate client based on context
+	client, err := h.client.ForContext(ctx, params.Context)
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to create client with context %s: %v", params.Context, err)
+	}
+
+	list, err := client.ListNodes(ctx, metav1.ListOptions{LabelSelector: params.LabelSelector})
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to list nodes: %v", err)
+	}
+
+	nodes := make([]*corev1.Node, 0, len(list.Items))
+	for i := range list.Items {
+		if params.NodeName == "" || list.Items[i].Name == params.NodeName {
+			nodes = append(nodes, &list.Items[i])
+		}
+	}
+
+	if params.NodeName != "" && len(nodes) == 0 {
+		return response.Errorf("node %q not found", params.NodeName)
+	}
+
+	summaries, errs := nodeStatsSummaries(ctx, client, nodes)
+
+	var warnings []string
+	failed := 0
+	var firstErr error
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		failed++
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	if failed > 0 {
+		warnings = append(warnings, fmt.Sprintf("failed to read kubelet stats of %d of %d nodes, so their image filesystem usage is unknown (this needs get access to nodes/proxy): %v",
+			failed, len(nodes), firstErr))
+	}
+
+	reports := make([]NodeImages, 0, len(nodes))
+	atRisk := 0
+	for i, node := range nodes {
+		report := nodeImages(node, summaries[i])
+		if report.Risk == imageRiskHigh || report.Risk == imageRiskMedium {
+			atRisk++
+		}
+		reports = append(reports, report)
+	}
+
+	sort.Slice(reports, func(i, j int) bool {
+		if oi, oj := imageRiskOrder[reports[i].Risk], imageRiskOrder[reports[j].Risk]; oi != oj {
+			return oi < oj
+		}
+		if pi, pj := imageFSUsedPercent(reports[i]), imageFSUsedPercent(reports[j]); pi != pj {
+			return pi > pj
+		}
+		return reports[i].Name < reports[j].Name
+	})
+
+	return response.JSON(NodeImagesReportResult{
+		TotalNodes:  len(reports),
+		AtRiskNodes: atRisk,
+		Nodes:       reports,
+		Thresholds: ImageGCThresholds{
+			ImageGCHighPercent: imageGCHighThresholdPercent,
+			ImageGCLowPercent:  imageGCLowThresholdPercent,
+		},
+		Warnings: warnings,
+	})
+}
+
+// nodeStatsSummaries reads the kubelet stats summary of every node, a few
+// at a time. The results and errors are indexed like nodes.
+func nodeStatsSummaries(ctx context.Context, client kubernetes.ClusterReader, nodes []*corev1.Node) ([]*kubernetes.NodeStatsSummary, []error) {
+	summaries := make([]*kubernetes.NodeStatsSummary, len(nodes))
+	errs := make([]error, len(nodes))
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, nodeStatsConcurrency)
+
+	for i, node := range nodes {
+		if !acquireSlot(ctx, sem) {
+			errs[i] = ctx.Err()
+			continue
+		}
+		wg.Add(1)
+
+		go func(i int, name string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			summaries[i], errs[i] = client.GetNodeStatsSummary(ctx, name)
+		}(i, node.Name)
+	}
+
+	wg.Wait()
+	return summaries, errs
+}
+
+// nodeImages builds the image report of a node. summary may be nil when the
+// kubelet stats are unavailable.
+func nodeImages(node *corev1.Node, summary *kubernetes.NodeStatsSummary) NodeImages {
+	report := NodeImages{
+		Name:                node.Name,
+		ImageCount:          len(node.Status.Images),
+		ImageCountTruncated: len(node.Status.Images) >= nodeStatusMaxImages,
+	}
+
+	for _, image := range node.Status.Images {
+		report.ImagesSizeBytes += image.SizeBytes
+	}
+	report.ImagesSize = formatBytes(report.ImagesSizeBytes)
+
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeDiskPressure && condition.Status == corev1.ConditionTrue {
+			report.DiskPressure = true
+		}
+	}
+
+	if summary != nil {
+		report.ImageFilesystem = imageFilesystem(summary)
+	}
+
+	report.Risk = imageRiskLow
+	if report.DiskPressure {
+		report.Risk = imageRiskHigh
+		report.Reasons = append(report.Reasons, "the node reports DiskPressure, so the kubelet is deleting images and may evict pods")
+	}
+
+	switch fs := report.ImageFilesystem; {
+	case fs == nil:
+		if !report.DiskPressure {
+			report.Risk = imageRiskUnknown
+		}
+	case fs.UsedPercent >= imageGCHighThresholdPercent:
+		report.Risk = imageRiskHigh
+		report.Reasons = append(report.Reasons, fmt.Sprintf("image filesystem is %.1f%% used, above the %d%% image GC threshold: the kubelet deletes unused images down to %d%%, and pods pulling large images can trigger repeated collections",
+			fs.UsedPercent, imageGCHighThresholdPercent, imageGCLowThresholdPercent))
+	case fs.UsedPercent >= imageGCLowThresholdPercent:
+		if report.Risk != imageRiskHigh {
+			report.Risk = imageRiskMedium
+		}
+		report.Reasons = append(report.Reasons, fmt.Sprintf("image filesystem is %.1f%% used, close to the %d%% image GC threshold",
+			fs.UsedPercent, imageGCHighThresholdPercent))
+	}
+
+	if report.ImageCountTruncated {
+		report.Reasons = append(report.Reasons, fmt.Sprintf("node status lists only the %d largest images, so the image count and size are lower bounds", nodeStatusMaxImages))
+	}
+
+	return report
+}
+
+// imageFilesystem returns the usage of the filesystem the runtime stores
+// images on, falling back to the kubelet's root filesystem when the runtime
+// does not report a separate one. It returns nil without usable numbers.
+func imageFilesystem(summary *kubernetes.NodeStatsSummary) *ImageFilesystem {
+	fs := summary.Node.Fs
+	if summary.Node.Runtime != nil && summary.Node.Runtime.ImageFs != nil {
+		fs = summary.Node.Runtime.ImageFs
+	}
+
+	if fs == nil || fs.CapacityBytes == nil || *fs.CapacityBytes == 0 || fs.AvailableBytes == nil {
+		return nil
+	}
+
+	// Like the kubelet's image GC, count everything that is not available
+	// as used: on a shared filesystem, other data counts toward the
+	// thresholds too.
+	capacity := *fs.CapacityBytes
+	available := min(*fs.AvailableBytes, capacity)
+	used := capacity - available
+
+	return &ImageFilesystem{
+		Capacity:    formatBytes(int64(capacity)),
+		Used:        formatBytes(int64(used)),
+		Available:   formatBytes(int64(available)),
+		UsedPercent: math.Round(float64(used)/float64(capacity)*1000) / 10,
+	}
+}
+
+// imageFSUsedPercent returns the image filesystem usage of a report, or -1
+// when it is unknown.
+func imageFSUsedPercent(report NodeImages) float64 {
+	if report.ImageFilesystem == nil {
+		return -1
+	}
+	return report.ImageFilesystem.UsedPercent
+}
+
+// formatBytes formats a byte count with binary units, such as "3.4 GiB".
+func formatBytes(b int64) string {
+	const unit = 1024
+	if b < unit {
+		return fmt.Sprintf("%d B", b)
+	}
+
+	div, exp := int64(unit), 0
+	for n := b / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %ciB", float64(b)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,179 @@
+package handlers
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/fakecluster"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/kubernetes"
+)
+
+func TestNodeImages(t *testing.T) {
+	t.Parallel()
+
+	const gib = 1 << 30
+
+	uint64p := func(v uint64) *uint64 { return &v }
+	imageFS := func(capacity, available uint64) *kubernetes.NodeStatsSummary {
+		return &kubernetes.NodeStatsSummary{Node: kubernetes.NodeStats{
+			Fs: &kubernetes.FsStats{CapacityBytes: uint64p(1000 * gib), AvailableBytes: uint64p(900 * gib)},
+			Runtime: &kubernetes.RuntimeStats{ImageFs: &kubernetes.FsStats{
+				CapacityBytes:  uint64p(capacity),
+				AvailableBytes: uint64p(available),
+			}},
+		}}
+	}
+
+	node := func(images int, diskPressure bool) *corev1.Node {
+		node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "worker-1"}}
+		for i := 0; i < images; i++ {
+			node.Status.Images = append(node.Status.Images, corev1.ContainerImage{
+				Names:     []string{fmt.Sprintf("registry.example.com/app-%d:1.0", i)},
+				SizeBytes: gib / 2,
+			})
+		}
+		if diskPressure {
+			node.Status.Conditions = []corev1.NodeCondition{{Type: corev1.NodeDiskPressure, Status: corev1.ConditionTrue}}
+		}
+		return node
+	}
+
+	tests := []struct {
+		name        string
+		node        *corev1.Node
+		summary     *kubernetes.NodeStatsSummary
+		wantRisk    string
+		wantPercent float64
+		wantSize    string
+		wantReasons int
+	}{
+		{
+			name:        "plenty of space",
+			node:        node(4, false),
+			summary:     imageFS(100*gib, 60*gib),
+			wantRisk:    imageRiskLow,
+			wantPercent: 40,
+			wantSize:    "2.0 GiB",
+		},
+		{
+			name:        "close to the GC threshold",
+			node:        node(4, false),
+			summary:     imageFS(100*gib, 18*gib),
+			wantRisk:    imageRiskMedium,
+			wantPercent: 82,
+			wantSize:    "2.0 GiB",
+			wantReasons: 1,
+		},
+		{
+			name:        "above the GC threshold with a truncated image list",
+			node:        node(50, false),
+			summary:     imageFS(100*gib, 9*gib),
+			wantRisk:    imageRiskHigh,
+			wantPercent: 91,
+			wantSize:    "25.0 GiB",
+			wantReasons: 2,
+		},
+		{
+			name:        "stats unavailable",
+			node:        node(1, false),
+			wantRisk:    imageRiskUnknown,
+			wantPercent: -1,
+			wantSize:    "512.0 MiB",
+		},
+		{
+			name:        "disk pressure without stats",
+			node:        node(1, true),
+			wantRisk:    imageRiskHigh,
+			wantPercent: -1,
+			wantSize:    "512.0 MiB",
+			wantReasons: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := nodeImages(tt.node, tt.summary)
+			if got.Risk != tt.wantRisk {
+				t.Errorf("expected risk %q, got %q (%v)", tt.wantRisk, got.Risk, got.Reasons)
+			}
+			if percent := imageFSUsedPercent(got); percent != tt.wantPercent {
+				t.Errorf("expected %.1f%% used, got %.1f%%", tt.wantPercent, percent)
+			}
+			if got.ImagesSize != tt.wantSize {
+				t.Errorf("expected images size %q, got %q", tt.wantSize, got.ImagesSize)
+			}
+			if len(got.Reasons) != tt.wantReasons {
+				t.Errorf("expected %d reasons, got %v", tt.wantReasons, got.Reasons)
+			}
+		})
+	}
+}
+
+func TestNodeImagesReport_FakeCluster(t *testing.T) {
+	t.Parallel()
+
+	handler := NewNodeHandler(fakecluster.New(fakecluster.Config{
+		Objects: []runtime.Object{
+			&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "worker-1"}},
+			&corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{Name: "worker-2"},
+				Status: corev1.NodeStatus{
+					Conditions: []corev1.NodeCondition{{Type: corev1.NodeDiskPressure, Status: corev1.ConditionTrue}},
+					Images:     []corev1.ContainerImage{{Names: []string{"nginx:1.27"}, SizeBytes: 70 << 20}},
+				},
+			},
+		},
+	}), false)
+
+	result, isErr := callTool(t, handler.NodeImagesReport, map[string]any{})
+	if isErr {
+		t.Fatalf("unexpected error: %v", result["error"])
+	}
+
+	var nodes []NodeImages
+	decodeInto(t, result["nodes"], &nodes)
+
+	var got []string
+	for _, node := range nodes {
+		got = append(got, node.Name+"="+node.Risk)
+	}
+	if want := []string{"worker-2=high", "worker-1=unknown"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected nodes %v, got %v", want, got)
+	}
+
+	// The fake cluster cannot reach a kubelet, so stats are reported missing.
+	if warnings, _ := result["warnings"].([]any); len(warnings) != 1 {
+		t.Errorf("expected one warning about kubelet stats, got %v", result["warnings"])
+	}
+
+	if _, isErr := callTool(t, handler.NodeImagesReport, map[string]any{"node_name": "missing"}); !isErr {
+		t.Error("expected an error for an unknown node")
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	t.Parallel()
+
+	tests := map[int64]string{
+		0:                 "0 B",
+		1023:              "1023 B",
+		1536:              "1.5 KiB",
+		70 << 20:          "70.0 MiB",
+		3*(1<<30) + 1<<29: "3.5 GiB",
+		2 * (1 << 40):     "2.0 TiB",
+	}
+
+	for in, want := range tests {
+		if got := formatBytes(in); got != want {
+			t.Errorf("formatBytes(%d): expected %q, got %q", in, want, got)
+		}
+	}
+}
@@ -0,0 +1,306 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/version"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/connectivity"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/response"
+)
+
+const (
+	// labelNodeRolePrefix prefixes the labels that give a node its roles,
+	// such as node-role.kubernetes.io/control-plane.
+	labelNodeRolePrefix = "node-role.kubernetes.io/"
+
+	// maxNodeSkew is how many minor versions the kubelet and kube-proxy may
+	// trail the API server by under the Kubernetes version skew policy.
+	maxNodeSkew = 3
+
+	// maxGroupNodes bounds how many node names a version group lists. Larger
+	// groups only report their count, since the small groups are the ones
+	// that stand out.
+	maxGroupNodes = 20
+)
+
+// GetNodeInfoParams defines the parameters for the get_node_info MCP tool.
+type GetNodeInfoParams struct {
+	// NodeName restricts the report to a single node.
+	NodeName string `json:"node_name,omitempty" description:"Name of a single node to report on (leave empty for all nodes)"`
+
+	// LabelSelector restricts the report to nodes matching the selector.
+	LabelSelector string `json:"label_selector,omitempty" description:"Label selector to filter nodes (e.g., 'node-role.kubernetes.io/worker=')"`
+
+	// SummaryOnly omits the per-node list, keeping the groups and findings.
+	SummaryOnly bool `json:"summary_only,omitempty" description:"Only return the version groups and findings, without the per-node details. Useful for large fleets"`
+
+	// Context specifies which Kubernetes context to use for this operation.
+	// If empty, uses the current context from kubeconfig.
+	Context string `json:"context,omitempty" description:"Kubernetes context to use (defaults to current context from kubeconfig)"`
+}
+
+// NodeInfo describes the software and hardware of a single node.
+type NodeInfo struct {
+	Name             string   `json:"name"`
+	Ready            bool     `json:"ready"`
+	Roles            []string `json:"roles,omitempty"`
+	OperatingSystem  string   `json:"operating_system"`
+	Architecture     string   `json:"architecture"`
+	OSImage          string   `json:"os_image"`
+	KernelVersion    string   `json:"kernel_version"`
+	ContainerRuntime string   `json:"container_runtime"`
+	KubeletVersion   string   `json:"kubelet_version"`
+
+	// KubeProxyVersion is only reported by older kubelets; the field is
+	// deprecated and no longer set since Kubernetes 1.31.
+	KubeProxyVersion string            `json:"kube_proxy_version,omitempty"`
+	Capacity         map[string]string `json:"capacity"`
+	Allocatable      map[string]string `json:"allocatable"`
+	Labels           map[string]string `json:"labels,omitempty"`
+	Created          string            `json:"created"`
+}
+
+// NodeVersionGroup is a set of nodes sharing the same value of a property,
+// such as the same kubelet version.
+type NodeVersionGroup struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+
+	// Nodes lists the nodes in the group when there are at most
+	// maxGroupNodes of them.
+	Nodes []string `json:"nodes,omitempty"`
+}
+
+// nodeInfoGroups are the properties get_node_info groups nodes by, with how
+// to read each one from a node.
+var nodeInfoGroups = []struct {
+	name  string
+	value func(NodeInfo) string
+}{
+	{"kubelet_version", func(n NodeInfo) string { return n.KubeletVersion }},
+	{"kube_proxy_version", func(n NodeInfo) string { return n.KubeProxyVersion }},
+	{"container_runtime", func(n NodeInfo) string { return n.ContainerRuntime }},
+	{"os_image", func(n NodeInfo) string { return n.OSImage }},
+	{"kernel_version", func(n NodeInfo) string { return n.KernelVersion }},
+	{"platform", func(n NodeInfo) string { return n.OperatingSystem + "/" + n.Architecture }},
+}
+
+// GetNodeInfoResult is the result of the get_node_info MCP tool.
+type GetNodeInfoResult struct {
+	NodeCount     int    `json:"node_count"`
+	ServerVersion string `json:"server_version,omitempty"`
+
+	// Groups groups the nodes by version or runtime, under the name of the
+	// field they share.
+	Groups   map[string][]NodeVersionGroup `json:"groups"`
+	Findings []string                      `json:"findings"`
+
+	// Nodes is left out when only the summary was requested.
+	Nodes    []NodeInfo `json:"nodes,omitzero"`
+	Warnings []string   `json:"warnings,omitempty"`
+}
+
+// GetNodeInfo implements the get_node_info MCP tool.
+// It reports the OS image, kernel, container runtime, kubelet and kube-proxy
+// versions, architecture, labels, capacity, and allocatable resources of
+// every node, and groups nodes by each of those properties so version skew
+// across the fleet stands out. Kubelet and kube-proxy versions are checked
+// against the API server under the version skew policy: they must not be
+// newer, and may trail it by at most three minor versions.
+func (h *NodeHandler) GetNodeInfo(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var params GetNodeInfoParams
+	if err := bindParams(request, &params); err != nil {
+		return response.Errorf("failed to parse arguments: %s", err)
+	}
+
+	// Use the appropriate client based on context
+	client, err := h.client.ForContext(ctx, params.Context)
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to create client with context %s: %v", params.Context, err)
+	}
+
+	nodes, err := client.ListNodes(ctx, metav1.ListOptions{LabelSelector: params.LabelSelector})
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to list nodes: %v", err)
+	}
+
+	infos := make([]NodeInfo, 0, len(nodes.Items))
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+		if params.NodeName != "" && node.Name != params.NodeName {
+			continue
+		}
+		infos = append(infos, nodeInfo(node))
+	}
+
+	if params.NodeName != "" && len(infos) == 0 {
+		return response.Errorf("node %q not found", params.NodeName)
+	}
+
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].Name < infos[j].Name
+	})
+
+	groups := make(map[string][]NodeVersionGroup, len(nodeInfoGroups))
+	for _, group := range nodeInfoGroups {
+		if grouped := groupNodes(infos, group.value); len(grouped) > 0 {
+			groups[group.name] = grouped
+		}
+	}
+
+	var warnings []string
+	serverVersion := ""
+	if info, err := client.ServerVersion(); err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		warnings = append(warnings, fmt.Sprintf("failed to get the server version, so node versions were not checked against it: %v", err))
+	} else {
+		serverVersion = info.GitVersion
+	}
+
+	findings := nodeVersionFindings(groups, serverVersion)
+
+	result := GetNodeInfoResult{
+		NodeCount:     len(infos),
+		ServerVersion: serverVersion,
+		Groups:        groups,
+		Findings:      findings,
+		Warnings:      warnings,
+	}
+	if !params.SummaryOnly {
+		result.Nodes = infos
+	}
+
+	return response.JSON(result)
+}
+
+// nodeInfo builds the report of a single node.
+func nodeInfo(node *corev1.Node) NodeInfo {
+	system := node.Status.NodeInfo
+	info := NodeInfo{
+		Name:             node.Name,
+		Ready:            isNodeReady(node),
+		OperatingSystem:  nodeOperatingSystem(node),
+		Architecture:     system.Architecture,
+		OSImage:          system.OSImage,
+		KernelVersion:    system.KernelVersion,
+		ContainerRuntime: system.ContainerRuntimeVersion,
+		KubeletVersion:   system.KubeletVersion,
+		KubeProxyVersion: system.KubeProxyVersion, //nolint:staticcheck // still reported by older kubelets
+		Capacity:         formatResources(node.Status.Capacity),
+		Allocatable:      formatResources(node.Status.Allocatable),
+		Labels:           node.Labels,
+		Created:          formatTime(node.CreationTimestamp.Time),
+	}
+
+	for key := range node.Labels {
+		if role, ok := strings.CutPrefix(key, labelNodeRolePrefix); ok && role != "" {
+			info.Roles = append(info.Roles, role)
+		}
+	}
+	sort.Strings(info.Roles)
+
+	return info
+}
+
+// groupNodes groups nodes by a property, largest group first. Nodes where
+// the property is empty are left out.
+func groupNodes(infos []NodeInfo, value func(NodeInfo) string) []NodeVersionGroup {
+	members := make(map[string][]string)
+	for _, info := range infos {
+		if v := value(info); v != "" && v != "/" {
+			members[v] = append(members[v], info.Name)
+		}
+	}
+
+	groups := make([]NodeVersionGroup, 0, len(members))
+	for v, nodes := range members {
+		group := NodeVersionGroup{Value: v, Count: len(nodes)}
+		if len(nodes) <= maxGroupNodes {
+			group.Nodes = nodes
+		}
+		groups = append(groups, group)
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].Count != groups[j].Count {
+			return groups[i].Count > groups[j].Count
+		}
+		return groups[i].Value < groups[j].Value
+	})
+
+	return groups
+}
+
+// nodeVersionFindings flags kubelet and kube-proxy versions that break the
+// version skew policy against the API server, and fleets running more than
+// one kubelet minor version. serverVersion may be empty when it is unknown.
+func nodeVersionFindings(groups map[string][]NodeVersionGroup, serverVersion string) []string {
+	findings := make([]string, 0)
+
+	var server *version.Version
+	if parsed, err := version.ParseGeneric(serverVersion); err == nil {
+		server = parsed
+	}
+
+	minors := make(map[string]bool)
+	for _, group := range groups["kubelet_version"] {
+		if parsed, err := version.ParseGeneric(group.Value); err == nil {
+			minors[fmt.Sprintf("%d.%d", parsed.Major(), parsed.Minor())] = true
+		}
+	}
+	if len(minors) > 1 {
+		list := make([]string, 0, len(minors))
+		for minor := range minors {
+			list = append(list, minor)
+		}
+		sort.Slice(list, func(i, j int) bool {
+			return version.MustParseGeneric(list[i]).LessThan(version.MustParseGeneric(list[j]))
+		})
+		findings = append(findings, fmt.Sprintf("nodes run %d kubelet minor versions (%s); finish the upgrade so the fleet runs a single version", len(list), strings.Join(list, ", ")))
+	}
+
+	if server == nil {
+		return findings
+	}
+
+	for _, component := range []struct{ name, group string }{{"kubelet", "kubelet_version"}, {"kube-proxy", "kube_proxy_version"}} {
+		for _, group := range groups[component.group] {
+			parsed, err := version.ParseGeneric(group.Value)
+			if err != nil {
+				continue
+			}
+
+			subject := fmt.Sprintf("%d node(s) run %s %s", group.Count, component.name, group.Value)
+			if len(group.Nodes) > 0 {
+				subject += " (" + strings.Join(group.Nodes, ", ") + ")"
+			}
+
+			behind := int(server.Minor()) - int(parsed.Minor())
+			switch {
+			case parsed.Major() != server.Major():
+				findings = append(findings, fmt.Sprintf("%s, a different major version than the API server %s", subject, serverVersion))
+			case behind < 0:
+				findings = append(findings, fmt.Sprintf("%s, newer than the API server %s, which the version skew policy does not allow", subject, serverVersion))
+			case behind > maxNodeSkew:
+				findings = append(findings, fmt.Sprintf("%s, %d minor versions older than the API server %s; the version skew policy allows at most %d", subject, behind, serverVersion, maxNodeSkew))
+			}
+		}
+	}
+
+	return findings
+}
@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/fakecluster"
+)
+
+func TestNodeVersionFindings(t *testing.T) {
+	t.Parallel()
+
+	kubelets := func(values ...string) map[string][]NodeVersionGroup {
+		groups := make([]NodeVersionGroup, 0, len(values))
+		for _, value := range values {
+			groups = append(groups, NodeVersionGroup{Value: value, Count: 1, Nodes: []string{"node-" + value}})
+		}
+		return map[string][]NodeVersionGroup{"kubelet_version": groups}
+	}
+
+	tests := []struct {
+		name   string
+		groups map[string][]NodeVersionGroup
+		server string
+		want   []string
+	}{
+		{
+			name:   "uniform fleet",
+			groups: kubelets("v1.30.2"),
+			server: "v1.30.4",
+			want:   []string{},
+		},
+		{
+			name:   "supported skew",
+			groups: kubelets("v1.30.2", "v1.27.9"),
+			server: "v1.30.4",
+			want:   []string{"nodes run 2 kubelet minor versions (1.27, 1.30); finish the upgrade so the fleet runs a single version"},
+		},
+		{
+			name:   "too old and too new",
+			groups: kubelets("v1.26.1", "v1.31.0"),
+			server: "v1.30.4",
+			want: []string{
+				"nodes run 2 kubelet minor versions (1.26, 1.31); finish the upgrade so the fleet runs a single version",
+				"1 node(s) run kubelet v1.26.1 (node-v1.26.1), 4 minor versions older than the API server v1.30.4; the version skew policy allows at most 3",
+				"1 node(s) run kubelet v1.31.0 (node-v1.31.0), newer than the API server v1.30.4, which the version skew policy does not allow",
+			},
+		},
+		{
+			name:   "unknown server version",
+			groups: kubelets("v1.26.1"),
+			want:   []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := nodeVersionFindings(tt.groups, tt.server); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestGetNodeInfo_FakeCluster(t *testing.T) {
+	t.Parallel()
+
+	node := func(name, kubelet, containerRuntime string, labels map[string]string) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+			Status: corev1.NodeStatus{
+				Capacity:    corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4"), corev1.ResourceMemory: resource.MustParse("16Gi")},
+				Allocatable: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("3800m"), corev1.ResourceMemory: resource.MustParse("15Gi")},
+				Conditions:  []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}},
+				NodeInfo: corev1.NodeSystemInfo{
+					OperatingSystem:         "linux",
+					Architecture:            "amd64",
+					OSImage:                 "Ubuntu 22.04.4 LTS",
+					KernelVersion:           "5.15.0-105-generic",
+					ContainerRuntimeVersion: containerRuntime,
+					KubeletVersion:          kubelet,
+				},
+			},
+		}
+	}
+
+	handler := NewNodeHandler(fakecluster.New(fakecluster.Config{
+		ServerVersion: "v1.30.4",
+		Objects: []runtime.Object{
+			node("cp-1", "v1.30.4", "containerd://1.7.13", map[string]string{"node-role.kubernetes.io/control-plane": ""}),
+			node("worker-1", "v1.30.4", "containerd://1.7.13", nil),
+			node("worker-2", "v1.29.8", "containerd://1.6.28", nil),
+		},
+	}), false)
+
+	result, isErr := callTool(t, handler.GetNodeInfo, map[string]any{})
+	if isErr {
+		t.Fatalf("unexpected error: %v", result["error"])
+	}
+
+	var nodes []NodeInfo
+	decodeInto(t, result["nodes"], &nodes)
+	if len(nodes) != 3 || !reflect.DeepEqual(nodes[0].Roles, []string{"control-plane"}) || nodes[0].Capacity["cpu"] != "4" || nodes[0].Allocatable["cpu"] != "3800m" {
+		t.Fatalf("unexpected nodes %+v", nodes)
+	}
+
+	var groups map[string][]NodeVersionGroup
+	decodeInto(t, result["groups"], &groups)
+
+	wantRuntimes := []NodeVersionGroup{
+		{Value: "containerd://1.7.13", Count: 2, Nodes: []string{"cp-1", "worker-1"}},
+		{Value: "containerd://1.6.28", Count: 1, Nodes: []string{"worker-2"}},
+	}
+	if !reflect.DeepEqual(groups["container_runtime"], wantRuntimes) {
+		t.Errorf("expected runtime groups %+v, got %+v", wantRuntimes, groups["container_runtime"])
+	}
+	if _, ok := groups["kube_proxy_version"]; ok {
+		t.Errorf("expected no kube-proxy group when no node reports it, got %+v", groups["kube_proxy_version"])
+	}
+	if platform := groups["platform"]; len(platform) != 1 || platform[0].Value != "linux/amd64" {
+		t.Errorf("unexpected platform groups %+v", platform)
+	}
+
+	var findings []string
+	decodeInto(t, result["findings"], &findings)
+	if want := []string{"nodes run 2 kubelet minor versions (1.29, 1.30); finish the upgrade so the fleet runs a single version"}; !reflect.DeepEqual(findings, want) {
+		t.Errorf("expected findings %q, got %q", want, findings)
+	}
+
+	result, isErr = callTool(t, handler.GetNodeInfo, map[string]any{"summary_only": true})
+	if isErr {
+		t.Fatalf("unexpected error: %v", result["error"])
+	}
+	if _, ok := result["nodes"]; ok || result["node_count"] != float64(3) {
+		t.Errorf("expected only the summary, got %+v", result)
+	}
+
+	if _, isErr := callTool(t, handler.GetNodeInfo, map[string]any{"node_name": "missing"}); !isErr {
+		t.Error("expected an error for a missing node")
+	}
+}
@@ -0,0 +1,395 @@
+package handlers
+
+import (
+	"context"
+	"sort"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/connectivity"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/kubernetes"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/response"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/toolschema"
+)
+
+const (
+	// labelOS is the well-known node label holding the node's operating system.
+	labelOS = "kubernetes.io/os"
+
+	// labelOSBeta is the deprecated form of labelOS, still honored by some workloads.
+	labelOSBeta = "beta.kubernetes.io/os"
+
+	// labelWindowsBuild is the node label holding the Windows build number.
+	labelWindowsBuild = "node.kubernetes.io/windows-build"
+)
+
+// NodeHandler provides MCP tools for node-level reports, most of which
+// combine node objects with the pods scheduled on them.
+type NodeHandler struct {
+	client      kubernetes.ClusterReader
+	alwaysStart bool
+}
+
+// NewNodeHandler creates a new NodeHandler with the provided Kubernetes client.
+// alwaysStart mirrors the --always-start flag: when true, connectivity and auth errors
+// are intercepted and returned as structured tool errors so the LLM can surface them
+// to the user rather than treating them as retryable failures.
+func NewNodeHandler(client kubernetes.ClusterReader, alwaysStart bool) *NodeHandler {
+	return &NodeHandler{
+		client:      client,
+		alwaysStart: alwaysStart,
+	}
+}
+
+// WindowsReportParams defines the parameters for the windows_report MCP tool.
+type WindowsReportParams struct {
+	// Namespace restricts the pod analysis to a namespace.
+	// If empty, pods across all namespaces are analyzed.
+	Namespace string `json:"namespace,omitempty" description:"Namespace to analyze pods in (leave empty for all namespaces)"`
+
+	// Context specifies which Kubernetes context to use for this operation.
+	// If empty, uses the current context from kubeconfig.
+	Context string `json:"context,omitempty" description:"Kubernetes context to use (defaults to current context from kubeconfig)"`
+}
+
+// WindowsNode describes a Windows node and its OS build details.
+type WindowsNode struct {
+	Name             string   `json:"name"`
+	Ready            bool     `json:"ready"`
+	OSImage          string   `json:"os_image"`
+	KernelVersion    string   `json:"kernel_version"`
+	Build            string   `json:"build,omitempty"`
+	KubeletVersion   string   `json:"kubelet_version"`
+	ContainerRuntime string   `json:"container_runtime"`
+	Taints           []string `json:"taints,omitempty"`
+
+	// Tainted indicates the node has a NoSchedule or NoExecute taint that
+	// keeps workloads without a matching toleration off of it.
+	Tainted bool `json:"tainted"`
+}
+
+// OSMismatchPod describes a pod whose declared operating system does not
+// match the node it was scheduled on, or a pod that cannot be scheduled
+// because no suitable node exists.
+type OSMismatchPod struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Node      string `json:"node,omitempty"`
+	Owner     string `json:"owner,omitempty"`
+	Phase     string `json:"phase"`
+
+	// TargetOS is the operating system the pod declares through spec.os,
+	// nodeSelector, or required node affinity. Empty means undeclared.
+	TargetOS string `json:"target_os,omitempty"`
+
+	// Reason explains why the pod was reported.
+	Reason string `json:"reason"`
+
+	// WaitingReasons lists the waiting reasons of containers that are not
+	// running, such as ImagePullBackOff from pulling a Linux-only image.
+	WaitingReasons []string `json:"waiting_reasons,omitempty"`
+}
+
+// WindowsReportResult is the result of the windows_report MCP tool.
+type WindowsReportResult struct {
+	Namespace                string          `json:"namespace"`
+	WindowsNodeCount         int             `json:"windows_node_count"`
+	TotalNodeCount           int             `json:"total_node_count"`
+	WindowsNodes             []WindowsNode   `json:"windows_nodes"`
+	MismatchedPods           []OSMismatchPod `json:"mismatched_pods"`
+	UnschedulableWindowsPods []OSMismatchPod `json:"unschedulable_windows_pods"`
+	Warnings                 []string        `json:"warnings,omitempty"`
+}
+
+// WindowsReport implements the windows_report MCP tool.
+// It identifies Windows nodes and their build versions, and finds pods that
+// landed on Windows nodes without declaring Windows as their target OS (often
+// Linux-only images) along with Windows pods that cannot be scheduled.
+func (h *NodeHandler) WindowsReport(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var params WindowsReportParams
+	if err := bindParams(request, &params); err != nil {
+		return response.Errorf("failed to parse arguments: %s", err)
+	}
+
+	// Use the appropriate client based on context
+	client, err := h.client.ForContext(ctx, params.Context)
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to create client with context %s: %v", params.Context, err)
+	}
+
+	nodes, err := client.ListNodes(ctx, metav1.ListOptions{})
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to list nodes: %v", err)
+	}
+
+	pods, err := client.ListPods(ctx, params.Namespace, metav1.ListOptions{})
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to list pods: %v", err)
+	}
+
+	windowsNodes := make([]WindowsNode, 0)
+	nodeOS := make(map[string]string, len(nodes.Items))
+	readyWindowsNodes := 0
+	untainted := 0
+
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+		nodeOS[node.Name] = nodeOperatingSystem(node)
+
+		if nodeOS[node.Name] != "windows" {
+			continue
+		}
+
+		info := WindowsNode{
+			Name:             node.Name,
+			Ready:            isNodeReady(node),
+			OSImage:          node.Status.NodeInfo.OSImage,
+			KernelVersion:    node.Status.NodeInfo.KernelVersion,
+			Build:            node.Labels[labelWindowsBuild],
+			KubeletVersion:   node.Status.NodeInfo.KubeletVersion,
+			ContainerRuntime: node.Status.NodeInfo.ContainerRuntimeVersion,
+		}
+
+		for _, taint := range node.Spec.Taints {
+			info.Taints = append(info.Taints, formatTaint(taint))
+			if taint.Effect == corev1.TaintEffectNoSchedule || taint.Effect == corev1.TaintEffectNoExecute {
+				info.Tainted = true
+			}
+		}
+
+		if info.Ready {
+			readyWindowsNodes++
+		}
+		if !info.Tainted {
+			untainted++
+		}
+
+		windowsNodes = append(windowsNodes, info)
+	}
+
+	sort.Slice(windowsNodes, func(i, j int) bool {
+		return windowsNodes[i].Name < windowsNodes[j].Name
+	})
+
+	mismatched := make([]OSMismatchPod, 0)
+	unschedulable := make([]OSMismatchPod, 0)
+
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if entry, ok := classifyWindowsPod(pod, nodeOS, readyWindowsNodes); ok {
+			if pod.Spec.NodeName != "" {
+				mismatched = append(mismatched, entry)
+			} else {
+				unschedulable = append(unschedulable, entry)
+			}
+		}
+	}
+
+	var warnings []string
+	if untainted > 0 {
+		warnings = append(warnings, "Some Windows nodes have no NoSchedule/NoExecute taint, so Linux workloads without a kubernetes.io/os=linux nodeSelector may be scheduled onto them. Consider tainting Windows nodes (e.g. os=windows:NoSchedule) and adding matching tolerations to Windows workloads.")
+	}
+	if len(windowsNodes) > 0 && readyWindowsNodes == 0 {
+		warnings = append(warnings, "No Windows node is Ready, so Windows workloads cannot be scheduled.")
+	}
+
+	return response.JSON(WindowsReportResult{
+		Namespace:                params.Namespace,
+		WindowsNodeCount:         len(windowsNodes),
+		TotalNodeCount:           len(nodes.Items),
+		WindowsNodes:             windowsNodes,
+		MismatchedPods:           mismatched,
+		UnschedulableWindowsPods: unschedulable,
+		Warnings:                 warnings,
+	})
+}
+
+// classifyWindowsPod reports whether pod should be included in the Windows
+// compatibility report. Scheduled pods are reported when they run on a Windows
+// node without declaring Windows, or when they declare Windows but run on a
+// non-Windows node. Pending pods that declare Windows are reported when no
+// Ready Windows node exists to host them.
+func classifyWindowsPod(pod *corev1.Pod, nodeOS map[string]string, readyWindowsNodes int) (OSMismatchPod, bool) {
+	if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+		return OSMismatchPod{}, false
+	}
+
+	target := podTargetOS(pod)
+	entry := OSMismatchPod{
+		Namespace:      pod.Namespace,
+		Name:           pod.Name,
+		Node:           pod.Spec.NodeName,
+		Owner:          podOwner(pod),
+		Phase:          string(pod.Status.Phase),
+		TargetOS:       target,
+		WaitingReasons: waitingReasons(pod),
+	}
+
+	if pod.Spec.NodeName == "" {
+		if target == "windows" && readyWindowsNodes == 0 {
+			entry.Reason = "pod targets Windows but there are no Ready Windows nodes"
+			return entry, true
+		}
+		return OSMismatchPod{}, false
+	}
+
+	switch runningOS := nodeOS[pod.Spec.NodeName]; {
+	case runningOS == "":
+		// The node is gone or reports no OS; nothing to compare against.
+		return OSMismatchPod{}, false
+	case runningOS == "windows" && target == "":
+		entry.Reason = "pod runs on a Windows node but does not declare a target OS (spec.os or kubernetes.io/os nodeSelector); it may be a Linux-only workload"
+		return entry, true
+	case runningOS == "windows" && target != "windows":
+		entry.Reason = "pod targets " + target + " but runs on a Windows node"
+		return entry, true
+	case runningOS != "windows" && target == "windows":
+		entry.Reason = "pod targets Windows but runs on a " + runningOS + " node"
+		return entry, true
+	}
+
+	return OSMismatchPod{}, false
+}
+
+// podTargetOS returns the operating system a pod declares through spec.os, a
+// kubernetes.io/os nodeSelector, or a required node affinity term selecting a
+// single OS. It returns an empty string when the pod does not declare one.
+func podTargetOS(pod *corev1.Pod) string {
+	if pod.Spec.OS != nil && pod.Spec.OS.Name != "" {
+		return string(pod.Spec.OS.Name)
+	}
+
+	for _, key := range []string{labelOS, labelOSBeta} {
+		if value, ok := pod.Spec.NodeSelector[key]; ok {
+			return value
+		}
+	}
+
+	if affinity := pod.Spec.Affinity; affinity != nil && affinity.NodeAffinity != nil {
+		if required := affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution; required != nil {
+			for _, term := range required.NodeSelectorTerms {
+				for _, expr := range term.MatchExpressions {
+					if (expr.Key == labelOS || expr.Key == labelOSBeta) &&
+						expr.Operator == corev1.NodeSelectorOpIn && len(expr.Values) == 1 {
+						return expr.Values[0]
+					}
+				}
+			}
+		}
+	}
+
+	return ""
+}
+
+// nodeOperatingSystem returns the node's operating system from its labels,
+// falling back to the value reported by the kubelet.
+func nodeOperatingSystem(node *corev1.Node) string {
+	if value := node.Labels[labelOS]; value != "" {
+		return value
+	}
+	if value := node.Labels[labelOSBeta]; value != "" {
+		return value
+	}
+	return node.Status.NodeInfo.OperatingSystem
+}
+
+// isNodeReady reports whether the node's Ready condition is True.
+func isNodeReady(node *corev1.Node) bool {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// formatTaint renders a taint the way kubectl does: key=value:Effect.
+func formatTaint(taint corev1.Taint) string {
+	if taint.Value == "" {
+		return taint.Key + ":" + string(taint.Effect)
+	}
+	return taint.Key + "=" + taint.Value + ":" + string(taint.Effect)
+}
+
+// podOwner returns the controlling owner of a pod as "Kind/name", or an empty
+// string for standalone pods.
+func podOwner(pod *corev1.Pod) string {
+	if owner := metav1.GetControllerOf(pod); owner != nil {
+		return owner.Kind + "/" + owner.Name
+	}
+	return ""
+}
+
+// waitingReasons returns the distinct waiting reasons across a pod's init and
+// regular containers.
+func waitingReasons(pod *corev1.Pod) []string {
+	var reasons []string
+	seen := make(map[string]bool)
+
+	statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+	for i := range statuses {
+		if waiting := statuses[i].State.Waiting; waiting != nil && waiting.Reason != "" && !seen[waiting.Reason] {
+			seen[waiting.Reason] = true
+			reasons = append(reasons, waiting.Reason)
+		}
+	}
+
+	return reasons
+}
+
+// GetTools returns all node-related MCP tools provided by this handler.
+func (h *NodeHandler) GetTools() []MCPTool {
+	return []MCPTool{
+		NewMCPTool(
+			mcp.NewTool("windows_report",
+				mcp.WithDescription("Report Windows nodes with their OS image, build, and kubelet versions, and find workloads that landed on the wrong OS (pods on Windows nodes that do not declare Windows, often Linux-only images) or Windows pods that cannot be scheduled. Useful for mixed-OS clusters."),
+				toolschema.Input[WindowsReportParams](),
+				toolschema.Output[WindowsReportResult](),
+			),
+			h.WindowsReport,
+		),
+		NewMCPTool(
+			mcp.NewTool("get_node_conditions",
+				mcp.WithDescription("Summarize node health across the cluster: Ready, MemoryPressure, DiskPressure, PIDPressure, and NetworkUnavailable conditions (plus any custom conditions such as those from node-problem-detector), taints, cordon state, kubelet version, and last heartbeat. Unhealthy nodes are listed first; use unhealthy_only to hide healthy ones."),
+				toolschema.Input[GetNodeConditionsParams](),
+				toolschema.Output[GetNodeConditionsResult](),
+			),
+			h.GetNodeConditions,
+		),
+		NewMCPTool(
+			mcp.NewTool("topology_report",
+				mcp.WithDescription("Summarize node distribution across topology.kubernetes.io zones and regions, and report how each workload's running replicas are spread across zones, flagging workloads whose replicas are all concentrated in a single zone. Useful for availability reviews."),
+				toolschema.Input[TopologyReportParams](),
+				toolschema.Output[TopologyReportResult](),
+			),
+			h.TopologyReport,
+		),
+		NewMCPTool(
+			mcp.NewTool("node_images_report",
+				mcp.WithDescription("Report container image storage per node: the number and total size of images from node status, and image filesystem capacity, usage, and percentage from the kubelet stats summary. Rates each node's risk of image garbage collection storms against the kubelet's default 85%/80% image GC thresholds and DiskPressure, listing the riskiest nodes first. Kubelet stats need get access to nodes/proxy."),
+				toolschema.Input[NodeImagesReportParams](),
+				toolschema.Output[NodeImagesReportResult](),
+			),
+			h.NodeImagesReport,
+		),
+		NewMCPTool(
+			mcp.NewTool("get_node_info",
+				mcp.WithDescription("Report the OS image, kernel, container runtime, kubelet and kube-proxy versions, OS and architecture, roles, labels, capacity, and allocatable resources of each node, and group nodes by each of those properties to spot version skew across the fleet. Flags fleets running more than one kubelet minor version and kubelet or kube-proxy versions the version skew policy does not allow against the API server. Use summary_only for large fleets."),
+				toolschema.Input[GetNodeInfoParams](),
+				toolschema.Output[GetNodeInfoResult](),
+			),
+			h.GetNodeInfo,
+		),
+	}
+}
@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPodTargetOS(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		spec corev1.PodSpec
+		want string
+	}{
+		{
+			name: "undeclared",
+			spec: corev1.PodSpec{},
+			want: "",
+		},
+		{
+			name: "spec.os takes precedence",
+			spec: corev1.PodSpec{
+				OS:           &corev1.PodOS{Name: corev1.Windows},
+				NodeSelector: map[string]string{labelOS: "linux"},
+			},
+			want: "windows",
+		},
+		{
+			name: "nodeSelector",
+			spec: corev1.PodSpec{NodeSelector: map[string]string{labelOS: "linux"}},
+			want: "linux",
+		},
+		{
+			name: "deprecated beta nodeSelector",
+			spec: corev1.PodSpec{NodeSelector: map[string]string{labelOSBeta: "windows"}},
+			want: "windows",
+		},
+		{
+			name: "required node affinity with a single value",
+			spec: corev1.PodSpec{Affinity: &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+				RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+					NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+						MatchExpressions: []corev1.NodeSelectorRequirement{{
+							Key:      labelOS,
+							Operator: corev1.NodeSelectorOpIn,
+							Values:   []string{"windows"},
+						}},
+					}},
+				},
+			}}},
+			want: "windows",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := podTargetOS(&corev1.Pod{Spec: tt.spec}); got != tt.want {
+				t.Fatalf("podTargetOS() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClassifyWindowsPod(t *testing.T) {
+	t.Parallel()
+
+	nodeOS := map[string]string{"win-1": "windows", "linux-1": "linux"}
+
+	tests := []struct {
+		name       string
+		pod        corev1.Pod
+		readyNodes int
+		wantReport bool
+	}{
+		{
+			name:       "undeclared pod on windows node is reported",
+			pod:        corev1.Pod{Spec: corev1.PodSpec{NodeName: "win-1"}, Status: corev1.PodStatus{Phase: corev1.PodPending}},
+			readyNodes: 1,
+			wantReport: true,
+		},
+		{
+			name: "windows pod on windows node is fine",
+			pod: corev1.Pod{Spec: corev1.PodSpec{
+				NodeName:     "win-1",
+				NodeSelector: map[string]string{labelOS: "windows"},
+			}},
+			readyNodes: 1,
+		},
+		{
+			name:       "undeclared pod on linux node is fine",
+			pod:        corev1.Pod{Spec: corev1.PodSpec{NodeName: "linux-1"}},
+			readyNodes: 1,
+		},
+		{
+			name:       "pending windows pod without ready windows nodes is reported",
+			pod:        corev1.Pod{Spec: corev1.PodSpec{OS: &corev1.PodOS{Name: corev1.Windows}}},
+			readyNodes: 0,
+			wantReport: true,
+		},
+		{
+			name:       "pending windows pod with ready windows nodes is not reported",
+			pod:        corev1.Pod{Spec: corev1.PodSpec{OS: &corev1.PodOS{Name: corev1.Windows}}},
+			readyNodes: 1,
+		},
+		{
+			name: "completed pods are ignored",
+			pod: corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "job"},
+				Spec:       corev1.PodSpec{NodeName: "win-1"},
+				Status:     corev1.PodStatus{Phase: corev1.PodSucceeded},
+			},
+			readyNodes: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, got := classifyWindowsPod(&tt.pod, nodeOS, tt.readyNodes)
+			if got != tt.wantReport {
+				t.Fatalf("classifyWindowsPod() reported = %v, want %v", got, tt.wantReport)
+			}
+		})
+	}
+}
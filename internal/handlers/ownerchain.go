@@ -0,0 +1,237 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/connectivity"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/resourcefilter"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/response"
+)
+
+// maxOwnerChainDepth bounds how many owners get_owner_chain follows, in case
+// owner references form a loop that UIDs do not catch.
+const maxOwnerChainDepth = 10
+
+// GetOwnerChainParams defines the parameters for the get_owner_chain MCP tool.
+type GetOwnerChainParams struct {
+	// ResourceType specifies the type of the resource to start from.
+	ResourceType string `json:"resource_type" required:"true" description:"Resource type of the starting object (e.g., pods, jobs, replicasets)"`
+
+	// APIVersion disambiguates resource types served by several API groups.
+	APIVersion string `json:"api_version,omitempty" description:"API version of the starting object (e.g., 'v1', 'apps/v1'). Optional, used to disambiguate resource types"`
+
+	// Namespace specifies the namespace of the starting object.
+	Namespace string `json:"namespace,omitempty" description:"Namespace of the starting object (leave empty for cluster-scoped resources)"`
+
+	// Name specifies the starting object.
+	Name string `json:"name" required:"true" description:"Name of the starting object"`
+
+	// Context specifies which Kubernetes context to use for this operation.
+	Context string `json:"context,omitempty" description:"Kubernetes context to use (defaults to current context from kubeconfig)"`
+}
+
+// OwnerLink is one object in an owner chain.
+type OwnerLink struct {
+	Kind       string `json:"kind"`
+	APIVersion string `json:"api_version"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name"`
+	UID        string `json:"uid,omitempty"`
+
+	// Controller is true when this object is the managing controller of the
+	// previous link, rather than just one of its owners.
+	Controller bool `json:"controller,omitempty"`
+
+	// Missing is true when the previous link references this owner but it no
+	// longer exists, which leaves the previous link orphaned.
+	Missing bool `json:"missing,omitempty"`
+
+	// OtherOwners lists the owners of this object that the chain does not
+	// follow, as kind/name.
+	OtherOwners []string `json:"other_owners,omitempty"`
+}
+
+// GetOwnerChainResult is the result of the get_owner_chain MCP tool.
+type GetOwnerChainResult struct {
+	// Chain starts with the requested object and ends with Root, the
+	// top-level owner. Depth is the number of owners above the object.
+	Chain []OwnerLink `json:"chain"`
+	Root  OwnerLink   `json:"root"`
+	Depth int         `json:"depth"`
+
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// GetOwnerChain implements the get_owner_chain MCP tool.
+// It reads a resource and follows its ownerReferences upward, preferring the
+// controller reference at each step, until it reaches an object without
+// owners: for example Pod, ReplicaSet, Deployment, or Pod, Job, CronJob.
+// The chain stops early at owners that no longer exist, at resource types
+// disabled by configuration, and after maxOwnerChainDepth links.
+func (h *ResourceHandler) GetOwnerChain(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var params GetOwnerChainParams
+	if err := bindParams(request, &params); err != nil {
+		return response.Errorf("failed to parse arguments: %s", err)
+	}
+
+	// Use the appropriate client based on context
+	client, err := h.client.ForContext(ctx, params.Context)
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to create client with context %s: %v", params.Context, err)
+	}
+
+	gvr, err := client.ResolveResourceType(params.ResourceType, params.APIVersion)
+	if err != nil {
+		if h.alwaysStart && connectivity.IsError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to resolve resource type: %v", err)
+	}
+
+	if h.resourceFilter != nil && h.resourceFilter.IsDisabled(gvr) {
+		if initErr := h.resourceFilter.InitError(); initErr != nil {
+			if h.alwaysStart && connectivity.IsError(initErr) {
+				return response.Error(connectivity.ErrorMessage(initErr))
+			}
+			return response.Errorf("resource filter could not be initialized: %v", initErr)
+		}
+		return response.Errorf("access to resource %q (%s) is disabled by configuration and cannot be queried",
+			params.ResourceType, resourcefilter.FormatGVR(gvr))
+	}
+
+	current, err := client.GetResource(ctx, gvr, params.Namespace, params.Name)
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to get resource: %v", err)
+	}
+
+	var warnings []string
+
+	link, owner := ownerLink(current)
+	chain := []OwnerLink{link}
+	seen := map[types.UID]bool{current.GetUID(): true}
+
+	for owner != nil {
+		if len(chain) > maxOwnerChainDepth {
+			warnings = append(warnings, fmt.Sprintf("stopped after %d owners", maxOwnerChainDepth))
+			break
+		}
+
+		if owner.UID != "" && seen[owner.UID] {
+			warnings = append(warnings, fmt.Sprintf("owner references loop back to %s %s", owner.Kind, owner.Name))
+			break
+		}
+		seen[owner.UID] = true
+
+		// Owners always live in the namespace of their dependents, and
+		// cluster-scoped objects can only be owned by cluster-scoped ones.
+		namespace := current.GetNamespace()
+
+		ownerGVR, err := client.ResolveResourceType(owner.Kind, owner.APIVersion)
+		if err != nil {
+			// The owner's API version may not be the preferred one.
+			ownerGVR, err = client.ResolveResourceType(owner.Kind, "")
+		}
+		if err != nil {
+			if h.alwaysStart && connectivity.IsError(err) {
+				return response.Error(connectivity.ErrorMessage(err))
+			}
+			warnings = append(warnings, fmt.Sprintf("failed to resolve the type of owner %s %s: %v", owner.Kind, owner.Name, err))
+			break
+		}
+
+		if h.resourceFilter != nil && h.resourceFilter.IsDisabled(ownerGVR) {
+			warnings = append(warnings, fmt.Sprintf("owner %s %s is a %s, which is disabled by configuration; the chain stops here",
+				owner.Kind, owner.Name, resourcefilter.FormatGVR(ownerGVR)))
+			break
+		}
+
+		next, err := client.GetResource(ctx, ownerGVR, namespace, owner.Name)
+		if err != nil {
+			if h.alwaysStart && connectivity.IsTransportError(err) {
+				return response.Error(connectivity.ErrorMessage(err))
+			}
+
+			if apierrors.IsNotFound(err) {
+				chain = append(chain, OwnerLink{
+					Kind:       owner.Kind,
+					APIVersion: owner.APIVersion,
+					Namespace:  namespace,
+					Name:       owner.Name,
+					UID:        string(owner.UID),
+					Controller: owner.Controller != nil && *owner.Controller,
+					Missing:    true,
+				})
+				break
+			}
+
+			warnings = append(warnings, fmt.Sprintf("failed to get owner %s %s: %v", owner.Kind, owner.Name, err))
+			break
+		}
+
+		if owner.UID != "" && next.GetUID() != "" && next.GetUID() != owner.UID {
+			warnings = append(warnings, fmt.Sprintf("%s %s was recreated after %s %s referenced it, so it is no longer its owner",
+				owner.Kind, owner.Name, current.GetKind(), current.GetName()))
+		}
+
+		link, nextOwner := ownerLink(next)
+		link.Controller = owner.Controller != nil && *owner.Controller
+		chain = append(chain, link)
+
+		current, owner = next, nextOwner
+	}
+
+	return response.JSON(GetOwnerChainResult{
+		Chain:    chain,
+		Root:     chain[len(chain)-1],
+		Depth:    len(chain) - 1,
+		Warnings: warnings,
+	})
+}
+
+// ownerLink describes obj as a chain link and returns the owner reference
+// to follow next: the controller reference if there is one, otherwise the
+// first owner. It returns a nil owner for objects without owners.
+func ownerLink(obj *unstructured.Unstructured) (OwnerLink, *metav1.OwnerReference) {
+	link := OwnerLink{
+		Kind:       obj.GetKind(),
+		APIVersion: obj.GetAPIVersion(),
+		Namespace:  obj.GetNamespace(),
+		Name:       obj.GetName(),
+		UID:        string(obj.GetUID()),
+	}
+
+	refs := obj.GetOwnerReferences()
+	if len(refs) == 0 {
+		return link, nil
+	}
+
+	follow := 0
+	for i := range refs {
+		if refs[i].Controller != nil && *refs[i].Controller {
+			follow = i
+			break
+		}
+	}
+
+	for i := range refs {
+		if i != follow {
+			link.OtherOwners = append(link.OtherOwners, refs[i].Kind+"/"+refs[i].Name)
+		}
+	}
+
+	owner := refs[follow]
+	return link, &owner
+}
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"reflect"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/fakecluster"
+)
+
+func TestGetOwnerChain_FakeCluster(t *testing.T) {
+	t.Parallel()
+
+	isController := true
+	ownedBy := func(apiVersion, kind, name, uid string) []metav1.OwnerReference {
+		return []metav1.OwnerReference{{APIVersion: apiVersion, Kind: kind, Name: name, UID: types.UID(uid), Controller: &isController}}
+	}
+
+	handler := NewResourceHandler(fakecluster.New(fakecluster.Config{
+		Objects: []runtime.Object{
+			&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop", UID: "deploy-uid"}},
+			&appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{
+				Name: "web-5d4f8", Namespace: "shop", UID: "rs-uid",
+				OwnerReferences: ownedBy("apps/v1", "Deployment", "web", "deploy-uid"),
+			}},
+			&corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+				Name: "web-5d4f8-x2x8q", Namespace: "shop", UID: "pod-uid",
+				OwnerReferences: ownedBy("apps/v1", "ReplicaSet", "web-5d4f8", "rs-uid"),
+			}},
+			&batchv1.Job{ObjectMeta: metav1.ObjectMeta{
+				Name: "nightly-29000000", Namespace: "shop", UID: "job-uid",
+				OwnerReferences: ownedBy("batch/v1", "CronJob", "nightly", "cronjob-uid"),
+			}},
+			&corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+				Name: "nightly-29000000-abcde", Namespace: "shop", UID: "job-pod-uid",
+				OwnerReferences: ownedBy("batch/v1", "Job", "nightly-29000000", "job-uid"),
+			}},
+		},
+	}), nil, false)
+
+	tests := []struct {
+		name      string
+		pod       string
+		wantChain []string
+		wantRoot  OwnerLink
+	}{
+		{
+			name:      "pod owned by a deployment",
+			pod:       "web-5d4f8-x2x8q",
+			wantChain: []string{"Pod/web-5d4f8-x2x8q", "ReplicaSet/web-5d4f8", "Deployment/web"},
+			wantRoot:  OwnerLink{Kind: "Deployment", APIVersion: "apps/v1", Namespace: "shop", Name: "web", UID: "deploy-uid", Controller: true},
+		},
+		{
+			name:      "cronjob that no longer exists",
+			pod:       "nightly-29000000-abcde",
+			wantChain: []string{"Pod/nightly-29000000-abcde", "Job/nightly-29000000", "CronJob/nightly"},
+			wantRoot:  OwnerLink{Kind: "CronJob", APIVersion: "batch/v1", Namespace: "shop", Name: "nightly", UID: "cronjob-uid", Controller: true, Missing: true},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			result, isErr := callTool(t, handler.GetOwnerChain, map[string]any{
+				"resource_type": "pods",
+				"namespace":     "shop",
+				"name":          tt.pod,
+			})
+			if isErr {
+				t.Fatalf("unexpected error: %v", result["error"])
+			}
+
+			var chain []OwnerLink
+			decodeInto(t, result["chain"], &chain)
+
+			var got []string
+			for _, link := range chain {
+				got = append(got, link.Kind+"/"+link.Name)
+			}
+			if !reflect.DeepEqual(got, tt.wantChain) {
+				t.Errorf("expected chain %v, got %v", tt.wantChain, got)
+			}
+
+			var root OwnerLink
+			decodeInto(t, result["root"], &root)
+			if !reflect.DeepEqual(root, tt.wantRoot) {
+				t.Errorf("expected root %+v, got %+v", tt.wantRoot, root)
+			}
+		})
+	}
+}
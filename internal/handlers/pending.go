@@ -0,0 +1,374 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/connectivity"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/response"
+)
+
+// WhyPendingParams defines the parameters for the why_pending MCP tool.
+type WhyPendingParams struct {
+	// Namespace specifies the pod's namespace.
+	Namespace string `json:"namespace" required:"true" description:"Pod namespace"`
+
+	// Name specifies which pod to analyze.
+	Name string `json:"name" required:"true" description:"Pod name"`
+
+	// Context specifies which Kubernetes context to use for this operation.
+	Context string `json:"context,omitempty" description:"Kubernetes context to use (defaults to current context from kubeconfig)"`
+}
+
+// SchedulingBlockerCount is how many nodes reject a pod for the same reason.
+type SchedulingBlockerCount struct {
+	Reason string `json:"reason"`
+	Nodes  int    `json:"nodes"`
+}
+
+// UnfitNode is a node that cannot run the pod, with every reason why.
+type UnfitNode struct {
+	Name     string   `json:"name"`
+	Blockers []string `json:"blockers"`
+}
+
+// PodVolumeClaim is a PersistentVolumeClaim a pod mounts and whether it can
+// be bound.
+type PodVolumeClaim struct {
+	Volume       string `json:"volume"`
+	Claim        string `json:"claim"`
+	Phase        string `json:"phase,omitempty"`
+	StorageClass string `json:"storage_class,omitempty"`
+
+	// Problem explains why the claim can keep the pod from scheduling.
+	Problem string `json:"problem,omitempty"`
+
+	// blocking is true when the claim prevents scheduling on every node.
+	blocking bool
+}
+
+// WhyPendingResult is the result of the why_pending MCP tool. The scheduling
+// details are only reported for pods waiting to be scheduled.
+type WhyPendingResult struct {
+	Namespace        string                   `json:"namespace"`
+	Name             string                   `json:"name"`
+	Phase            string                   `json:"phase"`
+	Pending          bool                     `json:"pending"`
+	Scheduled        bool                     `json:"scheduled"`
+	Node             string                   `json:"node,omitempty"`
+	Summary          string                   `json:"summary"`
+	SchedulerMessage string                   `json:"scheduler_message,omitempty"`
+	Requests         map[string]string        `json:"requests,omitzero"`
+	TotalNodes       int                      `json:"total_nodes,omitempty"`
+	FeasibleNodes    []string                 `json:"feasible_nodes,omitzero"`
+	Blockers         []SchedulingBlockerCount `json:"blockers,omitzero"`
+	UnfitNodes       []UnfitNode              `json:"unfit_nodes,omitzero"`
+	Volumes          []PodVolumeClaim         `json:"volumes,omitzero"`
+	Notes            []string                 `json:"notes,omitempty"`
+	Warnings         []string                 `json:"warnings,omitempty"`
+}
+
+// WhyPending implements the why_pending MCP tool.
+// It explains why a Pending pod is not scheduled by combining the scheduler's
+// FailedScheduling events with its own evaluation of every node: cordoning,
+// untolerated taints, the node selector, required node affinity, and the
+// requests of the pod against each node's free allocatable resources. It also
+// checks that the PersistentVolumeClaims the pod mounts exist and are bound.
+// Inter-pod affinity and topology spread constraints are not evaluated.
+func (h *PodHandler) WhyPending(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var params WhyPendingParams
+	if err := bindParams(request, &params); err != nil {
+		return response.Errorf("failed to parse arguments: %s", err)
+	}
+
+	// Use the appropriate client based on context
+	client, err := h.client.ForContext(ctx, params.Context)
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to create client with context %s: %v", params.Context, err)
+	}
+
+	pod, err := client.GetPod(ctx, params.Namespace, params.Name)
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("%v", err)
+	}
+
+	result := WhyPendingResult{
+		Namespace: pod.Namespace,
+		Name:      pod.Name,
+		Phase:     string(pod.Status.Phase),
+		Pending:   pod.Status.Phase == corev1.PodPending,
+		Scheduled: pod.Spec.NodeName != "",
+	}
+
+	if pod.Status.Phase != corev1.PodPending {
+		result.Summary = fmt.Sprintf("the pod is not pending; its phase is %s", pod.Status.Phase)
+		return response.JSON(result)
+	}
+
+	if pod.Spec.NodeName != "" {
+		result.Node = pod.Spec.NodeName
+		result.Summary = fmt.Sprintf("the pod is scheduled on node %s and is pending because its containers have not started yet; use diagnose_pod to see why", pod.Spec.NodeName)
+		return response.JSON(result)
+	}
+
+	nodes, err := client.ListNodes(ctx, metav1.ListOptions{})
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to list nodes: %v", err)
+	}
+
+	var warnings []string
+
+	schedulerMessage := ""
+	selector := fields.Set{"involvedObject.kind": "Pod", "involvedObject.name": pod.Name}.AsSelector().String()
+	if list, err := client.ListEvents(ctx, pod.Namespace, metav1.ListOptions{FieldSelector: selector}); err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		warnings = append(warnings, fmt.Sprintf("failed to list events: %v", err))
+	} else {
+		for _, event := range podEvents(list.Items, pod) {
+			if event.Reason == "FailedScheduling" {
+				schedulerMessage = event.Message
+				break
+			}
+		}
+	}
+
+	// A nil map skips the resource checks when pods cannot be listed.
+	var requested map[string]corev1.ResourceList
+	var podCounts map[string]int64
+	if pods, err := client.ListPods(ctx, "", metav1.ListOptions{}); err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		warnings = append(warnings, fmt.Sprintf("failed to list pods, resource requests were not checked: %v", err))
+	} else {
+		requested, podCounts = nodeAllocations(pods.Items)
+	}
+
+	podRequests := podResourceRequests(pod)
+	feasible, unfit, blockers := evaluateNodes(pod, nodes.Items, podRequests, requested, podCounts)
+
+	volumes := make([]PodVolumeClaim, 0)
+	if claimNames := podClaimNames(pod); len(claimNames) > 0 {
+		claims, err := client.ListPersistentVolumeClaims(ctx, pod.Namespace, metav1.ListOptions{})
+		if err != nil {
+			if h.alwaysStart && connectivity.IsTransportError(err) {
+				return response.Error(connectivity.ErrorMessage(err))
+			}
+			warnings = append(warnings, fmt.Sprintf("failed to list persistent volume claims: %v", err))
+		} else {
+			volumes = podVolumeClaims(pod, claims.Items)
+		}
+	}
+
+	result.Summary = pendingSummary(len(nodes.Items), feasible, blockers, volumes, schedulerMessage)
+	result.SchedulerMessage = schedulerMessage
+	result.Requests = formatResources(podRequests)
+	result.TotalNodes = len(nodes.Items)
+	result.FeasibleNodes = feasible
+	result.Blockers = blockers
+	result.UnfitNodes = unfit
+	result.Volumes = volumes
+	result.Notes = pendingNotes(pod)
+	result.Warnings = warnings
+
+	return response.JSON(result)
+}
+
+// evaluateNodes checks the pod against every node. It returns the names of
+// the nodes that can run the pod, the nodes that cannot along with why, and
+// how many nodes each reason rejects, most common first.
+func evaluateNodes(pod *corev1.Pod, nodes []corev1.Node, podRequests corev1.ResourceList, requested map[string]corev1.ResourceList, podCounts map[string]int64) ([]string, []UnfitNode, []SchedulingBlockerCount) {
+	feasible := make([]string, 0)
+	unfit := make([]UnfitNode, 0)
+	counts := make(map[string]int)
+
+	for i := range nodes {
+		node := &nodes[i]
+
+		var nodeRequested corev1.ResourceList
+		if requested != nil {
+			nodeRequested = requested[node.Name]
+			if nodeRequested == nil {
+				nodeRequested = corev1.ResourceList{}
+			}
+		}
+
+		found := nodeSchedulingBlockers(pod, node, podRequests, nodeRequested, podCounts[node.Name])
+		if len(found) == 0 {
+			feasible = append(feasible, node.Name)
+			continue
+		}
+
+		entry := UnfitNode{Name: node.Name}
+		seen := make(map[string]bool)
+		for _, blocker := range found {
+			message := blocker.reason
+			if blocker.detail != "" {
+				message += " (" + blocker.detail + ")"
+			}
+			entry.Blockers = append(entry.Blockers, message)
+
+			if !seen[blocker.reason] {
+				seen[blocker.reason] = true
+				counts[blocker.reason]++
+			}
+		}
+		unfit = append(unfit, entry)
+	}
+
+	sort.Strings(feasible)
+	sort.Slice(unfit, func(i, j int) bool { return unfit[i].Name < unfit[j].Name })
+
+	blockers := make([]SchedulingBlockerCount, 0, len(counts))
+	for reason, count := range counts {
+		blockers = append(blockers, SchedulingBlockerCount{Reason: reason, Nodes: count})
+	}
+	sort.Slice(blockers, func(i, j int) bool {
+		if blockers[i].Nodes != blockers[j].Nodes {
+			return blockers[i].Nodes > blockers[j].Nodes
+		}
+		return blockers[i].Reason < blockers[j].Reason
+	})
+
+	return feasible, unfit, blockers
+}
+
+// podClaimNames maps the pod's volumes to the PersistentVolumeClaims they
+// use, including the claims created for generic ephemeral volumes.
+func podClaimNames(pod *corev1.Pod) map[string]string {
+	names := make(map[string]string)
+
+	for _, volume := range pod.Spec.Volumes {
+		switch {
+		case volume.PersistentVolumeClaim != nil:
+			names[volume.Name] = volume.PersistentVolumeClaim.ClaimName
+		case volume.Ephemeral != nil:
+			names[volume.Name] = pod.Name + "-" + volume.Name
+		}
+	}
+
+	return names
+}
+
+// podVolumeClaims reports the state of every claim the pod mounts. Missing,
+// lost, and terminating claims block scheduling; Pending claims may only be
+// waiting for the pod to be scheduled.
+func podVolumeClaims(pod *corev1.Pod, claims []corev1.PersistentVolumeClaim) []PodVolumeClaim {
+	byName := make(map[string]*corev1.PersistentVolumeClaim, len(claims))
+	for i := range claims {
+		byName[claims[i].Name] = &claims[i]
+	}
+
+	names := podClaimNames(pod)
+	volumes := make([]PodVolumeClaim, 0, len(names))
+
+	for _, volume := range sortedKeys(names) {
+		entry := PodVolumeClaim{Volume: volume, Claim: names[volume]}
+
+		claim, ok := byName[entry.Claim]
+		if !ok {
+			entry.Problem = "the claim does not exist"
+			entry.blocking = true
+			volumes = append(volumes, entry)
+			continue
+		}
+
+		entry.Phase = string(claim.Status.Phase)
+		if claim.Spec.StorageClassName != nil {
+			entry.StorageClass = *claim.Spec.StorageClassName
+		}
+
+		switch {
+		case claim.DeletionTimestamp != nil:
+			entry.Problem = "the claim is being deleted"
+			entry.blocking = true
+		case claim.Status.Phase == corev1.ClaimLost:
+			entry.Problem = "the claim lost its PersistentVolume"
+			entry.blocking = true
+		case claim.Status.Phase == corev1.ClaimPending:
+			entry.Problem = "the claim is not bound; with WaitForFirstConsumer binding this is expected until the pod is scheduled, otherwise check the provisioner or that a matching PersistentVolume exists"
+		}
+
+		volumes = append(volumes, entry)
+	}
+
+	return volumes
+}
+
+// pendingSummary explains in one sentence why the pod is not scheduled.
+func pendingSummary(totalNodes int, feasible []string, blockers []SchedulingBlockerCount, volumes []PodVolumeClaim, schedulerMessage string) string {
+	for _, volume := range volumes {
+		if volume.blocking {
+			return fmt.Sprintf("volume %s cannot be used: claim %s: %s", volume.Volume, volume.Claim, volume.Problem)
+		}
+	}
+
+	if totalNodes == 0 {
+		return "the cluster has no nodes"
+	}
+
+	if len(feasible) == 0 {
+		return noFeasibleNodeSummary(totalNodes, blockers)
+	}
+
+	for _, volume := range volumes {
+		if volume.Problem != "" {
+			return fmt.Sprintf("%d/%d nodes can run the pod, but claim %s is not bound yet", len(feasible), totalNodes, volume.Claim)
+		}
+	}
+
+	summary := fmt.Sprintf("%d/%d nodes appear able to run the pod, so it is likely blocked by a constraint not evaluated here, such as inter-pod affinity, topology spread, or volume topology", len(feasible), totalNodes)
+	if schedulerMessage != "" {
+		summary += "; the scheduler reports: " + schedulerMessage
+	}
+
+	return summary
+}
+
+// noFeasibleNodeSummary explains why no node can run a pod, counting the
+// nodes rejected for each reason like the scheduler's FailedScheduling message.
+func noFeasibleNodeSummary(totalNodes int, blockers []SchedulingBlockerCount) string {
+	parts := make([]string, 0, len(blockers))
+	for _, blocker := range blockers {
+		parts = append(parts, fmt.Sprintf("%d %s", blocker.Nodes, blocker.Reason))
+	}
+	return fmt.Sprintf("0/%d nodes can run the pod: %s", totalNodes, strings.Join(parts, ", "))
+}
+
+// pendingNotes lists scheduling inputs that why_pending does not evaluate
+// but that the pod uses, and any preemption in progress.
+func pendingNotes(pod *corev1.Pod) []string {
+	var notes []string
+
+	if pod.Status.NominatedNodeName != "" {
+		notes = append(notes, fmt.Sprintf("the scheduler preempted pods on node %s to make room; the pod should be scheduled there once they terminate", pod.Status.NominatedNodeName))
+	}
+
+	if affinity := pod.Spec.Affinity; affinity != nil && (affinity.PodAffinity != nil || affinity.PodAntiAffinity != nil) {
+		notes = append(notes, "the pod uses inter-pod affinity or anti-affinity, which is not evaluated here")
+	}
+
+	if len(pod.Spec.TopologySpreadConstraints) > 0 {
+		notes = append(notes, "the pod uses topology spread constraints, which are not evaluated here")
+	}
+
+	return notes
+}
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/fakecluster"
+)
+
+func TestWhyPending_FakeCluster(t *testing.T) {
+	t.Parallel()
+
+	node := func(name string, cpu string, taints ...corev1.Taint) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       corev1.NodeSpec{Taints: taints},
+			Status: corev1.NodeStatus{Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU:  resource.MustParse(cpu),
+				corev1.ResourcePods: resource.MustParse("110"),
+			}},
+		}
+	}
+
+	cpuRequest := func(cpu string) []corev1.Container {
+		return []corev1.Container{{Name: "app", Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{
+			corev1.ResourceCPU: resource.MustParse(cpu),
+		}}}}
+	}
+
+	handler := NewPodHandler(fakecluster.New(fakecluster.Config{
+		Objects: []runtime.Object{
+			node("control-plane", "4", corev1.Taint{Key: "node-role.kubernetes.io/control-plane", Effect: corev1.TaintEffectNoSchedule}),
+			node("worker-1", "2"),
+			node("worker-2", "2"),
+			&corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "busy", Namespace: "other"},
+				Spec:       corev1.PodSpec{NodeName: "worker-1", Containers: cpuRequest("1500m")},
+				Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+			},
+			&corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "big", Namespace: "shop"},
+				Spec:       corev1.PodSpec{Containers: cpuRequest("3")},
+				Status:     corev1.PodStatus{Phase: corev1.PodPending},
+			},
+			&corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "small", Namespace: "shop"},
+				Spec: corev1.PodSpec{
+					Containers: cpuRequest("1"),
+					Volumes: []corev1.Volume{{Name: "data", VolumeSource: corev1.VolumeSource{
+						PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "missing"},
+					}}},
+				},
+				Status: corev1.PodStatus{Phase: corev1.PodPending},
+			},
+			&corev1.Event{
+				ObjectMeta:     metav1.ObjectMeta{Name: "big.1", Namespace: "shop"},
+				InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "big", Namespace: "shop"},
+				Type:           corev1.EventTypeWarning,
+				Reason:         "FailedScheduling",
+				Message:        "0/3 nodes are available: 1 node(s) had untolerated taint, 2 Insufficient cpu.",
+			},
+		},
+	}), false)
+
+	result, isErr := callTool(t, handler.WhyPending, map[string]any{"namespace": "shop", "name": "big"})
+	if isErr {
+		t.Fatalf("unexpected error: %v", result["error"])
+	}
+
+	if want := "0/3 nodes can run the pod: 2 insufficient cpu, 1 untolerated taint"; result["summary"] != want {
+		t.Errorf("expected summary %q, got %q", want, result["summary"])
+	}
+	if result["scheduler_message"] == nil {
+		t.Error("expected the FailedScheduling message to be reported")
+	}
+
+	var unfit []UnfitNode
+	decodeInto(t, result["unfit_nodes"], &unfit)
+	if want := []string{"insufficient cpu (requests 3, 500m of 2 allocatable free)"}; len(unfit) != 3 || !reflect.DeepEqual(unfit[1].Blockers, want) {
+		t.Errorf("expected worker-1 blockers %v, got %#v", want, unfit)
+	}
+
+	result, isErr = callTool(t, handler.WhyPending, map[string]any{"namespace": "shop", "name": "small"})
+	if isErr {
+		t.Fatalf("unexpected error: %v", result["error"])
+	}
+
+	if want := "volume data cannot be used: claim missing: the claim does not exist"; result["summary"] != want {
+		t.Errorf("expected summary %q, got %q", want, result["summary"])
+	}
+	if want := []any{"worker-2"}; !reflect.DeepEqual(result["feasible_nodes"], want) {
+		t.Errorf("expected feasible nodes %v, got %v", want, result["feasible_nodes"])
+	}
+}
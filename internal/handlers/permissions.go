@@ -0,0 +1,398 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/connectivity"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/response"
+)
+
+// escalationVerbs are RBAC verbs that let a subject gain permissions it was
+// not granted directly.
+var escalationVerbs = []string{"escalate", "bind", "impersonate"}
+
+// GetEffectivePermissionsParams defines the parameters for the get_effective_permissions MCP tool.
+type GetEffectivePermissionsParams struct {
+	// Kind is the kind of subject to resolve.
+	Kind string `json:"kind" required:"true" enum:"User,Group,ServiceAccount" description:"Kind of subject: User, Group, or ServiceAccount"`
+
+	// Name is the user, group, or ServiceAccount name.
+	Name string `json:"name" required:"true" description:"Name of the user, group, or ServiceAccount"`
+
+	// Namespace is the namespace of a ServiceAccount.
+	Namespace string `json:"namespace,omitempty" description:"Namespace of the ServiceAccount (required when kind is ServiceAccount)"`
+
+	// Groups lists the groups a user belongs to, since they are asserted by
+	// the authenticator and not stored in the cluster.
+	Groups string `json:"groups,omitempty" description:"Groups the user belongs to, separated by commas, since group membership comes from the authenticator and is not stored in the cluster (only used when kind is User)"`
+
+	// Context specifies which Kubernetes context to use for this operation.
+	Context string `json:"context,omitempty" description:"Kubernetes context to use (defaults to current context from kubeconfig)"`
+}
+
+// PermissionRule is a set of verbs a subject may perform on a resource or a
+// non-resource URL, in a namespace or cluster-wide.
+type PermissionRule struct {
+	// Namespace is where the rule applies. It is empty when ClusterWide is
+	// set.
+	Namespace   string `json:"namespace,omitempty"`
+	ClusterWide bool   `json:"cluster_wide,omitempty"`
+
+	// Resource is written like kubectl does, such as pods, pods/log, or
+	// deployments.apps; "*" stands for any resource or API group.
+	Resource      string   `json:"resource,omitempty"`
+	ResourceNames []string `json:"resource_names,omitempty"`
+
+	// NonResourceURL is set instead of Resource for rules on API server
+	// paths such as /healthz.
+	NonResourceURL string `json:"non_resource_url,omitempty"`
+
+	Verbs []string `json:"verbs"`
+
+	// Via names the bindings and roles that grant the rule, such as
+	// ClusterRoleBinding/admins (ClusterRole/admin).
+	Via []string `json:"via"`
+}
+
+// GetEffectivePermissionsResult is the result of the
+// get_effective_permissions MCP tool.
+type GetEffectivePermissionsResult struct {
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+
+	// User and Groups are the user name and groups the bindings were matched
+	// against.
+	User   string   `json:"user"`
+	Groups []string `json:"groups"`
+
+	Bindings    []SubjectBinding `json:"bindings"`
+	Permissions []PermissionRule `json:"permissions"`
+	Count       int              `json:"count"`
+	Findings    []string         `json:"findings,omitempty"`
+}
+
+// GetEffectivePermissions implements the get_effective_permissions MCP tool.
+// It finds every RoleBinding and ClusterRoleBinding that applies to a user,
+// group, or ServiceAccount, directly or through the groups the identity is
+// in, resolves the Roles and ClusterRoles they reference, including the
+// rules of aggregated ClusterRoles, and merges the result into one
+// deduplicated list of verbs per resource and namespace.
+func (h *AccessHandler) GetEffectivePermissions(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var params GetEffectivePermissionsParams
+	if err := bindParams(request, &params); err != nil {
+		return response.Errorf("failed to parse arguments: %s", err)
+	}
+
+	var identity rbacIdentity
+	switch params.Kind {
+	case rbacv1.ServiceAccountKind:
+		if params.Namespace == "" {
+			return response.Errorf("namespace is required when kind is %s", rbacv1.ServiceAccountKind)
+		}
+		identity = serviceAccountIdentity(params.Namespace, params.Name)
+	case rbacv1.GroupKind:
+		identity = groupIdentity(params.Name)
+	default:
+		var groups []string
+		for _, group := range strings.Split(params.Groups, ",") {
+			groups = append(groups, strings.TrimSpace(group))
+		}
+		identity = userIdentity(params.Name, groups)
+	}
+
+	// Use the appropriate client based on context
+	client, err := h.client.ForContext(ctx, params.Context)
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to create client with context %s: %v", params.Context, err)
+	}
+
+	// Permissions resolved without one of the RBAC types would look
+	// complete while missing grants, so a disabled one refuses the call.
+	for _, rbac := range []struct {
+		resourceType string
+		gvr          schema.GroupVersionResource
+	}{
+		{"clusterrolebindings", accessClusterRoleBindingsGVR},
+		{"rolebindings", accessRoleBindingsGVR},
+		{"clusterroles", accessClusterRolesGVR},
+		{"roles", accessRolesGVR},
+	} {
+		if result, err := h.disabledResult(rbac.resourceType, rbac.gvr); result != nil || err != nil {
+			return result, err
+		}
+	}
+
+	clusterRoleBindings, err := client.ListClusterRoleBindings(ctx, metav1.ListOptions{})
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to list cluster role bindings: %v", err)
+	}
+
+	roleBindings, err := client.ListRoleBindings(ctx, "", metav1.ListOptions{})
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to list role bindings: %v", err)
+	}
+
+	clusterRoles, err := client.ListClusterRoles(ctx, metav1.ListOptions{})
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to list cluster roles: %v", err)
+	}
+
+	roles, err := client.ListRoles(ctx, "", metav1.ListOptions{})
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to list roles: %v", err)
+	}
+
+	resolver := newRoleResolver(roles.Items, clusterRoles.Items)
+	bindings := subjectBindings(identity, roleBindings.Items, clusterRoleBindings.Items)
+	permissions, findings := resolver.permissions(bindings)
+
+	result := GetEffectivePermissionsResult{
+		Kind:        params.Kind,
+		Name:        params.Name,
+		User:        identity.user,
+		Groups:      identity.groups,
+		Bindings:    bindings,
+		Permissions: permissions,
+		Count:       len(permissions),
+		Findings:    findings,
+	}
+
+	if identity.kind == rbacv1.ServiceAccountKind {
+		result.Kind = rbacv1.ServiceAccountKind
+		result.Name = identity.serviceAccount
+		result.Namespace = identity.namespace
+	}
+
+	return response.JSON(result)
+}
+
+// roleResolver looks up the rules of Roles and ClusterRoles by name.
+type roleResolver struct {
+	roles        map[string]*rbacv1.Role
+	clusterRoles map[string]*rbacv1.ClusterRole
+	all          []rbacv1.ClusterRole
+}
+
+// newRoleResolver indexes Roles by namespace/name and ClusterRoles by name.
+func newRoleResolver(roles []rbacv1.Role, clusterRoles []rbacv1.ClusterRole) *roleResolver {
+	r := &roleResolver{
+		roles:        make(map[string]*rbacv1.Role, len(roles)),
+		clusterRoles: make(map[string]*rbacv1.ClusterRole, len(clusterRoles)),
+		all:          clusterRoles,
+	}
+	for i := range roles {
+		r.roles[roles[i].Namespace+"/"+roles[i].Name] = &roles[i]
+	}
+	for i := range clusterRoles {
+		r.clusterRoles[clusterRoles[i].Name] = &clusterRoles[i]
+	}
+	return r
+}
+
+// clusterRoleRules returns the rules of a ClusterRole. For an aggregated
+// ClusterRole, the rules of every ClusterRole its selectors match are added,
+// so the result is right even before the aggregation controller catches up.
+func (r *roleResolver) clusterRoleRules(name string) ([]rbacv1.PolicyRule, bool) {
+	role, ok := r.clusterRoles[name]
+	if !ok {
+		return nil, false
+	}
+
+	rules := append([]rbacv1.PolicyRule{}, role.Rules...)
+	if role.AggregationRule == nil {
+		return rules, true
+	}
+
+	for _, selector := range role.AggregationRule.ClusterRoleSelectors {
+		parsed, err := metav1.LabelSelectorAsSelector(&selector)
+		if err != nil || parsed.Empty() {
+			continue
+		}
+		for i := range r.all {
+			if r.all[i].Name != name && parsed.Matches(labels.Set(r.all[i].Labels)) {
+				rules = append(rules, r.all[i].Rules...)
+			}
+		}
+	}
+
+	return rules, true
+}
+
+// permissions resolves the roles of the bindings into merged permission
+// rules, cluster-wide ones first, and reports bindings to missing roles and
+// risky grants as findings.
+func (r *roleResolver) permissions(bindings []SubjectBinding) ([]PermissionRule, []string) {
+	var findings []string
+
+	merged := make(map[string]*PermissionRule)
+	var order []string
+
+	add := func(namespace, resource, url string, resourceNames, verbs []string, via string) {
+		names := append([]string{}, resourceNames...)
+		sort.Strings(names)
+		key := strings.Join([]string{namespace, resource, url, strings.Join(names, ",")}, "|")
+
+		rule, ok := merged[key]
+		if !ok {
+			rule = &PermissionRule{Namespace: namespace, ClusterWide: namespace == "", Resource: resource, NonResourceURL: url, ResourceNames: names}
+			if len(names) == 0 {
+				rule.ResourceNames = nil
+			}
+			merged[key] = rule
+			order = append(order, key)
+		}
+		for _, verb := range verbs {
+			if !containsString(rule.Verbs, verb) {
+				rule.Verbs = append(rule.Verbs, verb)
+			}
+		}
+		if !containsString(rule.Via, via) {
+			rule.Via = append(rule.Via, via)
+		}
+	}
+
+	for _, binding := range bindings {
+		kind, name, _ := strings.Cut(binding.Role, "/")
+
+		var rules []rbacv1.PolicyRule
+		var found bool
+		if kind == "Role" {
+			var role *rbacv1.Role
+			role, found = r.roles[binding.Namespace+"/"+name]
+			if found {
+				rules = role.Rules
+			}
+		} else {
+			rules, found = r.clusterRoleRules(name)
+		}
+
+		bindingName := binding.Kind + "/" + binding.Name
+		if binding.Namespace != "" {
+			bindingName = binding.Kind + "/" + binding.Namespace + "/" + binding.Name
+		}
+		if !found {
+			findings = append(findings, fmt.Sprintf("%s references %s, which does not exist, so it grants nothing", bindingName, binding.Role))
+			continue
+		}
+
+		via := fmt.Sprintf("%s (%s)", bindingName, binding.Role)
+		for _, rule := range rules {
+			for _, url := range rule.NonResourceURLs {
+				// Non-resource URLs are only granted cluster-wide.
+				if binding.Kind == "ClusterRoleBinding" {
+					add("", "", url, nil, rule.Verbs, via)
+				}
+			}
+			for _, group := range rule.APIGroups {
+				for _, resource := range rule.Resources {
+					add(binding.Namespace, qualifiedResource(resource, group), "", rule.ResourceNames, rule.Verbs, via)
+				}
+			}
+		}
+	}
+
+	permissions := make([]PermissionRule, 0, len(order))
+	for _, key := range order {
+		rule := merged[key]
+		if containsString(rule.Verbs, rbacv1.VerbAll) {
+			rule.Verbs = []string{rbacv1.VerbAll}
+		} else {
+			sort.Strings(rule.Verbs)
+		}
+		permissions = append(permissions, *rule)
+	}
+
+	sort.SliceStable(permissions, func(i, j int) bool {
+		a, b := permissions[i], permissions[j]
+		if a.ClusterWide != b.ClusterWide {
+			return a.ClusterWide
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		if (a.NonResourceURL == "") != (b.NonResourceURL == "") {
+			return a.NonResourceURL == ""
+		}
+		if a.Resource != b.Resource {
+			return a.Resource < b.Resource
+		}
+		return a.NonResourceURL < b.NonResourceURL
+	})
+
+	return permissions, append(findings, permissionFindings(permissions)...)
+}
+
+// qualifiedResource writes a resource and API group the way kubectl does,
+// such as deployments.apps, or pods for the core group.
+func qualifiedResource(resource, group string) string {
+	if group == "" {
+		return resource
+	}
+	return resource + "." + group
+}
+
+// permissionFindings flags full wildcard access, reading Secrets, and verbs
+// that allow privilege escalation.
+func permissionFindings(permissions []PermissionRule) []string {
+	var findings []string
+
+	for _, rule := range permissions {
+		if rule.NonResourceURL != "" || len(rule.ResourceNames) > 0 {
+			continue
+		}
+
+		scope := "in namespace " + rule.Namespace
+		if rule.ClusterWide {
+			scope = "in every namespace"
+		}
+
+		allVerbs := containsString(rule.Verbs, rbacv1.VerbAll)
+		resource, group, _ := strings.Cut(rule.Resource, ".")
+
+		switch {
+		case resource == rbacv1.ResourceAll && group == rbacv1.APIGroupAll && allVerbs:
+			findings = append(findings, fmt.Sprintf("has every verb on every resource %s, granted by %s", scope, strings.Join(rule.Via, ", ")))
+			continue
+		case (resource == "secrets" || resource == rbacv1.ResourceAll) && (group == "" || group == rbacv1.APIGroupAll):
+			for _, verb := range []string{"get", "list", "watch"} {
+				if allVerbs || containsString(rule.Verbs, verb) {
+					findings = append(findings, fmt.Sprintf("can read Secrets %s (%s on %s), granted by %s", scope, verb, rule.Resource, strings.Join(rule.Via, ", ")))
+					break
+				}
+			}
+		}
+
+		for _, verb := range escalationVerbs {
+			if containsString(rule.Verbs, verb) {
+				findings = append(findings, fmt.Sprintf("can %s %s %s, which allows gaining permissions it was not granted, granted by %s", verb, rule.Resource, scope, strings.Join(rule.Via, ", ")))
+			}
+		}
+	}
+
+	return findings
+}
@@ -0,0 +1,211 @@
+package handlers
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/fakecluster"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/resourcefilter"
+)
+
+func TestUserIdentity(t *testing.T) {
+	t.Parallel()
+
+	user := userIdentity("jane", []string{"devs", "", "devs"})
+	if user.kind != rbacv1.UserKind || !reflect.DeepEqual(user.groups, []string{"devs", authenticatedGroup}) {
+		t.Errorf("unexpected user identity %+v", user)
+	}
+
+	account := userIdentity("system:serviceaccount:shop:web", nil)
+	if account.kind != rbacv1.ServiceAccountKind || account.namespace != "shop" || account.serviceAccount != "web" {
+		t.Errorf("expected a ServiceAccount user name to resolve to the ServiceAccount, got %+v", account)
+	}
+}
+
+func TestPermissionFindings(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		rules []PermissionRule
+		want  []string
+	}{
+		{
+			name:  "read only",
+			rules: []PermissionRule{{Namespace: "shop", Resource: "pods", Verbs: []string{"get", "list"}, Via: []string{"RoleBinding/shop/read (Role/read)"}}},
+		},
+		{
+			name:  "full wildcard",
+			rules: []PermissionRule{{ClusterWide: true, Resource: "*.*", Verbs: []string{"*"}, Via: []string{"ClusterRoleBinding/admins (ClusterRole/cluster-admin)"}}},
+			want:  []string{"has every verb on every resource in every namespace, granted by ClusterRoleBinding/admins (ClusterRole/cluster-admin)"},
+		},
+		{
+			name:  "secrets",
+			rules: []PermissionRule{{Namespace: "shop", Resource: "secrets", Verbs: []string{"list", "watch"}, Via: []string{"RoleBinding/shop/x (Role/x)"}}},
+			want:  []string{"can read Secrets in namespace shop (list on secrets), granted by RoleBinding/shop/x (Role/x)"},
+		},
+		{
+			name:  "named secret",
+			rules: []PermissionRule{{Namespace: "shop", Resource: "secrets", ResourceNames: []string{"tls"}, Verbs: []string{"get"}, Via: []string{"RoleBinding/shop/x (Role/x)"}}},
+		},
+		{
+			name:  "escalation",
+			rules: []PermissionRule{{ClusterWide: true, Resource: "clusterroles.rbac.authorization.k8s.io", Verbs: []string{"bind", "get"}, Via: []string{"ClusterRoleBinding/x (ClusterRole/x)"}}},
+			want:  []string{"can bind clusterroles.rbac.authorization.k8s.io in every namespace, which allows gaining permissions it was not granted, granted by ClusterRoleBinding/x (ClusterRole/x)"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := permissionFindings(tt.rules); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected findings %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestGetEffectivePermissions_FakeCluster(t *testing.T) {
+	t.Parallel()
+
+	aggregate := map[string]string{"rbac.example.com/aggregate-to-monitoring": "true"}
+
+	handler := NewAccessHandler(fakecluster.New(fakecluster.Config{
+		Objects: []runtime.Object{
+			&rbacv1.ClusterRole{
+				ObjectMeta: metav1.ObjectMeta{Name: "monitoring"},
+				AggregationRule: &rbacv1.AggregationRule{ClusterRoleSelectors: []metav1.LabelSelector{
+					{MatchLabels: aggregate},
+				}},
+			},
+			&rbacv1.ClusterRole{
+				ObjectMeta: metav1.ObjectMeta{Name: "monitoring-pods", Labels: aggregate},
+				Rules:      []rbacv1.PolicyRule{{APIGroups: []string{""}, Resources: []string{"pods", "nodes"}, Verbs: []string{"get", "list"}}},
+			},
+			&rbacv1.ClusterRole{
+				ObjectMeta: metav1.ObjectMeta{Name: "pod-reader"},
+				Rules:      []rbacv1.PolicyRule{{APIGroups: []string{""}, Resources: []string{"pods", "pods/log"}, Verbs: []string{"get", "watch"}}},
+			},
+			&rbacv1.Role{
+				ObjectMeta: metav1.ObjectMeta{Name: "secrets", Namespace: "shop"},
+				Rules:      []rbacv1.PolicyRule{{APIGroups: []string{""}, Resources: []string{"secrets"}, Verbs: []string{"get"}}},
+			},
+			&rbacv1.ClusterRoleBinding{
+				ObjectMeta: metav1.ObjectMeta{Name: "monitoring"},
+				Subjects:   []rbacv1.Subject{{Kind: rbacv1.GroupKind, Name: "system:serviceaccounts:observability"}},
+				RoleRef:    rbacv1.RoleRef{Kind: "ClusterRole", Name: "monitoring"},
+			},
+			&rbacv1.RoleBinding{
+				ObjectMeta: metav1.ObjectMeta{Name: "read-pods", Namespace: "shop"},
+				Subjects:   []rbacv1.Subject{{Kind: rbacv1.ServiceAccountKind, Name: "agent", Namespace: "observability"}},
+				RoleRef:    rbacv1.RoleRef{Kind: "ClusterRole", Name: "pod-reader"},
+			},
+			&rbacv1.RoleBinding{
+				ObjectMeta: metav1.ObjectMeta{Name: "read-secrets", Namespace: "shop"},
+				Subjects:   []rbacv1.Subject{{Kind: rbacv1.ServiceAccountKind, Name: "agent", Namespace: "observability"}},
+				RoleRef:    rbacv1.RoleRef{Kind: "Role", Name: "secrets"},
+			},
+			&rbacv1.RoleBinding{
+				ObjectMeta: metav1.ObjectMeta{Name: "stale", Namespace: "shop"},
+				Subjects:   []rbacv1.Subject{{Kind: rbacv1.ServiceAccountKind, Name: "agent", Namespace: "observability"}},
+				RoleRef:    rbacv1.RoleRef{Kind: "Role", Name: "deleted"},
+			},
+			&rbacv1.RoleBinding{
+				ObjectMeta: metav1.ObjectMeta{Name: "someone-else", Namespace: "shop"},
+				Subjects:   []rbacv1.Subject{{Kind: rbacv1.UserKind, Name: "jane"}},
+				RoleRef:    rbacv1.RoleRef{Kind: "ClusterRole", Name: "pod-reader"},
+			},
+		},
+	}), nil, false)
+
+	result, isErr := callTool(t, handler.GetEffectivePermissions, map[string]any{"kind": "ServiceAccount", "name": "agent", "namespace": "observability"})
+	if isErr {
+		t.Fatalf("unexpected error: %v", result["error"])
+	}
+
+	var bindings []SubjectBinding
+	decodeInto(t, result["bindings"], &bindings)
+	if len(bindings) != 4 || bindings[3].Name != "monitoring" || bindings[3].Via != "Group/system:serviceaccounts:observability" {
+		t.Errorf("unexpected bindings %+v", bindings)
+	}
+
+	var permissions []PermissionRule
+	decodeInto(t, result["permissions"], &permissions)
+
+	want := []PermissionRule{
+		{ClusterWide: true, Resource: "nodes", Verbs: []string{"get", "list"}, Via: []string{"ClusterRoleBinding/monitoring (ClusterRole/monitoring)"}},
+		{ClusterWide: true, Resource: "pods", Verbs: []string{"get", "list"}, Via: []string{"ClusterRoleBinding/monitoring (ClusterRole/monitoring)"}},
+		{Namespace: "shop", Resource: "pods", Verbs: []string{"get", "watch"}, Via: []string{"RoleBinding/shop/read-pods (ClusterRole/pod-reader)"}},
+		{Namespace: "shop", Resource: "pods/log", Verbs: []string{"get", "watch"}, Via: []string{"RoleBinding/shop/read-pods (ClusterRole/pod-reader)"}},
+		{Namespace: "shop", Resource: "secrets", Verbs: []string{"get"}, Via: []string{"RoleBinding/shop/read-secrets (Role/secrets)"}},
+	}
+	if !reflect.DeepEqual(permissions, want) {
+		t.Errorf("expected permissions\n%+v\ngot\n%+v", want, permissions)
+	}
+
+	var findings []string
+	decodeInto(t, result["findings"], &findings)
+	wantFindings := []string{
+		"RoleBinding/shop/stale references Role/deleted, which does not exist, so it grants nothing",
+		"can read Secrets in namespace shop (get on secrets), granted by RoleBinding/shop/read-secrets (Role/secrets)",
+	}
+	if !reflect.DeepEqual(findings, wantFindings) {
+		t.Errorf("expected findings %q, got %q", wantFindings, findings)
+	}
+
+	result, isErr = callTool(t, handler.GetEffectivePermissions, map[string]any{"kind": "User", "name": "system:serviceaccount:observability:agent"})
+	if isErr {
+		t.Fatalf("unexpected error: %v", result["error"])
+	}
+	if result["kind"] != "ServiceAccount" || result["namespace"] != "observability" || result["count"] != float64(5) {
+		t.Errorf("expected the ServiceAccount user name to resolve like the ServiceAccount, got %+v", result)
+	}
+
+	if _, isErr := callTool(t, handler.GetEffectivePermissions, map[string]any{"kind": "ServiceAccount", "name": "agent"}); !isErr {
+		t.Error("expected an error for a ServiceAccount without a namespace")
+	}
+}
+
+func TestGetEffectivePermissions_DisabledResources(t *testing.T) {
+	t.Parallel()
+
+	rbacResource := func(name, kind string, namespaced bool) metav1.APIResource {
+		return metav1.APIResource{Name: name, Kind: kind, Namespaced: namespaced, Verbs: metav1.Verbs{"get", "list"}}
+	}
+	client := fakecluster.New(fakecluster.Config{
+		APIResources: append(fakecluster.DefaultAPIResources(), &metav1.APIResourceList{
+			GroupVersion: "rbac.authorization.k8s.io/v1",
+			APIResources: []metav1.APIResource{
+				rbacResource("roles", "Role", true),
+				rbacResource("rolebindings", "RoleBinding", true),
+				rbacResource("clusterroles", "ClusterRole", false),
+				rbacResource("clusterrolebindings", "ClusterRoleBinding", false),
+			},
+		}),
+	})
+
+	for _, disabled := range []string{"roles", "rolebindings", "clusterroles", "clusterrolebindings"} {
+		t.Run(disabled, func(t *testing.T) {
+			t.Parallel()
+
+			filter, err := resourcefilter.NewFilter(disabled, client)
+			if err != nil {
+				t.Fatal(err)
+			}
+			handler := NewAccessHandler(client, filter, false)
+
+			result, isErr := callTool(t, handler.GetEffectivePermissions, map[string]any{"kind": "User", "name": "jane"})
+			if !isErr || !strings.Contains(fmt.Sprint(result["error"]), `"`+disabled+`"`) {
+				t.Errorf("expected the call to be refused for disabled %s, got %v", disabled, result)
+			}
+		})
+	}
+}
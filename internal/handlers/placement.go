@@ -0,0 +1,755 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/connectivity"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/response"
+)
+
+// Placement rule types reported by explain_pod_placement.
+const (
+	placementNodeSelector    = "node_selector"
+	placementNodeAffinity    = "node_affinity"
+	placementPodAffinity     = "pod_affinity"
+	placementPodAntiAffinity = "pod_anti_affinity"
+	placementTopologySpread  = "topology_spread"
+)
+
+// ExplainPodPlacementParams defines the parameters for the explain_pod_placement MCP tool.
+type ExplainPodPlacementParams struct {
+	// Namespace specifies the namespace of the pod or workload.
+	Namespace string `json:"namespace" required:"true" description:"Namespace of the pod or workload"`
+
+	// Name specifies which pod or workload to explain.
+	Name string `json:"name" required:"true" description:"Name of the pod or workload"`
+
+	// ResourceType specifies the kind of object to read. Workloads are
+	// explained through their pod template.
+	ResourceType string `json:"resource_type,omitempty" default:"pods" description:"Resource type of the object: pods, or a workload such as deployments, statefulsets, daemonsets, replicasets, jobs, or cronjobs (defaults to pods)"`
+
+	// Context specifies which Kubernetes context to use for this operation.
+	Context string `json:"context,omitempty" description:"Kubernetes context to use (defaults to current context from kubeconfig)"`
+}
+
+// PlacementRule is one scheduling constraint of a pod, explained in plain
+// language, with the current distribution of the pods it refers to.
+type PlacementRule struct {
+	// Type is node_selector, node_affinity, pod_affinity,
+	// pod_anti_affinity, or topology_spread.
+	Type string `json:"type"`
+
+	// Required is false for preferences, which only rank nodes.
+	Required    bool   `json:"required"`
+	Weight      int32  `json:"weight,omitempty"`
+	Explanation string `json:"explanation"`
+
+	TopologyKey string `json:"topology_key,omitempty"`
+	Selector    string `json:"selector,omitempty"`
+
+	// MatchingNodes counts the nodes a node selector or node affinity
+	// term accepts.
+	MatchingNodes *int `json:"matching_nodes,omitempty"`
+
+	// Distribution counts the matching pods in every domain of the
+	// topology key, for pod affinity and topology spread rules.
+	Distribution []PlacementDomain `json:"distribution,omitempty"`
+	Findings     []string          `json:"findings,omitempty"`
+}
+
+// PlacementDomain is one value of a topology key, such as a zone, with the
+// pods a rule matches there.
+type PlacementDomain struct {
+	Domain       string `json:"domain"`
+	Nodes        int    `json:"nodes"`
+	MatchingPods int    `json:"matching_pods"`
+
+	// Allowed reports whether another pod like this one could be placed in
+	// the domain under a required rule. It is omitted for preferences.
+	Allowed *bool `json:"allowed,omitempty"`
+}
+
+// ExplainPodPlacementResult is the result of the explain_pod_placement MCP
+// tool.
+type ExplainPodPlacementResult struct {
+	Kind        string          `json:"kind"`
+	Namespace   string          `json:"namespace"`
+	Name        string          `json:"name"`
+	Node        string          `json:"node,omitempty"`
+	Explanation []string        `json:"explanation"`
+	Rules       []PlacementRule `json:"rules"`
+	TotalNodes  int             `json:"total_nodes"`
+	Warnings    []string        `json:"warnings,omitempty"`
+}
+
+// ExplainPodPlacement implements the explain_pod_placement MCP tool.
+// It reads a pod, or the pod template of a workload, and explains its node
+// selector, node affinity, inter-pod affinity and anti-affinity, and
+// topology spread constraints in plain language. For the rules that depend
+// on other pods, it counts the matching pods in every domain of the topology
+// key, such as each zone or node, and marks where another pod like this one
+// could go. Pods and namespaces are best effort and report listing failures
+// under warnings.
+func (h *PodHandler) ExplainPodPlacement(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var params ExplainPodPlacementParams
+	if err := bindParams(request, &params); err != nil {
+		return response.Errorf("failed to parse arguments: %s", err)
+	}
+
+	if params.ResourceType == "" {
+		params.ResourceType = "pods"
+	}
+
+	// Use the appropriate client based on context
+	client, err := h.client.ForContext(ctx, params.Context)
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to create client with context %s: %v", params.Context, err)
+	}
+
+	gvr, err := client.ResolveResourceType(params.ResourceType, "")
+	if err != nil {
+		if h.alwaysStart && connectivity.IsError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to resolve resource type: %v", err)
+	}
+
+	obj, err := client.GetResource(ctx, gvr, params.Namespace, params.Name)
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to get resource: %v", err)
+	}
+
+	pod, err := schedulingPod(obj)
+	if err != nil {
+		return response.Errorf("%v", err)
+	}
+
+	nodes, err := client.ListNodes(ctx, metav1.ListOptions{})
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to list nodes: %v", err)
+	}
+
+	var warnings []string
+
+	placement := &placementEvaluation{pod: pod, nodes: nodes.Items}
+
+	if usesPodPlacement(pod) {
+		if list, err := client.ListPods(ctx, "", metav1.ListOptions{}); err != nil {
+			if h.alwaysStart && connectivity.IsTransportError(err) {
+				return response.Error(connectivity.ErrorMessage(err))
+			}
+			warnings = append(warnings, fmt.Sprintf("failed to list pods, the distribution of matching pods was not reported: %v", err))
+		} else {
+			placement.pods = list.Items
+			placement.podsListed = true
+		}
+
+		if usesNamespaceSelector(pod) {
+			if list, err := client.ListNamespaces(ctx, metav1.ListOptions{}); err != nil {
+				if h.alwaysStart && connectivity.IsTransportError(err) {
+					return response.Error(connectivity.ErrorMessage(err))
+				}
+				warnings = append(warnings, fmt.Sprintf("failed to list namespaces, namespace selectors were treated as matching no namespace: %v", err))
+			} else {
+				placement.namespaces = list.Items
+			}
+		}
+	}
+
+	rules := placement.rules()
+
+	explanation := make([]string, 0, len(rules))
+	for _, rule := range rules {
+		explanation = append(explanation, rule.Explanation)
+	}
+	if len(explanation) == 0 {
+		explanation = append(explanation, "the pod has no node selector, affinity, or topology spread constraints, so it can run on any node that tolerates its taints and has room for its requests")
+	}
+
+	return response.JSON(ExplainPodPlacementResult{
+		Kind:        obj.GetKind(),
+		Namespace:   pod.Namespace,
+		Name:        obj.GetName(),
+		Node:        pod.Spec.NodeName,
+		Explanation: explanation,
+		Rules:       rules,
+		TotalNodes:  len(nodes.Items),
+		Warnings:    warnings,
+	})
+}
+
+// usesPodPlacement reports whether a pod has rules that depend on where
+// other pods run.
+func usesPodPlacement(pod *corev1.Pod) bool {
+	affinity := pod.Spec.Affinity
+	return len(pod.Spec.TopologySpreadConstraints) > 0 ||
+		(affinity != nil && (affinity.PodAffinity != nil || affinity.PodAntiAffinity != nil))
+}
+
+// usesNamespaceSelector reports whether any pod affinity term of a pod
+// selects namespaces by label.
+func usesNamespaceSelector(pod *corev1.Pod) bool {
+	for _, term := range podAffinityTerms(pod) {
+		if selector := term.term.NamespaceSelector; selector != nil && webhookSelector(selector) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// weightedPodAffinityTerm is a pod affinity or anti-affinity term with its
+// type, and its weight when it is a preference.
+type weightedPodAffinityTerm struct {
+	ruleType string
+	required bool
+	weight   int32
+	term     corev1.PodAffinityTerm
+}
+
+// podAffinityTerms flattens the inter-pod affinity and anti-affinity terms
+// of a pod, required terms first.
+func podAffinityTerms(pod *corev1.Pod) []weightedPodAffinityTerm {
+	var terms []weightedPodAffinityTerm
+
+	affinity := pod.Spec.Affinity
+	if affinity == nil {
+		return nil
+	}
+
+	if podAffinity := affinity.PodAffinity; podAffinity != nil {
+		for _, term := range podAffinity.RequiredDuringSchedulingIgnoredDuringExecution {
+			terms = append(terms, weightedPodAffinityTerm{ruleType: placementPodAffinity, required: true, term: term})
+		}
+		for _, term := range podAffinity.PreferredDuringSchedulingIgnoredDuringExecution {
+			terms = append(terms, weightedPodAffinityTerm{ruleType: placementPodAffinity, weight: term.Weight, term: term.PodAffinityTerm})
+		}
+	}
+
+	if antiAffinity := affinity.PodAntiAffinity; antiAffinity != nil {
+		for _, term := range antiAffinity.RequiredDuringSchedulingIgnoredDuringExecution {
+			terms = append(terms, weightedPodAffinityTerm{ruleType: placementPodAntiAffinity, required: true, term: term})
+		}
+		for _, term := range antiAffinity.PreferredDuringSchedulingIgnoredDuringExecution {
+			terms = append(terms, weightedPodAffinityTerm{ruleType: placementPodAntiAffinity, weight: term.Weight, term: term.PodAffinityTerm})
+		}
+	}
+
+	return terms
+}
+
+// placementEvaluation holds what explain_pod_placement needs to explain the
+// rules of a pod. pods is only set when podsListed is true.
+type placementEvaluation struct {
+	pod        *corev1.Pod
+	nodes      []corev1.Node
+	pods       []corev1.Pod
+	podsListed bool
+	namespaces []corev1.Namespace
+}
+
+// rules explains every placement rule of the pod: the node selector, node
+// affinity, pod affinity and anti-affinity, then topology spread.
+func (e *placementEvaluation) rules() []PlacementRule {
+	rules := make([]PlacementRule, 0)
+
+	if len(e.pod.Spec.NodeSelector) > 0 {
+		parts := make([]string, 0, len(e.pod.Spec.NodeSelector))
+		for _, key := range sortedKeys(e.pod.Spec.NodeSelector) {
+			parts = append(parts, key+"="+e.pod.Spec.NodeSelector[key])
+		}
+
+		matching := 0
+		for i := range e.nodes {
+			if labels.SelectorFromSet(e.pod.Spec.NodeSelector).Matches(labels.Set(e.nodes[i].Labels)) {
+				matching++
+			}
+		}
+
+		rule := PlacementRule{
+			Type:          placementNodeSelector,
+			Required:      true,
+			Explanation:   fmt.Sprintf("must run on a node labeled %s (%d of %d nodes match)", strings.Join(parts, " and "), matching, len(e.nodes)),
+			MatchingNodes: &matching,
+		}
+		if matching == 0 {
+			rule.Findings = append(rule.Findings, "no node has these labels, so the pod cannot be scheduled")
+		}
+		rules = append(rules, rule)
+	}
+
+	if affinity := e.pod.Spec.Affinity; affinity != nil && affinity.NodeAffinity != nil {
+		if required := affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution; required != nil {
+			matching := 0
+			for i := range e.nodes {
+				if matchesNodeSelectorTerms(&e.nodes[i], required.NodeSelectorTerms) {
+					matching++
+				}
+			}
+
+			rule := PlacementRule{
+				Type:          placementNodeAffinity,
+				Required:      true,
+				Explanation:   fmt.Sprintf("must run on a node where %s (%d of %d nodes match)", describeNodeSelectorTerms(required.NodeSelectorTerms), matching, len(e.nodes)),
+				MatchingNodes: &matching,
+			}
+			if matching == 0 {
+				rule.Findings = append(rule.Findings, "no node matches the required node affinity, so the pod cannot be scheduled")
+			}
+			rules = append(rules, rule)
+		}
+
+		for _, preferred := range affinity.NodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution {
+			matching := 0
+			for i := range e.nodes {
+				if matchesNodeSelectorTerms(&e.nodes[i], []corev1.NodeSelectorTerm{preferred.Preference}) {
+					matching++
+				}
+			}
+
+			rules = append(rules, PlacementRule{
+				Type:          placementNodeAffinity,
+				Weight:        preferred.Weight,
+				Explanation:   fmt.Sprintf("prefers nodes where %s, with weight %d (%d of %d nodes match)", describeNodeSelectorTerms([]corev1.NodeSelectorTerm{preferred.Preference}), preferred.Weight, matching, len(e.nodes)),
+				MatchingNodes: &matching,
+			})
+		}
+	}
+
+	for _, term := range podAffinityTerms(e.pod) {
+		rules = append(rules, e.podAffinityRule(term))
+	}
+
+	for i := range e.pod.Spec.TopologySpreadConstraints {
+		rules = append(rules, e.topologySpreadRule(&e.pod.Spec.TopologySpreadConstraints[i]))
+	}
+
+	return rules
+}
+
+// podAffinityRule explains a pod affinity or anti-affinity term and counts
+// the pods it matches in every domain of its topology key.
+func (e *placementEvaluation) podAffinityRule(weighted weightedPodAffinityTerm) PlacementRule {
+	term := weighted.term
+	selector := e.podAffinitySelector(term.LabelSelector, term.MatchLabelKeys, term.MismatchLabelKeys)
+	domain := topologyDomainName(term.TopologyKey)
+
+	rule := PlacementRule{
+		Type:        weighted.ruleType,
+		Required:    weighted.required,
+		Weight:      weighted.weight,
+		TopologyKey: term.TopologyKey,
+		Selector:    describePodSelector(selector),
+	}
+
+	subject := fmt.Sprintf("%s %s", describePodSelector(selector), e.describeNamespaces(term))
+	switch {
+	case weighted.ruleType == placementPodAffinity && weighted.required:
+		rule.Explanation = fmt.Sprintf("must run in the same %s as at least one of the %s", domain, subject)
+	case weighted.ruleType == placementPodAffinity:
+		rule.Explanation = fmt.Sprintf("prefers to run in the same %s as the %s, with weight %d", domain, subject, weighted.weight)
+	case weighted.required:
+		rule.Explanation = fmt.Sprintf("must not run in the same %s as any of the %s", domain, subject)
+	default:
+		rule.Explanation = fmt.Sprintf("prefers not to run in the same %s as the %s, with weight %d", domain, subject, weighted.weight)
+	}
+
+	if !e.podsListed {
+		return rule
+	}
+
+	parsed, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		rule.Findings = append(rule.Findings, fmt.Sprintf("the label selector is invalid, so it matches no pods: %v", err))
+		return rule
+	}
+
+	namespaces := e.termNamespaces(term)
+	counts := e.domainCounts(term.TopologyKey, e.nodes, func(pod *corev1.Pod) bool {
+		return namespaces(pod.Namespace) && parsed.Matches(labels.Set(pod.Labels))
+	})
+
+	total := 0
+	for _, count := range counts {
+		total += count.MatchingPods
+	}
+
+	allowedDomains := 0
+	for i := range counts {
+		if !weighted.required {
+			continue
+		}
+		allowed := counts[i].MatchingPods > 0
+		if weighted.ruleType == placementPodAntiAffinity {
+			allowed = counts[i].MatchingPods == 0
+		}
+		if allowed {
+			allowedDomains++
+		}
+		counts[i].Allowed = &allowed
+	}
+	rule.Distribution = counts
+
+	if !weighted.required {
+		return rule
+	}
+
+	switch weighted.ruleType {
+	case placementPodAffinity:
+		if total == 0 {
+			if namespaces(e.pod.Namespace) && parsed.Matches(labels.Set(e.pod.Labels)) {
+				rule.Findings = append(rule.Findings, "no pod matches this term yet; the scheduler still places the pod because it matches the term itself, as the first pod of a group does")
+			} else {
+				rule.Findings = append(rule.Findings, "no pod matches this term, so the pod cannot be scheduled until a matching pod runs")
+			}
+		}
+	case placementPodAntiAffinity:
+		if allowedDomains == 0 && len(counts) > 0 {
+			rule.Findings = append(rule.Findings, fmt.Sprintf("every %s already runs a matching pod, so another pod like this one cannot be scheduled; add capacity in a new %s or make the rule a preference", domain, domain))
+		}
+	}
+
+	return rule
+}
+
+// topologySpreadRule explains a topology spread constraint and counts the
+// pods it matches in every domain of its topology key, among the nodes the
+// constraint considers.
+func (e *placementEvaluation) topologySpreadRule(constraint *corev1.TopologySpreadConstraint) PlacementRule {
+	selector := e.podAffinitySelector(constraint.LabelSelector, constraint.MatchLabelKeys, nil)
+	domain := topologyDomainName(constraint.TopologyKey)
+	required := constraint.WhenUnsatisfiable == corev1.DoNotSchedule
+
+	rule := PlacementRule{
+		Type:        placementTopologySpread,
+		Required:    required,
+		TopologyKey: constraint.TopologyKey,
+		Selector:    describePodSelector(selector),
+	}
+
+	explanation := fmt.Sprintf("spreads the %s in namespace %s across each %s, so the number of them in any %s is at most %d more than in the %s with the fewest", describePodSelector(selector), e.pod.Namespace, domain, domain, constraint.MaxSkew, domain)
+	if constraint.MinDomains != nil && *constraint.MinDomains > 1 {
+		explanation += fmt.Sprintf(", counting on at least %d %ss", *constraint.MinDomains, domain)
+	}
+	if required {
+		explanation += "; when that is not possible the pod stays Pending (DoNotSchedule)"
+	} else {
+		explanation += "; when that is not possible the pod is still scheduled, preferring the least loaded domains (ScheduleAnyway)"
+	}
+	rule.Explanation = explanation
+
+	// Only nodes that pass the pod's node affinity, and its taints when the
+	// policy says so, count as domains.
+	eligible := make([]corev1.Node, 0, len(e.nodes))
+	unlabeled := 0
+	for i := range e.nodes {
+		node := &e.nodes[i]
+		if constraint.NodeAffinityPolicy == nil || *constraint.NodeAffinityPolicy == corev1.NodeInclusionPolicyHonor {
+			if !matchesPodNodeAffinity(e.pod, node) {
+				continue
+			}
+		}
+		if constraint.NodeTaintsPolicy != nil && *constraint.NodeTaintsPolicy == corev1.NodeInclusionPolicyHonor && hasUntoleratedTaint(e.pod, node) {
+			continue
+		}
+		if _, ok := node.Labels[constraint.TopologyKey]; !ok {
+			unlabeled++
+			continue
+		}
+		eligible = append(eligible, *node)
+	}
+
+	if unlabeled > 0 {
+		rule.Findings = append(rule.Findings, fmt.Sprintf("%d nodes have no %s label and are not considered by this constraint", unlabeled, constraint.TopologyKey))
+	}
+
+	if !e.podsListed {
+		return rule
+	}
+
+	parsed, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		rule.Findings = append(rule.Findings, fmt.Sprintf("the label selector is invalid, so it matches no pods: %v", err))
+		return rule
+	}
+
+	counts := e.domainCounts(constraint.TopologyKey, eligible, func(pod *corev1.Pod) bool {
+		return pod.Namespace == e.pod.Namespace && parsed.Matches(labels.Set(pod.Labels))
+	})
+	if len(counts) == 0 {
+		return rule
+	}
+
+	minimum, maximum := counts[0].MatchingPods, counts[0].MatchingPods
+	for _, count := range counts {
+		minimum = min(minimum, count.MatchingPods)
+		maximum = max(maximum, count.MatchingPods)
+	}
+
+	// With fewer domains than minDomains, the scheduler treats the global
+	// minimum as zero.
+	globalMinimum := minimum
+	if constraint.MinDomains != nil && int32(len(counts)) < *constraint.MinDomains {
+		globalMinimum = 0
+	}
+
+	self := 0
+	if parsed.Matches(labels.Set(e.pod.Labels)) {
+		self = 1
+	}
+
+	var allowedDomains []string
+	for i := range counts {
+		allowed := int32(counts[i].MatchingPods+self-globalMinimum) <= constraint.MaxSkew
+		if allowed {
+			allowedDomains = append(allowedDomains, counts[i].Domain)
+		}
+		if required {
+			counts[i].Allowed = &allowed
+		}
+	}
+	rule.Distribution = counts
+
+	if skew := maximum - minimum; int32(skew) > constraint.MaxSkew {
+		rule.Findings = append(rule.Findings, fmt.Sprintf("the pods are unevenly spread: the skew is %d, above the maximum of %d", skew, constraint.MaxSkew))
+	}
+
+	switch {
+	case required && len(allowedDomains) == 0:
+		rule.Findings = append(rule.Findings, fmt.Sprintf("no %s can take another pod like this one without exceeding the maximum skew, so it would stay Pending", domain))
+	case required && len(allowedDomains) < len(counts):
+		rule.Findings = append(rule.Findings, fmt.Sprintf("another pod like this one can only be placed in %s %s", domain, strings.Join(truncate(allowedDomains, 10), ", ")))
+	}
+
+	return rule
+}
+
+// domainCounts counts the running pods that match in every domain of a
+// topology key, over the given nodes. Nodes without the key are skipped.
+func (e *placementEvaluation) domainCounts(topologyKey string, nodes []corev1.Node, matches func(*corev1.Pod) bool) []PlacementDomain {
+	byDomain := make(map[string]*PlacementDomain)
+	nodeDomains := make(map[string]string, len(nodes))
+	for i := range nodes {
+		value, ok := nodes[i].Labels[topologyKey]
+		if !ok {
+			continue
+		}
+		nodeDomains[nodes[i].Name] = value
+		if byDomain[value] == nil {
+			byDomain[value] = &PlacementDomain{Domain: value}
+		}
+		byDomain[value].Nodes++
+	}
+
+	for i := range e.pods {
+		pod := &e.pods[i]
+		if pod.Spec.NodeName == "" || pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		value, ok := nodeDomains[pod.Spec.NodeName]
+		if !ok || !matches(pod) {
+			continue
+		}
+		byDomain[value].MatchingPods++
+	}
+
+	domains := make([]PlacementDomain, 0, len(byDomain))
+	for _, domain := range byDomain {
+		domains = append(domains, *domain)
+	}
+	sort.Slice(domains, func(i, j int) bool { return domains[i].Domain < domains[j].Domain })
+
+	return domains
+}
+
+// podAffinitySelector adds the requirements of matchLabelKeys and
+// mismatchLabelKeys to a selector: the pod's own values of those labels
+// must, or must not, match.
+func (e *placementEvaluation) podAffinitySelector(selector *metav1.LabelSelector, matchKeys, mismatchKeys []string) *metav1.LabelSelector {
+	if selector == nil || (len(matchKeys) == 0 && len(mismatchKeys) == 0) {
+		return selector
+	}
+
+	merged := selector.DeepCopy()
+	for _, key := range matchKeys {
+		if value, ok := e.pod.Labels[key]; ok {
+			merged.MatchExpressions = append(merged.MatchExpressions, metav1.LabelSelectorRequirement{Key: key, Operator: metav1.LabelSelectorOpIn, Values: []string{value}})
+		}
+	}
+	for _, key := range mismatchKeys {
+		if value, ok := e.pod.Labels[key]; ok {
+			merged.MatchExpressions = append(merged.MatchExpressions, metav1.LabelSelectorRequirement{Key: key, Operator: metav1.LabelSelectorOpNotIn, Values: []string{value}})
+		}
+	}
+
+	return merged
+}
+
+// termNamespaces returns whether a namespace is one a pod affinity term
+// applies to: the listed namespaces and those its namespace selector
+// matches, or the pod's own namespace when it sets neither.
+func (e *placementEvaluation) termNamespaces(term corev1.PodAffinityTerm) func(string) bool {
+	if len(term.Namespaces) == 0 && term.NamespaceSelector == nil {
+		return func(namespace string) bool { return namespace == e.pod.Namespace }
+	}
+
+	allowed := make(map[string]bool, len(term.Namespaces))
+	for _, namespace := range term.Namespaces {
+		allowed[namespace] = true
+	}
+
+	if term.NamespaceSelector != nil {
+		if webhookSelector(term.NamespaceSelector) == "" {
+			return func(string) bool { return true }
+		}
+		if selector, err := metav1.LabelSelectorAsSelector(term.NamespaceSelector); err == nil {
+			for i := range e.namespaces {
+				if selector.Matches(labels.Set(e.namespaces[i].Labels)) {
+					allowed[e.namespaces[i].Name] = true
+				}
+			}
+		}
+	}
+
+	return func(namespace string) bool { return allowed[namespace] }
+}
+
+// describeNamespaces renders the namespaces a pod affinity term applies to.
+func (e *placementEvaluation) describeNamespaces(term corev1.PodAffinityTerm) string {
+	var parts []string
+	if len(term.Namespaces) == 1 {
+		parts = append(parts, "namespace "+term.Namespaces[0])
+	} else if len(term.Namespaces) > 1 {
+		parts = append(parts, "namespaces "+strings.Join(term.Namespaces, ", "))
+	}
+
+	if term.NamespaceSelector != nil {
+		selector := webhookSelector(term.NamespaceSelector)
+		if selector == "" {
+			return "in any namespace"
+		}
+		parts = append(parts, "namespaces labeled "+selector)
+	}
+
+	if len(parts) == 0 {
+		return "in namespace " + e.pod.Namespace
+	}
+	return "in " + strings.Join(parts, " or ")
+}
+
+// describePodSelector renders the pods a label selector matches.
+func describePodSelector(selector *metav1.LabelSelector) string {
+	if selector == nil {
+		return "no pods"
+	}
+	if rendered := webhookSelector(selector); rendered != "" {
+		return "pods matching " + rendered
+	}
+	return "all pods"
+}
+
+// describeNodeSelectorTerms renders node selector terms in plain language.
+// The requirements of a term must all hold, and any term may match.
+func describeNodeSelectorTerms(terms []corev1.NodeSelectorTerm) string {
+	described := make([]string, 0, len(terms))
+	for _, term := range terms {
+		var parts []string
+		for _, requirement := range term.MatchExpressions {
+			parts = append(parts, describeNodeSelectorRequirement("label "+requirement.Key, requirement))
+		}
+		for _, requirement := range term.MatchFields {
+			subject := "field " + requirement.Key
+			if requirement.Key == nodeFieldName {
+				subject = "the node name"
+			}
+			parts = append(parts, describeNodeSelectorRequirement(subject, requirement))
+		}
+		if len(parts) == 0 {
+			parts = append(parts, "an empty term matches (which it never does)")
+		}
+		described = append(described, strings.Join(parts, " and "))
+	}
+	return strings.Join(described, ", or where ")
+}
+
+// describeNodeSelectorRequirement renders a single requirement on subject,
+// such as "label disktype is ssd".
+func describeNodeSelectorRequirement(subject string, requirement corev1.NodeSelectorRequirement) string {
+	values := strings.Join(requirement.Values, ", ")
+	switch requirement.Operator {
+	case corev1.NodeSelectorOpIn:
+		if len(requirement.Values) == 1 {
+			return subject + " is " + values
+		}
+		return subject + " is one of " + values
+	case corev1.NodeSelectorOpNotIn:
+		if len(requirement.Values) == 1 {
+			return subject + " is not " + values
+		}
+		return subject + " is none of " + values
+	case corev1.NodeSelectorOpExists:
+		return subject + " is set"
+	case corev1.NodeSelectorOpDoesNotExist:
+		return subject + " is not set"
+	case corev1.NodeSelectorOpGt:
+		return subject + " is greater than " + values
+	case corev1.NodeSelectorOpLt:
+		return subject + " is less than " + values
+	}
+	return fmt.Sprintf("%s %s %s", subject, requirement.Operator, values)
+}
+
+// topologyDomainName names the domains of a topology key, such as "zone"
+// for topology.kubernetes.io/zone.
+func topologyDomainName(key string) string {
+	switch key {
+	case corev1.LabelHostname:
+		return "node"
+	case labelZone, labelZoneBeta:
+		return "zone"
+	case labelRegion, labelRegionBeta:
+		return "region"
+	}
+	return key + " domain"
+}
+
+// matchesPodNodeAffinity reports whether a node passes a pod's node
+// selector and required node affinity.
+func matchesPodNodeAffinity(pod *corev1.Pod, node *corev1.Node) bool {
+	if !labels.SelectorFromSet(pod.Spec.NodeSelector).Matches(labels.Set(node.Labels)) {
+		return false
+	}
+	if affinity := pod.Spec.Affinity; affinity != nil && affinity.NodeAffinity != nil {
+		if required := affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution; required != nil {
+			return matchesNodeSelectorTerms(node, required.NodeSelectorTerms)
+		}
+	}
+	return true
+}
+
+// hasUntoleratedTaint reports whether a node has a NoSchedule or NoExecute
+// taint the pod does not tolerate.
+func hasUntoleratedTaint(pod *corev1.Pod, node *corev1.Node) bool {
+	for _, taint := range node.Spec.Taints {
+		if taint.Effect != corev1.TaintEffectPreferNoSchedule && !toleratesTaint(pod.Spec.Tolerations, taint) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,192 @@
+package handlers
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/fakecluster"
+)
+
+func TestDescribeNodeSelectorTerms(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		terms []corev1.NodeSelectorTerm
+		want  string
+	}{
+		{
+			name: "single value",
+			terms: []corev1.NodeSelectorTerm{{MatchExpressions: []corev1.NodeSelectorRequirement{
+				{Key: "disktype", Operator: corev1.NodeSelectorOpIn, Values: []string{"ssd"}},
+			}}},
+			want: "label disktype is ssd",
+		},
+		{
+			name: "several requirements",
+			terms: []corev1.NodeSelectorTerm{{MatchExpressions: []corev1.NodeSelectorRequirement{
+				{Key: labelZone, Operator: corev1.NodeSelectorOpNotIn, Values: []string{"us-east-1a", "us-east-1b"}},
+				{Key: "spot", Operator: corev1.NodeSelectorOpDoesNotExist},
+				{Key: "cores", Operator: corev1.NodeSelectorOpGt, Values: []string{"8"}},
+			}}},
+			want: "label topology.kubernetes.io/zone is none of us-east-1a, us-east-1b and label spot is not set and label cores is greater than 8",
+		},
+		{
+			name: "alternative terms",
+			terms: []corev1.NodeSelectorTerm{
+				{MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "gpu", Operator: corev1.NodeSelectorOpExists}}},
+				{MatchFields: []corev1.NodeSelectorRequirement{{Key: nodeFieldName, Operator: corev1.NodeSelectorOpIn, Values: []string{"worker-1", "worker-2"}}}},
+			},
+			want: "label gpu is set, or where the node name is one of worker-1, worker-2",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := describeNodeSelectorTerms(tt.terms); got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestTopologyDomainName(t *testing.T) {
+	t.Parallel()
+
+	for key, want := range map[string]string{
+		corev1.LabelHostname: "node",
+		labelZone:            "zone",
+		labelZoneBeta:        "zone",
+		labelRegion:          "region",
+		"rack":               "rack domain",
+	} {
+		if got := topologyDomainName(key); got != want {
+			t.Errorf("expected %q for %q, got %q", want, key, got)
+		}
+	}
+}
+
+func TestExplainPodPlacement_FakeCluster(t *testing.T) {
+	t.Parallel()
+
+	node := func(name, zone string) *corev1.Node {
+		labels := map[string]string{corev1.LabelHostname: name}
+		if zone != "" {
+			labels[labelZone] = zone
+		}
+		return &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+	}
+
+	pod := func(name, nodeName string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "shop", Labels: map[string]string{"app": "web"}},
+			Spec:       corev1.PodSpec{NodeName: nodeName},
+			Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+		}
+	}
+
+	web := map[string]string{"app": "web"}
+
+	handler := NewPodHandler(fakecluster.New(fakecluster.Config{
+		Objects: []runtime.Object{
+			node("a-1", "zone-a"),
+			node("a-2", "zone-a"),
+			node("b-1", "zone-b"),
+			node("edge", ""),
+			pod("web-1", "a-1"),
+			pod("web-2", "a-2"),
+			pod("web-3", "b-1"),
+			&appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop"},
+				Spec: appsv1.DeploymentSpec{Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{Labels: web},
+					Spec: corev1.PodSpec{
+						Affinity: &corev1.Affinity{PodAntiAffinity: &corev1.PodAntiAffinity{
+							RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{{
+								LabelSelector: &metav1.LabelSelector{MatchLabels: web},
+								TopologyKey:   corev1.LabelHostname,
+							}},
+						}},
+						TopologySpreadConstraints: []corev1.TopologySpreadConstraint{{
+							MaxSkew:           1,
+							TopologyKey:       labelZone,
+							WhenUnsatisfiable: corev1.DoNotSchedule,
+							LabelSelector:     &metav1.LabelSelector{MatchLabels: web},
+						}},
+						Containers: []corev1.Container{{Name: "web"}},
+					},
+				}},
+			},
+		},
+	}), false)
+
+	result, isErr := callTool(t, handler.ExplainPodPlacement, map[string]any{"namespace": "shop", "name": "web", "resource_type": "deployments"})
+	if isErr {
+		t.Fatalf("unexpected error: %v", result["error"])
+	}
+
+	var rules []PlacementRule
+	decodeInto(t, result["rules"], &rules)
+	if len(rules) != 2 {
+		t.Fatalf("expected 2 rules, got %+v", rules)
+	}
+
+	antiAffinity := rules[0]
+	if antiAffinity.Type != placementPodAntiAffinity || !antiAffinity.Required ||
+		antiAffinity.Explanation != "must not run in the same node as any of the pods matching app=web in namespace shop" {
+		t.Errorf("unexpected anti-affinity rule %+v", antiAffinity)
+	}
+
+	allowed := make([]string, 0)
+	for _, domain := range antiAffinity.Distribution {
+		if domain.Allowed != nil && *domain.Allowed {
+			allowed = append(allowed, domain.Domain)
+		}
+	}
+	if !reflect.DeepEqual(allowed, []string{"edge"}) {
+		t.Errorf("expected only the empty node to be allowed, got %v", allowed)
+	}
+
+	spread := rules[1]
+	if spread.Type != placementTopologySpread || !spread.Required || spread.TopologyKey != labelZone {
+		t.Errorf("unexpected spread rule %+v", spread)
+	}
+
+	want := []PlacementDomain{{Domain: "zone-a", Nodes: 2, MatchingPods: 2}, {Domain: "zone-b", Nodes: 1, MatchingPods: 1}}
+	got := make([]PlacementDomain, 0, len(spread.Distribution))
+	for _, domain := range spread.Distribution {
+		domain.Allowed = nil
+		got = append(got, domain)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected distribution %+v, got %+v", want, got)
+	}
+	if spread.Distribution[0].Allowed == nil || *spread.Distribution[0].Allowed || !*spread.Distribution[1].Allowed {
+		t.Errorf("expected only zone-b to take another pod, got %+v", spread.Distribution)
+	}
+
+	findings := strings.Join(spread.Findings, "\n")
+	if !strings.Contains(findings, "1 nodes have no topology.kubernetes.io/zone label") || !strings.Contains(findings, "can only be placed in zone zone-b") {
+		t.Errorf("unexpected spread findings %q", spread.Findings)
+	}
+
+	result, isErr = callTool(t, handler.ExplainPodPlacement, map[string]any{"namespace": "shop", "name": "web-1"})
+	if isErr {
+		t.Fatalf("unexpected error: %v", result["error"])
+	}
+
+	var explanation []string
+	decodeInto(t, result["explanation"], &explanation)
+	if len(explanation) != 1 || !strings.Contains(explanation[0], "can run on any node") || result["node"] != "a-1" {
+		t.Errorf("expected a pod without rules to be allowed anywhere, got %+v", result)
+	}
+}
@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/kubernetes"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/toolschema"
+)
+
+// PodHandler provides MCP tools that troubleshoot individual pods by
+// combining their status with related events and logs.
+type PodHandler struct {
+	client      kubernetes.ClusterReader
+	alwaysStart bool
+}
+
+// NewPodHandler creates a new PodHandler with the provided Kubernetes client.
+// alwaysStart mirrors the --always-start flag: when true, connectivity and auth errors
+// are intercepted and returned as structured tool errors so the LLM can surface them
+// to the user rather than treating them as retryable failures.
+func NewPodHandler(client kubernetes.ClusterReader, alwaysStart bool) *PodHandler {
+	return &PodHandler{
+		client:      client,
+		alwaysStart: alwaysStart,
+	}
+}
+
+// GetTools returns all pod troubleshooting MCP tools provided by this handler.
+func (h *PodHandler) GetTools() []MCPTool {
+	return []MCPTool{
+		NewMCPTool(
+			mcp.NewTool("diagnose_pod",
+				mcp.WithDescription("Diagnose why a pod is failing in one call: inspects container waiting reasons, last termination state, exit codes, and restart counts, scheduling and eviction status, recent events, and a tail of logs from failing containers (previous instance logs for crash loops), then returns structured findings with likely causes and suggested next steps. Use it before fetching logs or events manually."),
+				toolschema.Input[DiagnosePodParams](),
+				toolschema.Output[DiagnosePodResult](),
+			),
+			h.DiagnosePod,
+		),
+		NewMCPTool(
+			mcp.NewTool("why_pending",
+				mcp.WithDescription("Explain why a Pending pod cannot be scheduled: checks every node for cordoning, untolerated taints, node selector and required node affinity mismatches, and whether the pod's resource requests fit the node's free allocatable capacity, verifies that the pod's PersistentVolumeClaims exist and are bound, and includes the scheduler's latest FailedScheduling message. Returns the nodes that fit, per-node blockers, and blocker counts across nodes."),
+				toolschema.Input[WhyPendingParams](),
+				toolschema.Output[WhyPendingResult](),
+			),
+			h.WhyPending,
+		),
+		NewMCPTool(
+			mcp.NewTool("check_scheduling_fit",
+				mcp.WithDescription("Check which nodes could run a pod, a workload's pod template, or a pod manifest that has not been created yet: evaluates node taints against the pod's tolerations (including the ones the DaemonSet controller adds), the node selector, required node affinity, cordoning, and whether the pod's requests fit each node's free allocatable capacity. Returns the feasible nodes, every reason each other node is excluded, and blocker counts across nodes."),
+				toolschema.Input[CheckSchedulingFitParams](),
+				toolschema.Output[CheckSchedulingFitResult](),
+			),
+			h.CheckSchedulingFit,
+		),
+		NewMCPTool(
+			mcp.NewTool("explain_pod_placement",
+				mcp.WithDescription("Explain where a pod, or a workload's pod template, is allowed to run: renders its node selector, node affinity, inter-pod affinity and anti-affinity, and topologySpreadConstraints into plain language, with how many nodes each node rule matches. For pod affinity and spread rules, reports how many matching pods run in every domain of the topology key (each zone, node, or region), which domains another pod like this one could go to, and findings such as skew above maxSkew or an anti-affinity rule that every domain already violates."),
+				toolschema.Input[ExplainPodPlacementParams](),
+				toolschema.Output[ExplainPodPlacementResult](),
+			),
+			h.ExplainPodPlacement,
+		),
+		NewMCPTool(
+			mcp.NewTool("init_failures",
+				mcp.WithDescription("List pods stuck initializing, such as in Init:CrashLoopBackOff, Init:Error, or Init:0/2. For each pod, reports the init container blocking it with its image, state, exit code and its usual meaning, restart count, how many init containers have completed, how long the pod has been stuck, and a tail of the blocking container's logs (previous instance logs when it is waiting to restart). Pods stuck the longest come first."),
+				toolschema.Input[InitFailuresParams](),
+				toolschema.Output[InitFailuresResult](),
+			),
+			h.InitFailures,
+		),
+		NewMCPTool(
+			mcp.NewTool("projected_tokens_report",
+				mcp.WithDescription("Inspect the projected serviceAccountToken volumes of pods: each token's audience, expiration, and the paths containers mount it at, next to the workload identity annotations (EKS IAM roles for service accounts, Azure workload identity, GKE Workload Identity) of the pod's ServiceAccount. Flags audience mismatches, such as a ServiceAccount annotated for IRSA whose pods have no sts.amazonaws.com token, along with unmounted and long-lived tokens. Pods are grouped by workload; the default kube-api-access volume is hidden unless requested."),
+				toolschema.Input[ProjectedTokensReportParams](),
+				toolschema.Output[ProjectedTokensReportResult](),
+			),
+			h.ProjectedTokensReport,
+		),
+		NewMCPTool(
+			mcp.NewTool("resolve_security_context",
+				mcp.WithDescription("Resolve the effective security settings of every container in a pod or in a workload's pod template: merges the pod-level and container-level securityContext (container wins), applies the defaults for unset fields, computes the effective Linux capabilities, and reports where each value comes from (container, pod, or default). Also returns pod-level host namespace settings, the namespace's Pod Security Admission labels, and per-container concerns for security audits."),
+				toolschema.Input[ResolveSecurityContextParams](),
+				toolschema.Output[ResolveSecurityContextResult](),
+			),
+			h.ResolveSecurityContext,
+		),
+	}
+}
@@ -0,0 +1,247 @@
+package handlers
+
+import (
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ContainerStateSummary is a flattened view of a corev1.ContainerState. Only one
+// of the running, waiting, or terminated states is set at a time, so the summary
+// reports which one applies along with its relevant details.
+type ContainerStateSummary struct {
+	// State is one of "running", "waiting", "terminated", or "unknown".
+	State string `json:"state"`
+
+	// Reason is the short machine-readable reason for a waiting or terminated state.
+	Reason string `json:"reason,omitempty"`
+
+	// Message is the human-readable explanation for a waiting or terminated state.
+	Message string `json:"message,omitempty"`
+
+	// ExitCode is the exit code of a terminated container.
+	ExitCode *int32 `json:"exit_code,omitempty"`
+
+	// StartedAt is when the container started running, in RFC3339 format.
+	StartedAt string `json:"started_at,omitempty"`
+
+	// FinishedAt is when a terminated container finished, in RFC3339 format.
+	FinishedAt string `json:"finished_at,omitempty"`
+}
+
+// summarizeContainerState converts a corev1.ContainerState to a ContainerStateSummary.
+func summarizeContainerState(state corev1.ContainerState) ContainerStateSummary {
+	switch {
+	case state.Running != nil:
+		return ContainerStateSummary{
+			State:     "running",
+			StartedAt: formatTime(state.Running.StartedAt.Time),
+		}
+	case state.Waiting != nil:
+		return ContainerStateSummary{
+			State:   "waiting",
+			Reason:  state.Waiting.Reason,
+			Message: state.Waiting.Message,
+		}
+	case state.Terminated != nil:
+		exitCode := state.Terminated.ExitCode
+		return ContainerStateSummary{
+			State:      "terminated",
+			Reason:     state.Terminated.Reason,
+			Message:    state.Terminated.Message,
+			ExitCode:   &exitCode,
+			StartedAt:  formatTime(state.Terminated.StartedAt.Time),
+			FinishedAt: formatTime(state.Terminated.FinishedAt.Time),
+		}
+	default:
+		return ContainerStateSummary{State: "unknown"}
+	}
+}
+
+// formatTime renders t in RFC3339 format, returning an empty string for the
+// zero time so unset timestamps are omitted from responses.
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// EphemeralContainerInfo describes an ephemeral container attached to a pod,
+// typically through "kubectl debug". These containers are not part of the pod's
+// regular container list, so they are reported separately.
+type EphemeralContainerInfo struct {
+	// Name is the ephemeral container name. It can be passed as the container
+	// parameter of get_logs to read its output.
+	Name string `json:"name"`
+
+	// Image is the container image used by the ephemeral container.
+	Image string `json:"image"`
+
+	// TargetContainer is the container whose process namespace the ephemeral
+	// container shares, if one was specified.
+	TargetContainer string `json:"target_container,omitempty"`
+
+	// State is the current state of the ephemeral container.
+	State ContainerStateSummary `json:"state"`
+}
+
+// ephemeralContainerInfos returns the ephemeral containers attached to pod,
+// combining their spec with the matching entry from the pod status.
+func ephemeralContainerInfos(pod *corev1.Pod) []EphemeralContainerInfo {
+	statuses := make(map[string]corev1.ContainerStatus, len(pod.Status.EphemeralContainerStatuses))
+	for i := range pod.Status.EphemeralContainerStatuses {
+		status := pod.Status.EphemeralContainerStatuses[i]
+		statuses[status.Name] = status
+	}
+
+	infos := make([]EphemeralContainerInfo, 0, len(pod.Spec.EphemeralContainers))
+	for i := range pod.Spec.EphemeralContainers {
+		container := &pod.Spec.EphemeralContainers[i]
+
+		info := EphemeralContainerInfo{
+			Name:            container.Name,
+			Image:           container.Image,
+			TargetContainer: container.TargetContainerName,
+			State:           ContainerStateSummary{State: "unknown"},
+		}
+
+		if status, ok := statuses[container.Name]; ok {
+			info.State = summarizeContainerState(status.State)
+		}
+
+		infos = append(infos, info)
+	}
+
+	return infos
+}
+
+// PodStatusRollup is the view of a pod "kubectl get pods" prints, computed
+// from the full object so listings can show it without returning the pod.
+type PodStatusRollup struct {
+	// Ready counts the ready containers out of the pod's containers, as "1/2".
+	Ready string `json:"ready"`
+
+	// Phase is the pod phase, such as "Running" or "Pending".
+	Phase string `json:"phase"`
+
+	// Status is what kubectl shows in its STATUS column: the phase, or the
+	// reason a container is not running, such as "CrashLoopBackOff",
+	// "Init:0/2", or "Terminating".
+	Status string `json:"status"`
+
+	// Restarts sums the restarts of every container, init containers
+	// included.
+	Restarts int32 `json:"restarts"`
+
+	// Age is how long ago the pod was created.
+	Age string `json:"age"`
+
+	// Node is the node the pod is scheduled on, empty while pending.
+	Node string `json:"node,omitempty"`
+}
+
+// rollUpPodStatus computes the PodStatusRollup of pod as of now.
+func rollUpPodStatus(pod *corev1.Pod, now time.Time) PodStatusRollup {
+	rollup := PodStatusRollup{
+		Phase: string(pod.Status.Phase),
+		Node:  pod.Spec.NodeName,
+		Age:   now.Sub(pod.CreationTimestamp.Time).Round(time.Second).String(),
+	}
+
+	ready := 0
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Ready {
+			ready++
+		}
+		rollup.Restarts += status.RestartCount
+	}
+	for _, status := range pod.Status.InitContainerStatuses {
+		rollup.Restarts += status.RestartCount
+	}
+	rollup.Ready = fmt.Sprintf("%d/%d", ready, len(pod.Spec.Containers))
+
+	rollup.Status = podDisplayStatus(pod)
+	return rollup
+}
+
+// podDisplayStatus mirrors the STATUS column of "kubectl get pods": a
+// failing init container wins over the regular containers, whose waiting or
+// terminated reasons win over the phase, and a pod being deleted is
+// "Terminating".
+func podDisplayStatus(pod *corev1.Pod) string {
+	status := string(pod.Status.Phase)
+	if pod.Status.Reason != "" {
+		status = pod.Status.Reason
+	}
+
+	// Sidecars are init containers that keep running, so a started one
+	// does not hold the pod in initialization.
+	sidecars := make(map[string]bool)
+	for i := range pod.Spec.InitContainers {
+		if policy := pod.Spec.InitContainers[i].RestartPolicy; policy != nil && *policy == corev1.ContainerRestartPolicyAlways {
+			sidecars[pod.Spec.InitContainers[i].Name] = true
+		}
+	}
+
+	initializing := false
+	for i, container := range pod.Status.InitContainerStatuses {
+		switch {
+		case container.State.Terminated != nil && container.State.Terminated.ExitCode == 0:
+			continue
+		case sidecars[container.Name] && container.Started != nil && *container.Started:
+			continue
+		case container.State.Terminated != nil:
+			status = "Init:" + terminatedReason(container.State.Terminated)
+		case container.State.Waiting != nil && container.State.Waiting.Reason != "" && container.State.Waiting.Reason != "PodInitializing":
+			status = "Init:" + container.State.Waiting.Reason
+		default:
+			status = fmt.Sprintf("Init:%d/%d", i, len(pod.Spec.InitContainers))
+		}
+		initializing = true
+		break
+	}
+
+	if !initializing {
+		running := false
+		for i := len(pod.Status.ContainerStatuses) - 1; i >= 0; i-- {
+			container := pod.Status.ContainerStatuses[i]
+			switch {
+			case container.State.Waiting != nil && container.State.Waiting.Reason != "":
+				status = container.State.Waiting.Reason
+			case container.State.Terminated != nil:
+				status = terminatedReason(container.State.Terminated)
+			case container.State.Running != nil && container.Ready:
+				running = true
+			}
+		}
+
+		// A pod whose other containers still run is not done when one of
+		// them completes.
+		if status == "Completed" && running {
+			status = string(corev1.PodRunning)
+		}
+	}
+
+	if pod.DeletionTimestamp != nil {
+		if pod.Status.Reason == "NodeLost" {
+			return "Unknown"
+		}
+		return "Terminating"
+	}
+	return status
+}
+
+// terminatedReason names why a container terminated, falling back to the
+// signal or exit code when the runtime gave no reason.
+func terminatedReason(terminated *corev1.ContainerStateTerminated) string {
+	switch {
+	case terminated.Reason != "":
+		return terminated.Reason
+	case terminated.Signal != 0:
+		return fmt.Sprintf("Signal:%d", terminated.Signal)
+	default:
+		return fmt.Sprintf("ExitCode:%d", terminated.ExitCode)
+	}
+}
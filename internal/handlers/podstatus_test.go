@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestEphemeralContainerInfos(t *testing.T) {
+	t.Parallel()
+
+	started := metav1.NewTime(time.Date(2026, 3, 11, 12, 0, 0, 0, time.UTC))
+	exitCode := int32(0)
+
+	pod := &corev1.Pod{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "app", Image: "nginx"}},
+			EphemeralContainers: []corev1.EphemeralContainer{
+				{
+					EphemeralContainerCommon: corev1.EphemeralContainerCommon{Name: "debugger-abc", Image: "busybox"},
+					TargetContainerName:      "app",
+				},
+				{
+					EphemeralContainerCommon: corev1.EphemeralContainerCommon{Name: "debugger-def", Image: "alpine"},
+				},
+				{
+					EphemeralContainerCommon: corev1.EphemeralContainerCommon{Name: "debugger-ghi", Image: "ubuntu"},
+				},
+			},
+		},
+		Status: corev1.PodStatus{
+			EphemeralContainerStatuses: []corev1.ContainerStatus{
+				{
+					Name:  "debugger-abc",
+					State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{StartedAt: started}},
+				},
+				{
+					Name: "debugger-def",
+					State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+						Reason:    "Completed",
+						ExitCode:  exitCode,
+						StartedAt: started,
+					}},
+				},
+			},
+		},
+	}
+
+	got := ephemeralContainerInfos(pod)
+	want := []EphemeralContainerInfo{
+		{
+			Name:            "debugger-abc",
+			Image:           "busybox",
+			TargetContainer: "app",
+			State:           ContainerStateSummary{State: "running", StartedAt: "2026-03-11T12:00:00Z"},
+		},
+		{
+			Name:  "debugger-def",
+			Image: "alpine",
+			State: ContainerStateSummary{
+				State:     "terminated",
+				Reason:    "Completed",
+				ExitCode:  &exitCode,
+				StartedAt: "2026-03-11T12:00:00Z",
+			},
+		},
+		{
+			Name:  "debugger-ghi",
+			Image: "ubuntu",
+			State: ContainerStateSummary{State: "unknown"},
+		},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("ephemeralContainerInfos() mismatch\nwant: %#v\ngot:  %#v", want, got)
+	}
+}
+
+func TestEphemeralContainerInfos_None(t *testing.T) {
+	t.Parallel()
+
+	got := ephemeralContainerInfos(&corev1.Pod{})
+	if got == nil || len(got) != 0 {
+		t.Fatalf("expected empty non-nil slice, got %#v", got)
+	}
+}
+
+func TestRollUpPodStatus(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 3, 11, 12, 0, 0, 0, time.UTC)
+	always := corev1.ContainerRestartPolicyAlways
+	started := true
+	running := corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}
+
+	tests := []struct {
+		name string
+		pod  corev1.Pod
+		want PodStatusRollup
+	}{
+		{
+			name: "running",
+			pod: corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(now.Add(-90 * time.Minute))},
+				Spec:       corev1.PodSpec{NodeName: "worker-1", Containers: []corev1.Container{{Name: "app"}, {Name: "proxy"}}},
+				Status: corev1.PodStatus{
+					Phase: corev1.PodRunning,
+					ContainerStatuses: []corev1.ContainerStatus{
+						{Name: "app", Ready: true, RestartCount: 2, State: running},
+						{Name: "proxy", Ready: true, State: running},
+					},
+				},
+			},
+			want: PodStatusRollup{Ready: "2/2", Phase: "Running", Status: "Running", Restarts: 2, Age: "1h30m0s", Node: "worker-1"},
+		},
+		{
+			name: "crash looping",
+			pod: corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(now.Add(-time.Minute))},
+				Spec:       corev1.PodSpec{NodeName: "worker-1", Containers: []corev1.Container{{Name: "app"}}},
+				Status: corev1.PodStatus{
+					Phase: corev1.PodRunning,
+					ContainerStatuses: []corev1.ContainerStatus{
+						{Name: "app", RestartCount: 5, State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}}},
+					},
+				},
+			},
+			want: PodStatusRollup{Ready: "0/1", Phase: "Running", Status: "CrashLoopBackOff", Restarts: 5, Age: "1m0s", Node: "worker-1"},
+		},
+		{
+			name: "initializing behind a sidecar",
+			pod: corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(now)},
+				Spec: corev1.PodSpec{
+					InitContainers: []corev1.Container{{Name: "mesh", RestartPolicy: &always}, {Name: "migrate"}},
+					Containers:     []corev1.Container{{Name: "app"}},
+				},
+				Status: corev1.PodStatus{
+					Phase: corev1.PodPending,
+					InitContainerStatuses: []corev1.ContainerStatus{
+						{Name: "mesh", Started: &started, State: running},
+						{Name: "migrate", RestartCount: 1, State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 3}}},
+					},
+				},
+			},
+			want: PodStatusRollup{Ready: "0/1", Phase: "Pending", Status: "Init:ExitCode:3", Restarts: 1, Age: "0s"},
+		},
+		{
+			name: "waiting on init containers",
+			pod: corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(now)},
+				Spec:       corev1.PodSpec{InitContainers: []corev1.Container{{Name: "a"}, {Name: "b"}}, Containers: []corev1.Container{{Name: "app"}}},
+				Status: corev1.PodStatus{
+					Phase: corev1.PodPending,
+					InitContainerStatuses: []corev1.ContainerStatus{
+						{Name: "a", State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "Completed"}}},
+						{Name: "b", State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "PodInitializing"}}},
+					},
+				},
+			},
+			want: PodStatusRollup{Ready: "0/1", Phase: "Pending", Status: "Init:1/2", Age: "0s"},
+		},
+		{
+			name: "terminating",
+			pod: corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(now), DeletionTimestamp: &metav1.Time{Time: now}},
+				Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "app"}}},
+				Status: corev1.PodStatus{
+					Phase:             corev1.PodRunning,
+					ContainerStatuses: []corev1.ContainerStatus{{Name: "app", Ready: true, State: running}},
+				},
+			},
+			want: PodStatusRollup{Ready: "1/1", Phase: "Running", Status: "Terminating", Age: "0s"},
+		},
+	}
+
+	for _, tt := range tests {
+		if got := rollUpPodStatus(&tt.pod, now); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: expected %+v, got %+v", tt.name, tt.want, got)
+		}
+	}
+}
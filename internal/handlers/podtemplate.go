@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// podSpecPaths maps the kinds that run pods to the location of their pod spec.
+var podSpecPaths = map[string][]string{
+	"Pod":                   {"spec"},
+	"PodTemplate":           {"template", "spec"},
+	"Deployment":            {"spec", "template", "spec"},
+	"ReplicaSet":            {"spec", "template", "spec"},
+	"ReplicationController": {"spec", "template", "spec"},
+	"StatefulSet":           {"spec", "template", "spec"},
+	"DaemonSet":             {"spec", "template", "spec"},
+	"Job":                   {"spec", "template", "spec"},
+	"CronJob":               {"spec", "jobTemplate", "spec", "template", "spec"},
+}
+
+// podSpecOf extracts the pod spec of a pod or of a workload's pod template.
+func podSpecOf(obj *unstructured.Unstructured) (*corev1.PodSpec, error) {
+	path, ok := podSpecPaths[obj.GetKind()]
+	if !ok {
+		return nil, fmt.Errorf("kind %s does not run pods", obj.GetKind())
+	}
+
+	raw, found, err := unstructured.NestedMap(obj.Object, path...)
+	if err != nil || !found {
+		return nil, fmt.Errorf("%s %s has no pod spec", obj.GetKind(), obj.GetName())
+	}
+
+	var spec corev1.PodSpec
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(raw, &spec); err != nil {
+		return nil, fmt.Errorf("failed to decode the pod spec of %s %s: %w", obj.GetKind(), obj.GetName(), err)
+	}
+
+	return &spec, nil
+}
+
+// podTemplateLabels returns the labels of a pod, or of the pods a workload
+// creates from its template.
+func podTemplateLabels(obj *unstructured.Unstructured) map[string]string {
+	path, ok := podSpecPaths[obj.GetKind()]
+	if !ok {
+		return nil
+	}
+
+	// The labels sit next to the pod spec, under the template's metadata.
+	labelsPath := append(append([]string{}, path[:len(path)-1]...), "metadata", "labels")
+	labels, _, _ := unstructured.NestedStringMap(obj.Object, labelsPath...)
+	return labels
+}
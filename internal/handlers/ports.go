@@ -0,0 +1,437 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/connectivity"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/response"
+)
+
+// defaultNodePortRange is the kube-apiserver default --service-node-port-range.
+const defaultNodePortRange = "30000-32767"
+
+// Collision types reported by port_allocation_report.
+const (
+	portCollisionHostPort         = "host_port"
+	portCollisionNodePort         = "node_port"
+	portCollisionHostPortNodePort = "host_port_node_port"
+)
+
+// PortAllocationReportParams defines the parameters for the port_allocation_report MCP tool.
+type PortAllocationReportParams struct {
+	// Namespace restricts the report to a namespace. Node ports are shared by
+	// the whole cluster, so collisions and capacity are only complete when it
+	// is empty.
+	Namespace string `json:"namespace,omitempty" description:"Namespace to report on (leave empty for all namespaces; node ports are cluster-wide, so collisions and capacity are only complete across all namespaces)"`
+
+	// NodePortRange is the API server's --service-node-port-range, which the
+	// API does not expose.
+	NodePortRange string `json:"node_port_range,omitempty" default:"30000-32767" description:"The cluster's NodePort range as configured with the API server's --service-node-port-range flag (defaults to 30000-32767)"`
+
+	// Context specifies which Kubernetes context to use for this operation.
+	Context string `json:"context,omitempty" description:"Kubernetes context to use (defaults to current context from kubeconfig)"`
+}
+
+// NodePortAllocation is a node port allocated to a Service.
+type NodePortAllocation struct {
+	NodePort    int32  `json:"node_port"`
+	Protocol    string `json:"protocol"`
+	Namespace   string `json:"namespace"`
+	Service     string `json:"service"`
+	ServiceType string `json:"service_type"`
+
+	// Port and PortName identify the Service port. They are empty for the
+	// health check node port of a LoadBalancer Service, marked HealthCheck.
+	Port        int32  `json:"port,omitempty"`
+	PortName    string `json:"port_name,omitempty"`
+	HealthCheck bool   `json:"health_check,omitempty"`
+}
+
+// HostPortUsage is a port a pod binds on the node it runs on, through a
+// hostPort or because it uses the host network.
+type HostPortUsage struct {
+	// Node is empty for pods that are not scheduled yet.
+	Node        string `json:"node,omitempty"`
+	HostPort    int32  `json:"host_port"`
+	Protocol    string `json:"protocol"`
+	HostIP      string `json:"host_ip,omitempty"`
+	Namespace   string `json:"namespace"`
+	Pod         string `json:"pod"`
+	Container   string `json:"container"`
+	HostNetwork bool   `json:"host_network,omitempty"`
+}
+
+// PortCollision is a port claimed more than once.
+type PortCollision struct {
+	// Type is host_port, node_port, or host_port_node_port.
+	Type     string   `json:"type"`
+	Port     int32    `json:"port"`
+	Protocol string   `json:"protocol,omitempty"`
+	Node     string   `json:"node,omitempty"`
+	Users    []string `json:"users"`
+	Message  string   `json:"message"`
+}
+
+// NodePortCapacity summarizes the use of the NodePort range.
+type NodePortCapacity struct {
+	Range       string  `json:"range"`
+	Size        int     `json:"size"`
+	Allocated   int     `json:"allocated"`
+	Remaining   int     `json:"remaining"`
+	UsedPercent float64 `json:"used_percent"`
+
+	// OutOfRange lists allocated node ports outside Range, which suggests
+	// the cluster uses a different range.
+	OutOfRange []int32 `json:"out_of_range,omitempty"`
+}
+
+// PortAllocationReportResult is the result of the port_allocation_report MCP
+// tool.
+type PortAllocationReportResult struct {
+	NodePortCapacity NodePortCapacity     `json:"node_port_capacity"`
+	NodePorts        []NodePortAllocation `json:"node_ports"`
+	HostPorts        []HostPortUsage      `json:"host_ports"`
+	Collisions       []PortCollision      `json:"collisions"`
+	Notes            []string             `json:"notes,omitempty"`
+}
+
+// PortAllocationReport implements the port_allocation_report MCP tool.
+// It lists the node ports allocated to NodePort and LoadBalancer Services
+// and the host ports bound by pods, then reports collisions and how much of
+// the NodePort range is left. The range itself is an API server flag the
+// API does not expose, so it defaults to the Kubernetes default and can be
+// overridden.
+func (h *NetworkHandler) PortAllocationReport(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var params PortAllocationReportParams
+	if err := bindParams(request, &params); err != nil {
+		return response.Errorf("failed to parse arguments: %s", err)
+	}
+
+	if params.NodePortRange == "" {
+		params.NodePortRange = defaultNodePortRange
+	}
+
+	low, high, err := parsePortRange(params.NodePortRange)
+	if err != nil {
+		return response.Errorf("invalid node_port_range: %v", err)
+	}
+
+	// Use the appropriate client based on context
+	client, err := h.client.ForContext(ctx, params.Context)
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to create client with context %s: %v", params.Context, err)
+	}
+
+	services, err := client.ListServices(ctx, params.Namespace, metav1.ListOptions{})
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to list services: %v", err)
+	}
+
+	pods, err := client.ListPods(ctx, params.Namespace, metav1.ListOptions{})
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to list pods: %v", err)
+	}
+
+	nodePorts := nodePortAllocations(services.Items)
+	hostPorts := hostPortUsages(pods.Items)
+	capacity := nodePortCapacity(nodePorts, low, high)
+
+	result := PortAllocationReportResult{
+		NodePortCapacity: capacity,
+		NodePorts:        nodePorts,
+		HostPorts:        hostPorts,
+		Collisions:       portCollisions(nodePorts, hostPorts),
+	}
+
+	var notes []string
+	if params.Namespace != "" {
+		notes = append(notes, fmt.Sprintf("only namespace %s was checked; node ports are shared by the whole cluster, so collisions with other namespaces and the remaining capacity are not accurate", params.Namespace))
+	}
+	if len(capacity.OutOfRange) > 0 {
+		notes = append(notes, fmt.Sprintf("%d node ports are outside %s, so the cluster likely uses a different --service-node-port-range; set node_port_range to match it",
+			len(capacity.OutOfRange), capacity.Range))
+	}
+	result.Notes = notes
+
+	return response.JSON(result)
+}
+
+// parsePortRange parses a port range such as "30000-32767".
+func parsePortRange(value string) (low, high int32, err error) {
+	from, to, ok := strings.Cut(value, "-")
+	if !ok {
+		return 0, 0, fmt.Errorf("%q is not a range like %s", value, defaultNodePortRange)
+	}
+
+	lo, err := strconv.ParseInt(strings.TrimSpace(from), 10, 32)
+	if err != nil {
+		return 0, 0, fmt.Errorf("%q is not a range like %s", value, defaultNodePortRange)
+	}
+	hi, err := strconv.ParseInt(strings.TrimSpace(to), 10, 32)
+	if err != nil {
+		return 0, 0, fmt.Errorf("%q is not a range like %s", value, defaultNodePortRange)
+	}
+
+	if lo < 1 || hi > 65535 || lo > hi {
+		return 0, 0, fmt.Errorf("%q must be within 1-65535 with the lower port first", value)
+	}
+
+	return int32(lo), int32(hi), nil
+}
+
+// nodePortAllocations lists the node ports allocated to Services, ordered
+// by port.
+func nodePortAllocations(services []corev1.Service) []NodePortAllocation {
+	allocations := make([]NodePortAllocation, 0)
+
+	for i := range services {
+		svc := &services[i]
+		if svc.Spec.Type != corev1.ServiceTypeNodePort && svc.Spec.Type != corev1.ServiceTypeLoadBalancer {
+			continue
+		}
+
+		for _, port := range svc.Spec.Ports {
+			if port.NodePort == 0 {
+				continue
+			}
+			allocations = append(allocations, NodePortAllocation{
+				NodePort:    port.NodePort,
+				Protocol:    portProtocol(port.Protocol),
+				Namespace:   svc.Namespace,
+				Service:     svc.Name,
+				ServiceType: string(svc.Spec.Type),
+				Port:        port.Port,
+				PortName:    port.Name,
+			})
+		}
+
+		if svc.Spec.HealthCheckNodePort != 0 {
+			allocations = append(allocations, NodePortAllocation{
+				NodePort:    svc.Spec.HealthCheckNodePort,
+				Protocol:    string(corev1.ProtocolTCP),
+				Namespace:   svc.Namespace,
+				Service:     svc.Name,
+				ServiceType: string(svc.Spec.Type),
+				HealthCheck: true,
+			})
+		}
+	}
+
+	sort.SliceStable(allocations, func(i, j int) bool {
+		if allocations[i].NodePort != allocations[j].NodePort {
+			return allocations[i].NodePort < allocations[j].NodePort
+		}
+		return allocations[i].Protocol < allocations[j].Protocol
+	})
+
+	return allocations
+}
+
+// hostPortUsages lists the host ports bound by pods that have not
+// terminated, ordered by node and port. Pods on the host network bind every
+// container port they declare.
+func hostPortUsages(pods []corev1.Pod) []HostPortUsage {
+	usages := make([]HostPortUsage, 0)
+
+	for i := range pods {
+		pod := &pods[i]
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+
+		containers := append(append([]corev1.Container{}, pod.Spec.InitContainers...), pod.Spec.Containers...)
+		for _, container := range containers {
+			for _, port := range container.Ports {
+				hostPort := port.HostPort
+				if pod.Spec.HostNetwork && hostPort == 0 {
+					hostPort = port.ContainerPort
+				}
+				if hostPort == 0 {
+					continue
+				}
+
+				usages = append(usages, HostPortUsage{
+					Node:        pod.Spec.NodeName,
+					HostPort:    hostPort,
+					Protocol:    portProtocol(port.Protocol),
+					HostIP:      port.HostIP,
+					Namespace:   pod.Namespace,
+					Pod:         pod.Name,
+					Container:   container.Name,
+					HostNetwork: pod.Spec.HostNetwork,
+				})
+			}
+		}
+	}
+
+	sort.SliceStable(usages, func(i, j int) bool {
+		if usages[i].Node != usages[j].Node {
+			return usages[i].Node < usages[j].Node
+		}
+		if usages[i].HostPort != usages[j].HostPort {
+			return usages[i].HostPort < usages[j].HostPort
+		}
+		if usages[i].Namespace != usages[j].Namespace {
+			return usages[i].Namespace < usages[j].Namespace
+		}
+		return usages[i].Pod < usages[j].Pod
+	})
+
+	return usages
+}
+
+// portCollisions finds ports claimed more than once: a node port allocated
+// to several Services, a host port bound by several pods on the same node
+// and address, and a host port that is also an allocated node port, which
+// makes the node's port-mapping rules and kube-proxy compete for the port.
+func portCollisions(nodePorts []NodePortAllocation, hostPorts []HostPortUsage) []PortCollision {
+	collisions := make([]PortCollision, 0)
+
+	servicesByPort := make(map[int32][]string)
+	var nodePortOrder []int32
+	for _, allocation := range nodePorts {
+		service := "service " + allocation.Namespace + "/" + allocation.Service
+		if _, ok := servicesByPort[allocation.NodePort]; !ok {
+			nodePortOrder = append(nodePortOrder, allocation.NodePort)
+		}
+		if !containsString(servicesByPort[allocation.NodePort], service) {
+			servicesByPort[allocation.NodePort] = append(servicesByPort[allocation.NodePort], service)
+		}
+	}
+	for _, port := range nodePortOrder {
+		if services := servicesByPort[port]; len(services) > 1 {
+			collisions = append(collisions, PortCollision{
+				Type:    portCollisionNodePort,
+				Port:    port,
+				Users:   services,
+				Message: fmt.Sprintf("node port %d is allocated to %d Services", port, len(services)),
+			})
+		}
+	}
+
+	type hostPortKey struct {
+		node     string
+		port     int32
+		protocol string
+	}
+	byKey := make(map[hostPortKey][]HostPortUsage)
+	var keyOrder []hostPortKey
+	for _, usage := range hostPorts {
+		if usage.Node == "" {
+			continue
+		}
+		key := hostPortKey{usage.Node, usage.HostPort, usage.Protocol}
+		if _, ok := byKey[key]; !ok {
+			keyOrder = append(keyOrder, key)
+		}
+		byKey[key] = append(byKey[key], usage)
+	}
+
+	for _, key := range keyOrder {
+		group := byKey[key]
+
+		var users []string
+		for i := range group {
+			for j := range group {
+				if i != j && hostIPsOverlap(group[i].HostIP, group[j].HostIP) {
+					users = append(users, hostPortUser(group[i]))
+					break
+				}
+			}
+		}
+		if len(users) > 1 {
+			collisions = append(collisions, PortCollision{
+				Type:     portCollisionHostPort,
+				Port:     key.port,
+				Protocol: key.protocol,
+				Node:     key.node,
+				Users:    users,
+				Message:  fmt.Sprintf("%d containers bind host port %d/%s on node %s", len(users), key.port, key.protocol, key.node),
+			})
+		}
+
+		if services, ok := servicesByPort[key.port]; ok {
+			users := make([]string, 0, len(group)+len(services))
+			for _, usage := range group {
+				users = append(users, hostPortUser(usage))
+			}
+			users = append(users, services...)
+			collisions = append(collisions, PortCollision{
+				Type:     portCollisionHostPortNodePort,
+				Port:     key.port,
+				Protocol: key.protocol,
+				Node:     key.node,
+				Users:    users,
+				Message:  fmt.Sprintf("host port %d on node %s is also an allocated node port, so traffic to it may reach either the pod or the Service", key.port, key.node),
+			})
+		}
+	}
+
+	return collisions
+}
+
+// hostIPsOverlap reports whether two hostIP values of container ports can
+// bind the same address. An empty or unspecified address binds all of them.
+func hostIPsOverlap(a, b string) bool {
+	isAny := func(ip string) bool { return ip == "" || ip == "0.0.0.0" || ip == "::" }
+	return isAny(a) || isAny(b) || a == b
+}
+
+// hostPortUser names the container behind a host port.
+func hostPortUser(usage HostPortUsage) string {
+	return fmt.Sprintf("pod %s/%s container %s", usage.Namespace, usage.Pod, usage.Container)
+}
+
+// nodePortCapacity summarizes how many ports of the NodePort range from low
+// to high are allocated.
+func nodePortCapacity(allocations []NodePortAllocation, low, high int32) NodePortCapacity {
+	capacity := NodePortCapacity{
+		Range: fmt.Sprintf("%d-%d", low, high),
+		Size:  int(high-low) + 1,
+	}
+
+	seen := make(map[int32]bool)
+	for _, allocation := range allocations {
+		if seen[allocation.NodePort] {
+			continue
+		}
+		seen[allocation.NodePort] = true
+
+		if allocation.NodePort < low || allocation.NodePort > high {
+			capacity.OutOfRange = append(capacity.OutOfRange, allocation.NodePort)
+			continue
+		}
+		capacity.Allocated++
+	}
+
+	capacity.Remaining = capacity.Size - capacity.Allocated
+	capacity.UsedPercent = math.Round(float64(capacity.Allocated)/float64(capacity.Size)*1000) / 10
+
+	return capacity
+}
+
+// portProtocol returns the protocol of a port, which defaults to TCP.
+func portProtocol(protocol corev1.Protocol) string {
+	if protocol == "" {
+		return string(corev1.ProtocolTCP)
+	}
+	return string(protocol)
+}
@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/fakecluster"
+)
+
+func TestParsePortRange(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		value     string
+		low, high int32
+		wantErr   bool
+	}{
+		{value: "30000-32767", low: 30000, high: 32767},
+		{value: "20000 - 22767", low: 20000, high: 22767},
+		{value: "30000", wantErr: true},
+		{value: "32767-30000", wantErr: true},
+		{value: "0-100", wantErr: true},
+		{value: "a-b", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.value, func(t *testing.T) {
+			t.Parallel()
+
+			low, high, err := parsePortRange(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if low != tt.low || high != tt.high {
+				t.Errorf("expected %d-%d, got %d-%d", tt.low, tt.high, low, high)
+			}
+		})
+	}
+}
+
+func TestPortCollisions(t *testing.T) {
+	t.Parallel()
+
+	nodePorts := []NodePortAllocation{
+		{NodePort: 30080, Protocol: "TCP", Namespace: "shop", Service: "web"},
+		{NodePort: 30443, Protocol: "TCP", Namespace: "shop", Service: "web"},
+	}
+	hostPorts := []HostPortUsage{
+		{Node: "worker-1", HostPort: 8080, Protocol: "TCP", Namespace: "a", Pod: "one", Container: "app"},
+		{Node: "worker-1", HostPort: 8080, Protocol: "TCP", Namespace: "b", Pod: "two", Container: "app"},
+		{Node: "worker-1", HostPort: 9090, Protocol: "TCP", HostIP: "10.0.0.1", Namespace: "a", Pod: "three", Container: "app"},
+		{Node: "worker-1", HostPort: 9090, Protocol: "TCP", HostIP: "10.0.0.2", Namespace: "b", Pod: "four", Container: "app"},
+		{Node: "worker-2", HostPort: 8080, Protocol: "UDP", Namespace: "a", Pod: "five", Container: "app"},
+		{Node: "worker-2", HostPort: 30080, Protocol: "TCP", Namespace: "a", Pod: "six", Container: "app"},
+		{HostPort: 8080, Protocol: "TCP", Namespace: "a", Pod: "pending", Container: "app"},
+	}
+
+	var got []string
+	for _, collision := range portCollisions(nodePorts, hostPorts) {
+		got = append(got, collision.Type+"/"+collision.Node)
+		if collision.Type == portCollisionHostPort {
+			want := []string{"pod a/one container app", "pod b/two container app"}
+			if !reflect.DeepEqual(collision.Users, want) {
+				t.Errorf("expected users %v, got %v", want, collision.Users)
+			}
+		}
+	}
+
+	want := []string{"host_port/worker-1", "host_port_node_port/worker-2"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected collisions %v, got %v", want, got)
+	}
+}
+
+func TestPortAllocationReport_FakeCluster(t *testing.T) {
+	t.Parallel()
+
+	handler := NewNetworkHandler(fakecluster.New(fakecluster.Config{
+		Objects: []runtime.Object{
+			&corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop"},
+				Spec: corev1.ServiceSpec{
+					Type:                corev1.ServiceTypeLoadBalancer,
+					Ports:               []corev1.ServicePort{{Name: "http", Port: 80, NodePort: 30080}},
+					HealthCheckNodePort: 31000,
+				},
+			},
+			&corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{Name: "internal", Namespace: "shop"},
+				Spec:       corev1.ServiceSpec{Type: corev1.ServiceTypeClusterIP, Ports: []corev1.ServicePort{{Port: 80}}},
+			},
+			&corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "node-exporter-abcde", Namespace: "monitoring"},
+				Spec: corev1.PodSpec{
+					NodeName:    "worker-1",
+					HostNetwork: true,
+					Containers:  []corev1.Container{{Name: "exporter", Ports: []corev1.ContainerPort{{ContainerPort: 9100}}}},
+				},
+			},
+			&corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "finished", Namespace: "monitoring"},
+				Spec: corev1.PodSpec{
+					NodeName:   "worker-1",
+					Containers: []corev1.Container{{Name: "app", Ports: []corev1.ContainerPort{{ContainerPort: 80, HostPort: 80}}}},
+				},
+				Status: corev1.PodStatus{Phase: corev1.PodSucceeded},
+			},
+		},
+	}), false)
+
+	result, isErr := callTool(t, handler.PortAllocationReport, map[string]any{})
+	if isErr {
+		t.Fatalf("unexpected error: %v", result["error"])
+	}
+
+	var nodePorts []NodePortAllocation
+	decodeInto(t, result["node_ports"], &nodePorts)
+	wantNodePorts := []NodePortAllocation{
+		{NodePort: 30080, Protocol: "TCP", Namespace: "shop", Service: "web", ServiceType: "LoadBalancer", Port: 80, PortName: "http"},
+		{NodePort: 31000, Protocol: "TCP", Namespace: "shop", Service: "web", ServiceType: "LoadBalancer", HealthCheck: true},
+	}
+	if !reflect.DeepEqual(nodePorts, wantNodePorts) {
+		t.Errorf("expected node ports %+v, got %+v", wantNodePorts, nodePorts)
+	}
+
+	var hostPorts []HostPortUsage
+	decodeInto(t, result["host_ports"], &hostPorts)
+	wantHostPorts := []HostPortUsage{
+		{Node: "worker-1", HostPort: 9100, Protocol: "TCP", Namespace: "monitoring", Pod: "node-exporter-abcde", Container: "exporter", HostNetwork: true},
+	}
+	if !reflect.DeepEqual(hostPorts, wantHostPorts) {
+		t.Errorf("expected host ports %+v, got %+v", wantHostPorts, hostPorts)
+	}
+
+	var capacity NodePortCapacity
+	decodeInto(t, result["node_port_capacity"], &capacity)
+	if capacity.Size != 2768 || capacity.Allocated != 2 || capacity.Remaining != 2766 {
+		t.Errorf("unexpected capacity %+v", capacity)
+	}
+
+	if _, isErr := callTool(t, handler.PortAllocationReport, map[string]any{"node_port_range": "bogus"}); !isErr {
+		t.Error("expected an error for an invalid node port range")
+	}
+}
@@ -0,0 +1,442 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/connectivity"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/response"
+)
+
+const (
+	// defaultPriorityEventWindow is how far back priority_class_report looks
+	// for preemption events.
+	defaultPriorityEventWindow = 60
+
+	// defaultPriorityMaxItems is how many workloads and preemptions
+	// priority_class_report lists.
+	defaultPriorityMaxItems = 50
+
+	// systemPriorityClassPrefix is reserved for the built-in
+	// system-cluster-critical and system-node-critical classes.
+	systemPriorityClassPrefix = "system-"
+)
+
+// Event reasons recorded on the pods evicted to make room for another pod.
+const (
+	// eventReasonPreempted is recorded by the scheduler when it preempts a
+	// pod for a pending pod with a higher priority.
+	eventReasonPreempted = "Preempted"
+
+	// eventReasonPreempting is recorded by the kubelet when it evicts a pod
+	// to admit a critical pod on its node.
+	eventReasonPreempting = "Preempting"
+)
+
+// PriorityClassReportParams defines the parameters for the
+// priority_class_report MCP tool.
+type PriorityClassReportParams struct {
+	// Namespace restricts the workload and preemption sections to a
+	// namespace. PriorityClasses are always reported cluster-wide.
+	Namespace string `json:"namespace,omitempty" description:"Namespace to report workloads and preemptions for (leave empty for all namespaces). PriorityClasses are always reported cluster-wide"`
+
+	// EventWindowMinutes is how far back to look for preemption events.
+	EventWindowMinutes int `json:"event_window_minutes,omitempty" minimum:"1" default:"60" description:"How many minutes back to look for preemption events (defaults to 60). Events are usually kept for one hour"`
+
+	// MaxItems caps the workloads and preemptions listed; totals are always reported.
+	MaxItems int `json:"max_items,omitempty" minimum:"1" default:"50" description:"Maximum number of workloads and preemptions listed (defaults to 50). Totals always cover every entry"`
+
+	// Context specifies which Kubernetes context to use for this operation.
+	Context string `json:"context,omitempty" description:"Kubernetes context to use (defaults to current context from kubeconfig)"`
+}
+
+// PriorityClassInfo is a PriorityClass with the number of pods using it.
+type PriorityClassInfo struct {
+	Name             string `json:"name"`
+	Value            int32  `json:"value"`
+	GlobalDefault    bool   `json:"global_default"`
+	PreemptionPolicy string `json:"preemption_policy"`
+	Description      string `json:"description,omitempty"`
+
+	// System is true for the built-in classes reserved for critical
+	// cluster components.
+	System bool `json:"system"`
+
+	// Pods and Workloads count the running pods using the class and the
+	// workloads they belong to. They are only reported when pods could be listed.
+	Pods      *int `json:"pods,omitempty"`
+	Workloads *int `json:"workloads,omitempty"`
+}
+
+// PriorityWorkload is a workload and the priority its pods run at.
+type PriorityWorkload struct {
+	Namespace string `json:"namespace"`
+
+	// Workload is "Kind/name", or "Pod/name" for standalone pods.
+	Workload string `json:"workload"`
+
+	// PriorityClass is empty for pods created without a priorityClassName
+	// while no global default class existed.
+	PriorityClass    string `json:"priority_class,omitempty"`
+	Priority         int32  `json:"priority"`
+	PreemptionPolicy string `json:"preemption_policy"`
+	Pods             int    `json:"pods"`
+}
+
+// PreemptedPod is a pod that was evicted to make room for a pod with a
+// higher priority, reconstructed from its events.
+type PreemptedPod struct {
+	Namespace string `json:"namespace"`
+	Pod       string `json:"pod"`
+
+	// Reason is Preempted for scheduler preemption, or Preempting when the
+	// kubelet evicted the pod to admit a critical pod.
+	Reason   string `json:"reason"`
+	Message  string `json:"message"`
+	Node     string `json:"node,omitempty"`
+	Count    int32  `json:"count"`
+	LastSeen string `json:"last_seen"`
+
+	// Workload and Priority are only known while the pod still exists.
+	Workload string `json:"workload,omitempty"`
+	Priority *int32 `json:"priority,omitempty"`
+
+	lastSeen time.Time
+}
+
+// PriorityClassReportResult is the result of the priority_class_report MCP
+// tool.
+type PriorityClassReportResult struct {
+	Namespace          string              `json:"namespace"`
+	PriorityClasses    []PriorityClassInfo `json:"priority_classes"`
+	GlobalDefault      string              `json:"global_default"`
+	WorkloadCount      int                 `json:"workload_count"`
+	Workloads          []PriorityWorkload  `json:"workloads"`
+	PreemptionCount    int                 `json:"preemption_count"`
+	Preemptions        []PreemptedPod      `json:"preemptions"`
+	EventWindowMinutes int                 `json:"event_window_minutes"`
+	Findings           []string            `json:"findings,omitempty"`
+	Warnings           []string            `json:"warnings,omitempty"`
+}
+
+// PriorityClassReport implements the priority_class_report MCP tool.
+// It lists the PriorityClasses in the cluster with how many pods use each,
+// groups running pods by workload and the priority they run at, and reports
+// the pods preempted within the event window, from the Preempted events the
+// scheduler records and the Preempting events the kubelet records. Pods and
+// events are best effort and report listing failures under warnings.
+func (h *ClusterHandler) PriorityClassReport(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var params PriorityClassReportParams
+	if err := bindParams(request, &params); err != nil {
+		return response.Errorf("failed to parse arguments: %s", err)
+	}
+
+	eventWindow := params.EventWindowMinutes
+	if eventWindow == 0 {
+		eventWindow = defaultPriorityEventWindow
+	}
+
+	maxItems := params.MaxItems
+	if maxItems == 0 {
+		maxItems = defaultPriorityMaxItems
+	}
+
+	// Use the appropriate client based on context
+	client, err := h.client.ForContext(ctx, params.Context)
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to create client with context %s: %v", params.Context, err)
+	}
+
+	classList, err := client.ListPriorityClasses(ctx, metav1.ListOptions{})
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to list priority classes: %v", err)
+	}
+
+	now := h.now()
+	var warnings []string
+
+	var pods []corev1.Pod
+	podsListed := false
+	if list, err := client.ListPods(ctx, params.Namespace, metav1.ListOptions{}); err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		warnings = append(warnings, fmt.Sprintf("failed to list pods, workloads were not reported: %v", err))
+	} else {
+		pods = list.Items
+		podsListed = true
+	}
+
+	classes := priorityClasses(classList.Items)
+	workloads := priorityWorkloads(pods, classes)
+	if podsListed {
+		countPriorityClassUsage(classes, workloads)
+	}
+
+	preemptions := make([]PreemptedPod, 0)
+	if list, err := client.ListEvents(ctx, params.Namespace, metav1.ListOptions{}); err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		warnings = append(warnings, fmt.Sprintf("failed to list events, preemptions were not reported: %v", err))
+	} else {
+		preemptions = preemptedPods(list.Items, pods, now.Add(-time.Duration(eventWindow)*time.Minute))
+	}
+
+	globalDefault := ""
+	for i := range classes {
+		if classes[i].GlobalDefault {
+			globalDefault = classes[i].Name
+			break
+		}
+	}
+
+	return response.JSON(PriorityClassReportResult{
+		Namespace:          params.Namespace,
+		PriorityClasses:    classes,
+		GlobalDefault:      globalDefault,
+		WorkloadCount:      len(workloads),
+		Workloads:          truncate(workloads, maxItems),
+		PreemptionCount:    len(preemptions),
+		Preemptions:        truncate(preemptions, maxItems),
+		EventWindowMinutes: eventWindow,
+		Findings:           priorityFindings(classes, workloads, podsListed),
+		Warnings:           warnings,
+	})
+}
+
+// priorityClasses reports the PriorityClasses from the highest value.
+func priorityClasses(items []schedulingv1.PriorityClass) []PriorityClassInfo {
+	classes := make([]PriorityClassInfo, 0, len(items))
+	for i := range items {
+		class := &items[i]
+		policy := string(corev1.PreemptLowerPriority)
+		if class.PreemptionPolicy != nil {
+			policy = string(*class.PreemptionPolicy)
+		}
+		classes = append(classes, PriorityClassInfo{
+			Name:             class.Name,
+			Value:            class.Value,
+			GlobalDefault:    class.GlobalDefault,
+			PreemptionPolicy: policy,
+			Description:      class.Description,
+			System:           strings.HasPrefix(class.Name, systemPriorityClassPrefix),
+		})
+	}
+
+	sort.SliceStable(classes, func(i, j int) bool {
+		if classes[i].Value != classes[j].Value {
+			return classes[i].Value > classes[j].Value
+		}
+		return classes[i].Name < classes[j].Name
+	})
+
+	return classes
+}
+
+// priorityWorkloads groups the pods that have not finished by namespace,
+// workload, and priority class, from the highest priority. A workload whose
+// pods run at different priorities, such as during a rollout that changes
+// the class, is reported once per priority.
+func priorityWorkloads(pods []corev1.Pod, classes []PriorityClassInfo) []PriorityWorkload {
+	type key struct {
+		namespace, workload, class string
+		priority                   int32
+	}
+
+	policies := make(map[string]string, len(classes))
+	for _, class := range classes {
+		policies[class.Name] = class.PreemptionPolicy
+	}
+
+	grouped := make(map[key]*PriorityWorkload)
+	for i := range pods {
+		pod := &pods[i]
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+
+		workload := podWorkload(pod)
+		if workload == "" {
+			workload = "Pod/" + pod.Name
+		}
+
+		var priority int32
+		if pod.Spec.Priority != nil {
+			priority = *pod.Spec.Priority
+		}
+
+		k := key{namespace: pod.Namespace, workload: workload, class: pod.Spec.PriorityClassName, priority: priority}
+		entry, ok := grouped[k]
+		if !ok {
+			policy := string(corev1.PreemptLowerPriority)
+			if pod.Spec.PreemptionPolicy != nil {
+				policy = string(*pod.Spec.PreemptionPolicy)
+			} else if classPolicy, ok := policies[pod.Spec.PriorityClassName]; ok {
+				policy = classPolicy
+			}
+			entry = &PriorityWorkload{
+				Namespace:        pod.Namespace,
+				Workload:         workload,
+				PriorityClass:    pod.Spec.PriorityClassName,
+				Priority:         priority,
+				PreemptionPolicy: policy,
+			}
+			grouped[k] = entry
+		}
+		entry.Pods++
+	}
+
+	workloads := make([]PriorityWorkload, 0, len(grouped))
+	for _, entry := range grouped {
+		workloads = append(workloads, *entry)
+	}
+
+	sort.SliceStable(workloads, func(i, j int) bool {
+		a, b := &workloads[i], &workloads[j]
+		if a.Priority != b.Priority {
+			return a.Priority > b.Priority
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Workload < b.Workload
+	})
+
+	return workloads
+}
+
+// countPriorityClassUsage fills in how many pods and workloads use each class.
+func countPriorityClassUsage(classes []PriorityClassInfo, workloads []PriorityWorkload) {
+	for i := range classes {
+		pods, count := 0, 0
+		for _, workload := range workloads {
+			if workload.PriorityClass == classes[i].Name {
+				pods += workload.Pods
+				count++
+			}
+		}
+		classes[i].Pods = &pods
+		classes[i].Workloads = &count
+	}
+}
+
+// preemptedPods returns the pods with a Preempted or Preempting event last
+// seen after since, newest first. pods fills in the workload and priority of
+// the victims that still exist.
+func preemptedPods(events []corev1.Event, pods []corev1.Pod, since time.Time) []PreemptedPod {
+	existing := make(map[string]*corev1.Pod, len(pods))
+	for i := range pods {
+		existing[pods[i].Namespace+"/"+pods[i].Name] = &pods[i]
+	}
+
+	preempted := make([]PreemptedPod, 0)
+	for i := range events {
+		event := &events[i]
+		if event.InvolvedObject.Kind != "Pod" || (event.Reason != eventReasonPreempted && event.Reason != eventReasonPreempting) {
+			continue
+		}
+
+		lastSeen := eventLastSeen(event)
+		if lastSeen.Before(since) {
+			continue
+		}
+
+		count := event.Count
+		if event.Series != nil && event.Series.Count > count {
+			count = event.Series.Count
+		}
+		if count == 0 {
+			count = 1
+		}
+
+		entry := PreemptedPod{
+			Namespace: event.InvolvedObject.Namespace,
+			Pod:       event.InvolvedObject.Name,
+			Reason:    event.Reason,
+			Message:   event.Message,
+			Node:      event.Source.Host,
+			Count:     count,
+			LastSeen:  formatTime(lastSeen),
+			lastSeen:  lastSeen,
+		}
+
+		// The scheduler names the node in the message, as in
+		// "Preempted by pod 1b2c... on node worker-2".
+		if _, node, ok := strings.Cut(event.Message, " on node "); ok && entry.Node == "" {
+			entry.Node = strings.TrimSpace(node)
+		}
+
+		if pod, ok := existing[entry.Namespace+"/"+entry.Pod]; ok {
+			entry.Workload = podWorkload(pod)
+			entry.Priority = pod.Spec.Priority
+			if entry.Node == "" {
+				entry.Node = pod.Spec.NodeName
+			}
+		}
+
+		preempted = append(preempted, entry)
+	}
+
+	sort.SliceStable(preempted, func(i, j int) bool {
+		return preempted[i].lastSeen.After(preempted[j].lastSeen)
+	})
+
+	return preempted
+}
+
+// priorityFindings flags PriorityClass setups that affect scheduling: more
+// than one global default, and pods using a class that no longer exists.
+func priorityFindings(classes []PriorityClassInfo, workloads []PriorityWorkload, podsListed bool) []string {
+	var findings []string
+
+	var defaults []string
+	known := make(map[string]bool, len(classes))
+	for _, class := range classes {
+		known[class.Name] = true
+		if class.GlobalDefault {
+			defaults = append(defaults, class.Name)
+		}
+	}
+
+	if len(defaults) > 1 {
+		findings = append(findings, fmt.Sprintf("%d PriorityClasses are marked as the global default (%s); pods without a priorityClassName get the one with the lowest value", len(defaults), strings.Join(defaults, ", ")))
+	}
+
+	if !podsListed {
+		return findings
+	}
+
+	missing := make(map[string][]string)
+	var missingNames []string
+	for _, workload := range workloads {
+		if workload.PriorityClass == "" || known[workload.PriorityClass] {
+			continue
+		}
+		if _, ok := missing[workload.PriorityClass]; !ok {
+			missingNames = append(missingNames, workload.PriorityClass)
+		}
+		missing[workload.PriorityClass] = append(missing[workload.PriorityClass], workload.Namespace+"/"+workload.Workload)
+	}
+	sort.Strings(missingNames)
+
+	for _, name := range missingNames {
+		findings = append(findings, fmt.Sprintf("PriorityClass %q no longer exists but is used by %s; new pods that reference it are rejected at admission", name, strings.Join(truncate(missing[name], 10), ", ")))
+	}
+
+	return findings
+}
@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/fakecluster"
+)
+
+// priorityPod builds a running pod owned by owner ("Kind/name") that uses a
+// PriorityClass.
+func priorityPod(namespace, name, owner, class string, priority int32) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec:       corev1.PodSpec{PriorityClassName: class, Priority: &priority, NodeName: "worker-1"},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+	if kind, ownerName, ok := strings.Cut(owner, "/"); ok {
+		controller := true
+		pod.OwnerReferences = []metav1.OwnerReference{{Kind: kind, Name: ownerName, Controller: &controller}}
+	}
+	return pod
+}
+
+func TestPriorityFindings(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		classes   []PriorityClassInfo
+		workloads []PriorityWorkload
+		want      []string
+	}{
+		{
+			name:      "consistent",
+			classes:   []PriorityClassInfo{{Name: "high", Value: 1000}, {Name: "default", GlobalDefault: true}},
+			workloads: []PriorityWorkload{{Namespace: "shop", Workload: "StatefulSet/db", PriorityClass: "high", Priority: 1000}, {Namespace: "shop", Workload: "Pod/debug"}},
+		},
+		{
+			name:    "several global defaults",
+			classes: []PriorityClassInfo{{Name: "normal", Value: 100, GlobalDefault: true}, {Name: "low", GlobalDefault: true}},
+			want:    []string{"2 PriorityClasses are marked as the global default (normal, low); pods without a priorityClassName get the one with the lowest value"},
+		},
+		{
+			name:    "deleted class",
+			classes: []PriorityClassInfo{{Name: "high", Value: 1000}},
+			workloads: []PriorityWorkload{
+				{Namespace: "shop", Workload: "Deployment/web", PriorityClass: "critical", Priority: 5000},
+				{Namespace: "shop", Workload: "Deployment/api", PriorityClass: "critical", Priority: 5000},
+			},
+			want: []string{`PriorityClass "critical" no longer exists but is used by shop/Deployment/web, shop/Deployment/api; new pods that reference it are rejected at admission`},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := priorityFindings(tt.classes, tt.workloads, true); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected findings %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestPreemptedPods(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now().Truncate(time.Second)
+	since := now.Add(-time.Hour)
+
+	events := []corev1.Event{
+		{
+			InvolvedObject: corev1.ObjectReference{Kind: "Pod", Namespace: "batch", Name: "report-abc"},
+			Reason:         eventReasonPreempted,
+			Message:        "Preempted by pod 6f1c2d3e-aaaa-bbbb-cccc-1234567890ab on node worker-2",
+			LastTimestamp:  metav1.NewTime(now.Add(-10 * time.Minute)),
+		},
+		{
+			InvolvedObject: corev1.ObjectReference{Kind: "Pod", Namespace: "batch", Name: "etl-xyz"},
+			Reason:         eventReasonPreempting,
+			Message:        "Preempted in order to admit critical pod",
+			Source:         corev1.EventSource{Host: "worker-3"},
+			Count:          2,
+			LastTimestamp:  metav1.NewTime(now.Add(-5 * time.Minute)),
+		},
+		{
+			InvolvedObject: corev1.ObjectReference{Kind: "Pod", Namespace: "batch", Name: "old"},
+			Reason:         eventReasonPreempted,
+			LastTimestamp:  metav1.NewTime(now.Add(-2 * time.Hour)),
+		},
+		{
+			InvolvedObject: corev1.ObjectReference{Kind: "Pod", Namespace: "batch", Name: "report-abc"},
+			Reason:         "Scheduled",
+			LastTimestamp:  metav1.NewTime(now),
+		},
+	}
+
+	pods := []corev1.Pod{*priorityPod("batch", "etl-xyz", "Job/etl", "batch-low", 10)}
+
+	got := preemptedPods(events, pods, since)
+	if len(got) != 2 {
+		t.Fatalf("expected 2 preempted pods, got %+v", got)
+	}
+
+	if got[0].Pod != "etl-xyz" || got[0].Node != "worker-3" || got[0].Count != 2 || got[0].Workload != "Job/etl" || got[0].Priority == nil || *got[0].Priority != 10 {
+		t.Errorf("unexpected kubelet preemption %+v", got[0])
+	}
+	if got[1].Pod != "report-abc" || got[1].Node != "worker-2" || got[1].Count != 1 || got[1].Workload != "" {
+		t.Errorf("unexpected scheduler preemption %+v", got[1])
+	}
+}
+
+func TestPriorityClassReport_FakeCluster(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now().Truncate(time.Second)
+	never := corev1.PreemptNever
+
+	handler := NewClusterHandler(fakecluster.New(fakecluster.Config{
+		Objects: []runtime.Object{
+			&schedulingv1.PriorityClass{ObjectMeta: metav1.ObjectMeta{Name: "system-cluster-critical"}, Value: 2000000000},
+			&schedulingv1.PriorityClass{ObjectMeta: metav1.ObjectMeta{Name: "high"}, Value: 1000, Description: "Customer facing"},
+			&schedulingv1.PriorityClass{ObjectMeta: metav1.ObjectMeta{Name: "batch-low"}, Value: -10, PreemptionPolicy: &never},
+			priorityPod("kube-system", "coredns-1", "Deployment/coredns", "system-cluster-critical", 2000000000),
+			priorityPod("shop", "web-1", "StatefulSet/web", "high", 1000),
+			priorityPod("shop", "web-2", "StatefulSet/web", "high", 1000),
+			priorityPod("batch", "etl-1", "Job/etl", "batch-low", -10),
+			priorityPod("batch", "debug", "", "", 0),
+			&corev1.Event{
+				ObjectMeta:     metav1.ObjectMeta{Name: "report-abc.1", Namespace: "batch"},
+				InvolvedObject: corev1.ObjectReference{Kind: "Pod", Namespace: "batch", Name: "report-abc"},
+				Reason:         eventReasonPreempted,
+				Message:        "Preempted by pod 6f1c2d3e on node worker-2",
+				LastTimestamp:  metav1.NewTime(now.Add(-time.Minute)),
+			},
+		},
+	}), false)
+	handler.now = func() time.Time { return now }
+
+	result, isErr := callTool(t, handler.PriorityClassReport, map[string]any{})
+	if isErr {
+		t.Fatalf("unexpected error: %v", result["error"])
+	}
+
+	var classes []PriorityClassInfo
+	decodeInto(t, result["priority_classes"], &classes)
+	if len(classes) != 3 || classes[0].Name != "system-cluster-critical" || !classes[0].System || classes[2].PreemptionPolicy != "Never" {
+		t.Fatalf("unexpected priority classes %+v", classes)
+	}
+	if classes[1].Pods == nil || *classes[1].Pods != 2 || classes[1].Workloads == nil || *classes[1].Workloads != 1 {
+		t.Errorf("expected the high class to be used by 2 pods of one workload, got %+v", classes[1])
+	}
+
+	var workloads []PriorityWorkload
+	decodeInto(t, result["workloads"], &workloads)
+	want := []string{"kube-system/Deployment/coredns", "shop/StatefulSet/web", "batch/Pod/debug", "batch/Job/etl"}
+	got := make([]string, 0, len(workloads))
+	for _, workload := range workloads {
+		got = append(got, workload.Namespace+"/"+workload.Workload)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected workloads %q, got %q", want, got)
+	}
+	if workloads[3].PreemptionPolicy != "Never" {
+		t.Errorf("expected the class preemption policy to apply, got %+v", workloads[3])
+	}
+
+	var preemptions []PreemptedPod
+	decodeInto(t, result["preemptions"], &preemptions)
+	if len(preemptions) != 1 || preemptions[0].Pod != "report-abc" || preemptions[0].Node != "worker-2" {
+		t.Errorf("unexpected preemptions %+v", preemptions)
+	}
+
+	if result["global_default"] != "" || result["findings"] != nil || result["warnings"] != nil {
+		t.Errorf("unexpected result %+v", result)
+	}
+}
@@ -0,0 +1,360 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/connectivity"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/response"
+)
+
+const (
+	// maxProjectedTokenPods bounds how many pod names each
+	// projected_tokens_report entry lists; PodCount always covers all of them.
+	maxProjectedTokenPods = 10
+
+	// defaultTokenExpirationSeconds is the expiration the API server sets on
+	// serviceAccountToken projections that do not specify one.
+	defaultTokenExpirationSeconds = 3600
+
+	// defaultTokenVolumePrefix is the name prefix of the token volume the
+	// API server adds to every pod that automounts its ServiceAccount token.
+	defaultTokenVolumePrefix = "kube-api-access-"
+)
+
+// workloadIdentity is a cloud workload identity integration that exchanges a
+// projected ServiceAccount token for cloud credentials.
+type workloadIdentity struct {
+	name string
+
+	// annotation is the ServiceAccount annotation that enables it.
+	annotation string
+
+	// audience is the token audience the cloud provider expects, if it uses
+	// a projected token.
+	audience string
+}
+
+// workloadIdentities are the workload identity integrations recognized by
+// projected_tokens_report.
+var workloadIdentities = []workloadIdentity{
+	{name: "EKS IAM roles for service accounts", annotation: "eks.amazonaws.com/role-arn", audience: "sts.amazonaws.com"},
+	{name: "Azure workload identity", annotation: "azure.workload.identity/client-id", audience: "api://AzureADTokenExchange"},
+	{name: "GKE Workload Identity", annotation: "iam.gke.io/gcp-service-account"},
+}
+
+// ProjectedTokensReportParams defines the parameters for the projected_tokens_report MCP tool.
+type ProjectedTokensReportParams struct {
+	// Namespace restricts the report to a namespace.
+	Namespace string `json:"namespace,omitempty" description:"Namespace to report on (leave empty for all namespaces)"`
+
+	// LabelSelector restricts the report to matching pods.
+	LabelSelector string `json:"label_selector,omitempty" description:"Label selector to filter pods (e.g., 'app=web')"`
+
+	// IncludeDefault includes the kube-api-access token volume that every pod
+	// gets by default.
+	IncludeDefault bool `json:"include_default,omitempty" description:"Include the default kube-api-access token volume mounted in every pod (defaults to false)"`
+
+	// Context specifies which Kubernetes context to use for this operation.
+	Context string `json:"context,omitempty" description:"Kubernetes context to use (defaults to current context from kubeconfig)"`
+}
+
+// ProjectedToken is a serviceAccountToken projection in a pod.
+type ProjectedToken struct {
+	Volume string `json:"volume"`
+	Path   string `json:"path"`
+
+	// Audience is empty when the token is issued for the API server's own
+	// audiences.
+	Audience          string `json:"audience,omitempty"`
+	ExpirationSeconds int64  `json:"expiration_seconds"`
+
+	// MountPaths are where containers mount the volume, as container:path.
+	MountPaths []string `json:"mount_paths,omitempty"`
+
+	// Default is true for the kube-api-access volume added to every pod.
+	Default bool `json:"default,omitempty"`
+}
+
+// PodTokenProjections is a group of pods of the same workload and
+// ServiceAccount that project the same tokens.
+type PodTokenProjections struct {
+	Namespace string `json:"namespace"`
+
+	// Workload is the controller of the pods, such as Deployment/web. It is
+	// empty for standalone pods.
+	Workload       string   `json:"workload,omitempty"`
+	Pods           []string `json:"pods"`
+	PodCount       int      `json:"pod_count"`
+	ServiceAccount string   `json:"service_account"`
+
+	// IdentityAnnotations are the workload identity annotations of the
+	// ServiceAccount.
+	IdentityAnnotations map[string]string `json:"identity_annotations,omitempty"`
+	Tokens              []ProjectedToken  `json:"tokens"`
+	Findings            []string          `json:"findings"`
+}
+
+// ProjectedTokensReportResult is the result of the projected_tokens_report
+// MCP tool.
+type ProjectedTokensReportResult struct {
+	Pods     []PodTokenProjections `json:"pods"`
+	Count    int                   `json:"count"`
+	Warnings []string              `json:"warnings,omitempty"`
+}
+
+// ProjectedTokensReport implements the projected_tokens_report MCP tool.
+// It lists the serviceAccountToken projections of pods with their audiences,
+// expirations, and mount paths, next to the workload identity annotations of
+// the pods' ServiceAccounts, and flags audience mismatches that break EKS
+// IAM roles for service accounts and Azure workload identity. Pods of the
+// same workload with the same projections are reported once. ServiceAccounts
+// are read on a best-effort basis.
+func (h *PodHandler) ProjectedTokensReport(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var params ProjectedTokensReportParams
+	if err := bindParams(request, &params); err != nil {
+		return response.Errorf("failed to parse arguments: %s", err)
+	}
+
+	// Use the appropriate client based on context
+	client, err := h.client.ForContext(ctx, params.Context)
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to create client with context %s: %v", params.Context, err)
+	}
+
+	pods, err := client.ListPods(ctx, params.Namespace, metav1.ListOptions{LabelSelector: params.LabelSelector})
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to list pods: %v", err)
+	}
+
+	var warnings []string
+
+	// A nil map skips the identity checks when ServiceAccounts cannot be read.
+	var annotations map[string]map[string]string
+	if list, err := client.ListServiceAccounts(ctx, params.Namespace, metav1.ListOptions{}); err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		warnings = append(warnings, fmt.Sprintf("failed to list service accounts, workload identity annotations were not checked: %v", err))
+	} else {
+		annotations = identityAnnotations(list.Items)
+	}
+
+	projections := podTokenProjections(pods.Items, annotations, params.IncludeDefault)
+
+	return response.JSON(ProjectedTokensReportResult{
+		Pods:     projections,
+		Count:    len(projections),
+		Warnings: warnings,
+	})
+}
+
+// identityAnnotations returns the workload identity annotations of each
+// ServiceAccount, keyed by namespace/name. ServiceAccounts without any map
+// to an empty map.
+func identityAnnotations(accounts []corev1.ServiceAccount) map[string]map[string]string {
+	result := make(map[string]map[string]string, len(accounts))
+	for i := range accounts {
+		found := make(map[string]string)
+		for _, identity := range workloadIdentities {
+			if value, ok := accounts[i].Annotations[identity.annotation]; ok {
+				found[identity.annotation] = value
+			}
+		}
+		result[accounts[i].Namespace+"/"+accounts[i].Name] = found
+	}
+	return result
+}
+
+// podTokenProjections groups the pods with token projections by workload,
+// ServiceAccount, and projections, ordered by namespace and workload. Pods
+// that only have the default token are skipped unless includeDefault is set
+// or their ServiceAccount has workload identity annotations.
+func podTokenProjections(pods []corev1.Pod, annotations map[string]map[string]string, includeDefault bool) []PodTokenProjections {
+	groups := make(map[string]*PodTokenProjections)
+	var order []string
+
+	for i := range pods {
+		pod := &pods[i]
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+
+		serviceAccount := pod.Spec.ServiceAccountName
+		if serviceAccount == "" {
+			serviceAccount = "default"
+		}
+
+		accountAnnotations, accountFound := annotations[pod.Namespace+"/"+serviceAccount]
+
+		// Pods of annotated ServiceAccounts are reported even without
+		// tokens, since a missing token is what breaks their identity.
+		tokens := projectedTokens(pod, includeDefault)
+		if len(tokens) == 0 && len(accountAnnotations) == 0 {
+			continue
+		}
+
+		entry := PodTokenProjections{
+			Namespace:           pod.Namespace,
+			Workload:            podWorkload(pod),
+			Pods:                []string{},
+			ServiceAccount:      serviceAccount,
+			IdentityAnnotations: accountAnnotations,
+			Tokens:              tokens,
+			Findings:            []string{},
+		}
+		if entry.Tokens == nil {
+			entry.Tokens = []ProjectedToken{}
+		}
+		if len(accountAnnotations) == 0 {
+			entry.IdentityAnnotations = nil
+		}
+
+		if annotations != nil && !accountFound {
+			entry.Findings = append(entry.Findings, fmt.Sprintf("ServiceAccount %s does not exist, so the kubelet cannot issue its tokens", serviceAccount))
+		}
+		entry.Findings = append(entry.Findings, tokenFindings(tokens, accountAnnotations, annotations != nil)...)
+
+		// Group by workload and by the projections themselves, so a rollout
+		// that changes them shows both versions.
+		fingerprint, _ := json.Marshal(entry)
+		owner := entry.Workload
+		if owner == "" {
+			owner = "Pod/" + pod.Name
+		}
+		key := pod.Namespace + "/" + owner + "/" + string(fingerprint)
+
+		group, ok := groups[key]
+		if !ok {
+			group = &entry
+			groups[key] = group
+			order = append(order, key)
+		}
+		group.PodCount++
+		if len(group.Pods) < maxProjectedTokenPods {
+			group.Pods = append(group.Pods, pod.Name)
+		}
+	}
+
+	result := make([]PodTokenProjections, 0, len(order))
+	for _, key := range order {
+		result = append(result, *groups[key])
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Namespace != result[j].Namespace {
+			return result[i].Namespace < result[j].Namespace
+		}
+		if result[i].Workload != result[j].Workload {
+			return result[i].Workload < result[j].Workload
+		}
+		return result[i].Pods[0] < result[j].Pods[0]
+	})
+
+	return result
+}
+
+// projectedTokens lists the serviceAccountToken projections of a pod, in
+// volume order, with where each container mounts them.
+func projectedTokens(pod *corev1.Pod, includeDefault bool) []ProjectedToken {
+	var tokens []ProjectedToken
+
+	for _, volume := range pod.Spec.Volumes {
+		if volume.Projected == nil {
+			continue
+		}
+
+		isDefault := strings.HasPrefix(volume.Name, defaultTokenVolumePrefix)
+		if isDefault && !includeDefault {
+			continue
+		}
+
+		var mounts []string
+		containers := append(append([]corev1.Container{}, pod.Spec.InitContainers...), pod.Spec.Containers...)
+		for _, container := range containers {
+			for _, mount := range container.VolumeMounts {
+				if mount.Name == volume.Name {
+					mounts = append(mounts, container.Name+":"+mount.MountPath)
+				}
+			}
+		}
+
+		for _, source := range volume.Projected.Sources {
+			projection := source.ServiceAccountToken
+			if projection == nil {
+				continue
+			}
+
+			token := ProjectedToken{
+				Volume:            volume.Name,
+				Path:              projection.Path,
+				Audience:          projection.Audience,
+				ExpirationSeconds: defaultTokenExpirationSeconds,
+				Default:           isDefault,
+			}
+			if projection.ExpirationSeconds != nil {
+				token.ExpirationSeconds = *projection.ExpirationSeconds
+			}
+			for _, mount := range mounts {
+				token.MountPaths = append(token.MountPaths, mount+"/"+projection.Path)
+			}
+
+			tokens = append(tokens, token)
+		}
+	}
+
+	return tokens
+}
+
+// tokenFindings explains mismatches between the projected tokens of a pod and
+// the workload identity annotations of its ServiceAccount. When annotations
+// were not read, only the tokens themselves are checked.
+func tokenFindings(tokens []ProjectedToken, annotations map[string]string, checked bool) []string {
+	var findings []string
+
+	audiences := make(map[string]bool, len(tokens))
+	for _, token := range tokens {
+		audiences[token.Audience] = true
+
+		if len(token.MountPaths) == 0 {
+			findings = append(findings, fmt.Sprintf("token %s in volume %s is not mounted by any container", token.Path, token.Volume))
+		}
+		if token.ExpirationSeconds > 24*3600 {
+			findings = append(findings, fmt.Sprintf("token %s in volume %s expires after %s; the kubelet refreshes it at 80%% of its lifetime, so a leaked token stays valid for long", token.Path, token.Volume, time.Duration(token.ExpirationSeconds)*time.Second))
+		}
+	}
+
+	if !checked {
+		return findings
+	}
+
+	for _, identity := range workloadIdentities {
+		if identity.audience == "" {
+			continue
+		}
+
+		_, annotated := annotations[identity.annotation]
+		switch {
+		case annotated && !audiences[identity.audience]:
+			findings = append(findings, fmt.Sprintf("the ServiceAccount is annotated for %s (%s) but no token has audience %s; the pod may have been created before the annotation was added or the identity webhook did not run, so restart it", identity.name, identity.annotation, identity.audience))
+		case !annotated && audiences[identity.audience]:
+			findings = append(findings, fmt.Sprintf("a token has audience %s for %s but the ServiceAccount has no %s annotation, so no identity is bound to it", identity.audience, identity.name, identity.annotation))
+		}
+	}
+
+	return findings
+}
@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/fakecluster"
+)
+
+func tokenPod(name, serviceAccount, audience string) *corev1.Pod {
+	expiration := int64(86400)
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "shop"},
+		Spec: corev1.PodSpec{
+			ServiceAccountName: serviceAccount,
+			Containers: []corev1.Container{{
+				Name:         "app",
+				VolumeMounts: []corev1.VolumeMount{{Name: "kube-api-access-abcde", MountPath: "/var/run/secrets/kubernetes.io/serviceaccount"}},
+			}},
+			Volumes: []corev1.Volume{{
+				Name: "kube-api-access-abcde",
+				VolumeSource: corev1.VolumeSource{Projected: &corev1.ProjectedVolumeSource{Sources: []corev1.VolumeProjection{
+					{ServiceAccountToken: &corev1.ServiceAccountTokenProjection{Path: "token"}},
+				}}},
+			}},
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+
+	if audience != "" {
+		pod.Spec.Containers[0].VolumeMounts = append(pod.Spec.Containers[0].VolumeMounts, corev1.VolumeMount{Name: "aws-iam-token", MountPath: "/var/run/secrets/eks.amazonaws.com/serviceaccount"})
+		pod.Spec.Volumes = append(pod.Spec.Volumes, corev1.Volume{
+			Name: "aws-iam-token",
+			VolumeSource: corev1.VolumeSource{Projected: &corev1.ProjectedVolumeSource{Sources: []corev1.VolumeProjection{
+				{ServiceAccountToken: &corev1.ServiceAccountTokenProjection{Path: "token", Audience: audience, ExpirationSeconds: &expiration}},
+			}}},
+		})
+	}
+
+	return pod
+}
+
+func TestTokenFindings(t *testing.T) {
+	t.Parallel()
+
+	irsa := map[string]string{"eks.amazonaws.com/role-arn": "arn:aws:iam::123456789012:role/shop"}
+	stsToken := ProjectedToken{Volume: "aws-iam-token", Path: "token", Audience: "sts.amazonaws.com", ExpirationSeconds: 86400, MountPaths: []string{"app:/var/run/secrets/eks.amazonaws.com/serviceaccount/token"}}
+
+	tests := []struct {
+		name        string
+		tokens      []ProjectedToken
+		annotations map[string]string
+		checked     bool
+		want        int
+	}{
+		{name: "matching audience", tokens: []ProjectedToken{stsToken}, annotations: irsa, checked: true},
+		{name: "annotated without token", annotations: irsa, checked: true, want: 1},
+		{name: "token without annotation", tokens: []ProjectedToken{stsToken}, checked: true, want: 1},
+		{name: "annotations not read", tokens: []ProjectedToken{stsToken}},
+		{name: "unmounted long lived token", tokens: []ProjectedToken{{Volume: "extra", Path: "token", ExpirationSeconds: 172800}}, want: 2},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tokenFindings(tt.tokens, tt.annotations, tt.checked); len(got) != tt.want {
+				t.Errorf("expected %d findings, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestProjectedTokensReport_FakeCluster(t *testing.T) {
+	t.Parallel()
+
+	handler := NewPodHandler(fakecluster.New(fakecluster.Config{
+		Objects: []runtime.Object{
+			&corev1.ServiceAccount{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "shop",
+					Namespace:   "shop",
+					Annotations: map[string]string{"eks.amazonaws.com/role-arn": "arn:aws:iam::123456789012:role/shop"},
+				},
+			},
+			&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "shop"}},
+			tokenPod("good", "shop", "sts.amazonaws.com"),
+			tokenPod("stale", "shop", ""),
+			tokenPod("plain", "", ""),
+		},
+	}), false)
+
+	result, isErr := callTool(t, handler.ProjectedTokensReport, map[string]any{})
+	if isErr {
+		t.Fatalf("unexpected error: %v", result["error"])
+	}
+
+	var projections []PodTokenProjections
+	decodeInto(t, result["pods"], &projections)
+	if len(projections) != 2 {
+		t.Fatalf("expected the two pods of the annotated ServiceAccount, got %+v", projections)
+	}
+
+	good, stale := projections[0], projections[1]
+	wantTokens := []ProjectedToken{{
+		Volume:            "aws-iam-token",
+		Path:              "token",
+		Audience:          "sts.amazonaws.com",
+		ExpirationSeconds: 86400,
+		MountPaths:        []string{"app:/var/run/secrets/eks.amazonaws.com/serviceaccount/token"},
+	}}
+	if good.Pods[0] != "good" || !reflect.DeepEqual(good.Tokens, wantTokens) || len(good.Findings) != 0 {
+		t.Errorf("unexpected entry for the injected pod: %+v", good)
+	}
+	if stale.Pods[0] != "stale" || len(stale.Tokens) != 0 || len(stale.Findings) != 1 {
+		t.Errorf("expected a missing audience finding for the stale pod, got %+v", stale)
+	}
+
+	result, isErr = callTool(t, handler.ProjectedTokensReport, map[string]any{"include_default": true})
+	if isErr {
+		t.Fatalf("unexpected error: %v", result["error"])
+	}
+	if result["count"] != float64(3) {
+		t.Errorf("expected every pod with default tokens included, got %v", result["count"])
+	}
+}
@@ -0,0 +1,276 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/connectivity"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/kubernetes"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/response"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/toolschema"
+)
+
+// defaultQuotaThreshold is the usage percentage at which a quota resource is
+// considered close to exhaustion when no explicit threshold is provided.
+const defaultQuotaThreshold = 80
+
+// QuotaHandler provides MCP tools for inspecting namespace-level resource
+// constraints such as ResourceQuotas and LimitRanges. Exhausted quotas are a
+// frequent cause of pods failing to be created, and the raw objects are
+// awkward to read.
+type QuotaHandler struct {
+	client      kubernetes.ClusterReader
+	alwaysStart bool
+}
+
+// NewQuotaHandler creates a new QuotaHandler with the provided Kubernetes client.
+// alwaysStart mirrors the --always-start flag: when true, connectivity and auth errors
+// are intercepted and returned as structured tool errors so the LLM can surface them
+// to the user rather than treating them as retryable failures.
+func NewQuotaHandler(client kubernetes.ClusterReader, alwaysStart bool) *QuotaHandler {
+	return &QuotaHandler{
+		client:      client,
+		alwaysStart: alwaysStart,
+	}
+}
+
+// GetQuotaUsageParams defines the parameters for the get_quota_usage MCP tool.
+type GetQuotaUsageParams struct {
+	// Namespace specifies the namespace whose quotas should be reported.
+	// If empty, quotas across all namespaces are returned.
+	Namespace string `json:"namespace,omitempty" description:"Namespace to report quotas for (leave empty for all namespaces)"`
+
+	// Context specifies which Kubernetes context to use for this operation.
+	// If empty, uses the current context from kubeconfig.
+	Context string `json:"context,omitempty" description:"Kubernetes context to use (defaults to current context from kubeconfig)"`
+
+	// Threshold is the usage percentage (1-100) at which a resource is flagged
+	// as near exhaustion. Defaults to 80.
+	Threshold int `json:"threshold,omitempty" minimum:"1" maximum:"100" default:"80" description:"Usage percentage (1-100) at which a resource is flagged as near its limit (defaults to 80)"`
+
+	// OnlyNearLimit when true, returns only quotas with at least one resource
+	// at or above the threshold.
+	OnlyNearLimit bool `json:"only_near_limit,omitempty" description:"When true, returns only quotas with at least one resource at or above the threshold"`
+}
+
+// QuotaResourceUsage describes the hard limit and current usage of a single
+// resource tracked by a ResourceQuota.
+type QuotaResourceUsage struct {
+	// Resource is the quota resource name (e.g., "requests.cpu", "pods").
+	Resource string `json:"resource"`
+
+	// Hard is the configured limit for the resource.
+	Hard string `json:"hard"`
+
+	// Used is the amount currently consumed in the namespace.
+	Used string `json:"used"`
+
+	// PercentUsed is the used value as a percentage of the hard limit.
+	PercentUsed float64 `json:"percent_used"`
+
+	// NearLimit indicates the usage is at or above the requested threshold.
+	NearLimit bool `json:"near_limit"`
+
+	// Exhausted indicates the usage has reached the hard limit, so further
+	// requests for this resource will be rejected.
+	Exhausted bool `json:"exhausted"`
+}
+
+// QuotaUsage summarizes a single ResourceQuota object.
+type QuotaUsage struct {
+	// Name is the ResourceQuota name.
+	Name string `json:"name"`
+
+	// Namespace is the namespace the quota applies to.
+	Namespace string `json:"namespace"`
+
+	// Scopes lists the quota scopes, such as BestEffort or Terminating,
+	// that limit which objects the quota tracks.
+	Scopes []string `json:"scopes,omitempty"`
+
+	// Resources lists the per-resource usage, most constrained first.
+	Resources []QuotaResourceUsage `json:"resources"`
+
+	// NearLimit indicates at least one resource is at or above the threshold.
+	NearLimit bool `json:"near_limit"`
+}
+
+// GetQuotaUsageResult is the result of the get_quota_usage MCP tool.
+type GetQuotaUsageResult struct {
+	Namespace      string       `json:"namespace"`
+	Threshold      int          `json:"threshold"`
+	Count          int          `json:"count"`
+	NearLimitCount int          `json:"near_limit_count"`
+	Quotas         []QuotaUsage `json:"quotas"`
+}
+
+// GetQuotaUsage implements the get_quota_usage MCP tool.
+// It reports ResourceQuota hard limits against the values currently used in each
+// namespace, flagging resources whose usage is at or above the given threshold.
+func (h *QuotaHandler) GetQuotaUsage(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var params GetQuotaUsageParams
+	if err := bindParams(request, &params); err != nil {
+		return response.Errorf("failed to parse arguments: %s", err)
+	}
+
+	threshold := params.Threshold
+	if threshold == 0 {
+		threshold = defaultQuotaThreshold
+	}
+
+	// Use the appropriate client based on context
+	client, err := h.client.ForContext(ctx, params.Context)
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to create client with context %s: %v", params.Context, err)
+	}
+
+	quotas, err := client.ListResourceQuotas(ctx, params.Namespace)
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to list resource quotas: %v", err)
+	}
+
+	items := make([]QuotaUsage, 0, len(quotas.Items))
+	nearLimitCount := 0
+
+	for i := range quotas.Items {
+		quota := &quotas.Items[i]
+
+		usage := quotaUsage(quota, threshold)
+
+		if usage.NearLimit {
+			nearLimitCount++
+		} else if params.OnlyNearLimit {
+			continue
+		}
+
+		items = append(items, usage)
+	}
+
+	// Sort quotas near exhaustion first, then by namespace and name
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].NearLimit != items[j].NearLimit {
+			return items[i].NearLimit
+		}
+		if items[i].Namespace != items[j].Namespace {
+			return items[i].Namespace < items[j].Namespace
+		}
+		return items[i].Name < items[j].Name
+	})
+
+	return response.JSON(GetQuotaUsageResult{
+		Namespace:      params.Namespace,
+		Threshold:      threshold,
+		Count:          len(items),
+		NearLimitCount: nearLimitCount,
+		Quotas:         items,
+	})
+}
+
+// quotaUsage summarizes a ResourceQuota against the threshold.
+func quotaUsage(quota *corev1.ResourceQuota, threshold int) QuotaUsage {
+	usage := QuotaUsage{
+		Name:      quota.Name,
+		Namespace: quota.Namespace,
+		Resources: computeQuotaUsage(quota.Status.Hard, quota.Status.Used, threshold),
+	}
+
+	for _, scope := range quota.Spec.Scopes {
+		usage.Scopes = append(usage.Scopes, string(scope))
+	}
+	if quota.Spec.ScopeSelector != nil {
+		for _, expression := range quota.Spec.ScopeSelector.MatchExpressions {
+			usage.Scopes = append(usage.Scopes, fmt.Sprintf("%s %s %s", expression.ScopeName, expression.Operator, strings.Join(expression.Values, ",")))
+		}
+	}
+
+	for _, res := range usage.Resources {
+		if res.NearLimit {
+			usage.NearLimit = true
+			break
+		}
+	}
+
+	return usage
+}
+
+// computeQuotaUsage pairs each hard limit with its used value and computes the
+// usage percentage. Resources near their limit are sorted first, then by highest
+// usage, so the most constrained ones appear at the top. A missing used value is
+// treated as zero.
+func computeQuotaUsage(hard, used corev1.ResourceList, threshold int) []QuotaResourceUsage {
+	resources := make([]QuotaResourceUsage, 0, len(hard))
+
+	for name, hardQty := range hard {
+		usedQty := used[name]
+
+		entry := QuotaResourceUsage{
+			Resource: string(name),
+			Hard:     hardQty.String(),
+			Used:     usedQty.String(),
+		}
+
+		hardValue := hardQty.AsApproximateFloat64()
+		usedValue := usedQty.AsApproximateFloat64()
+
+		switch {
+		case hardValue > 0:
+			entry.PercentUsed = math.Round(usedValue/hardValue*10000) / 100
+		case usedValue > 0:
+			// A zero hard limit with any usage is fully exhausted.
+			entry.PercentUsed = 100
+		}
+
+		// A zero hard limit forbids the resource entirely, so it is
+		// always exhausted even when nothing has been used yet.
+		entry.Exhausted = usedQty.Cmp(hardQty) >= 0
+		entry.NearLimit = entry.Exhausted || entry.PercentUsed >= float64(threshold)
+
+		resources = append(resources, entry)
+	}
+
+	sort.Slice(resources, func(i, j int) bool {
+		if resources[i].NearLimit != resources[j].NearLimit {
+			return resources[i].NearLimit
+		}
+		if resources[i].PercentUsed != resources[j].PercentUsed {
+			return resources[i].PercentUsed > resources[j].PercentUsed
+		}
+		return resources[i].Resource < resources[j].Resource
+	})
+
+	return resources
+}
+
+// GetTools returns all quota-related MCP tools provided by this handler.
+func (h *QuotaHandler) GetTools() []MCPTool {
+	return []MCPTool{
+		NewMCPTool(
+			mcp.NewTool("get_quota_usage",
+				mcp.WithDescription("Report ResourceQuota hard limits against current usage per namespace, highlighting quotas near exhaustion. Exhausted quotas are a common cause of pods failing to be created."),
+				toolschema.Input[GetQuotaUsageParams](),
+				toolschema.Output[GetQuotaUsageResult](),
+			),
+			h.GetQuotaUsage,
+		),
+		NewMCPTool(
+			mcp.NewTool("get_namespace_limits",
+				mcp.WithDescription("Summarize the ResourceQuotas and LimitRanges of a namespace in one place: quota usage against hard limits, the min, max, and default values of every LimitRange, and the default requests and limits that will be applied to containers of new pods that do not set their own. Flags exhausted quotas, quotas that require requests or limits no LimitRange defaults (so pods that omit them are rejected), and resources defaulted by several LimitRanges."),
+				toolschema.Input[GetNamespaceLimitsParams](),
+				toolschema.Output[GetNamespaceLimitsResult](),
+			),
+			h.GetNamespaceLimits,
+		),
+	}
+}
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/fakecluster"
+)
+
+func TestComputeQuotaUsage(t *testing.T) {
+	t.Parallel()
+
+	hard := corev1.ResourceList{
+		corev1.ResourcePods:           resource.MustParse("10"),
+		corev1.ResourceRequestsCPU:    resource.MustParse("4"),
+		corev1.ResourceRequestsMemory: resource.MustParse("8Gi"),
+		corev1.ResourceServices:       resource.MustParse("0"),
+	}
+	used := corev1.ResourceList{
+		corev1.ResourcePods:           resource.MustParse("10"),
+		corev1.ResourceRequestsCPU:    resource.MustParse("2500m"),
+		corev1.ResourceRequestsMemory: resource.MustParse("7Gi"),
+	}
+
+	got := computeQuotaUsage(hard, used, 80)
+
+	want := []QuotaResourceUsage{
+		{Resource: "pods", Hard: "10", Used: "10", PercentUsed: 100, NearLimit: true, Exhausted: true},
+		{Resource: "requests.memory", Hard: "8Gi", Used: "7Gi", PercentUsed: 87.5, NearLimit: true},
+		{Resource: "services", Hard: "0", Used: "0", PercentUsed: 0, NearLimit: true, Exhausted: true},
+		{Resource: "requests.cpu", Hard: "4", Used: "2500m", PercentUsed: 62.5},
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("expected %d resources, got %d: %#v", len(want), len(got), got)
+	}
+
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("resource %d mismatch\nwant: %#v\ngot:  %#v", i, want[i], got[i])
+		}
+	}
+}
+
+func TestGetQuotaUsage_FakeCluster(t *testing.T) {
+	t.Parallel()
+
+	quota := func(namespace, name, used string) *corev1.ResourceQuota {
+		return &corev1.ResourceQuota{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Status: corev1.ResourceQuotaStatus{
+				Hard: corev1.ResourceList{corev1.ResourcePods: resource.MustParse("10")},
+				Used: corev1.ResourceList{corev1.ResourcePods: resource.MustParse(used)},
+			},
+		}
+	}
+
+	handler := NewQuotaHandler(fakecluster.New(fakecluster.Config{
+		Objects: []runtime.Object{
+			quota("alpha", "pods", "2"),
+			quota("beta", "pods", "9"),
+		},
+	}), false)
+
+	result, isErr := callTool(t, handler.GetQuotaUsage, map[string]any{"only_near_limit": true})
+	if isErr {
+		t.Fatalf("unexpected error: %v", result["error"])
+	}
+
+	if result["near_limit_count"] != float64(1) {
+		t.Errorf("expected 1 quota near its limit, got %v", result["near_limit_count"])
+	}
+
+	quotas, _ := result["quotas"].([]any)
+	if len(quotas) != 1 {
+		t.Fatalf("expected 1 quota, got %v", result["quotas"])
+	}
+	if ns := quotas[0].(map[string]any)["namespace"]; ns != "beta" {
+		t.Errorf("expected quota in namespace beta, got %v", ns)
+	}
+
+	if result, isErr := callTool(t, handler.GetQuotaUsage, map[string]any{"threshold": float64(0)}); !isErr {
+		t.Errorf("expected an out-of-range threshold to be rejected, got %v", result)
+	}
+}
@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	"sort"
+	"strings"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+)
+
+const (
+	// clusterAdminRole is the built-in ClusterRole that grants every verb
+	// on every resource.
+	clusterAdminRole = "cluster-admin"
+
+	// serviceAccountUserPrefix and serviceAccountGroupPrefix are how the
+	// API server names ServiceAccounts and their groups when it
+	// authenticates their tokens.
+	serviceAccountUserPrefix  = "system:serviceaccount:"
+	serviceAccountGroupPrefix = "system:serviceaccounts"
+
+	// authenticatedGroup is the group every authenticated identity is in.
+	authenticatedGroup = "system:authenticated"
+)
+
+// SubjectBinding is a RoleBinding or ClusterRoleBinding that applies to a
+// subject.
+type SubjectBinding struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+
+	// Role is the bound role, such as ClusterRole/view.
+	Role string `json:"role"`
+
+	// Via is the group or user subject that matched, when the binding does
+	// not name the subject itself.
+	Via string `json:"via,omitempty"`
+}
+
+// rbacIdentity is who a request is authenticated as: a user name and its
+// groups. For a ServiceAccount, namespace and serviceAccount are set too,
+// and bindings may name it either as a ServiceAccount or as its user name.
+type rbacIdentity struct {
+	kind           string
+	user           string
+	groups         []string
+	namespace      string
+	serviceAccount string
+}
+
+// serviceAccountIdentity returns the identity of a ServiceAccount token,
+// with the groups the API server adds to it.
+func serviceAccountIdentity(namespace, name string) rbacIdentity {
+	return rbacIdentity{
+		kind:           rbacv1.ServiceAccountKind,
+		user:           serviceAccountUserPrefix + namespace + ":" + name,
+		groups:         []string{serviceAccountGroupPrefix, serviceAccountGroupPrefix + ":" + namespace, authenticatedGroup},
+		namespace:      namespace,
+		serviceAccount: name,
+	}
+}
+
+// userIdentity returns the identity of an authenticated user in the given
+// groups. A ServiceAccount user name returns the ServiceAccount identity.
+func userIdentity(name string, groups []string) rbacIdentity {
+	if rest, ok := strings.CutPrefix(name, serviceAccountUserPrefix); ok {
+		if namespace, account, ok := strings.Cut(rest, ":"); ok && namespace != "" && account != "" {
+			return serviceAccountIdentity(namespace, account)
+		}
+	}
+
+	identity := rbacIdentity{kind: rbacv1.UserKind, user: name}
+	for _, group := range append(groups, authenticatedGroup) {
+		if group != "" && !containsString(identity.groups, group) {
+			identity.groups = append(identity.groups, group)
+		}
+	}
+	return identity
+}
+
+// groupIdentity returns the identity of a group on its own, without the
+// groups any of its members may also be in.
+func groupIdentity(name string) rbacIdentity {
+	return rbacIdentity{kind: rbacv1.GroupKind, groups: []string{name}}
+}
+
+// matches reports whether any of the subjects of a binding applies to the
+// identity. via is empty when a subject names the identity itself, and
+// otherwise names the user or group subject that matched.
+func (id rbacIdentity) matches(subjects []rbacv1.Subject, bindingNamespace string) (bool, string) {
+	for _, subject := range subjects {
+		switch subject.Kind {
+		case rbacv1.ServiceAccountKind:
+			// A ServiceAccount subject of a RoleBinding defaults to the
+			// binding's namespace.
+			namespace := subject.Namespace
+			if namespace == "" {
+				namespace = bindingNamespace
+			}
+			if id.serviceAccount != "" && subject.Name == id.serviceAccount && namespace == id.namespace {
+				return true, ""
+			}
+		case rbacv1.UserKind:
+			if id.user != "" && subject.Name == id.user && id.kind == rbacv1.UserKind {
+				return true, ""
+			}
+		case rbacv1.GroupKind:
+			if id.kind == rbacv1.GroupKind && containsString(id.groups, subject.Name) {
+				return true, ""
+			}
+		}
+	}
+
+	for _, subject := range subjects {
+		if subject.Kind == rbacv1.UserKind && id.user != "" && subject.Name == id.user {
+			return true, "User/" + subject.Name
+		}
+	}
+	for _, subject := range subjects {
+		if subject.Kind == rbacv1.GroupKind && containsString(id.groups, subject.Name) {
+			return true, "Group/" + subject.Name
+		}
+	}
+
+	return false, ""
+}
+
+// subjectBindings lists the RoleBindings and ClusterRoleBindings that apply
+// to an identity, RoleBindings first.
+func subjectBindings(identity rbacIdentity, roleBindings []rbacv1.RoleBinding, clusterRoleBindings []rbacv1.ClusterRoleBinding) []SubjectBinding {
+	var result []SubjectBinding
+
+	for i := range roleBindings {
+		binding := &roleBindings[i]
+		if ok, via := identity.matches(binding.Subjects, binding.Namespace); ok {
+			result = append(result, SubjectBinding{
+				Kind:      "RoleBinding",
+				Namespace: binding.Namespace,
+				Name:      binding.Name,
+				Role:      binding.RoleRef.Kind + "/" + binding.RoleRef.Name,
+				Via:       via,
+			})
+		}
+	}
+
+	for i := range clusterRoleBindings {
+		binding := &clusterRoleBindings[i]
+		if ok, via := identity.matches(binding.Subjects, ""); ok {
+			result = append(result, SubjectBinding{
+				Kind: "ClusterRoleBinding",
+				Name: binding.Name,
+				Role: binding.RoleRef.Kind + "/" + binding.RoleRef.Name,
+				Via:  via,
+			})
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Kind != result[j].Kind {
+			return result[i].Kind > result[j].Kind
+		}
+		if result[i].Namespace != result[j].Namespace {
+			return result[i].Namespace < result[j].Namespace
+		}
+		return result[i].Name < result[j].Name
+	})
+
+	return result
+}
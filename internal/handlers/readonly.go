@@ -0,0 +1,347 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	authorizationv1 "k8s.io/api/authorization/v1"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/connectivity"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/kubernetes"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/response"
+)
+
+const (
+	// readOnlyVerdictPass means every write check was denied.
+	readOnlyVerdictPass = "read_only"
+
+	// readOnlyVerdictFail means at least one write check was allowed.
+	readOnlyVerdictFail = "not_read_only"
+
+	// readOnlyVerdictInconclusive means no write check was allowed, but some
+	// could not be evaluated.
+	readOnlyVerdictInconclusive = "inconclusive"
+
+	// accessReviewConcurrency bounds the SelfSubjectAccessReviews in flight.
+	accessReviewConcurrency = 8
+)
+
+// writeVerbs are the verbs that change the state of a resource.
+var writeVerbs = []string{"create", "update", "patch", "delete", "deletecollection"}
+
+// readOnlyCheck is a resource whose write verbs verify_readonly expects to be
+// denied.
+type readOnlyCheck struct {
+	Group         string
+	Resource      string
+	Subresource   string
+	ClusterScoped bool
+	Verbs         []string
+}
+
+// readOnlyChecks covers the resources whose modification matters most: the
+// core workload and configuration types, subresources that run code in or
+// evict pods, RBAC and the privilege escalation verbs, impersonation, and the
+// cluster-wide extension points. The last check asks for every verb on every
+// resource, which only cluster-admin style bindings allow.
+var readOnlyChecks = []readOnlyCheck{
+	{Resource: "pods", Verbs: writeVerbs},
+	{Resource: "pods", Subresource: "exec", Verbs: []string{"create"}},
+	{Resource: "pods", Subresource: "attach", Verbs: []string{"create"}},
+	{Resource: "pods", Subresource: "portforward", Verbs: []string{"create"}},
+	{Resource: "pods", Subresource: "eviction", Verbs: []string{"create"}},
+	{Resource: "pods", Subresource: "ephemeralcontainers", Verbs: []string{"update", "patch"}},
+	{Resource: "secrets", Verbs: writeVerbs},
+	{Resource: "configmaps", Verbs: writeVerbs},
+	{Resource: "services", Verbs: writeVerbs},
+	{Resource: "serviceaccounts", Verbs: writeVerbs},
+	{Resource: "serviceaccounts", Subresource: "token", Verbs: []string{"create"}},
+	{Resource: "persistentvolumeclaims", Verbs: writeVerbs},
+	{Resource: "namespaces", ClusterScoped: true, Verbs: writeVerbs},
+	{Resource: "nodes", ClusterScoped: true, Verbs: writeVerbs},
+	{Resource: "persistentvolumes", ClusterScoped: true, Verbs: writeVerbs},
+	{Group: "apps", Resource: "deployments", Verbs: writeVerbs},
+	{Group: "apps", Resource: "deployments", Subresource: "scale", Verbs: []string{"update", "patch"}},
+	{Group: "apps", Resource: "statefulsets", Verbs: writeVerbs},
+	{Group: "apps", Resource: "daemonsets", Verbs: writeVerbs},
+	{Group: "apps", Resource: "replicasets", Verbs: writeVerbs},
+	{Group: "batch", Resource: "jobs", Verbs: writeVerbs},
+	{Group: "batch", Resource: "cronjobs", Verbs: writeVerbs},
+	{Group: "networking.k8s.io", Resource: "ingresses", Verbs: writeVerbs},
+	{Group: "networking.k8s.io", Resource: "networkpolicies", Verbs: writeVerbs},
+	{Group: "rbac.authorization.k8s.io", Resource: "roles", Verbs: append([]string{"escalate", "bind"}, writeVerbs...)},
+	{Group: "rbac.authorization.k8s.io", Resource: "rolebindings", Verbs: writeVerbs},
+	{Group: "rbac.authorization.k8s.io", Resource: "clusterroles", ClusterScoped: true, Verbs: append([]string{"escalate", "bind"}, writeVerbs...)},
+	{Group: "rbac.authorization.k8s.io", Resource: "clusterrolebindings", ClusterScoped: true, Verbs: writeVerbs},
+	{Resource: "users", ClusterScoped: true, Verbs: []string{"impersonate"}},
+	{Resource: "groups", ClusterScoped: true, Verbs: []string{"impersonate"}},
+	{Resource: "serviceaccounts", Verbs: []string{"impersonate"}},
+	{Group: "apiextensions.k8s.io", Resource: "customresourcedefinitions", ClusterScoped: true, Verbs: writeVerbs},
+	{Group: "admissionregistration.k8s.io", Resource: "mutatingwebhookconfigurations", ClusterScoped: true, Verbs: writeVerbs},
+	{Group: "admissionregistration.k8s.io", Resource: "validatingwebhookconfigurations", ClusterScoped: true, Verbs: writeVerbs},
+	{Group: "*", Resource: "*", ClusterScoped: true, Verbs: []string{"*"}},
+}
+
+// VerifyReadOnlyParams defines the parameters for the verify_readonly MCP tool.
+type VerifyReadOnlyParams struct {
+	// Namespace is where namespaced permissions are checked.
+	Namespace string `json:"namespace,omitempty" description:"Namespace to check namespaced permissions in (defaults to the kubeconfig context's namespace, or 'default'). Cluster-scoped permissions are always checked"`
+
+	// Context specifies which Kubernetes context to verify.
+	Context string `json:"context,omitempty" description:"Kubernetes context to verify (defaults to current context from kubeconfig)"`
+}
+
+// ReadOnlyCheckResult reports which write verbs on a resource the
+// credentials are allowed and denied.
+type ReadOnlyCheckResult struct {
+	APIGroup    string `json:"api_group"`
+	Resource    string `json:"resource"`
+	Subresource string `json:"subresource,omitempty"`
+
+	// Scope is the namespace the check ran in, or "cluster".
+	Scope        string   `json:"scope"`
+	AllowedVerbs []string `json:"allowed_verbs,omitempty"`
+	DeniedVerbs  []string `json:"denied_verbs"`
+
+	// Reasons are the authorizer's explanations for allowed verbs, such as
+	// the binding that grants them.
+	Reasons []string `json:"reasons,omitempty"`
+
+	// Errors are the verbs whose review failed or returned an evaluation
+	// error, with the error.
+	Errors []string `json:"errors,omitempty"`
+}
+
+// VerifyReadOnlyResult is the result of the verify_readonly MCP tool.
+type VerifyReadOnlyResult struct {
+	// Verdict is read_only when every check was denied.
+	Verdict     string                `json:"verdict"`
+	ReadOnly    bool                  `json:"read_only"`
+	Summary     string                `json:"summary"`
+	CheckedAt   string                `json:"checked_at"`
+	Cluster     ConnectedCluster      `json:"cluster"`
+	Namespace   string                `json:"namespace"`
+	ReviewCount int                   `json:"review_count"`
+	Checks      []ReadOnlyCheckResult `json:"checks"`
+
+	// Violations lists the checks with allowed verbs.
+	Violations []ReadOnlyCheckResult `json:"violations,omitempty"`
+
+	Notes    []string `json:"notes"`
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// VerifyReadOnly implements the verify_readonly MCP tool.
+// It runs a SelfSubjectAccessReview for each write verb on a suite of
+// resources and reports whether the credentials of the context are denied
+// all of them. SelfSubjectAccessReviews are answered by the authorizer and
+// not stored, so running them does not change the cluster. The report
+// names the context, cluster, and user it checked and when, so it can be
+// kept as evidence when approving a deployment of this server.
+func (h *CapabilitiesHandler) VerifyReadOnly(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var params VerifyReadOnlyParams
+	if err := bindParams(request, &params); err != nil {
+		return response.Errorf("failed to parse arguments: %s", err)
+	}
+
+	// Use the appropriate client based on context
+	client, err := h.client.ForContext(ctx, params.Context)
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to create client with context %s: %v", params.Context, err)
+	}
+
+	var warnings []string
+
+	cluster, err := kubeconfigCluster(client, params.Context)
+	if err != nil {
+		warnings = append(warnings, fmt.Sprintf("failed to read kubeconfig contexts: %v", err))
+	}
+
+	if info, err := client.ServerVersion(); err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		warnings = append(warnings, fmt.Sprintf("failed to get the Kubernetes server version: %v", err))
+	} else {
+		cluster.ServerVersion = info.GitVersion
+	}
+
+	namespace := params.Namespace
+	if namespace == "" {
+		namespace = cluster.Namespace
+	}
+	if namespace == "" {
+		namespace = "default"
+	}
+
+	checkedAt := time.Now()
+	results := reviewReadOnlyChecks(ctx, client, namespace)
+
+	verdict := readOnlyVerdict(results)
+	if verdict != readOnlyVerdictPass && h.alwaysStart {
+		// A transport failure means the reviews never reached the API server.
+		for i := range results {
+			for _, reviewErr := range results[i].errs {
+				if connectivity.IsTransportError(reviewErr) {
+					return response.Error(connectivity.ErrorMessage(reviewErr))
+				}
+			}
+		}
+	}
+
+	checks := make([]ReadOnlyCheckResult, 0, len(results))
+	var violations []ReadOnlyCheckResult
+	reviews, allowed, failed := 0, 0, 0
+
+	for i := range results {
+		check := results[i].ReadOnlyCheckResult
+		reviews += len(check.AllowedVerbs) + len(check.DeniedVerbs) + len(check.Errors)
+		allowed += len(check.AllowedVerbs)
+		failed += len(check.Errors)
+
+		if len(check.AllowedVerbs) > 0 {
+			violations = append(violations, check)
+		}
+		checks = append(checks, check)
+	}
+
+	var summary string
+	switch verdict {
+	case readOnlyVerdictPass:
+		summary = fmt.Sprintf("all %d write and escalation checks were denied; the credentials are read-only for the checked resources", reviews)
+	case readOnlyVerdictFail:
+		summary = fmt.Sprintf("%d of %d write and escalation checks were allowed; the credentials can modify the cluster", allowed, reviews)
+	default:
+		summary = fmt.Sprintf("no checks were allowed, but %d of %d could not be evaluated", failed, reviews)
+	}
+
+	return response.JSON(VerifyReadOnlyResult{
+		Verdict:     verdict,
+		ReadOnly:    verdict == readOnlyVerdictPass,
+		Summary:     summary,
+		CheckedAt:   formatTime(checkedAt),
+		Cluster:     cluster,
+		Namespace:   namespace,
+		ReviewCount: reviews,
+		Checks:      checks,
+		Violations:  violations,
+		Notes: []string{
+			"checks use SelfSubjectAccessReviews, which are evaluated by the API server's authorizers and not stored",
+			fmt.Sprintf("namespaced permissions were checked in %s only; run the tool per namespace to cover grants limited to other namespaces", namespace),
+			"admission webhooks and policy engines can still reject allowed requests, and are not evaluated",
+		},
+		Warnings: warnings,
+	})
+}
+
+// reviewedCheck is a check result along with the review errors behind it.
+type reviewedCheck struct {
+	ReadOnlyCheckResult
+	errs []error
+}
+
+// reviewReadOnlyChecks runs the SelfSubjectAccessReviews of every check, a
+// few at a time. The results are in the order of readOnlyChecks, with verbs
+// in the order of each check.
+func reviewReadOnlyChecks(ctx context.Context, client kubernetes.ClusterReader, namespace string) []reviewedCheck {
+	statuses := make([][]*authorizationv1.SubjectAccessReviewStatus, len(readOnlyChecks))
+	errs := make([][]error, len(readOnlyChecks))
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, accessReviewConcurrency)
+
+	for i, check := range readOnlyChecks {
+		statuses[i] = make([]*authorizationv1.SubjectAccessReviewStatus, len(check.Verbs))
+		errs[i] = make([]error, len(check.Verbs))
+
+		for j, verb := range check.Verbs {
+			attributes := &authorizationv1.ResourceAttributes{
+				Group:       check.Group,
+				Resource:    check.Resource,
+				Subresource: check.Subresource,
+				Verb:        verb,
+			}
+			if !check.ClusterScoped {
+				attributes.Namespace = namespace
+			}
+
+			if !acquireSlot(ctx, sem) {
+				errs[i][j] = ctx.Err()
+				continue
+			}
+			wg.Add(1)
+
+			go func(i, j int) {
+				defer wg.Done()
+				defer func() { <-sem }()
+
+				statuses[i][j], errs[i][j] = client.ReviewAccess(ctx, attributes)
+			}(i, j)
+		}
+	}
+
+	wg.Wait()
+
+	results := make([]reviewedCheck, len(readOnlyChecks))
+	for i, check := range readOnlyChecks {
+		scope := namespace
+		if check.ClusterScoped {
+			scope = "cluster"
+		}
+
+		results[i].ReadOnlyCheckResult = ReadOnlyCheckResult{
+			APIGroup:    check.Group,
+			Resource:    check.Resource,
+			Subresource: check.Subresource,
+			Scope:       scope,
+			DeniedVerbs: make([]string, 0),
+		}
+		results[i].record(check.Verbs, statuses[i], errs[i])
+	}
+
+	return results
+}
+
+// record sorts the review outcome of each verb into allowed, denied, or
+// failed.
+func (r *reviewedCheck) record(verbs []string, statuses []*authorizationv1.SubjectAccessReviewStatus, errs []error) {
+	for i, verb := range verbs {
+		switch {
+		case errs[i] != nil:
+			r.Errors = append(r.Errors, fmt.Sprintf("%s: %v", verb, errs[i]))
+			r.errs = append(r.errs, errs[i])
+		case statuses[i].Allowed:
+			r.AllowedVerbs = append(r.AllowedVerbs, verb)
+			if statuses[i].Reason != "" && !containsString(r.Reasons, statuses[i].Reason) {
+				r.Reasons = append(r.Reasons, statuses[i].Reason)
+			}
+		case statuses[i].EvaluationError != "" && !statuses[i].Denied:
+			// Without an explicit denial, an authorizer that failed might
+			// have allowed the request.
+			r.Errors = append(r.Errors, fmt.Sprintf("%s: %s", verb, statuses[i].EvaluationError))
+		default:
+			r.DeniedVerbs = append(r.DeniedVerbs, verb)
+		}
+	}
+}
+
+// readOnlyVerdict summarizes the checks: any allowed verb fails the
+// verification, and otherwise any failed review makes it inconclusive.
+func readOnlyVerdict(results []reviewedCheck) string {
+	verdict := readOnlyVerdictPass
+	for i := range results {
+		if len(results[i].AllowedVerbs) > 0 {
+			return readOnlyVerdictFail
+		}
+		if len(results[i].Errors) > 0 {
+			verdict = readOnlyVerdictInconclusive
+		}
+	}
+	return verdict
+}
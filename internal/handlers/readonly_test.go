@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/fakecluster"
+)
+
+func TestReviewedCheckRecord(t *testing.T) {
+	t.Parallel()
+
+	var check reviewedCheck
+	check.record(
+		[]string{"create", "update", "patch", "delete"},
+		[]*authorizationv1.SubjectAccessReviewStatus{
+			{Allowed: true, Reason: `RBAC: allowed by ClusterRoleBinding "ops" of ClusterRole "edit"`},
+			nil,
+			{EvaluationError: "webhook authorizer timed out"},
+			{Denied: true, EvaluationError: "webhook authorizer timed out"},
+		},
+		[]error{nil, errors.New("connection refused"), nil, nil},
+	)
+
+	if want := []string{"create"}; !reflect.DeepEqual(check.AllowedVerbs, want) {
+		t.Errorf("expected allowed verbs %v, got %v", want, check.AllowedVerbs)
+	}
+	if want := []string{"delete"}; !reflect.DeepEqual(check.DeniedVerbs, want) {
+		t.Errorf("expected denied verbs %v, got %v", want, check.DeniedVerbs)
+	}
+	if want := []string{"update: connection refused", "patch: webhook authorizer timed out"}; !reflect.DeepEqual(check.Errors, want) {
+		t.Errorf("expected errors %v, got %v", want, check.Errors)
+	}
+	if len(check.Reasons) != 1 {
+		t.Errorf("expected the reason for the allowed verb, got %v", check.Reasons)
+	}
+
+	tests := []struct {
+		name    string
+		results []reviewedCheck
+		want    string
+	}{
+		{name: "all denied", results: []reviewedCheck{{}, {}}, want: readOnlyVerdictPass},
+		{name: "failed review", results: []reviewedCheck{{ReadOnlyCheckResult: ReadOnlyCheckResult{Errors: []string{"x"}}}}, want: readOnlyVerdictInconclusive},
+		{name: "allowed verb", results: []reviewedCheck{{ReadOnlyCheckResult: ReadOnlyCheckResult{Errors: []string{"x"}}}, check}, want: readOnlyVerdictFail},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := readOnlyVerdict(tt.results); got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestVerifyReadOnly_FakeCluster(t *testing.T) {
+	t.Parallel()
+
+	// The fake API server denies every access review not listed in
+	// AllowedAccess.
+	handler := NewCapabilitiesHandler(fakecluster.New(fakecluster.Config{ServerVersion: "v1.30.2"}), false, ServerSettings{}, nil)
+
+	result, isErr := callTool(t, handler.VerifyReadOnly, map[string]any{"namespace": "shop"})
+	if isErr {
+		t.Fatalf("unexpected error: %v", result["error"])
+	}
+
+	if result["verdict"] != readOnlyVerdictPass || result["read_only"] != true {
+		t.Errorf("expected a read_only verdict, got %v", result["summary"])
+	}
+	if _, ok := result["violations"]; ok {
+		t.Errorf("expected no violations, got %v", result["violations"])
+	}
+
+	var checks []ReadOnlyCheckResult
+	decodeInto(t, result["checks"], &checks)
+	if len(checks) != len(readOnlyChecks) {
+		t.Fatalf("expected %d checks, got %d", len(readOnlyChecks), len(checks))
+	}
+	if checks[0].Resource != "pods" || checks[0].Scope != "shop" || !reflect.DeepEqual(checks[0].DeniedVerbs, writeVerbs) {
+		t.Errorf("unexpected pods check %+v", checks[0])
+	}
+	if last := checks[len(checks)-1]; last.Scope != "cluster" || last.Resource != "*" {
+		t.Errorf("expected the wildcard check last, got %+v", last)
+	}
+
+	handler = NewCapabilitiesHandler(fakecluster.New(fakecluster.Config{
+		AllowedAccess: []authorizationv1.ResourceAttributes{{Namespace: "shop", Resource: "pods", Subresource: "exec", Verb: "create"}},
+	}), false, ServerSettings{}, nil)
+
+	result, isErr = callTool(t, handler.VerifyReadOnly, map[string]any{"namespace": "shop"})
+	if isErr {
+		t.Fatalf("unexpected error: %v", result["error"])
+	}
+
+	if result["verdict"] != readOnlyVerdictFail {
+		t.Errorf("expected a not_read_only verdict, got %v", result["summary"])
+	}
+
+	var violations []ReadOnlyCheckResult
+	decodeInto(t, result["violations"], &violations)
+	if len(violations) != 1 || violations[0].Subresource != "exec" || !reflect.DeepEqual(violations[0].AllowedVerbs, []string{"create"}) {
+		t.Errorf("expected pods/exec to be reported, got %+v", violations)
+	}
+}
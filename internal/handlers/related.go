@@ -0,0 +1,805 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/connectivity"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/kubernetes"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/resourcefilter"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/response"
+)
+
+// maxRelatedPods bounds how many pods get_related_resources lists for a
+// Service.
+const maxRelatedPods = 50
+
+// Resource types get_related_resources reads, checked against the resource
+// filter before each lookup.
+var (
+	relatedServicesGVR        = schema.GroupVersionResource{Version: "v1", Resource: "services"}
+	relatedPodsGVR            = schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+	relatedConfigMapsGVR      = schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+	relatedSecretsGVR         = schema.GroupVersionResource{Version: "v1", Resource: "secrets"}
+	relatedServiceAccountsGVR = schema.GroupVersionResource{Version: "v1", Resource: "serviceaccounts"}
+	relatedPVCsGVR            = schema.GroupVersionResource{Version: "v1", Resource: "persistentvolumeclaims"}
+	relatedEndpointSlicesGVR  = schema.GroupVersionResource{Group: "discovery.k8s.io", Version: "v1", Resource: "endpointslices"}
+	relatedIngressesGVR       = schema.GroupVersionResource{Group: "networking.k8s.io", Version: "v1", Resource: "ingresses"}
+	relatedHPAsGVR            = schema.GroupVersionResource{Group: "autoscaling", Version: "v2", Resource: "horizontalpodautoscalers"}
+)
+
+// GetRelatedResourcesParams defines the parameters for the get_related_resources MCP tool.
+type GetRelatedResourcesParams struct {
+	// ResourceType specifies the type of the target: services, pods, or a
+	// workload that runs pods.
+	ResourceType string `json:"resource_type" required:"true" description:"Resource type of the target: services, pods, or a workload such as deployments, statefulsets, daemonsets, replicasets, jobs, or cronjobs"`
+
+	// Namespace specifies the namespace of the target.
+	Namespace string `json:"namespace,omitempty" description:"Namespace of the target (defaults to the namespace of the current context)"`
+
+	// Name specifies the target.
+	Name string `json:"name" required:"true" description:"Name of the target"`
+
+	// Context specifies which Kubernetes context to use for this operation.
+	Context string `json:"context,omitempty" description:"Kubernetes context to use (defaults to current context from kubeconfig)"`
+}
+
+// RelatedResource is an object connected to the target of get_related_resources.
+type RelatedResource struct {
+	// Kind is set in sections that mix kinds, such as workloads.
+	Kind string `json:"kind,omitempty"`
+	Name string `json:"name"`
+
+	// Via explains how the object is connected to the target.
+	Via []string `json:"via,omitempty"`
+
+	// Status is a short summary of the object's state, when it has one.
+	Status string `json:"status,omitempty"`
+
+	// Missing is true when the target references the object but it does
+	// not exist.
+	Missing bool `json:"missing,omitempty"`
+}
+
+// GetRelatedResourcesResult is the result of the get_related_resources MCP
+// tool.
+type GetRelatedResourcesResult struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+
+	// Related groups the connected objects by section, such as services or
+	// secrets.
+	Related map[string][]RelatedResource `json:"related"`
+
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// GetRelatedResources implements the get_related_resources MCP tool.
+// For a Pod or a workload, it finds the Services selecting its pods, their
+// EndpointSlices and the Ingresses routing to them, the ConfigMaps, Secrets,
+// ServiceAccount, and PersistentVolumeClaims its pods use, its controllers,
+// and the HorizontalPodAutoscalers scaling it. For a Service, it finds the
+// pods it selects and their workloads, its EndpointSlices, and the Ingresses
+// routing to it. Secrets are reported by name only, from the pod spec; their
+// contents are never read. Sections whose resource type is disabled by
+// configuration are left out with a warning.
+func (h *ResourceHandler) GetRelatedResources(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var params GetRelatedResourcesParams
+	if err := bindParams(request, &params); err != nil {
+		return response.Errorf("failed to parse arguments: %s", err)
+	}
+
+	// Use the appropriate client based on context
+	client, err := h.client.ForContext(ctx, params.Context)
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to create client with context %s: %v", params.Context, err)
+	}
+
+	gvr, err := client.ResolveResourceType(params.ResourceType, "")
+	if err != nil {
+		if h.alwaysStart && connectivity.IsError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to resolve resource type: %v", err)
+	}
+
+	if h.resourceFilter != nil && h.resourceFilter.IsDisabled(gvr) {
+		if initErr := h.resourceFilter.InitError(); initErr != nil {
+			if h.alwaysStart && connectivity.IsError(initErr) {
+				return response.Error(connectivity.ErrorMessage(initErr))
+			}
+			return response.Errorf("resource filter could not be initialized: %v", initErr)
+		}
+		return response.Errorf("access to resource %q (%s) is disabled by configuration and cannot be queried",
+			params.ResourceType, resourcefilter.FormatGVR(gvr))
+	}
+
+	target, err := client.GetResource(ctx, gvr, params.Namespace, params.Name)
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to get resource: %v", err)
+	}
+
+	lookup := &relatedLookup{
+		handler:   h,
+		client:    client,
+		namespace: target.GetNamespace(),
+		related:   make(map[string][]RelatedResource),
+	}
+
+	if target.GetKind() == "Service" && target.GetAPIVersion() == "v1" {
+		var service corev1.Service
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(target.Object, &service); err != nil {
+			return response.Errorf("failed to decode Service %s: %v", target.GetName(), err)
+		}
+		err = lookup.forService(ctx, &service)
+	} else {
+		spec, specErr := podSpecOf(target)
+		if specErr != nil {
+			return response.Errorf("get_related_resources works on Services, Pods, and workloads that run pods: %v", specErr)
+		}
+		err = lookup.forWorkload(ctx, target, spec)
+	}
+	if err != nil {
+		return response.Error(connectivity.ErrorMessage(err))
+	}
+
+	return response.JSON(GetRelatedResourcesResult{
+		Kind:      target.GetKind(),
+		Namespace: target.GetNamespace(),
+		Name:      target.GetName(),
+		Related:   lookup.related,
+		Warnings:  lookup.warnings,
+	})
+}
+
+// relatedLookup collects the sections of a get_related_resources response.
+// Its methods only return connectivity errors that should be surfaced as
+// they are; other failures become warnings.
+type relatedLookup struct {
+	handler   *ResourceHandler
+	client    kubernetes.ClusterReader
+	namespace string
+	related   map[string][]RelatedResource
+	warnings  []string
+}
+
+// enabled reports whether gvr may be read, recording a warning when the
+// resource filter disables it.
+func (l *relatedLookup) enabled(gvr schema.GroupVersionResource) bool {
+	filter := l.handler.resourceFilter
+	if filter != nil && filter.IsDisabled(gvr) {
+		l.warnings = append(l.warnings, fmt.Sprintf("%s are disabled by configuration and were not checked", resourcefilter.FormatGVR(gvr)))
+		return false
+	}
+	return true
+}
+
+// failed records a failed lookup as a warning, or returns err when it is a
+// connectivity error that --always-start reports as is.
+func (l *relatedLookup) failed(what string, err error) error {
+	if l.handler.alwaysStart && connectivity.IsTransportError(err) {
+		return err
+	}
+	l.warnings = append(l.warnings, fmt.Sprintf("failed to read %s: %v", what, err))
+	return nil
+}
+
+// add stores a section sorted by kind and name.
+func (l *relatedLookup) add(section string, items []RelatedResource) {
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Kind != items[j].Kind {
+			return items[i].Kind < items[j].Kind
+		}
+		return items[i].Name < items[j].Name
+	})
+	l.related[section] = items
+}
+
+// forWorkload fills the sections of a Pod or of a workload that runs pods.
+func (l *relatedLookup) forWorkload(ctx context.Context, target *unstructured.Unstructured, spec *corev1.PodSpec) error {
+	controllers, err := l.controllers(ctx, target)
+	if err != nil {
+		return err
+	}
+	if len(controllers) > 0 {
+		l.add("controllers", controllers)
+	}
+
+	var serviceNames []string
+	if l.enabled(relatedServicesGVR) {
+		services, err := l.servicesSelecting(ctx, podTemplateLabels(target))
+		if err != nil {
+			return err
+		}
+		for _, service := range services {
+			serviceNames = append(serviceNames, service.Name)
+		}
+		l.add("services", services)
+	}
+
+	if err := l.endpointSlices(ctx, serviceNames); err != nil {
+		return err
+	}
+	if err := l.ingresses(ctx, serviceNames); err != nil {
+		return err
+	}
+
+	configMaps, secrets := podConfigReferences(spec)
+	if l.enabled(relatedConfigMapsGVR) {
+		l.add("configmaps", configMaps)
+	}
+	if l.enabled(relatedSecretsGVR) {
+		l.add("secrets", secrets)
+	}
+
+	if err := l.serviceAccount(ctx, spec); err != nil {
+		return err
+	}
+	if err := l.claims(ctx, target, spec); err != nil {
+		return err
+	}
+
+	// Autoscalers scale the workload at the top of the chain, but may
+	// target any controller in it.
+	targets := []RelatedResource{{Kind: target.GetKind(), Name: target.GetName()}}
+	targets = append(targets, controllers...)
+	return l.autoscalers(ctx, targets)
+}
+
+// forService fills the sections of a Service.
+func (l *relatedLookup) forService(ctx context.Context, service *corev1.Service) error {
+	var workloads []RelatedResource
+
+	switch {
+	case len(service.Spec.Selector) == 0:
+		l.warnings = append(l.warnings, "the Service has no selector, so its endpoints are managed manually rather than by selecting pods")
+	case l.enabled(relatedPodsGVR):
+		selector := labels.SelectorFromSet(service.Spec.Selector).String()
+		pods, err := l.client.ListPods(ctx, l.namespace, metav1.ListOptions{LabelSelector: selector})
+		if err != nil {
+			if err := l.failed("pods", err); err != nil {
+				return err
+			}
+			break
+		}
+
+		items := make([]RelatedResource, 0, len(pods.Items))
+		for i := range pods.Items {
+			items = append(items, RelatedResource{
+				Name:   pods.Items[i].Name,
+				Via:    []string{"selector " + selector},
+				Status: string(pods.Items[i].Status.Phase),
+			})
+		}
+		l.add("pods", items)
+		if len(items) > maxRelatedPods {
+			l.related["pods"] = items[:maxRelatedPods]
+			l.warnings = append(l.warnings, fmt.Sprintf("the Service selects %d pods; only the first %d are listed", len(items), maxRelatedPods))
+		}
+
+		// Walk up from one pod per controller to find the workloads.
+		seen := make(map[types.UID]bool)
+		known := make(map[string]int)
+		for i := range pods.Items {
+			ref := metav1.GetControllerOf(&pods.Items[i])
+			if ref == nil || seen[ref.UID] {
+				continue
+			}
+			seen[ref.UID] = true
+
+			pod, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&pods.Items[i])
+			if err != nil {
+				continue
+			}
+			obj := &unstructured.Unstructured{Object: pod}
+			obj.SetKind("Pod")
+			obj.SetAPIVersion("v1")
+
+			controllers, err := l.controllers(ctx, obj)
+			if err != nil {
+				return err
+			}
+			for _, controller := range controllers {
+				key := controller.Kind + "/" + controller.Name
+				if idx, ok := known[key]; ok {
+					for _, via := range controller.Via {
+						if !containsString(workloads[idx].Via, via) {
+							workloads[idx].Via = append(workloads[idx].Via, via)
+						}
+					}
+					continue
+				}
+				known[key] = len(workloads)
+				workloads = append(workloads, controller)
+			}
+		}
+		l.add("workloads", workloads)
+	}
+
+	if err := l.endpointSlices(ctx, []string{service.Name}); err != nil {
+		return err
+	}
+	if err := l.ingresses(ctx, []string{service.Name}); err != nil {
+		return err
+	}
+	if len(workloads) > 0 {
+		return l.autoscalers(ctx, workloads)
+	}
+	return nil
+}
+
+// controllers follows the controller references of obj upward and returns
+// each controller, nearest first.
+func (l *relatedLookup) controllers(ctx context.Context, obj *unstructured.Unstructured) ([]RelatedResource, error) {
+	var controllers []RelatedResource
+	seen := map[types.UID]bool{obj.GetUID(): true}
+
+	current := obj
+	for len(controllers) < maxOwnerChainDepth {
+		ref := metav1.GetControllerOfNoCopy(current)
+		if ref == nil || (ref.UID != "" && seen[ref.UID]) {
+			break
+		}
+		seen[ref.UID] = true
+
+		controller := RelatedResource{
+			Kind: ref.Kind,
+			Name: ref.Name,
+			Via:  []string{fmt.Sprintf("controller of %s/%s", current.GetKind(), current.GetName())},
+		}
+
+		gvr, err := l.client.ResolveResourceType(ref.Kind, ref.APIVersion)
+		if err != nil {
+			gvr, err = l.client.ResolveResourceType(ref.Kind, "")
+		}
+		if err != nil {
+			if l.handler.alwaysStart && connectivity.IsError(err) {
+				return nil, err
+			}
+			controllers = append(controllers, controller)
+			l.warnings = append(l.warnings, fmt.Sprintf("failed to resolve the type of controller %s %s: %v", ref.Kind, ref.Name, err))
+			break
+		}
+
+		if l.handler.resourceFilter != nil && l.handler.resourceFilter.IsDisabled(gvr) {
+			controllers = append(controllers, controller)
+			break
+		}
+
+		next, err := l.client.GetResource(ctx, gvr, current.GetNamespace(), ref.Name)
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				controller.Missing = true
+				controllers = append(controllers, controller)
+				break
+			}
+			controllers = append(controllers, controller)
+			if err := l.failed(fmt.Sprintf("%s %s", ref.Kind, ref.Name), err); err != nil {
+				return nil, err
+			}
+			break
+		}
+
+		controllers = append(controllers, controller)
+		current = next
+	}
+
+	return controllers, nil
+}
+
+// servicesSelecting returns the Services whose selector matches podLabels.
+func (l *relatedLookup) servicesSelecting(ctx context.Context, podLabels map[string]string) ([]RelatedResource, error) {
+	services := make([]RelatedResource, 0)
+	if len(podLabels) == 0 {
+		return services, nil
+	}
+
+	list, err := l.client.ListServices(ctx, l.namespace, metav1.ListOptions{})
+	if err != nil {
+		return services, l.failed("services", err)
+	}
+
+	for i := range list.Items {
+		selector := list.Items[i].Spec.Selector
+		if len(selector) == 0 || !labels.SelectorFromSet(selector).Matches(labels.Set(podLabels)) {
+			continue
+		}
+		services = append(services, RelatedResource{
+			Name:   list.Items[i].Name,
+			Via:    []string{"selector " + labels.SelectorFromSet(selector).String()},
+			Status: string(list.Items[i].Spec.Type),
+		})
+	}
+
+	return services, nil
+}
+
+// endpointSlices adds the EndpointSlices of the named Services.
+func (l *relatedLookup) endpointSlices(ctx context.Context, serviceNames []string) error {
+	if !l.enabled(relatedEndpointSlicesGVR) {
+		return nil
+	}
+
+	slices := make([]RelatedResource, 0)
+	if len(serviceNames) == 0 {
+		l.add("endpointslices", slices)
+		return nil
+	}
+
+	list, err := l.client.ListEndpointSlices(ctx, l.namespace, metav1.ListOptions{})
+	if err != nil {
+		return l.failed("endpointslices", err)
+	}
+
+	for i := range list.Items {
+		service := list.Items[i].Labels[discoveryv1.LabelServiceName]
+		if !containsString(serviceNames, service) {
+			continue
+		}
+		slices = append(slices, RelatedResource{
+			Name:   list.Items[i].Name,
+			Via:    []string{"endpoints of Service " + service},
+			Status: endpointSliceStatus(&list.Items[i]),
+		})
+	}
+
+	l.add("endpointslices", slices)
+	return nil
+}
+
+// endpointSliceStatus summarizes how many endpoints of a slice are ready.
+// Like kube-proxy, it treats an unknown ready condition as ready.
+func endpointSliceStatus(slice *discoveryv1.EndpointSlice) string {
+	ready := 0
+	for _, endpoint := range slice.Endpoints {
+		if endpoint.Conditions.Ready == nil || *endpoint.Conditions.Ready {
+			ready++
+		}
+	}
+	return fmt.Sprintf("%d/%d endpoints ready", ready, len(slice.Endpoints))
+}
+
+// ingresses adds the Ingresses with a backend that is one of the named
+// Services.
+func (l *relatedLookup) ingresses(ctx context.Context, serviceNames []string) error {
+	if !l.enabled(relatedIngressesGVR) {
+		return nil
+	}
+
+	ingresses := make([]RelatedResource, 0)
+	if len(serviceNames) == 0 {
+		l.add("ingresses", ingresses)
+		return nil
+	}
+
+	list, err := l.client.ListIngresses(ctx, l.namespace, metav1.ListOptions{})
+	if err != nil {
+		return l.failed("ingresses", err)
+	}
+
+	for i := range list.Items {
+		if routes := ingressRoutesTo(&list.Items[i], serviceNames); len(routes) > 0 {
+			ingresses = append(ingresses, RelatedResource{
+				Name:   list.Items[i].Name,
+				Via:    routes,
+				Status: ingressAddresses(&list.Items[i]),
+			})
+		}
+	}
+
+	l.add("ingresses", ingresses)
+	return nil
+}
+
+// ingressRoutesTo describes the routes of an Ingress whose backend is one of
+// the named Services, such as "shop.example.com/api -> web:80".
+func ingressRoutesTo(ingress *networkingv1.Ingress, serviceNames []string) []string {
+	var routes []string
+
+	describe := func(prefix string, backend *networkingv1.IngressBackend) {
+		if backend == nil || backend.Service == nil || !containsString(serviceNames, backend.Service.Name) {
+			return
+		}
+		port := backend.Service.Port.Name
+		if port == "" {
+			port = fmt.Sprintf("%d", backend.Service.Port.Number)
+		}
+		routes = append(routes, fmt.Sprintf("%s -> %s:%s", prefix, backend.Service.Name, port))
+	}
+
+	describe("default backend", ingress.Spec.DefaultBackend)
+	for _, rule := range ingress.Spec.Rules {
+		if rule.HTTP == nil {
+			continue
+		}
+		host := rule.Host
+		if host == "" {
+			host = "*"
+		}
+		for i := range rule.HTTP.Paths {
+			describe(host+rule.HTTP.Paths[i].Path, &rule.HTTP.Paths[i].Backend)
+		}
+	}
+
+	return routes
+}
+
+// ingressAddresses lists the load balancer addresses of an Ingress.
+func ingressAddresses(ingress *networkingv1.Ingress) string {
+	var addresses []string
+	for _, lb := range ingress.Status.LoadBalancer.Ingress {
+		if lb.Hostname != "" {
+			addresses = append(addresses, lb.Hostname)
+		} else if lb.IP != "" {
+			addresses = append(addresses, lb.IP)
+		}
+	}
+	return strings.Join(addresses, ", ")
+}
+
+// podConfigReferences returns the ConfigMaps and Secrets a pod spec
+// references through volumes, environment variables, and image pull
+// secrets, with how each one is used.
+func podConfigReferences(spec *corev1.PodSpec) (configMaps, secrets []RelatedResource) {
+	cmVia := make(map[string][]string)
+	secretVia := make(map[string][]string)
+	var cmOrder, secretOrder []string
+
+	addRef := func(via map[string][]string, order *[]string, name, how string) {
+		if name == "" {
+			return
+		}
+		if _, ok := via[name]; !ok {
+			*order = append(*order, name)
+		}
+		if !containsString(via[name], how) {
+			via[name] = append(via[name], how)
+		}
+	}
+	addConfigMap := func(name, how string) { addRef(cmVia, &cmOrder, name, how) }
+	addSecret := func(name, how string) { addRef(secretVia, &secretOrder, name, how) }
+
+	for _, volume := range spec.Volumes {
+		switch {
+		case volume.ConfigMap != nil:
+			addConfigMap(volume.ConfigMap.Name, "volume "+volume.Name)
+		case volume.Secret != nil:
+			addSecret(volume.Secret.SecretName, "volume "+volume.Name)
+		case volume.Projected != nil:
+			for _, source := range volume.Projected.Sources {
+				if source.ConfigMap != nil {
+					addConfigMap(source.ConfigMap.Name, "projected volume "+volume.Name)
+				}
+				if source.Secret != nil {
+					addSecret(source.Secret.Name, "projected volume "+volume.Name)
+				}
+			}
+		}
+	}
+
+	type containerEnv struct {
+		name    string
+		env     []corev1.EnvVar
+		envFrom []corev1.EnvFromSource
+	}
+	var containers []containerEnv
+	for _, c := range spec.InitContainers {
+		containers = append(containers, containerEnv{c.Name, c.Env, c.EnvFrom})
+	}
+	for _, c := range spec.Containers {
+		containers = append(containers, containerEnv{c.Name, c.Env, c.EnvFrom})
+	}
+	for _, c := range spec.EphemeralContainers {
+		containers = append(containers, containerEnv{c.Name, c.Env, c.EnvFrom})
+	}
+
+	for _, c := range containers {
+		for _, env := range c.env {
+			if env.ValueFrom == nil {
+				continue
+			}
+			if ref := env.ValueFrom.ConfigMapKeyRef; ref != nil {
+				addConfigMap(ref.Name, fmt.Sprintf("env %s in container %s", env.Name, c.name))
+			}
+			if ref := env.ValueFrom.SecretKeyRef; ref != nil {
+				addSecret(ref.Name, fmt.Sprintf("env %s in container %s", env.Name, c.name))
+			}
+		}
+		for _, from := range c.envFrom {
+			if from.ConfigMapRef != nil {
+				addConfigMap(from.ConfigMapRef.Name, "envFrom in container "+c.name)
+			}
+			if from.SecretRef != nil {
+				addSecret(from.SecretRef.Name, "envFrom in container "+c.name)
+			}
+		}
+	}
+
+	for _, ref := range spec.ImagePullSecrets {
+		addSecret(ref.Name, "imagePullSecrets")
+	}
+
+	configMaps = make([]RelatedResource, 0, len(cmOrder))
+	for _, name := range cmOrder {
+		configMaps = append(configMaps, RelatedResource{Name: name, Via: cmVia[name]})
+	}
+	secrets = make([]RelatedResource, 0, len(secretOrder))
+	for _, name := range secretOrder {
+		secrets = append(secrets, RelatedResource{Name: name, Via: secretVia[name]})
+	}
+
+	return configMaps, secrets
+}
+
+// serviceAccount adds the ServiceAccount the pods run as, flagging it when
+// it does not exist.
+func (l *relatedLookup) serviceAccount(ctx context.Context, spec *corev1.PodSpec) error {
+	if !l.enabled(relatedServiceAccountsGVR) {
+		return nil
+	}
+
+	account := RelatedResource{Name: spec.ServiceAccountName, Via: []string{"serviceAccountName"}}
+	if account.Name == "" {
+		account = RelatedResource{Name: "default", Via: []string{"default ServiceAccount of the namespace"}}
+	}
+	if spec.AutomountServiceAccountToken != nil && !*spec.AutomountServiceAccountToken {
+		account.Status = "token not mounted"
+	}
+
+	if _, err := l.client.GetServiceAccount(ctx, l.namespace, account.Name); err != nil {
+		if !apierrors.IsNotFound(err) {
+			if err := l.failed("serviceaccount "+account.Name, err); err != nil {
+				return err
+			}
+		}
+		account.Missing = apierrors.IsNotFound(err)
+	}
+
+	l.add("serviceaccounts", []RelatedResource{account})
+	return nil
+}
+
+// claims adds the PersistentVolumeClaims the pods mount, including those a
+// StatefulSet creates from its volumeClaimTemplates.
+func (l *relatedLookup) claims(ctx context.Context, target *unstructured.Unstructured, spec *corev1.PodSpec) error {
+	var wanted []RelatedResource
+	for _, volume := range spec.Volumes {
+		switch {
+		case volume.PersistentVolumeClaim != nil:
+			wanted = append(wanted, RelatedResource{Name: volume.PersistentVolumeClaim.ClaimName, Via: []string{"volume " + volume.Name}})
+		case volume.Ephemeral != nil && target.GetKind() == "Pod":
+			// Generic ephemeral volumes get a claim named after the pod.
+			wanted = append(wanted, RelatedResource{Name: target.GetName() + "-" + volume.Name, Via: []string{"ephemeral volume " + volume.Name}})
+		}
+	}
+
+	var templates []string
+	if target.GetKind() == "StatefulSet" {
+		list, _, _ := unstructured.NestedSlice(target.Object, "spec", "volumeClaimTemplates")
+		for _, item := range list {
+			if tpl, ok := item.(map[string]interface{}); ok {
+				if name, _, _ := unstructured.NestedString(tpl, "metadata", "name"); name != "" {
+					templates = append(templates, name)
+				}
+			}
+		}
+	}
+
+	if len(wanted) == 0 && len(templates) == 0 {
+		return nil
+	}
+	if !l.enabled(relatedPVCsGVR) {
+		return nil
+	}
+
+	list, err := l.client.ListPersistentVolumeClaims(ctx, l.namespace, metav1.ListOptions{})
+	if err != nil {
+		return l.failed("persistentvolumeclaims", err)
+	}
+
+	phases := make(map[string]string, len(list.Items))
+	for i := range list.Items {
+		phases[list.Items[i].Name] = string(list.Items[i].Status.Phase)
+	}
+
+	claims := make([]RelatedResource, 0, len(wanted))
+	for _, claim := range wanted {
+		if phase, ok := phases[claim.Name]; ok {
+			claim.Status = phase
+		} else {
+			claim.Missing = true
+		}
+		claims = append(claims, claim)
+	}
+
+	// StatefulSet claims are named <template>-<statefulset>-<ordinal>.
+	for _, tpl := range templates {
+		prefix := tpl + "-" + target.GetName() + "-"
+		for i := range list.Items {
+			name := list.Items[i].Name
+			if ordinal, ok := strings.CutPrefix(name, prefix); ok && isOrdinal(ordinal) {
+				claims = append(claims, RelatedResource{
+					Name:   name,
+					Via:    []string{"volumeClaimTemplate " + tpl},
+					Status: phases[name],
+				})
+			}
+		}
+	}
+
+	l.add("persistentvolumeclaims", claims)
+	return nil
+}
+
+// isOrdinal reports whether s is a StatefulSet pod ordinal.
+func isOrdinal(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// autoscalers adds the HorizontalPodAutoscalers whose scale target is one of
+// targets.
+func (l *relatedLookup) autoscalers(ctx context.Context, targets []RelatedResource) error {
+	if !l.enabled(relatedHPAsGVR) {
+		return nil
+	}
+
+	list, err := l.client.ListHorizontalPodAutoscalers(ctx, l.namespace, metav1.ListOptions{})
+	if err != nil {
+		return l.failed("horizontalpodautoscalers", err)
+	}
+
+	hpas := make([]RelatedResource, 0)
+	for i := range list.Items {
+		hpa := &list.Items[i]
+		ref := hpa.Spec.ScaleTargetRef
+		for _, target := range targets {
+			if ref.Kind != target.Kind || ref.Name != target.Name {
+				continue
+			}
+
+			minReplicas := int32(1)
+			if hpa.Spec.MinReplicas != nil {
+				minReplicas = *hpa.Spec.MinReplicas
+			}
+			hpas = append(hpas, RelatedResource{
+				Name: hpa.Name,
+				Via:  []string{fmt.Sprintf("scaleTargetRef %s/%s", ref.Kind, ref.Name)},
+				Status: fmt.Sprintf("%d replicas (min %d, max %d)",
+					hpa.Status.CurrentReplicas, minReplicas, hpa.Spec.MaxReplicas),
+			})
+			break
+		}
+	}
+
+	l.add("horizontalpodautoscalers", hpas)
+	return nil
+}
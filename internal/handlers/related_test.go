@@ -0,0 +1,232 @@
+package handlers
+
+import (
+	"reflect"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/fakecluster"
+)
+
+func TestPodConfigReferences(t *testing.T) {
+	t.Parallel()
+
+	spec := &corev1.PodSpec{
+		Volumes: []corev1.Volume{
+			{Name: "config", VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{LocalObjectReference: corev1.LocalObjectReference{Name: "web-config"}}}},
+			{Name: "tls", VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: "web-tls"}}},
+			{Name: "bundle", VolumeSource: corev1.VolumeSource{Projected: &corev1.ProjectedVolumeSource{Sources: []corev1.VolumeProjection{
+				{ConfigMap: &corev1.ConfigMapProjection{LocalObjectReference: corev1.LocalObjectReference{Name: "ca-bundle"}}},
+			}}}},
+		},
+		Containers: []corev1.Container{{
+			Name: "app",
+			Env: []corev1.EnvVar{
+				{Name: "MODE", ValueFrom: &corev1.EnvVarSource{ConfigMapKeyRef: &corev1.ConfigMapKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "web-config"}, Key: "mode"}}},
+				{Name: "DB_PASSWORD", ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "db"}, Key: "password"}}},
+				{Name: "PLAIN", Value: "1"},
+			},
+			EnvFrom: []corev1.EnvFromSource{{SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "db"}}}},
+		}},
+		ImagePullSecrets: []corev1.LocalObjectReference{{Name: "registry"}},
+	}
+
+	configMaps, secrets := podConfigReferences(spec)
+
+	wantConfigMaps := []RelatedResource{
+		{Name: "web-config", Via: []string{"volume config", "env MODE in container app"}},
+		{Name: "ca-bundle", Via: []string{"projected volume bundle"}},
+	}
+	if !reflect.DeepEqual(configMaps, wantConfigMaps) {
+		t.Errorf("expected configmaps %+v, got %+v", wantConfigMaps, configMaps)
+	}
+
+	wantSecrets := []RelatedResource{
+		{Name: "web-tls", Via: []string{"volume tls"}},
+		{Name: "db", Via: []string{"env DB_PASSWORD in container app", "envFrom in container app"}},
+		{Name: "registry", Via: []string{"imagePullSecrets"}},
+	}
+	if !reflect.DeepEqual(secrets, wantSecrets) {
+		t.Errorf("expected secrets %+v, got %+v", wantSecrets, secrets)
+	}
+}
+
+func TestGetRelatedResources_FakeCluster(t *testing.T) {
+	t.Parallel()
+
+	isController := true
+	ownedBy := func(apiVersion, kind, name, uid string) []metav1.OwnerReference {
+		return []metav1.OwnerReference{{APIVersion: apiVersion, Kind: kind, Name: name, UID: types.UID(uid), Controller: &isController}}
+	}
+	appLabels := map[string]string{"app": "web"}
+	ready := true
+	pathType := networkingv1.PathTypePrefix
+
+	template := corev1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{Labels: appLabels},
+		Spec: corev1.PodSpec{
+			ServiceAccountName: "web",
+			Containers:         []corev1.Container{{Name: "app", Image: "web:1.0"}},
+			Volumes: []corev1.Volume{
+				{Name: "data", VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "web-data"}}},
+				{Name: "cache", VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "web-cache"}}},
+			},
+		},
+	}
+
+	handler := NewResourceHandler(fakecluster.New(fakecluster.Config{
+		Objects: []runtime.Object{
+			&appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop", UID: "deploy-uid"},
+				Spec:       appsv1.DeploymentSpec{Selector: &metav1.LabelSelector{MatchLabels: appLabels}, Template: template},
+			},
+			&appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{
+				Name: "web-5d4f8", Namespace: "shop", UID: "rs-uid",
+				OwnerReferences: ownedBy("apps/v1", "Deployment", "web", "deploy-uid"),
+			}},
+			&corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "web-5d4f8-x2x8q", Namespace: "shop", UID: "pod-uid", Labels: appLabels,
+					OwnerReferences: ownedBy("apps/v1", "ReplicaSet", "web-5d4f8", "rs-uid"),
+				},
+				Spec:   template.Spec,
+				Status: corev1.PodStatus{Phase: corev1.PodRunning},
+			},
+			&corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop"},
+				Spec:       corev1.ServiceSpec{Selector: appLabels, Type: corev1.ServiceTypeClusterIP},
+			},
+			&corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "shop"},
+				Spec:       corev1.ServiceSpec{Selector: map[string]string{"app": "other"}},
+			},
+			&discoveryv1.EndpointSlice{
+				ObjectMeta: metav1.ObjectMeta{Name: "web-abcde", Namespace: "shop", Labels: map[string]string{discoveryv1.LabelServiceName: "web"}},
+				Endpoints:  []discoveryv1.Endpoint{{Addresses: []string{"10.0.0.1"}, Conditions: discoveryv1.EndpointConditions{Ready: &ready}}},
+			},
+			&networkingv1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{Name: "shop", Namespace: "shop"},
+				Spec: networkingv1.IngressSpec{Rules: []networkingv1.IngressRule{{
+					Host: "shop.example.com",
+					IngressRuleValue: networkingv1.IngressRuleValue{HTTP: &networkingv1.HTTPIngressRuleValue{Paths: []networkingv1.HTTPIngressPath{{
+						Path:     "/",
+						PathType: &pathType,
+						Backend:  networkingv1.IngressBackend{Service: &networkingv1.IngressServiceBackend{Name: "web", Port: networkingv1.ServiceBackendPort{Number: 80}}},
+					}}}},
+				}}},
+			},
+			&autoscalingv2.HorizontalPodAutoscaler{
+				ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop"},
+				Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+					ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{APIVersion: "apps/v1", Kind: "Deployment", Name: "web"},
+					MaxReplicas:    5,
+				},
+				Status: autoscalingv2.HorizontalPodAutoscalerStatus{CurrentReplicas: 2},
+			},
+			&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "web-config", Namespace: "shop"}},
+			&corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{Name: "web-data", Namespace: "shop"},
+				Status:     corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimBound},
+			},
+		},
+	}), nil, false)
+
+	related := func(t *testing.T, resourceType, name string) map[string][]RelatedResource {
+		t.Helper()
+
+		result, isErr := callTool(t, handler.GetRelatedResources, map[string]any{
+			"resource_type": resourceType,
+			"namespace":     "shop",
+			"name":          name,
+		})
+		if isErr {
+			t.Fatalf("unexpected error: %v", result["error"])
+		}
+
+		var sections map[string][]RelatedResource
+		decodeInto(t, result["related"], &sections)
+		return sections
+	}
+
+	names := func(items []RelatedResource) []string {
+		var out []string
+		for _, item := range items {
+			name := item.Name
+			if item.Kind != "" {
+				name = item.Kind + "/" + name
+			}
+			if item.Missing {
+				name += " (missing)"
+			}
+			out = append(out, name)
+		}
+		return out
+	}
+
+	t.Run("pod", func(t *testing.T) {
+		t.Parallel()
+
+		sections := related(t, "pods", "web-5d4f8-x2x8q")
+
+		want := map[string][]string{
+			"controllers":              {"Deployment/web", "ReplicaSet/web-5d4f8"},
+			"services":                 {"web"},
+			"endpointslices":           {"web-abcde"},
+			"ingresses":                {"shop"},
+			"serviceaccounts":          {"web (missing)"},
+			"persistentvolumeclaims":   {"web-cache (missing)", "web-data"},
+			"horizontalpodautoscalers": {"web"},
+		}
+		for section, wantNames := range want {
+			if got := names(sections[section]); !reflect.DeepEqual(got, wantNames) {
+				t.Errorf("expected %s %v, got %v", section, wantNames, got)
+			}
+		}
+
+		if got := sections["ingresses"][0].Via; !reflect.DeepEqual(got, []string{"shop.example.com/ -> web:80"}) {
+			t.Errorf("unexpected ingress route %v", got)
+		}
+		if got := sections["endpointslices"][0].Status; got != "1/1 endpoints ready" {
+			t.Errorf("unexpected endpoint slice status %q", got)
+		}
+	})
+
+	t.Run("service", func(t *testing.T) {
+		t.Parallel()
+
+		sections := related(t, "services", "web")
+
+		want := map[string][]string{
+			"pods":                     {"web-5d4f8-x2x8q"},
+			"workloads":                {"Deployment/web", "ReplicaSet/web-5d4f8"},
+			"endpointslices":           {"web-abcde"},
+			"ingresses":                {"shop"},
+			"horizontalpodautoscalers": {"web"},
+		}
+		for section, wantNames := range want {
+			if got := names(sections[section]); !reflect.DeepEqual(got, wantNames) {
+				t.Errorf("expected %s %v, got %v", section, wantNames, got)
+			}
+		}
+	})
+
+	t.Run("unsupported kind", func(t *testing.T) {
+		t.Parallel()
+
+		if _, isErr := callTool(t, handler.GetRelatedResources, map[string]any{
+			"resource_type": "configmaps",
+			"namespace":     "shop",
+			"name":          "web-config",
+		}); !isErr {
+			t.Error("expected an error for a kind without pods")
+		}
+	})
+}
@@ -2,27 +2,54 @@ package handlers
 
 import (
 	"context"
+	"fmt"
+	"slices"
 	"sort"
 	"strings"
 	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/connectivity"
 	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/kubernetes"
 	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/resourcefilter"
 	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/response"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/toolschema"
 )
 
+const (
+	// fullObjectsDefaultLimit is the page size of list_resources with
+	// include_spec when no limit is given.
+	fullObjectsDefaultLimit = 50
+
+	// fullObjectsMaxLimit is the largest page list_resources returns with
+	// include_spec.
+	fullObjectsMaxLimit = 500
+
+	// sortedListPageSize is how many objects are requested per page when
+	// list_resources reads a whole listing to sort it.
+	sortedListPageSize = 500
+)
+
+// sortOrders maps the sort_by values of list_resources to the sort orders
+// recorded in continue tokens.
+var sortOrders = map[string]string{
+	"newest": sortByTimestamp,
+	"oldest": sortByTimestampAscending,
+	"name":   sortByNamespaceName,
+}
+
 // ResourceHandler provides MCP tools for Kubernetes resource operations.
 // It handles listing and retrieving resources across all API groups, with support
 // for filtering, pagination, and dynamic resource type resolution. The handler
 // supports both namespaced and cluster-scoped resources.
 type ResourceHandler struct {
-	client         *kubernetes.Client
+	client         kubernetes.ClusterReader
 	resourceFilter *resourcefilter.Filter
 	alwaysStart    bool
 }
@@ -32,7 +59,7 @@ type ResourceHandler struct {
 // alwaysStart mirrors the --always-start flag: when true, connectivity and auth errors
 // are intercepted and returned as structured tool errors so the LLM can surface them
 // to the user rather than treating them as retryable failures.
-func NewResourceHandler(client *kubernetes.Client, filter *resourcefilter.Filter, alwaysStart bool) *ResourceHandler {
+func NewResourceHandler(client kubernetes.ClusterReader, filter *resourcefilter.Filter, alwaysStart bool) *ResourceHandler {
 	return &ResourceHandler{
 		client:         client,
 		resourceFilter: filter,
@@ -45,41 +72,95 @@ func NewResourceHandler(client *kubernetes.Client, filter *resourcefilter.Filter
 type ListResourcesParams struct {
 	// ResourceType is the type of resource to list (e.g., "pods", "deployments").
 	// Supports plural names, singular names, kinds, and short names.
-	ResourceType string `json:"resource_type"`
+	ResourceType string `json:"resource_type" required:"true" description:"The type of resource to list"`
 
 	// APIVersion optionally constrains the search to a specific API version.
 	// If empty, searches across all available API versions.
-	APIVersion string `json:"api_version,omitempty"`
+	APIVersion string `json:"api_version,omitempty" description:"API version for the resource (e.g., \"v1\", \"apps/v1\"), if not provided, the tool will try to resolve the resource type from the API resources list"`
 
-	// Namespace specifies the target namespace for namespaced resources.
-	// Leave empty for cluster-scoped resources.
-	Namespace string `json:"namespace,omitempty"`
+	// Namespace specifies the target namespace for namespaced resources, or
+	// several namespaces separated by commas. Leave empty for cluster-scoped
+	// resources.
+	Namespace string `json:"namespace,omitempty" description:"Target namespace, or several comma-separated namespaces listed together (e.g., \"shop,billing\"). Leave empty for cluster-scoped resources, or for the server's default namespace when one is configured"`
+
+	// AllNamespaces lists across every namespace, even when the server has a
+	// default namespace that an empty Namespace would fall back to.
+	AllNamespaces bool `json:"all_namespaces,omitempty" description:"When true, lists across every namespace, even when the server has a default namespace configured. Cannot be combined with namespace"`
 
 	// Context specifies which Kubernetes context to use for this operation.
 	// If empty, uses the current context from kubeconfig.
-	Context string `json:"context,omitempty"`
+	Context string `json:"context,omitempty" description:"Kubernetes context to use (defaults to current context from kubeconfig)"`
 
 	// LabelSelector filters resources by labels (e.g., "app=nginx,version=1.0").
-	LabelSelector string `json:"label_selector,omitempty"`
+	LabelSelector string `json:"label_selector,omitempty" description:"Label selector to filter resources (e.g., \"app=nginx,version=1.0\")"`
 
 	// FieldSelector filters resources by fields (e.g., "status.phase=Running").
-	FieldSelector string `json:"field_selector,omitempty"`
+	FieldSelector string `json:"field_selector,omitempty" description:"Field selector to filter resources (e.g., \"status.phase=Running\")"`
 
 	// Limit restricts the maximum number of resources returned.
 	// If 0, returns all matching resources.
-	Limit int `json:"limit,omitempty"`
+	Limit int `json:"limit,omitempty" minimum:"0" description:"Maximum number of resources to return (defaults to all)"`
 
 	// Continue is a pagination token from a previous response.
 	// Used to retrieve the next page of results.
-	Continue string `json:"continue,omitempty"`
+	Continue string `json:"continue,omitempty" description:"Continue token for pagination (from previous response)"`
 
 	// TitleOnly when true (default), returns only metadata.name for each resource.
 	// When false, returns metadata, apiVersion, and kind.
-	TitleOnly *bool `json:"title_only,omitempty"`
+	TitleOnly *bool `json:"title_only,omitempty" default:"true" description:"When true (default), returns only resource names. When false, returns metadata, apiVersion, and kind"`
+
+	// Columns lists the fields to return for each resource instead of its
+	// name or summary, as comma-separated paths.
+	Columns string `json:"columns,omitempty" description:"Comma-separated field paths to return for each resource instead of its name or summary, such as \"metadata.name,status.phase,spec.nodeName\". Each item maps every path to its value, or null when missing. Use [n] to pick a list element, apply keys to a list to get them from every element (e.g., \"spec.containers.image\"), and escape dots inside keys with a backslash (e.g., \"metadata.labels.app\\.kubernetes\\.io/name\"). Overrides title_only"`
 
 	// IncludeManagedFields when true, preserves metadata.managedFields in responses.
 	// By default, managed fields are omitted to reduce noise.
-	IncludeManagedFields bool `json:"include_managed_fields,omitempty"`
+	IncludeManagedFields bool `json:"include_managed_fields,omitempty" default:"false" description:"When true, preserves metadata.managedFields in the response. By default these fields are omitted to reduce noise"`
+
+	// IncludeNoisyAnnotations when true, preserves the annotations listed in
+	// noisyAnnotations. By default, they are omitted to reduce noise.
+	IncludeNoisyAnnotations bool `json:"include_noisy_annotations,omitempty" default:"false" description:"When true, preserves annotations holding a copy of the object as last applied, such as kubectl.kubernetes.io/last-applied-configuration, in the response. By default these annotations are omitted, since they often double the size of each object"`
+
+	// IncludeSpec returns complete objects instead of names or summaries.
+	IncludeSpec bool `json:"include_spec,omitempty" description:"When true, returns complete objects, with their spec and status, instead of names or summaries, to avoid a get_resource call per item. Pages hold 50 objects unless limit is set, up to 500; pass continue to get the rest. Overrides title_only and cannot be combined with columns"`
+
+	// PodStatus adds the "kubectl get pods" view of each pod to the items.
+	PodStatus bool `json:"pod_status,omitempty" description:"When listing pods, adds a pod_status field to each item with what \"kubectl get pods\" shows: ready containers (e.g., \"1/2\"), phase, status (e.g., \"CrashLoopBackOff\"), restarts, age, and node. Works with every output mode. With count_only, counts the pods by status instead"`
+
+	// SortBy orders the items, and makes paginated listings stable.
+	SortBy string `json:"sort_by,omitempty" enum:"newest,oldest,name" description:"Order of the items: \"newest\" or \"oldest\" by creation time, or \"name\" by namespace and name. With limit, the server reads every matching object, sorts them, and pages through the sorted list with its own continue tokens, which resume right after the last item returned, so objects created or deleted between pages do not make pages overlap or skip items. Without sort_by, a single namespace is returned newest first, or in the API server's order when limit or continue is set"`
+
+	// CountOnly returns the number of matching objects per namespace
+	// instead of the objects.
+	CountOnly bool `json:"count_only,omitempty" description:"When true, returns only the number of matching objects, in total and per namespace, without any items, so questions like \"how many pods are failing\" do not transfer every object. Combine with pod_status to count pods by status (e.g., how many are in CrashLoopBackOff). Cannot be combined with columns, include_spec, limit, or continue"`
+}
+
+// ListResourcesResult is the result of the list_resources MCP tool.
+type ListResourcesResult struct {
+	ResourceType string `json:"resource_type"`
+
+	// Namespace is the namespace listed, and Namespaces the namespaces
+	// listed together when several were given.
+	Namespace     string   `json:"namespace,omitempty"`
+	Namespaces    []string `json:"namespaces,omitempty"`
+	AllNamespaces bool     `json:"all_namespaces,omitempty"`
+
+	Count int `json:"count"`
+
+	// Items holds the name of each resource, its metadata, apiVersion, and
+	// kind when title_only is false, the complete object with include_spec,
+	// or the requested columns, with a
+	// pod_status field for pods when pod_status is set.
+	Items []any `json:"items"`
+
+	// Continue is the token to pass to get the next page, when there is one.
+	Continue string `json:"continue,omitempty"`
+
+	// NamespaceCounts holds the count of each namespace with count_only,
+	// largest first, and PodStatuses the pods counted by status when
+	// pod_status is also set.
+	NamespaceCounts []NamespaceObjectCount `json:"namespace_counts,omitempty"`
+	PodStatuses     map[string]int         `json:"pod_statuses,omitempty"`
 }
 
 // ListResources implements the list_resources MCP tool.
@@ -88,16 +169,16 @@ type ListResourcesParams struct {
 // for consistent ordering across requests.
 func (h *ResourceHandler) ListResources(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	var params ListResourcesParams
-	if err := request.BindArguments(&params); err != nil {
+	if err := bindParams(request, &params); err != nil {
 		return response.Errorf("failed to parse arguments: %s", err)
 	}
 
-	if params.ResourceType == "" {
-		return response.Error("resource_type is required")
+	if params.AllNamespaces && strings.TrimSpace(params.Namespace) != "" {
+		return response.Error(errNamespaceScope.Error())
 	}
 
 	// Use the appropriate client based on context
-	client, err := h.client.ForContext(params.Context)
+	client, err := h.client.ForContext(ctx, params.Context)
 	if err != nil {
 		if h.alwaysStart && connectivity.IsTransportError(err) {
 			return response.Error(connectivity.ErrorMessage(err))
@@ -124,6 +205,65 @@ func (h *ResourceHandler) ListResources(ctx context.Context, request mcp.CallToo
 			params.ResourceType, resourcefilter.FormatGVR(gvr))
 	}
 
+	// Determine whether to show title only (default to true). Columns are
+	// listed in the order of full summaries.
+	titleOnly := true
+	if params.TitleOnly != nil {
+		titleOnly = *params.TitleOnly
+	}
+
+	var columns []column
+	if params.Columns != "" {
+		if columns, err = parseColumns(params.Columns); err != nil {
+			return response.Errorf("invalid columns: %v", err)
+		}
+		titleOnly = false
+	}
+
+	// Complete objects are large, so they are always paged.
+	if params.IncludeSpec {
+		if columns != nil {
+			return response.Error("include_spec cannot be combined with columns: columns already pick the fields to return")
+		}
+		if params.Limit > fullObjectsMaxLimit {
+			return response.Errorf("limit must be at most %d with include_spec, since complete objects are large; page through the rest with continue", fullObjectsMaxLimit)
+		}
+		if params.Limit == 0 {
+			params.Limit = fullObjectsDefaultLimit
+		}
+		titleOnly = false
+	}
+
+	if params.PodStatus && (gvr.Group != "" || gvr.Resource != "pods") {
+		return response.Errorf("pod_status only applies to pods, not %s", resourcefilter.FormatGVR(gvr))
+	}
+
+	namespaces := splitNamespaces(params.Namespace)
+	if params.CountOnly {
+		switch {
+		case columns != nil || params.IncludeSpec:
+			return response.Error("count_only cannot be combined with columns or include_spec, since it returns no items")
+		case params.Limit > 0 || params.Continue != "":
+			return response.Error("count_only counts every matching object, so it cannot be combined with limit or continue")
+		}
+		return h.countResources(ctx, client, gvr, namespaces, params)
+	}
+	if len(namespaces) > 1 {
+		return h.listSorted(ctx, client, gvr, namespaces, params, titleOnly, columns)
+	}
+
+	namespace := params.Namespace
+	if len(namespaces) == 1 {
+		namespace = namespaces[0]
+	}
+	if params.AllNamespaces {
+		namespace = kubernetes.AllNamespaces
+	}
+
+	if params.SortBy != "" {
+		return h.listSorted(ctx, client, gvr, []string{namespace}, params, titleOnly, columns)
+	}
+
 	listOptions := metav1.ListOptions{
 		LabelSelector: params.LabelSelector,
 		FieldSelector: params.FieldSelector,
@@ -134,7 +274,7 @@ func (h *ResourceHandler) ListResources(ctx context.Context, request mcp.CallToo
 		listOptions.Limit = int64(params.Limit)
 	}
 
-	resources, err := client.ListResources(ctx, gvr, params.Namespace, listOptions)
+	resources, err := client.ListResources(ctx, gvr, namespace, listOptions)
 	if err != nil {
 		if h.alwaysStart && connectivity.IsTransportError(err) {
 			return response.Error(connectivity.ErrorMessage(err))
@@ -142,84 +282,323 @@ func (h *ResourceHandler) ListResources(ctx context.Context, request mcp.CallToo
 		return response.Errorf("failed to list resources: %v", err)
 	}
 
-	// Determine whether to show title only (default to true)
-	titleOnly := true
-	if params.TitleOnly != nil {
-		titleOnly = *params.TitleOnly
+	// Extract resource summaries based on title_only setting. Names alone
+	// are ambiguous across namespaces, so titles listed across every
+	// namespace carry theirs. Columns leave no timestamps to sort by, so
+	// the objects are sorted before they are projected.
+	now := time.Now()
+	items := make([]map[string]interface{}, len(resources.Items))
+	if columns != nil {
+		objects := make([]*unstructured.Unstructured, len(resources.Items))
+		for i := range resources.Items {
+			objects[i] = &resources.Items[i]
+		}
+		if params.Continue == "" && params.Limit == 0 {
+			sortObjects(objects, sortByTimestamp)
+		}
+		for i, object := range objects {
+			items[i] = projectColumns(object.Object, columns)
+			if params.PodStatus {
+				addPodStatus(items[i], object, now)
+			}
+		}
+	} else {
+		for i, resource := range resources.Items {
+			if titleOnly {
+				items[i] = extractResourceTitle(&resource)
+				if params.AllNamespaces && resource.GetNamespace() != "" {
+					items[i]["namespace"] = resource.GetNamespace()
+				}
+			} else if params.IncludeSpec {
+				items[i] = sanitizeResourceObject(resource.Object, params.IncludeManagedFields, params.IncludeNoisyAnnotations)
+			} else {
+				items[i] = extractResourceSummary(&resource, params.IncludeManagedFields, params.IncludeNoisyAnnotations)
+			}
+			if params.PodStatus {
+				addPodStatus(items[i], &resource, now)
+			}
+		}
 	}
 
-	// Extract resource summaries based on title_only setting
-	items := make([]map[string]interface{}, len(resources.Items))
-	for i, resource := range resources.Items {
+	// Only sort if not using pagination (no continue token and no limit).
+	// Pages keep the API server's order; sort_by sorts them in listSorted.
+	if params.Continue == "" && params.Limit == 0 {
+		sortNewestFirst(items)
+	}
+
+	result := ListResourcesResult{
+		ResourceType:  params.ResourceType,
+		Namespace:     params.Namespace,
+		AllNamespaces: params.AllNamespaces,
+		Count:         len(items),
+		Items:         make([]any, len(items)),
+		Continue:      resources.GetContinue(),
+	}
+	for i := range items {
+		result.Items[i] = items[i]
+	}
+
+	return response.JSON(result)
+}
+
+// listSorted lists resources for list_resources in a stable order: in
+// several namespaces, or in one with sort_by. The namespaces are listed
+// concurrently, each paged through in full, and merged in the sort_by
+// order, or, without one, newest first, or by namespace and name for
+// titles. The API server's continue tokens only resume its own order in a
+// single namespace, so with a limit the sorted list is paged with this
+// server's own continue tokens. Each holds the sort key of the last item
+// returned, and the next page starts right after it in the list as it is
+// then, so objects created or deleted in the meantime do not shift the
+// remaining items across pages. Columns are projected out of the objects
+// once they are sorted.
+//
+//nolint:gocritic // params is passed by value like the other tool parameters
+func (h *ResourceHandler) listSorted(ctx context.Context, client kubernetes.ClusterReader, gvr schema.GroupVersionResource, namespaces []string, params ListResourcesParams, titleOnly bool, columns []column) (*mcp.CallToolResult, error) {
+	listing := PaginationState{
+		Type:          "resource",
+		Resource:      gvr.String(),
+		Namespace:     strings.Join(namespaces, ","),
+		LabelSelector: params.LabelSelector,
+		FieldSelector: params.FieldSelector,
+		Sort:          sortOrders[params.SortBy],
+		TitleOnly:     titleOnly,
+	}
+	if listing.Sort == "" {
+		listing.Sort = sortByTimestamp
 		if titleOnly {
-			items[i] = extractResourceTitle(&resource)
-		} else {
-			items[i] = extractResourceSummary(&resource, params.IncludeManagedFields)
+			listing.Sort = sortByNamespaceName
 		}
 	}
 
-	// Only sort if not using pagination (no continue token and no limit)
-	// When using pagination, sorting should be handled consistently by the server
-	if params.Continue == "" && params.Limit == 0 {
-		// Sort by creation timestamp (newest first)
-		sort.Slice(items, func(i, j int) bool {
-			timeI, okI := getCreationTime(items[i])
-			timeJ, okJ := getCreationTime(items[j])
+	resume := &PaginationState{}
+	if params.Limit > 0 {
+		var err error
+		if resume, err = resumeState(params.Continue, listing); err != nil {
+			return response.Errorf("invalid continue token: %v", err)
+		}
+	}
 
-			if !okI && !okJ {
-				return false // both invalid, maintain order
-			}
-			if !okI {
-				return false // i is invalid, j comes first
-			}
-			if !okJ {
-				return true // j is invalid, i comes first
-			}
+	listOptions := metav1.ListOptions{
+		LabelSelector: params.LabelSelector,
+		FieldSelector: params.FieldSelector,
+	}
+
+	lists, err := fetchPerNamespace(ctx, namespaces, func(ctx context.Context, namespace string) ([]unstructured.Unstructured, error) {
+		return listAllPages(ctx, client, gvr, namespace, listOptions)
+	})
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to list resources: %v", err)
+	}
 
-			return timeI.After(timeJ) // newer first
+	var objects []*unstructured.Unstructured
+	for _, list := range lists {
+		for i := range list {
+			objects = append(objects, &list[i])
+		}
+	}
+	sortObjects(objects, listing.Sort)
+
+	offset := resume.Offset
+	if after := resume.After; after != nil {
+		offset = sort.Search(len(objects), func(i int) bool {
+			return sortKeyLess(*after, objectSortKey(objects[i]), listing.Sort)
 		})
 	}
 
-	result := map[string]interface{}{
-		"resource_type": params.ResourceType,
-		"namespace":     params.Namespace,
-		"count":         len(items),
-		"items":         items,
+	// Names alone are ambiguous across namespaces, so titles carry theirs.
+	tagNamespace := len(namespaces) > 1 || params.AllNamespaces
+
+	now := time.Now()
+	allItems := make([]interface{}, 0, len(objects))
+	for _, resource := range objects {
+		var item map[string]interface{}
+		switch {
+		case columns != nil:
+			item = projectColumns(resource.Object, columns)
+		case titleOnly:
+			item = extractResourceTitle(resource)
+			if tagNamespace && resource.GetNamespace() != "" {
+				item["namespace"] = resource.GetNamespace()
+			}
+		case params.IncludeSpec:
+			item = sanitizeResourceObject(resource.Object, params.IncludeManagedFields, params.IncludeNoisyAnnotations)
+		default:
+			item = extractResourceSummary(resource, params.IncludeManagedFields, params.IncludeNoisyAnnotations)
+		}
+		if params.PodStatus {
+			addPodStatus(item, resource, now)
+		}
+		allItems = append(allItems, item)
 	}
 
-	// Add continue token if there are more results
-	if resources.GetContinue() != "" {
-		result["continue"] = resources.GetContinue()
+	result := ListResourcesResult{
+		ResourceType:  params.ResourceType,
+		Namespace:     params.Namespace,
+		AllNamespaces: params.AllNamespaces,
+	}
+	if len(namespaces) > 1 {
+		result.Namespace = ""
+		result.Namespaces = namespaces
 	}
 
+	if params.Limit > 0 {
+		paginatedItems, hasMore := paginateItems(allItems, params.Limit, offset)
+		result.Count = len(paginatedItems)
+		result.Items = paginatedItems
+
+		if hasMore {
+			last := objectSortKey(objects[offset+len(paginatedItems)-1])
+			listing.After = &last
+			result.Continue = generateContinueToken(0, listing)
+		}
+
+		return response.JSON(result)
+	}
+
+	result.Count = len(allItems)
+	result.Items = allItems
+
 	return response.JSON(result)
 }
 
+// listAllPages lists every object of gvr in namespace matching opts, a page
+// of sortedListPageSize objects at a time.
+//
+//nolint:gocritic // opts is copied so its paging fields can be set
+func listAllPages(ctx context.Context, client kubernetes.ClusterReader, gvr schema.GroupVersionResource, namespace string, opts metav1.ListOptions) ([]unstructured.Unstructured, error) {
+	var objects []unstructured.Unstructured
+
+	opts.Limit = sortedListPageSize
+	for {
+		page, err := client.ListResources(ctx, gvr, namespace, opts)
+		if err != nil {
+			return nil, err //nolint:wrapcheck // kubernetes API errors are self-descriptive
+		}
+		objects = append(objects, page.Items...)
+
+		if page.GetContinue() == "" {
+			return objects, nil
+		}
+		opts.Continue = page.GetContinue()
+	}
+}
+
+// sortObjects sorts listed objects in order, one of the sort orders of
+// continue tokens. Objects without a creation timestamp go last in either
+// direction, and ties are broken by namespace and name, so every page of a
+// listing is cut from the same order.
+func sortObjects(objects []*unstructured.Unstructured, order string) {
+	sort.SliceStable(objects, func(i, j int) bool {
+		return sortKeyLess(objectSortKey(objects[i]), objectSortKey(objects[j]), order)
+	})
+}
+
+// SortKey is the position of an object in a sorted resource listing, which
+// continue tokens record to resume right after it.
+type SortKey struct {
+	Created   time.Time `json:"created,omitzero"`
+	Namespace string    `json:"namespace,omitempty"`
+	Name      string    `json:"name"`
+}
+
+// objectSortKey returns the sort key of a listed object.
+func objectSortKey(object *unstructured.Unstructured) SortKey {
+	return SortKey{
+		Created:   object.GetCreationTimestamp().Time,
+		Namespace: object.GetNamespace(),
+		Name:      object.GetName(),
+	}
+}
+
+// sortKeyLess reports whether a sorts before b in order. Objects without a
+// creation timestamp go last, and ties are broken by namespace and name, so
+// no two objects share a position.
+func sortKeyLess(a, b SortKey, order string) bool {
+	if order != sortByNamespaceName {
+		switch {
+		case a.Created.IsZero() != b.Created.IsZero():
+			return b.Created.IsZero()
+		case !a.Created.Equal(b.Created):
+			if order == sortByTimestampAscending {
+				return a.Created.Before(b.Created)
+			}
+			return a.Created.After(b.Created)
+		}
+	}
+
+	if a.Namespace != b.Namespace {
+		return a.Namespace < b.Namespace
+	}
+	return a.Name < b.Name
+}
+
+// sortNewestFirst sorts listed items by creation timestamp, newest first.
+// Items without a timestamp go last, and ties keep their order.
+func sortNewestFirst(items []map[string]interface{}) {
+	sort.SliceStable(items, func(i, j int) bool {
+		timeI, okI := getCreationTime(items[i])
+		timeJ, okJ := getCreationTime(items[j])
+
+		if !okI && !okJ {
+			return false // both invalid, maintain order
+		}
+		if !okI {
+			return false // i is invalid, j comes first
+		}
+		if !okJ {
+			return true // j is invalid, i comes first
+		}
+
+		return timeI.After(timeJ) // newer first
+	})
+}
+
 // GetResourceParams defines the parameters for the get_resource MCP tool.
 // It specifies which specific resource instance to retrieve by name and type.
 type GetResourceParams struct {
 	// ResourceType is the type of resource to retrieve (e.g., "pod", "deployment").
 	// Supports plural names, singular names, kinds, and short names.
-	ResourceType string `json:"resource_type"`
+	ResourceType string `json:"resource_type" required:"true" description:"The type of resource to get"`
 
 	// Name is the specific name of the resource instance to retrieve.
-	Name string `json:"name"`
+	Name string `json:"name" required:"true" description:"Resource name"`
 
 	// APIVersion optionally constrains the search to a specific API version.
 	// If empty, searches across all available API versions.
-	APIVersion string `json:"api_version,omitempty"`
+	APIVersion string `json:"api_version,omitempty" description:"API version for the resource (e.g., \"v1\", \"apps/v1\"), if not provided, the tool will try to resolve the resource type from the API resources list"`
 
 	// Namespace specifies the target namespace for namespaced resources.
 	// Required for namespaced resources, leave empty for cluster-scoped resources.
-	Namespace string `json:"namespace,omitempty"`
+	Namespace string `json:"namespace,omitempty" description:"Target namespace (required for namespaced resources)"`
 
 	// Context specifies which Kubernetes context to use for this operation.
 	// If empty, uses the current context from kubeconfig.
-	Context string `json:"context,omitempty"`
+	Context string `json:"context,omitempty" description:"Kubernetes context to use (defaults to current context from kubeconfig)"`
 
 	// IncludeManagedFields when true, preserves metadata.managedFields in responses.
 	// By default, managed fields are omitted to reduce noise.
-	IncludeManagedFields bool `json:"include_managed_fields,omitempty"`
+	IncludeManagedFields bool `json:"include_managed_fields,omitempty" default:"false" description:"When true, preserves metadata.managedFields in the response. By default these fields are omitted to reduce noise"`
+
+	// IncludeNoisyAnnotations when true, preserves the annotations listed in
+	// noisyAnnotations. By default, they are omitted to reduce noise.
+	IncludeNoisyAnnotations bool `json:"include_noisy_annotations,omitempty" default:"false" description:"When true, preserves annotations holding a copy of the object as last applied, such as kubectl.kubernetes.io/last-applied-configuration, in the response. By default these annotations are omitted, since they often double the size of each object"`
+
+	// Subresource names a subresource to get instead of the object itself,
+	// such as "status" or "scale".
+	Subresource string `json:"subresource,omitempty" description:"Subresource to get instead of the whole object, such as \"status\" or \"scale\" (e.g., the scale of a Deployment or a custom resource). Must be served by the cluster for the resource type; use get_logs for pod logs"`
+}
+
+// Object describes a Kubernetes object returned as read from the cluster,
+// such as by get_resource, in output schemas. Its other fields depend on its
+// kind.
+type Object struct {
+	APIVersion string         `json:"apiVersion"`
+	Kind       string         `json:"kind"`
+	Metadata   map[string]any `json:"metadata"`
 }
 
 // GetResource implements the get_resource MCP tool.
@@ -227,20 +606,12 @@ type GetResourceParams struct {
 // by name and type. Returns the full resource object including all fields.
 func (h *ResourceHandler) GetResource(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	var params GetResourceParams
-	if err := request.BindArguments(&params); err != nil {
+	if err := bindParams(request, &params); err != nil {
 		return response.Errorf("failed to parse arguments: %s", err)
 	}
 
-	if params.ResourceType == "" {
-		return response.Error("resource_type is required")
-	}
-
-	if params.Name == "" {
-		return response.Error("name is required")
-	}
-
 	// Use the appropriate client based on context
-	client, err := h.client.ForContext(params.Context)
+	client, err := h.client.ForContext(ctx, params.Context)
 	if err != nil {
 		if h.alwaysStart && connectivity.IsTransportError(err) {
 			return response.Error(connectivity.ErrorMessage(err))
@@ -267,15 +638,86 @@ func (h *ResourceHandler) GetResource(ctx context.Context, request mcp.CallToolR
 			params.ResourceType, resourcefilter.FormatGVR(gvr))
 	}
 
-	resource, err := client.GetResource(ctx, gvr, params.Namespace, params.Name)
+	subresource := strings.Trim(strings.TrimSpace(params.Subresource), "/")
+	if subresource != "" {
+		if err := checkSubresource(ctx, client, gvr, subresource); err != nil {
+			if h.alwaysStart && connectivity.IsError(err) {
+				return response.Error(connectivity.ErrorMessage(err))
+			}
+			return response.Error(err.Error())
+		}
+	}
+
+	resource, err := client.GetSubresource(ctx, gvr, params.Namespace, params.Name, subresource)
 	if err != nil {
 		if h.alwaysStart && connectivity.IsTransportError(err) {
 			return response.Error(connectivity.ErrorMessage(err))
 		}
+		if subresource != "" {
+			return response.Errorf("failed to get subresource %q: %v", subresource, err)
+		}
 		return response.Errorf("failed to get resource: %v", err)
 	}
 
-	return response.JSON(sanitizeResourceObject(resource.Object, params.IncludeManagedFields))
+	return response.JSON(sanitizeResourceObject(resource.Object, params.IncludeManagedFields, params.IncludeNoisyAnnotations))
+}
+
+// unreadableSubresources are subresources served with get that are not
+// objects, or that open connections into pods and nodes. Logs are read with
+// get_logs instead.
+var unreadableSubresources = map[string]bool{
+	"log":         true,
+	"exec":        true,
+	"attach":      true,
+	"portforward": true,
+	"proxy":       true,
+}
+
+// checkSubresource returns an error unless the cluster serves subresource
+// of gvr with the get verb and it is an object get_resource may return. The
+// error lists the subresources that can be read instead.
+func checkSubresource(ctx context.Context, client kubernetes.ClusterReader, gvr schema.GroupVersionResource, subresource string) error {
+	if unreadableSubresources[subresource] {
+		return fmt.Errorf("subresource %q cannot be read with get_resource: it is not an object, or opens a connection to the workload", subresource)
+	}
+
+	lists, err := client.DiscoverResources(ctx)
+	if err != nil && len(lists) == 0 {
+		return fmt.Errorf("failed to discover subresources: %w", err)
+	}
+
+	// Types resolved to a version other than the preferred one are only
+	// described by the discovery of every version.
+	groupVersion := gvr.GroupVersion().String()
+	if !slices.ContainsFunc(lists, func(list *metav1.APIResourceList) bool { return list != nil && list.GroupVersion == groupVersion }) {
+		if lists, err = client.DiscoverAllResources(ctx); err != nil && len(lists) == 0 {
+			return fmt.Errorf("failed to discover subresources: %w", err)
+		}
+	}
+
+	var available []string
+	for _, list := range lists {
+		if list == nil || list.GroupVersion != groupVersion {
+			continue
+		}
+
+		for _, resource := range list.APIResources {
+			parent, name, ok := strings.Cut(resource.Name, "/")
+			if !ok || parent != gvr.Resource || !slices.Contains(resource.Verbs, "get") || unreadableSubresources[name] {
+				continue
+			}
+			if name == subresource {
+				return nil
+			}
+			available = append(available, name)
+		}
+	}
+
+	if len(available) == 0 {
+		return fmt.Errorf("subresource %q is not served for %s, which has no subresources get_resource can read", subresource, resourcefilter.FormatGVR(gvr))
+	}
+	sort.Strings(available)
+	return fmt.Errorf("subresource %q is not served for %s; available subresources: %s", subresource, resourcefilter.FormatGVR(gvr), strings.Join(available, ", "))
 }
 
 // extractResourceTitle extracts only the resource name for title-only listing operations.
@@ -295,7 +737,7 @@ func extractResourceTitle(resource *unstructured.Unstructured) map[string]interf
 // It returns a lightweight summary containing just metadata, apiVersion, and kind,
 // which is sufficient for most listing and browsing operations while minimizing
 // response size and processing time.
-func extractResourceSummary(resource *unstructured.Unstructured, includeManagedFields bool) map[string]interface{} {
+func extractResourceSummary(resource *unstructured.Unstructured, includeManagedFields, includeNoisyAnnotations bool) map[string]interface{} {
 	summary := make(map[string]interface{})
 
 	if apiVersion := resource.GetAPIVersion(); apiVersion != "" {
@@ -307,14 +749,14 @@ func extractResourceSummary(resource *unstructured.Unstructured, includeManagedF
 	}
 
 	if metadata, ok := resource.Object["metadata"].(map[string]interface{}); ok {
-		summary["metadata"] = sanitizeMetadata(metadata, includeManagedFields)
+		summary["metadata"] = sanitizeMetadata(metadata, includeManagedFields, includeNoisyAnnotations)
 	}
 
 	return summary
 }
 
-func sanitizeResourceObject(resource map[string]interface{}, includeManagedFields bool) map[string]interface{} {
-	if includeManagedFields {
+func sanitizeResourceObject(resource map[string]interface{}, includeManagedFields, includeNoisyAnnotations bool) map[string]interface{} {
+	if includeManagedFields && includeNoisyAnnotations {
 		return resource
 	}
 
@@ -322,7 +764,7 @@ func sanitizeResourceObject(resource map[string]interface{}, includeManagedField
 	for key, value := range resource {
 		if key == "metadata" {
 			if metadata, ok := value.(map[string]interface{}); ok {
-				sanitized[key] = sanitizeMetadata(metadata, false)
+				sanitized[key] = sanitizeMetadata(metadata, includeManagedFields, includeNoisyAnnotations)
 				continue
 			}
 
@@ -336,23 +778,65 @@ func sanitizeResourceObject(resource map[string]interface{}, includeManagedField
 	return sanitized
 }
 
-func sanitizeMetadata(metadata map[string]interface{}, includeManagedFields bool) map[string]interface{} {
-	if includeManagedFields {
+// noisyAnnotations hold a copy of the object as a deployment tool last
+// applied it, which can make up half of a response while repeating what the
+// object already says.
+var noisyAnnotations = map[string]bool{
+	lastAppliedAnnotation:             true,
+	"kapp.k14s.io/original":           true,
+	"objectset.rio.cattle.io/applied": true,
+}
+
+func sanitizeMetadata(metadata map[string]interface{}, includeManagedFields, includeNoisyAnnotations bool) map[string]interface{} {
+	if includeManagedFields && includeNoisyAnnotations {
 		return metadata
 	}
 
 	sanitized := make(map[string]interface{}, len(metadata))
 	for key, value := range metadata {
-		if key == "managedFields" {
+		if key == "managedFields" && !includeManagedFields {
 			continue
 		}
 
+		if key == "annotations" && !includeNoisyAnnotations {
+			if annotations, ok := value.(map[string]interface{}); ok {
+				// Dropping the noisy annotations can leave none behind.
+				if kept := withoutNoisyAnnotations(annotations); len(kept) > 0 {
+					sanitized[key] = kept
+				}
+				continue
+			}
+		}
+
 		sanitized[key] = value
 	}
 
 	return sanitized
 }
 
+// withoutNoisyAnnotations returns a copy of annotations without the ones in
+// noisyAnnotations.
+func withoutNoisyAnnotations(annotations map[string]interface{}) map[string]interface{} {
+	kept := make(map[string]interface{}, len(annotations))
+	for key, value := range annotations {
+		if !noisyAnnotations[key] {
+			kept[key] = value
+		}
+	}
+	return kept
+}
+
+// addPodStatus adds the PodStatusRollup of the pod in resource to a listed
+// item, as its pod_status field. Objects that do not decode as pods are left
+// as they are.
+func addPodStatus(item map[string]interface{}, resource *unstructured.Unstructured, now time.Time) {
+	var pod corev1.Pod
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(resource.Object, &pod); err != nil {
+		return
+	}
+	item["pod_status"] = rollUpPodStatus(&pod, now)
+}
+
 // getCreationTime extracts the creation timestamp from a resource summary for sorting purposes.
 // It safely navigates the metadata structure and parses the RFC3339 timestamp format
 // used by Kubernetes. Returns false if the timestamp is missing or invalid.
@@ -404,20 +888,39 @@ type APIResource struct {
 	Categories []string `json:"categories,omitempty"`
 }
 
+// ListAPIResourcesParams defines the parameters for the list_api_resources MCP tool.
+type ListAPIResourcesParams struct {
+	// TitleOnly when true (default), returns only resource names.
+	// When false, returns complete API resource information.
+	TitleOnly *bool `json:"title_only,omitempty" default:"true" description:"When true (default), returns only resource names. When false, returns complete API resource details"`
+
+	// Refresh runs API discovery again instead of using cached results.
+	Refresh bool `json:"refresh,omitempty" description:"When true, runs API discovery again instead of using cached results, for example right after CRDs were installed or removed"`
+}
+
+// ListAPIResourcesResult is the result of the list_api_resources MCP tool.
+type ListAPIResourcesResult struct {
+	// Resources holds the resource names, or an APIResource for each when
+	// title_only is false.
+	Resources any `json:"resources"`
+	Count     int `json:"count"`
+}
+
 // ListAPIResources implements the list_api_resources MCP tool.
 // It discovers and returns information about all available Kubernetes API resources
 // in the cluster, similar to "kubectl api-resources". This is useful for understanding
 // what resource types are available and their capabilities.
 func (h *ResourceHandler) ListAPIResources(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	var params struct {
-		// TitleOnly when true (default), returns only resource names.
-		// When false, returns complete API resource information.
-		TitleOnly *bool `json:"title_only,omitempty"`
-	}
+	var params ListAPIResourcesParams
 
-	if err := request.BindArguments(&params); err != nil {
+	if err := bindParams(request, &params); err != nil {
 		return response.Errorf("failed to parse arguments: %s", err)
 	}
+
+	if params.Refresh {
+		h.client.InvalidateDiscovery()
+	}
+
 	lists, err := h.client.DiscoverResources(ctx)
 	if err != nil {
 		if h.alwaysStart && connectivity.IsError(err) {
@@ -467,12 +970,7 @@ func (h *ResourceHandler) ListAPIResources(ctx context.Context, request mcp.Call
 
 		sort.Strings(resourceNames)
 
-		result := map[string]interface{}{
-			"resources": resourceNames,
-			"count":     len(resourceNames),
-		}
-
-		return response.JSON(result)
+		return response.JSON(ListAPIResourcesResult{Resources: resourceNames, Count: len(resourceNames)})
 	}
 
 	// Return full API resource information
@@ -510,12 +1008,22 @@ func (h *ResourceHandler) ListAPIResources(ctx context.Context, request mcp.Call
 		return resources[i].Name < resources[j].Name
 	})
 
-	result := map[string]interface{}{
-		"resources": resources,
-		"count":     len(resources),
-	}
+	return response.JSON(ListAPIResourcesResult{Resources: resources, Count: len(resources)})
+}
 
-	return response.JSON(result)
+// ListContextsParams defines the parameters for the list_contexts MCP tool.
+type ListContextsParams struct {
+	// TitleOnly when true (default), returns only context names.
+	// When false, returns complete context information.
+	TitleOnly *bool `json:"title_only,omitempty" default:"true" description:"When true (default), returns only context names. When false, returns complete context information"`
+}
+
+// ListContextsResult is the result of the list_contexts MCP tool.
+type ListContextsResult struct {
+	// Contexts holds the context names, or the details of each when
+	// title_only is false.
+	Contexts any `json:"contexts"`
+	Count    int `json:"count"`
 }
 
 // ListContexts implements the list_contexts MCP tool.
@@ -523,13 +1031,9 @@ func (h *ResourceHandler) ListAPIResources(ctx context.Context, request mcp.Call
 // Kubernetes contexts. This helps users understand what clusters and configurations
 // are available for use with the context parameter in other tools.
 func (h *ResourceHandler) ListContexts(_ context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	var params struct {
-		// TitleOnly when true (default), returns only context names.
-		// When false, returns complete context information.
-		TitleOnly *bool `json:"title_only,omitempty"`
-	}
+	var params ListContextsParams
 
-	if err := request.BindArguments(&params); err != nil {
+	if err := bindParams(request, &params); err != nil {
 		return response.Errorf("failed to parse arguments: %s", err)
 	}
 
@@ -554,21 +1058,11 @@ func (h *ResourceHandler) ListContexts(_ context.Context, request mcp.CallToolRe
 			contextNames = append(contextNames, context.Name)
 		}
 
-		result := map[string]interface{}{
-			"contexts": contextNames,
-			"count":    len(contextNames),
-		}
-
-		return response.JSON(result)
+		return response.JSON(ListContextsResult{Contexts: contextNames, Count: len(contextNames)})
 	}
 
 	// Return complete context information
-	result := map[string]interface{}{
-		"contexts": contexts,
-		"count":    len(contexts),
-	}
-
-	return response.JSON(result)
+	return response.JSON(ListContextsResult{Contexts: contexts, Count: len(contexts)})
 }
 
 // GetTools returns all resource-related MCP tools provided by this handler.
@@ -578,88 +1072,179 @@ func (h *ResourceHandler) GetTools() []MCPTool {
 	return []MCPTool{
 		NewMCPTool(
 			mcp.NewTool("list_resources",
-				mcp.WithDescription("List any Kubernetes resources by type with optional filtering, sorted newest first. Returns only resource names by default (title_only=true), or metadata, apiVersion, and kind when title_only=false. metadata.managedFields is omitted unless include_managed_fields=true."),
-				mcp.WithString("resource_type",
-					mcp.Required(),
-					mcp.Description("The type of resource to list"),
-				),
-				mcp.WithString("api_version",
-					mcp.Description("API version for the resource (e.g., \"v1\", \"apps/v1\"), if not provided, the tool will try to resolve the resource type from the API resources list"),
-				),
-				mcp.WithString("namespace",
-					mcp.Description("Target namespace (leave empty for cluster-scoped resources)"),
-				),
-				mcp.WithString("context",
-					mcp.Description("Kubernetes context to use (defaults to current context from kubeconfig)"),
-				),
-				mcp.WithString("label_selector",
-					mcp.Description("Label selector to filter resources (e.g., \"app=nginx,version=1.0\")"),
-				),
-				mcp.WithString("field_selector",
-					mcp.Description("Field selector to filter resources (e.g., \"status.phase=Running\")"),
-				),
-				mcp.WithNumber("limit",
-					mcp.Description("Maximum number of resources to return (defaults to all)"),
-				),
-				mcp.WithString("continue",
-					mcp.Description("Continue token for pagination (from previous response)"),
-				),
-				mcp.WithBoolean("title_only",
-					mcp.Description("When true (default), returns only resource names. When false, returns metadata, apiVersion, and kind"),
-					mcp.DefaultBool(true),
-				),
-				mcp.WithBoolean("include_managed_fields",
-					mcp.Description("When true, preserves metadata.managedFields in the response. By default these fields are omitted to reduce noise"),
-					mcp.DefaultBool(false),
-				),
+				mcp.WithDescription("List any Kubernetes resources by type with optional filtering, sorted newest first. Returns only resource names by default (title_only=true), or metadata, apiVersion, and kind when title_only=false. metadata.managedFields is omitted unless include_managed_fields=true, and last-applied-configuration annotations unless include_noisy_annotations=true."),
+				toolschema.Input[ListResourcesParams](),
+				toolschema.Output[ListResourcesResult](),
 			),
 			h.ListResources,
 		),
 		NewMCPTool(
 			mcp.NewTool("get_resource",
-				mcp.WithDescription("Get specific resource details. metadata.managedFields is omitted unless include_managed_fields=true."),
-				mcp.WithString("resource_type",
-					mcp.Required(),
-					mcp.Description("The type of resource to get"),
-				),
-				mcp.WithString("name",
-					mcp.Required(),
-					mcp.Description("Resource name"),
-				),
-				mcp.WithString("api_version",
-					mcp.Description("API version for the resource (e.g., \"v1\", \"apps/v1\"), if not provided, the tool will try to resolve the resource type from the API resources list"),
-				),
-				mcp.WithString("namespace",
-					mcp.Description("Target namespace (required for namespaced resources)"),
-				),
-				mcp.WithString("context",
-					mcp.Description("Kubernetes context to use (defaults to current context from kubeconfig)"),
-				),
-				mcp.WithBoolean("include_managed_fields",
-					mcp.Description("When true, preserves metadata.managedFields in the response. By default these fields are omitted to reduce noise"),
-					mcp.DefaultBool(false),
-				),
+				mcp.WithDescription("Get specific resource details. metadata.managedFields is omitted unless include_managed_fields=true, and last-applied-configuration annotations unless include_noisy_annotations=true. Set subresource to get a subresource such as status or scale instead of the whole object."),
+				toolschema.Input[GetResourceParams](),
+				toolschema.Output[Object](),
 			),
 			h.GetResource,
 		),
+		NewMCPTool(
+			mcp.NewTool("get_owner_chain",
+				mcp.WithDescription("Walk the ownerReferences of any resource upward and return the full chain in one call, such as Pod, ReplicaSet, Deployment or Pod, Job, CronJob. Follows the controller reference at each step, flags owners that no longer exist, and returns the top-level controlling object as root. Use it to find the workload that manages a pod."),
+				toolschema.Input[GetOwnerChainParams](),
+				toolschema.Output[GetOwnerChainResult](),
+			),
+			h.GetOwnerChain,
+		),
+		NewMCPTool(
+			mcp.NewTool("get_related_resources",
+				mcp.WithDescription("Map the objects connected to a Service, Pod, or workload in one call. For a Pod or workload: the Services selecting its pods with their EndpointSlices and Ingresses, the ConfigMaps, Secrets (names only), ServiceAccount, and PersistentVolumeClaims its pods use, its controllers, and the HorizontalPodAutoscalers scaling it. For a Service: the pods it selects and their workloads, its EndpointSlices, and the Ingresses routing to it. Each entry explains how it is connected and flags references to objects that do not exist."),
+				toolschema.Input[GetRelatedResourcesParams](),
+				toolschema.Output[GetRelatedResourcesResult](),
+			),
+			h.GetRelatedResources,
+		),
+		NewMCPTool(
+			mcp.NewTool("get_dns_config",
+				mcp.WithDescription("Get a consolidated picture of cluster DNS for troubleshooting: the DNS Service and its cluster IP, the CoreDNS or kube-dns Deployments with their images and ready replicas, NodeLocal DNSCache, the DNS ConfigMaps with the server blocks, plugins, and forward upstreams parsed from the Corefile, and the cluster domain. Given a pod, also returns its dnsPolicy, dnsConfig, and hostAliases and the resolv.conf the kubelet writes for it."),
+				toolschema.Input[GetDNSConfigParams](),
+				toolschema.Output[GetDNSConfigResult](),
+			),
+			h.GetDNSConfig,
+		),
+		NewMCPTool(
+			mcp.NewTool("diff_resource_across_contexts",
+				mcp.WithDescription("Compare the same resource in two kubeconfig contexts, such as staging and production, and return a field-level diff. Ignores server-managed fields like resourceVersion, uid, managedFields, and allocated cluster IPs, and the status stanza unless include_status=true. Lists of named entries like containers and env are matched by name."),
+				toolschema.Input[DiffResourceAcrossContextsParams](),
+				toolschema.Output[DiffResourceAcrossContextsResult](),
+			),
+			h.DiffResourceAcrossContexts,
+		),
 		NewMCPTool(
 			mcp.NewTool("list_api_resources",
 				mcp.WithDescription("List available Kubernetes API resources. Returns only resource names by default (title_only=true), or complete details when title_only=false (similar to kubectl api-resources)"),
-				mcp.WithBoolean("title_only",
-					mcp.Description("When true (default), returns only resource names. When false, returns complete API resource details"),
-					mcp.DefaultBool(true),
-				),
+				toolschema.Input[ListAPIResourcesParams](),
+				toolschema.Output[ListAPIResourcesResult](),
 			),
 			h.ListAPIResources,
 		),
+		NewMCPTool(
+			mcp.NewTool("list_api_versions",
+				mcp.WithDescription("List every API group the cluster serves with its available versions and preferred version. For groups defined by CustomResourceDefinitions, also reports each CRD's served, deprecated, and storage versions and the versions objects may still be stored as (status.storedVersions), flagging what blocks removing an old version. Useful for migration planning beyond what list_api_resources shows"),
+				toolschema.Input[ListAPIVersionsParams](),
+				toolschema.Output[ListAPIVersionsResult](),
+			),
+			h.ListAPIVersions,
+		),
 		NewMCPTool(
 			mcp.NewTool("list_contexts",
 				mcp.WithDescription("List available Kubernetes contexts from the kubeconfig file. Returns only context names by default (title_only=true), or complete context details when title_only=false"),
-				mcp.WithBoolean("title_only",
-					mcp.Description("When true (default), returns only context names. When false, returns complete context information"),
-				),
+				toolschema.Input[ListContextsParams](),
+				toolschema.Output[ListContextsResult](),
 			),
 			h.ListContexts,
 		),
+		NewMCPTool(
+			mcp.NewTool("explain_resource",
+				mcp.WithDescription("Explain the fields of a resource type from the OpenAPI schema published by the server (like kubectl explain), including custom resources. Accepts a dot-separated field path such as deployment.spec.strategy"),
+				toolschema.Input[ExplainResourceParams](),
+				toolschema.Output[ExplainResourceResult](),
+			),
+			h.ExplainResource,
+		),
+		NewMCPTool(
+			mcp.NewTool("validate_manifest",
+				mcp.WithDescription("Validate a YAML or JSON manifest (one or more documents) against the OpenAPI schemas the cluster publishes, including custom resources with structural schemas, before the user applies it. Reports unknown fields, type mismatches, missing required fields, and unsupported enum values with their field paths. Runs entirely client-side: nothing is written and no server-side dry run is performed, so admission webhooks and defaulting are not evaluated"),
+				toolschema.Input[ValidateManifestParams](),
+				toolschema.Output[ValidateManifestResult](),
+			),
+			h.ValidateManifest,
+		),
+		NewMCPTool(
+			mcp.NewTool("diff_manifest",
+				mcp.WithDescription("Preview what \"kubectl apply\" would change, without applying anything. Takes a YAML or JSON manifest (one or more documents), reads each live object, and returns whether it would be created, configured, or left unchanged, with a field-level diff where a is the live value and b the manifest value. Status, managedFields, and other server-managed fields are ignored; fields the manifest leaves out are kept, as apply does, unless the last-applied-configuration annotation shows they would be removed. Server-side defaulting and admission webhooks are not simulated"),
+				toolschema.Input[DiffManifestParams](),
+				toolschema.Output[DiffManifestResult](),
+			),
+			h.DiffManifest,
+		),
+		NewMCPTool(
+			mcp.NewTool("check_deprecated_apis",
+				mcp.WithDescription("Check for deprecated and removed Kubernetes API versions before an upgrade. Reports deprecated API versions the cluster still serves and objects last applied or written through them (from the last-applied-configuration annotation and managedFields), flagging what breaks by the target version"),
+				toolschema.Input[CheckDeprecatedAPIsParams](),
+				toolschema.Output[CheckDeprecatedAPIsResult](),
+			),
+			h.CheckDeprecatedAPIs,
+		),
+		NewMCPTool(
+			mcp.NewTool("migration_targets",
+				mcp.WithDescription("Explain how to migrate a deprecated API version of a kind: the replacement API version, the field-level changes between the two versions, and the objects last applied or written through the deprecated version, with their namespaces"),
+				toolschema.Input[MigrationTargetsParams](),
+				toolschema.Output[MigrationTargetsResult](),
+			),
+			h.MigrationTargets,
+		),
+		NewMCPTool(
+			mcp.NewTool("count_custom_resources",
+				mcp.WithDescription("Count the instances of every installed CustomResourceDefinition, or a single one, per namespace, using paginated metadata-only lists so even very large collections are cheap to count. Flags CRDs without instances, which point at unused operators, and CRDs at or above a threshold, which point at controllers creating objects without cleaning them up"),
+				toolschema.Input[CountCustomResourcesParams](),
+				toolschema.Output[CountCustomResourcesResult](),
+			),
+			h.CountCustomResources,
+		),
+		NewMCPTool(
+			mcp.NewTool("find_unused_config",
+				mcp.WithDescription("Find the ConfigMaps and Secrets of a namespace that nothing appears to reference, by cross-referencing them against pod and workload volumes (including projected volumes), envFrom, env valueFrom, image pull secrets, Ingress TLS, and ServiceAccount secrets. Objects the control plane, ServiceAccount token controller, or Helm manage, and objects owned by another object, are excluded. Secret values are never read"),
+				toolschema.Input[FindUnusedConfigParams](),
+				toolschema.Output[FindUnusedConfigResult](),
+			),
+			h.FindUnusedConfig,
+		),
+		NewMCPTool(
+			mcp.NewTool("check_certificates",
+				mcp.WithDescription("Inspect the certificates in kubernetes.io/tls Secrets: subject, subject alternative names, issuer, validity dates, and days until expiry for the certificate and its chain. Flags certificates that are expired, not yet valid, or expire within a configurable window, certificates without subject alternative names, and private keys that do not match. Private keys are never returned"),
+				toolschema.Input[CheckCertificatesParams](),
+				toolschema.Output[CheckCertificatesResult](),
+			),
+			h.CheckCertificates,
+		),
+		NewMCPTool(
+			mcp.NewTool("get_cert_manager_status",
+				mcp.WithDescription("Summarize cert-manager Certificates, CertificateRequests, and ACME Challenges: readiness, issuer, expiry and renewal times, issuance failures, and the messages explaining them. Flags certificates that are not ready or overdue for renewal, requests that failed, were denied, or are not approved, and challenges that are invalid or stuck. Reports that cert-manager is not installed instead of failing when its CRDs are missing"),
+				toolschema.Input[GetCertManagerStatusParams](),
+				toolschema.Output[GetCertManagerStatusResult](),
+			),
+			h.GetCertManagerStatus,
+		),
+		NewMCPTool(
+			mcp.NewTool("get_gitops_status",
+				mcp.WithDescription("Read-only view of GitOps drift. Detects Argo CD and Flux through their CRDs and reports the sync and health status, source, revision, last reconcile time, and error messages of Argo CD Applications, Flux Kustomizations, and Flux HelmReleases. Flags applications that are OutOfSync (listing the drifted resources), degraded, failing to sync, suspended, or stuck on a revision that failed to apply. Reports that neither tool is installed instead of failing when their CRDs are missing"),
+				toolschema.Input[GetGitOpsStatusParams](),
+				toolschema.Output[GetGitOpsStatusResult](),
+			),
+			h.GetGitOpsStatus,
+		),
+		NewMCPTool(
+			mcp.NewTool("search_by_label",
+				mcp.WithDescription("Find everything carrying a label across resource types at once, such as \"show me everything with app=payments\". Lists the given types, or every namespaced type the cluster serves except events, concurrently through metadata-only lists, and returns the matching names grouped by kind, capped per type. Types that cannot be listed are reported as warnings"),
+				toolschema.Input[SearchByLabelParams](),
+				toolschema.Output[SearchByLabelResult](),
+			),
+			h.SearchByLabel,
+		),
+		NewMCPTool(
+			mcp.NewTool("find_resource",
+				mcp.WithDescription("Find resources by part of their name, or a regular expression, across all namespaces when the namespace is not known. Searches a single resource type, or the common types (pods, deployments, statefulsets, daemonsets, jobs, cronjobs, services, ingresses, configmaps, secrets, persistentvolumeclaims, and serviceaccounts), and returns the kind, namespace, and name of each match, exact names first"),
+				toolschema.Input[FindResourceParams](),
+				toolschema.Output[FindResourceResult](),
+			),
+			h.FindResource,
+		),
+		NewMCPTool(
+			mcp.NewTool("get_namespace_overview",
+				mcp.WithDescription("Inventory of a namespace in one call, like \"kubectl get all\" plus configuration and storage: counts and a brief status of its Deployments, StatefulSets, DaemonSets, Jobs, CronJobs, pods, Services, Ingresses, PersistentVolumeClaims, ConfigMaps, and Secrets (metadata only). Objects needing attention, such as crashing pods, unavailable workloads, failed Jobs, pending LoadBalancers, and unbound PVCs, are flagged and listed first"),
+				toolschema.Input[GetNamespaceOverviewParams](),
+				toolschema.Output[GetNamespaceOverviewResult](),
+			),
+			h.GetNamespaceOverview,
+		),
 	}
 }
@@ -1,20 +1,30 @@
 package handlers
 
 import (
+	"fmt"
 	"reflect"
+	"sort"
+	"strings"
 	"testing"
+	"time"
 
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/fakecluster"
 )
 
 func TestSanitizeMetadata(t *testing.T) {
 	t.Parallel()
 
 	tests := []struct {
-		name                 string
-		metadata             map[string]interface{}
-		includeManagedFields bool
-		want                 map[string]interface{}
+		name                    string
+		metadata                map[string]interface{}
+		includeManagedFields    bool
+		includeNoisyAnnotations bool
+		want                    map[string]interface{}
 	}{
 		{
 			name: "strips managed fields by default",
@@ -76,6 +86,51 @@ func TestSanitizeMetadata(t *testing.T) {
 				"creationTimestamp": "2026-03-11T12:00:00Z",
 			},
 		},
+		{
+			name: "strips noisy annotations by default",
+			metadata: map[string]interface{}{
+				"name": "demo-pod",
+				"annotations": map[string]interface{}{
+					"kubectl.kubernetes.io/last-applied-configuration": `{"apiVersion":"v1","kind":"Pod"}`,
+					"team": "payments",
+				},
+			},
+			want: map[string]interface{}{
+				"name": "demo-pod",
+				"annotations": map[string]interface{}{
+					"team": "payments",
+				},
+			},
+		},
+		{
+			name: "drops annotations left empty",
+			metadata: map[string]interface{}{
+				"name": "demo-pod",
+				"annotations": map[string]interface{}{
+					"kapp.k14s.io/original": `{"apiVersion":"v1","kind":"Pod"}`,
+				},
+			},
+			want: map[string]interface{}{
+				"name": "demo-pod",
+			},
+		},
+		{
+			name: "preserves noisy annotations when requested",
+			metadata: map[string]interface{}{
+				"name": "demo-pod",
+				"annotations": map[string]interface{}{
+					"kubectl.kubernetes.io/last-applied-configuration": `{"apiVersion":"v1","kind":"Pod"}`,
+				},
+				"managedFields": []interface{}{},
+			},
+			includeNoisyAnnotations: true,
+			want: map[string]interface{}{
+				"name": "demo-pod",
+				"annotations": map[string]interface{}{
+					"kubectl.kubernetes.io/last-applied-configuration": `{"apiVersion":"v1","kind":"Pod"}`,
+				},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -83,7 +138,7 @@ func TestSanitizeMetadata(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 
-			got := sanitizeMetadata(tt.metadata, tt.includeManagedFields)
+			got := sanitizeMetadata(tt.metadata, tt.includeManagedFields, tt.includeNoisyAnnotations)
 			if !reflect.DeepEqual(got, tt.want) {
 				t.Fatalf("sanitizeMetadata() mismatch\nwant: %#v\ngot:  %#v", tt.want, got)
 			}
@@ -95,10 +150,11 @@ func TestSanitizeResourceObject(t *testing.T) {
 	t.Parallel()
 
 	tests := []struct {
-		name                 string
-		resource             map[string]interface{}
-		includeManagedFields bool
-		want                 map[string]interface{}
+		name                    string
+		resource                map[string]interface{}
+		includeManagedFields    bool
+		includeNoisyAnnotations bool
+		want                    map[string]interface{}
 	}{
 		{
 			name: "strips managed fields from metadata by default",
@@ -187,6 +243,27 @@ func TestSanitizeResourceObject(t *testing.T) {
 				"metadata":   "unexpected",
 			},
 		},
+		{
+			name: "strips noisy annotations while preserving managed fields",
+			resource: map[string]interface{}{
+				"apiVersion": "v1",
+				"metadata": map[string]interface{}{
+					"name": "demo-pod",
+					"annotations": map[string]interface{}{
+						"kubectl.kubernetes.io/last-applied-configuration": `{"apiVersion":"v1","kind":"Pod"}`,
+					},
+					"managedFields": []interface{}{},
+				},
+			},
+			includeManagedFields: true,
+			want: map[string]interface{}{
+				"apiVersion": "v1",
+				"metadata": map[string]interface{}{
+					"name":          "demo-pod",
+					"managedFields": []interface{}{},
+				},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -194,7 +271,7 @@ func TestSanitizeResourceObject(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 
-			got := sanitizeResourceObject(tt.resource, tt.includeManagedFields)
+			got := sanitizeResourceObject(tt.resource, tt.includeManagedFields, tt.includeNoisyAnnotations)
 			if !reflect.DeepEqual(got, tt.want) {
 				t.Fatalf("sanitizeResourceObject() mismatch\nwant: %#v\ngot:  %#v", tt.want, got)
 			}
@@ -223,3 +300,454 @@ func TestExtractResourceTitleIsUnchanged(t *testing.T) {
 		t.Fatalf("extractResourceTitle() mismatch\nwant: %#v\ngot:  %#v", want, title)
 	}
 }
+
+func TestListResources_FakeCluster(t *testing.T) {
+	t.Parallel()
+
+	client := fakecluster.New(fakecluster.Config{
+		Objects: []runtime.Object{
+			&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "shop", Labels: map[string]string{"app": "web"}}},
+			&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "db-0", Namespace: "shop", Labels: map[string]string{"app": "db"}}},
+			&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "default"}},
+		},
+	})
+	handler := NewResourceHandler(client, nil, false)
+
+	tests := []struct {
+		name      string
+		args      map[string]any
+		wantNames []string
+		wantErr   bool
+	}{
+		{
+			name:      "namespace",
+			args:      map[string]any{"resource_type": "pods", "namespace": "shop"},
+			wantNames: []string{"db-0", "web-1"},
+		},
+		{
+			name:      "short name and label selector",
+			args:      map[string]any{"resource_type": "po", "namespace": "shop", "label_selector": "app=web"},
+			wantNames: []string{"web-1"},
+		},
+		{
+			name:    "unknown resource type",
+			args:    map[string]any{"resource_type": "widgets"},
+			wantErr: true,
+		},
+		{
+			name:    "missing resource type",
+			args:    map[string]any{},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			result, isErr := callTool(t, handler.ListResources, tt.args)
+			if isErr != tt.wantErr {
+				t.Fatalf("expected error=%v, got result %v", tt.wantErr, result)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			items, _ := result["items"].([]any)
+			names := make([]string, 0, len(items))
+			for _, item := range items {
+				name, _ := item.(map[string]any)["name"].(string)
+				names = append(names, name)
+			}
+			sort.Strings(names)
+
+			if !reflect.DeepEqual(names, tt.wantNames) {
+				t.Errorf("expected names %v, got %v", tt.wantNames, names)
+			}
+		})
+	}
+}
+
+func TestGetResource_FakeCluster(t *testing.T) {
+	t.Parallel()
+
+	client := fakecluster.New(fakecluster.Config{
+		Objects: []runtime.Object{
+			&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:          "settings",
+					Namespace:     "shop",
+					ManagedFields: []metav1.ManagedFieldsEntry{{Manager: "kubectl"}},
+				},
+				Data: map[string]string{"mode": "fast"},
+			},
+		},
+	})
+	handler := NewResourceHandler(client, nil, false)
+
+	result, isErr := callTool(t, handler.GetResource, map[string]any{
+		"resource_type": "configmap",
+		"namespace":     "shop",
+		"name":          "settings",
+	})
+	if isErr {
+		t.Fatalf("unexpected error: %v", result["error"])
+	}
+
+	metadata, _ := result["metadata"].(map[string]any)
+	if _, ok := metadata["managedFields"]; ok {
+		t.Error("expected managedFields to be stripped by default")
+	}
+
+	data, _ := result["data"].(map[string]any)
+	if data["mode"] != "fast" {
+		t.Errorf("expected data.mode=fast, got %v", data["mode"])
+	}
+
+	if _, isErr := callTool(t, handler.GetResource, map[string]any{
+		"resource_type": "configmap",
+		"namespace":     "shop",
+		"name":          "missing",
+	}); !isErr {
+		t.Error("expected an error for a missing resource")
+	}
+}
+
+func TestListResources_AcrossNamespaces(t *testing.T) {
+	t.Parallel()
+
+	client := fakecluster.New(fakecluster.Config{
+		Namespace: "shop",
+		Objects: []runtime.Object{
+			&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "shop"}},
+			&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "db-0", Namespace: "shop"}},
+			&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "invoicer", Namespace: "billing"}},
+			&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "coredns", Namespace: "kube-system"}},
+		},
+	})
+	handler := NewResourceHandler(client, nil, false)
+
+	titles := func(result map[string]any) []string {
+		items, _ := result["items"].([]any)
+		names := make([]string, 0, len(items))
+		for _, item := range items {
+			fields, _ := item.(map[string]any)
+			names = append(names, fmt.Sprintf("%v/%v", fields["namespace"], fields["name"]))
+		}
+		return names
+	}
+
+	// The default namespace applies when no namespace is given.
+	result, isErr := callTool(t, handler.ListResources, map[string]any{"resource_type": "pods"})
+	if isErr {
+		t.Fatalf("unexpected error: %v", result["error"])
+	}
+	if result["count"] != float64(2) {
+		t.Errorf("expected the default namespace's 2 pods, got %v", result["count"])
+	}
+
+	result, isErr = callTool(t, handler.ListResources, map[string]any{"resource_type": "pods", "all_namespaces": true})
+	if isErr {
+		t.Fatalf("unexpected error: %v", result["error"])
+	}
+	got := titles(result)
+	sort.Strings(got)
+	if want := []string{"billing/invoicer", "kube-system/coredns", "shop/db-0", "shop/web-1"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected every namespace's pods %v, got %v", want, got)
+	}
+
+	result, isErr = callTool(t, handler.ListResources, map[string]any{"resource_type": "pods", "namespace": "shop, billing"})
+	if isErr {
+		t.Fatalf("unexpected error: %v", result["error"])
+	}
+	if want := []string{"billing/invoicer", "shop/db-0", "shop/web-1"}; !reflect.DeepEqual(titles(result), want) {
+		t.Errorf("expected the pods of both namespaces %v, got %v", want, titles(result))
+	}
+
+	// Pages of a merged listing continue with this server's tokens.
+	args := map[string]any{"resource_type": "pods", "namespace": "shop,billing", "limit": 2}
+	result, isErr = callTool(t, handler.ListResources, args)
+	if isErr {
+		t.Fatalf("unexpected error: %v", result["error"])
+	}
+	token, _ := result["continue"].(string)
+	if token == "" || !reflect.DeepEqual(titles(result), []string{"billing/invoicer", "shop/db-0"}) {
+		t.Fatalf("expected a first page of 2 with a continue token, got %v", result)
+	}
+
+	args["continue"] = token
+	result, isErr = callTool(t, handler.ListResources, args)
+	if isErr {
+		t.Fatalf("unexpected error: %v", result["error"])
+	}
+	if _, more := result["continue"]; more || !reflect.DeepEqual(titles(result), []string{"shop/web-1"}) {
+		t.Errorf("expected the last page, got %v", result)
+	}
+
+	if result, isErr := callTool(t, handler.ListResources, map[string]any{"resource_type": "pods", "namespace": "shop", "all_namespaces": true}); !isErr {
+		t.Errorf("expected namespace and all_namespaces together to be rejected, got %v", result)
+	}
+}
+
+func TestListResources_SortBy(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	pod := func(name string, age time.Duration) *corev1.Pod {
+		return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "shop", CreationTimestamp: metav1.NewTime(now.Add(-age))}}
+	}
+	client := fakecluster.New(fakecluster.Config{
+		Objects: []runtime.Object{
+			pod("web-2", time.Minute),
+			pod("web-1", time.Hour),
+			pod("db-0", 24*time.Hour),
+			pod("cache-0", time.Hour),
+		},
+	})
+	handler := NewResourceHandler(client, nil, false)
+
+	// Pages through the sorted listing until the server stops issuing
+	// continue tokens, and returns the names in the order received.
+	pages := func(args map[string]any) []string {
+		t.Helper()
+
+		var names []string
+		for {
+			result, isErr := callTool(t, handler.ListResources, args)
+			if isErr {
+				t.Fatalf("unexpected error: %v", result["error"])
+			}
+			items, _ := result["items"].([]any)
+			for _, item := range items {
+				fields, _ := item.(map[string]any)
+				names = append(names, fmt.Sprint(fields["name"]))
+			}
+
+			token, _ := result["continue"].(string)
+			if token == "" {
+				return names
+			}
+			args["continue"] = token
+		}
+	}
+
+	tests := []struct {
+		sortBy string
+		want   []string
+	}{
+		{"oldest", []string{"db-0", "cache-0", "web-1", "web-2"}},
+		{"newest", []string{"web-2", "cache-0", "web-1", "db-0"}},
+		{"name", []string{"cache-0", "db-0", "web-1", "web-2"}},
+	}
+	for _, tt := range tests {
+		got := pages(map[string]any{"resource_type": "pods", "namespace": "shop", "sort_by": tt.sortBy, "limit": 1})
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("expected pages sorted by %s %v, got %v", tt.sortBy, tt.want, got)
+		}
+	}
+
+	// A token is only valid for the order it was issued for.
+	result, _ := callTool(t, handler.ListResources, map[string]any{"resource_type": "pods", "namespace": "shop", "sort_by": "oldest", "limit": 1})
+	token, _ := result["continue"].(string)
+	if result, isErr := callTool(t, handler.ListResources, map[string]any{"resource_type": "pods", "namespace": "shop", "sort_by": "name", "limit": 1, "continue": token}); !isErr {
+		t.Errorf("expected a token of another order to be rejected, got %v", result)
+	}
+
+	// Nor for other selectors.
+	if result, isErr := callTool(t, handler.ListResources, map[string]any{"resource_type": "pods", "namespace": "shop", "sort_by": "oldest", "limit": 1, "label_selector": "app=web", "continue": token}); !isErr {
+		t.Errorf("expected a token issued without a selector to be rejected, got %v", result)
+	}
+
+	if result, isErr := callTool(t, handler.ListResources, map[string]any{"resource_type": "pods", "sort_by": "size"}); !isErr {
+		t.Errorf("expected an unknown sort_by to be rejected, got %v", result)
+	}
+
+	// The next page starts after the last item returned, even when the pods
+	// before it are deleted and new ones are created in between.
+	args := map[string]any{"resource_type": "pods", "namespace": "shop", "sort_by": "oldest", "limit": 2}
+	result, _ = callTool(t, handler.ListResources, args)
+	args["continue"] = result["continue"]
+
+	changed := NewResourceHandler(fakecluster.New(fakecluster.Config{
+		Objects: []runtime.Object{
+			pod("web-2", time.Minute),
+			pod("web-1", time.Hour),
+			pod("web-3", time.Second),
+			pod("db-1", 48*time.Hour),
+		},
+	}), nil, false)
+	result, isErr := callTool(t, changed.ListResources, args)
+	if isErr {
+		t.Fatalf("unexpected error: %v", result["error"])
+	}
+
+	var names []string
+	for _, item := range result["items"].([]any) {
+		names = append(names, fmt.Sprint(item.(map[string]any)["name"]))
+	}
+	if want := []string{"web-1", "web-2"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("expected the page after cache-0 to be %v, got %v", want, names)
+	}
+}
+
+func TestListResources_PodStatus(t *testing.T) {
+	t.Parallel()
+
+	client := fakecluster.New(fakecluster.Config{
+		Objects: []runtime.Object{
+			&corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "shop"},
+				Spec:       corev1.PodSpec{NodeName: "worker-1", Containers: []corev1.Container{{Name: "app"}}},
+				Status: corev1.PodStatus{
+					Phase: corev1.PodRunning,
+					ContainerStatuses: []corev1.ContainerStatus{
+						{Name: "app", RestartCount: 4, State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}}},
+					},
+				},
+			},
+		},
+	})
+	handler := NewResourceHandler(client, nil, false)
+
+	for _, args := range []map[string]any{
+		{"resource_type": "pods", "namespace": "shop", "pod_status": true},
+		{"resource_type": "pods", "namespace": "shop", "pod_status": true, "title_only": false},
+		{"resource_type": "pods", "namespace": "shop", "pod_status": true, "columns": "metadata.name"},
+		{"resource_type": "pods", "namespace": "shop,billing", "pod_status": true},
+	} {
+		result, isErr := callTool(t, handler.ListResources, args)
+		if isErr {
+			t.Fatalf("unexpected error for %v: %v", args, result["error"])
+		}
+
+		items, _ := result["items"].([]any)
+		if len(items) != 1 {
+			t.Fatalf("expected 1 pod for %v, got %v", args, items)
+		}
+		status, _ := items[0].(map[string]any)["pod_status"].(map[string]any)
+		if status["ready"] != "0/1" || status["status"] != "CrashLoopBackOff" || status["restarts"] != float64(4) || status["node"] != "worker-1" {
+			t.Errorf("expected the pod status of web-1 for %v, got %v", args, status)
+		}
+	}
+
+	if result, isErr := callTool(t, handler.ListResources, map[string]any{"resource_type": "services", "pod_status": true}); !isErr {
+		t.Errorf("expected pod_status to be rejected for services, got %v", result)
+	}
+}
+
+func TestListResources_IncludeSpec(t *testing.T) {
+	t.Parallel()
+
+	client := fakecluster.New(fakecluster.Config{
+		Objects: []runtime.Object{
+			&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "settings", Namespace: "shop", ManagedFields: []metav1.ManagedFieldsEntry{{Manager: "kubectl"}}},
+				Data:       map[string]string{"mode": "fast"},
+			},
+			&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "ledger", Namespace: "billing"},
+				Data:       map[string]string{"currency": "EUR"},
+			},
+		},
+	})
+	handler := NewResourceHandler(client, nil, false)
+
+	for _, namespace := range []string{"shop", "shop,billing"} {
+		result, isErr := callTool(t, handler.ListResources, map[string]any{"resource_type": "configmaps", "namespace": namespace, "include_spec": true})
+		if isErr {
+			t.Fatalf("unexpected error: %v", result["error"])
+		}
+
+		items, _ := result["items"].([]any)
+		var settings map[string]any
+		for _, item := range items {
+			object, _ := item.(map[string]any)
+			if metadata, _ := object["metadata"].(map[string]any); metadata["name"] == "settings" {
+				settings = object
+				if _, ok := metadata["managedFields"]; ok {
+					t.Error("expected managedFields to be stripped by default")
+				}
+			}
+		}
+		if data, _ := settings["data"].(map[string]any); data["mode"] != "fast" {
+			t.Errorf("expected the complete settings ConfigMap in namespace %q, got %v", namespace, items)
+		}
+	}
+
+	for _, args := range []map[string]any{
+		{"resource_type": "configmaps", "include_spec": true, "limit": fullObjectsMaxLimit + 1},
+		{"resource_type": "configmaps", "include_spec": true, "columns": "metadata.name"},
+	} {
+		if result, isErr := callTool(t, handler.ListResources, args); !isErr {
+			t.Errorf("expected %v to be rejected, got %v", args, result)
+		}
+	}
+}
+
+func TestGetResource_Subresource(t *testing.T) {
+	t.Parallel()
+
+	apiResources := fakecluster.DefaultAPIResources()
+	for _, list := range apiResources {
+		switch list.GroupVersion {
+		case "v1":
+			list.APIResources = append(list.APIResources,
+				metav1.APIResource{Name: "pods/log", Namespaced: true, Kind: "Pod", Verbs: []string{"get"}},
+				metav1.APIResource{Name: "pods/status", Namespaced: true, Kind: "Pod", Verbs: []string{"get", "patch", "update"}},
+			)
+		case "apps/v1":
+			list.APIResources = append(list.APIResources,
+				metav1.APIResource{Name: "deployments/scale", Namespaced: true, Group: "autoscaling", Version: "v1", Kind: "Scale", Verbs: []string{"get", "patch", "update"}},
+				metav1.APIResource{Name: "deployments/status", Namespaced: true, Kind: "Deployment", Verbs: []string{"get", "patch", "update"}},
+			)
+		}
+	}
+
+	client := fakecluster.New(fakecluster.Config{
+		APIResources: apiResources,
+		Objects: []runtime.Object{
+			&corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "shop"},
+				Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+			},
+		},
+	})
+	handler := NewResourceHandler(client, nil, false)
+
+	result, isErr := callTool(t, handler.GetResource, map[string]any{
+		"resource_type": "pods",
+		"namespace":     "shop",
+		"name":          "web-1",
+		"subresource":   "status",
+	})
+	if isErr {
+		t.Fatalf("unexpected error: %v", result["error"])
+	}
+	if status, _ := result["status"].(map[string]any); status["phase"] != "Running" {
+		t.Errorf("expected the pod status, got %v", result)
+	}
+
+	tests := []struct {
+		resourceType string
+		subresource  string
+		want         string
+	}{
+		{"pods", "scale", `subresource "scale" is not served for core/v1/pods; available subresources: status`},
+		{"pods", "log", `subresource "log" cannot be read with get_resource`},
+		{"pods", "exec", `subresource "exec" cannot be read with get_resource`},
+		{"deployments", "rollback", `available subresources: scale, status`},
+		{"configmaps", "status", "which has no subresources get_resource can read"},
+	}
+	for _, tt := range tests {
+		result, isErr := callTool(t, handler.GetResource, map[string]any{
+			"resource_type": tt.resourceType,
+			"namespace":     "shop",
+			"name":          "web-1",
+			"subresource":   tt.subresource,
+		})
+		if errMessage, _ := result["error"].(string); !isErr || !strings.Contains(errMessage, tt.want) {
+			t.Errorf("expected %s/%s to be rejected with %q, got %v", tt.resourceType, tt.subresource, tt.want, result)
+		}
+	}
+}
@@ -0,0 +1,356 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	appsv1 "k8s.io/api/apps/v1"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/connectivity"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/response"
+)
+
+// Rollout states reported by get_rollout_status.
+const (
+	rolloutComplete    = "complete"
+	rolloutProgressing = "progressing"
+	rolloutPaused      = "paused"
+	rolloutStuck       = "stuck"
+)
+
+// GetRolloutStatusParams defines the parameters for the get_rollout_status MCP tool.
+type GetRolloutStatusParams struct {
+	// ResourceType is the kind of workload: deployments, statefulsets, or
+	// daemonsets, including their singular and short forms.
+	ResourceType string `json:"resource_type" required:"true" description:"Workload type: deployment, statefulset, or daemonset (plural and short names such as deploy, sts, and ds also work)"`
+
+	// Namespace specifies the workload's namespace.
+	Namespace string `json:"namespace" required:"true" description:"Workload namespace"`
+
+	// Name specifies which workload to check.
+	Name string `json:"name" required:"true" description:"Workload name"`
+
+	// Context specifies which Kubernetes context to use for this operation.
+	Context string `json:"context,omitempty" description:"Kubernetes context to use (defaults to current context from kubeconfig)"`
+}
+
+// RolloutReplicas are the replica counts of a rollout. For DaemonSets they
+// count the nodes that should run a daemon pod.
+type RolloutReplicas struct {
+	Desired     int32 `json:"desired"`
+	Current     int32 `json:"current"`
+	Updated     int32 `json:"updated"`
+	Ready       int32 `json:"ready"`
+	Available   int32 `json:"available"`
+	Unavailable int32 `json:"unavailable"`
+}
+
+// RolloutCondition is a status condition of a workload controller.
+type RolloutCondition struct {
+	Type               string `json:"type"`
+	Status             string `json:"status"`
+	Reason             string `json:"reason,omitempty"`
+	Message            string `json:"message,omitempty"`
+	LastUpdate         string `json:"last_update,omitempty"`
+	LastTransitionTime string `json:"last_transition_time,omitempty"`
+}
+
+// RolloutStatus describes where the rollout of a workload stands.
+type RolloutStatus struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+
+	// Status is complete, progressing, paused, or stuck.
+	Status string `json:"status"`
+	Done   bool   `json:"done"`
+
+	// Message mirrors the output of "kubectl rollout status".
+	Message string `json:"message"`
+
+	Strategy           string          `json:"strategy"`
+	Generation         int64           `json:"generation"`
+	ObservedGeneration int64           `json:"observed_generation"`
+	Replicas           RolloutReplicas `json:"replicas"`
+
+	// CurrentRevision and UpdateRevision are the controller revisions of a
+	// StatefulSet's old and new pods.
+	CurrentRevision string `json:"current_revision,omitempty"`
+	UpdateRevision  string `json:"update_revision,omitempty"`
+
+	Conditions []RolloutCondition `json:"conditions"`
+}
+
+// GetRolloutStatus implements the get_rollout_status MCP tool.
+// It reads a Deployment, StatefulSet, or DaemonSet once and evaluates its
+// rollout with the same rules as "kubectl rollout status", without waiting
+// for the rollout to finish. A Deployment whose Progressing condition reports
+// ProgressDeadlineExceeded is reported as stuck.
+func (h *WorkloadHandler) GetRolloutStatus(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var params GetRolloutStatusParams
+	if err := bindParams(request, &params); err != nil {
+		return response.Errorf("failed to parse arguments: %s", err)
+	}
+
+	kind, ok := rolloutKind(params.ResourceType)
+	if !ok {
+		return response.Errorf("unsupported resource type %q: rollout status is available for deployments, statefulsets, and daemonsets", params.ResourceType)
+	}
+
+	// Use the appropriate client based on context
+	client, err := h.client.ForContext(ctx, params.Context)
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to create client with context %s: %v", params.Context, err)
+	}
+
+	var status RolloutStatus
+	switch kind {
+	case "Deployment":
+		deployment, err := client.GetDeployment(ctx, params.Namespace, params.Name)
+		if err != nil {
+			if h.alwaysStart && connectivity.IsTransportError(err) {
+				return response.Error(connectivity.ErrorMessage(err))
+			}
+			return response.Errorf("failed to get deployment: %v", err)
+		}
+		status = deploymentRolloutStatus(deployment)
+	case "StatefulSet":
+		statefulSet, err := client.GetStatefulSet(ctx, params.Namespace, params.Name)
+		if err != nil {
+			if h.alwaysStart && connectivity.IsTransportError(err) {
+				return response.Error(connectivity.ErrorMessage(err))
+			}
+			return response.Errorf("failed to get statefulset: %v", err)
+		}
+		status = statefulSetRolloutStatus(statefulSet)
+	case "DaemonSet":
+		daemonSet, err := client.GetDaemonSet(ctx, params.Namespace, params.Name)
+		if err != nil {
+			if h.alwaysStart && connectivity.IsTransportError(err) {
+				return response.Error(connectivity.ErrorMessage(err))
+			}
+			return response.Errorf("failed to get daemonset: %v", err)
+		}
+		status = daemonSetRolloutStatus(daemonSet)
+	}
+
+	return response.JSON(status)
+}
+
+// rolloutKind maps the resource type names users pass to the workload kinds
+// that support rollouts.
+func rolloutKind(resourceType string) (string, bool) {
+	switch strings.ToLower(resourceType) {
+	case "deployment", "deployments", "deploy":
+		return "Deployment", true
+	case "statefulset", "statefulsets", "sts":
+		return "StatefulSet", true
+	case "daemonset", "daemonsets", "ds":
+		return "DaemonSet", true
+	default:
+		return "", false
+	}
+}
+
+// deploymentRolloutStatus evaluates a Deployment rollout the way
+// "kubectl rollout status" does, and additionally reports paused rollouts.
+func deploymentRolloutStatus(deployment *appsv1.Deployment) RolloutStatus {
+	desired := int32(1)
+	if deployment.Spec.Replicas != nil {
+		desired = *deployment.Spec.Replicas
+	}
+
+	status := RolloutStatus{
+		Kind:               "Deployment",
+		Namespace:          deployment.Namespace,
+		Name:               deployment.Name,
+		Strategy:           string(deployment.Spec.Strategy.Type),
+		Generation:         deployment.Generation,
+		ObservedGeneration: deployment.Status.ObservedGeneration,
+		Replicas: RolloutReplicas{
+			Desired:     desired,
+			Current:     deployment.Status.Replicas,
+			Updated:     deployment.Status.UpdatedReplicas,
+			Ready:       deployment.Status.ReadyReplicas,
+			Available:   deployment.Status.AvailableReplicas,
+			Unavailable: deployment.Status.UnavailableReplicas,
+		},
+		Conditions: make([]RolloutCondition, 0, len(deployment.Status.Conditions)),
+	}
+
+	var progressDeadlineExceeded bool
+	for _, condition := range deployment.Status.Conditions {
+		status.Conditions = append(status.Conditions, RolloutCondition{
+			Type:               string(condition.Type),
+			Status:             string(condition.Status),
+			Reason:             condition.Reason,
+			Message:            condition.Message,
+			LastUpdate:         formatTime(condition.LastUpdateTime.Time),
+			LastTransitionTime: formatTime(condition.LastTransitionTime.Time),
+		})
+
+		if condition.Type == appsv1.DeploymentProgressing && condition.Reason == "ProgressDeadlineExceeded" {
+			progressDeadlineExceeded = true
+		}
+	}
+
+	replicas := status.Replicas
+	switch {
+	case deployment.Generation > deployment.Status.ObservedGeneration:
+		status.Status = rolloutProgressing
+		status.Message = "Waiting for deployment spec update to be observed..."
+	case progressDeadlineExceeded:
+		status.Status = rolloutStuck
+		status.Message = fmt.Sprintf("deployment %q exceeded its progress deadline", deployment.Name)
+	case replicas.Updated < desired:
+		status.Status = rolloutProgressing
+		status.Message = fmt.Sprintf("Waiting for deployment %q rollout to finish: %d out of %d new replicas have been updated...", deployment.Name, replicas.Updated, desired)
+	case replicas.Current > replicas.Updated:
+		status.Status = rolloutProgressing
+		status.Message = fmt.Sprintf("Waiting for deployment %q rollout to finish: %d old replicas are pending termination...", deployment.Name, replicas.Current-replicas.Updated)
+	case replicas.Available < replicas.Updated:
+		status.Status = rolloutProgressing
+		status.Message = fmt.Sprintf("Waiting for deployment %q rollout to finish: %d of %d updated replicas are available...", deployment.Name, replicas.Available, replicas.Updated)
+	default:
+		status.Status = rolloutComplete
+		status.Done = true
+		status.Message = fmt.Sprintf("deployment %q successfully rolled out", deployment.Name)
+	}
+
+	if deployment.Spec.Paused && !status.Done {
+		status.Status = rolloutPaused
+		status.Message = fmt.Sprintf("deployment %q is paused and will not progress until it is resumed; %s", deployment.Name, status.Message)
+	}
+
+	return status
+}
+
+// statefulSetRolloutStatus evaluates a StatefulSet rollout the way
+// "kubectl rollout status" does, including partitioned rolling updates.
+// StatefulSets with the OnDelete strategy only update pods that are deleted,
+// so their rollout progresses only as pods are deleted by hand.
+func statefulSetRolloutStatus(statefulSet *appsv1.StatefulSet) RolloutStatus {
+	desired := int32(1)
+	if statefulSet.Spec.Replicas != nil {
+		desired = *statefulSet.Spec.Replicas
+	}
+
+	status := RolloutStatus{
+		Kind:               "StatefulSet",
+		Namespace:          statefulSet.Namespace,
+		Name:               statefulSet.Name,
+		Strategy:           string(statefulSet.Spec.UpdateStrategy.Type),
+		Generation:         statefulSet.Generation,
+		ObservedGeneration: statefulSet.Status.ObservedGeneration,
+		Replicas: RolloutReplicas{
+			Desired:     desired,
+			Current:     statefulSet.Status.Replicas,
+			Updated:     statefulSet.Status.UpdatedReplicas,
+			Ready:       statefulSet.Status.ReadyReplicas,
+			Available:   statefulSet.Status.AvailableReplicas,
+			Unavailable: max(statefulSet.Status.Replicas-statefulSet.Status.AvailableReplicas, 0),
+		},
+		CurrentRevision: statefulSet.Status.CurrentRevision,
+		UpdateRevision:  statefulSet.Status.UpdateRevision,
+		Conditions:      make([]RolloutCondition, 0, len(statefulSet.Status.Conditions)),
+	}
+
+	for _, condition := range statefulSet.Status.Conditions {
+		status.Conditions = append(status.Conditions, RolloutCondition{
+			Type:               string(condition.Type),
+			Status:             string(condition.Status),
+			Reason:             condition.Reason,
+			Message:            condition.Message,
+			LastTransitionTime: formatTime(condition.LastTransitionTime.Time),
+		})
+	}
+
+	var partition int32
+	if rollingUpdate := statefulSet.Spec.UpdateStrategy.RollingUpdate; rollingUpdate != nil && rollingUpdate.Partition != nil {
+		partition = *rollingUpdate.Partition
+	}
+
+	replicas := status.Replicas
+	status.Status = rolloutProgressing
+	switch {
+	case statefulSet.Status.ObservedGeneration == 0 || statefulSet.Generation > statefulSet.Status.ObservedGeneration:
+		status.Message = "Waiting for statefulset spec update to be observed..."
+	case replicas.Ready < desired:
+		status.Message = fmt.Sprintf("Waiting for %d pods to be ready...", desired-replicas.Ready)
+	case statefulSet.Spec.UpdateStrategy.Type == appsv1.OnDeleteStatefulSetStrategyType && status.UpdateRevision != status.CurrentRevision:
+		status.Message = fmt.Sprintf("update strategy is OnDelete: %d of %d pods run revision %s, and the others update only when they are deleted", replicas.Updated, desired, status.UpdateRevision)
+	case partition > 0 && replicas.Updated < desired-partition:
+		status.Message = fmt.Sprintf("Waiting for partitioned roll out to finish: %d out of %d new pods have been updated...", replicas.Updated, desired-partition)
+	case partition > 0:
+		status.Status = rolloutComplete
+		status.Done = true
+		status.Message = fmt.Sprintf("partitioned roll out complete: %d new pods have been updated...", replicas.Updated)
+	case status.UpdateRevision != status.CurrentRevision:
+		status.Message = fmt.Sprintf("waiting for statefulset rolling update to complete %d pods at revision %s...", replicas.Updated, status.UpdateRevision)
+	default:
+		status.Status = rolloutComplete
+		status.Done = true
+		status.Message = fmt.Sprintf("statefulset rolling update complete %d pods at revision %s...", replicas.Current, status.CurrentRevision)
+	}
+
+	return status
+}
+
+// daemonSetRolloutStatus evaluates a DaemonSet rollout the way
+// "kubectl rollout status" does. DaemonSets with the OnDelete strategy only
+// update pods that are deleted.
+func daemonSetRolloutStatus(daemonSet *appsv1.DaemonSet) RolloutStatus {
+	desired := daemonSet.Status.DesiredNumberScheduled
+
+	status := RolloutStatus{
+		Kind:               "DaemonSet",
+		Namespace:          daemonSet.Namespace,
+		Name:               daemonSet.Name,
+		Strategy:           string(daemonSet.Spec.UpdateStrategy.Type),
+		Generation:         daemonSet.Generation,
+		ObservedGeneration: daemonSet.Status.ObservedGeneration,
+		Replicas: RolloutReplicas{
+			Desired:     desired,
+			Current:     daemonSet.Status.CurrentNumberScheduled,
+			Updated:     daemonSet.Status.UpdatedNumberScheduled,
+			Ready:       daemonSet.Status.NumberReady,
+			Available:   daemonSet.Status.NumberAvailable,
+			Unavailable: daemonSet.Status.NumberUnavailable,
+		},
+		Conditions: make([]RolloutCondition, 0, len(daemonSet.Status.Conditions)),
+	}
+
+	for _, condition := range daemonSet.Status.Conditions {
+		status.Conditions = append(status.Conditions, RolloutCondition{
+			Type:               string(condition.Type),
+			Status:             string(condition.Status),
+			Reason:             condition.Reason,
+			Message:            condition.Message,
+			LastTransitionTime: formatTime(condition.LastTransitionTime.Time),
+		})
+	}
+
+	replicas := status.Replicas
+	status.Status = rolloutProgressing
+	switch {
+	case daemonSet.Generation > daemonSet.Status.ObservedGeneration:
+		status.Message = "Waiting for daemon set spec update to be observed..."
+	case replicas.Updated < desired && daemonSet.Spec.UpdateStrategy.Type == appsv1.OnDeleteDaemonSetStrategyType:
+		status.Message = fmt.Sprintf("update strategy is OnDelete: %d of %d pods have been updated, and the others update only when they are deleted", replicas.Updated, desired)
+	case replicas.Updated < desired:
+		status.Message = fmt.Sprintf("Waiting for daemon set %q rollout to finish: %d out of %d new pods have been updated...", daemonSet.Name, replicas.Updated, desired)
+	case replicas.Available < desired:
+		status.Message = fmt.Sprintf("Waiting for daemon set %q rollout to finish: %d of %d updated pods are available...", daemonSet.Name, replicas.Available, desired)
+	default:
+		status.Status = rolloutComplete
+		status.Done = true
+		status.Message = fmt.Sprintf("daemon set %q successfully rolled out", daemonSet.Name)
+	}
+
+	return status
+}
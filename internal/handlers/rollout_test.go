@@ -0,0 +1,224 @@
+package handlers
+
+import (
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/fakecluster"
+)
+
+func TestDeploymentRolloutStatus(t *testing.T) {
+	t.Parallel()
+
+	three := int32(3)
+
+	deployment := func(generation, observed int64, status appsv1.DeploymentStatus, paused bool) *appsv1.Deployment {
+		status.ObservedGeneration = observed
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop", Generation: generation},
+			Spec:       appsv1.DeploymentSpec{Replicas: &three, Paused: paused},
+			Status:     status,
+		}
+	}
+
+	tests := []struct {
+		name        string
+		deployment  *appsv1.Deployment
+		wantStatus  string
+		wantMessage string
+	}{
+		{
+			name:        "spec update not observed yet",
+			deployment:  deployment(2, 1, appsv1.DeploymentStatus{Replicas: 3, UpdatedReplicas: 3, AvailableReplicas: 3}, false),
+			wantStatus:  rolloutProgressing,
+			wantMessage: "Waiting for deployment spec update to be observed...",
+		},
+		{
+			name:        "new replicas still being created",
+			deployment:  deployment(2, 2, appsv1.DeploymentStatus{Replicas: 4, UpdatedReplicas: 1, AvailableReplicas: 3}, false),
+			wantStatus:  rolloutProgressing,
+			wantMessage: `Waiting for deployment "web" rollout to finish: 1 out of 3 new replicas have been updated...`,
+		},
+		{
+			name:        "old replicas terminating",
+			deployment:  deployment(2, 2, appsv1.DeploymentStatus{Replicas: 4, UpdatedReplicas: 3, AvailableReplicas: 3}, false),
+			wantStatus:  rolloutProgressing,
+			wantMessage: `Waiting for deployment "web" rollout to finish: 1 old replicas are pending termination...`,
+		},
+		{
+			name:        "updated replicas not available",
+			deployment:  deployment(2, 2, appsv1.DeploymentStatus{Replicas: 3, UpdatedReplicas: 3, AvailableReplicas: 2}, false),
+			wantStatus:  rolloutProgressing,
+			wantMessage: `Waiting for deployment "web" rollout to finish: 2 of 3 updated replicas are available...`,
+		},
+		{
+			name: "progress deadline exceeded",
+			deployment: deployment(2, 2, appsv1.DeploymentStatus{
+				Replicas: 4, UpdatedReplicas: 1, AvailableReplicas: 3,
+				Conditions: []appsv1.DeploymentCondition{{Type: appsv1.DeploymentProgressing, Status: corev1.ConditionFalse, Reason: "ProgressDeadlineExceeded"}},
+			}, false),
+			wantStatus:  rolloutStuck,
+			wantMessage: `deployment "web" exceeded its progress deadline`,
+		},
+		{
+			name:        "paused mid rollout",
+			deployment:  deployment(2, 2, appsv1.DeploymentStatus{Replicas: 4, UpdatedReplicas: 1, AvailableReplicas: 3}, true),
+			wantStatus:  rolloutPaused,
+			wantMessage: `deployment "web" is paused and will not progress until it is resumed; Waiting for deployment "web" rollout to finish: 1 out of 3 new replicas have been updated...`,
+		},
+		{
+			name:        "complete",
+			deployment:  deployment(2, 2, appsv1.DeploymentStatus{Replicas: 3, UpdatedReplicas: 3, AvailableReplicas: 3}, false),
+			wantStatus:  rolloutComplete,
+			wantMessage: `deployment "web" successfully rolled out`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := deploymentRolloutStatus(tt.deployment)
+			if got.Status != tt.wantStatus {
+				t.Errorf("expected status %q, got %q", tt.wantStatus, got.Status)
+			}
+			if got.Message != tt.wantMessage {
+				t.Errorf("expected message %q, got %q", tt.wantMessage, got.Message)
+			}
+			if got.Done != (tt.wantStatus == rolloutComplete) {
+				t.Errorf("expected done to be %v", tt.wantStatus == rolloutComplete)
+			}
+		})
+	}
+}
+
+func TestStatefulSetRolloutStatus(t *testing.T) {
+	t.Parallel()
+
+	three := int32(3)
+	two := int32(2)
+
+	statefulSet := func(partition *int32, status appsv1.StatefulSetStatus) *appsv1.StatefulSet {
+		status.ObservedGeneration = 1
+		return &appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "shop", Generation: 1},
+			Spec: appsv1.StatefulSetSpec{
+				Replicas: &three,
+				UpdateStrategy: appsv1.StatefulSetUpdateStrategy{
+					Type:          appsv1.RollingUpdateStatefulSetStrategyType,
+					RollingUpdate: &appsv1.RollingUpdateStatefulSetStrategy{Partition: partition},
+				},
+			},
+			Status: status,
+		}
+	}
+
+	tests := []struct {
+		name        string
+		statefulSet *appsv1.StatefulSet
+		wantStatus  string
+		wantMessage string
+	}{
+		{
+			name:        "pods not ready",
+			statefulSet: statefulSet(nil, appsv1.StatefulSetStatus{Replicas: 3, ReadyReplicas: 1}),
+			wantStatus:  rolloutProgressing,
+			wantMessage: "Waiting for 2 pods to be ready...",
+		},
+		{
+			name:        "partitioned rollout in progress",
+			statefulSet: statefulSet(&two, appsv1.StatefulSetStatus{Replicas: 3, ReadyReplicas: 3, UpdatedReplicas: 0, CurrentRevision: "db-1", UpdateRevision: "db-2"}),
+			wantStatus:  rolloutProgressing,
+			wantMessage: "Waiting for partitioned roll out to finish: 0 out of 1 new pods have been updated...",
+		},
+		{
+			name:        "partitioned rollout complete",
+			statefulSet: statefulSet(&two, appsv1.StatefulSetStatus{Replicas: 3, ReadyReplicas: 3, UpdatedReplicas: 1, CurrentRevision: "db-1", UpdateRevision: "db-2"}),
+			wantStatus:  rolloutComplete,
+			wantMessage: "partitioned roll out complete: 1 new pods have been updated...",
+		},
+		{
+			name:        "rolling update in progress",
+			statefulSet: statefulSet(nil, appsv1.StatefulSetStatus{Replicas: 3, ReadyReplicas: 3, UpdatedReplicas: 2, CurrentRevision: "db-1", UpdateRevision: "db-2"}),
+			wantStatus:  rolloutProgressing,
+			wantMessage: "waiting for statefulset rolling update to complete 2 pods at revision db-2...",
+		},
+		{
+			name:        "complete",
+			statefulSet: statefulSet(nil, appsv1.StatefulSetStatus{Replicas: 3, ReadyReplicas: 3, UpdatedReplicas: 3, CurrentRevision: "db-2", UpdateRevision: "db-2"}),
+			wantStatus:  rolloutComplete,
+			wantMessage: "statefulset rolling update complete 3 pods at revision db-2...",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := statefulSetRolloutStatus(tt.statefulSet)
+			if got.Status != tt.wantStatus {
+				t.Errorf("expected status %q, got %q", tt.wantStatus, got.Status)
+			}
+			if got.Message != tt.wantMessage {
+				t.Errorf("expected message %q, got %q", tt.wantMessage, got.Message)
+			}
+		})
+	}
+}
+
+func TestGetRolloutStatus_FakeCluster(t *testing.T) {
+	t.Parallel()
+
+	handler := NewWorkloadHandler(fakecluster.New(fakecluster.Config{
+		Objects: []runtime.Object{
+			&appsv1.DaemonSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "kube-system", Generation: 3},
+				Spec: appsv1.DaemonSetSpec{UpdateStrategy: appsv1.DaemonSetUpdateStrategy{
+					Type: appsv1.RollingUpdateDaemonSetStrategyType,
+				}},
+				Status: appsv1.DaemonSetStatus{
+					ObservedGeneration:     3,
+					DesiredNumberScheduled: 4,
+					CurrentNumberScheduled: 4,
+					UpdatedNumberScheduled: 2,
+					NumberReady:            4,
+					NumberAvailable:        4,
+				},
+			},
+		},
+	}), false)
+
+	result, isErr := callTool(t, handler.GetRolloutStatus, map[string]any{
+		"resource_type": "ds",
+		"namespace":     "kube-system",
+		"name":          "agent",
+	})
+	if isErr {
+		t.Fatalf("unexpected error: %v", result["error"])
+	}
+
+	var status RolloutStatus
+	decodeInto(t, result, &status)
+
+	if status.Kind != "DaemonSet" || status.Status != rolloutProgressing || status.Done {
+		t.Errorf("expected a progressing DaemonSet rollout, got %+v", status)
+	}
+	if want := `Waiting for daemon set "agent" rollout to finish: 2 out of 4 new pods have been updated...`; status.Message != want {
+		t.Errorf("expected message %q, got %q", want, status.Message)
+	}
+
+	result, isErr = callTool(t, handler.GetRolloutStatus, map[string]any{
+		"resource_type": "cronjobs",
+		"namespace":     "kube-system",
+		"name":          "agent",
+	})
+	if !isErr {
+		t.Fatalf("expected an error for an unsupported resource type, got %v", result)
+	}
+}
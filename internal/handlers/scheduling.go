@@ -0,0 +1,279 @@
+package handlers
+
+import (
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+)
+
+// Scheduling blockers, used to group nodes that reject a pod for the same
+// reason, the way the scheduler's FailedScheduling message does.
+const (
+	blockerCordoned     = "node is cordoned"
+	blockerTaint        = "untolerated taint"
+	blockerNodeSelector = "node selector does not match"
+	blockerNodeAffinity = "required node affinity does not match"
+	blockerInsufficient = "insufficient "
+	blockerTooManyPods  = "too many pods"
+)
+
+const (
+	// taintNodeUnschedulable is the taint the node controller adds to cordoned nodes.
+	taintNodeUnschedulable = "node.kubernetes.io/unschedulable"
+
+	// nodeFieldName is the only node field that node affinity matchFields supports.
+	nodeFieldName = "metadata.name"
+)
+
+// schedulingBlocker is one reason a node cannot run a pod.
+type schedulingBlocker struct {
+	// reason groups the blocker across nodes, such as "insufficient cpu".
+	reason string
+
+	// detail explains the blocker on this node, such as the free capacity.
+	detail string
+}
+
+// podResourceRequests returns the resources the scheduler reserves for a pod:
+// the sum of its regular and sidecar container requests, or the largest init
+// container request when that is higher, plus the pod overhead.
+func podResourceRequests(pod *corev1.Pod) corev1.ResourceList {
+	requests := corev1.ResourceList{}
+	for i := range pod.Spec.Containers {
+		addResources(requests, pod.Spec.Containers[i].Resources.Requests)
+	}
+
+	// Sidecars keep running, so they add to both the pod total and every
+	// init container started after them.
+	sidecars := corev1.ResourceList{}
+	initPeak := corev1.ResourceList{}
+	for i := range pod.Spec.InitContainers {
+		container := &pod.Spec.InitContainers[i]
+		if container.RestartPolicy != nil && *container.RestartPolicy == corev1.ContainerRestartPolicyAlways {
+			addResources(requests, container.Resources.Requests)
+			addResources(sidecars, container.Resources.Requests)
+			continue
+		}
+
+		running := sidecars.DeepCopy()
+		addResources(running, container.Resources.Requests)
+		maxResources(initPeak, running)
+	}
+
+	maxResources(requests, initPeak)
+	addResources(requests, pod.Spec.Overhead)
+
+	return requests
+}
+
+// addResources adds every quantity in add to total.
+func addResources(total, add corev1.ResourceList) {
+	for name, quantity := range add {
+		current := total[name]
+		current.Add(quantity)
+		total[name] = current
+	}
+}
+
+// maxResources raises every quantity in total to at least the one in other.
+func maxResources(total, other corev1.ResourceList) {
+	for name, quantity := range other {
+		if current, ok := total[name]; !ok || quantity.Cmp(current) > 0 {
+			total[name] = quantity.DeepCopy()
+		}
+	}
+}
+
+// nodeAllocations sums the requests and counts the pods already assigned to
+// each node. Pods that finished no longer hold their requests.
+func nodeAllocations(pods []corev1.Pod) (map[string]corev1.ResourceList, map[string]int64) {
+	requested := make(map[string]corev1.ResourceList)
+	counts := make(map[string]int64)
+
+	for i := range pods {
+		pod := &pods[i]
+		if pod.Spec.NodeName == "" || pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+
+		if requested[pod.Spec.NodeName] == nil {
+			requested[pod.Spec.NodeName] = corev1.ResourceList{}
+		}
+		addResources(requested[pod.Spec.NodeName], podResourceRequests(pod))
+		counts[pod.Spec.NodeName]++
+	}
+
+	return requested, counts
+}
+
+// toleratesTaint reports whether any of the tolerations matches the taint.
+// An empty toleration key with the Exists operator matches every taint, and
+// an empty effect matches every effect.
+func toleratesTaint(tolerations []corev1.Toleration, taint corev1.Taint) bool {
+	for _, toleration := range tolerations {
+		if toleration.Effect != "" && toleration.Effect != taint.Effect {
+			continue
+		}
+
+		if toleration.Key != "" && toleration.Key != taint.Key {
+			continue
+		}
+
+		switch toleration.Operator {
+		case corev1.TolerationOpExists:
+			return true
+		case corev1.TolerationOpEqual, "":
+			if toleration.Value == taint.Value {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// matchesNodeSelectorTerms reports whether a node satisfies at least one of
+// the terms. The requirements within a term must all match, and a term
+// without requirements matches no node.
+func matchesNodeSelectorTerms(node *corev1.Node, terms []corev1.NodeSelectorTerm) bool {
+	for _, term := range terms {
+		if len(term.MatchExpressions) == 0 && len(term.MatchFields) == 0 {
+			continue
+		}
+
+		if matchesNodeSelectorRequirements(node.Labels, term.MatchExpressions) &&
+			matchesNodeSelectorRequirements(labels.Set{nodeFieldName: node.Name}, term.MatchFields) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchesNodeSelectorRequirements reports whether the set satisfies every
+// requirement. Invalid requirements never match, as in the scheduler.
+func matchesNodeSelectorRequirements(set labels.Set, requirements []corev1.NodeSelectorRequirement) bool {
+	operators := map[corev1.NodeSelectorOperator]selection.Operator{
+		corev1.NodeSelectorOpIn:           selection.In,
+		corev1.NodeSelectorOpNotIn:        selection.NotIn,
+		corev1.NodeSelectorOpExists:       selection.Exists,
+		corev1.NodeSelectorOpDoesNotExist: selection.DoesNotExist,
+		corev1.NodeSelectorOpGt:           selection.GreaterThan,
+		corev1.NodeSelectorOpLt:           selection.LessThan,
+	}
+
+	for _, requirement := range requirements {
+		operator, ok := operators[requirement.Operator]
+		if !ok {
+			return false
+		}
+
+		parsed, err := labels.NewRequirement(requirement.Key, operator, requirement.Values)
+		if err != nil || !parsed.Matches(set) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// nodeSchedulingBlockers returns the reasons a node cannot run a pod, checking
+// cordoning, taints, the node selector, required node affinity, and free
+// resources. requested and podCount are what the node's current pods already
+// use; when requested is nil, resources are not checked. Inter-pod affinity,
+// topology spread, and volume topology are not evaluated.
+func nodeSchedulingBlockers(pod *corev1.Pod, node *corev1.Node, podRequests, requested corev1.ResourceList, podCount int64) []schedulingBlocker {
+	var blockers []schedulingBlocker
+
+	unschedulable := corev1.Taint{Key: taintNodeUnschedulable, Effect: corev1.TaintEffectNoSchedule}
+	if node.Spec.Unschedulable && !toleratesTaint(pod.Spec.Tolerations, unschedulable) {
+		blockers = append(blockers, schedulingBlocker{reason: blockerCordoned})
+	}
+
+	for _, taint := range node.Spec.Taints {
+		// Cordoned nodes carry the unschedulable taint, already reported above.
+		if taint.Key == taintNodeUnschedulable || taint.Effect == corev1.TaintEffectPreferNoSchedule || toleratesTaint(pod.Spec.Tolerations, taint) {
+			continue
+		}
+		blockers = append(blockers, schedulingBlocker{reason: blockerTaint, detail: formatTaint(taint)})
+	}
+
+	for _, key := range sortedKeys(pod.Spec.NodeSelector) {
+		if value, ok := node.Labels[key]; !ok || value != pod.Spec.NodeSelector[key] {
+			blockers = append(blockers, schedulingBlocker{reason: blockerNodeSelector, detail: key + "=" + pod.Spec.NodeSelector[key]})
+		}
+	}
+
+	if affinity := pod.Spec.Affinity; affinity != nil && affinity.NodeAffinity != nil {
+		if required := affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution; required != nil && !matchesNodeSelectorTerms(node, required.NodeSelectorTerms) {
+			blockers = append(blockers, schedulingBlocker{reason: blockerNodeAffinity})
+		}
+	}
+
+	if requested == nil {
+		return blockers
+	}
+
+	if allocatable, ok := node.Status.Allocatable[corev1.ResourcePods]; ok && podCount+1 > allocatable.Value() {
+		blockers = append(blockers, schedulingBlocker{
+			reason: blockerTooManyPods,
+			detail: fmt.Sprintf("%d of %d pods already running", podCount, allocatable.Value()),
+		})
+	}
+
+	for _, name := range sortedResourceNames(podRequests) {
+		want := podRequests[name]
+		if want.IsZero() {
+			continue
+		}
+
+		allocatable := node.Status.Allocatable[name]
+		free := allocatable.DeepCopy()
+		free.Sub(requested[name])
+
+		if want.Cmp(free) > 0 {
+			if free.Sign() < 0 {
+				free = resource.Quantity{}
+			}
+			blockers = append(blockers, schedulingBlocker{
+				reason: blockerInsufficient + string(name),
+				detail: fmt.Sprintf("requests %s, %s of %s allocatable free", want.String(), free.String(), allocatable.String()),
+			})
+		}
+	}
+
+	return blockers
+}
+
+// sortedKeys returns the keys of a string map in order.
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// sortedResourceNames returns the resource names of a list in order.
+func sortedResourceNames(list corev1.ResourceList) []corev1.ResourceName {
+	names := make([]corev1.ResourceName, 0, len(list))
+	for name := range list {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return names[i] < names[j] })
+	return names
+}
+
+// formatResources renders a resource list as name to quantity strings.
+func formatResources(list corev1.ResourceList) map[string]string {
+	formatted := make(map[string]string, len(list))
+	for name, quantity := range list {
+		formatted[string(name)] = quantity.String()
+	}
+	return formatted
+}
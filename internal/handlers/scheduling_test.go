@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPodResourceRequests(t *testing.T) {
+	t.Parallel()
+
+	always := corev1.ContainerRestartPolicyAlways
+	requests := func(cpu, memory string) corev1.ResourceRequirements {
+		return corev1.ResourceRequirements{Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse(cpu),
+			corev1.ResourceMemory: resource.MustParse(memory),
+		}}
+	}
+
+	tests := []struct {
+		name       string
+		spec       corev1.PodSpec
+		wantCPU    string
+		wantMemory string
+	}{
+		{
+			name: "containers are summed",
+			spec: corev1.PodSpec{Containers: []corev1.Container{
+				{Resources: requests("100m", "64Mi")},
+				{Resources: requests("250m", "128Mi")},
+			}},
+			wantCPU:    "350m",
+			wantMemory: "192Mi",
+		},
+		{
+			name: "largest init container wins when higher",
+			spec: corev1.PodSpec{
+				InitContainers: []corev1.Container{{Resources: requests("1", "32Mi")}},
+				Containers:     []corev1.Container{{Resources: requests("100m", "64Mi")}},
+			},
+			wantCPU:    "1",
+			wantMemory: "64Mi",
+		},
+		{
+			name: "sidecars add to the total and later init containers",
+			spec: corev1.PodSpec{
+				InitContainers: []corev1.Container{
+					{Resources: requests("200m", "64Mi"), RestartPolicy: &always},
+					{Resources: requests("500m", "32Mi")},
+				},
+				Containers: []corev1.Container{{Resources: requests("100m", "64Mi")}},
+				Overhead:   corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("50m")},
+			},
+			wantCPU:    "750m",
+			wantMemory: "128Mi",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := podResourceRequests(&corev1.Pod{Spec: tt.spec})
+			if cpu := got[corev1.ResourceCPU]; cpu.Cmp(resource.MustParse(tt.wantCPU)) != 0 {
+				t.Errorf("expected cpu %s, got %s", tt.wantCPU, cpu.String())
+			}
+			if memory := got[corev1.ResourceMemory]; memory.Cmp(resource.MustParse(tt.wantMemory)) != 0 {
+				t.Errorf("expected memory %s, got %s", tt.wantMemory, memory.String())
+			}
+		})
+	}
+}
+
+func TestNodeSchedulingBlockers(t *testing.T) {
+	t.Parallel()
+
+	node := corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "worker-1", Labels: map[string]string{"disk": "ssd", "zone": "a"}},
+		Spec: corev1.NodeSpec{Taints: []corev1.Taint{
+			{Key: "dedicated", Value: "gpu", Effect: corev1.TaintEffectNoSchedule},
+			{Key: "spot", Effect: corev1.TaintEffectPreferNoSchedule},
+		}},
+		Status: corev1.NodeStatus{Allocatable: corev1.ResourceList{
+			corev1.ResourceCPU:  resource.MustParse("2"),
+			corev1.ResourcePods: resource.MustParse("10"),
+		}},
+	}
+
+	gpuToleration := []corev1.Toleration{{Key: "dedicated", Operator: corev1.TolerationOpEqual, Value: "gpu", Effect: corev1.TaintEffectNoSchedule}}
+
+	affinity := func(operator corev1.NodeSelectorOperator, values ...string) *corev1.Affinity {
+		return &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{NodeSelectorTerms: []corev1.NodeSelectorTerm{
+				{MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "zone", Operator: operator, Values: values}}},
+			}},
+		}}
+	}
+
+	tests := []struct {
+		name        string
+		spec        corev1.PodSpec
+		requested   corev1.ResourceList
+		podCount    int64
+		wantReasons []string
+	}{
+		{
+			name:        "untolerated taint",
+			wantReasons: []string{blockerTaint},
+		},
+		{
+			name: "tolerated taint, matching selector and affinity",
+			spec: corev1.PodSpec{
+				Tolerations:  gpuToleration,
+				NodeSelector: map[string]string{"disk": "ssd"},
+				Affinity:     affinity(corev1.NodeSelectorOpIn, "a", "b"),
+			},
+			wantReasons: []string{},
+		},
+		{
+			name:        "wildcard toleration",
+			spec:        corev1.PodSpec{Tolerations: []corev1.Toleration{{Operator: corev1.TolerationOpExists}}},
+			wantReasons: []string{},
+		},
+		{
+			name: "selector and affinity mismatch",
+			spec: corev1.PodSpec{
+				Tolerations:  gpuToleration,
+				NodeSelector: map[string]string{"disk": "hdd"},
+				Affinity:     affinity(corev1.NodeSelectorOpNotIn, "a"),
+			},
+			wantReasons: []string{blockerNodeSelector, blockerNodeAffinity},
+		},
+		{
+			name: "insufficient cpu and pods",
+			spec: corev1.PodSpec{
+				Tolerations: gpuToleration,
+				Containers: []corev1.Container{{Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{
+					corev1.ResourceCPU: resource.MustParse("500m"),
+				}}}},
+			},
+			requested:   corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1800m")},
+			podCount:    10,
+			wantReasons: []string{blockerTooManyPods, blockerInsufficient + "cpu"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			pod := &corev1.Pod{Spec: tt.spec}
+			requested := tt.requested
+			if requested == nil {
+				requested = corev1.ResourceList{}
+			}
+
+			reasons := make([]string, 0)
+			for _, blocker := range nodeSchedulingBlockers(pod, &node, podResourceRequests(pod), requested, tt.podCount) {
+				reasons = append(reasons, blocker.reason)
+			}
+			if !reflect.DeepEqual(reasons, tt.wantReasons) {
+				t.Errorf("expected blockers %v, got %v", tt.wantReasons, reasons)
+			}
+		})
+	}
+}
+
+func TestNodeSchedulingBlockers_Cordoned(t *testing.T) {
+	t.Parallel()
+
+	node := &corev1.Node{Spec: corev1.NodeSpec{
+		Unschedulable: true,
+		Taints:        []corev1.Taint{{Key: taintNodeUnschedulable, Effect: corev1.TaintEffectNoSchedule}},
+	}}
+
+	blockers := nodeSchedulingBlockers(&corev1.Pod{}, node, corev1.ResourceList{}, nil, 0)
+	if len(blockers) != 1 || blockers[0].reason != blockerCordoned {
+		t.Errorf("expected only the cordoned blocker, got %v", blockers)
+	}
+}
@@ -0,0 +1,324 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/connectivity"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/response"
+)
+
+// daemonSetTolerations are the tolerations the DaemonSet controller adds to
+// every pod it creates, so they are added to DaemonSet pod templates too.
+// The network-unavailable toleration is only added to host network pods.
+var daemonSetTolerations = []corev1.Toleration{
+	{Key: "node.kubernetes.io/not-ready", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoExecute},
+	{Key: "node.kubernetes.io/unreachable", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoExecute},
+	{Key: "node.kubernetes.io/disk-pressure", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+	{Key: "node.kubernetes.io/memory-pressure", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+	{Key: "node.kubernetes.io/pid-pressure", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+	{Key: taintNodeUnschedulable, Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+}
+
+// daemonSetHostNetworkToleration is added to DaemonSet pods that use the
+// host network, since they do not depend on the pod network being ready.
+var daemonSetHostNetworkToleration = corev1.Toleration{
+	Key: "node.kubernetes.io/network-unavailable", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule,
+}
+
+// CheckSchedulingFitParams defines the parameters for the check_scheduling_fit MCP tool.
+type CheckSchedulingFitParams struct {
+	// Namespace specifies the namespace of the pod or workload. For a
+	// manifest, it is used when the manifest sets none.
+	Namespace string `json:"namespace,omitempty" description:"Namespace of the pod or workload to check. With a manifest, used when the manifest sets none"`
+
+	// Name specifies which pod or workload to check. Either Name or
+	// Manifest is required.
+	Name string `json:"name,omitempty" description:"Name of the pod or workload to check (either name or manifest is required)"`
+
+	// ResourceType specifies the kind of object to read. Workloads are
+	// checked through their pod template.
+	ResourceType string `json:"resource_type,omitempty" default:"pods" description:"Resource type of the object: pods, or a workload such as deployments, statefulsets, daemonsets, replicasets, jobs, or cronjobs (defaults to pods)"`
+
+	// Manifest is a pod, a workload, or a bare pod spec to check without
+	// creating it.
+	Manifest string `json:"manifest,omitempty" description:"Pod or workload manifest, or a bare pod spec, as YAML or JSON, to check before creating it (either name or manifest is required)"`
+
+	// Context specifies which Kubernetes context to use for this operation.
+	Context string `json:"context,omitempty" description:"Kubernetes context to use (defaults to current context from kubeconfig)"`
+}
+
+// CheckSchedulingFitResult is the result of the check_scheduling_fit MCP
+// tool.
+type CheckSchedulingFitResult struct {
+	Kind          string                   `json:"kind"`
+	Namespace     string                   `json:"namespace"`
+	Name          string                   `json:"name"`
+	Node          string                   `json:"node,omitempty"`
+	Summary       string                   `json:"summary"`
+	Requests      map[string]string        `json:"requests"`
+	Tolerations   []string                 `json:"tolerations"`
+	NodeSelector  map[string]string        `json:"node_selector"`
+	TotalNodes    int                      `json:"total_nodes"`
+	FeasibleNodes []string                 `json:"feasible_nodes"`
+	Blockers      []SchedulingBlockerCount `json:"blockers"`
+	UnfitNodes    []UnfitNode              `json:"unfit_nodes"`
+	Notes         []string                 `json:"notes,omitempty"`
+	Warnings      []string                 `json:"warnings,omitempty"`
+}
+
+// CheckSchedulingFit implements the check_scheduling_fit MCP tool.
+// It reads a pod, the pod template of a workload, or a manifest, and checks
+// it against every node the way why_pending does: cordoning, untolerated
+// taints, the node selector, required node affinity, and whether its
+// requests fit the node's free allocatable resources. A pod that is already
+// running does not count against its own node. Inter-pod affinity, topology
+// spread, and volume topology are not evaluated.
+func (h *PodHandler) CheckSchedulingFit(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var params CheckSchedulingFitParams
+	if err := bindParams(request, &params); err != nil {
+		return response.Errorf("failed to parse arguments: %s", err)
+	}
+
+	if (params.Name == "") == (params.Manifest == "") {
+		return response.Errorf("exactly one of name or manifest is required")
+	}
+
+	if params.ResourceType == "" {
+		params.ResourceType = "pods"
+	}
+
+	// Use the appropriate client based on context
+	client, err := h.client.ForContext(ctx, params.Context)
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to create client with context %s: %v", params.Context, err)
+	}
+
+	var obj *unstructured.Unstructured
+	if params.Manifest != "" {
+		obj, err = schedulingManifest(params.Manifest, params.Namespace)
+		if err != nil {
+			return response.Errorf("%v", err)
+		}
+	} else {
+		gvr, err := client.ResolveResourceType(params.ResourceType, "")
+		if err != nil {
+			if h.alwaysStart && connectivity.IsError(err) {
+				return response.Error(connectivity.ErrorMessage(err))
+			}
+			return response.Errorf("failed to resolve resource type: %v", err)
+		}
+
+		obj, err = client.GetResource(ctx, gvr, params.Namespace, params.Name)
+		if err != nil {
+			if h.alwaysStart && connectivity.IsTransportError(err) {
+				return response.Error(connectivity.ErrorMessage(err))
+			}
+			return response.Errorf("failed to get resource: %v", err)
+		}
+	}
+
+	pod, err := schedulingPod(obj)
+	if err != nil {
+		return response.Errorf("%v", err)
+	}
+
+	nodes, err := client.ListNodes(ctx, metav1.ListOptions{})
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to list nodes: %v", err)
+	}
+
+	var warnings []string
+
+	// A nil map skips the resource checks when pods cannot be listed.
+	var requested map[string]corev1.ResourceList
+	var podCounts map[string]int64
+	if pods, err := client.ListPods(ctx, "", metav1.ListOptions{}); err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		warnings = append(warnings, fmt.Sprintf("failed to list pods, resource requests were not checked: %v", err))
+	} else {
+		requested, podCounts = nodeAllocations(otherPods(pods.Items, pod))
+	}
+
+	podRequests := podResourceRequests(pod)
+	feasible, unfit, blockers := evaluateNodes(pod, nodes.Items, podRequests, requested, podCounts)
+
+	summary := fmt.Sprintf("%d/%d nodes can run the pod", len(feasible), len(nodes.Items))
+	switch {
+	case len(nodes.Items) == 0:
+		summary = "the cluster has no nodes"
+	case len(feasible) == 0:
+		summary = noFeasibleNodeSummary(len(nodes.Items), blockers)
+	}
+
+	return response.JSON(CheckSchedulingFitResult{
+		Kind:          obj.GetKind(),
+		Namespace:     pod.Namespace,
+		Name:          obj.GetName(),
+		Node:          pod.Spec.NodeName,
+		Summary:       summary,
+		Requests:      formatResources(podRequests),
+		Tolerations:   formatTolerations(pod.Spec.Tolerations),
+		NodeSelector:  pod.Spec.NodeSelector,
+		TotalNodes:    len(nodes.Items),
+		FeasibleNodes: feasible,
+		Blockers:      blockers,
+		UnfitNodes:    unfit,
+		Notes:         schedulingFitNotes(pod, nodes.Items, feasible),
+		Warnings:      warnings,
+	})
+}
+
+// schedulingManifest decodes the single document of a manifest. A document
+// without a kind is read as a bare pod spec.
+func schedulingManifest(manifest, namespace string) (*unstructured.Unstructured, error) {
+	documents, err := decodeManifest(manifest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse manifest: %w", err)
+	}
+	if len(documents) != 1 {
+		return nil, fmt.Errorf("manifest must contain exactly one document, found %d", len(documents))
+	}
+
+	obj := &unstructured.Unstructured{Object: documents[0]}
+	if obj.GetKind() == "" {
+		obj = &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Pod",
+			"spec":       documents[0],
+		}}
+	}
+
+	if obj.GetNamespace() == "" {
+		obj.SetNamespace(namespace)
+	}
+
+	return obj, nil
+}
+
+// schedulingPod returns the pod to check for an object: the pod itself, or
+// a pod built from a workload's template, including the tolerations the
+// DaemonSet controller adds.
+func schedulingPod(obj *unstructured.Unstructured) (*corev1.Pod, error) {
+	if obj.GetKind() == "Pod" {
+		var pod corev1.Pod
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &pod); err != nil {
+			return nil, fmt.Errorf("failed to decode pod %s: %w", obj.GetName(), err)
+		}
+		return &pod, nil
+	}
+
+	spec, err := podSpecOf(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	if obj.GetKind() == "DaemonSet" {
+		spec.Tolerations = append(spec.Tolerations, daemonSetTolerations...)
+		if spec.HostNetwork {
+			spec.Tolerations = append(spec.Tolerations, daemonSetHostNetworkToleration)
+		}
+	}
+
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: obj.GetNamespace(),
+			Name:      obj.GetName(),
+			Labels:    podTemplateLabels(obj),
+		},
+		Spec: *spec,
+	}, nil
+}
+
+// otherPods returns the pods except the one being checked, so a running
+// pod's own requests do not count against its node.
+func otherPods(pods []corev1.Pod, pod *corev1.Pod) []corev1.Pod {
+	if pod.UID == "" {
+		return pods
+	}
+
+	others := make([]corev1.Pod, 0, len(pods))
+	for i := range pods {
+		if pods[i].UID != pod.UID {
+			others = append(others, pods[i])
+		}
+	}
+	return others
+}
+
+// formatTolerations renders tolerations the way kubectl describe does, such
+// as "key=value:NoSchedule", "key:NoExecute op=Exists for 300s", or
+// "op=Exists" for a toleration of every taint.
+func formatTolerations(tolerations []corev1.Toleration) []string {
+	formatted := make([]string, 0, len(tolerations))
+	for _, toleration := range tolerations {
+		entry := toleration.Key
+		if toleration.Value != "" {
+			entry += "=" + toleration.Value
+		}
+		if toleration.Effect != "" {
+			entry += ":" + string(toleration.Effect)
+		}
+		if toleration.Operator == corev1.TolerationOpExists {
+			entry += " op=Exists"
+		}
+		if toleration.TolerationSeconds != nil {
+			entry += fmt.Sprintf(" for %ds", *toleration.TolerationSeconds)
+		}
+		formatted = append(formatted, strings.TrimSpace(entry))
+	}
+	return formatted
+}
+
+// schedulingFitNotes lists what affects placement beyond the checks: nodes
+// that fit but carry PreferNoSchedule taints the pod does not tolerate,
+// preferred node affinity, and the constraints that are not evaluated.
+func schedulingFitNotes(pod *corev1.Pod, nodes []corev1.Node, feasible []string) []string {
+	var notes []string
+
+	fits := make(map[string]bool, len(feasible))
+	for _, name := range feasible {
+		fits[name] = true
+	}
+
+	var avoided []string
+	for i := range nodes {
+		node := &nodes[i]
+		if !fits[node.Name] {
+			continue
+		}
+		for _, taint := range node.Spec.Taints {
+			if taint.Effect == corev1.TaintEffectPreferNoSchedule && !toleratesTaint(pod.Spec.Tolerations, taint) {
+				avoided = append(avoided, fmt.Sprintf("%s (%s)", node.Name, formatTaint(taint)))
+				break
+			}
+		}
+	}
+	sort.Strings(avoided)
+
+	if len(avoided) > 0 {
+		notes = append(notes, fmt.Sprintf("%d of the feasible nodes have PreferNoSchedule taints the pod does not tolerate, so the scheduler avoids them when it can: %s", len(avoided), strings.Join(truncate(avoided, 10), ", ")))
+	}
+
+	if affinity := pod.Spec.Affinity; affinity != nil && affinity.NodeAffinity != nil && len(affinity.NodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution) > 0 {
+		notes = append(notes, "the pod has preferred node affinity, which ranks the feasible nodes but never excludes one")
+	}
+
+	return append(notes, pendingNotes(pod)...)
+}
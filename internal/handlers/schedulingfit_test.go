@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/fakecluster"
+)
+
+func TestFormatTolerations(t *testing.T) {
+	t.Parallel()
+
+	seconds := int64(300)
+	got := formatTolerations([]corev1.Toleration{
+		{Key: "dedicated", Operator: corev1.TolerationOpEqual, Value: "gpu", Effect: corev1.TaintEffectNoSchedule},
+		{Key: "node.kubernetes.io/not-ready", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoExecute, TolerationSeconds: &seconds},
+		{Operator: corev1.TolerationOpExists},
+	})
+
+	want := []string{"dedicated=gpu:NoSchedule", "node.kubernetes.io/not-ready:NoExecute op=Exists for 300s", "op=Exists"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+func TestSchedulingPod(t *testing.T) {
+	t.Parallel()
+
+	bare, err := schedulingManifest("nodeSelector:\n  disktype: ssd\ncontainers:\n- name: app\n  image: nginx\n", "shop")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	pod, err := schedulingPod(bare)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pod.Namespace != "shop" || pod.Spec.NodeSelector["disktype"] != "ssd" || len(pod.Spec.Containers) != 1 {
+		t.Errorf("expected the bare pod spec to be read as a pod in shop, got %+v", pod)
+	}
+
+	daemonSet, err := schedulingManifest(`{"apiVersion":"apps/v1","kind":"DaemonSet","metadata":{"name":"agent","namespace":"monitoring"},"spec":{"template":{"metadata":{"labels":{"app":"agent"}},"spec":{"hostNetwork":true,"containers":[{"name":"agent"}]}}}}`, "shop")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	pod, err = schedulingPod(daemonSet)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pod.Namespace != "monitoring" || pod.Labels["app"] != "agent" || len(pod.Spec.Tolerations) != len(daemonSetTolerations)+1 {
+		t.Errorf("expected the DaemonSet tolerations to be added, got %+v", pod)
+	}
+
+	if _, err := schedulingManifest("kind: Pod\n---\nkind: Pod\n", ""); err == nil {
+		t.Error("expected an error for several documents")
+	}
+
+	service, err := schedulingManifest("apiVersion: v1\nkind: Service\nmetadata:\n  name: web\n", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := schedulingPod(service); err == nil {
+		t.Error("expected an error for a kind that does not run pods")
+	}
+}
+
+func TestCheckSchedulingFit_FakeCluster(t *testing.T) {
+	t.Parallel()
+
+	node := func(name string, labels map[string]string, taints ...corev1.Taint) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+			Spec:       corev1.NodeSpec{Taints: taints},
+			Status: corev1.NodeStatus{Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU:  resource.MustParse("2"),
+				corev1.ResourcePods: resource.MustParse("110"),
+			}},
+		}
+	}
+
+	containers := []corev1.Container{{Name: "app", Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{
+		corev1.ResourceCPU: resource.MustParse("1500m"),
+	}}}}
+	gpu := map[string]string{"accelerator": "nvidia"}
+	gpuTaint := corev1.Taint{Key: "nvidia.com/gpu", Value: "present", Effect: corev1.TaintEffectNoSchedule}
+
+	handler := NewPodHandler(fakecluster.New(fakecluster.Config{
+		Objects: []runtime.Object{
+			node("gpu-1", gpu, gpuTaint),
+			node("gpu-2", gpu, gpuTaint, corev1.Taint{Key: "spot", Effect: corev1.TaintEffectPreferNoSchedule}),
+			node("cpu-1", nil),
+			&corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "trainer-0", Namespace: "ml", UID: "trainer-0"},
+				Spec: corev1.PodSpec{
+					NodeName:     "gpu-1",
+					NodeSelector: gpu,
+					Tolerations:  []corev1.Toleration{{Key: "nvidia.com/gpu", Operator: corev1.TolerationOpExists}},
+					Containers:   containers,
+				},
+				Status: corev1.PodStatus{Phase: corev1.PodRunning},
+			},
+			&appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Name: "inference", Namespace: "ml"},
+				Spec: appsv1.DeploymentSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+					NodeSelector: gpu,
+					Containers:   containers,
+				}}},
+			},
+		},
+	}), false)
+
+	result, isErr := callTool(t, handler.CheckSchedulingFit, map[string]any{"namespace": "ml", "name": "trainer-0"})
+	if isErr {
+		t.Fatalf("unexpected error: %v", result["error"])
+	}
+
+	var feasible []string
+	decodeInto(t, result["feasible_nodes"], &feasible)
+	if !reflect.DeepEqual(feasible, []string{"gpu-1", "gpu-2"}) {
+		t.Errorf("expected the running pod to fit both GPU nodes, including its own, got %v", feasible)
+	}
+	if result["node"] != "gpu-1" || result["summary"] != "2/3 nodes can run the pod" {
+		t.Errorf("unexpected result %+v", result)
+	}
+
+	var notes []string
+	decodeInto(t, result["notes"], &notes)
+	if len(notes) != 1 || !strings.Contains(notes[0], "gpu-2 (spot:PreferNoSchedule)") {
+		t.Errorf("expected a note about the PreferNoSchedule taint, got %q", notes)
+	}
+
+	result, isErr = callTool(t, handler.CheckSchedulingFit, map[string]any{"namespace": "ml", "name": "inference", "resource_type": "deployments"})
+	if isErr {
+		t.Fatalf("unexpected error: %v", result["error"])
+	}
+
+	want := "0/3 nodes can run the pod: 2 untolerated taint, 1 insufficient cpu, 1 node selector does not match"
+	if result["summary"] != want {
+		t.Errorf("expected summary %q, got %q", want, result["summary"])
+	}
+
+	var unfit []UnfitNode
+	decodeInto(t, result["unfit_nodes"], &unfit)
+	if len(unfit) != 3 || unfit[1].Name != "gpu-1" || !reflect.DeepEqual(unfit[1].Blockers, []string{
+		"untolerated taint (nvidia.com/gpu=present:NoSchedule)",
+		"insufficient cpu (requests 1500m, 500m of 2 allocatable free)",
+	}) {
+		t.Errorf("unexpected unfit nodes %+v", unfit)
+	}
+
+	manifest := "apiVersion: v1\nkind: Pod\nmetadata:\n  name: batch\nspec:\n  containers:\n  - name: app\n"
+	result, isErr = callTool(t, handler.CheckSchedulingFit, map[string]any{"namespace": "ml", "manifest": manifest})
+	if isErr {
+		t.Fatalf("unexpected error: %v", result["error"])
+	}
+
+	decodeInto(t, result["feasible_nodes"], &feasible)
+	if !reflect.DeepEqual(feasible, []string{"cpu-1"}) {
+		t.Errorf("expected only the untainted node to fit, got %v", feasible)
+	}
+
+	if _, isErr := callTool(t, handler.CheckSchedulingFit, map[string]any{"namespace": "ml"}); !isErr {
+		t.Error("expected an error without a name or manifest")
+	}
+}
@@ -0,0 +1,504 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/connectivity"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/response"
+)
+
+// Where the effective value of a security setting comes from.
+const (
+	sourceContainer = "container"
+	sourcePod       = "pod"
+	sourceDefault   = "default"
+)
+
+// podSecurityLabelPrefix prefixes the namespace labels that configure Pod
+// Security Admission.
+const podSecurityLabelPrefix = "pod-security.kubernetes.io/"
+
+// runtimeDefaultCapabilities are the capabilities containerd and Docker grant
+// to a container that neither adds nor drops any.
+var runtimeDefaultCapabilities = []string{
+	"AUDIT_WRITE", "CHOWN", "DAC_OVERRIDE", "FOWNER", "FSETID", "KILL", "MKNOD",
+	"NET_BIND_SERVICE", "NET_RAW", "SETFCAP", "SETGID", "SETPCAP", "SETUID", "SYS_CHROOT",
+}
+
+// ResolveSecurityContextParams defines the parameters for the resolve_security_context MCP tool.
+type ResolveSecurityContextParams struct {
+	// Namespace specifies the namespace of the pod or workload.
+	Namespace string `json:"namespace" required:"true" description:"Namespace of the pod or workload"`
+
+	// Name specifies which pod or workload to analyze.
+	Name string `json:"name" required:"true" description:"Name of the pod or workload"`
+
+	// ResourceType specifies the kind of object to read. Workloads are
+	// analyzed through their pod template.
+	ResourceType string `json:"resource_type,omitempty" default:"pods" description:"Resource type of the object: pods, or a workload such as deployments, statefulsets, daemonsets, replicasets, jobs, or cronjobs (defaults to pods)"`
+
+	// Container restricts the output to a single container.
+	Container string `json:"container,omitempty" description:"Only resolve this container (defaults to every container)"`
+
+	// Context specifies which Kubernetes context to use for this operation.
+	Context string `json:"context,omitempty" description:"Kubernetes context to use (defaults to current context from kubeconfig)"`
+}
+
+// ResolvedSetting is the effective value of a security setting and the level
+// it was taken from: the container, the pod, or the default that applies when
+// neither sets it.
+type ResolvedSetting struct {
+	Value  interface{} `json:"value"`
+	Source string      `json:"source"`
+	Note   string      `json:"note,omitempty"`
+}
+
+// EffectiveCapabilities is the Linux capability set a container ends up with.
+type EffectiveCapabilities struct {
+	Effective []string `json:"effective"`
+	Added     []string `json:"added"`
+	Dropped   []string `json:"dropped"`
+	Note      string   `json:"note,omitempty"`
+}
+
+// ContainerSecurity is the effective security context of one container.
+type ContainerSecurity struct {
+	Name string `json:"name"`
+
+	// Type is container, init, sidecar (an init container that keeps
+	// running), or ephemeral.
+	Type string `json:"type"`
+
+	RunAsUser                ResolvedSetting       `json:"run_as_user"`
+	RunAsGroup               ResolvedSetting       `json:"run_as_group"`
+	RunAsNonRoot             ResolvedSetting       `json:"run_as_non_root"`
+	Privileged               ResolvedSetting       `json:"privileged"`
+	AllowPrivilegeEscalation ResolvedSetting       `json:"allow_privilege_escalation"`
+	ReadOnlyRootFilesystem   ResolvedSetting       `json:"read_only_root_filesystem"`
+	ProcMount                ResolvedSetting       `json:"proc_mount"`
+	SeccompProfile           ResolvedSetting       `json:"seccomp_profile"`
+	AppArmorProfile          ResolvedSetting       `json:"apparmor_profile"`
+	SELinuxOptions           ResolvedSetting       `json:"selinux_options"`
+	Capabilities             EffectiveCapabilities `json:"capabilities"`
+
+	// Concerns lists the settings that weaken isolation, for audits.
+	Concerns []string `json:"concerns"`
+}
+
+// PodSecurity holds the settings that only exist at the pod level and apply
+// to every container.
+type PodSecurity struct {
+	HostNetwork           bool              `json:"host_network"`
+	HostPID               bool              `json:"host_pid"`
+	HostIPC               bool              `json:"host_ipc"`
+	HostUsers             bool              `json:"host_users"`
+	ShareProcessNamespace bool              `json:"share_process_namespace"`
+	FSGroup               *int64            `json:"fs_group,omitempty"`
+	FSGroupChangePolicy   string            `json:"fs_group_change_policy,omitempty"`
+	SupplementalGroups    []int64           `json:"supplemental_groups,omitempty"`
+	Sysctls               map[string]string `json:"sysctls,omitempty"`
+	Concerns              []string          `json:"concerns"`
+}
+
+// ResolveSecurityContextResult is the result of the resolve_security_context
+// MCP tool.
+type ResolveSecurityContextResult struct {
+	Kind       string              `json:"kind"`
+	Namespace  string              `json:"namespace"`
+	Name       string              `json:"name"`
+	Pod        PodSecurity         `json:"pod"`
+	Containers []ContainerSecurity `json:"containers"`
+
+	// NamespacePodSecurity holds the Pod Security Admission labels of the
+	// namespace, without their common prefix.
+	NamespacePodSecurity map[string]string `json:"namespace_pod_security"`
+
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// ResolveSecurityContext implements the resolve_security_context MCP tool.
+// It reads a pod, or the pod template of a workload, and merges the pod-level
+// and container-level security contexts the way the kubelet does: container
+// settings override pod settings, and unset settings fall back to their
+// defaults. Capabilities are resolved against the usual container runtime
+// defaults. The Pod Security Admission labels of the namespace are included
+// when the namespace can be read.
+func (h *PodHandler) ResolveSecurityContext(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var params ResolveSecurityContextParams
+	if err := bindParams(request, &params); err != nil {
+		return response.Errorf("failed to parse arguments: %s", err)
+	}
+
+	if params.ResourceType == "" {
+		params.ResourceType = "pods"
+	}
+
+	// Use the appropriate client based on context
+	client, err := h.client.ForContext(ctx, params.Context)
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to create client with context %s: %v", params.Context, err)
+	}
+
+	gvr, err := client.ResolveResourceType(params.ResourceType, "")
+	if err != nil {
+		if h.alwaysStart && connectivity.IsError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to resolve resource type: %v", err)
+	}
+
+	obj, err := client.GetResource(ctx, gvr, params.Namespace, params.Name)
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to get resource: %v", err)
+	}
+
+	spec, err := podSpecOf(obj)
+	if err != nil {
+		return response.Errorf("%v", err)
+	}
+
+	containers := resolveContainerSecurity(spec)
+	if params.Container != "" {
+		var selected []ContainerSecurity
+		for _, container := range containers {
+			if container.Name == params.Container {
+				selected = append(selected, container)
+			}
+		}
+
+		if len(selected) == 0 {
+			return response.Errorf("container %q not found in %s %s", params.Container, obj.GetKind(), obj.GetName())
+		}
+		containers = selected
+	}
+
+	var warnings []string
+
+	podSecurityLabels := map[string]string{}
+	if namespace, err := client.GetNamespace(ctx, obj.GetNamespace()); err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		warnings = append(warnings, fmt.Sprintf("failed to read the Pod Security Admission labels of namespace %s: %v", obj.GetNamespace(), err))
+	} else {
+		for key, value := range namespace.Labels {
+			if strings.HasPrefix(key, podSecurityLabelPrefix) {
+				podSecurityLabels[strings.TrimPrefix(key, podSecurityLabelPrefix)] = value
+			}
+		}
+	}
+
+	return response.JSON(ResolveSecurityContextResult{
+		Kind:                 obj.GetKind(),
+		Namespace:            obj.GetNamespace(),
+		Name:                 obj.GetName(),
+		Pod:                  resolvePodSecurity(spec),
+		Containers:           containers,
+		NamespacePodSecurity: podSecurityLabels,
+		Warnings:             warnings,
+	})
+}
+
+// resolvePodSecurity collects the pod-level security settings and flags the
+// ones that share host namespaces with the pod.
+func resolvePodSecurity(spec *corev1.PodSpec) PodSecurity {
+	out := PodSecurity{
+		HostNetwork:           spec.HostNetwork,
+		HostPID:               spec.HostPID,
+		HostIPC:               spec.HostIPC,
+		HostUsers:             spec.HostUsers == nil || *spec.HostUsers,
+		ShareProcessNamespace: spec.ShareProcessNamespace != nil && *spec.ShareProcessNamespace,
+		Concerns:              []string{},
+	}
+
+	if sc := spec.SecurityContext; sc != nil {
+		out.FSGroup = sc.FSGroup
+		if sc.FSGroupChangePolicy != nil {
+			out.FSGroupChangePolicy = string(*sc.FSGroupChangePolicy)
+		}
+		out.SupplementalGroups = sc.SupplementalGroups
+		if len(sc.Sysctls) > 0 {
+			out.Sysctls = make(map[string]string, len(sc.Sysctls))
+			for _, sysctl := range sc.Sysctls {
+				out.Sysctls[sysctl.Name] = sysctl.Value
+			}
+		}
+	}
+
+	if out.HostNetwork {
+		out.Concerns = append(out.Concerns, "hostNetwork is true: the pod shares the node's network namespace")
+	}
+	if out.HostPID {
+		out.Concerns = append(out.Concerns, "hostPID is true: the pod can see and signal every process on the node")
+	}
+	if out.HostIPC {
+		out.Concerns = append(out.Concerns, "hostIPC is true: the pod shares the node's IPC namespace")
+	}
+
+	return out
+}
+
+// resolveContainerSecurity returns the effective security context of every
+// init, regular, and ephemeral container in the pod spec, in that order.
+func resolveContainerSecurity(spec *corev1.PodSpec) []ContainerSecurity {
+	out := make([]ContainerSecurity, 0, len(spec.InitContainers)+len(spec.Containers)+len(spec.EphemeralContainers))
+
+	for _, container := range spec.InitContainers {
+		kind := "init"
+		if container.RestartPolicy != nil && *container.RestartPolicy == corev1.ContainerRestartPolicyAlways {
+			kind = "sidecar"
+		}
+		out = append(out, resolveSecurity(container.Name, kind, spec.SecurityContext, container.SecurityContext))
+	}
+
+	for _, container := range spec.Containers {
+		out = append(out, resolveSecurity(container.Name, "container", spec.SecurityContext, container.SecurityContext))
+	}
+
+	for _, container := range spec.EphemeralContainers {
+		out = append(out, resolveSecurity(container.Name, "ephemeral", spec.SecurityContext, container.SecurityContext))
+	}
+
+	return out
+}
+
+// resolveSecurity merges a pod security context with a container security
+// context. Either can be nil.
+func resolveSecurity(name, kind string, pod *corev1.PodSecurityContext, container *corev1.SecurityContext) ContainerSecurity {
+	if pod == nil {
+		pod = &corev1.PodSecurityContext{}
+	}
+	if container == nil {
+		container = &corev1.SecurityContext{}
+	}
+
+	out := ContainerSecurity{
+		Name:     name,
+		Type:     kind,
+		Concerns: []string{},
+	}
+
+	// Settings the container overrides from the pod.
+	out.RunAsUser = layered(container.RunAsUser, pod.RunAsUser, nil, "the image's USER, which is root (0) when the image sets none")
+	out.RunAsGroup = layered(container.RunAsGroup, pod.RunAsGroup, nil, "the image's primary group")
+	out.RunAsNonRoot = layered(container.RunAsNonRoot, pod.RunAsNonRoot, false, "the kubelet does not check that the container runs as a non-root user")
+	out.SeccompProfile = layered(seccompValue(container.SeccompProfile), seccompValue(pod.SeccompProfile), string(corev1.SeccompProfileTypeUnconfined), "Unconfined unless the kubelet runs with --seccomp-default, which makes it RuntimeDefault")
+	out.AppArmorProfile = layered(appArmorValue(container.AppArmorProfile), appArmorValue(pod.AppArmorProfile), string(corev1.AppArmorProfileTypeRuntimeDefault), "RuntimeDefault on nodes with AppArmor enabled")
+	out.SELinuxOptions = layered(container.SELinuxOptions, pod.SELinuxOptions, nil, "a label assigned by the container runtime")
+
+	// Settings that only exist on the container.
+	privileged := container.Privileged != nil && *container.Privileged
+	out.Privileged = layered(container.Privileged, nil, false, "")
+	out.ReadOnlyRootFilesystem = layered(container.ReadOnlyRootFilesystem, nil, false, "")
+	out.ProcMount = layered(procMountValue(container.ProcMount), nil, string(corev1.DefaultProcMount), "")
+	out.AllowPrivilegeEscalation = layered(container.AllowPrivilegeEscalation, nil, true, "allowed unless set to false, so setuid binaries can gain privileges")
+	if privileged {
+		out.AllowPrivilegeEscalation = ResolvedSetting{Value: true, Source: sourceContainer, Note: "always allowed for privileged containers"}
+	}
+	out.Capabilities = resolveCapabilities(container.Capabilities, privileged)
+
+	// Concerns, roughly ordered by impact.
+	if privileged {
+		out.Concerns = append(out.Concerns, "runs privileged, with access to every device and capability of the node")
+	}
+	if allowed, _ := out.AllowPrivilegeEscalation.Value.(bool); allowed && !privileged {
+		out.Concerns = append(out.Concerns, "allowPrivilegeEscalation is not set to false")
+	}
+
+	runAsNonRoot, _ := out.RunAsNonRoot.Value.(bool)
+	switch uid, ok := out.RunAsUser.Value.(int64); {
+	case ok && uid == 0:
+		out.Concerns = append(out.Concerns, fmt.Sprintf("runs as root: runAsUser is 0, set at the %s level", out.RunAsUser.Source))
+	case out.RunAsUser.Value == nil && !runAsNonRoot:
+		out.Concerns = append(out.Concerns, "may run as root: neither runAsUser nor runAsNonRoot is set, so the image decides")
+	}
+
+	for _, capability := range out.Capabilities.Added {
+		if capability != "NET_BIND_SERVICE" {
+			out.Concerns = append(out.Concerns, fmt.Sprintf("adds capability %s", capability))
+		}
+	}
+	if !privileged && !containsString(out.Capabilities.Dropped, "ALL") {
+		out.Concerns = append(out.Concerns, "does not drop ALL capabilities")
+	}
+
+	if out.SeccompProfile.Value == string(corev1.SeccompProfileTypeUnconfined) {
+		if out.SeccompProfile.Source == sourceDefault {
+			out.Concerns = append(out.Concerns, "no seccomp profile is set, so the container is Unconfined unless the kubelet defaults to RuntimeDefault")
+		} else {
+			out.Concerns = append(out.Concerns, "seccomp profile is explicitly Unconfined")
+		}
+	}
+	if out.AppArmorProfile.Value == string(corev1.AppArmorProfileTypeUnconfined) {
+		out.Concerns = append(out.Concerns, "AppArmor profile is Unconfined")
+	}
+	if out.ProcMount.Value == string(corev1.UnmaskedProcMount) {
+		out.Concerns = append(out.Concerns, "procMount is Unmasked: /proc is not masked")
+	}
+	if readOnly, _ := out.ReadOnlyRootFilesystem.Value.(bool); !readOnly {
+		out.Concerns = append(out.Concerns, "root filesystem is writable")
+	}
+
+	return out
+}
+
+// layered picks the container value of a setting over the pod value, and
+// falls back to fallback, described by note, when neither is set. Values are
+// pointers or nil interfaces; a nil pointer counts as unset, and set pointers
+// are dereferenced.
+func layered(container, pod, fallback interface{}, note string) ResolvedSetting {
+	if value, ok := setValue(container); ok {
+		return ResolvedSetting{Value: value, Source: sourceContainer}
+	}
+
+	if value, ok := setValue(pod); ok {
+		return ResolvedSetting{Value: value, Source: sourcePod}
+	}
+
+	return ResolvedSetting{Value: fallback, Source: sourceDefault, Note: note}
+}
+
+// setValue dereferences the pointer types the security context uses and
+// reports whether v is set at all.
+func setValue(v interface{}) (interface{}, bool) {
+	switch value := v.(type) {
+	case nil:
+		return nil, false
+	case *int64:
+		if value == nil {
+			return nil, false
+		}
+		return *value, true
+	case *bool:
+		if value == nil {
+			return nil, false
+		}
+		return *value, true
+	case *corev1.SELinuxOptions:
+		if value == nil {
+			return nil, false
+		}
+		return value, true
+	default:
+		return v, true
+	}
+}
+
+// seccompValue describes a seccomp profile as its type, or as
+// Localhost/<path> for node-local profiles.
+func seccompValue(profile *corev1.SeccompProfile) interface{} {
+	if profile == nil {
+		return nil
+	}
+
+	if profile.Type == corev1.SeccompProfileTypeLocalhost && profile.LocalhostProfile != nil {
+		return fmt.Sprintf("%s/%s", profile.Type, *profile.LocalhostProfile)
+	}
+
+	return string(profile.Type)
+}
+
+// appArmorValue describes an AppArmor profile as its type, or as
+// Localhost/<name> for node-local profiles.
+func appArmorValue(profile *corev1.AppArmorProfile) interface{} {
+	if profile == nil {
+		return nil
+	}
+
+	if profile.Type == corev1.AppArmorProfileTypeLocalhost && profile.LocalhostProfile != nil {
+		return fmt.Sprintf("%s/%s", profile.Type, *profile.LocalhostProfile)
+	}
+
+	return string(profile.Type)
+}
+
+// procMountValue returns the proc mount type as a plain string.
+func procMountValue(procMount *corev1.ProcMountType) interface{} {
+	if procMount == nil {
+		return nil
+	}
+
+	return string(*procMount)
+}
+
+// resolveCapabilities computes the capabilities a container ends up with:
+// the runtime defaults, minus the dropped ones, plus the added ones.
+// Privileged containers get every capability regardless.
+func resolveCapabilities(capabilities *corev1.Capabilities, privileged bool) EffectiveCapabilities {
+	out := EffectiveCapabilities{
+		Added:   []string{},
+		Dropped: []string{},
+	}
+
+	if capabilities != nil {
+		for _, capability := range capabilities.Add {
+			out.Added = append(out.Added, normalizeCapability(capability))
+		}
+		for _, capability := range capabilities.Drop {
+			out.Dropped = append(out.Dropped, normalizeCapability(capability))
+		}
+	}
+
+	if privileged {
+		out.Effective = []string{"ALL"}
+		out.Note = "privileged containers get every capability, whatever is added or dropped"
+		return out
+	}
+
+	if containsString(out.Added, "ALL") {
+		out.Effective = []string{"ALL"}
+		return out
+	}
+
+	effective := map[string]bool{}
+	if !containsString(out.Dropped, "ALL") {
+		for _, capability := range runtimeDefaultCapabilities {
+			effective[capability] = true
+		}
+		out.Note = "assumes the containerd default capability set; CRI-O grants fewer by default"
+	}
+
+	for _, capability := range out.Dropped {
+		delete(effective, capability)
+	}
+	for _, capability := range out.Added {
+		effective[capability] = true
+	}
+
+	out.Effective = make([]string, 0, len(effective))
+	for capability := range effective {
+		out.Effective = append(out.Effective, capability)
+	}
+	sort.Strings(out.Effective)
+
+	return out
+}
+
+// normalizeCapability upper-cases a capability name and strips the CAP_
+// prefix, which Kubernetes accepts but the runtime does not require.
+func normalizeCapability(capability corev1.Capability) string {
+	return strings.TrimPrefix(strings.ToUpper(string(capability)), "CAP_")
+}
+
+// containsString reports whether list contains s.
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,193 @@
+package handlers
+
+import (
+	"reflect"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/fakecluster"
+)
+
+func TestResolveSecurity(t *testing.T) {
+	t.Parallel()
+
+	int64p := func(v int64) *int64 { return &v }
+	boolp := func(v bool) *bool { return &v }
+
+	tests := []struct {
+		name          string
+		pod           *corev1.PodSecurityContext
+		container     *corev1.SecurityContext
+		wantUser      ResolvedSetting
+		wantSeccomp   ResolvedSetting
+		wantEscalate  interface{}
+		wantEffective []string
+		wantConcerns  []string
+	}{
+		{
+			name:         "nothing set falls back to defaults",
+			wantUser:     ResolvedSetting{Value: nil, Source: sourceDefault, Note: "the image's USER, which is root (0) when the image sets none"},
+			wantSeccomp:  ResolvedSetting{Value: "Unconfined", Source: sourceDefault, Note: "Unconfined unless the kubelet runs with --seccomp-default, which makes it RuntimeDefault"},
+			wantEscalate: true,
+			wantEffective: []string{
+				"AUDIT_WRITE", "CHOWN", "DAC_OVERRIDE", "FOWNER", "FSETID", "KILL", "MKNOD",
+				"NET_BIND_SERVICE", "NET_RAW", "SETFCAP", "SETGID", "SETPCAP", "SETUID", "SYS_CHROOT",
+			},
+			wantConcerns: []string{
+				"allowPrivilegeEscalation is not set to false",
+				"may run as root: neither runAsUser nor runAsNonRoot is set, so the image decides",
+				"does not drop ALL capabilities",
+				"no seccomp profile is set, so the container is Unconfined unless the kubelet defaults to RuntimeDefault",
+				"root filesystem is writable",
+			},
+		},
+		{
+			name: "pod settings apply when the container sets none",
+			pod: &corev1.PodSecurityContext{
+				RunAsUser:      int64p(1000),
+				SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault},
+			},
+			container: &corev1.SecurityContext{
+				AllowPrivilegeEscalation: boolp(false),
+				ReadOnlyRootFilesystem:   boolp(true),
+				Capabilities:             &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}, Add: []corev1.Capability{"NET_BIND_SERVICE"}},
+			},
+			wantUser:      ResolvedSetting{Value: int64(1000), Source: sourcePod},
+			wantSeccomp:   ResolvedSetting{Value: "RuntimeDefault", Source: sourcePod},
+			wantEscalate:  false,
+			wantEffective: []string{"NET_BIND_SERVICE"},
+			wantConcerns:  []string{},
+		},
+		{
+			name:         "container settings override the pod",
+			pod:          &corev1.PodSecurityContext{RunAsUser: int64p(1000), RunAsNonRoot: boolp(true)},
+			container:    &corev1.SecurityContext{RunAsUser: int64p(0), SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeUnconfined}, Capabilities: &corev1.Capabilities{Drop: []corev1.Capability{"cap_net_raw"}, Add: []corev1.Capability{"SYS_ADMIN"}}},
+			wantUser:     ResolvedSetting{Value: int64(0), Source: sourceContainer},
+			wantSeccomp:  ResolvedSetting{Value: "Unconfined", Source: sourceContainer},
+			wantEscalate: true,
+			wantEffective: []string{
+				"AUDIT_WRITE", "CHOWN", "DAC_OVERRIDE", "FOWNER", "FSETID", "KILL", "MKNOD",
+				"NET_BIND_SERVICE", "SETFCAP", "SETGID", "SETPCAP", "SETUID", "SYS_ADMIN", "SYS_CHROOT",
+			},
+			wantConcerns: []string{
+				"allowPrivilegeEscalation is not set to false",
+				"runs as root: runAsUser is 0, set at the container level",
+				"adds capability SYS_ADMIN",
+				"does not drop ALL capabilities",
+				"seccomp profile is explicitly Unconfined",
+				"root filesystem is writable",
+			},
+		},
+		{
+			name:          "privileged containers get every capability",
+			pod:           &corev1.PodSecurityContext{RunAsNonRoot: boolp(true), SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault}},
+			container:     &corev1.SecurityContext{Privileged: boolp(true), ReadOnlyRootFilesystem: boolp(true), Capabilities: &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}}},
+			wantUser:      ResolvedSetting{Value: nil, Source: sourceDefault, Note: "the image's USER, which is root (0) when the image sets none"},
+			wantSeccomp:   ResolvedSetting{Value: "RuntimeDefault", Source: sourcePod},
+			wantEscalate:  true,
+			wantEffective: []string{"ALL"},
+			wantConcerns: []string{
+				"runs privileged, with access to every device and capability of the node",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := resolveSecurity("app", "container", tt.pod, tt.container)
+
+			if !reflect.DeepEqual(got.RunAsUser, tt.wantUser) {
+				t.Errorf("run_as_user: expected %+v, got %+v", tt.wantUser, got.RunAsUser)
+			}
+			if !reflect.DeepEqual(got.SeccompProfile, tt.wantSeccomp) {
+				t.Errorf("seccomp_profile: expected %+v, got %+v", tt.wantSeccomp, got.SeccompProfile)
+			}
+			if !reflect.DeepEqual(got.AllowPrivilegeEscalation.Value, tt.wantEscalate) {
+				t.Errorf("allow_privilege_escalation: expected %v, got %v", tt.wantEscalate, got.AllowPrivilegeEscalation.Value)
+			}
+			if !reflect.DeepEqual(got.Capabilities.Effective, tt.wantEffective) {
+				t.Errorf("effective capabilities: expected %v, got %v", tt.wantEffective, got.Capabilities.Effective)
+			}
+			if !reflect.DeepEqual(got.Concerns, tt.wantConcerns) {
+				t.Errorf("concerns: expected %q, got %q", tt.wantConcerns, got.Concerns)
+			}
+		})
+	}
+}
+
+func TestResolveSecurityContext_FakeCluster(t *testing.T) {
+	t.Parallel()
+
+	nonRoot := true
+	always := corev1.ContainerRestartPolicyAlways
+
+	handler := NewPodHandler(fakecluster.New(fakecluster.Config{
+		Objects: []runtime.Object{
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "shop", Labels: map[string]string{
+				"pod-security.kubernetes.io/enforce": "baseline",
+				"pod-security.kubernetes.io/warn":    "restricted",
+				"team":                               "payments",
+			}}},
+			&appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop"},
+				Spec: appsv1.DeploymentSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+					HostNetwork:     true,
+					SecurityContext: &corev1.PodSecurityContext{RunAsNonRoot: &nonRoot},
+					InitContainers:  []corev1.Container{{Name: "proxy", RestartPolicy: &always}},
+					Containers:      []corev1.Container{{Name: "app"}},
+				}}},
+			},
+		},
+	}), false)
+
+	result, isErr := callTool(t, handler.ResolveSecurityContext, map[string]any{
+		"namespace":     "shop",
+		"name":          "web",
+		"resource_type": "deployments",
+	})
+	if isErr {
+		t.Fatalf("unexpected error: %v", result["error"])
+	}
+
+	var containers []ContainerSecurity
+	decodeInto(t, result["containers"], &containers)
+
+	if len(containers) != 2 {
+		t.Fatalf("expected 2 containers, got %d", len(containers))
+	}
+	if containers[0].Name != "proxy" || containers[0].Type != "sidecar" {
+		t.Errorf("expected the proxy sidecar first, got %s (%s)", containers[0].Name, containers[0].Type)
+	}
+	if got := containers[1].RunAsNonRoot; got.Value != true || got.Source != sourcePod {
+		t.Errorf("expected run_as_non_root true from the pod, got %+v", got)
+	}
+
+	var pod PodSecurity
+	decodeInto(t, result["pod"], &pod)
+	if !pod.HostNetwork || len(pod.Concerns) != 1 {
+		t.Errorf("expected hostNetwork to be flagged, got %+v", pod)
+	}
+
+	var labels map[string]string
+	decodeInto(t, result["namespace_pod_security"], &labels)
+	if want := map[string]string{"enforce": "baseline", "warn": "restricted"}; !reflect.DeepEqual(labels, want) {
+		t.Errorf("expected pod security labels %v, got %v", want, labels)
+	}
+
+	result, isErr = callTool(t, handler.ResolveSecurityContext, map[string]any{
+		"namespace":     "shop",
+		"name":          "web",
+		"resource_type": "deployments",
+		"container":     "missing",
+	})
+	if !isErr {
+		t.Fatalf("expected an error for an unknown container, got %v", result)
+	}
+}
@@ -0,0 +1,537 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/connectivity"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/resourcefilter"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/response"
+)
+
+// GetServiceAccountCredentialsParams defines the parameters for the get_service_account_credentials MCP tool.
+type GetServiceAccountCredentialsParams struct {
+	// Namespace specifies the namespace to map.
+	Namespace string `json:"namespace" required:"true" description:"Namespace to map ServiceAccounts and credentials for"`
+
+	// Name restricts the report to the ServiceAccount of a single pod or
+	// workload.
+	Name string `json:"name,omitempty" description:"Name of a pod or workload to report on (leave empty for every ServiceAccount in the namespace)"`
+
+	// ResourceType specifies the kind of object Name refers to.
+	ResourceType string `json:"resource_type,omitempty" default:"pods" description:"Resource type of the named object: pods, or a workload such as deployments, statefulsets, daemonsets, replicasets, jobs, or cronjobs (defaults to pods)"`
+
+	// Context specifies which Kubernetes context to use for this operation.
+	Context string `json:"context,omitempty" description:"Kubernetes context to use (defaults to current context from kubeconfig)"`
+}
+
+// ServiceAccountCredentials describes a ServiceAccount, the workloads that
+// run as it, and the credentials and permissions they get from it.
+type ServiceAccountCredentials struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+
+	// Missing is true when pods reference a ServiceAccount that does not
+	// exist.
+	Missing bool `json:"missing,omitempty"`
+
+	// AutomountToken reports whether pods get an API token for the
+	// ServiceAccount mounted by default, after a pod-level override when a
+	// single workload is reported.
+	AutomountToken bool `json:"automount_token"`
+
+	IdentityAnnotations map[string]string `json:"identity_annotations,omitempty"`
+
+	// Workloads are the controllers, or standalone pods, that run as the
+	// ServiceAccount, such as Deployment/web.
+	Workloads []string `json:"workloads,omitempty"`
+
+	ImagePullSecrets []CredentialSecret `json:"image_pull_secrets,omitempty"`
+
+	// TokenSecrets are legacy Secrets that hold a long-lived token for the
+	// ServiceAccount.
+	TokenSecrets []CredentialSecret `json:"token_secrets,omitempty"`
+
+	// BoundTokens are the serviceAccountToken projections pods declare,
+	// besides the default kube-api-access volume.
+	BoundTokens []ProjectedToken `json:"bound_tokens,omitempty"`
+	Bindings    []SubjectBinding `json:"bindings,omitempty"`
+	Findings    []string         `json:"findings,omitempty"`
+}
+
+// CredentialSecret is a Secret used as a credential, with its values
+// redacted: only its type and key names are reported.
+type CredentialSecret struct {
+	Name string `json:"name"`
+	Type string `json:"type,omitempty"`
+
+	// Via says where the Secret is referenced, such as serviceaccount or
+	// pod spec.
+	Via  string   `json:"via,omitempty"`
+	Keys []string `json:"keys,omitempty"`
+
+	// Registries are the registry hosts a docker config Secret has
+	// credentials for.
+	Registries []string `json:"registries,omitempty"`
+	Missing    bool     `json:"missing,omitempty"`
+}
+
+// GetServiceAccountCredentialsResult is the result of the
+// get_service_account_credentials MCP tool.
+type GetServiceAccountCredentialsResult struct {
+	Namespace              string                      `json:"namespace"`
+	Workload               string                      `json:"workload,omitempty"`
+	ServiceAccounts        []ServiceAccountCredentials `json:"service_accounts"`
+	Count                  int                         `json:"count"`
+	AccountsWithFindings   int                         `json:"accounts_with_findings"`
+	SecretValuesAreOmitted bool                        `json:"secret_values_are_omitted"`
+	Warnings               []string                    `json:"warnings,omitempty"`
+}
+
+// GetServiceAccountCredentials implements the get_service_account_credentials MCP tool.
+// It maps the ServiceAccounts of a namespace, or the one a single pod or
+// workload runs as, to the workloads that use them, their image pull
+// secrets, legacy token Secrets, projected tokens, and RBAC bindings. Secret
+// values are never read into the result: only names, types, key names, and
+// the registry hosts of docker config Secrets are reported. Pods, Secrets,
+// and bindings are read on a best-effort basis.
+func (h *AccessHandler) GetServiceAccountCredentials(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var params GetServiceAccountCredentialsParams
+	if err := bindParams(request, &params); err != nil {
+		return response.Errorf("failed to parse arguments: %s", err)
+	}
+
+	if params.ResourceType == "" {
+		params.ResourceType = "pods"
+	}
+
+	// Use the appropriate client based on context
+	client, err := h.client.ForContext(ctx, params.Context)
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to create client with context %s: %v", params.Context, err)
+	}
+
+	// Resolving the filter here also reports a filter that failed to
+	// initialize, before the other types are skipped as disabled.
+	if result, err := h.disabledResult("serviceaccounts", accessServiceAccountsGVR); result != nil || err != nil {
+		return result, err
+	}
+
+	var warnings []string
+	disabled := func(gvr schema.GroupVersionResource, skipped string) bool {
+		if !h.isDisabled(gvr) {
+			return false
+		}
+		warnings = append(warnings, fmt.Sprintf("%s are disabled by configuration, so %s", resourcefilter.FormatGVR(gvr), skipped))
+		return true
+	}
+
+	sources := &credentialSources{namespace: params.Namespace}
+
+	// A single workload reports the ServiceAccount of its pod spec;
+	// otherwise every pod in the namespace is read to find who uses what.
+	var pods []corev1.Pod
+	if params.Name != "" {
+		gvr, err := client.ResolveResourceType(params.ResourceType, "")
+		if err != nil {
+			if h.alwaysStart && connectivity.IsError(err) {
+				return response.Error(connectivity.ErrorMessage(err))
+			}
+			return response.Errorf("failed to resolve resource type: %v", err)
+		}
+		if result, err := h.disabledResult(params.ResourceType, gvr); result != nil || err != nil {
+			return result, err
+		}
+
+		obj, err := client.GetResource(ctx, gvr, params.Namespace, params.Name)
+		if err != nil {
+			if h.alwaysStart && connectivity.IsTransportError(err) {
+				return response.Error(connectivity.ErrorMessage(err))
+			}
+			return response.Errorf("failed to get resource: %v", err)
+		}
+
+		pod, err := schedulingPod(obj)
+		if err != nil {
+			return response.Errorf("%v", err)
+		}
+		pod.Name = obj.GetName()
+
+		sources.workload = obj.GetKind() + "/" + obj.GetName()
+		pods = []corev1.Pod{*pod}
+	} else if !disabled(accessPodsGVR, "the workloads using each ServiceAccount were not reported") {
+		list, err := client.ListPods(ctx, params.Namespace, metav1.ListOptions{})
+		if err != nil {
+			if h.alwaysStart && connectivity.IsTransportError(err) {
+				return response.Error(connectivity.ErrorMessage(err))
+			}
+			warnings = append(warnings, fmt.Sprintf("failed to list pods, the workloads using each ServiceAccount were not reported: %v", err))
+		} else {
+			pods = list.Items
+		}
+	}
+
+	accounts, err := client.ListServiceAccounts(ctx, params.Namespace, metav1.ListOptions{})
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to list service accounts: %v", err)
+	}
+	sources.accounts = accounts.Items
+
+	if !disabled(accessSecretsGVR, "image pull and token secrets were reported by name only") {
+		if list, err := client.ListSecrets(ctx, params.Namespace, metav1.ListOptions{}); err != nil {
+			if h.alwaysStart && connectivity.IsTransportError(err) {
+				return response.Error(connectivity.ErrorMessage(err))
+			}
+			warnings = append(warnings, fmt.Sprintf("failed to list secrets, image pull and token secrets were reported without their types and keys: %v", err))
+		} else {
+			sources.secrets = list.Items
+			sources.secretsListed = true
+		}
+	}
+
+	if !disabled(accessRoleBindingsGVR, "namespaced permissions were not reported") {
+		if list, err := client.ListRoleBindings(ctx, params.Namespace, metav1.ListOptions{}); err != nil {
+			if h.alwaysStart && connectivity.IsTransportError(err) {
+				return response.Error(connectivity.ErrorMessage(err))
+			}
+			warnings = append(warnings, fmt.Sprintf("failed to list role bindings, namespaced permissions were not reported: %v", err))
+		} else {
+			sources.roleBindings = list.Items
+		}
+	}
+
+	if !disabled(accessClusterRoleBindingsGVR, "cluster-wide permissions were not reported") {
+		if list, err := client.ListClusterRoleBindings(ctx, metav1.ListOptions{}); err != nil {
+			if h.alwaysStart && connectivity.IsTransportError(err) {
+				return response.Error(connectivity.ErrorMessage(err))
+			}
+			warnings = append(warnings, fmt.Sprintf("failed to list cluster role bindings, cluster-wide permissions were not reported: %v", err))
+		} else {
+			sources.clusterRoleBindings = list.Items
+		}
+	}
+
+	credentials := sources.credentials(pods)
+
+	withFindings := 0
+	for _, account := range credentials {
+		if len(account.Findings) > 0 {
+			withFindings++
+		}
+	}
+
+	return response.JSON(GetServiceAccountCredentialsResult{
+		Namespace:              params.Namespace,
+		Workload:               sources.workload,
+		ServiceAccounts:        credentials,
+		Count:                  len(credentials),
+		AccountsWithFindings:   withFindings,
+		SecretValuesAreOmitted: true,
+		Warnings:               warnings,
+	})
+}
+
+// credentialSources holds what get_service_account_credentials reads from a
+// namespace. workload is set when a single pod or workload is reported.
+type credentialSources struct {
+	namespace           string
+	workload            string
+	accounts            []corev1.ServiceAccount
+	secrets             []corev1.Secret
+	secretsListed       bool
+	roleBindings        []rbacv1.RoleBinding
+	clusterRoleBindings []rbacv1.ClusterRoleBinding
+}
+
+// credentials builds the report of every ServiceAccount the pods use. For
+// a whole namespace, unused ServiceAccounts are included too. Accounts with
+// findings come first.
+func (s *credentialSources) credentials(pods []corev1.Pod) []ServiceAccountCredentials {
+	byName := make(map[string]*ServiceAccountCredentials)
+	var order []string
+
+	get := func(name string) *ServiceAccountCredentials {
+		if entry, ok := byName[name]; ok {
+			return entry
+		}
+		entry := &ServiceAccountCredentials{Namespace: s.namespace, Name: name, Missing: true, AutomountToken: true}
+		byName[name] = entry
+		order = append(order, name)
+		return entry
+	}
+
+	accounts := make(map[string]*corev1.ServiceAccount, len(s.accounts))
+	for i := range s.accounts {
+		accounts[s.accounts[i].Name] = &s.accounts[i]
+		if s.workload == "" {
+			get(s.accounts[i].Name)
+		}
+	}
+
+	// Pod-level settings are collected per ServiceAccount before the
+	// ServiceAccount's own are applied.
+	podPullSecrets := make(map[string][]string)
+	var podAutomount *bool
+	tokens := make(map[string]map[string]ProjectedToken)
+	for i := range pods {
+		pod := &pods[i]
+		if s.workload == "" && (pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed) {
+			continue
+		}
+
+		name := pod.Spec.ServiceAccountName
+		if name == "" {
+			name = "default"
+		}
+		entry := get(name)
+
+		workload := s.workload
+		if workload == "" {
+			workload = podWorkload(pod)
+		}
+		if workload == "" {
+			workload = "Pod/" + pod.Name
+		}
+		if !containsString(entry.Workloads, workload) {
+			entry.Workloads = append(entry.Workloads, workload)
+		}
+
+		for _, ref := range pod.Spec.ImagePullSecrets {
+			if !containsString(podPullSecrets[name], ref.Name) {
+				podPullSecrets[name] = append(podPullSecrets[name], ref.Name)
+			}
+		}
+
+		if s.workload != "" {
+			podAutomount = pod.Spec.AutomountServiceAccountToken
+		}
+
+		for _, token := range projectedTokens(pod, false) {
+			// Identical projections of several pods are reported once.
+			token.MountPaths = nil
+			fingerprint, _ := json.Marshal(token)
+			if tokens[name] == nil {
+				tokens[name] = make(map[string]ProjectedToken)
+			}
+			tokens[name][string(fingerprint)] = token
+		}
+	}
+
+	secrets := make(map[string]*corev1.Secret, len(s.secrets))
+	for i := range s.secrets {
+		secrets[s.secrets[i].Name] = &s.secrets[i]
+	}
+
+	result := make([]ServiceAccountCredentials, 0, len(order))
+	for _, name := range order {
+		entry := byName[name]
+		account := accounts[name]
+
+		// The pod's automountServiceAccountToken overrides the
+		// ServiceAccount's, and both default to true.
+		switch {
+		case podAutomount != nil:
+			entry.AutomountToken = *podAutomount
+		case account != nil && account.AutomountServiceAccountToken != nil:
+			entry.AutomountToken = *account.AutomountServiceAccountToken
+		}
+
+		if account != nil {
+			entry.Missing = false
+			for _, identity := range workloadIdentities {
+				if value, ok := account.Annotations[identity.annotation]; ok {
+					if entry.IdentityAnnotations == nil {
+						entry.IdentityAnnotations = make(map[string]string)
+					}
+					entry.IdentityAnnotations[identity.annotation] = value
+				}
+			}
+			for _, ref := range account.ImagePullSecrets {
+				entry.ImagePullSecrets = append(entry.ImagePullSecrets, s.credentialSecret(secrets, ref.Name, "serviceaccount"))
+			}
+		}
+
+		for _, secretName := range podPullSecrets[name] {
+			entry.ImagePullSecrets = append(entry.ImagePullSecrets, s.credentialSecret(secrets, secretName, "pod spec"))
+		}
+
+		entry.TokenSecrets = s.tokenSecrets(account, name, secrets)
+
+		for _, token := range tokens[name] {
+			entry.BoundTokens = append(entry.BoundTokens, token)
+		}
+		sort.Slice(entry.BoundTokens, func(i, j int) bool {
+			if entry.BoundTokens[i].Volume != entry.BoundTokens[j].Volume {
+				return entry.BoundTokens[i].Volume < entry.BoundTokens[j].Volume
+			}
+			return entry.BoundTokens[i].Path < entry.BoundTokens[j].Path
+		})
+
+		entry.Bindings = subjectBindings(serviceAccountIdentity(s.namespace, name), s.roleBindings, s.clusterRoleBindings)
+		entry.Findings = s.credentialFindings(entry)
+
+		sort.Strings(entry.Workloads)
+		result = append(result, *entry)
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return findingsFirst(len(result[i].Findings), len(result[j].Findings), result[i].Name, result[j].Name)
+	})
+
+	return result
+}
+
+// credentialSecret describes a referenced Secret without its values. When
+// Secrets could not be listed, only the name and reference are known.
+func (s *credentialSources) credentialSecret(secrets map[string]*corev1.Secret, name, via string) CredentialSecret {
+	entry := CredentialSecret{Name: name, Via: via}
+	if !s.secretsListed {
+		return entry
+	}
+
+	secret, ok := secrets[name]
+	if !ok {
+		entry.Missing = true
+		return entry
+	}
+
+	entry.Type = string(secret.Type)
+	for key := range secret.Data {
+		entry.Keys = append(entry.Keys, key)
+	}
+	sort.Strings(entry.Keys)
+	entry.Registries = dockerConfigRegistries(secret)
+
+	return entry
+}
+
+// tokenSecrets lists the legacy Secrets that hold a long-lived token for a
+// ServiceAccount, found by their service-account.name annotation or listed
+// in the ServiceAccount's secrets field.
+func (s *credentialSources) tokenSecrets(account *corev1.ServiceAccount, name string, secrets map[string]*corev1.Secret) []CredentialSecret {
+	var names []string
+	for i := range s.secrets {
+		secret := &s.secrets[i]
+		if secret.Type == corev1.SecretTypeServiceAccountToken && secret.Annotations[corev1.ServiceAccountNameKey] == name {
+			names = append(names, secret.Name)
+		}
+	}
+
+	if account != nil {
+		for _, ref := range account.Secrets {
+			if ref.Name != "" && !containsString(names, ref.Name) {
+				// The secrets field may also list other Secrets mounted
+				// by pods, so only token Secrets are kept.
+				if secret, ok := secrets[ref.Name]; ok && secret.Type != corev1.SecretTypeServiceAccountToken {
+					continue
+				}
+				names = append(names, ref.Name)
+			}
+		}
+	}
+
+	sort.Strings(names)
+
+	result := make([]CredentialSecret, 0, len(names))
+	for _, secretName := range names {
+		result = append(result, s.credentialSecret(secrets, secretName, ""))
+	}
+	return result
+}
+
+// credentialFindings flags missing ServiceAccounts and pull secrets, pull
+// secrets of a type the kubelet ignores, long-lived tokens, cluster-admin
+// bindings, and permissions granted to the default ServiceAccount.
+func (s *credentialSources) credentialFindings(entry *ServiceAccountCredentials) []string {
+	var findings []string
+
+	if entry.Missing {
+		findings = append(findings, fmt.Sprintf("ServiceAccount %s does not exist, so new pods that use it are rejected", entry.Name))
+	}
+
+	for _, secret := range entry.ImagePullSecrets {
+		switch {
+		case secret.Missing:
+			findings = append(findings, fmt.Sprintf("image pull secret %s (from %s) does not exist, so pulls from private registries fail", secret.Name, secret.Via))
+		case secret.Type != "" && secret.Type != string(corev1.SecretTypeDockerConfigJson) && secret.Type != string(corev1.SecretTypeDockercfg):
+			findings = append(findings, fmt.Sprintf("image pull secret %s has type %s, which the kubelet does not use for image pulls; it must be %s", secret.Name, secret.Type, corev1.SecretTypeDockerConfigJson))
+		}
+	}
+
+	for _, secret := range entry.TokenSecrets {
+		if !secret.Missing {
+			findings = append(findings, fmt.Sprintf("Secret %s holds a long-lived token for this ServiceAccount that never expires; delete it unless something outside the cluster still depends on it", secret.Name))
+		}
+	}
+
+	for _, binding := range entry.Bindings {
+		if binding.Role != "ClusterRole/"+clusterAdminRole {
+			continue
+		}
+		finding := fmt.Sprintf("bound to %s through %s %s", clusterAdminRole, binding.Kind, binding.Name)
+		if binding.Via != "" {
+			finding += " (via " + binding.Via + ")"
+		}
+		finding += ", so its tokens have full control of the cluster"
+		if entry.AutomountToken && len(entry.Workloads) > 0 {
+			finding += ", and one is mounted into its pods"
+		}
+		findings = append(findings, finding)
+	}
+
+	if entry.Name == "default" && s.workload == "" && len(entry.Workloads) > 0 {
+		for _, binding := range entry.Bindings {
+			if binding.Via == "" {
+				findings = append(findings, "the default ServiceAccount has role bindings of its own, so every pod in the namespace that does not set serviceAccountName gets these permissions")
+				break
+			}
+		}
+	}
+
+	return findings
+}
+
+// dockerConfigRegistries returns the registry hosts a docker config Secret
+// has credentials for, without reading the credentials themselves.
+func dockerConfigRegistries(secret *corev1.Secret) []string {
+	var hosts map[string]json.RawMessage
+
+	switch secret.Type {
+	case corev1.SecretTypeDockerConfigJson:
+		var config struct {
+			Auths map[string]json.RawMessage `json:"auths"`
+		}
+		if err := json.Unmarshal(secret.Data[corev1.DockerConfigJsonKey], &config); err != nil {
+			return nil
+		}
+		hosts = config.Auths
+	case corev1.SecretTypeDockercfg:
+		if err := json.Unmarshal(secret.Data[corev1.DockerConfigKey], &hosts); err != nil {
+			return nil
+		}
+	default:
+		return nil
+	}
+
+	registries := make([]string, 0, len(hosts))
+	for host := range hosts {
+		registries = append(registries, host)
+	}
+	sort.Strings(registries)
+
+	return registries
+}
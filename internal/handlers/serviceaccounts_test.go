@@ -0,0 +1,302 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/fakecluster"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/kubernetes"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/resourcefilter"
+)
+
+func TestDockerConfigRegistries(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		secret corev1.Secret
+		want   []string
+	}{
+		{
+			name: "docker config json",
+			secret: corev1.Secret{Type: corev1.SecretTypeDockerConfigJson, Data: map[string][]byte{
+				corev1.DockerConfigJsonKey: []byte(`{"auths":{"ghcr.io":{"auth":"c2VjcmV0"},"registry.example.com":{"password":"secret"}}}`),
+			}},
+			want: []string{"ghcr.io", "registry.example.com"},
+		},
+		{
+			name: "legacy docker config",
+			secret: corev1.Secret{Type: corev1.SecretTypeDockercfg, Data: map[string][]byte{
+				corev1.DockerConfigKey: []byte(`{"quay.io":{"auth":"c2VjcmV0"}}`),
+			}},
+			want: []string{"quay.io"},
+		},
+		{
+			name: "invalid json",
+			secret: corev1.Secret{Type: corev1.SecretTypeDockerConfigJson, Data: map[string][]byte{
+				corev1.DockerConfigJsonKey: []byte(`not json`),
+			}},
+		},
+		{
+			name:   "opaque",
+			secret: corev1.Secret{Type: corev1.SecretTypeOpaque, Data: map[string][]byte{"auths": []byte(`{}`)}},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := dockerConfigRegistries(&tt.secret); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestSubjectBindings(t *testing.T) {
+	t.Parallel()
+
+	roleBindings := []rbacv1.RoleBinding{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "web-reader", Namespace: "shop"},
+			Subjects:   []rbacv1.Subject{{Kind: rbacv1.ServiceAccountKind, Name: "web"}},
+			RoleRef:    rbacv1.RoleRef{Kind: "Role", Name: "reader"},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "shop"},
+			Subjects:   []rbacv1.Subject{{Kind: rbacv1.ServiceAccountKind, Name: "web", Namespace: "batch"}},
+			RoleRef:    rbacv1.RoleRef{Kind: "Role", Name: "reader"},
+		},
+	}
+	clusterRoleBindings := []rbacv1.ClusterRoleBinding{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "all-accounts"},
+			Subjects:   []rbacv1.Subject{{Kind: rbacv1.GroupKind, Name: "system:serviceaccounts:shop"}},
+			RoleRef:    rbacv1.RoleRef{Kind: "ClusterRole", Name: "view"},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "by-user"},
+			Subjects:   []rbacv1.Subject{{Kind: rbacv1.UserKind, Name: "system:serviceaccount:shop:web"}},
+			RoleRef:    rbacv1.RoleRef{Kind: "ClusterRole", Name: "cluster-admin"},
+		},
+	}
+
+	want := []SubjectBinding{
+		{Kind: "RoleBinding", Namespace: "shop", Name: "web-reader", Role: "Role/reader"},
+		{Kind: "ClusterRoleBinding", Name: "all-accounts", Role: "ClusterRole/view", Via: "Group/system:serviceaccounts:shop"},
+		{Kind: "ClusterRoleBinding", Name: "by-user", Role: "ClusterRole/cluster-admin", Via: "User/system:serviceaccount:shop:web"},
+	}
+
+	if got := subjectBindings(serviceAccountIdentity("shop", "web"), roleBindings, clusterRoleBindings); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+}
+
+func TestGetServiceAccountCredentials_FakeCluster(t *testing.T) {
+	t.Parallel()
+
+	disabled := false
+	expiration := int64(86400)
+	controller := true
+
+	handler := NewAccessHandler(fakecluster.New(fakecluster.Config{
+		Objects: []runtime.Object{
+			&corev1.ServiceAccount{
+				ObjectMeta:       metav1.ObjectMeta{Name: "web", Namespace: "shop", Annotations: map[string]string{"eks.amazonaws.com/role-arn": "arn:aws:iam::123:role/web"}},
+				ImagePullSecrets: []corev1.LocalObjectReference{{Name: "registry"}, {Name: "gone"}},
+			},
+			&corev1.ServiceAccount{
+				ObjectMeta:                   metav1.ObjectMeta{Name: "default", Namespace: "shop"},
+				AutomountServiceAccountToken: &disabled,
+			},
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "registry", Namespace: "shop"},
+				Type:       corev1.SecretTypeDockerConfigJson,
+				Data:       map[string][]byte{corev1.DockerConfigJsonKey: []byte(`{"auths":{"ghcr.io":{"auth":"dG9wLXNlY3JldA=="}}}`)},
+			},
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "web-token", Namespace: "shop", Annotations: map[string]string{corev1.ServiceAccountNameKey: "web"}},
+				Type:       corev1.SecretTypeServiceAccountToken,
+				Data:       map[string][]byte{"token": []byte("top-secret"), "ca.crt": []byte("cert")},
+			},
+			&corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "web-abc-1", Namespace: "shop", Labels: map[string]string{appsv1.DefaultDeploymentUniqueLabelKey: "abc"},
+					OwnerReferences: []metav1.OwnerReference{{Kind: "ReplicaSet", Name: "web-abc", Controller: &controller}},
+				},
+				Spec: corev1.PodSpec{
+					ServiceAccountName: "web",
+					Volumes: []corev1.Volume{{Name: "aws-token", VolumeSource: corev1.VolumeSource{Projected: &corev1.ProjectedVolumeSource{
+						Sources: []corev1.VolumeProjection{{ServiceAccountToken: &corev1.ServiceAccountTokenProjection{Audience: "sts.amazonaws.com", ExpirationSeconds: &expiration, Path: "token"}}},
+					}}}},
+				},
+				Status: corev1.PodStatus{Phase: corev1.PodRunning},
+			},
+			&corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "debug", Namespace: "shop"},
+				Spec:       corev1.PodSpec{ServiceAccountName: "builder"},
+				Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+			},
+			&appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Name: "worker", Namespace: "shop"},
+				Spec: appsv1.DeploymentSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+					AutomountServiceAccountToken: &disabled,
+					Containers:                   []corev1.Container{{Name: "worker"}},
+				}}},
+			},
+			&rbacv1.ClusterRoleBinding{
+				ObjectMeta: metav1.ObjectMeta{Name: "web-admin"},
+				Subjects:   []rbacv1.Subject{{Kind: rbacv1.ServiceAccountKind, Name: "web", Namespace: "shop"}},
+				RoleRef:    rbacv1.RoleRef{Kind: "ClusterRole", Name: clusterAdminRole},
+			},
+		},
+	}), nil, false)
+
+	result, isErr := callTool(t, handler.GetServiceAccountCredentials, map[string]any{"namespace": "shop"})
+	if isErr {
+		t.Fatalf("unexpected error: %v", result["error"])
+	}
+
+	raw, err := json.Marshal(result)
+	if err != nil {
+		t.Fatalf("failed to encode result: %v", err)
+	}
+	if strings.Contains(string(raw), "top-secret") || strings.Contains(string(raw), "dG9wLXNlY3JldA") {
+		t.Fatalf("secret values leaked into the result: %s", raw)
+	}
+
+	var accounts []ServiceAccountCredentials
+	decodeInto(t, result["service_accounts"], &accounts)
+
+	names := make([]string, 0, len(accounts))
+	for _, account := range accounts {
+		names = append(names, account.Name)
+	}
+	if !reflect.DeepEqual(names, []string{"builder", "web", "default"}) {
+		t.Fatalf("expected accounts with findings first, got %v", names)
+	}
+
+	if !accounts[0].Missing || !reflect.DeepEqual(accounts[0].Workloads, []string{"Pod/debug"}) {
+		t.Errorf("expected the missing builder account used by the debug pod, got %+v", accounts[0])
+	}
+
+	web := accounts[1]
+	if !web.AutomountToken || !reflect.DeepEqual(web.Workloads, []string{"Deployment/web"}) || web.IdentityAnnotations["eks.amazonaws.com/role-arn"] == "" {
+		t.Errorf("unexpected web account %+v", web)
+	}
+	if len(web.ImagePullSecrets) != 2 || !reflect.DeepEqual(web.ImagePullSecrets[0].Registries, []string{"ghcr.io"}) || !web.ImagePullSecrets[1].Missing {
+		t.Errorf("unexpected image pull secrets %+v", web.ImagePullSecrets)
+	}
+	if len(web.TokenSecrets) != 1 || !reflect.DeepEqual(web.TokenSecrets[0].Keys, []string{"ca.crt", "token"}) {
+		t.Errorf("unexpected token secrets %+v", web.TokenSecrets)
+	}
+	if len(web.BoundTokens) != 1 || web.BoundTokens[0].Audience != "sts.amazonaws.com" || web.BoundTokens[0].ExpirationSeconds != 86400 {
+		t.Errorf("unexpected bound tokens %+v", web.BoundTokens)
+	}
+
+	wantFindings := []string{
+		"image pull secret gone (from serviceaccount) does not exist, so pulls from private registries fail",
+		"Secret web-token holds a long-lived token for this ServiceAccount that never expires; delete it unless something outside the cluster still depends on it",
+		"bound to cluster-admin through ClusterRoleBinding web-admin, so its tokens have full control of the cluster, and one is mounted into its pods",
+	}
+	if !reflect.DeepEqual(web.Findings, wantFindings) {
+		t.Errorf("expected findings %q, got %q", wantFindings, web.Findings)
+	}
+
+	if accounts[2].AutomountToken {
+		t.Errorf("expected the default account to disable automounting, got %+v", accounts[2])
+	}
+
+	result, isErr = callTool(t, handler.GetServiceAccountCredentials, map[string]any{"namespace": "shop", "name": "worker", "resource_type": "deployments"})
+	if isErr {
+		t.Fatalf("unexpected error: %v", result["error"])
+	}
+
+	decodeInto(t, result["service_accounts"], &accounts)
+	if result["workload"] != "Deployment/worker" || len(accounts) != 1 || accounts[0].Name != "default" || accounts[0].AutomountToken {
+		t.Errorf("expected only the default account of the worker, got %+v", accounts)
+	}
+}
+
+// secretListCounter counts the Secret lists made through it.
+type secretListCounter struct {
+	kubernetes.ClusterReader
+	lists *atomic.Int32
+}
+
+func (c secretListCounter) ForContext(context.Context, string) (kubernetes.ClusterReader, error) {
+	return c, nil
+}
+
+func (c secretListCounter) ListSecrets(ctx context.Context, namespace string, opts metav1.ListOptions) (*corev1.SecretList, error) {
+	c.lists.Add(1)
+	return c.ClusterReader.ListSecrets(ctx, namespace, opts) //nolint:wrapcheck // passed through unchanged
+}
+
+func TestGetServiceAccountCredentials_DisabledResources(t *testing.T) {
+	t.Parallel()
+
+	client := fakecluster.New(fakecluster.Config{
+		Objects: []runtime.Object{
+			&corev1.ServiceAccount{
+				ObjectMeta:       metav1.ObjectMeta{Name: "web", Namespace: "shop"},
+				ImagePullSecrets: []corev1.LocalObjectReference{{Name: "registry"}},
+			},
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "registry", Namespace: "shop"},
+				Type:       corev1.SecretTypeDockerConfigJson,
+				Data:       map[string][]byte{corev1.DockerConfigJsonKey: []byte(`{"auths":{"ghcr.io":{"auth":"c2VjcmV0"}}}`)},
+			},
+			&appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop"},
+				Spec: appsv1.DeploymentSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+					ServiceAccountName: "web",
+					Containers:         []corev1.Container{{Name: "web"}},
+				}}},
+			},
+		},
+	})
+
+	filter, err := resourcefilter.NewFilter("secrets,deployments", client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lists := &atomic.Int32{}
+	handler := NewAccessHandler(secretListCounter{ClusterReader: client, lists: lists}, filter, false)
+
+	result, isErr := callTool(t, handler.GetServiceAccountCredentials, map[string]any{"namespace": "shop"})
+	if isErr {
+		t.Fatalf("unexpected error: %v", result["error"])
+	}
+	if n := lists.Load(); n != 0 {
+		t.Fatalf("expected no Secret list with secrets disabled, got %d", n)
+	}
+
+	var accounts []ServiceAccountCredentials
+	decodeInto(t, result["service_accounts"], &accounts)
+	want := []CredentialSecret{{Name: "registry", Via: "serviceaccount"}}
+	if len(accounts) != 1 || !reflect.DeepEqual(accounts[0].ImagePullSecrets, want) {
+		t.Errorf("expected the pull secret by name only, got %+v", accounts)
+	}
+	if warnings, _ := result["warnings"].([]any); len(warnings) != 1 || !strings.Contains(fmt.Sprint(warnings[0]), "disabled by configuration") {
+		t.Errorf("expected a warning about the disabled secrets, got %v", result["warnings"])
+	}
+
+	result, isErr = callTool(t, handler.GetServiceAccountCredentials, map[string]any{"namespace": "shop", "name": "web", "resource_type": "deployments"})
+	if !isErr || !strings.Contains(fmt.Sprint(result["error"]), "disabled by configuration") {
+		t.Errorf("expected the disabled workload type to be refused, got %v", result)
+	}
+}
@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/kubernetes"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/toolschema"
+)
+
+// StorageHandler provides MCP tools that inspect persistent storage:
+// PersistentVolumes, PersistentVolumeClaims, the pods that mount them, and the
+// StorageClasses that provision them.
+type StorageHandler struct {
+	client      kubernetes.ClusterReader
+	alwaysStart bool
+}
+
+// NewStorageHandler creates a new StorageHandler with the provided Kubernetes client.
+// alwaysStart mirrors the --always-start flag: when true, connectivity and auth errors
+// are intercepted and returned as structured tool errors so the LLM can surface them
+// to the user rather than treating them as retryable failures.
+func NewStorageHandler(client kubernetes.ClusterReader, alwaysStart bool) *StorageHandler {
+	return &StorageHandler{
+		client:      client,
+		alwaysStart: alwaysStart,
+	}
+}
+
+// GetTools returns all storage MCP tools provided by this handler.
+func (h *StorageHandler) GetTools() []MCPTool {
+	return []MCPTool{
+		NewMCPTool(
+			mcp.NewTool("get_storage_status",
+				mcp.WithDescription("List PersistentVolumeClaims and PersistentVolumes with their binding status, storage class, requested and provisioned capacity, access modes, reclaim policy, and volume source, plus the pods that mount each claim. Flags claims that are still unbound or lost, claims being deleted or resized, and volumes that are released, failed, or bound to a claim that no longer exists."),
+				toolschema.Input[GetStorageStatusParams](),
+				toolschema.Output[GetStorageStatusResult](),
+			),
+			h.GetStorageStatus,
+		),
+		NewMCPTool(
+			mcp.NewTool("list_storage_classes",
+				mcp.WithDescription("List StorageClasses with their provisioner, reclaim policy, volume binding mode, volume expansion support, parameters, and the number of claims using each, and show which one is the cluster default. Flags a cluster without a default StorageClass, several default classes, unbound claims that name a StorageClass that does not exist, and local volume classes that bind immediately."),
+				toolschema.Input[ListStorageClassesParams](),
+				toolschema.Output[ListStorageClassesResult](),
+			),
+			h.ListStorageClasses,
+		),
+	}
+}
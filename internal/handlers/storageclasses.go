@@ -0,0 +1,266 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/connectivity"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/response"
+)
+
+const (
+	// defaultStorageClassAnnotation marks the StorageClass used for claims
+	// that omit storageClassName.
+	defaultStorageClassAnnotation = "storageclass.kubernetes.io/is-default-class"
+
+	// betaDefaultStorageClassAnnotation is the deprecated form of
+	// defaultStorageClassAnnotation, still honored by the API server.
+	betaDefaultStorageClassAnnotation = "storageclass.beta.kubernetes.io/is-default-class"
+
+	// noProvisioner is the provisioner of StorageClasses whose volumes are
+	// created by hand, such as local volumes.
+	noProvisioner = "kubernetes.io/no-provisioner"
+)
+
+// ListStorageClassesParams defines the parameters for the list_storage_classes MCP tool.
+type ListStorageClassesParams struct {
+	// Context specifies which Kubernetes context to use for this operation.
+	Context string `json:"context,omitempty" description:"Kubernetes context to use (defaults to current context from kubeconfig)"`
+}
+
+// StorageClassSummary describes a StorageClass with its effective settings.
+type StorageClassSummary struct {
+	Name                 string            `json:"name"`
+	Provisioner          string            `json:"provisioner"`
+	ReclaimPolicy        string            `json:"reclaim_policy"`
+	VolumeBindingMode    string            `json:"volume_binding_mode"`
+	AllowVolumeExpansion bool              `json:"allow_volume_expansion"`
+	IsDefault            bool              `json:"is_default"`
+	Parameters           map[string]string `json:"parameters,omitempty"`
+	MountOptions         []string          `json:"mount_options,omitempty"`
+
+	// Claims is the number of PersistentVolumeClaims that use the class.
+	Claims   int      `json:"claims"`
+	Age      string   `json:"age"`
+	Findings []string `json:"findings,omitempty"`
+}
+
+// ListStorageClassesResult is the result of the list_storage_classes MCP
+// tool.
+type ListStorageClassesResult struct {
+	StorageClasses      []StorageClassSummary `json:"storage_classes"`
+	Count               int                   `json:"count"`
+	DefaultStorageClass string                `json:"default_storage_class"`
+	Findings            []string              `json:"findings,omitempty"`
+	Warnings            []string              `json:"warnings,omitempty"`
+}
+
+// ListStorageClasses implements the list_storage_classes MCP tool.
+// It lists StorageClasses with their provisioner, reclaim policy, binding
+// mode, and whether they are the cluster default, and explains the
+// misconfigurations that leave claims unprovisioned: no default class,
+// several default classes, and claims naming classes that do not exist.
+func (h *StorageHandler) ListStorageClasses(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var params ListStorageClassesParams
+	if err := bindParams(request, &params); err != nil {
+		return response.Errorf("failed to parse arguments: %s", err)
+	}
+
+	// Use the appropriate client based on context
+	client, err := h.client.ForContext(ctx, params.Context)
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to create client with context %s: %v", params.Context, err)
+	}
+
+	classList, err := client.ListStorageClasses(ctx, metav1.ListOptions{})
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to list storage classes: %v", err)
+	}
+
+	var warnings []string
+
+	claimList, err := client.ListPersistentVolumeClaims(ctx, "", metav1.ListOptions{})
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		warnings = append(warnings, fmt.Sprintf("failed to list persistent volume claims, claim counts and claim findings are omitted: %v", err))
+		claimList = &corev1.PersistentVolumeClaimList{}
+	}
+
+	now := time.Now()
+
+	classes := make([]StorageClassSummary, 0, len(classList.Items))
+	classIndex := make(map[string]int, len(classList.Items))
+	var defaults []*storagev1.StorageClass
+
+	for i := range classList.Items {
+		class := &classList.Items[i]
+		if isDefaultStorageClass(class) {
+			defaults = append(defaults, class)
+		}
+		classIndex[class.Name] = len(classes)
+		classes = append(classes, storageClassSummary(class, now))
+	}
+
+	// Claims that omit storageClassName and are still unbound wait for a
+	// default class; unbound claims naming a missing class are never
+	// provisioned.
+	var waitingForDefault []string
+	missingClasses := make(map[string][]string)
+
+	for i := range claimList.Items {
+		claim := &claimList.Items[i]
+		key := claim.Namespace + "/" + claim.Name
+		unbound := claim.Status.Phase != corev1.ClaimBound && claim.Spec.VolumeName == ""
+
+		name := claimStorageClass(claim)
+		if name == "" {
+			if unbound && claim.Spec.StorageClassName == nil && claim.Annotations[corev1.BetaStorageClassAnnotation] == "" {
+				waitingForDefault = append(waitingForDefault, key)
+			}
+			continue
+		}
+
+		if idx, ok := classIndex[name]; ok {
+			classes[idx].Claims++
+		} else if unbound {
+			missingClasses[name] = append(missingClasses[name], key)
+		}
+	}
+
+	var findings []string
+	defaultClass := ""
+
+	switch len(defaults) {
+	case 0:
+		finding := "no StorageClass is marked as the default, so PersistentVolumeClaims that omit storageClassName are not provisioned dynamically"
+		if len(classes) == 0 {
+			finding = "no StorageClasses exist, so PersistentVolumeClaims can only bind to PersistentVolumes created by hand"
+		}
+		if len(waitingForDefault) > 0 {
+			sort.Strings(waitingForDefault)
+			finding += fmt.Sprintf("; %d unbound claims are waiting for a default (%s)", len(waitingForDefault), strings.Join(truncate(waitingForDefault, 10), ", "))
+		}
+		findings = append(findings, finding)
+
+	case 1:
+		defaultClass = defaults[0].Name
+
+	default:
+		// The API server picks the most recently created default class,
+		// breaking ties by name.
+		sort.Slice(defaults, func(i, j int) bool {
+			a, b := defaults[i].CreationTimestamp, defaults[j].CreationTimestamp
+			if !a.Equal(&b) {
+				return b.Before(&a)
+			}
+			return defaults[i].Name < defaults[j].Name
+		})
+		defaultClass = defaults[0].Name
+
+		names := make([]string, 0, len(defaults))
+		for _, class := range defaults {
+			names = append(names, class.Name)
+		}
+		sort.Strings(names)
+		findings = append(findings, fmt.Sprintf("%d StorageClasses are marked as the default (%s); claims that omit storageClassName get the most recently created one, %q, on Kubernetes 1.26 and newer, and are rejected by older API servers", len(defaults), strings.Join(names, ", "), defaultClass))
+	}
+
+	missingNames := make([]string, 0, len(missingClasses))
+	for name := range missingClasses {
+		missingNames = append(missingNames, name)
+	}
+	sort.Strings(missingNames)
+
+	for _, name := range missingNames {
+		claims := missingClasses[name]
+		sort.Strings(claims)
+		findings = append(findings, fmt.Sprintf("StorageClass %q does not exist, so %d unbound claims that use it are not provisioned (%s)", name, len(claims), strings.Join(truncate(claims, 10), ", ")))
+	}
+
+	if defaultClass != "" {
+		if idx, ok := classIndex[defaultClass]; ok && classes[idx].Provisioner == noProvisioner {
+			classes[idx].Findings = append(classes[idx].Findings, "is the default but does not provision volumes, so claims that omit storageClassName wait for a PersistentVolume created by hand")
+		}
+	}
+
+	// The default class first, then by name
+	sort.SliceStable(classes, func(i, j int) bool {
+		a, b := classes[i], classes[j]
+		if (a.Name == defaultClass) != (b.Name == defaultClass) {
+			return a.Name == defaultClass
+		}
+		return a.Name < b.Name
+	})
+
+	return response.JSON(ListStorageClassesResult{
+		StorageClasses:      classes,
+		Count:               len(classes),
+		DefaultStorageClass: defaultClass,
+		Findings:            findings,
+		Warnings:            warnings,
+	})
+}
+
+// storageClassSummary describes a StorageClass, filling in the API server's
+// defaults for fields left unset, and flags settings that are likely
+// mistakes.
+func storageClassSummary(class *storagev1.StorageClass, now time.Time) StorageClassSummary {
+	entry := StorageClassSummary{
+		Name:              class.Name,
+		Provisioner:       class.Provisioner,
+		ReclaimPolicy:     string(corev1.PersistentVolumeReclaimDelete),
+		VolumeBindingMode: string(storagev1.VolumeBindingImmediate),
+		IsDefault:         isDefaultStorageClass(class),
+		Parameters:        class.Parameters,
+		MountOptions:      class.MountOptions,
+		Age:               now.Sub(class.CreationTimestamp.Time).Round(time.Second).String(),
+	}
+
+	if class.ReclaimPolicy != nil {
+		entry.ReclaimPolicy = string(*class.ReclaimPolicy)
+	}
+
+	if class.VolumeBindingMode != nil {
+		entry.VolumeBindingMode = string(*class.VolumeBindingMode)
+	}
+
+	if class.AllowVolumeExpansion != nil {
+		entry.AllowVolumeExpansion = *class.AllowVolumeExpansion
+	}
+
+	if class.Annotations[defaultStorageClassAnnotation] != "true" && class.Annotations[betaDefaultStorageClassAnnotation] == "true" {
+		entry.Findings = append(entry.Findings, fmt.Sprintf("is marked as the default with the deprecated %s annotation; use %s instead", betaDefaultStorageClassAnnotation, defaultStorageClassAnnotation))
+	}
+
+	if value, ok := class.Annotations[defaultStorageClassAnnotation]; ok && value != "true" && value != "false" {
+		entry.Findings = append(entry.Findings, fmt.Sprintf("has %s set to %q, which is not the default; only \"true\" marks a default class", defaultStorageClassAnnotation, value))
+	}
+
+	if entry.Provisioner == noProvisioner && entry.VolumeBindingMode == string(storagev1.VolumeBindingImmediate) {
+		entry.Findings = append(entry.Findings, "does not provision volumes but binds claims immediately; local volumes need WaitForFirstConsumer so a claim binds to a volume on the node its pod is scheduled to")
+	}
+
+	return entry
+}
+
+// isDefaultStorageClass reports whether a StorageClass is marked as the
+// default, which the API server only recognizes with the value "true".
+func isDefaultStorageClass(class *storagev1.StorageClass) bool {
+	return class.Annotations[defaultStorageClassAnnotation] == "true" || class.Annotations[betaDefaultStorageClassAnnotation] == "true"
+}
@@ -0,0 +1,191 @@
+package handlers
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/fakecluster"
+)
+
+func TestStorageClassSummary(t *testing.T) {
+	t.Parallel()
+
+	waitForConsumer := storagev1.VolumeBindingWaitForFirstConsumer
+	retain := corev1.PersistentVolumeReclaimRetain
+
+	tests := []struct {
+		name        string
+		class       storagev1.StorageClass
+		wantDefault bool
+		wantPolicy  string
+		wantMode    string
+		want        []string
+	}{
+		{
+			name:       "api server defaults",
+			class:      storagev1.StorageClass{Provisioner: "ebs.csi.aws.com"},
+			wantPolicy: "Delete",
+			wantMode:   "Immediate",
+		},
+		{
+			name: "default class",
+			class: storagev1.StorageClass{
+				ObjectMeta:        metav1.ObjectMeta{Annotations: map[string]string{defaultStorageClassAnnotation: "true"}},
+				Provisioner:       "ebs.csi.aws.com",
+				ReclaimPolicy:     &retain,
+				VolumeBindingMode: &waitForConsumer,
+			},
+			wantDefault: true,
+			wantPolicy:  "Retain",
+			wantMode:    "WaitForFirstConsumer",
+		},
+		{
+			name: "beta annotation",
+			class: storagev1.StorageClass{
+				ObjectMeta:        metav1.ObjectMeta{Annotations: map[string]string{betaDefaultStorageClassAnnotation: "true"}},
+				Provisioner:       "ebs.csi.aws.com",
+				VolumeBindingMode: &waitForConsumer,
+			},
+			wantDefault: true,
+			wantPolicy:  "Delete",
+			wantMode:    "WaitForFirstConsumer",
+			want:        []string{"is marked as the default with the deprecated storageclass.beta.kubernetes.io/is-default-class annotation; use storageclass.kubernetes.io/is-default-class instead"},
+		},
+		{
+			name: "annotation value that is not true",
+			class: storagev1.StorageClass{
+				ObjectMeta:        metav1.ObjectMeta{Annotations: map[string]string{defaultStorageClassAnnotation: "True"}},
+				Provisioner:       "ebs.csi.aws.com",
+				VolumeBindingMode: &waitForConsumer,
+			},
+			wantPolicy: "Delete",
+			wantMode:   "WaitForFirstConsumer",
+			want:       []string{`has storageclass.kubernetes.io/is-default-class set to "True", which is not the default; only "true" marks a default class`},
+		},
+		{
+			name:       "local volumes bound immediately",
+			class:      storagev1.StorageClass{Provisioner: noProvisioner},
+			wantPolicy: "Delete",
+			wantMode:   "Immediate",
+			want:       []string{"does not provision volumes but binds claims immediately; local volumes need WaitForFirstConsumer so a claim binds to a volume on the node its pod is scheduled to"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := storageClassSummary(&tt.class, time.Now())
+			if got.IsDefault != tt.wantDefault || got.ReclaimPolicy != tt.wantPolicy || got.VolumeBindingMode != tt.wantMode {
+				t.Errorf("expected default=%v policy=%s mode=%s, got %+v", tt.wantDefault, tt.wantPolicy, tt.wantMode, got)
+			}
+			if !reflect.DeepEqual(got.Findings, tt.want) {
+				t.Errorf("expected findings %q, got %q", tt.want, got.Findings)
+			}
+		})
+	}
+}
+
+func TestListStorageClasses_FakeCluster(t *testing.T) {
+	t.Parallel()
+
+	standard, fast := "standard", "fast"
+	waitForConsumer := storagev1.VolumeBindingWaitForFirstConsumer
+
+	claims := []runtime.Object{
+		&corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: "data-db-0", Namespace: "shop"},
+			Spec:       corev1.PersistentVolumeClaimSpec{StorageClassName: &standard, VolumeName: "pv-db"},
+			Status:     corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimBound},
+		},
+		&corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: "uploads", Namespace: "shop"},
+			Status:     corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimPending},
+		},
+		&corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: "cache", Namespace: "ops"},
+			Spec:       corev1.PersistentVolumeClaimSpec{StorageClassName: &fast},
+			Status:     corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimPending},
+		},
+	}
+
+	handler := NewStorageHandler(fakecluster.New(fakecluster.Config{
+		Objects: append([]runtime.Object{
+			&storagev1.StorageClass{
+				ObjectMeta:        metav1.ObjectMeta{Name: "standard"},
+				Provisioner:       "ebs.csi.aws.com",
+				VolumeBindingMode: &waitForConsumer,
+				Parameters:        map[string]string{"type": "gp3"},
+			},
+		}, claims...),
+	}), false)
+
+	result, isErr := callTool(t, handler.ListStorageClasses, map[string]any{})
+	if isErr {
+		t.Fatalf("unexpected error: %v", result["error"])
+	}
+
+	if result["default_storage_class"] != "" {
+		t.Errorf("expected no default storage class, got %v", result["default_storage_class"])
+	}
+
+	var findings []string
+	decodeInto(t, result["findings"], &findings)
+	if len(findings) != 2 {
+		t.Fatalf("expected findings for the missing default and the missing class, got %q", findings)
+	}
+	if !strings.HasPrefix(findings[0], "no StorageClass is marked as the default") || !strings.Contains(findings[0], "shop/uploads") {
+		t.Errorf("expected the missing default to name the waiting claim, got %q", findings[0])
+	}
+	if !strings.Contains(findings[1], `StorageClass "fast" does not exist`) || !strings.Contains(findings[1], "ops/cache") {
+		t.Errorf("expected the missing class to name its claim, got %q", findings[1])
+	}
+
+	var classes []StorageClassSummary
+	decodeInto(t, result["storage_classes"], &classes)
+	if len(classes) != 1 || classes[0].Claims != 1 || classes[0].Parameters["type"] != "gp3" {
+		t.Errorf("unexpected storage classes %+v", classes)
+	}
+
+	older := metav1.NewTime(time.Now().Add(-time.Hour))
+	newer := metav1.NewTime(time.Now())
+	handler = NewStorageHandler(fakecluster.New(fakecluster.Config{
+		Objects: []runtime.Object{
+			&storagev1.StorageClass{
+				ObjectMeta:  metav1.ObjectMeta{Name: "gp2", CreationTimestamp: older, Annotations: map[string]string{defaultStorageClassAnnotation: "true"}},
+				Provisioner: "kubernetes.io/aws-ebs",
+			},
+			&storagev1.StorageClass{
+				ObjectMeta:  metav1.ObjectMeta{Name: "gp3", CreationTimestamp: newer, Annotations: map[string]string{defaultStorageClassAnnotation: "true"}},
+				Provisioner: "ebs.csi.aws.com",
+			},
+		},
+	}), false)
+
+	result, isErr = callTool(t, handler.ListStorageClasses, map[string]any{})
+	if isErr {
+		t.Fatalf("unexpected error: %v", result["error"])
+	}
+
+	if result["default_storage_class"] != "gp3" {
+		t.Errorf("expected the newest default class to win, got %v", result["default_storage_class"])
+	}
+
+	decodeInto(t, result["findings"], &findings)
+	if len(findings) != 1 || !strings.HasPrefix(findings[0], "2 StorageClasses are marked as the default (gp2, gp3)") {
+		t.Errorf("expected a finding for the two default classes, got %q", findings)
+	}
+
+	decodeInto(t, result["storage_classes"], &classes)
+	if len(classes) != 2 || classes[0].Name != "gp3" {
+		t.Errorf("expected the default class first, got %+v", classes)
+	}
+}
@@ -0,0 +1,425 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/connectivity"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/response"
+)
+
+// GetStorageStatusParams defines the parameters for the get_storage_status MCP tool.
+type GetStorageStatusParams struct {
+	// Namespace limits the claims reported, and the volumes to those bound
+	// to claims in the namespace.
+	Namespace string `json:"namespace,omitempty" description:"Namespace to report PersistentVolumeClaims for (leave empty for all namespaces). When set, only PersistentVolumes bound to claims in the namespace are reported"`
+
+	// StorageClass limits the report to claims and volumes of a storage class.
+	StorageClass string `json:"storage_class,omitempty" description:"Only report claims and volumes of this StorageClass"`
+
+	// OnlyProblems limits the report to claims and volumes with findings.
+	OnlyProblems bool `json:"only_problems,omitempty" description:"When true, returns only claims and volumes with findings, such as unbound, lost, or released ones"`
+
+	// Context specifies which Kubernetes context to use for this operation.
+	Context string `json:"context,omitempty" description:"Kubernetes context to use (defaults to current context from kubeconfig)"`
+}
+
+// StorageClaim summarizes a PersistentVolumeClaim and the pods that mount it.
+type StorageClaim struct {
+	Namespace    string   `json:"namespace"`
+	Name         string   `json:"name"`
+	Phase        string   `json:"phase"`
+	StorageClass string   `json:"storage_class,omitempty"`
+	Volume       string   `json:"volume,omitempty"`
+	Requested    string   `json:"requested,omitempty"`
+	Capacity     string   `json:"capacity,omitempty"`
+	AccessModes  []string `json:"access_modes"`
+	VolumeMode   string   `json:"volume_mode,omitempty"`
+	Age          string   `json:"age"`
+
+	// Pods are the pods in the claim's namespace that mount it.
+	Pods     []string `json:"pods"`
+	Findings []string `json:"findings,omitempty"`
+}
+
+// StorageVolume summarizes a PersistentVolume.
+type StorageVolume struct {
+	Name          string   `json:"name"`
+	Phase         string   `json:"phase"`
+	StorageClass  string   `json:"storage_class,omitempty"`
+	Capacity      string   `json:"capacity,omitempty"`
+	AccessModes   []string `json:"access_modes"`
+	ReclaimPolicy string   `json:"reclaim_policy,omitempty"`
+
+	// Claim is the claim the volume is bound or reserved to, as namespace/name.
+	Claim string `json:"claim,omitempty"`
+
+	// Source describes the volume plugin and what it points at, such as
+	// "csi:ebs.csi.aws.com" or "nfs:10.0.0.5:/exports".
+	Source   string   `json:"source"`
+	Message  string   `json:"message,omitempty"`
+	Age      string   `json:"age"`
+	Findings []string `json:"findings,omitempty"`
+}
+
+// GetStorageStatusResult is the result of the get_storage_status MCP tool.
+type GetStorageStatusResult struct {
+	Namespace    string `json:"namespace,omitempty"`
+	StorageClass string `json:"storage_class,omitempty"`
+
+	Claims  []StorageClaim  `json:"claims"`
+	Volumes []StorageVolume `json:"volumes"`
+
+	// ClaimsByPhase and VolumesByPhase count the claims and volumes by
+	// phase.
+	ClaimsByPhase       map[string]int `json:"claims_by_phase"`
+	VolumesByPhase      map[string]int `json:"volumes_by_phase"`
+	ClaimsWithFindings  int            `json:"claims_with_findings"`
+	VolumesWithFindings int            `json:"volumes_with_findings"`
+
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// GetStorageStatus implements the get_storage_status MCP tool.
+// It lists PersistentVolumeClaims with the pods that mount them and
+// PersistentVolumes with their binding, and flags the states that usually
+// need attention: claims that are not bound or have lost their volume, and
+// volumes left released or failed after their claim went away.
+func (h *StorageHandler) GetStorageStatus(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var params GetStorageStatusParams
+	if err := bindParams(request, &params); err != nil {
+		return response.Errorf("failed to parse arguments: %s", err)
+	}
+
+	// Use the appropriate client based on context
+	client, err := h.client.ForContext(ctx, params.Context)
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to create client with context %s: %v", params.Context, err)
+	}
+
+	claimList, err := client.ListPersistentVolumeClaims(ctx, params.Namespace, metav1.ListOptions{})
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to list persistent volume claims: %v", err)
+	}
+
+	volumeList, err := client.ListPersistentVolumes(ctx, metav1.ListOptions{})
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to list persistent volumes: %v", err)
+	}
+
+	var warnings []string
+
+	podsByClaim := make(map[string][]string)
+	if pods, err := client.ListPods(ctx, params.Namespace, metav1.ListOptions{}); err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		warnings = append(warnings, fmt.Sprintf("failed to list pods, claims are reported without the pods that mount them: %v", err))
+	} else {
+		for i := range pods.Items {
+			pod := &pods.Items[i]
+			for _, claimName := range podClaimNames(pod) {
+				key := pod.Namespace + "/" + claimName
+				if !containsString(podsByClaim[key], pod.Name) {
+					podsByClaim[key] = append(podsByClaim[key], pod.Name)
+				}
+			}
+		}
+	}
+
+	// The namespace of listed claims tells which namespaces were fully
+	// listed: all of them, or only the requested one.
+	namespaceListed := func(namespace string) bool {
+		return params.Namespace == "" || params.Namespace == namespace
+	}
+
+	volumesByName := make(map[string]*corev1.PersistentVolume, len(volumeList.Items))
+	for i := range volumeList.Items {
+		volumesByName[volumeList.Items[i].Name] = &volumeList.Items[i]
+	}
+
+	claimsByKey := make(map[string]*corev1.PersistentVolumeClaim, len(claimList.Items))
+	for i := range claimList.Items {
+		claimsByKey[claimList.Items[i].Namespace+"/"+claimList.Items[i].Name] = &claimList.Items[i]
+	}
+
+	now := time.Now()
+
+	claims := make([]StorageClaim, 0, len(claimList.Items))
+	claimPhases := make(map[string]int)
+	claimsWithFindings := 0
+
+	for i := range claimList.Items {
+		claim := &claimList.Items[i]
+
+		entry := storageClaim(claim, volumesByName, podsByClaim[claim.Namespace+"/"+claim.Name], now)
+		if params.StorageClass != "" && entry.StorageClass != params.StorageClass {
+			continue
+		}
+
+		claimPhases[entry.Phase]++
+		if len(entry.Findings) > 0 {
+			claimsWithFindings++
+		} else if params.OnlyProblems {
+			continue
+		}
+
+		claims = append(claims, entry)
+	}
+
+	volumes := make([]StorageVolume, 0, len(volumeList.Items))
+	volumePhases := make(map[string]int)
+	volumesWithFindings := 0
+
+	for i := range volumeList.Items {
+		volume := &volumeList.Items[i]
+
+		ref := volume.Spec.ClaimRef
+		if params.Namespace != "" && (ref == nil || ref.Namespace != params.Namespace) {
+			continue
+		}
+
+		entry := storageVolume(volume, now)
+		if params.StorageClass != "" && entry.StorageClass != params.StorageClass {
+			continue
+		}
+
+		if volume.Status.Phase == corev1.VolumeBound && ref != nil && namespaceListed(ref.Namespace) {
+			if _, ok := claimsByKey[ref.Namespace+"/"+ref.Name]; !ok {
+				entry.Findings = append(entry.Findings, fmt.Sprintf("bound to claim %s, which no longer exists", entry.Claim))
+			}
+		}
+
+		volumePhases[entry.Phase]++
+		if len(entry.Findings) > 0 {
+			volumesWithFindings++
+		} else if params.OnlyProblems {
+			continue
+		}
+
+		volumes = append(volumes, entry)
+	}
+
+	// Entries with findings first, then by namespace and name
+	sort.SliceStable(claims, func(i, j int) bool {
+		a, b := claims[i], claims[j]
+		if (len(a.Findings) > 0) != (len(b.Findings) > 0) {
+			return len(a.Findings) > 0
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+
+	sort.SliceStable(volumes, func(i, j int) bool {
+		a, b := volumes[i], volumes[j]
+		if (len(a.Findings) > 0) != (len(b.Findings) > 0) {
+			return len(a.Findings) > 0
+		}
+		return a.Name < b.Name
+	})
+
+	return response.JSON(GetStorageStatusResult{
+		Namespace:           params.Namespace,
+		StorageClass:        params.StorageClass,
+		Claims:              claims,
+		Volumes:             volumes,
+		ClaimsByPhase:       claimPhases,
+		VolumesByPhase:      volumePhases,
+		ClaimsWithFindings:  claimsWithFindings,
+		VolumesWithFindings: volumesWithFindings,
+		Warnings:            warnings,
+	})
+}
+
+// storageClaim summarizes a claim and explains why it may need attention.
+func storageClaim(claim *corev1.PersistentVolumeClaim, volumes map[string]*corev1.PersistentVolume, pods []string, now time.Time) StorageClaim {
+	entry := StorageClaim{
+		Namespace:    claim.Namespace,
+		Name:         claim.Name,
+		Phase:        string(claim.Status.Phase),
+		StorageClass: claimStorageClass(claim),
+		Volume:       claim.Spec.VolumeName,
+		AccessModes:  accessModes(claim.Spec.AccessModes),
+		Age:          now.Sub(claim.CreationTimestamp.Time).Round(time.Second).String(),
+		Pods:         pods,
+	}
+
+	if entry.Phase == "" {
+		entry.Phase = string(corev1.ClaimPending)
+	}
+
+	if entry.Pods == nil {
+		entry.Pods = make([]string, 0)
+	}
+
+	if claim.Spec.VolumeMode != nil {
+		entry.VolumeMode = string(*claim.Spec.VolumeMode)
+	}
+
+	requested, hasRequest := claim.Spec.Resources.Requests[corev1.ResourceStorage]
+	if hasRequest {
+		entry.Requested = requested.String()
+	}
+
+	capacity, hasCapacity := claim.Status.Capacity[corev1.ResourceStorage]
+	if hasCapacity {
+		entry.Capacity = capacity.String()
+	}
+
+	switch claim.Status.Phase {
+	case corev1.ClaimBound:
+		if hasRequest && hasCapacity && capacity.Cmp(requested) < 0 {
+			entry.Findings = append(entry.Findings, fmt.Sprintf("requested %s but the volume provides %s; an expansion has not completed", entry.Requested, entry.Capacity))
+		}
+
+	case corev1.ClaimLost:
+		entry.Findings = append(entry.Findings, fmt.Sprintf("lost its volume %q, which no longer exists or is bound elsewhere; the data may be gone and pods mounting the claim cannot start", claim.Spec.VolumeName))
+
+	default:
+		finding := fmt.Sprintf("not bound after %s", entry.Age)
+		switch {
+		case claim.Spec.VolumeName != "" && volumes[claim.Spec.VolumeName] == nil:
+			finding += fmt.Sprintf("; it requests volume %q, which does not exist", claim.Spec.VolumeName)
+		case claim.Spec.VolumeName != "":
+			finding += fmt.Sprintf("; it requests volume %q", claim.Spec.VolumeName)
+		case len(entry.Pods) == 0:
+			finding += "; no pod mounts it, so a StorageClass with WaitForFirstConsumer binding will not provision it yet"
+		default:
+			finding += "; check the claim's events for provisioning errors"
+		}
+		entry.Findings = append(entry.Findings, finding)
+	}
+
+	if claim.DeletionTimestamp != nil {
+		finding := "being deleted"
+		if len(entry.Pods) > 0 {
+			finding += fmt.Sprintf("; deletion waits until no pod uses it (%s)", strings.Join(entry.Pods, ", "))
+		}
+		entry.Findings = append(entry.Findings, finding)
+	}
+
+	for _, condition := range claim.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue {
+			continue
+		}
+
+		switch condition.Type {
+		case corev1.PersistentVolumeClaimResizing:
+			entry.Findings = append(entry.Findings, "volume expansion is in progress")
+		case corev1.PersistentVolumeClaimFileSystemResizePending:
+			entry.Findings = append(entry.Findings, "the volume was expanded; the file system is resized when a pod next mounts it")
+		}
+	}
+
+	return entry
+}
+
+// storageVolume summarizes a volume and explains why it may need attention.
+// Whether its claim still exists is checked by the caller.
+func storageVolume(volume *corev1.PersistentVolume, now time.Time) StorageVolume {
+	entry := StorageVolume{
+		Name:          volume.Name,
+		Phase:         string(volume.Status.Phase),
+		StorageClass:  volume.Spec.StorageClassName,
+		AccessModes:   accessModes(volume.Spec.AccessModes),
+		ReclaimPolicy: string(volume.Spec.PersistentVolumeReclaimPolicy),
+		Source:        persistentVolumeSource(&volume.Spec.PersistentVolumeSource),
+		Message:       volume.Status.Message,
+		Age:           now.Sub(volume.CreationTimestamp.Time).Round(time.Second).String(),
+	}
+
+	if capacity, ok := volume.Spec.Capacity[corev1.ResourceStorage]; ok {
+		entry.Capacity = capacity.String()
+	}
+
+	if ref := volume.Spec.ClaimRef; ref != nil {
+		entry.Claim = ref.Namespace + "/" + ref.Name
+	}
+
+	switch volume.Status.Phase {
+	case corev1.VolumeReleased:
+		if volume.Spec.PersistentVolumeReclaimPolicy == corev1.PersistentVolumeReclaimRetain {
+			entry.Findings = append(entry.Findings, fmt.Sprintf("released by deleted claim %s; the data is retained, but the volume cannot be bound again until its claimRef is cleared", entry.Claim))
+		} else {
+			entry.Findings = append(entry.Findings, fmt.Sprintf("released by deleted claim %s and not yet reclaimed with policy %s", entry.Claim, entry.ReclaimPolicy))
+		}
+	case corev1.VolumeFailed:
+		entry.Findings = append(entry.Findings, "automatic reclamation failed")
+	case corev1.VolumeAvailable:
+		if entry.Claim != "" {
+			entry.Findings = append(entry.Findings, fmt.Sprintf("reserved for claim %s, which has not bound to it", entry.Claim))
+		}
+	}
+
+	return entry
+}
+
+// claimStorageClass returns the storage class of a claim, falling back to the
+// beta annotation that predates spec.storageClassName.
+func claimStorageClass(claim *corev1.PersistentVolumeClaim) string {
+	if claim.Spec.StorageClassName != nil {
+		return *claim.Spec.StorageClassName
+	}
+	return claim.Annotations[corev1.BetaStorageClassAnnotation]
+}
+
+// accessModes converts access modes to strings, returning an empty slice
+// rather than nil.
+func accessModes(modes []corev1.PersistentVolumeAccessMode) []string {
+	result := make([]string, 0, len(modes))
+	for _, mode := range modes {
+		result = append(result, string(mode))
+	}
+	return result
+}
+
+// persistentVolumeSource describes the plugin backing a volume and what it
+// points at.
+func persistentVolumeSource(source *corev1.PersistentVolumeSource) string {
+	switch {
+	case source.CSI != nil:
+		return "csi:" + source.CSI.Driver
+	case source.NFS != nil:
+		return fmt.Sprintf("nfs:%s:%s", source.NFS.Server, source.NFS.Path)
+	case source.HostPath != nil:
+		return "hostPath:" + source.HostPath.Path
+	case source.Local != nil:
+		return "local:" + source.Local.Path
+	case source.AWSElasticBlockStore != nil:
+		return "awsElasticBlockStore:" + source.AWSElasticBlockStore.VolumeID
+	case source.GCEPersistentDisk != nil:
+		return "gcePersistentDisk:" + source.GCEPersistentDisk.PDName
+	case source.AzureDisk != nil:
+		return "azureDisk:" + source.AzureDisk.DiskName
+	case source.AzureFile != nil:
+		return "azureFile:" + source.AzureFile.ShareName
+	case source.ISCSI != nil:
+		return "iscsi:" + source.ISCSI.TargetPortal
+	case source.CephFS != nil:
+		return "cephfs"
+	case source.RBD != nil:
+		return "rbd:" + source.RBD.RBDImage
+	case source.FC != nil:
+		return "fc"
+	default:
+		return "other"
+	}
+}
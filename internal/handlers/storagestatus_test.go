@@ -0,0 +1,196 @@
+package handlers
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/fakecluster"
+)
+
+func TestStorageClaimFindings(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	storage := func(size string) corev1.ResourceList {
+		return corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(size)}
+	}
+
+	tests := []struct {
+		name  string
+		claim corev1.PersistentVolumeClaim
+		pods  []string
+		want  []string
+	}{
+		{
+			name: "bound",
+			claim: corev1.PersistentVolumeClaim{
+				Spec:   corev1.PersistentVolumeClaimSpec{VolumeName: "pv-1", Resources: corev1.VolumeResourceRequirements{Requests: storage("10Gi")}},
+				Status: corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimBound, Capacity: storage("10Gi")},
+			},
+		},
+		{
+			name: "expansion not completed",
+			claim: corev1.PersistentVolumeClaim{
+				Spec:   corev1.PersistentVolumeClaimSpec{VolumeName: "pv-1", Resources: corev1.VolumeResourceRequirements{Requests: storage("20Gi")}},
+				Status: corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimBound, Capacity: storage("10Gi")},
+			},
+			want: []string{"requested 20Gi but the volume provides 10Gi; an expansion has not completed"},
+		},
+		{
+			name:  "pending without pods",
+			claim: corev1.PersistentVolumeClaim{Status: corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimPending}},
+			want:  []string{"not bound after 5m0s; no pod mounts it, so a StorageClass with WaitForFirstConsumer binding will not provision it yet"},
+		},
+		{
+			name: "pending on a missing volume",
+			claim: corev1.PersistentVolumeClaim{
+				Spec:   corev1.PersistentVolumeClaimSpec{VolumeName: "pv-gone"},
+				Status: corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimPending},
+			},
+			pods: []string{"web-0"},
+			want: []string{`not bound after 5m0s; it requests volume "pv-gone", which does not exist`},
+		},
+		{
+			name: "lost",
+			claim: corev1.PersistentVolumeClaim{
+				Spec:   corev1.PersistentVolumeClaimSpec{VolumeName: "pv-gone"},
+				Status: corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimLost},
+			},
+			want: []string{`lost its volume "pv-gone", which no longer exists or is bound elsewhere; the data may be gone and pods mounting the claim cannot start`},
+		},
+		{
+			name: "terminating while mounted",
+			claim: corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{DeletionTimestamp: &metav1.Time{Time: now}},
+				Spec:       corev1.PersistentVolumeClaimSpec{VolumeName: "pv-1"},
+				Status:     corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimBound},
+			},
+			pods: []string{"web-0"},
+			want: []string{"being deleted; deletion waits until no pod uses it (web-0)"},
+		},
+	}
+
+	volumes := map[string]*corev1.PersistentVolume{"pv-1": {}}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			claim := tt.claim
+			claim.CreationTimestamp = metav1.Time{Time: now.Add(-5 * time.Minute)}
+
+			got := storageClaim(&claim, volumes, tt.pods, now)
+			if !reflect.DeepEqual(got.Findings, tt.want) {
+				t.Errorf("expected findings %q, got %q", tt.want, got.Findings)
+			}
+		})
+	}
+}
+
+func TestGetStorageStatus_FakeCluster(t *testing.T) {
+	t.Parallel()
+
+	storageClass := "standard"
+	handler := NewStorageHandler(fakecluster.New(fakecluster.Config{
+		Objects: []runtime.Object{
+			&corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{Name: "data-db-0", Namespace: "shop", CreationTimestamp: metav1.Now()},
+				Spec: corev1.PersistentVolumeClaimSpec{
+					StorageClassName: &storageClass,
+					VolumeName:       "pv-db",
+					AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+				},
+				Status: corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimBound},
+			},
+			&corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{Name: "uploads", Namespace: "shop", CreationTimestamp: metav1.Now()},
+				Spec:       corev1.PersistentVolumeClaimSpec{StorageClassName: &storageClass},
+				Status:     corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimPending},
+			},
+			&corev1.PersistentVolume{
+				ObjectMeta: metav1.ObjectMeta{Name: "pv-db"},
+				Spec: corev1.PersistentVolumeSpec{
+					StorageClassName:              storageClass,
+					ClaimRef:                      &corev1.ObjectReference{Namespace: "shop", Name: "data-db-0"},
+					PersistentVolumeReclaimPolicy: corev1.PersistentVolumeReclaimDelete,
+					PersistentVolumeSource:        corev1.PersistentVolumeSource{CSI: &corev1.CSIPersistentVolumeSource{Driver: "ebs.csi.aws.com"}},
+				},
+				Status: corev1.PersistentVolumeStatus{Phase: corev1.VolumeBound},
+			},
+			&corev1.PersistentVolume{
+				ObjectMeta: metav1.ObjectMeta{Name: "pv-old"},
+				Spec: corev1.PersistentVolumeSpec{
+					StorageClassName:              storageClass,
+					ClaimRef:                      &corev1.ObjectReference{Namespace: "shop", Name: "old-data"},
+					PersistentVolumeReclaimPolicy: corev1.PersistentVolumeReclaimRetain,
+				},
+				Status: corev1.PersistentVolumeStatus{Phase: corev1.VolumeReleased},
+			},
+			&corev1.PersistentVolume{
+				ObjectMeta: metav1.ObjectMeta{Name: "pv-other"},
+				Spec:       corev1.PersistentVolumeSpec{ClaimRef: &corev1.ObjectReference{Namespace: "ops", Name: "logs"}},
+				Status:     corev1.PersistentVolumeStatus{Phase: corev1.VolumeBound},
+			},
+			&corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "db-0", Namespace: "shop"},
+				Spec: corev1.PodSpec{Volumes: []corev1.Volume{{
+					Name:         "data",
+					VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "data-db-0"}},
+				}}},
+			},
+		},
+	}), false)
+
+	result, isErr := callTool(t, handler.GetStorageStatus, map[string]any{"namespace": "shop"})
+	if isErr {
+		t.Fatalf("unexpected error: %v", result["error"])
+	}
+
+	var claims []StorageClaim
+	decodeInto(t, result["claims"], &claims)
+	if len(claims) != 2 || claims[0].Name != "uploads" || len(claims[0].Findings) != 1 {
+		t.Fatalf("expected the pending claim first with a finding, got %+v", claims)
+	}
+	if !reflect.DeepEqual(claims[1].Pods, []string{"db-0"}) || claims[1].Volume != "pv-db" {
+		t.Errorf("expected data-db-0 to be mounted by db-0, got %+v", claims[1])
+	}
+
+	var volumes []StorageVolume
+	decodeInto(t, result["volumes"], &volumes)
+	if len(volumes) != 2 {
+		t.Fatalf("expected only the volumes of claims in shop, got %+v", volumes)
+	}
+	if volumes[0].Name != "pv-old" || len(volumes[0].Findings) != 1 || !strings.Contains(volumes[0].Findings[0], "data is retained") {
+		t.Errorf("expected the released volume first, got %+v", volumes[0])
+	}
+	if volumes[1].Source != "csi:ebs.csi.aws.com" || volumes[1].Claim != "shop/data-db-0" {
+		t.Errorf("unexpected bound volume %+v", volumes[1])
+	}
+
+	result, isErr = callTool(t, handler.GetStorageStatus, map[string]any{"only_problems": true})
+	if isErr {
+		t.Fatalf("unexpected error: %v", result["error"])
+	}
+
+	decodeInto(t, result["volumes"], &volumes)
+	want := map[string]bool{"pv-old": true, "pv-other": true}
+	if len(volumes) != len(want) {
+		t.Fatalf("expected the released volume and the volume with a missing claim, got %+v", volumes)
+	}
+	for _, volume := range volumes {
+		if !want[volume.Name] {
+			t.Errorf("unexpected volume %s reported as a problem", volume.Name)
+		}
+	}
+	if result["claims_with_findings"] != float64(1) {
+		t.Errorf("expected one claim with findings, got %v", result["claims_with_findings"])
+	}
+}
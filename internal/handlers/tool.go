@@ -6,6 +6,8 @@ import (
 	"context"
 
 	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/toolschema"
 )
 
 // MCPTool represents a Model Context Protocol tool with both its definition and handler combined.
@@ -19,15 +21,34 @@ type MCPTool struct {
 // NewMCPTool creates a new MCPTool with the given tool definition and handler function.
 // The tool parameter defines the MCP tool specification (name, description, input schema),
 // while the handler parameter provides the implementation that processes requests.
+// No tool changes the cluster, so the tool is annotated as read-only, idempotent,
+// and not destructive, letting clients approve calls without asking the user each time.
 //
 //nolint:gocritic // Using value semantics for encapsulation
 func NewMCPTool(tool mcp.Tool, handler func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error)) MCPTool {
+	tool.Annotations.ReadOnlyHint = mcp.ToBoolPtr(true)
+	tool.Annotations.IdempotentHint = mcp.ToBoolPtr(true)
+	tool.Annotations.DestructiveHint = mcp.ToBoolPtr(false)
+
 	return MCPTool{
 		tool:    tool,
 		handler: handler,
 	}
 }
 
+// newStatefulMCPTool creates an MCPTool for a tool that leaves the cluster
+// untouched but changes state held by the server, such as starting a port
+// forward. It is annotated as not destructive, but neither read-only nor
+// idempotent, so clients may still ask the user before calling it.
+//
+//nolint:gocritic // Using value semantics for encapsulation
+func newStatefulMCPTool(tool mcp.Tool, handler func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error)) MCPTool {
+	t := NewMCPTool(tool, handler)
+	t.tool.Annotations.ReadOnlyHint = mcp.ToBoolPtr(false)
+	t.tool.Annotations.IdempotentHint = mcp.ToBoolPtr(false)
+	return t
+}
+
 // Tool returns the MCP tool definition containing the tool's metadata and schema.
 // This is used by the MCP server to register the tool and provide information
 // to clients about available tools and their parameters.
@@ -55,3 +76,11 @@ type ToolRegistrator interface {
 	// that can be invoked by MCP clients.
 	GetTools() []MCPTool
 }
+
+// bindParams decodes the tool call arguments into params, a pointer to a
+// params struct, after validating them against the types, enums, bounds, and
+// required fields declared in the struct tags. Validation errors name the
+// offending argument so the caller can correct it.
+func bindParams(request mcp.CallToolRequest, params any) error {
+	return toolschema.Bind(request.GetArguments(), params) //nolint:wrapcheck // validation errors are already descriptive
+}
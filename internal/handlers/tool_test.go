@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// allTools returns the tools of every handler. Handlers are built without a
+// client since only their tool definitions are inspected.
+func allTools() []MCPTool {
+	registrators := []ToolRegistrator{
+		NewResourceHandler(nil, nil, false),
+		NewLogHandler(nil, false),
+		NewMetricsHandler(nil, false),
+		NewQuotaHandler(nil, false),
+		NewNodeHandler(nil, false),
+		NewAutoscalingHandler(nil, false),
+		NewClusterHandler(nil, false),
+		NewPodHandler(nil, false),
+		NewWorkloadHandler(nil, false),
+		NewNetworkHandler(nil, false),
+		NewAccessHandler(nil, nil, false),
+		NewCapabilitiesHandler(nil, false, ServerSettings{}, nil),
+		NewUtilsHandler(),
+		NewMetricsHistoryHandler(nil),
+		NewPortForwardHandler(nil, nil, false),
+	}
+
+	var tools []MCPTool
+	for _, r := range registrators {
+		tools = append(tools, r.GetTools()...)
+	}
+	return tools
+}
+
+func TestToolInputSchemas(t *testing.T) {
+	t.Parallel()
+
+	seen := make(map[string]bool)
+
+	for _, tool := range allTools() {
+		def := tool.Tool()
+
+		if seen[def.Name] {
+			t.Errorf("tool %q is registered more than once", def.Name)
+		}
+		seen[def.Name] = true
+
+		if def.InputSchema.Type != "object" {
+			t.Errorf("tool %q: expected object input schema, got %q", def.Name, def.InputSchema.Type)
+		}
+
+		for name, raw := range def.InputSchema.Properties {
+			prop, ok := raw.(map[string]any)
+			if !ok {
+				t.Errorf("tool %q: property %q has unexpected schema %#v", def.Name, name, raw)
+				continue
+			}
+
+			if desc, _ := prop["description"].(string); desc == "" {
+				t.Errorf("tool %q: property %q has no description", def.Name, name)
+			}
+		}
+
+		for _, name := range def.InputSchema.Required {
+			if _, ok := def.InputSchema.Properties[name]; !ok {
+				t.Errorf("tool %q: required property %q is not defined", def.Name, name)
+			}
+		}
+	}
+}
+
+func TestToolAnnotations(t *testing.T) {
+	t.Parallel()
+
+	stateful := map[string]bool{"start_port_forward": true, "stop_port_forward": true}
+
+	for _, tool := range allTools() {
+		def := tool.Tool()
+		annotations := def.Annotations
+
+		if annotations.DestructiveHint == nil || *annotations.DestructiveHint {
+			t.Errorf("tool %q: expected to be annotated as not destructive", def.Name)
+		}
+
+		want := !stateful[def.Name]
+		if annotations.ReadOnlyHint == nil || *annotations.ReadOnlyHint != want {
+			t.Errorf("tool %q: expected readOnlyHint %t", def.Name, want)
+		}
+		if annotations.IdempotentHint == nil || *annotations.IdempotentHint != want {
+			t.Errorf("tool %q: expected idempotentHint %t", def.Name, want)
+		}
+	}
+}
+
+func TestToolOutputSchemas(t *testing.T) {
+	t.Parallel()
+
+	for _, tool := range allTools() {
+		def := tool.Tool()
+
+		if def.OutputSchema.Type != "object" {
+			t.Errorf("tool %q: expected object output schema, got %q", def.Name, def.OutputSchema.Type)
+			continue
+		}
+
+		for _, name := range def.OutputSchema.Required {
+			if _, ok := def.OutputSchema.Properties[name]; !ok {
+				t.Errorf("tool %q: required output property %q is not defined", def.Name, name)
+			}
+		}
+	}
+}
+
+// callTool invokes a tool handler with the given arguments and decodes its
+// JSON result. It reports whether the tool returned an error result, in which
+// case the error text is returned under the "error" key.
+func callTool(t *testing.T, handler func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error), args map[string]any) (map[string]any, bool) {
+	t.Helper()
+
+	var request mcp.CallToolRequest
+	request.Params.Arguments = args
+
+	result, err := handler(context.Background(), request)
+	if err != nil {
+		return map[string]any{"error": err.Error()}, true
+	}
+
+	if len(result.Content) != 1 {
+		t.Fatalf("expected a single content item, got %d", len(result.Content))
+	}
+
+	text, ok := result.Content[0].(mcp.TextContent)
+	if !ok {
+		t.Fatalf("expected text content, got %T", result.Content[0])
+	}
+
+	if result.IsError {
+		return map[string]any{"error": text.Text}, true
+	}
+
+	var decoded map[string]any
+	if err := json.Unmarshal([]byte(text.Text), &decoded); err != nil {
+		t.Fatalf("failed to decode tool result %q: %v", text.Text, err)
+	}
+
+	if result.StructuredContent == nil {
+		t.Errorf("expected the result to carry structured content")
+	}
+
+	return decoded, false
+}
+
+// decodeInto converts a decoded JSON value from a tool result into a typed value.
+func decodeInto(t *testing.T, value any, dst any) {
+	t.Helper()
+
+	if value == nil {
+		return
+	}
+
+	raw, err := json.Marshal(value)
+	if err != nil {
+		t.Fatalf("failed to encode %v: %v", value, err)
+	}
+
+	if err := json.Unmarshal(raw, dst); err != nil {
+		t.Fatalf("failed to decode %s: %v", raw, err)
+	}
+}
@@ -0,0 +1,306 @@
+package handlers
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/connectivity"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/response"
+)
+
+const (
+	// labelZone is the well-known node label holding the node's availability zone.
+	labelZone = "topology.kubernetes.io/zone"
+
+	// labelZoneBeta is the deprecated form of labelZone.
+	labelZoneBeta = "failure-domain.beta.kubernetes.io/zone"
+
+	// labelRegion is the well-known node label holding the node's region.
+	labelRegion = "topology.kubernetes.io/region"
+
+	// labelRegionBeta is the deprecated form of labelRegion.
+	labelRegionBeta = "failure-domain.beta.kubernetes.io/region"
+)
+
+// TopologyReportParams defines the parameters for the topology_report MCP tool.
+type TopologyReportParams struct {
+	// Namespace restricts the workload analysis to a namespace.
+	// If empty, workloads across all namespaces are analyzed.
+	Namespace string `json:"namespace,omitempty" description:"Namespace to analyze workloads in (leave empty for all namespaces)"`
+
+	// LabelSelector restricts the analyzed pods using label selector syntax.
+	LabelSelector string `json:"label_selector,omitempty" description:"Label selector to filter the analyzed pods (e.g., 'app=nginx')"`
+
+	// Workload restricts the report to a single workload, written as
+	// "Kind/name" (e.g., "Deployment/web"). Matching is case-insensitive.
+	Workload string `json:"workload,omitempty" description:"Restrict the report to a single workload as Kind/name (e.g., 'Deployment/web', 'StatefulSet/db')"`
+
+	// Context specifies which Kubernetes context to use for this operation.
+	// If empty, uses the current context from kubeconfig.
+	Context string `json:"context,omitempty" description:"Kubernetes context to use (defaults to current context from kubeconfig)"`
+}
+
+// TopologyZone summarizes the nodes in a single availability zone.
+type TopologyZone struct {
+	Region     string `json:"region,omitempty"`
+	Zone       string `json:"zone"`
+	Nodes      int    `json:"nodes"`
+	ReadyNodes int    `json:"ready_nodes"`
+}
+
+// WorkloadSpread describes how the running replicas of a workload are
+// distributed across zones.
+type WorkloadSpread struct {
+	Namespace string         `json:"namespace"`
+	Workload  string         `json:"workload"`
+	Replicas  int            `json:"replicas"`
+	Zones     map[string]int `json:"zones"`
+
+	// UnknownZone counts replicas on nodes without a zone label.
+	UnknownZone int `json:"unknown_zone,omitempty"`
+
+	// Concentrated indicates a workload with several replicas that all run in
+	// a single zone even though the cluster spans more than one, so losing
+	// that zone takes the whole workload down.
+	Concentrated bool `json:"concentrated"`
+}
+
+// TopologyReportResult is the result of the topology_report MCP tool.
+type TopologyReportResult struct {
+	Namespace         string           `json:"namespace"`
+	NodeCount         int              `json:"node_count"`
+	RegionCount       int              `json:"region_count"`
+	ZoneCount         int              `json:"zone_count"`
+	Zones             []TopologyZone   `json:"zones"`
+	NodesWithoutZone  []string         `json:"nodes_without_zone"`
+	Workloads         []WorkloadSpread `json:"workloads"`
+	ConcentratedCount int              `json:"concentrated_count"`
+	Warnings          []string         `json:"warnings,omitempty"`
+}
+
+// TopologyReport implements the topology_report MCP tool.
+// It summarizes how nodes are distributed across zones and regions, and how
+// the scheduled replicas of each workload are spread across those zones.
+func (h *NodeHandler) TopologyReport(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var params TopologyReportParams
+	if err := bindParams(request, &params); err != nil {
+		return response.Errorf("failed to parse arguments: %s", err)
+	}
+
+	// Use the appropriate client based on context
+	client, err := h.client.ForContext(ctx, params.Context)
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to create client with context %s: %v", params.Context, err)
+	}
+
+	nodes, err := client.ListNodes(ctx, metav1.ListOptions{})
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to list nodes: %v", err)
+	}
+
+	pods, err := client.ListPods(ctx, params.Namespace, metav1.ListOptions{LabelSelector: params.LabelSelector})
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to list pods: %v", err)
+	}
+
+	zones, nodeZones, unzoned := summarizeZones(nodes.Items)
+
+	spread := summarizeWorkloadSpread(pods.Items, nodeZones, len(zones))
+	if params.Workload != "" {
+		filtered := make([]WorkloadSpread, 0, 1)
+		for _, w := range spread {
+			if strings.EqualFold(w.Workload, params.Workload) {
+				filtered = append(filtered, w)
+			}
+		}
+		spread = filtered
+	}
+
+	concentrated := 0
+	for _, w := range spread {
+		if w.Concentrated {
+			concentrated++
+		}
+	}
+
+	regions := make(map[string]bool)
+	for _, z := range zones {
+		if z.Region != "" {
+			regions[z.Region] = true
+		}
+	}
+
+	var warnings []string
+	if len(zones) <= 1 {
+		warnings = append(warnings, "All zoned nodes are in a single zone (or no node has a topology.kubernetes.io/zone label), so no workload can survive a zone outage.")
+	}
+	if len(unzoned) > 0 {
+		warnings = append(warnings, "Some nodes have no topology.kubernetes.io/zone label; replicas scheduled on them are counted as unknown_zone.")
+	}
+
+	return response.JSON(TopologyReportResult{
+		Namespace:         params.Namespace,
+		NodeCount:         len(nodes.Items),
+		RegionCount:       len(regions),
+		ZoneCount:         len(zones),
+		Zones:             zones,
+		NodesWithoutZone:  unzoned,
+		Workloads:         spread,
+		ConcentratedCount: concentrated,
+		Warnings:          warnings,
+	})
+}
+
+// nodeTopology returns the zone and region of a node from its well-known
+// topology labels, falling back to the deprecated failure-domain labels.
+func nodeTopology(node *corev1.Node) (zone, region string) {
+	zone = node.Labels[labelZone]
+	if zone == "" {
+		zone = node.Labels[labelZoneBeta]
+	}
+
+	region = node.Labels[labelRegion]
+	if region == "" {
+		region = node.Labels[labelRegionBeta]
+	}
+
+	return zone, region
+}
+
+// summarizeZones groups nodes by zone, sorted by region and zone. It also
+// returns each node's zone keyed by node name, and the names of nodes that
+// carry no zone label.
+func summarizeZones(nodes []corev1.Node) ([]TopologyZone, map[string]string, []string) {
+	byZone := make(map[string]*TopologyZone)
+	nodeZones := make(map[string]string, len(nodes))
+	unzoned := make([]string, 0)
+
+	for i := range nodes {
+		node := &nodes[i]
+
+		zone, region := nodeTopology(node)
+		if zone == "" {
+			unzoned = append(unzoned, node.Name)
+			continue
+		}
+		nodeZones[node.Name] = zone
+
+		entry, ok := byZone[zone]
+		if !ok {
+			entry = &TopologyZone{Zone: zone, Region: region}
+			byZone[zone] = entry
+		}
+
+		entry.Nodes++
+		if isNodeReady(node) {
+			entry.ReadyNodes++
+		}
+	}
+
+	zones := make([]TopologyZone, 0, len(byZone))
+	for _, entry := range byZone {
+		zones = append(zones, *entry)
+	}
+
+	sort.Slice(zones, func(i, j int) bool {
+		if zones[i].Region != zones[j].Region {
+			return zones[i].Region < zones[j].Region
+		}
+		return zones[i].Zone < zones[j].Zone
+	})
+	sort.Strings(unzoned)
+
+	return zones, nodeZones, unzoned
+}
+
+// summarizeWorkloadSpread groups scheduled, non-terminated pods by their
+// workload and counts replicas per zone. Standalone pods are skipped. Results
+// list concentrated workloads first, then sort by namespace and workload.
+func summarizeWorkloadSpread(pods []corev1.Pod, nodeZones map[string]string, zoneCount int) []WorkloadSpread {
+	byWorkload := make(map[string]*WorkloadSpread)
+
+	for i := range pods {
+		pod := &pods[i]
+
+		if pod.Spec.NodeName == "" || pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+
+		workload := podWorkload(pod)
+		if workload == "" {
+			continue
+		}
+
+		key := pod.Namespace + "/" + workload
+		entry, ok := byWorkload[key]
+		if !ok {
+			entry = &WorkloadSpread{
+				Namespace: pod.Namespace,
+				Workload:  workload,
+				Zones:     make(map[string]int),
+			}
+			byWorkload[key] = entry
+		}
+
+		entry.Replicas++
+		if zone, ok := nodeZones[pod.Spec.NodeName]; ok {
+			entry.Zones[zone]++
+		} else {
+			entry.UnknownZone++
+		}
+	}
+
+	spread := make([]WorkloadSpread, 0, len(byWorkload))
+	for _, entry := range byWorkload {
+		entry.Concentrated = zoneCount > 1 && entry.Replicas > 1 && len(entry.Zones) == 1 && entry.UnknownZone == 0
+		spread = append(spread, *entry)
+	}
+
+	sort.Slice(spread, func(i, j int) bool {
+		if spread[i].Concentrated != spread[j].Concentrated {
+			return spread[i].Concentrated
+		}
+		if spread[i].Namespace != spread[j].Namespace {
+			return spread[i].Namespace < spread[j].Namespace
+		}
+		return spread[i].Workload < spread[j].Workload
+	})
+
+	return spread
+}
+
+// podWorkload returns the workload that manages a pod as "Kind/name". Pods
+// owned by a ReplicaSet created by a Deployment are attributed to the
+// Deployment, detected through the pod-template-hash suffix on the
+// ReplicaSet name. It returns an empty string for standalone pods.
+func podWorkload(pod *corev1.Pod) string {
+	owner := metav1.GetControllerOf(pod)
+	if owner == nil {
+		return ""
+	}
+
+	if owner.Kind == "ReplicaSet" {
+		if hash := pod.Labels[appsv1.DefaultDeploymentUniqueLabelKey]; hash != "" {
+			if name, ok := strings.CutSuffix(owner.Name, "-"+hash); ok {
+				return "Deployment/" + name
+			}
+		}
+	}
+
+	return owner.Kind + "/" + owner.Name
+}
@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func topologyNode(name, zone, region string, ready bool) corev1.Node {
+	status := corev1.ConditionFalse
+	if ready {
+		status = corev1.ConditionTrue
+	}
+
+	labels := map[string]string{}
+	if zone != "" {
+		labels[labelZone] = zone
+	}
+	if region != "" {
+		labels[labelRegion] = region
+	}
+
+	return corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+		Status: corev1.NodeStatus{
+			Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: status}},
+		},
+	}
+}
+
+func topologyPod(name, node, ownerKind, ownerName, hash string) corev1.Pod {
+	controller := true
+	pod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+			Labels:    map[string]string{},
+		},
+		Spec:   corev1.PodSpec{NodeName: node},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+
+	if ownerKind != "" {
+		pod.OwnerReferences = []metav1.OwnerReference{{Kind: ownerKind, Name: ownerName, Controller: &controller}}
+	}
+	if hash != "" {
+		pod.Labels["pod-template-hash"] = hash
+	}
+
+	return pod
+}
+
+func TestSummarizeZones(t *testing.T) {
+	t.Parallel()
+
+	nodes := []corev1.Node{
+		topologyNode("b-1", "us-east-1b", "us-east-1", true),
+		topologyNode("a-1", "us-east-1a", "us-east-1", true),
+		topologyNode("a-2", "us-east-1a", "us-east-1", false),
+		topologyNode("bare", "", "", true),
+	}
+
+	zones, nodeZones, unzoned := summarizeZones(nodes)
+
+	wantZones := []TopologyZone{
+		{Region: "us-east-1", Zone: "us-east-1a", Nodes: 2, ReadyNodes: 1},
+		{Region: "us-east-1", Zone: "us-east-1b", Nodes: 1, ReadyNodes: 1},
+	}
+	if !reflect.DeepEqual(zones, wantZones) {
+		t.Errorf("zones mismatch\nwant: %#v\ngot:  %#v", wantZones, zones)
+	}
+
+	wantNodeZones := map[string]string{"a-1": "us-east-1a", "a-2": "us-east-1a", "b-1": "us-east-1b"}
+	if !reflect.DeepEqual(nodeZones, wantNodeZones) {
+		t.Errorf("node zones mismatch\nwant: %#v\ngot:  %#v", wantNodeZones, nodeZones)
+	}
+
+	if !reflect.DeepEqual(unzoned, []string{"bare"}) {
+		t.Errorf("expected [bare] without zone, got %v", unzoned)
+	}
+}
+
+func TestSummarizeWorkloadSpread(t *testing.T) {
+	t.Parallel()
+
+	nodeZones := map[string]string{"a-1": "zone-a", "a-2": "zone-a", "b-1": "zone-b"}
+
+	pending := topologyPod("web-pending", "", "ReplicaSet", "web-5d9f", "5d9f")
+	pods := []corev1.Pod{
+		topologyPod("web-1", "a-1", "ReplicaSet", "web-5d9f", "5d9f"),
+		topologyPod("web-2", "a-2", "ReplicaSet", "web-5d9f", "5d9f"),
+		topologyPod("db-0", "a-1", "StatefulSet", "db", ""),
+		topologyPod("db-1", "b-1", "StatefulSet", "db", ""),
+		topologyPod("db-2", "unlabeled", "StatefulSet", "db", ""),
+		topologyPod("standalone", "a-1", "", "", ""),
+		pending,
+	}
+
+	got := summarizeWorkloadSpread(pods, nodeZones, 2)
+
+	want := []WorkloadSpread{
+		{Namespace: "default", Workload: "Deployment/web", Replicas: 2, Zones: map[string]int{"zone-a": 2}, Concentrated: true},
+		{Namespace: "default", Workload: "StatefulSet/db", Replicas: 3, Zones: map[string]int{"zone-a": 1, "zone-b": 1}, UnknownZone: 1},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("spread mismatch\nwant: %#v\ngot:  %#v", want, got)
+	}
+}
+
+func TestSummarizeWorkloadSpread_SingleZoneCluster(t *testing.T) {
+	t.Parallel()
+
+	pods := []corev1.Pod{
+		topologyPod("web-1", "a-1", "ReplicaSet", "web-5d9f", "5d9f"),
+		topologyPod("web-2", "a-1", "ReplicaSet", "web-5d9f", "5d9f"),
+	}
+
+	got := summarizeWorkloadSpread(pods, map[string]string{"a-1": "zone-a"}, 1)
+	if len(got) != 1 || got[0].Concentrated {
+		t.Errorf("expected a single non-concentrated workload in a single-zone cluster, got %#v", got)
+	}
+}
+
+func TestPodWorkload(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		pod  corev1.Pod
+		want string
+	}{
+		{"deployment", topologyPod("p", "n", "ReplicaSet", "api-7c8b", "7c8b"), "Deployment/api"},
+		{"bare replicaset", topologyPod("p", "n", "ReplicaSet", "api", ""), "ReplicaSet/api"},
+		{"daemonset", topologyPod("p", "n", "DaemonSet", "agent", ""), "DaemonSet/agent"},
+		{"standalone", topologyPod("p", "n", "", "", ""), ""},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := podWorkload(&tt.pod); got != tt.want {
+				t.Errorf("podWorkload() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,357 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/connectivity"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/resourcefilter"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/response"
+)
+
+const (
+	// rootCAConfigMap is the ConfigMap the control plane publishes in every
+	// namespace with the cluster's CA bundle.
+	rootCAConfigMap = "kube-root-ca.crt"
+
+	// helmOwnerLabel marks the Secrets (or ConfigMaps) Helm stores release
+	// history in.
+	helmOwnerLabel = "owner"
+)
+
+// configReferrers are the kinds whose pod specs can reference ConfigMaps and
+// Secrets, in the order find_unused_config reads them.
+var configReferrers = []struct {
+	kind string
+	gvr  schema.GroupVersionResource
+}{
+	{"Pod", relatedPodsGVR},
+	{"Deployment", schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}},
+	{"ReplicaSet", schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "replicasets"}},
+	{"StatefulSet", schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "statefulsets"}},
+	{"DaemonSet", schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "daemonsets"}},
+	{"Job", schema.GroupVersionResource{Group: "batch", Version: "v1", Resource: "jobs"}},
+	{"CronJob", schema.GroupVersionResource{Group: "batch", Version: "v1", Resource: "cronjobs"}},
+}
+
+// FindUnusedConfigParams defines the parameters for the find_unused_config MCP tool.
+type FindUnusedConfigParams struct {
+	// Namespace is the namespace to check.
+	Namespace string `json:"namespace" required:"true" description:"Namespace whose ConfigMaps and Secrets to check"`
+
+	// IncludeReferenced also lists the referenced and excluded objects.
+	IncludeReferenced bool `json:"include_referenced,omitempty" description:"When true, also lists the referenced ConfigMaps and Secrets with what references them, and the ones excluded because the cluster or a tool manages them"`
+
+	// Context specifies which Kubernetes context to use for this operation.
+	Context string `json:"context,omitempty" description:"Kubernetes context to use (defaults to current context from kubeconfig)"`
+}
+
+// ConfigUsage is a ConfigMap or Secret and what references it.
+type ConfigUsage struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+	Age  string `json:"age,omitempty"`
+
+	// ReferencedBy lists the objects that reference it and how, such as
+	// "Deployment/web (envFrom in container app)".
+	ReferencedBy []string `json:"referenced_by,omitempty"`
+
+	// ExcludedBecause explains why an object is not reported as unused even
+	// though nothing in the namespace references it.
+	ExcludedBecause string `json:"excluded_because,omitempty"`
+}
+
+// FindUnusedConfigResult is the result of the find_unused_config MCP tool.
+type FindUnusedConfigResult struct {
+	Namespace string        `json:"namespace"`
+	Unused    []ConfigUsage `json:"unused"`
+	Count     int           `json:"count"`
+
+	ConfigMapsChecked int `json:"config_maps_checked"`
+	SecretsChecked    int `json:"secrets_checked"`
+	ReferencedCount   int `json:"referenced_count"`
+	ExcludedCount     int `json:"excluded_count"`
+
+	// Referenced and Excluded are only listed when include_referenced is
+	// set.
+	Referenced []ConfigUsage `json:"referenced,omitempty"`
+	Excluded   []ConfigUsage `json:"excluded,omitempty"`
+
+	Note                   string   `json:"note"`
+	SecretValuesAreOmitted bool     `json:"secret_values_are_omitted"`
+	Warnings               []string `json:"warnings,omitempty"`
+}
+
+// FindUnusedConfig implements the find_unused_config MCP tool.
+// It cross-references the ConfigMaps and Secrets of a namespace against
+// what can use them: pod and pod template volumes, projected volumes,
+// envFrom, env valueFrom, and image pull secrets, Ingress TLS, and
+// ServiceAccount secrets and image pull secrets, and reports the ones
+// nothing references. Pods and ReplicaSets run by a workload that was read
+// are covered by their controller's template. ConfigMaps and Secrets are
+// listed through metadata only, so Secret values are never read.
+func (h *ResourceHandler) FindUnusedConfig(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var params FindUnusedConfigParams
+	if err := bindParams(request, &params); err != nil {
+		return response.Errorf("failed to parse arguments: %s", err)
+	}
+
+	// Use the appropriate client based on context
+	client, err := h.client.ForContext(ctx, params.Context)
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to create client with context %s: %v", params.Context, err)
+	}
+
+	if result, err := h.disabledResult("pods", relatedPodsGVR); result != nil || err != nil {
+		return result, err
+	}
+
+	var warnings []string
+	enabled := func(gvr schema.GroupVersionResource) bool {
+		if h.resourceFilter != nil && h.resourceFilter.IsDisabled(gvr) {
+			warnings = append(warnings, fmt.Sprintf("%s are disabled by configuration and were not checked", resourcefilter.FormatGVR(gvr)))
+			return false
+		}
+		return true
+	}
+
+	refs := make(configReferences)
+
+	// Read every workload kind before walking them, so pods and ReplicaSets
+	// are only skipped when their controller was actually read.
+	read := make(map[string]bool)
+	var referrers []referrer
+	for _, kind := range configReferrers {
+		// A partial read would report referenced objects as unused.
+		if err := ctx.Err(); err != nil {
+			return nil, err //nolint:wrapcheck // cancellation is reported as is
+		}
+		if kind.kind != "Pod" && !enabled(kind.gvr) {
+			continue
+		}
+
+		list, err := client.ListResources(ctx, kind.gvr, params.Namespace, metav1.ListOptions{})
+		if err != nil {
+			if h.alwaysStart && connectivity.IsTransportError(err) {
+				return response.Error(connectivity.ErrorMessage(err))
+			}
+			if kind.kind == "Pod" {
+				return response.Errorf("failed to list pods: %v", err)
+			}
+			warnings = append(warnings, fmt.Sprintf("failed to list %s, so the ConfigMaps and Secrets they reference may be reported as unused: %v", kind.gvr.Resource, err))
+			continue
+		}
+
+		read[kind.kind] = true
+		for i := range list.Items {
+			obj := &list.Items[i]
+			spec, err := podSpecOf(obj)
+			if err != nil {
+				warnings = append(warnings, err.Error())
+				continue
+			}
+
+			r := referrer{name: kind.kind + "/" + obj.GetName(), spec: spec}
+			if owner := metav1.GetControllerOfNoCopy(obj); owner != nil {
+				r.controller = owner.Kind
+			}
+			referrers = append(referrers, r)
+		}
+	}
+
+	for _, r := range referrers {
+		if r.controller != "" && read[r.controller] {
+			continue
+		}
+
+		configMaps, secrets := podConfigReferences(r.spec)
+		for _, ref := range configMaps {
+			for _, via := range ref.Via {
+				refs.add("ConfigMap", ref.Name, r.name, via)
+			}
+		}
+		for _, ref := range secrets {
+			for _, via := range ref.Via {
+				refs.add("Secret", ref.Name, r.name, via)
+			}
+		}
+	}
+
+	if enabled(relatedIngressesGVR) {
+		ingresses, err := client.ListIngresses(ctx, params.Namespace, metav1.ListOptions{})
+		if err != nil {
+			if h.alwaysStart && connectivity.IsTransportError(err) {
+				return response.Error(connectivity.ErrorMessage(err))
+			}
+			warnings = append(warnings, fmt.Sprintf("failed to list ingresses, so TLS Secrets may be reported as unused: %v", err))
+		} else {
+			for _, ingress := range ingresses.Items {
+				for _, tls := range ingress.Spec.TLS {
+					refs.add("Secret", tls.SecretName, "Ingress/"+ingress.Name, "tls")
+				}
+			}
+		}
+	}
+
+	accounts := make(map[string]bool)
+	if enabled(relatedServiceAccountsGVR) {
+		list, err := client.ListServiceAccounts(ctx, params.Namespace, metav1.ListOptions{})
+		if err != nil {
+			if h.alwaysStart && connectivity.IsTransportError(err) {
+				return response.Error(connectivity.ErrorMessage(err))
+			}
+			warnings = append(warnings, fmt.Sprintf("failed to list serviceaccounts, so their Secrets may be reported as unused: %v", err))
+		} else {
+			for _, account := range list.Items {
+				accounts[account.Name] = true
+				for _, ref := range account.Secrets {
+					refs.add("Secret", ref.Name, "ServiceAccount/"+account.Name, "secrets")
+				}
+				for _, ref := range account.ImagePullSecrets {
+					refs.add("Secret", ref.Name, "ServiceAccount/"+account.Name, "imagePullSecrets")
+				}
+			}
+		}
+	}
+
+	now := time.Now()
+	var unused, referenced, excluded []ConfigUsage
+	checked := map[string]int{}
+
+	for _, target := range []struct {
+		kind string
+		gvr  schema.GroupVersionResource
+	}{
+		{"ConfigMap", relatedConfigMapsGVR},
+		{"Secret", relatedSecretsGVR},
+	} {
+		if !enabled(target.gvr) {
+			continue
+		}
+
+		list, err := client.ListResourceMetadata(ctx, target.gvr, params.Namespace, metav1.ListOptions{})
+		if err != nil {
+			if h.alwaysStart && connectivity.IsTransportError(err) {
+				return response.Error(connectivity.ErrorMessage(err))
+			}
+			return response.Errorf("failed to list %s: %v", target.gvr.Resource, err)
+		}
+
+		checked[target.kind] = len(list.Items)
+		for i := range list.Items {
+			obj := &list.Items[i]
+			usage := ConfigUsage{
+				Kind:         target.kind,
+				Name:         obj.Name,
+				Age:          now.Sub(obj.CreationTimestamp.Time).Round(time.Second).String(),
+				ReferencedBy: refs[target.kind+"/"+obj.Name],
+			}
+
+			if len(usage.ReferencedBy) > 0 {
+				referenced = append(referenced, usage)
+				continue
+			}
+
+			if usage.ExcludedBecause = configExclusion(target.kind, obj, accounts); usage.ExcludedBecause != "" {
+				excluded = append(excluded, usage)
+			} else {
+				unused = append(unused, usage)
+			}
+		}
+	}
+
+	for _, list := range [][]ConfigUsage{unused, referenced, excluded} {
+		sort.Slice(list, func(i, j int) bool {
+			if list[i].Kind != list[j].Kind {
+				return list[i].Kind < list[j].Kind
+			}
+			return list[i].Name < list[j].Name
+		})
+	}
+
+	if unused == nil {
+		unused = []ConfigUsage{}
+	}
+
+	result := FindUnusedConfigResult{
+		Namespace:              params.Namespace,
+		Unused:                 unused,
+		Count:                  len(unused),
+		ConfigMapsChecked:      checked["ConfigMap"],
+		SecretsChecked:         checked["Secret"],
+		ReferencedCount:        len(referenced),
+		ExcludedCount:          len(excluded),
+		Note:                   "Only references from objects in this namespace are visible. Applications that read ConfigMaps or Secrets through the API, operators that consume them by name, and references from custom resources are not detected, so confirm before deleting anything reported as unused.",
+		SecretValuesAreOmitted: true,
+		Warnings:               warnings,
+	}
+	if params.IncludeReferenced {
+		result.Referenced = referenced
+		result.Excluded = excluded
+	}
+
+	return response.JSON(result)
+}
+
+// referrer is an object with a pod spec that may reference ConfigMaps and
+// Secrets.
+type referrer struct {
+	name string
+	spec *corev1.PodSpec
+
+	// controller is the kind of the object's controller, if any.
+	controller string
+}
+
+// configReferences maps "Kind/name" of a ConfigMap or Secret to what
+// references it.
+type configReferences map[string][]string
+
+// add records that referrer references the named object through via.
+func (r configReferences) add(kind, name, referrer, via string) {
+	if name == "" {
+		return
+	}
+
+	key := kind + "/" + name
+	entry := referrer + " (" + via + ")"
+	if !containsString(r[key], entry) {
+		r[key] = append(r[key], entry)
+	}
+}
+
+// configExclusion explains why an unreferenced ConfigMap or Secret is
+// still not reported as unused: the cluster publishes it, a ServiceAccount
+// token controller or Helm manages it, or another object owns it. It returns
+// an empty string when none of those apply.
+func configExclusion(kind string, obj *metav1.PartialObjectMetadata, accounts map[string]bool) string {
+	if kind == "ConfigMap" && obj.Name == rootCAConfigMap {
+		return "published by the control plane in every namespace for pods to verify the API server"
+	}
+
+	if kind == "Secret" {
+		if account := obj.Annotations[corev1.ServiceAccountNameKey]; account != "" && accounts[account] {
+			return "token of ServiceAccount " + account
+		}
+	}
+
+	if obj.Labels[helmOwnerLabel] == "helm" {
+		return "Helm release history"
+	}
+
+	if owner := metav1.GetControllerOfNoCopy(obj); owner != nil {
+		return "owned by " + owner.Kind + "/" + owner.Name
+	}
+
+	return ""
+}
@@ -0,0 +1,180 @@
+package handlers
+
+import (
+	"reflect"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/fakecluster"
+)
+
+func TestConfigExclusion(t *testing.T) {
+	t.Parallel()
+
+	controller := true
+	accounts := map[string]bool{"builder": true}
+
+	tests := []struct {
+		name string
+		kind string
+		meta metav1.ObjectMeta
+		want string
+	}{
+		{
+			name: "root ca",
+			kind: "ConfigMap",
+			meta: metav1.ObjectMeta{Name: rootCAConfigMap},
+			want: "published by the control plane in every namespace for pods to verify the API server",
+		},
+		{
+			name: "root ca name on a secret",
+			kind: "Secret",
+			meta: metav1.ObjectMeta{Name: rootCAConfigMap},
+		},
+		{
+			name: "token of an existing account",
+			kind: "Secret",
+			meta: metav1.ObjectMeta{Name: "builder-token", Annotations: map[string]string{corev1.ServiceAccountNameKey: "builder"}},
+			want: "token of ServiceAccount builder",
+		},
+		{
+			name: "token of a deleted account",
+			kind: "Secret",
+			meta: metav1.ObjectMeta{Name: "gone-token", Annotations: map[string]string{corev1.ServiceAccountNameKey: "gone"}},
+		},
+		{
+			name: "helm release",
+			kind: "Secret",
+			meta: metav1.ObjectMeta{Name: "sh.helm.release.v1.web.v3", Labels: map[string]string{helmOwnerLabel: "helm"}},
+			want: "Helm release history",
+		},
+		{
+			name: "owned",
+			kind: "Secret",
+			meta: metav1.ObjectMeta{Name: "web-tls", OwnerReferences: []metav1.OwnerReference{{Kind: "Certificate", Name: "web", Controller: &controller}}},
+			want: "owned by Certificate/web",
+		},
+		{
+			name: "plain",
+			kind: "ConfigMap",
+			meta: metav1.ObjectMeta{Name: "settings"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := configExclusion(tt.kind, &metav1.PartialObjectMetadata{ObjectMeta: tt.meta}, accounts); got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestFindUnusedConfig_FakeCluster(t *testing.T) {
+	t.Parallel()
+
+	controller := true
+	configMap := func(name string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "shop"}}
+	}
+	secret := func(meta metav1.ObjectMeta) *corev1.Secret {
+		meta.Namespace = "shop"
+		return &corev1.Secret{ObjectMeta: meta, Data: map[string][]byte{"password": []byte("top-secret")}}
+	}
+
+	handler := NewResourceHandler(fakecluster.New(fakecluster.Config{
+		Objects: []runtime.Object{
+			configMap("app-config"), configMap("debug-config"), configMap("old-config"), configMap(rootCAConfigMap),
+			secret(metav1.ObjectMeta{Name: "db"}),
+			secret(metav1.ObjectMeta{Name: "web-tls"}),
+			secret(metav1.ObjectMeta{Name: "registry"}),
+			secret(metav1.ObjectMeta{Name: "stale"}),
+			secret(metav1.ObjectMeta{Name: "sh.helm.release.v1.web.v1", Labels: map[string]string{helmOwnerLabel: "helm"}}),
+			&appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop"},
+				Spec: appsv1.DeploymentSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{
+						Name:    "app",
+						EnvFrom: []corev1.EnvFromSource{{ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "app-config"}}}},
+						Env: []corev1.EnvVar{{Name: "PASSWORD", ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "db"}, Key: "password",
+						}}}},
+					}},
+				}}},
+			},
+			&appsv1.ReplicaSet{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "web-abc", Namespace: "shop",
+					OwnerReferences: []metav1.OwnerReference{{Kind: "Deployment", Name: "web", Controller: &controller}},
+				},
+				Spec: appsv1.ReplicaSetSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+					Volumes: []corev1.Volume{{Name: "old", VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "old-config"},
+					}}}},
+				}}},
+			},
+			&corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "debug", Namespace: "shop"},
+				Spec: corev1.PodSpec{Volumes: []corev1.Volume{{Name: "config", VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "debug-config"},
+				}}}}},
+			},
+			&networkingv1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{Name: "shop", Namespace: "shop"},
+				Spec:       networkingv1.IngressSpec{TLS: []networkingv1.IngressTLS{{SecretName: "web-tls"}}},
+			},
+			&corev1.ServiceAccount{
+				ObjectMeta:       metav1.ObjectMeta{Name: "default", Namespace: "shop"},
+				ImagePullSecrets: []corev1.LocalObjectReference{{Name: "registry"}},
+			},
+		},
+	}), nil, false)
+
+	result, isErr := callTool(t, handler.FindUnusedConfig, map[string]any{"namespace": "shop", "include_referenced": true})
+	if isErr {
+		t.Fatalf("unexpected error: %v", result["error"])
+	}
+
+	var unused []ConfigUsage
+	decodeInto(t, result["unused"], &unused)
+
+	names := make([]string, 0, len(unused))
+	for _, usage := range unused {
+		names = append(names, usage.Kind+"/"+usage.Name)
+	}
+	// The old ReplicaSet is covered by its Deployment, whose template no
+	// longer references old-config.
+	if want := []string{"ConfigMap/old-config", "Secret/stale"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("expected unused %v, got %v", want, names)
+	}
+
+	var referenced []ConfigUsage
+	decodeInto(t, result["referenced"], &referenced)
+
+	got := make(map[string][]string, len(referenced))
+	for _, usage := range referenced {
+		got[usage.Kind+"/"+usage.Name] = usage.ReferencedBy
+	}
+	want := map[string][]string{
+		"ConfigMap/app-config":   {"Deployment/web (envFrom in container app)"},
+		"ConfigMap/debug-config": {"Pod/debug (volume config)"},
+		"Secret/db":              {"Deployment/web (env PASSWORD in container app)"},
+		"Secret/registry":        {"ServiceAccount/default (imagePullSecrets)"},
+		"Secret/web-tls":         {"Ingress/shop (tls)"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected references %v, got %v", want, got)
+	}
+
+	if result["excluded_count"] != float64(2) || result["secrets_checked"] != float64(5) || result["config_maps_checked"] != float64(4) {
+		t.Errorf("unexpected counts in %+v", result)
+	}
+}
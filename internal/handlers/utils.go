@@ -6,6 +6,7 @@ import (
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/response"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/toolschema"
 )
 
 // UtilsHandler provides MCP tools for utility operations related to Kubernetes.
@@ -22,13 +23,25 @@ func NewUtilsHandler() *UtilsHandler {
 // EncodeBase64Params defines the parameters for the encode_base64 MCP tool.
 type EncodeBase64Params struct {
 	// Data is the text data to encode to base64 format.
-	Data string `json:"data"`
+	Data string `json:"data" required:"true" description:"Text data to encode"`
 }
 
 // DecodeBase64Params defines the parameters for the decode_base64 MCP tool.
 type DecodeBase64Params struct {
 	// Data is the base64-encoded data to decode to text format.
-	Data string `json:"data"`
+	Data string `json:"data" required:"true" description:"Base64 data to decode"`
+}
+
+// EncodeBase64Result is the result of the encode_base64 MCP tool.
+type EncodeBase64Result struct {
+	Original string `json:"original"`
+	Encoded  string `json:"encoded"`
+}
+
+// DecodeBase64Result is the result of the decode_base64 MCP tool.
+type DecodeBase64Result struct {
+	Original string `json:"original"`
+	Decoded  string `json:"decoded"`
 }
 
 // EncodeBase64 implements the encode_base64 MCP tool.
@@ -36,22 +49,16 @@ type DecodeBase64Params struct {
 // Kubernetes secrets and other base64-encoded resources.
 func (h *UtilsHandler) EncodeBase64(_ context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	var params EncodeBase64Params
-	if err := request.BindArguments(&params); err != nil {
+	if err := bindParams(request, &params); err != nil {
 		return response.Errorf("failed to parse arguments: %s", err)
 	}
 
-	if params.Data == "" {
-		return response.Error("data is required")
-	}
-
 	encoded := base64.StdEncoding.EncodeToString([]byte(params.Data))
 
-	result := map[string]interface{}{
-		"original": params.Data,
-		"encoded":  encoded,
-	}
-
-	return response.JSON(result)
+	return response.JSON(EncodeBase64Result{
+		Original: params.Data,
+		Encoded:  encoded,
+	})
 }
 
 // DecodeBase64 implements the decode_base64 MCP tool.
@@ -59,25 +66,19 @@ func (h *UtilsHandler) EncodeBase64(_ context.Context, request mcp.CallToolReque
 // of Kubernetes secrets and other base64-encoded resources.
 func (h *UtilsHandler) DecodeBase64(_ context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	var params DecodeBase64Params
-	if err := request.BindArguments(&params); err != nil {
+	if err := bindParams(request, &params); err != nil {
 		return response.Errorf("failed to parse arguments: %s", err)
 	}
 
-	if params.Data == "" {
-		return response.Error("data is required")
-	}
-
 	decoded, err := base64.StdEncoding.DecodeString(params.Data)
 	if err != nil {
 		return response.Errorf("failed to decode base64 data: %s", err)
 	}
 
-	result := map[string]any{
-		"original": params.Data,
-		"decoded":  string(decoded),
-	}
-
-	return response.JSON(result)
+	return response.JSON(DecodeBase64Result{
+		Original: params.Data,
+		Decoded:  string(decoded),
+	})
 }
 
 // GetTools returns all utility-related MCP tools provided by this handler.
@@ -88,20 +89,16 @@ func (h *UtilsHandler) GetTools() []MCPTool {
 		NewMCPTool(
 			mcp.NewTool("encode_base64",
 				mcp.WithDescription("Encode text data to base64 format"),
-				mcp.WithString("data",
-					mcp.Required(),
-					mcp.Description("Text data to encode"),
-				),
+				toolschema.Input[EncodeBase64Params](),
+				toolschema.Output[EncodeBase64Result](),
 			),
 			h.EncodeBase64,
 		),
 		NewMCPTool(
 			mcp.NewTool("decode_base64",
 				mcp.WithDescription("Decode base64 data to text format"),
-				mcp.WithString("data",
-					mcp.Required(),
-					mcp.Description("Base64 data to decode"),
-				),
+				toolschema.Input[DecodeBase64Params](),
+				toolschema.Output[DecodeBase64Result](),
 			),
 			h.DecodeBase64,
 		),
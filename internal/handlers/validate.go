@@ -0,0 +1,430 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/connectivity"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/kubernetes"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/response"
+)
+
+// Manifest issue types reported by validate_manifest.
+const (
+	issueUnknownField    = "unknown_field"
+	issueTypeMismatch    = "type_mismatch"
+	issueMissingRequired = "missing_required"
+	issueInvalidValue    = "invalid_value"
+	issueUnknownKind     = "unknown_kind"
+)
+
+// maxManifestDocuments bounds how many documents a single validate_manifest
+// call accepts.
+const maxManifestDocuments = 50
+
+// ValidateManifestParams defines the parameters for the validate_manifest MCP tool.
+type ValidateManifestParams struct {
+	// Manifest is the YAML or JSON to validate. YAML may hold several
+	// documents separated by "---".
+	Manifest string `json:"manifest" required:"true" description:"Manifest to validate, as YAML or JSON. YAML may contain several documents separated by ---"`
+
+	// Context specifies the Kubernetes context whose schemas are used.
+	Context string `json:"context,omitempty" description:"Kubernetes context whose schemas to validate against (defaults to current context from kubeconfig)"`
+}
+
+// ManifestIssue is a single problem found in a manifest document.
+type ManifestIssue struct {
+	// Path is the location of the problem, such as spec.template.spec.containers[0].image.
+	Path string `json:"path"`
+
+	// Type is unknown_field, type_mismatch, missing_required, invalid_value, or unknown_kind.
+	Type string `json:"type"`
+
+	// Message describes the problem.
+	Message string `json:"message"`
+}
+
+// ManifestDocumentResult is the validation result of one manifest document.
+type ManifestDocumentResult struct {
+	Index      int             `json:"index"`
+	APIVersion string          `json:"api_version,omitempty"`
+	Kind       string          `json:"kind,omitempty"`
+	Name       string          `json:"name,omitempty"`
+	Valid      bool            `json:"valid"`
+	Issues     []ManifestIssue `json:"issues"`
+}
+
+// ValidateManifestResult is the result of the validate_manifest MCP tool.
+type ValidateManifestResult struct {
+	Valid      bool                     `json:"valid"`
+	Documents  []ManifestDocumentResult `json:"documents"`
+	Count      int                      `json:"count"`
+	IssueCount int                      `json:"issue_count"`
+}
+
+// ValidateManifest implements the validate_manifest MCP tool.
+// It checks every document of a manifest against the OpenAPI v3 schema the
+// cluster publishes for its kind, which covers built-in types and custom
+// resources with structural schemas. Validation happens entirely on the
+// client: nothing is sent to the API server except schema reads, so it is not
+// a server-side dry run and does not run admission webhooks or defaulting.
+func (h *ResourceHandler) ValidateManifest(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var params ValidateManifestParams
+	if err := bindParams(request, &params); err != nil {
+		return response.Errorf("failed to parse arguments: %s", err)
+	}
+
+	documents, err := decodeManifest(params.Manifest)
+	if err != nil {
+		return response.Errorf("failed to parse manifest: %v", err)
+	}
+
+	if len(documents) == 0 {
+		return response.Error("the manifest contains no documents")
+	}
+
+	if len(documents) > maxManifestDocuments {
+		return response.Errorf("the manifest contains %d documents, more than the maximum of %d", len(documents), maxManifestDocuments)
+	}
+
+	// Use the appropriate client based on context
+	client, err := h.client.ForContext(ctx, params.Context)
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to create client with context %s: %v", params.Context, err)
+	}
+
+	// Documents of the same group version share a schema document.
+	schemas := make(map[schema.GroupVersion]*openAPIDocument)
+	schemaErrors := make(map[schema.GroupVersion]error)
+
+	results := make([]ManifestDocumentResult, 0, len(documents))
+	issueCount := 0
+
+	for i, object := range documents {
+		result := ManifestDocumentResult{Index: i, Issues: make([]ManifestIssue, 0)}
+		result.APIVersion, _ = object["apiVersion"].(string)
+		result.Kind, _ = object["kind"].(string)
+		if metadata, ok := object["metadata"].(map[string]interface{}); ok {
+			result.Name, _ = metadata["name"].(string)
+		}
+
+		gvk, issues := manifestGroupVersionKind(result.APIVersion, result.Kind)
+		result.Issues = append(result.Issues, issues...)
+
+		if len(issues) == 0 {
+			gv := gvk.GroupVersion()
+			if _, loaded := schemas[gv]; !loaded && schemaErrors[gv] == nil {
+				doc, err := loadOpenAPIDocument(client, gv)
+				if err != nil {
+					if h.alwaysStart && connectivity.IsTransportError(err) {
+						return response.Error(connectivity.ErrorMessage(err))
+					}
+					schemaErrors[gv] = err
+				} else {
+					schemas[gv] = doc
+				}
+			}
+
+			switch doc := schemas[gv]; {
+			case doc == nil:
+				result.Issues = append(result.Issues, ManifestIssue{
+					Path:    "apiVersion",
+					Type:    issueUnknownKind,
+					Message: fmt.Sprintf("no schema is available for %s: %v", gv, schemaErrors[gv]),
+				})
+			case doc.findKind(gvk) == nil:
+				result.Issues = append(result.Issues, ManifestIssue{
+					Path:    "kind",
+					Type:    issueUnknownKind,
+					Message: fmt.Sprintf("kind %s is not served by %s", gvk.Kind, gv),
+				})
+			default:
+				doc.validate(doc.findKind(gvk), object, "", &result.Issues)
+				result.Issues = append(result.Issues, validateObjectName(object)...)
+			}
+		}
+
+		sort.SliceStable(result.Issues, func(a, b int) bool {
+			return result.Issues[a].Path < result.Issues[b].Path
+		})
+
+		result.Valid = len(result.Issues) == 0
+		issueCount += len(result.Issues)
+		results = append(results, result)
+	}
+
+	return response.JSON(ValidateManifestResult{
+		Valid:      issueCount == 0,
+		Documents:  results,
+		Count:      len(results),
+		IssueCount: issueCount,
+	})
+}
+
+// decodeManifest splits a YAML or JSON manifest into its documents, skipping
+// empty ones.
+func decodeManifest(manifest string) ([]map[string]interface{}, error) {
+	decoder := utilyaml.NewYAMLOrJSONDecoder(strings.NewReader(manifest), 4096)
+
+	var documents []map[string]interface{}
+	for {
+		var object map[string]interface{}
+		if err := decoder.Decode(&object); err != nil {
+			if errors.Is(err, io.EOF) {
+				return documents, nil
+			}
+			return nil, fmt.Errorf("document %d: %w", len(documents), err)
+		}
+
+		if len(object) > 0 {
+			documents = append(documents, object)
+		}
+	}
+}
+
+// manifestGroupVersionKind parses a document's apiVersion and kind, reporting
+// them as issues when they are missing or malformed.
+func manifestGroupVersionKind(apiVersion, kind string) (schema.GroupVersionKind, []ManifestIssue) {
+	var issues []ManifestIssue
+
+	if apiVersion == "" {
+		issues = append(issues, ManifestIssue{Path: "apiVersion", Type: issueMissingRequired, Message: "apiVersion is required"})
+	}
+	if kind == "" {
+		issues = append(issues, ManifestIssue{Path: "kind", Type: issueMissingRequired, Message: "kind is required"})
+	}
+	if len(issues) > 0 {
+		return schema.GroupVersionKind{}, issues
+	}
+
+	gv, err := schema.ParseGroupVersion(apiVersion)
+	if err != nil {
+		return schema.GroupVersionKind{}, []ManifestIssue{{Path: "apiVersion", Type: issueInvalidValue, Message: err.Error()}}
+	}
+
+	return gv.WithKind(kind), nil
+}
+
+// loadOpenAPIDocument fetches and parses the OpenAPI v3 document of a group version.
+func loadOpenAPIDocument(client kubernetes.ClusterReader, gv schema.GroupVersion) (*openAPIDocument, error) {
+	raw, err := client.GetOpenAPISchema(gv)
+	if err != nil {
+		return nil, err //nolint:wrapcheck // the client error already names the group version
+	}
+
+	var doc openAPIDocument
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse OpenAPI schema for %s: %w", gv, err)
+	}
+
+	return &doc, nil
+}
+
+// validateObjectName reports a missing metadata.name. The schemas do not mark
+// it as required because generateName may be used instead, but the API server
+// rejects objects that have neither.
+func validateObjectName(object map[string]interface{}) []ManifestIssue {
+	metadata, _ := object["metadata"].(map[string]interface{})
+	name, _ := metadata["name"].(string)
+	generateName, _ := metadata["generateName"].(string)
+
+	if name == "" && generateName == "" {
+		return []ManifestIssue{{Path: "metadata.name", Type: issueMissingRequired, Message: "metadata.name or metadata.generateName is required"}}
+	}
+
+	return nil
+}
+
+// validate checks a decoded value against a schema, appending every problem
+// to issues. Null values are accepted anywhere, as the API server drops them.
+// Schemas without a type, such as quantities, accept any value.
+func (d *openAPIDocument) validate(s *openAPISchema, value interface{}, path string, issues *[]ManifestIssue) {
+	resolved, _ := d.resolve(s)
+	if resolved == nil || value == nil {
+		return
+	}
+
+	mismatch := func(want string) {
+		*issues = append(*issues, ManifestIssue{
+			Path:    displayPath(path),
+			Type:    issueTypeMismatch,
+			Message: fmt.Sprintf("expected %s, got %s", want, jsonTypeName(value)),
+		})
+	}
+
+	if resolved.IntOrString {
+		if _, ok := value.(string); !ok && !isInteger(value) {
+			mismatch("integer or string")
+		}
+		return
+	}
+
+	switch resolved.Type {
+	case "string":
+		if _, ok := value.(string); !ok {
+			mismatch("string")
+			return
+		}
+	case "integer":
+		if !isInteger(value) {
+			mismatch("integer")
+			return
+		}
+	case "number":
+		if _, ok := value.(float64); !ok {
+			mismatch("number")
+			return
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			mismatch("boolean")
+			return
+		}
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok {
+			mismatch("array")
+			return
+		}
+		if resolved.Items != nil {
+			for i, item := range items {
+				d.validate(resolved.Items, item, path+"["+strconv.Itoa(i)+"]", issues)
+			}
+		}
+		return
+	case "object", "":
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			if resolved.Type == "object" {
+				mismatch("object")
+			}
+			return
+		}
+		d.validateObject(resolved, object, path, issues)
+		return
+	}
+
+	if len(resolved.Enum) > 0 && !enumContains(resolved.Enum, value) {
+		*issues = append(*issues, ManifestIssue{
+			Path:    displayPath(path),
+			Type:    issueInvalidValue,
+			Message: fmt.Sprintf("unsupported value %v; allowed values: %s", value, formatEnum(resolved.Enum)),
+		})
+	}
+}
+
+// validateObject checks the required fields and every field of an object.
+// Fields the schema does not declare are reported unless the schema accepts
+// arbitrary keys through additionalProperties or
+// x-kubernetes-preserve-unknown-fields.
+func (d *openAPIDocument) validateObject(s *openAPISchema, object map[string]interface{}, path string, issues *[]ManifestIssue) {
+	for _, name := range s.Required {
+		if _, ok := object[name]; !ok {
+			*issues = append(*issues, ManifestIssue{
+				Path:    joinPath(path, name),
+				Type:    issueMissingRequired,
+				Message: fmt.Sprintf("required field %q is missing", name),
+			})
+		}
+	}
+
+	values := s.mapValues()
+	freeForm := s.PreserveUnknownFields || string(s.AdditionalProperties) == "true" || (len(s.Properties) == 0 && values == nil)
+
+	names := make([]string, 0, len(object))
+	for name := range object {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		switch property, ok := s.Properties[name]; {
+		case ok:
+			d.validate(property, object[name], joinPath(path, name), issues)
+		case values != nil:
+			d.validate(values, object[name], joinPath(path, name), issues)
+		case !freeForm:
+			*issues = append(*issues, ManifestIssue{
+				Path:    joinPath(path, name),
+				Type:    issueUnknownField,
+				Message: fmt.Sprintf("field %q is not declared by the schema", name),
+			})
+		}
+	}
+}
+
+// joinPath appends a field name to a dotted path.
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// displayPath returns the path shown for the document root.
+func displayPath(path string) string {
+	if path == "" {
+		return "(root)"
+	}
+	return path
+}
+
+// isInteger reports whether a decoded JSON number has no fractional part.
+func isInteger(value interface{}) bool {
+	number, ok := value.(float64)
+	return ok && number == math.Trunc(number)
+}
+
+// jsonTypeName names the JSON type of a decoded value.
+func jsonTypeName(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case float64:
+		if v == math.Trunc(v) {
+			return "integer"
+		}
+		return "number"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+// enumContains reports whether value is one of the allowed enum values.
+func enumContains(enum []interface{}, value interface{}) bool {
+	for _, allowed := range enum {
+		if allowed == value {
+			return true
+		}
+	}
+	return false
+}
+
+// formatEnum renders allowed enum values as a comma-separated list.
+func formatEnum(enum []interface{}) string {
+	values := make([]string, 0, len(enum))
+	for _, allowed := range enum {
+		values = append(values, fmt.Sprint(allowed))
+	}
+	return strings.Join(values, ", ")
+}
@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/fakecluster"
+)
+
+// widgetOpenAPI is an OpenAPI v3 document for a custom resource with a
+// structural schema, shaped like the one the API server publishes for CRDs.
+const widgetOpenAPI = `{
+  "components": {
+    "schemas": {
+      "com.example.v1.Widget": {
+        "type": "object",
+        "properties": {
+          "apiVersion": {"type": "string"},
+          "kind": {"type": "string"},
+          "metadata": {"allOf": [{"$ref": "#/components/schemas/io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta"}]},
+          "spec": {
+            "type": "object",
+            "required": ["size"],
+            "properties": {
+              "size": {"type": "integer"},
+              "mode": {"type": "string", "enum": ["fast", "slow"]},
+              "port": {"x-kubernetes-int-or-string": true},
+              "tags": {"type": "array", "items": {"type": "string"}},
+              "config": {"type": "object", "x-kubernetes-preserve-unknown-fields": true}
+            }
+          }
+        },
+        "x-kubernetes-group-version-kind": [{"group": "example.com", "kind": "Widget", "version": "v1"}]
+      },
+      "io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta": {
+        "type": "object",
+        "properties": {
+          "name": {"type": "string"},
+          "generateName": {"type": "string"},
+          "labels": {"type": "object", "additionalProperties": {"type": "string"}}
+        }
+      }
+    }
+  }
+}`
+
+func TestValidateManifest(t *testing.T) {
+	t.Parallel()
+
+	handler := NewResourceHandler(fakecluster.New(fakecluster.Config{
+		OpenAPISchemas: map[string][]byte{"example.com/v1": []byte(widgetOpenAPI)},
+	}), nil, false)
+
+	tests := []struct {
+		name       string
+		manifest   string
+		wantIssues [][]ManifestIssue
+		wantErr    bool
+	}{
+		{
+			name: "valid",
+			manifest: `apiVersion: example.com/v1
+kind: Widget
+metadata:
+  name: small
+  labels:
+    app: shop
+spec:
+  size: 3
+  mode: fast
+  port: http
+  tags: [a, b]
+  config:
+    anything: goes
+`,
+			wantIssues: [][]ManifestIssue{{}},
+		},
+		{
+			name: "every kind of issue across documents",
+			manifest: `apiVersion: example.com/v1
+kind: Widget
+metadata:
+  labels:
+    app: 1
+spec:
+  mode: medium
+  port: 1.5
+  tags: a
+  colour: red
+---
+apiVersion: example.com/v1
+kind: Gadget
+metadata:
+  name: other
+---
+kind: Widget
+`,
+			wantIssues: [][]ManifestIssue{
+				{
+					{Path: "metadata.labels.app", Type: issueTypeMismatch, Message: "expected string, got integer"},
+					{Path: "metadata.name", Type: issueMissingRequired, Message: "metadata.name or metadata.generateName is required"},
+					{Path: "spec.colour", Type: issueUnknownField, Message: `field "colour" is not declared by the schema`},
+					{Path: "spec.mode", Type: issueInvalidValue, Message: "unsupported value medium; allowed values: fast, slow"},
+					{Path: "spec.port", Type: issueTypeMismatch, Message: "expected integer or string, got number"},
+					{Path: "spec.size", Type: issueMissingRequired, Message: `required field "size" is missing`},
+					{Path: "spec.tags", Type: issueTypeMismatch, Message: "expected array, got string"},
+				},
+				{
+					{Path: "kind", Type: issueUnknownKind, Message: "kind Gadget is not served by example.com/v1"},
+				},
+				{
+					{Path: "apiVersion", Type: issueMissingRequired, Message: "apiVersion is required"},
+				},
+			},
+		},
+		{
+			name:     "malformed",
+			manifest: "apiVersion: [",
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			result, isErr := callTool(t, handler.ValidateManifest, map[string]any{"manifest": tt.manifest})
+			if isErr != tt.wantErr {
+				t.Fatalf("expected error %t, got result %v", tt.wantErr, result)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			var documents []ManifestDocumentResult
+			decodeInto(t, result["documents"], &documents)
+
+			got := make([][]ManifestIssue, 0, len(documents))
+			for _, document := range documents {
+				got = append(got, document.Issues)
+			}
+			if !reflect.DeepEqual(got, tt.wantIssues) {
+				t.Errorf("expected issues %+v, got %+v", tt.wantIssues, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,458 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/connectivity"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/kubernetes"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/response"
+)
+
+const (
+	// webhookTypeValidating and webhookTypeMutating name the two kinds of
+	// admission webhook configurations.
+	webhookTypeValidating = "validating"
+	webhookTypeMutating   = "mutating"
+
+	// defaultWebhookTimeoutSeconds and defaultWebhookPort are what the API
+	// server uses when a webhook leaves them unset.
+	defaultWebhookTimeoutSeconds = 10
+	defaultWebhookPort           = 443
+
+	// longWebhookTimeoutSeconds is the timeout above which list_webhooks
+	// flags how long matching requests may wait.
+	longWebhookTimeoutSeconds = 15
+
+	// caInjectionAnnotation asks cert-manager's cainjector to fill in the
+	// caBundle of a webhook configuration.
+	caInjectionAnnotation = "cert-manager.io/inject-ca-from"
+)
+
+// ListWebhooksParams defines the parameters for the list_webhooks MCP tool.
+type ListWebhooksParams struct {
+	// Type limits the report to validating or mutating webhooks.
+	Type string `json:"type,omitempty" enum:"validating,mutating" description:"Only report \"validating\" or \"mutating\" webhooks (defaults to both)"`
+
+	// OnlyProblems limits the report to webhooks with findings.
+	OnlyProblems bool `json:"only_problems,omitempty" description:"When true, returns only webhooks with findings, such as a missing backing Service or no ready endpoints"`
+
+	// Context specifies which Kubernetes context to use for this operation.
+	Context string `json:"context,omitempty" description:"Kubernetes context to use (defaults to current context from kubeconfig)"`
+}
+
+// WebhookInfo describes an admission webhook and whether its backend can
+// answer.
+type WebhookInfo struct {
+	Configuration string `json:"configuration"`
+	Type          string `json:"type"`
+	Name          string `json:"name"`
+
+	// FailurePolicy, MatchPolicy, and TimeoutSeconds are reported with the
+	// API server's defaults when unset.
+	FailurePolicy      string   `json:"failure_policy"`
+	MatchPolicy        string   `json:"match_policy"`
+	SideEffects        string   `json:"side_effects,omitempty"`
+	TimeoutSeconds     int32    `json:"timeout_seconds"`
+	ReinvocationPolicy string   `json:"reinvocation_policy,omitempty"`
+	Rules              []string `json:"rules"`
+
+	// NamespaceSelector and ObjectSelector are empty when the webhook
+	// applies to every namespace or object.
+	NamespaceSelector string `json:"namespace_selector,omitempty"`
+	ObjectSelector    string `json:"object_selector,omitempty"`
+	MatchConditions   int    `json:"match_conditions,omitempty"`
+
+	// Service is the backing Service as namespace/name:port/path; URL is
+	// set instead for webhooks outside the cluster.
+	Service        string   `json:"service,omitempty"`
+	URL            string   `json:"url,omitempty"`
+	CABundle       bool     `json:"ca_bundle"`
+	ServiceExists  *bool    `json:"service_exists,omitempty"`
+	ReadyEndpoints *int     `json:"ready_endpoints,omitempty"`
+	Findings       []string `json:"findings,omitempty"`
+}
+
+// webhookSpec holds the fields validating and mutating webhooks share.
+type webhookSpec struct {
+	configuration      metav1.ObjectMeta
+	webhookType        string
+	name               string
+	clientConfig       admissionregistrationv1.WebhookClientConfig
+	rules              []admissionregistrationv1.RuleWithOperations
+	failurePolicy      *admissionregistrationv1.FailurePolicyType
+	matchPolicy        *admissionregistrationv1.MatchPolicyType
+	namespaceSelector  *metav1.LabelSelector
+	objectSelector     *metav1.LabelSelector
+	sideEffects        *admissionregistrationv1.SideEffectClass
+	timeoutSeconds     *int32
+	reinvocationPolicy *admissionregistrationv1.ReinvocationPolicyType
+	matchConditions    int
+}
+
+// webhookBackend is what list_webhooks found about a webhook's Service.
+type webhookBackend struct {
+	exists         bool
+	externalName   bool
+	ports          []int32
+	readyEndpoints int
+}
+
+// ListWebhooksResult is the result of the list_webhooks MCP tool.
+type ListWebhooksResult struct {
+	Webhooks []WebhookInfo `json:"webhooks"`
+	Count    int           `json:"count"`
+
+	// ByFailurePolicy counts the webhooks by failure policy.
+	ByFailurePolicy      map[string]int `json:"by_failure_policy"`
+	WebhooksWithFindings int            `json:"webhooks_with_findings"`
+
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// ListWebhooks implements the list_webhooks MCP tool.
+// It lists the webhooks of every ValidatingWebhookConfiguration and
+// MutatingWebhookConfiguration with their failure policy, selectors,
+// timeout, and rules, and checks that the Service behind each webhook
+// exists and has ready endpoints. A webhook that fails closed with no
+// backend rejects every request it matches, which is a common cause of
+// cluster-wide API failures.
+func (h *ClusterHandler) ListWebhooks(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var params ListWebhooksParams
+	if err := bindParams(request, &params); err != nil {
+		return response.Errorf("failed to parse arguments: %s", err)
+	}
+
+	if params.Type != "" && params.Type != webhookTypeValidating && params.Type != webhookTypeMutating {
+		return response.Errorf("invalid type %q: must be %q or %q", params.Type, webhookTypeValidating, webhookTypeMutating)
+	}
+
+	// Use the appropriate client based on context
+	client, err := h.client.ForContext(ctx, params.Context)
+	if err != nil {
+		if h.alwaysStart && connectivity.IsTransportError(err) {
+			return response.Error(connectivity.ErrorMessage(err))
+		}
+		return response.Errorf("failed to create client with context %s: %v", params.Context, err)
+	}
+
+	var specs []webhookSpec
+
+	if params.Type != webhookTypeMutating {
+		list, err := client.ListValidatingWebhookConfigurations(ctx, metav1.ListOptions{})
+		if err != nil {
+			if h.alwaysStart && connectivity.IsTransportError(err) {
+				return response.Error(connectivity.ErrorMessage(err))
+			}
+			return response.Errorf("failed to list validating webhook configurations: %v", err)
+		}
+		for i := range list.Items {
+			for _, webhook := range list.Items[i].Webhooks {
+				specs = append(specs, webhookSpec{
+					configuration:     list.Items[i].ObjectMeta,
+					webhookType:       webhookTypeValidating,
+					name:              webhook.Name,
+					clientConfig:      webhook.ClientConfig,
+					rules:             webhook.Rules,
+					failurePolicy:     webhook.FailurePolicy,
+					matchPolicy:       webhook.MatchPolicy,
+					namespaceSelector: webhook.NamespaceSelector,
+					objectSelector:    webhook.ObjectSelector,
+					sideEffects:       webhook.SideEffects,
+					timeoutSeconds:    webhook.TimeoutSeconds,
+					matchConditions:   len(webhook.MatchConditions),
+				})
+			}
+		}
+	}
+
+	if params.Type != webhookTypeValidating {
+		list, err := client.ListMutatingWebhookConfigurations(ctx, metav1.ListOptions{})
+		if err != nil {
+			if h.alwaysStart && connectivity.IsTransportError(err) {
+				return response.Error(connectivity.ErrorMessage(err))
+			}
+			return response.Errorf("failed to list mutating webhook configurations: %v", err)
+		}
+		for i := range list.Items {
+			for _, webhook := range list.Items[i].Webhooks {
+				specs = append(specs, webhookSpec{
+					configuration:      list.Items[i].ObjectMeta,
+					webhookType:        webhookTypeMutating,
+					name:               webhook.Name,
+					clientConfig:       webhook.ClientConfig,
+					rules:              webhook.Rules,
+					failurePolicy:      webhook.FailurePolicy,
+					matchPolicy:        webhook.MatchPolicy,
+					namespaceSelector:  webhook.NamespaceSelector,
+					objectSelector:     webhook.ObjectSelector,
+					sideEffects:        webhook.SideEffects,
+					timeoutSeconds:     webhook.TimeoutSeconds,
+					reinvocationPolicy: webhook.ReinvocationPolicy,
+					matchConditions:    len(webhook.MatchConditions),
+				})
+			}
+		}
+	}
+
+	var warnings []string
+	backends := make(map[string]*webhookBackend)
+
+	webhooks := make([]WebhookInfo, 0, len(specs))
+	failurePolicies := make(map[string]int)
+	withFindings := 0
+
+	for i := range specs {
+		spec := &specs[i]
+
+		var backend *webhookBackend
+		if ref := spec.clientConfig.Service; ref != nil {
+			key := ref.Namespace + "/" + ref.Name
+			var ok bool
+			if backend, ok = backends[key]; !ok {
+				backend, err = lookupWebhookBackend(ctx, client, ref)
+				if err != nil {
+					if h.alwaysStart && connectivity.IsTransportError(err) {
+						return response.Error(connectivity.ErrorMessage(err))
+					}
+					warnings = append(warnings, fmt.Sprintf("failed to check Service %s: %v", key, err))
+				}
+				backends[key] = backend
+			}
+		}
+
+		entry := webhookInfo(spec, backend)
+		failurePolicies[entry.FailurePolicy]++
+		if len(entry.Findings) > 0 {
+			withFindings++
+		} else if params.OnlyProblems {
+			continue
+		}
+		webhooks = append(webhooks, entry)
+	}
+
+	// Entries with findings first, then by type, configuration, and name
+	sort.SliceStable(webhooks, func(i, j int) bool {
+		a, b := webhooks[i], webhooks[j]
+		if (len(a.Findings) > 0) != (len(b.Findings) > 0) {
+			return len(a.Findings) > 0
+		}
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		if a.Configuration != b.Configuration {
+			return a.Configuration < b.Configuration
+		}
+		return a.Name < b.Name
+	})
+
+	return response.JSON(ListWebhooksResult{
+		Webhooks:             webhooks,
+		Count:                len(specs),
+		ByFailurePolicy:      failurePolicies,
+		WebhooksWithFindings: withFindings,
+		Warnings:             warnings,
+	})
+}
+
+// lookupWebhookBackend reads the Service a webhook calls and counts its
+// ready endpoints. A Service that does not exist is not an error.
+func lookupWebhookBackend(ctx context.Context, client kubernetes.ClusterReader, ref *admissionregistrationv1.ServiceReference) (*webhookBackend, error) {
+	service, err := client.GetService(ctx, ref.Namespace, ref.Name)
+	if apierrors.IsNotFound(err) {
+		return &webhookBackend{}, nil
+	}
+	if err != nil {
+		return nil, err //nolint:wrapcheck // kubernetes API errors are self-descriptive
+	}
+
+	backend := &webhookBackend{
+		exists:       true,
+		externalName: service.Spec.Type == corev1.ServiceTypeExternalName,
+	}
+	for _, port := range service.Spec.Ports {
+		backend.ports = append(backend.ports, port.Port)
+	}
+
+	if backend.externalName {
+		return backend, nil
+	}
+
+	slices, err := client.ListEndpointSlices(ctx, service.Namespace, metav1.ListOptions{
+		LabelSelector: discoveryv1.LabelServiceName + "=" + service.Name,
+	})
+	if err != nil {
+		return nil, err //nolint:wrapcheck // kubernetes API errors are self-descriptive
+	}
+
+	for _, slice := range serviceEndpointSlices(slices.Items) {
+		for _, endpoint := range slice.Endpoints {
+			if endpoint.Ready {
+				backend.readyEndpoints++
+			}
+		}
+	}
+
+	return backend, nil
+}
+
+// webhookInfo describes a webhook, filling in the API server's defaults, and
+// explains what may make it reject or slow down API requests. backend is
+// nil for URL webhooks and when the Service could not be read.
+func webhookInfo(spec *webhookSpec, backend *webhookBackend) WebhookInfo {
+	entry := WebhookInfo{
+		Configuration:   spec.configuration.Name,
+		Type:            spec.webhookType,
+		Name:            spec.name,
+		FailurePolicy:   string(admissionregistrationv1.Fail),
+		MatchPolicy:     string(admissionregistrationv1.Equivalent),
+		TimeoutSeconds:  defaultWebhookTimeoutSeconds,
+		Rules:           make([]string, 0, len(spec.rules)),
+		CABundle:        len(spec.clientConfig.CABundle) > 0,
+		MatchConditions: spec.matchConditions,
+	}
+
+	if spec.failurePolicy != nil {
+		entry.FailurePolicy = string(*spec.failurePolicy)
+	}
+	if spec.matchPolicy != nil {
+		entry.MatchPolicy = string(*spec.matchPolicy)
+	}
+	if spec.timeoutSeconds != nil {
+		entry.TimeoutSeconds = *spec.timeoutSeconds
+	}
+	if spec.sideEffects != nil {
+		entry.SideEffects = string(*spec.sideEffects)
+	}
+	if spec.reinvocationPolicy != nil {
+		entry.ReinvocationPolicy = string(*spec.reinvocationPolicy)
+	} else if spec.webhookType == webhookTypeMutating {
+		entry.ReinvocationPolicy = string(admissionregistrationv1.NeverReinvocationPolicy)
+	}
+
+	matchesEverything := false
+	for _, rule := range spec.rules {
+		entry.Rules = append(entry.Rules, webhookRule(rule))
+		if containsString(rule.APIGroups, "*") && containsString(rule.Resources, "*") {
+			matchesEverything = true
+		}
+	}
+
+	entry.NamespaceSelector = webhookSelector(spec.namespaceSelector)
+	entry.ObjectSelector = webhookSelector(spec.objectSelector)
+
+	failClosed := entry.FailurePolicy == string(admissionregistrationv1.Fail)
+	impact := "matching requests are admitted without the webhook after each call fails"
+	if failClosed {
+		impact = "every matching request is rejected"
+	}
+
+	if ref := spec.clientConfig.Service; ref != nil {
+		port := int32(defaultWebhookPort)
+		if ref.Port != nil {
+			port = *ref.Port
+		}
+		entry.Service = fmt.Sprintf("%s/%s:%d", ref.Namespace, ref.Name, port)
+		if ref.Path != nil {
+			entry.Service += *ref.Path
+		}
+
+		if backend != nil {
+			exists := backend.exists
+			entry.ServiceExists = &exists
+
+			switch {
+			case !backend.exists:
+				entry.Findings = append(entry.Findings, fmt.Sprintf("Service %s/%s does not exist, so %s", ref.Namespace, ref.Name, impact))
+			case !containsInt32(backend.ports, port):
+				entry.Findings = append(entry.Findings, fmt.Sprintf("Service %s/%s has no port %d, so %s", ref.Namespace, ref.Name, port, impact))
+			case !backend.externalName:
+				ready := backend.readyEndpoints
+				entry.ReadyEndpoints = &ready
+				if ready == 0 {
+					entry.Findings = append(entry.Findings, fmt.Sprintf("Service %s/%s has no ready endpoints, so %s", ref.Namespace, ref.Name, impact))
+				}
+			}
+		}
+
+		if !entry.CABundle {
+			finding := "has no caBundle, so the API server checks the webhook's serving certificate against its own trusted roots, which in-cluster certificates usually fail"
+			if source := spec.configuration.Annotations[caInjectionAnnotation]; source != "" {
+				finding = fmt.Sprintf("has no caBundle although cert-manager is asked to inject it from %s; check that the cainjector is running and the Certificate is ready", source)
+			}
+			entry.Findings = append(entry.Findings, finding)
+		}
+	} else if spec.clientConfig.URL != nil {
+		entry.URL = *spec.clientConfig.URL
+	}
+
+	if failClosed && entry.NamespaceSelector == "" && entry.ObjectSelector == "" && entry.MatchConditions == 0 {
+		entry.Findings = append(entry.Findings, "fails closed for every namespace, including kube-system; if the webhook is down, matching requests from system components are rejected too. Exclude system namespaces with a namespaceSelector")
+	}
+
+	if matchesEverything {
+		entry.Findings = append(entry.Findings, "matches every resource in every API group, so any outage or slowdown of the webhook affects the whole API")
+	}
+
+	if entry.TimeoutSeconds > longWebhookTimeoutSeconds {
+		entry.Findings = append(entry.Findings, fmt.Sprintf("has a timeout of %ds, so each matching request may wait that long when the webhook is slow or unreachable", entry.TimeoutSeconds))
+	}
+
+	if entry.SideEffects == string(admissionregistrationv1.SideEffectClassSome) || entry.SideEffects == string(admissionregistrationv1.SideEffectClassUnknown) {
+		entry.Findings = append(entry.Findings, fmt.Sprintf("declares sideEffects %s, so dry-run requests it matches, such as kubectl diff, are rejected", entry.SideEffects))
+	}
+
+	return entry
+}
+
+// webhookRule renders a rule as "OPERATIONS group/version/resources", with
+// the core group written as "core" and the scope appended when it is not
+// "*".
+func webhookRule(rule admissionregistrationv1.RuleWithOperations) string {
+	operations := make([]string, 0, len(rule.Operations))
+	for _, operation := range rule.Operations {
+		operations = append(operations, string(operation))
+	}
+
+	groups := make([]string, 0, len(rule.APIGroups))
+	for _, group := range rule.APIGroups {
+		if group == "" {
+			group = "core"
+		}
+		groups = append(groups, group)
+	}
+
+	result := fmt.Sprintf("%s %s/%s/%s", strings.Join(operations, ","), strings.Join(groups, ","), strings.Join(rule.APIVersions, ","), strings.Join(rule.Resources, ","))
+	if rule.Scope != nil && *rule.Scope != admissionregistrationv1.AllScopes {
+		result += " (" + string(*rule.Scope) + ")"
+	}
+	return result
+}
+
+// webhookSelector renders a label selector, or an empty string when it
+// matches everything.
+func webhookSelector(selector *metav1.LabelSelector) string {
+	if selector == nil || (len(selector.MatchLabels) == 0 && len(selector.MatchExpressions) == 0) {
+		return ""
+	}
+	return metav1.FormatLabelSelector(selector)
+}
+
+// containsInt32 reports whether values contains value.
+func containsInt32(values []int32, value int32) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
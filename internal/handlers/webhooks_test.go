@@ -0,0 +1,195 @@
+package handlers
+
+import (
+	"reflect"
+	"testing"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/fakecluster"
+)
+
+func TestWebhookInfo(t *testing.T) {
+	t.Parallel()
+
+	fail := admissionregistrationv1.Fail
+	ignore := admissionregistrationv1.Ignore
+	none := admissionregistrationv1.SideEffectClassNone
+	some := admissionregistrationv1.SideEffectClassSome
+	timeout := int32(30)
+	namespaced := admissionregistrationv1.NamespacedScope
+	service := &admissionregistrationv1.ServiceReference{Namespace: "policy", Name: "gatekeeper"}
+	selector := &metav1.LabelSelector{MatchLabels: map[string]string{"policy": "enforced"}}
+	podRule := admissionregistrationv1.RuleWithOperations{
+		Operations: []admissionregistrationv1.OperationType{admissionregistrationv1.Create, admissionregistrationv1.Update},
+		Rule:       admissionregistrationv1.Rule{APIGroups: []string{""}, APIVersions: []string{"v1"}, Resources: []string{"pods"}, Scope: &namespaced},
+	}
+
+	tests := []struct {
+		name      string
+		spec      webhookSpec
+		backend   *webhookBackend
+		wantRules []string
+		want      []string
+	}{
+		{
+			name: "healthy",
+			spec: webhookSpec{
+				clientConfig:      admissionregistrationv1.WebhookClientConfig{Service: service, CABundle: []byte("ca")},
+				rules:             []admissionregistrationv1.RuleWithOperations{podRule},
+				failurePolicy:     &fail,
+				namespaceSelector: selector,
+				sideEffects:       &none,
+			},
+			backend:   &webhookBackend{exists: true, ports: []int32{443}, readyEndpoints: 2},
+			wantRules: []string{"CREATE,UPDATE core/v1/pods (Namespaced)"},
+		},
+		{
+			name: "missing service fails closed",
+			spec: webhookSpec{
+				clientConfig:      admissionregistrationv1.WebhookClientConfig{Service: service, CABundle: []byte("ca")},
+				failurePolicy:     &fail,
+				namespaceSelector: selector,
+			},
+			backend: &webhookBackend{},
+			want:    []string{"Service policy/gatekeeper does not exist, so every matching request is rejected"},
+		},
+		{
+			name: "no ready endpoints fails open",
+			spec: webhookSpec{
+				clientConfig:  admissionregistrationv1.WebhookClientConfig{Service: service, CABundle: []byte("ca")},
+				failurePolicy: &ignore,
+			},
+			backend: &webhookBackend{exists: true, ports: []int32{443}},
+			want:    []string{"Service policy/gatekeeper has no ready endpoints, so matching requests are admitted without the webhook after each call fails"},
+		},
+		{
+			name: "wrong port",
+			spec: webhookSpec{
+				clientConfig:      admissionregistrationv1.WebhookClientConfig{Service: service, CABundle: []byte("ca")},
+				namespaceSelector: selector,
+			},
+			backend: &webhookBackend{exists: true, ports: []int32{8443}, readyEndpoints: 1},
+			want:    []string{"Service policy/gatekeeper has no port 443, so every matching request is rejected"},
+		},
+		{
+			name: "risky configuration",
+			spec: webhookSpec{
+				configuration: metav1.ObjectMeta{Annotations: map[string]string{caInjectionAnnotation: "policy/gatekeeper-cert"}},
+				clientConfig:  admissionregistrationv1.WebhookClientConfig{Service: service},
+				rules: []admissionregistrationv1.RuleWithOperations{{
+					Operations: []admissionregistrationv1.OperationType{admissionregistrationv1.OperationAll},
+					Rule:       admissionregistrationv1.Rule{APIGroups: []string{"*"}, APIVersions: []string{"*"}, Resources: []string{"*"}},
+				}},
+				sideEffects:    &some,
+				timeoutSeconds: &timeout,
+			},
+			backend:   &webhookBackend{exists: true, ports: []int32{443}, readyEndpoints: 1},
+			wantRules: []string{"* */*/*"},
+			want: []string{
+				"has no caBundle although cert-manager is asked to inject it from policy/gatekeeper-cert; check that the cainjector is running and the Certificate is ready",
+				"fails closed for every namespace, including kube-system; if the webhook is down, matching requests from system components are rejected too. Exclude system namespaces with a namespaceSelector",
+				"matches every resource in every API group, so any outage or slowdown of the webhook affects the whole API",
+				"has a timeout of 30s, so each matching request may wait that long when the webhook is slow or unreachable",
+				"declares sideEffects Some, so dry-run requests it matches, such as kubectl diff, are rejected",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := webhookInfo(&tt.spec, tt.backend)
+			if !reflect.DeepEqual(got.Findings, tt.want) {
+				t.Errorf("expected findings %q, got %q", tt.want, got.Findings)
+			}
+			if tt.wantRules != nil && !reflect.DeepEqual(got.Rules, tt.wantRules) {
+				t.Errorf("expected rules %q, got %q", tt.wantRules, got.Rules)
+			}
+		})
+	}
+}
+
+func TestListWebhooks_FakeCluster(t *testing.T) {
+	t.Parallel()
+
+	ignore := admissionregistrationv1.Ignore
+	ready := true
+	selector := &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{
+		Key: "kubernetes.io/metadata.name", Operator: metav1.LabelSelectorOpNotIn, Values: []string{"kube-system"},
+	}}}
+
+	handler := NewClusterHandler(fakecluster.New(fakecluster.Config{
+		Objects: []runtime.Object{
+			&admissionregistrationv1.ValidatingWebhookConfiguration{
+				ObjectMeta: metav1.ObjectMeta{Name: "gatekeeper"},
+				Webhooks: []admissionregistrationv1.ValidatingWebhook{{
+					Name:              "validation.gatekeeper.sh",
+					ClientConfig:      admissionregistrationv1.WebhookClientConfig{Service: &admissionregistrationv1.ServiceReference{Namespace: "policy", Name: "gatekeeper"}, CABundle: []byte("ca")},
+					NamespaceSelector: selector,
+				}},
+			},
+			&admissionregistrationv1.MutatingWebhookConfiguration{
+				ObjectMeta: metav1.ObjectMeta{Name: "istio-sidecar-injector"},
+				Webhooks: []admissionregistrationv1.MutatingWebhook{{
+					Name:              "sidecar-injector.istio.io",
+					ClientConfig:      admissionregistrationv1.WebhookClientConfig{Service: &admissionregistrationv1.ServiceReference{Namespace: "istio-system", Name: "istiod"}, CABundle: []byte("ca")},
+					FailurePolicy:     &ignore,
+					NamespaceSelector: selector,
+				}},
+			},
+			&corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{Name: "istiod", Namespace: "istio-system"},
+				Spec:       corev1.ServiceSpec{Ports: []corev1.ServicePort{{Port: 443}}},
+			},
+			&discoveryv1.EndpointSlice{
+				ObjectMeta: metav1.ObjectMeta{Name: "istiod-abc", Namespace: "istio-system", Labels: map[string]string{discoveryv1.LabelServiceName: "istiod"}},
+				Endpoints:  []discoveryv1.Endpoint{{Addresses: []string{"10.0.0.5"}, Conditions: discoveryv1.EndpointConditions{Ready: &ready}}},
+			},
+		},
+	}), false)
+
+	result, isErr := callTool(t, handler.ListWebhooks, map[string]any{})
+	if isErr {
+		t.Fatalf("unexpected error: %v", result["error"])
+	}
+
+	var webhooks []WebhookInfo
+	decodeInto(t, result["webhooks"], &webhooks)
+	if len(webhooks) != 2 {
+		t.Fatalf("expected 2 webhooks, got %+v", webhooks)
+	}
+
+	missing := webhooks[0]
+	if missing.Name != "validation.gatekeeper.sh" || missing.ServiceExists == nil || *missing.ServiceExists || len(missing.Findings) != 1 {
+		t.Errorf("expected the webhook with a missing Service first, got %+v", missing)
+	}
+	if missing.FailurePolicy != "Fail" || missing.TimeoutSeconds != defaultWebhookTimeoutSeconds || missing.NamespaceSelector != "kubernetes.io/metadata.name notin (kube-system)" {
+		t.Errorf("expected the API server defaults and the selector, got %+v", missing)
+	}
+
+	healthy := webhooks[1]
+	if healthy.ReadyEndpoints == nil || *healthy.ReadyEndpoints != 1 || healthy.ReinvocationPolicy != "Never" || len(healthy.Findings) != 0 {
+		t.Errorf("unexpected healthy webhook %+v", healthy)
+	}
+
+	result, isErr = callTool(t, handler.ListWebhooks, map[string]any{"type": "mutating", "only_problems": true})
+	if isErr {
+		t.Fatalf("unexpected error: %v", result["error"])
+	}
+
+	decodeInto(t, result["webhooks"], &webhooks)
+	if len(webhooks) != 0 || result["count"] != float64(1) {
+		t.Errorf("expected no mutating webhooks with findings out of 1, got %+v", result)
+	}
+
+	if _, isErr := callTool(t, handler.ListWebhooks, map[string]any{"type": "admission"}); !isErr {
+		t.Error("expected an error for an unknown type")
+	}
+}
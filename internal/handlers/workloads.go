@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/kubernetes"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/toolschema"
+)
+
+// WorkloadHandler provides MCP tools that inspect workload controllers such
+// as Deployments, StatefulSets, and DaemonSets, and the PodDisruptionBudgets
+// that protect them.
+type WorkloadHandler struct {
+	client      kubernetes.ClusterReader
+	alwaysStart bool
+
+	// now returns the current time. It defaults to time.Now and is replaced
+	// in tests so ages are deterministic.
+	now func() time.Time
+}
+
+// NewWorkloadHandler creates a new WorkloadHandler with the provided Kubernetes client.
+// alwaysStart mirrors the --always-start flag: when true, connectivity and auth errors
+// are intercepted and returned as structured tool errors so the LLM can surface them
+// to the user rather than treating them as retryable failures.
+func NewWorkloadHandler(client kubernetes.ClusterReader, alwaysStart bool) *WorkloadHandler {
+	return &WorkloadHandler{
+		client:      client,
+		alwaysStart: alwaysStart,
+		now:         time.Now,
+	}
+}
+
+// GetTools returns all workload MCP tools provided by this handler.
+func (h *WorkloadHandler) GetTools() []MCPTool {
+	return []MCPTool{
+		NewMCPTool(
+			mcp.NewTool("get_rollout_status",
+				mcp.WithDescription("Report the rollout status of a Deployment, StatefulSet, or DaemonSet, like \"kubectl rollout status\" without waiting: desired, updated, ready, and available replica counts, whether the controller has observed the latest spec, rollout conditions, and whether the rollout is complete, still progressing, paused, or stuck because its progress deadline was exceeded."),
+				toolschema.Input[GetRolloutStatusParams](),
+				toolschema.Output[RolloutStatus](),
+			),
+			h.GetRolloutStatus,
+		),
+		NewMCPTool(
+			mcp.NewTool("gc_policy_report",
+				mcp.WithDescription("Audit garbage collection and cleanup policies: lists Jobs without ttlSecondsAfterFinished (Jobs created by CronJobs are excluded because their history limits clean them up), Deployments, StatefulSets, and DaemonSets that keep the default or a larger revisionHistoryLimit along with the old ReplicaSets Deployments retain, and the Succeeded and Failed pods accumulated per namespace. Each finding includes a kubectl command to review and run manually; nothing is changed."),
+				toolschema.Input[GCPolicyReportParams](),
+				toolschema.Output[GCPolicyReportResult](),
+			),
+			h.GCPolicyReport,
+		),
+		NewMCPTool(
+			mcp.NewTool("list_pod_disruption_budgets",
+				mcp.WithDescription("List PodDisruptionBudgets with their minAvailable or maxUnavailable, expected, current, and desired healthy pod counts, and how many disruptions are allowed right now. Each budget is matched to the workloads whose pods it selects. Budgets that currently block node drains are listed first with the nodes whose drain would wait on them, along with budgets that match no pods or overlap with another budget, which makes evictions fail."),
+				toolschema.Input[ListPodDisruptionBudgetsParams](),
+				toolschema.Output[ListPodDisruptionBudgetsResult](),
+			),
+			h.ListPodDisruptionBudgets,
+		),
+	}
+}
@@ -0,0 +1,110 @@
+// Package impersonation adds per-request impersonation to tools. When
+// enabled, every tool that accepts a kubeconfig context also accepts an "as"
+// user and "as_groups" groups, and its Kubernetes requests are made with
+// impersonation headers for that identity. This lets a server running with
+// broad credentials answer each query with the permissions of a restricted
+// user.
+package impersonation
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/kubernetes"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/response"
+)
+
+const (
+	// UserArgument is the tool argument naming the user to act as.
+	UserArgument = "as"
+
+	// GroupsArgument is the tool argument listing the groups to act as.
+	GroupsArgument = "as_groups"
+
+	// contextArgument marks the tools that talk to a cluster: only those
+	// get the impersonation arguments.
+	contextArgument = "context"
+)
+
+// AddParameters returns tool with the impersonation arguments added to its
+// input schema, and whether it was changed. Tools without a context argument
+// do not query a cluster and are returned unchanged.
+//
+//nolint:gocritic // mcp.Tool is passed by value the way the MCP server registers it
+func AddParameters(tool mcp.Tool) (mcp.Tool, bool) {
+	if _, ok := tool.InputSchema.Properties[contextArgument]; !ok {
+		return tool, false
+	}
+
+	// The properties map may be shared with the original tool definition,
+	// so the arguments are added to a copy.
+	properties := make(map[string]any, len(tool.InputSchema.Properties)+2)
+	for name, property := range tool.InputSchema.Properties {
+		properties[name] = property
+	}
+	properties[UserArgument] = map[string]any{
+		"type":        "string",
+		"description": "Kubernetes user to impersonate for this request, so it only sees what that user is allowed to read (defaults to the server's configured identity)",
+	}
+	properties[GroupsArgument] = map[string]any{
+		"type":        "array",
+		"items":       map[string]any{"type": "string"},
+		"description": "Kubernetes groups to impersonate for this request, together with the user given in as",
+	}
+	tool.InputSchema.Properties = properties
+
+	return tool, true
+}
+
+// Wrap returns a handler that reads the impersonation arguments of a call
+// and runs next with them set on the context through
+// kubernetes.WithImpersonation. Calls without them run next unchanged.
+func Wrap(next func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error)) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		imp, err := FromArguments(request.GetArguments())
+		if err != nil {
+			return response.Errorf("failed to parse arguments: %s", err)
+		}
+		if imp.IsZero() {
+			return next(ctx, request)
+		}
+		if err := imp.Validate(); err != nil {
+			return response.Error(err.Error())
+		}
+
+		return next(kubernetes.WithImpersonation(ctx, imp), request)
+	}
+}
+
+// FromArguments reads the impersonation arguments of a tool call.
+func FromArguments(args map[string]any) (kubernetes.Impersonation, error) {
+	var imp kubernetes.Impersonation
+
+	if raw, ok := args[UserArgument]; ok && raw != nil {
+		user, ok := raw.(string)
+		if !ok {
+			return imp, fmt.Errorf("%s must be a string", UserArgument)
+		}
+		imp.User = user
+	}
+
+	if raw, ok := args[GroupsArgument]; ok && raw != nil {
+		groups, ok := raw.([]any)
+		if !ok {
+			return imp, fmt.Errorf("%s must be an array of strings", GroupsArgument)
+		}
+		for _, rawGroup := range groups {
+			group, ok := rawGroup.(string)
+			if !ok {
+				return imp, fmt.Errorf("%s must be an array of strings", GroupsArgument)
+			}
+			if group != "" {
+				imp.Groups = append(imp.Groups, group)
+			}
+		}
+	}
+
+	return imp, nil
+}
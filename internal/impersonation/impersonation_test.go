@@ -0,0 +1,100 @@
+package impersonation
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/kubernetes"
+)
+
+func request(args map[string]any) mcp.CallToolRequest {
+	var r mcp.CallToolRequest
+	r.Params.Arguments = args
+	return r
+}
+
+func TestAddParameters(t *testing.T) {
+	t.Parallel()
+
+	properties := map[string]any{"context": map[string]any{"type": "string"}}
+	tool := mcp.Tool{Name: "list_resources", InputSchema: mcp.ToolInputSchema{Type: "object", Properties: properties}}
+
+	got, ok := AddParameters(tool)
+	if !ok {
+		t.Fatal("expected a tool with a context argument to get the impersonation arguments")
+	}
+	for _, name := range []string{UserArgument, GroupsArgument} {
+		if _, ok := got.InputSchema.Properties[name]; !ok {
+			t.Errorf("expected argument %q in %v", name, got.InputSchema.Properties)
+		}
+	}
+	if len(properties) != 1 {
+		t.Errorf("expected the original properties to be unchanged, got %v", properties)
+	}
+
+	local := mcp.Tool{Name: "list_port_forwards", InputSchema: mcp.ToolInputSchema{Type: "object", Properties: map[string]any{}}}
+	if _, ok := AddParameters(local); ok {
+		t.Error("expected a tool without a context argument to be left unchanged")
+	}
+}
+
+func TestWrap(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		args    map[string]any
+		want    kubernetes.Impersonation
+		wantSet bool
+		wantErr bool
+	}{
+		{
+			name: "no impersonation",
+			args: map[string]any{"namespace": "shop"},
+		},
+		{
+			name:    "user and groups",
+			args:    map[string]any{"as": "viewer", "as_groups": []any{"readers", "auditors"}},
+			want:    kubernetes.Impersonation{User: "viewer", Groups: []string{"readers", "auditors"}},
+			wantSet: true,
+		},
+		{
+			name:    "groups without a user",
+			args:    map[string]any{"as_groups": []any{"readers"}},
+			wantErr: true,
+		},
+		{
+			name:    "groups of the wrong type",
+			args:    map[string]any{"as": "viewer", "as_groups": "readers"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			called := false
+			handler := Wrap(func(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				called = true
+				got, ok := kubernetes.ImpersonationFrom(ctx)
+				if ok != tt.wantSet || !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("expected impersonation %+v (set: %t), got %+v (set: %t)", tt.want, tt.wantSet, got, ok)
+				}
+				return mcp.NewToolResultText("{}"), nil
+			})
+
+			result, err := handler(context.Background(), request(tt.args))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.IsError != tt.wantErr || called == tt.wantErr {
+				t.Errorf("expected error %t, got result %+v (handler called: %t)", tt.wantErr, result, called)
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package kubernetes
+
+import (
+	"context"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ReviewAccess asks the API server whether the client's own credentials may
+// perform an action, using a SelfSubjectAccessReview. Reviews are evaluated
+// by the authorizer and never persisted, so they do not change cluster state.
+// The attributes are sent as is: an empty namespace asks about all namespaces.
+func (c *Client) ReviewAccess(ctx context.Context, attributes *authorizationv1.ResourceAttributes) (*authorizationv1.SubjectAccessReviewStatus, error) {
+	review := &authorizationv1.SelfSubjectAccessReview{
+		Spec: authorizationv1.SelfSubjectAccessReviewSpec{ResourceAttributes: attributes},
+	}
+
+	result, err := c.clientset.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx, review, metav1.CreateOptions{})
+	if err != nil {
+		return nil, err //nolint:wrapcheck // kubernetes API errors are self-descriptive
+	}
+
+	return &result.Status, nil
+}
+
+// ReviewRules asks the API server for the rules the client's own credentials
+// are granted in a namespace, using a SelfSubjectRulesReview. Cluster-wide
+// rules are included. Like access reviews, rules reviews are never persisted.
+func (c *Client) ReviewRules(ctx context.Context, namespace string) (*authorizationv1.SubjectRulesReviewStatus, error) {
+	review := &authorizationv1.SelfSubjectRulesReview{
+		Spec: authorizationv1.SelfSubjectRulesReviewSpec{Namespace: namespace},
+	}
+
+	result, err := c.clientset.AuthorizationV1().SelfSubjectRulesReviews().Create(ctx, review, metav1.CreateOptions{})
+	if err != nil {
+		return nil, err //nolint:wrapcheck // kubernetes API errors are self-descriptive
+	}
+
+	return &result.Status, nil
+}
@@ -0,0 +1,24 @@
+package kubernetes
+
+import (
+	"context"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ListValidatingWebhookConfigurations retrieves the cluster-scoped
+// ValidatingWebhookConfigurations using the typed clientset.
+//
+//nolint:gocritic // opts is from external package, can't change signature
+func (c *Client) ListValidatingWebhookConfigurations(ctx context.Context, opts metav1.ListOptions) (*admissionregistrationv1.ValidatingWebhookConfigurationList, error) {
+	return c.clientset.AdmissionregistrationV1().ValidatingWebhookConfigurations().List(ctx, opts) //nolint:wrapcheck // kubernetes API errors are self-descriptive
+}
+
+// ListMutatingWebhookConfigurations retrieves the cluster-scoped
+// MutatingWebhookConfigurations using the typed clientset.
+//
+//nolint:gocritic // opts is from external package, can't change signature
+func (c *Client) ListMutatingWebhookConfigurations(ctx context.Context, opts metav1.ListOptions) (*admissionregistrationv1.MutatingWebhookConfigurationList, error) {
+	return c.clientset.AdmissionregistrationV1().MutatingWebhookConfigurations().List(ctx, opts) //nolint:wrapcheck // kubernetes API errors are self-descriptive
+}
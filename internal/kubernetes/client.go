@@ -8,31 +8,38 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net/http"
 	"os"
 	"path/filepath"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/version"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/metadata"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/util/flowcontrol"
 	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
 	metricsClient "k8s.io/metrics/pkg/client/clientset/versioned"
 )
 
 // Client provides a unified interface for read-only Kubernetes operations.
-// It wraps multiple Kubernetes client types (clientset, dynamic, discovery, metrics)
-// to provide a single interface for all the operations needed by the MCP server.
+// It wraps multiple Kubernetes client types (clientset, dynamic, metadata, discovery,
+// metrics) to provide a single interface for all the operations needed by the MCP server.
 //
 // The client supports:
 //   - Resource listing and retrieval using dynamic client
+//   - Metadata-only listing for counting and indexing resources cheaply
 //   - Pod log access with filtering options
 //   - Container discovery within pods
 //   - API resource discovery for dynamic resource type resolution
@@ -41,11 +48,35 @@ import (
 type Client struct {
 	clientset       kubernetes.Interface
 	dynamicClient   dynamic.Interface
+	metadataClient  metadata.Interface
 	discoveryClient discovery.DiscoveryInterface
 	metricsClient   metricsClient.Interface
 	config          *rest.Config
 	namespace       string
 	originalConfig  *Config
+
+	// httpClient is shared by the client interfaces above. It is nil for
+	// clients built from interfaces.
+	httpClient *http.Client
+
+	// contextName is the kubeconfig context the client was switched to with
+	// WithContext, or empty for the context it was created with.
+	contextName string
+
+	// clusterUser identifies the kubeconfig cluster and user the client
+	// connects with, as returned by contextClusterUser. It is empty for
+	// clients that do not come from a kubeconfig context.
+	clusterUser string
+
+	// discovery caches the resources discovered through discoveryClient.
+	// It is nil for clients built from interfaces, which run discovery on
+	// every call.
+	discovery *discoveryCache
+
+	// contexts caches the clients WithContext and ForContext build, for
+	// other contexts and impersonated identities. It is nil for clients built
+	// from interfaces, which cannot switch contexts.
+	contexts *contextCache
 }
 
 // Config holds the configuration parameters for creating a Kubernetes client.
@@ -61,6 +92,86 @@ type Config struct {
 	// will use the current namespace from the kubeconfig or require explicit
 	// namespace specification.
 	Namespace string
+
+	// AllowedContexts restricts the kubeconfig contexts WithContext may
+	// switch to. When empty, every context is allowed. The kubeconfig's
+	// current context, which the client uses by default, is always allowed.
+	AllowedContexts []string
+
+	// Server is the URL of the API server to connect to directly, without a
+	// kubeconfig. It is authenticated with Token or TokenFile and verified
+	// with CertificateAuthority. Kubeconfig contexts are not available when
+	// it is set.
+	Server string
+
+	// Token is the bearer token sent to Server.
+	Token string
+
+	// TokenFile is a file holding the bearer token sent to Server. It is
+	// read again when it changes, so rotated tokens such as projected
+	// ServiceAccount tokens keep working.
+	TokenFile string
+
+	// CertificateAuthority is the path to the CA bundle that verifies
+	// Server's certificate. When empty, the system roots are used.
+	CertificateAuthority string
+
+	// QPS and Burst limit the rate of requests to the API server, as
+	// sustained queries per second and the burst allowed above it. Zero
+	// keeps the client library defaults of 5 and 10. A negative QPS disables
+	// client-side rate limiting.
+	QPS   float32
+	Burst int
+
+	// ExecPluginTimeout limits how long a kubeconfig credential plugin, such
+	// as aws, gke-gcloud-auth-plugin, or kubelogin, may run to produce
+	// credentials before it is stopped. Zero means no limit. See
+	// ExecPluginCommand.
+	ExecPluginTimeout time.Duration
+
+	// Impersonate makes every request act as another user and groups, so
+	// broad credentials can serve queries with a restricted identity's
+	// permissions. It applies to every context the client switches to.
+	Impersonate Impersonation
+
+	// MaxConcurrentRequests bounds the requests to the API server in flight
+	// at once, across every context the client switches to. Requests over
+	// the limit wait up to RequestQueueTimeout for one to finish, and are
+	// then refused with a 429 Too Many Requests error. Zero means no limit.
+	MaxConcurrentRequests int
+	RequestQueueTimeout   time.Duration
+
+	// ContextClientTTL is how long a client built for another context or an
+	// impersonated identity is kept after its last use. Idle clients are
+	// dropped once it passes, and their connections closed, so the next
+	// switch loads the kubeconfig and runs credential plugins again. Zero
+	// keeps them for the life of the process.
+	ContextClientTTL time.Duration
+
+	// DiscoveryCacheTTL is how long the API resources a cluster serves are
+	// reused for resolving resource types before discovery runs again. A
+	// resource type missing from them refreshes them sooner, so new custom
+	// resources are found. Zero runs discovery on every call.
+	DiscoveryCacheTTL time.Duration
+
+	// slots is built on first use and shared by every client made from this
+	// Config. It is guarded by slotsMu, which is not kept in Config so the
+	// struct can still be copied.
+	slots *requestSlots
+}
+
+var slotsMu sync.Mutex
+
+// requestSlots returns the request slots shared by the clients made from
+// cfg.
+func (cfg *Config) requestSlots() *requestSlots {
+	slotsMu.Lock()
+	defer slotsMu.Unlock()
+
+	if cfg.slots == nil {
+		cfg.slots = newRequestSlots(cfg.MaxConcurrentRequests, cfg.RequestQueueTimeout)
+	}
+	return cfg.slots
 }
 
 // NewClientWithContext creates a new Kubernetes client using the provided configuration
@@ -72,32 +183,124 @@ type Config struct {
 //
 // This function resolves the kubeconfig path and updates the original Config struct
 // with the resolved path, ensuring all components have access to the complete configuration.
+//
+// When cfg.Server is set, no kubeconfig is loaded: the client connects to that
+// server directly, and contextName must be empty.
 func NewClientWithContext(cfg *Config, contextName string) (*Client, error) {
-	// Resolve and update the kubeconfig path in the original Config struct
-	resolvedKubeconfig := resolveKubeconfigPath(cfg.Kubeconfig)
-	cfg.Kubeconfig = resolvedKubeconfig
+	var (
+		config      *rest.Config
+		clusterUser string
+	)
+	if cfg.Server != "" {
+		if contextName != "" {
+			return nil, errDirectContext
+		}
+
+		direct, err := buildDirectConfig(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build Kubernetes config: %w", err)
+		}
+		config = direct
+	} else {
+		if cfg.Token != "" || cfg.TokenFile != "" || cfg.CertificateAuthority != "" {
+			return nil, errors.New("a token, token file, or certificate authority requires a server to connect to")
+		}
+
+		// Resolve and update the kubeconfig path in the original Config struct
+		resolvedKubeconfig := resolveKubeconfigPath(cfg.Kubeconfig)
+		cfg.Kubeconfig = resolvedKubeconfig
+
+		fromKubeconfig, err := buildConfig(resolvedKubeconfig, contextName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build Kubernetes config: %w", err)
+		}
+		config = fromKubeconfig
+
+		limitExecPlugin(config, cfg.ExecPluginTimeout)
+
+		// In-cluster configs have no kubeconfig contexts to share with.
+		if resolvedKubeconfig != "" {
+			clusterUser, _ = contextClusterUser(resolvedKubeconfig, contextName)
+		}
+	}
+
+	if !cfg.Impersonate.IsZero() {
+		if err := cfg.Impersonate.Validate(); err != nil {
+			return nil, err
+		}
+		config.Impersonate = cfg.Impersonate.restConfig()
+	}
+
+	// The clientset, dynamic, metadata, discovery, and metrics clients would
+	// each get a rate limiter of their own, so they share one that keeps the
+	// configured limits for the context as a whole.
+	if cfg.QPS > 0 || (cfg.QPS == 0 && cfg.Burst > 0) {
+		qps, burst := cfg.QPS, cfg.Burst
+		if qps == 0 {
+			qps = rest.DefaultQPS
+		}
+		if burst == 0 {
+			burst = rest.DefaultBurst
+		}
+		config.QPS, config.Burst = qps, burst
+		config.RateLimiter = flowcontrol.NewTokenBucketRateLimiter(qps, burst)
+	} else if cfg.QPS < 0 {
+		config.QPS = cfg.QPS
+	}
+
+	if cfg.MaxConcurrentRequests > 0 {
+		config.Wrap(cfg.requestSlots().wrap)
+	}
 
-	config, err := buildConfig(resolvedKubeconfig, contextName)
+	client, err := newClientForConfig(config, cfg)
 	if err != nil {
-		return nil, fmt.Errorf("failed to build Kubernetes config: %w", err)
+		return nil, err
 	}
 
-	clientset, err := kubernetes.NewForConfig(config)
+	client.clusterUser = clusterUser
+	client.contexts = newContextCache(client, cfg.ContextClientTTL)
+	return client, nil
+}
+
+// discoveryTimeout limits discovery requests when the REST config sets no
+// timeout, as the discovery client does when it builds its own HTTP client.
+const discoveryTimeout = 32 * time.Second
+
+// newClientForConfig creates every client interface from a REST config. They
+// share one HTTP client, so a context keeps a single pool of TLS connections
+// and a single set of credentials, instead of one for each client interface.
+func newClientForConfig(config *rest.Config, cfg *Config) (*Client, error) {
+	httpClient, err := rest.HTTPClientFor(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP client: %w", err)
+	}
+
+	clientset, err := kubernetes.NewForConfigAndClient(config, httpClient)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Kubernetes clientset: %w", err)
 	}
 
-	dynamicClient, err := dynamic.NewForConfig(config)
+	dynamicClient, err := dynamic.NewForConfigAndClient(config, httpClient)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create dynamic client: %w", err)
 	}
 
-	discoveryClient, err := discovery.NewDiscoveryClientForConfig(config)
+	metadataClient, err := metadata.NewForConfigAndClient(config, httpClient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create metadata client: %w", err)
+	}
+
+	discoveryHTTPClient := httpClient
+	if config.Timeout == 0 {
+		discoveryHTTPClient = &http.Client{Transport: httpClient.Transport, Timeout: discoveryTimeout}
+	}
+
+	discoveryClient, err := discovery.NewDiscoveryClientForConfigAndClient(config, discoveryHTTPClient)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create discovery client: %w", err)
 	}
 
-	metricsClientset, err := metricsClient.NewForConfig(config)
+	metricsClientset, err := metricsClient.NewForConfigAndClient(config, httpClient)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create metrics client: %w", err)
 	}
@@ -105,14 +308,41 @@ func NewClientWithContext(cfg *Config, contextName string) (*Client, error) {
 	return &Client{
 		clientset:       clientset,
 		dynamicClient:   dynamicClient,
+		metadataClient:  metadataClient,
 		discoveryClient: discoveryClient,
 		metricsClient:   metricsClientset,
+		httpClient:      httpClient,
 		config:          config,
 		namespace:       cfg.Namespace,
 		originalConfig:  cfg,
+		discovery:       newDiscoveryCache(discoveryClient, cfg.DiscoveryCacheTTL),
 	}, nil
 }
 
+// NewClientFromInterfaces creates a Client from already constructed client
+// interfaces instead of a kubeconfig. It is meant for alternative backends such
+// as in-memory fake clientsets in tests. The resulting client has no REST config
+// and no kubeconfig, so port forwarding, context switching, and ListContexts are
+// unavailable.
+func NewClientFromInterfaces(
+	clientset kubernetes.Interface,
+	dynamicClient dynamic.Interface,
+	metadataClient metadata.Interface,
+	discoveryClient discovery.DiscoveryInterface,
+	metricsClientset metricsClient.Interface,
+	namespace string,
+) *Client {
+	return &Client{
+		clientset:       clientset,
+		dynamicClient:   dynamicClient,
+		metadataClient:  metadataClient,
+		discoveryClient: discoveryClient,
+		metricsClient:   metricsClientset,
+		namespace:       namespace,
+		originalConfig:  &Config{Namespace: namespace},
+	}
+}
+
 // resolveKubeconfigPath resolves the kubeconfig path using the same logic as buildConfig.
 // It returns the resolved path or an empty string if in-cluster config should be used.
 func resolveKubeconfigPath(kubeconfig string) string {
@@ -139,6 +369,30 @@ func newLoadingRules(kubeconfig string) *clientcmd.ClientConfigLoadingRules {
 	return rules
 }
 
+// contextClusterUser returns a key naming the kubeconfig cluster and user of
+// contextName, or of the current context when it is empty. Contexts with the
+// same key build the same REST config, since a context only adds a default
+// namespace to them, which the client does not use.
+func contextClusterUser(kubeconfig, contextName string) (string, error) {
+	rawConfig, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		newLoadingRules(kubeconfig),
+		&clientcmd.ConfigOverrides{},
+	).RawConfig()
+	if err != nil {
+		return "", fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+
+	if contextName == "" {
+		contextName = rawConfig.CurrentContext
+	}
+	kubeContext, ok := rawConfig.Contexts[contextName]
+	if !ok || kubeContext.Cluster == "" {
+		return "", fmt.Errorf("context %q not found in kubeconfig", contextName)
+	}
+
+	return kubeContext.Cluster + "\x00" + kubeContext.AuthInfo, nil
+}
+
 func buildConfig(kubeconfig, contextName string) (*rest.Config, error) {
 	resolvedKubeconfig := resolveKubeconfigPath(kubeconfig)
 
@@ -158,14 +412,121 @@ func buildConfig(kubeconfig, contextName string) (*rest.Config, error) {
 	return clientConfig.ClientConfig() //nolint:wrapcheck // kubernetes client-go errors are self-descriptive
 }
 
+// errDirectContext is returned when a context is requested from a client that
+// connects to a server directly instead of through a kubeconfig.
+var errDirectContext = errors.New("kubeconfig contexts are not available: the server connects to the API server directly, without a kubeconfig")
+
+// buildDirectConfig builds a REST config for the server, token, and
+// certificate authority of cfg, without a kubeconfig.
+func buildDirectConfig(cfg *Config) (*rest.Config, error) {
+	if cfg.Token != "" && cfg.TokenFile != "" {
+		return nil, errors.New("a token and a token file cannot be used together")
+	}
+
+	// Read the files upfront so a wrong path fails at startup instead of
+	// on the first request.
+	if cfg.TokenFile != "" {
+		if _, err := os.ReadFile(cfg.TokenFile); err != nil {
+			return nil, fmt.Errorf("failed to read token file: %w", err)
+		}
+	}
+	if cfg.CertificateAuthority != "" {
+		if _, err := os.ReadFile(cfg.CertificateAuthority); err != nil {
+			return nil, fmt.Errorf("failed to read certificate authority: %w", err)
+		}
+	}
+
+	return &rest.Config{
+		Host:            cfg.Server,
+		BearerToken:     cfg.Token,
+		BearerTokenFile: cfg.TokenFile,
+		TLSClientConfig: rest.TLSClientConfig{
+			CAFile: cfg.CertificateAuthority,
+		},
+	}, nil
+}
+
 // WithContext returns a new client configured to use the specified Kubernetes context.
 // If contextName is empty, it returns the current client unchanged.
 // This method allows for per-operation context switching without modifying the original client.
+//
+// Clients are cached per context and reused until they go unused for
+// Config.ContextClientTTL, so credential plugins and TLS handshakes do not
+// run again on every switch.
 func (c *Client) WithContext(contextName string) (*Client, error) {
 	if contextName == "" {
 		return c, nil
 	}
-	return NewClientWithContext(c.originalConfig, contextName)
+	if c.originalConfig != nil && c.originalConfig.Server != "" {
+		return nil, errDirectContext
+	}
+	if err := c.checkContextAllowed(contextName); err != nil {
+		return nil, err
+	}
+
+	if c.contexts == nil {
+		return NewClientWithContext(c.originalConfig, contextName)
+	}
+
+	return c.contexts.get(contextName, func() (*Client, error) {
+		// Contexts that differ from a cached one only in name or default
+		// namespace connect the same way, so they share its REST config,
+		// connections, and discovery cache.
+		if clusterUser, err := contextClusterUser(c.originalConfig.Kubeconfig, contextName); err == nil {
+			if shared := c.contexts.sharingLocked(clusterUser); shared != nil {
+				client := *shared
+				client.contextName = contextName
+				return &client, nil
+			}
+		}
+
+		client, err := NewClientWithContext(c.originalConfig, contextName)
+		if err != nil {
+			return nil, err
+		}
+
+		// Clients for other contexts share the cache, so switching from
+		// them reuses the same clients.
+		client.contextName = contextName
+		client.contexts = c.contexts
+		return client, nil
+	})
+}
+
+// checkContextAllowed returns an error when the configured AllowedContexts
+// do not include contextName. The current context is always allowed, since
+// it is what the client uses when no context is given.
+func (c *Client) checkContextAllowed(contextName string) error {
+	if c.originalConfig == nil || len(c.originalConfig.AllowedContexts) == 0 {
+		return nil
+	}
+
+	allowed := c.originalConfig.AllowedContexts
+	for _, name := range allowed {
+		if name == contextName {
+			return nil
+		}
+	}
+
+	if contexts, err := c.ListContexts(); err == nil {
+		for _, kubeContext := range contexts {
+			if kubeContext.Current && kubeContext.Name == contextName {
+				return nil
+			}
+		}
+	}
+
+	return fmt.Errorf("context %q is not allowed by the server configuration; allowed contexts are: %s", contextName, strings.Join(allowed, ", "))
+}
+
+// ServerURL returns the URL of the API server the client connects to, as set
+// in the kubeconfig cluster entry or the in-cluster configuration. It is empty
+// for clients built with NewClientFromInterfaces.
+func (c *Client) ServerURL() string {
+	if c.config == nil {
+		return ""
+	}
+	return c.config.Host
 }
 
 // RESTConfig returns the underlying rest.Config for creating SPDY transports.
@@ -184,12 +545,31 @@ func (c *Client) Clientset() kubernetes.Interface {
 
 // ForContext returns a new client configured for the specified Kubernetes context.
 // If contextName is empty, it returns the current client unchanged.
-// This is a convenience method for handlers that need to conditionally switch contexts.
-func (c *Client) ForContext(contextName string) (*Client, error) {
-	if contextName == "" {
-		return c, nil
+// This is a convenience method for handlers that need to conditionally switch contexts,
+// and is how *Client satisfies ClusterReader.
+//
+// When ctx carries an impersonation set with WithImpersonation, the returned
+// client acts as that user and groups instead. Impersonating clients are
+// cached per context and identity like the clients for other contexts.
+//
+//nolint:ireturn // returning the interface lets handlers stay backend-agnostic
+func (c *Client) ForContext(ctx context.Context, contextName string) (ClusterReader, error) {
+	client, err := c.WithContext(contextName)
+	if err != nil {
+		return nil, err
+	}
+
+	imp, ok := ImpersonationFrom(ctx)
+	if !ok {
+		return client, nil
 	}
-	return c.WithContext(contextName)
+	if client.contexts == nil {
+		return client.impersonating(imp)
+	}
+
+	return client.contexts.get(impersonationCacheKey(client.contextName, imp), func() (*Client, error) {
+		return client.impersonating(imp)
+	})
 }
 
 // KubeContext represents a Kubernetes context from the kubeconfig file.
@@ -215,7 +595,13 @@ type KubeContext struct {
 // ListContexts reads and parses the kubeconfig file to extract context information.
 // It requires that the kubeconfig path has already been resolved during client creation.
 // If no kubeconfig is available, it fails rather than attempting resolution.
+// When AllowedContexts is set, only the allowed contexts and the current
+// context are listed.
 func (c *Client) ListContexts() ([]KubeContext, error) {
+	if c.originalConfig.Server != "" {
+		return nil, errDirectContext
+	}
+
 	kubeconfig := c.originalConfig.Kubeconfig
 	if kubeconfig == "" {
 		return nil, errors.New("no kubeconfig available: provide a kubeconfig file path for the MCP server")
@@ -234,8 +620,19 @@ func (c *Client) ListContexts() ([]KubeContext, error) {
 		return nil, fmt.Errorf("failed to load kubeconfig: %w", err)
 	}
 
+	// Contexts outside the allowlist cannot be selected, so they are not
+	// listed either.
+	allowed := make(map[string]bool, len(c.originalConfig.AllowedContexts))
+	for _, name := range c.originalConfig.AllowedContexts {
+		allowed[name] = true
+	}
+
 	contexts := make([]KubeContext, 0, len(rawConfig.Contexts))
 	for name, context := range rawConfig.Contexts {
+		if len(allowed) > 0 && !allowed[name] && name != rawConfig.CurrentContext {
+			continue
+		}
+
 		kubeContext := KubeContext{
 			Name:      name,
 			Cluster:   context.Cluster,
@@ -260,19 +657,36 @@ func (c *Client) ListContexts() ([]KubeContext, error) {
 	return contexts, nil
 }
 
+// AllNamespaces is passed as the namespace to ListResources and
+// ListResourceMetadata to list across every namespace, even when the client
+// has a default namespace that an empty namespace would fall back to.
+const AllNamespaces = "*"
+
+// listNamespace returns the namespace a list request is made in: the
+// client's default namespace for an empty one, and every namespace for
+// AllNamespaces.
+func (c *Client) listNamespace(namespace string) string {
+	switch namespace {
+	case AllNamespaces:
+		return metav1.NamespaceAll
+	case "":
+		return c.namespace
+	}
+	return namespace
+}
+
 // ListResources retrieves a list of Kubernetes resources of the specified type.
 // It supports both namespaced and cluster-scoped resources, with optional filtering
 // through the provided ListOptions (label selectors, field selectors, pagination).
 //
 // The gvr parameter specifies the GroupVersionResource to list.
-// The namespace parameter is used for namespaced resources; leave empty for cluster-scoped resources.
+// The namespace parameter is used for namespaced resources; leave empty for cluster-scoped resources,
+// or pass AllNamespaces to list across every namespace.
 // The opts parameter provides filtering and pagination options.
 //
 //nolint:gocritic // opts is from external package, can't change signature
 func (c *Client) ListResources(ctx context.Context, gvr schema.GroupVersionResource, namespace string, opts metav1.ListOptions) (*unstructured.UnstructuredList, error) {
-	if namespace == "" && c.namespace != "" {
-		namespace = c.namespace
-	}
+	namespace = c.listNamespace(namespace)
 
 	var resourceInterface dynamic.ResourceInterface
 	if namespace != "" {
@@ -284,6 +698,23 @@ func (c *Client) ListResources(ctx context.Context, gvr schema.GroupVersionResou
 	return resourceInterface.List(ctx, opts) //nolint:wrapcheck // kubernetes API errors are self-descriptive
 }
 
+// ListResourceMetadata lists resources of any type through the metadata client,
+// which returns only each object's type and object metadata. It is much cheaper
+// than ListResources when the spec and status are not needed, such as when
+// counting or indexing large numbers of objects.
+//
+// The namespace parameter follows the same defaulting as ListResources.
+//
+//nolint:gocritic // opts is from external package, can't change signature
+func (c *Client) ListResourceMetadata(ctx context.Context, gvr schema.GroupVersionResource, namespace string, opts metav1.ListOptions) (*metav1.PartialObjectMetadataList, error) {
+	namespace = c.listNamespace(namespace)
+
+	if namespace != "" {
+		return c.metadataClient.Resource(gvr).Namespace(namespace).List(ctx, opts) //nolint:wrapcheck // kubernetes API errors are self-descriptive
+	}
+	return c.metadataClient.Resource(gvr).List(ctx, opts) //nolint:wrapcheck // kubernetes API errors are self-descriptive
+}
+
 // GetResource retrieves a specific Kubernetes resource by name and type.
 // It works with both namespaced and cluster-scoped resources.
 //
@@ -291,6 +722,14 @@ func (c *Client) ListResources(ctx context.Context, gvr schema.GroupVersionResou
 // The namespace parameter is required for namespaced resources; leave empty for cluster-scoped resources.
 // The name parameter specifies which resource instance to retrieve.
 func (c *Client) GetResource(ctx context.Context, gvr schema.GroupVersionResource, namespace, name string) (*unstructured.Unstructured, error) {
+	return c.GetSubresource(ctx, gvr, namespace, name, "")
+}
+
+// GetSubresource retrieves a subresource of a specific Kubernetes resource,
+// such as the "status" or "scale" of a Deployment, or the resource itself
+// when subresource is empty. The namespace parameter follows the same
+// defaulting as GetResource.
+func (c *Client) GetSubresource(ctx context.Context, gvr schema.GroupVersionResource, namespace, name, subresource string) (*unstructured.Unstructured, error) {
 	if namespace == "" && c.namespace != "" {
 		namespace = c.namespace
 	}
@@ -302,14 +741,82 @@ func (c *Client) GetResource(ctx context.Context, gvr schema.GroupVersionResourc
 		resourceInterface = c.dynamicClient.Resource(gvr)
 	}
 
-	return resourceInterface.Get(ctx, name, metav1.GetOptions{}) //nolint:wrapcheck // kubernetes API errors are self-descriptive
+	var subresources []string
+	if subresource != "" {
+		subresources = []string{subresource}
+	}
+
+	return resourceInterface.Get(ctx, name, metav1.GetOptions{}, subresources...) //nolint:wrapcheck // kubernetes API errors are self-descriptive
 }
 
 // DiscoverResources retrieves the list of available API resources from the cluster.
 // This is used to understand what resource types are available and their capabilities
 // (namespaced vs cluster-scoped, supported verbs, etc.).
+//
+// Results are cached for Config.DiscoveryCacheTTL and shared by every caller,
+// so they must not be modified.
 func (c *Client) DiscoverResources(_ context.Context) ([]*metav1.APIResourceList, error) {
-	return c.discoveryClient.ServerPreferredResources() //nolint:wrapcheck // kubernetes API errors are self-descriptive
+	return c.preferredResources()
+}
+
+// InvalidateDiscovery drops the cached API resources, so the next call that
+// needs them runs discovery again. Use it after CRDs are installed or
+// removed.
+func (c *Client) InvalidateDiscovery() {
+	if c.discovery != nil {
+		c.discovery.invalidate()
+	}
+}
+
+// preferredResources returns the preferred API resources of the cluster,
+// from the discovery cache when the client has one.
+func (c *Client) preferredResources() ([]*metav1.APIResourceList, error) {
+	if c.discovery == nil {
+		return c.discoveryClient.ServerPreferredResources() //nolint:wrapcheck // kubernetes API errors are self-descriptive
+	}
+	return c.discovery.preferredResources()
+}
+
+// DiscoverAllResources returns the API resources served by the cluster in every
+// served version, not only the preferred one. Deprecated API versions that are
+// still served only show up here. Partial results are returned together with
+// the error when some API groups fail discovery.
+func (c *Client) DiscoverAllResources(_ context.Context) ([]*metav1.APIResourceList, error) {
+	_, lists, err := c.discoveryClient.ServerGroupsAndResources()
+	return lists, err //nolint:wrapcheck // kubernetes API errors are self-descriptive
+}
+
+// ServerGroups returns the API groups served by the cluster, with every
+// served version and the version the server prefers for each group.
+func (c *Client) ServerGroups(_ context.Context) (*metav1.APIGroupList, error) {
+	return c.discoveryClient.ServerGroups() //nolint:wrapcheck // kubernetes API errors are self-descriptive
+}
+
+// ServerVersion returns the Kubernetes version reported by the API server.
+func (c *Client) ServerVersion() (*version.Info, error) {
+	return c.discoveryClient.ServerVersion() //nolint:wrapcheck // kubernetes API errors are self-descriptive
+}
+
+// GetOpenAPISchema returns the OpenAPI v3 document the server publishes for a
+// group version, as JSON. Built-in types and CRDs with structural schemas are
+// both described there, which makes it the source for field documentation.
+func (c *Client) GetOpenAPISchema(gv schema.GroupVersion) ([]byte, error) {
+	paths, err := c.discoveryClient.OpenAPIV3().Paths()
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover OpenAPI v3 schemas: %w", err)
+	}
+
+	path := "apis/" + gv.Group + "/" + gv.Version
+	if gv.Group == "" {
+		path = "api/" + gv.Version
+	}
+
+	doc, found := paths[path]
+	if !found {
+		return nil, fmt.Errorf("the server does not publish an OpenAPI v3 schema for %q", gv.String())
+	}
+
+	return doc.Schema(runtime.ContentTypeJSON) //nolint:wrapcheck // kubernetes API errors are self-descriptive
 }
 
 // ResolveResourceType converts a user-friendly resource type name to a GroupVersionResource.
@@ -320,12 +827,30 @@ func (c *Client) DiscoverResources(_ context.Context) ([]*metav1.APIResourceList
 // The apiVersion parameter optionally constrains the search to a specific API version.
 //
 // Returns a detailed error message with available resource types if the lookup fails.
+//
+// Discovery results are cached; a resource type missing from cached results
+// refreshes them once, so custom resources installed since are found.
 func (c *Client) ResolveResourceType(resourceType, apiVersion string) (schema.GroupVersionResource, error) {
-	lists, err := c.discoveryClient.ServerPreferredResources()
+	lists, err := c.preferredResources()
 	if err != nil && len(lists) == 0 {
 		return schema.GroupVersionResource{}, fmt.Errorf("failed to discover resources: %w", err)
 	}
 
+	gvr, err := resolveResourceType(lists, resourceType, apiVersion)
+	if err == nil || c.discovery == nil || !c.discovery.refreshOnMiss() {
+		return gvr, err
+	}
+
+	lists, discoveryErr := c.preferredResources()
+	if discoveryErr != nil && len(lists) == 0 {
+		return schema.GroupVersionResource{}, fmt.Errorf("failed to discover resources: %w", discoveryErr)
+	}
+	return resolveResourceType(lists, resourceType, apiVersion)
+}
+
+// resolveResourceType looks up resourceType in discovered API resources.
+func resolveResourceType(lists []*metav1.APIResourceList, resourceType, apiVersion string) (schema.GroupVersionResource, error) {
+
 	// Build a comprehensive mapping of all possible names to their resource info
 	type resourceInfo struct {
 		gvr        schema.GroupVersionResource
@@ -529,17 +1054,9 @@ func (c *Client) GetPodLogsWithOptions(ctx context.Context, namespace, podName s
 // The namespace parameter specifies the pod's namespace.
 // The podName parameter specifies which pod to inspect.
 func (c *Client) GetPodContainers(ctx context.Context, namespace, podName string) ([]string, error) {
-	if namespace == "" && c.namespace != "" {
-		namespace = c.namespace
-	}
-
-	if namespace == "" {
-		return nil, errors.New("namespace is required")
-	}
-
-	pod, err := c.clientset.CoreV1().Pods(namespace).Get(ctx, podName, metav1.GetOptions{})
+	pod, err := c.GetPod(ctx, namespace, podName)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get pod %q: %w", podName, err)
+		return nil, err
 	}
 
 	containers := make([]string, 0, len(pod.Spec.Containers))
@@ -623,14 +1140,14 @@ func (c *Client) GetPodMetricsByName(ctx context.Context, namespace, podName str
 // Returns a detailed error with troubleshooting guidance if any check fails.
 func (c *Client) TestConnectivity(ctx context.Context) error {
 	// Test 1: Check if we can reach the API server by getting cluster version
-	version, err := c.discoveryClient.ServerVersion()
+	serverVersion, err := c.discoveryClient.ServerVersion()
 	if err != nil {
 		return fmt.Errorf("failed to get server version: %w", err)
 	}
 
 	// Test 2: Try to discover API resources to ensure discovery works
 	// Note: This can have warnings (like deprecated APIs) but should not fail connectivity
-	resources, err := c.discoveryClient.ServerPreferredResources()
+	resources, err := c.preferredResources()
 	if err != nil {
 		// Check if we got no results: this is likely a failure
 		if len(resources) == 0 {
@@ -650,7 +1167,7 @@ func (c *Client) TestConnectivity(ctx context.Context) error {
 
 		fmt.Fprintf(os.Stderr,
 			"✓ Successfully connected to Kubernetes cluster (version: %s, namespace: %s)\n",
-			version.String(), c.namespace,
+			serverVersion.String(), c.namespace,
 		)
 	} else {
 		_, err = c.clientset.CoreV1().Namespaces().List(ctx, metav1.ListOptions{Limit: 1})
@@ -660,7 +1177,7 @@ func (c *Client) TestConnectivity(ctx context.Context) error {
 
 		fmt.Fprintf(os.Stderr,
 			"✓ Successfully connected to Kubernetes cluster (version: %s)\n",
-			version.String(),
+			serverVersion.String(),
 		)
 	}
 	return nil
@@ -2,6 +2,9 @@ package kubernetes
 
 import (
 	"context"
+	"errors"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -177,3 +180,261 @@ func TestTestConnectivity_WithNamespace_ThenGetPod(t *testing.T) {
 		t.Fatalf("expected namespace 'my-ns', got %q", result.GetNamespace())
 	}
 }
+
+func TestAllowedContexts(t *testing.T) {
+	kubeconfig := filepath.Join(t.TempDir(), "config")
+	contents := `apiVersion: v1
+kind: Config
+current-context: dev
+clusters:
+- name: shared
+  cluster:
+    server: https://127.0.0.1:6443
+users:
+- name: admin
+  user:
+    token: abc
+contexts:
+- name: dev
+  context: {cluster: shared, user: admin}
+- name: staging
+  context: {cluster: shared, user: admin}
+- name: production
+  context: {cluster: shared, user: admin}
+`
+	if err := os.WriteFile(kubeconfig, []byte(contents), 0o600); err != nil {
+		t.Fatalf("failed to write kubeconfig: %v", err)
+	}
+
+	client := &Client{originalConfig: &Config{Kubeconfig: kubeconfig, AllowedContexts: []string{"staging"}}}
+
+	contexts, err := client.ListContexts()
+	if err != nil {
+		t.Fatalf("failed to list contexts: %v", err)
+	}
+	names := make([]string, 0, len(contexts))
+	for _, kubeContext := range contexts {
+		names = append(names, kubeContext.Name)
+	}
+	if strings.Join(names, ",") != "dev,staging" {
+		t.Fatalf("expected the current and allowed contexts, got %v", names)
+	}
+
+	for _, name := range []string{"dev", "staging"} {
+		if _, err := client.WithContext(name); err != nil {
+			t.Errorf("expected context %q to be allowed, got: %v", name, err)
+		}
+	}
+
+	_, err = client.WithContext("production")
+	if err == nil || !strings.Contains(err.Error(), `context "production" is not allowed`) {
+		t.Fatalf("expected production to be rejected, got: %v", err)
+	}
+}
+
+func TestWithContextCachesClients(t *testing.T) {
+	kubeconfig := filepath.Join(t.TempDir(), "config")
+	contents := `apiVersion: v1
+kind: Config
+current-context: dev
+clusters:
+- name: shared
+  cluster:
+    server: https://127.0.0.1:6443
+users:
+- name: admin
+  user:
+    token: abc
+- name: viewer
+  user:
+    token: def
+contexts:
+- name: dev
+  context: {cluster: shared, user: admin}
+- name: staging
+  context: {cluster: shared, user: admin, namespace: staging}
+- name: readonly
+  context: {cluster: shared, user: viewer}
+`
+	if err := os.WriteFile(kubeconfig, []byte(contents), 0o600); err != nil {
+		t.Fatalf("failed to write kubeconfig: %v", err)
+	}
+
+	client, err := NewClientWithContext(&Config{Kubeconfig: kubeconfig}, "")
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	first, err := client.WithContext("staging")
+	if err != nil {
+		t.Fatalf("failed to switch context: %v", err)
+	}
+	second, err := client.WithContext("staging")
+	if err != nil {
+		t.Fatalf("failed to switch context: %v", err)
+	}
+	if first != second {
+		t.Error("expected switching to the same context to reuse its client")
+	}
+
+	// Switching from a derived client shares the same cache
+	fromStaging, err := first.WithContext("dev")
+	if err != nil {
+		t.Fatalf("failed to switch context: %v", err)
+	}
+	fromRoot, err := client.WithContext("dev")
+	if err != nil {
+		t.Fatalf("failed to switch context: %v", err)
+	}
+	if fromStaging != fromRoot {
+		t.Error("expected derived clients to share the context cache")
+	}
+
+	// Contexts with the same cluster and user share their connections,
+	// while other users get their own
+	if first.RESTConfig() != client.RESTConfig() || first.httpClient != client.httpClient {
+		t.Error("expected a context with the same cluster and user to share the REST config and HTTP client")
+	}
+	readonly, err := client.WithContext("readonly")
+	if err != nil {
+		t.Fatalf("failed to switch context: %v", err)
+	}
+	if readonly.httpClient == client.httpClient || readonly.RESTConfig().BearerToken != "def" {
+		t.Error("expected a context with another user to get its own HTTP client")
+	}
+
+	if _, err := client.WithContext("missing"); err == nil {
+		t.Error("expected an unknown context to fail")
+	}
+}
+
+func TestImpersonation(t *testing.T) {
+	kubeconfig := filepath.Join(t.TempDir(), "config")
+	contents := `apiVersion: v1
+kind: Config
+current-context: dev
+clusters:
+- name: shared
+  cluster:
+    server: https://127.0.0.1:6443
+users:
+- name: admin
+  user:
+    token: abc
+contexts:
+- name: dev
+  context: {cluster: shared, user: admin}
+- name: staging
+  context: {cluster: shared, user: admin}
+`
+	if err := os.WriteFile(kubeconfig, []byte(contents), 0o600); err != nil {
+		t.Fatalf("failed to write kubeconfig: %v", err)
+	}
+
+	client, err := NewClientWithContext(&Config{
+		Kubeconfig:  kubeconfig,
+		Impersonate: Impersonation{User: "viewer", Groups: []string{"readers"}},
+	}, "")
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	if got := client.RESTConfig().Impersonate; got.UserName != "viewer" || strings.Join(got.Groups, ",") != "readers" {
+		t.Fatalf("expected the configured impersonation, got %+v", got)
+	}
+
+	// The configured impersonation carries over to other contexts
+	staging, err := client.ForContext(context.Background(), "staging")
+	if err != nil {
+		t.Fatalf("failed to switch context: %v", err)
+	}
+	if got := staging.(*Client).RESTConfig().Impersonate; got.UserName != "viewer" {
+		t.Errorf("expected the configured impersonation on another context, got %+v", got)
+	}
+
+	// A per-request impersonation replaces it, without changing the client
+	ctx := WithImpersonation(context.Background(), Impersonation{User: "auditor"})
+	reader, err := client.ForContext(ctx, "")
+	if err != nil {
+		t.Fatalf("failed to impersonate: %v", err)
+	}
+	if got := reader.(*Client).RESTConfig().Impersonate; got.UserName != "auditor" || len(got.Groups) != 0 {
+		t.Errorf("expected the per-request impersonation, got %+v", got)
+	}
+	if got := client.RESTConfig().Impersonate; got.UserName != "viewer" {
+		t.Errorf("expected the original client to be unchanged, got %+v", got)
+	}
+
+	// Impersonating clients are reused per context and identity
+	again, err := client.ForContext(ctx, "")
+	if err != nil {
+		t.Fatalf("failed to impersonate: %v", err)
+	}
+	if again != reader {
+		t.Error("expected the same impersonation to reuse its client")
+	}
+	onStaging, err := client.ForContext(ctx, "staging")
+	if err != nil {
+		t.Fatalf("failed to impersonate: %v", err)
+	}
+	if onStaging == reader {
+		t.Error("expected impersonating on another context to use another client")
+	}
+	if got := onStaging.(*Client).RESTConfig().Host; got != "https://127.0.0.1:6443" {
+		t.Errorf("expected the staging cluster, got %q", got)
+	}
+
+	ctx = WithImpersonation(context.Background(), Impersonation{Groups: []string{"readers"}})
+	if _, err := client.ForContext(ctx, ""); err == nil {
+		t.Error("expected impersonating groups without a user to fail")
+	}
+
+	// Clients without a REST config cannot impersonate, and must not
+	// silently answer with their own identity
+	fake := NewClientFromInterfaces(nil, nil, nil, nil, nil, "")
+	if _, err := fake.ForContext(WithImpersonation(context.Background(), Impersonation{User: "auditor"}), ""); err == nil {
+		t.Error("expected impersonation to fail without a REST config")
+	}
+}
+
+func TestDirectServerConfig(t *testing.T) {
+	dir := t.TempDir()
+	tokenFile := filepath.Join(dir, "token")
+	if err := os.WriteFile(tokenFile, []byte("abc"), 0o600); err != nil {
+		t.Fatalf("failed to write token file: %v", err)
+	}
+
+	client, err := NewClientWithContext(&Config{Server: "https://10.0.0.1:6443", TokenFile: tokenFile, QPS: 50, Burst: 100}, "")
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	if config := client.RESTConfig(); config.Host != "https://10.0.0.1:6443" || config.BearerTokenFile != tokenFile {
+		t.Errorf("unexpected REST config %+v", config)
+	}
+	if config := client.RESTConfig(); config.QPS != 50 || config.Burst != 100 || config.RateLimiter == nil || config.RateLimiter.QPS() != 50 {
+		t.Errorf("expected a shared rate limiter with the configured limits, got QPS %v and burst %d", config.QPS, config.Burst)
+	}
+
+	if _, err := client.WithContext("staging"); !errors.Is(err, errDirectContext) {
+		t.Errorf("expected context switching to fail, got: %v", err)
+	}
+	if _, err := client.ListContexts(); !errors.Is(err, errDirectContext) {
+		t.Errorf("expected listing contexts to fail, got: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		cfg  Config
+	}{
+		{name: "token without server", cfg: Config{Token: "abc"}},
+		{name: "token and token file", cfg: Config{Server: "https://10.0.0.1:6443", Token: "abc", TokenFile: tokenFile}},
+		{name: "missing certificate authority", cfg: Config{Server: "https://10.0.0.1:6443", Token: "abc", CertificateAuthority: filepath.Join(dir, "missing.crt")}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewClientWithContext(&tt.cfg, ""); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package kubernetes
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// requestSlots bounds how many requests to the API server are in flight at
+// once, across every context and impersonated identity of a client. Large
+// list responses are held in memory while they are decoded, so the bound
+// protects the server's memory as much as the API server.
+type requestSlots struct {
+	limit int
+	slots chan struct{}
+
+	// wait is how long a request waits for a slot before it is refused.
+	// Zero refuses requests as soon as every slot is taken.
+	wait time.Duration
+}
+
+func newRequestSlots(limit int, wait time.Duration) *requestSlots {
+	return &requestSlots{limit: limit, slots: make(chan struct{}, limit), wait: wait}
+}
+
+// acquire takes a slot, waiting up to s.wait. It returns false when no slot
+// freed up in time, and the context's error when it ends first.
+func (s *requestSlots) acquire(ctx context.Context) (bool, error) {
+	select {
+	case s.slots <- struct{}{}:
+		return true, nil
+	default:
+	}
+
+	if s.wait <= 0 {
+		return false, nil
+	}
+
+	timer := time.NewTimer(s.wait)
+	defer timer.Stop()
+
+	select {
+	case s.slots <- struct{}{}:
+		return true, nil
+	case <-timer.C:
+		return false, nil
+	case <-ctx.Done():
+		return false, ctx.Err() //nolint:wrapcheck // the caller's cancellation is returned as is
+	}
+}
+
+func (s *requestSlots) release() {
+	<-s.slots
+}
+
+// wrap is a rest.Config WrapTransport function that makes every request
+// through rt hold a slot.
+func (s *requestSlots) wrap(rt http.RoundTripper) http.RoundTripper {
+	return &slotRoundTripper{slots: s, next: rt}
+}
+
+// slotRoundTripper holds a slot for each request until its response body is
+// read or closed, since streamed bodies such as logs are still in flight
+// after the response headers arrive.
+type slotRoundTripper struct {
+	slots *requestSlots
+	next  http.RoundTripper
+}
+
+func (rt *slotRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	ok, err := rt.slots.acquire(req.Context())
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return rt.refused(req), nil
+	}
+
+	resp, err := rt.next.RoundTrip(req)
+
+	// Upgraded connections, such as port forwards, are used directly
+	// rather than read as a body, so their slot is released right away.
+	if err != nil || resp.Body == nil || resp.StatusCode == http.StatusSwitchingProtocols {
+		rt.slots.release()
+		return resp, err //nolint:wrapcheck // errors of the wrapped transport are returned as is
+	}
+
+	resp.Body = &slotBody{ReadCloser: resp.Body, release: sync.OnceFunc(rt.slots.release)}
+	return resp, nil
+}
+
+// WrappedRoundTripper lets client-go find the transport underneath, as it
+// does for its own wrappers.
+func (rt *slotRoundTripper) WrappedRoundTripper() http.RoundTripper {
+	return rt.next
+}
+
+// refused builds the response to a request that found no free slot: a 429
+// Too Many Requests Status, which client-go turns into an API error carrying
+// the message. It has no Retry-After header, so client-go does not retry it.
+func (rt *slotRoundTripper) refused(req *http.Request) *http.Response {
+	message := fmt.Sprintf("the server's limit of %d concurrent Kubernetes API requests was reached", rt.slots.limit)
+	if rt.slots.wait > 0 {
+		message += fmt.Sprintf(" and no request finished within %s", rt.slots.wait)
+	}
+	message += ". Retry shortly, or make fewer tool calls at once"
+
+	body, _ := json.Marshal(metav1.Status{ //nolint:errcheck // a Status always encodes
+		TypeMeta: metav1.TypeMeta{Kind: "Status", APIVersion: "v1"},
+		Status:   metav1.StatusFailure,
+		Message:  message,
+		Reason:   metav1.StatusReasonTooManyRequests,
+		Code:     http.StatusTooManyRequests,
+	})
+
+	return &http.Response{
+		Status:        strconv.Itoa(http.StatusTooManyRequests) + " " + http.StatusText(http.StatusTooManyRequests),
+		StatusCode:    http.StatusTooManyRequests,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+// slotBody releases its request's slot once it is read to the end, fails, or
+// is closed.
+type slotBody struct {
+	io.ReadCloser
+	release func()
+}
+
+func (b *slotBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if err != nil {
+		b.release()
+	}
+	return n, err //nolint:wrapcheck // body read errors are returned as is
+}
+
+func (b *slotBody) Close() error {
+	b.release()
+	return b.ReadCloser.Close() //nolint:wrapcheck // body close errors are returned as is
+}
@@ -0,0 +1,131 @@
+package kubernetes
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+// blockingAPIServer answers namespace lists once release is closed, and
+// reports each request it starts on started.
+func blockingAPIServer(t *testing.T) (server *httptest.Server, started chan struct{}, release chan struct{}) {
+	t.Helper()
+
+	started = make(chan struct{}, 10)
+	release = make(chan struct{})
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		started <- struct{}{}
+		<-release
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"kind":"NamespaceList","apiVersion":"v1","metadata":{},"items":[]}`))
+	}))
+	t.Cleanup(server.Close)
+
+	return server, started, release
+}
+
+func slotClient(t *testing.T, host string, slots *requestSlots) kubernetes.Interface {
+	t.Helper()
+
+	config := &rest.Config{Host: host}
+	config.Wrap(slots.wrap)
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return clientset
+}
+
+func TestRequestSlotsRefuseExcessRequests(t *testing.T) {
+	t.Parallel()
+
+	server, started, release := blockingAPIServer(t)
+	clientset := slotClient(t, server.URL, newRequestSlots(1, 0))
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := clientset.CoreV1().Namespaces().List(context.Background(), metav1.ListOptions{})
+		done <- err
+	}()
+	<-started
+
+	_, err := clientset.CoreV1().Namespaces().List(context.Background(), metav1.ListOptions{})
+	if !apierrors.IsTooManyRequests(err) {
+		t.Fatalf("expected a Too Many Requests error while the slot is taken, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "limit of 1 concurrent Kubernetes API requests") {
+		t.Errorf("expected the error to explain the limit, got %q", err)
+	}
+
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatalf("expected the first request to succeed, got %v", err)
+	}
+
+	// The slot is free again once the first response was read.
+	if _, err := clientset.CoreV1().Namespaces().List(context.Background(), metav1.ListOptions{}); err != nil {
+		t.Fatalf("expected a request after the slot freed up to succeed, got %v", err)
+	}
+}
+
+func TestRequestSlotsQueueExcessRequests(t *testing.T) {
+	t.Parallel()
+
+	server, started, release := blockingAPIServer(t)
+	clientset := slotClient(t, server.URL, newRequestSlots(1, 10*time.Second))
+
+	done := make(chan error, 2)
+	for range 2 {
+		go func() {
+			_, err := clientset.CoreV1().Namespaces().List(context.Background(), metav1.ListOptions{})
+			done <- err
+		}()
+	}
+	<-started
+
+	select {
+	case <-started:
+		t.Fatal("expected the second request to wait for the slot")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	close(release)
+	for range 2 {
+		if err := <-done; err != nil {
+			t.Fatalf("expected queued requests to succeed, got %v", err)
+		}
+	}
+}
+
+func TestRequestSlotsStopWaitingWithContext(t *testing.T) {
+	t.Parallel()
+
+	slots := newRequestSlots(1, time.Minute)
+	if ok, err := slots.acquire(context.Background()); !ok || err != nil {
+		t.Fatalf("expected the free slot to be taken, got %v, %v", ok, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if ok, err := slots.acquire(ctx); ok || err == nil {
+		t.Fatalf("expected waiting to stop with the context, got %v, %v", ok, err)
+	}
+}
+
+func TestConfigSharesRequestSlots(t *testing.T) {
+	t.Parallel()
+
+	cfg := &Config{MaxConcurrentRequests: 4}
+	if cfg.requestSlots() != cfg.requestSlots() {
+		t.Error("expected every client made from a Config to share its request slots")
+	}
+}
@@ -0,0 +1,154 @@
+package kubernetes
+
+import (
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxCachedClients bounds the clients a contextCache keeps. Impersonation
+// parameters can name any identity, so without a bound a long-running server
+// would keep a client for every identity it was ever asked to act as. The
+// least recently used client is dropped to make room.
+const maxCachedClients = 64
+
+// contextCache holds the clients WithContext and ForContext build, keyed by
+// context name and impersonated identity. It is shared by a client and every
+// client derived from it, so switching to a context reuses its connections
+// and credentials instead of loading the kubeconfig and running credential
+// plugins again on every tool call.
+type contextCache struct {
+	// base is the client the cache was created for. It is not cached
+	// itself, but contexts can share its connections.
+	base *Client
+	ttl  time.Duration
+
+	// now returns the current time. It defaults to time.Now and is replaced
+	// in tests.
+	now func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]*cachedClient
+}
+
+type cachedClient struct {
+	client   *Client
+	lastUsed time.Time
+}
+
+// newContextCache creates a cache for clients derived from base that drops
+// them once unused for ttl. A zero ttl keeps them until the cache is full.
+func newContextCache(base *Client, ttl time.Duration) *contextCache {
+	return &contextCache{base: base, ttl: ttl, now: time.Now, entries: make(map[string]*cachedClient)}
+}
+
+// get returns the cached client for key, building it with build on first
+// use. Building happens under the lock so concurrent calls for the same key
+// build a single client. Errors are not cached, so a failed build is retried
+// on the next call. build runs with the lock held, so it may call
+// sharingLocked.
+func (c *contextCache) get(key string, build func() (*Client, error)) (*Client, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	c.evictIdle(now)
+
+	if entry, ok := c.entries[key]; ok {
+		entry.lastUsed = now
+		return entry.client, nil
+	}
+
+	client, err := build()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(c.entries) >= maxCachedClients {
+		c.evictLeastRecentlyUsed()
+	}
+	c.entries[key] = &cachedClient{client: client, lastUsed: now}
+	return client, nil
+}
+
+// evictIdle drops the clients unused for longer than the TTL.
+func (c *contextCache) evictIdle(now time.Time) {
+	if c.ttl <= 0 {
+		return
+	}
+
+	for key, entry := range c.entries {
+		if now.Sub(entry.lastUsed) >= c.ttl {
+			c.evict(key)
+		}
+	}
+}
+
+// evictLeastRecentlyUsed drops the client that went unused the longest.
+func (c *contextCache) evictLeastRecentlyUsed() {
+	var (
+		oldestKey string
+		oldest    *cachedClient
+	)
+	for key, entry := range c.entries {
+		if oldest == nil || entry.lastUsed.Before(oldest.lastUsed) {
+			oldestKey, oldest = key, entry
+		}
+	}
+	c.evict(oldestKey)
+}
+
+// sharingLocked returns the base client or a cached client that connects
+// with the kubeconfig cluster and user identified by clusterUser, or nil if
+// there is none. The caller must hold the lock.
+func (c *contextCache) sharingLocked(clusterUser string) *Client {
+	if clusterUser == "" {
+		return nil
+	}
+	if c.base != nil && c.base.clusterUser == clusterUser {
+		return c.base
+	}
+	for _, entry := range c.entries {
+		if entry.client.clusterUser == clusterUser {
+			return entry.client
+		}
+	}
+	return nil
+}
+
+// evict drops the client for key and, unless another client shares them,
+// closes its idle connections. Requests still running on it keep their
+// connections until they finish, and the transport closes those once they
+// sit idle.
+func (c *contextCache) evict(key string) {
+	entry, ok := c.entries[key]
+	if !ok {
+		return
+	}
+
+	delete(c.entries, key)
+
+	httpClient := entry.client.httpClient
+	if httpClient == nil || (c.base != nil && c.base.httpClient == httpClient) {
+		return
+	}
+	for _, other := range c.entries {
+		if other.client.httpClient == httpClient {
+			return
+		}
+	}
+	httpClient.CloseIdleConnections()
+}
+
+// impersonationCacheKey returns the contextCache key of a client for
+// contextName acting as imp. Its parts are separated by NUL bytes, which do
+// not appear in context or user names, so it does not collide with the key
+// of a context or another identity. Groups are sorted since their order does
+// not change the identity.
+func impersonationCacheKey(contextName string, imp Impersonation) string {
+	groups := slices.Clone(imp.Groups)
+	slices.Sort(groups)
+
+	return contextName + "\x00" + imp.User + "\x00" + strings.Join(groups, "\x00")
+}
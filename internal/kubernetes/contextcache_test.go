@@ -0,0 +1,112 @@
+package kubernetes
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// contextCacheTestCache returns a cache that drops clients unused for ttl,
+// and a function that moves its clock forward.
+func contextCacheTestCache(ttl time.Duration) (cache *contextCache, advance func(time.Duration)) {
+	now := time.Now()
+	cache = newContextCache(nil, ttl)
+	cache.now = func() time.Time { return now }
+	return cache, func(d time.Duration) { now = now.Add(d) }
+}
+
+// countingBuild returns a build function for contextCache.get that counts
+// how many clients it made.
+func countingBuild(builds *int) func() (*Client, error) {
+	return func() (*Client, error) {
+		*builds++
+		return &Client{}, nil
+	}
+}
+
+func TestContextCacheEvictsIdleClients(t *testing.T) {
+	t.Parallel()
+
+	cache, advance := contextCacheTestCache(time.Minute)
+	builds := 0
+
+	first, _ := cache.get("staging", countingBuild(&builds))
+	advance(50 * time.Second)
+	second, _ := cache.get("staging", countingBuild(&builds))
+	if first != second || builds != 1 {
+		t.Fatalf("expected a client used within the TTL to be reused, built %d", builds)
+	}
+
+	// Using a client keeps it, so the TTL counts from its last use.
+	advance(50 * time.Second)
+	if _, err := cache.get("staging", countingBuild(&builds)); err != nil || builds != 1 {
+		t.Fatalf("expected the TTL to count from the last use, built %d", builds)
+	}
+
+	advance(time.Minute)
+	third, _ := cache.get("staging", countingBuild(&builds))
+	if third == first || builds != 2 {
+		t.Fatalf("expected an idle client to be rebuilt, built %d", builds)
+	}
+}
+
+func TestContextCacheBoundsClients(t *testing.T) {
+	t.Parallel()
+
+	cache, advance := contextCacheTestCache(0)
+	builds := 0
+
+	for i := range maxCachedClients {
+		if _, err := cache.get(fmt.Sprintf("context-%d", i), countingBuild(&builds)); err != nil {
+			t.Fatal(err)
+		}
+		advance(time.Second)
+	}
+
+	// Using the oldest client makes context-1 the least recently used.
+	if _, err := cache.get("context-0", countingBuild(&builds)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cache.get("one-more", countingBuild(&builds)); err != nil {
+		t.Fatal(err)
+	}
+	if len(cache.entries) != maxCachedClients {
+		t.Fatalf("expected at most %d clients, got %d", maxCachedClients, len(cache.entries))
+	}
+	if _, ok := cache.entries["context-1"]; ok {
+		t.Error("expected the least recently used client to be dropped")
+	}
+	if _, ok := cache.entries["context-0"]; !ok {
+		t.Error("expected a recently used client to be kept")
+	}
+}
+
+func TestContextCacheDoesNotCacheErrors(t *testing.T) {
+	t.Parallel()
+
+	cache, _ := contextCacheTestCache(time.Minute)
+	if _, err := cache.get("staging", func() (*Client, error) { return nil, errors.New("boom") }); err == nil {
+		t.Fatal("expected the build error")
+	}
+
+	builds := 0
+	if _, err := cache.get("staging", countingBuild(&builds)); err != nil || builds != 1 {
+		t.Fatalf("expected a failed build to be retried, built %d", builds)
+	}
+}
+
+func TestImpersonationCacheKey(t *testing.T) {
+	t.Parallel()
+
+	key := impersonationCacheKey("dev", Impersonation{User: "jane", Groups: []string{"b", "a"}})
+	if other := impersonationCacheKey("dev", Impersonation{User: "jane", Groups: []string{"a", "b"}}); other != key {
+		t.Errorf("expected the order of groups not to matter, got %q and %q", key, other)
+	}
+	if other := impersonationCacheKey("staging", Impersonation{User: "jane", Groups: []string{"a", "b"}}); other == key {
+		t.Error("expected different contexts to have different keys")
+	}
+	if other := impersonationCacheKey("dev", Impersonation{User: "jane"}); other == key {
+		t.Error("expected different groups to have different keys")
+	}
+}
@@ -0,0 +1,93 @@
+package kubernetes
+
+import (
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/discovery"
+)
+
+// missRefreshInterval is how old cached discovery results must be before a
+// resource type missing from them triggers a refresh. Custom resources
+// installed moments ago resolve on the next call, while repeated lookups of
+// a misspelled type do not run discovery every time.
+const missRefreshInterval = 10 * time.Second
+
+// discoveryCache keeps the preferred API resources of a cluster for a while.
+// Resolving a resource type needs them on every list and get call, and full
+// discovery makes a request per API group, which adds up to seconds of
+// latency and dozens of requests on clusters with many CRDs.
+type discoveryCache struct {
+	client discovery.DiscoveryInterface
+	ttl    time.Duration
+
+	// now returns the current time. It defaults to time.Now and is replaced
+	// in tests.
+	now func() time.Time
+
+	mu      sync.Mutex
+	lists   []*metav1.APIResourceList
+	err     error
+	fetched time.Time
+}
+
+// newDiscoveryCache creates a cache that keeps discovery results from client
+// for ttl. A zero ttl disables caching, so every call runs discovery.
+func newDiscoveryCache(client discovery.DiscoveryInterface, ttl time.Duration) *discoveryCache {
+	return &discoveryCache{client: client, ttl: ttl, now: time.Now}
+}
+
+// preferredResources returns the resources of the preferred version of each
+// API group, from the cache while it is fresh. Results are returned together
+// with the error when some API groups failed discovery, such as an
+// unavailable metrics-server, and those partial results are cached as well.
+// Complete failures are not cached, so the next call tries again.
+//
+// Fetching happens under the lock so concurrent calls on a cold cache run
+// discovery once. The returned lists are shared and must not be modified.
+func (d *discoveryCache) preferredResources() ([]*metav1.APIResourceList, error) {
+	if d.ttl <= 0 {
+		return d.client.ServerPreferredResources() //nolint:wrapcheck // kubernetes API errors are self-descriptive
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if !d.fetched.IsZero() && d.now().Sub(d.fetched) < d.ttl {
+		return d.lists, d.err
+	}
+
+	lists, err := d.client.ServerPreferredResources()
+	if len(lists) == 0 {
+		d.fetched = time.Time{}
+		return lists, err //nolint:wrapcheck // kubernetes API errors are self-descriptive
+	}
+
+	d.lists, d.err, d.fetched = lists, err, d.now()
+	return lists, err //nolint:wrapcheck // kubernetes API errors are self-descriptive
+}
+
+// invalidate drops the cached results, so the next call runs discovery.
+func (d *discoveryCache) invalidate() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.lists, d.err, d.fetched = nil, nil, time.Time{}
+}
+
+// refreshOnMiss drops the cached results when they are older than
+// missRefreshInterval, and reports whether it did. It is called when a
+// resource type is missing from them, which may mean it was installed after
+// they were fetched.
+func (d *discoveryCache) refreshOnMiss() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.ttl <= 0 || d.fetched.IsZero() || d.now().Sub(d.fetched) < missRefreshInterval {
+		return false
+	}
+
+	d.lists, d.err, d.fetched = nil, nil, time.Time{}
+	return true
+}
@@ -0,0 +1,179 @@
+package kubernetes
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+)
+
+// countingDiscovery serves preferred resources from a list that tests can
+// change, counting how often discovery runs.
+type countingDiscovery struct {
+	discovery.DiscoveryInterface
+
+	mu    sync.Mutex
+	lists []*metav1.APIResourceList
+	err   error
+	calls int
+}
+
+func (d *countingDiscovery) ServerPreferredResources() ([]*metav1.APIResourceList, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.calls++
+	return d.lists, d.err
+}
+
+func (d *countingDiscovery) set(lists []*metav1.APIResourceList, err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.lists, d.err = lists, err
+}
+
+func (d *countingDiscovery) count() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.calls
+}
+
+func podResources() []*metav1.APIResourceList {
+	return []*metav1.APIResourceList{{
+		GroupVersion: "v1",
+		APIResources: []metav1.APIResource{{Name: "pods", SingularName: "pod", Kind: "Pod", ShortNames: []string{"po"}}},
+	}}
+}
+
+func withWidgets(lists []*metav1.APIResourceList) []*metav1.APIResourceList {
+	return append(lists, &metav1.APIResourceList{
+		GroupVersion: "example.com/v1",
+		APIResources: []metav1.APIResource{{Name: "widgets", SingularName: "widget", Kind: "Widget"}},
+	})
+}
+
+// discoveryTestClient returns a client whose discovery is cached for ttl, and
+// a function that moves the cache's clock forward.
+func discoveryTestClient(ttl time.Duration) (client *Client, fake *countingDiscovery, advance func(time.Duration)) {
+	fake = &countingDiscovery{DiscoveryInterface: kubefake.NewSimpleClientset().Discovery(), lists: podResources()}
+
+	now := time.Now()
+	cache := newDiscoveryCache(fake, ttl)
+	cache.now = func() time.Time { return now }
+
+	client = &Client{discoveryClient: fake, discovery: cache}
+	return client, fake, func(d time.Duration) { now = now.Add(d) }
+}
+
+func TestDiscoveryCacheReusesResultsWithinTTL(t *testing.T) {
+	t.Parallel()
+
+	client, fake, advance := discoveryTestClient(time.Minute)
+
+	for range 3 {
+		gvr, err := client.ResolveResourceType("po", "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if gvr != (schema.GroupVersionResource{Version: "v1", Resource: "pods"}) {
+			t.Fatalf("unexpected resource %v", gvr)
+		}
+	}
+	if got := fake.count(); got != 1 {
+		t.Fatalf("expected discovery to run once, ran %d times", got)
+	}
+
+	advance(time.Minute)
+	if _, err := client.ResolveResourceType("pods", ""); err != nil {
+		t.Fatal(err)
+	}
+	if got := fake.count(); got != 2 {
+		t.Fatalf("expected discovery to run again once the TTL passed, ran %d times", got)
+	}
+
+	client.InvalidateDiscovery()
+	if _, err := client.ResolveResourceType("pods", ""); err != nil {
+		t.Fatal(err)
+	}
+	if got := fake.count(); got != 3 {
+		t.Fatalf("expected discovery to run again after invalidation, ran %d times", got)
+	}
+}
+
+func TestDiscoveryCacheRefreshesOnMiss(t *testing.T) {
+	t.Parallel()
+
+	client, fake, advance := discoveryTestClient(time.Hour)
+
+	if _, err := client.ResolveResourceType("pods", ""); err != nil {
+		t.Fatal(err)
+	}
+	fake.set(withWidgets(podResources()), nil)
+
+	// Cached results this recent are trusted, so a misspelled type does not
+	// run discovery on every call.
+	if _, err := client.ResolveResourceType("widgets", ""); err == nil {
+		t.Fatal("expected widgets to be missing from fresh cached results")
+	}
+	if got := fake.count(); got != 1 {
+		t.Fatalf("expected a miss on fresh results not to run discovery, ran %d times", got)
+	}
+
+	advance(missRefreshInterval)
+	gvr, err := client.ResolveResourceType("widget", "")
+	if err != nil {
+		t.Fatalf("expected the new resource to be found after a refresh, got %v", err)
+	}
+	if gvr.Group != "example.com" || gvr.Resource != "widgets" {
+		t.Fatalf("unexpected resource %v", gvr)
+	}
+	if got := fake.count(); got != 2 {
+		t.Fatalf("expected the miss to run discovery once more, ran %d times", got)
+	}
+}
+
+func TestDiscoveryCacheErrors(t *testing.T) {
+	t.Parallel()
+
+	client, fake, _ := discoveryTestClient(time.Hour)
+	partial := errors.New("unable to retrieve the complete list of server APIs: metrics.k8s.io/v1beta1")
+
+	// Complete failures are retried on the next call.
+	fake.set(nil, errors.New("connection refused"))
+	if _, err := client.ResolveResourceType("pods", ""); err == nil {
+		t.Fatal("expected discovery to fail")
+	}
+
+	// Partial results are cached together with their error.
+	fake.set(podResources(), partial)
+	for range 2 {
+		lists, err := client.DiscoverResources(t.Context())
+		if len(lists) != 1 || !errors.Is(err, partial) {
+			t.Fatalf("expected partial results with their error, got %v, %v", lists, err)
+		}
+	}
+	if got := fake.count(); got != 2 {
+		t.Fatalf("expected discovery to run once per complete failure and once for partial results, ran %d times", got)
+	}
+}
+
+func TestDiscoveryCacheDisabled(t *testing.T) {
+	t.Parallel()
+
+	client, fake, _ := discoveryTestClient(0)
+	for range 3 {
+		if _, err := client.ResolveResourceType("pods", ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := fake.count(); got != 3 {
+		t.Fatalf("expected discovery to run on every call without a TTL, ran %d times", got)
+	}
+}
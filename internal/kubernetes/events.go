@@ -0,0 +1,22 @@
+package kubernetes
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ListEvents retrieves core/v1 events in a namespace using the typed clientset.
+// If namespace is empty, the client's default namespace is used; if that is
+// also empty, events across all namespaces are returned. The opts parameter
+// supports field selectors such as "type=Warning".
+//
+//nolint:gocritic // opts is from external package, can't change signature
+func (c *Client) ListEvents(ctx context.Context, namespace string, opts metav1.ListOptions) (*corev1.EventList, error) {
+	if namespace == "" && c.namespace != "" {
+		namespace = c.namespace
+	}
+
+	return c.clientset.CoreV1().Events(namespace).List(ctx, opts) //nolint:wrapcheck // kubernetes API errors are self-descriptive
+}
@@ -0,0 +1,88 @@
+package kubernetes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+
+	"k8s.io/client-go/rest"
+)
+
+// ExecPluginCommand is the hidden subcommand the server runs itself with to
+// run a credential plugin under a time limit. Programs using
+// Config.ExecPluginTimeout must hand it to RunExecPlugin before parsing
+// their own flags.
+const ExecPluginCommand = "__exec-credential-plugin"
+
+// limitExecPlugin makes client-go run the credential plugin of config, such
+// as aws, gke-gcloud-auth-plugin, or kubelogin, through this executable's
+// ExecPluginCommand, which stops the plugin once timeout elapses. client-go
+// runs plugins without a time limit, so a plugin that hangs would otherwise
+// block every request using its credentials.
+func limitExecPlugin(config *rest.Config, timeout time.Duration) {
+	if config.ExecProvider == nil || timeout <= 0 {
+		return
+	}
+
+	// Commands that cannot be found are left alone, so client-go reports
+	// them along with the plugin's install hint.
+	command, err := exec.LookPath(config.ExecProvider.Command)
+	if err != nil {
+		return
+	}
+
+	self, err := os.Executable()
+	if err != nil {
+		return
+	}
+
+	provider := *config.ExecProvider
+	provider.Command = self
+	provider.Args = append([]string{ExecPluginCommand, timeout.String(), command}, config.ExecProvider.Args...)
+	config.ExecProvider = &provider
+}
+
+// RunExecPlugin implements ExecPluginCommand. args are the timeout, the
+// plugin command, and its arguments. The environment and standard streams,
+// which carry the exec credential protocol, are passed to the plugin as is.
+// It returns the exit code to exit with.
+func RunExecPlugin(args []string) int {
+	if len(args) < 2 {
+		fmt.Fprintf(os.Stderr, "usage: %s TIMEOUT COMMAND [ARGS...]\n", ExecPluginCommand)
+		return 2
+	}
+
+	timeout, err := time.ParseDuration(args[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid credential plugin timeout %q: %v\n", args[0], err)
+		return 2
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, args[1], args[2:]...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	err = cmd.Run()
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		fmt.Fprintf(os.Stderr, "credential plugin %s did not finish within %s and was stopped\n", args[1], timeout)
+		return 1
+	}
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to run credential plugin %s: %v\n", args[1], err)
+		return 1
+	}
+
+	return 0
+}
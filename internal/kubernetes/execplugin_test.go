@@ -0,0 +1,70 @@
+package kubernetes
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"k8s.io/client-go/rest"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+func TestLimitExecPlugin(t *testing.T) {
+	t.Parallel()
+
+	config := &rest.Config{ExecProvider: &clientcmdapi.ExecConfig{Command: "sh", Args: []string{"-c", "echo"}}}
+	original := config.ExecProvider
+
+	limitExecPlugin(config, 30*time.Second)
+
+	self, err := os.Executable()
+	if err != nil {
+		t.Fatalf("failed to find the test executable: %v", err)
+	}
+	if config.ExecProvider.Command != self {
+		t.Errorf("expected the plugin to run through %s, got %s", self, config.ExecProvider.Command)
+	}
+	args := config.ExecProvider.Args
+	if len(args) != 5 || args[0] != ExecPluginCommand || args[1] != "30s" || args[3] != "-c" || args[4] != "echo" {
+		t.Errorf("unexpected arguments %q", args)
+	}
+	if original.Command != "sh" {
+		t.Error("expected the original exec config to be left unchanged")
+	}
+
+	missing := &rest.Config{ExecProvider: &clientcmdapi.ExecConfig{Command: "definitely-not-an-auth-plugin"}}
+	limitExecPlugin(missing, 30*time.Second)
+	if missing.ExecProvider.Command != "definitely-not-an-auth-plugin" {
+		t.Errorf("expected a missing plugin to be left for client-go to report, got %s", missing.ExecProvider.Command)
+	}
+}
+
+func TestRunExecPlugin(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		args []string
+		want int
+	}{
+		{name: "success", args: []string{"5s", "sh", "-c", "exit 0"}, want: 0},
+		{name: "exit code is kept", args: []string{"5s", "sh", "-c", "exit 3"}, want: 3},
+		{name: "timeout", args: []string{"100ms", "sleep", "10"}, want: 1},
+		{name: "invalid timeout", args: []string{"soon", "sh"}, want: 2},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			start := time.Now()
+			if got := RunExecPlugin(tt.args); got != tt.want {
+				t.Errorf("expected exit code %d, got %d", tt.want, got)
+			}
+			if elapsed := time.Since(start); elapsed > 5*time.Second {
+				t.Errorf("expected the plugin to be stopped, took %s", elapsed)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package kubernetes
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// HealthProbe is the response of an API server health endpoint such as
+// /readyz or /livez.
+type HealthProbe struct {
+	// StatusCode is the HTTP status: 200 when every check passes, and 500
+	// when any check fails.
+	StatusCode int
+
+	// Body is the plain-text report, one "[+]name ok" or "[-]name failed"
+	// line per check when verbose output was requested.
+	Body string
+}
+
+// ProbeHealth requests an API server health endpoint, such as /readyz or
+// /livez, optionally with the verbose per-check breakdown. A failing probe is
+// not an error: its report is returned with the status code. Errors are only
+// returned when the endpoint could not be reached or read, such as when
+// access is denied. Clients built from fake interfaces have no REST config
+// and cannot reach the endpoints, so they return an error.
+func (c *Client) ProbeHealth(ctx context.Context, endpoint string, verbose bool) (*HealthProbe, error) {
+	if c.config == nil {
+		return nil, errors.New("API server health endpoints are not available without a REST config")
+	}
+
+	request := c.clientset.CoreV1().RESTClient().Get().AbsPath(endpoint)
+	if verbose {
+		request = request.Param("verbose", "true")
+	}
+
+	result := request.Do(ctx)
+	var code int
+	result.StatusCode(&code)
+	body, err := result.Raw()
+
+	// Health endpoints report failed checks with a 500 and a plain-text
+	// body, which is the answer rather than a failure to get one.
+	if err != nil && code != http.StatusInternalServerError {
+		return nil, err //nolint:wrapcheck // kubernetes API errors are self-descriptive
+	}
+
+	return &HealthProbe{StatusCode: code, Body: string(body)}, nil
+}
+
+// Ping checks that the API server is reachable and accepts the client's
+// credentials by reading the core API discovery document, which every
+// authenticated user may read and which is cheap for the API server to
+// serve. Clients built from fake interfaces have no REST config and return
+// an error.
+func (c *Client) Ping(ctx context.Context) error {
+	if c.config == nil {
+		return errors.New("the API server cannot be pinged without a REST config")
+	}
+
+	return c.clientset.CoreV1().RESTClient().Get().AbsPath("/api").Do(ctx).Error() //nolint:wrapcheck // kubernetes API errors are self-descriptive
+}
@@ -0,0 +1,74 @@
+package kubernetes
+
+import (
+	"context"
+	"errors"
+
+	"k8s.io/client-go/rest"
+)
+
+// Impersonation is a user and groups to act as through Kubernetes
+// impersonation headers, so requests are authorized with that identity's
+// permissions instead of the credentials' own. The credentials need the
+// impersonate verb on the users and groups involved.
+type Impersonation struct {
+	// User is the username to act as. Kubernetes requires it whenever
+	// Groups is set.
+	User string
+
+	// Groups are the groups to act as, in addition to the user's.
+	Groups []string
+}
+
+// IsZero reports whether no impersonation is set.
+func (i Impersonation) IsZero() bool {
+	return i.User == "" && len(i.Groups) == 0
+}
+
+// Validate returns an error when the impersonation would be rejected by the
+// API server, such as groups without a user.
+func (i Impersonation) Validate() error {
+	if i.User == "" && len(i.Groups) > 0 {
+		return errors.New("impersonating groups requires a user to impersonate as well")
+	}
+	return nil
+}
+
+// restConfig converts the impersonation to its rest.Config form.
+func (i Impersonation) restConfig() rest.ImpersonationConfig {
+	return rest.ImpersonationConfig{UserName: i.User, Groups: i.Groups}
+}
+
+type impersonationKey struct{}
+
+// WithImpersonation returns a context that makes ForContext return a client
+// acting as imp. It overrides the server-wide Config.Impersonate for the
+// requests made with that client.
+func WithImpersonation(ctx context.Context, imp Impersonation) context.Context {
+	return context.WithValue(ctx, impersonationKey{}, imp)
+}
+
+// ImpersonationFrom returns the impersonation set on ctx by
+// WithImpersonation, if any.
+func ImpersonationFrom(ctx context.Context) (Impersonation, bool) {
+	imp, ok := ctx.Value(impersonationKey{}).(Impersonation)
+	if !ok || imp.IsZero() {
+		return Impersonation{}, false
+	}
+	return imp, true
+}
+
+// impersonating returns a copy of the client that acts as imp.
+func (c *Client) impersonating(imp Impersonation) (*Client, error) {
+	if err := imp.Validate(); err != nil {
+		return nil, err
+	}
+	if c.config == nil {
+		return nil, errors.New("impersonation is not available: the client has no REST config")
+	}
+
+	config := rest.CopyConfig(c.config)
+	config.Impersonate = imp.restConfig()
+
+	return newClientForConfig(config, c.originalConfig)
+}
@@ -0,0 +1,19 @@
+package kubernetes
+
+import (
+	"context"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GetLease retrieves a single Lease using the typed clientset. Control plane
+// components such as kube-scheduler hold one for leader election.
+// If namespace is empty, the client's default namespace is used.
+func (c *Client) GetLease(ctx context.Context, namespace, name string) (*coordinationv1.Lease, error) {
+	if namespace == "" && c.namespace != "" {
+		namespace = c.namespace
+	}
+
+	return c.clientset.CoordinationV1().Leases(namespace).Get(ctx, name, metav1.GetOptions{}) //nolint:wrapcheck // kubernetes API errors are self-descriptive
+}
@@ -0,0 +1,23 @@
+package kubernetes
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GetNamespace retrieves a single namespace using the typed clientset.
+// Namespaces are cluster-scoped, so the client's default namespace does not
+// apply.
+func (c *Client) GetNamespace(ctx context.Context, name string) (*corev1.Namespace, error) {
+	return c.clientset.CoreV1().Namespaces().Get(ctx, name, metav1.GetOptions{}) //nolint:wrapcheck // kubernetes API errors are self-descriptive
+}
+
+// ListNamespaces retrieves the cluster's namespaces using the typed
+// clientset.
+//
+//nolint:gocritic // opts is from external package, can't change signature
+func (c *Client) ListNamespaces(ctx context.Context, opts metav1.ListOptions) (*corev1.NamespaceList, error) {
+	return c.clientset.CoreV1().Namespaces().List(ctx, opts) //nolint:wrapcheck // kubernetes API errors are self-descriptive
+}
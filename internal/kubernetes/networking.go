@@ -0,0 +1,75 @@
+package kubernetes
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GetService retrieves a single Service using the typed clientset. If
+// namespace is empty, the client's default namespace is used.
+func (c *Client) GetService(ctx context.Context, namespace, name string) (*corev1.Service, error) {
+	if namespace == "" {
+		namespace = c.namespace
+	}
+
+	return c.clientset.CoreV1().Services(namespace).Get(ctx, name, metav1.GetOptions{}) //nolint:wrapcheck // kubernetes API errors are self-descriptive
+}
+
+// ListServices retrieves the Services in a namespace using the typed
+// clientset. If namespace is empty, the client's default namespace is used; if
+// that is also empty, Services across all namespaces are returned.
+//
+//nolint:gocritic // opts is from external package, can't change signature
+func (c *Client) ListServices(ctx context.Context, namespace string, opts metav1.ListOptions) (*corev1.ServiceList, error) {
+	if namespace == "" && c.namespace != "" {
+		namespace = c.namespace
+	}
+
+	return c.clientset.CoreV1().Services(namespace).List(ctx, opts) //nolint:wrapcheck // kubernetes API errors are self-descriptive
+}
+
+// ListEndpointSlices retrieves the EndpointSlices in a namespace using the
+// typed clientset. If namespace is empty, the client's default namespace is
+// used; if that is also empty, EndpointSlices across all namespaces are
+// returned. The opts parameter supports label selectors such as
+// "kubernetes.io/service-name=web".
+//
+//nolint:gocritic // opts is from external package, can't change signature
+func (c *Client) ListEndpointSlices(ctx context.Context, namespace string, opts metav1.ListOptions) (*discoveryv1.EndpointSliceList, error) {
+	if namespace == "" && c.namespace != "" {
+		namespace = c.namespace
+	}
+
+	return c.clientset.DiscoveryV1().EndpointSlices(namespace).List(ctx, opts) //nolint:wrapcheck // kubernetes API errors are self-descriptive
+}
+
+// ListIngresses retrieves the Ingresses in a namespace using the typed
+// clientset. If namespace is empty, the client's default namespace is used; if
+// that is also empty, Ingresses across all namespaces are returned.
+//
+//nolint:gocritic // opts is from external package, can't change signature
+func (c *Client) ListIngresses(ctx context.Context, namespace string, opts metav1.ListOptions) (*networkingv1.IngressList, error) {
+	if namespace == "" && c.namespace != "" {
+		namespace = c.namespace
+	}
+
+	return c.clientset.NetworkingV1().Ingresses(namespace).List(ctx, opts) //nolint:wrapcheck // kubernetes API errors are self-descriptive
+}
+
+// ListNetworkPolicies retrieves the NetworkPolicies in a namespace using the
+// typed clientset. If namespace is empty, the client's default namespace is
+// used; if that is also empty, NetworkPolicies across all namespaces are
+// returned.
+//
+//nolint:gocritic // opts is from external package, can't change signature
+func (c *Client) ListNetworkPolicies(ctx context.Context, namespace string, opts metav1.ListOptions) (*networkingv1.NetworkPolicyList, error) {
+	if namespace == "" && c.namespace != "" {
+		namespace = c.namespace
+	}
+
+	return c.clientset.NetworkingV1().NetworkPolicies(namespace).List(ctx, opts) //nolint:wrapcheck // kubernetes API errors are self-descriptive
+}
@@ -0,0 +1,79 @@
+package kubernetes
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ListNodes retrieves the nodes in the cluster using the typed clientset.
+// The opts parameter supports label selectors to narrow the result.
+//
+//nolint:gocritic // opts is from external package, can't change signature
+func (c *Client) ListNodes(ctx context.Context, opts metav1.ListOptions) (*corev1.NodeList, error) {
+	return c.clientset.CoreV1().Nodes().List(ctx, opts) //nolint:wrapcheck // kubernetes API errors are self-descriptive
+}
+
+// NodeStatsSummary is the part of the kubelet's stats summary that tools
+// read. The full type lives in k8s.io/kubelet, which this module does not
+// depend on.
+type NodeStatsSummary struct {
+	Node NodeStats `json:"node"`
+}
+
+// NodeStats holds the node-level filesystem statistics of a stats summary.
+type NodeStats struct {
+	NodeName string `json:"nodeName"`
+
+	// Fs is the filesystem of the kubelet's root directory.
+	Fs *FsStats `json:"fs,omitempty"`
+
+	// Runtime holds the container runtime's filesystems.
+	Runtime *RuntimeStats `json:"runtime,omitempty"`
+}
+
+// RuntimeStats holds the filesystems the container runtime stores images and
+// writable container layers on.
+type RuntimeStats struct {
+	ImageFs     *FsStats `json:"imageFs,omitempty"`
+	ContainerFs *FsStats `json:"containerFs,omitempty"`
+}
+
+// FsStats is the usage of a filesystem. Fields are nil when the kubelet does
+// not report them.
+type FsStats struct {
+	AvailableBytes *uint64 `json:"availableBytes,omitempty"`
+	CapacityBytes  *uint64 `json:"capacityBytes,omitempty"`
+	UsedBytes      *uint64 `json:"usedBytes,omitempty"`
+	InodesFree     *uint64 `json:"inodesFree,omitempty"`
+	Inodes         *uint64 `json:"inodes,omitempty"`
+	InodesUsed     *uint64 `json:"inodesUsed,omitempty"`
+}
+
+// GetNodeStatsSummary retrieves the kubelet's stats summary of a node through
+// the API server's node proxy, which requires get access to nodes/proxy.
+// Clients built from fake interfaces have no REST config and cannot reach a
+// kubelet, so they return an error.
+func (c *Client) GetNodeStatsSummary(ctx context.Context, nodeName string) (*NodeStatsSummary, error) {
+	if c.config == nil {
+		return nil, errors.New("kubelet stats are not available without a REST config")
+	}
+
+	raw, err := c.clientset.CoreV1().RESTClient().Get().
+		AbsPath("/api/v1/nodes", nodeName, "proxy", "stats", "summary").
+		DoRaw(ctx)
+	if err != nil {
+		return nil, err //nolint:wrapcheck // kubernetes API errors are self-descriptive
+	}
+
+	var summary NodeStatsSummary
+	if err := json.Unmarshal(raw, &summary); err != nil {
+		return nil, fmt.Errorf("failed to decode the stats summary of node %s: %w", nodeName, err)
+	}
+
+	return &summary, nil
+}
@@ -0,0 +1,45 @@
+package kubernetes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GetPod retrieves a single pod by name using the typed clientset, so callers
+// can inspect its spec and status without converting from unstructured data.
+//
+// The namespace parameter specifies the pod's namespace; if empty, the client's
+// default namespace is used.
+func (c *Client) GetPod(ctx context.Context, namespace, podName string) (*corev1.Pod, error) {
+	if namespace == "" && c.namespace != "" {
+		namespace = c.namespace
+	}
+
+	if namespace == "" {
+		return nil, errors.New("namespace is required")
+	}
+
+	pod, err := c.clientset.CoreV1().Pods(namespace).Get(ctx, podName, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pod %q: %w", podName, err)
+	}
+
+	return pod, nil
+}
+
+// ListPods retrieves the pods in a namespace using the typed clientset.
+// If namespace is empty, the client's default namespace is used; if that is
+// also empty, pods across all namespaces are returned.
+//
+//nolint:gocritic // opts is from external package, can't change signature
+func (c *Client) ListPods(ctx context.Context, namespace string, opts metav1.ListOptions) (*corev1.PodList, error) {
+	if namespace == "" && c.namespace != "" {
+		namespace = c.namespace
+	}
+
+	return c.clientset.CoreV1().Pods(namespace).List(ctx, opts) //nolint:wrapcheck // kubernetes API errors are self-descriptive
+}
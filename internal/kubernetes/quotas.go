@@ -0,0 +1,30 @@
+package kubernetes
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ListResourceQuotas retrieves the ResourceQuota objects in a namespace.
+// If namespace is empty, the client's default namespace is used; if that is
+// also empty, quotas across all namespaces are returned.
+func (c *Client) ListResourceQuotas(ctx context.Context, namespace string) (*corev1.ResourceQuotaList, error) {
+	if namespace == "" && c.namespace != "" {
+		namespace = c.namespace
+	}
+
+	return c.clientset.CoreV1().ResourceQuotas(namespace).List(ctx, metav1.ListOptions{}) //nolint:wrapcheck // kubernetes API errors are self-descriptive
+}
+
+// ListLimitRanges retrieves the LimitRange objects in a namespace.
+// If namespace is empty, the client's default namespace is used; if that is
+// also empty, limit ranges across all namespaces are returned.
+func (c *Client) ListLimitRanges(ctx context.Context, namespace string) (*corev1.LimitRangeList, error) {
+	if namespace == "" && c.namespace != "" {
+		namespace = c.namespace
+	}
+
+	return c.clientset.CoreV1().LimitRanges(namespace).List(ctx, metav1.ListOptions{}) //nolint:wrapcheck // kubernetes API errors are self-descriptive
+}
@@ -0,0 +1,50 @@
+package kubernetes
+
+import (
+	"context"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ListRoleBindings retrieves the RoleBindings in a namespace using the typed
+// clientset. If namespace is empty, the client's default namespace is used;
+// if that is also empty, RoleBindings across all namespaces are returned.
+//
+//nolint:gocritic // opts is from external package, can't change signature
+func (c *Client) ListRoleBindings(ctx context.Context, namespace string, opts metav1.ListOptions) (*rbacv1.RoleBindingList, error) {
+	if namespace == "" && c.namespace != "" {
+		namespace = c.namespace
+	}
+
+	return c.clientset.RbacV1().RoleBindings(namespace).List(ctx, opts) //nolint:wrapcheck // kubernetes API errors are self-descriptive
+}
+
+// ListClusterRoleBindings retrieves the cluster's ClusterRoleBindings using
+// the typed clientset.
+//
+//nolint:gocritic // opts is from external package, can't change signature
+func (c *Client) ListClusterRoleBindings(ctx context.Context, opts metav1.ListOptions) (*rbacv1.ClusterRoleBindingList, error) {
+	return c.clientset.RbacV1().ClusterRoleBindings().List(ctx, opts) //nolint:wrapcheck // kubernetes API errors are self-descriptive
+}
+
+// ListRoles retrieves the Roles in a namespace using the typed clientset.
+// If namespace is empty, the client's default namespace is used; if that is
+// also empty, Roles across all namespaces are returned.
+//
+//nolint:gocritic // opts is from external package, can't change signature
+func (c *Client) ListRoles(ctx context.Context, namespace string, opts metav1.ListOptions) (*rbacv1.RoleList, error) {
+	if namespace == "" && c.namespace != "" {
+		namespace = c.namespace
+	}
+
+	return c.clientset.RbacV1().Roles(namespace).List(ctx, opts) //nolint:wrapcheck // kubernetes API errors are self-descriptive
+}
+
+// ListClusterRoles retrieves the cluster's ClusterRoles using the typed
+// clientset.
+//
+//nolint:gocritic // opts is from external package, can't change signature
+func (c *Client) ListClusterRoles(ctx context.Context, opts metav1.ListOptions) (*rbacv1.ClusterRoleList, error) {
+	return c.clientset.RbacV1().ClusterRoles().List(ctx, opts) //nolint:wrapcheck // kubernetes API errors are self-descriptive
+}
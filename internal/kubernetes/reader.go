@@ -0,0 +1,236 @@
+package kubernetes
+
+import (
+	"context"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	batchv1 "k8s.io/api/batch/v1"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/version"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+)
+
+// ClusterReader is the read-only view of a cluster that tool handlers depend
+// on. *Client implements it against a live API server; other implementations
+// (in-memory fakes for tests, snapshot or caching backends) can be swapped in
+// without touching handler logic.
+//
+// Implementations apply the same namespace defaulting as *Client: an empty
+// namespace falls back to the configured default namespace, if any.
+type ClusterReader interface {
+	// ForContext returns a reader for the named kubeconfig context, or the
+	// receiver itself when contextName is empty. It honors an impersonation
+	// set on ctx with WithImpersonation.
+	ForContext(ctx context.Context, contextName string) (ClusterReader, error)
+
+	// ListContexts returns the contexts available in the kubeconfig.
+	ListContexts() ([]KubeContext, error)
+
+	// DiscoverResources returns the API resources served by the cluster.
+	DiscoverResources(ctx context.Context) ([]*metav1.APIResourceList, error)
+
+	// InvalidateDiscovery drops the cached API resources, so the next call
+	// runs discovery again.
+	InvalidateDiscovery()
+
+	// DiscoverAllResources returns the API resources served in every version.
+	DiscoverAllResources(ctx context.Context) ([]*metav1.APIResourceList, error)
+
+	// ServerGroups returns the served API groups with their preferred versions.
+	ServerGroups(ctx context.Context) (*metav1.APIGroupList, error)
+
+	// ServerURL returns the URL of the API server, or an empty string when it
+	// is not known.
+	ServerURL() string
+
+	// ServerVersion returns the Kubernetes version reported by the API server.
+	ServerVersion() (*version.Info, error)
+
+	// ResolveResourceType maps a plural, singular, kind, or short name to a
+	// GroupVersionResource, optionally constrained to an API version.
+	ResolveResourceType(resourceType, apiVersion string) (schema.GroupVersionResource, error)
+
+	// GetOpenAPISchema returns the OpenAPI v3 document for a group version as JSON.
+	GetOpenAPISchema(gv schema.GroupVersion) ([]byte, error)
+
+	// ListResources lists resources of any type through the dynamic client.
+	// AllNamespaces as the namespace lists across every namespace.
+	ListResources(ctx context.Context, gvr schema.GroupVersionResource, namespace string, opts metav1.ListOptions) (*unstructured.UnstructuredList, error)
+
+	// ListResourceMetadata lists only the object metadata of resources of any
+	// type through the metadata client.
+	ListResourceMetadata(ctx context.Context, gvr schema.GroupVersionResource, namespace string, opts metav1.ListOptions) (*metav1.PartialObjectMetadataList, error)
+
+	// GetResource retrieves a single resource of any type through the dynamic client.
+	GetResource(ctx context.Context, gvr schema.GroupVersionResource, namespace, name string) (*unstructured.Unstructured, error)
+
+	// GetSubresource retrieves a subresource of a single resource, such as
+	// its status or scale, through the dynamic client.
+	GetSubresource(ctx context.Context, gvr schema.GroupVersionResource, namespace, name, subresource string) (*unstructured.Unstructured, error)
+
+	// GetPod retrieves a single typed pod.
+	GetPod(ctx context.Context, namespace, podName string) (*corev1.Pod, error)
+
+	// ListPods lists typed pods.
+	ListPods(ctx context.Context, namespace string, opts metav1.ListOptions) (*corev1.PodList, error)
+
+	// GetPodContainers returns the names of a pod's regular containers.
+	GetPodContainers(ctx context.Context, namespace, podName string) ([]string, error)
+
+	// GetPodLogsWithOptions retrieves a container's logs.
+	GetPodLogsWithOptions(ctx context.Context, namespace, podName string, opts *LogOptions) (string, error)
+
+	// GetNamespace retrieves a single typed namespace.
+	GetNamespace(ctx context.Context, name string) (*corev1.Namespace, error)
+
+	// ListNamespaces lists typed namespaces.
+	ListNamespaces(ctx context.Context, opts metav1.ListOptions) (*corev1.NamespaceList, error)
+
+	// ListNodes lists typed nodes.
+	ListNodes(ctx context.Context, opts metav1.ListOptions) (*corev1.NodeList, error)
+
+	// GetNodeStatsSummary retrieves a node's kubelet stats summary.
+	GetNodeStatsSummary(ctx context.Context, nodeName string) (*NodeStatsSummary, error)
+
+	// ProbeHealth requests an API server health endpoint such as /readyz.
+	ProbeHealth(ctx context.Context, endpoint string, verbose bool) (*HealthProbe, error)
+
+	// Ping checks that the API server is reachable and accepts the client's
+	// credentials.
+	Ping(ctx context.Context) error
+
+	// GetLease retrieves a single typed Lease.
+	GetLease(ctx context.Context, namespace, name string) (*coordinationv1.Lease, error)
+
+	// ListEvents lists core/v1 events.
+	ListEvents(ctx context.Context, namespace string, opts metav1.ListOptions) (*corev1.EventList, error)
+
+	// ListPersistentVolumeClaims lists typed PersistentVolumeClaims.
+	ListPersistentVolumeClaims(ctx context.Context, namespace string, opts metav1.ListOptions) (*corev1.PersistentVolumeClaimList, error)
+
+	// ListPersistentVolumes lists typed PersistentVolumes.
+	ListPersistentVolumes(ctx context.Context, opts metav1.ListOptions) (*corev1.PersistentVolumeList, error)
+
+	// ListStorageClasses lists typed StorageClasses.
+	ListStorageClasses(ctx context.Context, opts metav1.ListOptions) (*storagev1.StorageClassList, error)
+
+	// ListValidatingWebhookConfigurations lists typed ValidatingWebhookConfigurations.
+	ListValidatingWebhookConfigurations(ctx context.Context, opts metav1.ListOptions) (*admissionregistrationv1.ValidatingWebhookConfigurationList, error)
+
+	// ListMutatingWebhookConfigurations lists typed MutatingWebhookConfigurations.
+	ListMutatingWebhookConfigurations(ctx context.Context, opts metav1.ListOptions) (*admissionregistrationv1.MutatingWebhookConfigurationList, error)
+
+	// ListPriorityClasses lists typed PriorityClasses.
+	ListPriorityClasses(ctx context.Context, opts metav1.ListOptions) (*schedulingv1.PriorityClassList, error)
+
+	// ListDeployments lists typed Deployments.
+	ListDeployments(ctx context.Context, namespace string, opts metav1.ListOptions) (*appsv1.DeploymentList, error)
+
+	// GetDeployment retrieves a single typed Deployment.
+	GetDeployment(ctx context.Context, namespace, name string) (*appsv1.Deployment, error)
+
+	// GetStatefulSet retrieves a single typed StatefulSet.
+	GetStatefulSet(ctx context.Context, namespace, name string) (*appsv1.StatefulSet, error)
+
+	// GetDaemonSet retrieves a single typed DaemonSet.
+	GetDaemonSet(ctx context.Context, namespace, name string) (*appsv1.DaemonSet, error)
+
+	// ListReplicaSets lists typed ReplicaSets.
+	ListReplicaSets(ctx context.Context, namespace string, opts metav1.ListOptions) (*appsv1.ReplicaSetList, error)
+
+	// ListStatefulSets lists typed StatefulSets.
+	ListStatefulSets(ctx context.Context, namespace string, opts metav1.ListOptions) (*appsv1.StatefulSetList, error)
+
+	// ListDaemonSets lists typed DaemonSets.
+	ListDaemonSets(ctx context.Context, namespace string, opts metav1.ListOptions) (*appsv1.DaemonSetList, error)
+
+	// ListJobs lists typed Jobs.
+	ListJobs(ctx context.Context, namespace string, opts metav1.ListOptions) (*batchv1.JobList, error)
+
+	// ListHorizontalPodAutoscalers lists typed autoscaling/v2 HorizontalPodAutoscalers.
+	ListHorizontalPodAutoscalers(ctx context.Context, namespace string, opts metav1.ListOptions) (*autoscalingv2.HorizontalPodAutoscalerList, error)
+
+	// ListPodDisruptionBudgets lists typed policy/v1 PodDisruptionBudgets.
+	ListPodDisruptionBudgets(ctx context.Context, namespace string, opts metav1.ListOptions) (*policyv1.PodDisruptionBudgetList, error)
+
+	// GetService retrieves a single typed Service.
+	GetService(ctx context.Context, namespace, name string) (*corev1.Service, error)
+
+	// ListServices lists typed Services.
+	ListServices(ctx context.Context, namespace string, opts metav1.ListOptions) (*corev1.ServiceList, error)
+
+	// ListEndpointSlices lists typed EndpointSlices.
+	ListEndpointSlices(ctx context.Context, namespace string, opts metav1.ListOptions) (*discoveryv1.EndpointSliceList, error)
+
+	// ListIngresses lists typed Ingresses.
+	ListIngresses(ctx context.Context, namespace string, opts metav1.ListOptions) (*networkingv1.IngressList, error)
+
+	// ListNetworkPolicies lists typed NetworkPolicies.
+	ListNetworkPolicies(ctx context.Context, namespace string, opts metav1.ListOptions) (*networkingv1.NetworkPolicyList, error)
+
+	// GetServiceAccount retrieves a single typed ServiceAccount.
+	GetServiceAccount(ctx context.Context, namespace, name string) (*corev1.ServiceAccount, error)
+
+	// ListServiceAccounts lists typed ServiceAccounts.
+	ListServiceAccounts(ctx context.Context, namespace string, opts metav1.ListOptions) (*corev1.ServiceAccountList, error)
+
+	// ListSecrets lists typed Secrets, including their data.
+	ListSecrets(ctx context.Context, namespace string, opts metav1.ListOptions) (*corev1.SecretList, error)
+
+	// ListRoleBindings lists typed RoleBindings.
+	ListRoleBindings(ctx context.Context, namespace string, opts metav1.ListOptions) (*rbacv1.RoleBindingList, error)
+
+	// ListClusterRoleBindings lists typed ClusterRoleBindings.
+	ListClusterRoleBindings(ctx context.Context, opts metav1.ListOptions) (*rbacv1.ClusterRoleBindingList, error)
+
+	// ListRoles lists typed Roles.
+	ListRoles(ctx context.Context, namespace string, opts metav1.ListOptions) (*rbacv1.RoleList, error)
+
+	// ListClusterRoles lists typed ClusterRoles.
+	ListClusterRoles(ctx context.Context, opts metav1.ListOptions) (*rbacv1.ClusterRoleList, error)
+
+	// ReviewAccess reports whether the client's credentials may perform an
+	// action, through a SelfSubjectAccessReview.
+	ReviewAccess(ctx context.Context, attributes *authorizationv1.ResourceAttributes) (*authorizationv1.SubjectAccessReviewStatus, error)
+
+	// ReviewRules returns the rules the client's credentials are granted in
+	// a namespace, through a SelfSubjectRulesReview.
+	ReviewRules(ctx context.Context, namespace string) (*authorizationv1.SubjectRulesReviewStatus, error)
+
+	// ListResourceQuotas lists the ResourceQuota objects in a namespace.
+	ListResourceQuotas(ctx context.Context, namespace string) (*corev1.ResourceQuotaList, error)
+
+	// ListLimitRanges lists the LimitRange objects in a namespace.
+	ListLimitRanges(ctx context.Context, namespace string) (*corev1.LimitRangeList, error)
+
+	// GetNodeMetrics returns metrics-server usage for all nodes.
+	GetNodeMetrics(ctx context.Context) (*metricsv1beta1.NodeMetricsList, error)
+
+	// GetNodeMetricsByName returns metrics-server usage for a single node.
+	GetNodeMetricsByName(ctx context.Context, nodeName string) (*metricsv1beta1.NodeMetrics, error)
+
+	// GetPodMetrics returns metrics-server usage for pods in all namespaces.
+	GetPodMetrics(ctx context.Context) (*metricsv1beta1.PodMetricsList, error)
+
+	// GetPodMetricsByNamespace returns metrics-server usage for pods in a namespace.
+	GetPodMetricsByNamespace(ctx context.Context, namespace string) (*metricsv1beta1.PodMetricsList, error)
+
+	// GetPodMetricsByName returns metrics-server usage for a single pod.
+	GetPodMetricsByName(ctx context.Context, namespace, podName string) (*metricsv1beta1.PodMetrics, error)
+}
+
+// Ensure *Client satisfies ClusterReader.
+var _ ClusterReader = (*Client)(nil)
@@ -0,0 +1,16 @@
+package kubernetes
+
+import (
+	"context"
+
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ListPriorityClasses retrieves the cluster-scoped PriorityClasses using the
+// typed clientset.
+//
+//nolint:gocritic // opts is from external package, can't change signature
+func (c *Client) ListPriorityClasses(ctx context.Context, opts metav1.ListOptions) (*schedulingv1.PriorityClassList, error) {
+	return c.clientset.SchedulingV1().PriorityClasses().List(ctx, opts) //nolint:wrapcheck // kubernetes API errors are self-descriptive
+}
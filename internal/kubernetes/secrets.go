@@ -0,0 +1,24 @@
+package kubernetes
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ListSecrets retrieves the Secrets in a namespace using the typed
+// clientset. If namespace is empty, the client's default namespace is used;
+// if that is also empty, Secrets across all namespaces are returned.
+//
+// The returned Secrets include their data. Callers must not return it in
+// tool results.
+//
+//nolint:gocritic // opts is from external package, can't change signature
+func (c *Client) ListSecrets(ctx context.Context, namespace string, opts metav1.ListOptions) (*corev1.SecretList, error) {
+	if namespace == "" && c.namespace != "" {
+		namespace = c.namespace
+	}
+
+	return c.clientset.CoreV1().Secrets(namespace).List(ctx, opts) //nolint:wrapcheck // kubernetes API errors are self-descriptive
+}
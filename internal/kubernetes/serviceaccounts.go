@@ -0,0 +1,32 @@
+package kubernetes
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GetServiceAccount retrieves a single ServiceAccount using the typed
+// clientset. If namespace is empty, the client's default namespace is used.
+func (c *Client) GetServiceAccount(ctx context.Context, namespace, name string) (*corev1.ServiceAccount, error) {
+	if namespace == "" {
+		namespace = c.namespace
+	}
+
+	return c.clientset.CoreV1().ServiceAccounts(namespace).Get(ctx, name, metav1.GetOptions{}) //nolint:wrapcheck // kubernetes API errors are self-descriptive
+}
+
+// ListServiceAccounts retrieves the ServiceAccounts in a namespace using the
+// typed clientset. If namespace is empty, the client's default namespace is
+// used; if that is also empty, ServiceAccounts across all namespaces are
+// returned.
+//
+//nolint:gocritic // opts is from external package, can't change signature
+func (c *Client) ListServiceAccounts(ctx context.Context, namespace string, opts metav1.ListOptions) (*corev1.ServiceAccountList, error) {
+	if namespace == "" && c.namespace != "" {
+		namespace = c.namespace
+	}
+
+	return c.clientset.CoreV1().ServiceAccounts(namespace).List(ctx, opts) //nolint:wrapcheck // kubernetes API errors are self-descriptive
+}
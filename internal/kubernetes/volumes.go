@@ -0,0 +1,39 @@
+package kubernetes
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ListPersistentVolumeClaims retrieves the PersistentVolumeClaims in a namespace
+// using the typed clientset. If namespace is empty, the client's default
+// namespace is used; if that is also empty, claims across all namespaces are
+// returned.
+//
+//nolint:gocritic // opts is from external package, can't change signature
+func (c *Client) ListPersistentVolumeClaims(ctx context.Context, namespace string, opts metav1.ListOptions) (*corev1.PersistentVolumeClaimList, error) {
+	if namespace == "" && c.namespace != "" {
+		namespace = c.namespace
+	}
+
+	return c.clientset.CoreV1().PersistentVolumeClaims(namespace).List(ctx, opts) //nolint:wrapcheck // kubernetes API errors are self-descriptive
+}
+
+// ListPersistentVolumes retrieves the cluster-scoped PersistentVolumes using
+// the typed clientset.
+//
+//nolint:gocritic // opts is from external package, can't change signature
+func (c *Client) ListPersistentVolumes(ctx context.Context, opts metav1.ListOptions) (*corev1.PersistentVolumeList, error) {
+	return c.clientset.CoreV1().PersistentVolumes().List(ctx, opts) //nolint:wrapcheck // kubernetes API errors are self-descriptive
+}
+
+// ListStorageClasses retrieves the cluster-scoped StorageClasses using the
+// typed clientset.
+//
+//nolint:gocritic // opts is from external package, can't change signature
+func (c *Client) ListStorageClasses(ctx context.Context, opts metav1.ListOptions) (*storagev1.StorageClassList, error) {
+	return c.clientset.StorageV1().StorageClasses().List(ctx, opts) //nolint:wrapcheck // kubernetes API errors are self-descriptive
+}
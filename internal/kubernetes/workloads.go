@@ -0,0 +1,134 @@
+package kubernetes
+
+import (
+	"context"
+
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	batchv1 "k8s.io/api/batch/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ListDeployments retrieves the Deployments in a namespace using the typed
+// clientset. If namespace is empty, the client's default namespace is used; if
+// that is also empty, deployments across all namespaces are returned.
+//
+//nolint:gocritic // opts is from external package, can't change signature
+func (c *Client) ListDeployments(ctx context.Context, namespace string, opts metav1.ListOptions) (*appsv1.DeploymentList, error) {
+	if namespace == "" && c.namespace != "" {
+		namespace = c.namespace
+	}
+
+	return c.clientset.AppsV1().Deployments(namespace).List(ctx, opts) //nolint:wrapcheck // kubernetes API errors are self-descriptive
+}
+
+// GetDeployment retrieves a single Deployment using the typed clientset. If
+// namespace is empty, the client's default namespace is used.
+func (c *Client) GetDeployment(ctx context.Context, namespace, name string) (*appsv1.Deployment, error) {
+	if namespace == "" {
+		namespace = c.namespace
+	}
+
+	return c.clientset.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{}) //nolint:wrapcheck // kubernetes API errors are self-descriptive
+}
+
+// GetStatefulSet retrieves a single StatefulSet using the typed clientset. If
+// namespace is empty, the client's default namespace is used.
+func (c *Client) GetStatefulSet(ctx context.Context, namespace, name string) (*appsv1.StatefulSet, error) {
+	if namespace == "" {
+		namespace = c.namespace
+	}
+
+	return c.clientset.AppsV1().StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{}) //nolint:wrapcheck // kubernetes API errors are self-descriptive
+}
+
+// GetDaemonSet retrieves a single DaemonSet using the typed clientset. If
+// namespace is empty, the client's default namespace is used.
+func (c *Client) GetDaemonSet(ctx context.Context, namespace, name string) (*appsv1.DaemonSet, error) {
+	if namespace == "" {
+		namespace = c.namespace
+	}
+
+	return c.clientset.AppsV1().DaemonSets(namespace).Get(ctx, name, metav1.GetOptions{}) //nolint:wrapcheck // kubernetes API errors are self-descriptive
+}
+
+// ListReplicaSets retrieves the ReplicaSets in a namespace using the typed
+// clientset. If namespace is empty, the client's default namespace is used; if
+// that is also empty, ReplicaSets across all namespaces are returned.
+//
+//nolint:gocritic // opts is from external package, can't change signature
+func (c *Client) ListReplicaSets(ctx context.Context, namespace string, opts metav1.ListOptions) (*appsv1.ReplicaSetList, error) {
+	if namespace == "" && c.namespace != "" {
+		namespace = c.namespace
+	}
+
+	return c.clientset.AppsV1().ReplicaSets(namespace).List(ctx, opts) //nolint:wrapcheck // kubernetes API errors are self-descriptive
+}
+
+// ListStatefulSets retrieves the StatefulSets in a namespace using the typed
+// clientset. If namespace is empty, the client's default namespace is used; if
+// that is also empty, StatefulSets across all namespaces are returned.
+//
+//nolint:gocritic // opts is from external package, can't change signature
+func (c *Client) ListStatefulSets(ctx context.Context, namespace string, opts metav1.ListOptions) (*appsv1.StatefulSetList, error) {
+	if namespace == "" && c.namespace != "" {
+		namespace = c.namespace
+	}
+
+	return c.clientset.AppsV1().StatefulSets(namespace).List(ctx, opts) //nolint:wrapcheck // kubernetes API errors are self-descriptive
+}
+
+// ListDaemonSets retrieves the DaemonSets in a namespace using the typed
+// clientset. If namespace is empty, the client's default namespace is used; if
+// that is also empty, DaemonSets across all namespaces are returned.
+//
+//nolint:gocritic // opts is from external package, can't change signature
+func (c *Client) ListDaemonSets(ctx context.Context, namespace string, opts metav1.ListOptions) (*appsv1.DaemonSetList, error) {
+	if namespace == "" && c.namespace != "" {
+		namespace = c.namespace
+	}
+
+	return c.clientset.AppsV1().DaemonSets(namespace).List(ctx, opts) //nolint:wrapcheck // kubernetes API errors are self-descriptive
+}
+
+// ListJobs retrieves the Jobs in a namespace using the typed clientset. If
+// namespace is empty, the client's default namespace is used; if that is also
+// empty, Jobs across all namespaces are returned.
+//
+//nolint:gocritic // opts is from external package, can't change signature
+func (c *Client) ListJobs(ctx context.Context, namespace string, opts metav1.ListOptions) (*batchv1.JobList, error) {
+	if namespace == "" && c.namespace != "" {
+		namespace = c.namespace
+	}
+
+	return c.clientset.BatchV1().Jobs(namespace).List(ctx, opts) //nolint:wrapcheck // kubernetes API errors are self-descriptive
+}
+
+// ListHorizontalPodAutoscalers retrieves the autoscaling/v2
+// HorizontalPodAutoscalers in a namespace using the typed clientset. If
+// namespace is empty, the client's default namespace is used; if that is also
+// empty, HorizontalPodAutoscalers across all namespaces are returned.
+//
+//nolint:gocritic // opts is from external package, can't change signature
+func (c *Client) ListHorizontalPodAutoscalers(ctx context.Context, namespace string, opts metav1.ListOptions) (*autoscalingv2.HorizontalPodAutoscalerList, error) {
+	if namespace == "" && c.namespace != "" {
+		namespace = c.namespace
+	}
+
+	return c.clientset.AutoscalingV2().HorizontalPodAutoscalers(namespace).List(ctx, opts) //nolint:wrapcheck // kubernetes API errors are self-descriptive
+}
+
+// ListPodDisruptionBudgets retrieves the policy/v1 PodDisruptionBudgets in a
+// namespace using the typed clientset. If namespace is empty, the client's
+// default namespace is used; if that is also empty, PodDisruptionBudgets
+// across all namespaces are returned.
+//
+//nolint:gocritic // opts is from external package, can't change signature
+func (c *Client) ListPodDisruptionBudgets(ctx context.Context, namespace string, opts metav1.ListOptions) (*policyv1.PodDisruptionBudgetList, error) {
+	if namespace == "" && c.namespace != "" {
+		namespace = c.namespace
+	}
+
+	return c.clientset.PolicyV1().PodDisruptionBudgets(namespace).List(ctx, opts) //nolint:wrapcheck // kubernetes API errors are self-descriptive
+}
@@ -0,0 +1,343 @@
+// Package metricshistory keeps a bounded, in-memory history of node and pod
+// metrics by polling the metrics-server on a fixed interval. Point-in-time
+// metrics hide spikes; the retained samples allow reporting recent trends
+// (min/max/avg) without requiring a full monitoring stack.
+package metricshistory
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+)
+
+// Kind identifies whether a series tracks a node or a pod.
+type Kind string
+
+const (
+	// KindNode identifies a node metrics series.
+	KindNode Kind = "node"
+
+	// KindPod identifies a pod metrics series.
+	KindPod Kind = "pod"
+)
+
+// Source provides the metrics snapshots the sampler polls. It is satisfied by
+// *kubernetes.Client.
+type Source interface {
+	GetNodeMetrics(ctx context.Context) (*metricsv1beta1.NodeMetricsList, error)
+	GetPodMetrics(ctx context.Context) (*metricsv1beta1.PodMetricsList, error)
+}
+
+// Sample is a single CPU and memory observation for a node or pod.
+type Sample struct {
+	// Timestamp is when the metrics-server collected the sample.
+	Timestamp time.Time `json:"timestamp"`
+
+	// CPUMillicores is the CPU usage in millicores.
+	CPUMillicores int64 `json:"cpu_millicores"`
+
+	// MemoryBytes is the memory usage in bytes.
+	MemoryBytes int64 `json:"memory_bytes"`
+}
+
+// Key identifies a single tracked node or pod.
+type Key struct {
+	Kind      Kind   `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+}
+
+// Sampler polls a Source on a fixed interval and retains up to size samples
+// per node and pod in ring buffers. It is safe for concurrent use.
+type Sampler struct {
+	source   Source
+	interval time.Duration
+	size     int
+
+	// now returns the current time. It defaults to time.Now and is replaced
+	// in tests to exercise retention without waiting.
+	now func() time.Time
+
+	mu         sync.RWMutex
+	series     map[Key]*ring
+	lastPoll   time.Time
+	lastErr    error
+	pollsTotal int
+}
+
+// NewSampler creates a Sampler that polls source every interval and keeps at
+// most size samples per node or pod. Call Run to start polling.
+func NewSampler(source Source, interval time.Duration, size int) (*Sampler, error) {
+	if interval <= 0 {
+		return nil, fmt.Errorf("metrics history interval must be positive, got %s", interval)
+	}
+
+	if size <= 0 {
+		return nil, fmt.Errorf("metrics history size must be positive, got %d", size)
+	}
+
+	return &Sampler{
+		source:   source,
+		interval: interval,
+		size:     size,
+		now:      time.Now,
+		series:   make(map[Key]*ring),
+	}, nil
+}
+
+// Interval returns the configured polling interval.
+func (s *Sampler) Interval() time.Duration {
+	return s.interval
+}
+
+// Size returns the maximum number of samples retained per node or pod.
+func (s *Sampler) Size() int {
+	return s.size
+}
+
+// Run polls the source immediately and then every interval until ctx is done.
+// Poll failures are recorded (see Status) and logged, but do not stop the loop
+// since the metrics-server may recover.
+func (s *Sampler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.Poll(ctx); err != nil && ctx.Err() == nil {
+			fmt.Fprintf(os.Stderr, "Metrics history sampling failed: %v\n", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll collects a single round of node and pod metrics and appends them to
+// the history. Series that have not received a sample within the retention
+// window are dropped so deleted pods do not accumulate forever.
+func (s *Sampler) Poll(ctx context.Context) error {
+	pollCtx, cancel := context.WithTimeout(ctx, s.interval)
+	defer cancel()
+
+	samples := make(map[Key]Sample)
+	var errs []error
+
+	if nodes, err := s.source.GetNodeMetrics(pollCtx); err != nil {
+		errs = append(errs, fmt.Errorf("failed to get node metrics: %w", err))
+	} else {
+		for i := range nodes.Items {
+			node := &nodes.Items[i]
+			samples[Key{Kind: KindNode, Name: node.Name}] = Sample{
+				Timestamp:     node.Timestamp.Time,
+				CPUMillicores: node.Usage.Cpu().MilliValue(),
+				MemoryBytes:   node.Usage.Memory().Value(),
+			}
+		}
+	}
+
+	if pods, err := s.source.GetPodMetrics(pollCtx); err != nil {
+		errs = append(errs, fmt.Errorf("failed to get pod metrics: %w", err))
+	} else {
+		for i := range pods.Items {
+			pod := &pods.Items[i]
+
+			var cpu, memory int64
+			for j := range pod.Containers {
+				cpu += pod.Containers[j].Usage.Cpu().MilliValue()
+				memory += pod.Containers[j].Usage.Memory().Value()
+			}
+
+			samples[Key{Kind: KindPod, Namespace: pod.Namespace, Name: pod.Name}] = Sample{
+				Timestamp:     pod.Timestamp.Time,
+				CPUMillicores: cpu,
+				MemoryBytes:   memory,
+			}
+		}
+	}
+
+	now := s.now()
+	retention := s.interval * time.Duration(s.size)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key, sample := range samples {
+		r, ok := s.series[key]
+		if !ok {
+			r = newRing(s.size)
+			s.series[key] = r
+		}
+		r.add(sample)
+	}
+
+	for key, r := range s.series {
+		if latest, ok := r.latest(); ok && now.Sub(latest.Timestamp) > retention {
+			delete(s.series, key)
+		}
+	}
+
+	s.lastPoll = now
+	s.pollsTotal++
+	s.lastErr = nil
+	if len(errs) > 0 {
+		s.lastErr = errs[0]
+		if len(errs) > 1 {
+			s.lastErr = fmt.Errorf("%w; %w", errs[0], errs[1])
+		}
+	}
+
+	return s.lastErr
+}
+
+// Status reports the sampler's polling state.
+type Status struct {
+	// Interval is the configured polling interval.
+	Interval string `json:"interval"`
+
+	// Size is the maximum number of samples retained per node or pod.
+	Size int `json:"size"`
+
+	// Polls is the number of completed polling rounds.
+	Polls int `json:"polls"`
+
+	// LastPoll is when the most recent polling round completed.
+	LastPoll *time.Time `json:"last_poll,omitempty"`
+
+	// LastError is the error from the most recent polling round, if any.
+	LastError string `json:"last_error,omitempty"`
+
+	// Series is the number of nodes and pods currently tracked.
+	Series int `json:"series"`
+}
+
+// Status returns a snapshot of the sampler's polling state.
+func (s *Sampler) Status() Status {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	status := Status{
+		Interval: s.interval.String(),
+		Size:     s.size,
+		Polls:    s.pollsTotal,
+		Series:   len(s.series),
+	}
+
+	if !s.lastPoll.IsZero() {
+		lastPoll := s.lastPoll
+		status.LastPoll = &lastPoll
+	}
+
+	if s.lastErr != nil {
+		status.LastError = s.lastErr.Error()
+	}
+
+	return status
+}
+
+// Filter selects which series History returns. Empty fields match everything.
+type Filter struct {
+	Kind      Kind
+	Namespace string
+	Name      string
+}
+
+func (f Filter) matches(key Key) bool {
+	if f.Kind != "" && f.Kind != key.Kind {
+		return false
+	}
+	if f.Namespace != "" && f.Namespace != key.Namespace {
+		return false
+	}
+	if f.Name != "" && f.Name != key.Name {
+		return false
+	}
+	return true
+}
+
+// Series is the retained history for a single node or pod, with its samples
+// ordered oldest first.
+type Series struct {
+	Key
+	Samples []Sample
+}
+
+// History returns the retained series matching filter, sorted by kind,
+// namespace, and name.
+func (s *Sampler) History(filter Filter) []Series {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var result []Series
+	for key, r := range s.series {
+		if !filter.matches(key) {
+			continue
+		}
+		result = append(result, Series{Key: key, Samples: r.samples()})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Kind != result[j].Kind {
+			return result[i].Kind < result[j].Kind
+		}
+		if result[i].Namespace != result[j].Namespace {
+			return result[i].Namespace < result[j].Namespace
+		}
+		return result[i].Name < result[j].Name
+	})
+
+	return result
+}
+
+// ring is a fixed-capacity circular buffer of samples. Once full, adding a
+// sample overwrites the oldest one.
+type ring struct {
+	buf   []Sample
+	start int
+	count int
+}
+
+func newRing(size int) *ring {
+	return &ring{buf: make([]Sample, size)}
+}
+
+// add appends a sample, skipping it when it repeats the latest timestamp: the
+// metrics-server only refreshes its values every scrape window, so polling
+// faster than that would otherwise record duplicates.
+func (r *ring) add(sample Sample) {
+	if latest, ok := r.latest(); ok && latest.Timestamp.Equal(sample.Timestamp) {
+		return
+	}
+
+	if r.count < len(r.buf) {
+		r.buf[(r.start+r.count)%len(r.buf)] = sample
+		r.count++
+		return
+	}
+
+	r.buf[r.start] = sample
+	r.start = (r.start + 1) % len(r.buf)
+}
+
+func (r *ring) latest() (Sample, bool) {
+	if r.count == 0 {
+		return Sample{}, false
+	}
+	return r.buf[(r.start+r.count-1)%len(r.buf)], true
+}
+
+// samples returns a copy of the buffered samples, oldest first.
+func (r *ring) samples() []Sample {
+	out := make([]Sample, r.count)
+	for i := range r.count {
+		out[i] = r.buf[(r.start+i)%len(r.buf)]
+	}
+	return out
+}
@@ -0,0 +1,213 @@
+package metricshistory
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+)
+
+// fakeSource returns one node and one pod sample per poll, with usage values
+// taken from the cpu and memory slices in order.
+type fakeSource struct {
+	calls   int
+	start   time.Time
+	cpu     []string
+	memory  []string
+	podErr  error
+	nodeErr error
+}
+
+func (f *fakeSource) usage() corev1.ResourceList {
+	return corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse(f.cpu[f.calls%len(f.cpu)]),
+		corev1.ResourceMemory: resource.MustParse(f.memory[f.calls%len(f.memory)]),
+	}
+}
+
+func (f *fakeSource) timestamp() metav1.Time {
+	return metav1.NewTime(f.start.Add(time.Duration(f.calls) * time.Second))
+}
+
+func (f *fakeSource) GetNodeMetrics(_ context.Context) (*metricsv1beta1.NodeMetricsList, error) {
+	if f.nodeErr != nil {
+		return nil, f.nodeErr
+	}
+	return &metricsv1beta1.NodeMetricsList{Items: []metricsv1beta1.NodeMetrics{{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+		Timestamp:  f.timestamp(),
+		Usage:      f.usage(),
+	}}}, nil
+}
+
+func (f *fakeSource) GetPodMetrics(_ context.Context) (*metricsv1beta1.PodMetricsList, error) {
+	defer func() { f.calls++ }()
+	if f.podErr != nil {
+		return nil, f.podErr
+	}
+	return &metricsv1beta1.PodMetricsList{Items: []metricsv1beta1.PodMetrics{{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Timestamp:  f.timestamp(),
+		Containers: []metricsv1beta1.ContainerMetrics{
+			{Name: "app", Usage: f.usage()},
+			{Name: "sidecar", Usage: f.usage()},
+		},
+	}}}, nil
+}
+
+func TestSamplerRingBufferIsBounded(t *testing.T) {
+	source := &fakeSource{
+		start:  time.Now(),
+		cpu:    []string{"100m", "300m", "200m", "500m"},
+		memory: []string{"1Mi", "2Mi", "3Mi", "4Mi"},
+	}
+
+	sampler, err := NewSampler(source, time.Minute, 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for range 4 {
+		if err := sampler.Poll(context.Background()); err != nil {
+			t.Fatalf("poll failed: %v", err)
+		}
+	}
+
+	history := sampler.History(Filter{Kind: KindNode})
+	if len(history) != 1 {
+		t.Fatalf("expected 1 node series, got %d", len(history))
+	}
+
+	samples := history[0].Samples
+	if len(samples) != 3 {
+		t.Fatalf("expected 3 retained samples, got %d", len(samples))
+	}
+
+	// The oldest sample (100m) must have been evicted.
+	wantCPU := []int64{300, 200, 500}
+	for i, want := range wantCPU {
+		if samples[i].CPUMillicores != want {
+			t.Fatalf("sample %d: expected %dm CPU, got %dm", i, want, samples[i].CPUMillicores)
+		}
+	}
+
+	summary, ok := Summarize(samples)
+	if !ok {
+		t.Fatal("expected summary for non-empty samples")
+	}
+
+	want := Stats{Min: 200, Max: 500, Avg: 333, Latest: 500, Delta: 200, Samples: 3}
+	if summary.CPUMillicores != want {
+		t.Fatalf("cpu stats mismatch\nwant: %#v\ngot:  %#v", want, summary.CPUMillicores)
+	}
+}
+
+func TestSamplerSumsPodContainers(t *testing.T) {
+	source := &fakeSource{start: time.Now(), cpu: []string{"150m"}, memory: []string{"64Mi"}}
+
+	sampler, err := NewSampler(source, time.Minute, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := sampler.Poll(context.Background()); err != nil {
+		t.Fatalf("poll failed: %v", err)
+	}
+
+	history := sampler.History(Filter{Kind: KindPod, Namespace: "default", Name: "web"})
+	if len(history) != 1 || len(history[0].Samples) != 1 {
+		t.Fatalf("expected a single pod sample, got %#v", history)
+	}
+
+	sample := history[0].Samples[0]
+	if sample.CPUMillicores != 300 || sample.MemoryBytes != 128*1024*1024 {
+		t.Fatalf("expected container usage to be summed, got %#v", sample)
+	}
+}
+
+func TestSamplerSkipsDuplicateTimestamps(t *testing.T) {
+	r := newRing(5)
+	now := time.Now()
+
+	r.add(Sample{Timestamp: now, CPUMillicores: 1})
+	r.add(Sample{Timestamp: now, CPUMillicores: 2})
+	r.add(Sample{Timestamp: now.Add(time.Second), CPUMillicores: 3})
+
+	samples := r.samples()
+	if len(samples) != 2 || samples[0].CPUMillicores != 1 || samples[1].CPUMillicores != 3 {
+		t.Fatalf("unexpected samples: %#v", samples)
+	}
+}
+
+func TestSamplerRecordsErrors(t *testing.T) {
+	source := &fakeSource{
+		start:  time.Now(),
+		cpu:    []string{"1m"},
+		memory: []string{"1Mi"},
+		podErr: errors.New("metrics.k8s.io unavailable"),
+	}
+
+	sampler, err := NewSampler(source, time.Minute, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := sampler.Poll(context.Background()); err == nil {
+		t.Fatal("expected poll error")
+	}
+
+	status := sampler.Status()
+	if status.LastError == "" || status.Polls != 1 || status.Series != 1 {
+		t.Fatalf("unexpected status: %#v", status)
+	}
+}
+
+func TestSamplerPrunesStaleSeries(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	source := &fakeSource{start: start, cpu: []string{"1m"}, memory: []string{"1Mi"}}
+
+	sampler, err := NewSampler(source, time.Minute, 5)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	now := start
+	sampler.now = func() time.Time { return now }
+
+	if err := sampler.Poll(context.Background()); err != nil {
+		t.Fatalf("poll failed: %v", err)
+	}
+
+	// Move past the 5 minute retention window. The node keeps reporting but
+	// the pod disappears, so only the pod series must be dropped.
+	now = start.Add(10 * time.Minute)
+	source.start = now
+	source.podErr = errors.New("metrics.k8s.io unavailable")
+
+	if err := sampler.Poll(context.Background()); err == nil {
+		t.Fatal("expected poll error")
+	}
+
+	if got := sampler.History(Filter{Kind: KindPod}); len(got) != 0 {
+		t.Fatalf("expected stale pod series to be pruned, got %#v", got)
+	}
+
+	if got := sampler.History(Filter{Kind: KindNode}); len(got) != 1 {
+		t.Fatalf("expected node series to be retained, got %#v", got)
+	}
+}
+
+func TestNewSamplerValidation(t *testing.T) {
+	if _, err := NewSampler(&fakeSource{}, 0, 10); err == nil {
+		t.Fatal("expected error for zero interval")
+	}
+
+	if _, err := NewSampler(&fakeSource{}, time.Second, 0); err == nil {
+		t.Fatal("expected error for zero size")
+	}
+}
@@ -0,0 +1,71 @@
+package metricshistory
+
+import "time"
+
+// Stats summarizes a set of values observed over time.
+type Stats struct {
+	Min     int64 `json:"min"`
+	Max     int64 `json:"max"`
+	Avg     int64 `json:"avg"`
+	Latest  int64 `json:"latest"`
+	Delta   int64 `json:"delta"`
+	Samples int   `json:"samples"`
+}
+
+// Summary reports CPU and memory trends over the window covered by a series.
+type Summary struct {
+	// From is the timestamp of the oldest sample in the window.
+	From time.Time `json:"from"`
+
+	// To is the timestamp of the newest sample in the window.
+	To time.Time `json:"to"`
+
+	// CPUMillicores summarizes CPU usage in millicores.
+	CPUMillicores Stats `json:"cpu_millicores"`
+
+	// MemoryBytes summarizes memory usage in bytes.
+	MemoryBytes Stats `json:"memory_bytes"`
+}
+
+// Summarize computes min/max/avg/latest and the first-to-last delta for the
+// given samples, which must be ordered oldest first. It returns false when
+// samples is empty.
+func Summarize(samples []Sample) (Summary, bool) {
+	if len(samples) == 0 {
+		return Summary{}, false
+	}
+
+	cpu := make([]int64, len(samples))
+	memory := make([]int64, len(samples))
+	for i, sample := range samples {
+		cpu[i] = sample.CPUMillicores
+		memory[i] = sample.MemoryBytes
+	}
+
+	return Summary{
+		From:          samples[0].Timestamp,
+		To:            samples[len(samples)-1].Timestamp,
+		CPUMillicores: computeStats(cpu),
+		MemoryBytes:   computeStats(memory),
+	}, true
+}
+
+func computeStats(values []int64) Stats {
+	stats := Stats{
+		Min:     values[0],
+		Max:     values[0],
+		Latest:  values[len(values)-1],
+		Delta:   values[len(values)-1] - values[0],
+		Samples: len(values),
+	}
+
+	var sum int64
+	for _, v := range values {
+		sum += v
+		stats.Min = min(stats.Min, v)
+		stats.Max = max(stats.Max, v)
+	}
+	stats.Avg = sum / int64(len(values))
+
+	return stats
+}
@@ -0,0 +1,288 @@
+// Package objectresources exposes cluster objects as MCP resources, so
+// clients can browse them and attach them to a conversation instead of only
+// reading them through tool calls. Objects are addressed with URIs such as
+// k8s://prod/shop/deployments/web, and clients can subscribe to them to be
+// told when they change.
+package objectresources
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/connectivity"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/kubernetes"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/resourcefilter"
+)
+
+const (
+	// Scheme is the URI scheme of cluster object resources.
+	Scheme = "k8s"
+
+	// Unset stands for the current kubeconfig context in the context part of
+	// a URI, and for no namespace in the namespace part, as used by
+	// cluster-scoped objects.
+	Unset = "_"
+
+	// ObjectTemplate addresses a single object.
+	ObjectTemplate = "k8s://{context}/{namespace}/{type}/{name}"
+
+	// CollectionTemplate addresses every object of a type in a namespace.
+	CollectionTemplate = "k8s://{context}/{namespace}/{type}"
+
+	mimeType = "application/json"
+)
+
+// Address identifies the object or collection of objects a URI points to.
+type Address struct {
+	// Context is the kubeconfig context, empty for the current one.
+	Context string
+
+	// Namespace is the namespace, empty for cluster-scoped objects.
+	Namespace string
+
+	// Type is the resource type, resolved like the resource_type argument
+	// of the list_resources and get_resource tools.
+	Type string
+
+	// Name is the object name, empty for a collection.
+	Name string
+}
+
+// URI returns the resource URI of a.
+func (a Address) URI() string {
+	parts := []string{orUnset(a.Context), orUnset(a.Namespace), a.Type}
+	if a.Name != "" {
+		parts = append(parts, a.Name)
+	}
+
+	for i, part := range parts {
+		parts[i] = escape(part)
+	}
+	return Scheme + "://" + strings.Join(parts, "/")
+}
+
+// escape percent-encodes every byte of part but the unreserved characters
+// of RFC 3986, the only ones URI template variables match unencoded. Context
+// names such as EKS cluster ARNs contain colons and slashes.
+func escape(part string) string {
+	var b strings.Builder
+	for i := range len(part) {
+		c := part[i]
+		if ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9') || strings.IndexByte("-._~", c) >= 0 {
+			b.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", c)
+	}
+	return b.String()
+}
+
+func orUnset(value string) string {
+	if value == "" {
+		return Unset
+	}
+	return value
+}
+
+// ParseURI parses a resource URI into the address it points to.
+func ParseURI(uri string) (Address, error) {
+	rest, ok := strings.CutPrefix(uri, Scheme+"://")
+	if !ok {
+		return Address{}, fmt.Errorf("resource URI %q must start with %s://", uri, Scheme)
+	}
+
+	parts := strings.Split(rest, "/")
+	if len(parts) != 3 && len(parts) != 4 {
+		return Address{}, fmt.Errorf("resource URI %q must look like %s or %s", uri, ObjectTemplate, CollectionTemplate)
+	}
+
+	for i, part := range parts {
+		unescaped, err := url.PathUnescape(part)
+		if err != nil {
+			return Address{}, fmt.Errorf("resource URI %q is not properly escaped: %w", uri, err)
+		}
+		if unescaped == "" {
+			return Address{}, fmt.Errorf("resource URI %q has an empty part: use %q for the current context or no namespace", uri, Unset)
+		}
+		parts[i] = unescaped
+	}
+
+	address := Address{Context: parts[0], Namespace: parts[1], Type: parts[2]}
+	if address.Context == Unset {
+		address.Context = ""
+	}
+	if address.Namespace == Unset {
+		address.Namespace = ""
+	}
+	if len(parts) == 4 {
+		address.Name = parts[3]
+	}
+	return address, nil
+}
+
+// Server reads cluster objects for MCP resource requests, and tracks the
+// subscriptions to them.
+type Server struct {
+	client         kubernetes.ClusterReader
+	resourceFilter *resourcefilter.Filter
+	alwaysStart    bool
+
+	subscriptions *subscriptions
+}
+
+// New creates a Server that reads objects through client. Resource types
+// disabled by filter, which may be nil, cannot be read. alwaysStart mirrors
+// the --always-start flag, reporting connectivity errors with guidance for the
+// user the way tools do.
+func New(client kubernetes.ClusterReader, filter *resourcefilter.Filter, alwaysStart bool) *Server {
+	return &Server{
+		client:         client,
+		resourceFilter: filter,
+		alwaysStart:    alwaysStart,
+		subscriptions:  newSubscriptions(),
+	}
+}
+
+// Templates returns the resource templates to register with the MCP server.
+func (s *Server) Templates() []server.ServerResourceTemplate {
+	return []server.ServerResourceTemplate{
+		{
+			Template: mcp.NewResourceTemplate(ObjectTemplate, "Kubernetes object",
+				mcp.WithTemplateDescription("A single Kubernetes object as JSON, without managed fields. Use _ as the context for the current kubeconfig context, and as the namespace for cluster-scoped objects. The type accepts the same names as the resource_type argument of get_resource, such as pods, deploy, or Ingress"),
+				mcp.WithTemplateMIMEType(mimeType),
+			),
+			Handler: s.Read,
+		},
+		{
+			Template: mcp.NewResourceTemplate(CollectionTemplate, "Kubernetes objects of a type",
+				mcp.WithTemplateDescription("The names and resource URIs of every object of a type in a namespace, to browse them. Use _ as the context for the current kubeconfig context, and as the namespace for cluster-scoped types or the server's default namespace"),
+				mcp.WithTemplateMIMEType(mimeType),
+			),
+			Handler: s.Read,
+		},
+	}
+}
+
+// Read serves a resources/read request for an object or a collection URI.
+func (s *Server) Read(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	address, err := ParseURI(request.Params.URI)
+	if err != nil {
+		return nil, err
+	}
+
+	var document any
+	if address.Name != "" {
+		document, err = s.readObject(ctx, address)
+	} else {
+		document, err = s.readCollection(ctx, address)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	contents, err := json.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s: %w", request.Params.URI, err)
+	}
+
+	return []mcp.ResourceContents{
+		mcp.TextResourceContents{URI: request.Params.URI, MIMEType: mimeType, Text: string(contents)},
+	}, nil
+}
+
+// readObject returns the object at address, without its managed fields.
+func (s *Server) readObject(ctx context.Context, address Address) (map[string]any, error) {
+	client, gvr, err := s.resolve(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+
+	object, err := client.GetResource(ctx, gvr, address.Namespace, address.Name)
+	if err != nil {
+		return nil, s.clusterError("failed to get resource", err)
+	}
+
+	unstructured.RemoveNestedField(object.Object, "metadata", "managedFields")
+	return object.Object, nil
+}
+
+// collectionItem is an object in a collection listing.
+type collectionItem struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+	URI       string `json:"uri"`
+}
+
+// readCollection lists the names and URIs of the objects at address.
+func (s *Server) readCollection(ctx context.Context, address Address) (map[string]any, error) {
+	client, gvr, err := s.resolve(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := client.ListResourceMetadata(ctx, gvr, address.Namespace, metav1.ListOptions{})
+	if err != nil {
+		return nil, s.clusterError("failed to list resources", err)
+	}
+
+	items := make([]collectionItem, 0, len(list.Items))
+	for i := range list.Items {
+		item := &list.Items[i]
+		items = append(items, collectionItem{
+			Name:      item.Name,
+			Namespace: item.Namespace,
+			URI:       Address{Context: address.Context, Namespace: item.Namespace, Type: address.Type, Name: item.Name}.URI(),
+		})
+	}
+
+	return map[string]any{
+		"resource": gvr.String(),
+		"count":    len(items),
+		"items":    items,
+	}, nil
+}
+
+// resolve returns the client for the context of address and the resource its
+// type names, refusing types disabled by configuration.
+//
+//nolint:ireturn // the reader interface keeps the server backend-agnostic
+func (s *Server) resolve(ctx context.Context, address Address) (kubernetes.ClusterReader, schema.GroupVersionResource, error) {
+	client, err := s.client.ForContext(ctx, address.Context)
+	if err != nil {
+		return nil, schema.GroupVersionResource{}, s.clusterError(fmt.Sprintf("failed to create client with context %s", address.Context), err)
+	}
+
+	gvr, err := client.ResolveResourceType(address.Type, "")
+	if err != nil {
+		return nil, schema.GroupVersionResource{}, s.clusterError("failed to resolve resource type", err)
+	}
+
+	if s.resourceFilter != nil && s.resourceFilter.IsDisabled(gvr) {
+		if initErr := s.resourceFilter.InitError(); initErr != nil {
+			return nil, schema.GroupVersionResource{}, s.clusterError("resource filter could not be initialized", initErr)
+		}
+		return nil, schema.GroupVersionResource{}, fmt.Errorf("access to resource %q (%s) is disabled by configuration and cannot be queried",
+			address.Type, resourcefilter.FormatGVR(gvr))
+	}
+
+	return client, gvr, nil
+}
+
+// clusterError describes err, returned while doing what, the way tools
+// report it.
+func (s *Server) clusterError(what string, err error) error {
+	if s.alwaysStart && connectivity.IsError(err) {
+		return errors.New(connectivity.ErrorMessage(err))
+	}
+	return fmt.Errorf("%s: %w", what, err)
+}
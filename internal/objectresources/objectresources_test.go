@@ -0,0 +1,229 @@
+package objectresources
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/fakecluster"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/kubernetes"
+)
+
+func TestAddressURI(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		address Address
+		uri     string
+	}{
+		{Address{Context: "prod", Namespace: "shop", Type: "deployments", Name: "web"}, "k8s://prod/shop/deployments/web"},
+		{Address{Type: "nodes", Name: "worker-1"}, "k8s://_/_/nodes/worker-1"},
+		{Address{Namespace: "shop", Type: "pods"}, "k8s://_/shop/pods"},
+		{
+			Address{Context: "arn:aws:eks:us-east-1:123456789012:cluster/prod", Namespace: "shop", Type: "pods", Name: "web-1"},
+			"k8s://arn%3Aaws%3Aeks%3Aus-east-1%3A123456789012%3Acluster%2Fprod/shop/pods/web-1",
+		},
+	}
+
+	for _, tt := range tests {
+		if got := tt.address.URI(); got != tt.uri {
+			t.Errorf("expected %+v to have URI %q, got %q", tt.address, tt.uri, got)
+		}
+
+		parsed, err := ParseURI(tt.uri)
+		if err != nil {
+			t.Errorf("failed to parse %q: %v", tt.uri, err)
+			continue
+		}
+		if parsed != tt.address {
+			t.Errorf("expected %q to parse into %+v, got %+v", tt.uri, tt.address, parsed)
+		}
+
+		template := mcp.NewResourceTemplate(ObjectTemplate, "object")
+		if tt.address.Name == "" {
+			template = mcp.NewResourceTemplate(CollectionTemplate, "collection")
+		}
+		if !template.URITemplate.Regexp().MatchString(tt.uri) {
+			t.Errorf("expected %q to match its resource template", tt.uri)
+		}
+	}
+
+	for _, uri := range []string{"https://prod/shop/pods/web", "k8s://prod/shop", "k8s://prod/shop/pods/web/logs", "k8s://prod//pods/web", "k8s://prod/shop/pods/%zz"} {
+		if _, err := ParseURI(uri); err == nil {
+			t.Errorf("expected %q to be rejected", uri)
+		}
+	}
+}
+
+func testCluster() *kubernetes.Client {
+	return fakecluster.New(fakecluster.Config{
+		Objects: []runtime.Object{
+			&corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+				Name: "web-1", Namespace: "shop", ResourceVersion: "10",
+				ManagedFields: []metav1.ManagedFieldsEntry{{Manager: "kubectl"}},
+			}},
+			&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web-2", Namespace: "shop", ResourceVersion: "11"}},
+			&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "db-1", Namespace: "billing", ResourceVersion: "12"}},
+		},
+	})
+}
+
+func read(t *testing.T, s *Server, uri string) map[string]any {
+	t.Helper()
+
+	request := mcp.ReadResourceRequest{}
+	request.Params.URI = uri
+
+	contents, err := s.Read(context.Background(), request)
+	if err != nil {
+		t.Fatalf("failed to read %s: %v", uri, err)
+	}
+	if len(contents) != 1 {
+		t.Fatalf("expected one content, got %d", len(contents))
+	}
+	text, ok := contents[0].(mcp.TextResourceContents)
+	if !ok || text.URI != uri || text.MIMEType != "application/json" {
+		t.Fatalf("unexpected content %+v", contents[0])
+	}
+
+	var document map[string]any
+	if err := json.Unmarshal([]byte(text.Text), &document); err != nil {
+		t.Fatalf("failed to decode %s: %v", uri, err)
+	}
+	return document
+}
+
+func TestRead(t *testing.T) {
+	t.Parallel()
+
+	s := New(testCluster(), nil, false)
+
+	pod := read(t, s, "k8s://_/shop/pods/web-1")
+	metadata, _ := pod["metadata"].(map[string]any)
+	if metadata["name"] != "web-1" {
+		t.Errorf("expected the web-1 pod, got %v", metadata)
+	}
+	if _, ok := metadata["managedFields"]; ok {
+		t.Error("expected managed fields to be dropped")
+	}
+
+	collection := read(t, s, "k8s://_/shop/po")
+	items, _ := collection["items"].([]any)
+	if collection["count"] != float64(2) || len(items) != 2 {
+		t.Fatalf("expected the two pods in shop, got %v", collection)
+	}
+	for _, item := range items {
+		uri, _ := item.(map[string]any)["uri"].(string)
+		if !strings.HasPrefix(uri, "k8s://_/shop/po/web-") {
+			t.Errorf("expected item URIs to point at the pods, got %q", uri)
+		}
+	}
+
+	request := mcp.ReadResourceRequest{}
+	request.Params.URI = "k8s://_/shop/pods/missing"
+	if _, err := s.Read(context.Background(), request); err == nil {
+		t.Error("expected reading a missing object to fail")
+	}
+}
+
+// changingReader serves the objects of a fake cluster, but reports the
+// resource version set with setVersion for the web-1 pod.
+type changingReader struct {
+	kubernetes.ClusterReader
+
+	mu      sync.Mutex
+	version string
+}
+
+//nolint:ireturn // satisfies kubernetes.ClusterReader
+func (r *changingReader) ForContext(context.Context, string) (kubernetes.ClusterReader, error) {
+	return r, nil
+}
+
+func (r *changingReader) GetResource(ctx context.Context, gvr schema.GroupVersionResource, namespace, name string) (*unstructured.Unstructured, error) {
+	object, err := r.ClusterReader.GetResource(ctx, gvr, namespace, name)
+	if err != nil || name != "web-1" {
+		return object, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.version == deletedVersion {
+		return nil, apierrors.NewNotFound(gvr.GroupResource(), name)
+	}
+	object.SetResourceVersion(r.version)
+	return object, nil
+}
+
+func (r *changingReader) setVersion(version string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.version = version
+}
+
+func TestSubscriptions(t *testing.T) {
+	t.Parallel()
+
+	reader := &changingReader{ClusterReader: testCluster(), version: "10"}
+	s := New(reader, nil, false)
+	ctx := context.Background()
+
+	var notified []string
+	notify := func(sessionID, uri string) {
+		notified = append(notified, sessionID+" "+uri)
+	}
+
+	const uri = "k8s://_/shop/pods/web-1"
+	for _, sessionID := range []string{"a", "b"} {
+		if err := s.Subscribe(ctx, sessionID, uri); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.Subscribe(ctx, "a", "k8s://_/shop/pods"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Subscribe(ctx, "a", "not a uri"); err == nil {
+		t.Error("expected subscribing to an invalid URI to fail")
+	}
+
+	s.Poll(ctx, notify)
+	if len(notified) != 0 {
+		t.Fatalf("expected no notifications without changes, got %v", notified)
+	}
+
+	reader.setVersion("20")
+	s.Poll(ctx, notify)
+	if strings.Join(notified, ",") != "a "+uri+",b "+uri {
+		t.Fatalf("expected both sessions to be notified once, got %v", notified)
+	}
+
+	notified = nil
+	s.Unsubscribe("a", uri)
+	s.Forget("b")
+	reader.setVersion(deletedVersion)
+	s.Poll(ctx, notify)
+	if len(notified) != 0 {
+		t.Fatalf("expected no notifications after unsubscribing, got %v", notified)
+	}
+
+	if err := s.Subscribe(ctx, "c", uri); err != nil {
+		t.Fatal(err)
+	}
+	reader.setVersion("30")
+	s.Poll(ctx, notify)
+	if strings.Join(notified, ",") != "c "+uri {
+		t.Fatalf("expected a recreated object to notify, got %v", notified)
+	}
+}
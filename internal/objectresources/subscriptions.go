@@ -0,0 +1,209 @@
+package objectresources
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Notifier tells the session sessionID that the resource at uri changed.
+type Notifier func(sessionID, uri string)
+
+// subscriptions tracks which sessions subscribed to which resource URIs, and
+// the version of each resource last seen.
+type subscriptions struct {
+	mu    sync.Mutex
+	byURI map[string]*watched
+}
+
+// watched is a subscribed resource URI.
+type watched struct {
+	sessions map[string]bool
+
+	// version identifies the state of the resource when last polled, and is
+	// empty until a poll succeeds.
+	version string
+}
+
+func newSubscriptions() *subscriptions {
+	return &subscriptions{byURI: make(map[string]*watched)}
+}
+
+// Subscribe records that the session sessionID wants to be told when the
+// resource at uri changes. The resource's current version is read right away,
+// so changes made before the next poll are not missed.
+func (s *Server) Subscribe(ctx context.Context, sessionID, uri string) error {
+	address, err := ParseURI(uri)
+	if err != nil {
+		return err
+	}
+
+	s.subscriptions.mu.Lock()
+	entry, ok := s.subscriptions.byURI[uri]
+	if ok {
+		entry.sessions[sessionID] = true
+	} else {
+		entry = &watched{sessions: map[string]bool{sessionID: true}}
+		s.subscriptions.byURI[uri] = entry
+	}
+	s.subscriptions.mu.Unlock()
+
+	if ok {
+		return nil
+	}
+
+	// A failed read leaves the version empty, and the next successful poll
+	// records it.
+	if version, err := s.version(ctx, address); err == nil {
+		s.subscriptions.mu.Lock()
+		if entry.version == "" {
+			entry.version = version
+		}
+		s.subscriptions.mu.Unlock()
+	}
+	return nil
+}
+
+// Unsubscribe drops the subscription of the session sessionID to uri.
+func (s *Server) Unsubscribe(sessionID, uri string) {
+	s.subscriptions.mu.Lock()
+	defer s.subscriptions.mu.Unlock()
+
+	if entry, ok := s.subscriptions.byURI[uri]; ok {
+		delete(entry.sessions, sessionID)
+		if len(entry.sessions) == 0 {
+			delete(s.subscriptions.byURI, uri)
+		}
+	}
+}
+
+// Forget drops every subscription of the session sessionID, once it ends.
+func (s *Server) Forget(sessionID string) {
+	s.subscriptions.mu.Lock()
+	defer s.subscriptions.mu.Unlock()
+
+	for uri, entry := range s.subscriptions.byURI {
+		delete(entry.sessions, sessionID)
+		if len(entry.sessions) == 0 {
+			delete(s.subscriptions.byURI, uri)
+		}
+	}
+}
+
+// Run polls the subscribed resources every interval until ctx is done, and
+// calls notify for every session subscribed to a resource that changed.
+func (s *Server) Run(ctx context.Context, interval time.Duration, notify Notifier) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		s.Poll(ctx, notify)
+	}
+}
+
+// Poll reads the version of every subscribed resource once, and calls notify
+// for every session subscribed to a resource whose version changed since the
+// last poll. Resources that cannot be read are skipped until a later poll.
+func (s *Server) Poll(ctx context.Context, notify Notifier) {
+	s.subscriptions.mu.Lock()
+	uris := make([]string, 0, len(s.subscriptions.byURI))
+	for uri := range s.subscriptions.byURI {
+		uris = append(uris, uri)
+	}
+	s.subscriptions.mu.Unlock()
+	sort.Strings(uris)
+
+	for _, uri := range uris {
+		if ctx.Err() != nil {
+			return
+		}
+
+		address, err := ParseURI(uri)
+		if err != nil {
+			continue
+		}
+
+		version, err := s.version(ctx, address)
+		if err != nil {
+			if ctx.Err() == nil {
+				fmt.Fprintf(os.Stderr, "Checking subscribed resource %s for changes failed: %v\n", uri, err)
+			}
+			continue
+		}
+
+		s.subscriptions.mu.Lock()
+		entry, ok := s.subscriptions.byURI[uri]
+		var sessions []string
+		if ok {
+			if entry.version != "" && entry.version != version {
+				for sessionID := range entry.sessions {
+					sessions = append(sessions, sessionID)
+				}
+			}
+			entry.version = version
+		}
+		s.subscriptions.mu.Unlock()
+
+		sort.Strings(sessions)
+		for _, sessionID := range sessions {
+			notify(sessionID, uri)
+		}
+	}
+}
+
+// deletedVersion is the version of an object that does not exist.
+const deletedVersion = "deleted"
+
+// version returns a value that changes whenever the resource at address
+// does: the resource version of an object, or a digest of the names and
+// resource versions of the objects in a collection.
+func (s *Server) version(ctx context.Context, address Address) (string, error) {
+	client, gvr, err := s.resolve(ctx, address)
+	if err != nil {
+		return "", err
+	}
+
+	if address.Name != "" {
+		object, err := client.GetResource(ctx, gvr, address.Namespace, address.Name)
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				return deletedVersion, nil
+			}
+			return "", s.clusterError("failed to get resource", err)
+		}
+		return object.GetResourceVersion(), nil
+	}
+
+	list, err := client.ListResourceMetadata(ctx, gvr, address.Namespace, metav1.ListOptions{})
+	if err != nil {
+		return "", s.clusterError("failed to list resources", err)
+	}
+
+	entries := make([]string, 0, len(list.Items))
+	for i := range list.Items {
+		item := &list.Items[i]
+		entries = append(entries, item.Namespace+"/"+item.Name+"@"+item.ResourceVersion)
+	}
+	sort.Strings(entries)
+
+	digest := sha256.New()
+	for _, entry := range entries {
+		digest.Write([]byte(entry))
+		digest.Write([]byte{0})
+	}
+	return hex.EncodeToString(digest.Sum(nil)), nil
+}
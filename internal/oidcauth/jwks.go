@@ -0,0 +1,233 @@
+package oidcauth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+)
+
+const (
+	// keysMaxAge is how long fetched keys are used before they are fetched
+	// again, so rotated keys are picked up even when tokens keep using a
+	// known key ID.
+	keysMaxAge = time.Hour
+
+	// minRefreshInterval bounds how often an unknown key ID triggers a
+	// fetch, so tokens with made-up key IDs cannot flood the provider.
+	minRefreshInterval = time.Minute
+
+	// failedRefreshInterval bounds how often the keys are fetched again
+	// after a fetch failed, so a provider that is down is not asked on
+	// every request.
+	failedRefreshInterval = 10 * time.Second
+
+	// fetchTimeout bounds a single fetch of the discovery document and the
+	// keys.
+	fetchTimeout = 10 * time.Second
+
+	// maxDocumentSize bounds the discovery document and key set read from
+	// the provider.
+	maxDocumentSize = 1 << 20
+)
+
+// keySet fetches and caches the provider's signing keys. Fetches run outside
+// its lock and are shared by the lookups that need them.
+type keySet struct {
+	client    *http.Client
+	issuer    string
+	discovery bool
+
+	mu          sync.Mutex
+	jwksURL     string
+	keys        []jose.JSONWebKey
+	fetchedAt   time.Time
+	attemptedAt time.Time
+	lastErr     error
+	fetching    *keyFetch
+	now         func() time.Time
+}
+
+// keyFetch is a fetch of the keys in flight.
+type keyFetch struct {
+	done chan struct{}
+	err  error
+}
+
+// lookup returns the keys matching keyID, or every key when keyID is empty.
+// It fetches the keys when they are missing or old, and again when keyID is
+// unknown, at most once per minRefreshInterval, or per failedRefreshInterval
+// after a failed fetch. When a fetch fails, the keys fetched before keep
+// being used.
+func (s *keySet) lookup(ctx context.Context, keyID string) ([]jose.JSONWebKey, error) {
+	s.mu.Lock()
+	found := s.matching(keyID)
+	aged := s.keys == nil || s.now().Sub(s.fetchedAt) > keysMaxAge
+	if (aged || len(found) == 0) && s.mayFetchLocked() {
+		fetch := s.startFetchLocked()
+		s.mu.Unlock()
+
+		select {
+		case <-fetch.done:
+		case <-ctx.Done():
+			return nil, ctx.Err() //nolint:wrapcheck // cancellation is reported as is
+		}
+
+		s.mu.Lock()
+		found = s.matching(keyID)
+	}
+	lastErr := s.lastErr
+	s.mu.Unlock()
+
+	if len(found) > 0 {
+		return found, nil
+	}
+	if lastErr != nil {
+		return nil, lastErr
+	}
+	return nil, fmt.Errorf("no signing key with ID %q is published by the issuer", keyID)
+}
+
+// mayFetchLocked reports whether a lookup may fetch the keys, or join the
+// fetch in flight. The caller must hold s.mu.
+func (s *keySet) mayFetchLocked() bool {
+	if s.fetching != nil || s.attemptedAt.IsZero() {
+		return true
+	}
+	interval := minRefreshInterval
+	if s.lastErr != nil {
+		interval = failedRefreshInterval
+	}
+	return s.now().Sub(s.attemptedAt) >= interval
+}
+
+// startFetchLocked returns the fetch in flight, starting one if there is
+// none. The fetch is not tied to the lookup that started it, so a caller that
+// gives up does not fail the others. The caller must hold s.mu.
+func (s *keySet) startFetchLocked() *keyFetch {
+	if s.fetching != nil {
+		return s.fetching
+	}
+
+	fetch := &keyFetch{done: make(chan struct{})}
+	s.fetching = fetch
+	jwksURL := s.jwksURL
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), fetchTimeout)
+		defer cancel()
+
+		keys, jwksURL, err := s.fetch(ctx, jwksURL)
+
+		s.mu.Lock()
+		s.attemptedAt = s.now()
+		s.lastErr = err
+		if err == nil {
+			s.jwksURL = jwksURL
+			s.keys = keys
+			s.fetchedAt = s.attemptedAt
+		}
+		s.fetching = nil
+		s.mu.Unlock()
+
+		fetch.err = err
+		close(fetch.done)
+	}()
+
+	return fetch
+}
+
+// matching returns the cached keys with keyID, or all of them when keyID is
+// empty. The caller must hold s.mu.
+func (s *keySet) matching(keyID string) []jose.JSONWebKey {
+	var found []jose.JSONWebKey
+	for _, key := range s.keys {
+		if keyID == "" || key.KeyID == keyID {
+			found = append(found, key)
+		}
+	}
+	return found
+}
+
+// fetch fetches the keys from jwksURL, discovering it first if needed, and
+// returns them with the URL they were fetched from.
+func (s *keySet) fetch(ctx context.Context, jwksURL string) ([]jose.JSONWebKey, string, error) {
+	if jwksURL == "" && s.discovery {
+		var document struct {
+			Issuer  string `json:"issuer"`
+			JWKSURI string `json:"jwks_uri"`
+		}
+		discoveryURL := strings.TrimSuffix(s.issuer, "/") + "/.well-known/openid-configuration"
+		if err := s.getJSON(ctx, discoveryURL, &document); err != nil {
+			return nil, "", fmt.Errorf("failed to discover the issuer's configuration: %w", err)
+		}
+		if document.Issuer != s.issuer {
+			return nil, "", fmt.Errorf("the discovery document is for issuer %q, not %q", document.Issuer, s.issuer)
+		}
+		if document.JWKSURI == "" {
+			return nil, "", errors.New("the discovery document has no jwks_uri")
+		}
+		jwksURL = document.JWKSURI
+	}
+
+	var set struct {
+		Keys []json.RawMessage `json:"keys"`
+	}
+	if err := s.getJSON(ctx, jwksURL, &set); err != nil {
+		return nil, "", fmt.Errorf("failed to fetch the issuer's signing keys: %w", err)
+	}
+
+	keys := make([]jose.JSONWebKey, 0, len(set.Keys))
+	for _, raw := range set.Keys {
+		var key jose.JSONWebKey
+		if err := key.UnmarshalJSON(raw); err != nil {
+			// Keys of unsupported types are skipped, so one of them does
+			// not make the others unusable.
+			continue
+		}
+		if key.Use != "" && key.Use != "sig" {
+			continue
+		}
+		if !key.Valid() || !key.IsPublic() {
+			continue
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, jwksURL, nil
+}
+
+// getJSON fetches url and decodes its JSON body into v.
+func (s *keySet) getJSON(ctx context.Context, url string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err //nolint:wrapcheck // net/http errors are self-descriptive
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err //nolint:wrapcheck // net/http errors are self-descriptive
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s returned %s", url, resp.Status)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxDocumentSize))
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", url, err)
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("failed to decode %s: %w", url, err)
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package oidcauth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+type claimsKey struct{}
+
+// ClaimsFrom returns the verified claims of the request's token, set by
+// Middleware.
+func ClaimsFrom(ctx context.Context) (*Claims, bool) {
+	claims, ok := ctx.Value(claimsKey{}).(*Claims)
+	return claims, ok
+}
+
+// Middleware returns a handler that lets a request through to next only when
+// it carries a valid bearer token, and answers 401 Unauthorized otherwise.
+// The token's claims are available to next through ClaimsFrom.
+func (v *Verifier) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := bearerToken(r)
+		if !ok {
+			unauthorized(w, "", "a bearer token is required")
+			return
+		}
+
+		claims, err := v.Verify(r.Context(), token)
+		if err != nil {
+			unauthorized(w, "invalid_token", err.Error())
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), claimsKey{}, claims)))
+	})
+}
+
+// bearerToken returns the token of the request's Authorization header.
+func bearerToken(r *http.Request) (string, bool) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+// unauthorized answers 401 with a WWW-Authenticate challenge, as RFC 6750
+// describes: requests without a token get a bare challenge, and requests with
+// a rejected one get the error code and its reason.
+func unauthorized(w http.ResponseWriter, code, description string) {
+	challenge := "Bearer"
+	if code != "" {
+		challenge = fmt.Sprintf("Bearer error=%q, error_description=%q", code, strings.ReplaceAll(description, `"`, "'"))
+	}
+	w.Header().Set("WWW-Authenticate", challenge)
+	http.Error(w, description, http.StatusUnauthorized)
+}
@@ -0,0 +1,203 @@
+// Package oidcauth guards the HTTP transports with OpenID Connect access
+// tokens. Clients send a token issued by the identity provider as a bearer
+// token, and each request is let through only when the token is signed by
+// one of the issuer's published keys, was issued by the configured issuer
+// for the configured audience, and has not expired. The issuer's keys are
+// found through its discovery document, or at a key set URL given directly,
+// and are cached and fetched again when the issuer rotates them.
+package oidcauth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+)
+
+// leeway is the clock skew allowed between the server and the issuer when
+// checking the token's time claims.
+const leeway = time.Minute
+
+// Config configures a Verifier.
+type Config struct {
+	// Issuer is the issuer URL tokens must carry in their iss claim. Unless
+	// JWKSURL is set, the issuer's keys are discovered from
+	// Issuer/.well-known/openid-configuration.
+	Issuer string
+
+	// Audience is the value tokens must carry in their aud claim, usually
+	// the client ID the identity provider issued for this server.
+	Audience string
+
+	// JWKSURL is the URL of the issuer's JSON Web Key Set. It skips
+	// discovery, for providers that do not serve a discovery document.
+	JWKSURL string
+
+	// HTTPClient fetches the discovery document and the keys. Defaults to a
+	// client with a 10 second timeout.
+	HTTPClient *http.Client
+}
+
+// Claims are the verified claims of a token.
+type Claims struct {
+	Subject string `json:"sub"`
+	Email   string `json:"email,omitempty"`
+	Issuer  string `json:"iss"`
+
+	// Raw holds every claim of the token.
+	Raw map[string]any `json:"-"`
+}
+
+// Verifier checks tokens against the configured issuer. It is safe for
+// concurrent use.
+type Verifier struct {
+	issuer   string
+	audience string
+	keys     *keySet
+	now      func() time.Time
+}
+
+// New creates a Verifier. Keys are fetched on the first verification, so an
+// identity provider that is briefly unreachable does not stop the server
+// from starting.
+func New(cfg Config) (*Verifier, error) {
+	if cfg.Issuer == "" {
+		return nil, errors.New("an issuer URL is required")
+	}
+	if cfg.Audience == "" {
+		return nil, errors.New("an audience is required")
+	}
+
+	client := cfg.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	return &Verifier{
+		issuer:   cfg.Issuer,
+		audience: cfg.Audience,
+		keys: &keySet{
+			client:    client,
+			issuer:    cfg.Issuer,
+			jwksURL:   cfg.JWKSURL,
+			discovery: cfg.JWKSURL == "",
+			now:       time.Now,
+		},
+		now: time.Now,
+	}, nil
+}
+
+// signingAlgorithms are the signing algorithms the verifier accepts. "none"
+// and the HMAC algorithms are left out: HMAC would let anyone holding the
+// issuer's public key sign tokens.
+var signingAlgorithms = []jose.SignatureAlgorithm{
+	jose.RS256, jose.RS384, jose.RS512,
+	jose.PS256, jose.PS384, jose.PS512,
+	jose.ES256, jose.ES384, jose.ES512,
+	jose.EdDSA,
+}
+
+// Verify checks the signature and claims of a compact-serialized JWT and
+// returns its claims.
+func (v *Verifier) Verify(ctx context.Context, token string) (*Claims, error) {
+	if strings.Count(token, ".") != 2 {
+		return nil, errors.New("the token is not a JWT")
+	}
+
+	signed, err := jose.ParseSignedCompact(token, signingAlgorithms)
+	if err != nil {
+		var unexpected *jose.ErrUnexpectedSignatureAlgorithm
+		if errors.As(err, &unexpected) {
+			return nil, fmt.Errorf("unsupported token signing algorithm %q", unexpected.Got)
+		}
+		return nil, fmt.Errorf("invalid token: %w", err)
+	}
+
+	keys, err := v.keys.lookup(ctx, signed.Signatures[0].Header.KeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	var payload []byte
+	for _, key := range keys {
+		if payload, err = signed.Verify(key); err == nil {
+			break
+		}
+	}
+	if payload == nil {
+		return nil, errors.New("the token signature does not match any of the issuer's keys")
+	}
+
+	var raw map[string]any
+	if err := json.Unmarshal(payload, &raw); err != nil {
+		return nil, fmt.Errorf("invalid token claims: %w", err)
+	}
+
+	return v.checkClaims(raw)
+}
+
+// checkClaims validates the issuer, audience, and time claims.
+func (v *Verifier) checkClaims(raw map[string]any) (*Claims, error) {
+	claims := &Claims{Raw: raw}
+	claims.Subject, _ = raw["sub"].(string)
+	claims.Email, _ = raw["email"].(string)
+	claims.Issuer, _ = raw["iss"].(string)
+
+	if claims.Issuer != v.issuer {
+		return nil, fmt.Errorf("the token was issued by %q, not %q", claims.Issuer, v.issuer)
+	}
+
+	if !hasAudience(raw["aud"], v.audience) {
+		return nil, fmt.Errorf("the token is not meant for audience %q", v.audience)
+	}
+
+	now := v.now()
+
+	expiry, ok := numericDate(raw["exp"])
+	if !ok {
+		return nil, errors.New("the token has no expiry")
+	}
+	if now.After(expiry.Add(leeway)) {
+		return nil, fmt.Errorf("the token expired at %s", expiry.UTC().Format(time.RFC3339))
+	}
+
+	if notBefore, ok := numericDate(raw["nbf"]); ok && now.Add(leeway).Before(notBefore) {
+		return nil, fmt.Errorf("the token is not valid until %s", notBefore.UTC().Format(time.RFC3339))
+	}
+
+	if issuedAt, ok := numericDate(raw["iat"]); ok && now.Add(leeway).Before(issuedAt) {
+		return nil, errors.New("the token was issued in the future")
+	}
+
+	return claims, nil
+}
+
+// hasAudience reports whether the aud claim, a string or an array of
+// strings, contains audience.
+func hasAudience(claim any, audience string) bool {
+	switch aud := claim.(type) {
+	case string:
+		return aud == audience
+	case []any:
+		for _, value := range aud {
+			if s, ok := value.(string); ok && s == audience {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// numericDate parses a JWT NumericDate, seconds since the epoch.
+func numericDate(claim any) (time.Time, bool) {
+	seconds, ok := claim.(float64)
+	if !ok {
+		return time.Time{}, false
+	}
+	return time.Unix(int64(seconds), 0), true
+}
@@ -0,0 +1,417 @@
+package oidcauth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// provider is a fake identity provider serving a discovery document and a
+// key set.
+type provider struct {
+	server     *httptest.Server
+	rsaKey     *rsa.PrivateKey
+	ecKey      *ecdsa.PrivateKey
+	keyFetches atomic.Int32
+
+	// failKeys makes the key set answer 500, and holdKeys, when set, is
+	// waited on before the key set is served.
+	failKeys atomic.Bool
+	holdKeys chan struct{}
+}
+
+func newProvider(t *testing.T) *provider {
+	t.Helper()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := &provider{rsaKey: rsaKey, ecKey: ecKey}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"issuer":   p.server.URL,
+			"jwks_uri": p.server.URL + "/keys",
+		})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, _ *http.Request) {
+		p.keyFetches.Add(1)
+		if p.holdKeys != nil {
+			<-p.holdKeys
+		}
+		if p.failKeys.Load() {
+			http.Error(w, "unavailable", http.StatusInternalServerError)
+			return
+		}
+		encode := func(b []byte) string { return base64.RawURLEncoding.EncodeToString(b) }
+		ecBytes, _ := ecKey.PublicKey.Bytes() //nolint:errcheck // a generated key always encodes
+		_ = json.NewEncoder(w).Encode(map[string]any{"keys": []map[string]string{
+			{"kid": "rsa", "kty": "RSA", "use": "sig", "n": encode(rsaKey.N.Bytes()), "e": encode(big.NewInt(int64(rsaKey.E)).Bytes())},
+			{"kid": "ec", "kty": "EC", "crv": "P-256", "x": encode(ecBytes[1:33]), "y": encode(ecBytes[33:])},
+			{"kid": "enc", "kty": "RSA", "use": "enc", "n": encode(rsaKey.N.Bytes()), "e": "AQAB"},
+		}})
+	})
+	p.server = httptest.NewServer(mux)
+	t.Cleanup(p.server.Close)
+
+	return p
+}
+
+// sign builds a token with the given header and claims.
+func (p *provider) sign(t *testing.T, head, claims map[string]any) string {
+	t.Helper()
+
+	segment := func(v any) string {
+		raw, err := json.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return base64.RawURLEncoding.EncodeToString(raw)
+	}
+
+	signed := segment(head) + "." + segment(claims)
+	digest := sha256.Sum256([]byte(signed))
+
+	var signature []byte
+	switch head["alg"] {
+	case "RS256":
+		var err error
+		signature, err = rsa.SignPKCS1v15(rand.Reader, p.rsaKey, crypto.SHA256, digest[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+	case "ES256":
+		r, s, err := ecdsa.Sign(rand.Reader, p.ecKey, digest[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		signature = append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...)
+	}
+
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+// claims returns valid claims for the provider, with overrides applied.
+func (p *provider) claims(overrides map[string]any) map[string]any {
+	now := time.Now()
+	claims := map[string]any{
+		"iss":   p.server.URL,
+		"aud":   "mcp-kubernetes-ro",
+		"sub":   "user-1",
+		"email": "user@example.com",
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	}
+	for name, value := range overrides {
+		if value == nil {
+			delete(claims, name)
+			continue
+		}
+		claims[name] = value
+	}
+	return claims
+}
+
+func TestVerify(t *testing.T) {
+	t.Parallel()
+
+	p := newProvider(t)
+	verifier, err := New(Config{Issuer: p.server.URL, Audience: "mcp-kubernetes-ro"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rsaHeader := map[string]any{"alg": "RS256", "kid": "rsa"}
+	hour := time.Hour.Seconds()
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr string
+	}{
+		{name: "valid RS256", token: p.sign(t, rsaHeader, p.claims(nil))},
+		{name: "valid ES256", token: p.sign(t, map[string]any{"alg": "ES256", "kid": "ec"}, p.claims(nil))},
+		{name: "no key ID", token: p.sign(t, map[string]any{"alg": "RS256"}, p.claims(nil))},
+		{name: "audience in a list", token: p.sign(t, rsaHeader, p.claims(map[string]any{"aud": []string{"other", "mcp-kubernetes-ro"}}))},
+		{name: "wrong audience", token: p.sign(t, rsaHeader, p.claims(map[string]any{"aud": "other"})), wantErr: "audience"},
+		{name: "wrong issuer", token: p.sign(t, rsaHeader, p.claims(map[string]any{"iss": "https://evil.example.com"})), wantErr: "issued by"},
+		{name: "expired", token: p.sign(t, rsaHeader, p.claims(map[string]any{"exp": float64(time.Now().Unix()) - 2*hour})), wantErr: "expired"},
+		{name: "no expiry", token: p.sign(t, rsaHeader, p.claims(map[string]any{"exp": nil})), wantErr: "no expiry"},
+		{name: "not yet valid", token: p.sign(t, rsaHeader, p.claims(map[string]any{"nbf": float64(time.Now().Unix()) + hour})), wantErr: "not valid until"},
+		{name: "key of another type", token: p.sign(t, map[string]any{"alg": "RS256", "kid": "ec"}, p.claims(nil)), wantErr: "does not match"},
+		{name: "encryption key", token: p.sign(t, map[string]any{"alg": "RS256", "kid": "enc"}, p.claims(nil)), wantErr: "no signing key"},
+		{name: "none algorithm", token: p.sign(t, map[string]any{"alg": "none"}, p.claims(nil)), wantErr: "unsupported"},
+		{name: "HMAC algorithm", token: p.sign(t, map[string]any{"alg": "HS256", "kid": "rsa"}, p.claims(nil)), wantErr: "unsupported"},
+		{name: "not a JWT", token: "opaque-token", wantErr: "not a JWT"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			claims, err := verifier.Verify(context.Background(), tt.token)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected an error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if claims.Subject != "user-1" || claims.Email != "user@example.com" {
+				t.Errorf("unexpected claims %+v", claims)
+			}
+		})
+	}
+}
+
+func TestVerifyTamperedToken(t *testing.T) {
+	t.Parallel()
+
+	p := newProvider(t)
+	verifier, err := New(Config{Issuer: p.server.URL, Audience: "mcp-kubernetes-ro"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token := p.sign(t, map[string]any{"alg": "RS256", "kid": "rsa"}, p.claims(nil))
+	parts := strings.Split(token, ".")
+	forged, err := json.Marshal(p.claims(map[string]any{"sub": "admin"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts[1] = base64.RawURLEncoding.EncodeToString(forged)
+
+	if _, err := verifier.Verify(context.Background(), strings.Join(parts, ".")); err == nil || !strings.Contains(err.Error(), "signature") {
+		t.Fatalf("expected a signature error, got %v", err)
+	}
+}
+
+func TestUnknownKeyRefreshIsRateLimited(t *testing.T) {
+	t.Parallel()
+
+	p := newProvider(t)
+	verifier, err := New(Config{Issuer: p.server.URL, Audience: "mcp-kubernetes-ro", JWKSURL: p.server.URL + "/keys"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	verifier.keys.now = func() time.Time { return now }
+
+	unknown := p.sign(t, map[string]any{"alg": "RS256", "kid": "rotated"}, p.claims(nil))
+	for range 3 {
+		if _, err := verifier.Verify(context.Background(), unknown); err == nil {
+			t.Fatal("expected an unknown key ID to be rejected")
+		}
+	}
+	if got := p.keyFetches.Load(); got != 1 {
+		t.Fatalf("expected 1 key fetch within the refresh interval, got %d", got)
+	}
+
+	now = now.Add(2 * minRefreshInterval)
+	if _, err := verifier.Verify(context.Background(), unknown); err == nil {
+		t.Fatal("expected an unknown key ID to be rejected")
+	}
+	if got := p.keyFetches.Load(); got != 2 {
+		t.Fatalf("expected the keys to be fetched again after the refresh interval, got %d fetches", got)
+	}
+}
+
+func TestFailedRefreshServesStaleKeys(t *testing.T) {
+	t.Parallel()
+
+	p := newProvider(t)
+	verifier, err := New(Config{Issuer: p.server.URL, Audience: "mcp-kubernetes-ro", JWKSURL: p.server.URL + "/keys"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	verifier.keys.now = func() time.Time { return now }
+
+	token := p.sign(t, map[string]any{"alg": "RS256", "kid": "rsa"}, p.claims(nil))
+	if _, err := verifier.Verify(context.Background(), token); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	p.failKeys.Store(true)
+	now = now.Add(keysMaxAge + time.Minute)
+	for range 3 {
+		if _, err := verifier.Verify(context.Background(), token); err != nil {
+			t.Fatalf("expected the aged keys to be used while the provider fails, got %v", err)
+		}
+	}
+	if got := p.keyFetches.Load(); got != 2 {
+		t.Fatalf("expected 1 failed refresh within the retry interval, got %d fetches", got)
+	}
+
+	p.failKeys.Store(false)
+	now = now.Add(failedRefreshInterval)
+	if _, err := verifier.Verify(context.Background(), token); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := p.keyFetches.Load(); got != 3 {
+		t.Fatalf("expected the keys to be fetched again after the retry interval, got %d fetches", got)
+	}
+}
+
+func TestFailedFetchBacksOff(t *testing.T) {
+	t.Parallel()
+
+	p := newProvider(t)
+	p.failKeys.Store(true)
+	verifier, err := New(Config{Issuer: p.server.URL, Audience: "mcp-kubernetes-ro", JWKSURL: p.server.URL + "/keys"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	verifier.keys.now = func() time.Time { return now }
+
+	token := p.sign(t, map[string]any{"alg": "RS256", "kid": "rsa"}, p.claims(nil))
+	for range 3 {
+		if _, err := verifier.Verify(context.Background(), token); err == nil || !strings.Contains(err.Error(), "500") {
+			t.Fatalf("expected the fetch error, got %v", err)
+		}
+	}
+	if got := p.keyFetches.Load(); got != 1 {
+		t.Fatalf("expected 1 fetch within the retry interval, got %d", got)
+	}
+
+	p.failKeys.Store(false)
+	now = now.Add(failedRefreshInterval)
+	if _, err := verifier.Verify(context.Background(), token); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestConcurrentLookupsShareFetch(t *testing.T) {
+	t.Parallel()
+
+	p := newProvider(t)
+	p.holdKeys = make(chan struct{})
+	verifier, err := New(Config{Issuer: p.server.URL, Audience: "mcp-kubernetes-ro", JWKSURL: p.server.URL + "/keys"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token := p.sign(t, map[string]any{"alg": "RS256", "kid": "rsa"}, p.claims(nil))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan error, 1)
+	go func() {
+		_, err := verifier.Verify(ctx, token)
+		stopped <- err
+	}()
+
+	results := make(chan error, 3)
+	for range 3 {
+		go func() {
+			_, err := verifier.Verify(context.Background(), token)
+			results <- err
+		}()
+	}
+
+	cancel()
+	if err := <-stopped; !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected the cancelled lookup to stop waiting, got %v", err)
+	}
+
+	close(p.holdKeys)
+	for range 3 {
+		if err := <-results; err != nil {
+			t.Fatalf("expected the shared fetch to outlive the cancelled lookup, got %v", err)
+		}
+	}
+	if got := p.keyFetches.Load(); got != 1 {
+		t.Fatalf("expected the lookups to share 1 fetch, got %d", got)
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	t.Parallel()
+
+	p := newProvider(t)
+	verifier, err := New(Config{Issuer: p.server.URL, Audience: "mcp-kubernetes-ro"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	handler := verifier.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := ClaimsFrom(r.Context())
+		if !ok {
+			t.Error("expected the claims in the request context")
+			return
+		}
+		_, _ = w.Write([]byte(claims.Subject))
+	}))
+
+	tests := []struct {
+		name          string
+		authorization string
+		wantStatus    int
+		wantChallenge string
+	}{
+		{name: "valid token", authorization: "Bearer " + p.sign(t, map[string]any{"alg": "RS256", "kid": "rsa"}, p.claims(nil)), wantStatus: http.StatusOK},
+		{name: "no token", wantStatus: http.StatusUnauthorized, wantChallenge: "Bearer"},
+		{name: "basic auth", authorization: "Basic dXNlcjpwYXNz", wantStatus: http.StatusUnauthorized, wantChallenge: "Bearer"},
+		{name: "invalid token", authorization: "Bearer opaque-token", wantStatus: http.StatusUnauthorized, wantChallenge: `Bearer error="invalid_token"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body.String())
+			}
+			if challenge := rec.Header().Get("WWW-Authenticate"); !strings.HasPrefix(challenge, tt.wantChallenge) {
+				t.Errorf("expected a challenge starting with %q, got %q", tt.wantChallenge, challenge)
+			}
+			if tt.wantStatus == http.StatusOK && rec.Body.String() != "user-1" {
+				t.Errorf("expected the handler to see subject user-1, got %q", rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestNewRequiresIssuerAndAudience(t *testing.T) {
+	t.Parallel()
+
+	if _, err := New(Config{Audience: "mcp-kubernetes-ro"}); err == nil {
+		t.Error("expected an error without an issuer")
+	}
+	if _, err := New(Config{Issuer: "https://issuer.example.com"}); err == nil {
+		t.Error("expected an error without an audience")
+	}
+}
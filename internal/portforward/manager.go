@@ -24,10 +24,10 @@ import (
 // PortMapping represents a single local-to-pod port mapping.
 type PortMapping struct {
 	// PodPort is the port on the pod to forward to.
-	PodPort int `json:"pod_port"`
+	PodPort int `json:"pod_port" required:"true" minimum:"1" maximum:"65535" description:"Port on the pod to forward to (1-65535)"`
 
 	// LocalPort is the local port to listen on. If 0, a free port is auto-assigned.
-	LocalPort int `json:"local_port"`
+	LocalPort int `json:"local_port" minimum:"0" maximum:"65535" description:"Local port to listen on (0 or omit for auto-assign)"`
 }
 
 // ForwardEntry holds metadata and control channels for one active port-forward session.
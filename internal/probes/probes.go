@@ -0,0 +1,153 @@
+// Package probes serves the /healthz and /readyz endpoints of the HTTP
+// transports, so the server can run in Kubernetes behind liveness and
+// readiness probes. /healthz reports that the process is serving requests.
+// /readyz also reports whether the cluster is reachable with the server's
+// credentials. Probes arrive every few seconds from every kubelet and load
+// balancer, so the cluster check result is cached instead of reaching the API
+// server on each one. The reason a check failed is logged rather than served,
+// since the probe endpoints answer without authentication.
+package probes
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// LivenessPath and ReadinessPath are the endpoints the probes are
+	// served on.
+	LivenessPath  = "/healthz"
+	ReadinessPath = "/readyz"
+
+	// checkTimeout bounds a single cluster check, so a hung API server
+	// fails readiness instead of hanging the probe.
+	checkTimeout = 5 * time.Second
+
+	// unreadyBody is served by /readyz when the cluster check fails.
+	unreadyBody = "cluster unreachable"
+)
+
+// Prober answers liveness and readiness probes. It is safe for concurrent use.
+type Prober struct {
+	check func(context.Context) error
+	ttl   time.Duration
+
+	mu        sync.Mutex
+	lastErr   error
+	checkedAt time.Time
+	running   *checkRun
+	now       func() time.Time
+}
+
+// checkRun is a cluster check in flight, shared by the probes that arrive
+// while it runs.
+type checkRun struct {
+	done chan struct{}
+	err  error
+}
+
+// New creates a Prober whose readiness depends on check, which should fail
+// when the cluster cannot be reached. Its result is reused for ttl; a zero
+// ttl checks on every readiness probe.
+func New(check func(context.Context) error, ttl time.Duration) *Prober {
+	return &Prober{check: check, ttl: ttl, now: time.Now}
+}
+
+// Ready runs the cluster check, or returns its cached result when it ran
+// within the ttl. Concurrent probes wait for a single check, which is not
+// tied to any of them: a probe that gives up returns ctx's error, and the
+// check still completes and is cached for the probes that follow.
+func (p *Prober) Ready(ctx context.Context) error {
+	p.mu.Lock()
+	if !p.checkedAt.IsZero() && p.now().Sub(p.checkedAt) < p.ttl {
+		err := p.lastErr
+		p.mu.Unlock()
+		return err
+	}
+
+	run := p.running
+	if run == nil {
+		run = &checkRun{done: make(chan struct{})}
+		p.running = run
+		go p.runCheck(run)
+	}
+	p.mu.Unlock()
+
+	select {
+	case <-run.done:
+		return run.err
+	case <-ctx.Done():
+		return ctx.Err() //nolint:wrapcheck // cancellation is reported as is
+	}
+}
+
+// runCheck runs the cluster check with its own timeout and records its result.
+func (p *Prober) runCheck(run *checkRun) {
+	ctx, cancel := context.WithTimeout(context.Background(), checkTimeout)
+	defer cancel()
+
+	run.err = p.check(ctx)
+	if run.err != nil {
+		log.Printf("Readiness check failed: %v", run.err)
+	}
+
+	p.mu.Lock()
+	p.lastErr = run.err
+	p.checkedAt = p.now()
+	p.running = nil
+	p.mu.Unlock()
+	close(run.done)
+}
+
+// Middleware returns a handler that answers the probe endpoints and passes
+// every other request to next. It goes outside any authentication, since
+// probes carry no credentials.
+func (p *Prober) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case LivenessPath:
+			if !probeMethod(w, r) {
+				return
+			}
+			writeStatus(w, r, http.StatusOK, "ok")
+
+		case ReadinessPath:
+			if !probeMethod(w, r) {
+				return
+			}
+			if err := p.Ready(r.Context()); err != nil {
+				writeStatus(w, r, http.StatusServiceUnavailable, unreadyBody)
+				return
+			}
+			writeStatus(w, r, http.StatusOK, "ok")
+
+		default:
+			next.ServeHTTP(w, r)
+		}
+	})
+}
+
+// probeMethod allows GET and HEAD, the methods probes use, and answers 405
+// to anything else.
+func probeMethod(w http.ResponseWriter, r *http.Request) bool {
+	if r.Method == http.MethodGet || r.Method == http.MethodHead {
+		return true
+	}
+	w.Header().Set("Allow", "GET, HEAD")
+	http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	return false
+}
+
+// writeStatus writes a plain-text probe answer that is never cached.
+func writeStatus(w http.ResponseWriter, r *http.Request, code int, body string) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+	if r.Method != http.MethodHead {
+		fmt.Fprintln(w, body)
+	}
+}
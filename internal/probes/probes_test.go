@@ -0,0 +1,133 @@
+package probes
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestReadyCachesResult(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+	var checkErr error
+	prober := New(func(context.Context) error {
+		calls++
+		return checkErr
+	}, 10*time.Second)
+
+	now := time.Now()
+	prober.now = func() time.Time { return now }
+
+	for range 3 {
+		if err := prober.Ready(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if calls != 1 {
+		t.Fatalf("expected 1 check within the ttl, got %d", calls)
+	}
+
+	checkErr = errors.New("connection refused")
+	if err := prober.Ready(context.Background()); err != nil {
+		t.Fatalf("expected the cached success within the ttl, got %v", err)
+	}
+
+	now = now.Add(11 * time.Second)
+	if err := prober.Ready(context.Background()); err == nil {
+		t.Fatal("expected the failed check to be reported once the ttl passed")
+	}
+	if calls != 2 {
+		t.Fatalf("expected 2 checks, got %d", calls)
+	}
+}
+
+func TestReadySharesDetachedCheck(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	started := make(chan struct{})
+	release := make(chan struct{})
+	prober := New(func(ctx context.Context) error {
+		calls.Add(1)
+		close(started)
+		<-release
+		return ctx.Err()
+	}, 10*time.Second)
+
+	probeCtx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan error, 1)
+	go func() { stopped <- prober.Ready(probeCtx) }()
+	<-started
+
+	waited := make(chan error, 1)
+	go func() { waited <- prober.Ready(context.Background()) }()
+
+	cancel()
+	select {
+	case err := <-stopped:
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected the cancelled probe to return its context error, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the cancelled probe kept waiting for the check")
+	}
+
+	close(release)
+	if err := <-waited; err != nil {
+		t.Fatalf("expected the check to outlive the cancelled probe, got %v", err)
+	}
+	if err := prober.Ready(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n := calls.Load(); n != 1 {
+		t.Fatalf("expected the probes to share 1 check, got %d", n)
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	t.Parallel()
+
+	var checkErr error
+	prober := New(func(context.Context) error { return checkErr }, 0)
+
+	handler := prober.Middleware(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}))
+
+	serve := func(method, path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(method, path, nil))
+		return rec
+	}
+
+	if rec := serve(http.MethodGet, LivenessPath); rec.Code != http.StatusOK {
+		t.Errorf("expected liveness to pass, got %d", rec.Code)
+	}
+	if rec := serve(http.MethodGet, ReadinessPath); rec.Code != http.StatusOK {
+		t.Errorf("expected readiness to pass, got %d", rec.Code)
+	}
+	if rec := serve(http.MethodHead, ReadinessPath); rec.Code != http.StatusOK || rec.Body.Len() != 0 {
+		t.Errorf("expected HEAD to pass with no body, got %d %q", rec.Code, rec.Body.String())
+	}
+	if rec := serve(http.MethodPost, LivenessPath); rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected POST to be refused, got %d", rec.Code)
+	}
+	if rec := serve(http.MethodPost, "/mcp"); rec.Code != http.StatusTeapot {
+		t.Errorf("expected other paths to reach the next handler, got %d", rec.Code)
+	}
+
+	checkErr = errors.New("dial tcp 10.0.0.1:6443: connection refused")
+	rec := serve(http.MethodGet, ReadinessPath)
+	if rec.Code != http.StatusServiceUnavailable || strings.TrimSpace(rec.Body.String()) != unreadyBody {
+		t.Errorf("expected readiness to fail without the check error, got %d %q", rec.Code, rec.Body.String())
+	}
+	if rec := serve(http.MethodGet, LivenessPath); rec.Code != http.StatusOK {
+		t.Errorf("expected liveness to pass while the cluster is unreachable, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,356 @@
+// Package prompts provides MCP prompts for common troubleshooting workflows.
+// Each prompt walks the model through the tools of this server in a sensible
+// order, with the arguments the user gave filled in, so clients that support
+// prompts offer guided workflows such as debugging a failing pod.
+package prompts
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// argument is a prompt argument.
+type argument struct {
+	name        string
+	description string
+	required    bool
+}
+
+// contextArgument selects the kubeconfig context, and is accepted by every
+// prompt.
+var contextArgument = argument{name: "context", description: "Kubernetes context to use (defaults to current context from kubeconfig)"}
+
+// step is a tool call in a workflow.
+type step struct {
+	// tool is the tool to call. Steps whose tool is disabled are left out.
+	tool string
+
+	// arguments returns the tool arguments, from the prompt arguments.
+	// Empty values are left out.
+	arguments func(args map[string]string) map[string]any
+
+	// purpose says what to look for in the result.
+	purpose string
+}
+
+// workflow is a prompt that chains tool calls.
+type workflow struct {
+	name        string
+	description string
+	arguments   []argument
+
+	// goal introduces the steps, from the prompt arguments.
+	goal func(args map[string]string) string
+
+	steps []step
+
+	// conclusion says what the answer should contain.
+	conclusion string
+}
+
+// workflows are the prompts the server offers.
+var workflows = []workflow{
+	{
+		name:        "debug_failing_pod",
+		description: "Find out why a pod is crashing, not starting, or not ready, and how to fix it",
+		arguments: []argument{
+			{name: "namespace", description: "Namespace of the pod", required: true},
+			{name: "pod", description: "Name of the failing pod", required: true},
+			contextArgument,
+		},
+		goal: func(args map[string]string) string {
+			return fmt.Sprintf("Debug the failing pod %q in namespace %q.", args["pod"], args["namespace"])
+		},
+		steps: []step{
+			{
+				tool:      "diagnose_pod",
+				arguments: podArguments,
+				purpose:   "to get its status, container states, recent events, and a log tail, and the likely cause",
+			},
+			{
+				tool:      "why_pending",
+				arguments: podArguments,
+				purpose:   "only if the pod is Pending, to see why it cannot be scheduled",
+			},
+			{
+				tool: "get_logs",
+				arguments: func(args map[string]string) map[string]any {
+					return map[string]any{"namespace": args["namespace"], "name": args["pod"], "previous": true, "max_lines": 100, "context": args["context"]}
+				},
+				purpose: "only if a container restarted, to read the logs of its previous run, which usually hold the crash reason",
+			},
+			{
+				tool: "get_owner_chain",
+				arguments: func(args map[string]string) map[string]any {
+					return map[string]any{"resource_type": "pods", "namespace": args["namespace"], "name": args["pod"], "context": args["context"]}
+				},
+				purpose: "to find the workload that manages the pod",
+			},
+			{
+				tool: "get_rollout_status",
+				arguments: func(args map[string]string) map[string]any {
+					return map[string]any{"resource_type": "<kind of the owning workload>", "namespace": args["namespace"], "name": "<name of the owning workload>", "context": args["context"]}
+				},
+				purpose: "if the pod belongs to a Deployment, StatefulSet, or DaemonSet, to see whether a recent rollout is stuck or introduced the failure",
+			},
+		},
+		conclusion: "Finish with the root cause, the evidence that shows it, and the kubectl commands or manifest changes the user could apply to fix it.",
+	},
+	{
+		name:        "service_unreachable",
+		description: "Find out why a Service cannot be reached, from endpoints and ingress routes to network policies and DNS",
+		arguments: []argument{
+			{name: "namespace", description: "Namespace of the Service", required: true},
+			{name: "service", description: "Name of the unreachable Service", required: true},
+			{name: "client_pod", description: "A pod that fails to reach the Service, in the same namespace unless client_namespace is set"},
+			{name: "client_namespace", description: "Namespace of client_pod, when it differs from the Service's"},
+			contextArgument,
+		},
+		goal: func(args map[string]string) string {
+			return fmt.Sprintf("Find out why the Service %q in namespace %q is unreachable.", args["service"], args["namespace"])
+		},
+		steps: []step{
+			{
+				tool: "get_service_endpoints",
+				arguments: func(args map[string]string) map[string]any {
+					return map[string]any{"namespace": args["namespace"], "name": args["service"], "context": args["context"]}
+				},
+				purpose: "to check that its selector matches ready pods and its target ports match their container ports",
+			},
+			{
+				tool: "diagnose_pod",
+				arguments: func(args map[string]string) map[string]any {
+					return map[string]any{"namespace": args["namespace"], "name": "<a backing pod that is not ready>", "context": args["context"]}
+				},
+				purpose: "only if some backing pods are not ready, to see why",
+			},
+			{
+				tool: "get_ingress_routes",
+				arguments: func(args map[string]string) map[string]any {
+					return map[string]any{"namespace": args["namespace"], "validate_backends": true, "context": args["context"]}
+				},
+				purpose: "if the Service is reached from outside the cluster, to check the routes that point at it",
+			},
+			{
+				tool: "analyze_network_policy",
+				arguments: func(args map[string]string) map[string]any {
+					return map[string]any{
+						"source_namespace":      firstNonEmpty(args["client_namespace"], args["namespace"]),
+						"source_pod":            firstNonEmpty(args["client_pod"], "<the client pod>"),
+						"destination_namespace": args["namespace"],
+						"destination_pod":       "<a backing pod>",
+						"port":                  "<the target port>",
+						"context":               args["context"],
+					}
+				},
+				purpose: "to check whether NetworkPolicies block traffic from the client to the backing pods",
+			},
+			{
+				tool: "get_dns_config",
+				arguments: func(args map[string]string) map[string]any {
+					return map[string]any{"namespace": args["client_namespace"], "pod": args["client_pod"], "context": args["context"]}
+				},
+				purpose: "if the client resolves the Service by name, to check the cluster DNS and the client's resolv.conf",
+			},
+		},
+		conclusion: "Finish with where the traffic stops, the evidence that shows it, and the changes the user could make to fix it.",
+	},
+	{
+		name:        "capacity_review",
+		description: "Review how much of the cluster's, or a namespace's, capacity is used and where it is running short",
+		arguments: []argument{
+			{name: "namespace", description: "Namespace to review. Reviews the whole cluster when empty"},
+			contextArgument,
+		},
+		goal: func(args map[string]string) string {
+			if args["namespace"] != "" {
+				return fmt.Sprintf("Review the capacity of namespace %q and the nodes it runs on.", args["namespace"])
+			}
+			return "Review the capacity of the cluster."
+		},
+		steps: []step{
+			{
+				tool: "get_node_conditions",
+				arguments: func(args map[string]string) map[string]any {
+					return map[string]any{"context": args["context"]}
+				},
+				purpose: "to find nodes under memory, disk, or PID pressure, or not ready",
+			},
+			{
+				tool: "get_node_metrics",
+				arguments: func(args map[string]string) map[string]any {
+					return map[string]any{"context": args["context"]}
+				},
+				purpose: "to compare each node's CPU and memory usage with its capacity",
+			},
+			{
+				tool: "get_pod_metrics",
+				arguments: func(args map[string]string) map[string]any {
+					return map[string]any{"namespace": args["namespace"], "context": args["context"]}
+				},
+				purpose: "to find the pods using the most CPU and memory",
+			},
+			{
+				tool: "get_quota_usage",
+				arguments: func(args map[string]string) map[string]any {
+					return map[string]any{"namespace": args["namespace"], "context": args["context"]}
+				},
+				purpose: "to find ResourceQuotas close to their limits",
+			},
+			{
+				tool: "get_namespace_limits",
+				arguments: func(args map[string]string) map[string]any {
+					return map[string]any{"namespace": args["namespace"], "context": args["context"]}
+				},
+				purpose: "only when reviewing a namespace, to see the default requests and limits applied to its pods",
+			},
+		},
+		conclusion: "Finish with a short summary of headroom per resource, the nodes, namespaces, or workloads at risk, and concrete suggestions such as adjusting requests, quotas, or node counts.",
+	},
+	{
+		name:        "cluster_health_check",
+		description: "Check the overall health of the cluster: control plane, nodes, workloads, and recent warnings",
+		arguments:   []argument{contextArgument},
+		goal: func(map[string]string) string {
+			return "Check the overall health of the cluster."
+		},
+		steps: []step{
+			{
+				tool: "check_control_plane_health",
+				arguments: func(args map[string]string) map[string]any {
+					return map[string]any{"context": args["context"]}
+				},
+				purpose: "to check the API server, etcd, scheduler, and controller manager",
+			},
+			{
+				tool: "cluster_summary",
+				arguments: func(args map[string]string) map[string]any {
+					return map[string]any{"context": args["context"]}
+				},
+				purpose: "to find unready nodes, unhealthy pods, failing deployments, pending volumes, and recent Warning events",
+			},
+			{
+				tool: "get_node_conditions",
+				arguments: func(args map[string]string) map[string]any {
+					return map[string]any{"unhealthy_only": true, "context": args["context"]}
+				},
+				purpose: "to see the details of any unhealthy node",
+			},
+			{
+				tool: "diagnose_pod",
+				arguments: func(args map[string]string) map[string]any {
+					return map[string]any{"namespace": "<namespace>", "name": "<an unhealthy pod>", "context": args["context"]}
+				},
+				purpose: "for the most important unhealthy pods found above, to see why they fail",
+			},
+		},
+		conclusion: "Finish with an overall verdict, the problems found ordered by severity, and the next steps for each.",
+	},
+}
+
+// podArguments returns the tool arguments naming the pod of a prompt.
+func podArguments(args map[string]string) map[string]any {
+	return map[string]any{"namespace": args["namespace"], "name": args["pod"], "context": args["context"]}
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// All returns the prompts to register with the MCP server. enabled reports
+// whether a tool is registered: steps that need a disabled tool are left
+// out, and prompts left without steps are not offered.
+func All(enabled func(tool string) bool) []server.ServerPrompt {
+	var prompts []server.ServerPrompt
+	for _, w := range workflows {
+		var steps []step
+		for _, s := range w.steps {
+			if enabled(s.tool) {
+				steps = append(steps, s)
+			}
+		}
+		if len(steps) == 0 {
+			continue
+		}
+
+		w.steps = steps
+		prompts = append(prompts, server.ServerPrompt{Prompt: w.prompt(), Handler: w.handle})
+	}
+	return prompts
+}
+
+// prompt returns the MCP definition of w.
+func (w workflow) prompt() mcp.Prompt {
+	options := []mcp.PromptOption{mcp.WithPromptDescription(w.description)}
+	for _, arg := range w.arguments {
+		argumentOptions := []mcp.ArgumentOption{mcp.ArgumentDescription(arg.description)}
+		if arg.required {
+			argumentOptions = append(argumentOptions, mcp.RequiredArgument())
+		}
+		options = append(options, mcp.WithArgument(arg.name, argumentOptions...))
+	}
+	return mcp.NewPrompt(w.name, options...)
+}
+
+// handle serves a prompts/get request for w.
+func (w workflow) handle(_ context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+	args := make(map[string]string, len(request.Params.Arguments))
+	for name, value := range request.Params.Arguments {
+		args[name] = strings.TrimSpace(value)
+	}
+	for _, arg := range w.arguments {
+		if arg.required && args[arg.name] == "" {
+			return nil, fmt.Errorf("argument %q is required", arg.name)
+		}
+	}
+
+	return mcp.NewGetPromptResult(w.description, []mcp.PromptMessage{
+		mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(w.render(args))),
+	}), nil
+}
+
+// render writes the instructions of w for args.
+func (w workflow) render(args map[string]string) string {
+	var b strings.Builder
+
+	b.WriteString(w.goal(args))
+	if args["context"] != "" {
+		fmt.Fprintf(&b, " Use the Kubernetes context %q.", args["context"])
+	}
+	b.WriteString(" Work through these steps with the read-only tools of this server, skipping the ones that do not apply and stopping once the answer is clear. Replace the <placeholders> with what earlier steps found.\n")
+
+	for i, s := range w.steps {
+		arguments := make(map[string]any)
+		for name, value := range s.arguments(args) {
+			if value != "" {
+				arguments[name] = value
+			}
+		}
+
+		// Placeholders are kept readable rather than escaped as HTML.
+		var encoded bytes.Buffer
+		encoder := json.NewEncoder(&encoded)
+		encoder.SetEscapeHTML(false)
+		if err := encoder.Encode(arguments); err != nil {
+			encoded.Reset()
+			encoded.WriteString("{}")
+		}
+		fmt.Fprintf(&b, "\n%d. Call `%s` with %s %s.", i+1, s.tool, bytes.TrimSpace(encoded.Bytes()), s.purpose)
+	}
+
+	b.WriteString("\n\n")
+	b.WriteString(w.conclusion)
+	b.WriteString(" This server is read-only: suggest commands for the user to run rather than running anything that changes the cluster.")
+	return b.String()
+}
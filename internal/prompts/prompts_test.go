@@ -0,0 +1,120 @@
+package prompts
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func allEnabled(string) bool { return true }
+
+func find(t *testing.T, prompts []server.ServerPrompt, name string) server.ServerPrompt {
+	t.Helper()
+
+	for _, p := range prompts {
+		if p.Prompt.Name == name {
+			return p
+		}
+	}
+	t.Fatalf("prompt %q not found", name)
+	return server.ServerPrompt{}
+}
+
+func get(t *testing.T, p server.ServerPrompt, args map[string]string) string {
+	t.Helper()
+
+	request := mcp.GetPromptRequest{}
+	request.Params.Name = p.Prompt.Name
+	request.Params.Arguments = args
+
+	result, err := p.Handler(context.Background(), request)
+	if err != nil {
+		t.Fatalf("failed to get prompt %s: %v", p.Prompt.Name, err)
+	}
+	if len(result.Messages) != 1 || result.Messages[0].Role != mcp.RoleUser {
+		t.Fatalf("expected a single user message, got %+v", result.Messages)
+	}
+	text, ok := result.Messages[0].Content.(mcp.TextContent)
+	if !ok {
+		t.Fatalf("expected text content, got %T", result.Messages[0].Content)
+	}
+	return text.Text
+}
+
+func TestAll(t *testing.T) {
+	t.Parallel()
+
+	prompts := All(allEnabled)
+	for _, name := range []string{"debug_failing_pod", "service_unreachable", "capacity_review", "cluster_health_check"} {
+		p := find(t, prompts, name)
+		if p.Prompt.Description == "" {
+			t.Errorf("expected prompt %s to have a description", name)
+		}
+	}
+
+	p := find(t, prompts, "debug_failing_pod")
+	required := map[string]bool{}
+	for _, arg := range p.Prompt.Arguments {
+		required[arg.Name] = arg.Required
+	}
+	if !required["namespace"] || !required["pod"] || required["context"] {
+		t.Errorf("expected namespace and pod to be required and context optional, got %+v", p.Prompt.Arguments)
+	}
+}
+
+func TestGet(t *testing.T) {
+	t.Parallel()
+
+	p := find(t, All(allEnabled), "debug_failing_pod")
+	text := get(t, p, map[string]string{"namespace": "shop", "pod": " web-1 ", "context": "prod"})
+
+	for _, want := range []string{
+		`Debug the failing pod "web-1" in namespace "shop".`,
+		`Use the Kubernetes context "prod".`,
+		"1. Call `diagnose_pod` with {\"context\":\"prod\",\"name\":\"web-1\",\"namespace\":\"shop\"}",
+		`"previous":true`,
+		`"name":"<name of the owning workload>"`,
+		"read-only",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected the prompt to contain %q, got:\n%s", want, text)
+		}
+	}
+
+	// Empty arguments are left out of the tool calls.
+	text = get(t, find(t, All(allEnabled), "capacity_review"), nil)
+	if !strings.Contains(text, "Review the capacity of the cluster.") || !strings.Contains(text, "`get_node_conditions` with {}") {
+		t.Errorf("expected a cluster-wide review without arguments, got:\n%s", text)
+	}
+
+	request := mcp.GetPromptRequest{}
+	request.Params.Arguments = map[string]string{"namespace": "shop"}
+	if _, err := p.Handler(context.Background(), request); err == nil || !strings.Contains(err.Error(), `"pod"`) {
+		t.Errorf("expected a missing pod argument to be rejected, got %v", err)
+	}
+}
+
+func TestAllSkipsDisabledTools(t *testing.T) {
+	t.Parallel()
+
+	prompts := All(func(tool string) bool {
+		return tool != "get_logs" && !strings.HasPrefix(tool, "get_node") && tool != "get_pod_metrics" && tool != "get_quota_usage" && tool != "get_namespace_limits"
+	})
+
+	text := get(t, find(t, prompts, "debug_failing_pod"), map[string]string{"namespace": "shop", "pod": "web-1"})
+	if strings.Contains(text, "get_logs") {
+		t.Errorf("expected the disabled get_logs tool to be left out, got:\n%s", text)
+	}
+	if !strings.Contains(text, "3. Call `get_owner_chain`") {
+		t.Errorf("expected the remaining steps to be renumbered, got:\n%s", text)
+	}
+
+	for _, p := range prompts {
+		if p.Prompt.Name == "capacity_review" {
+			t.Error("expected a prompt without enabled tools to be left out")
+		}
+	}
+}
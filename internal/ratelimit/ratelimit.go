@@ -0,0 +1,164 @@
+// Package ratelimit caps how many tool calls each client may make per
+// minute. An agent stuck in a loop can issue calls as fast as the server
+// answers them, and each one reaches the Kubernetes API server; the limit
+// stops a single runaway client from flooding the cluster while other
+// clients of a shared server keep working. Clients over their limit get an
+// error result telling them when to retry.
+package ratelimit
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"golang.org/x/time/rate"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/response"
+)
+
+// Limiter applies a per-client token bucket to tool calls. It is safe for
+// concurrent use.
+type Limiter struct {
+	perMinute int
+	burst     int
+
+	// key names the client making a call. Calls with the same key share a
+	// bucket.
+	key func(context.Context) string
+
+	// now returns the current time. It defaults to time.Now and is replaced
+	// in tests.
+	now func() time.Time
+
+	mu        sync.Mutex
+	clients   map[string]*bucket
+	lastSweep time.Time
+}
+
+// bucket is the token bucket of a single client.
+type bucket struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// New creates a Limiter that lets each client make perMinute calls per
+// minute, with bursts of up to burst calls. A burst of zero allows perMinute
+// calls at once. key names the client of a call.
+func New(perMinute, burst int, key func(context.Context) string) *Limiter {
+	if burst <= 0 {
+		burst = perMinute
+	}
+	return &Limiter{
+		perMinute: perMinute,
+		burst:     burst,
+		key:       key,
+		now:       time.Now,
+		clients:   make(map[string]*bucket),
+	}
+}
+
+// Wrap returns a handler that runs next only when the calling client is
+// within its limit, and returns an error result otherwise.
+func (l *Limiter) Wrap(next func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error)) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if wait, ok := l.allow(l.key(ctx)); !ok {
+			return response.Errorf("rate limit exceeded: this client may make %d tool calls per minute. Retry in %s, and avoid repeating calls whose results have not changed", l.perMinute, wait.Round(time.Second))
+		}
+		return next(ctx, request)
+	}
+}
+
+// allow takes a token from the client's bucket. When the bucket is empty it
+// returns false and how long until the next token.
+func (l *Limiter) allow(client string) (time.Duration, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweepLocked(now)
+
+	b, ok := l.clients[client]
+	if !ok {
+		b = &bucket{limiter: rate.NewLimiter(rate.Limit(float64(l.perMinute)/60), l.burst)}
+		l.clients[client] = b
+	}
+	b.lastSeen = now
+
+	if b.limiter.AllowN(now, 1) {
+		return 0, true
+	}
+
+	reservation := b.limiter.ReserveN(now, 1)
+	wait := reservation.DelayFrom(now)
+	reservation.CancelAt(now)
+	return max(wait, time.Second), false
+}
+
+// sweepLocked drops the buckets of clients idle long enough for their bucket
+// to refill, which behave the same as a new bucket. It runs at most once a
+// minute. The caller must hold l.mu.
+func (l *Limiter) sweepLocked(now time.Time) {
+	if now.Sub(l.lastSweep) < time.Minute {
+		return
+	}
+	l.lastSweep = now
+
+	refill := time.Duration(float64(l.burst) / float64(l.perMinute) * float64(time.Minute))
+	for client, b := range l.clients {
+		if now.Sub(b.lastSeen) > refill {
+			delete(l.clients, client)
+		}
+	}
+}
+
+// ClientKey returns a key function for New that names the client of a tool
+// call by the first of these that is known: the caller identity returns,
+// such as the subject of an OIDC token, its MCP session when
+// trackedSessions is set, or its IP address recorded by WithRemoteAddress.
+// Calls with none of these share the "local" key.
+//
+// Set trackedSessions only for transports that issue session IDs and know
+// which ones they issued, such as SSE. Stateless transports take the session
+// ID from a request header as the client sent it, and a client sending a new
+// one with every call would get a fresh budget each time.
+func ClientKey(identity func(context.Context) (caller, session string), trackedSessions bool) func(context.Context) string {
+	return func(ctx context.Context) string {
+		caller, session := identity(ctx)
+		switch {
+		case caller != "":
+			return "caller:" + caller
+		case session != "" && trackedSessions:
+			return "session:" + session
+		}
+		if address, ok := RemoteAddressFrom(ctx); ok {
+			return "address:" + address
+		}
+		return "local"
+	}
+}
+
+type addressKey struct{}
+
+// WithRemoteAddress returns a handler that records the IP address of each
+// request's client in its context, where RemoteAddressFrom finds it. The
+// MCP transports pass the request context on to tool calls, so the address
+// can tell clients apart when no better identity is known.
+func WithRemoteAddress(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		address := r.RemoteAddr
+		if host, _, err := net.SplitHostPort(address); err == nil {
+			address = host
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), addressKey{}, address)))
+	})
+}
+
+// RemoteAddressFrom returns the client IP address recorded by
+// WithRemoteAddress.
+func RemoteAddressFrom(ctx context.Context) (string, bool) {
+	address, ok := ctx.Value(addressKey{}).(string)
+	return address, ok && address != ""
+}
@@ -0,0 +1,208 @@
+package ratelimit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+type clientKey struct{}
+
+func withClient(name string) context.Context {
+	return context.WithValue(context.Background(), clientKey{}, name)
+}
+
+func keyFromContext(ctx context.Context) string {
+	name, _ := ctx.Value(clientKey{}).(string)
+	return name
+}
+
+func TestWrap(t *testing.T) {
+	t.Parallel()
+
+	limiter := New(60, 3, keyFromContext)
+	now := time.Now()
+	limiter.now = func() time.Time { return now }
+
+	calls := 0
+	handler := limiter.Wrap(func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		calls++
+		return mcp.NewToolResultText("ok"), nil
+	})
+
+	call := func(client string) *mcp.CallToolResult {
+		t.Helper()
+		result, err := handler(withClient(client), mcp.CallToolRequest{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return result
+	}
+
+	for i := range 3 {
+		if result := call("agent-a"); result.IsError {
+			t.Fatalf("expected call %d within the burst to pass", i+1)
+		}
+	}
+
+	result := call("agent-a")
+	if !result.IsError {
+		t.Fatal("expected the call past the burst to be limited")
+	}
+	text, _ := result.Content[0].(mcp.TextContent)
+	if !strings.Contains(text.Text, "60 tool calls per minute") || !strings.Contains(text.Text, "Retry in 1s") {
+		t.Errorf("unexpected message %q", text.Text)
+	}
+
+	// Another client has a bucket of its own.
+	if result := call("agent-b"); result.IsError {
+		t.Error("expected another client to be unaffected")
+	}
+
+	// One call per second refills.
+	now = now.Add(time.Second)
+	if result := call("agent-a"); result.IsError {
+		t.Error("expected a call after the refill to pass")
+	}
+
+	if calls != 5 {
+		t.Errorf("expected 5 calls to reach the handler, got %d", calls)
+	}
+}
+
+func TestSweepDropsIdleClients(t *testing.T) {
+	t.Parallel()
+
+	limiter := New(60, 0, keyFromContext)
+	now := time.Now()
+	limiter.now = func() time.Time { return now }
+
+	limiter.allow("agent-a")
+	now = now.Add(30 * time.Second)
+	limiter.allow("agent-b")
+
+	now = now.Add(45 * time.Second)
+	limiter.allow("agent-b")
+
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+	if _, ok := limiter.clients["agent-a"]; ok {
+		t.Error("expected the idle client's full bucket to be dropped")
+	}
+	if _, ok := limiter.clients["agent-b"]; !ok {
+		t.Error("expected the active client's bucket to be kept")
+	}
+}
+
+func TestWithRemoteAddress(t *testing.T) {
+	t.Parallel()
+
+	var got string
+	handler := WithRemoteAddress(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		got, _ = RemoteAddressFrom(r.Context())
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+	req.RemoteAddr = "10.1.2.3:51234"
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	if got != "10.1.2.3" {
+		t.Errorf("expected the client IP without its port, got %q", got)
+	}
+
+	if _, ok := RemoteAddressFrom(context.Background()); ok {
+		t.Error("expected no address outside an HTTP request")
+	}
+}
+
+// sessionIdentity reports the MCP session of a call, with no caller.
+func sessionIdentity(ctx context.Context) (caller, session string) {
+	if clientSession := server.ClientSessionFromContext(ctx); clientSession != nil {
+		session = clientSession.SessionID()
+	}
+	return "", session
+}
+
+func TestClientKey(t *testing.T) {
+	t.Parallel()
+
+	identity := func(caller, session string) func(context.Context) (string, string) {
+		return func(context.Context) (string, string) { return caller, session }
+	}
+
+	addressed := context.WithValue(context.Background(), addressKey{}, "10.1.2.3")
+
+	tests := []struct {
+		name            string
+		caller, session string
+		tracked         bool
+		ctx             context.Context
+		want            string
+	}{
+		{name: "caller", caller: "alice@example.com", session: "abc", tracked: true, ctx: addressed, want: "caller:alice@example.com"},
+		{name: "tracked session", session: "abc", tracked: true, ctx: addressed, want: "session:abc"},
+		{name: "untracked session", session: "abc", ctx: addressed, want: "address:10.1.2.3"},
+		{name: "nothing known", session: "abc", ctx: context.Background(), want: "local"},
+	}
+
+	for _, tt := range tests {
+		if got := ClientKey(identity(tt.caller, tt.session), tt.tracked)(tt.ctx); got != tt.want {
+			t.Errorf("%s: expected key %q, got %q", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestClientKeyRotatedSessionHeader(t *testing.T) {
+	t.Parallel()
+
+	limiter := New(60, 2, ClientKey(sessionIdentity, false))
+
+	s := server.NewMCPServer("ratelimit-test", "1.0.0", server.WithToolCapabilities(false))
+	s.AddTool(mcp.NewTool("ping"), limiter.Wrap(func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("pong"), nil
+	}))
+
+	httpServer := httptest.NewServer(WithRemoteAddress(server.NewStreamableHTTPServer(s, server.WithStateLess(true))))
+	defer httpServer.Close()
+
+	// A stateless server takes the session ID from the header as sent, so a
+	// client rotating it must still share one budget.
+	for i := range 3 {
+		body := fmt.Sprintf(`{"jsonrpc":"2.0","id":%d,"method":"tools/call","params":{"name":"ping"}}`, i)
+		req, err := http.NewRequest(http.MethodPost, httpServer.URL+"/mcp", strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "application/json, text/event-stream")
+		req.Header.Set(server.HeaderKeySessionID, fmt.Sprintf("rotated-%d", i))
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var decoded struct {
+			Result struct {
+				IsError bool `json:"isError"`
+			} `json:"result"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&decoded)
+		resp.Body.Close()
+		if err != nil {
+			t.Fatalf("call %d: failed to decode the response: %v", i+1, err)
+		}
+
+		if limited := i == 2; decoded.Result.IsError != limited {
+			t.Errorf("call %d: expected limited %t, got %t", i+1, limited, decoded.Result.IsError)
+		}
+	}
+}
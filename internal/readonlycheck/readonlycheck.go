@@ -0,0 +1,147 @@
+// Package readonlycheck finds the write permissions of the server's own
+// credentials. The server never writes to the cluster, but the credentials it
+// is given may allow it, and that is the blast radius if they leak. The check
+// reads the credentials' rules with a SelfSubjectRulesReview, which the API
+// server answers from its RBAC authorizer without storing anything, and lists
+// every rule granting a verb that changes the cluster.
+package readonlycheck
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+)
+
+// writeVerbs are the verbs that change the cluster or grant more access.
+var writeVerbs = map[string]bool{
+	"*":                true,
+	"create":           true,
+	"update":           true,
+	"patch":            true,
+	"delete":           true,
+	"deletecollection": true,
+	"escalate":         true,
+	"bind":             true,
+	"impersonate":      true,
+}
+
+// selfReviews are the resources every authenticated user may create to ask
+// about their own identity and permissions. Creating them stores nothing.
+var selfReviews = map[string]bool{
+	"authorization.k8s.io/selfsubjectaccessreviews": true,
+	"authorization.k8s.io/selfsubjectrulesreviews":  true,
+	"authentication.k8s.io/selfsubjectreviews":      true,
+}
+
+// nonResourceWriteVerbs are the verbs that change state through non-resource
+// URLs, which use HTTP methods as verbs.
+var nonResourceWriteVerbs = map[string]bool{
+	"*":      true,
+	"post":   true,
+	"put":    true,
+	"patch":  true,
+	"delete": true,
+}
+
+// RulesReviewer returns the rules the credentials are granted in a namespace.
+// *kubernetes.Client implements it.
+type RulesReviewer interface {
+	ReviewRules(ctx context.Context, namespace string) (*authorizationv1.SubjectRulesReviewStatus, error)
+}
+
+// Result lists the write permissions found.
+type Result struct {
+	// Namespace is the namespace whose rules were reviewed. Cluster-wide
+	// rules apply to it too.
+	Namespace string
+
+	// Writes describes each rule that grants a write verb, such as
+	// "create, delete on apps/deployments".
+	Writes []string
+
+	// Incomplete reports that the API server could not list every rule,
+	// typically because an authorizer other than RBAC, such as a webhook,
+	// is in use. Permissions it grants are not in Writes. Reason holds the
+	// evaluation error, when given.
+	Incomplete bool
+	Reason     string
+}
+
+// Check reviews the rules of the credentials in namespace and returns the
+// ones that allow writes.
+func Check(ctx context.Context, reviewer RulesReviewer, namespace string) (*Result, error) {
+	status, err := reviewer.ReviewRules(ctx, namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to review the rules of the credentials: %w", err)
+	}
+
+	result := &Result{
+		Namespace:  namespace,
+		Incomplete: status.Incomplete,
+		Reason:     status.EvaluationError,
+	}
+
+	for _, rule := range status.ResourceRules {
+		if verbs := matching(rule.Verbs, writeVerbs); len(verbs) > 0 {
+			if description, ok := describeResourceRule(verbs, rule); ok {
+				result.Writes = append(result.Writes, description)
+			}
+		}
+	}
+	for _, rule := range status.NonResourceRules {
+		if verbs := matching(rule.Verbs, nonResourceWriteVerbs); len(verbs) > 0 {
+			result.Writes = append(result.Writes, fmt.Sprintf("%s on %s", strings.Join(verbs, ", "), strings.Join(rule.NonResourceURLs, ", ")))
+		}
+	}
+
+	return result, nil
+}
+
+// matching returns the verbs that are in set, sorted.
+func matching(verbs []string, set map[string]bool) []string {
+	var found []string
+	for _, verb := range verbs {
+		if set[strings.ToLower(verb)] {
+			found = append(found, verb)
+		}
+	}
+	sort.Strings(found)
+	return found
+}
+
+// describeResourceRule renders a rule as verbs on group/resource pairs, with
+// its resource names when it is limited to some. Self reviews granted only
+// create are left out, and it returns false when nothing is left.
+func describeResourceRule(verbs []string, rule authorizationv1.ResourceRule) (string, bool) {
+	onlyCreate := len(verbs) == 1 && strings.EqualFold(verbs[0], "create")
+
+	groups := rule.APIGroups
+	if len(groups) == 0 {
+		groups = []string{""}
+	}
+
+	var targets []string
+	for _, group := range groups {
+		if group == "" {
+			group = "core"
+		}
+		for _, resource := range rule.Resources {
+			if onlyCreate && selfReviews[group+"/"+resource] {
+				continue
+			}
+			targets = append(targets, group+"/"+resource)
+		}
+	}
+	if len(targets) == 0 {
+		return "", false
+	}
+
+	description := fmt.Sprintf("%s on %s", strings.Join(verbs, ", "), strings.Join(targets, ", "))
+	if len(rule.ResourceNames) > 0 {
+		description += fmt.Sprintf(" (only %s)", strings.Join(rule.ResourceNames, ", "))
+	}
+	return description, true
+}
@@ -0,0 +1,89 @@
+package readonlycheck
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+)
+
+type staticReviewer struct {
+	status *authorizationv1.SubjectRulesReviewStatus
+	err    error
+}
+
+func (r staticReviewer) ReviewRules(context.Context, string) (*authorizationv1.SubjectRulesReviewStatus, error) {
+	return r.status, r.err
+}
+
+func TestCheck(t *testing.T) {
+	t.Parallel()
+
+	readOnlyRules := []authorizationv1.ResourceRule{
+		{Verbs: []string{"get", "list", "watch"}, APIGroups: []string{"*"}, Resources: []string{"*"}},
+	}
+	selfReviews := authorizationv1.ResourceRule{
+		Verbs: []string{"create"}, APIGroups: []string{"authorization.k8s.io"}, Resources: []string{"selfsubjectaccessreviews", "selfsubjectrulesreviews"},
+	}
+
+	tests := []struct {
+		name   string
+		status authorizationv1.SubjectRulesReviewStatus
+		want   []string
+	}{
+		{
+			name:   "read only",
+			status: authorizationv1.SubjectRulesReviewStatus{ResourceRules: readOnlyRules},
+		},
+		{
+			name: "write permissions",
+			status: authorizationv1.SubjectRulesReviewStatus{
+				ResourceRules: append(readOnlyRules,
+					authorizationv1.ResourceRule{Verbs: []string{"get", "patch", "delete"}, APIGroups: []string{"apps"}, Resources: []string{"deployments"}},
+					authorizationv1.ResourceRule{Verbs: []string{"update"}, APIGroups: []string{""}, Resources: []string{"configmaps"}, ResourceNames: []string{"settings"}},
+				),
+				NonResourceRules: []authorizationv1.NonResourceRule{
+					{Verbs: []string{"get"}, NonResourceURLs: []string{"/healthz"}},
+					{Verbs: []string{"*"}, NonResourceURLs: []string{"*"}},
+				},
+			},
+			want: []string{
+				"delete, patch on apps/deployments",
+				"update on core/configmaps (only settings)",
+				"* on *",
+			},
+		},
+		{
+			name:   "self reviews",
+			status: authorizationv1.SubjectRulesReviewStatus{ResourceRules: append(readOnlyRules, selfReviews)},
+		},
+		{
+			name: "self reviews next to a write",
+			status: authorizationv1.SubjectRulesReviewStatus{ResourceRules: []authorizationv1.ResourceRule{
+				{Verbs: []string{"create"}, APIGroups: []string{"authorization.k8s.io"}, Resources: []string{"selfsubjectrulesreviews", "subjectaccessreviews"}},
+			}},
+			want: []string{"create on authorization.k8s.io/subjectaccessreviews"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			result, err := Check(context.Background(), staticReviewer{status: &tt.status}, "default")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(result.Writes, tt.want) {
+				t.Errorf("expected writes %q, got %q", tt.want, result.Writes)
+			}
+		})
+	}
+
+	if _, err := Check(context.Background(), staticReviewer{err: errors.New("forbidden")}, "default"); err == nil {
+		t.Error("expected a failed review to return an error")
+	}
+}
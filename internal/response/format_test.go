@@ -0,0 +1,206 @@
+package response
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestReformat(t *testing.T) {
+	t.Parallel()
+
+	document := `{
+  "name": "web",
+  "labels": {},
+  "annotations": {
+    "note": null
+  },
+  "replicas": 0,
+  "ready": false,
+  "message": "",
+  "containers": [
+    {
+      "name": "app",
+      "args": [],
+      "image": "nginx:1.27"
+    },
+    null
+  ],
+  "size": 1.50
+}`
+
+	tests := []struct {
+		name   string
+		format Format
+		want   string
+	}{
+		{
+			name:   "compact",
+			format: Format{Compact: true},
+			want:   `{"name":"web","labels":{},"annotations":{"note":null},"replicas":0,"ready":false,"message":"","containers":[{"name":"app","args":[],"image":"nginx:1.27"},null],"size":1.50}`,
+		},
+		{
+			name:   "compact without empty fields",
+			format: Format{Compact: true, OmitEmpty: true},
+			want:   `{"name":"web","replicas":0,"ready":false,"containers":[{"name":"app","image":"nginx:1.27"},null],"size":1.50}`,
+		},
+		{
+			name:   "indented without empty fields",
+			format: Format{OmitEmpty: true},
+			want: `{
+  "name": "web",
+  "replicas": 0,
+  "ready": false,
+  "containers": [
+    {
+      "name": "app",
+      "image": "nginx:1.27"
+    },
+    null
+  ],
+  "size": 1.50
+}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, ok := Reformat(document, tt.format)
+			if !ok {
+				t.Fatal("expected the document to be reformatted")
+			}
+			if got != tt.want {
+				t.Errorf("expected:\n%s\ngot:\n%s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestReformatLeavesOtherContent(t *testing.T) {
+	t.Parallel()
+
+	for _, document := range []string{"", "plain text", `"a string"`, `{"broken": `, `{} {}`} {
+		if got, ok := Reformat(document, Format{Compact: true, OmitEmpty: true}); ok {
+			t.Errorf("expected %q to be left alone, got %q", document, got)
+		}
+	}
+
+	if got, ok := Reformat(`{"a": null}`, Format{OmitEmpty: true}); !ok || got != "{}" {
+		t.Errorf("expected an object left empty to stay an object, got %q", got)
+	}
+}
+
+func TestFormatterWrap(t *testing.T) {
+	t.Parallel()
+
+	stored, _ := JSON(map[string]any{"name": "web", "labels": map[string]string{}})
+	original := stored.Content[0].(mcp.TextContent).Text
+	next := func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return stored, nil
+	}
+
+	tests := []struct {
+		name     string
+		defaults Format
+		args     map[string]any
+		want     string
+	}{
+		{name: "indented by default", args: nil, want: original},
+		{name: "compact by default", defaults: Format{Compact: true}, want: `{"labels":{},"name":"web"}`},
+		{name: "compact per call", args: map[string]any{CompactArgument: true, OmitEmptyArgument: true}, want: `{"name":"web"}`},
+		{name: "indented per call", defaults: Format{Compact: true}, args: map[string]any{CompactArgument: false}, want: original},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			request := mcp.CallToolRequest{}
+			request.Params.Arguments = tt.args
+
+			result, err := NewFormatter(tt.defaults).Wrap(next)(context.Background(), request)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := result.Content[0].(mcp.TextContent).Text; got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+			if got, _ := result.StructuredContent.(json.RawMessage); string(got) != tt.want {
+				t.Errorf("expected structured content %q, got %q", tt.want, got)
+			}
+		})
+	}
+
+	if got := stored.Content[0].(mcp.TextContent).Text; got != original {
+		t.Errorf("expected the handler's result to be left unchanged, got %q", got)
+	}
+}
+
+func TestFormatterWrapRejectsInvalidArguments(t *testing.T) {
+	t.Parallel()
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]any{CompactArgument: "yes"}
+
+	called := false
+	result, err := NewFormatter(Format{}).Wrap(func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		called = true
+		return JSON(map[string]string{})
+	})(context.Background(), request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.IsError || called {
+		t.Errorf("expected an error result without calling the handler, got %+v", result)
+	}
+}
+
+func TestAddFormatParameters(t *testing.T) {
+	t.Parallel()
+
+	tool := mcp.NewTool("get_resource", mcp.WithString("name"))
+	withFormat, ok := AddFormatParameters(tool)
+	if !ok {
+		t.Fatal("expected the format arguments to be added")
+	}
+	for _, name := range []string{"name", CompactArgument, OmitEmptyArgument} {
+		if _, ok := withFormat.InputSchema.Properties[name]; !ok {
+			t.Errorf("expected argument %q", name)
+		}
+	}
+	if _, ok := tool.InputSchema.Properties[CompactArgument]; ok {
+		t.Error("expected the original tool to be left unchanged")
+	}
+
+	if _, ok := AddFormatParameters(mcp.NewTool("custom", mcp.WithBoolean(CompactArgument))); ok {
+		t.Error("expected a tool with its own compact argument to be left unchanged")
+	}
+
+	// omit_empty may drop any field, so none stays required.
+	item := map[string]any{"type": "object", "required": []string{"name"}, "properties": map[string]any{"name": map[string]any{"type": "string"}}}
+	tool.OutputSchema = mcp.ToolOutputSchema{
+		Type:       "object",
+		Required:   []string{"items"},
+		Properties: map[string]any{"items": map[string]any{"type": "array", "items": item}},
+	}
+	withFormat, _ = AddFormatParameters(tool)
+
+	want := mcp.ToolOutputSchema{
+		Type: "object",
+		Properties: map[string]any{"items": map[string]any{"type": "array", "items": map[string]any{
+			"type":       "object",
+			"properties": map[string]any{"name": map[string]any{"type": "string"}},
+		}}},
+	}
+	if !reflect.DeepEqual(withFormat.OutputSchema, want) {
+		t.Errorf("expected output schema %+v, got %+v", want, withFormat.OutputSchema)
+	}
+	if _, ok := item["required"]; !ok || tool.OutputSchema.Required == nil {
+		t.Error("expected the original output schema to be left unchanged")
+	}
+}
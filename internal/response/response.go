@@ -15,6 +15,11 @@ import (
 // an MCP CallToolResult. This is the standard way to return structured data
 // from MCP tools.
 //
+// When data is written as a JSON object, it is also set as the result's
+// structured content, which clients validate against the tool's output
+// schema. The text content carries the same document for clients that only
+// read text.
+//
 // The data parameter can be any serializable Go value (struct, map, slice, etc.).
 // Returns an error if the data cannot be marshaled to JSON.
 func JSON(data interface{}) (*mcp.CallToolResult, error) {
@@ -23,7 +28,13 @@ func JSON(data interface{}) (*mcp.CallToolResult, error) {
 		return mcp.NewToolResultError(err.Error()), nil
 	}
 
-	return mcp.NewToolResultText(string(content)), nil
+	result := mcp.NewToolResultText(string(content))
+	if len(content) > 0 && content[0] == '{' {
+		// The encoded document is kept rather than data, which the
+		// handler may still hold and change.
+		result.StructuredContent = json.RawMessage(content)
+	}
+	return result, nil
 }
 
 // Error creates an MCP tool response indicating an error occurred.
@@ -45,3 +56,93 @@ func Errorf(format string, args ...any) (*mcp.CallToolResult, error) {
 	message := fmt.Sprintf(format, args...)
 	return mcp.NewToolResultError(message), nil
 }
+
+// PartialMetaKey is the _meta field set to true on results of calls that
+// were cancelled or ran out of time while the handler was still gathering
+// them, so they may be missing objects or carry errors caused by the stop.
+const PartialMetaKey = "partial"
+
+// Partial returns a shallow copy of result marked as partial in its _meta,
+// leaving result itself untouched.
+func Partial(result *mcp.CallToolResult) *mcp.CallToolResult {
+	return WithMeta(result, PartialMetaKey, true)
+}
+
+// WithMeta returns a shallow copy of result with key set to value in its
+// _meta, keeping the fields and progress token it already has. Results may
+// be shared, for example by the dedupe cache, so result itself is left
+// untouched.
+func WithMeta(result *mcp.CallToolResult, key string, value any) *mcp.CallToolResult {
+	marked := *result
+
+	fields := map[string]any{key: value}
+	meta := &mcp.Meta{AdditionalFields: fields}
+	if result.Meta != nil {
+		meta.ProgressToken = result.Meta.ProgressToken
+		for k, v := range result.Meta.AdditionalFields {
+			if _, ok := fields[k]; !ok {
+				fields[k] = v
+			}
+		}
+	}
+	marked.Meta = meta
+
+	return &marked
+}
+
+// RewriteText returns a shallow copy of result with each of its text
+// contents replaced by what rewrite returns for it. Contents rewrite returns
+// false for are kept as they are. The structured content is rewritten from
+// its JSON encoding too, so clients reading it get the same document as
+// those reading the text. Results may be shared, for example by the dedupe
+// cache, so result itself is left untouched.
+func RewriteText(result *mcp.CallToolResult, rewrite func(string) (string, bool)) *mcp.CallToolResult {
+	rewritten := *result
+	rewritten.Content = make([]mcp.Content, len(result.Content))
+	copy(rewritten.Content, result.Content)
+
+	// JSON sets the structured content to the same document as the text,
+	// which is then only rewritten once.
+	replacements := make(map[string]string)
+
+	for i, content := range rewritten.Content {
+		text, ok := content.(mcp.TextContent)
+		if !ok {
+			continue
+		}
+
+		if replaced, ok := rewrite(text.Text); ok {
+			replacements[text.Text] = replaced
+			text.Text = replaced
+			rewritten.Content[i] = text
+		}
+	}
+
+	if result.StructuredContent == nil {
+		return &rewritten
+	}
+
+	var document string
+	if raw, ok := result.StructuredContent.(json.RawMessage); ok {
+		document = string(raw)
+	} else if encoded, err := json.Marshal(result.StructuredContent); err == nil {
+		document = string(encoded)
+	}
+
+	if replaced, ok := replacements[document]; ok {
+		rewritten.StructuredContent = json.RawMessage(replaced)
+	} else if replaced, ok := rewrite(document); ok {
+		rewritten.StructuredContent = json.RawMessage(replaced)
+	}
+
+	return &rewritten
+}
+
+// IsPartial reports whether result was marked by Partial.
+func IsPartial(result *mcp.CallToolResult) bool {
+	if result == nil || result.Meta == nil {
+		return false
+	}
+	partial, _ := result.Meta.AdditionalFields[PartialMetaKey].(bool)
+	return partial
+}
@@ -0,0 +1,104 @@
+package response
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestPartial(t *testing.T) {
+	t.Parallel()
+
+	result := mcp.NewToolResultText("{}")
+	result.Meta = &mcp.Meta{ProgressToken: "progress", AdditionalFields: map[string]any{"cached": true}}
+
+	if IsPartial(result) || IsPartial(nil) {
+		t.Fatal("expected unmarked results not to be partial")
+	}
+
+	marked := Partial(result)
+	if !IsPartial(marked) {
+		t.Fatal("expected the marked result to be partial")
+	}
+	if marked.Meta.ProgressToken != "progress" || marked.Meta.AdditionalFields["cached"] != true {
+		t.Errorf("expected the existing _meta fields to be kept, got %+v", marked.Meta)
+	}
+	if IsPartial(result) {
+		t.Error("expected the original result to be left unchanged")
+	}
+}
+
+func TestWithMeta(t *testing.T) {
+	t.Parallel()
+
+	result := mcp.NewToolResultText("{}")
+	result.Meta = &mcp.Meta{ProgressToken: "progress", AdditionalFields: map[string]any{"cached": true, "total_bytes": 10}}
+
+	marked := WithMeta(result, "total_bytes", 20)
+	if marked.Meta.ProgressToken != "progress" || marked.Meta.AdditionalFields["cached"] != true || marked.Meta.AdditionalFields["total_bytes"] != 20 {
+		t.Errorf("expected the new field to replace the old one and keep the rest, got %+v", marked.Meta)
+	}
+	if result.Meta.AdditionalFields["total_bytes"] != 10 {
+		t.Error("expected the original result to be left unchanged")
+	}
+
+	if marked := WithMeta(mcp.NewToolResultText("{}"), "cached", true); marked.Meta.AdditionalFields["cached"] != true {
+		t.Errorf("expected a result without _meta to get one, got %+v", marked.Meta)
+	}
+}
+
+func TestRewriteText(t *testing.T) {
+	t.Parallel()
+
+	image := mcp.NewImageContent("data", "image/png")
+	result := &mcp.CallToolResult{Content: []mcp.Content{
+		mcp.NewTextContent("web"),
+		mcp.NewTextContent("skip"),
+		image,
+	}}
+
+	rewritten := RewriteText(result, func(text string) (string, bool) {
+		if text == "skip" {
+			return "", false
+		}
+		return "pod/" + text, true
+	})
+
+	want := []mcp.Content{mcp.NewTextContent("pod/web"), mcp.NewTextContent("skip"), image}
+	if !reflect.DeepEqual(rewritten.Content, want) {
+		t.Errorf("expected %+v, got %+v", want, rewritten.Content)
+	}
+	if text := result.Content[0].(mcp.TextContent).Text; text != "web" {
+		t.Errorf("expected the original result to be left unchanged, got %q", text)
+	}
+}
+
+func TestRewriteTextStructuredContent(t *testing.T) {
+	t.Parallel()
+
+	compact := func(text string) (string, bool) {
+		return Reformat(text, Format{Compact: true})
+	}
+
+	fromJSON, _ := JSON(map[string]any{"name": "web"})
+	built := mcp.NewToolResultText("{}")
+	built.StructuredContent = map[string]any{"name": "api"}
+
+	tests := []struct {
+		name   string
+		result *mcp.CallToolResult
+		want   any
+	}{
+		{name: "same document as the text", result: fromJSON, want: json.RawMessage(`{"name":"web"}`)},
+		{name: "separate document", result: built, want: json.RawMessage(`{"name":"api"}`)},
+		{name: "no structured content", result: mcp.NewToolResultText(`{"name": "web"}`), want: nil},
+	}
+
+	for _, tt := range tests {
+		if got := RewriteText(tt.result, compact).StructuredContent; !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: expected structured content %s, got %v", tt.name, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,256 @@
+// Package responselimit keeps tool results within a size budget. A single
+// get_resource on a large custom resource, or a list across a big cluster,
+// can return megabytes of JSON that crowd everything else out of the model's
+// context. Results over the budget are cut at a line boundary, and the full
+// text is kept for a while so the rest can be read page by page with the
+// ContinueTool tool and the continuation token the result carries. Only the
+// caller and session the result was returned to can read the rest of it.
+package responselimit
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/response"
+)
+
+const (
+	// ContinueTool is the name of the tool that returns the next page of a
+	// truncated result. Its own results are paged already, so they are never
+	// truncated again.
+	ContinueTool = "continue_response"
+
+	// TokenMetaKey and TotalBytesMetaKey are the _meta fields set on
+	// truncated results.
+	TokenMetaKey      = "continuation_token"
+	TotalBytesMetaKey = "total_bytes"
+
+	// retention is how long truncated results can be continued.
+	retention = 30 * time.Minute
+
+	// maxStored bounds how many truncated results are kept at once. The
+	// oldest are dropped first.
+	maxStored = 32
+)
+
+// ErrUnknownToken is returned for continuation tokens that are malformed,
+// expired, or were dropped to make room for newer results.
+var ErrUnknownToken = errors.New("the continuation token is unknown or has expired; run the original tool call again")
+
+// ErrOtherCaller is returned for continuation tokens of results returned to
+// another caller or session.
+var ErrOtherCaller = errors.New("the continuation token was issued to another caller or session; run the original tool call again")
+
+// Limiter truncates large results and keeps their full text for paging. It
+// is safe for concurrent use.
+type Limiter struct {
+	maxBytes int
+	identify func(context.Context) (caller, session string)
+
+	// now returns the current time. It defaults to time.Now and is replaced
+	// in tests.
+	now func() time.Time
+
+	mu     sync.Mutex
+	stored map[string]*storedResult
+	order  []string
+}
+
+// storedResult is the full text of a truncated result, with the caller and
+// session it was returned to.
+type storedResult struct {
+	text    string
+	caller  string
+	session string
+	expires time.Time
+}
+
+// New creates a Limiter that cuts results longer than maxBytes. identify
+// names the caller and session of a call from its context, so a truncated
+// result is only continued by the caller it was returned to; it may be nil
+// when neither is known.
+func New(maxBytes int, identify func(context.Context) (caller, session string)) *Limiter {
+	return &Limiter{
+		maxBytes: maxBytes,
+		identify: identify,
+		now:      time.Now,
+		stored:   make(map[string]*storedResult),
+	}
+}
+
+// MaxBytes returns the configured size budget.
+func (l *Limiter) MaxBytes() int {
+	return l.maxBytes
+}
+
+// Wrap returns a handler that truncates the results of the named tool when
+// they are over the size budget.
+func (l *Limiter) Wrap(toolName string, next func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error)) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if toolName == ContinueTool {
+		return next
+	}
+
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		result, err := next(ctx, request)
+		if err != nil || result == nil || result.IsError || len(result.Content) != 1 {
+			return result, err
+		}
+
+		text, ok := result.Content[0].(mcp.TextContent)
+		if !ok || len(text.Text) <= l.maxBytes {
+			return result, nil
+		}
+
+		caller, session := l.owner(ctx)
+		id, err := l.store(text.Text, caller, session)
+		if err != nil {
+			return result, nil //nolint:nilerr // without a continuation the full result is better than none
+		}
+
+		return l.page(id, text.Text, 0, result), nil
+	}
+}
+
+// RemoveOutputSchema returns tool without its output schema, and whether it
+// had one. The pages of a truncated result carry no structured content,
+// which clients validating against an output schema reject, so tools whose
+// results may be truncated must not declare one.
+//
+//nolint:gocritic // mcp.Tool is passed by value the way the MCP server registers it
+func RemoveOutputSchema(tool mcp.Tool) (mcp.Tool, bool) {
+	if tool.OutputSchema.Type == "" && tool.RawOutputSchema == nil {
+		return tool, false
+	}
+
+	tool.OutputSchema = mcp.ToolOutputSchema{}
+	tool.RawOutputSchema = nil
+	return tool, true
+}
+
+// Continue returns the page of a stored result starting at the position the
+// continuation token points to. The result must have been returned to the
+// caller and session of ctx.
+func (l *Limiter) Continue(ctx context.Context, token string) (*mcp.CallToolResult, error) {
+	id, offsetText, ok := strings.Cut(token, ".")
+	offset, err := strconv.Atoi(offsetText)
+	if !ok || err != nil || offset < 0 {
+		return nil, ErrUnknownToken
+	}
+
+	l.mu.Lock()
+	l.sweepLocked()
+	stored, found := l.stored[id]
+	l.mu.Unlock()
+
+	if !found || offset >= len(stored.text) {
+		return nil, ErrUnknownToken
+	}
+	if caller, session := l.owner(ctx); stored.caller != caller || stored.session != session {
+		return nil, ErrOtherCaller
+	}
+
+	return l.page(id, stored.text, offset, &mcp.CallToolResult{}), nil
+}
+
+// page returns a shallow copy of base holding the page of text starting at
+// offset, followed by a note on how to read the next one when text goes on.
+// base is not changed, since results may be shared with the dedupe cache.
+// Its structured content is dropped, since it is as large as the full text.
+func (l *Limiter) page(id, text string, offset int, base *mcp.CallToolResult) *mcp.CallToolResult {
+	end := cut(text, offset, l.maxBytes)
+
+	result := *base
+	result.StructuredContent = nil
+	result.Content = []mcp.Content{mcp.NewTextContent(text[offset:end])}
+	if end == len(text) {
+		result.Content = append(result.Content, mcp.NewTextContent(fmt.Sprintf("[end of response: bytes %d-%d of %d]", offset, end, len(text))))
+		return &result
+	}
+
+	token := id + "." + strconv.Itoa(end)
+	result.Content = append(result.Content, mcp.NewTextContent(fmt.Sprintf(
+		"[response truncated: showing bytes %d-%d of %d. Call %s with continuation_token %q for the next part, or narrow the request with filters, a label selector, or a limit]",
+		offset, end, len(text), ContinueTool, token,
+	)))
+
+	paged := response.WithMeta(&result, TokenMetaKey, token)
+	return response.WithMeta(paged, TotalBytesMetaKey, len(text))
+}
+
+// cut returns where the page of text starting at offset ends: at the last
+// line break within maxBytes, so pages of indented JSON end on whole lines,
+// or at the last full character when that line break would leave the page
+// less than half full.
+func cut(text string, offset, maxBytes int) int {
+	end := offset + maxBytes
+	if end >= len(text) {
+		return len(text)
+	}
+
+	if newline := strings.LastIndexByte(text[offset:end], '\n'); newline >= maxBytes/2 {
+		return offset + newline + 1
+	}
+
+	for end > offset && !utf8.RuneStart(text[end]) {
+		end--
+	}
+	if end == offset {
+		_, size := utf8.DecodeRuneInString(text[offset:])
+		return offset + size
+	}
+	return end
+}
+
+// owner returns the caller and session of a call.
+func (l *Limiter) owner(ctx context.Context) (caller, session string) {
+	if l.identify == nil {
+		return "", ""
+	}
+	return l.identify(ctx)
+}
+
+// store keeps text for continuation by caller and session, and returns its ID.
+func (l *Limiter) store(text, caller, session string) (string, error) {
+	raw := make([]byte, 8)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("failed to generate a continuation ID: %w", err)
+	}
+	id := hex.EncodeToString(raw)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.sweepLocked()
+	for len(l.order) >= maxStored {
+		delete(l.stored, l.order[0])
+		l.order = l.order[1:]
+	}
+
+	l.stored[id] = &storedResult{text: text, caller: caller, session: session, expires: l.now().Add(retention)}
+	l.order = append(l.order, id)
+	return id, nil
+}
+
+// sweepLocked drops expired results. The caller must hold l.mu.
+func (l *Limiter) sweepLocked() {
+	now := l.now()
+	kept := l.order[:0]
+	for _, id := range l.order {
+		if now.After(l.stored[id].expires) {
+			delete(l.stored, id)
+			continue
+		}
+		kept = append(kept, id)
+	}
+	l.order = kept
+}
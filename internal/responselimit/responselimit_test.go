@@ -0,0 +1,246 @@
+package responselimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func textOf(t *testing.T, result *mcp.CallToolResult, index int) string {
+	t.Helper()
+
+	if result == nil || len(result.Content) <= index {
+		t.Fatalf("expected content %d in %+v", index, result)
+	}
+	text, ok := result.Content[index].(mcp.TextContent)
+	if !ok {
+		t.Fatalf("expected text content, got %T", result.Content[index])
+	}
+	return text.Text
+}
+
+// lines builds n numbered lines of about 20 bytes each.
+func lines(n int) string {
+	var b strings.Builder
+	for i := range n {
+		fmt.Fprintf(&b, "  \"line\": %08d,\n", i)
+	}
+	return b.String()
+}
+
+func TestWrapAndContinue(t *testing.T) {
+	t.Parallel()
+
+	limiter := New(100, nil)
+	full := lines(20)
+
+	stored := mcp.NewToolResultText(full)
+	stored.StructuredContent = map[string]any{"full": full}
+	handler := limiter.Wrap("get_resource", func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return stored, nil
+	})
+
+	result, err := handler(context.Background(), mcp.CallToolRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.StructuredContent != nil {
+		t.Error("expected the structured content of a truncated result to be dropped")
+	}
+
+	var pages []string
+	for {
+		page := textOf(t, result, 0)
+		if len(page) > 100 || !strings.HasSuffix(page, "\n") {
+			t.Fatalf("expected a page of whole lines within 100 bytes, got %q", page)
+		}
+		pages = append(pages, page)
+
+		note := textOf(t, result, 1)
+		if strings.HasPrefix(note, "[end of response") {
+			break
+		}
+
+		token, _ := result.Meta.AdditionalFields[TokenMetaKey].(string)
+		if token == "" || !strings.Contains(note, token) {
+			t.Fatalf("expected the note to carry the continuation token %q, got %q", token, note)
+		}
+		if result, err = limiter.Continue(context.Background(), token); err != nil {
+			t.Fatalf("unexpected error continuing: %v", err)
+		}
+	}
+
+	if got := strings.Join(pages, ""); got != full {
+		t.Fatalf("expected the pages to add up to the full result, got %d of %d bytes", len(got), len(full))
+	}
+	if textOf(t, stored, 0) != full || stored.Meta != nil || stored.StructuredContent == nil {
+		t.Error("expected the handler's result to be left unchanged")
+	}
+}
+
+func TestWrapLeavesSmallAndErrorResults(t *testing.T) {
+	t.Parallel()
+
+	limiter := New(100, nil)
+
+	small := mcp.NewToolResultText("ok")
+	failed := mcp.NewToolResultError(strings.Repeat("x", 500))
+	for _, want := range []*mcp.CallToolResult{small, failed} {
+		handler := limiter.Wrap("get_resource", func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return want, nil
+		})
+		if got, _ := handler(context.Background(), mcp.CallToolRequest{}); got != want {
+			t.Errorf("expected %+v to pass through unchanged, got %+v", want, got)
+		}
+	}
+
+	handlerErr := errors.New("boom")
+	handler := limiter.Wrap("get_resource", func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return nil, handlerErr
+	})
+	if _, err := handler(context.Background(), mcp.CallToolRequest{}); !errors.Is(err, handlerErr) {
+		t.Errorf("expected the handler error, got %v", err)
+	}
+}
+
+func TestCut(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		text   string
+		offset int
+		max    int
+		want   int
+	}{
+		{name: "fits", text: "abc\n", max: 10, want: 4},
+		{name: "line boundary", text: "aaaa\nbbbb\ncccc\n", max: 12, want: 10},
+		{name: "no line break", text: "aaaaaaaaaa", max: 4, want: 4},
+		{name: "line break too early", text: "a\nbbbbbbbbbb", max: 8, want: 8},
+		{name: "multibyte character", text: "ééééé", max: 5, want: 4},
+		{name: "from offset", text: "aaaa\nbbbb\ncccc\n", offset: 5, max: 6, want: 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := cut(tt.text, tt.offset, tt.max); got != tt.want {
+				t.Errorf("cut(%q, %d, %d) = %d, want %d", tt.text, tt.offset, tt.max, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestContinueUnknownTokens(t *testing.T) {
+	t.Parallel()
+
+	limiter := New(10, nil)
+	now := time.Now()
+	limiter.now = func() time.Time { return now }
+
+	id, err := limiter.store(lines(5), "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, token := range []string{"", "nope", id, id + ".-1", id + ".100000", "missing.0"} {
+		if _, err := limiter.Continue(context.Background(), token); !errors.Is(err, ErrUnknownToken) {
+			t.Errorf("expected token %q to be unknown, got %v", token, err)
+		}
+	}
+
+	if _, err := limiter.Continue(context.Background(), id+".0"); err != nil {
+		t.Fatalf("expected a stored result to continue, got %v", err)
+	}
+
+	now = now.Add(retention + time.Second)
+	if _, err := limiter.Continue(context.Background(), id+".0"); !errors.Is(err, ErrUnknownToken) {
+		t.Errorf("expected an expired result to be unknown, got %v", err)
+	}
+}
+
+func TestContinueOtherCaller(t *testing.T) {
+	t.Parallel()
+
+	type identity struct{ caller, session string }
+	type identityKey struct{}
+	limiter := New(100, func(ctx context.Context) (string, string) {
+		id, _ := ctx.Value(identityKey{}).(identity)
+		return id.caller, id.session
+	})
+	as := func(caller, session string) context.Context {
+		return context.WithValue(context.Background(), identityKey{}, identity{caller, session})
+	}
+
+	handler := limiter.Wrap("get_resource", func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText(lines(20)), nil
+	})
+	result, err := handler(as("alice@example.com", "session-1"), mcp.CallToolRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, _ := result.Meta.AdditionalFields[TokenMetaKey].(string)
+	if token == "" {
+		t.Fatal("expected the result to be truncated")
+	}
+
+	for _, ctx := range []context.Context{
+		as("bob@example.com", "session-1"),
+		as("alice@example.com", "session-2"),
+		as("", ""),
+	} {
+		if _, err := limiter.Continue(ctx, token); !errors.Is(err, ErrOtherCaller) {
+			t.Errorf("expected another caller to be refused, got %v", err)
+		}
+	}
+
+	if _, err := limiter.Continue(as("alice@example.com", "session-1"), token); err != nil {
+		t.Fatalf("expected the caller to continue its own result, got %v", err)
+	}
+}
+
+func TestStoreDropsOldestResults(t *testing.T) {
+	t.Parallel()
+
+	limiter := New(10, nil)
+	first, err := limiter.store("first", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for range maxStored {
+		if _, err := limiter.store("more", "", ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := limiter.Continue(context.Background(), first+".0"); !errors.Is(err, ErrUnknownToken) {
+		t.Errorf("expected the oldest result to be dropped, got %v", err)
+	}
+}
+
+func TestRemoveOutputSchema(t *testing.T) {
+	t.Parallel()
+
+	tool := mcp.NewTool("get_resource", mcp.WithOutputSchema[struct {
+		Name string `json:"name"`
+	}]())
+	if tool.OutputSchema.Type == "" && tool.RawOutputSchema == nil {
+		t.Fatal("expected the tool to declare an output schema")
+	}
+
+	removed, ok := RemoveOutputSchema(tool)
+	if !ok || removed.OutputSchema.Type != "" || removed.RawOutputSchema != nil {
+		t.Errorf("expected the output schema to be removed, got %+v and %+v", removed.OutputSchema, removed.RawOutputSchema)
+	}
+
+	if _, ok := RemoveOutputSchema(removed); ok {
+		t.Error("expected a tool without an output schema to be left unchanged")
+	}
+}
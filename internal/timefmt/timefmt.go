@@ -0,0 +1,339 @@
+// Package timefmt localizes the timestamps in tool results. Tools report
+// times as RFC3339 in UTC, and models are unreliable at converting those to
+// a user's timezone or at subtracting them from the current time. A
+// Formatter rewrites every RFC3339 timestamp in a JSON result into a
+// configured timezone and, optionally, adds a humanized age such as "3d4h"
+// next to it, without changing anything else in the result. Fields that hold
+// user data, such as annotations, ConfigMap data, and specs, are left as
+// they are, since their timestamps are values the user set.
+package timefmt
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/response"
+)
+
+// AgeSuffix is appended to the key of a timestamp to name the field holding
+// its humanized age.
+const AgeSuffix = "_age"
+
+// userDataKeys name the object fields whose contents are user data rather
+// than fields the server reports. They are copied as is: their timestamps
+// are values in their own right, and an added age field would look like a
+// key the user set.
+var userDataKeys = map[string]bool{
+	"annotations": true,
+	"labels":      true,
+	"data":        true,
+	"stringData":  true,
+	"binaryData":  true,
+	"spec":        true,
+}
+
+// Formatter rewrites the timestamps in JSON tool results. It is safe for
+// concurrent use.
+type Formatter struct {
+	location *time.Location
+	humanize bool
+
+	// now returns the current time. It defaults to time.Now and is replaced
+	// in tests to compute stable ages.
+	now func() time.Time
+}
+
+// New creates a Formatter that renders timestamps in location, or leaves
+// their timezone untouched when location is nil, and adds humanized ages
+// when humanize is true.
+func New(location *time.Location, humanize bool) *Formatter {
+	return &Formatter{
+		location: location,
+		humanize: humanize,
+		now:      time.Now,
+	}
+}
+
+// Wrap returns a handler that localizes the timestamps in the text and
+// structured content of the results of next. Error results and content that is not a JSON
+// object or array are returned as is.
+func (f *Formatter) Wrap(next func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error)) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		result, err := next(ctx, request)
+		if err != nil || result == nil || result.IsError {
+			return result, err //nolint:wrapcheck // errors from the wrapped handler are returned as is
+		}
+
+		return response.RewriteText(result, f.Rewrite), nil
+	}
+}
+
+// Rewrite localizes the timestamps in a JSON document, keeping the order of
+// object keys and the indentation used by the response package. It returns
+// false when the document is not a JSON object or array.
+func (f *Formatter) Rewrite(document string) (string, bool) {
+	trimmed := strings.TrimSpace(document)
+	if trimmed == "" || (trimmed[0] != '{' && trimmed[0] != '[') {
+		return "", false
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(trimmed))
+	decoder.UseNumber()
+
+	root, err := decodeValue(decoder)
+	if err != nil {
+		return "", false
+	}
+	if _, err := decoder.Token(); !errors.Is(err, io.EOF) {
+		return "", false
+	}
+
+	now := f.now()
+	root = f.transform(root, now)
+
+	var compact bytes.Buffer
+	if err := encodeValue(&compact, root); err != nil {
+		return "", false
+	}
+
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, compact.Bytes(), "", "  "); err != nil {
+		return "", false
+	}
+
+	return indented.String(), true
+}
+
+// transform localizes the timestamps in a decoded value. Timestamps that are
+// object values get a sibling age field, unless the object already has a
+// field by that name; timestamps in arrays are only localized. Fields named
+// in userDataKeys are left untouched.
+func (f *Formatter) transform(value any, now time.Time) any {
+	switch v := value.(type) {
+	case *object:
+		result := &object{}
+		for i, key := range v.keys {
+			if userDataKeys[key] {
+				result.add(key, v.values[i])
+				continue
+			}
+			result.add(key, f.transform(v.values[i], now))
+
+			s, ok := v.values[i].(string)
+			if !ok || !f.humanize {
+				continue
+			}
+			t, ok := parseTimestamp(s)
+			if !ok || v.has(key+AgeSuffix) {
+				continue
+			}
+			result.add(key+AgeSuffix, Age(t, now))
+		}
+		return result
+
+	case []any:
+		result := make([]any, len(v))
+		for i := range v {
+			result[i] = f.transform(v[i], now)
+		}
+		return result
+
+	case string:
+		if f.location == nil {
+			return v
+		}
+		t, ok := parseTimestamp(v)
+		if !ok {
+			return v
+		}
+		layout := time.RFC3339
+		if strings.Contains(v, ".") {
+			layout = time.RFC3339Nano
+		}
+		return t.In(f.location).Format(layout)
+	}
+
+	return value
+}
+
+// parseTimestamp parses an RFC3339 timestamp. Only strings that are entirely
+// a timestamp match, so free text that contains one is left alone.
+func parseTimestamp(s string) (time.Time, bool) {
+	if len(s) < len("2006-01-02T15:04:05Z") || s[4] != '-' || s[10] != 'T' {
+		return time.Time{}, false
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// Age renders the time elapsed from t to now with its two largest units,
+// such as "3d4h", "5h12m", or "45s". Times in the future are prefixed with
+// "in ", such as "in 29d23h".
+func Age(t, now time.Time) string {
+	d := now.Sub(t)
+	if d < 0 {
+		return "in " + Humanize(-d)
+	}
+	return Humanize(d)
+}
+
+// Humanize renders a non-negative duration with its two largest units, from
+// days down to seconds, dropping a second unit that is zero.
+func Humanize(d time.Duration) string {
+	d = d.Round(time.Second)
+
+	units := []struct {
+		suffix string
+		size   time.Duration
+	}{
+		{"d", 24 * time.Hour},
+		{"h", time.Hour},
+		{"m", time.Minute},
+		{"s", time.Second},
+	}
+
+	for i, unit := range units {
+		if d < unit.size && i < len(units)-1 {
+			continue
+		}
+
+		result := fmt.Sprintf("%d%s", d/unit.size, unit.suffix)
+		if i < len(units)-1 {
+			next := units[i+1]
+			if rest := (d % unit.size) / next.size; rest > 0 {
+				result += fmt.Sprintf("%d%s", rest, next.suffix)
+			}
+		}
+		return result
+	}
+
+	return "0s"
+}
+
+// object is a decoded JSON object that keeps the order of its keys.
+type object struct {
+	keys   []string
+	values []any
+}
+
+func (o *object) add(key string, value any) {
+	o.keys = append(o.keys, key)
+	o.values = append(o.values, value)
+}
+
+func (o *object) has(key string) bool {
+	for _, k := range o.keys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// decodeValue decodes the next JSON value from decoder, representing objects
+// as *object so their key order is kept.
+func decodeValue(decoder *json.Decoder) (any, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err //nolint:wrapcheck // decoding errors only signal that the content is left as is
+	}
+
+	delim, ok := token.(json.Delim)
+	if !ok {
+		return token, nil
+	}
+
+	switch delim {
+	case '{':
+		result := &object{}
+		for decoder.More() {
+			keyToken, err := decoder.Token()
+			if err != nil {
+				return nil, err //nolint:wrapcheck // decoding errors only signal that the content is left as is
+			}
+			key, ok := keyToken.(string)
+			if !ok {
+				return nil, fmt.Errorf("unexpected object key %v", keyToken)
+			}
+			value, err := decodeValue(decoder)
+			if err != nil {
+				return nil, err
+			}
+			result.add(key, value)
+		}
+		if _, err := decoder.Token(); err != nil {
+			return nil, err //nolint:wrapcheck // decoding errors only signal that the content is left as is
+		}
+		return result, nil
+
+	case '[':
+		result := make([]any, 0)
+		for decoder.More() {
+			value, err := decodeValue(decoder)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, value)
+		}
+		if _, err := decoder.Token(); err != nil {
+			return nil, err //nolint:wrapcheck // decoding errors only signal that the content is left as is
+		}
+		return result, nil
+	}
+
+	return nil, fmt.Errorf("unexpected delimiter %v", delim)
+}
+
+// encodeValue writes a value produced by decodeValue as compact JSON.
+func encodeValue(buf *bytes.Buffer, value any) error {
+	switch v := value.(type) {
+	case *object:
+		buf.WriteByte('{')
+		for i, key := range v.keys {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := encodeValue(buf, key); err != nil {
+				return err
+			}
+			buf.WriteByte(':')
+			if err := encodeValue(buf, v.values[i]); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+		return nil
+
+	case []any:
+		buf.WriteByte('[')
+		for i := range v {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := encodeValue(buf, v[i]); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+		return nil
+	}
+
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return err //nolint:wrapcheck // encoding errors only signal that the content is left as is
+	}
+	buf.Write(encoded)
+	return nil
+}
@@ -0,0 +1,238 @@
+package timefmt
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+	_ "time/tzdata" // the test timezone must load without a system zoneinfo database
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestHumanize(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		duration time.Duration
+		want     string
+	}{
+		{duration: 0, want: "0s"},
+		{duration: 45 * time.Second, want: "45s"},
+		{duration: 90 * time.Second, want: "1m30s"},
+		{duration: 5*time.Hour + 12*time.Minute + 9*time.Second, want: "5h12m"},
+		{duration: 3 * time.Hour, want: "3h"},
+		{duration: 76*time.Hour + 30*time.Minute, want: "3d4h"},
+		{duration: 400 * 24 * time.Hour, want: "400d"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.want, func(t *testing.T) {
+			t.Parallel()
+
+			if got := Humanize(tt.duration); got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestRewrite(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("failed to load timezone: %v", err)
+	}
+
+	document := `{
+  "name": "web",
+  "created": "2024-03-07T08:00:00Z",
+  "events": [
+    {
+      "last_seen": "2024-03-10T11:59:15.5Z",
+      "message": "pulled at 2024-03-10T11:59:15Z"
+    }
+  ],
+  "expires": "2024-04-09T12:00:00Z",
+  "started": "2024-03-10T11:00:00Z",
+  "started_age": "custom",
+  "restarts": 3,
+  "times": ["2024-03-10T11:00:00Z"]
+}`
+
+	tests := []struct {
+		name     string
+		location *time.Location
+		humanize bool
+		want     string
+	}{
+		{
+			name:     "timezone",
+			location: newYork,
+			want: `{
+  "name": "web",
+  "created": "2024-03-07T03:00:00-05:00",
+  "events": [
+    {
+      "last_seen": "2024-03-10T07:59:15.5-04:00",
+      "message": "pulled at 2024-03-10T11:59:15Z"
+    }
+  ],
+  "expires": "2024-04-09T08:00:00-04:00",
+  "started": "2024-03-10T07:00:00-04:00",
+  "started_age": "custom",
+  "restarts": 3,
+  "times": [
+    "2024-03-10T07:00:00-04:00"
+  ]
+}`,
+		},
+		{
+			name:     "humanized ages",
+			humanize: true,
+			want: `{
+  "name": "web",
+  "created": "2024-03-07T08:00:00Z",
+  "created_age": "3d4h",
+  "events": [
+    {
+      "last_seen": "2024-03-10T11:59:15.5Z",
+      "last_seen_age": "45s",
+      "message": "pulled at 2024-03-10T11:59:15Z"
+    }
+  ],
+  "expires": "2024-04-09T12:00:00Z",
+  "expires_age": "in 30d",
+  "started": "2024-03-10T11:00:00Z",
+  "started_age": "custom",
+  "restarts": 3,
+  "times": [
+    "2024-03-10T11:00:00Z"
+  ]
+}`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			formatter := New(tt.location, tt.humanize)
+			formatter.now = func() time.Time { return now }
+
+			got, ok := formatter.Rewrite(document)
+			if !ok {
+				t.Fatal("expected the document to be rewritten")
+			}
+			if got != tt.want {
+				t.Errorf("rewrite mismatch\nwant:\n%s\ngot:\n%s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestRewriteLeavesUserData(t *testing.T) {
+	t.Parallel()
+
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("failed to load timezone: %v", err)
+	}
+
+	formatter := New(newYork, true)
+	formatter.now = func() time.Time { return time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC) }
+
+	document := `{
+  "metadata": {
+    "creationTimestamp": "2024-03-10T11:00:00Z",
+    "annotations": {
+      "deploy.example.com/released-at": "2024-03-01T09:30:00Z"
+    },
+    "labels": {
+      "release": "2024-03-01T09:30:00Z"
+    }
+  },
+  "data": {
+    "cutoff": "2024-03-01T00:00:00Z"
+  },
+  "spec": {
+    "schedule": {
+      "start": "2024-03-01T00:00:00Z"
+    }
+  }
+}`
+
+	want := `{
+  "metadata": {
+    "creationTimestamp": "2024-03-10T07:00:00-04:00",
+    "creationTimestamp_age": "1h",
+    "annotations": {
+      "deploy.example.com/released-at": "2024-03-01T09:30:00Z"
+    },
+    "labels": {
+      "release": "2024-03-01T09:30:00Z"
+    }
+  },
+  "data": {
+    "cutoff": "2024-03-01T00:00:00Z"
+  },
+  "spec": {
+    "schedule": {
+      "start": "2024-03-01T00:00:00Z"
+    }
+  }
+}`
+
+	got, ok := formatter.Rewrite(document)
+	if !ok {
+		t.Fatal("expected the document to be rewritten")
+	}
+	if got != want {
+		t.Errorf("rewrite mismatch\nwant:\n%s\ngot:\n%s", want, got)
+	}
+}
+
+func TestWrap(t *testing.T) {
+	t.Parallel()
+
+	stored := mcp.NewToolResultText(`{"created": "2024-03-07T08:00:00Z"}`)
+	stored.StructuredContent = map[string]any{"created": "2024-03-07T08:00:00Z"}
+	formatter := New(time.UTC, true)
+	formatter.now = func() time.Time { return time.Date(2024, 3, 7, 9, 0, 0, 0, time.UTC) }
+
+	handler := formatter.Wrap(func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return stored, nil
+	})
+
+	result, err := handler(context.Background(), mcp.CallToolRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := "{\n  \"created\": \"2024-03-07T08:00:00Z\",\n  \"created_age\": \"1h\"\n}"
+	if got := result.Content[0].(mcp.TextContent).Text; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+	if got, _ := result.StructuredContent.(json.RawMessage); string(got) != want {
+		t.Errorf("expected structured content %q, got %q", want, got)
+	}
+	if got := stored.Content[0].(mcp.TextContent).Text; got != `{"created": "2024-03-07T08:00:00Z"}` {
+		t.Errorf("the wrapped handler's result must not be modified, got %q", got)
+	}
+
+	errorHandler := formatter.Wrap(func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultError("failed at 2024-03-07T08:00:00Z"), nil
+	})
+	result, _ = errorHandler(context.Background(), mcp.CallToolRequest{})
+	if got := result.Content[0].(mcp.TextContent).Text; got != "failed at 2024-03-07T08:00:00Z" {
+		t.Errorf("expected error results to be returned as is, got %q", got)
+	}
+
+	if _, ok := formatter.Rewrite("plain text 2024-03-07T08:00:00Z"); ok {
+		t.Error("expected text that is not JSON to be left alone")
+	}
+}
@@ -0,0 +1,268 @@
+package toolschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Bind validates the raw tool call arguments against the schema declared by
+// dst's struct tags and then decodes them into dst, which must be a pointer
+// to a struct. Validation reports the first problem found with a message
+// naming the offending argument, such as "max_lines must be an integer" or
+// "kind must be one of \"node\", \"pod\"", so models can correct their call.
+//
+// Arguments that are absent are not validated beyond the required check, so
+// zero values still mean "use the default" to the handler.
+func Bind(arguments map[string]any, dst any) error {
+	t := reflect.TypeOf(dst)
+	if t == nil || t.Kind() != reflect.Pointer || t.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("toolschema: Bind requires a pointer to a struct, got %T", dst)
+	}
+
+	if err := validateObject(t.Elem(), arguments, ""); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(arguments)
+	if err != nil {
+		return fmt.Errorf("failed to encode arguments: %w", err)
+	}
+
+	if err := json.Unmarshal(data, dst); err != nil {
+		return fmt.Errorf("failed to decode arguments: %w", err)
+	}
+
+	return nil
+}
+
+// validateObject checks each field of struct type t against its value in
+// args. The prefix is prepended to argument names in error messages so
+// nested values read like "ports[0].pod_port".
+func validateObject(t reflect.Type, args map[string]any, prefix string) error {
+	for i := range t.NumField() {
+		field := t.Field(i)
+
+		if field.Anonymous && field.Tag.Get("json") == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+
+			if embedded.Kind() == reflect.Struct {
+				if err := validateObject(embedded, args, prefix); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+
+		name := fieldName(field)
+		if name == "" {
+			continue
+		}
+
+		path := prefix + name
+		required := field.Tag.Get("required") == "true"
+
+		value, present := args[name]
+		if !present || value == nil {
+			if required {
+				return fmt.Errorf("%s is required", path)
+			}
+			continue
+		}
+
+		if err := validateValue(field.Type, field.Tag, value, path, required); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateValue checks that value matches the JSON type of t and satisfies
+// the enum, minimum, and maximum constraints from tag.
+func validateValue(t reflect.Type, tag reflect.StructTag, value any, path string, required bool) error {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("%s must be a string, got %s", path, describe(value))
+		}
+
+		if required && s == "" {
+			return fmt.Errorf("%s is required", path)
+		}
+
+		if raw, ok := tag.Lookup("enum"); ok && s != "" {
+			return checkEnum(path, raw, s, strconv.Quote)
+		}
+
+	case reflect.Bool:
+		if _, ok := value.(bool); !ok {
+			return fmt.Errorf("%s must be a boolean, got %s", path, describe(value))
+		}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, ok := toFloat(value)
+		if !ok || n != math.Trunc(n) {
+			return fmt.Errorf("%s must be an integer, got %s", path, describe(value))
+		}
+
+		if raw, ok := tag.Lookup("enum"); ok {
+			if err := checkEnum(path, raw, strconv.FormatInt(int64(n), 10), func(s string) string { return s }); err != nil {
+				return err
+			}
+		}
+
+		return checkBounds(path, tag, n)
+
+	case reflect.Float32, reflect.Float64:
+		n, ok := toFloat(value)
+		if !ok {
+			return fmt.Errorf("%s must be a number, got %s", path, describe(value))
+		}
+
+		return checkBounds(path, tag, n)
+
+	case reflect.Slice, reflect.Array:
+		items, ok := value.([]any)
+		if !ok {
+			return fmt.Errorf("%s must be an array, got %s", path, describe(value))
+		}
+
+		for i, item := range items {
+			itemPath := fmt.Sprintf("%s[%d]", path, i)
+			if item == nil {
+				return fmt.Errorf("%s must not be null", itemPath)
+			}
+			if err := validateValue(t.Elem(), "", item, itemPath, false); err != nil {
+				return err
+			}
+		}
+
+	case reflect.Struct:
+		obj, ok := value.(map[string]any)
+		if !ok {
+			return fmt.Errorf("%s must be an object, got %s", path, describe(value))
+		}
+
+		return validateObject(t, obj, path+".")
+
+	default:
+		// Free-form values are passed through to the decoder unchecked.
+	}
+
+	return nil
+}
+
+// checkEnum reports an error when value is not one of the comma-separated
+// values in raw. The quote function formats values in the error message.
+func checkEnum(path, raw, value string, quote func(string) string) error {
+	allowed := strings.Split(raw, ",")
+	for i := range allowed {
+		allowed[i] = strings.TrimSpace(allowed[i])
+		if allowed[i] == value {
+			return nil
+		}
+	}
+
+	formatted := make([]string, len(allowed))
+	for i, a := range allowed {
+		formatted[i] = quote(a)
+	}
+
+	return fmt.Errorf("%s must be one of %s, got %s", path, strings.Join(formatted, ", "), quote(value))
+}
+
+// checkBounds enforces the minimum and maximum tags on a numeric value.
+func checkBounds(path string, tag reflect.StructTag, n float64) error {
+	minimum, hasMin := parseBound(tag, "minimum")
+	maximum, hasMax := parseBound(tag, "maximum")
+
+	switch {
+	case hasMin && hasMax && (n < minimum || n > maximum):
+		return fmt.Errorf("%s must be between %s and %s, got %s", path, formatNumber(minimum), formatNumber(maximum), formatNumber(n))
+	case hasMin && n < minimum:
+		return fmt.Errorf("%s must be at least %s, got %s", path, formatNumber(minimum), formatNumber(n))
+	case hasMax && n > maximum:
+		return fmt.Errorf("%s must be at most %s, got %s", path, formatNumber(maximum), formatNumber(n))
+	}
+
+	return nil
+}
+
+func parseBound(tag reflect.StructTag, name string) (float64, bool) {
+	raw, ok := tag.Lookup(name)
+	if !ok {
+		return 0, false
+	}
+
+	n, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return 0, false
+	}
+
+	return n, true
+}
+
+// toFloat converts a decoded JSON number (or a Go numeric value, when
+// arguments are built programmatically) to a float64.
+func toFloat(value any) (float64, bool) {
+	switch v := value.(type) {
+	case json.Number:
+		n, err := v.Float64()
+		return n, err == nil
+	case float64:
+		return v, true
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() { //nolint:exhaustive // only numeric kinds are convertible
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), true
+	case reflect.Float32:
+		return rv.Float(), true
+	}
+
+	return 0, false
+}
+
+// describe renders a value and its JSON type for error messages.
+func describe(value any) string {
+	switch v := value.(type) {
+	case string:
+		return "string " + strconv.Quote(v)
+	case bool:
+		return "boolean " + strconv.FormatBool(v)
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+
+	if n, ok := toFloat(value); ok {
+		return "number " + formatNumber(n)
+	}
+
+	return fmt.Sprintf("%T", value)
+}
+
+func formatNumber(n float64) string {
+	return strconv.FormatFloat(n, 'f', -1, 64)
+}
@@ -0,0 +1,157 @@
+package toolschema
+
+import (
+	"testing"
+)
+
+type bindPort struct {
+	PodPort   int `json:"pod_port" required:"true" minimum:"1" maximum:"65535"`
+	LocalPort int `json:"local_port" minimum:"0" maximum:"65535"`
+}
+
+type bindParams struct {
+	Name      string     `json:"name" required:"true"`
+	Kind      string     `json:"kind,omitempty" enum:"node,pod"`
+	MaxLines  int        `json:"max_lines,omitempty" minimum:"0"`
+	Threshold int        `json:"threshold,omitempty" minimum:"1" maximum:"100"`
+	Ratio     float64    `json:"ratio,omitempty" maximum:"1"`
+	TitleOnly *bool      `json:"title_only,omitempty"`
+	Ports     []bindPort `json:"ports,omitempty"`
+}
+
+func TestBind(t *testing.T) {
+	t.Parallel()
+
+	var params bindParams
+	err := Bind(map[string]any{
+		"name":       "web",
+		"kind":       "pod",
+		"max_lines":  float64(100),
+		"threshold":  float64(90),
+		"ratio":      0.5,
+		"title_only": false,
+		"ports":      []any{map[string]any{"pod_port": float64(8080)}},
+	}, &params)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if params.Name != "web" || params.Kind != "pod" || params.MaxLines != 100 || params.Threshold != 90 || params.Ratio != 0.5 {
+		t.Errorf("unexpected params: %#v", params)
+	}
+	if params.TitleOnly == nil || *params.TitleOnly {
+		t.Errorf("expected title_only=false, got %v", params.TitleOnly)
+	}
+	if len(params.Ports) != 1 || params.Ports[0].PodPort != 8080 {
+		t.Errorf("unexpected ports: %#v", params.Ports)
+	}
+}
+
+func TestBind_Errors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		args    map[string]any
+		wantErr string
+	}{
+		{
+			name:    "missing required",
+			args:    map[string]any{},
+			wantErr: "name is required",
+		},
+		{
+			name:    "empty required string",
+			args:    map[string]any{"name": ""},
+			wantErr: "name is required",
+		},
+		{
+			name:    "string for integer",
+			args:    map[string]any{"name": "web", "max_lines": "100"},
+			wantErr: `max_lines must be an integer, got string "100"`,
+		},
+		{
+			name:    "fractional integer",
+			args:    map[string]any{"name": "web", "max_lines": 1.5},
+			wantErr: "max_lines must be an integer, got number 1.5",
+		},
+		{
+			name:    "below minimum",
+			args:    map[string]any{"name": "web", "max_lines": float64(-1)},
+			wantErr: "max_lines must be at least 0, got -1",
+		},
+		{
+			name:    "outside range",
+			args:    map[string]any{"name": "web", "threshold": float64(150)},
+			wantErr: "threshold must be between 1 and 100, got 150",
+		},
+		{
+			name:    "above maximum",
+			args:    map[string]any{"name": "web", "ratio": 1.5},
+			wantErr: "ratio must be at most 1, got 1.5",
+		},
+		{
+			name:    "enum",
+			args:    map[string]any{"name": "web", "kind": "deployment"},
+			wantErr: `kind must be one of "node", "pod", got "deployment"`,
+		},
+		{
+			name:    "string for boolean",
+			args:    map[string]any{"name": "web", "title_only": "true"},
+			wantErr: `title_only must be a boolean, got string "true"`,
+		},
+		{
+			name:    "object for array",
+			args:    map[string]any{"name": "web", "ports": map[string]any{}},
+			wantErr: "ports must be an array, got object",
+		},
+		{
+			name:    "nested required",
+			args:    map[string]any{"name": "web", "ports": []any{map[string]any{"local_port": float64(80)}}},
+			wantErr: "ports[0].pod_port is required",
+		},
+		{
+			name:    "nested range",
+			args:    map[string]any{"name": "web", "ports": []any{map[string]any{"pod_port": float64(70000)}}},
+			wantErr: "ports[0].pod_port must be between 1 and 65535, got 70000",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var params bindParams
+			err := Bind(tt.args, &params)
+			if err == nil {
+				t.Fatalf("expected error %q, got nil", tt.wantErr)
+			}
+			if err.Error() != tt.wantErr {
+				t.Errorf("expected error %q, got %q", tt.wantErr, err.Error())
+			}
+		})
+	}
+}
+
+func TestBind_AbsentValuesSkipBounds(t *testing.T) {
+	t.Parallel()
+
+	var params bindParams
+	if err := Bind(map[string]any{"name": "web"}, &params); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if params.Threshold != 0 {
+		t.Errorf("expected zero threshold when omitted, got %d", params.Threshold)
+	}
+}
+
+func TestBind_RequiresStructPointer(t *testing.T) {
+	t.Parallel()
+
+	var params bindParams
+	if err := Bind(map[string]any{}, params); err == nil {
+		t.Error("expected an error for a non-pointer destination")
+	}
+}
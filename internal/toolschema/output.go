@@ -0,0 +1,190 @@
+package toolschema
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// modulePath prefixes the packages of this server. Structs declared in them
+// are described field by field, while those of other modules, such as the
+// Kubernetes API types, are described as plain objects: their schemas are
+// large, often recursive, and documented elsewhere.
+const modulePath = "github.com/patrickdappollonio/mcp-kubernetes-ro/"
+
+// Output returns an mcp.ToolOption that sets the tool's output schema to the
+// schema generated from T, the struct a handler returns as its JSON result.
+func Output[T any]() mcp.ToolOption {
+	schema := OutputFor[T]()
+
+	return func(t *mcp.Tool) {
+		t.OutputSchema = schema
+	}
+}
+
+// OutputFor generates the output schema for T, which must be a struct type.
+//
+// Property names and descriptions come from the `json` and `description`
+// tags, as for input schemas. Fields without omitempty or omitzero are always
+// written, so they are required. Slices, maps, and pointers without either
+// option may also be written as null, and their schemas allow it. Interface
+// fields and types with custom JSON encodings, other than timestamps, accept
+// any value.
+func OutputFor[T any]() mcp.ToolOutputSchema {
+	t := reflect.TypeFor[T]()
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("toolschema: %s is not a struct", t))
+	}
+
+	properties, required := outputProperties(t, map[reflect.Type]bool{t: true})
+
+	return mcp.ToolOutputSchema{
+		Type:       "object",
+		Properties: properties,
+		Required:   required,
+	}
+}
+
+// outputProperties builds the properties and required list of a struct
+// type. seen holds the structs being described, to stop at recursive types.
+func outputProperties(t reflect.Type, seen map[reflect.Type]bool) (map[string]any, []string) {
+	properties := make(map[string]any)
+	var required []string
+
+	for i := range t.NumField() {
+		field := t.Field(i)
+
+		if field.Anonymous && field.Tag.Get("json") == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+
+			if embedded.Kind() == reflect.Struct {
+				props, req := outputProperties(embedded, seen)
+				for name, prop := range props {
+					properties[name] = prop
+				}
+				required = append(required, req...)
+				continue
+			}
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+
+		name := fieldName(field)
+		if name == "" {
+			continue
+		}
+
+		_, options, _ := strings.Cut(field.Tag.Get("json"), ",")
+		optional := false
+		for _, option := range strings.Split(options, ",") {
+			if option == "omitempty" || option == "omitzero" {
+				optional = true
+			}
+		}
+
+		schema := outputTypeSchema(field.Type, seen)
+		if !optional {
+			schema = nullable(field.Type, schema)
+			required = append(required, name)
+		}
+		if description := field.Tag.Get("description"); description != "" {
+			schema["description"] = description
+		}
+		properties[name] = schema
+	}
+
+	return properties, required
+}
+
+var (
+	jsonMarshaler = reflect.TypeFor[json.Marshaler]()
+	textMarshaler = reflect.TypeFor[encoding.TextMarshaler]()
+	timeTypes     = map[reflect.Type]bool{
+		reflect.TypeFor[time.Time]():        true,
+		reflect.TypeFor[metav1.Time]():      true,
+		reflect.TypeFor[metav1.MicroTime](): true,
+	}
+)
+
+// outputTypeSchema maps a Go type to the schema of its JSON encoding.
+func outputTypeSchema(t reflect.Type, seen map[reflect.Type]bool) map[string]any {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if timeTypes[t] {
+		return map[string]any{"type": "string", "format": "date-time"}
+	}
+	if t.Implements(jsonMarshaler) || reflect.PointerTo(t).Implements(jsonMarshaler) ||
+		t.Implements(textMarshaler) || reflect.PointerTo(t).Implements(textMarshaler) {
+		return map[string]any{}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			// Byte slices are written as base64 strings.
+			return map[string]any{"type": "string"}
+		}
+		return map[string]any{"type": "array", "items": nullable(t.Elem(), outputTypeSchema(t.Elem(), seen))}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": nullable(t.Elem(), outputTypeSchema(t.Elem(), seen))}
+	case reflect.Struct:
+		if seen[t] || !strings.HasPrefix(t.PkgPath(), modulePath) {
+			return map[string]any{"type": "object"}
+		}
+
+		seen[t] = true
+		properties, required := outputProperties(t, seen)
+		delete(seen, t)
+
+		schema := map[string]any{"type": "object", "properties": properties}
+		if len(required) > 0 {
+			schema["required"] = required
+		}
+		return schema
+	default:
+		return map[string]any{}
+	}
+}
+
+// nullable returns schema, extended to accept null when values of t can be
+// written as null: nil slices, maps, and pointers.
+func nullable(t reflect.Type, schema map[string]any) map[string]any {
+	switch t.Kind() {
+	case reflect.Slice, reflect.Map, reflect.Pointer:
+	default:
+		return schema
+	}
+
+	kind, ok := schema["type"].(string)
+	if !ok {
+		return schema
+	}
+	schema["type"] = []string{kind, "null"}
+	return schema
+}
@@ -0,0 +1,96 @@
+package toolschema
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type outputItem struct {
+	Name     string        `json:"name" description:"Item name"`
+	Children []*outputItem `json:"children,omitempty"`
+}
+
+type outputSample struct {
+	common
+
+	Count     int               `json:"count"`
+	Ratio     float64           `json:"ratio,omitempty"`
+	Items     []outputItem      `json:"items"`
+	Labels    map[string]string `json:"labels,omitempty"`
+	Owner     *outputItem       `json:"owner"`
+	Created   metav1.Time       `json:"created"`
+	Seen      *time.Time        `json:"seen,omitempty"`
+	Capacity  resource.Quantity `json:"capacity"`
+	Pod       corev1.Pod        `json:"pod"`
+	Value     any               `json:"value,omitempty"`
+	Ignored   string            `json:"-"`
+	unexposed string
+}
+
+func TestOutputFor(t *testing.T) {
+	t.Parallel()
+
+	item := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"name": map[string]any{"type": "string", "description": "Item name"},
+			"children": map[string]any{
+				"type":  "array",
+				"items": map[string]any{"type": []string{"object", "null"}},
+			},
+		},
+		"required": []string{"name"},
+	}
+
+	owner := map[string]any{
+		"type":       []string{"object", "null"},
+		"properties": item["properties"],
+		"required":   item["required"],
+	}
+
+	want := mcp.ToolOutputSchema{
+		Type: "object",
+		Properties: map[string]any{
+			"context":  map[string]any{"type": "string", "description": "Context to use"},
+			"count":    map[string]any{"type": "integer"},
+			"ratio":    map[string]any{"type": "number"},
+			"items":    map[string]any{"type": []string{"array", "null"}, "items": item},
+			"labels":   map[string]any{"type": "object", "additionalProperties": map[string]any{"type": "string"}},
+			"owner":    owner,
+			"created":  map[string]any{"type": "string", "format": "date-time"},
+			"seen":     map[string]any{"type": "string", "format": "date-time"},
+			"capacity": map[string]any{},
+			"pod":      map[string]any{"type": "object"},
+			"value":    map[string]any{},
+		},
+		Required: []string{"count", "items", "owner", "created", "capacity", "pod"},
+	}
+
+	got := OutputFor[outputSample]()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("schema mismatch\nwant: %#v\ngot:  %#v", want, got)
+	}
+
+	tool := mcp.NewTool("sample", Output[outputSample]())
+	if !reflect.DeepEqual(tool.OutputSchema, want) {
+		t.Errorf("Output did not set the generated schema: %#v", tool.OutputSchema)
+	}
+}
+
+func TestOutputFor_NonStruct(t *testing.T) {
+	t.Parallel()
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected a panic")
+		}
+	}()
+
+	OutputFor[[]string]()
+}
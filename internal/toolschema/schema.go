@@ -0,0 +1,229 @@
+// Package toolschema generates MCP tool input schemas from the params structs
+// handlers bind their arguments into, so the advertised schema and the fields
+// actually read by a handler cannot drift apart. Output schemas are generated
+// the same way from the result structs handlers return, with Output.
+//
+// Property names come from the `json` tag. The following struct tags enrich
+// each property:
+//
+//	description:"..."  human-readable description shown to the model
+//	required:"true"    adds the property to the schema's required list
+//	enum:"a,b,c"       restricts the value to a comma-separated set
+//	default:"..."      documents the value used when the property is omitted
+//	minimum:"1"        lower bound for numeric properties
+//	maximum:"100"      upper bound for numeric properties
+//
+// Go types map to JSON schema types: strings to "string", integers to
+// "integer", floats to "number", bools to "boolean", slices to "array", and
+// structs to "object". Pointers are unwrapped, and embedded structs have their
+// fields promoted.
+//
+// Bind enforces the same tags on incoming tool calls, so handlers receive
+// arguments that already match the advertised schema.
+//
+// Invalid tags are programming errors and cause a panic when the schema is
+// built, which happens once at startup when tools are registered.
+package toolschema
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Input returns an mcp.ToolOption that sets the tool's input schema to the
+// schema generated from T, which must be a struct type.
+func Input[T any]() mcp.ToolOption {
+	schema := For[T]()
+
+	return func(t *mcp.Tool) {
+		t.InputSchema = schema
+	}
+}
+
+// For generates the input schema for T, which must be a struct type.
+func For[T any]() mcp.ToolInputSchema {
+	t := reflect.TypeFor[T]()
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("toolschema: %s is not a struct", t))
+	}
+
+	properties, required := objectProperties(t)
+
+	return mcp.ToolInputSchema{
+		Type:       "object",
+		Properties: properties,
+		Required:   required,
+	}
+}
+
+// objectProperties builds the properties and required list for a struct type.
+func objectProperties(t reflect.Type) (map[string]any, []string) {
+	properties := make(map[string]any)
+	var required []string
+
+	for i := range t.NumField() {
+		field := t.Field(i)
+
+		if field.Anonymous && field.Tag.Get("json") == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+
+			if embedded.Kind() == reflect.Struct {
+				props, req := objectProperties(embedded)
+				for name, prop := range props {
+					properties[name] = prop
+				}
+				required = append(required, req...)
+				continue
+			}
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+
+		name := fieldName(field)
+		if name == "" {
+			continue
+		}
+
+		properties[name] = fieldSchema(t, field)
+
+		if field.Tag.Get("required") == "true" {
+			required = append(required, name)
+		}
+	}
+
+	return properties, required
+}
+
+// fieldName returns the JSON property name for a field, or an empty string
+// when the field is excluded from JSON.
+func fieldName(field reflect.StructField) string {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return ""
+	}
+
+	name, _, _ := strings.Cut(tag, ",")
+	if name == "" {
+		return field.Name
+	}
+
+	return name
+}
+
+// fieldSchema builds the schema for a single struct field, including the
+// constraints declared through its tags.
+func fieldSchema(parent reflect.Type, field reflect.StructField) map[string]any {
+	schema := typeSchema(field.Type)
+
+	fail := func(tag string, err error) {
+		panic(fmt.Sprintf("toolschema: %s.%s: invalid %s tag: %v", parent.Name(), field.Name, tag, err))
+	}
+
+	if description := field.Tag.Get("description"); description != "" {
+		schema["description"] = description
+	}
+
+	if raw, ok := field.Tag.Lookup("enum"); ok {
+		values := make([]any, 0)
+		for _, item := range strings.Split(raw, ",") {
+			value, err := parseValue(field.Type, strings.TrimSpace(item))
+			if err != nil {
+				fail("enum", err)
+			}
+			values = append(values, value)
+		}
+		schema["enum"] = values
+	}
+
+	if raw, ok := field.Tag.Lookup("default"); ok {
+		value, err := parseValue(field.Type, raw)
+		if err != nil {
+			fail("default", err)
+		}
+		schema["default"] = value
+	}
+
+	for _, bound := range []string{"minimum", "maximum"} {
+		raw, ok := field.Tag.Lookup(bound)
+		if !ok {
+			continue
+		}
+
+		if t := schema["type"]; t != "integer" && t != "number" {
+			fail(bound, fmt.Errorf("%s is only valid on numeric fields", bound))
+		}
+
+		value, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			fail(bound, err)
+		}
+		schema[bound] = value
+	}
+
+	return schema
+}
+
+// typeSchema maps a Go type to its JSON schema type.
+func typeSchema(t reflect.Type) map[string]any {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": typeSchema(t.Elem())}
+	case reflect.Struct:
+		properties, required := objectProperties(t)
+		schema := map[string]any{"type": "object", "properties": properties}
+		if len(required) > 0 {
+			schema["required"] = required
+		}
+		return schema
+	default:
+		return map[string]any{"type": "object"}
+	}
+}
+
+// parseValue converts a tag value to the Go value matching the field's type,
+// so enum and default values are emitted with the correct JSON type.
+func parseValue(t reflect.Type, raw string) (any, error) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return raw, nil
+	case reflect.Bool:
+		return strconv.ParseBool(raw) //nolint:wrapcheck // wrapped by the caller with the field name
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.ParseInt(raw, 10, 64) //nolint:wrapcheck // wrapped by the caller with the field name
+	case reflect.Float32, reflect.Float64:
+		return strconv.ParseFloat(raw, 64) //nolint:wrapcheck // wrapped by the caller with the field name
+	default:
+		return nil, fmt.Errorf("values are not supported for %s fields", t.Kind())
+	}
+}
@@ -0,0 +1,138 @@
+package toolschema
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+type nested struct {
+	Port  int `json:"port" description:"Port number" required:"true" minimum:"1" maximum:"65535"`
+	Label string
+}
+
+type common struct {
+	Context string `json:"context,omitempty" description:"Context to use"`
+}
+
+type sample struct {
+	common
+
+	Name      string   `json:"name" description:"Resource name" required:"true"`
+	Limit     int      `json:"limit,omitempty" default:"10" minimum:"0"`
+	Ratio     float64  `json:"ratio,omitempty"`
+	TitleOnly *bool    `json:"title_only,omitempty" default:"true"`
+	Kind      string   `json:"kind,omitempty" enum:"node, pod"`
+	Tags      []string `json:"tags,omitempty"`
+	Ports     []nested `json:"ports,omitempty"`
+	Ignored   string   `json:"-"`
+}
+
+func TestFor(t *testing.T) {
+	t.Parallel()
+
+	got := For[sample]()
+
+	want := mcp.ToolInputSchema{
+		Type: "object",
+		Properties: map[string]any{
+			"context":    map[string]any{"type": "string", "description": "Context to use"},
+			"name":       map[string]any{"type": "string", "description": "Resource name"},
+			"limit":      map[string]any{"type": "integer", "default": int64(10), "minimum": float64(0)},
+			"ratio":      map[string]any{"type": "number"},
+			"title_only": map[string]any{"type": "boolean", "default": true},
+			"kind":       map[string]any{"type": "string", "enum": []any{"node", "pod"}},
+			"tags":       map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+			"ports": map[string]any{
+				"type": "array",
+				"items": map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"port":  map[string]any{"type": "integer", "description": "Port number", "minimum": float64(1), "maximum": float64(65535)},
+						"Label": map[string]any{"type": "string"},
+					},
+					"required": []string{"port"},
+				},
+			},
+		},
+		Required: []string{"name"},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("schema mismatch\nwant: %#v\ngot:  %#v", want, got)
+	}
+}
+
+func TestFor_EmptyStruct(t *testing.T) {
+	t.Parallel()
+
+	got := For[struct{}]()
+	if got.Type != "object" || len(got.Properties) != 0 || got.Required != nil {
+		t.Errorf("expected empty object schema, got %#v", got)
+	}
+}
+
+func TestInput(t *testing.T) {
+	t.Parallel()
+
+	tool := mcp.NewTool("sample", Input[sample]())
+	if !reflect.DeepEqual(tool.InputSchema, For[sample]()) {
+		t.Errorf("Input did not set the generated schema: %#v", tool.InputSchema)
+	}
+}
+
+func TestFor_InvalidTags(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		build func()
+	}{
+		{
+			name: "non-struct",
+			build: func() {
+				For[string]()
+			},
+		},
+		{
+			name: "bad default",
+			build: func() {
+				For[struct {
+					Limit int `json:"limit" default:"ten"`
+				}]()
+			},
+		},
+		{
+			name: "bad enum",
+			build: func() {
+				For[struct {
+					Flag bool `json:"flag" enum:"yes,no"`
+				}]()
+			},
+		},
+		{
+			name: "minimum on string",
+			build: func() {
+				For[struct {
+					Name string `json:"name" minimum:"1"`
+				}]()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected a panic")
+				}
+			}()
+
+			tt.build()
+		})
+	}
+}
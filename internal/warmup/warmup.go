@@ -0,0 +1,156 @@
+// Package warmup prefetches commonly used cluster data when the server starts
+// or when the first client connects. The first request to a cluster pays for
+// TLS handshakes, credential plugins (such as cloud provider token helpers),
+// and kubeconfig parsing; running a handful of cheap reads concurrently ahead
+// of time moves that latency out of the first interactive tool calls.
+package warmup
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/kubernetes"
+)
+
+// Source provides the reads performed during warm-up. It is satisfied by
+// *kubernetes.Client.
+type Source interface {
+	ListContexts() ([]kubernetes.KubeContext, error)
+	DiscoverResources(ctx context.Context) ([]*metav1.APIResourceList, error)
+	ListResources(ctx context.Context, gvr schema.GroupVersionResource, namespace string, opts metav1.ListOptions) (*unstructured.UnstructuredList, error)
+	ListNodes(ctx context.Context, opts metav1.ListOptions) (*corev1.NodeList, error)
+}
+
+// Result reports the outcome of a single warm-up step.
+type Result struct {
+	// Name identifies the step: "contexts", "discovery", "namespaces", or "nodes".
+	Name string
+
+	// Duration is how long the step took.
+	Duration time.Duration
+
+	// Err is the error returned by the step, if any.
+	Err error
+}
+
+// Warmer runs the warm-up steps at most once. It is safe for concurrent use.
+type Warmer struct {
+	source  Source
+	timeout time.Duration
+	out     io.Writer
+
+	once sync.Once
+	done chan struct{}
+}
+
+// New creates a Warmer that reads from source, giving up on steps still
+// running after timeout. Progress is logged to out.
+func New(source Source, timeout time.Duration, out io.Writer) *Warmer {
+	return &Warmer{
+		source:  source,
+		timeout: timeout,
+		out:     out,
+		done:    make(chan struct{}),
+	}
+}
+
+// Start runs the warm-up in the background the first time it is called and
+// does nothing afterwards. It never blocks; use Wait to block until the
+// warm-up finishes.
+func (w *Warmer) Start() {
+	w.once.Do(func() {
+		go func() {
+			defer close(w.done)
+
+			ctx, cancel := context.WithTimeout(context.Background(), w.timeout)
+			defer cancel()
+
+			start := time.Now()
+			results := Run(ctx, w.source)
+
+			parts := make([]string, 0, len(results))
+			for _, result := range results {
+				if result.Err != nil {
+					parts = append(parts, fmt.Sprintf("%s failed after %s: %v", result.Name, result.Duration.Round(time.Millisecond), result.Err))
+					continue
+				}
+				parts = append(parts, fmt.Sprintf("%s in %s", result.Name, result.Duration.Round(time.Millisecond)))
+			}
+
+			fmt.Fprintf(w.out, "Warm-up finished in %s (%s)\n", time.Since(start).Round(time.Millisecond), strings.Join(parts, ", "))
+		}()
+	})
+}
+
+// Wait blocks until a started warm-up finishes or ctx is done.
+func (w *Warmer) Wait(ctx context.Context) error {
+	select {
+	case <-w.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err() //nolint:wrapcheck // cancellation is reported as is
+	}
+}
+
+// Run performs every warm-up step concurrently and returns their results
+// sorted by name. Failures are reported in the results rather than stopping
+// the other steps, since a missing permission for one read should not prevent
+// warming the rest.
+func Run(ctx context.Context, source Source) []Result {
+	steps := map[string]func(context.Context) error{
+		"contexts": func(context.Context) error {
+			_, err := source.ListContexts()
+			return err //nolint:wrapcheck // reported as is in the warm-up log
+		},
+		"discovery": func(ctx context.Context) error {
+			_, err := source.DiscoverResources(ctx)
+			return err //nolint:wrapcheck // reported as is in the warm-up log
+		},
+		"namespaces": func(ctx context.Context) error {
+			_, err := source.ListResources(ctx, schema.GroupVersionResource{Version: "v1", Resource: "namespaces"}, "", metav1.ListOptions{})
+			return err //nolint:wrapcheck // reported as is in the warm-up log
+		},
+		"nodes": func(ctx context.Context) error {
+			_, err := source.ListNodes(ctx, metav1.ListOptions{})
+			return err //nolint:wrapcheck // reported as is in the warm-up log
+		},
+	}
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		results = make([]Result, 0, len(steps))
+	)
+
+	for name, step := range steps {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			start := time.Now()
+			err := step(ctx)
+
+			mu.Lock()
+			results = append(results, Result{Name: name, Duration: time.Since(start), Err: err})
+			mu.Unlock()
+		}()
+	}
+
+	wg.Wait()
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Name < results[j].Name
+	})
+
+	return results
+}
@@ -0,0 +1,110 @@
+package warmup
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/kubernetes"
+)
+
+// blockingSource blocks every read until release is closed, so the test can
+// observe that all steps run at the same time.
+type blockingSource struct {
+	started sync.WaitGroup
+	release chan struct{}
+}
+
+func (s *blockingSource) wait() {
+	s.started.Done()
+	<-s.release
+}
+
+func (s *blockingSource) ListContexts() ([]kubernetes.KubeContext, error) {
+	s.wait()
+	return nil, errors.New("no kubeconfig available")
+}
+
+func (s *blockingSource) DiscoverResources(context.Context) ([]*metav1.APIResourceList, error) {
+	s.wait()
+	return nil, nil
+}
+
+func (s *blockingSource) ListResources(context.Context, schema.GroupVersionResource, string, metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+	s.wait()
+	return &unstructured.UnstructuredList{}, nil
+}
+
+func (s *blockingSource) ListNodes(context.Context, metav1.ListOptions) (*corev1.NodeList, error) {
+	s.wait()
+	return &corev1.NodeList{}, nil
+}
+
+func TestRunIsConcurrentAndReportsFailures(t *testing.T) {
+	t.Parallel()
+
+	source := &blockingSource{release: make(chan struct{})}
+	source.started.Add(4)
+
+	var results []Result
+	finished := make(chan struct{})
+	go func() {
+		results = Run(context.Background(), source)
+		close(finished)
+	}()
+
+	// Every step must be running before any of them is allowed to return
+	source.started.Wait()
+	close(source.release)
+	<-finished
+
+	names := make([]string, 0, len(results))
+	for _, result := range results {
+		names = append(names, result.Name)
+		if (result.Err != nil) != (result.Name == "contexts") {
+			t.Errorf("unexpected error for step %q: %v", result.Name, result.Err)
+		}
+	}
+
+	if got := strings.Join(names, ","); got != "contexts,discovery,namespaces,nodes" {
+		t.Errorf("unexpected steps %q", got)
+	}
+}
+
+func TestWarmerRunsOnce(t *testing.T) {
+	t.Parallel()
+
+	source := &blockingSource{release: make(chan struct{})}
+	source.started.Add(4)
+	close(source.release)
+
+	var out bytes.Buffer
+	warmer := New(source, time.Minute, &out)
+
+	for range 3 {
+		warmer.Start()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := warmer.Wait(ctx); err != nil {
+		t.Fatalf("warm-up did not finish: %v", err)
+	}
+
+	if got := strings.Count(out.String(), "Warm-up finished"); got != 1 {
+		t.Errorf("expected the warm-up to run once, got output %q", out.String())
+	}
+	if !strings.Contains(out.String(), "contexts failed after") {
+		t.Errorf("expected the failed step to be logged, got %q", out.String())
+	}
+}
@@ -2,8 +2,11 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"os"
@@ -12,13 +15,33 @@ import (
 	"strings"
 	"syscall"
 	"time"
+	_ "time/tzdata" // --timezone must resolve zone names on hosts and images without a zoneinfo database
 
+	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/audit"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/calltimeout"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/completion"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/cors"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/dedupe"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/env"
 	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/handlers"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/impersonation"
 	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/kubernetes"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/metricshistory"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/objectresources"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/oidcauth"
 	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/portforward"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/probes"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/prompts"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/ratelimit"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/readonlycheck"
 	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/resourcefilter"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/response"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/responselimit"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/timefmt"
 	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/toolfilter"
+	"github.com/patrickdappollonio/mcp-kubernetes-ro/internal/warmup"
 )
 
 // stringSlice implements flag.Value for a repeatable, comma-separated string flag.
@@ -44,60 +67,401 @@ func (s *stringSlice) Set(value string) error {
 var (
 	kubeconfig           = flag.String("kubeconfig", "", "Path to kubeconfig file")
 	namespace            = flag.String("namespace", "", "Default namespace")
+	apiServer            = flag.String("server", "", "URL of the Kubernetes API server to connect to directly, without a kubeconfig. Authenticate with --token or --token-file")
+	token                = flag.String("token", "", "Bearer token for the API server given in --server")
+	tokenFile            = flag.String("token-file", "", "File holding the bearer token for the API server given in --server, read again when it changes")
+	certificateAuthority = flag.String("certificate-authority", "", "CA bundle that verifies the certificate of the API server given in --server (defaults to the system roots)")
 	transport            = flag.String("transport", "stdio", "Transport type: stdio, sse, or streamable-http")
+	tlsCert              = flag.String("tls-cert", "", "Certificate file to serve HTTPS with, for -transport=sse or -transport=streamable-http. Requires --tls-key")
+	tlsKey               = flag.String("tls-key", "", "Private key file for --tls-cert")
+	oidcIssuer           = flag.String("oidc-issuer", "", "Require OpenID Connect bearer tokens from this issuer URL on the sse and streamable-http transports. Requires --oidc-audience")
+	oidcAudience         = flag.String("oidc-audience", "", "Audience (aud claim) that tokens from --oidc-issuer must be issued for, usually this server's client ID")
+	oidcJWKSURL          = flag.String("oidc-jwks-url", "", "URL of the --oidc-issuer signing keys, for issuers without a discovery document (discovered from the issuer when empty)")
 	port                 = flag.Int("port", 8080, "Port for HTTP-based transports (only used with -transport=sse or -transport=streamable-http)")
+	auditLog             = flag.String("audit-log", "", "Write a JSON line for every tool call (tool, redacted arguments, caller, duration, result size, and error) to this file, or to stderr with \"stderr\" or \"-\". Disabled when empty")
+	readinessCacheTTL    = flag.Duration("readiness-cache-ttl", 10*time.Second, "How long the cluster connectivity check behind /readyz is reused before it runs again, for -transport=sse or -transport=streamable-http")
 	disabledTools        stringSlice
 	disabledResources    stringSlice
+	allowedContexts      stringSlice
+	impersonateGroups    stringSlice
+	corsAllowedOrigins   stringSlice
+	corsAllowedHeaders   stringSlice
+	qps                  = flag.Float64("qps", 0, "Maximum sustained queries per second to the Kubernetes API server. Uses the client library default of 5 when zero; a negative value disables client-side rate limiting")
+	burst                = flag.Int("burst", 0, "Maximum burst of queries to the Kubernetes API server above --qps. Uses the client library default of 10 when zero")
+	maxConcurrent        = flag.Int("max-concurrent-requests", 0, "Maximum Kubernetes API requests in flight at once, across every context, to protect the API server and this server's memory. Excess requests wait for --request-queue-timeout and then fail. No limit when zero")
+	requestQueueTimeout  = flag.Duration("request-queue-timeout", 30*time.Second, "How long a Kubernetes API request waits for a slot when --max-concurrent-requests are in flight before it fails. Zero fails it right away")
+	contextClientTTL     = flag.Duration("context-client-ttl", 30*time.Minute, "How long a client built for another kubeconfig context or an impersonated identity is kept after its last use, reusing its connections and credentials. Zero keeps them for the life of the process")
+	discoveryCacheTTL    = flag.Duration("discovery-cache-ttl", 5*time.Minute, "How long the API resources discovered from a cluster are reused to resolve resource types before discovery runs again. Resource types missing from them trigger an earlier refresh. Zero runs discovery on every call")
+	execPluginTimeout    = flag.Duration("exec-plugin-timeout", 0, "Stop kubeconfig credential plugins (aws, gke-gcloud-auth-plugin, kubelogin, and others) that take longer than this to return credentials (e.g. 1m). No limit when zero")
+	impersonateUser      = flag.String("as", "", "Kubernetes user to impersonate for every request, so queries run with that user's permissions instead of the credentials' own")
+	impersonationParams  = flag.Bool("allow-impersonation-parameters", false, "Add as and as_groups parameters to every tool that queries the cluster, letting each request impersonate a Kubernetes user and groups of its own")
 	enablePortForwarding = flag.Bool("enable-port-forwarding", false, "Enable port forwarding tools (start_port_forward, stop_port_forward, list_port_forwards)")
+	metricsHistoryEvery  = flag.Duration("metrics-history-interval", 0, "Poll the metrics-server on this interval and keep an in-memory history for the get_metrics_history tool (e.g. 30s). Disabled when zero")
+	metricsHistorySize   = flag.Int("metrics-history-size", 60, "Number of samples retained per node and pod when metrics history is enabled")
+	verifyReadOnly       = flag.Bool("verify-readonly", false, "At startup, review the RBAC rules of the credentials with a SelfSubjectRulesReview and warn about any that allow writes")
+	strict               = flag.Bool("strict", false, "With --verify-readonly, refuse to start when the credentials allow writes or cannot be verified")
+	toolTimeout          = flag.Duration("tool-timeout", 2*time.Minute, "Stop tool calls that take longer than this, such as calls stuck on a hung API server, and report an error. Tools also accept a timeout_seconds argument that replaces it per call. Disabled when zero")
+	rateLimit            = flag.Int("rate-limit", 0, "Maximum tool calls per minute for each client, identified by its OIDC identity, SSE session, or IP address, so a runaway agent loop cannot flood the API server. Disabled when zero")
+	rateLimitBurst       = flag.Int("rate-limit-burst", 0, "Tool calls a client may make at once before --rate-limit applies. Defaults to --rate-limit when zero")
+	maxResponseBytes     = flag.Int("max-response-bytes", 0, "Truncate tool results longer than this many bytes at a line boundary, and register the continue_response tool to read the rest page by page. Disabled when zero")
+	resourcePollInterval = flag.Duration("resource-poll-interval", 30*time.Second, "How often cluster objects clients subscribed to as MCP resources are checked for changes, to notify the clients. Subscriptions are not offered when zero")
+	dedupeWindow         = flag.Duration("dedupe-window", 5*time.Second, "Serve identical tool calls repeated within this window from a short-lived cache instead of querying the cluster again. Disabled when zero")
+	warmUp               = flag.Bool("warm-up", false, "Prefetch namespaces, nodes, API discovery, and kubeconfig contexts concurrently so the first tool calls do not pay cold-start latency. Runs at startup, or when the first client connects if --always-start is set")
+	timezone             = flag.String("timezone", "", "Render timestamps in tool responses in this IANA timezone (e.g. America/New_York, or Local for the server's timezone) instead of UTC")
+	compactJSON          = flag.Bool("compact-json", false, "Return JSON tool results without indentation, which roughly halves their size. Tools also accept a compact argument that replaces it per call")
+	omitEmptyFields      = flag.Bool("omit-empty-fields", false, "Drop null and empty fields from JSON tool results. Tools also accept an omit_empty argument that replaces it per call")
+	humanizeAges         = flag.Bool("humanize-ages", false, "Add a humanized age (e.g. 3d4h) next to every timestamp in tool responses, in a field named after the timestamp with an _age suffix")
 	alwaysStart          = flag.Bool("always-start", false, "Skip the startup connectivity check and start the MCP server immediately. Useful for short-lived or browser-flow OIDC credentials that are not yet valid at process start. Connectivity and authentication errors will be reported as tool call failures instead of preventing startup.")
+	skipConnectivity     = flag.Bool("skip-connectivity-check", false, "Same as --always-start: start before the cluster is reachable, such as before a VPN is up, and report connectivity errors per tool call")
+	lazyConnect          = flag.Bool("lazy-connect", false, "Same as --always-start: connect to the cluster on the first tool call instead of at startup")
 	version              = "dev"
 )
 
 func init() {
 	flag.Var(&disabledTools, "disabled-tools", "Tool names to disable (repeatable, comma-separated)")
 	flag.Var(&disabledResources, "disabled-resources", "Resources to disable (repeatable, comma-separated, e.g. secrets or core/v1/secrets)")
+	flag.Var(&impersonateGroups, "as-group", "Kubernetes group to impersonate for every request, together with --as (repeatable, comma-separated)")
+	flag.Var(&corsAllowedOrigins, "cors-allowed-origins", "Origins allowed to call the sse and streamable-http transports from a browser, such as https://app.example.com or https://*.example.com, or * for any (repeatable, comma-separated)")
+	flag.Var(&corsAllowedHeaders, "cors-allowed-headers", "Request headers browsers may send in addition to the ones MCP clients use, for --cors-allowed-origins (repeatable, comma-separated)")
+	flag.Var(&allowedContexts, "allowed-contexts", "Kubeconfig contexts the per-request context parameter may select (repeatable, comma-separated). The current context is always allowed. Every context is allowed when empty")
+}
+
+// envAliases lists the environment variables accepted for a flag besides
+// its MCP_KUBERNETES_RO_ one, in order of precedence.
+var envAliases = map[string][]string{
+	"enable-port-forwarding": {"ENABLE_PORT_FORWARDING"},
+}
+
+// envVarName returns the environment variable for a flag:
+// MCP_KUBERNETES_RO_ followed by the flag name in upper case, with dashes
+// turned into underscores.
+func envVarName(flagName string) string {
+	return "MCP_KUBERNETES_RO_" + strings.ToUpper(strings.ReplaceAll(flagName, "-", "_"))
+}
+
+// resolveEnvFlags sets every flag that was not given on the command line
+// from its environment variable, so MCP clients that configure servers
+// through the environment can set any flag. Command-line values win.
+// Repeatable flags are merged with resolveEnvSlice instead. Boolean
+// variables also accept yes and no.
+func resolveEnvFlags() error {
+	var errs []error
+	flag.VisitAll(func(f *flag.Flag) {
+		if _, ok := f.Value.(*stringSlice); ok || isFlagSet(f.Name) {
+			return
+		}
+
+		key := envVarName(f.Name)
+		value := env.FirstDefault("", append([]string{key}, envAliases[f.Name]...)...)
+		if value == "" {
+			return
+		}
+
+		if boolFlag, ok := f.Value.(interface{ IsBoolFlag() bool }); ok && boolFlag.IsBoolFlag() {
+			switch strings.ToLower(value) {
+			case "yes":
+				value = "true"
+			case "no":
+				value = "false"
+			}
+		}
+
+		if err := f.Value.Set(value); err != nil {
+			errs = append(errs, fmt.Errorf("%s value %q: %w", key, value, err))
+		}
+	})
+	return errors.Join(errs...)
 }
 
 // resolveEnvSlice appends values from environment variables to a stringSlice
 // if the env var is set. This allows both flag and env var sources to contribute.
 func resolveEnvSlice(s *stringSlice, envVars ...string) {
-	for _, key := range envVars {
-		if value := strings.TrimSpace(os.Getenv(key)); value != "" {
-			_ = s.Set(value)
-			return // use first set env var only
+	if value := env.FirstDefault("", envVars...); value != "" {
+		_ = s.Set(value) // use first set env var only
+	}
+}
+
+// isFlagSet reports whether the named flag was set on the command line.
+func isFlagSet(name string) bool {
+	set := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
 		}
+	})
+	return set
+}
+
+// serveHTTP serves httpServer over HTTPS when certFile and keyFile are set,
+// and over plain HTTP otherwise.
+func serveHTTP(httpServer *http.Server, certFile, keyFile string) error {
+	if certFile == "" {
+		return httpServer.ListenAndServe() //nolint:wrapcheck // net/http errors are self-descriptive
+	}
+
+	httpServer.TLSConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	return httpServer.ListenAndServeTLS(certFile, keyFile) //nolint:wrapcheck // net/http errors are self-descriptive
+}
+
+// openAuditLog opens the audit log destination: stderr for "stderr" or "-",
+// and otherwise a file appended to, created readable only by its owner since
+// it names who queried what. Stdout is never used, since it carries the
+// stdio transport.
+func openAuditLog(path string) (io.Writer, error) {
+	if path == "stderr" || path == "-" {
+		return os.Stderr, nil
+	}
+
+	return os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600) //nolint:wrapcheck // os errors include the path
+}
+
+// callerIdentity names the caller of a tool call for the audit log: the email
+// or subject of its OIDC token when --oidc-issuer is set, and its MCP session.
+func callerIdentity(ctx context.Context) (caller, session string) {
+	if claims, ok := oidcauth.ClaimsFrom(ctx); ok {
+		caller = claims.Email
+		if caller == "" {
+			caller = claims.Subject
+		}
+	}
+	if clientSession := server.ClientSessionFromContext(ctx); clientSession != nil {
+		session = clientSession.SessionID()
+	}
+	return caller, session
+}
+
+// verifyCredentialsReadOnly reports the write permissions of the client's
+// credentials to stderr, and exits when strict is set and the credentials
+// allow writes or cannot be verified.
+func verifyCredentialsReadOnly(client *kubernetes.Client, namespace string, strict bool) {
+	if namespace == "" {
+		namespace = "default"
+	}
+
+	fmt.Fprintf(os.Stderr, "Verifying that the credentials are read-only in namespace %q...\n", namespace)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	result, err := readonlycheck.Check(ctx, client, namespace)
+	if err != nil {
+		if strict {
+			log.Fatalf("Refusing to start (--strict): could not verify that the credentials are read-only: %v", err)
+		}
+		fmt.Fprintf(os.Stderr, "WARNING: could not verify that the credentials are read-only: %v\n", err)
+		return
+	}
+
+	if result.Incomplete {
+		message := "WARNING: the API server could not list every rule of the credentials, so permissions granted by authorizers other than RBAC are not checked"
+		if result.Reason != "" {
+			message += ": " + result.Reason
+		}
+		fmt.Fprintln(os.Stderr, message)
+	}
+
+	if len(result.Writes) == 0 {
+		fmt.Fprintln(os.Stderr, "The credentials have no write permissions")
+		return
+	}
+
+	fmt.Fprintf(os.Stderr, "WARNING: the credentials allow writes, although this server never uses them:\n")
+	for _, write := range result.Writes {
+		fmt.Fprintf(os.Stderr, "  - %s\n", write)
+	}
+	if strict {
+		log.Fatalf("Refusing to start (--strict): the credentials allow %d write permission(s). Use credentials bound to a read-only role, or impersonate one with --as", len(result.Writes))
 	}
 }
 
 func main() {
+	// Credential plugins run under --exec-plugin-timeout are started through
+	// this same executable, before any flag parsing.
+	if len(os.Args) > 1 && os.Args[1] == kubernetes.ExecPluginCommand {
+		os.Exit(kubernetes.RunExecPlugin(os.Args[2:]))
+	}
+
 	flag.Parse()
 
-	// Merge environment variables into flag values
+	// Set the flags not given on the command line from their environment
+	// variables, then merge environment variables into repeatable flags
+	if err := resolveEnvFlags(); err != nil {
+		log.Fatalf("Invalid environment variable: %v", err)
+	}
 	resolveEnvSlice(&disabledTools, "MCP_KUBERNETES_RO_DISABLED_TOOLS", "DISABLED_TOOLS")
 	resolveEnvSlice(&disabledResources, "MCP_KUBERNETES_RO_DISABLED_RESOURCES")
+	resolveEnvSlice(&allowedContexts, "MCP_KUBERNETES_RO_ALLOWED_CONTEXTS")
+	resolveEnvSlice(&impersonateGroups, "MCP_KUBERNETES_RO_AS_GROUPS")
+	resolveEnvSlice(&corsAllowedOrigins, "MCP_KUBERNETES_RO_CORS_ALLOWED_ORIGINS")
+	resolveEnvSlice(&corsAllowedHeaders, "MCP_KUBERNETES_RO_CORS_ALLOWED_HEADERS")
+
+	// HTTPS is served with a certificate and key pair, loaded here so a
+	// wrong path or mismatched pair fails before anything else starts
+	if (*tlsCert == "") != (*tlsKey == "") {
+		log.Fatalf("--tls-cert and --tls-key must be set together")
+	}
+	if *tlsCert != "" {
+		if *transport == "stdio" {
+			log.Fatalf("--tls-cert and --tls-key only apply to the sse and streamable-http transports")
+		}
+		if _, err := tls.LoadX509KeyPair(*tlsCert, *tlsKey); err != nil {
+			log.Fatalf("Failed to load the TLS certificate: %v", err)
+		}
+	}
 
-	// Resolve port forwarding flag from CLI or environment variables
-	portForwardingEnabled := *enablePortForwarding
-	if !portForwardingEnabled {
-		for _, key := range []string{"MCP_KUBERNETES_RO_ENABLE_PORT_FORWARDING", "ENABLE_PORT_FORWARDING"} {
-			if val := strings.TrimSpace(os.Getenv(key)); val != "" {
-				portForwardingEnabled = strings.EqualFold(val, "true") || val == "1" || strings.EqualFold(val, "yes")
-				break
-			}
+	// The audit log is opened before anything else starts, so a path that
+	// cannot be written stops the server instead of losing records
+	var auditLogger *audit.Logger
+	if *auditLog != "" {
+		out, err := openAuditLog(*auditLog)
+		if err != nil {
+			log.Fatalf("Failed to open the audit log: %v", err)
+		}
+		auditLogger = audit.New(out, callerIdentity)
+	}
+
+	// Each client gets its own budget of tool calls. Over HTTP, clients
+	// without an OIDC identity are told apart by their session with the SSE
+	// transport, which issues and tracks session IDs, and otherwise by IP
+	// address, which is recorded for the tool calls by the HTTP middleware
+	// below. Calls over stdio come from the one client that started the
+	// server
+	var rateLimiter *ratelimit.Limiter
+	if *rateLimit < 0 || *rateLimitBurst < 0 {
+		log.Fatalf("--rate-limit and --rate-limit-burst cannot be negative")
+	}
+	if *rateLimit > 0 {
+		rateLimiter = ratelimit.New(*rateLimit, *rateLimitBurst, ratelimit.ClientKey(callerIdentity, *transport == "sse"))
+	}
+
+	// Results over the size limit are cut, and their full text is kept for
+	// the continue_response tool to page through
+	var responseLimiter *responselimit.Limiter
+	if *maxResponseBytes < 0 {
+		log.Fatalf("Invalid max response bytes %d: it must not be negative", *maxResponseBytes)
+	}
+	if *maxResponseBytes > 0 {
+		responseLimiter = responselimit.New(*maxResponseBytes, callerIdentity)
+		fmt.Fprintf(os.Stderr, "Truncating tool results longer than %d bytes\n", responseLimiter.MaxBytes())
+	}
+
+	// Requests to the HTTP transports pass through httpMiddleware, which
+	// checks their OIDC token when an issuer is configured and adds CORS
+	// headers for allowed browser origins
+	httpMiddleware := func(next http.Handler) http.Handler { return next }
+	if *oidcIssuer != "" || *oidcAudience != "" || *oidcJWKSURL != "" {
+		if *transport == "stdio" {
+			log.Fatalf("--oidc-issuer, --oidc-audience, and --oidc-jwks-url only apply to the sse and streamable-http transports")
+		}
+		verifier, err := oidcauth.New(oidcauth.Config{
+			Issuer:   *oidcIssuer,
+			Audience: *oidcAudience,
+			JWKSURL:  *oidcJWKSURL,
+		})
+		if err != nil {
+			log.Fatalf("Invalid OIDC settings: %v", err)
+		}
+		httpMiddleware = verifier.Middleware
+	}
+
+	// Browser clients need CORS headers, including on the preflight
+	// requests they send without a token, so CORS wraps authentication
+	if len(corsAllowedOrigins) > 0 || len(corsAllowedHeaders) > 0 {
+		if *transport == "stdio" {
+			log.Fatalf("--cors-allowed-origins and --cors-allowed-headers only apply to the sse and streamable-http transports")
+		}
+		policy, err := cors.New(cors.Config{
+			AllowedOrigins: corsAllowedOrigins,
+			AllowedHeaders: corsAllowedHeaders,
+		})
+		if err != nil {
+			log.Fatalf("Invalid CORS settings: %v", err)
 		}
+		inner := httpMiddleware
+		httpMiddleware = func(next http.Handler) http.Handler { return policy.Middleware(inner(next)) }
+	}
+
+	if rateLimiter != nil {
+		inner := httpMiddleware
+		httpMiddleware = func(next http.Handler) http.Handler { return ratelimit.WithRemoteAddress(inner(next)) }
+	}
+
+	serverURL := *apiServer
+	bearerToken := *token
+	bearerTokenFile := *tokenFile
+	caFile := *certificateAuthority
+
+	impersonate := kubernetes.Impersonation{User: *impersonateUser, Groups: impersonateGroups}
+	if err := impersonate.Validate(); err != nil {
+		log.Fatalf("Invalid impersonation settings: %v", err)
+	}
+	impersonationParamsEnabled := *impersonationParams
+
+	portForwardingEnabled := *enablePortForwarding
+	alwaysStartEnabled := *alwaysStart || *skipConnectivity || *lazyConnect
+	warmUpEnabled := *warmUp
+	metricsHistoryInterval := *metricsHistoryEvery
+
+	qpsValue := *qps
+	burstValue := *burst
+	if burstValue < 0 {
+		log.Fatalf("Invalid burst %d: it must not be negative", burstValue)
+	}
+
+	maxConcurrentValue := *maxConcurrent
+	if maxConcurrentValue < 0 {
+		log.Fatalf("Invalid max concurrent requests %d: it must not be negative", maxConcurrentValue)
+	}
+	if *requestQueueTimeout < 0 {
+		log.Fatalf("Invalid request queue timeout %s: it must not be negative", *requestQueueTimeout)
+	}
+
+	if *contextClientTTL < 0 {
+		log.Fatalf("Invalid context client TTL %s: it must not be negative", *contextClientTTL)
 	}
 
-	// Resolve always-start flag from CLI or environment variable
-	alwaysStartEnabled := *alwaysStart
-	if !alwaysStartEnabled {
-		if val := strings.TrimSpace(os.Getenv("MCP_KUBERNETES_RO_ALWAYS_START")); val != "" {
-			alwaysStartEnabled = strings.EqualFold(val, "true") || val == "1" || strings.EqualFold(val, "yes")
+	if *resourcePollInterval < 0 {
+		log.Fatalf("Invalid resource poll interval %s: it must not be negative", *resourcePollInterval)
+	}
+
+	if *discoveryCacheTTL < 0 {
+		log.Fatalf("Invalid discovery cache TTL %s: it must not be negative", *discoveryCacheTTL)
+	}
+
+	execPluginTimeoutValue := *execPluginTimeout
+	toolTimeoutValue := *toolTimeout
+	dedupeWindowValue := *dedupeWindow
+
+	var timezoneLocation *time.Location
+	if timezoneName := *timezone; timezoneName != "" {
+		location, err := time.LoadLocation(timezoneName)
+		if err != nil {
+			log.Fatalf("Invalid timezone %q: %v", timezoneName, err)
 		}
+		timezoneLocation = location
 	}
+	humanizeAgesEnabled := *humanizeAges
+	responseFormat := response.Format{Compact: *compactJSON, OmitEmpty: *omitEmptyFields}
 
 	kubeConfig := &kubernetes.Config{
-		Kubeconfig: *kubeconfig,
-		Namespace:  *namespace,
+		Kubeconfig:            *kubeconfig,
+		Namespace:             *namespace,
+		AllowedContexts:       allowedContexts,
+		Server:                serverURL,
+		Token:                 bearerToken,
+		TokenFile:             bearerTokenFile,
+		CertificateAuthority:  caFile,
+		QPS:                   float32(qpsValue),
+		Burst:                 burstValue,
+		ExecPluginTimeout:     execPluginTimeoutValue,
+		Impersonate:           impersonate,
+		MaxConcurrentRequests: maxConcurrentValue,
+		RequestQueueTimeout:   *requestQueueTimeout,
+		ContextClientTTL:      *contextClientTTL,
+		DiscoveryCacheTTL:     *discoveryCacheTTL,
+	}
+
+	if serverURL != "" {
+		fmt.Fprintf(os.Stderr, "Connecting to the API server at %s directly, without a kubeconfig\n", serverURL)
 	}
 
 	client, err := kubernetes.NewClientWithContext(kubeConfig, "")
@@ -122,6 +486,18 @@ func main() {
 		fmt.Fprintln(os.Stderr, "Connected to Kubernetes cluster, starting MCP server...")
 	}
 
+	if *verifyReadOnly {
+		verifyCredentialsReadOnly(client, *namespace, *strict)
+	}
+
+	if len(allowedContexts) > 0 {
+		fmt.Fprintf(os.Stderr, "Restricting the context parameter to the current context and: %s\n", strings.Join(allowedContexts, ", "))
+	}
+
+	if !impersonate.IsZero() {
+		fmt.Fprintf(os.Stderr, "Impersonating user %q with groups %v for every request\n", impersonate.User, impersonate.Groups)
+	}
+
 	// Create resource filter for disabled resources, using the client to
 	// resolve user-friendly names (singular, kind, short names) to canonical GVRs.
 	// In --always-start mode the filter is lazy: name resolution is deferred to
@@ -149,8 +525,28 @@ func main() {
 	resourceHandler := handlers.NewResourceHandler(client, resFilter, alwaysStartEnabled)
 	logHandler := handlers.NewLogHandler(client, alwaysStartEnabled)
 	metricsHandler := handlers.NewMetricsHandler(client, alwaysStartEnabled)
+	quotaHandler := handlers.NewQuotaHandler(client, alwaysStartEnabled)
+	nodeHandler := handlers.NewNodeHandler(client, alwaysStartEnabled)
+	autoscalingHandler := handlers.NewAutoscalingHandler(client, alwaysStartEnabled)
+	clusterHandler := handlers.NewClusterHandler(client, alwaysStartEnabled)
+	podHandler := handlers.NewPodHandler(client, alwaysStartEnabled)
+	workloadHandler := handlers.NewWorkloadHandler(client, alwaysStartEnabled)
+	networkHandler := handlers.NewNetworkHandler(client, alwaysStartEnabled)
+	storageHandler := handlers.NewStorageHandler(client, alwaysStartEnabled)
+	accessHandler := handlers.NewAccessHandler(client, resFilter, alwaysStartEnabled)
 	utilsHandler := handlers.NewUtilsHandler()
 
+	// Create the metrics history sampler (may be nil if not enabled)
+	var sampler *metricshistory.Sampler
+	if metricsHistoryInterval > 0 {
+		sampler, err = metricshistory.NewSampler(client, metricsHistoryInterval, *metricsHistorySize)
+		if err != nil {
+			log.Fatalf("Failed to configure metrics history: %v", err)
+		}
+		go sampler.Run(context.Background())
+		fmt.Fprintf(os.Stderr, "Metrics history enabled (interval: %s, samples retained: %d)\n", sampler.Interval(), sampler.Size())
+	}
+
 	// Create port-forward manager (may be nil if not enabled)
 	var pfManager *portforward.Manager
 	if portForwardingEnabled {
@@ -190,21 +586,185 @@ func main() {
 			"• Each session can forward multiple ports simultaneously."
 	}
 
-	s := server.NewMCPServer(
-		"mcp-kubernetes-ro",
-		version,
+	if sampler != nil {
+		instructions += "\n\nMETRICS HISTORY:\n" +
+			"• The metrics-server is sampled in the background every " + sampler.Interval().String() + ".\n" +
+			"• Use get_metrics_history to see recent min/max/avg CPU and memory trends for nodes and pods instead of relying on a single point-in-time reading."
+	}
+
+	// Cluster objects are also served as MCP resources. Clients that
+	// subscribe to one are notified when a poll finds it changed. The
+	// streamable-http transport is stateless, so it has no session to notify.
+	objectResources := objectresources.New(client, resFilter, alwaysStartEnabled)
+	subscriptionsEnabled := *resourcePollInterval > 0 && *transport != "streamable-http"
+
+	// Prompt arguments and resource template variables naming contexts,
+	// namespaces, and resource types are completed from the cluster.
+	completions := completion.New(client, resFilter)
+
+	serverOptions := []server.ServerOption{
 		server.WithInstructions(instructions),
 		server.WithLogging(),
-	)
+		server.WithResourceCapabilities(subscriptionsEnabled, false),
+		server.WithCompletions(),
+		server.WithPromptCompletionProvider(completions),
+		server.WithResourceCompletionProvider(completions),
+	}
+
+	hooks := &server.Hooks{}
+	serverOptions = append(serverOptions, server.WithHooks(hooks))
+
+	if subscriptionsEnabled {
+		hooks.AddAfterSubscribe(func(ctx context.Context, _ any, request *mcp.SubscribeRequest, _ *mcp.EmptyResult) {
+			if session := server.ClientSessionFromContext(ctx); session != nil {
+				if err := objectResources.Subscribe(ctx, session.SessionID(), request.Params.URI); err != nil {
+					fmt.Fprintf(os.Stderr, "Ignoring subscription to %s: %v\n", request.Params.URI, err)
+				}
+			}
+		})
+		hooks.AddAfterUnsubscribe(func(ctx context.Context, _ any, request *mcp.UnsubscribeRequest, _ *mcp.EmptyResult) {
+			if session := server.ClientSessionFromContext(ctx); session != nil {
+				objectResources.Unsubscribe(session.SessionID(), request.Params.URI)
+			}
+		})
+		hooks.AddOnUnregisterSession(func(_ context.Context, session server.ClientSession) {
+			objectResources.Forget(session.SessionID())
+		})
+	}
+
+	// Warm up the client so the first tool calls are fast. With --always-start
+	// credentials may not be usable yet at process start, so the warm-up waits
+	// for the first client to connect instead.
+	if warmUpEnabled {
+		warmer := warmup.New(client, 30*time.Second, os.Stderr)
+
+		if alwaysStartEnabled {
+			hooks.AddAfterInitialize(func(context.Context, any, *mcp.InitializeRequest, *mcp.InitializeResult) {
+				warmer.Start()
+			})
+			fmt.Fprintln(os.Stderr, "Warm-up will run when the first client connects")
+		} else {
+			fmt.Fprintln(os.Stderr, "Warming up cluster data in the background...")
+			warmer.Start()
+		}
+	}
+
+	s := server.NewMCPServer("mcp-kubernetes-ro", version, serverOptions...)
+
+	s.AddResourceTemplates(objectResources.Templates()...)
+	if subscriptionsEnabled {
+		go objectResources.Run(context.Background(), *resourcePollInterval, func(sessionID, uri string) {
+			// Sessions that ended in the meantime are skipped.
+			_ = s.SendNotificationToSpecificClient(sessionID, mcp.MethodNotificationResourceUpdated, map[string]any{"uri": uri})
+		})
+		fmt.Fprintf(os.Stderr, "Checking subscribed resources for changes every %s\n", *resourcePollInterval)
+	}
+
+	// Describe this deployment for the server_capabilities tool
+	settings := handlers.ServerSettings{
+		Version:   version,
+		Transport: *transport,
+		Policies: handlers.ServerPolicies{
+			ReadOnly:          true,
+			DefaultNamespace:  *namespace,
+			DisabledTools:     append([]string{}, disabledTools...),
+			DisabledResources: append([]string{}, disabledResources...),
+			AllowedContexts:   append([]string{}, allowedContexts...),
+			ImpersonateUser:   impersonate.User,
+			ImpersonateGroups: append([]string{}, impersonate.Groups...),
+		},
+		Features: map[string]bool{
+			"port_forwarding":          portForwardingEnabled,
+			"metrics_history":          sampler != nil,
+			"call_dedupe":              dedupeWindowValue > 0,
+			"warm_up":                  warmUpEnabled,
+			"always_start":             alwaysStartEnabled,
+			"humanize_ages":            humanizeAgesEnabled,
+			"compact_json":             responseFormat.Compact,
+			"omit_empty_fields":        responseFormat.OmitEmpty,
+			"impersonation_parameters": impersonationParamsEnabled,
+			"verify_readonly":          *verifyReadOnly,
+			"oidc_auth":                *oidcIssuer != "",
+			"cors":                     len(corsAllowedOrigins) > 0,
+			"audit_log":                auditLogger != nil,
+			"rate_limit":               rateLimiter != nil,
+			"response_limit":           responseLimiter != nil,
+			"resource_subscriptions":   subscriptionsEnabled,
+		},
+		Settings: map[string]string{
+			"dedupe_window":       dedupeWindowValue.String(),
+			"tool_timeout":        toolTimeoutValue.String(),
+			"context_client_ttl":  contextClientTTL.String(),
+			"discovery_cache_ttl": discoveryCacheTTL.String(),
+		},
+	}
+	if qpsValue != 0 {
+		settings.Settings["qps"] = strconv.FormatFloat(qpsValue, 'g', -1, 32)
+	}
+	if burstValue != 0 {
+		settings.Settings["burst"] = strconv.Itoa(burstValue)
+	}
+	if maxConcurrentValue > 0 {
+		settings.Settings["max_concurrent_requests"] = strconv.Itoa(maxConcurrentValue)
+		settings.Settings["request_queue_timeout"] = requestQueueTimeout.String()
+	}
+	if execPluginTimeoutValue > 0 {
+		settings.Settings["exec_plugin_timeout"] = execPluginTimeoutValue.String()
+	}
+	if *oidcIssuer != "" {
+		settings.Settings["oidc_issuer"] = *oidcIssuer
+	}
+	if rateLimiter != nil {
+		settings.Settings["rate_limit_per_minute"] = strconv.Itoa(*rateLimit)
+	}
+	if responseLimiter != nil {
+		settings.Settings["max_response_bytes"] = strconv.Itoa(responseLimiter.MaxBytes())
+	}
+	if subscriptionsEnabled {
+		settings.Settings["resource_poll_interval"] = resourcePollInterval.String()
+	}
+	if timezoneLocation != nil {
+		settings.Settings["timezone"] = timezoneLocation.String()
+	}
+	if sampler != nil {
+		settings.Settings["metrics_history_interval"] = sampler.Interval().String()
+		settings.Settings["metrics_history_size"] = strconv.Itoa(sampler.Size())
+	}
+
+	capabilitiesHandler := handlers.NewCapabilitiesHandler(client, alwaysStartEnabled, settings, func() []string {
+		names := make([]string, 0)
+		for name := range s.ListTools() {
+			names = append(names, name)
+		}
+		return names
+	})
 
 	// Register all tools from handlers
 	allHandlers := []handlers.ToolRegistrator{
 		resourceHandler,
 		logHandler,
 		metricsHandler,
+		quotaHandler,
+		nodeHandler,
+		autoscalingHandler,
+		clusterHandler,
+		podHandler,
+		workloadHandler,
+		networkHandler,
+		storageHandler,
+		accessHandler,
+		capabilitiesHandler,
 		utilsHandler,
 	}
 
+	if sampler != nil {
+		allHandlers = append(allHandlers, handlers.NewMetricsHistoryHandler(sampler))
+	}
+
+	if responseLimiter != nil {
+		allHandlers = append(allHandlers, handlers.NewContinueResponseHandler(responseLimiter))
+	}
+
 	if portForwardingEnabled {
 		portForwardHandler := handlers.NewPortForwardHandler(client, pfManager, alwaysStartEnabled)
 		allHandlers = append(allHandlers, portForwardHandler)
@@ -213,20 +773,121 @@ func main() {
 	// Create tool filter
 	filter := toolfilter.NewFilterFromList(disabledTools)
 
+	// Create the call deduplication cache (may be nil if disabled)
+	var dedupeCache *dedupe.Cache
+	if dedupeWindowValue > 0 {
+		dedupeCache = dedupe.New(dedupeWindowValue)
+		fmt.Fprintf(os.Stderr, "Serving identical tool calls repeated within %s from cache\n", dedupeCache.Window())
+	}
+
+	// Create the timestamp formatter (may be nil if timestamps are left as reported)
+	var timeFormatter *timefmt.Formatter
+	if timezoneLocation != nil || humanizeAgesEnabled {
+		timeFormatter = timefmt.New(timezoneLocation, humanizeAgesEnabled)
+		if timezoneLocation != nil {
+			fmt.Fprintf(os.Stderr, "Rendering timestamps in tool responses in %s\n", timezoneLocation)
+		}
+		if humanizeAgesEnabled {
+			fmt.Fprintln(os.Stderr, "Adding humanized ages next to timestamps in tool responses")
+		}
+	}
+
+	// Port forwarding tools change the server's state, so repeated calls
+	// must always run rather than being served from the dedupe cache.
+	statefulTools := map[string]bool{
+		"start_port_forward": true,
+		"stop_port_forward":  true,
+		"list_port_forwards": true,
+	}
+
+	callLimiter := calltimeout.New(toolTimeoutValue)
+	responseFormatter := response.NewFormatter(responseFormat)
+
 	// Register tools from handlers
+	registeredTools := make(map[string]bool)
 	for _, handler := range allHandlers {
 		for i := range handler.GetTools() {
 			mcpTool := &handler.GetTools()[i]
 
-			if tool := mcpTool.Tool().Name; filter.IsDisabled(tool) {
+			tool := mcpTool.Tool().Name
+			if filter.IsDisabled(tool) {
 				fmt.Fprintf(os.Stderr, "Skipping disabled tool: %q\n", tool)
 				continue
 			}
 
-			s.AddTool(mcpTool.Tool(), mcpTool.Handler())
+			mcpToolDefinition := mcpTool.Tool()
+			toolHandler := mcpTool.Handler()
+
+			// Impersonation is read from the call's arguments, which the
+			// dedupe cache keys on, so calls made as different identities
+			// never share a cached result.
+			if impersonationParamsEnabled {
+				var ok bool
+				if mcpToolDefinition, ok = impersonation.AddParameters(mcpToolDefinition); ok {
+					toolHandler = impersonation.Wrap(toolHandler)
+				}
+			}
+
+			// Port forwarding tools manage sessions that outlive the call,
+			// so they are not bound by the tool call timeout.
+			if !statefulTools[tool] {
+				var ok bool
+				if mcpToolDefinition, ok = calltimeout.AddParameter(mcpToolDefinition); ok {
+					toolHandler = callLimiter.Wrap(toolHandler)
+				}
+			}
+
+			if dedupeCache != nil && !statefulTools[tool] {
+				toolHandler = dedupeCache.Wrap(tool, toolHandler)
+			}
+
+			// Timestamps are localized outside the dedupe cache, so results
+			// served from it still get ages computed at the time of the call.
+			if timeFormatter != nil {
+				toolHandler = timeFormatter.Wrap(toolHandler)
+			}
+
+			// Results are formatted after timestamps are rendered, since
+			// those are rewritten with indentation. Pages of a truncated
+			// result are cut from text that was already formatted, so
+			// continue_response takes no format arguments.
+			if tool != responselimit.ContinueTool {
+				var ok bool
+				if mcpToolDefinition, ok = response.AddFormatParameters(mcpToolDefinition); ok {
+					toolHandler = responseFormatter.Wrap(toolHandler)
+				}
+			}
+
+			// Results are cut once formatted, so pages hold the text the
+			// client sees, and outside the dedupe cache, which keeps the
+			// full result. Pages carry no structured content, so tools
+			// whose results may be cut declare no output schema.
+			if responseLimiter != nil {
+				mcpToolDefinition, _ = responselimit.RemoveOutputSchema(mcpToolDefinition)
+				toolHandler = responseLimiter.Wrap(tool, toolHandler)
+			}
+
+			// Calls over the limit are refused before reaching the dedupe
+			// cache or the cluster, and still show in the audit log.
+			if rateLimiter != nil {
+				toolHandler = rateLimiter.Wrap(toolHandler)
+			}
+
+			// The audit log wraps everything else, so it records the
+			// arguments as the client sent them and the result it got back.
+			if auditLogger != nil {
+				toolHandler = auditLogger.Wrap(tool, toolHandler)
+			}
+
+			s.AddTool(mcpToolDefinition, toolHandler)
+			registeredTools[tool] = true
 		}
 	}
 
+	// Prompts guide clients through the registered tools, so steps that
+	// need a disabled tool are left out of them.
+	s.AddPrompts(prompts.All(func(tool string) bool { return registeredTools[tool] })...)
+
 	// Set up graceful shutdown for port forwarding
 	if portForwardingEnabled && pfManager != nil {
 		sigChan := make(chan os.Signal, 1)
@@ -239,6 +900,19 @@ func main() {
 		}()
 	}
 
+	scheme := "http"
+	if *tlsCert != "" {
+		scheme = "https"
+	}
+
+	// Liveness and readiness probes are answered before authentication,
+	// since kubelets and load balancers probe without credentials
+	prober := probes.New(client.Ping, *readinessCacheTTL)
+
+	if *oidcIssuer != "" {
+		log.Printf("Requiring OIDC bearer tokens from issuer %s for audience %s", *oidcIssuer, *oidcAudience)
+	}
+
 	switch *transport {
 	case "stdio":
 		log.Printf("Starting MCP server with stdio transport")
@@ -251,18 +925,19 @@ func main() {
 
 		addr := ":" + strconv.Itoa(*port)
 		log.Printf("Starting SSE MCP server on %s", addr)
-		log.Printf("SSE endpoint: http://localhost%s/sse", addr)
-		log.Printf("Message endpoint: http://localhost%s/message", addr)
+		log.Printf("SSE endpoint: %s://localhost%s/sse", scheme, addr)
+		log.Printf("Message endpoint: %s://localhost%s/message", scheme, addr)
+		log.Printf("Probe endpoints: %s://localhost%s%s and %s", scheme, addr, probes.LivenessPath, probes.ReadinessPath)
 
 		httpServer := &http.Server{
 			Addr:         addr,
-			Handler:      sseServer,
+			Handler:      prober.Middleware(httpMiddleware(sseServer)),
 			ReadTimeout:  15 * time.Second,
 			WriteTimeout: 15 * time.Second,
 			IdleTimeout:  60 * time.Second,
 		}
 
-		if err := httpServer.ListenAndServe(); err != nil {
+		if err := serveHTTP(httpServer, *tlsCert, *tlsKey); err != nil {
 			fmt.Printf("SSE server error: %v\n", err)
 		}
 	case "streamable-http":
@@ -276,17 +951,18 @@ func main() {
 
 		addr := ":" + strconv.Itoa(*port)
 		log.Printf("Starting streamable-http MCP server on %s", addr)
-		log.Printf("MCP endpoint: http://localhost%s/mcp", addr)
+		log.Printf("MCP endpoint: %s://localhost%s/mcp", scheme, addr)
+		log.Printf("Probe endpoints: %s://localhost%s%s and %s", scheme, addr, probes.LivenessPath, probes.ReadinessPath)
 
 		httpServer := &http.Server{
 			Addr:         addr,
-			Handler:      httpHandler,
+			Handler:      prober.Middleware(httpMiddleware(httpHandler)),
 			ReadTimeout:  15 * time.Second,
 			WriteTimeout: 15 * time.Second,
 			IdleTimeout:  60 * time.Second,
 		}
 
-		if err := httpServer.ListenAndServe(); err != nil {
+		if err := serveHTTP(httpServer, *tlsCert, *tlsKey); err != nil {
 			fmt.Printf("streamable-http server error: %v\n", err)
 		}
 	default: